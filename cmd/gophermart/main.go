@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"log"
+	"os"
 	"os/signal"
 	"syscall"
 
@@ -10,15 +11,23 @@ import (
 )
 
 func main() {
+	os.Exit(run())
+}
+
+// run запускает приложение и возвращает код завершения процесса (см. app.ExitCode)
+func run() int {
 	application, err := app.NewApp()
 	if err != nil {
-		log.Fatalf("Failed to initialize application: %v", err)
+		log.Printf("Failed to initialize application: %v", err)
+		return app.ExitCode(err)
 	}
 
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
 	if err := application.Run(ctx); err != nil {
-		log.Fatalf("Failed to run application: %v", err)
+		log.Printf("Failed to run application: %v", err)
+		return app.ExitCode(err)
 	}
+	return app.ExitOK
 }
@@ -0,0 +1,186 @@
+// Команда importer переносит заказы и операции по счетам из прежней системы лояльности.
+//
+// Пользователи должны быть созданы заранее через POST /api/admin/users/import.
+// Записи сохраняются с исходными временными метками, повторный запуск на тех же файлах
+// ничего не дублирует. Отчет сверки выводится в JSON; код выхода 2 означает, что часть
+// записей отклонена или балансы не сошлись.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/avc/loyalty-system-diploma/internal/importer"
+	"github.com/avc/loyalty-system-diploma/internal/repository/postgres"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// exitNotReconciled - код выхода, если импорт завершен с расхождениями
+const exitNotReconciled = 2
+
+type options struct {
+	databaseURI      string
+	ordersPath       string
+	transactionsPath string
+	reportPath       string
+	batchSize        int
+	dryRun           bool
+}
+
+func main() {
+	var opts options
+	flag.StringVar(&opts.databaseURI, "d", "", "database URI")
+	flag.StringVar(&opts.ordersPath, "orders", "", "legacy orders file (.csv or .json)")
+	flag.StringVar(&opts.transactionsPath, "transactions", "", "legacy transactions file (.csv or .json)")
+	flag.StringVar(&opts.reportPath, "report", "", "reconciliation report file (stdout by default)")
+	flag.IntVar(&opts.batchSize, "batch-size", importer.DefaultBatchSize, "records per insert")
+	flag.BoolVar(&opts.dryRun, "dry-run", false, "validate files without writing to the database")
+	flag.Parse()
+
+	if envDBURI, ok := os.LookupEnv("DATABASE_URI"); ok {
+		opts.databaseURI = envDBURI
+	}
+	if opts.databaseURI == "" {
+		log.Fatal("database URI is required (use -d flag or DATABASE_URI env)")
+	}
+	if opts.ordersPath == "" && opts.transactionsPath == "" {
+		log.Fatal("nothing to import: use -orders and/or -transactions")
+	}
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		log.Fatalf("Failed to init logger: %v", err)
+	}
+	defer logger.Sync() //nolint:errcheck // ошибка Sync для stderr не важна
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	report, err := run(ctx, opts, logger)
+	if err != nil {
+		log.Fatalf("Import failed: %v", err)
+	}
+
+	if err := writeReport(opts.reportPath, report); err != nil {
+		log.Fatalf("Failed to write report: %v", err)
+	}
+
+	if !report.Reconciled() {
+		logger.Warn("import finished with discrepancies",
+			zap.Int("rejected", len(report.Rejected)),
+			zap.Int("mismatches", report.Mismatches),
+		)
+		stop()
+		os.Exit(exitNotReconciled)
+	}
+	logger.Info("import finished")
+}
+
+// run читает файлы, подключается к БД и выполняет импорт
+func run(ctx context.Context, opts options, logger *zap.Logger) (*importer.Report, error) {
+	ledger, err := readLedger(opts.ordersPath, opts.transactionsPath)
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("legacy files read",
+		zap.Int("orders", len(ledger.Orders)),
+		zap.Int("transactions", len(ledger.Transactions)),
+		zap.Int("rejected", len(ledger.Rejected)),
+	)
+
+	dbPool, err := pgxpool.New(ctx, opts.databaseURI)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer dbPool.Close()
+
+	if err := postgres.RunMigrations(ctx, dbPool, logger); err != nil {
+		return nil, fmt.Errorf("failed to run migrations: %w", err)
+	}
+
+	store := struct {
+		*postgres.LegacyImportRepository
+		*postgres.TransactionRepository
+	}{
+		postgres.NewLegacyImportRepository(dbPool),
+		postgres.NewTransactionRepository(dbPool),
+	}
+
+	im := importer.New(store, importer.Config{
+		BatchSize: opts.batchSize,
+		DryRun:    opts.dryRun,
+	}, logger)
+
+	return im.Run(ctx, ledger)
+}
+
+// readLedger читает заказы и операции; пустой путь пропускается
+func readLedger(ordersPath, transactionsPath string) (importer.Ledger, error) {
+	var ledger importer.Ledger
+
+	if ordersPath != "" {
+		err := readFile(ordersPath, func(r io.Reader, format importer.Format) error {
+			orders, rejected, err := importer.ReadOrders(r, format)
+			ledger.Orders = orders
+			ledger.Rejected = append(ledger.Rejected, rejected...)
+			return err
+		})
+		if err != nil {
+			return ledger, err
+		}
+	}
+
+	if transactionsPath != "" {
+		err := readFile(transactionsPath, func(r io.Reader, format importer.Format) error {
+			transactions, rejected, err := importer.ReadTransactions(r, format)
+			ledger.Transactions = transactions
+			ledger.Rejected = append(ledger.Rejected, rejected...)
+			return err
+		})
+		if err != nil {
+			return ledger, err
+		}
+	}
+
+	return ledger, nil
+}
+
+func readFile(path string, read func(r io.Reader, format importer.Format) error) error {
+	format, err := importer.DetectFormat(path)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	return read(f, format)
+}
+
+// writeReport записывает отчет сверки в файл или в stdout
+func writeReport(path string, report *importer.Report) error {
+	out := os.Stdout
+	if path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", path, err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
@@ -0,0 +1,313 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.8
+// 	protoc        (unknown)
+// source: accrual/v2/accrual.proto
+
+// Протокол v2 системы начислений.
+
+package accrualpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type OrderStatus int32
+
+const (
+	OrderStatus_ORDER_STATUS_UNSPECIFIED OrderStatus = 0
+	OrderStatus_ORDER_STATUS_REGISTERED  OrderStatus = 1
+	OrderStatus_ORDER_STATUS_INVALID     OrderStatus = 2
+	OrderStatus_ORDER_STATUS_PROCESSING  OrderStatus = 3
+	OrderStatus_ORDER_STATUS_PROCESSED   OrderStatus = 4
+)
+
+// Enum value maps for OrderStatus.
+var (
+	OrderStatus_name = map[int32]string{
+		0: "ORDER_STATUS_UNSPECIFIED",
+		1: "ORDER_STATUS_REGISTERED",
+		2: "ORDER_STATUS_INVALID",
+		3: "ORDER_STATUS_PROCESSING",
+		4: "ORDER_STATUS_PROCESSED",
+	}
+	OrderStatus_value = map[string]int32{
+		"ORDER_STATUS_UNSPECIFIED": 0,
+		"ORDER_STATUS_REGISTERED":  1,
+		"ORDER_STATUS_INVALID":     2,
+		"ORDER_STATUS_PROCESSING":  3,
+		"ORDER_STATUS_PROCESSED":   4,
+	}
+)
+
+func (x OrderStatus) Enum() *OrderStatus {
+	p := new(OrderStatus)
+	*p = x
+	return p
+}
+
+func (x OrderStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (OrderStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_accrual_v2_accrual_proto_enumTypes[0].Descriptor()
+}
+
+func (OrderStatus) Type() protoreflect.EnumType {
+	return &file_accrual_v2_accrual_proto_enumTypes[0]
+}
+
+func (x OrderStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use OrderStatus.Descriptor instead.
+func (OrderStatus) EnumDescriptor() ([]byte, []int) {
+	return file_accrual_v2_accrual_proto_rawDescGZIP(), []int{0}
+}
+
+type GetOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Number        string                 `protobuf:"bytes,1,opt,name=number,proto3" json:"number,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrderRequest) Reset() {
+	*x = GetOrderRequest{}
+	mi := &file_accrual_v2_accrual_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrderRequest) ProtoMessage() {}
+
+func (x *GetOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_accrual_v2_accrual_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrderRequest.ProtoReflect.Descriptor instead.
+func (*GetOrderRequest) Descriptor() ([]byte, []int) {
+	return file_accrual_v2_accrual_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetOrderRequest) GetNumber() string {
+	if x != nil {
+		return x.Number
+	}
+	return ""
+}
+
+type StreamOrdersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Numbers       []string               `protobuf:"bytes,1,rep,name=numbers,proto3" json:"numbers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamOrdersRequest) Reset() {
+	*x = StreamOrdersRequest{}
+	mi := &file_accrual_v2_accrual_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamOrdersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamOrdersRequest) ProtoMessage() {}
+
+func (x *StreamOrdersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_accrual_v2_accrual_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamOrdersRequest.ProtoReflect.Descriptor instead.
+func (*StreamOrdersRequest) Descriptor() ([]byte, []int) {
+	return file_accrual_v2_accrual_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *StreamOrdersRequest) GetNumbers() []string {
+	if x != nil {
+		return x.Numbers
+	}
+	return nil
+}
+
+type Order struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Number string                 `protobuf:"bytes,1,opt,name=number,proto3" json:"number,omitempty"`
+	Status OrderStatus            `protobuf:"varint,2,opt,name=status,proto3,enum=accrual.v2.OrderStatus" json:"status,omitempty"`
+	// Задано только для ORDER_STATUS_PROCESSED.
+	Accrual       *float64 `protobuf:"fixed64,3,opt,name=accrual,proto3,oneof" json:"accrual,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Order) Reset() {
+	*x = Order{}
+	mi := &file_accrual_v2_accrual_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Order) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Order) ProtoMessage() {}
+
+func (x *Order) ProtoReflect() protoreflect.Message {
+	mi := &file_accrual_v2_accrual_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Order.ProtoReflect.Descriptor instead.
+func (*Order) Descriptor() ([]byte, []int) {
+	return file_accrual_v2_accrual_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Order) GetNumber() string {
+	if x != nil {
+		return x.Number
+	}
+	return ""
+}
+
+func (x *Order) GetStatus() OrderStatus {
+	if x != nil {
+		return x.Status
+	}
+	return OrderStatus_ORDER_STATUS_UNSPECIFIED
+}
+
+func (x *Order) GetAccrual() float64 {
+	if x != nil && x.Accrual != nil {
+		return *x.Accrual
+	}
+	return 0
+}
+
+var File_accrual_v2_accrual_proto protoreflect.FileDescriptor
+
+const file_accrual_v2_accrual_proto_rawDesc = "" +
+	"\n" +
+	"\x18accrual/v2/accrual.proto\x12\n" +
+	"accrual.v2\")\n" +
+	"\x0fGetOrderRequest\x12\x16\n" +
+	"\x06number\x18\x01 \x01(\tR\x06number\"/\n" +
+	"\x13StreamOrdersRequest\x12\x18\n" +
+	"\anumbers\x18\x01 \x03(\tR\anumbers\"{\n" +
+	"\x05Order\x12\x16\n" +
+	"\x06number\x18\x01 \x01(\tR\x06number\x12/\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x17.accrual.v2.OrderStatusR\x06status\x12\x1d\n" +
+	"\aaccrual\x18\x03 \x01(\x01H\x00R\aaccrual\x88\x01\x01B\n" +
+	"\n" +
+	"\b_accrual*\x9b\x01\n" +
+	"\vOrderStatus\x12\x1c\n" +
+	"\x18ORDER_STATUS_UNSPECIFIED\x10\x00\x12\x1b\n" +
+	"\x17ORDER_STATUS_REGISTERED\x10\x01\x12\x18\n" +
+	"\x14ORDER_STATUS_INVALID\x10\x02\x12\x1b\n" +
+	"\x17ORDER_STATUS_PROCESSING\x10\x03\x12\x1a\n" +
+	"\x16ORDER_STATUS_PROCESSED\x10\x042\x92\x01\n" +
+	"\x0eAccrualService\x12:\n" +
+	"\bGetOrder\x12\x1b.accrual.v2.GetOrderRequest\x1a\x11.accrual.v2.Order\x12D\n" +
+	"\fStreamOrders\x12\x1f.accrual.v2.StreamOrdersRequest\x1a\x11.accrual.v2.Order0\x01BDZBgithub.com/avc/loyalty-system-diploma/internal/accrualpb;accrualpbb\x06proto3"
+
+var (
+	file_accrual_v2_accrual_proto_rawDescOnce sync.Once
+	file_accrual_v2_accrual_proto_rawDescData []byte
+)
+
+func file_accrual_v2_accrual_proto_rawDescGZIP() []byte {
+	file_accrual_v2_accrual_proto_rawDescOnce.Do(func() {
+		file_accrual_v2_accrual_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_accrual_v2_accrual_proto_rawDesc), len(file_accrual_v2_accrual_proto_rawDesc)))
+	})
+	return file_accrual_v2_accrual_proto_rawDescData
+}
+
+var file_accrual_v2_accrual_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_accrual_v2_accrual_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_accrual_v2_accrual_proto_goTypes = []any{
+	(OrderStatus)(0),            // 0: accrual.v2.OrderStatus
+	(*GetOrderRequest)(nil),     // 1: accrual.v2.GetOrderRequest
+	(*StreamOrdersRequest)(nil), // 2: accrual.v2.StreamOrdersRequest
+	(*Order)(nil),               // 3: accrual.v2.Order
+}
+var file_accrual_v2_accrual_proto_depIdxs = []int32{
+	0, // 0: accrual.v2.Order.status:type_name -> accrual.v2.OrderStatus
+	1, // 1: accrual.v2.AccrualService.GetOrder:input_type -> accrual.v2.GetOrderRequest
+	2, // 2: accrual.v2.AccrualService.StreamOrders:input_type -> accrual.v2.StreamOrdersRequest
+	3, // 3: accrual.v2.AccrualService.GetOrder:output_type -> accrual.v2.Order
+	3, // 4: accrual.v2.AccrualService.StreamOrders:output_type -> accrual.v2.Order
+	3, // [3:5] is the sub-list for method output_type
+	1, // [1:3] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_accrual_v2_accrual_proto_init() }
+func file_accrual_v2_accrual_proto_init() {
+	if File_accrual_v2_accrual_proto != nil {
+		return
+	}
+	file_accrual_v2_accrual_proto_msgTypes[2].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_accrual_v2_accrual_proto_rawDesc), len(file_accrual_v2_accrual_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_accrual_v2_accrual_proto_goTypes,
+		DependencyIndexes: file_accrual_v2_accrual_proto_depIdxs,
+		EnumInfos:         file_accrual_v2_accrual_proto_enumTypes,
+		MessageInfos:      file_accrual_v2_accrual_proto_msgTypes,
+	}.Build()
+	File_accrual_v2_accrual_proto = out.File
+	file_accrual_v2_accrual_proto_goTypes = nil
+	file_accrual_v2_accrual_proto_depIdxs = nil
+}
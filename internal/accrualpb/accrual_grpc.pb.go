@@ -0,0 +1,173 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: accrual/v2/accrual.proto
+
+// Протокол v2 системы начислений.
+
+package accrualpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	AccrualService_GetOrder_FullMethodName     = "/accrual.v2.AccrualService/GetOrder"
+	AccrualService_StreamOrders_FullMethodName = "/accrual.v2.AccrualService/StreamOrders"
+)
+
+// AccrualServiceClient is the client API for AccrualService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AccrualServiceClient interface {
+	// Статус расчета начисления по заказу. NOT_FOUND - заказ не зарегистрирован,
+	// RESOURCE_EXHAUSTED с google.rpc.RetryInfo - превышен лимит запросов.
+	GetOrder(ctx context.Context, in *GetOrderRequest, opts ...grpc.CallOption) (*Order, error)
+	// Статусы нескольких заказов: по сообщению на каждый зарегистрированный заказ,
+	// незарегистрированные пропускаются.
+	StreamOrders(ctx context.Context, in *StreamOrdersRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Order], error)
+}
+
+type accrualServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAccrualServiceClient(cc grpc.ClientConnInterface) AccrualServiceClient {
+	return &accrualServiceClient{cc}
+}
+
+func (c *accrualServiceClient) GetOrder(ctx context.Context, in *GetOrderRequest, opts ...grpc.CallOption) (*Order, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Order)
+	err := c.cc.Invoke(ctx, AccrualService_GetOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accrualServiceClient) StreamOrders(ctx context.Context, in *StreamOrdersRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Order], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AccrualService_ServiceDesc.Streams[0], AccrualService_StreamOrders_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamOrdersRequest, Order]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AccrualService_StreamOrdersClient = grpc.ServerStreamingClient[Order]
+
+// AccrualServiceServer is the server API for AccrualService service.
+// All implementations must embed UnimplementedAccrualServiceServer
+// for forward compatibility.
+type AccrualServiceServer interface {
+	// Статус расчета начисления по заказу. NOT_FOUND - заказ не зарегистрирован,
+	// RESOURCE_EXHAUSTED с google.rpc.RetryInfo - превышен лимит запросов.
+	GetOrder(context.Context, *GetOrderRequest) (*Order, error)
+	// Статусы нескольких заказов: по сообщению на каждый зарегистрированный заказ,
+	// незарегистрированные пропускаются.
+	StreamOrders(*StreamOrdersRequest, grpc.ServerStreamingServer[Order]) error
+	mustEmbedUnimplementedAccrualServiceServer()
+}
+
+// UnimplementedAccrualServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAccrualServiceServer struct{}
+
+func (UnimplementedAccrualServiceServer) GetOrder(context.Context, *GetOrderRequest) (*Order, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOrder not implemented")
+}
+func (UnimplementedAccrualServiceServer) StreamOrders(*StreamOrdersRequest, grpc.ServerStreamingServer[Order]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamOrders not implemented")
+}
+func (UnimplementedAccrualServiceServer) mustEmbedUnimplementedAccrualServiceServer() {}
+func (UnimplementedAccrualServiceServer) testEmbeddedByValue()                        {}
+
+// UnsafeAccrualServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AccrualServiceServer will
+// result in compilation errors.
+type UnsafeAccrualServiceServer interface {
+	mustEmbedUnimplementedAccrualServiceServer()
+}
+
+func RegisterAccrualServiceServer(s grpc.ServiceRegistrar, srv AccrualServiceServer) {
+	// If the following call pancis, it indicates UnimplementedAccrualServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AccrualService_ServiceDesc, srv)
+}
+
+func _AccrualService_GetOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccrualServiceServer).GetOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AccrualService_GetOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccrualServiceServer).GetOrder(ctx, req.(*GetOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccrualService_StreamOrders_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamOrdersRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AccrualServiceServer).StreamOrders(m, &grpc.GenericServerStream[StreamOrdersRequest, Order]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AccrualService_StreamOrdersServer = grpc.ServerStreamingServer[Order]
+
+// AccrualService_ServiceDesc is the grpc.ServiceDesc for AccrualService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AccrualService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "accrual.v2.AccrualService",
+	HandlerType: (*AccrualServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetOrder",
+			Handler:    _AccrualService_GetOrder_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamOrders",
+			Handler:       _AccrualService_StreamOrders_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "accrual/v2/accrual.proto",
+}
@@ -6,20 +6,44 @@ import (
 	"net/http"
 
 	"github.com/avc/loyalty-system-diploma/internal/config"
+	"github.com/avc/loyalty-system-diploma/internal/events"
+	"github.com/avc/loyalty-system-diploma/internal/handlers"
+	"github.com/avc/loyalty-system-diploma/internal/jobs"
+	"github.com/avc/loyalty-system-diploma/internal/lock"
+	"github.com/avc/loyalty-system-diploma/internal/logctx"
+	"github.com/avc/loyalty-system-diploma/internal/metrics"
+	"github.com/avc/loyalty-system-diploma/internal/noncestore"
+	"github.com/avc/loyalty-system-diploma/internal/repository/postgres"
+	"github.com/avc/loyalty-system-diploma/internal/scheduler"
+	"github.com/avc/loyalty-system-diploma/internal/service"
+	"github.com/avc/loyalty-system-diploma/internal/tokenstore"
+	"github.com/avc/loyalty-system-diploma/internal/tracing"
 	"github.com/avc/loyalty-system-diploma/internal/worker"
-	"github.com/go-chi/chi/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/zap"
 )
 
 // App представляет приложение
 type App struct {
-	config     *config.Config
-	logger     *zap.Logger
-	db         *pgxpool.Pool
-	router     *chi.Mux
-	workerPool *worker.Pool
-	server     *http.Server
+	config        *config.Config
+	logger        *zap.Logger
+	db            *pgxpool.Pool
+	dbCreds       *postgres.Credentials
+	accrual       accrualClient
+	accrualRepair *service.AccrualRepairService
+	workerPool    *worker.Pool
+	dispatcher    *events.Dispatcher
+	dbState       *postgres.Availability
+	elector       *postgres.LeaderElector
+	jobs          *jobs.Manager
+	scheduler     *scheduler.Scheduler
+	requests      *handlers.RequestTracker
+	metrics       *metrics.Metrics
+	redisLocks    *lock.RedisBackend
+	redisTokens   *tokenstore.RedisStore
+	redisNonces   *noncestore.RedisStore
+	servers       []*http.Server
+	flushTraces   func(context.Context) error
 }
 
 // NewApp создает новое приложение
@@ -29,38 +53,106 @@ func NewApp() (*App, error) {
 	// Загрузка конфигурации
 	cfg, err := config.Load()
 	if err != nil {
-		return nil, fmt.Errorf("failed to load config: %w", err)
+		return nil, fmt.Errorf("%w: failed to load config: %w", ErrConfig, err)
 	}
 
 	// Инициализация логгера
 	logger, err := initLogger(cfg.LogLevel)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: %w", ErrConfig, err)
+	}
+	logctx.SetDefault(logger)
+	logEffectiveConfig(logger, cfg)
+
+	// Трассировка настраивается до зависимостей: клиенты и воркеры берут глобальный провайдер
+	flushTraces, err := tracing.Setup(ctx, tracing.Config{
+		Endpoint:    cfg.TracingEndpoint,
+		ServiceName: cfg.TracingServiceName,
+		SampleRatio: cfg.TracingSampleRatio,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrConfig, err)
 	}
 
+	// Общий бюджет ожидания зависимостей при старте
+	startupCtx, cancel := context.WithTimeout(ctx, cfg.StartupTimeout)
+	defer cancel()
+
 	// Инициализация базы данных
-	dbPool, err := initDatabase(ctx, cfg.DatabaseURI, logger)
+	poolConfig, dbCreds, err := newPoolConfig(cfg, logger)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: %w", ErrConfig, err)
+	}
+	dbPool, err := initDatabase(startupCtx, poolConfig, cfg.StartupRetryBackoff, logger)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDatabase, err)
 	}
 	logger.Info("connected to database")
 
 	// Инициализация зависимостей
-	deps := initDependencies(cfg, dbPool, logger)
+	deps, err := initDependencies(cfg, dbPool, logger)
+	if err != nil {
+		dbPool.Close()
+		return nil, err
+	}
+
+	// Система начислений не обязательна для старта: воркеры повторят запросы позже
+	if err := waitFor(startupCtx, "accrual system", cfg.StartupRetryBackoff, logger, deps.services.accrual.Ping); err != nil {
+		logger.Warn("starting without accrual system", zap.Error(err))
+	} else {
+		logger.Info("accrual system is reachable")
+	}
+
+	// Без Redis списания ждут его восстановления, но старт не прерывается
+	if deps.redisLocks != nil {
+		if err := waitFor(startupCtx, "redis", cfg.StartupRetryBackoff, logger, deps.redisLocks.Ping); err != nil {
+			logger.Warn("starting without redis locks", zap.Error(err))
+		} else {
+			logger.Info("redis is reachable")
+		}
+	}
+
+	// Недоступный Redis refresh-токенов ломает только обновление сессий
+	if deps.redisTokens != nil {
+		if err := waitFor(startupCtx, "refresh token redis", cfg.StartupRetryBackoff, logger, deps.redisTokens.Ping); err != nil {
+			logger.Warn("starting without refresh token redis", zap.Error(err))
+		} else {
+			logger.Info("refresh token redis is reachable")
+		}
+	}
 
-	// Настройка роутера
-	router := setupRouter(deps, deps.jwtManager, logger)
+	// Недоступный Redis nonce ломает только внутренние эндпоинты с защитой от повтора
+	if deps.redisNonces != nil {
+		if err := waitFor(startupCtx, "nonce redis", cfg.StartupRetryBackoff, logger, deps.redisNonces.Ping); err != nil {
+			logger.Warn("starting without nonce redis", zap.Error(err))
+		} else {
+			logger.Info("nonce redis is reachable")
+		}
+	}
 
-	// Создание HTTP сервера
-	server := createServer(cfg.RunAddress, router)
+	// Настройка роутеров и HTTP серверов всех слушателей
+	servers := createServers(cfg, deps, logger)
 
 	return &App{
-		config:     cfg,
-		logger:     logger,
-		db:         dbPool,
-		router:     router,
-		workerPool: deps.workerPool,
-		server:     server,
+		config:        cfg,
+		logger:        logger,
+		db:            dbPool,
+		dbCreds:       dbCreds,
+		accrual:       deps.services.accrual,
+		accrualRepair: deps.services.accrualRepair,
+		workerPool:    deps.workerPool,
+		dispatcher:    deps.dispatcher,
+		dbState:       deps.dbState,
+		elector:       deps.elector,
+		jobs:          deps.jobs,
+		scheduler:     deps.scheduler,
+		requests:      deps.requests,
+		metrics:       deps.metrics,
+		redisLocks:    deps.redisLocks,
+		redisTokens:   deps.redisTokens,
+		redisNonces:   deps.redisNonces,
+		servers:       servers,
+		flushTraces:   flushTraces,
 	}, nil
 }
 
@@ -69,20 +161,51 @@ func (a *App) Run(ctx context.Context) error {
 	appCtx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	// Запуск worker pool
+	// Отслеживание восстановления БД в режиме деградации
+	go a.dbState.Watch(appCtx, a.db, a.config.DBReconnectInterval)
+
+	// Перечитывание учетных данных БД из файла
+	if a.dbCreds != nil {
+		go a.dbCreds.Watch(appCtx, a.db, a.config.DBCredentialsReloadInterval)
+	}
+
+	// Выбор лидера среди реплик для сканера заказов
+	go a.elector.Run(appCtx)
+
+	// Сверка начислений с журналом после возможного сбоя до запуска воркеров.
+	// Ошибка не прерывает старт: сверку повторит задача расписания
+	if _, err := a.accrualRepair.Sweep(appCtx); err != nil {
+		a.logger.Error("startup accrual repair failed", zap.Error(err))
+	}
+
+	// Запуск worker pool; SIGUSR1 и SIGUSR2 приостанавливают и возобновляют обработку заказов
 	a.workerPool.Start(appCtx)
+	watchWorkerSignals(appCtx, a.workerPool, a.logger)
 	a.logger.Info("worker pool started")
 
-	// Запуск HTTP сервера
-	if err := a.runServer(); err != nil {
+	// Запуск рассылки событий заказов
+	a.dispatcher.Start(appCtx)
+
+	// Запуск периодических задач
+	a.scheduler.Start(appCtx)
+
+	// Запуск HTTP серверов. Ошибка сервера не завершает процесс сразу,
+	// а проходит через graceful shutdown, чтобы остановить воркеры и закрыть БД
+	serverErr, err := a.runServers()
+	if err != nil {
+		a.shutdown(cancel)
 		return err
 	}
 
-	// Ожидание сигнала завершения через контекст
-	<-appCtx.Done()
+	// Ожидание сигнала завершения через контекст или отказа сервера
+	select {
+	case <-appCtx.Done():
+	case err = <-serverErr:
+		a.logger.Error("HTTP server failed", zap.Error(err))
+	}
 
 	// Graceful shutdown
 	a.shutdown(cancel)
 
-	return nil
+	return err
 }
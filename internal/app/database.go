@@ -3,20 +3,45 @@ package app
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/avc/loyalty-system-diploma/internal/config"
 	"github.com/avc/loyalty-system-diploma/internal/repository/postgres"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/zap"
 )
 
-// initDatabase создает пул соединений с базой данных и выполняет миграции
-func initDatabase(ctx context.Context, databaseURI string, logger *zap.Logger) (*pgxpool.Pool, error) {
-	dbPool, err := pgxpool.New(ctx, databaseURI)
+// newPoolConfig разбирает строку подключения к БД. Если она читается из файла,
+// новые соединения открываются с логином и паролем из последней прочитанной версии файла
+func newPoolConfig(cfg *config.Config, logger *zap.Logger) (*pgxpool.Config, *postgres.Credentials, error) {
+	poolConfig, err := pgxpool.ParseConfig(cfg.DatabaseURI)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid database URI: %w", err)
+	}
+	if cfg.DatabaseURIFile == "" {
+		return poolConfig, nil, nil
+	}
+
+	credentials, err := postgres.NewCredentials(func() (string, error) {
+		return config.ReadSecretFile(cfg.DatabaseURIFile)
+	}, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+	poolConfig.BeforeConnect = credentials.BeforeConnect
+	return poolConfig, credentials, nil
+}
+
+// initDatabase создает пул соединений с базой данных и выполняет миграции.
+// Если БД еще не поднялась, подключение повторяется, пока не истечет ctx.
+func initDatabase(ctx context.Context, poolConfig *pgxpool.Config, backoff time.Duration, logger *zap.Logger) (*pgxpool.Pool, error) {
+	dbPool, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
-	if err := dbPool.Ping(ctx); err != nil {
+	if err := waitFor(ctx, "database", backoff, logger, dbPool.Ping); err != nil {
+		dbPool.Close()
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
@@ -26,5 +51,20 @@ func initDatabase(ctx context.Context, databaseURI string, logger *zap.Logger) (
 	}
 	logger.Info("migrations completed successfully")
 
+	warnMissingIndexes(ctx, dbPool, logger)
+
 	return dbPool, nil
 }
+
+// warnMissingIndexes предупреждает об отсутствующих индексах частых запросов.
+// Без них сервис работает, но запросы деградируют с ростом таблиц
+func warnMissingIndexes(ctx context.Context, db postgres.Querier, logger *zap.Logger) {
+	missing, err := postgres.MissingIndexes(ctx, db, postgres.ExpectedIndexes)
+	if err != nil {
+		logger.Warn("failed to check database indexes", zap.Error(err))
+		return
+	}
+	if len(missing) > 0 {
+		logger.Warn("database indexes are missing or invalid, queries may be slow", zap.Strings("indexes", missing))
+	}
+}
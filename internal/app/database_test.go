@@ -0,0 +1,48 @@
+package app
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/avc/loyalty-system-diploma/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestNewPoolConfig(t *testing.T) {
+	t.Run("Static URI", func(t *testing.T) {
+		poolConfig, creds, err := newPoolConfig(&config.Config{DatabaseURI: "postgres://app:secret@db/gophermart"}, zap.NewNop())
+		require.NoError(t, err)
+		assert.Nil(t, creds)
+		assert.Nil(t, poolConfig.BeforeConnect)
+	})
+
+	t.Run("URI from file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "database-uri")
+		require.NoError(t, os.WriteFile(path, []byte("postgres://app:v1@db/gophermart\n"), 0o600))
+
+		poolConfig, creds, err := newPoolConfig(&config.Config{
+			DatabaseURI:     "postgres://app:v1@db/gophermart",
+			DatabaseURIFile: path,
+		}, zap.NewNop())
+		require.NoError(t, err)
+		require.NotNil(t, creds)
+
+		require.NoError(t, os.WriteFile(path, []byte("postgres://app:v2@db/gophermart\n"), 0o600))
+		changed, err := creds.Reload()
+		require.NoError(t, err)
+		assert.True(t, changed)
+
+		connConfig := poolConfig.ConnConfig.Copy()
+		require.NoError(t, poolConfig.BeforeConnect(context.Background(), connConfig))
+		assert.Equal(t, "v2", connConfig.Password)
+	})
+
+	t.Run("Invalid URI", func(t *testing.T) {
+		_, _, err := newPoolConfig(&config.Config{DatabaseURI: "postgres://db:notaport"}, zap.NewNop())
+		assert.Error(t, err)
+	})
+}
@@ -1,11 +1,27 @@
 package app
 
 import (
+	"context"
+	"strings"
+	"time"
+
 	"github.com/avc/loyalty-system-diploma/internal/config"
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/avc/loyalty-system-diploma/internal/events"
 	"github.com/avc/loyalty-system-diploma/internal/handlers"
+	"github.com/avc/loyalty-system-diploma/internal/jobs"
+	"github.com/avc/loyalty-system-diploma/internal/lock"
+	"github.com/avc/loyalty-system-diploma/internal/metrics"
+	"github.com/avc/loyalty-system-diploma/internal/noncestore"
 	"github.com/avc/loyalty-system-diploma/internal/repository/postgres"
+	"github.com/avc/loyalty-system-diploma/internal/scheduler"
 	"github.com/avc/loyalty-system-diploma/internal/service"
+	"github.com/avc/loyalty-system-diploma/internal/tokenstore"
+	"github.com/avc/loyalty-system-diploma/internal/utils/envelope"
 	"github.com/avc/loyalty-system-diploma/internal/utils/jwt"
+	"github.com/avc/loyalty-system-diploma/internal/utils/oauth"
+	"github.com/avc/loyalty-system-diploma/internal/utils/oidc"
+	"github.com/avc/loyalty-system-diploma/internal/utils/ordernum"
 	"github.com/avc/loyalty-system-diploma/internal/utils/password"
 	"github.com/avc/loyalty-system-diploma/internal/worker"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -14,25 +30,72 @@ import (
 
 // repositories содержит все репозитории приложения
 type repositories struct {
-	user        service.UserRepository
-	order       service.OrderRepository
-	transaction service.TransactionRepository
+	user             service.UserRepository
+	userAdmin        service.UserAdminRepository
+	userContacts     service.UserContactsRepository
+	userProfile      service.UserProfileRepository
+	userData         service.UserDataRepository
+	order            service.OrderRepository
+	transaction      service.TransactionRepository
+	withdrawalLimit  service.WithdrawalLimitRepository
+	orderEvent       events.EventStore
+	settlement       service.SettlementRepository
+	orderExpiry      service.OrderExpiryRepository
+	processingStatus service.ProcessingStatusRepository
+	submissionBan    service.SubmissionBanRepository
+	rewardRule       service.RewardRuleRepository
+	accrualRepair    service.AccrualRepairRepository
+	refreshToken     service.RefreshTokenRepository
+	revokedToken     service.RevokedTokenRepository
 }
 
 // services содержит все сервисы приложения
 type services struct {
-	auth    *service.AuthService
-	order   *service.OrderService
-	balance *service.BalanceService
-	accrual service.AccrualClient
+	auth          *service.AuthService
+	order         *service.OrderService
+	balance       *service.BalanceService
+	accrual       accrualClient
+	userAdmin     *service.UserAdminService
+	oauth         *service.OAuthService
+	corrections   *service.AccrualCorrectionService
+	contacts      *service.UserContactsService
+	profile       *service.ProfileService
+	settlements   *service.SettlementService
+	orderExpiry   *service.OrderExpiryService
+	processing    *service.ProcessingStatusService
+	userExport    *service.UserExportService
+	submissions   *service.SubmissionGuard
+	rewardRules   *service.RewardRuleService
+	accrualRepair *service.AccrualRepairService
 }
 
 // handlerSet содержит все хендлеры приложения
 type handlerSet struct {
-	auth    *handlers.AuthHandler
-	orders  *handlers.OrdersHandler
-	balance *handlers.BalanceHandler
-	health  *handlers.HealthHandler
+	auth             *handlers.AuthHandler
+	orders           *handlers.OrdersHandler
+	balance          *handlers.BalanceHandler
+	health           *handlers.HealthHandler
+	admin            *handlers.AdminHandler
+	withdrawalLimits *handlers.WithdrawalLimitsHandler
+	adminOrders      *handlers.AdminOrdersHandler
+	config           *handlers.ConfigHandler
+	reports          *handlers.ReportsHandler
+	corrections      *handlers.AccrualCorrectionsHandler
+	transfers        *handlers.OrderTransfersHandler
+	merges           *handlers.UserMergesHandler
+	oauth            *handlers.OAuthHandler
+	orderWait        *handlers.OrderWaitHandler
+	contacts         *handlers.ContactsHandler
+	profile          *handlers.ProfileHandler
+	internalOrders   *handlers.InternalOrdersHandler
+	settlements      *handlers.SettlementsHandler
+	processingStatus *handlers.ProcessingStatusHandler
+	impersonation    *handlers.ImpersonationHandler
+	userExport       *handlers.UserExportHandler
+	submissionBans   *handlers.SubmissionBansHandler
+	disputes         *handlers.OrderDisputesHandler
+	rewardRules      *handlers.RewardRulesHandler
+	workerControl    *handlers.WorkerControlHandler
 }
 
 // dependencies содержит все зависимости приложения
@@ -40,55 +103,395 @@ type dependencies struct {
 	repos       *repositories
 	services    *services
 	handlers    *handlerSet
-	jwtManager  *jwt.Manager
 	workerPool  *worker.Pool
+	dispatcher  *events.Dispatcher
+	metrics     *metrics.Metrics
+	dbState     *postgres.Availability
+	elector     *postgres.LeaderElector
+	jobs        *jobs.Manager
+	scheduler   *scheduler.Scheduler
+	requests    *handlers.RequestTracker
+	redisLocks  *lock.RedisBackend     // nil, если блокировки хранятся в БД
+	redisTokens *tokenstore.RedisStore // nil, если refresh-токены хранятся не в Redis
+	nonces      handlers.NonceStore
+	redisNonces *noncestore.RedisStore // nil, если nonce запросов хранятся не в Redis
+}
+
+// schedulerLockKey - ключ advisory lock, за который соревнуются реплики.
+// Лидер запускает сканер заказов и остальные периодические задачи.
+const schedulerLockKey int64 = 0x676f706865726d61 // "gopherma"
+
+// jobResultTTL - время хранения результатов административных задач
+const jobResultTTL = time.Hour
+
+// accrualClient - клиент системы начислений по HTTP или gRPC
+type accrualClient interface {
+	service.AccrualClient
+	Ping(ctx context.Context) error
+	Close() error
 }
 
 // initDependencies создает все зависимости приложения
-func initDependencies(cfg *config.Config, dbPool *pgxpool.Pool, logger *zap.Logger) *dependencies {
+func initDependencies(cfg *config.Config, dbPool *pgxpool.Pool, logger *zap.Logger) (*dependencies, error) {
+	appMetrics := metrics.New()
+	dbState := postgres.NewAvailability(logger)
+
 	// Создание репозиториев
+	// Обертка нужна и без логирования: она учитывает длительность запросов в метриках
+	db := postgres.WithQueryLogging(dbPool, postgres.QueryLoggerConfig{
+		LogQueries:       cfg.LogSQL,
+		SlowThreshold:    cfg.SlowQueryThreshold,
+		Metrics:          appMetrics,
+		ExplainThreshold: explainThreshold(cfg, logger),
+	})
+	// Повторы снаружи логирования, чтобы каждая попытка попала в лог
+	db = postgres.WithRetry(db, postgres.RetryConfig{
+		MaxAttempts:    cfg.DBRetryAttempts,
+		InitialBackoff: cfg.DBRetryBackoff,
+		MaxBackoff:     cfg.DBRetryMaxBackoff,
+		Availability:   dbState,
+	})
+	userRepo := postgres.NewUserRepository(db, piiCipher(cfg, logger))
+	orderRepo := postgres.NewOrderRepository(db)
 	repos := &repositories{
-		user:        postgres.NewUserRepository(dbPool),
-		order:       postgres.NewOrderRepository(dbPool),
-		transaction: postgres.NewTransactionRepository(dbPool),
+		user:             userRepo,
+		userAdmin:        userRepo,
+		userContacts:     userRepo,
+		userProfile:      userRepo,
+		userData:         userRepo,
+		order:            orderRepo,
+		transaction:      postgres.NewTransactionRepository(db),
+		withdrawalLimit:  postgres.NewWithdrawalLimitRepository(db),
+		orderEvent:       postgres.NewOrderEventRepository(db),
+		settlement:       postgres.NewSettlementRepository(db),
+		orderExpiry:      orderRepo,
+		processingStatus: orderRepo,
+		submissionBan:    postgres.NewSubmissionBanRepository(db),
+		rewardRule:       postgres.NewRewardRuleRepository(db),
+		accrualRepair:    orderRepo,
+		revokedToken:     postgres.NewRevokedTokenRepository(db),
+	}
+	refreshTokens, redisTokens, err := newRefreshTokenStore(cfg, db, logger)
+	if err != nil {
+		return nil, err
+	}
+	repos.refreshToken = refreshTokens
+
+	// Блокировки реплик хранятся в Redis, если он задан, иначе в таблице БД
+	var lockBackend lock.Backend = postgres.NewLockRepository(db)
+	var redisLocks *lock.RedisBackend
+	if cfg.LockRedisURL != "" {
+		var err error
+		redisLocks, err = lock.NewRedisBackend(cfg.LockRedisURL)
+		if err != nil {
+			return nil, err
+		}
+		lockBackend = redisLocks
+	}
+	locker := lock.New(lockBackend, lock.Config{TTL: cfg.LockTTL}, logger)
+
+	nonces, redisNonces, err := newNonceStore(cfg, logger)
+	if err != nil {
+		return nil, err
 	}
 
 	// Создание утилит
 	passwordHasher := password.NewBCryptHasher(password.DefaultCost)
 	jwtManager := jwt.NewManager(cfg.JWTSecret, cfg.JWTTokenTTL)
 
+	// Токены корпоративного провайдера принимаются наряду с собственными
+	var externalVerifier service.ExternalTokenVerifier
+	if cfg.OIDCIssuer != "" {
+		externalVerifier = oidc.NewVerifier(oidc.Config{
+			Issuer:   cfg.OIDCIssuer,
+			Audience: cfg.OIDCAudience,
+			JWKSURL:  cfg.OIDCJWKSURL,
+		}, nil)
+	}
+
 	// Создание сервисов
 	authServiceConfig := service.AuthServiceConfig{
 		MinPasswordLength: cfg.MinPasswordLength,
+		AdminLogins:       cfg.AdminLogins,
+		RememberTTL:       cfg.JWTRememberTTL,
+		ImpersonationTTL:  cfg.ImpersonationTTL,
+		RefreshTTL:        cfg.JWTRefreshTTL,
 	}
-	svcs := &services{
-		auth:    service.NewAuthService(repos.user, passwordHasher, jwtManager, authServiceConfig),
-		order:   service.NewOrderService(repos.order),
-		balance: service.NewBalanceService(repos.transaction),
-		accrual: service.NewAccrualClient(cfg.AccrualSystemAddress, logger),
+	// Правила проверены при загрузке конфигурации
+	orderNumberValidators, err := ordernum.NewRegistry(cfg.OrderNumberValidators)
+	if err != nil {
+		return nil, err
+	}
+	orderNumberLimits := service.OrderNumberLimits{
+		MinLength:  cfg.OrderNumberMinLength,
+		MaxLength:  cfg.OrderNumberMaxLength,
+		Validators: orderNumberValidators,
+	}
+	withdrawalLimits := domain.WithdrawalLimits{
+		PerWithdrawal: cfg.WithdrawalMaxAmount,
+		Daily:         cfg.WithdrawalDailyLimit,
+		Monthly:       cfg.WithdrawalMonthlyLimit,
+	}
+	accrualClient, err := newAccrualClient(cfg, appMetrics, logger)
+	if err != nil {
+		return nil, err
 	}
 
-	// Создание handlers
-	hdlrs := &handlerSet{
-		auth:    handlers.NewAuthHandler(svcs.auth, logger),
-		orders:  handlers.NewOrdersHandler(svcs.order, logger),
-		balance: handlers.NewBalanceHandler(svcs.balance, logger),
-		health:  handlers.NewHealthHandler(dbPool, logger),
+	// Сканер работает только на лидере; после избрания он сканирует сразу
+	var workerPool *worker.Pool
+	elector := postgres.NewLeaderElector(postgres.PoolConnector(dbPool), postgres.LeaderConfig{
+		Key:           schedulerLockKey,
+		RenewInterval: cfg.LeaderRenewInterval,
+		OnElected:     func() { workerPool.ScanNow() },
+	}, logger)
+
+	rounding := domain.RoundingPolicy{
+		Mode:      cfg.AccrualRoundingMode,
+		Precision: cfg.AccrualRoundingPrecision,
 	}
 
 	// Создание worker pool
 	workerPoolConfig := worker.PoolConfig{
-		Workers:      cfg.WorkerPoolSize,
-		QueueSize:    cfg.WorkerQueueSize,
-		ScanInterval: cfg.WorkerScanInterval,
+		Workers:            cfg.WorkerPoolSize,
+		QueueSize:          cfg.WorkerQueueSize,
+		ScanInterval:       cfg.WorkerScanInterval,
+		MaxScanInterval:    cfg.WorkerMaxScanInterval,
+		ScanPolicies:       cfg.WorkerScanPolicies,
+		BacklogThreshold:   cfg.WorkerBacklogThreshold,
+		Rounding:           rounding,
+		AccrualCorrections: cfg.AccrualCorrectionsEnabled,
+	}
+	workerPool = worker.NewPool(workerPoolConfig, repos.order, accrualClient, dbState, elector, appMetrics, logger)
+
+	processingStatusConfig := service.DefaultProcessingStatusConfig()
+	submissionGuard := service.NewSubmissionGuard(repos.submissionBan, service.SubmissionGuardConfig{
+		RateLimit:      cfg.OrderSubmitRateLimit,
+		RateWindow:     cfg.OrderSubmitRateWindow,
+		ConflictLimit:  cfg.OrderConflictLimit,
+		ConflictWindow: cfg.OrderConflictWindow,
+		BanDuration:    cfg.OrderSubmitBanDuration,
+	}, appMetrics)
+	authService, err := service.NewAuthService(repos.user, passwordHasher, jwtManager, externalVerifier, repos.refreshToken, repos.revokedToken, authServiceConfig)
+	if err != nil {
+		return nil, err
+	}
+	svcs := &services{
+		auth:        authService,
+		order:       service.NewOrderService(repos.order, workerPool, orderNumberLimits, submissionGuard, locker, appMetrics),
+		balance:     service.NewBalanceService(repos.transaction, repos.order, repos.withdrawalLimit, orderNumberLimits, withdrawalLimits),
+		accrual:     accrualClient,
+		userAdmin:   service.NewUserAdminService(repos.userAdmin, passwordHasher, locker),
+		oauth:       service.NewOAuthService(repos.user, jwtManager, oauth.NewStateSigner(cfg.JWTSecret), oauthProviders(cfg)...),
+		corrections: service.NewAccrualCorrectionService(repos.order, accrualClient, rounding, cfg.AccrualCorrectionsEnabled, locker),
+		contacts:    service.NewUserContactsService(repos.userContacts),
+		profile:     service.NewProfileService(repos.userProfile),
+		settlements: service.NewSettlementService(repos.settlement, locker),
+		orderExpiry: service.NewOrderExpiryService(repos.orderExpiry, service.OrderExpiryConfig{
+			Days:   cfg.OrderExpiryDays,
+			DryRun: cfg.OrderExpiryDryRun,
+		}),
+		processing:    service.NewProcessingStatusService(repos.processingStatus, processingStatusConfig),
+		userExport:    service.NewUserExportService(repos.userData, repos.order, repos.transaction),
+		submissions:   submissionGuard,
+		rewardRules:   service.NewRewardRuleService(rewardRuleSource(accrualClient), repos.rewardRule),
+		accrualRepair: service.NewAccrualRepairService(repos.accrualRepair, locker),
 	}
-	workerPool := worker.NewPool(workerPoolConfig, repos.order, repos.transaction, svcs.accrual, logger)
+
+	// Административные задачи и выгрузки данных пользователей выполняются в фоне,
+	// их состояние хранится в памяти
+	jobManager := jobs.NewManager(jobResultTTL, logger)
+
+	// Периодические задачи выполняет только лидер
+	tasks := scheduler.New(elector, logger)
+	tasks.Every("withdrawal-settlement", cfg.SettlementInterval, svcs.settlements.RunScheduled)
+	expiryInterval := cfg.OrderExpiryInterval
+	if cfg.OrderExpiryDays == 0 {
+		expiryInterval = 0
+	}
+	tasks.Every("order-expiry", expiryInterval, svcs.orderExpiry.RunScheduled)
+	rulesSyncInterval := cfg.AccrualRulesSyncInterval
+	if rulesSyncInterval > 0 && rewardRuleSource(accrualClient) == nil {
+		logger.Warn("ACCRUAL_RULES_SYNC_INTERVAL applies only to the HTTP protocol")
+		rulesSyncInterval = 0
+	}
+	tasks.Every("reward-rules-sync", rulesSyncInterval, svcs.rewardRules.RunScheduled)
+	tasks.Every("accrual-repair", cfg.AccrualRepairInterval, svcs.accrualRepair.RunScheduled)
+	tasks.Every("refresh-token-cleanup", cfg.RefreshTokenCleanupInterval, svcs.auth.PurgeExpiredRefreshTokens)
+
+	// Ожидающие завершения обработки заказа запросы будятся событиями диспетчера
+	orderWaiters := events.NewOrderWaiters()
+
+	// Создание handlers
+	handlers.SetFixedAmountDecimals(cfg.JSONAmountFixedDecimals)
+	hdlrs := &handlerSet{
+		auth:             handlers.NewAuthHandler(svcs.auth, logger),
+		orders:           handlers.NewOrdersHandler(svcs.order, workerPool, logger),
+		balance:          handlers.NewBalanceHandler(svcs.balance, logger),
+		health:           handlers.NewHealthHandler(dbPool, dbState, svcs.accrual, workerPool, logger),
+		admin:            handlers.NewAdminHandler(svcs.userAdmin, jobManager, logger),
+		withdrawalLimits: handlers.NewWithdrawalLimitsHandler(svcs.balance, logger),
+		adminOrders:      handlers.NewAdminOrdersHandler(svcs.order, logger),
+		config:           handlers.NewConfigHandler(cfg.Settings(), logger),
+		reports:          handlers.NewReportsHandler(svcs.order, svcs.balance, svcs.order, logger),
+		corrections:      handlers.NewAccrualCorrectionsHandler(svcs.corrections, logger),
+		transfers:        handlers.NewOrderTransfersHandler(svcs.order, logger),
+		merges:           handlers.NewUserMergesHandler(svcs.userAdmin, logger),
+		oauth:            handlers.NewOAuthHandler(svcs.oauth, svcs.auth, logger),
+		orderWait:        handlers.NewOrderWaitHandler(svcs.order, orderWaiters, logger),
+		contacts:         handlers.NewContactsHandler(svcs.contacts, jobManager, logger),
+		profile:          handlers.NewProfileHandler(svcs.profile, logger),
+		internalOrders:   handlers.NewInternalOrdersHandler(svcs.order, logger),
+		settlements:      handlers.NewSettlementsHandler(svcs.settlements, logger),
+		processingStatus: handlers.NewProcessingStatusHandler(svcs.processing, processingStatusConfig.CacheTTL, logger),
+		impersonation:    handlers.NewImpersonationHandler(svcs.auth, logger),
+		userExport:       handlers.NewUserExportHandler(svcs.userExport, jobManager, logger),
+		submissionBans:   handlers.NewSubmissionBansHandler(svcs.submissions, logger),
+		disputes:         handlers.NewOrderDisputesHandler(svcs.order, logger),
+		rewardRules:      handlers.NewRewardRulesHandler(svcs.rewardRules, logger),
+		workerControl:    handlers.NewWorkerControlHandler(workerPool, logger),
+	}
+
+	// Диспетчер событий заказов из outbox таблицы
+	dispatcher := events.NewDispatcher(repos.orderEvent, events.DispatcherConfig{
+		PollInterval: cfg.EventPollInterval,
+	}, logger)
+	dispatcher.Subscribe("order-waiters", orderWaiters.Handle)
 
 	return &dependencies{
-		repos:      repos,
-		services:   svcs,
-		handlers:   hdlrs,
-		jwtManager: jwtManager,
-		workerPool: workerPool,
+		repos:       repos,
+		services:    svcs,
+		handlers:    hdlrs,
+		workerPool:  workerPool,
+		dispatcher:  dispatcher,
+		metrics:     appMetrics,
+		dbState:     dbState,
+		elector:     elector,
+		jobs:        jobManager,
+		scheduler:   tasks,
+		requests:    handlers.NewRequestTracker(),
+		redisLocks:  redisLocks,
+		redisTokens: redisTokens,
+		nonces:      nonces,
+		redisNonces: redisNonces,
+	}, nil
+}
+
+// newAccrualClient выбирает протокол системы начислений по схеме адреса:
+// grpc:// и grpcs:// - протокол v2, остальные адреса - HTTP
+func newAccrualClient(cfg *config.Config, m *metrics.Metrics, logger *zap.Logger) (accrualClient, error) {
+	scheme, target, _ := strings.Cut(cfg.AccrualSystemAddress, "://")
+	if scheme == "grpc" || scheme == "grpcs" {
+		if cfg.AccrualProxyURL != "" || cfg.AccrualSigningKey != "" {
+			logger.Warn("ACCRUAL_PROXY_URL and ACCRUAL_SIGNING_KEY apply only to the HTTP protocol")
+		}
+		return service.NewGRPCAccrualClient(service.GRPCAccrualConfig{
+			Target: strings.TrimSuffix(target, "/"),
+			TLS:    scheme == "grpcs",
+			CAFile: cfg.AccrualCAFile,
+		}, m, logger)
+	}
+
+	// Прокси, корневые сертификаты и подпись запросов
+	transport, err := service.NewAccrualTransport(service.AccrualTransportConfig{
+		ProxyURL:         cfg.AccrualProxyURL,
+		CAFile:           cfg.AccrualCAFile,
+		SigningKey:       cfg.AccrualSigningKey,
+		SigningClockSkew: cfg.AccrualClockSkew,
+	}, logger)
+	if err != nil {
+		return nil, err
+	}
+	return service.NewAccrualClient(cfg.AccrualSystemAddress, transport, m, logger), nil
+}
+
+// rewardRuleSource возвращает клиент как источник правил вознаграждения.
+// Правила отдает только HTTP API системы начислений
+func rewardRuleSource(client accrualClient) service.RewardRuleSource {
+	if source, ok := client.(service.RewardRuleSource); ok {
+		return source
+	}
+	return nil
+}
+
+// newRefreshTokenStore создает хранилище refresh-токенов, выбранное в REFRESH_TOKEN_STORE.
+// Для Redis возвращается и само хранилище, чтобы проверить и закрыть соединение
+func newRefreshTokenStore(cfg *config.Config, db postgres.DBTX, logger *zap.Logger) (service.RefreshTokenRepository, *tokenstore.RedisStore, error) {
+	switch cfg.RefreshTokenStore {
+	case config.RefreshTokenStoreRedis:
+		store, err := tokenstore.NewRedisStore(cfg.RefreshTokenRedisURL)
+		if err != nil {
+			return nil, nil, err
+		}
+		return store, store, nil
+	case config.RefreshTokenStoreMemory:
+		logger.Warn("refresh tokens are stored in memory: they are lost on restart and not shared between replicas")
+		return tokenstore.NewMemoryStore(), nil, nil
+	default:
+		return postgres.NewRefreshTokenRepository(db), nil, nil
+	}
+}
+
+// newNonceStore создает хранилище nonce защиты от повтора запросов, выбранное в REPLAY_NONCE_STORE.
+// Для Redis возвращается и само хранилище, чтобы проверить и закрыть соединение
+func newNonceStore(cfg *config.Config, logger *zap.Logger) (handlers.NonceStore, *noncestore.RedisStore, error) {
+	if cfg.ReplayNonceStore == config.ReplayNonceStoreRedis {
+		store, err := noncestore.NewRedisStore(cfg.ReplayNonceRedisURL)
+		if err != nil {
+			return nil, nil, err
+		}
+		return store, store, nil
+	}
+	if cfg.ReplayWindow > 0 {
+		logger.Warn("request nonces are stored in memory: replays sent to another replica are not detected")
+	}
+	return noncestore.NewMemoryStore(), nil, nil
+}
+
+// piiCipher создает шифрование персональных данных, если заданы мастер-ключи
+func piiCipher(cfg *config.Config, logger *zap.Logger) postgres.FieldCipher {
+	if len(cfg.PIIEncryptionKeys) == 0 {
+		logger.Info("PII encryption keys are not set, user contacts are disabled")
+		return nil
+	}
+	keyring, err := envelope.NewKeyring(cfg.PIIEncryptionKeys)
+	if err != nil {
+		// Ключи проверены при загрузке конфигурации
+		logger.Error("failed to create PII keyring, user contacts are disabled", zap.Error(err))
+		return nil
+	}
+	return keyring
+}
+
+// explainThreshold возвращает порог EXPLAIN для медленных запросов. В production планы
+// не строятся: повторный запрос нагружает и без того медленную БД
+func explainThreshold(cfg *config.Config, logger *zap.Logger) time.Duration {
+	if cfg.SlowQueryExplainThreshold > 0 && cfg.LogLevel == "production" {
+		logger.Warn("SLOW_QUERY_EXPLAIN_THRESHOLD is ignored in production")
+		return 0
+	}
+	return cfg.SlowQueryExplainThreshold
+}
+
+// oauthProviders создает провайдеров входа через социальные сети, для которых задан client ID
+func oauthProviders(cfg *config.Config) []service.OAuthProvider {
+	redirectURL := func(provider string) string {
+		return cfg.OAuthRedirectBaseURL + "/api/user/oauth/" + provider + "/callback"
+	}
+
+	var providers []service.OAuthProvider
+	if cfg.OAuthGoogleClientID != "" {
+		providers = append(providers, oauth.NewGoogle(oauth.Config{
+			ClientID:     cfg.OAuthGoogleClientID,
+			ClientSecret: cfg.OAuthGoogleClientSecret,
+			RedirectURL:  redirectURL("google"),
+		}, nil))
+	}
+	if cfg.OAuthVKClientID != "" {
+		providers = append(providers, oauth.NewVK(oauth.Config{
+			ClientID:     cfg.OAuthVKClientID,
+			ClientSecret: cfg.OAuthVKClientSecret,
+			RedirectURL:  redirectURL("vk"),
+		}, nil))
 	}
+	return providers
 }
@@ -0,0 +1,35 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/avc/loyalty-system-diploma/internal/config"
+	"github.com/avc/loyalty-system-diploma/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestNewAccrualClient(t *testing.T) {
+	tests := []struct {
+		address string
+		want    any
+	}{
+		{"http://accrual:8080", &service.HTTPAccrualClient{}},
+		{"https://accrual.example.com", &service.HTTPAccrualClient{}},
+		{"grpc://accrual:9090", &service.GRPCAccrualClient{}},
+		{"grpcs://accrual.example.com:443/", &service.GRPCAccrualClient{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			client, err := newAccrualClient(&config.Config{AccrualSystemAddress: tt.address}, nil, zap.NewNop())
+			require.NoError(t, err)
+			defer client.Close()
+			assert.IsType(t, tt.want, client)
+		})
+	}
+
+	_, err := newAccrualClient(&config.Config{AccrualSystemAddress: "http://accrual:8080", AccrualProxyURL: "ftp://proxy"}, nil, zap.NewNop())
+	assert.Error(t, err)
+}
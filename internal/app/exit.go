@@ -0,0 +1,38 @@
+package app
+
+import "errors"
+
+// Коды завершения процесса. По ним оркестратор отличает ошибки, которые
+// перезапуск не исправит (конфигурация), от временных (БД, занятый порт)
+const (
+	ExitOK       = 0
+	ExitFailure  = 1 // Прочие ошибки
+	ExitConfig   = 2
+	ExitDatabase = 3
+	ExitListen   = 4
+)
+
+var (
+	// ErrConfig - некорректная конфигурация
+	ErrConfig = errors.New("invalid configuration")
+	// ErrDatabase - база данных недоступна или миграции не применились
+	ErrDatabase = errors.New("database unavailable")
+	// ErrListen - HTTP сервер не смог принять соединения
+	ErrListen = errors.New("http server failed")
+)
+
+// ExitCode возвращает код завершения процесса для ошибки NewApp или Run
+func ExitCode(err error) int {
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.Is(err, ErrConfig):
+		return ExitConfig
+	case errors.Is(err, ErrDatabase):
+		return ExitDatabase
+	case errors.Is(err, ErrListen):
+		return ExitListen
+	default:
+		return ExitFailure
+	}
+}
@@ -0,0 +1,28 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "no error", err: nil, want: ExitOK},
+		{name: "config", err: fmt.Errorf("%w: failed to load config: %w", ErrConfig, errors.New("bad flag")), want: ExitConfig},
+		{name: "database", err: fmt.Errorf("%w: %w", ErrDatabase, errors.New("connection refused")), want: ExitDatabase},
+		{name: "listen", err: fmt.Errorf("%w: %w", ErrListen, errors.New("address already in use")), want: ExitListen},
+		{name: "other", err: errors.New("boom"), want: ExitFailure},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ExitCode(tt.err))
+		})
+	}
+}
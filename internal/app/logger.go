@@ -3,7 +3,9 @@ package app
 import (
 	"fmt"
 
+	"github.com/avc/loyalty-system-diploma/internal/config"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 // initLogger создает и настраивает логгер
@@ -23,3 +25,21 @@ func initLogger(logLevel string) (*zap.Logger, error) {
 
 	return logger, nil
 }
+
+// logEffectiveConfig выводит при старте действующую конфигурацию: значение и источник
+// каждого параметра (флаг, env или значение по умолчанию), секреты скрыты
+func logEffectiveConfig(logger *zap.Logger, cfg *config.Config) {
+	logger.Info("effective configuration", zap.Object("config", zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+		for _, setting := range cfg.Settings() {
+			err := enc.AddObject(setting.Name, zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+				enc.AddString("value", setting.Value)
+				enc.AddString("source", string(setting.Source))
+				return nil
+			}))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})))
+}
@@ -1,51 +1,247 @@
 package app
 
 import (
+	"net/http"
+
+	"github.com/avc/loyalty-system-diploma/internal/config"
 	"github.com/avc/loyalty-system-diploma/internal/handlers"
-	"github.com/avc/loyalty-system-diploma/internal/utils/jwt"
 	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/chi/v5/middleware"
 	"go.uber.org/zap"
 )
 
-// setupRouter создает и настраивает роутер
-func setupRouter(deps *dependencies, jwtManager *jwt.Manager, logger *zap.Logger) *chi.Mux {
+// routeWeights - вес маршрутов в лимите одновременных запросов, остальные маршруты весят 1.
+// Тяжелые выборки держат соединение с БД дольше обычного запроса. Проверки состояния
+// и метрики не ограничиваются, чтобы перегруженный экземпляр не сочли мертвым;
+// long polling почти все время ждет без соединения с БД.
+var routeWeights = map[string]int{
+	"GET /health":                                  0,
+	"GET /ready":                                   0,
+	"GET /health/dependencies":                     0,
+	"GET /metrics":                                 0,
+	"GET /api/user/orders/{number}/wait":           0,
+	"GET /api/user/orders":                         2,
+	"GET /api/user/withdrawals":                    2,
+	"GET /api/admin/orders":                        5,
+	"GET /api/admin/reports/accrual-mismatches":    5,
+	"GET /api/admin/reports/ledger-integrity":      5,
+	"GET /api/admin/reports/duplicate-submissions": 5,
+	"POST /api/admin/users/merge":                  5,
+	"POST /api/admin/settlements":                  5,
+	"GET /api/admin/settlements/{id}/file":         5,
+	"POST /api/internal/orders/status":             5,
+}
+
+// setupRouter создает роутер со всеми маршрутами для конфигурации без внутреннего слушателя
+func setupRouter(cfg *config.Config, deps *dependencies, logger *zap.Logger) *chi.Mux {
 	r := chi.NewRouter()
 
 	// Глобальные middleware
-	setupMiddleware(r, logger)
+	setupMiddleware(r, cfg, deps, logger)
 
 	// Маршруты
-	setupRoutes(r, deps, jwtManager)
+	setupServiceRoutes(r, deps)
+	setupUserRoutes(r, cfg, deps, logger)
+	setupAdminRoutes(r, cfg, deps, logger)
+	setupInternalRoutes(r, cfg, deps, logger)
 
+	setupFallbacks(r)
 	return r
 }
 
-// setupMiddleware настраивает middleware для роутера
-func setupMiddleware(r *chi.Mux, logger *zap.Logger) {
+// setupPublicRouter создает роутер публичного API, когда служебные маршруты
+// вынесены на внутренний слушатель
+func setupPublicRouter(cfg *config.Config, deps *dependencies, logger *zap.Logger) *chi.Mux {
+	r := chi.NewRouter()
+	setupMiddleware(r, cfg, deps, logger)
+	setupUserRoutes(r, cfg, deps, logger)
+	setupFallbacks(r)
+	return r
+}
+
+// setupInternalRouter создает роутер внутреннего слушателя. Его клиенты - мониторинг,
+// администраторы и внутренние сервисы, поэтому лимиты публичного API на него не действуют
+func setupInternalRouter(cfg *config.Config, deps *dependencies, logger *zap.Logger) *chi.Mux {
+	r := chi.NewRouter()
+	setupCommonMiddleware(r, cfg, deps, logger)
+	r.Use(compressionMiddleware(cfg))
+
+	setupServiceRoutes(r, deps)
+	setupAdminRoutes(r, cfg, deps, logger)
+	setupInternalRoutes(r, cfg, deps, logger)
+
+	setupFallbacks(r)
+	return r
+}
+
+// setupFallbacks настраивает JSON ответы для неизвестных маршрутов и методов
+func setupFallbacks(r *chi.Mux) {
+	r.NotFound(handlers.NotFoundHandler())
+	r.MethodNotAllowed(handlers.MethodNotAllowedHandler(r))
+}
+
+// setupMiddleware настраивает middleware публичного API
+func setupMiddleware(r *chi.Mux, cfg *config.Config, deps *dependencies, logger *zap.Logger) {
+	setupCommonMiddleware(r, cfg, deps, logger)
+	r.Use(handlers.LoadShedMiddleware(handlers.LoadShedConfig{
+		MaxInFlight: cfg.MaxInFlightRequests,
+		Weigher:     handlers.RouteWeights(r, routeWeights),
+	}, deps.metrics, logger))
+	r.Use(handlers.RateLimitMiddleware(handlers.RateLimitConfig{
+		Name:   "requests",
+		Limit:  cfg.RateLimitRequests,
+		Window: cfg.RateLimitWindow,
+	}, deps.metrics))
+	r.Use(compressionMiddleware(cfg))
+}
+
+// setupCommonMiddleware настраивает middleware, общие для всех слушателей
+func setupCommonMiddleware(r *chi.Mux, cfg *config.Config, deps *dependencies, logger *zap.Logger) {
+	r.Use(deps.requests.Middleware)
+	r.Use(handlers.RealIPMiddleware(cfg.TrustedProxies))
 	r.Use(handlers.RequestIDMiddleware())
-	r.Use(handlers.LoggingMiddleware(logger))
+	r.Use(handlers.ContextLoggerMiddleware(logger))
+	r.Use(handlers.TracingMiddleware())
+	r.Use(handlers.LocaleMiddleware())
+	r.Use(handlers.SlowRequestMiddleware(logger, cfg.SlowRequestThreshold, deps.metrics))
+	r.Use(handlers.LoggingMiddleware(logger, deps.metrics))
 	r.Use(handlers.RecoveryMiddleware(logger))
-	r.Use(middleware.Compress(5))
+	r.Use(handlers.MethodsMiddleware(r))
 }
 
-// setupRoutes настраивает маршруты приложения
-func setupRoutes(r *chi.Mux, deps *dependencies, jwtManager *jwt.Manager) {
+// compressionMiddleware создает middleware сжатия ответов
+func compressionMiddleware(cfg *config.Config) func(http.Handler) http.Handler {
+	return handlers.CompressionMiddleware(handlers.CompressionConfig{
+		Level:                cfg.CompressionLevel,
+		MinSize:              cfg.CompressionMinSize,
+		ExcludedContentTypes: cfg.CompressionExcludedTypes,
+		ExcludedPaths:        cfg.CompressionExcludedPaths,
+	})
+}
+
+// setupServiceRoutes настраивает проверки состояния и метрики
+func setupServiceRoutes(r chi.Router, deps *dependencies) {
 	// Health check эндпоинты
 	r.Get("/health", deps.handlers.health.Health)
 	r.Get("/ready", deps.handlers.health.Ready)
+	r.Get("/health/dependencies", deps.handlers.health.Dependencies)
+
+	// Метрики Prometheus
+	r.Handle("/metrics", deps.metrics.Handler())
+}
+
+// moneyRoutes - маршруты, которые начисляют, списывают или передают баллы. Решения
+// об авторизации запросов к ним записываются в журнал аудита
+var moneyRoutes = []string{
+	"POST /api/user/orders",
+	"POST /api/user/orders/receipt",
+	"POST /api/user/orders/{number}/dispute",
+	"POST /api/user/balance/withdraw",
+	"POST /api/admin/users/merge",
+	"POST /api/admin/orders/{number}/accrual/recheck",
+	"POST /api/admin/orders/{number}/transfer",
+	"POST /api/admin/disputes/{id}/resolve",
+}
+
+// authzAudit записывает решения об авторизации запросов к moneyRoutes
+func authzAudit(cfg *config.Config, logger *zap.Logger) func(http.Handler) http.Handler {
+	return handlers.AuthzAuditMiddleware(handlers.AuthzAuditConfig{
+		Routes:      moneyRoutes,
+		SampleRatio: cfg.AuthzAuditSampleRatio,
+	}, logger.Named("audit"))
+}
 
+// setupUserRoutes настраивает маршруты пользователей
+func setupUserRoutes(r chi.Router, cfg *config.Config, deps *dependencies, logger *zap.Logger) {
 	// Публичные эндпоинты
 	r.Post("/api/user/register", deps.handlers.auth.Register)
 	r.Post("/api/user/login", deps.handlers.auth.Login)
+	r.Post("/api/user/refresh", deps.handlers.auth.Refresh)
+	// Проверку логина ограничиваем сильнее общего лимита: она публичная и вызывается при каждом вводе
+	r.With(handlers.RateLimitMiddleware(handlers.RateLimitConfig{
+		Name:   "availability",
+		Limit:  cfg.AvailabilityRateLimit,
+		Window: cfg.RateLimitWindow,
+	}, deps.metrics)).Get("/api/user/availability", deps.handlers.auth.Availability)
+	r.Get("/api/user/oauth/{provider}/login", deps.handlers.oauth.Login)
+	r.Get("/api/user/oauth/{provider}/callback", deps.handlers.oauth.Callback)
+	r.Get("/api/status/processing", deps.handlers.processingStatus.Get)
 
 	// Защищенные эндпоинты
 	r.Group(func(r chi.Router) {
-		r.Use(handlers.AuthMiddleware(jwtManager))
+		r.Use(handlers.AuthMiddleware(deps.services.auth, deps.metrics))
+		r.Use(authzAudit(cfg, logger))
+		r.Use(handlers.ImpersonationMiddleware(cfg.ImpersonationReadOnly, logger.Named("audit")))
+		r.Use(handlers.UserLocaleMiddleware(deps.services.profile))
 		r.Post("/api/user/orders", deps.handlers.orders.SubmitOrder)
+		r.Post("/api/user/orders/receipt", deps.handlers.orders.SubmitFiscalReceipt)
 		r.Get("/api/user/orders", deps.handlers.orders.GetOrders)
+		r.Get("/api/user/orders/{id}", deps.handlers.orders.GetOrder)
+		r.Get("/api/user/orders/{number}/wait", deps.handlers.orderWait.Wait)
+		r.Post("/api/user/orders/{number}/dispute", deps.handlers.disputes.Open)
 		r.Get("/api/user/balance", deps.handlers.balance.GetBalance)
 		r.Post("/api/user/balance/withdraw", deps.handlers.balance.Withdraw)
 		r.Get("/api/user/withdrawals", deps.handlers.balance.GetWithdrawals)
+		r.Get("/api/user/withdrawals/summary", deps.handlers.balance.GetWithdrawalSummary)
+		r.Get("/api/user/withdrawals/{id}", deps.handlers.balance.GetWithdrawal)
+		r.Get("/api/user/contacts", deps.handlers.contacts.Get)
+		r.Put("/api/user/contacts", deps.handlers.contacts.Update)
+		r.Get("/api/user/profile", deps.handlers.profile.Get)
+		r.Put("/api/user/profile", deps.handlers.profile.Update)
+		r.Get("/api/user/export", deps.handlers.userExport.Export)
+		r.Get("/api/user/export/{id}", deps.handlers.userExport.Get)
+		r.Get("/api/user/export/{id}/download", deps.handlers.userExport.Download)
+	})
+	// Выход не проходит ImpersonationMiddleware: администратор может отозвать токен
+	// от имени пользователя и в режиме только для чтения
+	r.With(handlers.AuthMiddleware(deps.services.auth, deps.metrics)).Post("/api/user/logout", deps.handlers.auth.Logout)
+}
+
+// setupAdminRoutes настраивает административные маршруты
+func setupAdminRoutes(r chi.Router, cfg *config.Config, deps *dependencies, logger *zap.Logger) {
+	r.Group(func(r chi.Router) {
+		r.Use(handlers.IPFilterMiddleware(handlers.IPFilterConfig{
+			Allowed: cfg.AdminAllowedCIDRs,
+			Denied:  cfg.AdminDeniedCIDRs,
+		}, logger))
+		r.Use(handlers.AuthMiddleware(deps.services.auth, deps.metrics))
+		r.Use(authzAudit(cfg, logger))
+		r.Use(handlers.AdminMiddleware(deps.services.auth, logger))
+		r.Post("/api/admin/users/import", deps.handlers.admin.ImportUsers)
+		r.Post("/api/admin/users/export", deps.handlers.admin.ExportUsers)
+		r.Post("/api/admin/users/merge", deps.handlers.merges.Merge)
+		r.Post("/api/admin/users/contacts/rewrap", deps.handlers.contacts.Rewrap)
+		r.Post("/api/admin/impersonate/{userID}", deps.handlers.impersonation.Impersonate)
+		r.Get("/api/admin/jobs/{id}", deps.handlers.admin.GetJob)
+		r.Get("/api/admin/jobs/{id}/result", deps.handlers.admin.GetJobResult)
+		r.Get("/api/admin/users/{login}/withdrawal-limits", deps.handlers.withdrawalLimits.Get)
+		r.Put("/api/admin/users/{login}/withdrawal-limits", deps.handlers.withdrawalLimits.Set)
+		r.Delete("/api/admin/users/{login}/withdrawal-limits", deps.handlers.withdrawalLimits.Reset)
+		r.Get("/api/admin/submission-bans", deps.handlers.submissionBans.List)
+		r.Delete("/api/admin/users/{login}/submission-ban", deps.handlers.submissionBans.Lift)
+		r.Get("/api/admin/orders", deps.handlers.adminOrders.Search)
+		r.Post("/api/admin/orders/{number}/accrual/recheck", deps.handlers.corrections.Recheck)
+		r.Post("/api/admin/orders/{number}/transfer", deps.handlers.transfers.Transfer)
+		r.Get("/api/admin/disputes", deps.handlers.disputes.List)
+		r.Post("/api/admin/disputes/{id}/resolve", deps.handlers.disputes.Resolve)
+		r.Get("/api/admin/reward-rules", deps.handlers.rewardRules.List)
+		r.Get("/api/admin/worker", deps.handlers.workerControl.Status)
+		r.Post("/api/admin/worker/pause", deps.handlers.workerControl.Pause)
+		r.Post("/api/admin/worker/resume", deps.handlers.workerControl.Resume)
+		r.Get("/api/admin/config", deps.handlers.config.Get)
+		r.Get("/api/admin/reports/accrual-mismatches", deps.handlers.reports.AccrualMismatches)
+		r.Get("/api/admin/reports/ledger-integrity", deps.handlers.reports.LedgerIntegrity)
+		r.Get("/api/admin/reports/duplicate-submissions", deps.handlers.reports.DuplicateSubmissions)
+		r.Post("/api/admin/settlements", deps.handlers.settlements.Create)
+		r.Get("/api/admin/settlements", deps.handlers.settlements.List)
+		r.Get("/api/admin/settlements/{id}/file", deps.handlers.settlements.File)
+	})
+}
+
+// setupInternalRoutes настраивает эндпоинты для внутренних сервисов, доступ по сервисному токену
+func setupInternalRoutes(r chi.Router, cfg *config.Config, deps *dependencies, logger *zap.Logger) {
+	r.Group(func(r chi.Router) {
+		r.Use(handlers.ServiceTokenMiddleware(cfg.InternalServiceTokens, logger))
+		r.Use(handlers.ReplayProtectionMiddleware(handlers.ReplayProtectionConfig{Window: cfg.ReplayWindow}, deps.nonces, logger))
+		r.Post("/api/internal/orders/status", deps.handlers.internalOrders.Statuses)
 	})
 }
@@ -0,0 +1,200 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/avc/loyalty-system-diploma/internal/config"
+	"github.com/avc/loyalty-system-diploma/internal/handlers"
+	"github.com/avc/loyalty-system-diploma/internal/metrics"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// newTestRouter собирает роутер без сервисов: проверки маршрутизации
+// не доходят до обработчиков
+func newTestRouter() *chi.Mux {
+	deps := &dependencies{
+		services: &services{},
+		handlers: &handlerSet{},
+		metrics:  metrics.New(),
+		requests: handlers.NewRequestTracker(),
+	}
+	return setupRouter(&config.Config{}, deps, zap.NewNop())
+}
+
+func TestRouter_MethodNotAllowed(t *testing.T) {
+	router := newTestRouter()
+
+	routes := map[string][]string{
+		"/health":                                  {http.MethodGet},
+		"/ready":                                   {http.MethodGet},
+		"/health/dependencies":                     {http.MethodGet},
+		"/api/user/register":                       {http.MethodPost},
+		"/api/user/login":                          {http.MethodPost},
+		"/api/user/refresh":                        {http.MethodPost},
+		"/api/user/logout":                         {http.MethodPost},
+		"/api/user/availability":                   {http.MethodGet},
+		"/api/user/oauth/google/login":             {http.MethodGet},
+		"/api/user/oauth/google/callback":          {http.MethodGet},
+		"/api/status/processing":                   {http.MethodGet},
+		"/api/user/orders":                         {http.MethodGet, http.MethodPost},
+		"/api/user/orders/1":                       {http.MethodGet},
+		"/api/user/orders/1/wait":                  {http.MethodGet},
+		"/api/user/orders/1/dispute":               {http.MethodPost},
+		"/api/user/orders/receipt":                 {http.MethodGet, http.MethodPost},
+		"/api/user/balance":                        {http.MethodGet},
+		"/api/user/balance/withdraw":               {http.MethodPost},
+		"/api/user/withdrawals":                    {http.MethodGet},
+		"/api/user/withdrawals/1":                  {http.MethodGet},
+		"/api/user/withdrawals/summary":            {http.MethodGet},
+		"/api/user/contacts":                       {http.MethodGet, http.MethodPut},
+		"/api/user/profile":                        {http.MethodGet, http.MethodPut},
+		"/api/user/export":                         {http.MethodGet},
+		"/api/user/export/1":                       {http.MethodGet},
+		"/api/user/export/1/download":              {http.MethodGet},
+		"/api/admin/users/import":                  {http.MethodPost},
+		"/api/admin/users/export":                  {http.MethodPost},
+		"/api/admin/jobs/1":                        {http.MethodGet},
+		"/api/admin/jobs/1/result":                 {http.MethodGet},
+		"/api/admin/users/alice/withdrawal-limits": {http.MethodGet, http.MethodPut, http.MethodDelete},
+		"/api/admin/submission-bans":               {http.MethodGet},
+		"/api/admin/users/alice/submission-ban":    {http.MethodDelete},
+		"/api/admin/orders":                        {http.MethodGet},
+		"/api/admin/orders/1/accrual/recheck":      {http.MethodPost},
+		"/api/admin/orders/1/transfer":             {http.MethodPost},
+		"/api/admin/disputes":                      {http.MethodGet},
+		"/api/admin/disputes/1/resolve":            {http.MethodPost},
+		"/api/admin/reward-rules":                  {http.MethodGet},
+		"/api/admin/worker":                        {http.MethodGet},
+		"/api/admin/worker/pause":                  {http.MethodPost},
+		"/api/admin/worker/resume":                 {http.MethodPost},
+		"/api/admin/users/merge":                   {http.MethodPost},
+		"/api/admin/users/contacts/rewrap":         {http.MethodPost},
+		"/api/admin/impersonate/{userID}":          {http.MethodPost},
+		"/api/admin/config":                        {http.MethodGet},
+		"/api/admin/reports/accrual-mismatches":    {http.MethodGet},
+		"/api/admin/reports/ledger-integrity":      {http.MethodGet},
+		"/api/admin/reports/duplicate-submissions": {http.MethodGet},
+		"/api/admin/settlements":                   {http.MethodGet, http.MethodPost},
+		"/api/admin/settlements/1/file":            {http.MethodGet},
+		"/api/internal/orders/status":              {http.MethodPost},
+	}
+	methods := []string{
+		http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+		http.MethodPatch, http.MethodDelete, http.MethodOptions,
+	}
+
+	for path, allowed := range routes {
+		// HEAD обслуживается для GET маршрутов, OPTIONS - для всех
+		var allow []string
+		for _, method := range methods {
+			if slices.Contains(allowed, method) ||
+				method == http.MethodHead && slices.Contains(allowed, http.MethodGet) ||
+				method == http.MethodOptions {
+				allow = append(allow, method)
+			}
+		}
+
+		for _, method := range methods {
+			t.Run(method+" "+path, func(t *testing.T) {
+				if slices.Contains(allowed, method) {
+					assert.True(t, router.Match(chi.NewRouteContext(), method, path))
+					return
+				}
+				if method == http.MethodHead && slices.Contains(allow, method) {
+					assert.True(t, router.Match(chi.NewRouteContext(), http.MethodGet, path))
+					return
+				}
+
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+
+				if method == http.MethodOptions {
+					assert.Equal(t, http.StatusNoContent, w.Code)
+					assert.Equal(t, allow, w.Header().Values("Allow"))
+					return
+				}
+				assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+				assert.Equal(t, allow, w.Header().Values("Allow"))
+				assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+				var body handlers.ErrorResponse
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+				assert.Equal(t, "method not allowed", body.Error)
+			})
+		}
+	}
+}
+
+func TestRouter_NotFound(t *testing.T) {
+	router := newTestRouter()
+
+	for _, path := range []string{"/", "/api", "/api/user", "/api/user/unknown", "/api/user/orders/123/items"} {
+		t.Run(path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+			assert.Equal(t, http.StatusNotFound, w.Code)
+
+			var body handlers.ErrorResponse
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+			assert.Equal(t, "route not found", body.Error)
+		})
+	}
+}
+
+func TestRouteLists_MatchRegisteredRoutes(t *testing.T) {
+	router := newTestRouter()
+
+	registered := make(map[string]bool)
+	require.NoError(t, chi.Walk(router, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		registered[method+" "+route] = true
+		return nil
+	}))
+
+	for key := range routeWeights {
+		assert.True(t, registered[key], "weight for unknown route %q", key)
+	}
+	for _, key := range moneyRoutes {
+		assert.True(t, registered[key], "audit for unknown route %q", key)
+	}
+}
+
+func TestRouter_InternalListener(t *testing.T) {
+	deps := &dependencies{
+		services: &services{},
+		handlers: &handlerSet{},
+		metrics:  metrics.New(),
+		requests: handlers.NewRequestTracker(),
+	}
+	public := setupPublicRouter(&config.Config{}, deps, zap.NewNop())
+	internal := setupInternalRouter(&config.Config{}, deps, zap.NewNop())
+
+	publicRoutes := map[string]string{
+		"/api/user/login":       http.MethodPost,
+		"/api/user/orders":      http.MethodGet,
+		"/api/user/withdrawals": http.MethodGet,
+	}
+	internalRoutes := map[string]string{
+		"/health":                     http.MethodGet,
+		"/ready":                      http.MethodGet,
+		"/metrics":                    http.MethodGet,
+		"/api/admin/config":           http.MethodGet,
+		"/api/internal/orders/status": http.MethodPost,
+	}
+
+	for path, method := range publicRoutes {
+		assert.True(t, public.Match(chi.NewRouteContext(), method, path), path)
+		assert.False(t, internal.Match(chi.NewRouteContext(), method, path), path)
+	}
+	for path, method := range internalRoutes {
+		assert.True(t, internal.Match(chi.NewRouteContext(), method, path), path)
+		assert.False(t, public.Match(chi.NewRouteContext(), method, path), path)
+	}
+}
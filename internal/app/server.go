@@ -2,41 +2,77 @@ package app
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net"
 	"net/http"
-	"time"
+	"sync"
+	"sync/atomic"
 
-	"github.com/go-chi/chi/v5"
+	"github.com/avc/loyalty-system-diploma/internal/config"
+	"github.com/avc/loyalty-system-diploma/internal/handlers"
+	"github.com/avc/loyalty-system-diploma/internal/metrics"
 	"go.uber.org/zap"
 )
 
-const (
-	serverReadTimeout  = 15 * time.Second
-	serverWriteTimeout = 15 * time.Second
-	serverIdleTimeout  = 60 * time.Second
-	shutdownTimeout    = 10 * time.Second
-)
+// createServers создает HTTP серверы для всех адресов публичного API и, если он задан,
+// внутреннего слушателя. У слушателей свои роутеры и цепочки middleware
+func createServers(cfg *config.Config, deps *dependencies, logger *zap.Logger) []*http.Server {
+	internalAddrs := cfg.InternalRunAddresses()
+	if len(internalAddrs) == 0 {
+		return createListenerServers(cfg, cfg.RunAddresses(), setupRouter(cfg, deps, logger))
+	}
+
+	servers := createListenerServers(cfg, cfg.RunAddresses(), setupPublicRouter(cfg, deps, logger))
+	return append(servers, createListenerServers(cfg, internalAddrs, setupInternalRouter(cfg, deps, logger))...)
+}
+
+// createListenerServers создает по серверу на каждый адрес слушателя с общим роутером
+func createListenerServers(cfg *config.Config, addrs []string, handler http.Handler) []*http.Server {
+	servers := make([]*http.Server, 0, len(addrs))
+	for _, addr := range addrs {
+		servers = append(servers, createServer(cfg, addr, handler))
+	}
+	return servers
+}
 
 // createServer создает HTTP сервер
-func createServer(addr string, handler *chi.Mux) *http.Server {
+func createServer(cfg *config.Config, addr string, handler http.Handler) *http.Server {
 	return &http.Server{
 		Addr:         addr,
 		Handler:      handler,
-		ReadTimeout:  serverReadTimeout,
-		WriteTimeout: serverWriteTimeout,
-		IdleTimeout:  serverIdleTimeout,
+		ReadTimeout:  cfg.HTTPReadTimeout,
+		WriteTimeout: cfg.HTTPWriteTimeout,
+		IdleTimeout:  cfg.HTTPIdleTimeout,
+	}
+}
+
+// runServers запускает HTTP серверы. Порты занимаются синхронно, чтобы ошибка
+// вернулась до начала работы; ошибки обслуживания придут в возвращаемый канал
+func (a *App) runServers() (<-chan error, error) {
+	errCh := make(chan error, len(a.servers))
+	for _, server := range a.servers {
+		if err := serve(server, errCh, a.logger); err != nil {
+			return nil, err
+		}
 	}
+	return errCh, nil
 }
 
-// runServer запускает HTTP сервер
-func (a *App) runServer() error {
-	// Запуск HTTP сервера в горутине
+// serve занимает адрес server.Addr и обслуживает запросы в горутине.
+// В errCh должно оставаться место для ошибки этого сервера
+func serve(server *http.Server, errCh chan<- error, logger *zap.Logger) error {
+	listener, err := net.Listen("tcp", server.Addr)
+	if err != nil {
+		return fmt.Errorf("%w: failed to listen on %s: %w", ErrListen, server.Addr, err)
+	}
+	logger.Info("starting HTTP server", zap.String("address", listener.Addr().String()))
+
 	go func() {
-		a.logger.Info("starting HTTP server", zap.String("address", a.server.Addr))
-		if err := a.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			a.logger.Fatal("failed to start server", zap.Error(err))
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- fmt.Errorf("%w: %s: %w", ErrListen, server.Addr, err)
 		}
 	}()
-
 	return nil
 }
 
@@ -45,21 +81,111 @@ func (a *App) shutdown(cancel context.CancelFunc) {
 	a.logger.Info("shutting down server...")
 
 	// Останавливаем прием новых запросов
-	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), a.config.ShutdownTimeout)
 	defer shutdownCancel()
 
-	if err := a.server.Shutdown(shutdownCtx); err != nil {
-		a.logger.Error("server shutdown error", zap.Error(err))
-	}
+	shutdownServers(shutdownCtx, a.servers, a.requests, a.metrics, a.logger)
 
 	// Останавливаем worker pool
 	cancel()
-	a.workerPool.Stop()
-	a.logger.Info("worker pool stopped")
+	abandoned := a.workerPool.Stop()
+	a.logger.Info("worker pool stopped", zap.Int("abandoned_orders", abandoned))
+
+	a.dispatcher.Stop()
+	a.logger.Info("event dispatcher stopped")
+
+	a.scheduler.Stop()
+	a.logger.Info("scheduled tasks stopped")
+
+	// Незавершенные административные задачи отменяются
+	a.jobs.Shutdown()
+	a.logger.Info("background jobs stopped")
+
+	if err := a.accrual.Close(); err != nil {
+		a.logger.Warn("failed to close accrual client", zap.Error(err))
+	}
+
+	if a.redisLocks != nil {
+		if err := a.redisLocks.Close(); err != nil {
+			a.logger.Warn("failed to close redis connection", zap.Error(err))
+		}
+	}
+
+	if a.redisTokens != nil {
+		if err := a.redisTokens.Close(); err != nil {
+			a.logger.Warn("failed to close refresh token redis connection", zap.Error(err))
+		}
+	}
+
+	if a.redisNonces != nil {
+		if err := a.redisNonces.Close(); err != nil {
+			a.logger.Warn("failed to close nonce redis connection", zap.Error(err))
+		}
+	}
 
 	// Закрываем соединение с БД
 	a.db.Close()
 	a.logger.Info("database connection closed")
 
+	// Отправляем накопленные спаны последними: в них попадают и спаны остановки
+	if err := a.flushTraces(shutdownCtx); err != nil {
+		a.logger.Warn("failed to flush traces", zap.Error(err))
+	}
+
 	a.logger.Info("server stopped gracefully")
 }
+
+// shutdownStats - итог остановки HTTP сервера
+type shutdownStats struct {
+	inFlight int64 // Запросы, выполнявшиеся в момент начала остановки
+	drained  int64 // Завершились до истечения ctx
+	aborted  int64 // Прерваны закрытием соединений
+}
+
+// shutdownServers останавливает прием запросов всеми серверами и ждет завершения выполняемых.
+// Запросы, не завершившиеся до истечения ctx, прерываются закрытием соединений.
+func shutdownServers(
+	ctx context.Context,
+	servers []*http.Server,
+	requests *handlers.RequestTracker,
+	m *metrics.Metrics,
+	logger *zap.Logger,
+) shutdownStats {
+	stats := shutdownStats{inFlight: requests.InFlight()}
+	m.ObserveShutdownStarted(stats.inFlight)
+	logger.Info("draining HTTP requests", zap.Int64("in_flight", stats.inFlight))
+
+	// Серверы останавливаются параллельно: ctx - общий бюджет ожидания, а не бюджет каждого
+	var wg sync.WaitGroup
+	var failed atomic.Bool
+	for _, server := range servers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := server.Shutdown(ctx); err != nil {
+				failed.Store(true)
+				logger.Error("server shutdown error", zap.String("address", server.Addr), zap.Error(err))
+			}
+		}()
+	}
+	wg.Wait()
+
+	if failed.Load() {
+		stats.aborted = requests.InFlight()
+		for _, server := range servers {
+			if err := server.Close(); err != nil {
+				logger.Error("failed to close server connections", zap.String("address", server.Addr), zap.Error(err))
+			}
+		}
+	}
+	// Прерванными могут оказаться и запросы, начатые на открытых соединениях после начала остановки
+	stats.drained = max(stats.inFlight-stats.aborted, 0)
+
+	m.ObserveShutdownRequests(stats.drained, stats.aborted)
+	logger.Info("HTTP requests drained",
+		zap.Int64("in_flight", stats.inFlight),
+		zap.Int64("drained", stats.drained),
+		zap.Int64("aborted", stats.aborted),
+	)
+	return stats
+}
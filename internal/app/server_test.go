@@ -0,0 +1,166 @@
+package app
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/config"
+	"github.com/avc/loyalty-system-diploma/internal/handlers"
+	"github.com/avc/loyalty-system-diploma/internal/metrics"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// startTestServer запускает сервер, обработчик которого ждет закрытия release
+func startTestServer(t *testing.T, release <-chan struct{}) (*http.Server, *handlers.RequestTracker, string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	requests := handlers.NewRequestTracker()
+	server := &http.Server{Handler: requests.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))}
+	go server.Serve(listener) //nolint:errcheck // сервер останавливается тестом
+
+	return server, requests, "http://" + listener.Addr().String()
+}
+
+// sendRequest отправляет запрос и ждет, пока сервер начнет его обрабатывать
+func sendRequest(t *testing.T, requests *handlers.RequestTracker, url string, want int64) {
+	go func() {
+		resp, err := http.Get(url) //nolint:noctx // тестовый запрос
+		if err == nil {
+			resp.Body.Close()
+		}
+	}()
+	require.Eventually(t, func() bool { return requests.InFlight() == want }, time.Second, 5*time.Millisecond)
+}
+
+func TestShutdownServer_Drained(t *testing.T) {
+	release := make(chan struct{})
+	server, requests, url := startTestServer(t, release)
+	sendRequest(t, requests, url, 1)
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+	}()
+	stats := shutdownServers(context.Background(), []*http.Server{server}, requests, nil, zap.NewNop())
+
+	assert.Equal(t, shutdownStats{inFlight: 1, drained: 1}, stats)
+}
+
+func TestShutdownServer_Aborted(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	server, requests, url := startTestServer(t, release)
+	sendRequest(t, requests, url, 1)
+	sendRequest(t, requests, url, 2)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	stats := shutdownServers(ctx, []*http.Server{server}, requests, nil, zap.NewNop())
+
+	assert.Equal(t, shutdownStats{inFlight: 2, aborted: 2}, stats)
+}
+
+func TestShutdownServers_Multiple(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	first, requests, url := startTestServer(t, release)
+	second := &http.Server{Handler: requests.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go second.Serve(listener) //nolint:errcheck // сервер останавливается тестом
+
+	sendRequest(t, requests, url, 1)
+	sendRequest(t, requests, "http://"+listener.Addr().String(), 2)
+
+	// Ни один сервер не дожидается своих запросов: оба прерываются по общему бюджету
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	stats := shutdownServers(ctx, []*http.Server{first, second}, requests, nil, zap.NewNop())
+
+	assert.Equal(t, shutdownStats{inFlight: 2, aborted: 2}, stats)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestCreateServers(t *testing.T) {
+	deps := &dependencies{
+		services: &services{},
+		handlers: &handlerSet{},
+		metrics:  metrics.New(),
+		requests: handlers.NewRequestTracker(),
+	}
+
+	t.Run("Single listener", func(t *testing.T) {
+		cfg := &config.Config{RunAddress: "0.0.0.0:8080,[::]:8080"}
+
+		servers := createServers(cfg, deps, zap.NewNop())
+
+		require.Len(t, servers, 2)
+		assert.Equal(t, "0.0.0.0:8080", servers[0].Addr)
+		assert.Equal(t, "[::]:8080", servers[1].Addr)
+		assert.Same(t, servers[0].Handler, servers[1].Handler)
+	})
+
+	t.Run("Internal listener", func(t *testing.T) {
+		cfg := &config.Config{RunAddress: ":8080", InternalRunAddress: "127.0.0.1:9090"}
+
+		servers := createServers(cfg, deps, zap.NewNop())
+
+		require.Len(t, servers, 2)
+		assert.Equal(t, ":8080", servers[0].Addr)
+		assert.Equal(t, "127.0.0.1:9090", servers[1].Addr)
+		assert.NotSame(t, servers[0].Handler, servers[1].Handler)
+	})
+}
+
+func TestCreateServer(t *testing.T) {
+	cfg := &config.Config{
+		HTTPReadTimeout:  5 * time.Second,
+		HTTPWriteTimeout: 2 * time.Minute,
+		HTTPIdleTimeout:  time.Minute,
+	}
+
+	server := createServer(cfg, ":9090", chi.NewRouter())
+
+	assert.Equal(t, ":9090", server.Addr)
+	assert.Equal(t, 5*time.Second, server.ReadTimeout)
+	assert.Equal(t, 2*time.Minute, server.WriteTimeout)
+	assert.Equal(t, time.Minute, server.IdleTimeout)
+}
+
+func TestServe_AddressInUse(t *testing.T) {
+	busy, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer busy.Close()
+
+	server := &http.Server{Addr: busy.Addr().String(), Handler: http.NotFoundHandler()}
+	err = serve(server, make(chan error, 1), zap.NewNop())
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrListen)
+	assert.Equal(t, ExitListen, ExitCode(err))
+}
+
+func TestServe_ShutdownReportsNoError(t *testing.T) {
+	server := &http.Server{Addr: "127.0.0.1:0", Handler: http.NotFoundHandler()}
+	errCh := make(chan error, 1)
+	require.NoError(t, serve(server, errCh, zap.NewNop()))
+
+	require.NoError(t, server.Shutdown(context.Background()))
+	select {
+	case err := <-errCh:
+		t.Fatalf("unexpected server error: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,37 @@
+//go:build unix
+
+package app
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/avc/loyalty-system-diploma/internal/worker"
+	"go.uber.org/zap"
+)
+
+// watchWorkerSignals приостанавливает обработку заказов по SIGUSR1 и возобновляет по SIGUSR2
+// до отмены контекста. Сигналы перехватываются уже после возврата из функции
+func watchWorkerSignals(ctx context.Context, pool *worker.Pool, logger *zap.Logger) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGUSR1, syscall.SIGUSR2)
+
+	go func() {
+		defer signal.Stop(signals)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case sig := <-signals:
+				logger.Info("worker control signal received", zap.Stringer("signal", sig))
+				if sig == syscall.SIGUSR1 {
+					pool.Pause()
+				} else {
+					pool.Resume()
+				}
+			}
+		}
+	}()
+}
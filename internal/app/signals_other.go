@@ -0,0 +1,14 @@
+//go:build !unix
+
+package app
+
+import (
+	"context"
+
+	"github.com/avc/loyalty-system-diploma/internal/worker"
+	"go.uber.org/zap"
+)
+
+// watchWorkerSignals ничего не делает: SIGUSR1 и SIGUSR2 есть только в unix системах,
+// обработка заказов приостанавливается через API администратора
+func watchWorkerSignals(context.Context, *worker.Pool, *zap.Logger) {}
@@ -0,0 +1,33 @@
+//go:build unix
+
+package app
+
+import (
+	"context"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/worker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestWatchWorkerSignals(t *testing.T) {
+	pool := worker.NewPool(worker.DefaultPoolConfig(), nil, nil, nil, nil, nil, zap.NewNop())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	watchWorkerSignals(ctx, pool, zap.NewNop())
+	paused := func() bool {
+		_, paused := pool.PausedSince()
+		return paused
+	}
+
+	require.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGUSR1))
+	assert.Eventually(t, paused, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGUSR2))
+	assert.Eventually(t, func() bool { return !paused() }, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,45 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// startupMaxBackoff ограничивает паузу между проверками зависимостей при старте
+const startupMaxBackoff = 5 * time.Second
+
+// waitFor повторяет check с экспоненциальной паузой, пока проверка не пройдет
+// или не истечет контекст. Позволяет запускаться раньше зависимостей,
+// например, при старте всех контейнеров одновременно.
+func waitFor(ctx context.Context, name string, backoff time.Duration, logger *zap.Logger, check func(ctx context.Context) error) error {
+	for attempt := 1; ; attempt++ {
+		err := check(ctx)
+		if err == nil {
+			return nil
+		}
+
+		deadline, hasDeadline := ctx.Deadline()
+		if ctx.Err() != nil || (hasDeadline && time.Until(deadline) < backoff) {
+			return fmt.Errorf("%s is unavailable after %d attempts: %w", name, attempt, err)
+		}
+
+		logger.Warn("dependency is not ready, retrying",
+			zap.String("dependency", name),
+			zap.Int("attempt", attempt),
+			zap.Duration("backoff", backoff),
+			zap.Error(err),
+		)
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%s is unavailable after %d attempts: %w", name, attempt, err)
+		case <-timer.C:
+		}
+		backoff = min(backoff*2, startupMaxBackoff)
+	}
+}
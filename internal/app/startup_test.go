@@ -0,0 +1,43 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestWaitFor(t *testing.T) {
+	t.Run("Dependency becomes ready", func(t *testing.T) {
+		attempts := 0
+		check := func(context.Context) error {
+			attempts++
+			if attempts < 3 {
+				return errors.New("connection refused")
+			}
+			return nil
+		}
+
+		err := waitFor(context.Background(), "database", time.Millisecond, zap.NewNop(), check)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, attempts)
+	})
+
+	t.Run("Budget exhausted", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+		defer cancel()
+
+		checkErr := errors.New("connection refused")
+		start := time.Now()
+		err := waitFor(ctx, "database", 5*time.Millisecond, zap.NewNop(), func(context.Context) error {
+			return checkErr
+		})
+
+		assert.ErrorIs(t, err, checkErr)
+		assert.Contains(t, err.Error(), "database is unavailable")
+		assert.Less(t, time.Since(start), time.Second)
+	})
+}
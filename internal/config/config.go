@@ -3,91 +3,934 @@ package config
 import (
 	"flag"
 	"fmt"
+	"net/netip"
+	"net/url"
 	"os"
 	"strconv"
+	"strings"
 	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/avc/loyalty-system-diploma/internal/utils/envelope"
+	"github.com/avc/loyalty-system-diploma/internal/utils/ordernum"
 )
 
 // Config содержит конфигурацию приложения
 type Config struct {
-	RunAddress           string        // Адрес и порт запуска сервиса
+	RunAddress           string        // Адреса и порты публичного API через запятую
 	DatabaseURI          string        // URI подключения к БД
 	AccrualSystemAddress string        // Адрес системы расчета начислений
+	AccrualProxyURL      string        // Прокси для запросов к системе начислений (пусто - из HTTP_PROXY/HTTPS_PROXY)
+	AccrualCAFile        string        // PEM файл с дополнительными корневыми сертификатами
+	AccrualSigningKey    string        // Ключ HMAC подписи запросов к системе начислений (пусто - без подписи)
+	AccrualClockSkew     time.Duration // Допустимое расхождение часов с системой начислений
 	JWTSecret            string        // Секретный ключ для JWT
 	JWTTokenTTL          time.Duration // Время жизни JWT токена
+	JWTRememberTTL       time.Duration // Время жизни токена при входе с "remember" (не меньше JWTTokenTTL)
+	JWTRefreshTTL        time.Duration // Время жизни refresh-токена (0 - refresh-токены не выдаются)
 	LogLevel             string        // Уровень логирования
+	LogSQL               bool          // Логировать SQL запросы с временем выполнения (debug)
+
+	// JSONAmountFixedDecimals - выводить суммы в ответах API ровно с двумя знаками
+	// после точки (100.10), а не без конечных нулей (100.1)
+	JSONAmountFixedDecimals bool
+
+	// Работа администратора от имени пользователя: время жизни токена
+	// и запрет изменяющих запросов по нему
+	ImpersonationTTL      time.Duration
+	ImpersonationReadOnly bool
+
+	// AuthzAuditSampleRatio - доля разрешенных запросов к маршрутам, двигающим деньги,
+	// которые попадают в журнал аудита авторизации, от 0 до 1. Отказы записываются всегда
+	AuthzAuditSampleRatio float64
+
+	// Пороги медленных операций (0 - отключено)
+	SlowRequestThreshold time.Duration // Порог медленного HTTP запроса
+	SlowQueryThreshold   time.Duration // Порог медленного SQL запроса
+
+	// SlowQueryExplainThreshold - порог, начиная с которого к медленному SQL запросу в логе
+	// прикладывается план EXPLAIN (0 - отключено). Не действует при LOG_LEVEL=production
+	SlowQueryExplainThreshold time.Duration
+
+	// Распределенная трассировка OpenTelemetry. Пустой адрес коллектора - трассировка отключена
+	TracingEndpoint    string  // URL OTLP/HTTP коллектора, например http://otel-collector:4318
+	TracingServiceName string  // Имя сервиса в трассах
+	TracingSampleRatio float64 // Доля трасс, начатых сервисом, от 0 до 1
+
+	// Ожидание зависимостей при старте
+	StartupTimeout      time.Duration // Общий бюджет ожидания БД и системы начислений
+	StartupRetryBackoff time.Duration // Начальная пауза между проверками
+
+	// Поведение при недоступности БД
+	DBRetryAttempts     int           // Число попыток запроса при временных ошибках
+	DBRetryBackoff      time.Duration // Начальная пауза между попытками
+	DBRetryMaxBackoff   time.Duration // Максимальная пауза между попытками
+	DBReconnectInterval time.Duration // Интервал проверки БД в режиме деградации
+
+	// Файл со строкой подключения к БД (секрет, который обновляет внешний агент).
+	// Если задан, имеет приоритет над DatabaseURI, а логин и пароль перечитываются без перезапуска
+	DatabaseURIFile             string
+	DBCredentialsReloadInterval time.Duration // Интервал проверки файла
+
+	// Адреса внутреннего слушателя через запятую: проверки состояния, метрики,
+	// административные и внутренние эндпоинты. Пусто - все маршруты на RunAddress
+	InternalRunAddress string
+
+	// Таймауты HTTP сервера
+	HTTPReadTimeout  time.Duration // Чтение запроса вместе с телом
+	HTTPWriteTimeout time.Duration // Обработка запроса и запись ответа
+	HTTPIdleTimeout  time.Duration // Простой keep-alive соединения
+	ShutdownTimeout  time.Duration // Ожидание выполняемых запросов при остановке
+
+	// Сжатие ответов
+	CompressionLevel         int      // Уровень сжатия gzip/br (0 - отключено)
+	CompressionMinSize       int      // Минимальный размер ответа для сжатия в байтах
+	CompressionExcludedTypes []string // Типы содержимого, которые не сжимаются
+	CompressionExcludedPaths []string // Префиксы путей, которые не сжимаются
+
+	// Ограничение частоты запросов
+	RateLimitRequests int           // Запросов с одного адреса за окно (0 - отключено)
+	RateLimitWindow   time.Duration // Длительность окна
+	// Запросов проверки логина с одного адреса за то же окно (0 - только общее ограничение)
+	AvailabilityRateLimit int
+
+	// Суммарный вес одновременно выполняемых запросов, после которого
+	// новые запросы сразу получают 503 (0 - ограничение отключено)
+	MaxInFlightRequests int
 
 	// Worker Pool конфигурация
-	WorkerPoolSize     int           // Количество воркеров
-	WorkerQueueSize    int           // Размер очереди заказов
-	WorkerScanInterval time.Duration // Интервал сканирования pending заказов
+	WorkerPoolSize        int           // Количество воркеров
+	WorkerQueueSize       int           // Размер очереди заказов
+	WorkerScanInterval    time.Duration // Интервал сканирования, пока есть pending заказы
+	WorkerMaxScanInterval time.Duration // Максимальный интервал сканирования в периоды простоя
+	// Число необработанных заказов, после которого ответ на загрузку заказа
+	// предупреждает о задержке обработки (0 - не предупреждать)
+	WorkerBacklogThreshold int
+	// Интервал и предельный возраст опроса заказов по статусам
+	WorkerScanPolicies []domain.OrderScanPolicy
+
+	// Интервал опроса outbox таблицы событий заказов
+	EventPollInterval time.Duration
+
+	// Интервал проверки лидерства и попыток его захвата репликами
+	LeaderRenewInterval time.Duration
+
+	// Блокировки, общие для всех реплик (объединение пользователей, сверка начислений, взаиморасчеты).
+	// Пустой адрес Redis - блокировки хранятся в таблице БД
+	LockRedisURL string
+	LockTTL      time.Duration // Время, через которое блокировка упавшей реплики освобождается
+
+	// Интервал выгрузки списаний для взаиморасчетов с партнерами (0 - только вручную)
+	SettlementInterval time.Duration
+
+	// Интервал синхронизации локальной копии правил вознаграждения системы начислений (0 - не синхронизировать)
+	AccrualRulesSyncInterval time.Duration
+
+	// Завершение заказов, зависших в PROCESSING: через сколько суток после загрузки
+	// заказ переводится в INVALID (0 - не завершать), как часто проверять
+	// и режим dry-run, в котором зависшие заказы только пишутся в лог
+	OrderExpiryDays     int
+	OrderExpiryInterval time.Duration
+	OrderExpiryDryRun   bool
+
+	// Интервал проверки начислений, примененных наполовину после сбоя (0 - только при старте)
+	AccrualRepairInterval time.Duration
+
+	// Интервал удаления истекших refresh-токенов (0 - не удалять)
+	RefreshTokenCleanupInterval time.Duration
+
+	// Хранилище refresh-токенов (RefreshTokenStore*) и адрес Redis для RefreshTokenStoreRedis
+	RefreshTokenStore    string
+	RefreshTokenRedisURL string
+
+	// Защита от перебора номеров заказов: загрузок пользователя за окно (0 - без ограничения)
+	// и загрузок чужих заказов за окно, после которых загрузка запрещается на срок (0 - не запрещать)
+	OrderSubmitRateLimit   int
+	OrderSubmitRateWindow  time.Duration
+	OrderConflictLimit     int
+	OrderConflictWindow    time.Duration
+	OrderSubmitBanDuration time.Duration
+
+	// Ограничения списаний по умолчанию (0 - без ограничения),
+	// администратор может переопределить их для отдельного пользователя
+	WithdrawalMaxAmount    float64 // Максимальная сумма одного списания
+	WithdrawalDailyLimit   float64 // Сумма списаний за последние 24 часа
+	WithdrawalMonthlyLimit float64 // Сумма списаний за последние 30 дней
+
+	// Округление начислений перед записью (floor, round, bankers) и число знаков после запятой
+	AccrualRoundingMode      domain.RoundingMode
+	AccrualRoundingPrecision int
+
+	// Корректировать начисление, если система начислений пересчитала уже зачисленный заказ
+	AccrualCorrectionsEnabled bool
+
+	// Логины пользователей с доступом к административным эндпоинтам
+	AdminLogins []string
+
+	// Ограничение доступа к административным эндпоинтам по адресу клиента.
+	// Пустой список разрешенных сетей - разрешены все адреса, кроме запрещенных
+	AdminAllowedCIDRs []netip.Prefix
+	AdminDeniedCIDRs  []netip.Prefix
+
+	// Сети прокси, которым доверяется заголовок X-Forwarded-For
+	TrustedProxies []netip.Prefix
+
+	// Внешний провайдер удостоверений (SSO/OIDC). Пустой издатель - режим отключен,
+	// принимаются только токены, выпущенные сервисом
+	OIDCIssuer   string // Ожидаемый издатель токенов (iss)
+	OIDCAudience string // Ожидаемая аудитория токенов (aud), пусто - не проверяется
+	OIDCJWKSURL  string // Адрес ключей провайдера, пусто - из discovery документа издателя
+
+	// Вход через социальные сети (OAuth2). Провайдер включен, если задан его client ID
+	OAuthRedirectBaseURL    string // Внешний адрес сервиса для построения адресов callback
+	OAuthGoogleClientID     string
+	OAuthGoogleClientSecret string
+	OAuthVKClientID         string
+	OAuthVKClientSecret     string
+
+	// Мастер-ключи шифрования персональных данных; первый - текущий.
+	// Пустой список - контактные данные пользователей не сохраняются
+	PIIEncryptionKeys []envelope.Key
+
+	// Токены внутренних сервисов по имени сервиса. Пустой набор - внутренние эндпоинты недоступны
+	InternalServiceTokens map[string]string
+
+	// Защита внутренних эндпоинтов от повтора запросов. Нулевое окно - защита выключена
+	ReplayWindow        time.Duration // Допустимое расхождение времени запроса с часами сервера
+	ReplayNonceStore    string        // Хранилище nonce (ReplayNonceStore*)
+	ReplayNonceRedisURL string        // Адрес Redis для ReplayNonceStoreRedis
+
+	// Откуда взято значение каждого параметра, по имени переменной окружения
+	sources map[string]Source
 
 	// Валидация
-	MinPasswordLength int // Минимальная длина пароля
+	MinPasswordLength    int // Минимальная длина пароля
+	OrderNumberMinLength int // Минимальная длина номера заказа
+	OrderNumberMaxLength int // Максимальная длина номера заказа (не больше 64)
+	// Проверка номеров по префиксу или источнику вместо алгоритма Луна
+	OrderNumberValidators []ordernum.Rule
 }
 
 // Load загружает конфигурацию из переменных окружения и флагов
 // Приоритет: env переменные > флаги > дефолтные значения
 func Load() (*Config, error) {
 	cfg := &Config{
-		JWTTokenTTL:        24 * time.Hour,
-		LogLevel:           "info",
-		WorkerPoolSize:     3,
-		WorkerQueueSize:    100,
-		WorkerScanInterval: 10 * time.Second,
-		MinPasswordLength:  6,
+		JWTTokenTTL:                 24 * time.Hour,
+		JWTRememberTTL:              30 * 24 * time.Hour,
+		JWTRefreshTTL:               30 * 24 * time.Hour,
+		ImpersonationTTL:            15 * time.Minute,
+		ImpersonationReadOnly:       true,
+		AuthzAuditSampleRatio:       1,
+		AccrualClockSkew:            30 * time.Second,
+		LogLevel:                    "info",
+		SlowRequestThreshold:        time.Second,
+		SlowQueryThreshold:          200 * time.Millisecond,
+		TracingServiceName:          "gophermart",
+		TracingSampleRatio:          1,
+		StartupTimeout:              30 * time.Second,
+		StartupRetryBackoff:         500 * time.Millisecond,
+		DBRetryAttempts:             3,
+		DBRetryBackoff:              100 * time.Millisecond,
+		DBRetryMaxBackoff:           time.Second,
+		DBReconnectInterval:         2 * time.Second,
+		DBCredentialsReloadInterval: 30 * time.Second,
+		HTTPReadTimeout:             15 * time.Second,
+		HTTPWriteTimeout:            15 * time.Second,
+		HTTPIdleTimeout:             60 * time.Second,
+		ShutdownTimeout:             10 * time.Second,
+		CompressionLevel:            5,
+		CompressionMinSize:          1024,
+		// SSE не сжимаем, чтобы события не задерживались в буфере кодировщика
+		CompressionExcludedTypes: []string{"text/event-stream"},
+		// promhttp сжимает ответ сам
+		CompressionExcludedPaths:    []string{"/metrics"},
+		RateLimitRequests:           1000,
+		RateLimitWindow:             time.Minute,
+		AvailabilityRateLimit:       30,
+		MaxInFlightRequests:         500,
+		WorkerPoolSize:              3,
+		WorkerQueueSize:             100,
+		WorkerScanInterval:          10 * time.Second,
+		WorkerMaxScanInterval:       time.Minute,
+		WorkerScanPolicies:          domain.DefaultOrderScanPolicies(),
+		EventPollInterval:           time.Second,
+		LeaderRenewInterval:         5 * time.Second,
+		LockTTL:                     30 * time.Second,
+		SettlementInterval:          time.Hour,
+		OrderExpiryInterval:         time.Hour,
+		AccrualRepairInterval:       time.Hour,
+		RefreshTokenCleanupInterval: time.Hour,
+		RefreshTokenStore:           RefreshTokenStorePostgres,
+		ReplayNonceStore:            ReplayNonceStoreMemory,
+		OrderSubmitRateLimit:        30,
+		OrderSubmitRateWindow:       time.Minute,
+		OrderConflictLimit:          10,
+		OrderConflictWindow:         time.Hour,
+		OrderSubmitBanDuration:      24 * time.Hour,
+		AccrualRoundingMode:         domain.RoundingHalfUp,
+		AccrualRoundingPrecision:    domain.MaxRoundingPrecision,
+		MinPasswordLength:           6,
+		OrderNumberMinLength:        2,
+		OrderNumberMaxLength:        32,
+		sources:                     make(map[string]Source),
 	}
 
 	// Определяем флаги
 	flag.StringVar(&cfg.RunAddress, "a", ":8080", "address and port to run server")
+	flag.StringVar(&cfg.InternalRunAddress, "internal-address", "", "address and port for internal routes")
 	flag.StringVar(&cfg.DatabaseURI, "d", "", "database URI")
 	flag.StringVar(&cfg.AccrualSystemAddress, "r", "", "accrual system address")
+	flag.DurationVar(&cfg.HTTPReadTimeout, "http-read-timeout", cfg.HTTPReadTimeout, "HTTP server read timeout")
+	flag.DurationVar(&cfg.HTTPWriteTimeout, "http-write-timeout", cfg.HTTPWriteTimeout, "HTTP server write timeout")
+	flag.DurationVar(&cfg.HTTPIdleTimeout, "http-idle-timeout", cfg.HTTPIdleTimeout, "HTTP server idle timeout")
+	flag.IntVar(&cfg.CompressionLevel, "compression-level", cfg.CompressionLevel, "response compression level (0 disables)")
 	flag.Parse()
+	flag.Visit(func(f *flag.Flag) {
+		if name, ok := flagSettings[f.Name]; ok {
+			cfg.sources[name] = SourceFlag
+		}
+	})
 
 	// Переменные окружения имеют приоритет над флагами
 	if envRunAddr, ok := os.LookupEnv("RUN_ADDRESS"); ok {
 		cfg.RunAddress = envRunAddr
+		cfg.sources["RUN_ADDRESS"] = SourceEnv
+	}
+
+	if envInternalAddr, ok := os.LookupEnv("INTERNAL_RUN_ADDRESS"); ok {
+		cfg.InternalRunAddress = envInternalAddr
+		cfg.sources["INTERNAL_RUN_ADDRESS"] = SourceEnv
 	}
 
 	if envDBURI, ok := os.LookupEnv("DATABASE_URI"); ok {
 		cfg.DatabaseURI = envDBURI
+		cfg.sources["DATABASE_URI"] = SourceEnv
 	}
 
 	if envAccrualAddr, ok := os.LookupEnv("ACCRUAL_SYSTEM_ADDRESS"); ok {
 		cfg.AccrualSystemAddress = envAccrualAddr
+		cfg.sources["ACCRUAL_SYSTEM_ADDRESS"] = SourceEnv
+	}
+
+	if envProxy, ok := os.LookupEnv("ACCRUAL_PROXY_URL"); ok {
+		cfg.AccrualProxyURL = strings.TrimSpace(envProxy)
+		cfg.sources["ACCRUAL_PROXY_URL"] = SourceEnv
+	}
+
+	if envCAFile, ok := os.LookupEnv("ACCRUAL_CA_FILE"); ok {
+		cfg.AccrualCAFile = strings.TrimSpace(envCAFile)
+		cfg.sources["ACCRUAL_CA_FILE"] = SourceEnv
+	}
+
+	// Ключ подписи, как и JWT секрет, только из env
+	if envSigningKey, ok := os.LookupEnv("ACCRUAL_SIGNING_KEY"); ok {
+		cfg.AccrualSigningKey = envSigningKey
+		cfg.sources["ACCRUAL_SIGNING_KEY"] = SourceEnv
+	}
+
+	if envSkew, ok := os.LookupEnv("ACCRUAL_SIGNING_CLOCK_SKEW"); ok {
+		if skew, err := time.ParseDuration(envSkew); err == nil && skew > 0 {
+			cfg.AccrualClockSkew = skew
+			cfg.sources["ACCRUAL_SIGNING_CLOCK_SKEW"] = SourceEnv
+		}
 	}
 
 	// JWT секрет (только из env, не из флагов для безопасности)
 	if envJWTSecret, ok := os.LookupEnv("JWT_SECRET"); ok {
 		cfg.JWTSecret = envJWTSecret
+		cfg.sources["JWT_SECRET"] = SourceEnv
 	} else {
 		cfg.JWTSecret = "default-secret-key-change-in-production"
 	}
 
+	// Время жизни токена "запомнить меня"
+	if envRememberTTL, ok := os.LookupEnv("JWT_REMEMBER_TTL"); ok {
+		if ttl, err := time.ParseDuration(envRememberTTL); err == nil && ttl >= 0 {
+			cfg.JWTRememberTTL = ttl
+			cfg.sources["JWT_REMEMBER_TTL"] = SourceEnv
+		}
+	}
+
+	// Время жизни refresh-токена
+	if envRefreshTTL, ok := os.LookupEnv("JWT_REFRESH_TTL"); ok {
+		if ttl, err := time.ParseDuration(envRefreshTTL); err == nil && ttl >= 0 {
+			cfg.JWTRefreshTTL = ttl
+			cfg.sources["JWT_REFRESH_TTL"] = SourceEnv
+		}
+	}
+
+	// Работа администратора от имени пользователя
+	if envImpersonationTTL, ok := os.LookupEnv("IMPERSONATION_TTL"); ok {
+		if ttl, err := time.ParseDuration(envImpersonationTTL); err == nil && ttl > 0 {
+			cfg.ImpersonationTTL = ttl
+			cfg.sources["IMPERSONATION_TTL"] = SourceEnv
+		}
+	}
+
+	if envImpersonationReadOnly, ok := os.LookupEnv("IMPERSONATION_READ_ONLY"); ok {
+		if readOnly, err := strconv.ParseBool(envImpersonationReadOnly); err == nil {
+			cfg.ImpersonationReadOnly = readOnly
+			cfg.sources["IMPERSONATION_READ_ONLY"] = SourceEnv
+		}
+	}
+
+	if envRatio, ok := os.LookupEnv("AUTHZ_AUDIT_SAMPLE_RATIO"); ok {
+		ratio, err := strconv.ParseFloat(envRatio, 64)
+		if err != nil || ratio < 0 || ratio > 1 {
+			return nil, fmt.Errorf("invalid AUTHZ_AUDIT_SAMPLE_RATIO: expected number from 0 to 1, got %q", envRatio)
+		}
+		cfg.AuthzAuditSampleRatio = ratio
+		cfg.sources["AUTHZ_AUDIT_SAMPLE_RATIO"] = SourceEnv
+	}
+
 	// Уровень логирования
 	if envLogLevel, ok := os.LookupEnv("LOG_LEVEL"); ok {
 		cfg.LogLevel = envLogLevel
+		cfg.sources["LOG_LEVEL"] = SourceEnv
+	}
+
+	// Логирование SQL запросов
+	if envLogSQL, ok := os.LookupEnv("LOG_SQL"); ok {
+		if enabled, err := strconv.ParseBool(envLogSQL); err == nil {
+			cfg.LogSQL = enabled
+			cfg.sources["LOG_SQL"] = SourceEnv
+		}
+	}
+
+	// Формат сумм в JSON
+	if envFixed, ok := os.LookupEnv("JSON_AMOUNT_FIXED_DECIMALS"); ok {
+		if fixed, err := strconv.ParseBool(envFixed); err == nil {
+			cfg.JSONAmountFixedDecimals = fixed
+			cfg.sources["JSON_AMOUNT_FIXED_DECIMALS"] = SourceEnv
+		}
+	}
+
+	// Пороги медленных запросов
+	if envSlowRequest, ok := os.LookupEnv("SLOW_REQUEST_THRESHOLD"); ok {
+		if threshold, err := time.ParseDuration(envSlowRequest); err == nil && threshold >= 0 {
+			cfg.SlowRequestThreshold = threshold
+			cfg.sources["SLOW_REQUEST_THRESHOLD"] = SourceEnv
+		}
+	}
+
+	if envSlowQuery, ok := os.LookupEnv("SLOW_QUERY_THRESHOLD"); ok {
+		if threshold, err := time.ParseDuration(envSlowQuery); err == nil && threshold >= 0 {
+			cfg.SlowQueryThreshold = threshold
+			cfg.sources["SLOW_QUERY_THRESHOLD"] = SourceEnv
+		}
+	}
+
+	if envExplain, ok := os.LookupEnv("SLOW_QUERY_EXPLAIN_THRESHOLD"); ok {
+		if threshold, err := time.ParseDuration(envExplain); err == nil && threshold >= 0 {
+			cfg.SlowQueryExplainThreshold = threshold
+			cfg.sources["SLOW_QUERY_EXPLAIN_THRESHOLD"] = SourceEnv
+		}
+	}
+
+	// Неверные настройки трассировки - ошибка: иначе трассы молча не отправлялись бы
+	if envEndpoint, ok := os.LookupEnv("TRACING_OTLP_ENDPOINT"); ok {
+		endpoint := strings.TrimSpace(envEndpoint)
+		if endpoint != "" {
+			if u, err := url.Parse(endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return nil, fmt.Errorf("invalid TRACING_OTLP_ENDPOINT: expected http(s) URL, got %q", endpoint)
+			}
+		}
+		cfg.TracingEndpoint = endpoint
+		cfg.sources["TRACING_OTLP_ENDPOINT"] = SourceEnv
+	}
+
+	if envName, ok := os.LookupEnv("TRACING_SERVICE_NAME"); ok && strings.TrimSpace(envName) != "" {
+		cfg.TracingServiceName = strings.TrimSpace(envName)
+		cfg.sources["TRACING_SERVICE_NAME"] = SourceEnv
+	}
+
+	if envRatio, ok := os.LookupEnv("TRACING_SAMPLE_RATIO"); ok {
+		ratio, err := strconv.ParseFloat(envRatio, 64)
+		if err != nil || ratio < 0 || ratio > 1 {
+			return nil, fmt.Errorf("invalid TRACING_SAMPLE_RATIO: expected number from 0 to 1, got %q", envRatio)
+		}
+		cfg.TracingSampleRatio = ratio
+		cfg.sources["TRACING_SAMPLE_RATIO"] = SourceEnv
+	}
+
+	// Ожидание зависимостей при старте
+	if envTimeout, ok := os.LookupEnv("STARTUP_TIMEOUT"); ok {
+		if timeout, err := time.ParseDuration(envTimeout); err == nil && timeout > 0 {
+			cfg.StartupTimeout = timeout
+			cfg.sources["STARTUP_TIMEOUT"] = SourceEnv
+		}
+	}
+
+	if envBackoff, ok := os.LookupEnv("STARTUP_RETRY_BACKOFF"); ok {
+		if backoff, err := time.ParseDuration(envBackoff); err == nil && backoff > 0 {
+			cfg.StartupRetryBackoff = backoff
+			cfg.sources["STARTUP_RETRY_BACKOFF"] = SourceEnv
+		}
+	}
+
+	// Повторы запросов к БД
+	if envAttempts, ok := os.LookupEnv("DB_RETRY_ATTEMPTS"); ok {
+		if attempts, err := strconv.Atoi(envAttempts); err == nil && attempts > 0 {
+			cfg.DBRetryAttempts = attempts
+			cfg.sources["DB_RETRY_ATTEMPTS"] = SourceEnv
+		}
+	}
+
+	if envBackoff, ok := os.LookupEnv("DB_RETRY_BACKOFF"); ok {
+		if backoff, err := time.ParseDuration(envBackoff); err == nil && backoff > 0 {
+			cfg.DBRetryBackoff = backoff
+			cfg.sources["DB_RETRY_BACKOFF"] = SourceEnv
+		}
+	}
+
+	if envMaxBackoff, ok := os.LookupEnv("DB_RETRY_MAX_BACKOFF"); ok {
+		if backoff, err := time.ParseDuration(envMaxBackoff); err == nil && backoff > 0 {
+			cfg.DBRetryMaxBackoff = backoff
+			cfg.sources["DB_RETRY_MAX_BACKOFF"] = SourceEnv
+		}
+	}
+
+	if envInterval, ok := os.LookupEnv("DB_RECONNECT_INTERVAL"); ok {
+		if interval, err := time.ParseDuration(envInterval); err == nil && interval > 0 {
+			cfg.DBReconnectInterval = interval
+			cfg.sources["DB_RECONNECT_INTERVAL"] = SourceEnv
+		}
+	}
+
+	if envFile, ok := os.LookupEnv("DATABASE_URI_FILE"); ok && envFile != "" {
+		uri, err := ReadSecretFile(envFile)
+		if err != nil {
+			return nil, fmt.Errorf("invalid DATABASE_URI_FILE: %w", err)
+		}
+		cfg.DatabaseURIFile = envFile
+		cfg.DatabaseURI = uri
+		cfg.sources["DATABASE_URI_FILE"] = SourceEnv
+	}
+
+	if envInterval, ok := os.LookupEnv("DB_CREDENTIALS_RELOAD_INTERVAL"); ok {
+		if interval, err := time.ParseDuration(envInterval); err == nil && interval > 0 {
+			cfg.DBCredentialsReloadInterval = interval
+			cfg.sources["DB_CREDENTIALS_RELOAD_INTERVAL"] = SourceEnv
+		}
+	}
+
+	// Таймауты HTTP сервера. Неверное значение - ошибка, а не значение по умолчанию:
+	// слишком короткий таймаут незаметно обрывал бы долгие ответы
+	for _, timeout := range []struct {
+		name string
+		dst  *time.Duration
+	}{
+		{"HTTP_READ_TIMEOUT", &cfg.HTTPReadTimeout},
+		{"HTTP_WRITE_TIMEOUT", &cfg.HTTPWriteTimeout},
+		{"HTTP_IDLE_TIMEOUT", &cfg.HTTPIdleTimeout},
+		{"SHUTDOWN_TIMEOUT", &cfg.ShutdownTimeout},
+	} {
+		envValue, ok := os.LookupEnv(timeout.name)
+		if !ok {
+			continue
+		}
+		value, err := time.ParseDuration(envValue)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", timeout.name, err)
+		}
+		*timeout.dst = value
+		cfg.sources[timeout.name] = SourceEnv
+	}
+
+	// Сжатие ответов
+	if envLevel, ok := os.LookupEnv("COMPRESSION_LEVEL"); ok {
+		if level, err := strconv.Atoi(envLevel); err == nil {
+			cfg.CompressionLevel = level
+			cfg.sources["COMPRESSION_LEVEL"] = SourceEnv
+		}
+	}
+
+	if envMinSize, ok := os.LookupEnv("COMPRESSION_MIN_SIZE"); ok {
+		if size, err := strconv.Atoi(envMinSize); err == nil && size >= 0 {
+			cfg.CompressionMinSize = size
+			cfg.sources["COMPRESSION_MIN_SIZE"] = SourceEnv
+		}
+	}
+
+	if envTypes, ok := os.LookupEnv("COMPRESSION_EXCLUDED_TYPES"); ok {
+		cfg.CompressionExcludedTypes = splitList(envTypes)
+		cfg.sources["COMPRESSION_EXCLUDED_TYPES"] = SourceEnv
+	}
+
+	if envPaths, ok := os.LookupEnv("COMPRESSION_EXCLUDED_PATHS"); ok {
+		cfg.CompressionExcludedPaths = splitList(envPaths)
+		cfg.sources["COMPRESSION_EXCLUDED_PATHS"] = SourceEnv
+	}
+
+	if envRateLimit, ok := os.LookupEnv("RATE_LIMIT_REQUESTS"); ok {
+		if limit, err := strconv.Atoi(envRateLimit); err == nil && limit >= 0 {
+			cfg.RateLimitRequests = limit
+			cfg.sources["RATE_LIMIT_REQUESTS"] = SourceEnv
+		}
+	}
+
+	if envRateWindow, ok := os.LookupEnv("RATE_LIMIT_WINDOW"); ok {
+		if window, err := time.ParseDuration(envRateWindow); err == nil && window > 0 {
+			cfg.RateLimitWindow = window
+			cfg.sources["RATE_LIMIT_WINDOW"] = SourceEnv
+		}
+	}
+
+	if envAvailabilityLimit, ok := os.LookupEnv("AVAILABILITY_RATE_LIMIT_REQUESTS"); ok {
+		if limit, err := strconv.Atoi(envAvailabilityLimit); err == nil && limit >= 0 {
+			cfg.AvailabilityRateLimit = limit
+			cfg.sources["AVAILABILITY_RATE_LIMIT_REQUESTS"] = SourceEnv
+		}
+	}
+
+	if envMaxInFlight, ok := os.LookupEnv("MAX_INFLIGHT_REQUESTS"); ok {
+		if limit, err := strconv.Atoi(envMaxInFlight); err == nil && limit >= 0 {
+			cfg.MaxInFlightRequests = limit
+			cfg.sources["MAX_INFLIGHT_REQUESTS"] = SourceEnv
+		}
 	}
 
 	// Worker Pool конфигурация из env
 	if envWorkerPoolSize, ok := os.LookupEnv("WORKER_POOL_SIZE"); ok {
 		if size, err := strconv.Atoi(envWorkerPoolSize); err == nil && size > 0 {
 			cfg.WorkerPoolSize = size
+			cfg.sources["WORKER_POOL_SIZE"] = SourceEnv
 		}
 	}
 
 	if envWorkerQueueSize, ok := os.LookupEnv("WORKER_QUEUE_SIZE"); ok {
 		if size, err := strconv.Atoi(envWorkerQueueSize); err == nil && size > 0 {
 			cfg.WorkerQueueSize = size
+			cfg.sources["WORKER_QUEUE_SIZE"] = SourceEnv
+		}
+	}
+
+	if envBacklogThreshold, ok := os.LookupEnv("WORKER_BACKLOG_THRESHOLD"); ok {
+		if threshold, err := strconv.Atoi(envBacklogThreshold); err == nil && threshold >= 0 {
+			cfg.WorkerBacklogThreshold = threshold
+			cfg.sources["WORKER_BACKLOG_THRESHOLD"] = SourceEnv
 		}
 	}
 
 	if envScanInterval, ok := os.LookupEnv("WORKER_SCAN_INTERVAL"); ok {
 		if interval, err := time.ParseDuration(envScanInterval); err == nil && interval > 0 {
 			cfg.WorkerScanInterval = interval
+			cfg.sources["WORKER_SCAN_INTERVAL"] = SourceEnv
+		}
+	}
+
+	if envMaxScanInterval, ok := os.LookupEnv("WORKER_MAX_SCAN_INTERVAL"); ok {
+		if interval, err := time.ParseDuration(envMaxScanInterval); err == nil && interval > 0 {
+			cfg.WorkerMaxScanInterval = interval
+			cfg.sources["WORKER_MAX_SCAN_INTERVAL"] = SourceEnv
+		}
+	}
+
+	if envScanPolicies, ok := os.LookupEnv("WORKER_SCAN_POLICIES"); ok {
+		policies, err := parseScanPolicies(envScanPolicies)
+		if err != nil {
+			return nil, fmt.Errorf("invalid WORKER_SCAN_POLICIES: %w", err)
+		}
+		cfg.WorkerScanPolicies = policies
+		cfg.sources["WORKER_SCAN_POLICIES"] = SourceEnv
+	}
+
+	if envPollInterval, ok := os.LookupEnv("EVENT_POLL_INTERVAL"); ok {
+		if interval, err := time.ParseDuration(envPollInterval); err == nil && interval > 0 {
+			cfg.EventPollInterval = interval
+			cfg.sources["EVENT_POLL_INTERVAL"] = SourceEnv
+		}
+	}
+
+	if envRenewInterval, ok := os.LookupEnv("LEADER_RENEW_INTERVAL"); ok {
+		if interval, err := time.ParseDuration(envRenewInterval); err == nil && interval > 0 {
+			cfg.LeaderRenewInterval = interval
+			cfg.sources["LEADER_RENEW_INTERVAL"] = SourceEnv
+		}
+	}
+
+	if envLockRedisURL, ok := os.LookupEnv("LOCK_REDIS_URL"); ok {
+		cfg.LockRedisURL = strings.TrimSpace(envLockRedisURL)
+		cfg.sources["LOCK_REDIS_URL"] = SourceEnv
+	}
+
+	if envLockTTL, ok := os.LookupEnv("LOCK_TTL"); ok {
+		if ttl, err := time.ParseDuration(envLockTTL); err == nil && ttl > 0 {
+			cfg.LockTTL = ttl
+			cfg.sources["LOCK_TTL"] = SourceEnv
+		}
+	}
+
+	if envRulesSyncInterval, ok := os.LookupEnv("ACCRUAL_RULES_SYNC_INTERVAL"); ok {
+		if interval, err := time.ParseDuration(envRulesSyncInterval); err == nil && interval >= 0 {
+			cfg.AccrualRulesSyncInterval = interval
+			cfg.sources["ACCRUAL_RULES_SYNC_INTERVAL"] = SourceEnv
 		}
 	}
 
+	if envSettlementInterval, ok := os.LookupEnv("SETTLEMENT_INTERVAL"); ok {
+		if interval, err := time.ParseDuration(envSettlementInterval); err == nil && interval >= 0 {
+			cfg.SettlementInterval = interval
+			cfg.sources["SETTLEMENT_INTERVAL"] = SourceEnv
+		}
+	}
+
+	if envRepairInterval, ok := os.LookupEnv("ACCRUAL_REPAIR_INTERVAL"); ok {
+		if interval, err := time.ParseDuration(envRepairInterval); err == nil && interval >= 0 {
+			cfg.AccrualRepairInterval = interval
+			cfg.sources["ACCRUAL_REPAIR_INTERVAL"] = SourceEnv
+		}
+	}
+
+	if envCleanupInterval, ok := os.LookupEnv("REFRESH_TOKEN_CLEANUP_INTERVAL"); ok {
+		if interval, err := time.ParseDuration(envCleanupInterval); err == nil && interval >= 0 {
+			cfg.RefreshTokenCleanupInterval = interval
+			cfg.sources["REFRESH_TOKEN_CLEANUP_INTERVAL"] = SourceEnv
+		}
+	}
+
+	if envStore, ok := os.LookupEnv("REFRESH_TOKEN_STORE"); ok && strings.TrimSpace(envStore) != "" {
+		cfg.RefreshTokenStore = strings.ToLower(strings.TrimSpace(envStore))
+		cfg.sources["REFRESH_TOKEN_STORE"] = SourceEnv
+	}
+
+	if envRedisURL, ok := os.LookupEnv("REFRESH_TOKEN_REDIS_URL"); ok {
+		cfg.RefreshTokenRedisURL = strings.TrimSpace(envRedisURL)
+		cfg.sources["REFRESH_TOKEN_REDIS_URL"] = SourceEnv
+	}
+
+	if envExpiryDays, ok := os.LookupEnv("ORDER_EXPIRY_DAYS"); ok {
+		if days, err := strconv.Atoi(envExpiryDays); err == nil && days >= 0 {
+			cfg.OrderExpiryDays = days
+			cfg.sources["ORDER_EXPIRY_DAYS"] = SourceEnv
+		}
+	}
+
+	if envExpiryInterval, ok := os.LookupEnv("ORDER_EXPIRY_INTERVAL"); ok {
+		if interval, err := time.ParseDuration(envExpiryInterval); err == nil && interval > 0 {
+			cfg.OrderExpiryInterval = interval
+			cfg.sources["ORDER_EXPIRY_INTERVAL"] = SourceEnv
+		}
+	}
+
+	if envExpiryDryRun, ok := os.LookupEnv("ORDER_EXPIRY_DRY_RUN"); ok {
+		if dryRun, err := strconv.ParseBool(envExpiryDryRun); err == nil {
+			cfg.OrderExpiryDryRun = dryRun
+			cfg.sources["ORDER_EXPIRY_DRY_RUN"] = SourceEnv
+		}
+	}
+
+	if envMinLength, ok := os.LookupEnv("ORDER_NUMBER_MIN_LENGTH"); ok {
+		if length, err := strconv.Atoi(envMinLength); err == nil && length > 0 {
+			cfg.OrderNumberMinLength = length
+			cfg.sources["ORDER_NUMBER_MIN_LENGTH"] = SourceEnv
+		}
+	}
+
+	if envMaxLength, ok := os.LookupEnv("ORDER_NUMBER_MAX_LENGTH"); ok {
+		if length, err := strconv.Atoi(envMaxLength); err == nil && length > 0 && length <= domain.MaxOrderNumberLength {
+			cfg.OrderNumberMaxLength = length
+			cfg.sources["ORDER_NUMBER_MAX_LENGTH"] = SourceEnv
+		}
+	}
+
+	if envValidators, ok := os.LookupEnv("ORDER_NUMBER_VALIDATORS"); ok {
+		rules, err := parseOrderNumberValidators(envValidators)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ORDER_NUMBER_VALIDATORS: %w", err)
+		}
+		cfg.OrderNumberValidators = rules
+		cfg.sources["ORDER_NUMBER_VALIDATORS"] = SourceEnv
+	}
+
+	if envSubmitLimit, ok := os.LookupEnv("ORDER_SUBMIT_RATE_LIMIT"); ok {
+		if limit, err := strconv.Atoi(envSubmitLimit); err == nil && limit >= 0 {
+			cfg.OrderSubmitRateLimit = limit
+			cfg.sources["ORDER_SUBMIT_RATE_LIMIT"] = SourceEnv
+		}
+	}
+
+	if envSubmitWindow, ok := os.LookupEnv("ORDER_SUBMIT_RATE_WINDOW"); ok {
+		if window, err := time.ParseDuration(envSubmitWindow); err == nil && window > 0 {
+			cfg.OrderSubmitRateWindow = window
+			cfg.sources["ORDER_SUBMIT_RATE_WINDOW"] = SourceEnv
+		}
+	}
+
+	if envConflictLimit, ok := os.LookupEnv("ORDER_CONFLICT_LIMIT"); ok {
+		if limit, err := strconv.Atoi(envConflictLimit); err == nil && limit >= 0 {
+			cfg.OrderConflictLimit = limit
+			cfg.sources["ORDER_CONFLICT_LIMIT"] = SourceEnv
+		}
+	}
+
+	if envConflictWindow, ok := os.LookupEnv("ORDER_CONFLICT_WINDOW"); ok {
+		if window, err := time.ParseDuration(envConflictWindow); err == nil && window > 0 {
+			cfg.OrderConflictWindow = window
+			cfg.sources["ORDER_CONFLICT_WINDOW"] = SourceEnv
+		}
+	}
+
+	if envBanDuration, ok := os.LookupEnv("ORDER_SUBMIT_BAN_DURATION"); ok {
+		if duration, err := time.ParseDuration(envBanDuration); err == nil && duration >= 0 {
+			cfg.OrderSubmitBanDuration = duration
+			cfg.sources["ORDER_SUBMIT_BAN_DURATION"] = SourceEnv
+		}
+	}
+
+	if envMaxAmount, ok := os.LookupEnv("WITHDRAWAL_MAX_AMOUNT"); ok {
+		if amount, err := strconv.ParseFloat(envMaxAmount, 64); err == nil && amount >= 0 {
+			cfg.WithdrawalMaxAmount = amount
+			cfg.sources["WITHDRAWAL_MAX_AMOUNT"] = SourceEnv
+		}
+	}
+
+	if envDailyLimit, ok := os.LookupEnv("WITHDRAWAL_DAILY_LIMIT"); ok {
+		if amount, err := strconv.ParseFloat(envDailyLimit, 64); err == nil && amount >= 0 {
+			cfg.WithdrawalDailyLimit = amount
+			cfg.sources["WITHDRAWAL_DAILY_LIMIT"] = SourceEnv
+		}
+	}
+
+	if envMonthlyLimit, ok := os.LookupEnv("WITHDRAWAL_MONTHLY_LIMIT"); ok {
+		if amount, err := strconv.ParseFloat(envMonthlyLimit, 64); err == nil && amount >= 0 {
+			cfg.WithdrawalMonthlyLimit = amount
+			cfg.sources["WITHDRAWAL_MONTHLY_LIMIT"] = SourceEnv
+		}
+	}
+
+	if envMode, ok := os.LookupEnv("ACCRUAL_ROUNDING_MODE"); ok {
+		mode := domain.RoundingMode(strings.ToLower(strings.TrimSpace(envMode)))
+		if (domain.RoundingPolicy{Mode: mode}).IsValid() {
+			cfg.AccrualRoundingMode = mode
+			cfg.sources["ACCRUAL_ROUNDING_MODE"] = SourceEnv
+		}
+	}
+
+	if envPrecision, ok := os.LookupEnv("ACCRUAL_ROUNDING_PRECISION"); ok {
+		if precision, err := strconv.Atoi(envPrecision); err == nil && precision >= 0 && precision <= domain.MaxRoundingPrecision {
+			cfg.AccrualRoundingPrecision = precision
+			cfg.sources["ACCRUAL_ROUNDING_PRECISION"] = SourceEnv
+		}
+	}
+
+	if envCorrections, ok := os.LookupEnv("ACCRUAL_CORRECTIONS_ENABLED"); ok {
+		if enabled, err := strconv.ParseBool(envCorrections); err == nil {
+			cfg.AccrualCorrectionsEnabled = enabled
+			cfg.sources["ACCRUAL_CORRECTIONS_ENABLED"] = SourceEnv
+		}
+	}
+
+	if envAdminLogins, ok := os.LookupEnv("ADMIN_LOGINS"); ok {
+		cfg.AdminLogins = splitList(envAdminLogins)
+		cfg.sources["ADMIN_LOGINS"] = SourceEnv
+	}
+
+	// Списки сетей не игнорируются при ошибке: опечатка в списке разрешенных
+	// сетей иначе молча открыла бы доступ к административным эндпоинтам
+	prefixLists := []struct {
+		name string
+		dst  *[]netip.Prefix
+	}{
+		{"ADMIN_ALLOWED_CIDRS", &cfg.AdminAllowedCIDRs},
+		{"ADMIN_DENIED_CIDRS", &cfg.AdminDeniedCIDRs},
+		{"TRUSTED_PROXIES", &cfg.TrustedProxies},
+	}
+	for _, list := range prefixLists {
+		envValue, ok := os.LookupEnv(list.name)
+		if !ok {
+			continue
+		}
+		prefixes, err := parsePrefixes(envValue)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", list.name, err)
+		}
+		*list.dst = prefixes
+		cfg.sources[list.name] = SourceEnv
+	}
+
+	// Внешний провайдер удостоверений
+	if envIssuer, ok := os.LookupEnv("OIDC_ISSUER"); ok {
+		cfg.OIDCIssuer = strings.TrimSpace(envIssuer)
+		cfg.sources["OIDC_ISSUER"] = SourceEnv
+	}
+
+	if envAudience, ok := os.LookupEnv("OIDC_AUDIENCE"); ok {
+		cfg.OIDCAudience = strings.TrimSpace(envAudience)
+		cfg.sources["OIDC_AUDIENCE"] = SourceEnv
+	}
+
+	if envJWKSURL, ok := os.LookupEnv("OIDC_JWKS_URL"); ok {
+		cfg.OIDCJWKSURL = strings.TrimSpace(envJWKSURL)
+		cfg.sources["OIDC_JWKS_URL"] = SourceEnv
+	}
+
+	// Вход через социальные сети
+	if envBaseURL, ok := os.LookupEnv("OAUTH_REDIRECT_BASE_URL"); ok {
+		cfg.OAuthRedirectBaseURL = strings.TrimSuffix(strings.TrimSpace(envBaseURL), "/")
+		cfg.sources["OAUTH_REDIRECT_BASE_URL"] = SourceEnv
+	}
+
+	if envClientID, ok := os.LookupEnv("OAUTH_GOOGLE_CLIENT_ID"); ok {
+		cfg.OAuthGoogleClientID = strings.TrimSpace(envClientID)
+		cfg.sources["OAUTH_GOOGLE_CLIENT_ID"] = SourceEnv
+	}
+
+	if envClientSecret, ok := os.LookupEnv("OAUTH_GOOGLE_CLIENT_SECRET"); ok {
+		cfg.OAuthGoogleClientSecret = envClientSecret
+		cfg.sources["OAUTH_GOOGLE_CLIENT_SECRET"] = SourceEnv
+	}
+
+	if envClientID, ok := os.LookupEnv("OAUTH_VK_CLIENT_ID"); ok {
+		cfg.OAuthVKClientID = strings.TrimSpace(envClientID)
+		cfg.sources["OAUTH_VK_CLIENT_ID"] = SourceEnv
+	}
+
+	if envClientSecret, ok := os.LookupEnv("OAUTH_VK_CLIENT_SECRET"); ok {
+		cfg.OAuthVKClientSecret = envClientSecret
+		cfg.sources["OAUTH_VK_CLIENT_SECRET"] = SourceEnv
+	}
+
+	// Ошибка в ключе не игнорируется: иначе сервис не прочитал бы уже зашифрованные данные
+	if envKeys, ok := os.LookupEnv("PII_ENCRYPTION_KEYS"); ok {
+		keys, err := envelope.ParseKeys(envKeys)
+		if err != nil {
+			return nil, fmt.Errorf("invalid PII_ENCRYPTION_KEYS: %w", err)
+		}
+		cfg.PIIEncryptionKeys = keys
+		cfg.sources["PII_ENCRYPTION_KEYS"] = SourceEnv
+	}
+
+	if envTokens, ok := os.LookupEnv("INTERNAL_SERVICE_TOKENS"); ok {
+		tokens, err := parseServiceTokens(envTokens)
+		if err != nil {
+			return nil, fmt.Errorf("invalid INTERNAL_SERVICE_TOKENS: %w", err)
+		}
+		cfg.InternalServiceTokens = tokens
+		cfg.sources["INTERNAL_SERVICE_TOKENS"] = SourceEnv
+	}
+
+	if envReplayWindow, ok := os.LookupEnv("REPLAY_WINDOW"); ok {
+		if window, err := time.ParseDuration(envReplayWindow); err == nil && window >= 0 {
+			cfg.ReplayWindow = window
+			cfg.sources["REPLAY_WINDOW"] = SourceEnv
+		}
+	}
+
+	if envStore, ok := os.LookupEnv("REPLAY_NONCE_STORE"); ok && strings.TrimSpace(envStore) != "" {
+		cfg.ReplayNonceStore = strings.ToLower(strings.TrimSpace(envStore))
+		cfg.sources["REPLAY_NONCE_STORE"] = SourceEnv
+	}
+
+	if envRedisURL, ok := os.LookupEnv("REPLAY_NONCE_REDIS_URL"); ok {
+		cfg.ReplayNonceRedisURL = strings.TrimSpace(envRedisURL)
+		cfg.sources["REPLAY_NONCE_REDIS_URL"] = SourceEnv
+	}
+
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
 	// Валидация обязательных параметров
 	if cfg.DatabaseURI == "" {
 		return nil, fmt.Errorf("database URI is required (use -d flag or DATABASE_URI env)")
@@ -99,3 +942,204 @@ func Load() (*Config, error) {
 
 	return cfg, nil
 }
+
+// RunAddresses возвращает адреса публичного API, например IPv4 и IPv6 адреса одного порта
+func (c *Config) RunAddresses() []string {
+	return splitList(c.RunAddress)
+}
+
+// InternalRunAddresses возвращает адреса внутреннего слушателя, пусто - отдельного слушателя нет
+func (c *Config) InternalRunAddresses() []string {
+	return splitList(c.InternalRunAddress)
+}
+
+// maxCompressionLevel - наибольший уровень сжатия brotli; для gzip уровень ограничивается 9
+const maxCompressionLevel = 11
+
+// Хранилища refresh-токенов
+const (
+	RefreshTokenStorePostgres = "postgres"
+	RefreshTokenStoreRedis    = "redis"
+	// RefreshTokenStoreMemory - память процесса: токены теряются при перезапуске
+	// и не видны другим репликам, поэтому хранилище только для разработки
+	RefreshTokenStoreMemory = "memory"
+)
+
+// Хранилища nonce защиты от повтора запросов
+const (
+	// ReplayNonceStoreMemory - память процесса: nonce не видны другим репликам,
+	// поэтому повтор запроса на другую реплику не обнаруживается
+	ReplayNonceStoreMemory = "memory"
+	ReplayNonceStoreRedis  = "redis"
+)
+
+// validate проверяет значения, заданные флагами или переменными окружения
+func (c *Config) validate() error {
+	if len(c.RunAddresses()) == 0 {
+		return fmt.Errorf("RUN_ADDRESS must contain at least one address")
+	}
+	seen := make(map[string]bool)
+	for _, addr := range append(c.RunAddresses(), c.InternalRunAddresses()...) {
+		if seen[addr] {
+			return fmt.Errorf("address %s is listed more than once in RUN_ADDRESS and INTERNAL_RUN_ADDRESS", addr)
+		}
+		seen[addr] = true
+	}
+
+	for _, timeout := range []struct {
+		name  string
+		value time.Duration
+	}{
+		{"HTTP_READ_TIMEOUT", c.HTTPReadTimeout},
+		{"HTTP_WRITE_TIMEOUT", c.HTTPWriteTimeout},
+		{"HTTP_IDLE_TIMEOUT", c.HTTPIdleTimeout},
+		{"SHUTDOWN_TIMEOUT", c.ShutdownTimeout},
+	} {
+		if timeout.value <= 0 {
+			return fmt.Errorf("%s must be positive, got %s", timeout.name, timeout.value)
+		}
+	}
+
+	if c.CompressionLevel < 0 || c.CompressionLevel > maxCompressionLevel {
+		return fmt.Errorf("COMPRESSION_LEVEL must be from 0 to %d, got %d", maxCompressionLevel, c.CompressionLevel)
+	}
+
+	switch c.RefreshTokenStore {
+	case RefreshTokenStorePostgres, RefreshTokenStoreMemory:
+	case RefreshTokenStoreRedis:
+		if c.RefreshTokenRedisURL == "" {
+			return fmt.Errorf("REFRESH_TOKEN_REDIS_URL is required for REFRESH_TOKEN_STORE=redis")
+		}
+	default:
+		return fmt.Errorf("REFRESH_TOKEN_STORE must be postgres, redis or memory, got %q", c.RefreshTokenStore)
+	}
+
+	switch c.ReplayNonceStore {
+	case ReplayNonceStoreMemory:
+	case ReplayNonceStoreRedis:
+		if c.ReplayNonceRedisURL == "" {
+			return fmt.Errorf("REPLAY_NONCE_REDIS_URL is required for REPLAY_NONCE_STORE=redis")
+		}
+	default:
+		return fmt.Errorf("REPLAY_NONCE_STORE must be memory or redis, got %q", c.ReplayNonceStore)
+	}
+	return nil
+}
+
+// parsePrefixes разбирает список сетей через запятую. Адрес без маски - сеть из одного адреса
+func parsePrefixes(value string) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for _, item := range splitList(value) {
+		if !strings.Contains(item, "/") {
+			addr, err := netip.ParseAddr(item)
+			if err != nil {
+				return nil, err
+			}
+			addr = addr.Unmap()
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(item)
+		if err != nil {
+			return nil, err
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+// parseServiceTokens разбирает список "имя:токен" через запятую.
+// В ошибке указывается номер записи, а не ее значение, чтобы токен не попал в лог
+func parseServiceTokens(value string) (map[string]string, error) {
+	tokens := make(map[string]string)
+	for i, item := range splitList(value) {
+		name, token, ok := strings.Cut(item, ":")
+		name, token = strings.TrimSpace(name), strings.TrimSpace(token)
+		if !ok || name == "" || token == "" {
+			return nil, fmt.Errorf("entry %d must be in name:token format", i+1)
+		}
+		if _, exists := tokens[name]; exists {
+			return nil, fmt.Errorf("duplicate service %q", name)
+		}
+		tokens[name] = token
+	}
+	return tokens, nil
+}
+
+// parseOrderNumberValidators разбирает правила проверки номеров заказов через запятую
+// в формате prefix:<префикс>=<алгоритм> или source:<источник>=<алгоритм>
+func parseOrderNumberValidators(value string) ([]ordernum.Rule, error) {
+	var rules []ordernum.Rule
+	for _, item := range splitList(value) {
+		rule, err := ordernum.ParseRule(item)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	// Повторные правила для одного префикса или источника
+	if _, err := ordernum.NewRegistry(rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// parseScanPolicies разбирает политики сканирования заказов через запятую в формате
+// <статус>=<интервал>[/<возраст>]. Политика задается только для NEW и PROCESSING
+func parseScanPolicies(value string) ([]domain.OrderScanPolicy, error) {
+	var policies []domain.OrderScanPolicy
+	seen := make(map[domain.OrderStatus]bool)
+	for _, item := range splitList(value) {
+		status, durations, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, fmt.Errorf("policy %q must be in <status>=<interval>[/<max_age>] format", item)
+		}
+
+		policy := domain.OrderScanPolicy{Status: domain.OrderStatus(strings.ToUpper(strings.TrimSpace(status)))}
+		if policy.Status != domain.OrderStatusNew && policy.Status != domain.OrderStatusProcessing {
+			return nil, fmt.Errorf("status %q is not polled, expected NEW or PROCESSING", policy.Status)
+		}
+		if seen[policy.Status] {
+			return nil, fmt.Errorf("duplicate policy for %s", policy.Status)
+		}
+		seen[policy.Status] = true
+
+		interval, maxAge, hasMaxAge := strings.Cut(durations, "/")
+		var err error
+		if policy.Interval, err = time.ParseDuration(strings.TrimSpace(interval)); err != nil || policy.Interval < 0 {
+			return nil, fmt.Errorf("invalid interval %q for %s", interval, policy.Status)
+		}
+		if hasMaxAge {
+			// Возраст сравнивается в БД с точностью до секунды
+			if policy.MaxAge, err = time.ParseDuration(strings.TrimSpace(maxAge)); err != nil || policy.MaxAge < time.Second {
+				return nil, fmt.Errorf("invalid max age %q for %s, expected at least 1s", maxAge, policy.Status)
+			}
+		}
+		policies = append(policies, policy)
+	}
+	return policies, nil
+}
+
+// ReadSecretFile читает секрет из файла, отбрасывая пробелы и перевод строки в конце
+func ReadSecretFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	value := strings.TrimSpace(string(data))
+	if value == "" {
+		return "", fmt.Errorf("file %s is empty", path)
+	}
+	return value, nil
+}
+
+// splitList разбирает список значений через запятую, пропуская пустые
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,228 @@
+package config
+
+import (
+	"net/netip"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/avc/loyalty-system-diploma/internal/utils/envelope"
+	"github.com/avc/loyalty-system-diploma/internal/utils/ordernum"
+)
+
+// Source - откуда взято значение параметра конфигурации
+type Source string
+
+const (
+	SourceDefault Source = "default" // Значение по умолчанию
+	SourceFlag    Source = "flag"    // Флаг командной строки
+	SourceEnv     Source = "env"     // Переменная окружения
+)
+
+// redactedValue подставляется вместо секретов, как в url.URL.Redacted
+const redactedValue = "xxxxx"
+
+// flagSettings сопоставляет флаги командной строки с именами параметров
+var flagSettings = map[string]string{
+	"a":                "RUN_ADDRESS",
+	"internal-address": "INTERNAL_RUN_ADDRESS",
+	"d":                "DATABASE_URI",
+	"r":                "ACCRUAL_SYSTEM_ADDRESS",
+
+	"http-read-timeout":  "HTTP_READ_TIMEOUT",
+	"http-write-timeout": "HTTP_WRITE_TIMEOUT",
+	"http-idle-timeout":  "HTTP_IDLE_TIMEOUT",
+	"compression-level":  "COMPRESSION_LEVEL",
+}
+
+// dsnPassword находит пароль в строке подключения формата key=value
+var dsnPassword = regexp.MustCompile(`(?i)(password\s*=\s*)('[^']*'|\S+)`)
+
+// Setting - параметр действующей конфигурации
+type Setting struct {
+	Name   string // Имя переменной окружения
+	Value  string // Значение, секреты скрыты
+	Source Source // Откуда взято значение
+}
+
+// Settings возвращает действующую конфигурацию с источником каждого значения.
+// Секреты скрыты, поэтому результат можно писать в лог и отдавать администраторам.
+func (c *Config) Settings() []Setting {
+	settings := []Setting{
+		{Name: "RUN_ADDRESS", Value: c.RunAddress},
+		{Name: "INTERNAL_RUN_ADDRESS", Value: c.InternalRunAddress},
+		{Name: "DATABASE_URI", Value: redactURI(c.DatabaseURI)},
+		{Name: "ACCRUAL_SYSTEM_ADDRESS", Value: redactURI(c.AccrualSystemAddress)},
+		{Name: "ACCRUAL_PROXY_URL", Value: redactURI(c.AccrualProxyURL)},
+		{Name: "ACCRUAL_CA_FILE", Value: c.AccrualCAFile},
+		{Name: "ACCRUAL_SIGNING_KEY", Value: redactSecret(c.AccrualSigningKey)},
+		{Name: "ACCRUAL_SIGNING_CLOCK_SKEW", Value: c.AccrualClockSkew.String()},
+		{Name: "JWT_SECRET", Value: redactedValue},
+		{Name: "JWT_REMEMBER_TTL", Value: c.JWTRememberTTL.String()},
+		{Name: "JWT_REFRESH_TTL", Value: c.JWTRefreshTTL.String()},
+		{Name: "IMPERSONATION_TTL", Value: c.ImpersonationTTL.String()},
+		{Name: "IMPERSONATION_READ_ONLY", Value: strconv.FormatBool(c.ImpersonationReadOnly)},
+		{Name: "AUTHZ_AUDIT_SAMPLE_RATIO", Value: formatFloat(c.AuthzAuditSampleRatio)},
+		{Name: "LOG_LEVEL", Value: c.LogLevel},
+		{Name: "LOG_SQL", Value: strconv.FormatBool(c.LogSQL)},
+		{Name: "JSON_AMOUNT_FIXED_DECIMALS", Value: strconv.FormatBool(c.JSONAmountFixedDecimals)},
+		{Name: "SLOW_REQUEST_THRESHOLD", Value: c.SlowRequestThreshold.String()},
+		{Name: "SLOW_QUERY_THRESHOLD", Value: c.SlowQueryThreshold.String()},
+		{Name: "SLOW_QUERY_EXPLAIN_THRESHOLD", Value: c.SlowQueryExplainThreshold.String()},
+		{Name: "TRACING_OTLP_ENDPOINT", Value: redactURI(c.TracingEndpoint)},
+		{Name: "TRACING_SERVICE_NAME", Value: c.TracingServiceName},
+		{Name: "TRACING_SAMPLE_RATIO", Value: formatFloat(c.TracingSampleRatio)},
+		{Name: "STARTUP_TIMEOUT", Value: c.StartupTimeout.String()},
+		{Name: "STARTUP_RETRY_BACKOFF", Value: c.StartupRetryBackoff.String()},
+		{Name: "DB_RETRY_ATTEMPTS", Value: strconv.Itoa(c.DBRetryAttempts)},
+		{Name: "DB_RETRY_BACKOFF", Value: c.DBRetryBackoff.String()},
+		{Name: "DB_RETRY_MAX_BACKOFF", Value: c.DBRetryMaxBackoff.String()},
+		{Name: "DB_RECONNECT_INTERVAL", Value: c.DBReconnectInterval.String()},
+		{Name: "DATABASE_URI_FILE", Value: c.DatabaseURIFile},
+		{Name: "DB_CREDENTIALS_RELOAD_INTERVAL", Value: c.DBCredentialsReloadInterval.String()},
+		{Name: "HTTP_READ_TIMEOUT", Value: c.HTTPReadTimeout.String()},
+		{Name: "HTTP_WRITE_TIMEOUT", Value: c.HTTPWriteTimeout.String()},
+		{Name: "HTTP_IDLE_TIMEOUT", Value: c.HTTPIdleTimeout.String()},
+		{Name: "SHUTDOWN_TIMEOUT", Value: c.ShutdownTimeout.String()},
+		{Name: "COMPRESSION_LEVEL", Value: strconv.Itoa(c.CompressionLevel)},
+		{Name: "COMPRESSION_MIN_SIZE", Value: strconv.Itoa(c.CompressionMinSize)},
+		{Name: "COMPRESSION_EXCLUDED_TYPES", Value: strings.Join(c.CompressionExcludedTypes, ",")},
+		{Name: "COMPRESSION_EXCLUDED_PATHS", Value: strings.Join(c.CompressionExcludedPaths, ",")},
+		{Name: "RATE_LIMIT_REQUESTS", Value: strconv.Itoa(c.RateLimitRequests)},
+		{Name: "RATE_LIMIT_WINDOW", Value: c.RateLimitWindow.String()},
+		{Name: "AVAILABILITY_RATE_LIMIT_REQUESTS", Value: strconv.Itoa(c.AvailabilityRateLimit)},
+		{Name: "MAX_INFLIGHT_REQUESTS", Value: strconv.Itoa(c.MaxInFlightRequests)},
+		{Name: "WORKER_POOL_SIZE", Value: strconv.Itoa(c.WorkerPoolSize)},
+		{Name: "WORKER_QUEUE_SIZE", Value: strconv.Itoa(c.WorkerQueueSize)},
+		{Name: "WORKER_BACKLOG_THRESHOLD", Value: strconv.Itoa(c.WorkerBacklogThreshold)},
+		{Name: "WORKER_SCAN_INTERVAL", Value: c.WorkerScanInterval.String()},
+		{Name: "WORKER_MAX_SCAN_INTERVAL", Value: c.WorkerMaxScanInterval.String()},
+		{Name: "WORKER_SCAN_POLICIES", Value: formatScanPolicies(c.WorkerScanPolicies)},
+		{Name: "EVENT_POLL_INTERVAL", Value: c.EventPollInterval.String()},
+		{Name: "LEADER_RENEW_INTERVAL", Value: c.LeaderRenewInterval.String()},
+		{Name: "LOCK_REDIS_URL", Value: redactURI(c.LockRedisURL)},
+		{Name: "LOCK_TTL", Value: c.LockTTL.String()},
+		{Name: "SETTLEMENT_INTERVAL", Value: c.SettlementInterval.String()},
+		{Name: "ACCRUAL_RULES_SYNC_INTERVAL", Value: c.AccrualRulesSyncInterval.String()},
+		{Name: "ORDER_EXPIRY_DAYS", Value: strconv.Itoa(c.OrderExpiryDays)},
+		{Name: "ORDER_EXPIRY_INTERVAL", Value: c.OrderExpiryInterval.String()},
+		{Name: "ORDER_EXPIRY_DRY_RUN", Value: strconv.FormatBool(c.OrderExpiryDryRun)},
+		{Name: "ACCRUAL_REPAIR_INTERVAL", Value: c.AccrualRepairInterval.String()},
+		{Name: "REFRESH_TOKEN_CLEANUP_INTERVAL", Value: c.RefreshTokenCleanupInterval.String()},
+		{Name: "REFRESH_TOKEN_STORE", Value: c.RefreshTokenStore},
+		{Name: "REFRESH_TOKEN_REDIS_URL", Value: redactURI(c.RefreshTokenRedisURL)},
+		{Name: "ORDER_NUMBER_MIN_LENGTH", Value: strconv.Itoa(c.OrderNumberMinLength)},
+		{Name: "ORDER_NUMBER_MAX_LENGTH", Value: strconv.Itoa(c.OrderNumberMaxLength)},
+		{Name: "ORDER_NUMBER_VALIDATORS", Value: formatRules(c.OrderNumberValidators)},
+		{Name: "ORDER_SUBMIT_RATE_LIMIT", Value: strconv.Itoa(c.OrderSubmitRateLimit)},
+		{Name: "ORDER_SUBMIT_RATE_WINDOW", Value: c.OrderSubmitRateWindow.String()},
+		{Name: "ORDER_CONFLICT_LIMIT", Value: strconv.Itoa(c.OrderConflictLimit)},
+		{Name: "ORDER_CONFLICT_WINDOW", Value: c.OrderConflictWindow.String()},
+		{Name: "ORDER_SUBMIT_BAN_DURATION", Value: c.OrderSubmitBanDuration.String()},
+		{Name: "WITHDRAWAL_MAX_AMOUNT", Value: formatFloat(c.WithdrawalMaxAmount)},
+		{Name: "WITHDRAWAL_DAILY_LIMIT", Value: formatFloat(c.WithdrawalDailyLimit)},
+		{Name: "WITHDRAWAL_MONTHLY_LIMIT", Value: formatFloat(c.WithdrawalMonthlyLimit)},
+		{Name: "ACCRUAL_ROUNDING_MODE", Value: string(c.AccrualRoundingMode)},
+		{Name: "ACCRUAL_ROUNDING_PRECISION", Value: strconv.Itoa(c.AccrualRoundingPrecision)},
+		{Name: "ACCRUAL_CORRECTIONS_ENABLED", Value: strconv.FormatBool(c.AccrualCorrectionsEnabled)},
+		{Name: "ADMIN_LOGINS", Value: strings.Join(c.AdminLogins, ",")},
+		{Name: "ADMIN_ALLOWED_CIDRS", Value: formatPrefixes(c.AdminAllowedCIDRs)},
+		{Name: "ADMIN_DENIED_CIDRS", Value: formatPrefixes(c.AdminDeniedCIDRs)},
+		{Name: "TRUSTED_PROXIES", Value: formatPrefixes(c.TrustedProxies)},
+		{Name: "OIDC_ISSUER", Value: c.OIDCIssuer},
+		{Name: "OIDC_AUDIENCE", Value: c.OIDCAudience},
+		{Name: "OIDC_JWKS_URL", Value: redactURI(c.OIDCJWKSURL)},
+		{Name: "OAUTH_REDIRECT_BASE_URL", Value: c.OAuthRedirectBaseURL},
+		{Name: "OAUTH_GOOGLE_CLIENT_ID", Value: c.OAuthGoogleClientID},
+		{Name: "OAUTH_GOOGLE_CLIENT_SECRET", Value: redactSecret(c.OAuthGoogleClientSecret)},
+		{Name: "OAUTH_VK_CLIENT_ID", Value: c.OAuthVKClientID},
+		{Name: "OAUTH_VK_CLIENT_SECRET", Value: redactSecret(c.OAuthVKClientSecret)},
+		{Name: "PII_ENCRYPTION_KEYS", Value: formatKeyIDs(c.PIIEncryptionKeys)},
+		{Name: "INTERNAL_SERVICE_TOKENS", Value: formatServiceNames(c.InternalServiceTokens)},
+		{Name: "REPLAY_WINDOW", Value: c.ReplayWindow.String()},
+		{Name: "REPLAY_NONCE_STORE", Value: c.ReplayNonceStore},
+		{Name: "REPLAY_NONCE_REDIS_URL", Value: redactURI(c.ReplayNonceRedisURL)},
+	}
+
+	for i := range settings {
+		settings[i].Source = c.source(settings[i].Name)
+	}
+	return settings
+}
+
+// source возвращает источник значения параметра
+func (c *Config) source(name string) Source {
+	if source, ok := c.sources[name]; ok {
+		return source
+	}
+	return SourceDefault
+}
+
+// redactURI скрывает пароль в URI или в строке подключения формата key=value
+func redactURI(value string) string {
+	if u, err := url.Parse(value); err == nil && u.Scheme != "" {
+		return u.Redacted()
+	}
+	return dsnPassword.ReplaceAllString(value, "${1}"+redactedValue)
+}
+
+// redactSecret скрывает заданный секрет; пустое значение показывает, что секрет не задан
+func redactSecret(value string) string {
+	if value == "" {
+		return ""
+	}
+	return redactedValue
+}
+
+// formatKeyIDs показывает только идентификаторы ключей шифрования, сами ключи скрыты
+func formatKeyIDs(keys []envelope.Key) string {
+	items := make([]string, len(keys))
+	for i, key := range keys {
+		items[i] = key.ID + ":" + redactedValue
+	}
+	return strings.Join(items, ",")
+}
+
+// formatRules показывает правила проверки номеров заказов в формате конфигурации
+func formatRules(rules []ordernum.Rule) string {
+	values := make([]string, len(rules))
+	for i, rule := range rules {
+		values[i] = rule.String()
+	}
+	return strings.Join(values, ",")
+}
+
+func formatScanPolicies(policies []domain.OrderScanPolicy) string {
+	values := make([]string, len(policies))
+	for i, policy := range policies {
+		values[i] = policy.String()
+	}
+	return strings.Join(values, ",")
+}
+
+// formatServiceNames показывает только имена внутренних сервисов, токены скрыты
+func formatServiceNames(tokens map[string]string) string {
+	names := make([]string, 0, len(tokens))
+	for name := range tokens {
+		names = append(names, name+":"+redactedValue)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ",")
+}
+
+// formatPrefixes форматирует список сетей через запятую
+func formatPrefixes(prefixes []netip.Prefix) string {
+	items := make([]string, len(prefixes))
+	for i, prefix := range prefixes {
+		items[i] = prefix.String()
+	}
+	return strings.Join(items, ",")
+}
+
+// formatFloat форматирует число без лишних нулей
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
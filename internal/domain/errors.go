@@ -0,0 +1,107 @@
+package domain
+
+import "errors"
+
+// Ошибки пользователей и аутентификации
+var (
+	ErrUserExists         = errors.New("user already exists")
+	ErrUserNotFound       = errors.New("user not found")
+	ErrInvalidInput       = errors.New("invalid input")
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrInvalidToken       = errors.New("invalid token")
+	ErrTokenExpired       = errors.New("token expired")
+	ErrTokenMalformed     = errors.New("token malformed")
+	ErrTokenSignature     = errors.New("token signature is invalid")
+	ErrTokenRevoked       = errors.New("token revoked")
+	ErrRefreshTokenReused = errors.New("refresh token has already been used")
+	ErrRefreshTokenDevice = errors.New("refresh token presented from another device")
+	ErrUserMerged         = errors.New("user account has been merged into another")
+	ErrCannotImpersonate  = errors.New("user cannot be impersonated")
+	ErrUnsupportedLocale  = errors.New("unsupported locale")
+)
+
+// Ошибки персональных данных
+var (
+	ErrPIIEncryptionDisabled = errors.New("personal data encryption is not configured")
+	ErrInvalidContacts       = errors.New("invalid contact details")
+)
+
+// Ошибки входа через внешних провайдеров
+var (
+	ErrUnknownOAuthProvider  = errors.New("unknown oauth provider")
+	ErrInvalidOAuthState     = errors.New("invalid oauth state")
+	ErrOAuthExchangeFailed   = errors.New("oauth code exchange failed")
+	ErrIdentityAlreadyLinked = errors.New("external identity is linked to another user")
+)
+
+// Ошибки заказов
+var (
+	ErrInvalidOrderNumber   = errors.New("invalid order number")
+	ErrOrderExists          = errors.New("order already exists")
+	ErrOrderOwnedByAnother  = errors.New("order owned by another user")
+	ErrOrderNotFound        = errors.New("order not found")
+	ErrInvalidOrderMetadata = errors.New("invalid order metadata")
+	ErrOrderAlreadyOwned    = errors.New("order already belongs to this user")
+	ErrOrderAlreadyFinal    = errors.New("order is already in a final status")
+	ErrInvalidFiscalReceipt = errors.New("invalid fiscal receipt")
+)
+
+// Ошибки споров о принадлежности заказа
+var (
+	ErrDisputeNotFound = errors.New("order dispute not found")
+	ErrDisputeExists   = errors.New("order dispute already open")
+	ErrDisputeResolved = errors.New("order dispute already resolved")
+)
+
+// Ошибки ограничения загрузки заказов
+var (
+	ErrTooManySubmissions    = errors.New("too many order submissions")
+	ErrSubmissionsBanned     = errors.New("order submissions are temporarily banned")
+	ErrSubmissionBanNotFound = errors.New("order submission ban not found")
+)
+
+// Ошибки взаимодействия с системой начислений
+var (
+	ErrInvalidAccrualResponse = errors.New("invalid accrual response")
+)
+
+// Ошибки хранилища
+var (
+	ErrStorageUnavailable = errors.New("storage temporarily unavailable")
+)
+
+// Ошибки транзакций и баланса
+var (
+	ErrInsufficientFunds   = errors.New("insufficient funds")
+	ErrDuplicateAccrual    = errors.New("accrual already exists for this order")
+	ErrTransactionNotFound = errors.New("transaction not found")
+	ErrInvalidPartner      = errors.New("invalid partner")
+	ErrInvalidAmount       = errors.New("invalid amount")
+)
+
+// Ошибки взаиморасчетов с партнерами
+var (
+	ErrNothingToSettle    = errors.New("no unsettled withdrawals")
+	ErrSettlementNotFound = errors.New("settlement not found")
+)
+
+// Ошибки корректировки начислений
+var (
+	ErrAccrualCorrectionsDisabled = errors.New("accrual corrections are disabled")
+	ErrAccrualUnchanged           = errors.New("accrual has not changed")
+	ErrOrderNotProcessed          = errors.New("order is not processed")
+	ErrAccrualConsistent          = errors.New("order accrual matches the ledger")
+)
+
+// Ошибки ограничений списаний
+var (
+	ErrWithdrawalAmountLimit  = errors.New("withdrawal exceeds per-withdrawal limit")
+	ErrDailyWithdrawalLimit   = errors.New("daily withdrawal limit exceeded")
+	ErrMonthlyWithdrawalLimit = errors.New("monthly withdrawal limit exceeded")
+	ErrWithdrawalLimitsNotSet = errors.New("withdrawal limits not set")
+)
+
+// Ошибки синхронизации правил вознаграждения
+var (
+	ErrRewardRulesUnavailable = errors.New("accrual system does not expose reward rules")
+)
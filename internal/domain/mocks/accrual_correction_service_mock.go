@@ -0,0 +1,98 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/avc/loyalty-system-diploma/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AccrualCorrectionServiceMock is an autogenerated mock type for the AccrualCorrectionService type
+type AccrualCorrectionServiceMock struct {
+	mock.Mock
+}
+
+type AccrualCorrectionServiceMock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *AccrualCorrectionServiceMock) EXPECT() *AccrualCorrectionServiceMock_Expecter {
+	return &AccrualCorrectionServiceMock_Expecter{mock: &_m.Mock}
+}
+
+// RecheckAccrual provides a mock function with given fields: ctx, number, adminID
+func (_m *AccrualCorrectionServiceMock) RecheckAccrual(ctx context.Context, number string, adminID int64) (*domain.AccrualCorrection, error) {
+	ret := _m.Called(ctx, number, adminID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecheckAccrual")
+	}
+
+	var r0 *domain.AccrualCorrection
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) (*domain.AccrualCorrection, error)); ok {
+		return rf(ctx, number, adminID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) *domain.AccrualCorrection); ok {
+		r0 = rf(ctx, number, adminID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.AccrualCorrection)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64) error); ok {
+		r1 = rf(ctx, number, adminID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AccrualCorrectionServiceMock_RecheckAccrual_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecheckAccrual'
+type AccrualCorrectionServiceMock_RecheckAccrual_Call struct {
+	*mock.Call
+}
+
+// RecheckAccrual is a helper method to define mock.On call
+//   - ctx context.Context
+//   - number string
+//   - adminID int64
+func (_e *AccrualCorrectionServiceMock_Expecter) RecheckAccrual(ctx interface{}, number interface{}, adminID interface{}) *AccrualCorrectionServiceMock_RecheckAccrual_Call {
+	return &AccrualCorrectionServiceMock_RecheckAccrual_Call{Call: _e.mock.On("RecheckAccrual", ctx, number, adminID)}
+}
+
+func (_c *AccrualCorrectionServiceMock_RecheckAccrual_Call) Run(run func(ctx context.Context, number string, adminID int64)) *AccrualCorrectionServiceMock_RecheckAccrual_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int64))
+	})
+	return _c
+}
+
+func (_c *AccrualCorrectionServiceMock_RecheckAccrual_Call) Return(_a0 *domain.AccrualCorrection, _a1 error) *AccrualCorrectionServiceMock_RecheckAccrual_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AccrualCorrectionServiceMock_RecheckAccrual_Call) RunAndReturn(run func(context.Context, string, int64) (*domain.AccrualCorrection, error)) *AccrualCorrectionServiceMock_RecheckAccrual_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewAccrualCorrectionServiceMock creates a new instance of AccrualCorrectionServiceMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAccrualCorrectionServiceMock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AccrualCorrectionServiceMock {
+	mock := &AccrualCorrectionServiceMock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,155 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/avc/loyalty-system-diploma/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AccrualRepairRepositoryMock is an autogenerated mock type for the AccrualRepairRepository type
+type AccrualRepairRepositoryMock struct {
+	mock.Mock
+}
+
+type AccrualRepairRepositoryMock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *AccrualRepairRepositoryMock) EXPECT() *AccrualRepairRepositoryMock_Expecter {
+	return &AccrualRepairRepositoryMock_Expecter{mock: &_m.Mock}
+}
+
+// FindHalfAppliedAccruals provides a mock function with given fields: ctx, limit
+func (_m *AccrualRepairRepositoryMock) FindHalfAppliedAccruals(ctx context.Context, limit int) ([]*domain.AccrualRepair, error) {
+	ret := _m.Called(ctx, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindHalfAppliedAccruals")
+	}
+
+	var r0 []*domain.AccrualRepair
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]*domain.AccrualRepair, error)); ok {
+		return rf(ctx, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []*domain.AccrualRepair); ok {
+		r0 = rf(ctx, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.AccrualRepair)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AccrualRepairRepositoryMock_FindHalfAppliedAccruals_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindHalfAppliedAccruals'
+type AccrualRepairRepositoryMock_FindHalfAppliedAccruals_Call struct {
+	*mock.Call
+}
+
+// FindHalfAppliedAccruals is a helper method to define mock.On call
+//   - ctx context.Context
+//   - limit int
+func (_e *AccrualRepairRepositoryMock_Expecter) FindHalfAppliedAccruals(ctx interface{}, limit interface{}) *AccrualRepairRepositoryMock_FindHalfAppliedAccruals_Call {
+	return &AccrualRepairRepositoryMock_FindHalfAppliedAccruals_Call{Call: _e.mock.On("FindHalfAppliedAccruals", ctx, limit)}
+}
+
+func (_c *AccrualRepairRepositoryMock_FindHalfAppliedAccruals_Call) Run(run func(ctx context.Context, limit int)) *AccrualRepairRepositoryMock_FindHalfAppliedAccruals_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *AccrualRepairRepositoryMock_FindHalfAppliedAccruals_Call) Return(_a0 []*domain.AccrualRepair, _a1 error) *AccrualRepairRepositoryMock_FindHalfAppliedAccruals_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AccrualRepairRepositoryMock_FindHalfAppliedAccruals_Call) RunAndReturn(run func(context.Context, int) ([]*domain.AccrualRepair, error)) *AccrualRepairRepositoryMock_FindHalfAppliedAccruals_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RepairAccrual provides a mock function with given fields: ctx, number
+func (_m *AccrualRepairRepositoryMock) RepairAccrual(ctx context.Context, number string) (*domain.AccrualRepair, error) {
+	ret := _m.Called(ctx, number)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RepairAccrual")
+	}
+
+	var r0 *domain.AccrualRepair
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.AccrualRepair, error)); ok {
+		return rf(ctx, number)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.AccrualRepair); ok {
+		r0 = rf(ctx, number)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.AccrualRepair)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, number)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AccrualRepairRepositoryMock_RepairAccrual_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RepairAccrual'
+type AccrualRepairRepositoryMock_RepairAccrual_Call struct {
+	*mock.Call
+}
+
+// RepairAccrual is a helper method to define mock.On call
+//   - ctx context.Context
+//   - number string
+func (_e *AccrualRepairRepositoryMock_Expecter) RepairAccrual(ctx interface{}, number interface{}) *AccrualRepairRepositoryMock_RepairAccrual_Call {
+	return &AccrualRepairRepositoryMock_RepairAccrual_Call{Call: _e.mock.On("RepairAccrual", ctx, number)}
+}
+
+func (_c *AccrualRepairRepositoryMock_RepairAccrual_Call) Run(run func(ctx context.Context, number string)) *AccrualRepairRepositoryMock_RepairAccrual_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *AccrualRepairRepositoryMock_RepairAccrual_Call) Return(_a0 *domain.AccrualRepair, _a1 error) *AccrualRepairRepositoryMock_RepairAccrual_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AccrualRepairRepositoryMock_RepairAccrual_Call) RunAndReturn(run func(context.Context, string) (*domain.AccrualRepair, error)) *AccrualRepairRepositoryMock_RepairAccrual_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewAccrualRepairRepositoryMock creates a new instance of AccrualRepairRepositoryMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAccrualRepairRepositoryMock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AccrualRepairRepositoryMock {
+	mock := &AccrualRepairRepositoryMock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,98 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/avc/loyalty-system-diploma/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AccrualReportServiceMock is an autogenerated mock type for the AccrualReportService type
+type AccrualReportServiceMock struct {
+	mock.Mock
+}
+
+type AccrualReportServiceMock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *AccrualReportServiceMock) EXPECT() *AccrualReportServiceMock_Expecter {
+	return &AccrualReportServiceMock_Expecter{mock: &_m.Mock}
+}
+
+// AccrualMismatches provides a mock function with given fields: ctx, limit, offset
+func (_m *AccrualReportServiceMock) AccrualMismatches(ctx context.Context, limit int, offset int) (*domain.AccrualMismatchReport, error) {
+	ret := _m.Called(ctx, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AccrualMismatches")
+	}
+
+	var r0 *domain.AccrualMismatchReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (*domain.AccrualMismatchReport, error)); ok {
+		return rf(ctx, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) *domain.AccrualMismatchReport); ok {
+		r0 = rf(ctx, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.AccrualMismatchReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AccrualReportServiceMock_AccrualMismatches_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AccrualMismatches'
+type AccrualReportServiceMock_AccrualMismatches_Call struct {
+	*mock.Call
+}
+
+// AccrualMismatches is a helper method to define mock.On call
+//   - ctx context.Context
+//   - limit int
+//   - offset int
+func (_e *AccrualReportServiceMock_Expecter) AccrualMismatches(ctx interface{}, limit interface{}, offset interface{}) *AccrualReportServiceMock_AccrualMismatches_Call {
+	return &AccrualReportServiceMock_AccrualMismatches_Call{Call: _e.mock.On("AccrualMismatches", ctx, limit, offset)}
+}
+
+func (_c *AccrualReportServiceMock_AccrualMismatches_Call) Run(run func(ctx context.Context, limit int, offset int)) *AccrualReportServiceMock_AccrualMismatches_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *AccrualReportServiceMock_AccrualMismatches_Call) Return(_a0 *domain.AccrualMismatchReport, _a1 error) *AccrualReportServiceMock_AccrualMismatches_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AccrualReportServiceMock_AccrualMismatches_Call) RunAndReturn(run func(context.Context, int, int) (*domain.AccrualMismatchReport, error)) *AccrualReportServiceMock_AccrualMismatches_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewAccrualReportServiceMock creates a new instance of AccrualReportServiceMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAccrualReportServiceMock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AccrualReportServiceMock {
+	mock := &AccrualReportServiceMock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,93 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AdminCheckerMock is an autogenerated mock type for the AdminChecker type
+type AdminCheckerMock struct {
+	mock.Mock
+}
+
+type AdminCheckerMock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *AdminCheckerMock) EXPECT() *AdminCheckerMock_Expecter {
+	return &AdminCheckerMock_Expecter{mock: &_m.Mock}
+}
+
+// IsAdmin provides a mock function with given fields: ctx, userID
+func (_m *AdminCheckerMock) IsAdmin(ctx context.Context, userID int64) (bool, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsAdmin")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (bool, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) bool); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AdminCheckerMock_IsAdmin_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IsAdmin'
+type AdminCheckerMock_IsAdmin_Call struct {
+	*mock.Call
+}
+
+// IsAdmin is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+func (_e *AdminCheckerMock_Expecter) IsAdmin(ctx interface{}, userID interface{}) *AdminCheckerMock_IsAdmin_Call {
+	return &AdminCheckerMock_IsAdmin_Call{Call: _e.mock.On("IsAdmin", ctx, userID)}
+}
+
+func (_c *AdminCheckerMock_IsAdmin_Call) Run(run func(ctx context.Context, userID int64)) *AdminCheckerMock_IsAdmin_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *AdminCheckerMock_IsAdmin_Call) Return(_a0 bool, _a1 error) *AdminCheckerMock_IsAdmin_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AdminCheckerMock_IsAdmin_Call) RunAndReturn(run func(context.Context, int64) (bool, error)) *AdminCheckerMock_IsAdmin_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewAdminCheckerMock creates a new instance of AdminCheckerMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAdminCheckerMock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AdminCheckerMock {
+	mock := &AdminCheckerMock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
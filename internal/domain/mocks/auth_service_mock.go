@@ -5,6 +5,8 @@ package mocks
 import (
 	context "context"
 
+	domain "github.com/avc/loyalty-system-diploma/internal/domain"
+
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -21,27 +23,76 @@ func (_m *AuthServiceMock) EXPECT() *AuthServiceMock_Expecter {
 	return &AuthServiceMock_Expecter{mock: &_m.Mock}
 }
 
-// Login provides a mock function with given fields: ctx, login, password
-func (_m *AuthServiceMock) Login(ctx context.Context, login string, password string) (string, error) {
-	ret := _m.Called(ctx, login, password)
+// CheckLogin provides a mock function with given fields: ctx, login
+func (_m *AuthServiceMock) CheckLogin(ctx context.Context, login string) error {
+	ret := _m.Called(ctx, login)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CheckLogin")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, login)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AuthServiceMock_CheckLogin_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CheckLogin'
+type AuthServiceMock_CheckLogin_Call struct {
+	*mock.Call
+}
+
+// CheckLogin is a helper method to define mock.On call
+//   - ctx context.Context
+//   - login string
+func (_e *AuthServiceMock_Expecter) CheckLogin(ctx interface{}, login interface{}) *AuthServiceMock_CheckLogin_Call {
+	return &AuthServiceMock_CheckLogin_Call{Call: _e.mock.On("CheckLogin", ctx, login)}
+}
+
+func (_c *AuthServiceMock_CheckLogin_Call) Run(run func(ctx context.Context, login string)) *AuthServiceMock_CheckLogin_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *AuthServiceMock_CheckLogin_Call) Return(_a0 error) *AuthServiceMock_CheckLogin_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *AuthServiceMock_CheckLogin_Call) RunAndReturn(run func(context.Context, string) error) *AuthServiceMock_CheckLogin_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Login provides a mock function with given fields: ctx, login, password, device, remember
+func (_m *AuthServiceMock) Login(ctx context.Context, login string, password string, device string, remember bool) (*domain.AuthTokens, error) {
+	ret := _m.Called(ctx, login, password, device, remember)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Login")
 	}
 
-	var r0 string
+	var r0 *domain.AuthTokens
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) (string, error)); ok {
-		return rf(ctx, login, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, bool) (*domain.AuthTokens, error)); ok {
+		return rf(ctx, login, password, device, remember)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) string); ok {
-		r0 = rf(ctx, login, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, bool) *domain.AuthTokens); ok {
+		r0 = rf(ctx, login, password, device, remember)
 	} else {
-		r0 = ret.Get(0).(string)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.AuthTokens)
+		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
-		r1 = rf(ctx, login, password)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, bool) error); ok {
+		r1 = rf(ctx, login, password, device, remember)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -58,48 +109,159 @@ type AuthServiceMock_Login_Call struct {
 //   - ctx context.Context
 //   - login string
 //   - password string
-func (_e *AuthServiceMock_Expecter) Login(ctx interface{}, login interface{}, password interface{}) *AuthServiceMock_Login_Call {
-	return &AuthServiceMock_Login_Call{Call: _e.mock.On("Login", ctx, login, password)}
+//   - device string
+//   - remember bool
+func (_e *AuthServiceMock_Expecter) Login(ctx interface{}, login interface{}, password interface{}, device interface{}, remember interface{}) *AuthServiceMock_Login_Call {
+	return &AuthServiceMock_Login_Call{Call: _e.mock.On("Login", ctx, login, password, device, remember)}
 }
 
-func (_c *AuthServiceMock_Login_Call) Run(run func(ctx context.Context, login string, password string)) *AuthServiceMock_Login_Call {
+func (_c *AuthServiceMock_Login_Call) Run(run func(ctx context.Context, login string, password string, device string, remember bool)) *AuthServiceMock_Login_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].(bool))
+	})
+	return _c
+}
+
+func (_c *AuthServiceMock_Login_Call) Return(_a0 *domain.AuthTokens, _a1 error) *AuthServiceMock_Login_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AuthServiceMock_Login_Call) RunAndReturn(run func(context.Context, string, string, string, bool) (*domain.AuthTokens, error)) *AuthServiceMock_Login_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Logout provides a mock function with given fields: ctx, token
+func (_m *AuthServiceMock) Logout(ctx context.Context, token string) error {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Logout")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AuthServiceMock_Logout_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Logout'
+type AuthServiceMock_Logout_Call struct {
+	*mock.Call
+}
+
+// Logout is a helper method to define mock.On call
+//   - ctx context.Context
+//   - token string
+func (_e *AuthServiceMock_Expecter) Logout(ctx interface{}, token interface{}) *AuthServiceMock_Logout_Call {
+	return &AuthServiceMock_Logout_Call{Call: _e.mock.On("Logout", ctx, token)}
+}
+
+func (_c *AuthServiceMock_Logout_Call) Run(run func(ctx context.Context, token string)) *AuthServiceMock_Logout_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *AuthServiceMock_Logout_Call) Return(_a0 error) *AuthServiceMock_Logout_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *AuthServiceMock_Logout_Call) RunAndReturn(run func(context.Context, string) error) *AuthServiceMock_Logout_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Refresh provides a mock function with given fields: ctx, refreshToken, device
+func (_m *AuthServiceMock) Refresh(ctx context.Context, refreshToken string, device string) (*domain.AuthTokens, error) {
+	ret := _m.Called(ctx, refreshToken, device)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Refresh")
+	}
+
+	var r0 *domain.AuthTokens
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*domain.AuthTokens, error)); ok {
+		return rf(ctx, refreshToken, device)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *domain.AuthTokens); ok {
+		r0 = rf(ctx, refreshToken, device)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.AuthTokens)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, refreshToken, device)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AuthServiceMock_Refresh_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Refresh'
+type AuthServiceMock_Refresh_Call struct {
+	*mock.Call
+}
+
+// Refresh is a helper method to define mock.On call
+//   - ctx context.Context
+//   - refreshToken string
+//   - device string
+func (_e *AuthServiceMock_Expecter) Refresh(ctx interface{}, refreshToken interface{}, device interface{}) *AuthServiceMock_Refresh_Call {
+	return &AuthServiceMock_Refresh_Call{Call: _e.mock.On("Refresh", ctx, refreshToken, device)}
+}
+
+func (_c *AuthServiceMock_Refresh_Call) Run(run func(ctx context.Context, refreshToken string, device string)) *AuthServiceMock_Refresh_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		run(args[0].(context.Context), args[1].(string), args[2].(string))
 	})
 	return _c
 }
 
-func (_c *AuthServiceMock_Login_Call) Return(_a0 string, _a1 error) *AuthServiceMock_Login_Call {
+func (_c *AuthServiceMock_Refresh_Call) Return(_a0 *domain.AuthTokens, _a1 error) *AuthServiceMock_Refresh_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *AuthServiceMock_Login_Call) RunAndReturn(run func(context.Context, string, string) (string, error)) *AuthServiceMock_Login_Call {
+func (_c *AuthServiceMock_Refresh_Call) RunAndReturn(run func(context.Context, string, string) (*domain.AuthTokens, error)) *AuthServiceMock_Refresh_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Register provides a mock function with given fields: ctx, login, password
-func (_m *AuthServiceMock) Register(ctx context.Context, login string, password string) (string, error) {
-	ret := _m.Called(ctx, login, password)
+// Register provides a mock function with given fields: ctx, login, password, device
+func (_m *AuthServiceMock) Register(ctx context.Context, login string, password string, device string) (*domain.AuthTokens, error) {
+	ret := _m.Called(ctx, login, password, device)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Register")
 	}
 
-	var r0 string
+	var r0 *domain.AuthTokens
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) (string, error)); ok {
-		return rf(ctx, login, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (*domain.AuthTokens, error)); ok {
+		return rf(ctx, login, password, device)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) string); ok {
-		r0 = rf(ctx, login, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *domain.AuthTokens); ok {
+		r0 = rf(ctx, login, password, device)
 	} else {
-		r0 = ret.Get(0).(string)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.AuthTokens)
+		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
-		r1 = rf(ctx, login, password)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, login, password, device)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -116,23 +278,24 @@ type AuthServiceMock_Register_Call struct {
 //   - ctx context.Context
 //   - login string
 //   - password string
-func (_e *AuthServiceMock_Expecter) Register(ctx interface{}, login interface{}, password interface{}) *AuthServiceMock_Register_Call {
-	return &AuthServiceMock_Register_Call{Call: _e.mock.On("Register", ctx, login, password)}
+//   - device string
+func (_e *AuthServiceMock_Expecter) Register(ctx interface{}, login interface{}, password interface{}, device interface{}) *AuthServiceMock_Register_Call {
+	return &AuthServiceMock_Register_Call{Call: _e.mock.On("Register", ctx, login, password, device)}
 }
 
-func (_c *AuthServiceMock_Register_Call) Run(run func(ctx context.Context, login string, password string)) *AuthServiceMock_Register_Call {
+func (_c *AuthServiceMock_Register_Call) Run(run func(ctx context.Context, login string, password string, device string)) *AuthServiceMock_Register_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].(string))
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string))
 	})
 	return _c
 }
 
-func (_c *AuthServiceMock_Register_Call) Return(_a0 string, _a1 error) *AuthServiceMock_Register_Call {
+func (_c *AuthServiceMock_Register_Call) Return(_a0 *domain.AuthTokens, _a1 error) *AuthServiceMock_Register_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *AuthServiceMock_Register_Call) RunAndReturn(run func(context.Context, string, string) (string, error)) *AuthServiceMock_Register_Call {
+func (_c *AuthServiceMock_Register_Call) RunAndReturn(run func(context.Context, string, string, string) (*domain.AuthTokens, error)) *AuthServiceMock_Register_Call {
 	_c.Call.Return(run)
 	return _c
 }
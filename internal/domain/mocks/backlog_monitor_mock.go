@@ -0,0 +1,126 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "github.com/avc/loyalty-system-diploma/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// BacklogMonitorMock is an autogenerated mock type for the BacklogMonitor type
+type BacklogMonitorMock struct {
+	mock.Mock
+}
+
+type BacklogMonitorMock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *BacklogMonitorMock) EXPECT() *BacklogMonitorMock_Expecter {
+	return &BacklogMonitorMock_Expecter{mock: &_m.Mock}
+}
+
+// EstimateProcessing provides a mock function with no fields
+func (_m *BacklogMonitorMock) EstimateProcessing() domain.ProcessingEstimate {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for EstimateProcessing")
+	}
+
+	var r0 domain.ProcessingEstimate
+	if rf, ok := ret.Get(0).(func() domain.ProcessingEstimate); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(domain.ProcessingEstimate)
+	}
+
+	return r0
+}
+
+// BacklogMonitorMock_EstimateProcessing_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'EstimateProcessing'
+type BacklogMonitorMock_EstimateProcessing_Call struct {
+	*mock.Call
+}
+
+// EstimateProcessing is a helper method to define mock.On call
+func (_e *BacklogMonitorMock_Expecter) EstimateProcessing() *BacklogMonitorMock_EstimateProcessing_Call {
+	return &BacklogMonitorMock_EstimateProcessing_Call{Call: _e.mock.On("EstimateProcessing")}
+}
+
+func (_c *BacklogMonitorMock_EstimateProcessing_Call) Run(run func()) *BacklogMonitorMock_EstimateProcessing_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *BacklogMonitorMock_EstimateProcessing_Call) Return(_a0 domain.ProcessingEstimate) *BacklogMonitorMock_EstimateProcessing_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *BacklogMonitorMock_EstimateProcessing_Call) RunAndReturn(run func() domain.ProcessingEstimate) *BacklogMonitorMock_EstimateProcessing_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Overloaded provides a mock function with no fields
+func (_m *BacklogMonitorMock) Overloaded() bool {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Overloaded")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// BacklogMonitorMock_Overloaded_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Overloaded'
+type BacklogMonitorMock_Overloaded_Call struct {
+	*mock.Call
+}
+
+// Overloaded is a helper method to define mock.On call
+func (_e *BacklogMonitorMock_Expecter) Overloaded() *BacklogMonitorMock_Overloaded_Call {
+	return &BacklogMonitorMock_Overloaded_Call{Call: _e.mock.On("Overloaded")}
+}
+
+func (_c *BacklogMonitorMock_Overloaded_Call) Run(run func()) *BacklogMonitorMock_Overloaded_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *BacklogMonitorMock_Overloaded_Call) Return(_a0 bool) *BacklogMonitorMock_Overloaded_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *BacklogMonitorMock_Overloaded_Call) RunAndReturn(run func() bool) *BacklogMonitorMock_Overloaded_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewBacklogMonitorMock creates a new instance of BacklogMonitorMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewBacklogMonitorMock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *BacklogMonitorMock {
+	mock := &BacklogMonitorMock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
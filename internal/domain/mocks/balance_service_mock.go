@@ -6,7 +6,10 @@ import (
 	context "context"
 
 	domain "github.com/avc/loyalty-system-diploma/internal/domain"
+
 	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
 )
 
 // BalanceServiceMock is an autogenerated mock type for the BalanceService type
@@ -81,24 +84,143 @@ func (_c *BalanceServiceMock_GetBalance_Call) RunAndReturn(run func(context.Cont
 	return _c
 }
 
-// GetWithdrawals provides a mock function with given fields: ctx, userID
-func (_m *BalanceServiceMock) GetWithdrawals(ctx context.Context, userID int64) ([]*domain.Transaction, error) {
+// GetBalanceDetails provides a mock function with given fields: ctx, userID
+func (_m *BalanceServiceMock) GetBalanceDetails(ctx context.Context, userID int64) (*domain.BalanceDetails, error) {
 	ret := _m.Called(ctx, userID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetWithdrawals")
+		panic("no return value specified for GetBalanceDetails")
+	}
+
+	var r0 *domain.BalanceDetails
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*domain.BalanceDetails, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *domain.BalanceDetails); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.BalanceDetails)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// BalanceServiceMock_GetBalanceDetails_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetBalanceDetails'
+type BalanceServiceMock_GetBalanceDetails_Call struct {
+	*mock.Call
+}
+
+// GetBalanceDetails is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+func (_e *BalanceServiceMock_Expecter) GetBalanceDetails(ctx interface{}, userID interface{}) *BalanceServiceMock_GetBalanceDetails_Call {
+	return &BalanceServiceMock_GetBalanceDetails_Call{Call: _e.mock.On("GetBalanceDetails", ctx, userID)}
+}
+
+func (_c *BalanceServiceMock_GetBalanceDetails_Call) Run(run func(ctx context.Context, userID int64)) *BalanceServiceMock_GetBalanceDetails_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *BalanceServiceMock_GetBalanceDetails_Call) Return(_a0 *domain.BalanceDetails, _a1 error) *BalanceServiceMock_GetBalanceDetails_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *BalanceServiceMock_GetBalanceDetails_Call) RunAndReturn(run func(context.Context, int64) (*domain.BalanceDetails, error)) *BalanceServiceMock_GetBalanceDetails_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetWithdrawal provides a mock function with given fields: ctx, userID, publicID
+func (_m *BalanceServiceMock) GetWithdrawal(ctx context.Context, userID int64, publicID uuid.UUID) (*domain.Transaction, error) {
+	ret := _m.Called(ctx, userID, publicID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWithdrawal")
+	}
+
+	var r0 *domain.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, uuid.UUID) (*domain.Transaction, error)); ok {
+		return rf(ctx, userID, publicID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, uuid.UUID) *domain.Transaction); ok {
+		r0 = rf(ctx, userID, publicID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID, publicID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// BalanceServiceMock_GetWithdrawal_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetWithdrawal'
+type BalanceServiceMock_GetWithdrawal_Call struct {
+	*mock.Call
+}
+
+// GetWithdrawal is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - publicID uuid.UUID
+func (_e *BalanceServiceMock_Expecter) GetWithdrawal(ctx interface{}, userID interface{}, publicID interface{}) *BalanceServiceMock_GetWithdrawal_Call {
+	return &BalanceServiceMock_GetWithdrawal_Call{Call: _e.mock.On("GetWithdrawal", ctx, userID, publicID)}
+}
+
+func (_c *BalanceServiceMock_GetWithdrawal_Call) Run(run func(ctx context.Context, userID int64, publicID uuid.UUID)) *BalanceServiceMock_GetWithdrawal_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *BalanceServiceMock_GetWithdrawal_Call) Return(_a0 *domain.Transaction, _a1 error) *BalanceServiceMock_GetWithdrawal_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *BalanceServiceMock_GetWithdrawal_Call) RunAndReturn(run func(context.Context, int64, uuid.UUID) (*domain.Transaction, error)) *BalanceServiceMock_GetWithdrawal_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetWithdrawalSummary provides a mock function with given fields: ctx, userID
+func (_m *BalanceServiceMock) GetWithdrawalSummary(ctx context.Context, userID int64) (*domain.WithdrawalSummary, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWithdrawalSummary")
 	}
 
-	var r0 []*domain.Transaction
+	var r0 *domain.WithdrawalSummary
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]*domain.Transaction, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*domain.WithdrawalSummary, error)); ok {
 		return rf(ctx, userID)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int64) []*domain.Transaction); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *domain.WithdrawalSummary); ok {
 		r0 = rf(ctx, userID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*domain.Transaction)
+			r0 = ret.Get(0).(*domain.WithdrawalSummary)
 		}
 	}
 
@@ -111,6 +233,65 @@ func (_m *BalanceServiceMock) GetWithdrawals(ctx context.Context, userID int64)
 	return r0, r1
 }
 
+// BalanceServiceMock_GetWithdrawalSummary_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetWithdrawalSummary'
+type BalanceServiceMock_GetWithdrawalSummary_Call struct {
+	*mock.Call
+}
+
+// GetWithdrawalSummary is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+func (_e *BalanceServiceMock_Expecter) GetWithdrawalSummary(ctx interface{}, userID interface{}) *BalanceServiceMock_GetWithdrawalSummary_Call {
+	return &BalanceServiceMock_GetWithdrawalSummary_Call{Call: _e.mock.On("GetWithdrawalSummary", ctx, userID)}
+}
+
+func (_c *BalanceServiceMock_GetWithdrawalSummary_Call) Run(run func(ctx context.Context, userID int64)) *BalanceServiceMock_GetWithdrawalSummary_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *BalanceServiceMock_GetWithdrawalSummary_Call) Return(_a0 *domain.WithdrawalSummary, _a1 error) *BalanceServiceMock_GetWithdrawalSummary_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *BalanceServiceMock_GetWithdrawalSummary_Call) RunAndReturn(run func(context.Context, int64) (*domain.WithdrawalSummary, error)) *BalanceServiceMock_GetWithdrawalSummary_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetWithdrawals provides a mock function with given fields: ctx, userID, after, limit
+func (_m *BalanceServiceMock) GetWithdrawals(ctx context.Context, userID int64, after *domain.WithdrawalCursor, limit int) (*domain.WithdrawalPage, error) {
+	ret := _m.Called(ctx, userID, after, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWithdrawals")
+	}
+
+	var r0 *domain.WithdrawalPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *domain.WithdrawalCursor, int) (*domain.WithdrawalPage, error)); ok {
+		return rf(ctx, userID, after, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *domain.WithdrawalCursor, int) *domain.WithdrawalPage); ok {
+		r0 = rf(ctx, userID, after, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.WithdrawalPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, *domain.WithdrawalCursor, int) error); ok {
+		r1 = rf(ctx, userID, after, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // BalanceServiceMock_GetWithdrawals_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetWithdrawals'
 type BalanceServiceMock_GetWithdrawals_Call struct {
 	*mock.Call
@@ -119,38 +300,40 @@ type BalanceServiceMock_GetWithdrawals_Call struct {
 // GetWithdrawals is a helper method to define mock.On call
 //   - ctx context.Context
 //   - userID int64
-func (_e *BalanceServiceMock_Expecter) GetWithdrawals(ctx interface{}, userID interface{}) *BalanceServiceMock_GetWithdrawals_Call {
-	return &BalanceServiceMock_GetWithdrawals_Call{Call: _e.mock.On("GetWithdrawals", ctx, userID)}
+//   - after *domain.WithdrawalCursor
+//   - limit int
+func (_e *BalanceServiceMock_Expecter) GetWithdrawals(ctx interface{}, userID interface{}, after interface{}, limit interface{}) *BalanceServiceMock_GetWithdrawals_Call {
+	return &BalanceServiceMock_GetWithdrawals_Call{Call: _e.mock.On("GetWithdrawals", ctx, userID, after, limit)}
 }
 
-func (_c *BalanceServiceMock_GetWithdrawals_Call) Run(run func(ctx context.Context, userID int64)) *BalanceServiceMock_GetWithdrawals_Call {
+func (_c *BalanceServiceMock_GetWithdrawals_Call) Run(run func(ctx context.Context, userID int64, after *domain.WithdrawalCursor, limit int)) *BalanceServiceMock_GetWithdrawals_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int64))
+		run(args[0].(context.Context), args[1].(int64), args[2].(*domain.WithdrawalCursor), args[3].(int))
 	})
 	return _c
 }
 
-func (_c *BalanceServiceMock_GetWithdrawals_Call) Return(_a0 []*domain.Transaction, _a1 error) *BalanceServiceMock_GetWithdrawals_Call {
+func (_c *BalanceServiceMock_GetWithdrawals_Call) Return(_a0 *domain.WithdrawalPage, _a1 error) *BalanceServiceMock_GetWithdrawals_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *BalanceServiceMock_GetWithdrawals_Call) RunAndReturn(run func(context.Context, int64) ([]*domain.Transaction, error)) *BalanceServiceMock_GetWithdrawals_Call {
+func (_c *BalanceServiceMock_GetWithdrawals_Call) RunAndReturn(run func(context.Context, int64, *domain.WithdrawalCursor, int) (*domain.WithdrawalPage, error)) *BalanceServiceMock_GetWithdrawals_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Withdraw provides a mock function with given fields: ctx, userID, orderNumber, amount
-func (_m *BalanceServiceMock) Withdraw(ctx context.Context, userID int64, orderNumber string, amount float64) error {
-	ret := _m.Called(ctx, userID, orderNumber, amount)
+// Withdraw provides a mock function with given fields: ctx, userID, orderNumber, amount, partner
+func (_m *BalanceServiceMock) Withdraw(ctx context.Context, userID int64, orderNumber string, amount float64, partner string) error {
+	ret := _m.Called(ctx, userID, orderNumber, amount, partner)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Withdraw")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, int64, string, float64) error); ok {
-		r0 = rf(ctx, userID, orderNumber, amount)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, float64, string) error); ok {
+		r0 = rf(ctx, userID, orderNumber, amount, partner)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -168,13 +351,14 @@ type BalanceServiceMock_Withdraw_Call struct {
 //   - userID int64
 //   - orderNumber string
 //   - amount float64
-func (_e *BalanceServiceMock_Expecter) Withdraw(ctx interface{}, userID interface{}, orderNumber interface{}, amount interface{}) *BalanceServiceMock_Withdraw_Call {
-	return &BalanceServiceMock_Withdraw_Call{Call: _e.mock.On("Withdraw", ctx, userID, orderNumber, amount)}
+//   - partner string
+func (_e *BalanceServiceMock_Expecter) Withdraw(ctx interface{}, userID interface{}, orderNumber interface{}, amount interface{}, partner interface{}) *BalanceServiceMock_Withdraw_Call {
+	return &BalanceServiceMock_Withdraw_Call{Call: _e.mock.On("Withdraw", ctx, userID, orderNumber, amount, partner)}
 }
 
-func (_c *BalanceServiceMock_Withdraw_Call) Run(run func(ctx context.Context, userID int64, orderNumber string, amount float64)) *BalanceServiceMock_Withdraw_Call {
+func (_c *BalanceServiceMock_Withdraw_Call) Run(run func(ctx context.Context, userID int64, orderNumber string, amount float64, partner string)) *BalanceServiceMock_Withdraw_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int64), args[2].(string), args[3].(float64))
+		run(args[0].(context.Context), args[1].(int64), args[2].(string), args[3].(float64), args[4].(string))
 	})
 	return _c
 }
@@ -184,7 +368,7 @@ func (_c *BalanceServiceMock_Withdraw_Call) Return(_a0 error) *BalanceServiceMoc
 	return _c
 }
 
-func (_c *BalanceServiceMock_Withdraw_Call) RunAndReturn(run func(context.Context, int64, string, float64) error) *BalanceServiceMock_Withdraw_Call {
+func (_c *BalanceServiceMock_Withdraw_Call) RunAndReturn(run func(context.Context, int64, string, float64, string) error) *BalanceServiceMock_Withdraw_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -0,0 +1,216 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/avc/loyalty-system-diploma/internal/domain"
+
+	jobs "github.com/avc/loyalty-system-diploma/internal/jobs"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ContactsServiceMock is an autogenerated mock type for the ContactsService type
+type ContactsServiceMock struct {
+	mock.Mock
+}
+
+type ContactsServiceMock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *ContactsServiceMock) EXPECT() *ContactsServiceMock_Expecter {
+	return &ContactsServiceMock_Expecter{mock: &_m.Mock}
+}
+
+// GetContacts provides a mock function with given fields: ctx, userID
+func (_m *ContactsServiceMock) GetContacts(ctx context.Context, userID int64) (*domain.UserContacts, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetContacts")
+	}
+
+	var r0 *domain.UserContacts
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*domain.UserContacts, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *domain.UserContacts); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.UserContacts)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ContactsServiceMock_GetContacts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetContacts'
+type ContactsServiceMock_GetContacts_Call struct {
+	*mock.Call
+}
+
+// GetContacts is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+func (_e *ContactsServiceMock_Expecter) GetContacts(ctx interface{}, userID interface{}) *ContactsServiceMock_GetContacts_Call {
+	return &ContactsServiceMock_GetContacts_Call{Call: _e.mock.On("GetContacts", ctx, userID)}
+}
+
+func (_c *ContactsServiceMock_GetContacts_Call) Run(run func(ctx context.Context, userID int64)) *ContactsServiceMock_GetContacts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *ContactsServiceMock_GetContacts_Call) Return(_a0 *domain.UserContacts, _a1 error) *ContactsServiceMock_GetContacts_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ContactsServiceMock_GetContacts_Call) RunAndReturn(run func(context.Context, int64) (*domain.UserContacts, error)) *ContactsServiceMock_GetContacts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RewrapContacts provides a mock function with given fields: ctx, progress
+func (_m *ContactsServiceMock) RewrapContacts(ctx context.Context, progress jobs.Progress) (int, error) {
+	ret := _m.Called(ctx, progress)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RewrapContacts")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, jobs.Progress) (int, error)); ok {
+		return rf(ctx, progress)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, jobs.Progress) int); ok {
+		r0 = rf(ctx, progress)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, jobs.Progress) error); ok {
+		r1 = rf(ctx, progress)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ContactsServiceMock_RewrapContacts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RewrapContacts'
+type ContactsServiceMock_RewrapContacts_Call struct {
+	*mock.Call
+}
+
+// RewrapContacts is a helper method to define mock.On call
+//   - ctx context.Context
+//   - progress jobs.Progress
+func (_e *ContactsServiceMock_Expecter) RewrapContacts(ctx interface{}, progress interface{}) *ContactsServiceMock_RewrapContacts_Call {
+	return &ContactsServiceMock_RewrapContacts_Call{Call: _e.mock.On("RewrapContacts", ctx, progress)}
+}
+
+func (_c *ContactsServiceMock_RewrapContacts_Call) Run(run func(ctx context.Context, progress jobs.Progress)) *ContactsServiceMock_RewrapContacts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(jobs.Progress))
+	})
+	return _c
+}
+
+func (_c *ContactsServiceMock_RewrapContacts_Call) Return(_a0 int, _a1 error) *ContactsServiceMock_RewrapContacts_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ContactsServiceMock_RewrapContacts_Call) RunAndReturn(run func(context.Context, jobs.Progress) (int, error)) *ContactsServiceMock_RewrapContacts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateContacts provides a mock function with given fields: ctx, userID, contacts
+func (_m *ContactsServiceMock) UpdateContacts(ctx context.Context, userID int64, contacts domain.UserContacts) (*domain.UserContacts, error) {
+	ret := _m.Called(ctx, userID, contacts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateContacts")
+	}
+
+	var r0 *domain.UserContacts
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, domain.UserContacts) (*domain.UserContacts, error)); ok {
+		return rf(ctx, userID, contacts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, domain.UserContacts) *domain.UserContacts); ok {
+		r0 = rf(ctx, userID, contacts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.UserContacts)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, domain.UserContacts) error); ok {
+		r1 = rf(ctx, userID, contacts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ContactsServiceMock_UpdateContacts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateContacts'
+type ContactsServiceMock_UpdateContacts_Call struct {
+	*mock.Call
+}
+
+// UpdateContacts is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - contacts domain.UserContacts
+func (_e *ContactsServiceMock_Expecter) UpdateContacts(ctx interface{}, userID interface{}, contacts interface{}) *ContactsServiceMock_UpdateContacts_Call {
+	return &ContactsServiceMock_UpdateContacts_Call{Call: _e.mock.On("UpdateContacts", ctx, userID, contacts)}
+}
+
+func (_c *ContactsServiceMock_UpdateContacts_Call) Run(run func(ctx context.Context, userID int64, contacts domain.UserContacts)) *ContactsServiceMock_UpdateContacts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(domain.UserContacts))
+	})
+	return _c
+}
+
+func (_c *ContactsServiceMock_UpdateContacts_Call) Return(_a0 *domain.UserContacts, _a1 error) *ContactsServiceMock_UpdateContacts_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ContactsServiceMock_UpdateContacts_Call) RunAndReturn(run func(context.Context, int64, domain.UserContacts) (*domain.UserContacts, error)) *ContactsServiceMock_UpdateContacts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewContactsServiceMock creates a new instance of ContactsServiceMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewContactsServiceMock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ContactsServiceMock {
+	mock := &ContactsServiceMock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,99 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/avc/loyalty-system-diploma/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// DuplicateSubmissionReportServiceMock is an autogenerated mock type for the DuplicateSubmissionReportService type
+type DuplicateSubmissionReportServiceMock struct {
+	mock.Mock
+}
+
+type DuplicateSubmissionReportServiceMock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *DuplicateSubmissionReportServiceMock) EXPECT() *DuplicateSubmissionReportServiceMock_Expecter {
+	return &DuplicateSubmissionReportServiceMock_Expecter{mock: &_m.Mock}
+}
+
+// DuplicateSubmissions provides a mock function with given fields: ctx, days, limit, offset
+func (_m *DuplicateSubmissionReportServiceMock) DuplicateSubmissions(ctx context.Context, days int, limit int, offset int) (*domain.DuplicateSubmissionReport, error) {
+	ret := _m.Called(ctx, days, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DuplicateSubmissions")
+	}
+
+	var r0 *domain.DuplicateSubmissionReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) (*domain.DuplicateSubmissionReport, error)); ok {
+		return rf(ctx, days, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) *domain.DuplicateSubmissionReport); ok {
+		r0 = rf(ctx, days, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.DuplicateSubmissionReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, int) error); ok {
+		r1 = rf(ctx, days, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DuplicateSubmissionReportServiceMock_DuplicateSubmissions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DuplicateSubmissions'
+type DuplicateSubmissionReportServiceMock_DuplicateSubmissions_Call struct {
+	*mock.Call
+}
+
+// DuplicateSubmissions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - days int
+//   - limit int
+//   - offset int
+func (_e *DuplicateSubmissionReportServiceMock_Expecter) DuplicateSubmissions(ctx interface{}, days interface{}, limit interface{}, offset interface{}) *DuplicateSubmissionReportServiceMock_DuplicateSubmissions_Call {
+	return &DuplicateSubmissionReportServiceMock_DuplicateSubmissions_Call{Call: _e.mock.On("DuplicateSubmissions", ctx, days, limit, offset)}
+}
+
+func (_c *DuplicateSubmissionReportServiceMock_DuplicateSubmissions_Call) Run(run func(ctx context.Context, days int, limit int, offset int)) *DuplicateSubmissionReportServiceMock_DuplicateSubmissions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *DuplicateSubmissionReportServiceMock_DuplicateSubmissions_Call) Return(_a0 *domain.DuplicateSubmissionReport, _a1 error) *DuplicateSubmissionReportServiceMock_DuplicateSubmissions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DuplicateSubmissionReportServiceMock_DuplicateSubmissions_Call) RunAndReturn(run func(context.Context, int, int, int) (*domain.DuplicateSubmissionReport, error)) *DuplicateSubmissionReportServiceMock_DuplicateSubmissions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewDuplicateSubmissionReportServiceMock creates a new instance of DuplicateSubmissionReportServiceMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDuplicateSubmissionReportServiceMock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DuplicateSubmissionReportServiceMock {
+	mock := &DuplicateSubmissionReportServiceMock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
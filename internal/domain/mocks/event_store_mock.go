@@ -0,0 +1,144 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/avc/loyalty-system-diploma/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// EventStoreMock is an autogenerated mock type for the EventStore type
+type EventStoreMock struct {
+	mock.Mock
+}
+
+type EventStoreMock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *EventStoreMock) EXPECT() *EventStoreMock_Expecter {
+	return &EventStoreMock_Expecter{mock: &_m.Mock}
+}
+
+// GetPendingEvents provides a mock function with given fields: ctx, limit
+func (_m *EventStoreMock) GetPendingEvents(ctx context.Context, limit int) ([]*domain.OrderEvent, error) {
+	ret := _m.Called(ctx, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPendingEvents")
+	}
+
+	var r0 []*domain.OrderEvent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]*domain.OrderEvent, error)); ok {
+		return rf(ctx, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []*domain.OrderEvent); ok {
+		r0 = rf(ctx, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.OrderEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// EventStoreMock_GetPendingEvents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPendingEvents'
+type EventStoreMock_GetPendingEvents_Call struct {
+	*mock.Call
+}
+
+// GetPendingEvents is a helper method to define mock.On call
+//   - ctx context.Context
+//   - limit int
+func (_e *EventStoreMock_Expecter) GetPendingEvents(ctx interface{}, limit interface{}) *EventStoreMock_GetPendingEvents_Call {
+	return &EventStoreMock_GetPendingEvents_Call{Call: _e.mock.On("GetPendingEvents", ctx, limit)}
+}
+
+func (_c *EventStoreMock_GetPendingEvents_Call) Run(run func(ctx context.Context, limit int)) *EventStoreMock_GetPendingEvents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *EventStoreMock_GetPendingEvents_Call) Return(_a0 []*domain.OrderEvent, _a1 error) *EventStoreMock_GetPendingEvents_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *EventStoreMock_GetPendingEvents_Call) RunAndReturn(run func(context.Context, int) ([]*domain.OrderEvent, error)) *EventStoreMock_GetPendingEvents_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkEventsDispatched provides a mock function with given fields: ctx, ids
+func (_m *EventStoreMock) MarkEventsDispatched(ctx context.Context, ids []int64) error {
+	ret := _m.Called(ctx, ids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkEventsDispatched")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []int64) error); ok {
+		r0 = rf(ctx, ids)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EventStoreMock_MarkEventsDispatched_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkEventsDispatched'
+type EventStoreMock_MarkEventsDispatched_Call struct {
+	*mock.Call
+}
+
+// MarkEventsDispatched is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ids []int64
+func (_e *EventStoreMock_Expecter) MarkEventsDispatched(ctx interface{}, ids interface{}) *EventStoreMock_MarkEventsDispatched_Call {
+	return &EventStoreMock_MarkEventsDispatched_Call{Call: _e.mock.On("MarkEventsDispatched", ctx, ids)}
+}
+
+func (_c *EventStoreMock_MarkEventsDispatched_Call) Run(run func(ctx context.Context, ids []int64)) *EventStoreMock_MarkEventsDispatched_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]int64))
+	})
+	return _c
+}
+
+func (_c *EventStoreMock_MarkEventsDispatched_Call) Return(_a0 error) *EventStoreMock_MarkEventsDispatched_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *EventStoreMock_MarkEventsDispatched_Call) RunAndReturn(run func(context.Context, []int64) error) *EventStoreMock_MarkEventsDispatched_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewEventStoreMock creates a new instance of EventStoreMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewEventStoreMock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *EventStoreMock {
+	mock := &EventStoreMock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,142 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/avc/loyalty-system-diploma/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ExternalTokenVerifierMock is an autogenerated mock type for the ExternalTokenVerifier type
+type ExternalTokenVerifierMock struct {
+	mock.Mock
+}
+
+type ExternalTokenVerifierMock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *ExternalTokenVerifierMock) EXPECT() *ExternalTokenVerifierMock_Expecter {
+	return &ExternalTokenVerifierMock_Expecter{mock: &_m.Mock}
+}
+
+// Accepts provides a mock function with given fields: token
+func (_m *ExternalTokenVerifierMock) Accepts(token string) bool {
+	ret := _m.Called(token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Accepts")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(string) bool); ok {
+		r0 = rf(token)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// ExternalTokenVerifierMock_Accepts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Accepts'
+type ExternalTokenVerifierMock_Accepts_Call struct {
+	*mock.Call
+}
+
+// Accepts is a helper method to define mock.On call
+//   - token string
+func (_e *ExternalTokenVerifierMock_Expecter) Accepts(token interface{}) *ExternalTokenVerifierMock_Accepts_Call {
+	return &ExternalTokenVerifierMock_Accepts_Call{Call: _e.mock.On("Accepts", token)}
+}
+
+func (_c *ExternalTokenVerifierMock_Accepts_Call) Run(run func(token string)) *ExternalTokenVerifierMock_Accepts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *ExternalTokenVerifierMock_Accepts_Call) Return(_a0 bool) *ExternalTokenVerifierMock_Accepts_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *ExternalTokenVerifierMock_Accepts_Call) RunAndReturn(run func(string) bool) *ExternalTokenVerifierMock_Accepts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Verify provides a mock function with given fields: ctx, token
+func (_m *ExternalTokenVerifierMock) Verify(ctx context.Context, token string) (*domain.ExternalIdentity, error) {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Verify")
+	}
+
+	var r0 *domain.ExternalIdentity
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.ExternalIdentity, error)); ok {
+		return rf(ctx, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.ExternalIdentity); ok {
+		r0 = rf(ctx, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ExternalIdentity)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ExternalTokenVerifierMock_Verify_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Verify'
+type ExternalTokenVerifierMock_Verify_Call struct {
+	*mock.Call
+}
+
+// Verify is a helper method to define mock.On call
+//   - ctx context.Context
+//   - token string
+func (_e *ExternalTokenVerifierMock_Expecter) Verify(ctx interface{}, token interface{}) *ExternalTokenVerifierMock_Verify_Call {
+	return &ExternalTokenVerifierMock_Verify_Call{Call: _e.mock.On("Verify", ctx, token)}
+}
+
+func (_c *ExternalTokenVerifierMock_Verify_Call) Run(run func(ctx context.Context, token string)) *ExternalTokenVerifierMock_Verify_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *ExternalTokenVerifierMock_Verify_Call) Return(_a0 *domain.ExternalIdentity, _a1 error) *ExternalTokenVerifierMock_Verify_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ExternalTokenVerifierMock_Verify_Call) RunAndReturn(run func(context.Context, string) (*domain.ExternalIdentity, error)) *ExternalTokenVerifierMock_Verify_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewExternalTokenVerifierMock creates a new instance of ExternalTokenVerifierMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewExternalTokenVerifierMock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ExternalTokenVerifierMock {
+	mock := &ExternalTokenVerifierMock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
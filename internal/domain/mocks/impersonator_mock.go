@@ -0,0 +1,98 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/avc/loyalty-system-diploma/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ImpersonatorMock is an autogenerated mock type for the Impersonator type
+type ImpersonatorMock struct {
+	mock.Mock
+}
+
+type ImpersonatorMock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *ImpersonatorMock) EXPECT() *ImpersonatorMock_Expecter {
+	return &ImpersonatorMock_Expecter{mock: &_m.Mock}
+}
+
+// Impersonate provides a mock function with given fields: ctx, adminID, userID
+func (_m *ImpersonatorMock) Impersonate(ctx context.Context, adminID int64, userID int64) (*domain.Impersonation, error) {
+	ret := _m.Called(ctx, adminID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Impersonate")
+	}
+
+	var r0 *domain.Impersonation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) (*domain.Impersonation, error)); ok {
+		return rf(ctx, adminID, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) *domain.Impersonation); ok {
+		r0 = rf(ctx, adminID, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Impersonation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, adminID, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ImpersonatorMock_Impersonate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Impersonate'
+type ImpersonatorMock_Impersonate_Call struct {
+	*mock.Call
+}
+
+// Impersonate is a helper method to define mock.On call
+//   - ctx context.Context
+//   - adminID int64
+//   - userID int64
+func (_e *ImpersonatorMock_Expecter) Impersonate(ctx interface{}, adminID interface{}, userID interface{}) *ImpersonatorMock_Impersonate_Call {
+	return &ImpersonatorMock_Impersonate_Call{Call: _e.mock.On("Impersonate", ctx, adminID, userID)}
+}
+
+func (_c *ImpersonatorMock_Impersonate_Call) Run(run func(ctx context.Context, adminID int64, userID int64)) *ImpersonatorMock_Impersonate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(int64))
+	})
+	return _c
+}
+
+func (_c *ImpersonatorMock_Impersonate_Call) Return(_a0 *domain.Impersonation, _a1 error) *ImpersonatorMock_Impersonate_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ImpersonatorMock_Impersonate_Call) RunAndReturn(run func(context.Context, int64, int64) (*domain.Impersonation, error)) *ImpersonatorMock_Impersonate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewImpersonatorMock creates a new instance of ImpersonatorMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewImpersonatorMock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ImpersonatorMock {
+	mock := &ImpersonatorMock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,96 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/avc/loyalty-system-diploma/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// LedgerIntegrityServiceMock is an autogenerated mock type for the LedgerIntegrityService type
+type LedgerIntegrityServiceMock struct {
+	mock.Mock
+}
+
+type LedgerIntegrityServiceMock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *LedgerIntegrityServiceMock) EXPECT() *LedgerIntegrityServiceMock_Expecter {
+	return &LedgerIntegrityServiceMock_Expecter{mock: &_m.Mock}
+}
+
+// VerifyLedger provides a mock function with given fields: ctx
+func (_m *LedgerIntegrityServiceMock) VerifyLedger(ctx context.Context) (*domain.LedgerVerification, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyLedger")
+	}
+
+	var r0 *domain.LedgerVerification
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*domain.LedgerVerification, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *domain.LedgerVerification); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.LedgerVerification)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LedgerIntegrityServiceMock_VerifyLedger_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'VerifyLedger'
+type LedgerIntegrityServiceMock_VerifyLedger_Call struct {
+	*mock.Call
+}
+
+// VerifyLedger is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *LedgerIntegrityServiceMock_Expecter) VerifyLedger(ctx interface{}) *LedgerIntegrityServiceMock_VerifyLedger_Call {
+	return &LedgerIntegrityServiceMock_VerifyLedger_Call{Call: _e.mock.On("VerifyLedger", ctx)}
+}
+
+func (_c *LedgerIntegrityServiceMock_VerifyLedger_Call) Run(run func(ctx context.Context)) *LedgerIntegrityServiceMock_VerifyLedger_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *LedgerIntegrityServiceMock_VerifyLedger_Call) Return(_a0 *domain.LedgerVerification, _a1 error) *LedgerIntegrityServiceMock_VerifyLedger_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *LedgerIntegrityServiceMock_VerifyLedger_Call) RunAndReturn(run func(context.Context) (*domain.LedgerVerification, error)) *LedgerIntegrityServiceMock_VerifyLedger_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewLedgerIntegrityServiceMock creates a new instance of LedgerIntegrityServiceMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLedgerIntegrityServiceMock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *LedgerIntegrityServiceMock {
+	mock := &LedgerIntegrityServiceMock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,274 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/avc/loyalty-system-diploma/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// LedgerStoreMock is an autogenerated mock type for the LedgerStore type
+type LedgerStoreMock struct {
+	mock.Mock
+}
+
+type LedgerStoreMock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *LedgerStoreMock) EXPECT() *LedgerStoreMock_Expecter {
+	return &LedgerStoreMock_Expecter{mock: &_m.Mock}
+}
+
+// GetBalance provides a mock function with given fields: ctx, userID
+func (_m *LedgerStoreMock) GetBalance(ctx context.Context, userID int64) (*domain.Balance, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBalance")
+	}
+
+	var r0 *domain.Balance
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*domain.Balance, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *domain.Balance); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Balance)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LedgerStoreMock_GetBalance_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetBalance'
+type LedgerStoreMock_GetBalance_Call struct {
+	*mock.Call
+}
+
+// GetBalance is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+func (_e *LedgerStoreMock_Expecter) GetBalance(ctx interface{}, userID interface{}) *LedgerStoreMock_GetBalance_Call {
+	return &LedgerStoreMock_GetBalance_Call{Call: _e.mock.On("GetBalance", ctx, userID)}
+}
+
+func (_c *LedgerStoreMock_GetBalance_Call) Run(run func(ctx context.Context, userID int64)) *LedgerStoreMock_GetBalance_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *LedgerStoreMock_GetBalance_Call) Return(_a0 *domain.Balance, _a1 error) *LedgerStoreMock_GetBalance_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *LedgerStoreMock_GetBalance_Call) RunAndReturn(run func(context.Context, int64) (*domain.Balance, error)) *LedgerStoreMock_GetBalance_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUserIDsByLogins provides a mock function with given fields: ctx, logins
+func (_m *LedgerStoreMock) GetUserIDsByLogins(ctx context.Context, logins []string) (map[string]int64, error) {
+	ret := _m.Called(ctx, logins)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserIDsByLogins")
+	}
+
+	var r0 map[string]int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) (map[string]int64, error)); ok {
+		return rf(ctx, logins)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string) map[string]int64); ok {
+		r0 = rf(ctx, logins)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]int64)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, logins)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LedgerStoreMock_GetUserIDsByLogins_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserIDsByLogins'
+type LedgerStoreMock_GetUserIDsByLogins_Call struct {
+	*mock.Call
+}
+
+// GetUserIDsByLogins is a helper method to define mock.On call
+//   - ctx context.Context
+//   - logins []string
+func (_e *LedgerStoreMock_Expecter) GetUserIDsByLogins(ctx interface{}, logins interface{}) *LedgerStoreMock_GetUserIDsByLogins_Call {
+	return &LedgerStoreMock_GetUserIDsByLogins_Call{Call: _e.mock.On("GetUserIDsByLogins", ctx, logins)}
+}
+
+func (_c *LedgerStoreMock_GetUserIDsByLogins_Call) Run(run func(ctx context.Context, logins []string)) *LedgerStoreMock_GetUserIDsByLogins_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]string))
+	})
+	return _c
+}
+
+func (_c *LedgerStoreMock_GetUserIDsByLogins_Call) Return(_a0 map[string]int64, _a1 error) *LedgerStoreMock_GetUserIDsByLogins_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *LedgerStoreMock_GetUserIDsByLogins_Call) RunAndReturn(run func(context.Context, []string) (map[string]int64, error)) *LedgerStoreMock_GetUserIDsByLogins_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ImportOrders provides a mock function with given fields: ctx, orders
+func (_m *LedgerStoreMock) ImportOrders(ctx context.Context, orders []*domain.Order) (*domain.BatchImport, error) {
+	ret := _m.Called(ctx, orders)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ImportOrders")
+	}
+
+	var r0 *domain.BatchImport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*domain.Order) (*domain.BatchImport, error)); ok {
+		return rf(ctx, orders)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []*domain.Order) *domain.BatchImport); ok {
+		r0 = rf(ctx, orders)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.BatchImport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []*domain.Order) error); ok {
+		r1 = rf(ctx, orders)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LedgerStoreMock_ImportOrders_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ImportOrders'
+type LedgerStoreMock_ImportOrders_Call struct {
+	*mock.Call
+}
+
+// ImportOrders is a helper method to define mock.On call
+//   - ctx context.Context
+//   - orders []*domain.Order
+func (_e *LedgerStoreMock_Expecter) ImportOrders(ctx interface{}, orders interface{}) *LedgerStoreMock_ImportOrders_Call {
+	return &LedgerStoreMock_ImportOrders_Call{Call: _e.mock.On("ImportOrders", ctx, orders)}
+}
+
+func (_c *LedgerStoreMock_ImportOrders_Call) Run(run func(ctx context.Context, orders []*domain.Order)) *LedgerStoreMock_ImportOrders_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]*domain.Order))
+	})
+	return _c
+}
+
+func (_c *LedgerStoreMock_ImportOrders_Call) Return(_a0 *domain.BatchImport, _a1 error) *LedgerStoreMock_ImportOrders_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *LedgerStoreMock_ImportOrders_Call) RunAndReturn(run func(context.Context, []*domain.Order) (*domain.BatchImport, error)) *LedgerStoreMock_ImportOrders_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ImportTransactions provides a mock function with given fields: ctx, transactions
+func (_m *LedgerStoreMock) ImportTransactions(ctx context.Context, transactions []*domain.Transaction) (*domain.BatchImport, error) {
+	ret := _m.Called(ctx, transactions)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ImportTransactions")
+	}
+
+	var r0 *domain.BatchImport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*domain.Transaction) (*domain.BatchImport, error)); ok {
+		return rf(ctx, transactions)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []*domain.Transaction) *domain.BatchImport); ok {
+		r0 = rf(ctx, transactions)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.BatchImport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []*domain.Transaction) error); ok {
+		r1 = rf(ctx, transactions)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LedgerStoreMock_ImportTransactions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ImportTransactions'
+type LedgerStoreMock_ImportTransactions_Call struct {
+	*mock.Call
+}
+
+// ImportTransactions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - transactions []*domain.Transaction
+func (_e *LedgerStoreMock_Expecter) ImportTransactions(ctx interface{}, transactions interface{}) *LedgerStoreMock_ImportTransactions_Call {
+	return &LedgerStoreMock_ImportTransactions_Call{Call: _e.mock.On("ImportTransactions", ctx, transactions)}
+}
+
+func (_c *LedgerStoreMock_ImportTransactions_Call) Run(run func(ctx context.Context, transactions []*domain.Transaction)) *LedgerStoreMock_ImportTransactions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]*domain.Transaction))
+	})
+	return _c
+}
+
+func (_c *LedgerStoreMock_ImportTransactions_Call) Return(_a0 *domain.BatchImport, _a1 error) *LedgerStoreMock_ImportTransactions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *LedgerStoreMock_ImportTransactions_Call) RunAndReturn(run func(context.Context, []*domain.Transaction) (*domain.BatchImport, error)) *LedgerStoreMock_ImportTransactions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewLedgerStoreMock creates a new instance of LedgerStoreMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLedgerStoreMock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *LedgerStoreMock {
+	mock := &LedgerStoreMock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,95 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	locale "github.com/avc/loyalty-system-diploma/internal/locale"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// LocalePreferencesMock is an autogenerated mock type for the LocalePreferences type
+type LocalePreferencesMock struct {
+	mock.Mock
+}
+
+type LocalePreferencesMock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *LocalePreferencesMock) EXPECT() *LocalePreferencesMock_Expecter {
+	return &LocalePreferencesMock_Expecter{mock: &_m.Mock}
+}
+
+// PreferredLocale provides a mock function with given fields: ctx, userID
+func (_m *LocalePreferencesMock) PreferredLocale(ctx context.Context, userID int64) (locale.Locale, bool) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PreferredLocale")
+	}
+
+	var r0 locale.Locale
+	var r1 bool
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (locale.Locale, bool)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) locale.Locale); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(locale.Locale)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) bool); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	return r0, r1
+}
+
+// LocalePreferencesMock_PreferredLocale_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PreferredLocale'
+type LocalePreferencesMock_PreferredLocale_Call struct {
+	*mock.Call
+}
+
+// PreferredLocale is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+func (_e *LocalePreferencesMock_Expecter) PreferredLocale(ctx interface{}, userID interface{}) *LocalePreferencesMock_PreferredLocale_Call {
+	return &LocalePreferencesMock_PreferredLocale_Call{Call: _e.mock.On("PreferredLocale", ctx, userID)}
+}
+
+func (_c *LocalePreferencesMock_PreferredLocale_Call) Run(run func(ctx context.Context, userID int64)) *LocalePreferencesMock_PreferredLocale_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *LocalePreferencesMock_PreferredLocale_Call) Return(_a0 locale.Locale, _a1 bool) *LocalePreferencesMock_PreferredLocale_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *LocalePreferencesMock_PreferredLocale_Call) RunAndReturn(run func(context.Context, int64) (locale.Locale, bool)) *LocalePreferencesMock_PreferredLocale_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewLocalePreferencesMock creates a new instance of LocalePreferencesMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLocalePreferencesMock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *LocalePreferencesMock {
+	mock := &LocalePreferencesMock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,95 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// LockerMock is an autogenerated mock type for the Locker type
+type LockerMock struct {
+	mock.Mock
+}
+
+type LockerMock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *LockerMock) EXPECT() *LockerMock_Expecter {
+	return &LockerMock_Expecter{mock: &_m.Mock}
+}
+
+// Lock provides a mock function with given fields: ctx, key
+func (_m *LockerMock) Lock(ctx context.Context, key string) (func(), error) {
+	ret := _m.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Lock")
+	}
+
+	var r0 func()
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (func(), error)); ok {
+		return rf(ctx, key)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) func()); ok {
+		r0 = rf(ctx, key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(func())
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LockerMock_Lock_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Lock'
+type LockerMock_Lock_Call struct {
+	*mock.Call
+}
+
+// Lock is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+func (_e *LockerMock_Expecter) Lock(ctx interface{}, key interface{}) *LockerMock_Lock_Call {
+	return &LockerMock_Lock_Call{Call: _e.mock.On("Lock", ctx, key)}
+}
+
+func (_c *LockerMock_Lock_Call) Run(run func(ctx context.Context, key string)) *LockerMock_Lock_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *LockerMock_Lock_Call) Return(unlock func(), err error) *LockerMock_Lock_Call {
+	_c.Call.Return(unlock, err)
+	return _c
+}
+
+func (_c *LockerMock_Lock_Call) RunAndReturn(run func(context.Context, string) (func(), error)) *LockerMock_Lock_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewLockerMock creates a new instance of LockerMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLockerMock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *LockerMock {
+	mock := &LockerMock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
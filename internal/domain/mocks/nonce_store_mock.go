@@ -0,0 +1,96 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// NonceStoreMock is an autogenerated mock type for the NonceStore type
+type NonceStoreMock struct {
+	mock.Mock
+}
+
+type NonceStoreMock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *NonceStoreMock) EXPECT() *NonceStoreMock_Expecter {
+	return &NonceStoreMock_Expecter{mock: &_m.Mock}
+}
+
+// Remember provides a mock function with given fields: ctx, nonce, ttl
+func (_m *NonceStoreMock) Remember(ctx context.Context, nonce string, ttl time.Duration) (bool, error) {
+	ret := _m.Called(ctx, nonce, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Remember")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration) (bool, error)); ok {
+		return rf(ctx, nonce, ttl)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration) bool); ok {
+		r0 = rf(ctx, nonce, ttl)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Duration) error); ok {
+		r1 = rf(ctx, nonce, ttl)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NonceStoreMock_Remember_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Remember'
+type NonceStoreMock_Remember_Call struct {
+	*mock.Call
+}
+
+// Remember is a helper method to define mock.On call
+//   - ctx context.Context
+//   - nonce string
+//   - ttl time.Duration
+func (_e *NonceStoreMock_Expecter) Remember(ctx interface{}, nonce interface{}, ttl interface{}) *NonceStoreMock_Remember_Call {
+	return &NonceStoreMock_Remember_Call{Call: _e.mock.On("Remember", ctx, nonce, ttl)}
+}
+
+func (_c *NonceStoreMock_Remember_Call) Run(run func(ctx context.Context, nonce string, ttl time.Duration)) *NonceStoreMock_Remember_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *NonceStoreMock_Remember_Call) Return(_a0 bool, _a1 error) *NonceStoreMock_Remember_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *NonceStoreMock_Remember_Call) RunAndReturn(run func(context.Context, string, time.Duration) (bool, error)) *NonceStoreMock_Remember_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewNonceStoreMock creates a new instance of NonceStoreMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewNonceStoreMock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *NonceStoreMock {
+	mock := &NonceStoreMock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,187 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/avc/loyalty-system-diploma/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// OAuthProviderMock is an autogenerated mock type for the OAuthProvider type
+type OAuthProviderMock struct {
+	mock.Mock
+}
+
+type OAuthProviderMock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *OAuthProviderMock) EXPECT() *OAuthProviderMock_Expecter {
+	return &OAuthProviderMock_Expecter{mock: &_m.Mock}
+}
+
+// AuthCodeURL provides a mock function with given fields: state
+func (_m *OAuthProviderMock) AuthCodeURL(state string) string {
+	ret := _m.Called(state)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AuthCodeURL")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(state)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// OAuthProviderMock_AuthCodeURL_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AuthCodeURL'
+type OAuthProviderMock_AuthCodeURL_Call struct {
+	*mock.Call
+}
+
+// AuthCodeURL is a helper method to define mock.On call
+//   - state string
+func (_e *OAuthProviderMock_Expecter) AuthCodeURL(state interface{}) *OAuthProviderMock_AuthCodeURL_Call {
+	return &OAuthProviderMock_AuthCodeURL_Call{Call: _e.mock.On("AuthCodeURL", state)}
+}
+
+func (_c *OAuthProviderMock_AuthCodeURL_Call) Run(run func(state string)) *OAuthProviderMock_AuthCodeURL_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *OAuthProviderMock_AuthCodeURL_Call) Return(_a0 string) *OAuthProviderMock_AuthCodeURL_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *OAuthProviderMock_AuthCodeURL_Call) RunAndReturn(run func(string) string) *OAuthProviderMock_AuthCodeURL_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Exchange provides a mock function with given fields: ctx, code
+func (_m *OAuthProviderMock) Exchange(ctx context.Context, code string) (*domain.ExternalIdentity, error) {
+	ret := _m.Called(ctx, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Exchange")
+	}
+
+	var r0 *domain.ExternalIdentity
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.ExternalIdentity, error)); ok {
+		return rf(ctx, code)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.ExternalIdentity); ok {
+		r0 = rf(ctx, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ExternalIdentity)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OAuthProviderMock_Exchange_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Exchange'
+type OAuthProviderMock_Exchange_Call struct {
+	*mock.Call
+}
+
+// Exchange is a helper method to define mock.On call
+//   - ctx context.Context
+//   - code string
+func (_e *OAuthProviderMock_Expecter) Exchange(ctx interface{}, code interface{}) *OAuthProviderMock_Exchange_Call {
+	return &OAuthProviderMock_Exchange_Call{Call: _e.mock.On("Exchange", ctx, code)}
+}
+
+func (_c *OAuthProviderMock_Exchange_Call) Run(run func(ctx context.Context, code string)) *OAuthProviderMock_Exchange_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *OAuthProviderMock_Exchange_Call) Return(_a0 *domain.ExternalIdentity, _a1 error) *OAuthProviderMock_Exchange_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *OAuthProviderMock_Exchange_Call) RunAndReturn(run func(context.Context, string) (*domain.ExternalIdentity, error)) *OAuthProviderMock_Exchange_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Name provides a mock function with no fields
+func (_m *OAuthProviderMock) Name() string {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Name")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// OAuthProviderMock_Name_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Name'
+type OAuthProviderMock_Name_Call struct {
+	*mock.Call
+}
+
+// Name is a helper method to define mock.On call
+func (_e *OAuthProviderMock_Expecter) Name() *OAuthProviderMock_Name_Call {
+	return &OAuthProviderMock_Name_Call{Call: _e.mock.On("Name")}
+}
+
+func (_c *OAuthProviderMock_Name_Call) Run(run func()) *OAuthProviderMock_Name_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *OAuthProviderMock_Name_Call) Return(_a0 string) *OAuthProviderMock_Name_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *OAuthProviderMock_Name_Call) RunAndReturn(run func() string) *OAuthProviderMock_Name_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewOAuthProviderMock creates a new instance of OAuthProviderMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewOAuthProviderMock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *OAuthProviderMock {
+	mock := &OAuthProviderMock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
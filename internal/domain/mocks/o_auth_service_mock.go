@@ -0,0 +1,158 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/avc/loyalty-system-diploma/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// OAuthServiceMock is an autogenerated mock type for the OAuthService type
+type OAuthServiceMock struct {
+	mock.Mock
+}
+
+type OAuthServiceMock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *OAuthServiceMock) EXPECT() *OAuthServiceMock_Expecter {
+	return &OAuthServiceMock_Expecter{mock: &_m.Mock}
+}
+
+// BeginLogin provides a mock function with given fields: ctx, provider, linkUserID
+func (_m *OAuthServiceMock) BeginLogin(ctx context.Context, provider string, linkUserID int64) (*domain.OAuthLogin, error) {
+	ret := _m.Called(ctx, provider, linkUserID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BeginLogin")
+	}
+
+	var r0 *domain.OAuthLogin
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) (*domain.OAuthLogin, error)); ok {
+		return rf(ctx, provider, linkUserID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) *domain.OAuthLogin); ok {
+		r0 = rf(ctx, provider, linkUserID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.OAuthLogin)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64) error); ok {
+		r1 = rf(ctx, provider, linkUserID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OAuthServiceMock_BeginLogin_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BeginLogin'
+type OAuthServiceMock_BeginLogin_Call struct {
+	*mock.Call
+}
+
+// BeginLogin is a helper method to define mock.On call
+//   - ctx context.Context
+//   - provider string
+//   - linkUserID int64
+func (_e *OAuthServiceMock_Expecter) BeginLogin(ctx interface{}, provider interface{}, linkUserID interface{}) *OAuthServiceMock_BeginLogin_Call {
+	return &OAuthServiceMock_BeginLogin_Call{Call: _e.mock.On("BeginLogin", ctx, provider, linkUserID)}
+}
+
+func (_c *OAuthServiceMock_BeginLogin_Call) Run(run func(ctx context.Context, provider string, linkUserID int64)) *OAuthServiceMock_BeginLogin_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int64))
+	})
+	return _c
+}
+
+func (_c *OAuthServiceMock_BeginLogin_Call) Return(_a0 *domain.OAuthLogin, _a1 error) *OAuthServiceMock_BeginLogin_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *OAuthServiceMock_BeginLogin_Call) RunAndReturn(run func(context.Context, string, int64) (*domain.OAuthLogin, error)) *OAuthServiceMock_BeginLogin_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CompleteLogin provides a mock function with given fields: ctx, provider, state, nonce, code
+func (_m *OAuthServiceMock) CompleteLogin(ctx context.Context, provider string, state string, nonce string, code string) (string, error) {
+	ret := _m.Called(ctx, provider, state, nonce, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompleteLogin")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) (string, error)); ok {
+		return rf(ctx, provider, state, nonce, code)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) string); ok {
+		r0 = rf(ctx, provider, state, nonce, code)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, string) error); ok {
+		r1 = rf(ctx, provider, state, nonce, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OAuthServiceMock_CompleteLogin_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CompleteLogin'
+type OAuthServiceMock_CompleteLogin_Call struct {
+	*mock.Call
+}
+
+// CompleteLogin is a helper method to define mock.On call
+//   - ctx context.Context
+//   - provider string
+//   - state string
+//   - nonce string
+//   - code string
+func (_e *OAuthServiceMock_Expecter) CompleteLogin(ctx interface{}, provider interface{}, state interface{}, nonce interface{}, code interface{}) *OAuthServiceMock_CompleteLogin_Call {
+	return &OAuthServiceMock_CompleteLogin_Call{Call: _e.mock.On("CompleteLogin", ctx, provider, state, nonce, code)}
+}
+
+func (_c *OAuthServiceMock_CompleteLogin_Call) Run(run func(ctx context.Context, provider string, state string, nonce string, code string)) *OAuthServiceMock_CompleteLogin_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].(string))
+	})
+	return _c
+}
+
+func (_c *OAuthServiceMock_CompleteLogin_Call) Return(_a0 string, _a1 error) *OAuthServiceMock_CompleteLogin_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *OAuthServiceMock_CompleteLogin_Call) RunAndReturn(run func(context.Context, string, string, string, string) (string, error)) *OAuthServiceMock_CompleteLogin_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewOAuthServiceMock creates a new instance of OAuthServiceMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewOAuthServiceMock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *OAuthServiceMock {
+	mock := &OAuthServiceMock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,222 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/avc/loyalty-system-diploma/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// OrderDisputeServiceMock is an autogenerated mock type for the OrderDisputeService type
+type OrderDisputeServiceMock struct {
+	mock.Mock
+}
+
+type OrderDisputeServiceMock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *OrderDisputeServiceMock) EXPECT() *OrderDisputeServiceMock_Expecter {
+	return &OrderDisputeServiceMock_Expecter{mock: &_m.Mock}
+}
+
+// ListDisputes provides a mock function with given fields: ctx, status, limit, offset
+func (_m *OrderDisputeServiceMock) ListDisputes(ctx context.Context, status domain.DisputeStatus, limit int, offset int) (*domain.OrderDisputePage, error) {
+	ret := _m.Called(ctx, status, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListDisputes")
+	}
+
+	var r0 *domain.OrderDisputePage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.DisputeStatus, int, int) (*domain.OrderDisputePage, error)); ok {
+		return rf(ctx, status, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.DisputeStatus, int, int) *domain.OrderDisputePage); ok {
+		r0 = rf(ctx, status, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.OrderDisputePage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.DisputeStatus, int, int) error); ok {
+		r1 = rf(ctx, status, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OrderDisputeServiceMock_ListDisputes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListDisputes'
+type OrderDisputeServiceMock_ListDisputes_Call struct {
+	*mock.Call
+}
+
+// ListDisputes is a helper method to define mock.On call
+//   - ctx context.Context
+//   - status domain.DisputeStatus
+//   - limit int
+//   - offset int
+func (_e *OrderDisputeServiceMock_Expecter) ListDisputes(ctx interface{}, status interface{}, limit interface{}, offset interface{}) *OrderDisputeServiceMock_ListDisputes_Call {
+	return &OrderDisputeServiceMock_ListDisputes_Call{Call: _e.mock.On("ListDisputes", ctx, status, limit, offset)}
+}
+
+func (_c *OrderDisputeServiceMock_ListDisputes_Call) Run(run func(ctx context.Context, status domain.DisputeStatus, limit int, offset int)) *OrderDisputeServiceMock_ListDisputes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(domain.DisputeStatus), args[2].(int), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *OrderDisputeServiceMock_ListDisputes_Call) Return(_a0 *domain.OrderDisputePage, _a1 error) *OrderDisputeServiceMock_ListDisputes_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *OrderDisputeServiceMock_ListDisputes_Call) RunAndReturn(run func(context.Context, domain.DisputeStatus, int, int) (*domain.OrderDisputePage, error)) *OrderDisputeServiceMock_ListDisputes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// OpenDispute provides a mock function with given fields: ctx, userID, number, comment
+func (_m *OrderDisputeServiceMock) OpenDispute(ctx context.Context, userID int64, number string, comment string) (*domain.OrderDispute, error) {
+	ret := _m.Called(ctx, userID, number, comment)
+
+	if len(ret) == 0 {
+		panic("no return value specified for OpenDispute")
+	}
+
+	var r0 *domain.OrderDispute
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string) (*domain.OrderDispute, error)); ok {
+		return rf(ctx, userID, number, comment)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string) *domain.OrderDispute); ok {
+		r0 = rf(ctx, userID, number, comment)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.OrderDispute)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string, string) error); ok {
+		r1 = rf(ctx, userID, number, comment)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OrderDisputeServiceMock_OpenDispute_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'OpenDispute'
+type OrderDisputeServiceMock_OpenDispute_Call struct {
+	*mock.Call
+}
+
+// OpenDispute is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - number string
+//   - comment string
+func (_e *OrderDisputeServiceMock_Expecter) OpenDispute(ctx interface{}, userID interface{}, number interface{}, comment interface{}) *OrderDisputeServiceMock_OpenDispute_Call {
+	return &OrderDisputeServiceMock_OpenDispute_Call{Call: _e.mock.On("OpenDispute", ctx, userID, number, comment)}
+}
+
+func (_c *OrderDisputeServiceMock_OpenDispute_Call) Run(run func(ctx context.Context, userID int64, number string, comment string)) *OrderDisputeServiceMock_OpenDispute_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(string), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *OrderDisputeServiceMock_OpenDispute_Call) Return(_a0 *domain.OrderDispute, _a1 error) *OrderDisputeServiceMock_OpenDispute_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *OrderDisputeServiceMock_OpenDispute_Call) RunAndReturn(run func(context.Context, int64, string, string) (*domain.OrderDispute, error)) *OrderDisputeServiceMock_OpenDispute_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ResolveDispute provides a mock function with given fields: ctx, id, resolution, comment, adminID
+func (_m *OrderDisputeServiceMock) ResolveDispute(ctx context.Context, id int64, resolution domain.DisputeResolution, comment string, adminID int64) (*domain.OrderDispute, error) {
+	ret := _m.Called(ctx, id, resolution, comment, adminID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResolveDispute")
+	}
+
+	var r0 *domain.OrderDispute
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, domain.DisputeResolution, string, int64) (*domain.OrderDispute, error)); ok {
+		return rf(ctx, id, resolution, comment, adminID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, domain.DisputeResolution, string, int64) *domain.OrderDispute); ok {
+		r0 = rf(ctx, id, resolution, comment, adminID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.OrderDispute)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, domain.DisputeResolution, string, int64) error); ok {
+		r1 = rf(ctx, id, resolution, comment, adminID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OrderDisputeServiceMock_ResolveDispute_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResolveDispute'
+type OrderDisputeServiceMock_ResolveDispute_Call struct {
+	*mock.Call
+}
+
+// ResolveDispute is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+//   - resolution domain.DisputeResolution
+//   - comment string
+//   - adminID int64
+func (_e *OrderDisputeServiceMock_Expecter) ResolveDispute(ctx interface{}, id interface{}, resolution interface{}, comment interface{}, adminID interface{}) *OrderDisputeServiceMock_ResolveDispute_Call {
+	return &OrderDisputeServiceMock_ResolveDispute_Call{Call: _e.mock.On("ResolveDispute", ctx, id, resolution, comment, adminID)}
+}
+
+func (_c *OrderDisputeServiceMock_ResolveDispute_Call) Run(run func(ctx context.Context, id int64, resolution domain.DisputeResolution, comment string, adminID int64)) *OrderDisputeServiceMock_ResolveDispute_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(domain.DisputeResolution), args[3].(string), args[4].(int64))
+	})
+	return _c
+}
+
+func (_c *OrderDisputeServiceMock_ResolveDispute_Call) Return(_a0 *domain.OrderDispute, _a1 error) *OrderDisputeServiceMock_ResolveDispute_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *OrderDisputeServiceMock_ResolveDispute_Call) RunAndReturn(run func(context.Context, int64, domain.DisputeResolution, string, int64) (*domain.OrderDispute, error)) *OrderDisputeServiceMock_ResolveDispute_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewOrderDisputeServiceMock creates a new instance of OrderDisputeServiceMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewOrderDisputeServiceMock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *OrderDisputeServiceMock {
+	mock := &OrderDisputeServiceMock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,157 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/avc/loyalty-system-diploma/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// OrderExpiryRepositoryMock is an autogenerated mock type for the OrderExpiryRepository type
+type OrderExpiryRepositoryMock struct {
+	mock.Mock
+}
+
+type OrderExpiryRepositoryMock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *OrderExpiryRepositoryMock) EXPECT() *OrderExpiryRepositoryMock_Expecter {
+	return &OrderExpiryRepositoryMock_Expecter{mock: &_m.Mock}
+}
+
+// ExpireStuckOrders provides a mock function with given fields: ctx, days, limit
+func (_m *OrderExpiryRepositoryMock) ExpireStuckOrders(ctx context.Context, days int, limit int) ([]string, error) {
+	ret := _m.Called(ctx, days, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExpireStuckOrders")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]string, error)); ok {
+		return rf(ctx, days, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []string); ok {
+		r0 = rf(ctx, days, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, days, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OrderExpiryRepositoryMock_ExpireStuckOrders_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ExpireStuckOrders'
+type OrderExpiryRepositoryMock_ExpireStuckOrders_Call struct {
+	*mock.Call
+}
+
+// ExpireStuckOrders is a helper method to define mock.On call
+//   - ctx context.Context
+//   - days int
+//   - limit int
+func (_e *OrderExpiryRepositoryMock_Expecter) ExpireStuckOrders(ctx interface{}, days interface{}, limit interface{}) *OrderExpiryRepositoryMock_ExpireStuckOrders_Call {
+	return &OrderExpiryRepositoryMock_ExpireStuckOrders_Call{Call: _e.mock.On("ExpireStuckOrders", ctx, days, limit)}
+}
+
+func (_c *OrderExpiryRepositoryMock_ExpireStuckOrders_Call) Run(run func(ctx context.Context, days int, limit int)) *OrderExpiryRepositoryMock_ExpireStuckOrders_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *OrderExpiryRepositoryMock_ExpireStuckOrders_Call) Return(_a0 []string, _a1 error) *OrderExpiryRepositoryMock_ExpireStuckOrders_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *OrderExpiryRepositoryMock_ExpireStuckOrders_Call) RunAndReturn(run func(context.Context, int, int) ([]string, error)) *OrderExpiryRepositoryMock_ExpireStuckOrders_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindStuckOrders provides a mock function with given fields: ctx, days, limit
+func (_m *OrderExpiryRepositoryMock) FindStuckOrders(ctx context.Context, days int, limit int) ([]*domain.Order, error) {
+	ret := _m.Called(ctx, days, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindStuckOrders")
+	}
+
+	var r0 []*domain.Order
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]*domain.Order, error)); ok {
+		return rf(ctx, days, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []*domain.Order); ok {
+		r0 = rf(ctx, days, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Order)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, days, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OrderExpiryRepositoryMock_FindStuckOrders_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindStuckOrders'
+type OrderExpiryRepositoryMock_FindStuckOrders_Call struct {
+	*mock.Call
+}
+
+// FindStuckOrders is a helper method to define mock.On call
+//   - ctx context.Context
+//   - days int
+//   - limit int
+func (_e *OrderExpiryRepositoryMock_Expecter) FindStuckOrders(ctx interface{}, days interface{}, limit interface{}) *OrderExpiryRepositoryMock_FindStuckOrders_Call {
+	return &OrderExpiryRepositoryMock_FindStuckOrders_Call{Call: _e.mock.On("FindStuckOrders", ctx, days, limit)}
+}
+
+func (_c *OrderExpiryRepositoryMock_FindStuckOrders_Call) Run(run func(ctx context.Context, days int, limit int)) *OrderExpiryRepositoryMock_FindStuckOrders_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *OrderExpiryRepositoryMock_FindStuckOrders_Call) Return(_a0 []*domain.Order, _a1 error) *OrderExpiryRepositoryMock_FindStuckOrders_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *OrderExpiryRepositoryMock_FindStuckOrders_Call) RunAndReturn(run func(context.Context, int, int) ([]*domain.Order, error)) *OrderExpiryRepositoryMock_FindStuckOrders_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewOrderExpiryRepositoryMock creates a new instance of OrderExpiryRepositoryMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewOrderExpiryRepositoryMock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *OrderExpiryRepositoryMock {
+	mock := &OrderExpiryRepositoryMock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
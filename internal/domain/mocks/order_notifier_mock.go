@@ -0,0 +1,64 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+
+// OrderNotifierMock is an autogenerated mock type for the OrderNotifier type
+type OrderNotifierMock struct {
+	mock.Mock
+}
+
+type OrderNotifierMock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *OrderNotifierMock) EXPECT() *OrderNotifierMock_Expecter {
+	return &OrderNotifierMock_Expecter{mock: &_m.Mock}
+}
+
+// NotifyNewOrder provides a mock function with no fields
+func (_m *OrderNotifierMock) NotifyNewOrder() {
+	_m.Called()
+}
+
+// OrderNotifierMock_NotifyNewOrder_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'NotifyNewOrder'
+type OrderNotifierMock_NotifyNewOrder_Call struct {
+	*mock.Call
+}
+
+// NotifyNewOrder is a helper method to define mock.On call
+func (_e *OrderNotifierMock_Expecter) NotifyNewOrder() *OrderNotifierMock_NotifyNewOrder_Call {
+	return &OrderNotifierMock_NotifyNewOrder_Call{Call: _e.mock.On("NotifyNewOrder")}
+}
+
+func (_c *OrderNotifierMock_NotifyNewOrder_Call) Run(run func()) *OrderNotifierMock_NotifyNewOrder_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *OrderNotifierMock_NotifyNewOrder_Call) Return() *OrderNotifierMock_NotifyNewOrder_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *OrderNotifierMock_NotifyNewOrder_Call) RunAndReturn(run func()) *OrderNotifierMock_NotifyNewOrder_Call {
+	_c.Run(run)
+	return _c
+}
+
+// NewOrderNotifierMock creates a new instance of OrderNotifierMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewOrderNotifierMock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *OrderNotifierMock {
+	mock := &OrderNotifierMock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -7,6 +7,8 @@ import (
 
 	domain "github.com/avc/loyalty-system-diploma/internal/domain"
 	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
 )
 
 // OrderRepositoryMock is an autogenerated mock type for the OrderRepository type
@@ -22,9 +24,119 @@ func (_m *OrderRepositoryMock) EXPECT() *OrderRepositoryMock_Expecter {
 	return &OrderRepositoryMock_Expecter{mock: &_m.Mock}
 }
 
-// CreateOrder provides a mock function with given fields: ctx, userID, number
-func (_m *OrderRepositoryMock) CreateOrder(ctx context.Context, userID int64, number string) (*domain.Order, error) {
-	ret := _m.Called(ctx, userID, number)
+// ApplyAccrual provides a mock function with given fields: ctx, number, status, accrual
+func (_m *OrderRepositoryMock) ApplyAccrual(ctx context.Context, number string, status domain.OrderStatus, accrual *domain.Accrual) error {
+	ret := _m.Called(ctx, number, status, accrual)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ApplyAccrual")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.OrderStatus, *domain.Accrual) error); ok {
+		r0 = rf(ctx, number, status, accrual)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// OrderRepositoryMock_ApplyAccrual_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ApplyAccrual'
+type OrderRepositoryMock_ApplyAccrual_Call struct {
+	*mock.Call
+}
+
+// ApplyAccrual is a helper method to define mock.On call
+//   - ctx context.Context
+//   - number string
+//   - status domain.OrderStatus
+//   - accrual *domain.Accrual
+func (_e *OrderRepositoryMock_Expecter) ApplyAccrual(ctx interface{}, number interface{}, status interface{}, accrual interface{}) *OrderRepositoryMock_ApplyAccrual_Call {
+	return &OrderRepositoryMock_ApplyAccrual_Call{Call: _e.mock.On("ApplyAccrual", ctx, number, status, accrual)}
+}
+
+func (_c *OrderRepositoryMock_ApplyAccrual_Call) Run(run func(ctx context.Context, number string, status domain.OrderStatus, accrual *domain.Accrual)) *OrderRepositoryMock_ApplyAccrual_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(domain.OrderStatus), args[3].(*domain.Accrual))
+	})
+	return _c
+}
+
+func (_c *OrderRepositoryMock_ApplyAccrual_Call) Return(_a0 error) *OrderRepositoryMock_ApplyAccrual_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *OrderRepositoryMock_ApplyAccrual_Call) RunAndReturn(run func(context.Context, string, domain.OrderStatus, *domain.Accrual) error) *OrderRepositoryMock_ApplyAccrual_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CorrectAccrual provides a mock function with given fields: ctx, number, accrual, correctedBy
+func (_m *OrderRepositoryMock) CorrectAccrual(ctx context.Context, number string, accrual *domain.Accrual, correctedBy int64) (*domain.AccrualCorrection, error) {
+	ret := _m.Called(ctx, number, accrual, correctedBy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CorrectAccrual")
+	}
+
+	var r0 *domain.AccrualCorrection
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.Accrual, int64) (*domain.AccrualCorrection, error)); ok {
+		return rf(ctx, number, accrual, correctedBy)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.Accrual, int64) *domain.AccrualCorrection); ok {
+		r0 = rf(ctx, number, accrual, correctedBy)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.AccrualCorrection)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *domain.Accrual, int64) error); ok {
+		r1 = rf(ctx, number, accrual, correctedBy)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OrderRepositoryMock_CorrectAccrual_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CorrectAccrual'
+type OrderRepositoryMock_CorrectAccrual_Call struct {
+	*mock.Call
+}
+
+// CorrectAccrual is a helper method to define mock.On call
+//   - ctx context.Context
+//   - number string
+//   - accrual *domain.Accrual
+//   - correctedBy int64
+func (_e *OrderRepositoryMock_Expecter) CorrectAccrual(ctx interface{}, number interface{}, accrual interface{}, correctedBy interface{}) *OrderRepositoryMock_CorrectAccrual_Call {
+	return &OrderRepositoryMock_CorrectAccrual_Call{Call: _e.mock.On("CorrectAccrual", ctx, number, accrual, correctedBy)}
+}
+
+func (_c *OrderRepositoryMock_CorrectAccrual_Call) Run(run func(ctx context.Context, number string, accrual *domain.Accrual, correctedBy int64)) *OrderRepositoryMock_CorrectAccrual_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(*domain.Accrual), args[3].(int64))
+	})
+	return _c
+}
+
+func (_c *OrderRepositoryMock_CorrectAccrual_Call) Return(_a0 *domain.AccrualCorrection, _a1 error) *OrderRepositoryMock_CorrectAccrual_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *OrderRepositoryMock_CorrectAccrual_Call) RunAndReturn(run func(context.Context, string, *domain.Accrual, int64) (*domain.AccrualCorrection, error)) *OrderRepositoryMock_CorrectAccrual_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateOrder provides a mock function with given fields: ctx, userID, number, metadata
+func (_m *OrderRepositoryMock) CreateOrder(ctx context.Context, userID int64, number string, metadata *domain.OrderMetadata) (*domain.Order, error) {
+	ret := _m.Called(ctx, userID, number, metadata)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateOrder")
@@ -32,19 +144,19 @@ func (_m *OrderRepositoryMock) CreateOrder(ctx context.Context, userID int64, nu
 
 	var r0 *domain.Order
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, int64, string) (*domain.Order, error)); ok {
-		return rf(ctx, userID, number)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, *domain.OrderMetadata) (*domain.Order, error)); ok {
+		return rf(ctx, userID, number, metadata)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int64, string) *domain.Order); ok {
-		r0 = rf(ctx, userID, number)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, *domain.OrderMetadata) *domain.Order); ok {
+		r0 = rf(ctx, userID, number, metadata)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*domain.Order)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, int64, string) error); ok {
-		r1 = rf(ctx, userID, number)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string, *domain.OrderMetadata) error); ok {
+		r1 = rf(ctx, userID, number, metadata)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -61,13 +173,14 @@ type OrderRepositoryMock_CreateOrder_Call struct {
 //   - ctx context.Context
 //   - userID int64
 //   - number string
-func (_e *OrderRepositoryMock_Expecter) CreateOrder(ctx interface{}, userID interface{}, number interface{}) *OrderRepositoryMock_CreateOrder_Call {
-	return &OrderRepositoryMock_CreateOrder_Call{Call: _e.mock.On("CreateOrder", ctx, userID, number)}
+//   - metadata *domain.OrderMetadata
+func (_e *OrderRepositoryMock_Expecter) CreateOrder(ctx interface{}, userID interface{}, number interface{}, metadata interface{}) *OrderRepositoryMock_CreateOrder_Call {
+	return &OrderRepositoryMock_CreateOrder_Call{Call: _e.mock.On("CreateOrder", ctx, userID, number, metadata)}
 }
 
-func (_c *OrderRepositoryMock_CreateOrder_Call) Run(run func(ctx context.Context, userID int64, number string)) *OrderRepositoryMock_CreateOrder_Call {
+func (_c *OrderRepositoryMock_CreateOrder_Call) Run(run func(ctx context.Context, userID int64, number string, metadata *domain.OrderMetadata)) *OrderRepositoryMock_CreateOrder_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int64), args[2].(string))
+		run(args[0].(context.Context), args[1].(int64), args[2].(string), args[3].(*domain.OrderMetadata))
 	})
 	return _c
 }
@@ -77,7 +190,189 @@ func (_c *OrderRepositoryMock_CreateOrder_Call) Return(_a0 *domain.Order, _a1 er
 	return _c
 }
 
-func (_c *OrderRepositoryMock_CreateOrder_Call) RunAndReturn(run func(context.Context, int64, string) (*domain.Order, error)) *OrderRepositoryMock_CreateOrder_Call {
+func (_c *OrderRepositoryMock_CreateOrder_Call) RunAndReturn(run func(context.Context, int64, string, *domain.OrderMetadata) (*domain.Order, error)) *OrderRepositoryMock_CreateOrder_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateOrderDispute provides a mock function with given fields: ctx, claimantID, number, comment
+func (_m *OrderRepositoryMock) CreateOrderDispute(ctx context.Context, claimantID int64, number string, comment string) (*domain.OrderDispute, error) {
+	ret := _m.Called(ctx, claimantID, number, comment)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateOrderDispute")
+	}
+
+	var r0 *domain.OrderDispute
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string) (*domain.OrderDispute, error)); ok {
+		return rf(ctx, claimantID, number, comment)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string) *domain.OrderDispute); ok {
+		r0 = rf(ctx, claimantID, number, comment)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.OrderDispute)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string, string) error); ok {
+		r1 = rf(ctx, claimantID, number, comment)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OrderRepositoryMock_CreateOrderDispute_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateOrderDispute'
+type OrderRepositoryMock_CreateOrderDispute_Call struct {
+	*mock.Call
+}
+
+// CreateOrderDispute is a helper method to define mock.On call
+//   - ctx context.Context
+//   - claimantID int64
+//   - number string
+//   - comment string
+func (_e *OrderRepositoryMock_Expecter) CreateOrderDispute(ctx interface{}, claimantID interface{}, number interface{}, comment interface{}) *OrderRepositoryMock_CreateOrderDispute_Call {
+	return &OrderRepositoryMock_CreateOrderDispute_Call{Call: _e.mock.On("CreateOrderDispute", ctx, claimantID, number, comment)}
+}
+
+func (_c *OrderRepositoryMock_CreateOrderDispute_Call) Run(run func(ctx context.Context, claimantID int64, number string, comment string)) *OrderRepositoryMock_CreateOrderDispute_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(string), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *OrderRepositoryMock_CreateOrderDispute_Call) Return(_a0 *domain.OrderDispute, _a1 error) *OrderRepositoryMock_CreateOrderDispute_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *OrderRepositoryMock_CreateOrderDispute_Call) RunAndReturn(run func(context.Context, int64, string, string) (*domain.OrderDispute, error)) *OrderRepositoryMock_CreateOrderDispute_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindAccrualMismatches provides a mock function with given fields: ctx, limit, offset
+func (_m *OrderRepositoryMock) FindAccrualMismatches(ctx context.Context, limit int, offset int) ([]*domain.AccrualMismatch, error) {
+	ret := _m.Called(ctx, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindAccrualMismatches")
+	}
+
+	var r0 []*domain.AccrualMismatch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]*domain.AccrualMismatch, error)); ok {
+		return rf(ctx, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []*domain.AccrualMismatch); ok {
+		r0 = rf(ctx, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.AccrualMismatch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OrderRepositoryMock_FindAccrualMismatches_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindAccrualMismatches'
+type OrderRepositoryMock_FindAccrualMismatches_Call struct {
+	*mock.Call
+}
+
+// FindAccrualMismatches is a helper method to define mock.On call
+//   - ctx context.Context
+//   - limit int
+//   - offset int
+func (_e *OrderRepositoryMock_Expecter) FindAccrualMismatches(ctx interface{}, limit interface{}, offset interface{}) *OrderRepositoryMock_FindAccrualMismatches_Call {
+	return &OrderRepositoryMock_FindAccrualMismatches_Call{Call: _e.mock.On("FindAccrualMismatches", ctx, limit, offset)}
+}
+
+func (_c *OrderRepositoryMock_FindAccrualMismatches_Call) Run(run func(ctx context.Context, limit int, offset int)) *OrderRepositoryMock_FindAccrualMismatches_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *OrderRepositoryMock_FindAccrualMismatches_Call) Return(_a0 []*domain.AccrualMismatch, _a1 error) *OrderRepositoryMock_FindAccrualMismatches_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *OrderRepositoryMock_FindAccrualMismatches_Call) RunAndReturn(run func(context.Context, int, int) ([]*domain.AccrualMismatch, error)) *OrderRepositoryMock_FindAccrualMismatches_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetDuplicateSubmissionReport provides a mock function with given fields: ctx, days, limit, offset
+func (_m *OrderRepositoryMock) GetDuplicateSubmissionReport(ctx context.Context, days int, limit int, offset int) (*domain.DuplicateSubmissionReport, error) {
+	ret := _m.Called(ctx, days, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDuplicateSubmissionReport")
+	}
+
+	var r0 *domain.DuplicateSubmissionReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) (*domain.DuplicateSubmissionReport, error)); ok {
+		return rf(ctx, days, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) *domain.DuplicateSubmissionReport); ok {
+		r0 = rf(ctx, days, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.DuplicateSubmissionReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, int) error); ok {
+		r1 = rf(ctx, days, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OrderRepositoryMock_GetDuplicateSubmissionReport_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetDuplicateSubmissionReport'
+type OrderRepositoryMock_GetDuplicateSubmissionReport_Call struct {
+	*mock.Call
+}
+
+// GetDuplicateSubmissionReport is a helper method to define mock.On call
+//   - ctx context.Context
+//   - days int
+//   - limit int
+//   - offset int
+func (_e *OrderRepositoryMock_Expecter) GetDuplicateSubmissionReport(ctx interface{}, days interface{}, limit interface{}, offset interface{}) *OrderRepositoryMock_GetDuplicateSubmissionReport_Call {
+	return &OrderRepositoryMock_GetDuplicateSubmissionReport_Call{Call: _e.mock.On("GetDuplicateSubmissionReport", ctx, days, limit, offset)}
+}
+
+func (_c *OrderRepositoryMock_GetDuplicateSubmissionReport_Call) Run(run func(ctx context.Context, days int, limit int, offset int)) *OrderRepositoryMock_GetDuplicateSubmissionReport_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *OrderRepositoryMock_GetDuplicateSubmissionReport_Call) Return(_a0 *domain.DuplicateSubmissionReport, _a1 error) *OrderRepositoryMock_GetDuplicateSubmissionReport_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *OrderRepositoryMock_GetDuplicateSubmissionReport_Call) RunAndReturn(run func(context.Context, int, int, int) (*domain.DuplicateSubmissionReport, error)) *OrderRepositoryMock_GetDuplicateSubmissionReport_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -141,9 +436,128 @@ func (_c *OrderRepositoryMock_GetOrderByNumber_Call) RunAndReturn(run func(conte
 	return _c
 }
 
-// GetOrdersByUserID provides a mock function with given fields: ctx, userID
-func (_m *OrderRepositoryMock) GetOrdersByUserID(ctx context.Context, userID int64) ([]*domain.Order, error) {
-	ret := _m.Called(ctx, userID)
+// GetOrderByPublicID provides a mock function with given fields: ctx, userID, publicID
+func (_m *OrderRepositoryMock) GetOrderByPublicID(ctx context.Context, userID int64, publicID uuid.UUID) (*domain.Order, error) {
+	ret := _m.Called(ctx, userID, publicID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrderByPublicID")
+	}
+
+	var r0 *domain.Order
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, uuid.UUID) (*domain.Order, error)); ok {
+		return rf(ctx, userID, publicID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, uuid.UUID) *domain.Order); ok {
+		r0 = rf(ctx, userID, publicID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Order)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID, publicID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OrderRepositoryMock_GetOrderByPublicID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOrderByPublicID'
+type OrderRepositoryMock_GetOrderByPublicID_Call struct {
+	*mock.Call
+}
+
+// GetOrderByPublicID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - publicID uuid.UUID
+func (_e *OrderRepositoryMock_Expecter) GetOrderByPublicID(ctx interface{}, userID interface{}, publicID interface{}) *OrderRepositoryMock_GetOrderByPublicID_Call {
+	return &OrderRepositoryMock_GetOrderByPublicID_Call{Call: _e.mock.On("GetOrderByPublicID", ctx, userID, publicID)}
+}
+
+func (_c *OrderRepositoryMock_GetOrderByPublicID_Call) Run(run func(ctx context.Context, userID int64, publicID uuid.UUID)) *OrderRepositoryMock_GetOrderByPublicID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *OrderRepositoryMock_GetOrderByPublicID_Call) Return(_a0 *domain.Order, _a1 error) *OrderRepositoryMock_GetOrderByPublicID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *OrderRepositoryMock_GetOrderByPublicID_Call) RunAndReturn(run func(context.Context, int64, uuid.UUID) (*domain.Order, error)) *OrderRepositoryMock_GetOrderByPublicID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetOrdersByNumbers provides a mock function with given fields: ctx, numbers
+func (_m *OrderRepositoryMock) GetOrdersByNumbers(ctx context.Context, numbers []string) ([]*domain.Order, error) {
+	ret := _m.Called(ctx, numbers)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrdersByNumbers")
+	}
+
+	var r0 []*domain.Order
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) ([]*domain.Order, error)); ok {
+		return rf(ctx, numbers)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string) []*domain.Order); ok {
+		r0 = rf(ctx, numbers)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Order)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, numbers)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OrderRepositoryMock_GetOrdersByNumbers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOrdersByNumbers'
+type OrderRepositoryMock_GetOrdersByNumbers_Call struct {
+	*mock.Call
+}
+
+// GetOrdersByNumbers is a helper method to define mock.On call
+//   - ctx context.Context
+//   - numbers []string
+func (_e *OrderRepositoryMock_Expecter) GetOrdersByNumbers(ctx interface{}, numbers interface{}) *OrderRepositoryMock_GetOrdersByNumbers_Call {
+	return &OrderRepositoryMock_GetOrdersByNumbers_Call{Call: _e.mock.On("GetOrdersByNumbers", ctx, numbers)}
+}
+
+func (_c *OrderRepositoryMock_GetOrdersByNumbers_Call) Run(run func(ctx context.Context, numbers []string)) *OrderRepositoryMock_GetOrdersByNumbers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]string))
+	})
+	return _c
+}
+
+func (_c *OrderRepositoryMock_GetOrdersByNumbers_Call) Return(_a0 []*domain.Order, _a1 error) *OrderRepositoryMock_GetOrdersByNumbers_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *OrderRepositoryMock_GetOrdersByNumbers_Call) RunAndReturn(run func(context.Context, []string) ([]*domain.Order, error)) *OrderRepositoryMock_GetOrdersByNumbers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetOrdersByUserID provides a mock function with given fields: ctx, userID, filter
+func (_m *OrderRepositoryMock) GetOrdersByUserID(ctx context.Context, userID int64, filter domain.OrderFilter) ([]*domain.Order, error) {
+	ret := _m.Called(ctx, userID, filter)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetOrdersByUserID")
@@ -151,19 +565,19 @@ func (_m *OrderRepositoryMock) GetOrdersByUserID(ctx context.Context, userID int
 
 	var r0 []*domain.Order
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]*domain.Order, error)); ok {
-		return rf(ctx, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, domain.OrderFilter) ([]*domain.Order, error)); ok {
+		return rf(ctx, userID, filter)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int64) []*domain.Order); ok {
-		r0 = rf(ctx, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, domain.OrderFilter) []*domain.Order); ok {
+		r0 = rf(ctx, userID, filter)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*domain.Order)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
-		r1 = rf(ctx, userID)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, domain.OrderFilter) error); ok {
+		r1 = rf(ctx, userID, filter)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -179,13 +593,14 @@ type OrderRepositoryMock_GetOrdersByUserID_Call struct {
 // GetOrdersByUserID is a helper method to define mock.On call
 //   - ctx context.Context
 //   - userID int64
-func (_e *OrderRepositoryMock_Expecter) GetOrdersByUserID(ctx interface{}, userID interface{}) *OrderRepositoryMock_GetOrdersByUserID_Call {
-	return &OrderRepositoryMock_GetOrdersByUserID_Call{Call: _e.mock.On("GetOrdersByUserID", ctx, userID)}
+//   - filter domain.OrderFilter
+func (_e *OrderRepositoryMock_Expecter) GetOrdersByUserID(ctx interface{}, userID interface{}, filter interface{}) *OrderRepositoryMock_GetOrdersByUserID_Call {
+	return &OrderRepositoryMock_GetOrdersByUserID_Call{Call: _e.mock.On("GetOrdersByUserID", ctx, userID, filter)}
 }
 
-func (_c *OrderRepositoryMock_GetOrdersByUserID_Call) Run(run func(ctx context.Context, userID int64)) *OrderRepositoryMock_GetOrdersByUserID_Call {
+func (_c *OrderRepositoryMock_GetOrdersByUserID_Call) Run(run func(ctx context.Context, userID int64, filter domain.OrderFilter)) *OrderRepositoryMock_GetOrdersByUserID_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int64))
+		run(args[0].(context.Context), args[1].(int64), args[2].(domain.OrderFilter))
 	})
 	return _c
 }
@@ -195,14 +610,73 @@ func (_c *OrderRepositoryMock_GetOrdersByUserID_Call) Return(_a0 []*domain.Order
 	return _c
 }
 
-func (_c *OrderRepositoryMock_GetOrdersByUserID_Call) RunAndReturn(run func(context.Context, int64) ([]*domain.Order, error)) *OrderRepositoryMock_GetOrdersByUserID_Call {
+func (_c *OrderRepositoryMock_GetOrdersByUserID_Call) RunAndReturn(run func(context.Context, int64, domain.OrderFilter) ([]*domain.Order, error)) *OrderRepositoryMock_GetOrdersByUserID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPendingAccrual provides a mock function with given fields: ctx, userID
+func (_m *OrderRepositoryMock) GetPendingAccrual(ctx context.Context, userID int64) (*domain.PendingAccrual, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPendingAccrual")
+	}
+
+	var r0 *domain.PendingAccrual
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*domain.PendingAccrual, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *domain.PendingAccrual); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.PendingAccrual)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OrderRepositoryMock_GetPendingAccrual_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPendingAccrual'
+type OrderRepositoryMock_GetPendingAccrual_Call struct {
+	*mock.Call
+}
+
+// GetPendingAccrual is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+func (_e *OrderRepositoryMock_Expecter) GetPendingAccrual(ctx interface{}, userID interface{}) *OrderRepositoryMock_GetPendingAccrual_Call {
+	return &OrderRepositoryMock_GetPendingAccrual_Call{Call: _e.mock.On("GetPendingAccrual", ctx, userID)}
+}
+
+func (_c *OrderRepositoryMock_GetPendingAccrual_Call) Run(run func(ctx context.Context, userID int64)) *OrderRepositoryMock_GetPendingAccrual_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *OrderRepositoryMock_GetPendingAccrual_Call) Return(_a0 *domain.PendingAccrual, _a1 error) *OrderRepositoryMock_GetPendingAccrual_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *OrderRepositoryMock_GetPendingAccrual_Call) RunAndReturn(run func(context.Context, int64) (*domain.PendingAccrual, error)) *OrderRepositoryMock_GetPendingAccrual_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetPendingOrders provides a mock function with given fields: ctx
-func (_m *OrderRepositoryMock) GetPendingOrders(ctx context.Context) ([]*domain.Order, error) {
-	ret := _m.Called(ctx)
+// GetPendingOrders provides a mock function with given fields: ctx, policies
+func (_m *OrderRepositoryMock) GetPendingOrders(ctx context.Context, policies []domain.OrderScanPolicy) ([]*domain.Order, error) {
+	ret := _m.Called(ctx, policies)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetPendingOrders")
@@ -210,19 +684,19 @@ func (_m *OrderRepositoryMock) GetPendingOrders(ctx context.Context) ([]*domain.
 
 	var r0 []*domain.Order
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) ([]*domain.Order, error)); ok {
-		return rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, []domain.OrderScanPolicy) ([]*domain.Order, error)); ok {
+		return rf(ctx, policies)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) []*domain.Order); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, []domain.OrderScanPolicy) []*domain.Order); ok {
+		r0 = rf(ctx, policies)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*domain.Order)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func(context.Context, []domain.OrderScanPolicy) error); ok {
+		r1 = rf(ctx, policies)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -237,13 +711,14 @@ type OrderRepositoryMock_GetPendingOrders_Call struct {
 
 // GetPendingOrders is a helper method to define mock.On call
 //   - ctx context.Context
-func (_e *OrderRepositoryMock_Expecter) GetPendingOrders(ctx interface{}) *OrderRepositoryMock_GetPendingOrders_Call {
-	return &OrderRepositoryMock_GetPendingOrders_Call{Call: _e.mock.On("GetPendingOrders", ctx)}
+//   - policies []domain.OrderScanPolicy
+func (_e *OrderRepositoryMock_Expecter) GetPendingOrders(ctx interface{}, policies interface{}) *OrderRepositoryMock_GetPendingOrders_Call {
+	return &OrderRepositoryMock_GetPendingOrders_Call{Call: _e.mock.On("GetPendingOrders", ctx, policies)}
 }
 
-func (_c *OrderRepositoryMock_GetPendingOrders_Call) Run(run func(ctx context.Context)) *OrderRepositoryMock_GetPendingOrders_Call {
+func (_c *OrderRepositoryMock_GetPendingOrders_Call) Run(run func(ctx context.Context, policies []domain.OrderScanPolicy)) *OrderRepositoryMock_GetPendingOrders_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context))
+		run(args[0].(context.Context), args[1].([]domain.OrderScanPolicy))
 	})
 	return _c
 }
@@ -253,22 +728,83 @@ func (_c *OrderRepositoryMock_GetPendingOrders_Call) Return(_a0 []*domain.Order,
 	return _c
 }
 
-func (_c *OrderRepositoryMock_GetPendingOrders_Call) RunAndReturn(run func(context.Context) ([]*domain.Order, error)) *OrderRepositoryMock_GetPendingOrders_Call {
+func (_c *OrderRepositoryMock_GetPendingOrders_Call) RunAndReturn(run func(context.Context, []domain.OrderScanPolicy) ([]*domain.Order, error)) *OrderRepositoryMock_GetPendingOrders_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// UpdateOrderStatus provides a mock function with given fields: ctx, number, status, accrual
-func (_m *OrderRepositoryMock) UpdateOrderStatus(ctx context.Context, number string, status domain.OrderStatus, accrual *float64) error {
-	ret := _m.Called(ctx, number, status, accrual)
+// ListOrderDisputes provides a mock function with given fields: ctx, status, limit, offset
+func (_m *OrderRepositoryMock) ListOrderDisputes(ctx context.Context, status domain.DisputeStatus, limit int, offset int) ([]*domain.OrderDispute, error) {
+	ret := _m.Called(ctx, status, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListOrderDisputes")
+	}
+
+	var r0 []*domain.OrderDispute
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.DisputeStatus, int, int) ([]*domain.OrderDispute, error)); ok {
+		return rf(ctx, status, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.DisputeStatus, int, int) []*domain.OrderDispute); ok {
+		r0 = rf(ctx, status, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.OrderDispute)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.DisputeStatus, int, int) error); ok {
+		r1 = rf(ctx, status, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OrderRepositoryMock_ListOrderDisputes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListOrderDisputes'
+type OrderRepositoryMock_ListOrderDisputes_Call struct {
+	*mock.Call
+}
+
+// ListOrderDisputes is a helper method to define mock.On call
+//   - ctx context.Context
+//   - status domain.DisputeStatus
+//   - limit int
+//   - offset int
+func (_e *OrderRepositoryMock_Expecter) ListOrderDisputes(ctx interface{}, status interface{}, limit interface{}, offset interface{}) *OrderRepositoryMock_ListOrderDisputes_Call {
+	return &OrderRepositoryMock_ListOrderDisputes_Call{Call: _e.mock.On("ListOrderDisputes", ctx, status, limit, offset)}
+}
+
+func (_c *OrderRepositoryMock_ListOrderDisputes_Call) Run(run func(ctx context.Context, status domain.DisputeStatus, limit int, offset int)) *OrderRepositoryMock_ListOrderDisputes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(domain.DisputeStatus), args[2].(int), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *OrderRepositoryMock_ListOrderDisputes_Call) Return(_a0 []*domain.OrderDispute, _a1 error) *OrderRepositoryMock_ListOrderDisputes_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *OrderRepositoryMock_ListOrderDisputes_Call) RunAndReturn(run func(context.Context, domain.DisputeStatus, int, int) ([]*domain.OrderDispute, error)) *OrderRepositoryMock_ListOrderDisputes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RecordDuplicateSubmission provides a mock function with given fields: ctx, userID, channel
+func (_m *OrderRepositoryMock) RecordDuplicateSubmission(ctx context.Context, userID int64, channel string) error {
+	ret := _m.Called(ctx, userID, channel)
 
 	if len(ret) == 0 {
-		panic("no return value specified for UpdateOrderStatus")
+		panic("no return value specified for RecordDuplicateSubmission")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, domain.OrderStatus, *float64) error); ok {
-		r0 = rf(ctx, number, status, accrual)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) error); ok {
+		r0 = rf(ctx, userID, channel)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -276,33 +812,214 @@ func (_m *OrderRepositoryMock) UpdateOrderStatus(ctx context.Context, number str
 	return r0
 }
 
-// OrderRepositoryMock_UpdateOrderStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateOrderStatus'
-type OrderRepositoryMock_UpdateOrderStatus_Call struct {
+// OrderRepositoryMock_RecordDuplicateSubmission_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecordDuplicateSubmission'
+type OrderRepositoryMock_RecordDuplicateSubmission_Call struct {
 	*mock.Call
 }
 
-// UpdateOrderStatus is a helper method to define mock.On call
+// RecordDuplicateSubmission is a helper method to define mock.On call
 //   - ctx context.Context
-//   - number string
-//   - status domain.OrderStatus
-//   - accrual *float64
-func (_e *OrderRepositoryMock_Expecter) UpdateOrderStatus(ctx interface{}, number interface{}, status interface{}, accrual interface{}) *OrderRepositoryMock_UpdateOrderStatus_Call {
-	return &OrderRepositoryMock_UpdateOrderStatus_Call{Call: _e.mock.On("UpdateOrderStatus", ctx, number, status, accrual)}
+//   - userID int64
+//   - channel string
+func (_e *OrderRepositoryMock_Expecter) RecordDuplicateSubmission(ctx interface{}, userID interface{}, channel interface{}) *OrderRepositoryMock_RecordDuplicateSubmission_Call {
+	return &OrderRepositoryMock_RecordDuplicateSubmission_Call{Call: _e.mock.On("RecordDuplicateSubmission", ctx, userID, channel)}
 }
 
-func (_c *OrderRepositoryMock_UpdateOrderStatus_Call) Run(run func(ctx context.Context, number string, status domain.OrderStatus, accrual *float64)) *OrderRepositoryMock_UpdateOrderStatus_Call {
+func (_c *OrderRepositoryMock_RecordDuplicateSubmission_Call) Run(run func(ctx context.Context, userID int64, channel string)) *OrderRepositoryMock_RecordDuplicateSubmission_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].(domain.OrderStatus), args[3].(*float64))
+		run(args[0].(context.Context), args[1].(int64), args[2].(string))
 	})
 	return _c
 }
 
-func (_c *OrderRepositoryMock_UpdateOrderStatus_Call) Return(_a0 error) *OrderRepositoryMock_UpdateOrderStatus_Call {
+func (_c *OrderRepositoryMock_RecordDuplicateSubmission_Call) Return(_a0 error) *OrderRepositoryMock_RecordDuplicateSubmission_Call {
 	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *OrderRepositoryMock_UpdateOrderStatus_Call) RunAndReturn(run func(context.Context, string, domain.OrderStatus, *float64) error) *OrderRepositoryMock_UpdateOrderStatus_Call {
+func (_c *OrderRepositoryMock_RecordDuplicateSubmission_Call) RunAndReturn(run func(context.Context, int64, string) error) *OrderRepositoryMock_RecordDuplicateSubmission_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ResolveOrderDispute provides a mock function with given fields: ctx, id, resolution, comment, resolvedBy
+func (_m *OrderRepositoryMock) ResolveOrderDispute(ctx context.Context, id int64, resolution domain.DisputeResolution, comment string, resolvedBy int64) (*domain.OrderDispute, error) {
+	ret := _m.Called(ctx, id, resolution, comment, resolvedBy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResolveOrderDispute")
+	}
+
+	var r0 *domain.OrderDispute
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, domain.DisputeResolution, string, int64) (*domain.OrderDispute, error)); ok {
+		return rf(ctx, id, resolution, comment, resolvedBy)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, domain.DisputeResolution, string, int64) *domain.OrderDispute); ok {
+		r0 = rf(ctx, id, resolution, comment, resolvedBy)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.OrderDispute)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, domain.DisputeResolution, string, int64) error); ok {
+		r1 = rf(ctx, id, resolution, comment, resolvedBy)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OrderRepositoryMock_ResolveOrderDispute_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResolveOrderDispute'
+type OrderRepositoryMock_ResolveOrderDispute_Call struct {
+	*mock.Call
+}
+
+// ResolveOrderDispute is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+//   - resolution domain.DisputeResolution
+//   - comment string
+//   - resolvedBy int64
+func (_e *OrderRepositoryMock_Expecter) ResolveOrderDispute(ctx interface{}, id interface{}, resolution interface{}, comment interface{}, resolvedBy interface{}) *OrderRepositoryMock_ResolveOrderDispute_Call {
+	return &OrderRepositoryMock_ResolveOrderDispute_Call{Call: _e.mock.On("ResolveOrderDispute", ctx, id, resolution, comment, resolvedBy)}
+}
+
+func (_c *OrderRepositoryMock_ResolveOrderDispute_Call) Run(run func(ctx context.Context, id int64, resolution domain.DisputeResolution, comment string, resolvedBy int64)) *OrderRepositoryMock_ResolveOrderDispute_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(domain.DisputeResolution), args[3].(string), args[4].(int64))
+	})
+	return _c
+}
+
+func (_c *OrderRepositoryMock_ResolveOrderDispute_Call) Return(_a0 *domain.OrderDispute, _a1 error) *OrderRepositoryMock_ResolveOrderDispute_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *OrderRepositoryMock_ResolveOrderDispute_Call) RunAndReturn(run func(context.Context, int64, domain.DisputeResolution, string, int64) (*domain.OrderDispute, error)) *OrderRepositoryMock_ResolveOrderDispute_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SearchOrders provides a mock function with given fields: ctx, filter
+func (_m *OrderRepositoryMock) SearchOrders(ctx context.Context, filter domain.OrderSearchFilter) ([]*domain.AdminOrder, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SearchOrders")
+	}
+
+	var r0 []*domain.AdminOrder
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.OrderSearchFilter) ([]*domain.AdminOrder, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.OrderSearchFilter) []*domain.AdminOrder); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.AdminOrder)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.OrderSearchFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OrderRepositoryMock_SearchOrders_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SearchOrders'
+type OrderRepositoryMock_SearchOrders_Call struct {
+	*mock.Call
+}
+
+// SearchOrders is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter domain.OrderSearchFilter
+func (_e *OrderRepositoryMock_Expecter) SearchOrders(ctx interface{}, filter interface{}) *OrderRepositoryMock_SearchOrders_Call {
+	return &OrderRepositoryMock_SearchOrders_Call{Call: _e.mock.On("SearchOrders", ctx, filter)}
+}
+
+func (_c *OrderRepositoryMock_SearchOrders_Call) Run(run func(ctx context.Context, filter domain.OrderSearchFilter)) *OrderRepositoryMock_SearchOrders_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(domain.OrderSearchFilter))
+	})
+	return _c
+}
+
+func (_c *OrderRepositoryMock_SearchOrders_Call) Return(_a0 []*domain.AdminOrder, _a1 error) *OrderRepositoryMock_SearchOrders_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *OrderRepositoryMock_SearchOrders_Call) RunAndReturn(run func(context.Context, domain.OrderSearchFilter) ([]*domain.AdminOrder, error)) *OrderRepositoryMock_SearchOrders_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// TransferOrder provides a mock function with given fields: ctx, number, toLogin, transferredBy
+func (_m *OrderRepositoryMock) TransferOrder(ctx context.Context, number string, toLogin string, transferredBy int64) (*domain.OrderTransfer, error) {
+	ret := _m.Called(ctx, number, toLogin, transferredBy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TransferOrder")
+	}
+
+	var r0 *domain.OrderTransfer
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64) (*domain.OrderTransfer, error)); ok {
+		return rf(ctx, number, toLogin, transferredBy)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64) *domain.OrderTransfer); ok {
+		r0 = rf(ctx, number, toLogin, transferredBy)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.OrderTransfer)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int64) error); ok {
+		r1 = rf(ctx, number, toLogin, transferredBy)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OrderRepositoryMock_TransferOrder_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TransferOrder'
+type OrderRepositoryMock_TransferOrder_Call struct {
+	*mock.Call
+}
+
+// TransferOrder is a helper method to define mock.On call
+//   - ctx context.Context
+//   - number string
+//   - toLogin string
+//   - transferredBy int64
+func (_e *OrderRepositoryMock_Expecter) TransferOrder(ctx interface{}, number interface{}, toLogin interface{}, transferredBy interface{}) *OrderRepositoryMock_TransferOrder_Call {
+	return &OrderRepositoryMock_TransferOrder_Call{Call: _e.mock.On("TransferOrder", ctx, number, toLogin, transferredBy)}
+}
+
+func (_c *OrderRepositoryMock_TransferOrder_Call) Run(run func(ctx context.Context, number string, toLogin string, transferredBy int64)) *OrderRepositoryMock_TransferOrder_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(int64))
+	})
+	return _c
+}
+
+func (_c *OrderRepositoryMock_TransferOrder_Call) Return(_a0 *domain.OrderTransfer, _a1 error) *OrderRepositoryMock_TransferOrder_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *OrderRepositoryMock_TransferOrder_Call) RunAndReturn(run func(context.Context, string, string, int64) (*domain.OrderTransfer, error)) *OrderRepositoryMock_TransferOrder_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -0,0 +1,97 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/avc/loyalty-system-diploma/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// OrderSearchServiceMock is an autogenerated mock type for the OrderSearchService type
+type OrderSearchServiceMock struct {
+	mock.Mock
+}
+
+type OrderSearchServiceMock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *OrderSearchServiceMock) EXPECT() *OrderSearchServiceMock_Expecter {
+	return &OrderSearchServiceMock_Expecter{mock: &_m.Mock}
+}
+
+// SearchOrders provides a mock function with given fields: ctx, filter
+func (_m *OrderSearchServiceMock) SearchOrders(ctx context.Context, filter domain.OrderSearchFilter) (*domain.OrderSearchResult, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SearchOrders")
+	}
+
+	var r0 *domain.OrderSearchResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.OrderSearchFilter) (*domain.OrderSearchResult, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.OrderSearchFilter) *domain.OrderSearchResult); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.OrderSearchResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.OrderSearchFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OrderSearchServiceMock_SearchOrders_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SearchOrders'
+type OrderSearchServiceMock_SearchOrders_Call struct {
+	*mock.Call
+}
+
+// SearchOrders is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter domain.OrderSearchFilter
+func (_e *OrderSearchServiceMock_Expecter) SearchOrders(ctx interface{}, filter interface{}) *OrderSearchServiceMock_SearchOrders_Call {
+	return &OrderSearchServiceMock_SearchOrders_Call{Call: _e.mock.On("SearchOrders", ctx, filter)}
+}
+
+func (_c *OrderSearchServiceMock_SearchOrders_Call) Run(run func(ctx context.Context, filter domain.OrderSearchFilter)) *OrderSearchServiceMock_SearchOrders_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(domain.OrderSearchFilter))
+	})
+	return _c
+}
+
+func (_c *OrderSearchServiceMock_SearchOrders_Call) Return(_a0 *domain.OrderSearchResult, _a1 error) *OrderSearchServiceMock_SearchOrders_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *OrderSearchServiceMock_SearchOrders_Call) RunAndReturn(run func(context.Context, domain.OrderSearchFilter) (*domain.OrderSearchResult, error)) *OrderSearchServiceMock_SearchOrders_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewOrderSearchServiceMock creates a new instance of OrderSearchServiceMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewOrderSearchServiceMock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *OrderSearchServiceMock {
+	mock := &OrderSearchServiceMock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
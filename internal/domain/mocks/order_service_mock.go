@@ -6,7 +6,10 @@ import (
 	context "context"
 
 	domain "github.com/avc/loyalty-system-diploma/internal/domain"
+
 	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
 )
 
 // OrderServiceMock is an autogenerated mock type for the OrderService type
@@ -22,29 +25,89 @@ func (_m *OrderServiceMock) EXPECT() *OrderServiceMock_Expecter {
 	return &OrderServiceMock_Expecter{mock: &_m.Mock}
 }
 
-// GetOrders provides a mock function with given fields: ctx, userID
-func (_m *OrderServiceMock) GetOrders(ctx context.Context, userID int64) ([]*domain.Order, error) {
-	ret := _m.Called(ctx, userID)
+// GetOrder provides a mock function with given fields: ctx, userID, publicID
+func (_m *OrderServiceMock) GetOrder(ctx context.Context, userID int64, publicID uuid.UUID) (*domain.Order, error) {
+	ret := _m.Called(ctx, userID, publicID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrder")
+	}
+
+	var r0 *domain.Order
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, uuid.UUID) (*domain.Order, error)); ok {
+		return rf(ctx, userID, publicID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, uuid.UUID) *domain.Order); ok {
+		r0 = rf(ctx, userID, publicID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Order)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID, publicID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OrderServiceMock_GetOrder_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOrder'
+type OrderServiceMock_GetOrder_Call struct {
+	*mock.Call
+}
+
+// GetOrder is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - publicID uuid.UUID
+func (_e *OrderServiceMock_Expecter) GetOrder(ctx interface{}, userID interface{}, publicID interface{}) *OrderServiceMock_GetOrder_Call {
+	return &OrderServiceMock_GetOrder_Call{Call: _e.mock.On("GetOrder", ctx, userID, publicID)}
+}
+
+func (_c *OrderServiceMock_GetOrder_Call) Run(run func(ctx context.Context, userID int64, publicID uuid.UUID)) *OrderServiceMock_GetOrder_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *OrderServiceMock_GetOrder_Call) Return(_a0 *domain.Order, _a1 error) *OrderServiceMock_GetOrder_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *OrderServiceMock_GetOrder_Call) RunAndReturn(run func(context.Context, int64, uuid.UUID) (*domain.Order, error)) *OrderServiceMock_GetOrder_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetOrders provides a mock function with given fields: ctx, userID, filter
+func (_m *OrderServiceMock) GetOrders(ctx context.Context, userID int64, filter domain.OrderFilter) (*domain.OrderPage, error) {
+	ret := _m.Called(ctx, userID, filter)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetOrders")
 	}
 
-	var r0 []*domain.Order
+	var r0 *domain.OrderPage
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]*domain.Order, error)); ok {
-		return rf(ctx, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, domain.OrderFilter) (*domain.OrderPage, error)); ok {
+		return rf(ctx, userID, filter)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int64) []*domain.Order); ok {
-		r0 = rf(ctx, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, domain.OrderFilter) *domain.OrderPage); ok {
+		r0 = rf(ctx, userID, filter)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*domain.Order)
+			r0 = ret.Get(0).(*domain.OrderPage)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
-		r1 = rf(ctx, userID)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, domain.OrderFilter) error); ok {
+		r1 = rf(ctx, userID, filter)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -60,38 +123,97 @@ type OrderServiceMock_GetOrders_Call struct {
 // GetOrders is a helper method to define mock.On call
 //   - ctx context.Context
 //   - userID int64
-func (_e *OrderServiceMock_Expecter) GetOrders(ctx interface{}, userID interface{}) *OrderServiceMock_GetOrders_Call {
-	return &OrderServiceMock_GetOrders_Call{Call: _e.mock.On("GetOrders", ctx, userID)}
+//   - filter domain.OrderFilter
+func (_e *OrderServiceMock_Expecter) GetOrders(ctx interface{}, userID interface{}, filter interface{}) *OrderServiceMock_GetOrders_Call {
+	return &OrderServiceMock_GetOrders_Call{Call: _e.mock.On("GetOrders", ctx, userID, filter)}
 }
 
-func (_c *OrderServiceMock_GetOrders_Call) Run(run func(ctx context.Context, userID int64)) *OrderServiceMock_GetOrders_Call {
+func (_c *OrderServiceMock_GetOrders_Call) Run(run func(ctx context.Context, userID int64, filter domain.OrderFilter)) *OrderServiceMock_GetOrders_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int64))
+		run(args[0].(context.Context), args[1].(int64), args[2].(domain.OrderFilter))
 	})
 	return _c
 }
 
-func (_c *OrderServiceMock_GetOrders_Call) Return(_a0 []*domain.Order, _a1 error) *OrderServiceMock_GetOrders_Call {
+func (_c *OrderServiceMock_GetOrders_Call) Return(_a0 *domain.OrderPage, _a1 error) *OrderServiceMock_GetOrders_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *OrderServiceMock_GetOrders_Call) RunAndReturn(run func(context.Context, int64) ([]*domain.Order, error)) *OrderServiceMock_GetOrders_Call {
+func (_c *OrderServiceMock_GetOrders_Call) RunAndReturn(run func(context.Context, int64, domain.OrderFilter) (*domain.OrderPage, error)) *OrderServiceMock_GetOrders_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// SubmitOrder provides a mock function with given fields: ctx, userID, orderNumber
-func (_m *OrderServiceMock) SubmitOrder(ctx context.Context, userID int64, orderNumber string) error {
-	ret := _m.Called(ctx, userID, orderNumber)
+// SubmitFiscalReceipt provides a mock function with given fields: ctx, userID, payload
+func (_m *OrderServiceMock) SubmitFiscalReceipt(ctx context.Context, userID int64, payload string) (string, error) {
+	ret := _m.Called(ctx, userID, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SubmitFiscalReceipt")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) (string, error)); ok {
+		return rf(ctx, userID, payload)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) string); ok {
+		r0 = rf(ctx, userID, payload)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string) error); ok {
+		r1 = rf(ctx, userID, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OrderServiceMock_SubmitFiscalReceipt_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SubmitFiscalReceipt'
+type OrderServiceMock_SubmitFiscalReceipt_Call struct {
+	*mock.Call
+}
+
+// SubmitFiscalReceipt is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - payload string
+func (_e *OrderServiceMock_Expecter) SubmitFiscalReceipt(ctx interface{}, userID interface{}, payload interface{}) *OrderServiceMock_SubmitFiscalReceipt_Call {
+	return &OrderServiceMock_SubmitFiscalReceipt_Call{Call: _e.mock.On("SubmitFiscalReceipt", ctx, userID, payload)}
+}
+
+func (_c *OrderServiceMock_SubmitFiscalReceipt_Call) Run(run func(ctx context.Context, userID int64, payload string)) *OrderServiceMock_SubmitFiscalReceipt_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *OrderServiceMock_SubmitFiscalReceipt_Call) Return(_a0 string, _a1 error) *OrderServiceMock_SubmitFiscalReceipt_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *OrderServiceMock_SubmitFiscalReceipt_Call) RunAndReturn(run func(context.Context, int64, string) (string, error)) *OrderServiceMock_SubmitFiscalReceipt_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SubmitOrder provides a mock function with given fields: ctx, userID, orderNumber, metadata
+func (_m *OrderServiceMock) SubmitOrder(ctx context.Context, userID int64, orderNumber string, metadata *domain.OrderMetadata) error {
+	ret := _m.Called(ctx, userID, orderNumber, metadata)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SubmitOrder")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, int64, string) error); ok {
-		r0 = rf(ctx, userID, orderNumber)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, *domain.OrderMetadata) error); ok {
+		r0 = rf(ctx, userID, orderNumber, metadata)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -108,13 +230,14 @@ type OrderServiceMock_SubmitOrder_Call struct {
 //   - ctx context.Context
 //   - userID int64
 //   - orderNumber string
-func (_e *OrderServiceMock_Expecter) SubmitOrder(ctx interface{}, userID interface{}, orderNumber interface{}) *OrderServiceMock_SubmitOrder_Call {
-	return &OrderServiceMock_SubmitOrder_Call{Call: _e.mock.On("SubmitOrder", ctx, userID, orderNumber)}
+//   - metadata *domain.OrderMetadata
+func (_e *OrderServiceMock_Expecter) SubmitOrder(ctx interface{}, userID interface{}, orderNumber interface{}, metadata interface{}) *OrderServiceMock_SubmitOrder_Call {
+	return &OrderServiceMock_SubmitOrder_Call{Call: _e.mock.On("SubmitOrder", ctx, userID, orderNumber, metadata)}
 }
 
-func (_c *OrderServiceMock_SubmitOrder_Call) Run(run func(ctx context.Context, userID int64, orderNumber string)) *OrderServiceMock_SubmitOrder_Call {
+func (_c *OrderServiceMock_SubmitOrder_Call) Run(run func(ctx context.Context, userID int64, orderNumber string, metadata *domain.OrderMetadata)) *OrderServiceMock_SubmitOrder_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int64), args[2].(string))
+		run(args[0].(context.Context), args[1].(int64), args[2].(string), args[3].(*domain.OrderMetadata))
 	})
 	return _c
 }
@@ -124,7 +247,7 @@ func (_c *OrderServiceMock_SubmitOrder_Call) Return(_a0 error) *OrderServiceMock
 	return _c
 }
 
-func (_c *OrderServiceMock_SubmitOrder_Call) RunAndReturn(run func(context.Context, int64, string) error) *OrderServiceMock_SubmitOrder_Call {
+func (_c *OrderServiceMock_SubmitOrder_Call) RunAndReturn(run func(context.Context, int64, string, *domain.OrderMetadata) error) *OrderServiceMock_SubmitOrder_Call {
 	_c.Call.Return(run)
 	return _c
 }
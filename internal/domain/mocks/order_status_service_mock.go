@@ -0,0 +1,97 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/avc/loyalty-system-diploma/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// OrderStatusServiceMock is an autogenerated mock type for the OrderStatusService type
+type OrderStatusServiceMock struct {
+	mock.Mock
+}
+
+type OrderStatusServiceMock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *OrderStatusServiceMock) EXPECT() *OrderStatusServiceMock_Expecter {
+	return &OrderStatusServiceMock_Expecter{mock: &_m.Mock}
+}
+
+// GetOrderStatuses provides a mock function with given fields: ctx, numbers
+func (_m *OrderStatusServiceMock) GetOrderStatuses(ctx context.Context, numbers []string) ([]*domain.Order, error) {
+	ret := _m.Called(ctx, numbers)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrderStatuses")
+	}
+
+	var r0 []*domain.Order
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) ([]*domain.Order, error)); ok {
+		return rf(ctx, numbers)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string) []*domain.Order); ok {
+		r0 = rf(ctx, numbers)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Order)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, numbers)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OrderStatusServiceMock_GetOrderStatuses_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOrderStatuses'
+type OrderStatusServiceMock_GetOrderStatuses_Call struct {
+	*mock.Call
+}
+
+// GetOrderStatuses is a helper method to define mock.On call
+//   - ctx context.Context
+//   - numbers []string
+func (_e *OrderStatusServiceMock_Expecter) GetOrderStatuses(ctx interface{}, numbers interface{}) *OrderStatusServiceMock_GetOrderStatuses_Call {
+	return &OrderStatusServiceMock_GetOrderStatuses_Call{Call: _e.mock.On("GetOrderStatuses", ctx, numbers)}
+}
+
+func (_c *OrderStatusServiceMock_GetOrderStatuses_Call) Run(run func(ctx context.Context, numbers []string)) *OrderStatusServiceMock_GetOrderStatuses_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]string))
+	})
+	return _c
+}
+
+func (_c *OrderStatusServiceMock_GetOrderStatuses_Call) Return(_a0 []*domain.Order, _a1 error) *OrderStatusServiceMock_GetOrderStatuses_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *OrderStatusServiceMock_GetOrderStatuses_Call) RunAndReturn(run func(context.Context, []string) ([]*domain.Order, error)) *OrderStatusServiceMock_GetOrderStatuses_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewOrderStatusServiceMock creates a new instance of OrderStatusServiceMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewOrderStatusServiceMock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *OrderStatusServiceMock {
+	mock := &OrderStatusServiceMock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
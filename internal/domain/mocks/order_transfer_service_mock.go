@@ -0,0 +1,99 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/avc/loyalty-system-diploma/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// OrderTransferServiceMock is an autogenerated mock type for the OrderTransferService type
+type OrderTransferServiceMock struct {
+	mock.Mock
+}
+
+type OrderTransferServiceMock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *OrderTransferServiceMock) EXPECT() *OrderTransferServiceMock_Expecter {
+	return &OrderTransferServiceMock_Expecter{mock: &_m.Mock}
+}
+
+// TransferOrder provides a mock function with given fields: ctx, number, login, adminID
+func (_m *OrderTransferServiceMock) TransferOrder(ctx context.Context, number string, login string, adminID int64) (*domain.OrderTransfer, error) {
+	ret := _m.Called(ctx, number, login, adminID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TransferOrder")
+	}
+
+	var r0 *domain.OrderTransfer
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64) (*domain.OrderTransfer, error)); ok {
+		return rf(ctx, number, login, adminID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64) *domain.OrderTransfer); ok {
+		r0 = rf(ctx, number, login, adminID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.OrderTransfer)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int64) error); ok {
+		r1 = rf(ctx, number, login, adminID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OrderTransferServiceMock_TransferOrder_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TransferOrder'
+type OrderTransferServiceMock_TransferOrder_Call struct {
+	*mock.Call
+}
+
+// TransferOrder is a helper method to define mock.On call
+//   - ctx context.Context
+//   - number string
+//   - login string
+//   - adminID int64
+func (_e *OrderTransferServiceMock_Expecter) TransferOrder(ctx interface{}, number interface{}, login interface{}, adminID interface{}) *OrderTransferServiceMock_TransferOrder_Call {
+	return &OrderTransferServiceMock_TransferOrder_Call{Call: _e.mock.On("TransferOrder", ctx, number, login, adminID)}
+}
+
+func (_c *OrderTransferServiceMock_TransferOrder_Call) Run(run func(ctx context.Context, number string, login string, adminID int64)) *OrderTransferServiceMock_TransferOrder_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(int64))
+	})
+	return _c
+}
+
+func (_c *OrderTransferServiceMock_TransferOrder_Call) Return(_a0 *domain.OrderTransfer, _a1 error) *OrderTransferServiceMock_TransferOrder_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *OrderTransferServiceMock_TransferOrder_Call) RunAndReturn(run func(context.Context, string, string, int64) (*domain.OrderTransfer, error)) *OrderTransferServiceMock_TransferOrder_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewOrderTransferServiceMock creates a new instance of OrderTransferServiceMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewOrderTransferServiceMock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *OrderTransferServiceMock {
+	mock := &OrderTransferServiceMock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
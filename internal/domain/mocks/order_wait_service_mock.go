@@ -0,0 +1,98 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/avc/loyalty-system-diploma/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// OrderWaitServiceMock is an autogenerated mock type for the OrderWaitService type
+type OrderWaitServiceMock struct {
+	mock.Mock
+}
+
+type OrderWaitServiceMock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *OrderWaitServiceMock) EXPECT() *OrderWaitServiceMock_Expecter {
+	return &OrderWaitServiceMock_Expecter{mock: &_m.Mock}
+}
+
+// GetOrderByNumber provides a mock function with given fields: ctx, userID, number
+func (_m *OrderWaitServiceMock) GetOrderByNumber(ctx context.Context, userID int64, number string) (*domain.Order, error) {
+	ret := _m.Called(ctx, userID, number)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrderByNumber")
+	}
+
+	var r0 *domain.Order
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) (*domain.Order, error)); ok {
+		return rf(ctx, userID, number)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) *domain.Order); ok {
+		r0 = rf(ctx, userID, number)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Order)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string) error); ok {
+		r1 = rf(ctx, userID, number)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OrderWaitServiceMock_GetOrderByNumber_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOrderByNumber'
+type OrderWaitServiceMock_GetOrderByNumber_Call struct {
+	*mock.Call
+}
+
+// GetOrderByNumber is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - number string
+func (_e *OrderWaitServiceMock_Expecter) GetOrderByNumber(ctx interface{}, userID interface{}, number interface{}) *OrderWaitServiceMock_GetOrderByNumber_Call {
+	return &OrderWaitServiceMock_GetOrderByNumber_Call{Call: _e.mock.On("GetOrderByNumber", ctx, userID, number)}
+}
+
+func (_c *OrderWaitServiceMock_GetOrderByNumber_Call) Run(run func(ctx context.Context, userID int64, number string)) *OrderWaitServiceMock_GetOrderByNumber_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *OrderWaitServiceMock_GetOrderByNumber_Call) Return(_a0 *domain.Order, _a1 error) *OrderWaitServiceMock_GetOrderByNumber_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *OrderWaitServiceMock_GetOrderByNumber_Call) RunAndReturn(run func(context.Context, int64, string) (*domain.Order, error)) *OrderWaitServiceMock_GetOrderByNumber_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewOrderWaitServiceMock creates a new instance of OrderWaitServiceMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewOrderWaitServiceMock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *OrderWaitServiceMock {
+	mock := &OrderWaitServiceMock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
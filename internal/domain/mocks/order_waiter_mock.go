@@ -0,0 +1,92 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+
+// OrderWaiterMock is an autogenerated mock type for the OrderWaiter type
+type OrderWaiterMock struct {
+	mock.Mock
+}
+
+type OrderWaiterMock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *OrderWaiterMock) EXPECT() *OrderWaiterMock_Expecter {
+	return &OrderWaiterMock_Expecter{mock: &_m.Mock}
+}
+
+// Wait provides a mock function with given fields: number
+func (_m *OrderWaiterMock) Wait(number string) (<-chan struct{}, func()) {
+	ret := _m.Called(number)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Wait")
+	}
+
+	var r0 <-chan struct{}
+	var r1 func()
+	if rf, ok := ret.Get(0).(func(string) (<-chan struct{}, func())); ok {
+		return rf(number)
+	}
+	if rf, ok := ret.Get(0).(func(string) <-chan struct{}); ok {
+		r0 = rf(number)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan struct{})
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) func()); ok {
+		r1 = rf(number)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(func())
+		}
+	}
+
+	return r0, r1
+}
+
+// OrderWaiterMock_Wait_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Wait'
+type OrderWaiterMock_Wait_Call struct {
+	*mock.Call
+}
+
+// Wait is a helper method to define mock.On call
+//   - number string
+func (_e *OrderWaiterMock_Expecter) Wait(number interface{}) *OrderWaiterMock_Wait_Call {
+	return &OrderWaiterMock_Wait_Call{Call: _e.mock.On("Wait", number)}
+}
+
+func (_c *OrderWaiterMock_Wait_Call) Run(run func(number string)) *OrderWaiterMock_Wait_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *OrderWaiterMock_Wait_Call) Return(_a0 <-chan struct{}, _a1 func()) *OrderWaiterMock_Wait_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *OrderWaiterMock_Wait_Call) RunAndReturn(run func(string) (<-chan struct{}, func())) *OrderWaiterMock_Wait_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewOrderWaiterMock creates a new instance of OrderWaiterMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewOrderWaiterMock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *OrderWaiterMock {
+	mock := &OrderWaiterMock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
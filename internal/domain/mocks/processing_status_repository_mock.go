@@ -0,0 +1,98 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/avc/loyalty-system-diploma/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// ProcessingStatusRepositoryMock is an autogenerated mock type for the ProcessingStatusRepository type
+type ProcessingStatusRepositoryMock struct {
+	mock.Mock
+}
+
+type ProcessingStatusRepositoryMock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *ProcessingStatusRepositoryMock) EXPECT() *ProcessingStatusRepositoryMock_Expecter {
+	return &ProcessingStatusRepositoryMock_Expecter{mock: &_m.Mock}
+}
+
+// GetProcessingStatus provides a mock function with given fields: ctx, since
+func (_m *ProcessingStatusRepositoryMock) GetProcessingStatus(ctx context.Context, since time.Time) (*domain.ProcessingStatus, error) {
+	ret := _m.Called(ctx, since)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProcessingStatus")
+	}
+
+	var r0 *domain.ProcessingStatus
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (*domain.ProcessingStatus, error)); ok {
+		return rf(ctx, since)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) *domain.ProcessingStatus); ok {
+		r0 = rf(ctx, since)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ProcessingStatus)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, since)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ProcessingStatusRepositoryMock_GetProcessingStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetProcessingStatus'
+type ProcessingStatusRepositoryMock_GetProcessingStatus_Call struct {
+	*mock.Call
+}
+
+// GetProcessingStatus is a helper method to define mock.On call
+//   - ctx context.Context
+//   - since time.Time
+func (_e *ProcessingStatusRepositoryMock_Expecter) GetProcessingStatus(ctx interface{}, since interface{}) *ProcessingStatusRepositoryMock_GetProcessingStatus_Call {
+	return &ProcessingStatusRepositoryMock_GetProcessingStatus_Call{Call: _e.mock.On("GetProcessingStatus", ctx, since)}
+}
+
+func (_c *ProcessingStatusRepositoryMock_GetProcessingStatus_Call) Run(run func(ctx context.Context, since time.Time)) *ProcessingStatusRepositoryMock_GetProcessingStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time))
+	})
+	return _c
+}
+
+func (_c *ProcessingStatusRepositoryMock_GetProcessingStatus_Call) Return(_a0 *domain.ProcessingStatus, _a1 error) *ProcessingStatusRepositoryMock_GetProcessingStatus_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ProcessingStatusRepositoryMock_GetProcessingStatus_Call) RunAndReturn(run func(context.Context, time.Time) (*domain.ProcessingStatus, error)) *ProcessingStatusRepositoryMock_GetProcessingStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewProcessingStatusRepositoryMock creates a new instance of ProcessingStatusRepositoryMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewProcessingStatusRepositoryMock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ProcessingStatusRepositoryMock {
+	mock := &ProcessingStatusRepositoryMock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,96 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/avc/loyalty-system-diploma/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ProcessingStatusServiceMock is an autogenerated mock type for the ProcessingStatusService type
+type ProcessingStatusServiceMock struct {
+	mock.Mock
+}
+
+type ProcessingStatusServiceMock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *ProcessingStatusServiceMock) EXPECT() *ProcessingStatusServiceMock_Expecter {
+	return &ProcessingStatusServiceMock_Expecter{mock: &_m.Mock}
+}
+
+// GetProcessingStatus provides a mock function with given fields: ctx
+func (_m *ProcessingStatusServiceMock) GetProcessingStatus(ctx context.Context) (*domain.ProcessingStatus, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProcessingStatus")
+	}
+
+	var r0 *domain.ProcessingStatus
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*domain.ProcessingStatus, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *domain.ProcessingStatus); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ProcessingStatus)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ProcessingStatusServiceMock_GetProcessingStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetProcessingStatus'
+type ProcessingStatusServiceMock_GetProcessingStatus_Call struct {
+	*mock.Call
+}
+
+// GetProcessingStatus is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *ProcessingStatusServiceMock_Expecter) GetProcessingStatus(ctx interface{}) *ProcessingStatusServiceMock_GetProcessingStatus_Call {
+	return &ProcessingStatusServiceMock_GetProcessingStatus_Call{Call: _e.mock.On("GetProcessingStatus", ctx)}
+}
+
+func (_c *ProcessingStatusServiceMock_GetProcessingStatus_Call) Run(run func(ctx context.Context)) *ProcessingStatusServiceMock_GetProcessingStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *ProcessingStatusServiceMock_GetProcessingStatus_Call) Return(_a0 *domain.ProcessingStatus, _a1 error) *ProcessingStatusServiceMock_GetProcessingStatus_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ProcessingStatusServiceMock_GetProcessingStatus_Call) RunAndReturn(run func(context.Context) (*domain.ProcessingStatus, error)) *ProcessingStatusServiceMock_GetProcessingStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewProcessingStatusServiceMock creates a new instance of ProcessingStatusServiceMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewProcessingStatusServiceMock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ProcessingStatusServiceMock {
+	mock := &ProcessingStatusServiceMock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,157 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/avc/loyalty-system-diploma/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ProfileServiceMock is an autogenerated mock type for the ProfileService type
+type ProfileServiceMock struct {
+	mock.Mock
+}
+
+type ProfileServiceMock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *ProfileServiceMock) EXPECT() *ProfileServiceMock_Expecter {
+	return &ProfileServiceMock_Expecter{mock: &_m.Mock}
+}
+
+// GetProfile provides a mock function with given fields: ctx, userID
+func (_m *ProfileServiceMock) GetProfile(ctx context.Context, userID int64) (*domain.UserProfile, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProfile")
+	}
+
+	var r0 *domain.UserProfile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*domain.UserProfile, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *domain.UserProfile); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.UserProfile)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ProfileServiceMock_GetProfile_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetProfile'
+type ProfileServiceMock_GetProfile_Call struct {
+	*mock.Call
+}
+
+// GetProfile is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+func (_e *ProfileServiceMock_Expecter) GetProfile(ctx interface{}, userID interface{}) *ProfileServiceMock_GetProfile_Call {
+	return &ProfileServiceMock_GetProfile_Call{Call: _e.mock.On("GetProfile", ctx, userID)}
+}
+
+func (_c *ProfileServiceMock_GetProfile_Call) Run(run func(ctx context.Context, userID int64)) *ProfileServiceMock_GetProfile_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *ProfileServiceMock_GetProfile_Call) Return(_a0 *domain.UserProfile, _a1 error) *ProfileServiceMock_GetProfile_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ProfileServiceMock_GetProfile_Call) RunAndReturn(run func(context.Context, int64) (*domain.UserProfile, error)) *ProfileServiceMock_GetProfile_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateProfile provides a mock function with given fields: ctx, userID, profile
+func (_m *ProfileServiceMock) UpdateProfile(ctx context.Context, userID int64, profile domain.UserProfile) (*domain.UserProfile, error) {
+	ret := _m.Called(ctx, userID, profile)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateProfile")
+	}
+
+	var r0 *domain.UserProfile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, domain.UserProfile) (*domain.UserProfile, error)); ok {
+		return rf(ctx, userID, profile)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, domain.UserProfile) *domain.UserProfile); ok {
+		r0 = rf(ctx, userID, profile)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.UserProfile)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, domain.UserProfile) error); ok {
+		r1 = rf(ctx, userID, profile)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ProfileServiceMock_UpdateProfile_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateProfile'
+type ProfileServiceMock_UpdateProfile_Call struct {
+	*mock.Call
+}
+
+// UpdateProfile is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - profile domain.UserProfile
+func (_e *ProfileServiceMock_Expecter) UpdateProfile(ctx interface{}, userID interface{}, profile interface{}) *ProfileServiceMock_UpdateProfile_Call {
+	return &ProfileServiceMock_UpdateProfile_Call{Call: _e.mock.On("UpdateProfile", ctx, userID, profile)}
+}
+
+func (_c *ProfileServiceMock_UpdateProfile_Call) Run(run func(ctx context.Context, userID int64, profile domain.UserProfile)) *ProfileServiceMock_UpdateProfile_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(domain.UserProfile))
+	})
+	return _c
+}
+
+func (_c *ProfileServiceMock_UpdateProfile_Call) Return(_a0 *domain.UserProfile, _a1 error) *ProfileServiceMock_UpdateProfile_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ProfileServiceMock_UpdateProfile_Call) RunAndReturn(run func(context.Context, int64, domain.UserProfile) (*domain.UserProfile, error)) *ProfileServiceMock_UpdateProfile_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewProfileServiceMock creates a new instance of ProfileServiceMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewProfileServiceMock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ProfileServiceMock {
+	mock := &ProfileServiceMock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,243 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// RefreshTokenRepositoryMock is an autogenerated mock type for the RefreshTokenRepository type
+type RefreshTokenRepositoryMock struct {
+	mock.Mock
+}
+
+type RefreshTokenRepositoryMock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *RefreshTokenRepositoryMock) EXPECT() *RefreshTokenRepositoryMock_Expecter {
+	return &RefreshTokenRepositoryMock_Expecter{mock: &_m.Mock}
+}
+
+// CreateRefreshToken provides a mock function with given fields: ctx, userID, id, device, ttl
+func (_m *RefreshTokenRepositoryMock) CreateRefreshToken(ctx context.Context, userID int64, id string, device string, ttl time.Duration) error {
+	ret := _m.Called(ctx, userID, id, device, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateRefreshToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string, time.Duration) error); ok {
+		r0 = rf(ctx, userID, id, device, ttl)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RefreshTokenRepositoryMock_CreateRefreshToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateRefreshToken'
+type RefreshTokenRepositoryMock_CreateRefreshToken_Call struct {
+	*mock.Call
+}
+
+// CreateRefreshToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - id string
+//   - device string
+//   - ttl time.Duration
+func (_e *RefreshTokenRepositoryMock_Expecter) CreateRefreshToken(ctx interface{}, userID interface{}, id interface{}, device interface{}, ttl interface{}) *RefreshTokenRepositoryMock_CreateRefreshToken_Call {
+	return &RefreshTokenRepositoryMock_CreateRefreshToken_Call{Call: _e.mock.On("CreateRefreshToken", ctx, userID, id, device, ttl)}
+}
+
+func (_c *RefreshTokenRepositoryMock_CreateRefreshToken_Call) Run(run func(ctx context.Context, userID int64, id string, device string, ttl time.Duration)) *RefreshTokenRepositoryMock_CreateRefreshToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(string), args[3].(string), args[4].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *RefreshTokenRepositoryMock_CreateRefreshToken_Call) Return(_a0 error) *RefreshTokenRepositoryMock_CreateRefreshToken_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *RefreshTokenRepositoryMock_CreateRefreshToken_Call) RunAndReturn(run func(context.Context, int64, string, string, time.Duration) error) *RefreshTokenRepositoryMock_CreateRefreshToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteExpiredRefreshTokens provides a mock function with given fields: ctx, limit
+func (_m *RefreshTokenRepositoryMock) DeleteExpiredRefreshTokens(ctx context.Context, limit int) (int, error) {
+	ret := _m.Called(ctx, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteExpiredRefreshTokens")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (int, error)); ok {
+		return rf(ctx, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) int); ok {
+		r0 = rf(ctx, limit)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RefreshTokenRepositoryMock_DeleteExpiredRefreshTokens_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteExpiredRefreshTokens'
+type RefreshTokenRepositoryMock_DeleteExpiredRefreshTokens_Call struct {
+	*mock.Call
+}
+
+// DeleteExpiredRefreshTokens is a helper method to define mock.On call
+//   - ctx context.Context
+//   - limit int
+func (_e *RefreshTokenRepositoryMock_Expecter) DeleteExpiredRefreshTokens(ctx interface{}, limit interface{}) *RefreshTokenRepositoryMock_DeleteExpiredRefreshTokens_Call {
+	return &RefreshTokenRepositoryMock_DeleteExpiredRefreshTokens_Call{Call: _e.mock.On("DeleteExpiredRefreshTokens", ctx, limit)}
+}
+
+func (_c *RefreshTokenRepositoryMock_DeleteExpiredRefreshTokens_Call) Run(run func(ctx context.Context, limit int)) *RefreshTokenRepositoryMock_DeleteExpiredRefreshTokens_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *RefreshTokenRepositoryMock_DeleteExpiredRefreshTokens_Call) Return(_a0 int, _a1 error) *RefreshTokenRepositoryMock_DeleteExpiredRefreshTokens_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *RefreshTokenRepositoryMock_DeleteExpiredRefreshTokens_Call) RunAndReturn(run func(context.Context, int) (int, error)) *RefreshTokenRepositoryMock_DeleteExpiredRefreshTokens_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeRefreshTokens provides a mock function with given fields: ctx, userID
+func (_m *RefreshTokenRepositoryMock) RevokeRefreshTokens(ctx context.Context, userID int64) error {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeRefreshTokens")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RefreshTokenRepositoryMock_RevokeRefreshTokens_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeRefreshTokens'
+type RefreshTokenRepositoryMock_RevokeRefreshTokens_Call struct {
+	*mock.Call
+}
+
+// RevokeRefreshTokens is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+func (_e *RefreshTokenRepositoryMock_Expecter) RevokeRefreshTokens(ctx interface{}, userID interface{}) *RefreshTokenRepositoryMock_RevokeRefreshTokens_Call {
+	return &RefreshTokenRepositoryMock_RevokeRefreshTokens_Call{Call: _e.mock.On("RevokeRefreshTokens", ctx, userID)}
+}
+
+func (_c *RefreshTokenRepositoryMock_RevokeRefreshTokens_Call) Run(run func(ctx context.Context, userID int64)) *RefreshTokenRepositoryMock_RevokeRefreshTokens_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *RefreshTokenRepositoryMock_RevokeRefreshTokens_Call) Return(_a0 error) *RefreshTokenRepositoryMock_RevokeRefreshTokens_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *RefreshTokenRepositoryMock_RevokeRefreshTokens_Call) RunAndReturn(run func(context.Context, int64) error) *RefreshTokenRepositoryMock_RevokeRefreshTokens_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RotateRefreshToken provides a mock function with given fields: ctx, userID, id, nextID, device, ttl
+func (_m *RefreshTokenRepositoryMock) RotateRefreshToken(ctx context.Context, userID int64, id string, nextID string, device string, ttl time.Duration) error {
+	ret := _m.Called(ctx, userID, id, nextID, device, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RotateRefreshToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string, string, time.Duration) error); ok {
+		r0 = rf(ctx, userID, id, nextID, device, ttl)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RefreshTokenRepositoryMock_RotateRefreshToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RotateRefreshToken'
+type RefreshTokenRepositoryMock_RotateRefreshToken_Call struct {
+	*mock.Call
+}
+
+// RotateRefreshToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - id string
+//   - nextID string
+//   - device string
+//   - ttl time.Duration
+func (_e *RefreshTokenRepositoryMock_Expecter) RotateRefreshToken(ctx interface{}, userID interface{}, id interface{}, nextID interface{}, device interface{}, ttl interface{}) *RefreshTokenRepositoryMock_RotateRefreshToken_Call {
+	return &RefreshTokenRepositoryMock_RotateRefreshToken_Call{Call: _e.mock.On("RotateRefreshToken", ctx, userID, id, nextID, device, ttl)}
+}
+
+func (_c *RefreshTokenRepositoryMock_RotateRefreshToken_Call) Run(run func(ctx context.Context, userID int64, id string, nextID string, device string, ttl time.Duration)) *RefreshTokenRepositoryMock_RotateRefreshToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(string), args[3].(string), args[4].(string), args[5].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *RefreshTokenRepositoryMock_RotateRefreshToken_Call) Return(_a0 error) *RefreshTokenRepositoryMock_RotateRefreshToken_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *RefreshTokenRepositoryMock_RotateRefreshToken_Call) RunAndReturn(run func(context.Context, int64, string, string, string, time.Duration) error) *RefreshTokenRepositoryMock_RotateRefreshToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewRefreshTokenRepositoryMock creates a new instance of RefreshTokenRepositoryMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRefreshTokenRepositoryMock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RefreshTokenRepositoryMock {
+	mock := &RefreshTokenRepositoryMock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,144 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// RevokedTokenRepositoryMock is an autogenerated mock type for the RevokedTokenRepository type
+type RevokedTokenRepositoryMock struct {
+	mock.Mock
+}
+
+type RevokedTokenRepositoryMock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *RevokedTokenRepositoryMock) EXPECT() *RevokedTokenRepositoryMock_Expecter {
+	return &RevokedTokenRepositoryMock_Expecter{mock: &_m.Mock}
+}
+
+// IsTokenRevoked provides a mock function with given fields: ctx, tokenHash
+func (_m *RevokedTokenRepositoryMock) IsTokenRevoked(ctx context.Context, tokenHash string) (bool, error) {
+	ret := _m.Called(ctx, tokenHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsTokenRevoked")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
+		return rf(ctx, tokenHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(ctx, tokenHash)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, tokenHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokedTokenRepositoryMock_IsTokenRevoked_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IsTokenRevoked'
+type RevokedTokenRepositoryMock_IsTokenRevoked_Call struct {
+	*mock.Call
+}
+
+// IsTokenRevoked is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tokenHash string
+func (_e *RevokedTokenRepositoryMock_Expecter) IsTokenRevoked(ctx interface{}, tokenHash interface{}) *RevokedTokenRepositoryMock_IsTokenRevoked_Call {
+	return &RevokedTokenRepositoryMock_IsTokenRevoked_Call{Call: _e.mock.On("IsTokenRevoked", ctx, tokenHash)}
+}
+
+func (_c *RevokedTokenRepositoryMock_IsTokenRevoked_Call) Run(run func(ctx context.Context, tokenHash string)) *RevokedTokenRepositoryMock_IsTokenRevoked_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *RevokedTokenRepositoryMock_IsTokenRevoked_Call) Return(_a0 bool, _a1 error) *RevokedTokenRepositoryMock_IsTokenRevoked_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *RevokedTokenRepositoryMock_IsTokenRevoked_Call) RunAndReturn(run func(context.Context, string) (bool, error)) *RevokedTokenRepositoryMock_IsTokenRevoked_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeToken provides a mock function with given fields: ctx, userID, tokenHash, ttl
+func (_m *RevokedTokenRepositoryMock) RevokeToken(ctx context.Context, userID int64, tokenHash string, ttl time.Duration) error {
+	ret := _m.Called(ctx, userID, tokenHash, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, time.Duration) error); ok {
+		r0 = rf(ctx, userID, tokenHash, ttl)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokedTokenRepositoryMock_RevokeToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeToken'
+type RevokedTokenRepositoryMock_RevokeToken_Call struct {
+	*mock.Call
+}
+
+// RevokeToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - tokenHash string
+//   - ttl time.Duration
+func (_e *RevokedTokenRepositoryMock_Expecter) RevokeToken(ctx interface{}, userID interface{}, tokenHash interface{}, ttl interface{}) *RevokedTokenRepositoryMock_RevokeToken_Call {
+	return &RevokedTokenRepositoryMock_RevokeToken_Call{Call: _e.mock.On("RevokeToken", ctx, userID, tokenHash, ttl)}
+}
+
+func (_c *RevokedTokenRepositoryMock_RevokeToken_Call) Run(run func(ctx context.Context, userID int64, tokenHash string, ttl time.Duration)) *RevokedTokenRepositoryMock_RevokeToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(string), args[3].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *RevokedTokenRepositoryMock_RevokeToken_Call) Return(_a0 error) *RevokedTokenRepositoryMock_RevokeToken_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *RevokedTokenRepositoryMock_RevokeToken_Call) RunAndReturn(run func(context.Context, int64, string, time.Duration) error) *RevokedTokenRepositoryMock_RevokeToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewRevokedTokenRepositoryMock creates a new instance of RevokedTokenRepositoryMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRevokedTokenRepositoryMock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RevokedTokenRepositoryMock {
+	mock := &RevokedTokenRepositoryMock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,155 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/avc/loyalty-system-diploma/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RewardRuleRepositoryMock is an autogenerated mock type for the RewardRuleRepository type
+type RewardRuleRepositoryMock struct {
+	mock.Mock
+}
+
+type RewardRuleRepositoryMock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *RewardRuleRepositoryMock) EXPECT() *RewardRuleRepositoryMock_Expecter {
+	return &RewardRuleRepositoryMock_Expecter{mock: &_m.Mock}
+}
+
+// ListRewardRules provides a mock function with given fields: ctx
+func (_m *RewardRuleRepositoryMock) ListRewardRules(ctx context.Context) (*domain.RewardRuleSet, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListRewardRules")
+	}
+
+	var r0 *domain.RewardRuleSet
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*domain.RewardRuleSet, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *domain.RewardRuleSet); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.RewardRuleSet)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RewardRuleRepositoryMock_ListRewardRules_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListRewardRules'
+type RewardRuleRepositoryMock_ListRewardRules_Call struct {
+	*mock.Call
+}
+
+// ListRewardRules is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *RewardRuleRepositoryMock_Expecter) ListRewardRules(ctx interface{}) *RewardRuleRepositoryMock_ListRewardRules_Call {
+	return &RewardRuleRepositoryMock_ListRewardRules_Call{Call: _e.mock.On("ListRewardRules", ctx)}
+}
+
+func (_c *RewardRuleRepositoryMock_ListRewardRules_Call) Run(run func(ctx context.Context)) *RewardRuleRepositoryMock_ListRewardRules_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *RewardRuleRepositoryMock_ListRewardRules_Call) Return(_a0 *domain.RewardRuleSet, _a1 error) *RewardRuleRepositoryMock_ListRewardRules_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *RewardRuleRepositoryMock_ListRewardRules_Call) RunAndReturn(run func(context.Context) (*domain.RewardRuleSet, error)) *RewardRuleRepositoryMock_ListRewardRules_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SyncRewardRules provides a mock function with given fields: ctx, rules, fingerprint
+func (_m *RewardRuleRepositoryMock) SyncRewardRules(ctx context.Context, rules []domain.RewardRule, fingerprint string) (*domain.RewardRuleSync, error) {
+	ret := _m.Called(ctx, rules, fingerprint)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SyncRewardRules")
+	}
+
+	var r0 *domain.RewardRuleSync
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []domain.RewardRule, string) (*domain.RewardRuleSync, error)); ok {
+		return rf(ctx, rules, fingerprint)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []domain.RewardRule, string) *domain.RewardRuleSync); ok {
+		r0 = rf(ctx, rules, fingerprint)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.RewardRuleSync)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []domain.RewardRule, string) error); ok {
+		r1 = rf(ctx, rules, fingerprint)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RewardRuleRepositoryMock_SyncRewardRules_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SyncRewardRules'
+type RewardRuleRepositoryMock_SyncRewardRules_Call struct {
+	*mock.Call
+}
+
+// SyncRewardRules is a helper method to define mock.On call
+//   - ctx context.Context
+//   - rules []domain.RewardRule
+//   - fingerprint string
+func (_e *RewardRuleRepositoryMock_Expecter) SyncRewardRules(ctx interface{}, rules interface{}, fingerprint interface{}) *RewardRuleRepositoryMock_SyncRewardRules_Call {
+	return &RewardRuleRepositoryMock_SyncRewardRules_Call{Call: _e.mock.On("SyncRewardRules", ctx, rules, fingerprint)}
+}
+
+func (_c *RewardRuleRepositoryMock_SyncRewardRules_Call) Run(run func(ctx context.Context, rules []domain.RewardRule, fingerprint string)) *RewardRuleRepositoryMock_SyncRewardRules_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]domain.RewardRule), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *RewardRuleRepositoryMock_SyncRewardRules_Call) Return(_a0 *domain.RewardRuleSync, _a1 error) *RewardRuleRepositoryMock_SyncRewardRules_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *RewardRuleRepositoryMock_SyncRewardRules_Call) RunAndReturn(run func(context.Context, []domain.RewardRule, string) (*domain.RewardRuleSync, error)) *RewardRuleRepositoryMock_SyncRewardRules_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewRewardRuleRepositoryMock creates a new instance of RewardRuleRepositoryMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRewardRuleRepositoryMock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RewardRuleRepositoryMock {
+	mock := &RewardRuleRepositoryMock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,96 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/avc/loyalty-system-diploma/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RewardRuleServiceMock is an autogenerated mock type for the RewardRuleService type
+type RewardRuleServiceMock struct {
+	mock.Mock
+}
+
+type RewardRuleServiceMock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *RewardRuleServiceMock) EXPECT() *RewardRuleServiceMock_Expecter {
+	return &RewardRuleServiceMock_Expecter{mock: &_m.Mock}
+}
+
+// ListRewardRules provides a mock function with given fields: ctx
+func (_m *RewardRuleServiceMock) ListRewardRules(ctx context.Context) (*domain.RewardRuleSet, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListRewardRules")
+	}
+
+	var r0 *domain.RewardRuleSet
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*domain.RewardRuleSet, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *domain.RewardRuleSet); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.RewardRuleSet)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RewardRuleServiceMock_ListRewardRules_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListRewardRules'
+type RewardRuleServiceMock_ListRewardRules_Call struct {
+	*mock.Call
+}
+
+// ListRewardRules is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *RewardRuleServiceMock_Expecter) ListRewardRules(ctx interface{}) *RewardRuleServiceMock_ListRewardRules_Call {
+	return &RewardRuleServiceMock_ListRewardRules_Call{Call: _e.mock.On("ListRewardRules", ctx)}
+}
+
+func (_c *RewardRuleServiceMock_ListRewardRules_Call) Run(run func(ctx context.Context)) *RewardRuleServiceMock_ListRewardRules_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *RewardRuleServiceMock_ListRewardRules_Call) Return(_a0 *domain.RewardRuleSet, _a1 error) *RewardRuleServiceMock_ListRewardRules_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *RewardRuleServiceMock_ListRewardRules_Call) RunAndReturn(run func(context.Context) (*domain.RewardRuleSet, error)) *RewardRuleServiceMock_ListRewardRules_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewRewardRuleServiceMock creates a new instance of RewardRuleServiceMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRewardRuleServiceMock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RewardRuleServiceMock {
+	mock := &RewardRuleServiceMock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,95 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/avc/loyalty-system-diploma/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RewardRuleSourceMock is an autogenerated mock type for the RewardRuleSource type
+type RewardRuleSourceMock struct {
+	mock.Mock
+}
+
+type RewardRuleSourceMock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *RewardRuleSourceMock) EXPECT() *RewardRuleSourceMock_Expecter {
+	return &RewardRuleSourceMock_Expecter{mock: &_m.Mock}
+}
+
+// GetRewardRules provides a mock function with given fields: ctx
+func (_m *RewardRuleSourceMock) GetRewardRules(ctx context.Context) ([]domain.RewardRule, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRewardRules")
+	}
+
+	var r0 []domain.RewardRule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]domain.RewardRule, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []domain.RewardRule); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.RewardRule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RewardRuleSourceMock_GetRewardRules_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRewardRules'
+type RewardRuleSourceMock_GetRewardRules_Call struct {
+	*mock.Call
+}
+
+// GetRewardRules is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *RewardRuleSourceMock_Expecter) GetRewardRules(ctx interface{}) *RewardRuleSourceMock_GetRewardRules_Call {
+	return &RewardRuleSourceMock_GetRewardRules_Call{Call: _e.mock.On("GetRewardRules", ctx)}
+}
+
+func (_c *RewardRuleSourceMock_GetRewardRules_Call) Run(run func(ctx context.Context)) *RewardRuleSourceMock_GetRewardRules_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *RewardRuleSourceMock_GetRewardRules_Call) Return(_a0 []domain.RewardRule, _a1 error) *RewardRuleSourceMock_GetRewardRules_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *RewardRuleSourceMock_GetRewardRules_Call) RunAndReturn(run func(context.Context) ([]domain.RewardRule, error)) *RewardRuleSourceMock_GetRewardRules_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewRewardRuleSourceMock creates a new instance of RewardRuleSourceMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRewardRuleSourceMock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RewardRuleSourceMock {
+	mock := &RewardRuleSourceMock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
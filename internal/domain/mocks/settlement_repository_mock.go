@@ -0,0 +1,276 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/avc/loyalty-system-diploma/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
+)
+
+// SettlementRepositoryMock is an autogenerated mock type for the SettlementRepository type
+type SettlementRepositoryMock struct {
+	mock.Mock
+}
+
+type SettlementRepositoryMock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *SettlementRepositoryMock) EXPECT() *SettlementRepositoryMock_Expecter {
+	return &SettlementRepositoryMock_Expecter{mock: &_m.Mock}
+}
+
+// CreateSettlement provides a mock function with given fields: ctx, createdBy
+func (_m *SettlementRepositoryMock) CreateSettlement(ctx context.Context, createdBy *int64) (*domain.Settlement, error) {
+	ret := _m.Called(ctx, createdBy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateSettlement")
+	}
+
+	var r0 *domain.Settlement
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *int64) (*domain.Settlement, error)); ok {
+		return rf(ctx, createdBy)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *int64) *domain.Settlement); ok {
+		r0 = rf(ctx, createdBy)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Settlement)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *int64) error); ok {
+		r1 = rf(ctx, createdBy)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SettlementRepositoryMock_CreateSettlement_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateSettlement'
+type SettlementRepositoryMock_CreateSettlement_Call struct {
+	*mock.Call
+}
+
+// CreateSettlement is a helper method to define mock.On call
+//   - ctx context.Context
+//   - createdBy *int64
+func (_e *SettlementRepositoryMock_Expecter) CreateSettlement(ctx interface{}, createdBy interface{}) *SettlementRepositoryMock_CreateSettlement_Call {
+	return &SettlementRepositoryMock_CreateSettlement_Call{Call: _e.mock.On("CreateSettlement", ctx, createdBy)}
+}
+
+func (_c *SettlementRepositoryMock_CreateSettlement_Call) Run(run func(ctx context.Context, createdBy *int64)) *SettlementRepositoryMock_CreateSettlement_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*int64))
+	})
+	return _c
+}
+
+func (_c *SettlementRepositoryMock_CreateSettlement_Call) Return(_a0 *domain.Settlement, _a1 error) *SettlementRepositoryMock_CreateSettlement_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *SettlementRepositoryMock_CreateSettlement_Call) RunAndReturn(run func(context.Context, *int64) (*domain.Settlement, error)) *SettlementRepositoryMock_CreateSettlement_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetSettlement provides a mock function with given fields: ctx, id
+func (_m *SettlementRepositoryMock) GetSettlement(ctx context.Context, id uuid.UUID) (*domain.Settlement, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSettlement")
+	}
+
+	var r0 *domain.Settlement
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*domain.Settlement, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *domain.Settlement); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Settlement)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SettlementRepositoryMock_GetSettlement_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSettlement'
+type SettlementRepositoryMock_GetSettlement_Call struct {
+	*mock.Call
+}
+
+// GetSettlement is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+func (_e *SettlementRepositoryMock_Expecter) GetSettlement(ctx interface{}, id interface{}) *SettlementRepositoryMock_GetSettlement_Call {
+	return &SettlementRepositoryMock_GetSettlement_Call{Call: _e.mock.On("GetSettlement", ctx, id)}
+}
+
+func (_c *SettlementRepositoryMock_GetSettlement_Call) Run(run func(ctx context.Context, id uuid.UUID)) *SettlementRepositoryMock_GetSettlement_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *SettlementRepositoryMock_GetSettlement_Call) Return(_a0 *domain.Settlement, _a1 error) *SettlementRepositoryMock_GetSettlement_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *SettlementRepositoryMock_GetSettlement_Call) RunAndReturn(run func(context.Context, uuid.UUID) (*domain.Settlement, error)) *SettlementRepositoryMock_GetSettlement_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetSettlementLines provides a mock function with given fields: ctx, id
+func (_m *SettlementRepositoryMock) GetSettlementLines(ctx context.Context, id uuid.UUID) ([]*domain.SettlementLine, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSettlementLines")
+	}
+
+	var r0 []*domain.SettlementLine
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]*domain.SettlementLine, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []*domain.SettlementLine); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.SettlementLine)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SettlementRepositoryMock_GetSettlementLines_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSettlementLines'
+type SettlementRepositoryMock_GetSettlementLines_Call struct {
+	*mock.Call
+}
+
+// GetSettlementLines is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+func (_e *SettlementRepositoryMock_Expecter) GetSettlementLines(ctx interface{}, id interface{}) *SettlementRepositoryMock_GetSettlementLines_Call {
+	return &SettlementRepositoryMock_GetSettlementLines_Call{Call: _e.mock.On("GetSettlementLines", ctx, id)}
+}
+
+func (_c *SettlementRepositoryMock_GetSettlementLines_Call) Run(run func(ctx context.Context, id uuid.UUID)) *SettlementRepositoryMock_GetSettlementLines_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *SettlementRepositoryMock_GetSettlementLines_Call) Return(_a0 []*domain.SettlementLine, _a1 error) *SettlementRepositoryMock_GetSettlementLines_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *SettlementRepositoryMock_GetSettlementLines_Call) RunAndReturn(run func(context.Context, uuid.UUID) ([]*domain.SettlementLine, error)) *SettlementRepositoryMock_GetSettlementLines_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListSettlements provides a mock function with given fields: ctx, limit, offset
+func (_m *SettlementRepositoryMock) ListSettlements(ctx context.Context, limit int, offset int) ([]*domain.Settlement, error) {
+	ret := _m.Called(ctx, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListSettlements")
+	}
+
+	var r0 []*domain.Settlement
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]*domain.Settlement, error)); ok {
+		return rf(ctx, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []*domain.Settlement); ok {
+		r0 = rf(ctx, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Settlement)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SettlementRepositoryMock_ListSettlements_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListSettlements'
+type SettlementRepositoryMock_ListSettlements_Call struct {
+	*mock.Call
+}
+
+// ListSettlements is a helper method to define mock.On call
+//   - ctx context.Context
+//   - limit int
+//   - offset int
+func (_e *SettlementRepositoryMock_Expecter) ListSettlements(ctx interface{}, limit interface{}, offset interface{}) *SettlementRepositoryMock_ListSettlements_Call {
+	return &SettlementRepositoryMock_ListSettlements_Call{Call: _e.mock.On("ListSettlements", ctx, limit, offset)}
+}
+
+func (_c *SettlementRepositoryMock_ListSettlements_Call) Run(run func(ctx context.Context, limit int, offset int)) *SettlementRepositoryMock_ListSettlements_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *SettlementRepositoryMock_ListSettlements_Call) Return(_a0 []*domain.Settlement, _a1 error) *SettlementRepositoryMock_ListSettlements_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *SettlementRepositoryMock_ListSettlements_Call) RunAndReturn(run func(context.Context, int, int) ([]*domain.Settlement, error)) *SettlementRepositoryMock_ListSettlements_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewSettlementRepositoryMock creates a new instance of SettlementRepositoryMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSettlementRepositoryMock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SettlementRepositoryMock {
+	mock := &SettlementRepositoryMock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,227 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/avc/loyalty-system-diploma/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
+)
+
+// SettlementsServiceMock is an autogenerated mock type for the SettlementsService type
+type SettlementsServiceMock struct {
+	mock.Mock
+}
+
+type SettlementsServiceMock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *SettlementsServiceMock) EXPECT() *SettlementsServiceMock_Expecter {
+	return &SettlementsServiceMock_Expecter{mock: &_m.Mock}
+}
+
+// CreateSettlement provides a mock function with given fields: ctx, createdBy
+func (_m *SettlementsServiceMock) CreateSettlement(ctx context.Context, createdBy *int64) (*domain.Settlement, error) {
+	ret := _m.Called(ctx, createdBy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateSettlement")
+	}
+
+	var r0 *domain.Settlement
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *int64) (*domain.Settlement, error)); ok {
+		return rf(ctx, createdBy)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *int64) *domain.Settlement); ok {
+		r0 = rf(ctx, createdBy)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Settlement)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *int64) error); ok {
+		r1 = rf(ctx, createdBy)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SettlementsServiceMock_CreateSettlement_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateSettlement'
+type SettlementsServiceMock_CreateSettlement_Call struct {
+	*mock.Call
+}
+
+// CreateSettlement is a helper method to define mock.On call
+//   - ctx context.Context
+//   - createdBy *int64
+func (_e *SettlementsServiceMock_Expecter) CreateSettlement(ctx interface{}, createdBy interface{}) *SettlementsServiceMock_CreateSettlement_Call {
+	return &SettlementsServiceMock_CreateSettlement_Call{Call: _e.mock.On("CreateSettlement", ctx, createdBy)}
+}
+
+func (_c *SettlementsServiceMock_CreateSettlement_Call) Run(run func(ctx context.Context, createdBy *int64)) *SettlementsServiceMock_CreateSettlement_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*int64))
+	})
+	return _c
+}
+
+func (_c *SettlementsServiceMock_CreateSettlement_Call) Return(_a0 *domain.Settlement, _a1 error) *SettlementsServiceMock_CreateSettlement_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *SettlementsServiceMock_CreateSettlement_Call) RunAndReturn(run func(context.Context, *int64) (*domain.Settlement, error)) *SettlementsServiceMock_CreateSettlement_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetSettlementFile provides a mock function with given fields: ctx, id
+func (_m *SettlementsServiceMock) GetSettlementFile(ctx context.Context, id uuid.UUID) (*domain.Settlement, []*domain.SettlementLine, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSettlementFile")
+	}
+
+	var r0 *domain.Settlement
+	var r1 []*domain.SettlementLine
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*domain.Settlement, []*domain.SettlementLine, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *domain.Settlement); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Settlement)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) []*domain.SettlementLine); ok {
+		r1 = rf(ctx, id)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]*domain.SettlementLine)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, uuid.UUID) error); ok {
+		r2 = rf(ctx, id)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// SettlementsServiceMock_GetSettlementFile_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSettlementFile'
+type SettlementsServiceMock_GetSettlementFile_Call struct {
+	*mock.Call
+}
+
+// GetSettlementFile is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+func (_e *SettlementsServiceMock_Expecter) GetSettlementFile(ctx interface{}, id interface{}) *SettlementsServiceMock_GetSettlementFile_Call {
+	return &SettlementsServiceMock_GetSettlementFile_Call{Call: _e.mock.On("GetSettlementFile", ctx, id)}
+}
+
+func (_c *SettlementsServiceMock_GetSettlementFile_Call) Run(run func(ctx context.Context, id uuid.UUID)) *SettlementsServiceMock_GetSettlementFile_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *SettlementsServiceMock_GetSettlementFile_Call) Return(_a0 *domain.Settlement, _a1 []*domain.SettlementLine, _a2 error) *SettlementsServiceMock_GetSettlementFile_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *SettlementsServiceMock_GetSettlementFile_Call) RunAndReturn(run func(context.Context, uuid.UUID) (*domain.Settlement, []*domain.SettlementLine, error)) *SettlementsServiceMock_GetSettlementFile_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListSettlements provides a mock function with given fields: ctx, limit, offset
+func (_m *SettlementsServiceMock) ListSettlements(ctx context.Context, limit int, offset int) (*domain.SettlementPage, error) {
+	ret := _m.Called(ctx, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListSettlements")
+	}
+
+	var r0 *domain.SettlementPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (*domain.SettlementPage, error)); ok {
+		return rf(ctx, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) *domain.SettlementPage); ok {
+		r0 = rf(ctx, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.SettlementPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SettlementsServiceMock_ListSettlements_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListSettlements'
+type SettlementsServiceMock_ListSettlements_Call struct {
+	*mock.Call
+}
+
+// ListSettlements is a helper method to define mock.On call
+//   - ctx context.Context
+//   - limit int
+//   - offset int
+func (_e *SettlementsServiceMock_Expecter) ListSettlements(ctx interface{}, limit interface{}, offset interface{}) *SettlementsServiceMock_ListSettlements_Call {
+	return &SettlementsServiceMock_ListSettlements_Call{Call: _e.mock.On("ListSettlements", ctx, limit, offset)}
+}
+
+func (_c *SettlementsServiceMock_ListSettlements_Call) Run(run func(ctx context.Context, limit int, offset int)) *SettlementsServiceMock_ListSettlements_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *SettlementsServiceMock_ListSettlements_Call) Return(_a0 *domain.SettlementPage, _a1 error) *SettlementsServiceMock_ListSettlements_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *SettlementsServiceMock_ListSettlements_Call) RunAndReturn(run func(context.Context, int, int) (*domain.SettlementPage, error)) *SettlementsServiceMock_ListSettlements_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewSettlementsServiceMock creates a new instance of SettlementsServiceMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSettlementsServiceMock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SettlementsServiceMock {
+	mock := &SettlementsServiceMock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
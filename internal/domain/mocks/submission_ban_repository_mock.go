@@ -0,0 +1,264 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/avc/loyalty-system-diploma/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// SubmissionBanRepositoryMock is an autogenerated mock type for the SubmissionBanRepository type
+type SubmissionBanRepositoryMock struct {
+	mock.Mock
+}
+
+type SubmissionBanRepositoryMock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *SubmissionBanRepositoryMock) EXPECT() *SubmissionBanRepositoryMock_Expecter {
+	return &SubmissionBanRepositoryMock_Expecter{mock: &_m.Mock}
+}
+
+// BanSubmissions provides a mock function with given fields: ctx, userID, reason, duration
+func (_m *SubmissionBanRepositoryMock) BanSubmissions(ctx context.Context, userID int64, reason string, duration time.Duration) (*domain.SubmissionBan, error) {
+	ret := _m.Called(ctx, userID, reason, duration)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BanSubmissions")
+	}
+
+	var r0 *domain.SubmissionBan
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, time.Duration) (*domain.SubmissionBan, error)); ok {
+		return rf(ctx, userID, reason, duration)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, time.Duration) *domain.SubmissionBan); ok {
+		r0 = rf(ctx, userID, reason, duration)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.SubmissionBan)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string, time.Duration) error); ok {
+		r1 = rf(ctx, userID, reason, duration)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SubmissionBanRepositoryMock_BanSubmissions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BanSubmissions'
+type SubmissionBanRepositoryMock_BanSubmissions_Call struct {
+	*mock.Call
+}
+
+// BanSubmissions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - reason string
+//   - duration time.Duration
+func (_e *SubmissionBanRepositoryMock_Expecter) BanSubmissions(ctx interface{}, userID interface{}, reason interface{}, duration interface{}) *SubmissionBanRepositoryMock_BanSubmissions_Call {
+	return &SubmissionBanRepositoryMock_BanSubmissions_Call{Call: _e.mock.On("BanSubmissions", ctx, userID, reason, duration)}
+}
+
+func (_c *SubmissionBanRepositoryMock_BanSubmissions_Call) Run(run func(ctx context.Context, userID int64, reason string, duration time.Duration)) *SubmissionBanRepositoryMock_BanSubmissions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(string), args[3].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *SubmissionBanRepositoryMock_BanSubmissions_Call) Return(_a0 *domain.SubmissionBan, _a1 error) *SubmissionBanRepositoryMock_BanSubmissions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *SubmissionBanRepositoryMock_BanSubmissions_Call) RunAndReturn(run func(context.Context, int64, string, time.Duration) (*domain.SubmissionBan, error)) *SubmissionBanRepositoryMock_BanSubmissions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetSubmissionBan provides a mock function with given fields: ctx, userID
+func (_m *SubmissionBanRepositoryMock) GetSubmissionBan(ctx context.Context, userID int64) (*domain.SubmissionBan, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSubmissionBan")
+	}
+
+	var r0 *domain.SubmissionBan
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*domain.SubmissionBan, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *domain.SubmissionBan); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.SubmissionBan)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SubmissionBanRepositoryMock_GetSubmissionBan_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSubmissionBan'
+type SubmissionBanRepositoryMock_GetSubmissionBan_Call struct {
+	*mock.Call
+}
+
+// GetSubmissionBan is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+func (_e *SubmissionBanRepositoryMock_Expecter) GetSubmissionBan(ctx interface{}, userID interface{}) *SubmissionBanRepositoryMock_GetSubmissionBan_Call {
+	return &SubmissionBanRepositoryMock_GetSubmissionBan_Call{Call: _e.mock.On("GetSubmissionBan", ctx, userID)}
+}
+
+func (_c *SubmissionBanRepositoryMock_GetSubmissionBan_Call) Run(run func(ctx context.Context, userID int64)) *SubmissionBanRepositoryMock_GetSubmissionBan_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *SubmissionBanRepositoryMock_GetSubmissionBan_Call) Return(_a0 *domain.SubmissionBan, _a1 error) *SubmissionBanRepositoryMock_GetSubmissionBan_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *SubmissionBanRepositoryMock_GetSubmissionBan_Call) RunAndReturn(run func(context.Context, int64) (*domain.SubmissionBan, error)) *SubmissionBanRepositoryMock_GetSubmissionBan_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// LiftSubmissionBan provides a mock function with given fields: ctx, login
+func (_m *SubmissionBanRepositoryMock) LiftSubmissionBan(ctx context.Context, login string) error {
+	ret := _m.Called(ctx, login)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LiftSubmissionBan")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, login)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SubmissionBanRepositoryMock_LiftSubmissionBan_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LiftSubmissionBan'
+type SubmissionBanRepositoryMock_LiftSubmissionBan_Call struct {
+	*mock.Call
+}
+
+// LiftSubmissionBan is a helper method to define mock.On call
+//   - ctx context.Context
+//   - login string
+func (_e *SubmissionBanRepositoryMock_Expecter) LiftSubmissionBan(ctx interface{}, login interface{}) *SubmissionBanRepositoryMock_LiftSubmissionBan_Call {
+	return &SubmissionBanRepositoryMock_LiftSubmissionBan_Call{Call: _e.mock.On("LiftSubmissionBan", ctx, login)}
+}
+
+func (_c *SubmissionBanRepositoryMock_LiftSubmissionBan_Call) Run(run func(ctx context.Context, login string)) *SubmissionBanRepositoryMock_LiftSubmissionBan_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *SubmissionBanRepositoryMock_LiftSubmissionBan_Call) Return(_a0 error) *SubmissionBanRepositoryMock_LiftSubmissionBan_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *SubmissionBanRepositoryMock_LiftSubmissionBan_Call) RunAndReturn(run func(context.Context, string) error) *SubmissionBanRepositoryMock_LiftSubmissionBan_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListSubmissionBans provides a mock function with given fields: ctx
+func (_m *SubmissionBanRepositoryMock) ListSubmissionBans(ctx context.Context) ([]*domain.SubmissionBan, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListSubmissionBans")
+	}
+
+	var r0 []*domain.SubmissionBan
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*domain.SubmissionBan, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*domain.SubmissionBan); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.SubmissionBan)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SubmissionBanRepositoryMock_ListSubmissionBans_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListSubmissionBans'
+type SubmissionBanRepositoryMock_ListSubmissionBans_Call struct {
+	*mock.Call
+}
+
+// ListSubmissionBans is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *SubmissionBanRepositoryMock_Expecter) ListSubmissionBans(ctx interface{}) *SubmissionBanRepositoryMock_ListSubmissionBans_Call {
+	return &SubmissionBanRepositoryMock_ListSubmissionBans_Call{Call: _e.mock.On("ListSubmissionBans", ctx)}
+}
+
+func (_c *SubmissionBanRepositoryMock_ListSubmissionBans_Call) Run(run func(ctx context.Context)) *SubmissionBanRepositoryMock_ListSubmissionBans_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *SubmissionBanRepositoryMock_ListSubmissionBans_Call) Return(_a0 []*domain.SubmissionBan, _a1 error) *SubmissionBanRepositoryMock_ListSubmissionBans_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *SubmissionBanRepositoryMock_ListSubmissionBans_Call) RunAndReturn(run func(context.Context) ([]*domain.SubmissionBan, error)) *SubmissionBanRepositoryMock_ListSubmissionBans_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewSubmissionBanRepositoryMock creates a new instance of SubmissionBanRepositoryMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSubmissionBanRepositoryMock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SubmissionBanRepositoryMock {
+	mock := &SubmissionBanRepositoryMock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,143 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/avc/loyalty-system-diploma/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// SubmissionBanServiceMock is an autogenerated mock type for the SubmissionBanService type
+type SubmissionBanServiceMock struct {
+	mock.Mock
+}
+
+type SubmissionBanServiceMock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *SubmissionBanServiceMock) EXPECT() *SubmissionBanServiceMock_Expecter {
+	return &SubmissionBanServiceMock_Expecter{mock: &_m.Mock}
+}
+
+// LiftSubmissionBan provides a mock function with given fields: ctx, login
+func (_m *SubmissionBanServiceMock) LiftSubmissionBan(ctx context.Context, login string) error {
+	ret := _m.Called(ctx, login)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LiftSubmissionBan")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, login)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SubmissionBanServiceMock_LiftSubmissionBan_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LiftSubmissionBan'
+type SubmissionBanServiceMock_LiftSubmissionBan_Call struct {
+	*mock.Call
+}
+
+// LiftSubmissionBan is a helper method to define mock.On call
+//   - ctx context.Context
+//   - login string
+func (_e *SubmissionBanServiceMock_Expecter) LiftSubmissionBan(ctx interface{}, login interface{}) *SubmissionBanServiceMock_LiftSubmissionBan_Call {
+	return &SubmissionBanServiceMock_LiftSubmissionBan_Call{Call: _e.mock.On("LiftSubmissionBan", ctx, login)}
+}
+
+func (_c *SubmissionBanServiceMock_LiftSubmissionBan_Call) Run(run func(ctx context.Context, login string)) *SubmissionBanServiceMock_LiftSubmissionBan_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *SubmissionBanServiceMock_LiftSubmissionBan_Call) Return(_a0 error) *SubmissionBanServiceMock_LiftSubmissionBan_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *SubmissionBanServiceMock_LiftSubmissionBan_Call) RunAndReturn(run func(context.Context, string) error) *SubmissionBanServiceMock_LiftSubmissionBan_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListSubmissionBans provides a mock function with given fields: ctx
+func (_m *SubmissionBanServiceMock) ListSubmissionBans(ctx context.Context) ([]*domain.SubmissionBan, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListSubmissionBans")
+	}
+
+	var r0 []*domain.SubmissionBan
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*domain.SubmissionBan, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*domain.SubmissionBan); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.SubmissionBan)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SubmissionBanServiceMock_ListSubmissionBans_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListSubmissionBans'
+type SubmissionBanServiceMock_ListSubmissionBans_Call struct {
+	*mock.Call
+}
+
+// ListSubmissionBans is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *SubmissionBanServiceMock_Expecter) ListSubmissionBans(ctx interface{}) *SubmissionBanServiceMock_ListSubmissionBans_Call {
+	return &SubmissionBanServiceMock_ListSubmissionBans_Call{Call: _e.mock.On("ListSubmissionBans", ctx)}
+}
+
+func (_c *SubmissionBanServiceMock_ListSubmissionBans_Call) Run(run func(ctx context.Context)) *SubmissionBanServiceMock_ListSubmissionBans_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *SubmissionBanServiceMock_ListSubmissionBans_Call) Return(_a0 []*domain.SubmissionBan, _a1 error) *SubmissionBanServiceMock_ListSubmissionBans_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *SubmissionBanServiceMock_ListSubmissionBans_Call) RunAndReturn(run func(context.Context) ([]*domain.SubmissionBan, error)) *SubmissionBanServiceMock_ListSubmissionBans_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewSubmissionBanServiceMock creates a new instance of SubmissionBanServiceMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSubmissionBanServiceMock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SubmissionBanServiceMock {
+	mock := &SubmissionBanServiceMock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,97 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/avc/loyalty-system-diploma/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TokenValidatorMock is an autogenerated mock type for the TokenValidator type
+type TokenValidatorMock struct {
+	mock.Mock
+}
+
+type TokenValidatorMock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *TokenValidatorMock) EXPECT() *TokenValidatorMock_Expecter {
+	return &TokenValidatorMock_Expecter{mock: &_m.Mock}
+}
+
+// ValidateToken provides a mock function with given fields: ctx, token
+func (_m *TokenValidatorMock) ValidateToken(ctx context.Context, token string) (*domain.AccessToken, error) {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ValidateToken")
+	}
+
+	var r0 *domain.AccessToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.AccessToken, error)); ok {
+		return rf(ctx, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.AccessToken); ok {
+		r0 = rf(ctx, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.AccessToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TokenValidatorMock_ValidateToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ValidateToken'
+type TokenValidatorMock_ValidateToken_Call struct {
+	*mock.Call
+}
+
+// ValidateToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - token string
+func (_e *TokenValidatorMock_Expecter) ValidateToken(ctx interface{}, token interface{}) *TokenValidatorMock_ValidateToken_Call {
+	return &TokenValidatorMock_ValidateToken_Call{Call: _e.mock.On("ValidateToken", ctx, token)}
+}
+
+func (_c *TokenValidatorMock_ValidateToken_Call) Run(run func(ctx context.Context, token string)) *TokenValidatorMock_ValidateToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *TokenValidatorMock_ValidateToken_Call) Return(_a0 *domain.AccessToken, _a1 error) *TokenValidatorMock_ValidateToken_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *TokenValidatorMock_ValidateToken_Call) RunAndReturn(run func(context.Context, string) (*domain.AccessToken, error)) *TokenValidatorMock_ValidateToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewTokenValidatorMock creates a new instance of TokenValidatorMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTokenValidatorMock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TokenValidatorMock {
+	mock := &TokenValidatorMock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
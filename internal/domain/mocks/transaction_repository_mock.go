@@ -7,6 +7,8 @@ import (
 
 	domain "github.com/avc/loyalty-system-diploma/internal/domain"
 	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
 )
 
 // TransactionRepositoryMock is an autogenerated mock type for the TransactionRepository type
@@ -131,12 +133,12 @@ func (_c *TransactionRepositoryMock_GetBalance_Call) RunAndReturn(run func(conte
 	return _c
 }
 
-// GetWithdrawals provides a mock function with given fields: ctx, userID
-func (_m *TransactionRepositoryMock) GetWithdrawals(ctx context.Context, userID int64) ([]*domain.Transaction, error) {
+// GetTransactions provides a mock function with given fields: ctx, userID
+func (_m *TransactionRepositoryMock) GetTransactions(ctx context.Context, userID int64) ([]*domain.Transaction, error) {
 	ret := _m.Called(ctx, userID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetWithdrawals")
+		panic("no return value specified for GetTransactions")
 	}
 
 	var r0 []*domain.Transaction
@@ -161,6 +163,184 @@ func (_m *TransactionRepositoryMock) GetWithdrawals(ctx context.Context, userID
 	return r0, r1
 }
 
+// TransactionRepositoryMock_GetTransactions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTransactions'
+type TransactionRepositoryMock_GetTransactions_Call struct {
+	*mock.Call
+}
+
+// GetTransactions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+func (_e *TransactionRepositoryMock_Expecter) GetTransactions(ctx interface{}, userID interface{}) *TransactionRepositoryMock_GetTransactions_Call {
+	return &TransactionRepositoryMock_GetTransactions_Call{Call: _e.mock.On("GetTransactions", ctx, userID)}
+}
+
+func (_c *TransactionRepositoryMock_GetTransactions_Call) Run(run func(ctx context.Context, userID int64)) *TransactionRepositoryMock_GetTransactions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *TransactionRepositoryMock_GetTransactions_Call) Return(_a0 []*domain.Transaction, _a1 error) *TransactionRepositoryMock_GetTransactions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *TransactionRepositoryMock_GetTransactions_Call) RunAndReturn(run func(context.Context, int64) ([]*domain.Transaction, error)) *TransactionRepositoryMock_GetTransactions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetWithdrawalByPublicID provides a mock function with given fields: ctx, userID, publicID
+func (_m *TransactionRepositoryMock) GetWithdrawalByPublicID(ctx context.Context, userID int64, publicID uuid.UUID) (*domain.Transaction, error) {
+	ret := _m.Called(ctx, userID, publicID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWithdrawalByPublicID")
+	}
+
+	var r0 *domain.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, uuid.UUID) (*domain.Transaction, error)); ok {
+		return rf(ctx, userID, publicID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, uuid.UUID) *domain.Transaction); ok {
+		r0 = rf(ctx, userID, publicID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID, publicID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TransactionRepositoryMock_GetWithdrawalByPublicID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetWithdrawalByPublicID'
+type TransactionRepositoryMock_GetWithdrawalByPublicID_Call struct {
+	*mock.Call
+}
+
+// GetWithdrawalByPublicID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - publicID uuid.UUID
+func (_e *TransactionRepositoryMock_Expecter) GetWithdrawalByPublicID(ctx interface{}, userID interface{}, publicID interface{}) *TransactionRepositoryMock_GetWithdrawalByPublicID_Call {
+	return &TransactionRepositoryMock_GetWithdrawalByPublicID_Call{Call: _e.mock.On("GetWithdrawalByPublicID", ctx, userID, publicID)}
+}
+
+func (_c *TransactionRepositoryMock_GetWithdrawalByPublicID_Call) Run(run func(ctx context.Context, userID int64, publicID uuid.UUID)) *TransactionRepositoryMock_GetWithdrawalByPublicID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *TransactionRepositoryMock_GetWithdrawalByPublicID_Call) Return(_a0 *domain.Transaction, _a1 error) *TransactionRepositoryMock_GetWithdrawalByPublicID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *TransactionRepositoryMock_GetWithdrawalByPublicID_Call) RunAndReturn(run func(context.Context, int64, uuid.UUID) (*domain.Transaction, error)) *TransactionRepositoryMock_GetWithdrawalByPublicID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetWithdrawalSummary provides a mock function with given fields: ctx, userID
+func (_m *TransactionRepositoryMock) GetWithdrawalSummary(ctx context.Context, userID int64) (*domain.WithdrawalSummary, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWithdrawalSummary")
+	}
+
+	var r0 *domain.WithdrawalSummary
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*domain.WithdrawalSummary, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *domain.WithdrawalSummary); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.WithdrawalSummary)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TransactionRepositoryMock_GetWithdrawalSummary_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetWithdrawalSummary'
+type TransactionRepositoryMock_GetWithdrawalSummary_Call struct {
+	*mock.Call
+}
+
+// GetWithdrawalSummary is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+func (_e *TransactionRepositoryMock_Expecter) GetWithdrawalSummary(ctx interface{}, userID interface{}) *TransactionRepositoryMock_GetWithdrawalSummary_Call {
+	return &TransactionRepositoryMock_GetWithdrawalSummary_Call{Call: _e.mock.On("GetWithdrawalSummary", ctx, userID)}
+}
+
+func (_c *TransactionRepositoryMock_GetWithdrawalSummary_Call) Run(run func(ctx context.Context, userID int64)) *TransactionRepositoryMock_GetWithdrawalSummary_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *TransactionRepositoryMock_GetWithdrawalSummary_Call) Return(_a0 *domain.WithdrawalSummary, _a1 error) *TransactionRepositoryMock_GetWithdrawalSummary_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *TransactionRepositoryMock_GetWithdrawalSummary_Call) RunAndReturn(run func(context.Context, int64) (*domain.WithdrawalSummary, error)) *TransactionRepositoryMock_GetWithdrawalSummary_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetWithdrawals provides a mock function with given fields: ctx, userID, after, limit
+func (_m *TransactionRepositoryMock) GetWithdrawals(ctx context.Context, userID int64, after *domain.WithdrawalCursor, limit int) ([]*domain.Transaction, error) {
+	ret := _m.Called(ctx, userID, after, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWithdrawals")
+	}
+
+	var r0 []*domain.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *domain.WithdrawalCursor, int) ([]*domain.Transaction, error)); ok {
+		return rf(ctx, userID, after, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *domain.WithdrawalCursor, int) []*domain.Transaction); ok {
+		r0 = rf(ctx, userID, after, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, *domain.WithdrawalCursor, int) error); ok {
+		r1 = rf(ctx, userID, after, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // TransactionRepositoryMock_GetWithdrawals_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetWithdrawals'
 type TransactionRepositoryMock_GetWithdrawals_Call struct {
 	*mock.Call
@@ -169,13 +349,15 @@ type TransactionRepositoryMock_GetWithdrawals_Call struct {
 // GetWithdrawals is a helper method to define mock.On call
 //   - ctx context.Context
 //   - userID int64
-func (_e *TransactionRepositoryMock_Expecter) GetWithdrawals(ctx interface{}, userID interface{}) *TransactionRepositoryMock_GetWithdrawals_Call {
-	return &TransactionRepositoryMock_GetWithdrawals_Call{Call: _e.mock.On("GetWithdrawals", ctx, userID)}
+//   - after *domain.WithdrawalCursor
+//   - limit int
+func (_e *TransactionRepositoryMock_Expecter) GetWithdrawals(ctx interface{}, userID interface{}, after interface{}, limit interface{}) *TransactionRepositoryMock_GetWithdrawals_Call {
+	return &TransactionRepositoryMock_GetWithdrawals_Call{Call: _e.mock.On("GetWithdrawals", ctx, userID, after, limit)}
 }
 
-func (_c *TransactionRepositoryMock_GetWithdrawals_Call) Run(run func(ctx context.Context, userID int64)) *TransactionRepositoryMock_GetWithdrawals_Call {
+func (_c *TransactionRepositoryMock_GetWithdrawals_Call) Run(run func(ctx context.Context, userID int64, after *domain.WithdrawalCursor, limit int)) *TransactionRepositoryMock_GetWithdrawals_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int64))
+		run(args[0].(context.Context), args[1].(int64), args[2].(*domain.WithdrawalCursor), args[3].(int))
 	})
 	return _c
 }
@@ -185,22 +367,80 @@ func (_c *TransactionRepositoryMock_GetWithdrawals_Call) Return(_a0 []*domain.Tr
 	return _c
 }
 
-func (_c *TransactionRepositoryMock_GetWithdrawals_Call) RunAndReturn(run func(context.Context, int64) ([]*domain.Transaction, error)) *TransactionRepositoryMock_GetWithdrawals_Call {
+func (_c *TransactionRepositoryMock_GetWithdrawals_Call) RunAndReturn(run func(context.Context, int64, *domain.WithdrawalCursor, int) ([]*domain.Transaction, error)) *TransactionRepositoryMock_GetWithdrawals_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// VerifyLedgerChain provides a mock function with given fields: ctx
+func (_m *TransactionRepositoryMock) VerifyLedgerChain(ctx context.Context) (*domain.LedgerVerification, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyLedgerChain")
+	}
+
+	var r0 *domain.LedgerVerification
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*domain.LedgerVerification, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *domain.LedgerVerification); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.LedgerVerification)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TransactionRepositoryMock_VerifyLedgerChain_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'VerifyLedgerChain'
+type TransactionRepositoryMock_VerifyLedgerChain_Call struct {
+	*mock.Call
+}
+
+// VerifyLedgerChain is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *TransactionRepositoryMock_Expecter) VerifyLedgerChain(ctx interface{}) *TransactionRepositoryMock_VerifyLedgerChain_Call {
+	return &TransactionRepositoryMock_VerifyLedgerChain_Call{Call: _e.mock.On("VerifyLedgerChain", ctx)}
+}
+
+func (_c *TransactionRepositoryMock_VerifyLedgerChain_Call) Run(run func(ctx context.Context)) *TransactionRepositoryMock_VerifyLedgerChain_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *TransactionRepositoryMock_VerifyLedgerChain_Call) Return(_a0 *domain.LedgerVerification, _a1 error) *TransactionRepositoryMock_VerifyLedgerChain_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *TransactionRepositoryMock_VerifyLedgerChain_Call) RunAndReturn(run func(context.Context) (*domain.LedgerVerification, error)) *TransactionRepositoryMock_VerifyLedgerChain_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// WithdrawWithLock provides a mock function with given fields: ctx, userID, orderNumber, amount
-func (_m *TransactionRepositoryMock) WithdrawWithLock(ctx context.Context, userID int64, orderNumber string, amount float64) error {
-	ret := _m.Called(ctx, userID, orderNumber, amount)
+// WithdrawWithLock provides a mock function with given fields: ctx, userID, orderNumber, amount, partner, limits
+func (_m *TransactionRepositoryMock) WithdrawWithLock(ctx context.Context, userID int64, orderNumber string, amount float64, partner string, limits domain.WithdrawalLimits) error {
+	ret := _m.Called(ctx, userID, orderNumber, amount, partner, limits)
 
 	if len(ret) == 0 {
 		panic("no return value specified for WithdrawWithLock")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, int64, string, float64) error); ok {
-		r0 = rf(ctx, userID, orderNumber, amount)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, float64, string, domain.WithdrawalLimits) error); ok {
+		r0 = rf(ctx, userID, orderNumber, amount, partner, limits)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -218,13 +458,15 @@ type TransactionRepositoryMock_WithdrawWithLock_Call struct {
 //   - userID int64
 //   - orderNumber string
 //   - amount float64
-func (_e *TransactionRepositoryMock_Expecter) WithdrawWithLock(ctx interface{}, userID interface{}, orderNumber interface{}, amount interface{}) *TransactionRepositoryMock_WithdrawWithLock_Call {
-	return &TransactionRepositoryMock_WithdrawWithLock_Call{Call: _e.mock.On("WithdrawWithLock", ctx, userID, orderNumber, amount)}
+//   - partner string
+//   - limits domain.WithdrawalLimits
+func (_e *TransactionRepositoryMock_Expecter) WithdrawWithLock(ctx interface{}, userID interface{}, orderNumber interface{}, amount interface{}, partner interface{}, limits interface{}) *TransactionRepositoryMock_WithdrawWithLock_Call {
+	return &TransactionRepositoryMock_WithdrawWithLock_Call{Call: _e.mock.On("WithdrawWithLock", ctx, userID, orderNumber, amount, partner, limits)}
 }
 
-func (_c *TransactionRepositoryMock_WithdrawWithLock_Call) Run(run func(ctx context.Context, userID int64, orderNumber string, amount float64)) *TransactionRepositoryMock_WithdrawWithLock_Call {
+func (_c *TransactionRepositoryMock_WithdrawWithLock_Call) Run(run func(ctx context.Context, userID int64, orderNumber string, amount float64, partner string, limits domain.WithdrawalLimits)) *TransactionRepositoryMock_WithdrawWithLock_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int64), args[2].(string), args[3].(float64))
+		run(args[0].(context.Context), args[1].(int64), args[2].(string), args[3].(float64), args[4].(string), args[5].(domain.WithdrawalLimits))
 	})
 	return _c
 }
@@ -234,7 +476,7 @@ func (_c *TransactionRepositoryMock_WithdrawWithLock_Call) Return(_a0 error) *Tr
 	return _c
 }
 
-func (_c *TransactionRepositoryMock_WithdrawWithLock_Call) RunAndReturn(run func(context.Context, int64, string, float64) error) *TransactionRepositoryMock_WithdrawWithLock_Call {
+func (_c *TransactionRepositoryMock_WithdrawWithLock_Call) RunAndReturn(run func(context.Context, int64, string, float64, string, domain.WithdrawalLimits) error) *TransactionRepositoryMock_WithdrawWithLock_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -0,0 +1,334 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/avc/loyalty-system-diploma/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// UserAdminRepositoryMock is an autogenerated mock type for the UserAdminRepository type
+type UserAdminRepositoryMock struct {
+	mock.Mock
+}
+
+type UserAdminRepositoryMock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *UserAdminRepositoryMock) EXPECT() *UserAdminRepositoryMock_Expecter {
+	return &UserAdminRepositoryMock_Expecter{mock: &_m.Mock}
+}
+
+// CountUsers provides a mock function with given fields: ctx
+func (_m *UserAdminRepositoryMock) CountUsers(ctx context.Context) (int, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountUsers")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserAdminRepositoryMock_CountUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CountUsers'
+type UserAdminRepositoryMock_CountUsers_Call struct {
+	*mock.Call
+}
+
+// CountUsers is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *UserAdminRepositoryMock_Expecter) CountUsers(ctx interface{}) *UserAdminRepositoryMock_CountUsers_Call {
+	return &UserAdminRepositoryMock_CountUsers_Call{Call: _e.mock.On("CountUsers", ctx)}
+}
+
+func (_c *UserAdminRepositoryMock_CountUsers_Call) Run(run func(ctx context.Context)) *UserAdminRepositoryMock_CountUsers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *UserAdminRepositoryMock_CountUsers_Call) Return(_a0 int, _a1 error) *UserAdminRepositoryMock_CountUsers_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *UserAdminRepositoryMock_CountUsers_Call) RunAndReturn(run func(context.Context) (int, error)) *UserAdminRepositoryMock_CountUsers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateUser provides a mock function with given fields: ctx, login, passwordHash
+func (_m *UserAdminRepositoryMock) CreateUser(ctx context.Context, login string, passwordHash string) (*domain.User, error) {
+	ret := _m.Called(ctx, login, passwordHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateUser")
+	}
+
+	var r0 *domain.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*domain.User, error)); ok {
+		return rf(ctx, login, passwordHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *domain.User); ok {
+		r0 = rf(ctx, login, passwordHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, login, passwordHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserAdminRepositoryMock_CreateUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateUser'
+type UserAdminRepositoryMock_CreateUser_Call struct {
+	*mock.Call
+}
+
+// CreateUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - login string
+//   - passwordHash string
+func (_e *UserAdminRepositoryMock_Expecter) CreateUser(ctx interface{}, login interface{}, passwordHash interface{}) *UserAdminRepositoryMock_CreateUser_Call {
+	return &UserAdminRepositoryMock_CreateUser_Call{Call: _e.mock.On("CreateUser", ctx, login, passwordHash)}
+}
+
+func (_c *UserAdminRepositoryMock_CreateUser_Call) Run(run func(ctx context.Context, login string, passwordHash string)) *UserAdminRepositoryMock_CreateUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *UserAdminRepositoryMock_CreateUser_Call) Return(_a0 *domain.User, _a1 error) *UserAdminRepositoryMock_CreateUser_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *UserAdminRepositoryMock_CreateUser_Call) RunAndReturn(run func(context.Context, string, string) (*domain.User, error)) *UserAdminRepositoryMock_CreateUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUserByLogin provides a mock function with given fields: ctx, login
+func (_m *UserAdminRepositoryMock) GetUserByLogin(ctx context.Context, login string) (*domain.User, error) {
+	ret := _m.Called(ctx, login)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserByLogin")
+	}
+
+	var r0 *domain.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.User, error)); ok {
+		return rf(ctx, login)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.User); ok {
+		r0 = rf(ctx, login)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, login)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserAdminRepositoryMock_GetUserByLogin_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserByLogin'
+type UserAdminRepositoryMock_GetUserByLogin_Call struct {
+	*mock.Call
+}
+
+// GetUserByLogin is a helper method to define mock.On call
+//   - ctx context.Context
+//   - login string
+func (_e *UserAdminRepositoryMock_Expecter) GetUserByLogin(ctx interface{}, login interface{}) *UserAdminRepositoryMock_GetUserByLogin_Call {
+	return &UserAdminRepositoryMock_GetUserByLogin_Call{Call: _e.mock.On("GetUserByLogin", ctx, login)}
+}
+
+func (_c *UserAdminRepositoryMock_GetUserByLogin_Call) Run(run func(ctx context.Context, login string)) *UserAdminRepositoryMock_GetUserByLogin_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *UserAdminRepositoryMock_GetUserByLogin_Call) Return(_a0 *domain.User, _a1 error) *UserAdminRepositoryMock_GetUserByLogin_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *UserAdminRepositoryMock_GetUserByLogin_Call) RunAndReturn(run func(context.Context, string) (*domain.User, error)) *UserAdminRepositoryMock_GetUserByLogin_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListUserBalances provides a mock function with given fields: ctx, afterID, limit
+func (_m *UserAdminRepositoryMock) ListUserBalances(ctx context.Context, afterID int64, limit int) ([]*domain.UserBalance, error) {
+	ret := _m.Called(ctx, afterID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListUserBalances")
+	}
+
+	var r0 []*domain.UserBalance
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int) ([]*domain.UserBalance, error)); ok {
+		return rf(ctx, afterID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int) []*domain.UserBalance); ok {
+		r0 = rf(ctx, afterID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.UserBalance)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int) error); ok {
+		r1 = rf(ctx, afterID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserAdminRepositoryMock_ListUserBalances_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListUserBalances'
+type UserAdminRepositoryMock_ListUserBalances_Call struct {
+	*mock.Call
+}
+
+// ListUserBalances is a helper method to define mock.On call
+//   - ctx context.Context
+//   - afterID int64
+//   - limit int
+func (_e *UserAdminRepositoryMock_Expecter) ListUserBalances(ctx interface{}, afterID interface{}, limit interface{}) *UserAdminRepositoryMock_ListUserBalances_Call {
+	return &UserAdminRepositoryMock_ListUserBalances_Call{Call: _e.mock.On("ListUserBalances", ctx, afterID, limit)}
+}
+
+func (_c *UserAdminRepositoryMock_ListUserBalances_Call) Run(run func(ctx context.Context, afterID int64, limit int)) *UserAdminRepositoryMock_ListUserBalances_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *UserAdminRepositoryMock_ListUserBalances_Call) Return(_a0 []*domain.UserBalance, _a1 error) *UserAdminRepositoryMock_ListUserBalances_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *UserAdminRepositoryMock_ListUserBalances_Call) RunAndReturn(run func(context.Context, int64, int) ([]*domain.UserBalance, error)) *UserAdminRepositoryMock_ListUserBalances_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MergeUsers provides a mock function with given fields: ctx, sourceLogin, targetLogin, mergedBy, dryRun
+func (_m *UserAdminRepositoryMock) MergeUsers(ctx context.Context, sourceLogin string, targetLogin string, mergedBy int64, dryRun bool) (*domain.UserMerge, error) {
+	ret := _m.Called(ctx, sourceLogin, targetLogin, mergedBy, dryRun)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MergeUsers")
+	}
+
+	var r0 *domain.UserMerge
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64, bool) (*domain.UserMerge, error)); ok {
+		return rf(ctx, sourceLogin, targetLogin, mergedBy, dryRun)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64, bool) *domain.UserMerge); ok {
+		r0 = rf(ctx, sourceLogin, targetLogin, mergedBy, dryRun)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.UserMerge)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int64, bool) error); ok {
+		r1 = rf(ctx, sourceLogin, targetLogin, mergedBy, dryRun)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserAdminRepositoryMock_MergeUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MergeUsers'
+type UserAdminRepositoryMock_MergeUsers_Call struct {
+	*mock.Call
+}
+
+// MergeUsers is a helper method to define mock.On call
+//   - ctx context.Context
+//   - sourceLogin string
+//   - targetLogin string
+//   - mergedBy int64
+//   - dryRun bool
+func (_e *UserAdminRepositoryMock_Expecter) MergeUsers(ctx interface{}, sourceLogin interface{}, targetLogin interface{}, mergedBy interface{}, dryRun interface{}) *UserAdminRepositoryMock_MergeUsers_Call {
+	return &UserAdminRepositoryMock_MergeUsers_Call{Call: _e.mock.On("MergeUsers", ctx, sourceLogin, targetLogin, mergedBy, dryRun)}
+}
+
+func (_c *UserAdminRepositoryMock_MergeUsers_Call) Run(run func(ctx context.Context, sourceLogin string, targetLogin string, mergedBy int64, dryRun bool)) *UserAdminRepositoryMock_MergeUsers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(int64), args[4].(bool))
+	})
+	return _c
+}
+
+func (_c *UserAdminRepositoryMock_MergeUsers_Call) Return(_a0 *domain.UserMerge, _a1 error) *UserAdminRepositoryMock_MergeUsers_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *UserAdminRepositoryMock_MergeUsers_Call) RunAndReturn(run func(context.Context, string, string, int64, bool) (*domain.UserMerge, error)) *UserAdminRepositoryMock_MergeUsers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewUserAdminRepositoryMock creates a new instance of UserAdminRepositoryMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewUserAdminRepositoryMock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *UserAdminRepositoryMock {
+	mock := &UserAdminRepositoryMock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,159 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/avc/loyalty-system-diploma/internal/domain"
+
+	jobs "github.com/avc/loyalty-system-diploma/internal/jobs"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// UserAdminServiceMock is an autogenerated mock type for the UserAdminService type
+type UserAdminServiceMock struct {
+	mock.Mock
+}
+
+type UserAdminServiceMock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *UserAdminServiceMock) EXPECT() *UserAdminServiceMock_Expecter {
+	return &UserAdminServiceMock_Expecter{mock: &_m.Mock}
+}
+
+// ExportUsers provides a mock function with given fields: ctx, progress
+func (_m *UserAdminServiceMock) ExportUsers(ctx context.Context, progress jobs.Progress) ([]*domain.UserBalance, error) {
+	ret := _m.Called(ctx, progress)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExportUsers")
+	}
+
+	var r0 []*domain.UserBalance
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, jobs.Progress) ([]*domain.UserBalance, error)); ok {
+		return rf(ctx, progress)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, jobs.Progress) []*domain.UserBalance); ok {
+		r0 = rf(ctx, progress)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.UserBalance)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, jobs.Progress) error); ok {
+		r1 = rf(ctx, progress)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserAdminServiceMock_ExportUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ExportUsers'
+type UserAdminServiceMock_ExportUsers_Call struct {
+	*mock.Call
+}
+
+// ExportUsers is a helper method to define mock.On call
+//   - ctx context.Context
+//   - progress jobs.Progress
+func (_e *UserAdminServiceMock_Expecter) ExportUsers(ctx interface{}, progress interface{}) *UserAdminServiceMock_ExportUsers_Call {
+	return &UserAdminServiceMock_ExportUsers_Call{Call: _e.mock.On("ExportUsers", ctx, progress)}
+}
+
+func (_c *UserAdminServiceMock_ExportUsers_Call) Run(run func(ctx context.Context, progress jobs.Progress)) *UserAdminServiceMock_ExportUsers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(jobs.Progress))
+	})
+	return _c
+}
+
+func (_c *UserAdminServiceMock_ExportUsers_Call) Return(_a0 []*domain.UserBalance, _a1 error) *UserAdminServiceMock_ExportUsers_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *UserAdminServiceMock_ExportUsers_Call) RunAndReturn(run func(context.Context, jobs.Progress) ([]*domain.UserBalance, error)) *UserAdminServiceMock_ExportUsers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ImportUsers provides a mock function with given fields: ctx, logins, progress
+func (_m *UserAdminServiceMock) ImportUsers(ctx context.Context, logins []string, progress jobs.Progress) ([]domain.ImportedUser, error) {
+	ret := _m.Called(ctx, logins, progress)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ImportUsers")
+	}
+
+	var r0 []domain.ImportedUser
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string, jobs.Progress) ([]domain.ImportedUser, error)); ok {
+		return rf(ctx, logins, progress)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string, jobs.Progress) []domain.ImportedUser); ok {
+		r0 = rf(ctx, logins, progress)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.ImportedUser)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string, jobs.Progress) error); ok {
+		r1 = rf(ctx, logins, progress)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserAdminServiceMock_ImportUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ImportUsers'
+type UserAdminServiceMock_ImportUsers_Call struct {
+	*mock.Call
+}
+
+// ImportUsers is a helper method to define mock.On call
+//   - ctx context.Context
+//   - logins []string
+//   - progress jobs.Progress
+func (_e *UserAdminServiceMock_Expecter) ImportUsers(ctx interface{}, logins interface{}, progress interface{}) *UserAdminServiceMock_ImportUsers_Call {
+	return &UserAdminServiceMock_ImportUsers_Call{Call: _e.mock.On("ImportUsers", ctx, logins, progress)}
+}
+
+func (_c *UserAdminServiceMock_ImportUsers_Call) Run(run func(ctx context.Context, logins []string, progress jobs.Progress)) *UserAdminServiceMock_ImportUsers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]string), args[2].(jobs.Progress))
+	})
+	return _c
+}
+
+func (_c *UserAdminServiceMock_ImportUsers_Call) Return(_a0 []domain.ImportedUser, _a1 error) *UserAdminServiceMock_ImportUsers_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *UserAdminServiceMock_ImportUsers_Call) RunAndReturn(run func(context.Context, []string, jobs.Progress) ([]domain.ImportedUser, error)) *UserAdminServiceMock_ImportUsers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewUserAdminServiceMock creates a new instance of UserAdminServiceMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewUserAdminServiceMock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *UserAdminServiceMock {
+	mock := &UserAdminServiceMock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,257 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/avc/loyalty-system-diploma/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// UserContactsRepositoryMock is an autogenerated mock type for the UserContactsRepository type
+type UserContactsRepositoryMock struct {
+	mock.Mock
+}
+
+type UserContactsRepositoryMock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *UserContactsRepositoryMock) EXPECT() *UserContactsRepositoryMock_Expecter {
+	return &UserContactsRepositoryMock_Expecter{mock: &_m.Mock}
+}
+
+// CountStaleUserContacts provides a mock function with given fields: ctx
+func (_m *UserContactsRepositoryMock) CountStaleUserContacts(ctx context.Context) (int, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountStaleUserContacts")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserContactsRepositoryMock_CountStaleUserContacts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CountStaleUserContacts'
+type UserContactsRepositoryMock_CountStaleUserContacts_Call struct {
+	*mock.Call
+}
+
+// CountStaleUserContacts is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *UserContactsRepositoryMock_Expecter) CountStaleUserContacts(ctx interface{}) *UserContactsRepositoryMock_CountStaleUserContacts_Call {
+	return &UserContactsRepositoryMock_CountStaleUserContacts_Call{Call: _e.mock.On("CountStaleUserContacts", ctx)}
+}
+
+func (_c *UserContactsRepositoryMock_CountStaleUserContacts_Call) Run(run func(ctx context.Context)) *UserContactsRepositoryMock_CountStaleUserContacts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *UserContactsRepositoryMock_CountStaleUserContacts_Call) Return(_a0 int, _a1 error) *UserContactsRepositoryMock_CountStaleUserContacts_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *UserContactsRepositoryMock_CountStaleUserContacts_Call) RunAndReturn(run func(context.Context) (int, error)) *UserContactsRepositoryMock_CountStaleUserContacts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUserContacts provides a mock function with given fields: ctx, userID
+func (_m *UserContactsRepositoryMock) GetUserContacts(ctx context.Context, userID int64) (*domain.UserContacts, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserContacts")
+	}
+
+	var r0 *domain.UserContacts
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*domain.UserContacts, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *domain.UserContacts); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.UserContacts)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserContactsRepositoryMock_GetUserContacts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserContacts'
+type UserContactsRepositoryMock_GetUserContacts_Call struct {
+	*mock.Call
+}
+
+// GetUserContacts is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+func (_e *UserContactsRepositoryMock_Expecter) GetUserContacts(ctx interface{}, userID interface{}) *UserContactsRepositoryMock_GetUserContacts_Call {
+	return &UserContactsRepositoryMock_GetUserContacts_Call{Call: _e.mock.On("GetUserContacts", ctx, userID)}
+}
+
+func (_c *UserContactsRepositoryMock_GetUserContacts_Call) Run(run func(ctx context.Context, userID int64)) *UserContactsRepositoryMock_GetUserContacts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *UserContactsRepositoryMock_GetUserContacts_Call) Return(_a0 *domain.UserContacts, _a1 error) *UserContactsRepositoryMock_GetUserContacts_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *UserContactsRepositoryMock_GetUserContacts_Call) RunAndReturn(run func(context.Context, int64) (*domain.UserContacts, error)) *UserContactsRepositoryMock_GetUserContacts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RewrapUserContacts provides a mock function with given fields: ctx, limit
+func (_m *UserContactsRepositoryMock) RewrapUserContacts(ctx context.Context, limit int) (int, error) {
+	ret := _m.Called(ctx, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RewrapUserContacts")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (int, error)); ok {
+		return rf(ctx, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) int); ok {
+		r0 = rf(ctx, limit)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserContactsRepositoryMock_RewrapUserContacts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RewrapUserContacts'
+type UserContactsRepositoryMock_RewrapUserContacts_Call struct {
+	*mock.Call
+}
+
+// RewrapUserContacts is a helper method to define mock.On call
+//   - ctx context.Context
+//   - limit int
+func (_e *UserContactsRepositoryMock_Expecter) RewrapUserContacts(ctx interface{}, limit interface{}) *UserContactsRepositoryMock_RewrapUserContacts_Call {
+	return &UserContactsRepositoryMock_RewrapUserContacts_Call{Call: _e.mock.On("RewrapUserContacts", ctx, limit)}
+}
+
+func (_c *UserContactsRepositoryMock_RewrapUserContacts_Call) Run(run func(ctx context.Context, limit int)) *UserContactsRepositoryMock_RewrapUserContacts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *UserContactsRepositoryMock_RewrapUserContacts_Call) Return(_a0 int, _a1 error) *UserContactsRepositoryMock_RewrapUserContacts_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *UserContactsRepositoryMock_RewrapUserContacts_Call) RunAndReturn(run func(context.Context, int) (int, error)) *UserContactsRepositoryMock_RewrapUserContacts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetUserContacts provides a mock function with given fields: ctx, userID, contacts
+func (_m *UserContactsRepositoryMock) SetUserContacts(ctx context.Context, userID int64, contacts domain.UserContacts) error {
+	ret := _m.Called(ctx, userID, contacts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetUserContacts")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, domain.UserContacts) error); ok {
+		r0 = rf(ctx, userID, contacts)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UserContactsRepositoryMock_SetUserContacts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetUserContacts'
+type UserContactsRepositoryMock_SetUserContacts_Call struct {
+	*mock.Call
+}
+
+// SetUserContacts is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - contacts domain.UserContacts
+func (_e *UserContactsRepositoryMock_Expecter) SetUserContacts(ctx interface{}, userID interface{}, contacts interface{}) *UserContactsRepositoryMock_SetUserContacts_Call {
+	return &UserContactsRepositoryMock_SetUserContacts_Call{Call: _e.mock.On("SetUserContacts", ctx, userID, contacts)}
+}
+
+func (_c *UserContactsRepositoryMock_SetUserContacts_Call) Run(run func(ctx context.Context, userID int64, contacts domain.UserContacts)) *UserContactsRepositoryMock_SetUserContacts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(domain.UserContacts))
+	})
+	return _c
+}
+
+func (_c *UserContactsRepositoryMock_SetUserContacts_Call) Return(_a0 error) *UserContactsRepositoryMock_SetUserContacts_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *UserContactsRepositoryMock_SetUserContacts_Call) RunAndReturn(run func(context.Context, int64, domain.UserContacts) error) *UserContactsRepositoryMock_SetUserContacts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewUserContactsRepositoryMock creates a new instance of UserContactsRepositoryMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewUserContactsRepositoryMock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *UserContactsRepositoryMock {
+	mock := &UserContactsRepositoryMock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
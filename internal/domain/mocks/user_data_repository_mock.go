@@ -0,0 +1,155 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/avc/loyalty-system-diploma/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// UserDataRepositoryMock is an autogenerated mock type for the UserDataRepository type
+type UserDataRepositoryMock struct {
+	mock.Mock
+}
+
+type UserDataRepositoryMock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *UserDataRepositoryMock) EXPECT() *UserDataRepositoryMock_Expecter {
+	return &UserDataRepositoryMock_Expecter{mock: &_m.Mock}
+}
+
+// GetUserByID provides a mock function with given fields: ctx, id
+func (_m *UserDataRepositoryMock) GetUserByID(ctx context.Context, id int64) (*domain.User, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserByID")
+	}
+
+	var r0 *domain.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*domain.User, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *domain.User); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserDataRepositoryMock_GetUserByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserByID'
+type UserDataRepositoryMock_GetUserByID_Call struct {
+	*mock.Call
+}
+
+// GetUserByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *UserDataRepositoryMock_Expecter) GetUserByID(ctx interface{}, id interface{}) *UserDataRepositoryMock_GetUserByID_Call {
+	return &UserDataRepositoryMock_GetUserByID_Call{Call: _e.mock.On("GetUserByID", ctx, id)}
+}
+
+func (_c *UserDataRepositoryMock_GetUserByID_Call) Run(run func(ctx context.Context, id int64)) *UserDataRepositoryMock_GetUserByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *UserDataRepositoryMock_GetUserByID_Call) Return(_a0 *domain.User, _a1 error) *UserDataRepositoryMock_GetUserByID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *UserDataRepositoryMock_GetUserByID_Call) RunAndReturn(run func(context.Context, int64) (*domain.User, error)) *UserDataRepositoryMock_GetUserByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUserContacts provides a mock function with given fields: ctx, userID
+func (_m *UserDataRepositoryMock) GetUserContacts(ctx context.Context, userID int64) (*domain.UserContacts, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserContacts")
+	}
+
+	var r0 *domain.UserContacts
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*domain.UserContacts, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *domain.UserContacts); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.UserContacts)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserDataRepositoryMock_GetUserContacts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserContacts'
+type UserDataRepositoryMock_GetUserContacts_Call struct {
+	*mock.Call
+}
+
+// GetUserContacts is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+func (_e *UserDataRepositoryMock_Expecter) GetUserContacts(ctx interface{}, userID interface{}) *UserDataRepositoryMock_GetUserContacts_Call {
+	return &UserDataRepositoryMock_GetUserContacts_Call{Call: _e.mock.On("GetUserContacts", ctx, userID)}
+}
+
+func (_c *UserDataRepositoryMock_GetUserContacts_Call) Run(run func(ctx context.Context, userID int64)) *UserDataRepositoryMock_GetUserContacts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *UserDataRepositoryMock_GetUserContacts_Call) Return(_a0 *domain.UserContacts, _a1 error) *UserDataRepositoryMock_GetUserContacts_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *UserDataRepositoryMock_GetUserContacts_Call) RunAndReturn(run func(context.Context, int64) (*domain.UserContacts, error)) *UserDataRepositoryMock_GetUserContacts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewUserDataRepositoryMock creates a new instance of UserDataRepositoryMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewUserDataRepositoryMock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *UserDataRepositoryMock {
+	mock := &UserDataRepositoryMock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
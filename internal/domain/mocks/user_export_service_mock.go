@@ -0,0 +1,100 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/avc/loyalty-system-diploma/internal/domain"
+
+	jobs "github.com/avc/loyalty-system-diploma/internal/jobs"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// UserExportServiceMock is an autogenerated mock type for the UserExportService type
+type UserExportServiceMock struct {
+	mock.Mock
+}
+
+type UserExportServiceMock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *UserExportServiceMock) EXPECT() *UserExportServiceMock_Expecter {
+	return &UserExportServiceMock_Expecter{mock: &_m.Mock}
+}
+
+// ExportUserData provides a mock function with given fields: ctx, userID, progress
+func (_m *UserExportServiceMock) ExportUserData(ctx context.Context, userID int64, progress jobs.Progress) (*domain.UserDataExport, error) {
+	ret := _m.Called(ctx, userID, progress)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExportUserData")
+	}
+
+	var r0 *domain.UserDataExport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, jobs.Progress) (*domain.UserDataExport, error)); ok {
+		return rf(ctx, userID, progress)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, jobs.Progress) *domain.UserDataExport); ok {
+		r0 = rf(ctx, userID, progress)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.UserDataExport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, jobs.Progress) error); ok {
+		r1 = rf(ctx, userID, progress)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserExportServiceMock_ExportUserData_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ExportUserData'
+type UserExportServiceMock_ExportUserData_Call struct {
+	*mock.Call
+}
+
+// ExportUserData is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - progress jobs.Progress
+func (_e *UserExportServiceMock_Expecter) ExportUserData(ctx interface{}, userID interface{}, progress interface{}) *UserExportServiceMock_ExportUserData_Call {
+	return &UserExportServiceMock_ExportUserData_Call{Call: _e.mock.On("ExportUserData", ctx, userID, progress)}
+}
+
+func (_c *UserExportServiceMock_ExportUserData_Call) Run(run func(ctx context.Context, userID int64, progress jobs.Progress)) *UserExportServiceMock_ExportUserData_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(jobs.Progress))
+	})
+	return _c
+}
+
+func (_c *UserExportServiceMock_ExportUserData_Call) Return(_a0 *domain.UserDataExport, _a1 error) *UserExportServiceMock_ExportUserData_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *UserExportServiceMock_ExportUserData_Call) RunAndReturn(run func(context.Context, int64, jobs.Progress) (*domain.UserDataExport, error)) *UserExportServiceMock_ExportUserData_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewUserExportServiceMock creates a new instance of UserExportServiceMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewUserExportServiceMock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *UserExportServiceMock {
+	mock := &UserExportServiceMock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,100 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/avc/loyalty-system-diploma/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// UserMergeServiceMock is an autogenerated mock type for the UserMergeService type
+type UserMergeServiceMock struct {
+	mock.Mock
+}
+
+type UserMergeServiceMock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *UserMergeServiceMock) EXPECT() *UserMergeServiceMock_Expecter {
+	return &UserMergeServiceMock_Expecter{mock: &_m.Mock}
+}
+
+// MergeUsers provides a mock function with given fields: ctx, sourceLogin, targetLogin, adminID, dryRun
+func (_m *UserMergeServiceMock) MergeUsers(ctx context.Context, sourceLogin string, targetLogin string, adminID int64, dryRun bool) (*domain.UserMerge, error) {
+	ret := _m.Called(ctx, sourceLogin, targetLogin, adminID, dryRun)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MergeUsers")
+	}
+
+	var r0 *domain.UserMerge
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64, bool) (*domain.UserMerge, error)); ok {
+		return rf(ctx, sourceLogin, targetLogin, adminID, dryRun)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64, bool) *domain.UserMerge); ok {
+		r0 = rf(ctx, sourceLogin, targetLogin, adminID, dryRun)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.UserMerge)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int64, bool) error); ok {
+		r1 = rf(ctx, sourceLogin, targetLogin, adminID, dryRun)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserMergeServiceMock_MergeUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MergeUsers'
+type UserMergeServiceMock_MergeUsers_Call struct {
+	*mock.Call
+}
+
+// MergeUsers is a helper method to define mock.On call
+//   - ctx context.Context
+//   - sourceLogin string
+//   - targetLogin string
+//   - adminID int64
+//   - dryRun bool
+func (_e *UserMergeServiceMock_Expecter) MergeUsers(ctx interface{}, sourceLogin interface{}, targetLogin interface{}, adminID interface{}, dryRun interface{}) *UserMergeServiceMock_MergeUsers_Call {
+	return &UserMergeServiceMock_MergeUsers_Call{Call: _e.mock.On("MergeUsers", ctx, sourceLogin, targetLogin, adminID, dryRun)}
+}
+
+func (_c *UserMergeServiceMock_MergeUsers_Call) Run(run func(ctx context.Context, sourceLogin string, targetLogin string, adminID int64, dryRun bool)) *UserMergeServiceMock_MergeUsers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(int64), args[4].(bool))
+	})
+	return _c
+}
+
+func (_c *UserMergeServiceMock_MergeUsers_Call) Return(_a0 *domain.UserMerge, _a1 error) *UserMergeServiceMock_MergeUsers_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *UserMergeServiceMock_MergeUsers_Call) RunAndReturn(run func(context.Context, string, string, int64, bool) (*domain.UserMerge, error)) *UserMergeServiceMock_MergeUsers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewUserMergeServiceMock creates a new instance of UserMergeServiceMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewUserMergeServiceMock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *UserMergeServiceMock {
+	mock := &UserMergeServiceMock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
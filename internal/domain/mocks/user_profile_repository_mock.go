@@ -0,0 +1,141 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// UserProfileRepositoryMock is an autogenerated mock type for the UserProfileRepository type
+type UserProfileRepositoryMock struct {
+	mock.Mock
+}
+
+type UserProfileRepositoryMock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *UserProfileRepositoryMock) EXPECT() *UserProfileRepositoryMock_Expecter {
+	return &UserProfileRepositoryMock_Expecter{mock: &_m.Mock}
+}
+
+// GetUserLocale provides a mock function with given fields: ctx, userID
+func (_m *UserProfileRepositoryMock) GetUserLocale(ctx context.Context, userID int64) (string, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserLocale")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (string, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) string); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserProfileRepositoryMock_GetUserLocale_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserLocale'
+type UserProfileRepositoryMock_GetUserLocale_Call struct {
+	*mock.Call
+}
+
+// GetUserLocale is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+func (_e *UserProfileRepositoryMock_Expecter) GetUserLocale(ctx interface{}, userID interface{}) *UserProfileRepositoryMock_GetUserLocale_Call {
+	return &UserProfileRepositoryMock_GetUserLocale_Call{Call: _e.mock.On("GetUserLocale", ctx, userID)}
+}
+
+func (_c *UserProfileRepositoryMock_GetUserLocale_Call) Run(run func(ctx context.Context, userID int64)) *UserProfileRepositoryMock_GetUserLocale_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *UserProfileRepositoryMock_GetUserLocale_Call) Return(_a0 string, _a1 error) *UserProfileRepositoryMock_GetUserLocale_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *UserProfileRepositoryMock_GetUserLocale_Call) RunAndReturn(run func(context.Context, int64) (string, error)) *UserProfileRepositoryMock_GetUserLocale_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetUserLocale provides a mock function with given fields: ctx, userID, locale
+func (_m *UserProfileRepositoryMock) SetUserLocale(ctx context.Context, userID int64, locale string) error {
+	ret := _m.Called(ctx, userID, locale)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetUserLocale")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) error); ok {
+		r0 = rf(ctx, userID, locale)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UserProfileRepositoryMock_SetUserLocale_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetUserLocale'
+type UserProfileRepositoryMock_SetUserLocale_Call struct {
+	*mock.Call
+}
+
+// SetUserLocale is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - locale string
+func (_e *UserProfileRepositoryMock_Expecter) SetUserLocale(ctx interface{}, userID interface{}, locale interface{}) *UserProfileRepositoryMock_SetUserLocale_Call {
+	return &UserProfileRepositoryMock_SetUserLocale_Call{Call: _e.mock.On("SetUserLocale", ctx, userID, locale)}
+}
+
+func (_c *UserProfileRepositoryMock_SetUserLocale_Call) Run(run func(ctx context.Context, userID int64, locale string)) *UserProfileRepositoryMock_SetUserLocale_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *UserProfileRepositoryMock_SetUserLocale_Call) Return(_a0 error) *UserProfileRepositoryMock_SetUserLocale_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *UserProfileRepositoryMock_SetUserLocale_Call) RunAndReturn(run func(context.Context, int64, string) error) *UserProfileRepositoryMock_SetUserLocale_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewUserProfileRepositoryMock creates a new instance of UserProfileRepositoryMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewUserProfileRepositoryMock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *UserProfileRepositoryMock {
+	mock := &UserProfileRepositoryMock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -82,6 +82,65 @@ func (_c *UserRepositoryMock_CreateUser_Call) RunAndReturn(run func(context.Cont
 	return _c
 }
 
+// GetOrCreateExternalUser provides a mock function with given fields: ctx, identity
+func (_m *UserRepositoryMock) GetOrCreateExternalUser(ctx context.Context, identity domain.ExternalIdentity) (*domain.User, error) {
+	ret := _m.Called(ctx, identity)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrCreateExternalUser")
+	}
+
+	var r0 *domain.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.ExternalIdentity) (*domain.User, error)); ok {
+		return rf(ctx, identity)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.ExternalIdentity) *domain.User); ok {
+		r0 = rf(ctx, identity)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.ExternalIdentity) error); ok {
+		r1 = rf(ctx, identity)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserRepositoryMock_GetOrCreateExternalUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOrCreateExternalUser'
+type UserRepositoryMock_GetOrCreateExternalUser_Call struct {
+	*mock.Call
+}
+
+// GetOrCreateExternalUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - identity domain.ExternalIdentity
+func (_e *UserRepositoryMock_Expecter) GetOrCreateExternalUser(ctx interface{}, identity interface{}) *UserRepositoryMock_GetOrCreateExternalUser_Call {
+	return &UserRepositoryMock_GetOrCreateExternalUser_Call{Call: _e.mock.On("GetOrCreateExternalUser", ctx, identity)}
+}
+
+func (_c *UserRepositoryMock_GetOrCreateExternalUser_Call) Run(run func(ctx context.Context, identity domain.ExternalIdentity)) *UserRepositoryMock_GetOrCreateExternalUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(domain.ExternalIdentity))
+	})
+	return _c
+}
+
+func (_c *UserRepositoryMock_GetOrCreateExternalUser_Call) Return(_a0 *domain.User, _a1 error) *UserRepositoryMock_GetOrCreateExternalUser_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *UserRepositoryMock_GetOrCreateExternalUser_Call) RunAndReturn(run func(context.Context, domain.ExternalIdentity) (*domain.User, error)) *UserRepositoryMock_GetOrCreateExternalUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetUserByID provides a mock function with given fields: ctx, id
 func (_m *UserRepositoryMock) GetUserByID(ctx context.Context, id int64) (*domain.User, error) {
 	ret := _m.Called(ctx, id)
@@ -200,6 +259,54 @@ func (_c *UserRepositoryMock_GetUserByLogin_Call) RunAndReturn(run func(context.
 	return _c
 }
 
+// LinkExternalIdentity provides a mock function with given fields: ctx, userID, identity
+func (_m *UserRepositoryMock) LinkExternalIdentity(ctx context.Context, userID int64, identity domain.ExternalIdentity) error {
+	ret := _m.Called(ctx, userID, identity)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LinkExternalIdentity")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, domain.ExternalIdentity) error); ok {
+		r0 = rf(ctx, userID, identity)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UserRepositoryMock_LinkExternalIdentity_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LinkExternalIdentity'
+type UserRepositoryMock_LinkExternalIdentity_Call struct {
+	*mock.Call
+}
+
+// LinkExternalIdentity is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - identity domain.ExternalIdentity
+func (_e *UserRepositoryMock_Expecter) LinkExternalIdentity(ctx interface{}, userID interface{}, identity interface{}) *UserRepositoryMock_LinkExternalIdentity_Call {
+	return &UserRepositoryMock_LinkExternalIdentity_Call{Call: _e.mock.On("LinkExternalIdentity", ctx, userID, identity)}
+}
+
+func (_c *UserRepositoryMock_LinkExternalIdentity_Call) Run(run func(ctx context.Context, userID int64, identity domain.ExternalIdentity)) *UserRepositoryMock_LinkExternalIdentity_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(domain.ExternalIdentity))
+	})
+	return _c
+}
+
+func (_c *UserRepositoryMock_LinkExternalIdentity_Call) Return(_a0 error) *UserRepositoryMock_LinkExternalIdentity_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *UserRepositoryMock_LinkExternalIdentity_Call) RunAndReturn(run func(context.Context, int64, domain.ExternalIdentity) error) *UserRepositoryMock_LinkExternalIdentity_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewUserRepositoryMock creates a new instance of UserRepositoryMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewUserRepositoryMock(t interface {
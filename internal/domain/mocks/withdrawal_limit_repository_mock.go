@@ -0,0 +1,250 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/avc/loyalty-system-diploma/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// WithdrawalLimitRepositoryMock is an autogenerated mock type for the WithdrawalLimitRepository type
+type WithdrawalLimitRepositoryMock struct {
+	mock.Mock
+}
+
+type WithdrawalLimitRepositoryMock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *WithdrawalLimitRepositoryMock) EXPECT() *WithdrawalLimitRepositoryMock_Expecter {
+	return &WithdrawalLimitRepositoryMock_Expecter{mock: &_m.Mock}
+}
+
+// DeleteWithdrawalLimits provides a mock function with given fields: ctx, login
+func (_m *WithdrawalLimitRepositoryMock) DeleteWithdrawalLimits(ctx context.Context, login string) error {
+	ret := _m.Called(ctx, login)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteWithdrawalLimits")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, login)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// WithdrawalLimitRepositoryMock_DeleteWithdrawalLimits_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteWithdrawalLimits'
+type WithdrawalLimitRepositoryMock_DeleteWithdrawalLimits_Call struct {
+	*mock.Call
+}
+
+// DeleteWithdrawalLimits is a helper method to define mock.On call
+//   - ctx context.Context
+//   - login string
+func (_e *WithdrawalLimitRepositoryMock_Expecter) DeleteWithdrawalLimits(ctx interface{}, login interface{}) *WithdrawalLimitRepositoryMock_DeleteWithdrawalLimits_Call {
+	return &WithdrawalLimitRepositoryMock_DeleteWithdrawalLimits_Call{Call: _e.mock.On("DeleteWithdrawalLimits", ctx, login)}
+}
+
+func (_c *WithdrawalLimitRepositoryMock_DeleteWithdrawalLimits_Call) Run(run func(ctx context.Context, login string)) *WithdrawalLimitRepositoryMock_DeleteWithdrawalLimits_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *WithdrawalLimitRepositoryMock_DeleteWithdrawalLimits_Call) Return(_a0 error) *WithdrawalLimitRepositoryMock_DeleteWithdrawalLimits_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *WithdrawalLimitRepositoryMock_DeleteWithdrawalLimits_Call) RunAndReturn(run func(context.Context, string) error) *WithdrawalLimitRepositoryMock_DeleteWithdrawalLimits_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetWithdrawalLimits provides a mock function with given fields: ctx, userID
+func (_m *WithdrawalLimitRepositoryMock) GetWithdrawalLimits(ctx context.Context, userID int64) (*domain.WithdrawalLimits, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWithdrawalLimits")
+	}
+
+	var r0 *domain.WithdrawalLimits
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*domain.WithdrawalLimits, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *domain.WithdrawalLimits); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.WithdrawalLimits)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WithdrawalLimitRepositoryMock_GetWithdrawalLimits_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetWithdrawalLimits'
+type WithdrawalLimitRepositoryMock_GetWithdrawalLimits_Call struct {
+	*mock.Call
+}
+
+// GetWithdrawalLimits is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+func (_e *WithdrawalLimitRepositoryMock_Expecter) GetWithdrawalLimits(ctx interface{}, userID interface{}) *WithdrawalLimitRepositoryMock_GetWithdrawalLimits_Call {
+	return &WithdrawalLimitRepositoryMock_GetWithdrawalLimits_Call{Call: _e.mock.On("GetWithdrawalLimits", ctx, userID)}
+}
+
+func (_c *WithdrawalLimitRepositoryMock_GetWithdrawalLimits_Call) Run(run func(ctx context.Context, userID int64)) *WithdrawalLimitRepositoryMock_GetWithdrawalLimits_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *WithdrawalLimitRepositoryMock_GetWithdrawalLimits_Call) Return(_a0 *domain.WithdrawalLimits, _a1 error) *WithdrawalLimitRepositoryMock_GetWithdrawalLimits_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *WithdrawalLimitRepositoryMock_GetWithdrawalLimits_Call) RunAndReturn(run func(context.Context, int64) (*domain.WithdrawalLimits, error)) *WithdrawalLimitRepositoryMock_GetWithdrawalLimits_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetWithdrawalLimitsByLogin provides a mock function with given fields: ctx, login
+func (_m *WithdrawalLimitRepositoryMock) GetWithdrawalLimitsByLogin(ctx context.Context, login string) (*domain.WithdrawalLimits, error) {
+	ret := _m.Called(ctx, login)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWithdrawalLimitsByLogin")
+	}
+
+	var r0 *domain.WithdrawalLimits
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.WithdrawalLimits, error)); ok {
+		return rf(ctx, login)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.WithdrawalLimits); ok {
+		r0 = rf(ctx, login)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.WithdrawalLimits)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, login)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WithdrawalLimitRepositoryMock_GetWithdrawalLimitsByLogin_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetWithdrawalLimitsByLogin'
+type WithdrawalLimitRepositoryMock_GetWithdrawalLimitsByLogin_Call struct {
+	*mock.Call
+}
+
+// GetWithdrawalLimitsByLogin is a helper method to define mock.On call
+//   - ctx context.Context
+//   - login string
+func (_e *WithdrawalLimitRepositoryMock_Expecter) GetWithdrawalLimitsByLogin(ctx interface{}, login interface{}) *WithdrawalLimitRepositoryMock_GetWithdrawalLimitsByLogin_Call {
+	return &WithdrawalLimitRepositoryMock_GetWithdrawalLimitsByLogin_Call{Call: _e.mock.On("GetWithdrawalLimitsByLogin", ctx, login)}
+}
+
+func (_c *WithdrawalLimitRepositoryMock_GetWithdrawalLimitsByLogin_Call) Run(run func(ctx context.Context, login string)) *WithdrawalLimitRepositoryMock_GetWithdrawalLimitsByLogin_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *WithdrawalLimitRepositoryMock_GetWithdrawalLimitsByLogin_Call) Return(_a0 *domain.WithdrawalLimits, _a1 error) *WithdrawalLimitRepositoryMock_GetWithdrawalLimitsByLogin_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *WithdrawalLimitRepositoryMock_GetWithdrawalLimitsByLogin_Call) RunAndReturn(run func(context.Context, string) (*domain.WithdrawalLimits, error)) *WithdrawalLimitRepositoryMock_GetWithdrawalLimitsByLogin_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetWithdrawalLimits provides a mock function with given fields: ctx, login, limits
+func (_m *WithdrawalLimitRepositoryMock) SetWithdrawalLimits(ctx context.Context, login string, limits domain.WithdrawalLimits) error {
+	ret := _m.Called(ctx, login, limits)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetWithdrawalLimits")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.WithdrawalLimits) error); ok {
+		r0 = rf(ctx, login, limits)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// WithdrawalLimitRepositoryMock_SetWithdrawalLimits_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetWithdrawalLimits'
+type WithdrawalLimitRepositoryMock_SetWithdrawalLimits_Call struct {
+	*mock.Call
+}
+
+// SetWithdrawalLimits is a helper method to define mock.On call
+//   - ctx context.Context
+//   - login string
+//   - limits domain.WithdrawalLimits
+func (_e *WithdrawalLimitRepositoryMock_Expecter) SetWithdrawalLimits(ctx interface{}, login interface{}, limits interface{}) *WithdrawalLimitRepositoryMock_SetWithdrawalLimits_Call {
+	return &WithdrawalLimitRepositoryMock_SetWithdrawalLimits_Call{Call: _e.mock.On("SetWithdrawalLimits", ctx, login, limits)}
+}
+
+func (_c *WithdrawalLimitRepositoryMock_SetWithdrawalLimits_Call) Run(run func(ctx context.Context, login string, limits domain.WithdrawalLimits)) *WithdrawalLimitRepositoryMock_SetWithdrawalLimits_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(domain.WithdrawalLimits))
+	})
+	return _c
+}
+
+func (_c *WithdrawalLimitRepositoryMock_SetWithdrawalLimits_Call) Return(_a0 error) *WithdrawalLimitRepositoryMock_SetWithdrawalLimits_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *WithdrawalLimitRepositoryMock_SetWithdrawalLimits_Call) RunAndReturn(run func(context.Context, string, domain.WithdrawalLimits) error) *WithdrawalLimitRepositoryMock_SetWithdrawalLimits_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewWithdrawalLimitRepositoryMock creates a new instance of WithdrawalLimitRepositoryMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewWithdrawalLimitRepositoryMock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *WithdrawalLimitRepositoryMock {
+	mock := &WithdrawalLimitRepositoryMock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
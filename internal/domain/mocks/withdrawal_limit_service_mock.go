@@ -0,0 +1,204 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/avc/loyalty-system-diploma/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// WithdrawalLimitServiceMock is an autogenerated mock type for the WithdrawalLimitService type
+type WithdrawalLimitServiceMock struct {
+	mock.Mock
+}
+
+type WithdrawalLimitServiceMock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *WithdrawalLimitServiceMock) EXPECT() *WithdrawalLimitServiceMock_Expecter {
+	return &WithdrawalLimitServiceMock_Expecter{mock: &_m.Mock}
+}
+
+// GetWithdrawalLimits provides a mock function with given fields: ctx, login
+func (_m *WithdrawalLimitServiceMock) GetWithdrawalLimits(ctx context.Context, login string) (*domain.UserWithdrawalLimits, error) {
+	ret := _m.Called(ctx, login)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWithdrawalLimits")
+	}
+
+	var r0 *domain.UserWithdrawalLimits
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.UserWithdrawalLimits, error)); ok {
+		return rf(ctx, login)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.UserWithdrawalLimits); ok {
+		r0 = rf(ctx, login)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.UserWithdrawalLimits)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, login)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WithdrawalLimitServiceMock_GetWithdrawalLimits_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetWithdrawalLimits'
+type WithdrawalLimitServiceMock_GetWithdrawalLimits_Call struct {
+	*mock.Call
+}
+
+// GetWithdrawalLimits is a helper method to define mock.On call
+//   - ctx context.Context
+//   - login string
+func (_e *WithdrawalLimitServiceMock_Expecter) GetWithdrawalLimits(ctx interface{}, login interface{}) *WithdrawalLimitServiceMock_GetWithdrawalLimits_Call {
+	return &WithdrawalLimitServiceMock_GetWithdrawalLimits_Call{Call: _e.mock.On("GetWithdrawalLimits", ctx, login)}
+}
+
+func (_c *WithdrawalLimitServiceMock_GetWithdrawalLimits_Call) Run(run func(ctx context.Context, login string)) *WithdrawalLimitServiceMock_GetWithdrawalLimits_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *WithdrawalLimitServiceMock_GetWithdrawalLimits_Call) Return(_a0 *domain.UserWithdrawalLimits, _a1 error) *WithdrawalLimitServiceMock_GetWithdrawalLimits_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *WithdrawalLimitServiceMock_GetWithdrawalLimits_Call) RunAndReturn(run func(context.Context, string) (*domain.UserWithdrawalLimits, error)) *WithdrawalLimitServiceMock_GetWithdrawalLimits_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ResetWithdrawalLimits provides a mock function with given fields: ctx, login
+func (_m *WithdrawalLimitServiceMock) ResetWithdrawalLimits(ctx context.Context, login string) error {
+	ret := _m.Called(ctx, login)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetWithdrawalLimits")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, login)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// WithdrawalLimitServiceMock_ResetWithdrawalLimits_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResetWithdrawalLimits'
+type WithdrawalLimitServiceMock_ResetWithdrawalLimits_Call struct {
+	*mock.Call
+}
+
+// ResetWithdrawalLimits is a helper method to define mock.On call
+//   - ctx context.Context
+//   - login string
+func (_e *WithdrawalLimitServiceMock_Expecter) ResetWithdrawalLimits(ctx interface{}, login interface{}) *WithdrawalLimitServiceMock_ResetWithdrawalLimits_Call {
+	return &WithdrawalLimitServiceMock_ResetWithdrawalLimits_Call{Call: _e.mock.On("ResetWithdrawalLimits", ctx, login)}
+}
+
+func (_c *WithdrawalLimitServiceMock_ResetWithdrawalLimits_Call) Run(run func(ctx context.Context, login string)) *WithdrawalLimitServiceMock_ResetWithdrawalLimits_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *WithdrawalLimitServiceMock_ResetWithdrawalLimits_Call) Return(_a0 error) *WithdrawalLimitServiceMock_ResetWithdrawalLimits_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *WithdrawalLimitServiceMock_ResetWithdrawalLimits_Call) RunAndReturn(run func(context.Context, string) error) *WithdrawalLimitServiceMock_ResetWithdrawalLimits_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetWithdrawalLimits provides a mock function with given fields: ctx, login, limits
+func (_m *WithdrawalLimitServiceMock) SetWithdrawalLimits(ctx context.Context, login string, limits domain.WithdrawalLimits) (*domain.UserWithdrawalLimits, error) {
+	ret := _m.Called(ctx, login, limits)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetWithdrawalLimits")
+	}
+
+	var r0 *domain.UserWithdrawalLimits
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.WithdrawalLimits) (*domain.UserWithdrawalLimits, error)); ok {
+		return rf(ctx, login, limits)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.WithdrawalLimits) *domain.UserWithdrawalLimits); ok {
+		r0 = rf(ctx, login, limits)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.UserWithdrawalLimits)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, domain.WithdrawalLimits) error); ok {
+		r1 = rf(ctx, login, limits)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WithdrawalLimitServiceMock_SetWithdrawalLimits_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetWithdrawalLimits'
+type WithdrawalLimitServiceMock_SetWithdrawalLimits_Call struct {
+	*mock.Call
+}
+
+// SetWithdrawalLimits is a helper method to define mock.On call
+//   - ctx context.Context
+//   - login string
+//   - limits domain.WithdrawalLimits
+func (_e *WithdrawalLimitServiceMock_Expecter) SetWithdrawalLimits(ctx interface{}, login interface{}, limits interface{}) *WithdrawalLimitServiceMock_SetWithdrawalLimits_Call {
+	return &WithdrawalLimitServiceMock_SetWithdrawalLimits_Call{Call: _e.mock.On("SetWithdrawalLimits", ctx, login, limits)}
+}
+
+func (_c *WithdrawalLimitServiceMock_SetWithdrawalLimits_Call) Run(run func(ctx context.Context, login string, limits domain.WithdrawalLimits)) *WithdrawalLimitServiceMock_SetWithdrawalLimits_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(domain.WithdrawalLimits))
+	})
+	return _c
+}
+
+func (_c *WithdrawalLimitServiceMock_SetWithdrawalLimits_Call) Return(_a0 *domain.UserWithdrawalLimits, _a1 error) *WithdrawalLimitServiceMock_SetWithdrawalLimits_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *WithdrawalLimitServiceMock_SetWithdrawalLimits_Call) RunAndReturn(run func(context.Context, string, domain.WithdrawalLimits) (*domain.UserWithdrawalLimits, error)) *WithdrawalLimitServiceMock_SetWithdrawalLimits_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewWithdrawalLimitServiceMock creates a new instance of WithdrawalLimitServiceMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewWithdrawalLimitServiceMock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *WithdrawalLimitServiceMock {
+	mock := &WithdrawalLimitServiceMock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
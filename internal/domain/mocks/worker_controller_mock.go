@@ -0,0 +1,181 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// WorkerControllerMock is an autogenerated mock type for the WorkerController type
+type WorkerControllerMock struct {
+	mock.Mock
+}
+
+type WorkerControllerMock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *WorkerControllerMock) EXPECT() *WorkerControllerMock_Expecter {
+	return &WorkerControllerMock_Expecter{mock: &_m.Mock}
+}
+
+// Pause provides a mock function with no fields
+func (_m *WorkerControllerMock) Pause() bool {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Pause")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// WorkerControllerMock_Pause_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Pause'
+type WorkerControllerMock_Pause_Call struct {
+	*mock.Call
+}
+
+// Pause is a helper method to define mock.On call
+func (_e *WorkerControllerMock_Expecter) Pause() *WorkerControllerMock_Pause_Call {
+	return &WorkerControllerMock_Pause_Call{Call: _e.mock.On("Pause")}
+}
+
+func (_c *WorkerControllerMock_Pause_Call) Run(run func()) *WorkerControllerMock_Pause_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *WorkerControllerMock_Pause_Call) Return(_a0 bool) *WorkerControllerMock_Pause_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *WorkerControllerMock_Pause_Call) RunAndReturn(run func() bool) *WorkerControllerMock_Pause_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PausedSince provides a mock function with no fields
+func (_m *WorkerControllerMock) PausedSince() (time.Time, bool) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for PausedSince")
+	}
+
+	var r0 time.Time
+	var r1 bool
+	if rf, ok := ret.Get(0).(func() (time.Time, bool)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() time.Time); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Time)
+	}
+
+	if rf, ok := ret.Get(1).(func() bool); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	return r0, r1
+}
+
+// WorkerControllerMock_PausedSince_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PausedSince'
+type WorkerControllerMock_PausedSince_Call struct {
+	*mock.Call
+}
+
+// PausedSince is a helper method to define mock.On call
+func (_e *WorkerControllerMock_Expecter) PausedSince() *WorkerControllerMock_PausedSince_Call {
+	return &WorkerControllerMock_PausedSince_Call{Call: _e.mock.On("PausedSince")}
+}
+
+func (_c *WorkerControllerMock_PausedSince_Call) Run(run func()) *WorkerControllerMock_PausedSince_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *WorkerControllerMock_PausedSince_Call) Return(_a0 time.Time, _a1 bool) *WorkerControllerMock_PausedSince_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *WorkerControllerMock_PausedSince_Call) RunAndReturn(run func() (time.Time, bool)) *WorkerControllerMock_PausedSince_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Resume provides a mock function with no fields
+func (_m *WorkerControllerMock) Resume() bool {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Resume")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// WorkerControllerMock_Resume_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Resume'
+type WorkerControllerMock_Resume_Call struct {
+	*mock.Call
+}
+
+// Resume is a helper method to define mock.On call
+func (_e *WorkerControllerMock_Expecter) Resume() *WorkerControllerMock_Resume_Call {
+	return &WorkerControllerMock_Resume_Call{Call: _e.mock.On("Resume")}
+}
+
+func (_c *WorkerControllerMock_Resume_Call) Run(run func()) *WorkerControllerMock_Resume_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *WorkerControllerMock_Resume_Call) Return(_a0 bool) *WorkerControllerMock_Resume_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *WorkerControllerMock_Resume_Call) RunAndReturn(run func() bool) *WorkerControllerMock_Resume_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewWorkerControllerMock creates a new instance of WorkerControllerMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewWorkerControllerMock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *WorkerControllerMock {
+	mock := &WorkerControllerMock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
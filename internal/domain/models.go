@@ -1,6 +1,14 @@
 package domain
 
-import "time"
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
 
 // OrderStatus представляет статус заказа
 type OrderStatus string
@@ -12,12 +20,95 @@ const (
 	OrderStatusProcessed  OrderStatus = "PROCESSED"
 )
 
+// IsValid проверяет, что статус заказа известен
+func (s OrderStatus) IsValid() bool {
+	switch s {
+	case OrderStatusNew, OrderStatusProcessing, OrderStatusInvalid, OrderStatusProcessed:
+		return true
+	}
+	return false
+}
+
+// IsFinal сообщает, что обработка заказа завершена и статус больше не изменится
+func (s OrderStatus) IsFinal() bool {
+	return s == OrderStatusInvalid || s == OrderStatusProcessed
+}
+
+// OrderScanPolicy задает опрос системы начислений по заказам в статусе Status.
+// Заказы в финальных статусах не опрашиваются
+type OrderScanPolicy struct {
+	Status   OrderStatus
+	Interval time.Duration // Наименьшая пауза между сканированиями статуса, 0 - при каждом сканировании
+	MaxAge   time.Duration // Заказы, загруженные раньше, не опрашиваются; 0 - без ограничения
+}
+
+// String возвращает политику в формате конфигурации: <статус>=<интервал>[/<возраст>]
+func (p OrderScanPolicy) String() string {
+	value := string(p.Status) + "=" + p.Interval.String()
+	if p.MaxAge > 0 {
+		value += "/" + p.MaxAge.String()
+	}
+	return value
+}
+
+// DefaultOrderScanPolicies возвращает политики по умолчанию: необработанные заказы
+// опрашиваются при каждом сканировании без ограничения возраста
+func DefaultOrderScanPolicies() []OrderScanPolicy {
+	return []OrderScanPolicy{
+		{Status: OrderStatusNew},
+		{Status: OrderStatusProcessing},
+	}
+}
+
+// MaxLoginLength соответствует размеру колонки users.login
+const MaxLoginLength = 255
+
+// MaxOrderNumberLength - предельная длина номера заказа, закрепленная ограничением в БД
+const MaxOrderNumberLength = 64
+
+// MaxOrderStatusBatch - наибольшее число номеров в одном запросе статусов заказов
+const MaxOrderStatusBatch = 500
+
+// AccrualStatus представляет статус расчета в системе начислений
+type AccrualStatus string
+
+const (
+	AccrualStatusRegistered AccrualStatus = "REGISTERED"
+	AccrualStatusInvalid    AccrualStatus = "INVALID"
+	AccrualStatusProcessing AccrualStatus = "PROCESSING"
+	AccrualStatusProcessed  AccrualStatus = "PROCESSED"
+)
+
+// IsValid проверяет, что статус входит в протокол системы начислений
+func (s AccrualStatus) IsValid() bool {
+	switch s {
+	case AccrualStatusRegistered, AccrualStatusInvalid, AccrualStatusProcessing, AccrualStatusProcessed:
+		return true
+	}
+	return false
+}
+
+// OrderStatus переводит статус системы начислений в статус заказа.
+// REGISTERED означает, что расчет еще не начат, поэтому заказ остается в обработке.
+func (s AccrualStatus) OrderStatus() OrderStatus {
+	switch s {
+	case AccrualStatusInvalid:
+		return OrderStatusInvalid
+	case AccrualStatusProcessed:
+		return OrderStatusProcessed
+	default:
+		return OrderStatusProcessing
+	}
+}
+
 // TransactionType представляет тип транзакции
 type TransactionType string
 
 const (
 	TransactionTypeAccrual    TransactionType = "accrual"
 	TransactionTypeWithdrawal TransactionType = "withdrawal"
+	// Корректировка начисления, сумма может быть отрицательной
+	TransactionTypeAdjustment TransactionType = "adjustment"
 )
 
 // User представляет пользователя системы
@@ -28,19 +119,296 @@ type User struct {
 	CreatedAt    time.Time `json:"created_at"`
 }
 
+// UserDataExport - все данные пользователя, выгружаемые по его запросу
+type UserDataExport struct {
+	User         *User
+	Contacts     *UserContacts // nil, если шифрование контактов не настроено
+	Orders       []*Order
+	Transactions []*Transaction
+	GeneratedAt  time.Time
+}
+
+// UserContacts - контактные данные пользователя; в БД хранятся зашифрованными.
+// Пустое поле означает, что контакт не указан.
+type UserContacts struct {
+	Email string
+	Phone string
+}
+
+// UserProfile - настройки пользователя
+type UserProfile struct {
+	Locale string // Язык ошибок API и выписок; пустое значение - язык не выбран
+}
+
+// AccessToken - проверенный токен доступа
+type AccessToken struct {
+	UserID         int64
+	ExpiresAt      time.Time // Нулевое значение - срок действия не ограничен
+	ImpersonatorID int64     // Администратор, действующий от имени пользователя (0 - обычный токен)
+}
+
+// AuthTokens - токены, выданные при регистрации, входе или обновлении
+type AuthTokens struct {
+	AccessToken  string
+	RefreshToken string // Пусто, если refresh-токены не выдаются
+}
+
+// Impersonation - токен, выданный администратору для работы от имени пользователя
+type Impersonation struct {
+	Token          string
+	UserID         int64
+	Login          string
+	ImpersonatorID int64
+	ExpiresAt      time.Time
+}
+
+// ExternalIdentity представляет пользователя внешнего провайдера удостоверений (SSO/OIDC)
+type ExternalIdentity struct {
+	Issuer    string    // Провайдер, выпустивший токен
+	Subject   string    // Неизменный идентификатор пользователя у провайдера
+	Login     string    // Желаемый логин локальной учетной записи
+	ExpiresAt time.Time // Срок действия токена провайдера, если учетная запись получена из него
+}
+
+// FallbackLogin возвращает логин локальной учетной записи, если желаемый уже занят.
+// Существующие учетные записи не связываются по логину: иначе владелец логина
+// у провайдера получил бы доступ к чужому счету.
+func (i ExternalIdentity) FallbackLogin() string {
+	return "oidc:" + i.Subject
+}
+
+// OAuthLogin представляет начатый вход через провайдера OAuth2
+type OAuthLogin struct {
+	URL   string // Страница входа провайдера
+	Nonce string // Значение cookie, которое должен вернуть браузер в callback
+}
+
 // Order представляет заказ пользователя
 type Order struct {
-	ID         int64       `json:"-"`
-	UserID     int64       `json:"-"`
-	Number     string      `json:"number"`
-	Status     OrderStatus `json:"status"`
-	Accrual    *float64    `json:"accrual,omitempty"` // Может быть null
-	UploadedAt time.Time   `json:"uploaded_at"`
+	ID         int64          `json:"-"`
+	PublicID   uuid.UUID      `json:"id"` // Публичный идентификатор для ссылок в API
+	UserID     int64          `json:"-"`
+	Number     string         `json:"number"`
+	Status     OrderStatus    `json:"status"`
+	Accrual    *float64       `json:"accrual,omitempty"` // Может быть null
+	UploadedAt time.Time      `json:"uploaded_at"`
+	Metadata   *OrderMetadata `json:"metadata,omitempty"` // Может быть null
+}
+
+// OrderFilter задает выборку заказов пользователя. Пустые поля не ограничивают выборку,
+// нулевой Limit - все заказы пользователя
+type OrderFilter struct {
+	Status OrderStatus
+	From   time.Time // Заказы, загруженные не раньше From
+	To     time.Time // Заказы, загруженные раньше To
+	Limit  int
+	Offset int
+}
+
+// OrderPage - страница заказов пользователя
+type OrderPage struct {
+	Orders  []*Order
+	HasMore bool // Есть заказы за пределами страницы
+}
+
+// WithdrawalCursor - позиция в истории списаний: последнее списание предыдущей страницы
+type WithdrawalCursor struct {
+	ProcessedAt time.Time
+	ID          int64
+}
+
+// WithdrawalPage - страница истории списаний пользователя
+type WithdrawalPage struct {
+	Withdrawals []*Transaction
+	Next        *WithdrawalCursor // nil - страница последняя
+}
+
+// OrderSearchFilter задает условия поиска заказов в административном API.
+// Пустые поля не ограничивают выборку.
+type OrderSearchFilter struct {
+	NumberPrefix string
+	Login        string
+	Status       OrderStatus
+	Metadata     OrderMetadata // Заполненные поля должны совпасть
+	Limit        int
+	Offset       int
+}
+
+// AdminOrder представляет заказ вместе с логином владельца
+type AdminOrder struct {
+	Order
+	Login string
+}
+
+// OrderSearchResult - страница результатов поиска заказов
+type OrderSearchResult struct {
+	Orders  []*AdminOrder
+	HasMore bool // Есть заказы за пределами страницы
+}
+
+// AccrualMismatch - заказ, начисление которого расходится с записью в журнале транзакций:
+// суммы различаются, у обработанного заказа с начислением нет записи или запись есть у заказа без начисления
+type AccrualMismatch struct {
+	OrderNumber   string
+	UserID        int64
+	Login         string
+	Status        OrderStatus
+	OrderAccrual  *float64 // Начисление в заказе
+	LedgerAccrual *float64 // Начисление в журнале, nil - записи нет
+}
+
+// AccrualMismatchReport - страница отчета о расхождениях начислений
+type AccrualMismatchReport struct {
+	Mismatches []*AccrualMismatch
+	HasMore    bool // Есть расхождения за пределами страницы
+}
+
+// AccrualRepairKind - вид наполовину примененного начисления, которое исправляет сверка после сбоя
+type AccrualRepairKind string
+
+const (
+	// Заказ обработан с начислением, но в журнале нет транзакции начисления
+	AccrualRepairMissingTransaction AccrualRepairKind = "missing_transaction"
+	// В журнале есть начисление по заказу, а заказ остался без финального статуса
+	AccrualRepairMissingStatus AccrualRepairKind = "missing_status"
+)
+
+// AccrualRepair - исправленное расхождение заказа с журналом транзакций
+type AccrualRepair struct {
+	OrderNumber string
+	UserID      int64
+	Kind        AccrualRepairKind
+	Amount      float64 // Начисление, записанное в журнал или в заказ
+}
+
+// MaxDuplicateReportDays ограничивает период отчета о повторных загрузках заказов
+const MaxDuplicateReportDays = 90
+
+// DuplicateSubmissionReport - отчет о повторных загрузках заказов, уже загруженных
+// тем же пользователем, за последние дни
+type DuplicateSubmissionReport struct {
+	Since       time.Time                     // Начало периода, включая текущие сутки
+	Submissions int64                         // Повторных загрузок за период
+	Users       int                           // Пользователей с повторными загрузками
+	Orders      int64                         // Новых заказов за период, для сравнения
+	Channels    []*DuplicateSubmissionChannel // Разбивка по каналу продажи, больше повторов первыми
+	Submitters  []*DuplicateSubmitter         // Страница пользователей, больше повторов первыми
+	HasMore     bool                          // Есть пользователи за пределами страницы
+}
+
+// DuplicateSubmissionChannel - повторные загрузки по каналу продажи из метаданных заказа
+type DuplicateSubmissionChannel struct {
+	Channel     string // Пусто - канал не указан
+	Submissions int64
+	Users       int
+}
+
+// DuplicateSubmitter - повторные загрузки одного пользователя за период отчета
+type DuplicateSubmitter struct {
+	UserID      int64
+	Login       string
+	Submissions int64
+	LastAt      time.Time
+}
+
+// AccrualCorrection - корректировка начисления по заказу, пересчитанному системой начислений
+type AccrualCorrection struct {
+	OrderNumber    string
+	UserID         int64
+	PreviousAmount float64 // Сумма начислений по заказу до корректировки
+	NewAmount      float64
+	Delta          float64 // Сумма транзакции корректировки
+	CorrectedBy    int64   // Администратор, запустивший проверку; 0 - корректировка при обработке заказа
+	CreatedAt      time.Time
+}
+
+// OrderTransfer - перенос заказа и начисления по нему другому пользователю
+type OrderTransfer struct {
+	OrderNumber   string
+	FromUserID    int64
+	FromLogin     string
+	ToUserID      int64
+	ToLogin       string
+	Amount        float64 // Перенесенная сумма начислений по заказу
+	TransferredBy int64   // Администратор, выполнивший перенос
+	CreatedAt     time.Time
+}
+
+// MaxDisputeCommentLength ограничивает длину комментариев к спору о заказе
+const MaxDisputeCommentLength = 1000
+
+// DisputeStatus представляет состояние спора о принадлежности заказа
+type DisputeStatus string
+
+const (
+	DisputeStatusOpen        DisputeStatus = "open"
+	DisputeStatusTransferred DisputeStatus = "transferred" // Заказ передан заявителю
+	DisputeStatusRejected    DisputeStatus = "rejected"
+)
+
+// DisputeResolution - решение администратора по спору
+type DisputeResolution string
+
+const (
+	DisputeResolutionTransfer DisputeResolution = "transfer"
+	DisputeResolutionReject   DisputeResolution = "reject"
+)
+
+// OrderDispute - заявка пользователя на заказ, загруженный другим пользователем.
+// Заявитель получает 409 при загрузке и просит администратора передать ему заказ
+type OrderDispute struct {
+	ID                int64
+	OrderNumber       string
+	ClaimantID        int64
+	ClaimantLogin     string
+	OwnerID           int64 // Владелец заказа на момент открытия спора
+	OwnerLogin        string
+	Comment           string
+	Status            DisputeStatus
+	ResolutionComment string
+	ResolvedBy        *int64
+	CreatedAt         time.Time
+	ResolvedAt        *time.Time
+}
+
+// OrderDisputePage - страница споров о заказах
+type OrderDisputePage struct {
+	Disputes []*OrderDispute
+	HasMore  bool // Есть споры за пределами страницы
+}
+
+// MaxOrderMetadataFieldLength ограничивает длину каждого поля метаданных заказа
+const MaxOrderMetadataFieldLength = 128
+
+// OrderMetadata содержит необязательные сведения о заказе, переданные клиентом
+type OrderMetadata struct {
+	Channel   string `json:"channel,omitempty"`    // Канал продажи
+	StoreID   string `json:"store_id,omitempty"`   // Идентификатор магазина
+	ReceiptID string `json:"receipt_id,omitempty"` // Идентификатор чека
+}
+
+// FiscalReceiptChannel - канал продажи заказов, загруженных по QR коду кассового чека
+const FiscalReceiptChannel = "receipt_qr"
+
+// IsEmpty сообщает, что ни одно поле метаданных не заполнено
+func (m OrderMetadata) IsEmpty() bool {
+	return m.Channel == "" && m.StoreID == "" && m.ReceiptID == ""
+}
+
+// Validate проверяет длину полей метаданных
+func (m OrderMetadata) Validate() error {
+	for _, value := range []string{m.Channel, m.StoreID, m.ReceiptID} {
+		if len(value) > MaxOrderMetadataFieldLength {
+			return ErrInvalidOrderMetadata
+		}
+	}
+	return nil
 }
 
 // Transaction представляет операцию на счете
 type Transaction struct {
 	ID          int64           `json:"-"`
+	PublicID    uuid.UUID       `json:"id"` // Публичный идентификатор для ссылок в API
 	UserID      int64           `json:"-"`
 	OrderNumber string          `json:"order"`
 	Amount      float64         `json:"sum"`
@@ -48,15 +416,329 @@ type Transaction struct {
 	ProcessedAt time.Time       `json:"processed_at"`
 }
 
+// MaxPartnerLength ограничивает длину идентификатора партнера в списании
+const MaxPartnerLength = 128
+
+// MinAmount и MaxAmount - границы суммы операции: копейка и предел колонки DECIMAL(10,2)
+const (
+	MinAmount = 0.01
+	MaxAmount = 99999999.99
+)
+
+// Причины отклонения суммы операции, они же коды ошибки в ответе API
+const (
+	AmountReasonOutOfRange = "sum_out_of_range"
+	AmountReasonPrecision  = "sum_too_precise"
+)
+
+// AmountError сообщает, почему сумма операции отклонена. Сводится к ErrInvalidAmount
+type AmountError struct {
+	Amount float64
+	Reason string
+}
+
+func (e *AmountError) Error() string {
+	return fmt.Sprintf("invalid amount %v: %s", e.Amount, e.Reason)
+}
+
+func (e *AmountError) Unwrap() error {
+	return ErrInvalidAmount
+}
+
+// ValidateAmount проверяет, что сумма конечна, лежит в границах MinAmount..MaxAmount
+// и содержит не больше двух знаков после запятой
+func ValidateAmount(amount float64) error {
+	// Отрицание сравнения отклоняет и NaN
+	if !(amount >= MinAmount && amount <= MaxAmount) {
+		return &AmountError{Amount: amount, Reason: AmountReasonOutOfRange}
+	}
+	// Кратчайшая запись числа совпадает с переданной в JSON, поэтому знаки считаются точно
+	formatted := strconv.FormatFloat(amount, 'f', -1, 64)
+	if dot := strings.IndexByte(formatted, '.'); dot >= 0 && len(formatted)-dot-1 > 2 {
+		return &AmountError{Amount: amount, Reason: AmountReasonPrecision}
+	}
+	return nil
+}
+
+// Settlement - выгрузка списаний для взаиморасчетов с партнерами
+type Settlement struct {
+	ID          uuid.UUID
+	Cutoff      time.Time // В выгрузку вошли списания до начала этих суток
+	Withdrawals int
+	Total       float64
+	CreatedBy   *int64 // Администратор; nil - выгрузка по расписанию
+	CreatedAt   time.Time
+}
+
+// SettlementLine - итог списаний у партнера за сутки. Пустой Partner - списания без партнера
+type SettlementLine struct {
+	Day         time.Time
+	Partner     string
+	Withdrawals int
+	Total       float64
+}
+
+// SettlementPage - страница списка выгрузок
+type SettlementPage struct {
+	Settlements []*Settlement
+	HasMore     bool // Есть выгрузки за пределами страницы
+}
+
+// LedgerBreakReason - причина разрыва цепочки хешей журнала транзакций
+type LedgerBreakReason string
+
+const (
+	LedgerBreakGap          LedgerBreakReason = "gap"           // Пропущен номер в цепочке: запись удалена
+	LedgerBreakHashMismatch LedgerBreakReason = "hash_mismatch" // Хеш не совпадает: запись или предыдущий хеш изменены
+)
+
+// LedgerBreak - первая запись, на которой нарушена цепочка хешей
+type LedgerBreak struct {
+	UserID   int64 // Владелец цепочки
+	Login    string
+	Seq      int64
+	PublicID uuid.UUID
+	Reason   LedgerBreakReason
+}
+
+// LedgerVerification - результат проверки цепочек хешей журнала транзакций.
+// Цепочка ведется отдельно для каждого пользователя
+type LedgerVerification struct {
+	Checked   int64        // Записи в цепочках
+	Unchained int64        // Записи без номера в цепочке, добавленные в обход триггера
+	Chains    int64        // Цепочки пользователей
+	HeadHash  []byte       // Хеш последних записей всех цепочек; сохраненный вне БД, он подтверждает их до этих записей
+	Break     *LedgerBreak // nil - цепочки не нарушены
+}
+
+// IsIntact сообщает, что цепочка не нарушена и все записи журнала входят в нее
+func (v *LedgerVerification) IsIntact() bool {
+	return v.Break == nil && v.Unchained == 0
+}
+
 // Balance представляет баланс пользователя
 type Balance struct {
 	Current   float64 `json:"current"`
 	Withdrawn float64 `json:"withdrawn"`
 }
 
+// PendingAccrual описывает заказы, начисление по которым еще не зачислено
+type PendingAccrual struct {
+	Orders  int     // Заказы в статусах NEW и PROCESSING
+	Accrual float64 // Сумма начислений, уже известная по этим заказам
+}
+
+// ProcessingEstimate - оценка ожидания обработки только что загруженного заказа
+type ProcessingEstimate struct {
+	QueuePosition int           // Необработанные заказы, включая загруженный
+	Wait          time.Duration // Ожидаемое время до обработки
+}
+
+// ProcessingStatus - общее состояние обработки заказов для клиентских приложений
+type ProcessingStatus struct {
+	AverageLatency time.Duration // Среднее время от загрузки до финального статуса
+	Processed      int           // Заказы, по которым посчитано среднее
+	Backlog        int           // Заказы в статусах NEW и PROCESSING
+	UpdatedAt      time.Time     // Момент подсчета
+}
+
+// WithdrawalSummary представляет сводку по списаниям пользователя
+type WithdrawalSummary struct {
+	Count   int        // Количество списаний
+	Total   float64    // Сумма всех списаний
+	Largest float64    // Наибольшее списание
+	LastAt  *time.Time // Время последнего списания, nil - списаний не было
+}
+
+// BalanceDetails представляет баланс вместе с ожидаемыми начислениями
+type BalanceDetails struct {
+	Balance
+	Pending PendingAccrual
+}
+
+// WithdrawalLimits ограничивает списания пользователя.
+// Нулевое значение поля снимает соответствующее ограничение.
+type WithdrawalLimits struct {
+	PerWithdrawal float64 // Максимальная сумма одного списания
+	Daily         float64 // Сумма списаний за последние 24 часа
+	Monthly       float64 // Сумма списаний за последние 30 дней
+}
+
+// Check проверяет списание amount с учетом уже списанного за сутки и за месяц.
+// Суммы сравниваются в копейках, чтобы погрешность float64 не давала ложных отказов.
+func (l WithdrawalLimits) Check(amount, dailyTotal, monthlyTotal float64) error {
+	exceeds := func(total, limit float64) bool {
+		return limit > 0 && math.Round(total*100) > math.Round(limit*100)
+	}
+
+	switch {
+	case exceeds(amount, l.PerWithdrawal):
+		return ErrWithdrawalAmountLimit
+	case exceeds(dailyTotal+amount, l.Daily):
+		return ErrDailyWithdrawalLimit
+	case exceeds(monthlyTotal+amount, l.Monthly):
+		return ErrMonthlyWithdrawalLimit
+	}
+	return nil
+}
+
+// HasWindows сообщает, нужны ли для проверки суммы прежних списаний
+func (l WithdrawalLimits) HasWindows() bool {
+	return l.Daily > 0 || l.Monthly > 0
+}
+
+// SubmissionBanReasonConflicts - пользователь загружал слишком много номеров чужих заказов:
+// так перебором Luhn-валидных номеров выясняют, какие заказы уже загружены
+const SubmissionBanReasonConflicts = "conflicts"
+
+// SubmissionBan - временный запрет пользователю загружать заказы
+type SubmissionBan struct {
+	UserID      int64
+	Login       string
+	Reason      string
+	CreatedAt   time.Time
+	BannedUntil time.Time
+}
+
+// SubmissionLimitError сообщает, что загрузка заказов ограничена и до какого момента.
+// Сводится к ErrTooManySubmissions или ErrSubmissionsBanned
+type SubmissionLimitError struct {
+	Err   error
+	Until time.Time
+}
+
+func (e *SubmissionLimitError) Error() string {
+	return fmt.Sprintf("%s until %s", e.Err, e.Until.Format(time.RFC3339))
+}
+
+func (e *SubmissionLimitError) Unwrap() error {
+	return e.Err
+}
+
+// UserWithdrawalLimits представляет действующие ограничения списаний пользователя
+type UserWithdrawalLimits struct {
+	Login  string
+	Limits WithdrawalLimits
+	// Override - ограничения заданы администратором, иначе действуют значения по умолчанию
+	Override bool
+}
+
+// UserBalance представляет пользователя с балансом для выгрузки
+type UserBalance struct {
+	UserID    int64
+	Login     string
+	CreatedAt time.Time
+	Current   float64
+	Withdrawn float64
+}
+
+// UserMerge - объединение учетной записи-дубликата с основной
+type UserMerge struct {
+	SourceUserID int64
+	SourceLogin  string // Дубликат, отключается после объединения
+	TargetUserID int64
+	TargetLogin  string
+	Orders       int     // Перенесенные заказы
+	Transactions int     // Транзакции дубликата, по которым перенесен баланс
+	Balance      float64 // Баланс дубликата, добавленный к основной записи
+	DryRun       bool    // Предпросмотр без изменений
+	MergedBy     int64
+	CreatedAt    time.Time // Пусто для предпросмотра
+}
+
+// ImportStatus представляет результат импорта одного пользователя
+type ImportStatus string
+
+const (
+	ImportStatusCreated ImportStatus = "created"
+	ImportStatusExists  ImportStatus = "exists"
+	ImportStatusInvalid ImportStatus = "invalid"
+	ImportStatusFailed  ImportStatus = "failed"
+)
+
+// ImportedUser - результат импорта одного логина.
+// TemporaryPassword заполнен только для созданных пользователей.
+type ImportedUser struct {
+	Login             string
+	TemporaryPassword string
+	Status            ImportStatus
+}
+
+// BatchImport - итог записи пачки заказов или транзакций, перенесенных из другой системы.
+// Записи, которые уже были в БД, не входят ни в Imported, ни в Rejected
+type BatchImport struct {
+	Imported int
+	// Rejected - записи, отклоненные БД, по индексу в пачке. Остальные записи пачки сохранены
+	Rejected map[int]error
+}
+
+// OrderEventType представляет тип события заказа
+type OrderEventType string
+
+const (
+	OrderEventProcessed OrderEventType = "order.processed"
+	OrderEventInvalid   OrderEventType = "order.invalid"
+	// Заказ слишком долго ждал расчета и переведен в INVALID автоматически
+	OrderEventExpired OrderEventType = "order.expired"
+	// Спор о заказе решен; событие адресовано заявителю
+	OrderEventDisputeTransferred OrderEventType = "order.dispute_transferred"
+	OrderEventDisputeRejected    OrderEventType = "order.dispute_rejected"
+)
+
+// OrderEvent представляет событие заказа из outbox таблицы
+type OrderEvent struct {
+	ID          int64          `json:"id"`
+	Type        OrderEventType `json:"type"`
+	OrderNumber string         `json:"order"`
+	UserID      int64          `json:"-"`
+	Status      OrderStatus    `json:"status"`
+	Accrual     *float64       `json:"accrual,omitempty"`
+	Metadata    *OrderMetadata `json:"metadata,omitempty"` // Метаданные заказа для подписчиков
+	CreatedAt   time.Time      `json:"created_at"`
+}
+
 // AccrualResponse представляет ответ от системы начислений
 type AccrualResponse struct {
-	Order   string      `json:"order"`
-	Status  OrderStatus `json:"status"`
-	Accrual *float64    `json:"accrual,omitempty"`
+	Order   string        `json:"order"`
+	Status  AccrualStatus `json:"status"`
+	Accrual *float64      `json:"accrual,omitempty"`
+}
+
+// RewardType - способ расчета вознаграждения по правилу системы начислений
+type RewardType string
+
+const (
+	RewardTypePercent RewardType = "%"  // Процент от цены товара
+	RewardTypePoints  RewardType = "pt" // Фиксированное число баллов
+)
+
+// IsValid проверяет, известен ли способ расчета
+func (t RewardType) IsValid() bool {
+	return t == RewardTypePercent || t == RewardTypePoints
+}
+
+// RewardRule - правило вознаграждения системы начислений: товары, в названии
+// которых встречается Match, приносят Reward процентов или баллов
+type RewardRule struct {
+	Match      string     `json:"match"`
+	Reward     float64    `json:"reward"`
+	RewardType RewardType `json:"reward_type"`
+	UpdatedAt  time.Time  `json:"-"` // Когда правило последний раз изменилось в локальной копии
+}
+
+// RewardRuleSync - итог синхронизации локальной копии правил вознаграждения
+type RewardRuleSync struct {
+	Changed   bool // Набор правил отличается от прежнего
+	Added     int
+	Updated   int
+	Removed   int
+	CheckedAt time.Time
+}
+
+// RewardRuleSet - локальная копия правил вознаграждения.
+// Нулевые CheckedAt и ChangedAt означают, что синхронизации еще не было
+type RewardRuleSet struct {
+	Rules     []*RewardRule
+	CheckedAt time.Time // Последняя успешная синхронизация
+	ChangedAt time.Time // Последнее изменение набора правил
 }
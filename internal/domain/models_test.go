@@ -0,0 +1,130 @@
+package domain
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccrualStatus(t *testing.T) {
+	tests := []struct {
+		status      AccrualStatus
+		valid       bool
+		orderStatus OrderStatus
+	}{
+		{AccrualStatusRegistered, true, OrderStatusProcessing},
+		{AccrualStatusProcessing, true, OrderStatusProcessing},
+		{AccrualStatusInvalid, true, OrderStatusInvalid},
+		{AccrualStatusProcessed, true, OrderStatusProcessed},
+		{AccrualStatus("UNKNOWN"), false, OrderStatusProcessing},
+		{AccrualStatus(""), false, OrderStatusProcessing},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			assert.Equal(t, tt.valid, tt.status.IsValid())
+			assert.Equal(t, tt.orderStatus, tt.status.OrderStatus())
+		})
+	}
+}
+
+func TestWithdrawalLimits_Check(t *testing.T) {
+	limits := WithdrawalLimits{PerWithdrawal: 500, Daily: 1000, Monthly: 3000}
+
+	tests := []struct {
+		name    string
+		limits  WithdrawalLimits
+		amount  float64
+		daily   float64
+		monthly float64
+		wantErr error
+	}{
+		{name: "Within limits", limits: limits, amount: 500, daily: 500, monthly: 2500},
+		{name: "Per withdrawal", limits: limits, amount: 500.01, wantErr: ErrWithdrawalAmountLimit},
+		{name: "Daily", limits: limits, amount: 100, daily: 950, wantErr: ErrDailyWithdrawalLimit},
+		{name: "Monthly", limits: limits, amount: 100, daily: 0, monthly: 2950, wantErr: ErrMonthlyWithdrawalLimit},
+		// 0.1 + 0.2 в float64 больше 0.3, но в копейках суммы равны
+		{name: "Float rounding", limits: WithdrawalLimits{Daily: 0.3}, amount: 0.2, daily: 0.1},
+		{name: "No limits", amount: 1e6, daily: 1e6, monthly: 1e6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.limits.Check(tt.amount, tt.daily, tt.monthly), tt.wantErr)
+		})
+	}
+
+	assert.True(t, limits.HasWindows())
+	assert.False(t, WithdrawalLimits{PerWithdrawal: 100}.HasWindows())
+}
+
+func TestOrderMetadata_Validate(t *testing.T) {
+	assert.True(t, OrderMetadata{}.IsEmpty())
+	assert.False(t, OrderMetadata{StoreID: "42"}.IsEmpty())
+
+	assert.NoError(t, OrderMetadata{Channel: "web", StoreID: "42", ReceiptID: "R-1"}.Validate())
+	assert.NoError(t, OrderMetadata{}.Validate())
+
+	long := strings.Repeat("x", MaxOrderMetadataFieldLength+1)
+	assert.ErrorIs(t, OrderMetadata{ReceiptID: long}.Validate(), ErrInvalidOrderMetadata)
+}
+
+func TestValidateAmount(t *testing.T) {
+	tests := []struct {
+		name   string
+		amount float64
+		reason string
+	}{
+		{"Minimum", 0.01, ""},
+		{"Maximum", 99999999.99, ""},
+		{"Two decimals", 0.29, ""},
+		{"Whole", 751, ""},
+		{"Zero", 0, AmountReasonOutOfRange},
+		{"Negative zero", math.Copysign(0, -1), AmountReasonOutOfRange},
+		{"Negative", -100, AmountReasonOutOfRange},
+		{"Above column precision", 1e10, AmountReasonOutOfRange},
+		{"Huge", 1e308, AmountReasonOutOfRange},
+		{"Infinity", math.Inf(1), AmountReasonOutOfRange},
+		{"NaN", math.NaN(), AmountReasonOutOfRange},
+		{"Below a kopeck", 0.001, AmountReasonOutOfRange},
+		{"Fraction of a kopeck", 10.005, AmountReasonPrecision},
+		{"Smallest fraction near maximum", 99999999.989, AmountReasonPrecision},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAmount(tt.amount)
+			if tt.reason == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var amountErr *AmountError
+			require.ErrorAs(t, err, &amountErr)
+			assert.Equal(t, tt.reason, amountErr.Reason)
+			assert.ErrorIs(t, err, ErrInvalidAmount)
+		})
+	}
+}
+
+func TestOrderStatus_IsValid(t *testing.T) {
+	assert.True(t, OrderStatusNew.IsValid())
+	assert.True(t, OrderStatusProcessed.IsValid())
+	assert.False(t, OrderStatus("processed").IsValid())
+	assert.False(t, OrderStatus("").IsValid())
+}
+
+func TestOrderStatus_IsFinal(t *testing.T) {
+	assert.True(t, OrderStatusProcessed.IsFinal())
+	assert.True(t, OrderStatusInvalid.IsFinal())
+	assert.False(t, OrderStatusNew.IsFinal())
+	assert.False(t, OrderStatusProcessing.IsFinal())
+}
+
+func TestLedgerVerification_IsIntact(t *testing.T) {
+	assert.True(t, (&LedgerVerification{Checked: 5, Chains: 2}).IsIntact())
+	assert.False(t, (&LedgerVerification{Checked: 5, Unchained: 1}).IsIntact())
+	assert.False(t, (&LedgerVerification{Checked: 5, Break: &LedgerBreak{UserID: 1, Seq: 2, Reason: LedgerBreakHashMismatch}}).IsIntact())
+}
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+)
+
+// RoundingMode определяет способ округления начислений
+type RoundingMode string
+
+const (
+	RoundingFloor   RoundingMode = "floor"   // Отбрасывание знаков сверх точности
+	RoundingHalfUp  RoundingMode = "round"   // Половина округляется от нуля
+	RoundingBankers RoundingMode = "bankers" // Половина округляется к четному
+)
+
+// MaxRoundingPrecision соответствует точности колонок сумм DECIMAL(10,2)
+const MaxRoundingPrecision = 2
+
+// RoundingPolicy описывает округление начислений перед записью в БД
+type RoundingPolicy struct {
+	Mode      RoundingMode
+	Precision int // Знаков после запятой, от 0 до MaxRoundingPrecision
+}
+
+// DefaultRoundingPolicy округляет до копеек по математическим правилам
+func DefaultRoundingPolicy() RoundingPolicy {
+	return RoundingPolicy{Mode: RoundingHalfUp, Precision: MaxRoundingPrecision}
+}
+
+// IsValid проверяет, что режим известен, а точность помещается в колонку
+func (p RoundingPolicy) IsValid() bool {
+	switch p.Mode {
+	case RoundingFloor, RoundingHalfUp, RoundingBankers:
+		return p.Precision >= 0 && p.Precision <= MaxRoundingPrecision
+	}
+	return false
+}
+
+// String возвращает политику в виде "режим:точность", в таком виде она сохраняется для аудита
+func (p RoundingPolicy) String() string {
+	return fmt.Sprintf("%s:%d", p.Mode, p.Precision)
+}
+
+// Apply округляет значение по политике.
+// Перед округлением убирается погрешность представления float64 (1.15*100 = 114.99999999999999),
+// чтобы граничные значения округлялись по своей десятичной записи.
+func (p RoundingPolicy) Apply(value float64) float64 {
+	scale := math.Pow10(p.Precision)
+	scaled := math.Round(value*scale*1e6) / 1e6
+
+	switch p.Mode {
+	case RoundingFloor:
+		scaled = math.Floor(scaled)
+	case RoundingBankers:
+		scaled = math.RoundToEven(scaled)
+	default:
+		scaled = math.Round(scaled)
+	}
+	return scaled / scale
+}
+
+// RoundAccrual применяет политику к начислению из ответа системы начислений
+func (p RoundingPolicy) RoundAccrual(raw float64) *Accrual {
+	return &Accrual{Amount: p.Apply(raw), Raw: raw, Policy: p}
+}
+
+// Accrual представляет начисление по заказу после округления
+type Accrual struct {
+	Amount float64        // Сумма к зачислению
+	Raw    float64        // Сумма из ответа системы начислений
+	Policy RoundingPolicy // Примененная политика, сохраняется вместе с транзакцией
+}
@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoundingPolicy_Apply(t *testing.T) {
+	tests := []struct {
+		policy RoundingPolicy
+		value  float64
+		want   float64
+	}{
+		{RoundingPolicy{Mode: RoundingHalfUp, Precision: 2}, 1.005, 1.01},
+		{RoundingPolicy{Mode: RoundingHalfUp, Precision: 2}, 1.15, 1.15},
+		{RoundingPolicy{Mode: RoundingHalfUp, Precision: 2}, 0.125, 0.13},
+		{RoundingPolicy{Mode: RoundingBankers, Precision: 2}, 0.125, 0.12},
+		{RoundingPolicy{Mode: RoundingBankers, Precision: 2}, 0.135, 0.14},
+		{RoundingPolicy{Mode: RoundingFloor, Precision: 2}, 1.159, 1.15},
+		// 1.15*100 в float64 меньше 115, отбрасывание не должно терять копейку
+		{RoundingPolicy{Mode: RoundingFloor, Precision: 2}, 1.15, 1.15},
+		{RoundingPolicy{Mode: RoundingFloor, Precision: 0}, 729.98, 729},
+		{RoundingPolicy{Mode: RoundingBankers, Precision: 0}, 2.5, 2},
+		{RoundingPolicy{Mode: RoundingHalfUp, Precision: 0}, 2.5, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy.String(), func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.policy.Apply(tt.value))
+		})
+	}
+}
+
+func TestRoundingPolicy_IsValid(t *testing.T) {
+	assert.True(t, DefaultRoundingPolicy().IsValid())
+	assert.True(t, RoundingPolicy{Mode: RoundingFloor, Precision: 0}.IsValid())
+	assert.False(t, RoundingPolicy{Mode: RoundingFloor, Precision: 3}.IsValid())
+	assert.False(t, RoundingPolicy{Mode: RoundingFloor, Precision: -1}.IsValid())
+	assert.False(t, RoundingPolicy{Mode: "ceil", Precision: 2}.IsValid())
+	assert.False(t, RoundingPolicy{}.IsValid())
+}
+
+func TestRoundingPolicy_RoundAccrual(t *testing.T) {
+	policy := RoundingPolicy{Mode: RoundingBankers, Precision: 2}
+
+	accrual := policy.RoundAccrual(100.125)
+	assert.Equal(t, 100.12, accrual.Amount)
+	assert.Equal(t, 100.125, accrual.Raw)
+	assert.Equal(t, "bankers:2", accrual.Policy.String())
+}
@@ -0,0 +1,164 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"go.uber.org/zap"
+)
+
+// EventStore определяет методы outbox таблицы событий заказов
+type EventStore interface {
+	GetPendingEvents(ctx context.Context, limit int) ([]*domain.OrderEvent, error)
+	MarkEventsDispatched(ctx context.Context, ids []int64) error
+}
+
+// Handler обрабатывает событие заказа.
+// Доставка выполняется "хотя бы один раз", поэтому обработчик должен быть идемпотентным.
+type Handler func(ctx context.Context, event *domain.OrderEvent) error
+
+// DispatcherConfig содержит настройки диспетчера событий
+type DispatcherConfig struct {
+	PollInterval time.Duration // Интервал опроса outbox таблицы
+	BatchSize    int           // Максимум событий за один запрос
+}
+
+// DefaultDispatcherConfig возвращает конфигурацию по умолчанию
+func DefaultDispatcherConfig() DispatcherConfig {
+	return DispatcherConfig{
+		PollInterval: time.Second,
+		BatchSize:    100,
+	}
+}
+
+// Dispatcher читает события из outbox таблицы и рассылает их подписчикам
+type Dispatcher struct {
+	store       EventStore
+	config      DispatcherConfig
+	mu          sync.RWMutex
+	subscribers []subscriber
+	logger      *zap.Logger
+	wg          sync.WaitGroup
+}
+
+type subscriber struct {
+	name   string
+	handle Handler
+}
+
+// NewDispatcher создает новый диспетчер событий
+func NewDispatcher(store EventStore, config DispatcherConfig, logger *zap.Logger) *Dispatcher {
+	defaults := DefaultDispatcherConfig()
+	if config.PollInterval <= 0 {
+		config.PollInterval = defaults.PollInterval
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaults.BatchSize
+	}
+
+	return &Dispatcher{
+		store:  store,
+		config: config,
+		logger: logger,
+	}
+}
+
+// Subscribe регистрирует подписчика на все события заказов
+func (d *Dispatcher) Subscribe(name string, handler Handler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.subscribers = append(d.subscribers, subscriber{name: name, handle: handler})
+}
+
+// Start запускает опрос outbox таблицы до отмены контекста
+func (d *Dispatcher) Start(ctx context.Context) {
+	d.wg.Add(1)
+	go d.run(ctx)
+}
+
+// Stop ожидает завершения диспетчера после отмены контекста
+func (d *Dispatcher) Stop() {
+	d.wg.Wait()
+}
+
+func (d *Dispatcher) run(ctx context.Context) {
+	defer d.wg.Done()
+
+	ticker := time.NewTicker(d.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			d.logger.Info("event dispatcher stopping")
+			return
+		case <-ticker.C:
+			// Выбираем пачки, пока таблица не опустеет
+			for {
+				n, err := d.dispatchPending(ctx)
+				if err != nil {
+					d.logger.Error("failed to dispatch order events", zap.Error(err))
+					break
+				}
+				if n < d.config.BatchSize {
+					break
+				}
+			}
+		}
+	}
+}
+
+// dispatchPending доставляет одну пачку событий и возвращает ее размер
+func (d *Dispatcher) dispatchPending(ctx context.Context) (int, error) {
+	events, err := d.store.GetPendingEvents(ctx, d.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	if len(events) == 0 {
+		return 0, nil
+	}
+
+	d.mu.RLock()
+	subscribers := d.subscribers
+	d.mu.RUnlock()
+
+	ids := make([]int64, 0, len(events))
+	for _, event := range events {
+		for _, s := range subscribers {
+			d.deliver(ctx, s, event)
+		}
+		ids = append(ids, event.ID)
+	}
+
+	if err := d.store.MarkEventsDispatched(ctx, ids); err != nil {
+		return 0, err
+	}
+
+	d.logger.Debug("order events dispatched", zap.Int("count", len(events)))
+	return len(events), nil
+}
+
+// deliver вызывает подписчика, изолируя его ошибки и паники от остальных
+func (d *Dispatcher) deliver(ctx context.Context, s subscriber, event *domain.OrderEvent) {
+	defer func() {
+		if r := recover(); r != nil {
+			d.logger.Error("order event subscriber panicked",
+				zap.String("subscriber", s.name),
+				zap.Int64("event_id", event.ID),
+				zap.String("panic", fmt.Sprint(r)),
+			)
+		}
+	}()
+
+	if err := s.handle(ctx, event); err != nil {
+		d.logger.Error("order event subscriber failed",
+			zap.String("subscriber", s.name),
+			zap.Int64("event_id", event.ID),
+			zap.String("type", string(event.Type)),
+			zap.Error(err),
+		)
+	}
+}
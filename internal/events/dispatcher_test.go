@@ -0,0 +1,144 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	domainmocks "github.com/avc/loyalty-system-diploma/internal/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestDispatcher(t *testing.T) (*Dispatcher, *domainmocks.EventStoreMock) {
+	store := domainmocks.NewEventStoreMock(t)
+	d := NewDispatcher(store, DispatcherConfig{PollInterval: 10 * time.Millisecond, BatchSize: 2}, zap.NewNop())
+	return d, store
+}
+
+func TestNewDispatcher_Defaults(t *testing.T) {
+	d := NewDispatcher(nil, DispatcherConfig{}, zap.NewNop())
+
+	assert.Equal(t, DefaultDispatcherConfig(), d.config)
+}
+
+func TestDispatcher_DispatchPending(t *testing.T) {
+	ctx := context.Background()
+	events := []*domain.OrderEvent{
+		{ID: 1, Type: domain.OrderEventProcessed, OrderNumber: "111"},
+		{ID: 2, Type: domain.OrderEventInvalid, OrderNumber: "222"},
+	}
+
+	t.Run("Delivers to all subscribers", func(t *testing.T) {
+		d, store := newTestDispatcher(t)
+
+		var first, second []int64
+		d.Subscribe("first", func(_ context.Context, e *domain.OrderEvent) error {
+			first = append(first, e.ID)
+			return nil
+		})
+		d.Subscribe("second", func(_ context.Context, e *domain.OrderEvent) error {
+			second = append(second, e.ID)
+			return nil
+		})
+
+		store.EXPECT().GetPendingEvents(ctx, 2).Return(events, nil).Once()
+		store.EXPECT().MarkEventsDispatched(ctx, []int64{1, 2}).Return(nil).Once()
+
+		n, err := d.dispatchPending(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 2, n)
+		assert.Equal(t, []int64{1, 2}, first)
+		assert.Equal(t, []int64{1, 2}, second)
+	})
+
+	t.Run("Failing subscriber does not block others", func(t *testing.T) {
+		d, store := newTestDispatcher(t)
+
+		var delivered int
+		d.Subscribe("failing", func(context.Context, *domain.OrderEvent) error {
+			return errors.New("subscriber error")
+		})
+		d.Subscribe("panicking", func(context.Context, *domain.OrderEvent) error {
+			panic("boom")
+		})
+		d.Subscribe("healthy", func(context.Context, *domain.OrderEvent) error {
+			delivered++
+			return nil
+		})
+
+		store.EXPECT().GetPendingEvents(ctx, 2).Return(events, nil).Once()
+		store.EXPECT().MarkEventsDispatched(ctx, []int64{1, 2}).Return(nil).Once()
+
+		n, err := d.dispatchPending(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 2, n)
+		assert.Equal(t, 2, delivered)
+	})
+
+	t.Run("No pending events", func(t *testing.T) {
+		d, store := newTestDispatcher(t)
+
+		store.EXPECT().GetPendingEvents(ctx, 2).Return(nil, nil).Once()
+
+		n, err := d.dispatchPending(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, n)
+	})
+
+	t.Run("Store error", func(t *testing.T) {
+		d, store := newTestDispatcher(t)
+
+		store.EXPECT().GetPendingEvents(ctx, 2).Return(nil, errors.New("database error")).Once()
+
+		_, err := d.dispatchPending(ctx)
+		assert.Error(t, err)
+	})
+
+	t.Run("Mark dispatched error", func(t *testing.T) {
+		d, store := newTestDispatcher(t)
+
+		store.EXPECT().GetPendingEvents(ctx, 2).Return(events[:1], nil).Once()
+		store.EXPECT().MarkEventsDispatched(ctx, []int64{1}).Return(errors.New("database error")).Once()
+
+		_, err := d.dispatchPending(ctx)
+		assert.Error(t, err)
+	})
+}
+
+func TestDispatcher_StartStop(t *testing.T) {
+	d, store := newTestDispatcher(t)
+
+	delivered := make(chan int64, 3)
+	d.Subscribe("test", func(_ context.Context, e *domain.OrderEvent) error {
+		delivered <- e.ID
+		return nil
+	})
+
+	// Полная пачка выбирается повторно без ожидания следующего тика
+	store.EXPECT().GetPendingEvents(mock.Anything, 2).
+		Return([]*domain.OrderEvent{{ID: 1}, {ID: 2}}, nil).Once()
+	store.EXPECT().GetPendingEvents(mock.Anything, 2).
+		Return([]*domain.OrderEvent{{ID: 3}}, nil).Once()
+	store.EXPECT().GetPendingEvents(mock.Anything, 2).Return(nil, nil).Maybe()
+	store.EXPECT().MarkEventsDispatched(mock.Anything, mock.Anything).Return(nil).Times(2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	d.Start(ctx)
+
+	for _, want := range []int64{1, 2, 3} {
+		select {
+		case id := <-delivered:
+			assert.Equal(t, want, id)
+		case <-time.After(time.Second):
+			t.Fatalf("event %d was not delivered", want)
+		}
+	}
+
+	cancel()
+	d.Stop()
+}
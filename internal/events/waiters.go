@@ -0,0 +1,61 @@
+package events
+
+import (
+	"context"
+	"sync"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+)
+
+// OrderWaiters будит запросы, ожидающие завершения обработки заказа.
+// Подписывается на диспетчер через Handle.
+type OrderWaiters struct {
+	mu      sync.Mutex
+	waiters map[string]map[*orderWaiter]struct{}
+}
+
+type orderWaiter struct {
+	done chan struct{}
+}
+
+// NewOrderWaiters создает новый OrderWaiters
+func NewOrderWaiters() *OrderWaiters {
+	return &OrderWaiters{waiters: make(map[string]map[*orderWaiter]struct{})}
+}
+
+// Wait регистрирует ожидание события по заказу. Канал закрывается при получении события,
+// функция отмены снимает регистрацию и должна вызываться всегда.
+// Регистрироваться нужно до проверки статуса заказа, чтобы не пропустить событие между ними.
+func (w *OrderWaiters) Wait(number string) (<-chan struct{}, func()) {
+	waiter := &orderWaiter{done: make(chan struct{})}
+
+	w.mu.Lock()
+	if w.waiters[number] == nil {
+		w.waiters[number] = make(map[*orderWaiter]struct{})
+	}
+	w.waiters[number][waiter] = struct{}{}
+	w.mu.Unlock()
+
+	cancel := func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		delete(w.waiters[number], waiter)
+		if len(w.waiters[number]) == 0 {
+			delete(w.waiters, number)
+		}
+	}
+	return waiter.done, cancel
+}
+
+// Handle будит всех ожидающих заказ из события. Повторная доставка события безопасна.
+func (w *OrderWaiters) Handle(_ context.Context, event *domain.OrderEvent) error {
+	w.mu.Lock()
+	waiters := w.waiters[event.OrderNumber]
+	delete(w.waiters, event.OrderNumber)
+	w.mu.Unlock()
+
+	for waiter := range waiters {
+		close(waiter.done)
+	}
+	return nil
+}
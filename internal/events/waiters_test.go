@@ -0,0 +1,61 @@
+package events
+
+import (
+	"context"
+	"testing"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderWaiters(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Event wakes all waiters of the order", func(t *testing.T) {
+		w := NewOrderWaiters()
+		first, cancelFirst := w.Wait("111")
+		defer cancelFirst()
+		second, cancelSecond := w.Wait("111")
+		defer cancelSecond()
+		other, cancelOther := w.Wait("222")
+		defer cancelOther()
+
+		require.NoError(t, w.Handle(ctx, &domain.OrderEvent{OrderNumber: "111"}))
+
+		assert.True(t, isClosed(first))
+		assert.True(t, isClosed(second))
+		assert.False(t, isClosed(other))
+	})
+
+	t.Run("Repeated event is safe", func(t *testing.T) {
+		w := NewOrderWaiters()
+		done, cancel := w.Wait("111")
+		defer cancel()
+
+		require.NoError(t, w.Handle(ctx, &domain.OrderEvent{OrderNumber: "111"}))
+		require.NoError(t, w.Handle(ctx, &domain.OrderEvent{OrderNumber: "111"}))
+
+		assert.True(t, isClosed(done))
+	})
+
+	t.Run("Cancel removes waiter", func(t *testing.T) {
+		w := NewOrderWaiters()
+		done, cancel := w.Wait("111")
+		cancel()
+
+		require.NoError(t, w.Handle(ctx, &domain.OrderEvent{OrderNumber: "111"}))
+
+		assert.False(t, isClosed(done))
+		assert.Empty(t, w.waiters)
+	})
+}
+
+func isClosed(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,274 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/avc/loyalty-system-diploma/internal/jobs"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// AdminChecker определяет проверку прав администратора.
+type AdminChecker interface {
+	IsAdmin(ctx context.Context, userID int64) (bool, error)
+}
+
+// UserAdminService определяет методы массовых операций с пользователями.
+type UserAdminService interface {
+	ImportUsers(ctx context.Context, logins []string, progress jobs.Progress) ([]domain.ImportedUser, error)
+	ExportUsers(ctx context.Context, progress jobs.Progress) ([]*domain.UserBalance, error)
+}
+
+// JobRunner определяет запуск фоновых задач и получение их состояния.
+type JobRunner interface {
+	Start(jobType string, fn jobs.Func) jobs.Snapshot
+	Get(id string) (jobs.Snapshot, error)
+	Result(id string) (jobs.Snapshot, *jobs.Result, error)
+}
+
+const (
+	jobTypeUsersImport = "users_import"
+	jobTypeUsersExport = "users_export"
+
+	// maxImportBodySize ограничивает размер загружаемого CSV файла
+	maxImportBodySize = 10 << 20
+	// importLoginColumn - обязательная колонка CSV файла импорта
+	importLoginColumn = "login"
+
+	mediaTypeCSV = "text/csv; charset=utf-8"
+)
+
+// AdminMiddleware пропускает только администраторов. Токен, выданный для работы
+// от имени пользователя, к административным эндпоинтам не допускается.
+// Должен подключаться после AuthMiddleware.
+func AdminMiddleware(checker AdminChecker, logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := GetUserID(r.Context())
+			if !ok {
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+			if _, impersonated := GetImpersonatorID(r.Context()); impersonated {
+				http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+				return
+			}
+
+			isAdmin, err := checker.IsAdmin(r.Context(), userID)
+			if err != nil {
+				logger.Error("failed to check admin rights", zap.Error(err), zap.Int64("user_id", userID))
+				writeInternalError(w, err)
+				return
+			}
+			if !isAdmin {
+				http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+type AdminHandler struct {
+	userAdminService UserAdminService
+	jobs             JobRunner
+	logger           *zap.Logger
+}
+
+func NewAdminHandler(userAdminService UserAdminService, jobs JobRunner, logger *zap.Logger) *AdminHandler {
+	return &AdminHandler{
+		userAdminService: userAdminService,
+		jobs:             jobs,
+		logger:           logger,
+	}
+}
+
+// ImportUsers принимает CSV файл с колонкой login и запускает задачу импорта.
+// Результат задачи - CSV с временными паролями созданных пользователей.
+func (h *AdminHandler) ImportUsers(w http.ResponseWriter, r *http.Request) {
+	logins, err := readImportLogins(http.MaxBytesReader(w, r.Body, maxImportBodySize))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+			return
+		}
+		writeJSONError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	snapshot := h.jobs.Start(jobTypeUsersImport, func(ctx context.Context, progress jobs.Progress) (*jobs.Result, error) {
+		imported, err := h.userAdminService.ImportUsers(ctx, logins, progress)
+		if err != nil {
+			return nil, err
+		}
+		return encodeImportResult(imported)
+	})
+
+	writeJobAccepted(w, snapshot, h.logger)
+}
+
+// ExportUsers запускает задачу выгрузки пользователей с балансами в CSV
+func (h *AdminHandler) ExportUsers(w http.ResponseWriter, r *http.Request) {
+	snapshot := h.jobs.Start(jobTypeUsersExport, func(ctx context.Context, progress jobs.Progress) (*jobs.Result, error) {
+		balances, err := h.userAdminService.ExportUsers(ctx, progress)
+		if err != nil {
+			return nil, err
+		}
+		return encodeExportResult(balances)
+	})
+
+	writeJobAccepted(w, snapshot, h.logger)
+}
+
+// GetJob возвращает состояние и прогресс задачи
+func (h *AdminHandler) GetJob(w http.ResponseWriter, r *http.Request) {
+	snapshot, err := h.jobs.Get(chi.URLParam(r, "id"))
+	if err != nil {
+		writeJSONError(w, r, http.StatusNotFound, err.Error())
+		return
+	}
+
+	writeJob(w, http.StatusOK, snapshot, h.logger)
+}
+
+// GetJobResult отдает результат успешно завершенной задачи
+func (h *AdminHandler) GetJobResult(w http.ResponseWriter, r *http.Request) {
+	snapshot, result, err := h.jobs.Result(chi.URLParam(r, "id"))
+	if err != nil {
+		writeJSONError(w, r, http.StatusNotFound, err.Error())
+		return
+	}
+
+	switch {
+	case snapshot.Status == jobs.StatusRunning:
+		writeJSONError(w, r, http.StatusConflict, "job is still running")
+		return
+	case snapshot.Status == jobs.StatusFailed:
+		writeJSONError(w, r, http.StatusConflict, "job failed: "+snapshot.Error)
+		return
+	case result == nil:
+		writeJSONError(w, r, http.StatusConflict, "job has no result")
+		return
+	}
+
+	w.Header().Set("Content-Type", result.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-%s.csv"`, snapshot.Type, snapshot.ID))
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(result.Data); err != nil {
+		h.logger.Error("failed to write job result", zap.Error(err), zap.String("job_id", snapshot.ID))
+	}
+}
+
+// writeJobAccepted отвечает 202 со ссылкой на состояние запущенной задачи
+func writeJobAccepted(w http.ResponseWriter, snapshot jobs.Snapshot, logger *zap.Logger) {
+	w.Header().Set("Location", jobPath(snapshot.ID))
+	writeJob(w, http.StatusAccepted, snapshot, logger)
+}
+
+func writeJob(w http.ResponseWriter, status int, snapshot jobs.Snapshot, logger *zap.Logger) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(newJobResponse(snapshot)); err != nil {
+		logger.Error("failed to encode job response", zap.Error(err))
+	}
+}
+
+// jobPath возвращает путь эндпоинта состояния задачи
+func jobPath(id string) string {
+	return "/api/admin/jobs/" + id
+}
+
+// readImportLogins читает логины из CSV файла с заголовком, содержащим колонку login
+func readImportLogins(body io.Reader) ([]string, error) {
+	reader := csv.NewReader(body)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("csv file is empty")
+		}
+		return nil, fmt.Errorf("failed to read csv header: %w", err)
+	}
+
+	loginColumn := -1
+	for i, name := range header {
+		if strings.EqualFold(strings.TrimSpace(name), importLoginColumn) {
+			loginColumn = i
+			break
+		}
+	}
+	if loginColumn < 0 {
+		return nil, fmt.Errorf("csv header must contain %q column", importLoginColumn)
+	}
+
+	var logins []string
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read csv: %w", err)
+		}
+
+		login := ""
+		if loginColumn < len(record) {
+			login = strings.TrimSpace(record[loginColumn])
+		}
+		logins = append(logins, login)
+	}
+
+	if len(logins) == 0 {
+		return nil, errors.New("csv file contains no users")
+	}
+	return logins, nil
+}
+
+// encodeImportResult формирует CSV с результатом импорта
+func encodeImportResult(imported []domain.ImportedUser) (*jobs.Result, error) {
+	rows := make([][]string, 0, len(imported)+1)
+	rows = append(rows, []string{"login", "temporary_password", "status"})
+	for _, u := range imported {
+		rows = append(rows, []string{u.Login, u.TemporaryPassword, string(u.Status)})
+	}
+	return encodeCSVResult(rows)
+}
+
+// encodeExportResult формирует CSV с выгрузкой пользователей
+func encodeExportResult(balances []*domain.UserBalance) (*jobs.Result, error) {
+	rows := make([][]string, 0, len(balances)+1)
+	rows = append(rows, []string{"user_id", "login", "created_at", "current", "withdrawn"})
+	for _, b := range balances {
+		rows = append(rows, []string{
+			strconv.FormatInt(b.UserID, 10),
+			b.Login,
+			b.CreatedAt.UTC().Format(time.RFC3339),
+			strconv.FormatFloat(b.Current, 'f', 2, 64),
+			strconv.FormatFloat(b.Withdrawn, 'f', 2, 64),
+		})
+	}
+	return encodeCSVResult(rows)
+}
+
+func encodeCSVResult(rows [][]string) (*jobs.Result, error) {
+	var buf bytes.Buffer
+	if err := csv.NewWriter(&buf).WriteAll(rows); err != nil {
+		return nil, fmt.Errorf("failed to encode csv: %w", err)
+	}
+	return &jobs.Result{ContentType: mediaTypeCSV, Data: buf.Bytes()}, nil
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/avc/loyalty-system-diploma/internal/config"
+	"go.uber.org/zap"
+)
+
+// ConfigHandler отдает администраторам действующую конфигурацию сервиса
+type ConfigHandler struct {
+	settings []config.Setting
+	logger   *zap.Logger
+}
+
+// NewConfigHandler создает новый ConfigHandler. Конфигурация не меняется после старта,
+// поэтому параметры передаются один раз; секреты в них уже скрыты.
+func NewConfigHandler(settings []config.Setting, logger *zap.Logger) *ConfigHandler {
+	return &ConfigHandler{
+		settings: settings,
+		logger:   logger,
+	}
+}
+
+// Get возвращает параметры конфигурации с источником каждого значения
+func (h *ConfigHandler) Get(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newConfigResponse(h.settings)); err != nil {
+		h.logger.Error("failed to encode config response", zap.Error(err))
+	}
+}
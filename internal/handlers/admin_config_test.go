@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/avc/loyalty-system-diploma/internal/config"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestConfigHandler_Get(t *testing.T) {
+	handler := NewConfigHandler([]config.Setting{
+		{Name: "ACCRUAL_SYSTEM_ADDRESS", Value: "http://accrual:8080", Source: config.SourceFlag},
+		{Name: "JWT_SECRET", Value: "xxxxx", Source: config.SourceEnv},
+		{Name: "LOG_LEVEL", Value: "info", Source: config.SourceDefault},
+	}, zap.NewNop())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/config", nil)
+	w := httptest.NewRecorder()
+
+	handler.Get(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"settings":[
+		{"name":"ACCRUAL_SYSTEM_ADDRESS","value":"http://accrual:8080","source":"flag"},
+		{"name":"JWT_SECRET","value":"xxxxx","source":"env"},
+		{"name":"LOG_LEVEL","value":"info","source":"default"}]}`, w.Body.String())
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// AccrualCorrectionService определяет проверку начисления по обработанному заказу.
+type AccrualCorrectionService interface {
+	RecheckAccrual(ctx context.Context, number string, adminID int64) (*domain.AccrualCorrection, error)
+}
+
+// AccrualCorrectionsHandler обрабатывает запросы корректировки начислений
+type AccrualCorrectionsHandler struct {
+	service AccrualCorrectionService
+	logger  *zap.Logger
+}
+
+// NewAccrualCorrectionsHandler создает новый AccrualCorrectionsHandler
+func NewAccrualCorrectionsHandler(service AccrualCorrectionService, logger *zap.Logger) *AccrualCorrectionsHandler {
+	return &AccrualCorrectionsHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Recheck сверяет начисление по заказу с системой начислений и корректирует его.
+// Если сумма не изменилась, возвращает 204.
+func (h *AccrualCorrectionsHandler) Recheck(w http.ResponseWriter, r *http.Request) {
+	adminID, ok := GetUserID(r.Context())
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+	number := chi.URLParam(r, "number")
+
+	correction, err := h.service.RecheckAccrual(r.Context(), number, adminID)
+	switch {
+	case err == nil:
+	case errors.Is(err, domain.ErrAccrualUnchanged):
+		w.WriteHeader(http.StatusNoContent)
+		return
+	case errors.Is(err, domain.ErrOrderNotFound):
+		writeJSONError(w, r, http.StatusNotFound, "order not found")
+		return
+	case errors.Is(err, domain.ErrOrderNotProcessed):
+		writeJSONError(w, r, http.StatusConflict, "order is not processed")
+		return
+	case errors.Is(err, domain.ErrAccrualCorrectionsDisabled):
+		writeJSONError(w, r, http.StatusConflict, "accrual corrections are disabled")
+		return
+	default:
+		h.logger.Error("failed to recheck accrual", zap.String("order", number), zap.Error(err))
+		writeInternalError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newAccrualCorrectionResponse(correction)); err != nil {
+		h.logger.Error("failed to encode accrual correction response", zap.Error(err))
+	}
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	domainmocks "github.com/avc/loyalty-system-diploma/internal/domain/mocks"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func newAccrualCorrectionsRouter(handler *AccrualCorrectionsHandler) http.Handler {
+	r := chi.NewRouter()
+	r.Post("/api/admin/orders/{number}/accrual/recheck", handler.Recheck)
+	return r
+}
+
+func TestAccrualCorrectionsHandler_Recheck(t *testing.T) {
+	number := "12345678903"
+	correctedAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		setupMock      func(*domainmocks.AccrualCorrectionServiceMock)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "Corrected",
+			setupMock: func(m *domainmocks.AccrualCorrectionServiceMock) {
+				m.EXPECT().RecheckAccrual(mock.Anything, number, int64(1)).Return(&domain.AccrualCorrection{
+					OrderNumber:    number,
+					UserID:         7,
+					PreviousAmount: 100,
+					NewAmount:      70.25,
+					Delta:          -29.75,
+					CorrectedBy:    1,
+					CreatedAt:      correctedAt,
+				}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"order":"12345678903","previous_accrual":100,"accrual":70.25,"adjustment":-29.75,
+				"corrected_at":"2024-03-01T10:00:00Z"}`,
+		},
+		{
+			name: "Unchanged",
+			setupMock: func(m *domainmocks.AccrualCorrectionServiceMock) {
+				m.EXPECT().RecheckAccrual(mock.Anything, number, int64(1)).
+					Return(nil, fmt.Errorf("accrual correction service: %w", domain.ErrAccrualUnchanged)).Once()
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name: "Order not found",
+			setupMock: func(m *domainmocks.AccrualCorrectionServiceMock) {
+				m.EXPECT().RecheckAccrual(mock.Anything, number, int64(1)).Return(nil, domain.ErrOrderNotFound).Once()
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "Order not processed",
+			setupMock: func(m *domainmocks.AccrualCorrectionServiceMock) {
+				m.EXPECT().RecheckAccrual(mock.Anything, number, int64(1)).Return(nil, domain.ErrOrderNotProcessed).Once()
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":"order is not processed"}`,
+		},
+		{
+			name: "Corrections disabled",
+			setupMock: func(m *domainmocks.AccrualCorrectionServiceMock) {
+				m.EXPECT().RecheckAccrual(mock.Anything, number, int64(1)).Return(nil, domain.ErrAccrualCorrectionsDisabled).Once()
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":"accrual corrections are disabled"}`,
+		},
+		{
+			name: "Internal error",
+			setupMock: func(m *domainmocks.AccrualCorrectionServiceMock) {
+				m.EXPECT().RecheckAccrual(mock.Anything, number, int64(1)).Return(nil, errors.New("boom")).Once()
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := domainmocks.NewAccrualCorrectionServiceMock(t)
+			router := newAccrualCorrectionsRouter(NewAccrualCorrectionsHandler(svc, zap.NewNop()))
+			tt.setupMock(svc)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/admin/orders/"+number+"/accrual/recheck", nil)
+			req = req.WithContext(context.WithValue(req.Context(), UserIDKey, int64(1)))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestAccrualCorrectionsHandler_Recheck_Unauthorized(t *testing.T) {
+	router := newAccrualCorrectionsRouter(NewAccrualCorrectionsHandler(domainmocks.NewAccrualCorrectionServiceMock(t), zap.NewNop()))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/admin/orders/12345678903/accrual/recheck", nil))
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
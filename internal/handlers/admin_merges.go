@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"go.uber.org/zap"
+)
+
+// UserMergeService определяет объединение учетных записей-дубликатов.
+type UserMergeService interface {
+	MergeUsers(ctx context.Context, sourceLogin, targetLogin string, adminID int64, dryRun bool) (*domain.UserMerge, error)
+}
+
+// UserMergesHandler обрабатывает запросы объединения учетных записей
+type UserMergesHandler struct {
+	service UserMergeService
+	logger  *zap.Logger
+}
+
+// NewUserMergesHandler создает новый UserMergesHandler
+func NewUserMergesHandler(service UserMergeService, logger *zap.Logger) *UserMergesHandler {
+	return &UserMergesHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// userMergeRequest - дубликат, основная учетная запись и режим предпросмотра
+type userMergeRequest struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+	DryRun bool   `json:"dry_run"`
+}
+
+// Merge объединяет учетную запись source с target или, при dry_run, показывает,
+// что будет перенесено
+func (h *UserMergesHandler) Merge(w http.ResponseWriter, r *http.Request) {
+	adminID, ok := GetUserID(r.Context())
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	var req userMergeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	merge, err := h.service.MergeUsers(r.Context(), req.Source, req.Target, adminID, req.DryRun)
+	switch {
+	case err == nil:
+	case errors.Is(err, domain.ErrInvalidInput):
+		writeJSONError(w, r, http.StatusBadRequest, "source and target must be different logins")
+		return
+	case errors.Is(err, domain.ErrUserNotFound):
+		writeJSONError(w, r, http.StatusNotFound, "user not found")
+		return
+	case errors.Is(err, domain.ErrUserMerged):
+		writeJSONError(w, r, http.StatusConflict, "user has already been merged")
+		return
+	default:
+		h.logger.Error("failed to merge users", zap.String("source", req.Source), zap.String("target", req.Target), zap.Error(err))
+		writeInternalError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newUserMergeResponse(merge)); err != nil {
+		h.logger.Error("failed to encode user merge response", zap.Error(err))
+	}
+}
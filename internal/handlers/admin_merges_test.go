@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	domainmocks "github.com/avc/loyalty-system-diploma/internal/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestUserMergesHandler_Merge(t *testing.T) {
+	mergedAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(*domainmocks.UserMergeServiceMock)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "Merged",
+			body: `{"source":"alice2","target":"alice"}`,
+			setupMock: func(m *domainmocks.UserMergeServiceMock) {
+				m.EXPECT().MergeUsers(mock.Anything, "alice2", "alice", int64(1), false).Return(&domain.UserMerge{
+					SourceUserID: 8,
+					SourceLogin:  "alice2",
+					TargetUserID: 3,
+					TargetLogin:  "alice",
+					Orders:       2,
+					Transactions: 3,
+					Balance:      250.5,
+					MergedBy:     1,
+					CreatedAt:    mergedAt,
+				}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"source":"alice2","target":"alice","orders":2,"transactions":3,"balance":250.5,
+				"dry_run":false,"merged_at":"2024-03-01T10:00:00Z"}`,
+		},
+		{
+			name: "Dry run",
+			body: `{"source":"alice2","target":"alice","dry_run":true}`,
+			setupMock: func(m *domainmocks.UserMergeServiceMock) {
+				m.EXPECT().MergeUsers(mock.Anything, "alice2", "alice", int64(1), true).Return(&domain.UserMerge{
+					SourceLogin:  "alice2",
+					TargetLogin:  "alice",
+					Orders:       2,
+					Transactions: 3,
+					Balance:      250.5,
+					DryRun:       true,
+				}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"source":"alice2","target":"alice","orders":2,"transactions":3,"balance":250.5,"dry_run":true}`,
+		},
+		{
+			name:           "Invalid body",
+			body:           `[`,
+			setupMock:      func(m *domainmocks.UserMergeServiceMock) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid request body"}`,
+		},
+		{
+			name: "Same login",
+			body: `{"source":"alice","target":"alice"}`,
+			setupMock: func(m *domainmocks.UserMergeServiceMock) {
+				m.EXPECT().MergeUsers(mock.Anything, "alice", "alice", int64(1), false).
+					Return(nil, fmt.Errorf("user admin service: %w", domain.ErrInvalidInput)).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "User not found",
+			body: `{"source":"alice2","target":"alice"}`,
+			setupMock: func(m *domainmocks.UserMergeServiceMock) {
+				m.EXPECT().MergeUsers(mock.Anything, "alice2", "alice", int64(1), false).Return(nil, domain.ErrUserNotFound).Once()
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "Already merged",
+			body: `{"source":"alice2","target":"alice"}`,
+			setupMock: func(m *domainmocks.UserMergeServiceMock) {
+				m.EXPECT().MergeUsers(mock.Anything, "alice2", "alice", int64(1), false).Return(nil, domain.ErrUserMerged).Once()
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":"user has already been merged"}`,
+		},
+		{
+			name: "Internal error",
+			body: `{"source":"alice2","target":"alice"}`,
+			setupMock: func(m *domainmocks.UserMergeServiceMock) {
+				m.EXPECT().MergeUsers(mock.Anything, "alice2", "alice", int64(1), false).Return(nil, errors.New("boom")).Once()
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := domainmocks.NewUserMergeServiceMock(t)
+			handler := NewUserMergesHandler(svc, zap.NewNop())
+			tt.setupMock(svc)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/admin/users/merge", strings.NewReader(tt.body))
+			req = req.WithContext(context.WithValue(req.Context(), UserIDKey, int64(1)))
+			w := httptest.NewRecorder()
+			handler.Merge(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestUserMergesHandler_Merge_Unauthorized(t *testing.T) {
+	handler := NewUserMergesHandler(domainmocks.NewUserMergeServiceMock(t), zap.NewNop())
+
+	w := httptest.NewRecorder()
+	handler.Merge(w, httptest.NewRequest(http.MethodPost, "/api/admin/users/merge", strings.NewReader(`{}`)))
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
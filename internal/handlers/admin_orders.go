@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"go.uber.org/zap"
+)
+
+// OrderSearchService определяет поиск заказов всех пользователей.
+type OrderSearchService interface {
+	SearchOrders(ctx context.Context, filter domain.OrderSearchFilter) (*domain.OrderSearchResult, error)
+}
+
+// AdminOrdersHandler обрабатывает административные запросы к заказам
+type AdminOrdersHandler struct {
+	service OrderSearchService
+	logger  *zap.Logger
+}
+
+// NewAdminOrdersHandler создает новый AdminOrdersHandler
+func NewAdminOrdersHandler(service OrderSearchService, logger *zap.Logger) *AdminOrdersHandler {
+	return &AdminOrdersHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Search ищет заказы по началу номера, логину владельца, статусу и метаданным
+func (h *AdminOrdersHandler) Search(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	limit, ok := intQueryParam(query, "limit")
+	if !ok {
+		writeJSONError(w, r, http.StatusBadRequest, "limit must be a non-negative integer")
+		return
+	}
+	offset, ok := intQueryParam(query, "offset")
+	if !ok {
+		writeJSONError(w, r, http.StatusBadRequest, "offset must be a non-negative integer")
+		return
+	}
+
+	filter := domain.OrderSearchFilter{
+		NumberPrefix: query.Get("number_prefix"),
+		Login:        query.Get("login"),
+		Status:       domain.OrderStatus(query.Get("status")),
+		Metadata: domain.OrderMetadata{
+			Channel:   query.Get("channel"),
+			StoreID:   query.Get("store_id"),
+			ReceiptID: query.Get("receipt_id"),
+		},
+		Limit:  limit,
+		Offset: offset,
+	}
+
+	result, err := h.service.SearchOrders(r.Context(), filter)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidInput) {
+			writeJSONError(w, r, http.StatusBadRequest, "invalid search filter")
+			return
+		}
+		h.logger.Error("failed to search orders", zap.Error(err))
+		writeInternalError(w, err)
+		return
+	}
+
+	response := newOrderSearchResponse(result, offset)
+	w.Header().Add("Vary", "Accept")
+	if prefersEnvelope(r.Header.Get("Accept")) {
+		writeEnvelope(w, r, response.Orders, offset, response.HasMore, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error("failed to encode order search response", zap.Error(err))
+	}
+}
+
+// intQueryParam читает неотрицательное целое из query параметра, 0 - если параметр не задан
+func intQueryParam(query url.Values, name string) (int, bool) {
+	value := query.Get(name)
+	if value == "" {
+		return 0, true
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	return n, true
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	domainmocks "github.com/avc/loyalty-system-diploma/internal/domain/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestAdminOrdersHandler_Search(t *testing.T) {
+	publicID := uuid.MustParse("01890a5d-ac96-774b-bcce-b302099a8057")
+	uploadedAt := time.Date(2020, 12, 10, 15, 15, 45, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		query          string
+		setupMock      func(*domainmocks.OrderSearchServiceMock)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:  "Success",
+			query: "?number_prefix=9278&login=alice&status=PROCESSED&store_id=42&limit=1&offset=2",
+			setupMock: func(m *domainmocks.OrderSearchServiceMock) {
+				filter := domain.OrderSearchFilter{
+					NumberPrefix: "9278",
+					Login:        "alice",
+					Status:       domain.OrderStatusProcessed,
+					Metadata:     domain.OrderMetadata{StoreID: "42"},
+					Limit:        1,
+					Offset:       2,
+				}
+				m.EXPECT().SearchOrders(mock.Anything, filter).Return(&domain.OrderSearchResult{
+					Orders: []*domain.AdminOrder{{
+						Order: domain.Order{PublicID: publicID, Number: "9278923470", Status: domain.OrderStatusProcessed, UploadedAt: uploadedAt},
+						Login: "alice",
+					}},
+					HasMore: true,
+				}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"orders":[{"id":"01890a5d-ac96-774b-bcce-b302099a8057","number":"9278923470","status":"PROCESSED",
+				"uploaded_at":"2020-12-10T15:15:45Z","login":"alice"}],"offset":2,"has_more":true}`,
+		},
+		{
+			name:  "Nothing found",
+			query: "",
+			setupMock: func(m *domainmocks.OrderSearchServiceMock) {
+				m.EXPECT().SearchOrders(mock.Anything, domain.OrderSearchFilter{}).Return(&domain.OrderSearchResult{}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"orders":[],"offset":0,"has_more":false}`,
+		},
+		{
+			name:           "Invalid limit",
+			query:          "?limit=ten",
+			setupMock:      func(m *domainmocks.OrderSearchServiceMock) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Negative offset",
+			query:          "?offset=-1",
+			setupMock:      func(m *domainmocks.OrderSearchServiceMock) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "Invalid filter",
+			query: "?status=DONE",
+			setupMock: func(m *domainmocks.OrderSearchServiceMock) {
+				m.EXPECT().SearchOrders(mock.Anything, mock.Anything).
+					Return(nil, fmt.Errorf("order service: %w", domain.ErrInvalidInput)).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "Internal error",
+			query: "?login=alice",
+			setupMock: func(m *domainmocks.OrderSearchServiceMock) {
+				m.EXPECT().SearchOrders(mock.Anything, mock.Anything).Return(nil, errors.New("boom")).Once()
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := domainmocks.NewOrderSearchServiceMock(t)
+			handler := NewAdminOrdersHandler(svc, zap.NewNop())
+			tt.setupMock(svc)
+
+			w := httptest.NewRecorder()
+			handler.Search(w, httptest.NewRequest(http.MethodGet, "/api/admin/orders"+tt.query, nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
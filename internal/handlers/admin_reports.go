@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"go.uber.org/zap"
+)
+
+// AccrualReportService определяет сверку начислений заказов с журналом транзакций.
+type AccrualReportService interface {
+	AccrualMismatches(ctx context.Context, limit, offset int) (*domain.AccrualMismatchReport, error)
+}
+
+// LedgerIntegrityService определяет проверку цепочки хешей журнала транзакций.
+type LedgerIntegrityService interface {
+	VerifyLedger(ctx context.Context) (*domain.LedgerVerification, error)
+}
+
+// DuplicateSubmissionReportService определяет отчет о повторных загрузках заказов.
+type DuplicateSubmissionReportService interface {
+	DuplicateSubmissions(ctx context.Context, days, limit, offset int) (*domain.DuplicateSubmissionReport, error)
+}
+
+// ReportsHandler обрабатывает запросы административных отчетов
+type ReportsHandler struct {
+	accruals   AccrualReportService
+	ledger     LedgerIntegrityService
+	duplicates DuplicateSubmissionReportService
+	logger     *zap.Logger
+}
+
+// NewReportsHandler создает новый ReportsHandler
+func NewReportsHandler(accruals AccrualReportService, ledger LedgerIntegrityService, duplicates DuplicateSubmissionReportService, logger *zap.Logger) *ReportsHandler {
+	return &ReportsHandler{
+		accruals:   accruals,
+		ledger:     ledger,
+		duplicates: duplicates,
+		logger:     logger,
+	}
+}
+
+// AccrualMismatches возвращает заказы, начисление которых расходится с журналом транзакций
+func (h *ReportsHandler) AccrualMismatches(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	limit, ok := intQueryParam(query, "limit")
+	if !ok {
+		writeJSONError(w, r, http.StatusBadRequest, "limit must be a non-negative integer")
+		return
+	}
+	offset, ok := intQueryParam(query, "offset")
+	if !ok {
+		writeJSONError(w, r, http.StatusBadRequest, "offset must be a non-negative integer")
+		return
+	}
+
+	report, err := h.accruals.AccrualMismatches(r.Context(), limit, offset)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidInput) {
+			writeJSONError(w, r, http.StatusBadRequest, "invalid report parameters")
+			return
+		}
+		h.logger.Error("failed to build accrual mismatch report", zap.Error(err))
+		writeInternalError(w, err)
+		return
+	}
+
+	response := newAccrualMismatchesResponse(report, offset)
+	w.Header().Add("Vary", "Accept")
+	if prefersEnvelope(r.Header.Get("Accept")) {
+		writeEnvelope(w, r, response.Mismatches, offset, response.HasMore, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error("failed to encode accrual mismatch report", zap.Error(err))
+	}
+}
+
+// LedgerIntegrity проверяет цепочку хешей журнала транзакций.
+// Нарушенная цепочка - результат проверки, а не ошибка, поэтому ответ 200 с intact=false.
+func (h *ReportsHandler) LedgerIntegrity(w http.ResponseWriter, r *http.Request) {
+	result, err := h.ledger.VerifyLedger(r.Context())
+	if err != nil {
+		h.logger.Error("failed to verify ledger chain", zap.Error(err))
+		writeInternalError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newLedgerIntegrityResponse(result)); err != nil {
+		h.logger.Error("failed to encode ledger integrity report", zap.Error(err))
+	}
+}
+
+// DuplicateSubmissions возвращает отчет о повторных загрузках заказов, уже загруженных
+// тем же пользователем, за последние days суток (по умолчанию 7)
+func (h *ReportsHandler) DuplicateSubmissions(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	days, ok := intQueryParam(query, "days")
+	if !ok {
+		writeJSONError(w, r, http.StatusBadRequest, "days must be a non-negative integer")
+		return
+	}
+	limit, ok := intQueryParam(query, "limit")
+	if !ok {
+		writeJSONError(w, r, http.StatusBadRequest, "limit must be a non-negative integer")
+		return
+	}
+	offset, ok := intQueryParam(query, "offset")
+	if !ok {
+		writeJSONError(w, r, http.StatusBadRequest, "offset must be a non-negative integer")
+		return
+	}
+
+	report, err := h.duplicates.DuplicateSubmissions(r.Context(), days, limit, offset)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidInput) {
+			writeJSONError(w, r, http.StatusBadRequest, fmt.Sprintf("days must be at most %d", domain.MaxDuplicateReportDays))
+			return
+		}
+		h.logger.Error("failed to build duplicate submission report", zap.Error(err))
+		writeInternalError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newDuplicateSubmissionsResponse(report, offset)); err != nil {
+		h.logger.Error("failed to encode duplicate submission report", zap.Error(err))
+	}
+}
@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	domainmocks "github.com/avc/loyalty-system-diploma/internal/domain/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestReportsHandler_AccrualMismatches(t *testing.T) {
+	orderAccrual, ledgerAccrual := 150.0, 100.0
+
+	tests := []struct {
+		name           string
+		query          string
+		setupMock      func(*domainmocks.AccrualReportServiceMock)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:  "Success",
+			query: "?limit=2&offset=4",
+			setupMock: func(m *domainmocks.AccrualReportServiceMock) {
+				m.EXPECT().AccrualMismatches(mock.Anything, 2, 4).Return(&domain.AccrualMismatchReport{
+					Mismatches: []*domain.AccrualMismatch{
+						{OrderNumber: "12345678903", Login: "alice", Status: domain.OrderStatusProcessed, OrderAccrual: &orderAccrual, LedgerAccrual: &ledgerAccrual},
+						{OrderNumber: "9278923470", Login: "bob", Status: domain.OrderStatusProcessed, OrderAccrual: &orderAccrual},
+					},
+					HasMore: true,
+				}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"mismatches":[
+				{"order":"12345678903","login":"alice","status":"PROCESSED","order_accrual":150,"ledger_accrual":100},
+				{"order":"9278923470","login":"bob","status":"PROCESSED","order_accrual":150,"ledger_accrual":null}
+			],"offset":4,"has_more":true}`,
+		},
+		{
+			name:  "No mismatches",
+			query: "",
+			setupMock: func(m *domainmocks.AccrualReportServiceMock) {
+				m.EXPECT().AccrualMismatches(mock.Anything, 0, 0).Return(&domain.AccrualMismatchReport{}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"mismatches":[],"offset":0,"has_more":false}`,
+		},
+		{
+			name:           "Invalid limit",
+			query:          "?limit=ten",
+			setupMock:      func(m *domainmocks.AccrualReportServiceMock) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "Invalid input",
+			query: "?limit=1",
+			setupMock: func(m *domainmocks.AccrualReportServiceMock) {
+				m.EXPECT().AccrualMismatches(mock.Anything, 1, 0).
+					Return(nil, fmt.Errorf("order service: %w", domain.ErrInvalidInput)).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "Internal error",
+			query: "",
+			setupMock: func(m *domainmocks.AccrualReportServiceMock) {
+				m.EXPECT().AccrualMismatches(mock.Anything, 0, 0).Return(nil, errors.New("boom")).Once()
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := domainmocks.NewAccrualReportServiceMock(t)
+			handler := NewReportsHandler(svc, nil, nil, zap.NewNop())
+			tt.setupMock(svc)
+
+			w := httptest.NewRecorder()
+			handler.AccrualMismatches(w, httptest.NewRequest(http.MethodGet, "/api/admin/reports/accrual-mismatches"+tt.query, nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestReportsHandler_LedgerIntegrity(t *testing.T) {
+	publicID := uuid.MustParse("01890a5d-ac96-774b-bcce-b302099a8057")
+
+	tests := []struct {
+		name           string
+		setupMock      func(*domainmocks.LedgerIntegrityServiceMock)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "Intact",
+			setupMock: func(m *domainmocks.LedgerIntegrityServiceMock) {
+				m.EXPECT().VerifyLedger(mock.Anything).Return(&domain.LedgerVerification{
+					Checked:  3,
+					Chains:   2,
+					HeadHash: []byte{0xab, 0x01},
+				}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"intact":true,"checked":3,"unchained":0,"chains":2,"head_hash":"ab01","break":null}`,
+		},
+		{
+			name: "Broken",
+			setupMock: func(m *domainmocks.LedgerIntegrityServiceMock) {
+				m.EXPECT().VerifyLedger(mock.Anything).Return(&domain.LedgerVerification{
+					Checked:  3,
+					Chains:   2,
+					HeadHash: []byte{0xff},
+					Break:    &domain.LedgerBreak{UserID: 7, Login: "alice", Seq: 3, PublicID: publicID, Reason: domain.LedgerBreakGap},
+				}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"intact":false,"checked":3,"unchained":0,"chains":2,"head_hash":"ff",
+				"break":{"seq":3,"login":"alice","id":"01890a5d-ac96-774b-bcce-b302099a8057","reason":"gap"}}`,
+		},
+		{
+			name: "Internal error",
+			setupMock: func(m *domainmocks.LedgerIntegrityServiceMock) {
+				m.EXPECT().VerifyLedger(mock.Anything).Return(nil, errors.New("boom")).Once()
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := domainmocks.NewLedgerIntegrityServiceMock(t)
+			handler := NewReportsHandler(nil, svc, nil, zap.NewNop())
+			tt.setupMock(svc)
+
+			w := httptest.NewRecorder()
+			handler.LedgerIntegrity(w, httptest.NewRequest(http.MethodGet, "/api/admin/reports/ledger-integrity", nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestReportsHandler_DuplicateSubmissions(t *testing.T) {
+	since := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	lastAt := time.Date(2024, 3, 7, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		query          string
+		setupMock      func(*domainmocks.DuplicateSubmissionReportServiceMock)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:  "Success",
+			query: "?days=7&limit=1&offset=2",
+			setupMock: func(m *domainmocks.DuplicateSubmissionReportServiceMock) {
+				m.EXPECT().DuplicateSubmissions(mock.Anything, 7, 1, 2).Return(&domain.DuplicateSubmissionReport{
+					Since:       since,
+					Submissions: 12,
+					Users:       2,
+					Orders:      40,
+					Channels: []*domain.DuplicateSubmissionChannel{
+						{Channel: "mobile", Submissions: 10, Users: 1},
+						{Channel: "", Submissions: 2, Users: 1},
+					},
+					Submitters: []*domain.DuplicateSubmitter{{UserID: 1, Login: "alice", Submissions: 10, LastAt: lastAt}},
+					HasMore:    true,
+				}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"since":"2024-03-01T00:00:00Z","submissions":12,"users":2,"orders":40,
+				"channels":[{"channel":"mobile","submissions":10,"users":1},{"channel":"","submissions":2,"users":1}],
+				"submitters":[{"login":"alice","submissions":10,"last_at":"2024-03-07T12:00:00Z"}],
+				"offset":2,"has_more":true}`,
+		},
+		{
+			name:  "No duplicates",
+			query: "",
+			setupMock: func(m *domainmocks.DuplicateSubmissionReportServiceMock) {
+				m.EXPECT().DuplicateSubmissions(mock.Anything, 0, 0, 0).
+					Return(&domain.DuplicateSubmissionReport{Since: since, Orders: 3}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"since":"2024-03-01T00:00:00Z","submissions":0,"users":0,"orders":3,
+				"channels":[],"submitters":[],"offset":0,"has_more":false}`,
+		},
+		{
+			name:           "Invalid days",
+			query:          "?days=week",
+			setupMock:      func(m *domainmocks.DuplicateSubmissionReportServiceMock) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"days must be a non-negative integer"}`,
+		},
+		{
+			name:  "Period too long",
+			query: "?days=365",
+			setupMock: func(m *domainmocks.DuplicateSubmissionReportServiceMock) {
+				m.EXPECT().DuplicateSubmissions(mock.Anything, 365, 0, 0).
+					Return(nil, fmt.Errorf("order service: %w", domain.ErrInvalidInput)).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"days must be at most 90"}`,
+		},
+		{
+			name:  "Internal error",
+			query: "",
+			setupMock: func(m *domainmocks.DuplicateSubmissionReportServiceMock) {
+				m.EXPECT().DuplicateSubmissions(mock.Anything, 0, 0, 0).Return(nil, errors.New("boom")).Once()
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := domainmocks.NewDuplicateSubmissionReportServiceMock(t)
+			handler := NewReportsHandler(nil, nil, svc, zap.NewNop())
+			tt.setupMock(svc)
+
+			w := httptest.NewRecorder()
+			handler.DuplicateSubmissions(w, httptest.NewRequest(http.MethodGet, "/api/admin/reports/duplicate-submissions"+tt.query, nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
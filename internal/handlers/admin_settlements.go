@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// SettlementsService определяет выгрузки списаний для взаиморасчетов с партнерами.
+type SettlementsService interface {
+	CreateSettlement(ctx context.Context, createdBy *int64) (*domain.Settlement, error)
+	ListSettlements(ctx context.Context, limit, offset int) (*domain.SettlementPage, error)
+	GetSettlementFile(ctx context.Context, id uuid.UUID) (*domain.Settlement, []*domain.SettlementLine, error)
+}
+
+// SettlementsHandler обрабатывает запросы выгрузок для финансового отдела
+type SettlementsHandler struct {
+	service SettlementsService
+	logger  *zap.Logger
+}
+
+// NewSettlementsHandler создает новый SettlementsHandler
+func NewSettlementsHandler(service SettlementsService, logger *zap.Logger) *SettlementsHandler {
+	return &SettlementsHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Create выгружает списания, не вошедшие в прежние выгрузки, до начала текущих суток
+func (h *SettlementsHandler) Create(w http.ResponseWriter, r *http.Request) {
+	adminID, ok := GetUserID(r.Context())
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	settlement, err := h.service.CreateSettlement(r.Context(), &adminID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNothingToSettle) {
+			writeJSONError(w, r, http.StatusConflict, "no unsettled withdrawals")
+			return
+		}
+		h.logger.Error("failed to create settlement", zap.Error(err))
+		writeInternalError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", settlementFilePath(settlement.ID))
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(newSettlementResponse(settlement)); err != nil {
+		h.logger.Error("failed to encode settlement response", zap.Error(err))
+	}
+}
+
+// List возвращает страницу выгрузок, новые первыми
+func (h *SettlementsHandler) List(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	limit, ok := intQueryParam(query, "limit")
+	if !ok {
+		writeJSONError(w, r, http.StatusBadRequest, "limit must be a non-negative integer")
+		return
+	}
+	offset, ok := intQueryParam(query, "offset")
+	if !ok {
+		writeJSONError(w, r, http.StatusBadRequest, "offset must be a non-negative integer")
+		return
+	}
+
+	page, err := h.service.ListSettlements(r.Context(), limit, offset)
+	if err != nil {
+		h.logger.Error("failed to list settlements", zap.Error(err))
+		writeInternalError(w, err)
+		return
+	}
+
+	response := newSettlementsResponse(page, offset)
+	w.Header().Add("Vary", "Accept")
+	if prefersEnvelope(r.Header.Get("Accept")) {
+		writeEnvelope(w, r, response.Settlements, offset, response.HasMore, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error("failed to encode settlements response", zap.Error(err))
+	}
+}
+
+// File отдает файл выгрузки: CSV с итогами списаний по суткам и партнерам
+func (h *SettlementsHandler) File(w http.ResponseWriter, r *http.Request) {
+	id, ok := publicIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	settlement, lines, err := h.service.GetSettlementFile(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, domain.ErrSettlementNotFound) {
+			writeJSONError(w, r, http.StatusNotFound, "settlement not found")
+			return
+		}
+		h.logger.Error("failed to get settlement file", zap.Error(err))
+		writeInternalError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", mediaTypeCSV)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="settlement-%s.csv"`, settlement.ID))
+	w.WriteHeader(http.StatusOK)
+	if err := csv.NewWriter(w).WriteAll(settlementRows(lines)); err != nil {
+		h.logger.Error("failed to write settlement file", zap.Error(err), zap.String("settlement_id", settlement.ID.String()))
+	}
+}
+
+// settlementRows формирует строки CSV выгрузки. Сутки - по часам БД, как время списаний
+func settlementRows(lines []*domain.SettlementLine) [][]string {
+	rows := make([][]string, 0, len(lines)+1)
+	rows = append(rows, []string{"day", "partner", "withdrawals", "total"})
+	for _, line := range lines {
+		rows = append(rows, []string{
+			line.Day.Format(time.DateOnly),
+			line.Partner,
+			strconv.Itoa(line.Withdrawals),
+			strconv.FormatFloat(line.Total, 'f', 2, 64),
+		})
+	}
+	return rows
+}
+
+// settlementFilePath возвращает путь файла выгрузки
+func settlementFilePath(id uuid.UUID) string {
+	return "/api/admin/settlements/" + id.String() + "/file"
+}
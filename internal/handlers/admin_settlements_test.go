@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	domainmocks "github.com/avc/loyalty-system-diploma/internal/domain/mocks"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func newSettlementsRouter(handler *SettlementsHandler) http.Handler {
+	r := chi.NewRouter()
+	r.Post("/api/admin/settlements", handler.Create)
+	r.Get("/api/admin/settlements", handler.List)
+	r.Get("/api/admin/settlements/{id}/file", handler.File)
+	return r
+}
+
+func TestSettlementsHandler_Create(t *testing.T) {
+	id := uuid.MustParse("0b8e2f6a-3c4d-4e5f-8a9b-1c2d3e4f5a6b")
+	createdBy := int64(1)
+	settlement := &domain.Settlement{
+		ID:          id,
+		Cutoff:      time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC),
+		Withdrawals: 3,
+		Total:       1250.5,
+		CreatedBy:   &createdBy,
+		CreatedAt:   time.Date(2024, 3, 2, 9, 30, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name           string
+		setupMock      func(*domainmocks.SettlementsServiceMock)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "Created",
+			setupMock: func(m *domainmocks.SettlementsServiceMock) {
+				m.EXPECT().CreateSettlement(mock.Anything, &createdBy).Return(settlement, nil).Once()
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody: `{"id":"0b8e2f6a-3c4d-4e5f-8a9b-1c2d3e4f5a6b","cutoff":"2024-03-02T00:00:00Z","withdrawals":3,
+				"total":1250.5,"scheduled":false,"created_at":"2024-03-02T09:30:00Z",
+				"file_url":"/api/admin/settlements/0b8e2f6a-3c4d-4e5f-8a9b-1c2d3e4f5a6b/file"}`,
+		},
+		{
+			name: "Nothing to settle",
+			setupMock: func(m *domainmocks.SettlementsServiceMock) {
+				m.EXPECT().CreateSettlement(mock.Anything, &createdBy).Return(nil, domain.ErrNothingToSettle).Once()
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":"no unsettled withdrawals"}`,
+		},
+		{
+			name: "Internal error",
+			setupMock: func(m *domainmocks.SettlementsServiceMock) {
+				m.EXPECT().CreateSettlement(mock.Anything, &createdBy).Return(nil, errors.New("boom")).Once()
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := domainmocks.NewSettlementsServiceMock(t)
+			router := newSettlementsRouter(NewSettlementsHandler(svc, zap.NewNop()))
+			tt.setupMock(svc)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/admin/settlements", nil)
+			req = req.WithContext(context.WithValue(req.Context(), UserIDKey, int64(1)))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestSettlementsHandler_Create_Unauthorized(t *testing.T) {
+	router := newSettlementsRouter(NewSettlementsHandler(domainmocks.NewSettlementsServiceMock(t), zap.NewNop()))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/admin/settlements", nil))
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestSettlementsHandler_List(t *testing.T) {
+	page := &domain.SettlementPage{
+		Settlements: []*domain.Settlement{{
+			ID:          uuid.MustParse("0b8e2f6a-3c4d-4e5f-8a9b-1c2d3e4f5a6b"),
+			Cutoff:      time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC),
+			Withdrawals: 1,
+			Total:       100,
+			CreatedAt:   time.Date(2024, 3, 2, 0, 5, 0, 0, time.UTC),
+		}},
+		HasMore: true,
+	}
+
+	tests := []struct {
+		name           string
+		query          string
+		setupMock      func(*domainmocks.SettlementsServiceMock)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:  "Page",
+			query: "?limit=1&offset=2",
+			setupMock: func(m *domainmocks.SettlementsServiceMock) {
+				m.EXPECT().ListSettlements(mock.Anything, 1, 2).Return(page, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"settlements":[{"id":"0b8e2f6a-3c4d-4e5f-8a9b-1c2d3e4f5a6b","cutoff":"2024-03-02T00:00:00Z",
+				"withdrawals":1,"total":100,"scheduled":true,"created_at":"2024-03-02T00:05:00Z",
+				"file_url":"/api/admin/settlements/0b8e2f6a-3c4d-4e5f-8a9b-1c2d3e4f5a6b/file"}],"offset":2,"has_more":true}`,
+		},
+		{
+			name:  "Empty",
+			query: "",
+			setupMock: func(m *domainmocks.SettlementsServiceMock) {
+				m.EXPECT().ListSettlements(mock.Anything, 0, 0).Return(&domain.SettlementPage{}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"settlements":[],"offset":0,"has_more":false}`,
+		},
+		{
+			name:           "Invalid limit",
+			query:          "?limit=abc",
+			setupMock:      func(m *domainmocks.SettlementsServiceMock) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"limit must be a non-negative integer"}`,
+		},
+		{
+			name:  "Internal error",
+			query: "",
+			setupMock: func(m *domainmocks.SettlementsServiceMock) {
+				m.EXPECT().ListSettlements(mock.Anything, 0, 0).Return(nil, errors.New("boom")).Once()
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := domainmocks.NewSettlementsServiceMock(t)
+			router := newSettlementsRouter(NewSettlementsHandler(svc, zap.NewNop()))
+			tt.setupMock(svc)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/admin/settlements"+tt.query, nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestSettlementsHandler_File(t *testing.T) {
+	id := uuid.MustParse("0b8e2f6a-3c4d-4e5f-8a9b-1c2d3e4f5a6b")
+	path := "/api/admin/settlements/" + id.String() + "/file"
+
+	t.Run("CSV", func(t *testing.T) {
+		svc := domainmocks.NewSettlementsServiceMock(t)
+		router := newSettlementsRouter(NewSettlementsHandler(svc, zap.NewNop()))
+		svc.EXPECT().GetSettlementFile(mock.Anything, id).Return(&domain.Settlement{ID: id}, []*domain.SettlementLine{
+			{Day: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), Partner: "", Withdrawals: 1, Total: 50},
+			{Day: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), Partner: "cinema", Withdrawals: 2, Total: 300.25},
+		}, nil).Once()
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, mediaTypeCSV, w.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename="settlement-`+id.String()+`.csv"`, w.Header().Get("Content-Disposition"))
+		assert.Equal(t, "day,partner,withdrawals,total\n2024-03-01,,1,50.00\n2024-03-01,cinema,2,300.25\n", w.Body.String())
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		svc := domainmocks.NewSettlementsServiceMock(t)
+		router := newSettlementsRouter(NewSettlementsHandler(svc, zap.NewNop()))
+		svc.EXPECT().GetSettlementFile(mock.Anything, id).Return(nil, nil, domain.ErrSettlementNotFound).Once()
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.JSONEq(t, `{"error":"settlement not found"}`, w.Body.String())
+	})
+
+	t.Run("Invalid id", func(t *testing.T) {
+		router := newSettlementsRouter(NewSettlementsHandler(domainmocks.NewSettlementsServiceMock(t), zap.NewNop()))
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/admin/settlements/abc/file", nil))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
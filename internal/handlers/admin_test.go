@@ -0,0 +1,289 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	domainmocks "github.com/avc/loyalty-system-diploma/internal/domain/mocks"
+	"github.com/avc/loyalty-system-diploma/internal/jobs"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// newAdminRouter собирает маршруты администратора так же, как приложение
+func newAdminRouter(handler *AdminHandler) http.Handler {
+	r := chi.NewRouter()
+	r.Post("/api/admin/users/import", handler.ImportUsers)
+	r.Post("/api/admin/users/export", handler.ExportUsers)
+	r.Get("/api/admin/jobs/{id}", handler.GetJob)
+	r.Get("/api/admin/jobs/{id}/result", handler.GetJobResult)
+	return r
+}
+
+// waitJob опрашивает состояние задачи, пока она не завершится
+func waitJob(t *testing.T, router http.Handler, id string) JobResponse {
+	t.Helper()
+
+	var job JobResponse
+	require.Eventually(t, func() bool {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, jobPath(id), nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &job))
+		return job.Status != string(jobs.StatusRunning)
+	}, time.Second, 5*time.Millisecond)
+	return job
+}
+
+func TestAdminMiddleware(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         *int64
+		setupMock      func(*domainmocks.AdminCheckerMock)
+		expectedStatus int
+	}{
+		{
+			name:   "admin",
+			userID: ptrInt64(1),
+			setupMock: func(m *domainmocks.AdminCheckerMock) {
+				m.EXPECT().IsAdmin(mock.Anything, int64(1)).Return(true, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "not admin",
+			userID: ptrInt64(2),
+			setupMock: func(m *domainmocks.AdminCheckerMock) {
+				m.EXPECT().IsAdmin(mock.Anything, int64(2)).Return(false, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "storage unavailable",
+			userID: ptrInt64(1),
+			setupMock: func(m *domainmocks.AdminCheckerMock) {
+				m.EXPECT().IsAdmin(mock.Anything, int64(1)).Return(false, domain.ErrStorageUnavailable)
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:           "unauthorized",
+			setupMock:      func(m *domainmocks.AdminCheckerMock) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := domainmocks.NewAdminCheckerMock(t)
+			tt.setupMock(checker)
+
+			handler := AdminMiddleware(checker, zap.NewNop())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/admin/jobs/1", nil)
+			if tt.userID != nil {
+				req = req.WithContext(context.WithValue(req.Context(), UserIDKey, *tt.userID))
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestAdminMiddleware_Impersonation(t *testing.T) {
+	handler := AdminMiddleware(domainmocks.NewAdminCheckerMock(t), zap.NewNop())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/jobs/1", nil)
+	ctx := context.WithValue(req.Context(), UserIDKey, int64(7))
+	ctx = context.WithValue(ctx, ImpersonatorIDKey, int64(1))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req.WithContext(ctx))
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestAdminHandler_ImportUsers(t *testing.T) {
+	service := domainmocks.NewUserAdminServiceMock(t)
+	service.EXPECT().ImportUsers(mock.Anything, []string{"alice", "bob", ""}, mock.Anything).
+		RunAndReturn(func(_ context.Context, logins []string, progress jobs.Progress) ([]domain.ImportedUser, error) {
+			progress.SetTotal(len(logins))
+			progress.Advance(true)
+			progress.Advance(true)
+			progress.Advance(false)
+			return []domain.ImportedUser{
+				{Login: "alice", TemporaryPassword: "secret", Status: domain.ImportStatusCreated},
+				{Login: "bob", Status: domain.ImportStatusExists},
+				{Login: "", Status: domain.ImportStatusInvalid},
+			}, nil
+		})
+
+	manager := jobs.NewManager(time.Hour, zap.NewNop())
+	defer manager.Shutdown()
+	router := newAdminRouter(NewAdminHandler(service, manager, zap.NewNop()))
+
+	body := "email,Login\nalice@example.com,alice\nbob@example.com, bob\nnobody@example.com,\n"
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/admin/users/import", strings.NewReader(body)))
+
+	require.Equal(t, http.StatusAccepted, w.Code)
+	var started JobResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &started))
+	assert.Equal(t, jobTypeUsersImport, started.Type)
+	assert.Equal(t, jobPath(started.ID), w.Header().Get("Location"))
+
+	job := waitJob(t, router, started.ID)
+	assert.Equal(t, string(jobs.StatusCompleted), job.Status)
+	assert.Equal(t, 3, job.Total)
+	assert.Equal(t, 3, job.Processed)
+	assert.Equal(t, 1, job.Failed)
+	assert.Equal(t, jobPath(started.ID)+"/result", job.ResultURL)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, job.ResultURL, nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, mediaTypeCSV, w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "attachment")
+	assert.Equal(t, "login,temporary_password,status\nalice,secret,created\nbob,,exists\n,,invalid\n", w.Body.String())
+}
+
+func TestAdminHandler_ImportUsers_InvalidCSV(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "empty file", body: ""},
+		{name: "missing login column", body: "email\nalice@example.com\n"},
+		{name: "header only", body: "login\n"},
+		{name: "malformed csv", body: "login\n\"alice\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Задача не запускается, поэтому сервис не вызывается
+			service := domainmocks.NewUserAdminServiceMock(t)
+			manager := jobs.NewManager(time.Hour, zap.NewNop())
+			defer manager.Shutdown()
+			router := newAdminRouter(NewAdminHandler(service, manager, zap.NewNop()))
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/admin/users/import", strings.NewReader(tt.body)))
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+		})
+	}
+}
+
+func TestAdminHandler_ExportUsers(t *testing.T) {
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	service := domainmocks.NewUserAdminServiceMock(t)
+	service.EXPECT().ExportUsers(mock.Anything, mock.Anything).
+		RunAndReturn(func(_ context.Context, progress jobs.Progress) ([]*domain.UserBalance, error) {
+			progress.SetTotal(2)
+			progress.Advance(true)
+			progress.Advance(true)
+			return []*domain.UserBalance{
+				{UserID: 1, Login: "alice", CreatedAt: createdAt, Current: 500.5, Withdrawn: 42},
+				{UserID: 2, Login: "bob", CreatedAt: createdAt},
+			}, nil
+		})
+
+	manager := jobs.NewManager(time.Hour, zap.NewNop())
+	defer manager.Shutdown()
+	router := newAdminRouter(NewAdminHandler(service, manager, zap.NewNop()))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/admin/users/export", nil))
+
+	require.Equal(t, http.StatusAccepted, w.Code)
+	var started JobResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &started))
+
+	job := waitJob(t, router, started.ID)
+	assert.Equal(t, string(jobs.StatusCompleted), job.Status)
+	assert.Equal(t, 2, job.Processed)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, job.ResultURL, nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "user_id,login,created_at,current,withdrawn\n"+
+		"1,alice,2024-01-02T03:04:05Z,500.50,42.00\n"+
+		"2,bob,2024-01-02T03:04:05Z,0.00,0.00\n", w.Body.String())
+}
+
+func TestAdminHandler_FailedJob(t *testing.T) {
+	service := domainmocks.NewUserAdminServiceMock(t)
+	service.EXPECT().ExportUsers(mock.Anything, mock.Anything).Return(nil, errors.New("db is down"))
+
+	manager := jobs.NewManager(time.Hour, zap.NewNop())
+	defer manager.Shutdown()
+	router := newAdminRouter(NewAdminHandler(service, manager, zap.NewNop()))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/admin/users/export", nil))
+	require.Equal(t, http.StatusAccepted, w.Code)
+	var started JobResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &started))
+
+	job := waitJob(t, router, started.ID)
+	assert.Equal(t, string(jobs.StatusFailed), job.Status)
+	assert.Equal(t, "db is down", job.Error)
+	assert.Empty(t, job.ResultURL)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, jobPath(started.ID)+"/result", nil))
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestAdminHandler_RunningJobResult(t *testing.T) {
+	release := make(chan struct{})
+	service := domainmocks.NewUserAdminServiceMock(t)
+	service.EXPECT().ExportUsers(mock.Anything, mock.Anything).
+		RunAndReturn(func(context.Context, jobs.Progress) ([]*domain.UserBalance, error) {
+			<-release
+			return nil, nil
+		})
+
+	manager := jobs.NewManager(time.Hour, zap.NewNop())
+	defer manager.Shutdown()
+	defer close(release)
+	router := newAdminRouter(NewAdminHandler(service, manager, zap.NewNop()))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/admin/users/export", nil))
+	var started JobResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &started))
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, jobPath(started.ID)+"/result", nil))
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestAdminHandler_JobNotFound(t *testing.T) {
+	manager := jobs.NewManager(time.Hour, zap.NewNop())
+	router := newAdminRouter(NewAdminHandler(domainmocks.NewUserAdminServiceMock(t), manager, zap.NewNop()))
+
+	for _, path := range []string{jobPath("unknown"), jobPath("unknown") + "/result"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusNotFound, w.Code, path)
+	}
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// OrderTransferService определяет перенос заказа другому пользователю.
+type OrderTransferService interface {
+	TransferOrder(ctx context.Context, number, login string, adminID int64) (*domain.OrderTransfer, error)
+}
+
+// OrderTransfersHandler обрабатывает запросы переноса заказов между пользователями
+type OrderTransfersHandler struct {
+	service OrderTransferService
+	logger  *zap.Logger
+}
+
+// NewOrderTransfersHandler создает новый OrderTransfersHandler
+func NewOrderTransfersHandler(service OrderTransferService, logger *zap.Logger) *OrderTransfersHandler {
+	return &OrderTransfersHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// orderTransferRequest - новый владелец заказа
+type orderTransferRequest struct {
+	Login string `json:"login"`
+}
+
+// Transfer передает заказ вместе с начислением пользователю из тела запроса
+func (h *OrderTransfersHandler) Transfer(w http.ResponseWriter, r *http.Request) {
+	adminID, ok := GetUserID(r.Context())
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+	number := chi.URLParam(r, "number")
+
+	var req orderTransferRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	transfer, err := h.service.TransferOrder(r.Context(), number, req.Login, adminID)
+	switch {
+	case err == nil:
+	case errors.Is(err, domain.ErrInvalidInput):
+		writeJSONError(w, r, http.StatusBadRequest, "login is required")
+		return
+	case errors.Is(err, domain.ErrOrderNotFound):
+		writeJSONError(w, r, http.StatusNotFound, "order not found")
+		return
+	case errors.Is(err, domain.ErrUserNotFound):
+		writeJSONError(w, r, http.StatusNotFound, "user not found")
+		return
+	case errors.Is(err, domain.ErrOrderAlreadyOwned):
+		writeJSONError(w, r, http.StatusConflict, "order already belongs to this user")
+		return
+	default:
+		h.logger.Error("failed to transfer order", zap.String("order", number), zap.Error(err))
+		writeInternalError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newOrderTransferResponse(transfer)); err != nil {
+		h.logger.Error("failed to encode order transfer response", zap.Error(err))
+	}
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	domainmocks "github.com/avc/loyalty-system-diploma/internal/domain/mocks"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func newOrderTransfersRouter(handler *OrderTransfersHandler) http.Handler {
+	r := chi.NewRouter()
+	r.Post("/api/admin/orders/{number}/transfer", handler.Transfer)
+	return r
+}
+
+func TestOrderTransfersHandler_Transfer(t *testing.T) {
+	number := "12345678903"
+	transferredAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(*domainmocks.OrderTransferServiceMock)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "Transferred",
+			body: `{"login":"bob"}`,
+			setupMock: func(m *domainmocks.OrderTransferServiceMock) {
+				m.EXPECT().TransferOrder(mock.Anything, number, "bob", int64(1)).Return(&domain.OrderTransfer{
+					OrderNumber:   number,
+					FromUserID:    7,
+					FromLogin:     "alice",
+					ToUserID:      8,
+					ToLogin:       "bob",
+					Amount:        500,
+					TransferredBy: 1,
+					CreatedAt:     transferredAt,
+				}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"order":"12345678903","from_login":"alice","to_login":"bob","accrual":500,
+				"transferred_at":"2024-03-01T10:00:00Z"}`,
+		},
+		{
+			name:           "Invalid body",
+			body:           `{"login":`,
+			setupMock:      func(m *domainmocks.OrderTransferServiceMock) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid request body"}`,
+		},
+		{
+			name: "Login missing",
+			body: `{}`,
+			setupMock: func(m *domainmocks.OrderTransferServiceMock) {
+				m.EXPECT().TransferOrder(mock.Anything, number, "", int64(1)).
+					Return(nil, fmt.Errorf("order service: %w", domain.ErrInvalidInput)).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"login is required"}`,
+		},
+		{
+			name: "Order not found",
+			body: `{"login":"bob"}`,
+			setupMock: func(m *domainmocks.OrderTransferServiceMock) {
+				m.EXPECT().TransferOrder(mock.Anything, number, "bob", int64(1)).Return(nil, domain.ErrOrderNotFound).Once()
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":"order not found"}`,
+		},
+		{
+			name: "User not found",
+			body: `{"login":"bob"}`,
+			setupMock: func(m *domainmocks.OrderTransferServiceMock) {
+				m.EXPECT().TransferOrder(mock.Anything, number, "bob", int64(1)).Return(nil, domain.ErrUserNotFound).Once()
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":"user not found"}`,
+		},
+		{
+			name: "Already owned",
+			body: `{"login":"alice"}`,
+			setupMock: func(m *domainmocks.OrderTransferServiceMock) {
+				m.EXPECT().TransferOrder(mock.Anything, number, "alice", int64(1)).Return(nil, domain.ErrOrderAlreadyOwned).Once()
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":"order already belongs to this user"}`,
+		},
+		{
+			name: "Internal error",
+			body: `{"login":"bob"}`,
+			setupMock: func(m *domainmocks.OrderTransferServiceMock) {
+				m.EXPECT().TransferOrder(mock.Anything, number, "bob", int64(1)).Return(nil, errors.New("boom")).Once()
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := domainmocks.NewOrderTransferServiceMock(t)
+			router := newOrderTransfersRouter(NewOrderTransfersHandler(svc, zap.NewNop()))
+			tt.setupMock(svc)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/admin/orders/"+number+"/transfer", strings.NewReader(tt.body))
+			req = req.WithContext(context.WithValue(req.Context(), UserIDKey, int64(1)))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestOrderTransfersHandler_Transfer_Unauthorized(t *testing.T) {
+	router := newOrderTransfersRouter(NewOrderTransfersHandler(domainmocks.NewOrderTransferServiceMock(t), zap.NewNop()))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/admin/orders/12345678903/transfer", strings.NewReader(`{"login":"bob"}`)))
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"sync/atomic"
+)
+
+// Amount - денежная сумма в ответе API. В JSON выводится с точностью до копейки,
+// без артефактов двоичной арифметики вида 100.10000000000001
+type Amount float64
+
+// fixedAmountDecimals включает вывод сумм ровно с двумя знаками после точки
+var fixedAmountDecimals atomic.Bool
+
+// SetFixedAmountDecimals задает вид сумм в JSON: ровно два знака после точки (100.10, 100.00)
+// или не больше двух без конечных нулей (100.1, 100). Вызывается при старте
+func SetFixedAmountDecimals(fixed bool) {
+	fixedAmountDecimals.Store(fixed)
+}
+
+// MarshalJSON округляет сумму до копеек
+func (a Amount) MarshalJSON() ([]byte, error) {
+	v := float64(a)
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		// Такие суммы не проходят валидацию, encoding/json вернул бы ошибку для float64
+		return nil, &json.UnsupportedValueError{Str: strconv.FormatFloat(v, 'g', -1, 64)}
+	}
+	cents := math.Round(v * 100)
+	if cents == 0 {
+		cents = 0 // -0 выводится как 0
+	}
+	if fixedAmountDecimals.Load() {
+		return strconv.AppendFloat(nil, cents/100, 'f', 2, 64), nil
+	}
+	return strconv.AppendFloat(nil, cents/100, 'f', -1, 64), nil
+}
+
+// amountPtr преобразует необязательную сумму
+func amountPtr(v *float64) *Amount {
+	if v == nil {
+		return nil
+	}
+	a := Amount(*v)
+	return &a
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAmount_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		amount  float64
+		compact string
+		fixed   string
+	}{
+		{100.10000000000001, "100.1", "100.10"},
+		{0.1 + 0.2, "0.3", "0.30"},
+		{729.98, "729.98", "729.98"},
+		{500, "500", "500.00"},
+		{1.005, "1", "1.00"}, // 1.005 в двоичном виде чуть меньше 1.005
+		{2.675000001, "2.68", "2.68"},
+		{-99.999, "-100", "-100.00"},
+		{-0.001, "0", "0.00"},
+		{99999999.99, "99999999.99", "99999999.99"},
+	}
+
+	defer SetFixedAmountDecimals(false)
+	for _, tt := range tests {
+		SetFixedAmountDecimals(false)
+		data, err := json.Marshal(Amount(tt.amount))
+		require.NoError(t, err)
+		assert.Equal(t, tt.compact, string(data), tt.amount)
+
+		SetFixedAmountDecimals(true)
+		data, err = json.Marshal(Amount(tt.amount))
+		require.NoError(t, err)
+		assert.Equal(t, tt.fixed, string(data), tt.amount)
+	}
+
+	_, err := json.Marshal(Amount(math.NaN()))
+	assert.Error(t, err)
+}
+
+func TestAmount_InResponse(t *testing.T) {
+	accrual := 100.10000000000001
+	data, err := json.Marshal(BalanceResponse{Current: Amount(accrual), Withdrawn: Amount(0.1 + 0.2)})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"current":100.1,"withdrawn":0.3}`, string(data))
+
+	data, err = json.Marshal(OrderResponse{Number: "1", Accrual: amountPtr(&accrual)})
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"accrual":100.1,`)
+
+	data, err = json.Marshal(OrderResponse{Number: "1"})
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "accrual")
+
+	// Суммы читаются обратно как обычные числа
+	var balance BalanceResponse
+	require.NoError(t, json.Unmarshal([]byte(`{"current":100.1,"withdrawn":0}`), &balance))
+	assert.Equal(t, Amount(100.1), balance.Current)
+}
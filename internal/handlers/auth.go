@@ -4,18 +4,32 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 
-	"github.com/avc/loyalty-system-diploma/internal/service"
+	"github.com/avc/loyalty-system-diploma/internal/domain"
 	"go.uber.org/zap"
 )
 
 // AuthService определяет методы аутентификации.
 type AuthService interface {
-	Register(ctx context.Context, login, password string) (string, error)
-	Login(ctx context.Context, login, password string) (string, error)
+	Register(ctx context.Context, login, password, device string) (*domain.AuthTokens, error)
+	Login(ctx context.Context, login, password, device string, remember bool) (*domain.AuthTokens, error)
+	Refresh(ctx context.Context, refreshToken, device string) (*domain.AuthTokens, error)
+	Logout(ctx context.Context, token string) error
+	CheckLogin(ctx context.Context, login string) error
 }
 
+const (
+	// refreshTokenHeader - заголовок ответа с refresh-токеном
+	refreshTokenHeader = "X-Refresh-Token"
+	// deviceHeader - заголовок запроса с идентификатором устройства клиента,
+	// к которому привязывается refresh-токен
+	deviceHeader = "X-Device-ID"
+	// maxDeviceLength - предельная длина идентификатора устройства
+	maxDeviceLength = 128
+)
+
 type AuthHandler struct {
 	authService AuthService
 	logger      *zap.Logger
@@ -31,56 +45,159 @@ func NewAuthHandler(authService AuthService, logger *zap.Logger) *AuthHandler {
 type authRequest struct {
 	Login    string `json:"login"`
 	Password string `json:"password"`
+	Remember bool   `json:"remember"` // Только для входа: выдать долгоживущий токен
 }
 
 func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
-	var req authRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	req, err := decodeAuthRequest(http.MaxBytesReader(w, r.Body, maxJSONBodySize))
+	device, ok := deviceID(r)
+	if err != nil || !ok {
 		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
 		return
 	}
 
-	token, err := h.authService.Register(r.Context(), req.Login, req.Password)
+	tokens, err := h.authService.Register(r.Context(), req.Login, req.Password, device)
 	if err != nil {
-		if errors.Is(err, service.ErrUserExists) {
+		if errors.Is(err, domain.ErrUserExists) {
 			http.Error(w, http.StatusText(http.StatusConflict), http.StatusConflict)
 			return
 		}
-		if errors.Is(err, service.ErrInvalidInput) {
+		if errors.Is(err, domain.ErrInvalidInput) {
 			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
 			return
 		}
 		h.logger.Error("failed to register", zap.Error(err), zap.String("login", req.Login))
-		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		writeInternalError(w, err)
 		return
 	}
 
-	w.Header().Set("Authorization", "Bearer "+token)
-	w.WriteHeader(http.StatusOK)
+	writeAuthTokens(w, tokens)
 }
 
 func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
-	var req authRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	req, err := decodeAuthRequest(http.MaxBytesReader(w, r.Body, maxJSONBodySize))
+	device, ok := deviceID(r)
+	if err != nil || !ok {
 		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
 		return
 	}
 
-	token, err := h.authService.Login(r.Context(), req.Login, req.Password)
+	tokens, err := h.authService.Login(r.Context(), req.Login, req.Password, device, req.Remember)
 	if err != nil {
-		if errors.Is(err, service.ErrInvalidCredentials) {
+		if errors.Is(err, domain.ErrInvalidCredentials) {
 			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
 			return
 		}
-		if errors.Is(err, service.ErrInvalidInput) {
+		if errors.Is(err, domain.ErrInvalidInput) {
 			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
 			return
 		}
 		h.logger.Error("failed to login", zap.Error(err), zap.String("login", req.Login))
-		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		writeInternalError(w, err)
+		return
+	}
+
+	writeAuthTokens(w, tokens)
+}
+
+// refreshRequest - тело запроса обновления токена
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// Refresh выдает новый токен доступа по refresh-токену. Refresh-токен одноразовый:
+// в ответе приходит новый
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var req refreshRequest
+	err := decodeJSONBody(http.MaxBytesReader(w, r.Body, maxJSONBodySize), &req)
+	device, ok := deviceID(r)
+	if err != nil || req.RefreshToken == "" || !ok {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	tokens, err := h.authService.Refresh(r.Context(), req.RefreshToken, device)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidToken) {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		h.logger.Error("failed to refresh token", zap.Error(err))
+		writeInternalError(w, err)
 		return
 	}
 
-	w.Header().Set("Authorization", "Bearer "+token)
+	writeAuthTokens(w, tokens)
+}
+
+// Logout отзывает предъявленный токен доступа: до истечения срока он больше не принимается.
+// Вместе с ним отзываются refresh-токены пользователя.
+// Маршрут защищен AuthMiddleware, поэтому токен уже проверен
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	token, ok := bearerToken(r)
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.authService.Logout(r.Context(), token); err != nil {
+		if errors.Is(err, domain.ErrInvalidToken) {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		h.logger.Error("failed to logout", zap.Error(err))
+		writeInternalError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// deviceID возвращает идентификатор устройства из заголовка X-Device-ID. Токен, выданный
+// без заголовка, не привязан к устройству; токен, выданный устройству, без заголовка не
+// обновляется. Слишком длинный идентификатор - ошибка запроса
+func deviceID(r *http.Request) (string, bool) {
+	device := r.Header.Get(deviceHeader)
+	return device, len(device) <= maxDeviceLength
+}
+
+// writeAuthTokens отдает выданные токены в заголовках ответа
+func writeAuthTokens(w http.ResponseWriter, tokens *domain.AuthTokens) {
+	w.Header().Set("Authorization", "Bearer "+tokens.AccessToken)
+	if tokens.RefreshToken != "" {
+		w.Header().Set(refreshTokenHeader, tokens.RefreshToken)
+	}
+	w.Header().Set("Cache-Control", "no-store")
 	w.WriteHeader(http.StatusOK)
 }
+
+// loginAvailabilityMayBeAvailable - логин подходит для регистрации, если еще не занят
+const loginAvailabilityMayBeAvailable = "may_be_available"
+
+// loginAvailabilityResponse - результат проверки логина
+type loginAvailabilityResponse struct {
+	Login  string `json:"login"`
+	Status string `json:"status"`
+}
+
+// Availability проверяет логин перед регистрацией. Ответ не сообщает, занят ли логин,
+// поэтому подходящий по формату логин всегда "may_be_available".
+func (h *AuthHandler) Availability(w http.ResponseWriter, r *http.Request) {
+	login := r.URL.Query().Get("login")
+
+	if err := h.authService.CheckLogin(r.Context(), login); err != nil {
+		if errors.Is(err, domain.ErrInvalidInput) {
+			writeJSONError(w, r, http.StatusBadRequest, fmt.Sprintf("login must be from 1 to %d bytes", domain.MaxLoginLength))
+			return
+		}
+		h.logger.Error("failed to check login", zap.Error(err))
+		writeInternalError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(w).Encode(loginAvailabilityResponse{Login: login, Status: loginAvailabilityMayBeAvailable}); err != nil {
+		h.logger.Error("failed to encode login availability response", zap.Error(err))
+	}
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"math/rand/v2"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"go.uber.org/zap"
+)
+
+// AuthzAuditConfig - настройки журнала аудита авторизации
+type AuthzAuditConfig struct {
+	Routes      []string // Записываемые маршруты вида "POST /api/user/balance/withdraw"
+	SampleRatio float64  // Доля записываемых разрешенных запросов от 0 до 1
+}
+
+// AuthzAuditMiddleware записывает в журнал аудита решение об авторизации запросов
+// к маршрутам из cfg.Routes: пользователя, маршрут и allowed или denied. Отказом считается
+// ответ 401 или 403. Отказы записываются всегда, разрешенные запросы - с долей
+// cfg.SampleRatio. Должен подключаться после AuthMiddleware и до middleware, которые
+// могут отказать в доступе (ImpersonationMiddleware, AdminMiddleware), иначе их отказы
+// не попадут в журнал.
+func AuthzAuditMiddleware(cfg AuthzAuditConfig, audit *zap.Logger) func(http.Handler) http.Handler {
+	routes := make(map[string]struct{}, len(cfg.Routes))
+	for _, route := range cfg.Routes {
+		routes[route] = struct{}{}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r)
+
+			// Шаблон маршрута известен только после маршрутизации
+			route := routePattern(r)
+			if _, ok := routes[r.Method+" "+route]; !ok {
+				return
+			}
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			decision := "allowed"
+			if status == http.StatusUnauthorized || status == http.StatusForbidden {
+				decision = "denied"
+			} else if rand.Float64() >= cfg.SampleRatio {
+				return
+			}
+
+			userID, _ := GetUserID(r.Context())
+			requestID, _ := r.Context().Value(RequestIDKey).(string)
+			fields := []zap.Field{
+				zap.String("request_id", requestID),
+				zap.Int64("user_id", userID),
+				zap.String("method", r.Method),
+				zap.String("route", route),
+				zap.String("decision", decision),
+				zap.Int("status", status),
+			}
+			if impersonatorID, ok := GetImpersonatorID(r.Context()); ok {
+				fields = append(fields, zap.Int64("impersonator_id", impersonatorID))
+			}
+			audit.Info("authorization decision", fields...)
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestAuthzAuditMiddleware(t *testing.T) {
+	newRouter := func(ratio float64, readOnly bool) (*chi.Mux, *observer.ObservedLogs) {
+		core, logs := observer.New(zap.InfoLevel)
+		audit := zap.New(core)
+		ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+
+		// Как в роутере приложения: middleware группы выполняются после выбора маршрута
+		r := chi.NewRouter()
+		r.Group(func(r chi.Router) {
+			r.Use(func(next http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					ctx := context.WithValue(r.Context(), UserIDKey, int64(7))
+					if r.Header.Get("X-Impersonator") != "" {
+						ctx = context.WithValue(ctx, ImpersonatorIDKey, int64(1))
+					}
+					next.ServeHTTP(w, r.WithContext(ctx))
+				})
+			})
+			r.Use(AuthzAuditMiddleware(AuthzAuditConfig{
+				Routes:      []string{"POST /api/user/balance/withdraw", "POST /api/user/orders/{number}/dispute"},
+				SampleRatio: ratio,
+			}, audit))
+			r.Use(ImpersonationMiddleware(readOnly, zap.NewNop()))
+			r.Post("/api/user/balance/withdraw", ok)
+			r.Post("/api/user/orders/{number}/dispute", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusForbidden)
+			})
+			r.Get("/api/user/balance", ok)
+		})
+		return r, logs
+	}
+	serve := func(r http.Handler, method, path string, impersonated bool) int {
+		req := httptest.NewRequest(method, path, nil)
+		if impersonated {
+			req.Header.Set("X-Impersonator", "1")
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	t.Run("Allowed request recorded", func(t *testing.T) {
+		r, logs := newRouter(1, true)
+		require.Equal(t, http.StatusOK, serve(r, http.MethodPost, "/api/user/balance/withdraw", false))
+
+		require.Equal(t, 1, logs.Len())
+		fields := logs.All()[0].ContextMap()
+		assert.Equal(t, int64(7), fields["user_id"])
+		assert.Equal(t, "POST", fields["method"])
+		assert.Equal(t, "/api/user/balance/withdraw", fields["route"])
+		assert.Equal(t, "allowed", fields["decision"])
+		assert.NotContains(t, fields, "impersonator_id")
+	})
+
+	t.Run("Allowed requests are sampled", func(t *testing.T) {
+		r, logs := newRouter(0, true)
+		for range 10 {
+			serve(r, http.MethodPost, "/api/user/balance/withdraw", false)
+		}
+
+		assert.Zero(t, logs.Len())
+	})
+
+	t.Run("Denials are always recorded", func(t *testing.T) {
+		r, logs := newRouter(0, true)
+		require.Equal(t, http.StatusForbidden, serve(r, http.MethodPost, "/api/user/orders/123/dispute", false))
+		// Отказ ImpersonationMiddleware в режиме только для чтения
+		require.Equal(t, http.StatusForbidden, serve(r, http.MethodPost, "/api/user/balance/withdraw", true))
+
+		require.Equal(t, 2, logs.Len())
+		assert.Equal(t, "/api/user/orders/{number}/dispute", logs.All()[0].ContextMap()["route"])
+		assert.Equal(t, "denied", logs.All()[0].ContextMap()["decision"])
+		fields := logs.All()[1].ContextMap()
+		assert.Equal(t, "denied", fields["decision"])
+		assert.Equal(t, int64(1), fields["impersonator_id"])
+		assert.Equal(t, int64(http.StatusForbidden), fields["status"])
+	})
+
+	t.Run("Other routes are not recorded", func(t *testing.T) {
+		r, logs := newRouter(1, true)
+		require.Equal(t, http.StatusOK, serve(r, http.MethodGet, "/api/user/balance", false))
+
+		assert.Zero(t, logs.Len())
+	})
+}
@@ -2,20 +2,28 @@ package handlers
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/avc/loyalty-system-diploma/internal/domain"
-	"github.com/avc/loyalty-system-diploma/internal/service"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
 // BalanceService определяет методы работы с балансом.
 type BalanceService interface {
 	GetBalance(ctx context.Context, userID int64) (*domain.Balance, error)
-	Withdraw(ctx context.Context, userID int64, orderNumber string, amount float64) error
-	GetWithdrawals(ctx context.Context, userID int64) ([]*domain.Transaction, error)
+	GetBalanceDetails(ctx context.Context, userID int64) (*domain.BalanceDetails, error)
+	Withdraw(ctx context.Context, userID int64, orderNumber string, amount float64, partner string) error
+	GetWithdrawals(ctx context.Context, userID int64, after *domain.WithdrawalCursor, limit int) (*domain.WithdrawalPage, error)
+	GetWithdrawalSummary(ctx context.Context, userID int64) (*domain.WithdrawalSummary, error)
+	GetWithdrawal(ctx context.Context, userID int64, publicID uuid.UUID) (*domain.Transaction, error)
 }
 
 type BalanceHandler struct {
@@ -30,6 +38,8 @@ func NewBalanceHandler(balanceService BalanceService, logger *zap.Logger) *Balan
 	}
 }
 
+// GetBalance возвращает баланс пользователя.
+// С ?detailed=true ответ дополняется ожидаемыми начислениями по необработанным заказам.
 func (h *BalanceHandler) GetBalance(w http.ResponseWriter, r *http.Request) {
 	userID, ok := GetUserID(r.Context())
 	if !ok {
@@ -37,22 +47,51 @@ func (h *BalanceHandler) GetBalance(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	detailed := false
+	if value := r.URL.Query().Get("detailed"); value != "" {
+		var err error
+		if detailed, err = strconv.ParseBool(value); err != nil {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+	}
+	if detailed {
+		h.getBalanceDetails(w, r, userID)
+		return
+	}
+
 	balance, err := h.balanceService.GetBalance(r.Context(), userID)
 	if err != nil {
 		h.logger.Error("failed to get balance", zap.Error(err))
-		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		writeInternalError(w, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(balance); err != nil {
+	if err := json.NewEncoder(w).Encode(newBalanceResponse(balance)); err != nil {
 		h.logger.Error("failed to encode balance response", zap.Error(err))
 	}
 }
 
+// getBalanceDetails отвечает расширенным представлением баланса
+func (h *BalanceHandler) getBalanceDetails(w http.ResponseWriter, r *http.Request, userID int64) {
+	details, err := h.balanceService.GetBalanceDetails(r.Context(), userID)
+	if err != nil {
+		h.logger.Error("failed to get balance details", zap.Error(err))
+		writeInternalError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newBalanceDetailsResponse(details)); err != nil {
+		h.logger.Error("failed to encode balance details response", zap.Error(err))
+	}
+}
+
 type withdrawRequest struct {
-	Order string  `json:"order"`
-	Sum   float64 `json:"sum"`
+	Order   string  `json:"order"`
+	Sum     float64 `json:"sum"`
+	Partner string  `json:"partner"`
 }
 
 func (h *BalanceHandler) Withdraw(w http.ResponseWriter, r *http.Request) {
@@ -62,30 +101,66 @@ func (h *BalanceHandler) Withdraw(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var req withdrawRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	req, err := decodeWithdrawRequest(http.MaxBytesReader(w, r.Body, maxJSONBodySize))
+	if err != nil {
 		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
 		return
 	}
 
-	err := h.balanceService.Withdraw(r.Context(), userID, req.Order, req.Sum)
+	err = h.balanceService.Withdraw(r.Context(), userID, req.Order, req.Sum, req.Partner)
 	if err != nil {
-		if errors.Is(err, service.ErrInvalidOrderNumber) {
+		if errors.Is(err, domain.ErrInvalidPartner) {
+			writeJSONError(w, r, http.StatusBadRequest, "partner is too long")
+			return
+		}
+		var amountErr *domain.AmountError
+		if errors.As(err, &amountErr) {
+			writeErrorResponse(w, r, http.StatusBadRequest, ErrorResponse{Error: amountErrorMessage(amountErr.Reason), Code: amountErr.Reason})
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidOrderNumber) {
 			http.Error(w, http.StatusText(http.StatusUnprocessableEntity), http.StatusUnprocessableEntity)
 			return
 		}
-		if errors.Is(err, service.ErrInsufficientFunds) {
+		if errors.Is(err, domain.ErrInsufficientFunds) {
 			http.Error(w, http.StatusText(http.StatusPaymentRequired), http.StatusPaymentRequired)
 			return
 		}
+		if code, ok := withdrawalLimitCode(err); ok {
+			writeErrorResponse(w, r, http.StatusForbidden, ErrorResponse{Error: "withdrawal limit exceeded", Code: code})
+			return
+		}
 		h.logger.Error("failed to withdraw", zap.Error(err))
-		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		writeInternalError(w, err)
 		return
 	}
 
 	w.WriteHeader(http.StatusOK)
 }
 
+// amountErrorMessage описывает причину отклонения суммы для клиента
+func amountErrorMessage(reason string) string {
+	if reason == domain.AmountReasonPrecision {
+		return "sum must have at most two decimal places"
+	}
+	return "sum must be from 0.01 to 99999999.99"
+}
+
+// withdrawalLimitCode возвращает код ошибки API для превышенного ограничения списаний
+func withdrawalLimitCode(err error) (string, bool) {
+	switch {
+	case errors.Is(err, domain.ErrWithdrawalAmountLimit):
+		return "per_withdrawal_limit", true
+	case errors.Is(err, domain.ErrDailyWithdrawalLimit):
+		return "daily_limit", true
+	case errors.Is(err, domain.ErrMonthlyWithdrawalLimit):
+		return "monthly_limit", true
+	}
+	return "", false
+}
+
+// GetWithdrawals возвращает страницу истории списаний. Курсор следующей страницы
+// передается в заголовке Link, а в конверте - еще и в meta.pagination.next_cursor
 func (h *BalanceHandler) GetWithdrawals(w http.ResponseWriter, r *http.Request) {
 	userID, ok := GetUserID(r.Context())
 	if !ok {
@@ -93,20 +168,134 @@ func (h *BalanceHandler) GetWithdrawals(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	withdrawals, err := h.balanceService.GetWithdrawals(r.Context(), userID)
+	query := r.URL.Query()
+	limit, ok := intQueryParam(query, "limit")
+	if !ok {
+		writeJSONError(w, r, http.StatusBadRequest, "limit must be a non-negative integer")
+		return
+	}
+	after, ok := decodeWithdrawalCursor(query.Get("cursor"))
+	if !ok {
+		writeJSONError(w, r, http.StatusBadRequest, "invalid cursor")
+		return
+	}
+
+	page, err := h.balanceService.GetWithdrawals(r.Context(), userID, after, limit)
 	if err != nil {
+		if errors.Is(err, domain.ErrInvalidInput) {
+			writeJSONError(w, r, http.StatusBadRequest, "invalid withdrawals page")
+			return
+		}
 		h.logger.Error("failed to get withdrawals", zap.Error(err))
-		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		writeInternalError(w, err)
 		return
 	}
+	withdrawals := page.Withdrawals
+
+	var nextCursor string
+	if page.Next != nil {
+		nextCursor = encodeWithdrawalCursor(page.Next)
+		next := r.URL.Query()
+		next.Set("cursor", nextCursor)
+		w.Header().Set("Link", fmt.Sprintf(`<%s?%s>; rel="next"`, r.URL.Path, next.Encode()))
+	}
 
+	w.Header().Add("Vary", "Accept")
+	if prefersEnvelope(r.Header.Get("Accept")) {
+		writeEnvelopePage(w, r, newWithdrawalsResponse(withdrawals), PaginationResponse{
+			HasMore:    page.Next != nil,
+			NextCursor: nextCursor,
+		}, h.logger)
+		return
+	}
 	if len(withdrawals) == 0 {
 		w.WriteHeader(http.StatusNoContent)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(withdrawals); err != nil {
+	if err := json.NewEncoder(w).Encode(newWithdrawalsResponse(withdrawals)); err != nil {
 		h.logger.Error("failed to encode withdrawals response", zap.Error(err))
 	}
 }
+
+// encodeWithdrawalCursor кодирует позицию в истории списаний в непрозрачную для клиента строку
+func encodeWithdrawalCursor(cursor *domain.WithdrawalCursor) string {
+	raw := fmt.Sprintf("%d.%d", cursor.ProcessedAt.UnixMicro(), cursor.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeWithdrawalCursor разбирает курсор из query параметра, nil - если параметр не задан
+func decodeWithdrawalCursor(value string) (*domain.WithdrawalCursor, bool) {
+	if value == "" {
+		return nil, true
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, false
+	}
+	micros, id, found := strings.Cut(string(raw), ".")
+	if !found {
+		return nil, false
+	}
+	processedAt, err := strconv.ParseInt(micros, 10, 64)
+	if err != nil {
+		return nil, false
+	}
+	cursor := &domain.WithdrawalCursor{ProcessedAt: time.UnixMicro(processedAt).UTC()}
+	if cursor.ID, err = strconv.ParseInt(id, 10, 64); err != nil || cursor.ID <= 0 {
+		return nil, false
+	}
+	return cursor, true
+}
+
+// GetWithdrawalSummary возвращает сводку по списаниям пользователя без истории
+func (h *BalanceHandler) GetWithdrawalSummary(w http.ResponseWriter, r *http.Request) {
+	userID, ok := GetUserID(r.Context())
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	summary, err := h.balanceService.GetWithdrawalSummary(r.Context(), userID)
+	if err != nil {
+		h.logger.Error("failed to get withdrawal summary", zap.Error(err))
+		writeInternalError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newWithdrawalSummaryResponse(summary)); err != nil {
+		h.logger.Error("failed to encode withdrawal summary response", zap.Error(err))
+	}
+}
+
+// GetWithdrawal возвращает списание пользователя по публичному идентификатору
+func (h *BalanceHandler) GetWithdrawal(w http.ResponseWriter, r *http.Request) {
+	userID, ok := GetUserID(r.Context())
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	publicID, ok := publicIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	withdrawal, err := h.balanceService.GetWithdrawal(r.Context(), userID, publicID)
+	if err != nil {
+		if errors.Is(err, domain.ErrTransactionNotFound) {
+			writeJSONError(w, r, http.StatusNotFound, "withdrawal not found")
+			return
+		}
+		h.logger.Error("failed to get withdrawal", zap.Error(err))
+		writeInternalError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newWithdrawalResponse(withdrawal)); err != nil {
+		h.logger.Error("failed to encode withdrawal response", zap.Error(err))
+	}
+}
@@ -0,0 +1,302 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+)
+
+// Поддерживаемые кодировки в порядке предпочтения
+const (
+	encodingBrotli = "br"
+	encodingGzip   = "gzip"
+)
+
+// compressibleContentTypes перечисляет типы, которые имеет смысл сжимать.
+// Префиксы с "/" на конце покрывают все подтипы.
+var compressibleContentTypes = []string{
+	"text/",
+	"application/json",
+	"application/x-ndjson",
+	"application/javascript",
+	"application/xml",
+	"image/svg+xml",
+}
+
+// CompressionConfig содержит настройки сжатия ответов
+type CompressionConfig struct {
+	Level                int      // Уровень сжатия (0 - сжатие отключено)
+	MinSize              int      // Ответы меньше этого размера не сжимаются
+	ExcludedContentTypes []string // Типы содержимого, которые не сжимаются
+	ExcludedPaths        []string // Префиксы путей, ответы которых не сжимаются
+}
+
+// CompressionMiddleware сжимает ответы gzip или br в зависимости от Accept-Encoding.
+// Ответ буферизуется до MinSize байт: короткие ответы отдаются как есть,
+// чтобы не тратить CPU на горячих эндпоинтах с маленьким телом.
+func CompressionMiddleware(cfg CompressionConfig) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if cfg.Level <= 0 {
+			return next
+		}
+
+		gzipLevel := min(cfg.Level, gzip.BestCompression)
+		brotliLevel := min(cfg.Level, brotli.BestCompression)
+		gzipPool := &sync.Pool{New: func() any {
+			w, _ := gzip.NewWriterLevel(io.Discard, gzipLevel)
+			return w
+		}}
+		brotliPool := &sync.Pool{New: func() any {
+			return brotli.NewWriterLevel(io.Discard, brotliLevel)
+		}}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+
+			encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+			if encoding == "" || r.Method == http.MethodHead || hasPrefix(r.URL.Path, cfg.ExcludedPaths) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &compressWriter{
+				ResponseWriter: w,
+				config:         &cfg,
+				encoding:       encoding,
+				status:         http.StatusOK,
+			}
+			switch encoding {
+			case encodingBrotli:
+				cw.pool = brotliPool
+			case encodingGzip:
+				cw.pool = gzipPool
+			}
+			defer cw.Close()
+
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+// resettableWriter - общий интерфейс gzip.Writer и brotli.Writer
+type resettableWriter interface {
+	io.WriteCloser
+	Reset(w io.Writer)
+	Flush() error
+}
+
+// compressWriter откладывает решение о сжатии, пока не наберется MinSize байт,
+// не будет вызван Flush или не завершится обработчик
+type compressWriter struct {
+	http.ResponseWriter
+	config   *CompressionConfig
+	encoding string
+	pool     *sync.Pool
+
+	status      int
+	wroteHeader bool
+	decided     bool
+	buf         bytes.Buffer
+	encoder     resettableWriter
+}
+
+func (cw *compressWriter) WriteHeader(status int) {
+	if cw.wroteHeader {
+		return
+	}
+	cw.wroteHeader = true
+	cw.status = status
+}
+
+func (cw *compressWriter) Write(p []byte) (int, error) {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+
+	if !cw.decided {
+		cw.buf.Write(p)
+		if cw.buf.Len() < cw.config.MinSize {
+			return len(p), nil
+		}
+		if err := cw.decide(false); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+
+	if cw.encoder != nil {
+		return cw.encoder.Write(p)
+	}
+	return cw.ResponseWriter.Write(p)
+}
+
+// decide выбирает режим ответа, отправляет заголовки и накопленный буфер.
+// Для потоковых ответов (streaming) порог MinSize не учитывается.
+func (cw *compressWriter) decide(streaming bool) error {
+	cw.decided = true
+
+	if cw.shouldCompress(streaming) {
+		h := cw.Header()
+		h.Set("Content-Encoding", cw.encoding)
+		h.Del("Content-Length")
+		cw.encoder = cw.pool.Get().(resettableWriter)
+		cw.encoder.Reset(cw.ResponseWriter)
+	}
+
+	cw.ResponseWriter.WriteHeader(cw.status)
+	if cw.buf.Len() == 0 {
+		return nil
+	}
+
+	var err error
+	if cw.encoder != nil {
+		_, err = cw.encoder.Write(cw.buf.Bytes())
+	} else {
+		_, err = cw.ResponseWriter.Write(cw.buf.Bytes())
+	}
+	cw.buf.Reset()
+	return err
+}
+
+func (cw *compressWriter) shouldCompress(streaming bool) bool {
+	h := cw.Header()
+	if h.Get("Content-Encoding") != "" {
+		return false
+	}
+	if cw.status < http.StatusOK || cw.status == http.StatusNoContent || cw.status == http.StatusNotModified {
+		return false
+	}
+	if !streaming && cw.buf.Len() < cw.config.MinSize {
+		return false
+	}
+
+	contentType := h.Get("Content-Type")
+	if contentType == "" {
+		contentType = http.DetectContentType(cw.buf.Bytes())
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return hasPrefix(mediaType, compressibleContentTypes) &&
+		!matchesContentType(mediaType, cw.config.ExcludedContentTypes)
+}
+
+// Flush принимает решение о сжатии досрочно, чтобы потоковые ответы не задерживались
+func (cw *compressWriter) Flush() {
+	if !cw.decided {
+		if !cw.wroteHeader {
+			cw.WriteHeader(http.StatusOK)
+		}
+		if err := cw.decide(true); err != nil {
+			return
+		}
+	}
+	if cw.encoder != nil {
+		_ = cw.encoder.Flush()
+	}
+	if f, ok := cw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Close дописывает буфер и возвращает кодировщик в пул
+func (cw *compressWriter) Close() {
+	if !cw.decided {
+		if !cw.wroteHeader {
+			return
+		}
+		if cw.buf.Len() == 0 {
+			cw.decided = true
+			cw.ResponseWriter.WriteHeader(cw.status)
+			return
+		}
+		// Ответ не набрал MinSize и уходит без сжатия
+		cw.Header().Set("Content-Length", strconv.Itoa(cw.buf.Len()))
+		_ = cw.decide(false)
+	}
+	if cw.encoder != nil {
+		_ = cw.encoder.Close()
+		cw.encoder.Reset(io.Discard)
+		cw.pool.Put(cw.encoder)
+		cw.encoder = nil
+	}
+}
+
+func (cw *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if hj, ok := cw.ResponseWriter.(http.Hijacker); ok {
+		return hj.Hijack()
+	}
+	return nil, nil, http.ErrNotSupported
+}
+
+func (cw *compressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// negotiateEncoding выбирает кодировку из Accept-Encoding, предпочитая br.
+// Кодировки с q=0 считаются запрещенными.
+func negotiateEncoding(acceptEncoding string) string {
+	var gzipOK, brotliOK bool
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if v, err := strconv.ParseFloat(q, 64); err == nil && v == 0 {
+				continue
+			}
+		}
+		switch name {
+		case encodingBrotli:
+			brotliOK = true
+		case encodingGzip:
+			gzipOK = true
+		}
+	}
+
+	switch {
+	case brotliOK:
+		return encodingBrotli
+	case gzipOK:
+		return encodingGzip
+	default:
+		return ""
+	}
+}
+
+func hasPrefix(value string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if prefix != "" && strings.HasPrefix(value, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesContentType(mediaType string, types []string) bool {
+	for _, t := range types {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" {
+			continue
+		}
+		if strings.HasSuffix(t, "/") || strings.HasSuffix(t, "/*") {
+			if strings.HasPrefix(mediaType, strings.TrimSuffix(t, "*")) {
+				return true
+			}
+			continue
+		}
+		if mediaType == t {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompressionMiddleware(t *testing.T) {
+	largeBody := strings.Repeat(`{"number":"12345678903","status":"PROCESSED"},`, 100)
+
+	cfg := CompressionConfig{
+		Level:                5,
+		MinSize:              1024,
+		ExcludedContentTypes: []string{"text/event-stream"},
+		ExcludedPaths:        []string{"/metrics"},
+	}
+
+	serve := func(path, acceptEncoding, contentType, body string) *httptest.ResponseRecorder {
+		handler := CompressionMiddleware(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", contentType)
+			w.WriteHeader(http.StatusOK)
+			// Пишем частями, чтобы проверить буферизацию
+			for i := 0; i < len(body); i += 100 {
+				io.WriteString(w, body[i:min(i+100, len(body))])
+			}
+		}))
+
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if acceptEncoding != "" {
+			req.Header.Set("Accept-Encoding", acceptEncoding)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Gzip for large JSON", func(t *testing.T) {
+		w := serve("/api/user/orders", "gzip", "application/json", largeBody)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+		assert.Contains(t, w.Header().Values("Vary"), "Accept-Encoding")
+
+		reader, err := gzip.NewReader(w.Body)
+		require.NoError(t, err)
+		decoded, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		assert.Equal(t, largeBody, string(decoded))
+	})
+
+	t.Run("Brotli preferred over gzip", func(t *testing.T) {
+		w := serve("/api/user/orders", "gzip, deflate, br", "application/json", largeBody)
+
+		assert.Equal(t, "br", w.Header().Get("Content-Encoding"))
+		decoded, err := io.ReadAll(brotli.NewReader(w.Body))
+		require.NoError(t, err)
+		assert.Equal(t, largeBody, string(decoded))
+	})
+
+	t.Run("Brotli disabled with q=0", func(t *testing.T) {
+		w := serve("/api/user/orders", "br;q=0, gzip", "application/json", largeBody)
+
+		assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	})
+
+	t.Run("Small response is not compressed", func(t *testing.T) {
+		w := serve("/api/user/balance", "gzip, br", "application/json", `{"current":500.5,"withdrawn":42}`)
+
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+		assert.Equal(t, "32", w.Header().Get("Content-Length"))
+		assert.Equal(t, `{"current":500.5,"withdrawn":42}`, w.Body.String())
+	})
+
+	t.Run("Excluded content type", func(t *testing.T) {
+		w := serve("/api/events", "gzip", "text/event-stream", largeBody)
+
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+		assert.Equal(t, largeBody, w.Body.String())
+	})
+
+	t.Run("Incompressible content type", func(t *testing.T) {
+		w := serve("/api/file", "gzip", "image/png", largeBody)
+
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+		assert.Equal(t, largeBody, w.Body.String())
+	})
+
+	t.Run("Excluded path", func(t *testing.T) {
+		w := serve("/metrics", "gzip", "text/plain", largeBody)
+
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+		assert.Equal(t, largeBody, w.Body.String())
+	})
+
+	t.Run("No Accept-Encoding", func(t *testing.T) {
+		w := serve("/api/user/orders", "", "application/json", largeBody)
+
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+		assert.Equal(t, largeBody, w.Body.String())
+	})
+}
+
+func TestCompressionMiddleware_StatusWithoutBody(t *testing.T) {
+	handler := CompressionMiddleware(CompressionConfig{Level: 5})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/user/orders", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.Zero(t, w.Body.Len())
+}
+
+func TestCompressionMiddleware_FlushStartsStreaming(t *testing.T) {
+	handler := CompressionMiddleware(CompressionConfig{Level: 5, MinSize: 1024})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		io.WriteString(w, "{\"n\":1}\n")
+		w.(http.Flusher).Flush()
+		io.WriteString(w, "{\"n\":2}\n")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/user/orders", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.True(t, w.Flushed)
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+
+	reader, err := gzip.NewReader(w.Body)
+	require.NoError(t, err)
+	decoded, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, "{\"n\":1}\n{\"n\":2}\n", string(decoded))
+}
+
+func TestCompressionMiddleware_Disabled(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	handler := CompressionMiddleware(CompressionConfig{Level: 0})(next)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Empty(t, w.Header().Get("Vary"))
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		acceptEncoding string
+		expected       string
+	}{
+		{"", ""},
+		{"identity", ""},
+		{"gzip", "gzip"},
+		{"GZIP", "gzip"},
+		{"gzip, br", "br"},
+		{"br;q=1.0, gzip;q=0.8", "br"},
+		{"br;q=0", ""},
+		{"deflate", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.acceptEncoding, func(t *testing.T) {
+			assert.Equal(t, tt.expected, negotiateEncoding(tt.acceptEncoding))
+		})
+	}
+}
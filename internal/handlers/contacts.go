@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/avc/loyalty-system-diploma/internal/jobs"
+	"go.uber.org/zap"
+)
+
+// ContactsService определяет управление контактными данными пользователей.
+type ContactsService interface {
+	GetContacts(ctx context.Context, userID int64) (*domain.UserContacts, error)
+	UpdateContacts(ctx context.Context, userID int64, contacts domain.UserContacts) (*domain.UserContacts, error)
+	RewrapContacts(ctx context.Context, progress jobs.Progress) (int, error)
+}
+
+const jobTypeContactsRewrap = "contacts_rewrap"
+
+// ContactsHandler обрабатывает запросы к контактным данным пользователя
+type ContactsHandler struct {
+	service ContactsService
+	jobs    JobRunner
+	logger  *zap.Logger
+}
+
+// NewContactsHandler создает новый ContactsHandler
+func NewContactsHandler(service ContactsService, jobs JobRunner, logger *zap.Logger) *ContactsHandler {
+	return &ContactsHandler{
+		service: service,
+		jobs:    jobs,
+		logger:  logger,
+	}
+}
+
+// contactsRequest - новые контактные данные; пустое или пропущенное поле удаляет контакт
+type contactsRequest struct {
+	Email string `json:"email"`
+	Phone string `json:"phone"`
+}
+
+// Get возвращает контактные данные текущего пользователя
+func (h *ContactsHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID, ok := GetUserID(r.Context())
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	contacts, err := h.service.GetContacts(r.Context(), userID)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	h.writeContacts(w, contacts)
+}
+
+// Update заменяет контактные данные текущего пользователя
+func (h *ContactsHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID, ok := GetUserID(r.Context())
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	var req contactsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	contacts, err := h.service.UpdateContacts(r.Context(), userID, domain.UserContacts{Email: req.Email, Phone: req.Phone})
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	h.writeContacts(w, contacts)
+}
+
+// Rewrap запускает задачу перешифрования контактов текущим мастер-ключом после его смены
+func (h *ContactsHandler) Rewrap(w http.ResponseWriter, r *http.Request) {
+	snapshot := h.jobs.Start(jobTypeContactsRewrap, func(ctx context.Context, progress jobs.Progress) (*jobs.Result, error) {
+		_, err := h.service.RewrapContacts(ctx, progress)
+		return nil, err
+	})
+
+	writeJobAccepted(w, snapshot, h.logger)
+}
+
+func (h *ContactsHandler) writeContacts(w http.ResponseWriter, contacts *domain.UserContacts) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(w).Encode(newContactsResponse(contacts)); err != nil {
+		h.logger.Error("failed to encode contacts response", zap.Error(err))
+	}
+}
+
+func (h *ContactsHandler) writeError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidContacts):
+		writeJSONError(w, r, http.StatusBadRequest, "email must be a plain address and phone must be in international format")
+	case errors.Is(err, domain.ErrUserNotFound):
+		writeJSONError(w, r, http.StatusNotFound, "user not found")
+	case errors.Is(err, domain.ErrPIIEncryptionDisabled):
+		writeJSONError(w, r, http.StatusConflict, "personal data encryption is not configured")
+	default:
+		h.logger.Error("failed to manage contacts", zap.Error(err))
+		writeInternalError(w, err)
+	}
+}
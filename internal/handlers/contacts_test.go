@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	domainmocks "github.com/avc/loyalty-system-diploma/internal/domain/mocks"
+	"github.com/avc/loyalty-system-diploma/internal/jobs"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestContactsHandler_Get(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         *int64
+		setupMock      func(*domainmocks.ContactsServiceMock)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "Success",
+			userID: ptrInt64(1),
+			setupMock: func(m *domainmocks.ContactsServiceMock) {
+				m.EXPECT().GetContacts(mock.Anything, int64(1)).
+					Return(&domain.UserContacts{Email: "alice@example.com", Phone: "+79991234567"}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"email":"alice@example.com","phone":"+79991234567"}`,
+		},
+		{
+			name:   "Encryption disabled",
+			userID: ptrInt64(1),
+			setupMock: func(m *domainmocks.ContactsServiceMock) {
+				m.EXPECT().GetContacts(mock.Anything, int64(1)).Return(nil, domain.ErrPIIEncryptionDisabled).Once()
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:   "User not found",
+			userID: ptrInt64(1),
+			setupMock: func(m *domainmocks.ContactsServiceMock) {
+				m.EXPECT().GetContacts(mock.Anything, int64(1)).Return(nil, domain.ErrUserNotFound).Once()
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Unauthorized",
+			setupMock:      func(m *domainmocks.ContactsServiceMock) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := domainmocks.NewContactsServiceMock(t)
+			tt.setupMock(svc)
+			handler := NewContactsHandler(svc, nil, zap.NewNop())
+
+			req := httptest.NewRequest(http.MethodGet, "/api/user/contacts", nil)
+			if tt.userID != nil {
+				req = req.WithContext(context.WithValue(req.Context(), UserIDKey, *tt.userID))
+			}
+			w := httptest.NewRecorder()
+			handler.Get(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+				assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+			}
+		})
+	}
+}
+
+func TestContactsHandler_Update(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(*domainmocks.ContactsServiceMock)
+		expectedStatus int
+	}{
+		{
+			name: "Success",
+			body: `{"email":"alice@example.com","phone":"+7 999 123-45-67"}`,
+			setupMock: func(m *domainmocks.ContactsServiceMock) {
+				m.EXPECT().UpdateContacts(mock.Anything, int64(1), domain.UserContacts{Email: "alice@example.com", Phone: "+7 999 123-45-67"}).
+					Return(&domain.UserContacts{Email: "alice@example.com", Phone: "+79991234567"}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Invalid JSON",
+			body:           `{"email":`,
+			setupMock:      func(m *domainmocks.ContactsServiceMock) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Invalid contacts",
+			body: `{"email":"not an email"}`,
+			setupMock: func(m *domainmocks.ContactsServiceMock) {
+				m.EXPECT().UpdateContacts(mock.Anything, int64(1), domain.UserContacts{Email: "not an email"}).
+					Return(nil, domain.ErrInvalidContacts).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Service error",
+			body: `{}`,
+			setupMock: func(m *domainmocks.ContactsServiceMock) {
+				m.EXPECT().UpdateContacts(mock.Anything, int64(1), domain.UserContacts{}).
+					Return(nil, errors.New("db error")).Once()
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := domainmocks.NewContactsServiceMock(t)
+			tt.setupMock(svc)
+			handler := NewContactsHandler(svc, nil, zap.NewNop())
+
+			req := httptest.NewRequest(http.MethodPut, "/api/user/contacts", strings.NewReader(tt.body))
+			req = req.WithContext(context.WithValue(req.Context(), UserIDKey, int64(1)))
+			w := httptest.NewRecorder()
+			handler.Update(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				assert.JSONEq(t, `{"email":"alice@example.com","phone":"+79991234567"}`, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestContactsHandler_Rewrap(t *testing.T) {
+	svc := domainmocks.NewContactsServiceMock(t)
+	svc.EXPECT().RewrapContacts(mock.Anything, mock.Anything).
+		RunAndReturn(func(ctx context.Context, progress jobs.Progress) (int, error) {
+			progress.SetTotal(2)
+			progress.Advance(true)
+			progress.Advance(true)
+			return 2, nil
+		}).Once()
+
+	manager := jobs.NewManager(time.Hour, zap.NewNop())
+	defer manager.Shutdown()
+	admin := NewAdminHandler(domainmocks.NewUserAdminServiceMock(t), manager, zap.NewNop())
+
+	r := chi.NewRouter()
+	r.Post("/api/admin/users/contacts/rewrap", NewContactsHandler(svc, manager, zap.NewNop()).Rewrap)
+	r.Get("/api/admin/jobs/{id}", admin.GetJob)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/admin/users/contacts/rewrap", nil))
+
+	require.Equal(t, http.StatusAccepted, w.Code)
+	var started JobResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &started))
+	assert.Equal(t, jobTypeContactsRewrap, started.Type)
+
+	job := waitJob(t, r, started.ID)
+	assert.Equal(t, string(jobs.StatusCompleted), job.Status)
+	assert.Equal(t, 2, job.Processed)
+}
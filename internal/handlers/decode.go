@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// maxJSONBodySize ограничивает тело запросов с небольшим JSON объектом
+const maxJSONBodySize = 16 << 10
+
+// errTrailingData - после JSON значения в теле запроса есть что-то еще
+var errTrailingData = errors.New("unexpected data after JSON value")
+
+// decodeJSONBody читает из тела ровно одно JSON значение
+func decodeJSONBody(body io.Reader, dst any) error {
+	dec := json.NewDecoder(body)
+	if err := dec.Decode(dst); err != nil {
+		return err
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return errTrailingData
+	}
+	return nil
+}
+
+// decodeAuthRequest разбирает тело запроса регистрации и входа
+func decodeAuthRequest(body io.Reader) (authRequest, error) {
+	var req authRequest
+	err := decodeJSONBody(body, &req)
+	return req, err
+}
+
+// decodeWithdrawRequest разбирает тело запроса списания
+func decodeWithdrawRequest(body io.Reader) (withdrawRequest, error) {
+	var req withdrawRequest
+	err := decodeJSONBody(body, &req)
+	return req, err
+}
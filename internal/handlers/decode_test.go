@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeJSONBody(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr bool
+	}{
+		{"Object", `{"login":"user","password":"secret"}`, false},
+		{"Trailing whitespace", "{\"login\":\"user\"}\n", false},
+		{"Empty body", ``, true},
+		{"Trailing object", `{"login":"a"}{"login":"b"}`, true},
+		{"Trailing garbage", `{"login":"a"}}`, true},
+		{"Wrong type", `{"login":1}`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decodeAuthRequest(strings.NewReader(tt.body))
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDecodeWithdrawRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    withdrawRequest
+		wantErr bool
+	}{
+		{"Integer sum", `{"order":"79927398713","sum":751}`, withdrawRequest{Order: "79927398713", Sum: 751}, false},
+		{"Scientific notation", `{"order":"79927398713","sum":1e10}`, withdrawRequest{Order: "79927398713", Sum: 1e10}, false},
+		{"Exponent with fraction", `{"order":"79927398713","sum":7.51E2}`, withdrawRequest{Order: "79927398713", Sum: 751}, false},
+		{"Trailing zeros", `{"order":"79927398713","sum":100.500000}`, withdrawRequest{Order: "79927398713", Sum: 100.5}, false},
+		{"Sum out of float range", `{"order":"79927398713","sum":1e400}`, withdrawRequest{}, true},
+		{"NaN literal", `{"order":"79927398713","sum":NaN}`, withdrawRequest{}, true},
+		{"Infinity literal", `{"order":"79927398713","sum":Infinity}`, withdrawRequest{}, true},
+		{"Hex sum", `{"order":"79927398713","sum":0x10}`, withdrawRequest{}, true},
+		{"Leading plus", `{"order":"79927398713","sum":+1}`, withdrawRequest{}, true},
+		{"Sum as string", `{"order":"79927398713","sum":"100"}`, withdrawRequest{}, true},
+		{"Order as number", `{"order":79927398713,"sum":100}`, withdrawRequest{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeWithdrawRequest(strings.NewReader(tt.body))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func FuzzDecodeAuthRequest(f *testing.F) {
+	for _, seed := range []string{`{"login":"user","password":"secret"}`, `{"login":"\u0000"}`, `{"login":"\ud800"}`, `null`, `{}{}`, ``} {
+		f.Add([]byte(seed))
+	}
+
+	f.Fuzz(func(t *testing.T, body []byte) {
+		req, err := decodeAuthRequest(bytes.NewReader(body))
+		if err != nil {
+			return
+		}
+
+		// Разобранный запрос переживает повторное кодирование без изменений
+		encoded, err := json.Marshal(req)
+		require.NoError(t, err)
+		again, err := decodeAuthRequest(bytes.NewReader(encoded))
+		require.NoError(t, err)
+		assert.Equal(t, req, again)
+	})
+}
+
+func FuzzDecodeWithdrawRequest(f *testing.F) {
+	for _, seed := range []string{`{"order":"79927398713","sum":751}`, `{"sum":1e10}`, `{"sum":-0}`, `{"sum":1e400}`, `{"sum":4.9e-324}`, `{"order":"٧٩٩٢٧٣٩٨٧١٣"}`} {
+		f.Add([]byte(seed))
+	}
+
+	f.Fuzz(func(t *testing.T, body []byte) {
+		req, err := decodeWithdrawRequest(bytes.NewReader(body))
+		if err != nil {
+			return
+		}
+
+		// JSON не может задать бесконечность или NaN, поэтому сумма всегда сравнима с лимитами
+		if math.IsInf(req.Sum, 0) || math.IsNaN(req.Sum) {
+			t.Fatalf("decoded non-finite sum %v from %q", req.Sum, body)
+		}
+		encoded, err := json.Marshal(req)
+		require.NoError(t, err)
+		again, err := decodeWithdrawRequest(bytes.NewReader(encoded))
+		require.NoError(t, err)
+		assert.Equal(t, req, again)
+	})
+}
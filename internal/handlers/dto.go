@@ -0,0 +1,730 @@
+package handlers
+
+import (
+	"encoding/hex"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/config"
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/avc/loyalty-system-diploma/internal/jobs"
+)
+
+// Ответы API описаны отдельно от доменных моделей: в JSON попадают только
+// перечисленные здесь поля, а доменные структуры можно менять, не затрагивая клиентов.
+
+// OrderResponse представляет заказ в ответе API
+type OrderResponse struct {
+	ID         string                `json:"id"`
+	Number     string                `json:"number"`
+	Status     string                `json:"status"`
+	Accrual    *Amount               `json:"accrual,omitempty"`
+	UploadedAt time.Time             `json:"uploaded_at"`
+	Metadata   *domain.OrderMetadata `json:"metadata,omitempty"`
+}
+
+// AdminOrderResponse представляет заказ в ответе административного API
+type AdminOrderResponse struct {
+	OrderResponse
+	Login string `json:"login"`
+}
+
+// OrderSearchResponse представляет страницу результатов поиска заказов
+type OrderSearchResponse struct {
+	Orders  []AdminOrderResponse `json:"orders"`
+	Offset  int                  `json:"offset"`
+	HasMore bool                 `json:"has_more"`
+}
+
+// AccrualMismatchResponse представляет расхождение начисления заказа с журналом транзакций
+type AccrualMismatchResponse struct {
+	Order         string             `json:"order"`
+	Login         string             `json:"login"`
+	Status        domain.OrderStatus `json:"status"`
+	OrderAccrual  *Amount            `json:"order_accrual"`
+	LedgerAccrual *Amount            `json:"ledger_accrual"`
+}
+
+// AccrualMismatchesResponse представляет страницу отчета о расхождениях начислений
+type AccrualMismatchesResponse struct {
+	Mismatches []AccrualMismatchResponse `json:"mismatches"`
+	Offset     int                       `json:"offset"`
+	HasMore    bool                      `json:"has_more"`
+}
+
+// DuplicateChannelResponse представляет повторные загрузки заказов по каналу продажи
+type DuplicateChannelResponse struct {
+	Channel     string `json:"channel"`
+	Submissions int64  `json:"submissions"`
+	Users       int    `json:"users"`
+}
+
+// DuplicateSubmitterResponse представляет повторные загрузки заказов одного пользователя
+type DuplicateSubmitterResponse struct {
+	Login       string    `json:"login"`
+	Submissions int64     `json:"submissions"`
+	LastAt      time.Time `json:"last_at"`
+}
+
+// DuplicateSubmissionsResponse представляет отчет о повторных загрузках заказов
+// со страницей пользователей
+type DuplicateSubmissionsResponse struct {
+	Since       time.Time                    `json:"since"`
+	Submissions int64                        `json:"submissions"`
+	Users       int                          `json:"users"`
+	Orders      int64                        `json:"orders"`
+	Channels    []DuplicateChannelResponse   `json:"channels"`
+	Submitters  []DuplicateSubmitterResponse `json:"submitters"`
+	Offset      int                          `json:"offset"`
+	HasMore     bool                         `json:"has_more"`
+}
+
+// ContactsResponse представляет контактные данные пользователя в ответе API
+type ContactsResponse struct {
+	Email string `json:"email"`
+	Phone string `json:"phone"`
+}
+
+// LedgerBreakResponse представляет первую запись, на которой нарушена цепочка хешей журнала
+type LedgerBreakResponse struct {
+	Seq    int64                    `json:"seq"`
+	Login  string                   `json:"login"`
+	ID     string                   `json:"id"`
+	Reason domain.LedgerBreakReason `json:"reason"`
+}
+
+// LedgerIntegrityResponse представляет результат проверки цепочки хешей журнала транзакций
+type LedgerIntegrityResponse struct {
+	Intact    bool                 `json:"intact"`
+	Checked   int64                `json:"checked"`
+	Unchained int64                `json:"unchained"`
+	Chains    int64                `json:"chains"`
+	HeadHash  string               `json:"head_hash"`
+	Break     *LedgerBreakResponse `json:"break"`
+}
+
+// AccrualCorrectionResponse представляет корректировку начисления в ответе API
+type AccrualCorrectionResponse struct {
+	Order           string    `json:"order"`
+	PreviousAccrual Amount    `json:"previous_accrual"`
+	Accrual         Amount    `json:"accrual"`
+	Adjustment      Amount    `json:"adjustment"`
+	CorrectedAt     time.Time `json:"corrected_at"`
+}
+
+// OrderTransferResponse представляет перенос заказа в ответе API
+type OrderTransferResponse struct {
+	Order         string    `json:"order"`
+	FromLogin     string    `json:"from_login"`
+	ToLogin       string    `json:"to_login"`
+	Accrual       Amount    `json:"accrual"`
+	TransferredAt time.Time `json:"transferred_at"`
+}
+
+// OrderDisputeResponse представляет спор о заказе в ответе API. Логины участников
+// видит только администратор: заявителю владелец заказа не раскрывается
+type OrderDisputeResponse struct {
+	ID                int64      `json:"id"`
+	Order             string     `json:"order"`
+	Status            string     `json:"status"`
+	Claimant          string     `json:"claimant,omitempty"`
+	Owner             string     `json:"owner,omitempty"`
+	Comment           string     `json:"comment,omitempty"`
+	ResolutionComment string     `json:"resolution_comment,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	ResolvedAt        *time.Time `json:"resolved_at,omitempty"`
+}
+
+// OrderDisputesResponse представляет страницу списка споров
+type OrderDisputesResponse struct {
+	Disputes []OrderDisputeResponse `json:"disputes"`
+	Offset   int                    `json:"offset"`
+	HasMore  bool                   `json:"has_more"`
+}
+
+// OrderStatusResponse представляет статус заказа в ответе внутреннего API
+type OrderStatusResponse struct {
+	Number     string    `json:"number"`
+	Status     string    `json:"status"`
+	Accrual    *Amount   `json:"accrual,omitempty"`
+	UploadedAt time.Time `json:"uploaded_at"`
+}
+
+// OrderStatusesResponse представляет статусы заказов в порядке запроса и ненайденные номера
+type OrderStatusesResponse struct {
+	Orders   []OrderStatusResponse `json:"orders"`
+	NotFound []string              `json:"not_found"`
+}
+
+// SettlementResponse представляет выгрузку списаний для взаиморасчетов в ответе API
+type SettlementResponse struct {
+	ID          string    `json:"id"`
+	Cutoff      time.Time `json:"cutoff"`
+	Withdrawals int       `json:"withdrawals"`
+	Total       Amount    `json:"total"`
+	Scheduled   bool      `json:"scheduled"`
+	CreatedAt   time.Time `json:"created_at"`
+	FileURL     string    `json:"file_url"`
+}
+
+// SettlementsResponse представляет страницу списка выгрузок
+type SettlementsResponse struct {
+	Settlements []SettlementResponse `json:"settlements"`
+	Offset      int                  `json:"offset"`
+	HasMore     bool                 `json:"has_more"`
+}
+
+// UserMergeResponse представляет объединение учетных записей в ответе API
+type UserMergeResponse struct {
+	Source       string     `json:"source"`
+	Target       string     `json:"target"`
+	Orders       int        `json:"orders"`
+	Transactions int        `json:"transactions"`
+	Balance      Amount     `json:"balance"`
+	DryRun       bool       `json:"dry_run"`
+	MergedAt     *time.Time `json:"merged_at,omitempty"`
+}
+
+// OrderReceiptResponse - квитанция о приеме заказа на обработку
+type OrderReceiptResponse struct {
+	Number               string `json:"number"`
+	Status               string `json:"status,omitempty"` // Пусто, если заказ был загружен раньше
+	QueuePosition        int    `json:"queue_position,omitempty"`
+	EstimatedWaitSeconds int    `json:"estimated_wait_seconds,omitempty"`
+}
+
+// WithdrawalResponse представляет списание в ответе API
+type WithdrawalResponse struct {
+	ID          string    `json:"id"`
+	Order       string    `json:"order"`
+	Sum         Amount    `json:"sum"`
+	ProcessedAt time.Time `json:"processed_at"`
+}
+
+// WithdrawalSummaryResponse представляет сводку по списаниям в ответе API
+type WithdrawalSummaryResponse struct {
+	Count           int        `json:"count"`
+	Total           Amount     `json:"total"`
+	Largest         Amount     `json:"largest"`
+	LastProcessedAt *time.Time `json:"last_processed_at,omitempty"`
+}
+
+// BalanceResponse представляет баланс в ответе API
+type BalanceResponse struct {
+	Current   Amount `json:"current"`
+	Withdrawn Amount `json:"withdrawn"`
+}
+
+// BalanceDetailsResponse представляет расширенный баланс в ответе API
+type BalanceDetailsResponse struct {
+	BalanceResponse
+	Pending PendingAccrualResponse `json:"pending"`
+}
+
+// PendingAccrualResponse представляет ожидаемые начисления в ответе API
+type PendingAccrualResponse struct {
+	Orders  int    `json:"orders"`
+	Accrual Amount `json:"accrual"`
+}
+
+// WithdrawalLimitsResponse представляет ограничения списаний пользователя в ответе API
+type WithdrawalLimitsResponse struct {
+	Login         string `json:"login"`
+	PerWithdrawal Amount `json:"per_withdrawal"`
+	Daily         Amount `json:"daily"`
+	Monthly       Amount `json:"monthly"`
+	Override      bool   `json:"override"`
+}
+
+// SubmissionBanResponse представляет запрет загрузки заказов в ответе API
+type SubmissionBanResponse struct {
+	Login       string    `json:"login"`
+	Reason      string    `json:"reason"`
+	CreatedAt   time.Time `json:"created_at"`
+	BannedUntil time.Time `json:"banned_until"`
+}
+
+// RewardRuleResponse представляет правило вознаграждения в ответе API
+type RewardRuleResponse struct {
+	Match      string    `json:"match"`
+	Reward     Amount    `json:"reward"`
+	RewardType string    `json:"reward_type"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// RewardRulesResponse представляет локальную копию правил вознаграждения.
+// Время синхронизации не выводится, пока ее не было
+type RewardRulesResponse struct {
+	Rules     []RewardRuleResponse `json:"rules"`
+	CheckedAt *time.Time           `json:"checked_at,omitempty"`
+	ChangedAt *time.Time           `json:"changed_at,omitempty"`
+}
+
+// WorkerStatusResponse представляет состояние обработки заказов на реплике
+type WorkerStatusResponse struct {
+	Paused      bool       `json:"paused"`
+	PausedSince *time.Time `json:"paused_since,omitempty"`
+}
+
+// JobResponse представляет фоновую задачу в ответе API
+type JobResponse struct {
+	ID         string     `json:"id"`
+	Type       string     `json:"type"`
+	Status     string     `json:"status"`
+	Total      int        `json:"total"`
+	Processed  int        `json:"processed"`
+	Failed     int        `json:"failed"`
+	Error      string     `json:"error,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"` // Момент удаления задачи вместе с результатом
+	ResultURL  string     `json:"result_url,omitempty"`
+}
+
+// UserExportProfileResponse представляет профиль пользователя в выгрузке его данных
+type UserExportProfileResponse struct {
+	ID        int64             `json:"id"`
+	Login     string            `json:"login"`
+	CreatedAt time.Time         `json:"created_at"`
+	Contacts  *ContactsResponse `json:"contacts,omitempty"`
+}
+
+// UserExportTransactionResponse представляет транзакцию в выгрузке данных пользователя.
+// Списания имеют отрицательную сумму
+type UserExportTransactionResponse struct {
+	ID          string    `json:"id"`
+	Order       string    `json:"order"`
+	Type        string    `json:"type"`
+	Amount      Amount    `json:"amount"`
+	ProcessedAt time.Time `json:"processed_at"`
+}
+
+// EnvelopeResponse представляет список в конверте с метаданными ответа.
+// Отдается только по запросу клиента (см. mediaTypeEnvelope)
+type EnvelopeResponse struct {
+	Data any                  `json:"data"`
+	Meta EnvelopeMetaResponse `json:"meta"`
+}
+
+// EnvelopeMetaResponse представляет метаданные ответа в конверте
+type EnvelopeMetaResponse struct {
+	RequestID  string             `json:"request_id,omitempty"`
+	Pagination PaginationResponse `json:"pagination"`
+}
+
+// PaginationResponse представляет положение страницы в списке
+type PaginationResponse struct {
+	Offset  int  `json:"offset"`
+	Count   int  `json:"count"` // Число элементов на странице
+	HasMore bool `json:"has_more"`
+	// Курсор следующей страницы для списков с постраничным чтением по курсору
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// ConfigResponse представляет действующую конфигурацию в ответе API
+type ConfigResponse struct {
+	Settings []ConfigSettingResponse `json:"settings"`
+}
+
+// ConfigSettingResponse представляет параметр конфигурации в ответе API
+type ConfigSettingResponse struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
+}
+
+// newOrderResponse преобразует заказ в ответ API
+func newOrderResponse(order *domain.Order) OrderResponse {
+	return OrderResponse{
+		ID:         order.PublicID.String(),
+		Number:     order.Number,
+		Status:     string(order.Status),
+		Accrual:    amountPtr(order.Accrual),
+		UploadedAt: order.UploadedAt,
+		Metadata:   order.Metadata,
+	}
+}
+
+// newOrdersResponse преобразует список заказов в ответ API
+func newOrdersResponse(orders []*domain.Order) []OrderResponse {
+	response := make([]OrderResponse, 0, len(orders))
+	for _, order := range orders {
+		response = append(response, newOrderResponse(order))
+	}
+	return response
+}
+
+// newOrderStatusesResponse раскладывает найденные заказы в порядке запрошенных номеров,
+// повторяющиеся номера выводятся один раз
+func newOrderStatusesResponse(numbers []string, orders []*domain.Order) OrderStatusesResponse {
+	byNumber := make(map[string]*domain.Order, len(orders))
+	for _, order := range orders {
+		byNumber[order.Number] = order
+	}
+
+	response := OrderStatusesResponse{
+		Orders:   make([]OrderStatusResponse, 0, len(orders)),
+		NotFound: []string{},
+	}
+	seen := make(map[string]bool, len(numbers))
+	for _, number := range numbers {
+		if seen[number] {
+			continue
+		}
+		seen[number] = true
+
+		order, ok := byNumber[number]
+		if !ok {
+			response.NotFound = append(response.NotFound, number)
+			continue
+		}
+		response.Orders = append(response.Orders, OrderStatusResponse{
+			Number:     order.Number,
+			Status:     string(order.Status),
+			Accrual:    amountPtr(order.Accrual),
+			UploadedAt: order.UploadedAt,
+		})
+	}
+	return response
+}
+
+// newOrderSearchResponse преобразует результаты поиска заказов в ответ API
+func newOrderSearchResponse(result *domain.OrderSearchResult, offset int) OrderSearchResponse {
+	orders := make([]AdminOrderResponse, 0, len(result.Orders))
+	for _, order := range result.Orders {
+		orders = append(orders, AdminOrderResponse{
+			OrderResponse: newOrderResponse(&order.Order),
+			Login:         order.Login,
+		})
+	}
+	return OrderSearchResponse{
+		Orders:  orders,
+		Offset:  offset,
+		HasMore: result.HasMore,
+	}
+}
+
+// newAccrualMismatchesResponse преобразует отчет о расхождениях начислений в ответ API
+func newAccrualMismatchesResponse(report *domain.AccrualMismatchReport, offset int) AccrualMismatchesResponse {
+	mismatches := make([]AccrualMismatchResponse, 0, len(report.Mismatches))
+	for _, m := range report.Mismatches {
+		mismatches = append(mismatches, AccrualMismatchResponse{
+			Order:         m.OrderNumber,
+			Login:         m.Login,
+			Status:        m.Status,
+			OrderAccrual:  amountPtr(m.OrderAccrual),
+			LedgerAccrual: amountPtr(m.LedgerAccrual),
+		})
+	}
+	return AccrualMismatchesResponse{
+		Mismatches: mismatches,
+		Offset:     offset,
+		HasMore:    report.HasMore,
+	}
+}
+
+// newDuplicateSubmissionsResponse преобразует отчет о повторных загрузках в ответ API
+func newDuplicateSubmissionsResponse(report *domain.DuplicateSubmissionReport, offset int) DuplicateSubmissionsResponse {
+	channels := make([]DuplicateChannelResponse, 0, len(report.Channels))
+	for _, c := range report.Channels {
+		channels = append(channels, DuplicateChannelResponse{
+			Channel:     c.Channel,
+			Submissions: c.Submissions,
+			Users:       c.Users,
+		})
+	}
+	submitters := make([]DuplicateSubmitterResponse, 0, len(report.Submitters))
+	for _, s := range report.Submitters {
+		submitters = append(submitters, DuplicateSubmitterResponse{
+			Login:       s.Login,
+			Submissions: s.Submissions,
+			LastAt:      s.LastAt,
+		})
+	}
+	return DuplicateSubmissionsResponse{
+		Since:       report.Since,
+		Submissions: report.Submissions,
+		Users:       report.Users,
+		Orders:      report.Orders,
+		Channels:    channels,
+		Submitters:  submitters,
+		Offset:      offset,
+		HasMore:     report.HasMore,
+	}
+}
+
+// newSettlementResponse преобразует выгрузку в ответ API
+func newSettlementResponse(settlement *domain.Settlement) SettlementResponse {
+	return SettlementResponse{
+		ID:          settlement.ID.String(),
+		Cutoff:      settlement.Cutoff,
+		Withdrawals: settlement.Withdrawals,
+		Total:       Amount(settlement.Total),
+		Scheduled:   settlement.CreatedBy == nil,
+		CreatedAt:   settlement.CreatedAt,
+		FileURL:     settlementFilePath(settlement.ID),
+	}
+}
+
+// newSettlementsResponse преобразует страницу выгрузок в ответ API
+func newSettlementsResponse(page *domain.SettlementPage, offset int) SettlementsResponse {
+	settlements := make([]SettlementResponse, 0, len(page.Settlements))
+	for _, settlement := range page.Settlements {
+		settlements = append(settlements, newSettlementResponse(settlement))
+	}
+	return SettlementsResponse{
+		Settlements: settlements,
+		Offset:      offset,
+		HasMore:     page.HasMore,
+	}
+}
+
+// newContactsResponse преобразует контактные данные в ответ API
+func newContactsResponse(contacts *domain.UserContacts) ContactsResponse {
+	return ContactsResponse{Email: contacts.Email, Phone: contacts.Phone}
+}
+
+// newUserExportProfileResponse преобразует профиль и контакты пользователя в ответ API
+func newUserExportProfileResponse(user *domain.User, contacts *domain.UserContacts) UserExportProfileResponse {
+	response := UserExportProfileResponse{
+		ID:        user.ID,
+		Login:     user.Login,
+		CreatedAt: user.CreatedAt,
+	}
+	if contacts != nil {
+		c := newContactsResponse(contacts)
+		response.Contacts = &c
+	}
+	return response
+}
+
+// newUserExportTransactionsResponse преобразует транзакции пользователя в ответ API
+func newUserExportTransactionsResponse(transactions []*domain.Transaction) []UserExportTransactionResponse {
+	response := make([]UserExportTransactionResponse, 0, len(transactions))
+	for _, tx := range transactions {
+		response = append(response, UserExportTransactionResponse{
+			ID:          tx.PublicID.String(),
+			Order:       tx.OrderNumber,
+			Type:        string(tx.Type),
+			Amount:      Amount(tx.Amount),
+			ProcessedAt: tx.ProcessedAt,
+		})
+	}
+	return response
+}
+
+// newLedgerIntegrityResponse преобразует результат проверки цепочки хешей в ответ API
+func newLedgerIntegrityResponse(result *domain.LedgerVerification) LedgerIntegrityResponse {
+	resp := LedgerIntegrityResponse{
+		Intact:    result.IsIntact(),
+		Checked:   result.Checked,
+		Unchained: result.Unchained,
+		Chains:    result.Chains,
+		HeadHash:  hex.EncodeToString(result.HeadHash),
+	}
+	if result.Break != nil {
+		resp.Break = &LedgerBreakResponse{
+			Seq:    result.Break.Seq,
+			Login:  result.Break.Login,
+			ID:     result.Break.PublicID.String(),
+			Reason: result.Break.Reason,
+		}
+	}
+	return resp
+}
+
+// newAccrualCorrectionResponse преобразует корректировку начисления в ответ API
+func newAccrualCorrectionResponse(correction *domain.AccrualCorrection) AccrualCorrectionResponse {
+	return AccrualCorrectionResponse{
+		Order:           correction.OrderNumber,
+		PreviousAccrual: Amount(correction.PreviousAmount),
+		Accrual:         Amount(correction.NewAmount),
+		Adjustment:      Amount(correction.Delta),
+		CorrectedAt:     correction.CreatedAt,
+	}
+}
+
+// newOrderTransferResponse преобразует перенос заказа в ответ API
+func newOrderTransferResponse(transfer *domain.OrderTransfer) OrderTransferResponse {
+	return OrderTransferResponse{
+		Order:         transfer.OrderNumber,
+		FromLogin:     transfer.FromLogin,
+		ToLogin:       transfer.ToLogin,
+		Accrual:       Amount(transfer.Amount),
+		TransferredAt: transfer.CreatedAt,
+	}
+}
+
+// newOrderDisputeResponse преобразует спор о заказе в ответ API
+func newOrderDisputeResponse(dispute *domain.OrderDispute) OrderDisputeResponse {
+	return OrderDisputeResponse{
+		ID:                dispute.ID,
+		Order:             dispute.OrderNumber,
+		Status:            string(dispute.Status),
+		Claimant:          dispute.ClaimantLogin,
+		Owner:             dispute.OwnerLogin,
+		Comment:           dispute.Comment,
+		ResolutionComment: dispute.ResolutionComment,
+		CreatedAt:         dispute.CreatedAt,
+		ResolvedAt:        dispute.ResolvedAt,
+	}
+}
+
+// newOrderDisputesResponse преобразует страницу споров в ответ API
+func newOrderDisputesResponse(page *domain.OrderDisputePage, offset int) OrderDisputesResponse {
+	disputes := make([]OrderDisputeResponse, 0, len(page.Disputes))
+	for _, dispute := range page.Disputes {
+		disputes = append(disputes, newOrderDisputeResponse(dispute))
+	}
+	return OrderDisputesResponse{
+		Disputes: disputes,
+		Offset:   offset,
+		HasMore:  page.HasMore,
+	}
+}
+
+// newUserMergeResponse преобразует объединение учетных записей в ответ API
+func newUserMergeResponse(merge *domain.UserMerge) UserMergeResponse {
+	response := UserMergeResponse{
+		Source:       merge.SourceLogin,
+		Target:       merge.TargetLogin,
+		Orders:       merge.Orders,
+		Transactions: merge.Transactions,
+		Balance:      Amount(merge.Balance),
+		DryRun:       merge.DryRun,
+	}
+	if !merge.DryRun {
+		response.MergedAt = &merge.CreatedAt
+	}
+	return response
+}
+
+// newWithdrawalsResponse преобразует транзакции списания в ответ API
+func newWithdrawalsResponse(withdrawals []*domain.Transaction) []WithdrawalResponse {
+	response := make([]WithdrawalResponse, 0, len(withdrawals))
+	for _, tx := range withdrawals {
+		response = append(response, newWithdrawalResponse(tx))
+	}
+	return response
+}
+
+// newWithdrawalResponse преобразует транзакцию списания в ответ API
+func newWithdrawalResponse(tx *domain.Transaction) WithdrawalResponse {
+	return WithdrawalResponse{
+		ID:          tx.PublicID.String(),
+		Order:       tx.OrderNumber,
+		Sum:         Amount(tx.Amount),
+		ProcessedAt: tx.ProcessedAt,
+	}
+}
+
+// newWithdrawalSummaryResponse преобразует сводку по списаниям в ответ API
+func newWithdrawalSummaryResponse(summary *domain.WithdrawalSummary) WithdrawalSummaryResponse {
+	return WithdrawalSummaryResponse{
+		Count:           summary.Count,
+		Total:           Amount(summary.Total),
+		Largest:         Amount(summary.Largest),
+		LastProcessedAt: summary.LastAt,
+	}
+}
+
+// newBalanceResponse преобразует баланс в ответ API
+func newBalanceResponse(balance *domain.Balance) BalanceResponse {
+	return BalanceResponse{
+		Current:   Amount(balance.Current),
+		Withdrawn: Amount(balance.Withdrawn),
+	}
+}
+
+// newBalanceDetailsResponse преобразует расширенный баланс в ответ API
+func newBalanceDetailsResponse(details *domain.BalanceDetails) BalanceDetailsResponse {
+	return BalanceDetailsResponse{
+		BalanceResponse: newBalanceResponse(&details.Balance),
+		Pending: PendingAccrualResponse{
+			Orders:  details.Pending.Orders,
+			Accrual: Amount(details.Pending.Accrual),
+		},
+	}
+}
+
+// newWithdrawalLimitsResponse преобразует ограничения списаний в ответ API
+func newWithdrawalLimitsResponse(limits *domain.UserWithdrawalLimits) WithdrawalLimitsResponse {
+	return WithdrawalLimitsResponse{
+		Login:         limits.Login,
+		PerWithdrawal: Amount(limits.Limits.PerWithdrawal),
+		Daily:         Amount(limits.Limits.Daily),
+		Monthly:       Amount(limits.Limits.Monthly),
+		Override:      limits.Override,
+	}
+}
+
+// newSubmissionBansResponse преобразует запреты загрузки заказов в ответ API
+func newSubmissionBansResponse(bans []*domain.SubmissionBan) []SubmissionBanResponse {
+	response := make([]SubmissionBanResponse, 0, len(bans))
+	for _, ban := range bans {
+		response = append(response, SubmissionBanResponse{
+			Login:       ban.Login,
+			Reason:      ban.Reason,
+			CreatedAt:   ban.CreatedAt,
+			BannedUntil: ban.BannedUntil,
+		})
+	}
+	return response
+}
+
+// newRewardRulesResponse преобразует локальную копию правил вознаграждения в ответ API
+func newRewardRulesResponse(set *domain.RewardRuleSet) RewardRulesResponse {
+	response := RewardRulesResponse{Rules: make([]RewardRuleResponse, 0, len(set.Rules))}
+	for _, rule := range set.Rules {
+		response.Rules = append(response.Rules, RewardRuleResponse{
+			Match:      rule.Match,
+			Reward:     Amount(rule.Reward),
+			RewardType: string(rule.RewardType),
+			UpdatedAt:  rule.UpdatedAt,
+		})
+	}
+	if !set.CheckedAt.IsZero() {
+		response.CheckedAt = &set.CheckedAt
+		response.ChangedAt = &set.ChangedAt
+	}
+	return response
+}
+
+// newConfigResponse преобразует параметры конфигурации в ответ API
+func newConfigResponse(settings []config.Setting) ConfigResponse {
+	response := ConfigResponse{Settings: make([]ConfigSettingResponse, 0, len(settings))}
+	for _, setting := range settings {
+		response.Settings = append(response.Settings, ConfigSettingResponse{
+			Name:   setting.Name,
+			Value:  setting.Value,
+			Source: string(setting.Source),
+		})
+	}
+	return response
+}
+
+// newJobResponse преобразует состояние задачи в ответ API.
+// Ссылка на результат есть только у успешно завершенной задачи.
+func newJobResponse(snapshot jobs.Snapshot) JobResponse {
+	response := JobResponse{
+		ID:        snapshot.ID,
+		Type:      snapshot.Type,
+		Status:    string(snapshot.Status),
+		Total:     snapshot.Total,
+		Processed: snapshot.Processed,
+		Failed:    snapshot.Failed,
+		Error:     snapshot.Error,
+		CreatedAt: snapshot.CreatedAt,
+	}
+	if !snapshot.FinishedAt.IsZero() {
+		finishedAt := snapshot.FinishedAt
+		response.FinishedAt = &finishedAt
+	}
+	if !snapshot.ExpiresAt.IsZero() {
+		expiresAt := snapshot.ExpiresAt
+		response.ExpiresAt = &expiresAt
+	}
+	if snapshot.Status == jobs.StatusCompleted {
+		response.ResultURL = jobPath(snapshot.ID) + "/result"
+	}
+	return response
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/avc/loyalty-system-diploma/internal/jobs"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewOrdersResponse(t *testing.T) {
+	accrual := 500.0
+	uploadedAt := time.Date(2020, 12, 10, 15, 15, 45, 0, time.FixedZone("MSK", 3*60*60))
+	orders := []*domain.Order{
+		{ID: 1, PublicID: uuid.MustParse("01890a5d-ac96-774b-bcce-b302099a8057"), UserID: 7, Number: "9278923470", Status: domain.OrderStatusProcessed, Accrual: &accrual, UploadedAt: uploadedAt},
+		{ID: 2, PublicID: uuid.MustParse("01890a5d-ac96-774b-bcce-b302099a8058"), UserID: 7, Number: "346436439", Status: domain.OrderStatusInvalid, UploadedAt: uploadedAt, Metadata: &domain.OrderMetadata{Channel: "pos", StoreID: "42"}},
+	}
+
+	body, err := json.Marshal(newOrdersResponse(orders))
+	require.NoError(t, err)
+
+	// Внутренние поля (числовой id, user_id) не попадают в ответ, вместо id - публичный UUID
+	assert.JSONEq(t, `[
+		{"id":"01890a5d-ac96-774b-bcce-b302099a8057","number":"9278923470","status":"PROCESSED","accrual":500,"uploaded_at":"2020-12-10T15:15:45+03:00"},
+		{"id":"01890a5d-ac96-774b-bcce-b302099a8058","number":"346436439","status":"INVALID","uploaded_at":"2020-12-10T15:15:45+03:00","metadata":{"channel":"pos","store_id":"42"}}
+	]`, string(body))
+}
+
+func TestNewWithdrawalsResponse(t *testing.T) {
+	processedAt := time.Date(2020, 12, 9, 16, 9, 57, 0, time.FixedZone("MSK", 3*60*60))
+	withdrawals := []*domain.Transaction{
+		{ID: 1, PublicID: uuid.MustParse("01890a5d-ac96-774b-bcce-b302099a8059"), UserID: 7, OrderNumber: "2377225624", Amount: 500, Type: domain.TransactionTypeWithdrawal, ProcessedAt: processedAt},
+	}
+
+	body, err := json.Marshal(newWithdrawalsResponse(withdrawals))
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `[{"id":"01890a5d-ac96-774b-bcce-b302099a8059","order":"2377225624","sum":500,"processed_at":"2020-12-09T16:09:57+03:00"}]`, string(body))
+}
+
+func TestNewBalanceResponse(t *testing.T) {
+	body, err := json.Marshal(newBalanceResponse(&domain.Balance{Current: 500.5, Withdrawn: 42}))
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"current":500.5,"withdrawn":42}`, string(body))
+}
+
+func TestNewBalanceDetailsResponse(t *testing.T) {
+	details := &domain.BalanceDetails{
+		Balance: domain.Balance{Current: 500.5, Withdrawn: 42},
+		Pending: domain.PendingAccrual{Orders: 2, Accrual: 10.25},
+	}
+	body, err := json.Marshal(newBalanceDetailsResponse(details))
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"current":500.5,"withdrawn":42,"pending":{"orders":2,"accrual":10.25}}`, string(body))
+}
+
+func TestNewOrdersResponse_Empty(t *testing.T) {
+	body, err := json.Marshal(newOrdersResponse(nil))
+	require.NoError(t, err)
+
+	assert.Equal(t, "[]", string(body))
+}
+
+func TestNewJobResponse(t *testing.T) {
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	running := newJobResponse(jobs.Snapshot{ID: "42", Type: "users_export", Status: jobs.StatusRunning, Total: 10, Processed: 3, CreatedAt: createdAt})
+	body, err := json.Marshal(running)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":"42","type":"users_export","status":"running","total":10,"processed":3,"failed":0,"created_at":"2024-01-02T03:04:05Z"}`, string(body))
+
+	completed := newJobResponse(jobs.Snapshot{ID: "42", Status: jobs.StatusCompleted, CreatedAt: createdAt, FinishedAt: createdAt.Add(time.Minute)})
+	require.NotNil(t, completed.FinishedAt)
+	assert.Equal(t, "/api/admin/jobs/42/result", completed.ResultURL)
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+// mediaTypeEnvelope - список в конверте {"data": [...], "meta": {...}}. Клиенты запрашивают
+// его в Accept, остальные получают прежний ответ без изменений
+const mediaTypeEnvelope = "application/vnd.gophermart.envelope+json"
+
+// prefersEnvelope сообщает, запросил ли клиент конверт явно и не предпочел ли ему JSON
+func prefersEnvelope(accept string) bool {
+	envelopeQ := acceptQuality(accept, mediaTypeEnvelope)
+	return envelopeQ > 0 && envelopeQ >= acceptQuality(accept, "application/json")
+}
+
+// writeEnvelope отвечает страницей списка в конверте. Пустой список в конверте - это 200
+// с пустым data, а не 204: метаданные нужны клиенту и тогда
+func writeEnvelope[T any](w http.ResponseWriter, r *http.Request, data []T, offset int, hasMore bool, logger *zap.Logger) {
+	writeEnvelopePage(w, r, data, PaginationResponse{Offset: offset, HasMore: hasMore}, logger)
+}
+
+// writeEnvelopePage отвечает страницей списка в конверте с заданной пагинацией,
+// число элементов на странице заполняется по data
+func writeEnvelopePage[T any](w http.ResponseWriter, r *http.Request, data []T, pagination PaginationResponse, logger *zap.Logger) {
+	requestID, _ := r.Context().Value(RequestIDKey).(string)
+	pagination.Count = len(data)
+	response := EnvelopeResponse{
+		Data: data,
+		Meta: EnvelopeMetaResponse{
+			RequestID:  requestID,
+			Pagination: pagination,
+		},
+	}
+
+	w.Header().Set("Content-Type", mediaTypeEnvelope)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Error("failed to encode envelope response", zap.Error(err))
+	}
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	domainmocks "github.com/avc/loyalty-system-diploma/internal/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+// newEnvelopeRequest создает запрос пользователя 1 с идентификатором запроса и заголовком Accept
+func newEnvelopeRequest(target, accept string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	req.Header.Set("Accept", accept)
+	ctx := context.WithValue(req.Context(), UserIDKey, int64(1))
+	return req.WithContext(context.WithValue(ctx, RequestIDKey, "req-1"))
+}
+
+func TestPrefersEnvelope(t *testing.T) {
+	assert.True(t, prefersEnvelope(mediaTypeEnvelope))
+	assert.True(t, prefersEnvelope("application/json;q=0.5, application/vnd.gophermart.envelope+json"))
+	assert.False(t, prefersEnvelope(""))
+	assert.False(t, prefersEnvelope("*/*"))
+	assert.False(t, prefersEnvelope("application/json, application/vnd.gophermart.envelope+json;q=0.9"))
+}
+
+func TestOrdersHandler_GetOrders_Envelope(t *testing.T) {
+	service := domainmocks.NewOrderServiceMock(t)
+	handler := NewOrdersHandler(service, nil, zap.NewNop())
+
+	service.EXPECT().GetOrders(mock.Anything, int64(1), domain.OrderFilter{}).
+		Return(&domain.OrderPage{Orders: []*domain.Order{{Number: "111", Status: domain.OrderStatusNew}}}, nil).Once()
+	w := httptest.NewRecorder()
+	handler.GetOrders(w, newEnvelopeRequest("/api/user/orders", mediaTypeEnvelope))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, mediaTypeEnvelope, w.Header().Get("Content-Type"))
+	assert.Equal(t, "Accept", w.Header().Get("Vary"))
+	assert.JSONEq(t, `{"data":[{"id":"00000000-0000-0000-0000-000000000000","number":"111","status":"NEW","uploaded_at":"0001-01-01T00:00:00Z"}],
+		"meta":{"request_id":"req-1","pagination":{"offset":0,"count":1,"has_more":false}}}`, w.Body.String())
+
+	// Пустой список в конверте - 200 с метаданными, а не 204
+	service.EXPECT().GetOrders(mock.Anything, int64(1), domain.OrderFilter{}).Return(&domain.OrderPage{}, nil).Once()
+	w = httptest.NewRecorder()
+	handler.GetOrders(w, newEnvelopeRequest("/api/user/orders", mediaTypeEnvelope))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"data":[],"meta":{"request_id":"req-1","pagination":{"offset":0,"count":0,"has_more":false}}}`, w.Body.String())
+}
+
+func TestBalanceHandler_GetWithdrawals_Envelope(t *testing.T) {
+	service := domainmocks.NewBalanceServiceMock(t)
+	handler := NewBalanceHandler(service, zap.NewNop())
+
+	service.EXPECT().GetWithdrawals(mock.Anything, int64(1), (*domain.WithdrawalCursor)(nil), 0).Return(&domain.WithdrawalPage{}, nil).Once()
+	w := httptest.NewRecorder()
+	handler.GetWithdrawals(w, newEnvelopeRequest("/api/user/withdrawals", mediaTypeEnvelope))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"data":[],"meta":{"request_id":"req-1","pagination":{"offset":0,"count":0,"has_more":false}}}`, w.Body.String())
+
+	// Без запроса конверта ответ прежний
+	service.EXPECT().GetWithdrawals(mock.Anything, int64(1), (*domain.WithdrawalCursor)(nil), 0).Return(&domain.WithdrawalPage{}, nil).Once()
+	w = httptest.NewRecorder()
+	handler.GetWithdrawals(w, newEnvelopeRequest("/api/user/withdrawals", "application/json"))
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestAdminOrdersHandler_Search_Envelope(t *testing.T) {
+	service := domainmocks.NewOrderSearchServiceMock(t)
+	handler := NewAdminOrdersHandler(service, zap.NewNop())
+
+	service.EXPECT().SearchOrders(mock.Anything, domain.OrderSearchFilter{Limit: 1, Offset: 2}).Return(&domain.OrderSearchResult{
+		Orders:  []*domain.AdminOrder{{Order: domain.Order{Number: "9278923470", Status: domain.OrderStatusNew}, Login: "alice"}},
+		HasMore: true,
+	}, nil).Once()
+	w := httptest.NewRecorder()
+	handler.Search(w, newEnvelopeRequest("/api/admin/orders?limit=1&offset=2", mediaTypeEnvelope))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"data":[{"id":"00000000-0000-0000-0000-000000000000","number":"9278923470","status":"NEW","uploaded_at":"0001-01-01T00:00:00Z","login":"alice"}],
+		"meta":{"request_id":"req-1","pagination":{"offset":2,"count":1,"has_more":true}}}`, w.Body.String())
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/avc/loyalty-system-diploma/internal/locale"
+)
+
+// ErrorResponse представляет ошибку в ответе API
+type ErrorResponse struct {
+	Error string `json:"error"`
+	Code  string `json:"code,omitempty"` // Машиночитаемый код для ошибок, которые клиент различает
+}
+
+// writeJSONError отвечает ошибкой в формате ErrorResponse
+func writeJSONError(w http.ResponseWriter, r *http.Request, status int, message string) {
+	writeErrorResponse(w, r, status, ErrorResponse{Error: message})
+}
+
+// writeErrorResponse отвечает ошибкой с кодом. Сообщение переводится на язык запроса,
+// машиночитаемый код остается неизменным
+func writeErrorResponse(w http.ResponseWriter, r *http.Request, status int, response ErrorResponse) {
+	response.Error = locale.From(r.Context()).Translate(response.Error)
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+// storageRetryAfter подсказывает клиенту, когда повторить запрос при недоступной БД
+const storageRetryAfter = 5 // секунд
+
+// writeInternalError отвечает 503 с Retry-After, если хранилище временно недоступно,
+// и 500 в остальных случаях
+func writeInternalError(w http.ResponseWriter, err error) {
+	if errors.Is(err, domain.ErrStorageUnavailable) {
+		w.Header().Set("Retry-After", strconv.Itoa(storageRetryAfter))
+		http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		return
+	}
+	http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+}
+
+// writeSubmissionLimitError отвечает 429 при превышении частоты загрузки заказов
+// и 403 при запрете загрузки. Retry-After - сколько секунд осталось до снятия ограничения
+func writeSubmissionLimitError(w http.ResponseWriter, r *http.Request, limitErr *domain.SubmissionLimitError) {
+	retryAfter := int(math.Ceil(time.Until(limitErr.Until).Seconds()))
+	w.Header().Set("Retry-After", strconv.Itoa(max(retryAfter, 1)))
+
+	if errors.Is(limitErr, domain.ErrSubmissionsBanned) {
+		writeErrorResponse(w, r, http.StatusForbidden, ErrorResponse{
+			Error: "order submissions are temporarily banned",
+			Code:  "submissions_banned",
+		})
+		return
+	}
+	writeErrorResponse(w, r, http.StatusTooManyRequests, ErrorResponse{
+		Error: "too many order submissions",
+		Code:  "submission_rate_limit",
+	})
+}
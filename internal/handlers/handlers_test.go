@@ -3,17 +3,23 @@ package handlers
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/avc/loyalty-system-diploma/internal/domain"
 	domainmocks "github.com/avc/loyalty-system-diploma/internal/domain/mocks"
-	"github.com/avc/loyalty-system-diploma/internal/service"
-	"github.com/avc/loyalty-system-diploma/internal/utils/jwt"
+	"github.com/avc/loyalty-system-diploma/internal/locale"
+	"github.com/avc/loyalty-system-diploma/internal/metrics"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -32,7 +38,7 @@ func TestAuthHandler_Register(t *testing.T) {
 			name: "Success",
 			body: `{"login":"user","password":"pass"}`,
 			setupMock: func(m *domainmocks.AuthServiceMock) {
-				m.EXPECT().Register(mock.Anything, "user", "pass").Return("token", nil).Once()
+				m.EXPECT().Register(mock.Anything, "user", "pass", "phone").Return(&domain.AuthTokens{AccessToken: "token", RefreshToken: "refresh"}, nil).Once()
 			},
 			expectedStatus: http.StatusOK,
 			checkAuth:      true,
@@ -41,7 +47,7 @@ func TestAuthHandler_Register(t *testing.T) {
 			name: "User exists",
 			body: `{"login":"user","password":"pass"}`,
 			setupMock: func(m *domainmocks.AuthServiceMock) {
-				m.EXPECT().Register(mock.Anything, "user", "pass").Return("", service.ErrUserExists).Once()
+				m.EXPECT().Register(mock.Anything, "user", "pass", "phone").Return(nil, domain.ErrUserExists).Once()
 			},
 			expectedStatus: http.StatusConflict,
 		},
@@ -49,7 +55,7 @@ func TestAuthHandler_Register(t *testing.T) {
 			name: "Invalid input",
 			body: `{"login":"user","password":"pass"}`,
 			setupMock: func(m *domainmocks.AuthServiceMock) {
-				m.EXPECT().Register(mock.Anything, "user", "pass").Return("", service.ErrInvalidInput).Once()
+				m.EXPECT().Register(mock.Anything, "user", "pass", "phone").Return(nil, domain.ErrInvalidInput).Once()
 			},
 			expectedStatus: http.StatusBadRequest,
 		},
@@ -63,7 +69,7 @@ func TestAuthHandler_Register(t *testing.T) {
 			name: "Internal error",
 			body: `{"login":"user","password":"pass"}`,
 			setupMock: func(m *domainmocks.AuthServiceMock) {
-				m.EXPECT().Register(mock.Anything, "user", "pass").Return("", errors.New("db error")).Once()
+				m.EXPECT().Register(mock.Anything, "user", "pass", "phone").Return(nil, errors.New("db error")).Once()
 			},
 			expectedStatus: http.StatusInternalServerError,
 		},
@@ -78,6 +84,7 @@ func TestAuthHandler_Register(t *testing.T) {
 			tt.setupMock(mockService)
 
 			req := httptest.NewRequest(http.MethodPost, "/api/user/register", bytes.NewBufferString(tt.body))
+			req.Header.Set("X-Device-ID", "phone")
 			w := httptest.NewRecorder()
 
 			handler.Register(w, req)
@@ -85,6 +92,7 @@ func TestAuthHandler_Register(t *testing.T) {
 			assert.Equal(t, tt.expectedStatus, w.Code)
 			if tt.checkAuth {
 				assert.Contains(t, w.Header().Get("Authorization"), "Bearer token")
+				assert.Equal(t, "refresh", w.Header().Get("X-Refresh-Token"))
 			}
 		})
 	}
@@ -102,7 +110,16 @@ func TestAuthHandler_Login(t *testing.T) {
 			name: "Success",
 			body: `{"login":"user","password":"pass"}`,
 			setupMock: func(m *domainmocks.AuthServiceMock) {
-				m.EXPECT().Login(mock.Anything, "user", "pass").Return("token", nil).Once()
+				m.EXPECT().Login(mock.Anything, "user", "pass", "phone", false).Return(&domain.AuthTokens{AccessToken: "token", RefreshToken: "refresh"}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			checkAuth:      true,
+		},
+		{
+			name: "Remember",
+			body: `{"login":"user","password":"pass","remember":true}`,
+			setupMock: func(m *domainmocks.AuthServiceMock) {
+				m.EXPECT().Login(mock.Anything, "user", "pass", "phone", true).Return(&domain.AuthTokens{AccessToken: "token", RefreshToken: "refresh"}, nil).Once()
 			},
 			expectedStatus: http.StatusOK,
 			checkAuth:      true,
@@ -111,7 +128,7 @@ func TestAuthHandler_Login(t *testing.T) {
 			name: "Invalid credentials",
 			body: `{"login":"user","password":"wrong"}`,
 			setupMock: func(m *domainmocks.AuthServiceMock) {
-				m.EXPECT().Login(mock.Anything, "user", "wrong").Return("", service.ErrInvalidCredentials).Once()
+				m.EXPECT().Login(mock.Anything, "user", "wrong", "phone", false).Return(nil, domain.ErrInvalidCredentials).Once()
 			},
 			expectedStatus: http.StatusUnauthorized,
 		},
@@ -119,7 +136,7 @@ func TestAuthHandler_Login(t *testing.T) {
 			name: "Invalid input",
 			body: `{"login":"","password":"pass"}`,
 			setupMock: func(m *domainmocks.AuthServiceMock) {
-				m.EXPECT().Login(mock.Anything, "", "pass").Return("", service.ErrInvalidInput).Once()
+				m.EXPECT().Login(mock.Anything, "", "pass", "phone", false).Return(nil, domain.ErrInvalidInput).Once()
 			},
 			expectedStatus: http.StatusBadRequest,
 		},
@@ -140,6 +157,7 @@ func TestAuthHandler_Login(t *testing.T) {
 			tt.setupMock(mockService)
 
 			req := httptest.NewRequest(http.MethodPost, "/api/user/login", bytes.NewBufferString(tt.body))
+			req.Header.Set("X-Device-ID", "phone")
 			w := httptest.NewRecorder()
 
 			handler.Login(w, req)
@@ -152,6 +170,202 @@ func TestAuthHandler_Login(t *testing.T) {
 	}
 }
 
+func TestAuthHandler_Refresh(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		device         string
+		setupMock      func(*domainmocks.AuthServiceMock)
+		expectedStatus int
+		checkAuth      bool
+	}{
+		{
+			name:   "Success",
+			body:   `{"refresh_token":"old"}`,
+			device: "phone",
+			setupMock: func(m *domainmocks.AuthServiceMock) {
+				m.EXPECT().Refresh(mock.Anything, "old", "phone").Return(&domain.AuthTokens{AccessToken: "token", RefreshToken: "new"}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			checkAuth:      true,
+		},
+		{
+			name: "Without device ID",
+			body: `{"refresh_token":"old"}`,
+			setupMock: func(m *domainmocks.AuthServiceMock) {
+				m.EXPECT().Refresh(mock.Anything, "old", "").Return(&domain.AuthTokens{AccessToken: "token", RefreshToken: "new"}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			checkAuth:      true,
+		},
+		{
+			name: "Without device ID for token bound to device",
+			body: `{"refresh_token":"old"}`,
+			setupMock: func(m *domainmocks.AuthServiceMock) {
+				m.EXPECT().Refresh(mock.Anything, "old", "").Return(nil, fmt.Errorf("%w: %w", domain.ErrInvalidToken, domain.ErrRefreshTokenDevice)).Once()
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "Revoked token",
+			body:   `{"refresh_token":"old"}`,
+			device: "phone",
+			setupMock: func(m *domainmocks.AuthServiceMock) {
+				m.EXPECT().Refresh(mock.Anything, "old", "phone").Return(nil, domain.ErrInvalidToken).Once()
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Missing token",
+			body:           `{}`,
+			device:         "phone",
+			setupMock:      func(m *domainmocks.AuthServiceMock) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Device ID too long",
+			body:           `{"refresh_token":"old"}`,
+			device:         strings.Repeat("x", 129),
+			setupMock:      func(m *domainmocks.AuthServiceMock) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Internal error",
+			body:   `{"refresh_token":"old"}`,
+			device: "phone",
+			setupMock: func(m *domainmocks.AuthServiceMock) {
+				m.EXPECT().Refresh(mock.Anything, "old", "phone").Return(nil, errors.New("db error")).Once()
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := domainmocks.NewAuthServiceMock(t)
+			handler := NewAuthHandler(mockService, zap.NewNop())
+
+			tt.setupMock(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/user/refresh", bytes.NewBufferString(tt.body))
+			if tt.device != "" {
+				req.Header.Set("X-Device-ID", tt.device)
+			}
+			w := httptest.NewRecorder()
+
+			handler.Refresh(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.checkAuth {
+				assert.Equal(t, "Bearer token", w.Header().Get("Authorization"))
+				assert.Equal(t, "new", w.Header().Get("X-Refresh-Token"))
+			}
+		})
+	}
+}
+
+func TestAuthHandler_Logout(t *testing.T) {
+	tests := []struct {
+		name           string
+		authHeader     string
+		setupMock      func(*domainmocks.AuthServiceMock)
+		expectedStatus int
+	}{
+		{
+			name:       "Success",
+			authHeader: "Bearer token",
+			setupMock: func(m *domainmocks.AuthServiceMock) {
+				m.EXPECT().Logout(mock.Anything, "token").Return(nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:       "Invalid token",
+			authHeader: "Bearer token",
+			setupMock: func(m *domainmocks.AuthServiceMock) {
+				m.EXPECT().Logout(mock.Anything, "token").Return(domain.ErrInvalidToken).Once()
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Missing token",
+			setupMock:      func(m *domainmocks.AuthServiceMock) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "Internal error",
+			authHeader: "Bearer token",
+			setupMock: func(m *domainmocks.AuthServiceMock) {
+				m.EXPECT().Logout(mock.Anything, "token").Return(errors.New("db error")).Once()
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := domainmocks.NewAuthServiceMock(t)
+			handler := NewAuthHandler(mockService, zap.NewNop())
+
+			tt.setupMock(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/user/logout", nil)
+			if tt.authHeader != "" {
+				req.Header.Set("Authorization", tt.authHeader)
+			}
+			w := httptest.NewRecorder()
+
+			handler.Logout(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestAuthHandler_Availability(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		setupMock      func(*domainmocks.AuthServiceMock)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:  "Valid login",
+			query: "?login=user",
+			setupMock: func(m *domainmocks.AuthServiceMock) {
+				m.EXPECT().CheckLogin(mock.Anything, "user").Return(nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"login":"user","status":"may_be_available"}`,
+		},
+		{
+			name:  "Invalid login",
+			query: "",
+			setupMock: func(m *domainmocks.AuthServiceMock) {
+				m.EXPECT().CheckLogin(mock.Anything, "").Return(domain.ErrInvalidInput).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"login must be from 1 to 255 bytes"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := domainmocks.NewAuthServiceMock(t)
+			handler := NewAuthHandler(mockService, zap.NewNop())
+			tt.setupMock(mockService)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/user/availability"+tt.query, nil)
+			w := httptest.NewRecorder()
+			handler.Availability(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
 func TestOrdersHandler_SubmitOrder(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -165,7 +379,7 @@ func TestOrdersHandler_SubmitOrder(t *testing.T) {
 			body:   "79927398713",
 			userID: ptrInt64(1),
 			setupMock: func(m *domainmocks.OrderServiceMock) {
-				m.EXPECT().SubmitOrder(mock.Anything, int64(1), "79927398713").Return(nil).Once()
+				m.EXPECT().SubmitOrder(mock.Anything, int64(1), "79927398713", (*domain.OrderMetadata)(nil)).Return(nil).Once()
 			},
 			expectedStatus: http.StatusAccepted,
 		},
@@ -174,7 +388,7 @@ func TestOrdersHandler_SubmitOrder(t *testing.T) {
 			body:   "79927398713",
 			userID: ptrInt64(1),
 			setupMock: func(m *domainmocks.OrderServiceMock) {
-				m.EXPECT().SubmitOrder(mock.Anything, int64(1), "79927398713").Return(service.ErrOrderExists).Once()
+				m.EXPECT().SubmitOrder(mock.Anything, int64(1), "79927398713", (*domain.OrderMetadata)(nil)).Return(domain.ErrOrderExists).Once()
 			},
 			expectedStatus: http.StatusOK,
 		},
@@ -183,16 +397,36 @@ func TestOrdersHandler_SubmitOrder(t *testing.T) {
 			body:   "79927398713",
 			userID: ptrInt64(1),
 			setupMock: func(m *domainmocks.OrderServiceMock) {
-				m.EXPECT().SubmitOrder(mock.Anything, int64(1), "79927398713").Return(service.ErrOrderOwnedByAnother).Once()
+				m.EXPECT().SubmitOrder(mock.Anything, int64(1), "79927398713", (*domain.OrderMetadata)(nil)).Return(domain.ErrOrderOwnedByAnother).Once()
 			},
 			expectedStatus: http.StatusConflict,
 		},
+		{
+			name:   "Submission rate limit",
+			body:   "79927398713",
+			userID: ptrInt64(1),
+			setupMock: func(m *domainmocks.OrderServiceMock) {
+				err := &domain.SubmissionLimitError{Err: domain.ErrTooManySubmissions, Until: time.Now().Add(time.Minute)}
+				m.EXPECT().SubmitOrder(mock.Anything, int64(1), "79927398713", (*domain.OrderMetadata)(nil)).Return(fmt.Errorf("wrapped: %w", err)).Once()
+			},
+			expectedStatus: http.StatusTooManyRequests,
+		},
+		{
+			name:   "Submissions banned",
+			body:   "79927398713",
+			userID: ptrInt64(1),
+			setupMock: func(m *domainmocks.OrderServiceMock) {
+				err := &domain.SubmissionLimitError{Err: domain.ErrSubmissionsBanned, Until: time.Now().Add(time.Hour)}
+				m.EXPECT().SubmitOrder(mock.Anything, int64(1), "79927398713", (*domain.OrderMetadata)(nil)).Return(err).Once()
+			},
+			expectedStatus: http.StatusForbidden,
+		},
 		{
 			name:   "Invalid order number",
 			body:   "12345",
 			userID: ptrInt64(1),
 			setupMock: func(m *domainmocks.OrderServiceMock) {
-				m.EXPECT().SubmitOrder(mock.Anything, int64(1), "12345").Return(service.ErrInvalidOrderNumber).Once()
+				m.EXPECT().SubmitOrder(mock.Anything, int64(1), "12345", (*domain.OrderMetadata)(nil)).Return(domain.ErrInvalidOrderNumber).Once()
 			},
 			expectedStatus: http.StatusUnprocessableEntity,
 		},
@@ -216,55 +450,465 @@ func TestOrdersHandler_SubmitOrder(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockService := domainmocks.NewOrderServiceMock(t)
 			logger, _ := zap.NewDevelopment()
-			handler := NewOrdersHandler(mockService, logger)
+			handler := NewOrdersHandler(mockService, nil, logger)
+
+			tt.setupMock(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/user/orders", bytes.NewBufferString(tt.body))
+			if tt.userID != nil {
+				ctx := context.WithValue(req.Context(), UserIDKey, *tt.userID)
+				req = req.WithContext(ctx)
+			}
+			w := httptest.NewRecorder()
+
+			handler.SubmitOrder(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestOrdersHandler_SubmitOrder_Backlog(t *testing.T) {
+	for _, overloaded := range []bool{false, true} {
+		t.Run(fmt.Sprintf("overloaded=%t", overloaded), func(t *testing.T) {
+			mockService := domainmocks.NewOrderServiceMock(t)
+			backlog := domainmocks.NewBacklogMonitorMock(t)
+			handler := NewOrdersHandler(mockService, backlog, zap.NewNop())
+
+			mockService.EXPECT().SubmitOrder(mock.Anything, int64(1), "79927398713", (*domain.OrderMetadata)(nil)).Return(nil).Once()
+			backlog.EXPECT().Overloaded().Return(overloaded).Once()
+
+			req := httptest.NewRequest(http.MethodPost, "/api/user/orders", bytes.NewBufferString("79927398713"))
+			req = req.WithContext(context.WithValue(req.Context(), UserIDKey, int64(1)))
+			w := httptest.NewRecorder()
+
+			handler.SubmitOrder(w, req)
+
+			assert.Equal(t, http.StatusAccepted, w.Code)
+			if overloaded {
+				assert.Equal(t, "true", w.Header().Get(processingDelayedHeader))
+			} else {
+				assert.Empty(t, w.Header().Get(processingDelayedHeader))
+			}
+		})
+	}
+}
+
+func TestOrdersHandler_SubmitOrder_Receipt(t *testing.T) {
+	submit := func(t *testing.T, backlog BacklogMonitor, accept string) *httptest.ResponseRecorder {
+		mockService := domainmocks.NewOrderServiceMock(t)
+		mockService.EXPECT().SubmitOrder(mock.Anything, int64(1), "79927398713", (*domain.OrderMetadata)(nil)).Return(nil).Once()
+		handler := NewOrdersHandler(mockService, backlog, zap.NewNop())
+
+		req := httptest.NewRequest(http.MethodPost, "/api/user/orders", bytes.NewBufferString("79927398713"))
+		req.Header.Set("Accept", accept)
+		req = req.WithContext(context.WithValue(req.Context(), UserIDKey, int64(1)))
+		w := httptest.NewRecorder()
+		handler.SubmitOrder(w, req)
+		return w
+	}
+
+	t.Run("JSON receipt with estimate", func(t *testing.T) {
+		backlog := domainmocks.NewBacklogMonitorMock(t)
+		backlog.EXPECT().Overloaded().Return(false).Once()
+		backlog.EXPECT().EstimateProcessing().Return(domain.ProcessingEstimate{QueuePosition: 12, Wait: 7500 * time.Millisecond}).Once()
+
+		w := submit(t, backlog, "application/json")
+
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		assert.Equal(t, "8", w.Header().Get("Retry-After"))
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+		assert.JSONEq(t, `{"number":"79927398713","status":"NEW","queue_position":12,"estimated_wait_seconds":8}`, w.Body.String())
+	})
+
+	t.Run("JSON receipt without backlog monitor", func(t *testing.T) {
+		w := submit(t, nil, "application/json")
+
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		assert.Empty(t, w.Header().Get("Retry-After"))
+		assert.JSONEq(t, `{"number":"79927398713","status":"NEW"}`, w.Body.String())
+	})
+
+	t.Run("Empty body without JSON in Accept", func(t *testing.T) {
+		backlog := domainmocks.NewBacklogMonitorMock(t)
+		backlog.EXPECT().Overloaded().Return(false).Once()
+
+		w := submit(t, backlog, "*/*")
+
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		assert.Empty(t, w.Body.String())
+		assert.Empty(t, w.Header().Get("Retry-After"))
+		assert.Equal(t, "Accept", w.Header().Get("Vary"))
+	})
+}
+
+func TestOrdersHandler_SubmitOrder_JSON(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		contentType    string
+		setupMock      func(*domainmocks.OrderServiceMock)
+		expectedStatus int
+	}{
+		{
+			name:        "With metadata",
+			body:        `{"number":"79927398713","metadata":{"channel":"pos","store_id":"42","receipt_id":"R-1"}}`,
+			contentType: "application/json; charset=utf-8",
+			setupMock: func(m *domainmocks.OrderServiceMock) {
+				metadata := &domain.OrderMetadata{Channel: "pos", StoreID: "42", ReceiptID: "R-1"}
+				m.EXPECT().SubmitOrder(mock.Anything, int64(1), "79927398713", metadata).Return(nil).Once()
+			},
+			expectedStatus: http.StatusAccepted,
+		},
+		{
+			name:        "Without metadata",
+			body:        `{"number":"79927398713"}`,
+			contentType: "application/json",
+			setupMock: func(m *domainmocks.OrderServiceMock) {
+				m.EXPECT().SubmitOrder(mock.Anything, int64(1), "79927398713", (*domain.OrderMetadata)(nil)).Return(nil).Once()
+			},
+			expectedStatus: http.StatusAccepted,
+		},
+		{
+			name:        "Invalid metadata",
+			body:        `{"number":"79927398713","metadata":{"channel":"pos"}}`,
+			contentType: "application/json",
+			setupMock: func(m *domainmocks.OrderServiceMock) {
+				m.EXPECT().SubmitOrder(mock.Anything, int64(1), "79927398713", mock.Anything).
+					Return(fmt.Errorf("order service: %w", domain.ErrInvalidOrderMetadata)).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Malformed JSON",
+			body:           `{"number":`,
+			contentType:    "application/json",
+			setupMock:      func(m *domainmocks.OrderServiceMock) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Missing number",
+			body:           `{"metadata":{"channel":"pos"}}`,
+			contentType:    "application/json",
+			setupMock:      func(m *domainmocks.OrderServiceMock) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := domainmocks.NewOrderServiceMock(t)
+			handler := NewOrdersHandler(mockService, nil, zap.NewNop())
+
+			tt.setupMock(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/user/orders", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			req = req.WithContext(context.WithValue(req.Context(), UserIDKey, int64(1)))
+			w := httptest.NewRecorder()
+
+			handler.SubmitOrder(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestOrdersHandler_SubmitFiscalReceipt(t *testing.T) {
+	const qr = "t=20200924T1837&s=349.93&fn=9282440300682838&i=46534&fp=1273019065&n=1"
+	const number = "9282440300682838465342"
+
+	tests := []struct {
+		name           string
+		body           string
+		contentType    string
+		setupMock      func(*domainmocks.OrderServiceMock)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "Text payload",
+			body: qr,
+			setupMock: func(m *domainmocks.OrderServiceMock) {
+				m.EXPECT().SubmitFiscalReceipt(mock.Anything, int64(1), qr).Return(number, nil).Once()
+			},
+			expectedStatus: http.StatusAccepted,
+			expectedBody:   `{"number":"9282440300682838465342","status":"NEW"}`,
+		},
+		{
+			name:        "JSON payload",
+			body:        `{"qr":"` + qr + `"}`,
+			contentType: "application/json",
+			setupMock: func(m *domainmocks.OrderServiceMock) {
+				m.EXPECT().SubmitFiscalReceipt(mock.Anything, int64(1), qr).Return(number, nil).Once()
+			},
+			expectedStatus: http.StatusAccepted,
+			expectedBody:   `{"number":"9282440300682838465342","status":"NEW"}`,
+		},
+		{
+			name: "Already submitted",
+			body: qr,
+			setupMock: func(m *domainmocks.OrderServiceMock) {
+				m.EXPECT().SubmitFiscalReceipt(mock.Anything, int64(1), qr).
+					Return(number, fmt.Errorf("order service: %w", domain.ErrOrderExists)).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"number":"9282440300682838465342"}`,
+		},
+		{
+			name: "Invalid payload",
+			body: "12345678903",
+			setupMock: func(m *domainmocks.OrderServiceMock) {
+				m.EXPECT().SubmitFiscalReceipt(mock.Anything, int64(1), "12345678903").
+					Return("", fmt.Errorf("order service: %w", domain.ErrInvalidFiscalReceipt)).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid receipt QR code"}`,
+		},
+		{
+			name: "Invalid sum",
+			body: qr,
+			setupMock: func(m *domainmocks.OrderServiceMock) {
+				m.EXPECT().SubmitFiscalReceipt(mock.Anything, int64(1), qr).
+					Return("", &domain.AmountError{Amount: 0, Reason: domain.AmountReasonOutOfRange}).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Owned by another user",
+			body: qr,
+			setupMock: func(m *domainmocks.OrderServiceMock) {
+				m.EXPECT().SubmitFiscalReceipt(mock.Anything, int64(1), qr).Return(number, domain.ErrOrderOwnedByAnother).Once()
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "Submissions banned",
+			body: qr,
+			setupMock: func(m *domainmocks.OrderServiceMock) {
+				err := &domain.SubmissionLimitError{Err: domain.ErrSubmissionsBanned, Until: time.Now().Add(time.Hour)}
+				m.EXPECT().SubmitFiscalReceipt(mock.Anything, int64(1), qr).Return(number, err).Once()
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   `{"error":"order submissions are temporarily banned","code":"submissions_banned"}`,
+		},
+		{
+			name:           "Malformed JSON",
+			body:           `{"qr":`,
+			contentType:    "application/json",
+			setupMock:      func(m *domainmocks.OrderServiceMock) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := domainmocks.NewOrderServiceMock(t)
+			handler := NewOrdersHandler(mockService, nil, zap.NewNop())
+
+			tt.setupMock(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/user/orders/receipt", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			req = req.WithContext(context.WithValue(req.Context(), UserIDKey, int64(1)))
+			w := httptest.NewRecorder()
+
+			handler.SubmitFiscalReceipt(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestOrdersHandler_GetOrders(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         *int64
+		setupMock      func(*domainmocks.OrderServiceMock)
+		expectedStatus int
+		checkBody      bool
+	}{
+		{
+			name:   "Success with orders",
+			userID: ptrInt64(1),
+			setupMock: func(m *domainmocks.OrderServiceMock) {
+				orders := []*domain.Order{
+					{Number: "111", Status: domain.OrderStatusProcessed},
+				}
+				m.EXPECT().GetOrders(mock.Anything, int64(1), domain.OrderFilter{}).Return(&domain.OrderPage{Orders: orders}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			checkBody:      true,
+		},
+		{
+			name:   "No orders",
+			userID: ptrInt64(1),
+			setupMock: func(m *domainmocks.OrderServiceMock) {
+				m.EXPECT().GetOrders(mock.Anything, int64(1), domain.OrderFilter{}).Return(&domain.OrderPage{Orders: []*domain.Order{}}, nil).Once()
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "Unauthorized",
+			userID:         nil,
+			setupMock:      func(m *domainmocks.OrderServiceMock) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := domainmocks.NewOrderServiceMock(t)
+			logger, _ := zap.NewDevelopment()
+			handler := NewOrdersHandler(mockService, nil, logger)
+
+			tt.setupMock(mockService)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/user/orders", nil)
+			if tt.userID != nil {
+				ctx := context.WithValue(req.Context(), UserIDKey, *tt.userID)
+				req = req.WithContext(ctx)
+			}
+			w := httptest.NewRecorder()
+
+			handler.GetOrders(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.checkBody {
+				assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
+
+func TestOrdersHandler_GetOrders_Filter(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("Filter and page", func(t *testing.T) {
+		mockService := domainmocks.NewOrderServiceMock(t)
+		handler := NewOrdersHandler(mockService, nil, zap.NewNop())
+		mockService.EXPECT().GetOrders(mock.Anything, int64(1), domain.OrderFilter{
+			Status: domain.OrderStatusProcessed, From: from, To: from.AddDate(0, 1, 0), Limit: 10, Offset: 20,
+		}).Return(&domain.OrderPage{Orders: []*domain.Order{{Number: "111"}}, HasMore: true}, nil).Once()
 
-			tt.setupMock(mockService)
+		req := httptest.NewRequest(http.MethodGet,
+			"/api/user/orders?status=PROCESSED&from=2024-01-01T00:00:00Z&to=2024-02-01T00:00:00Z&limit=10&offset=20", nil)
+		req.Header.Set("Accept", mediaTypeEnvelope)
+		req = req.WithContext(context.WithValue(req.Context(), UserIDKey, int64(1)))
+		w := httptest.NewRecorder()
 
-			req := httptest.NewRequest(http.MethodPost, "/api/user/orders", bytes.NewBufferString(tt.body))
-			if tt.userID != nil {
-				ctx := context.WithValue(req.Context(), UserIDKey, *tt.userID)
-				req = req.WithContext(ctx)
-			}
+		handler.GetOrders(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"pagination":{"offset":20,"count":1,"has_more":true}`)
+	})
+
+	for name, query := range map[string]string{
+		"Invalid limit": "limit=-1",
+		"Invalid from":  "from=yesterday",
+		"Invalid to":    "to=2024-02-01",
+	} {
+		t.Run(name, func(t *testing.T) {
+			handler := NewOrdersHandler(domainmocks.NewOrderServiceMock(t), nil, zap.NewNop())
+
+			req := httptest.NewRequest(http.MethodGet, "/api/user/orders?"+query, nil)
+			req = req.WithContext(context.WithValue(req.Context(), UserIDKey, int64(1)))
 			w := httptest.NewRecorder()
 
-			handler.SubmitOrder(w, req)
+			handler.GetOrders(w, req)
 
-			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
 		})
 	}
+
+	t.Run("Rejected filter", func(t *testing.T) {
+		mockService := domainmocks.NewOrderServiceMock(t)
+		handler := NewOrdersHandler(mockService, nil, zap.NewNop())
+		mockService.EXPECT().GetOrders(mock.Anything, int64(1), domain.OrderFilter{Status: "LOST"}).
+			Return(nil, domain.ErrInvalidInput).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/api/user/orders?status=LOST", nil)
+		req = req.WithContext(context.WithValue(req.Context(), UserIDKey, int64(1)))
+		w := httptest.NewRecorder()
+
+		handler.GetOrders(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestOrdersHandler_GetOrders_NDJSON(t *testing.T) {
+	mockService := domainmocks.NewOrderServiceMock(t)
+	handler := NewOrdersHandler(mockService, nil, zap.NewNop())
+
+	orders := []*domain.Order{
+		{Number: "111", Status: domain.OrderStatusProcessed},
+		{Number: "222", Status: domain.OrderStatusNew},
+	}
+	mockService.EXPECT().GetOrders(mock.Anything, int64(1), domain.OrderFilter{}).Return(&domain.OrderPage{Orders: orders}, nil).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/user/orders", nil)
+	req.Header.Set("Accept", "application/x-ndjson")
+	req = req.WithContext(context.WithValue(req.Context(), UserIDKey, int64(1)))
+	w := httptest.NewRecorder()
+
+	handler.GetOrders(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+	assert.Equal(t, "Accept", w.Header().Get("Vary"))
+
+	lines := strings.Split(strings.TrimSuffix(w.Body.String(), "\n"), "\n")
+	require.Len(t, lines, 2)
+	for i, line := range lines {
+		var order OrderResponse
+		require.NoError(t, json.Unmarshal([]byte(line), &order))
+		assert.Equal(t, orders[i].Number, order.Number)
+	}
 }
 
-func TestOrdersHandler_GetOrders(t *testing.T) {
+func TestOrdersHandler_GetOrder(t *testing.T) {
+	publicID := uuid.New()
+
 	tests := []struct {
 		name           string
+		id             string
 		userID         *int64
 		setupMock      func(*domainmocks.OrderServiceMock)
 		expectedStatus int
-		checkBody      bool
 	}{
 		{
-			name:   "Success with orders",
+			name:   "Success",
+			id:     publicID.String(),
 			userID: ptrInt64(1),
 			setupMock: func(m *domainmocks.OrderServiceMock) {
-				orders := []*domain.Order{
-					{Number: "111", Status: domain.OrderStatusProcessed},
-				}
-				m.EXPECT().GetOrders(mock.Anything, int64(1)).Return(orders, nil).Once()
+				order := &domain.Order{PublicID: publicID, Number: "12345678903", Status: domain.OrderStatusNew}
+				m.EXPECT().GetOrder(mock.Anything, int64(1), publicID).Return(order, nil).Once()
 			},
 			expectedStatus: http.StatusOK,
-			checkBody:      true,
 		},
 		{
-			name:   "No orders",
+			name:   "Not found",
+			id:     publicID.String(),
 			userID: ptrInt64(1),
 			setupMock: func(m *domainmocks.OrderServiceMock) {
-				m.EXPECT().GetOrders(mock.Anything, int64(1)).Return([]*domain.Order{}, nil).Once()
+				m.EXPECT().GetOrder(mock.Anything, int64(1), publicID).Return(nil, domain.ErrOrderNotFound).Once()
 			},
-			expectedStatus: http.StatusNoContent,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Invalid id",
+			id:             "12345678903",
+			userID:         ptrInt64(1),
+			setupMock:      func(m *domainmocks.OrderServiceMock) {},
+			expectedStatus: http.StatusBadRequest,
 		},
 		{
 			name:           "Unauthorized",
-			userID:         nil,
+			id:             publicID.String(),
 			setupMock:      func(m *domainmocks.OrderServiceMock) {},
 			expectedStatus: http.StatusUnauthorized,
 		},
@@ -273,35 +917,81 @@ func TestOrdersHandler_GetOrders(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockService := domainmocks.NewOrderServiceMock(t)
-			logger, _ := zap.NewDevelopment()
-			handler := NewOrdersHandler(mockService, logger)
-
+			handler := NewOrdersHandler(mockService, nil, zap.NewNop())
 			tt.setupMock(mockService)
 
-			req := httptest.NewRequest(http.MethodGet, "/api/user/orders", nil)
+			r := chi.NewRouter()
+			r.Get("/api/user/orders/{id}", handler.GetOrder)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/user/orders/"+tt.id, nil)
 			if tt.userID != nil {
-				ctx := context.WithValue(req.Context(), UserIDKey, *tt.userID)
-				req = req.WithContext(ctx)
+				req = req.WithContext(context.WithValue(req.Context(), UserIDKey, *tt.userID))
 			}
 			w := httptest.NewRecorder()
 
-			handler.GetOrders(w, req)
+			r.ServeHTTP(w, req)
 
 			assert.Equal(t, tt.expectedStatus, w.Code)
-			if tt.checkBody {
-				assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+			if tt.expectedStatus == http.StatusOK {
+				var order OrderResponse
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&order))
+				assert.Equal(t, publicID.String(), order.ID)
+				assert.Equal(t, "12345678903", order.Number)
 			}
 		})
 	}
 }
 
+func TestPrefersNDJSON(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   bool
+	}{
+		{accept: "", want: false},
+		{accept: "*/*", want: false},
+		{accept: "application/json", want: false},
+		{accept: "application/x-ndjson", want: true},
+		{accept: "application/ndjson", want: true},
+		{accept: "application/json, application/x-ndjson", want: true},
+		{accept: "application/json;q=1, application/x-ndjson;q=0.5", want: false},
+		{accept: "application/json;q=0.5, application/x-ndjson", want: true},
+		{accept: "application/x-ndjson;q=0", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			assert.Equal(t, tt.want, prefersNDJSON(tt.accept))
+		})
+	}
+}
+
+func TestAcceptsJSON(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   bool
+	}{
+		{accept: "", want: false},
+		{accept: "*/*", want: false},
+		{accept: "text/plain", want: false},
+		{accept: "application/json", want: true},
+		{accept: "text/plain, Application/JSON;q=0.5", want: true},
+		{accept: "application/json;q=0", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			assert.Equal(t, tt.want, acceptsJSON(tt.accept))
+		})
+	}
+}
+
 func TestBalanceHandler_GetBalance(t *testing.T) {
 	tests := []struct {
 		name           string
 		userID         *int64
 		setupMock      func(*domainmocks.BalanceServiceMock)
 		expectedStatus int
-		checkBalance   *domain.Balance
+		checkBalance   *BalanceResponse
 	}{
 		{
 			name:   "Success",
@@ -311,7 +1001,24 @@ func TestBalanceHandler_GetBalance(t *testing.T) {
 				m.EXPECT().GetBalance(mock.Anything, int64(1)).Return(balance, nil).Once()
 			},
 			expectedStatus: http.StatusOK,
-			checkBalance:   &domain.Balance{Current: 500.0, Withdrawn: 200.0},
+			checkBalance:   &BalanceResponse{Current: 500.0, Withdrawn: 200.0},
+		},
+		{
+			name:   "Database unavailable",
+			userID: ptrInt64(1),
+			setupMock: func(m *domainmocks.BalanceServiceMock) {
+				err := fmt.Errorf("balance service: %w", domain.ErrStorageUnavailable)
+				m.EXPECT().GetBalance(mock.Anything, int64(1)).Return(nil, err).Once()
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:   "Internal error",
+			userID: ptrInt64(1),
+			setupMock: func(m *domainmocks.BalanceServiceMock) {
+				m.EXPECT().GetBalance(mock.Anything, int64(1)).Return(nil, errors.New("boom")).Once()
+			},
+			expectedStatus: http.StatusInternalServerError,
 		},
 		{
 			name:           "Unauthorized",
@@ -340,7 +1047,7 @@ func TestBalanceHandler_GetBalance(t *testing.T) {
 
 			assert.Equal(t, tt.expectedStatus, w.Code)
 			if tt.checkBalance != nil {
-				var result domain.Balance
+				var result BalanceResponse
 				err := json.NewDecoder(w.Body).Decode(&result)
 				require.NoError(t, err)
 				assert.Equal(t, tt.checkBalance.Current, result.Current)
@@ -350,6 +1057,73 @@ func TestBalanceHandler_GetBalance(t *testing.T) {
 	}
 }
 
+func TestBalanceHandler_GetBalance_Detailed(t *testing.T) {
+	logger := zap.NewNop()
+
+	t.Run("Success", func(t *testing.T) {
+		mockService := domainmocks.NewBalanceServiceMock(t)
+		handler := NewBalanceHandler(mockService, logger)
+
+		details := &domain.BalanceDetails{
+			Balance: domain.Balance{Current: 500, Withdrawn: 200},
+			Pending: domain.PendingAccrual{Orders: 1, Accrual: 25},
+		}
+		mockService.EXPECT().GetBalanceDetails(mock.Anything, int64(1)).Return(details, nil).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/api/user/balance?detailed=true", nil)
+		req = req.WithContext(context.WithValue(req.Context(), UserIDKey, int64(1)))
+		w := httptest.NewRecorder()
+
+		handler.GetBalance(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"current":500,"withdrawn":200,"pending":{"orders":1,"accrual":25}}`, w.Body.String())
+	})
+
+	t.Run("Detailed disabled", func(t *testing.T) {
+		mockService := domainmocks.NewBalanceServiceMock(t)
+		handler := NewBalanceHandler(mockService, logger)
+
+		mockService.EXPECT().GetBalance(mock.Anything, int64(1)).Return(&domain.Balance{Current: 500}, nil).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/api/user/balance?detailed=false", nil)
+		req = req.WithContext(context.WithValue(req.Context(), UserIDKey, int64(1)))
+		w := httptest.NewRecorder()
+
+		handler.GetBalance(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"current":500,"withdrawn":0}`, w.Body.String())
+	})
+
+	t.Run("Invalid flag", func(t *testing.T) {
+		handler := NewBalanceHandler(domainmocks.NewBalanceServiceMock(t), logger)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/user/balance?detailed=maybe", nil)
+		req = req.WithContext(context.WithValue(req.Context(), UserIDKey, int64(1)))
+		w := httptest.NewRecorder()
+
+		handler.GetBalance(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Internal error", func(t *testing.T) {
+		mockService := domainmocks.NewBalanceServiceMock(t)
+		handler := NewBalanceHandler(mockService, logger)
+
+		mockService.EXPECT().GetBalanceDetails(mock.Anything, int64(1)).Return(nil, errors.New("boom")).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/api/user/balance?detailed=1", nil)
+		req = req.WithContext(context.WithValue(req.Context(), UserIDKey, int64(1)))
+		w := httptest.NewRecorder()
+
+		handler.GetBalance(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
 func TestBalanceHandler_Withdraw(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -357,22 +1131,64 @@ func TestBalanceHandler_Withdraw(t *testing.T) {
 		userID         *int64
 		setupMock      func(*domainmocks.BalanceServiceMock)
 		expectedStatus int
+		expectedCode   string
 	}{
 		{
 			name:   "Success",
 			body:   `{"order":"79927398713","sum":100}`,
 			userID: ptrInt64(1),
 			setupMock: func(m *domainmocks.BalanceServiceMock) {
-				m.EXPECT().Withdraw(mock.Anything, int64(1), "79927398713", 100.0).Return(nil).Once()
+				m.EXPECT().Withdraw(mock.Anything, int64(1), "79927398713", 100.0, "").Return(nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "Success with partner",
+			body:   `{"order":"79927398713","sum":100,"partner":"store-42"}`,
+			userID: ptrInt64(1),
+			setupMock: func(m *domainmocks.BalanceServiceMock) {
+				m.EXPECT().Withdraw(mock.Anything, int64(1), "79927398713", 100.0, "store-42").Return(nil).Once()
 			},
 			expectedStatus: http.StatusOK,
 		},
+		{
+			name:   "Partner too long",
+			body:   `{"order":"79927398713","sum":100,"partner":"store-42"}`,
+			userID: ptrInt64(1),
+			setupMock: func(m *domainmocks.BalanceServiceMock) {
+				m.EXPECT().Withdraw(mock.Anything, int64(1), "79927398713", 100.0, "store-42").
+					Return(fmt.Errorf("balance service: %w", domain.ErrInvalidPartner)).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Sum exceeds limit",
+			body:   `{"order":"79927398713","sum":1e10}`,
+			userID: ptrInt64(1),
+			setupMock: func(m *domainmocks.BalanceServiceMock) {
+				m.EXPECT().Withdraw(mock.Anything, int64(1), "79927398713", 1e10, "").
+					Return(fmt.Errorf("balance service: %w", &domain.AmountError{Amount: 1e10, Reason: domain.AmountReasonOutOfRange})).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "sum_out_of_range",
+		},
+		{
+			name:   "Sum with fractions of a kopeck",
+			body:   `{"order":"79927398713","sum":10.005}`,
+			userID: ptrInt64(1),
+			setupMock: func(m *domainmocks.BalanceServiceMock) {
+				m.EXPECT().Withdraw(mock.Anything, int64(1), "79927398713", 10.005, "").
+					Return(fmt.Errorf("balance service: %w", &domain.AmountError{Amount: 10.005, Reason: domain.AmountReasonPrecision})).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "sum_too_precise",
+		},
 		{
 			name:   "Insufficient funds",
 			body:   `{"order":"79927398713","sum":1000}`,
 			userID: ptrInt64(1),
 			setupMock: func(m *domainmocks.BalanceServiceMock) {
-				m.EXPECT().Withdraw(mock.Anything, int64(1), "79927398713", 1000.0).Return(service.ErrInsufficientFunds).Once()
+				m.EXPECT().Withdraw(mock.Anything, int64(1), "79927398713", 1000.0, "").Return(domain.ErrInsufficientFunds).Once()
 			},
 			expectedStatus: http.StatusPaymentRequired,
 		},
@@ -381,10 +1197,21 @@ func TestBalanceHandler_Withdraw(t *testing.T) {
 			body:   `{"order":"12345","sum":100}`,
 			userID: ptrInt64(1),
 			setupMock: func(m *domainmocks.BalanceServiceMock) {
-				m.EXPECT().Withdraw(mock.Anything, int64(1), "12345", 100.0).Return(service.ErrInvalidOrderNumber).Once()
+				m.EXPECT().Withdraw(mock.Anything, int64(1), "12345", 100.0, "").Return(domain.ErrInvalidOrderNumber).Once()
 			},
 			expectedStatus: http.StatusUnprocessableEntity,
 		},
+		{
+			name:   "Daily limit exceeded",
+			body:   `{"order":"79927398713","sum":100}`,
+			userID: ptrInt64(1),
+			setupMock: func(m *domainmocks.BalanceServiceMock) {
+				err := fmt.Errorf("balance service: withdrawal rejected: %w", domain.ErrDailyWithdrawalLimit)
+				m.EXPECT().Withdraw(mock.Anything, int64(1), "79927398713", 100.0, "").Return(err).Once()
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedCode:   "daily_limit",
+		},
 		{
 			name:           "Unauthorized",
 			body:           `{"order":"79927398713","sum":100}`,
@@ -399,6 +1226,13 @@ func TestBalanceHandler_Withdraw(t *testing.T) {
 			setupMock:      func(m *domainmocks.BalanceServiceMock) {},
 			expectedStatus: http.StatusBadRequest,
 		},
+		{
+			name:           "Trailing data",
+			body:           `{"order":"79927398713","sum":100}{"sum":1}`,
+			userID:         ptrInt64(1),
+			setupMock:      func(m *domainmocks.BalanceServiceMock) {},
+			expectedStatus: http.StatusBadRequest,
+		},
 	}
 
 	for _, tt := range tests {
@@ -419,6 +1253,188 @@ func TestBalanceHandler_Withdraw(t *testing.T) {
 			handler.Withdraw(w, req)
 
 			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedCode != "" {
+				var body ErrorResponse
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+				assert.Equal(t, tt.expectedCode, body.Code)
+			}
+		})
+	}
+}
+
+func TestBalanceHandler_GetWithdrawals_Cursor(t *testing.T) {
+	processedAt := time.Date(2020, 12, 9, 16, 9, 57, 123456000, time.UTC)
+	next := &domain.WithdrawalCursor{ProcessedAt: processedAt, ID: 42}
+	withdrawals := []*domain.Transaction{{ID: 42, PublicID: uuid.New(), OrderNumber: "2377225624", Amount: 500, ProcessedAt: processedAt}}
+
+	t.Run("First page links the next one", func(t *testing.T) {
+		mockService := domainmocks.NewBalanceServiceMock(t)
+		handler := NewBalanceHandler(mockService, zap.NewNop())
+		mockService.EXPECT().GetWithdrawals(mock.Anything, int64(1), (*domain.WithdrawalCursor)(nil), 1).
+			Return(&domain.WithdrawalPage{Withdrawals: withdrawals, Next: next}, nil).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/api/user/withdrawals?limit=1", nil)
+		req.Header.Set("Accept", mediaTypeEnvelope)
+		req = req.WithContext(context.WithValue(req.Context(), UserIDKey, int64(1)))
+		w := httptest.NewRecorder()
+
+		handler.GetWithdrawals(w, req)
+
+		cursor := encodeWithdrawalCursor(next)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `</api/user/withdrawals?cursor=`+cursor+`&limit=1>; rel="next"`, w.Header().Get("Link"))
+		assert.Contains(t, w.Body.String(), `"pagination":{"offset":0,"count":1,"has_more":true,"next_cursor":"`+cursor+`"}`)
+	})
+
+	t.Run("Cursor is passed to the service", func(t *testing.T) {
+		mockService := domainmocks.NewBalanceServiceMock(t)
+		handler := NewBalanceHandler(mockService, zap.NewNop())
+		mockService.EXPECT().GetWithdrawals(mock.Anything, int64(1), next, 0).
+			Return(&domain.WithdrawalPage{Withdrawals: withdrawals}, nil).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/api/user/withdrawals?cursor="+encodeWithdrawalCursor(next), nil)
+		req = req.WithContext(context.WithValue(req.Context(), UserIDKey, int64(1)))
+		w := httptest.NewRecorder()
+
+		handler.GetWithdrawals(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("Link"))
+	})
+
+	for name, query := range map[string]string{
+		"Invalid limit":     "limit=-1",
+		"Malformed cursor":  "cursor=not-base64!",
+		"Cursor without id": "cursor=" + base64.RawURLEncoding.EncodeToString([]byte("1607530197123456")),
+	} {
+		t.Run(name, func(t *testing.T) {
+			handler := NewBalanceHandler(domainmocks.NewBalanceServiceMock(t), zap.NewNop())
+
+			req := httptest.NewRequest(http.MethodGet, "/api/user/withdrawals?"+query, nil)
+			req = req.WithContext(context.WithValue(req.Context(), UserIDKey, int64(1)))
+			w := httptest.NewRecorder()
+
+			handler.GetWithdrawals(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
+
+func TestWithdrawalCursor_RoundTrip(t *testing.T) {
+	cursor := &domain.WithdrawalCursor{ProcessedAt: time.Date(2020, 12, 9, 16, 9, 57, 123456000, time.UTC), ID: 7}
+
+	decoded, ok := decodeWithdrawalCursor(encodeWithdrawalCursor(cursor))
+
+	require.True(t, ok)
+	assert.Equal(t, cursor, decoded)
+}
+
+func TestBalanceHandler_GetWithdrawalSummary(t *testing.T) {
+	lastAt := time.Date(2020, 12, 9, 16, 9, 57, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		summary        *domain.WithdrawalSummary
+		err            error
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "Success",
+			summary:        &domain.WithdrawalSummary{Count: 3, Total: 1500, Largest: 800, LastAt: &lastAt},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"count":3,"total":1500,"largest":800,"last_processed_at":"2020-12-09T16:09:57Z"}`,
+		},
+		{
+			name:           "No withdrawals",
+			summary:        &domain.WithdrawalSummary{},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"count":0,"total":0,"largest":0}`,
+		},
+		{
+			name:           "Service error",
+			err:            errors.New("db error"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := domainmocks.NewBalanceServiceMock(t)
+			handler := NewBalanceHandler(mockService, zap.NewNop())
+			mockService.EXPECT().GetWithdrawalSummary(mock.Anything, int64(1)).Return(tt.summary, tt.err).Once()
+
+			req := httptest.NewRequest(http.MethodGet, "/api/user/withdrawals/summary", nil)
+			req = req.WithContext(context.WithValue(req.Context(), UserIDKey, int64(1)))
+			w := httptest.NewRecorder()
+
+			handler.GetWithdrawalSummary(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestBalanceHandler_GetWithdrawal(t *testing.T) {
+	publicID := uuid.New()
+
+	tests := []struct {
+		name           string
+		id             string
+		setupMock      func(*domainmocks.BalanceServiceMock)
+		expectedStatus int
+	}{
+		{
+			name: "Success",
+			id:   publicID.String(),
+			setupMock: func(m *domainmocks.BalanceServiceMock) {
+				withdrawal := &domain.Transaction{PublicID: publicID, OrderNumber: "2377225624", Amount: 100}
+				m.EXPECT().GetWithdrawal(mock.Anything, int64(1), publicID).Return(withdrawal, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "Not found",
+			id:   publicID.String(),
+			setupMock: func(m *domainmocks.BalanceServiceMock) {
+				m.EXPECT().GetWithdrawal(mock.Anything, int64(1), publicID).Return(nil, domain.ErrTransactionNotFound).Once()
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Invalid id",
+			id:             "not-a-uuid",
+			setupMock:      func(m *domainmocks.BalanceServiceMock) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := domainmocks.NewBalanceServiceMock(t)
+			handler := NewBalanceHandler(mockService, zap.NewNop())
+			tt.setupMock(mockService)
+
+			r := chi.NewRouter()
+			r.Get("/api/user/withdrawals/{id}", handler.GetWithdrawal)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/user/withdrawals/"+tt.id, nil)
+			req = req.WithContext(context.WithValue(req.Context(), UserIDKey, int64(1)))
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var withdrawal WithdrawalResponse
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&withdrawal))
+				assert.Equal(t, publicID.String(), withdrawal.ID)
+				assert.Equal(t, Amount(100), withdrawal.Sum)
+			}
 		})
 	}
 }
@@ -429,6 +1445,7 @@ func TestAuthMiddleware(t *testing.T) {
 		authHeader     string
 		expectedStatus int
 		checkUserID    bool
+		failureReason  string
 	}{
 		{
 			name:           "Valid token",
@@ -450,15 +1467,49 @@ func TestAuthMiddleware(t *testing.T) {
 			name:           "Invalid token",
 			authHeader:     "Bearer invalid.token.string",
 			expectedStatus: http.StatusUnauthorized,
+			failureReason:  "invalid",
+		},
+		{
+			name:           "Expired token",
+			authHeader:     "Bearer expired.token.string",
+			expectedStatus: http.StatusUnauthorized,
+			failureReason:  "expired",
+		},
+		{
+			name:           "Wrong signature",
+			authHeader:     "Bearer forged.token.string",
+			expectedStatus: http.StatusUnauthorized,
+			failureReason:  "signature",
+		},
+		{
+			name:           "Revoked token",
+			authHeader:     "Bearer revoked.token.string",
+			expectedStatus: http.StatusUnauthorized,
+			failureReason:  "revoked",
+		},
+		{
+			name:           "Storage unavailable",
+			authHeader:     "Bearer unverifiable.token.string",
+			expectedStatus: http.StatusServiceUnavailable,
 		},
 	}
 
-	jwtManager := jwt.NewManager("test-secret", time.Hour)
-	validToken, _ := jwtManager.Generate(123)
+	validToken := "valid.token.string"
+	validator := domainmocks.NewTokenValidatorMock(t)
+	validator.EXPECT().ValidateToken(mock.Anything, validToken).Return(&domain.AccessToken{UserID: 123}, nil).Maybe()
+	validator.EXPECT().ValidateToken(mock.Anything, "invalid.token.string").Return(nil, domain.ErrInvalidToken).Maybe()
+	validator.EXPECT().ValidateToken(mock.Anything, "unverifiable.token.string").Return(nil, domain.ErrStorageUnavailable).Maybe()
+	validator.EXPECT().ValidateToken(mock.Anything, "expired.token.string").
+		Return(nil, fmt.Errorf("%w: %w", domain.ErrInvalidToken, domain.ErrTokenExpired)).Maybe()
+	validator.EXPECT().ValidateToken(mock.Anything, "forged.token.string").
+		Return(nil, fmt.Errorf("%w: %w", domain.ErrInvalidToken, domain.ErrTokenSignature)).Maybe()
+	validator.EXPECT().ValidateToken(mock.Anything, "revoked.token.string").
+		Return(nil, fmt.Errorf("%w: %w", domain.ErrInvalidToken, domain.ErrTokenRevoked)).Maybe()
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			middleware := AuthMiddleware(jwtManager)
+			m := metrics.New()
+			middleware := AuthMiddleware(validator, m)
 			handler := middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 				if tt.checkUserID {
 					userID, ok := GetUserID(r.Context())
@@ -479,11 +1530,99 @@ func TestAuthMiddleware(t *testing.T) {
 			handler.ServeHTTP(w, req)
 
 			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.failureReason != "" {
+				metricsResp := httptest.NewRecorder()
+				m.Handler().ServeHTTP(metricsResp, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+				assert.Contains(t, metricsResp.Body.String(), `gophermart_auth_token_failures_total{reason="`+tt.failureReason+`"} 1`)
+			}
 		})
 	}
 }
 
+func TestAuthMiddleware_TokenExpiresIn(t *testing.T) {
+	expiresAt := time.Now().Add(90 * time.Minute)
+	validator := domainmocks.NewTokenValidatorMock(t)
+	validator.EXPECT().ValidateToken(mock.Anything, "expiring").Return(&domain.AccessToken{UserID: 1, ExpiresAt: expiresAt}, nil).Once()
+	validator.EXPECT().ValidateToken(mock.Anything, "unlimited").Return(&domain.AccessToken{UserID: 1}, nil).Once()
+
+	handler := AuthMiddleware(validator, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, ok := GetTokenExpiresAt(r.Context())
+		if r.Header.Get("Authorization") == "Bearer expiring" {
+			assert.True(t, ok)
+			assert.True(t, expiresAt.Equal(got))
+		} else {
+			assert.False(t, ok)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	send := func(token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	seconds, err := strconv.Atoi(send("expiring").Header().Get("X-Token-Expires-In"))
+	require.NoError(t, err)
+	assert.InDelta(t, 5400, seconds, 5)
+
+	assert.Empty(t, send("unlimited").Header().Get("X-Token-Expires-In"))
+}
+
+func TestAuthMiddleware_Impersonation(t *testing.T) {
+	validator := domainmocks.NewTokenValidatorMock(t)
+	validator.EXPECT().ValidateToken(mock.Anything, "impersonated").Return(&domain.AccessToken{UserID: 7, ImpersonatorID: 1}, nil).Once()
+	validator.EXPECT().ValidateToken(mock.Anything, "own").Return(&domain.AccessToken{UserID: 7}, nil).Once()
+
+	var impersonatorID int64
+	handler := AuthMiddleware(validator, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		impersonatorID, _ = GetImpersonatorID(r.Context())
+	}))
+
+	for token, want := range map[string]int64{"impersonated": 1, "own": 0} {
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		assert.Equal(t, want, impersonatorID, token)
+	}
+}
+
+func TestTokenExpiresIn(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, int64(3600), tokenExpiresIn(now.Add(time.Hour), now))
+	assert.Equal(t, int64(59), tokenExpiresIn(now.Add(59*time.Second+900*time.Millisecond), now))
+	assert.Equal(t, int64(0), tokenExpiresIn(now.Add(-time.Minute), now))
+}
+
 // Helper function
 func ptrInt64(i int64) *int64 {
 	return &i
 }
+
+func TestWriteSubmissionLimitError(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/api/user/orders", nil)
+	w := httptest.NewRecorder()
+	writeSubmissionLimitError(w, r, &domain.SubmissionLimitError{
+		Err:   domain.ErrTooManySubmissions,
+		Until: time.Now().Add(90 * time.Second),
+	})
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "90", w.Header().Get("Retry-After"))
+	assert.JSONEq(t, `{"error":"too many order submissions","code":"submission_rate_limit"}`, w.Body.String())
+
+	// Истекшее ограничение все равно подсказывает повтор не раньше чем через секунду
+	w = httptest.NewRecorder()
+	writeSubmissionLimitError(w, r, &domain.SubmissionLimitError{Err: domain.ErrSubmissionsBanned, Until: time.Now().Add(-time.Second)})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+
+	// Сообщение переводится на язык запроса, код остается прежним
+	w = httptest.NewRecorder()
+	r = r.WithContext(locale.With(r.Context(), locale.Russian))
+	writeSubmissionLimitError(w, r, &domain.SubmissionLimitError{Err: domain.ErrSubmissionsBanned, Until: time.Now().Add(time.Minute)})
+	assert.JSONEq(t, `{"error":"загрузка заказов временно запрещена","code":"submissions_banned"}`, w.Body.String())
+}
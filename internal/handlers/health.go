@@ -6,49 +6,155 @@ import (
 	"net/http"
 	"time"
 
-	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/zap"
 )
 
+// DatabasePinger проверяет соединение с БД
+type DatabasePinger interface {
+	Ping(ctx context.Context) error
+}
+
+// DatabaseMonitor сообщает, с какого момента БД недоступна по данным репозиториев
+type DatabaseMonitor interface {
+	UnavailableSince() (time.Time, bool)
+}
+
+// AccrualPinger проверяет доступность системы начислений
+type AccrualPinger interface {
+	Ping(ctx context.Context) error
+}
+
+// AccrualMonitor сообщает время последнего успешного опроса системы начислений воркерами
+type AccrualMonitor interface {
+	LastAccrualSuccess() (time.Time, bool)
+}
+
+// healthCheckTimeout ограничивает проверку одной зависимости
+const healthCheckTimeout = 2 * time.Second
+
 // HealthHandler обрабатывает health check запросы
 type HealthHandler struct {
-	db     *pgxpool.Pool
-	logger *zap.Logger
+	db             DatabasePinger
+	monitor        DatabaseMonitor
+	accrual        AccrualPinger
+	accrualMonitor AccrualMonitor
+	logger         *zap.Logger
 }
 
 // NewHealthHandler создает новый HealthHandler
-func NewHealthHandler(db *pgxpool.Pool, logger *zap.Logger) *HealthHandler {
+func NewHealthHandler(
+	db DatabasePinger,
+	monitor DatabaseMonitor,
+	accrual AccrualPinger,
+	accrualMonitor AccrualMonitor,
+	logger *zap.Logger,
+) *HealthHandler {
 	return &HealthHandler{
-		db:     db,
-		logger: logger,
+		db:             db,
+		monitor:        monitor,
+		accrual:        accrual,
+		accrualMonitor: accrualMonitor,
+		logger:         logger,
 	}
 }
 
 // HealthResponse представляет ответ health check
 type HealthResponse struct {
-	Status   string `json:"status"`
-	Database string `json:"database"`
+	Status           string     `json:"status"`
+	Database         string     `json:"database"`
+	UnavailableSince *time.Time `json:"unavailable_since,omitempty"`
+}
+
+// DependencyStatus описывает состояние одной внешней зависимости
+type DependencyStatus struct {
+	Status           string     `json:"status"`
+	UnavailableSince *time.Time `json:"unavailable_since,omitempty"`
+	LastSuccess      *time.Time `json:"last_success,omitempty"`
+}
+
+// DependenciesResponse представляет ответ проверки внешних зависимостей
+type DependenciesResponse struct {
+	Status   string           `json:"status"`
+	Database DependencyStatus `json:"database"`
+	Accrual  DependencyStatus `json:"accrual"`
 }
 
 // Health возвращает статус приложения
 func (h *HealthHandler) Health(w http.ResponseWriter, r *http.Request) {
+	database := h.checkDatabase(r.Context())
+
 	response := HealthResponse{
+		Status:           "ok",
+		Database:         database.Status,
+		UnavailableSince: database.UnavailableSince,
+	}
+	if database.Status != "ok" {
+		response.Status = "degraded"
+	}
+
+	h.writeHealth(w, response.Status, response)
+}
+
+// Dependencies возвращает состояние БД и системы начислений по отдельности,
+// чтобы отличить недоступность нашей БД от недоступности системы начислений
+func (h *HealthHandler) Dependencies(w http.ResponseWriter, r *http.Request) {
+	response := DependenciesResponse{
 		Status:   "ok",
-		Database: "ok",
+		Database: h.checkDatabase(r.Context()),
+		Accrual:  h.checkAccrual(r.Context()),
+	}
+	if response.Database.Status != "ok" || response.Accrual.Status != "ok" {
+		response.Status = "degraded"
 	}
 
-	// Проверяем подключение к БД с таймаутом
-	ctx, cancel := context.WithTimeout(r.Context(), 2*time.Second)
+	h.writeHealth(w, response.Status, response)
+}
+
+// checkDatabase пингует БД и учитывает режим деградации
+func (h *HealthHandler) checkDatabase(ctx context.Context) DependencyStatus {
+	status := DependencyStatus{Status: "ok"}
+
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
 	defer cancel()
 
 	if err := h.db.Ping(ctx); err != nil {
-		response.Status = "degraded"
-		response.Database = "unavailable"
+		status.Status = "unavailable"
 		h.logger.Warn("health check: database unavailable", zap.Error(err))
 	}
 
+	// Пока трекер не отметил восстановление, воркеры стоят на паузе,
+	// поэтому деградацию сообщаем даже при успешном пинге
+	if since, down := h.monitor.UnavailableSince(); down {
+		status.Status = "unavailable"
+		status.UnavailableSince = &since
+	}
+
+	return status
+}
+
+// checkAccrual проверяет доступность системы начислений и время последнего успешного опроса
+func (h *HealthHandler) checkAccrual(ctx context.Context) DependencyStatus {
+	status := DependencyStatus{Status: "ok"}
+
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	if err := h.accrual.Ping(ctx); err != nil {
+		status.Status = "unavailable"
+		h.logger.Warn("health check: accrual system unavailable", zap.Error(err))
+	}
+
+	if last, ok := h.accrualMonitor.LastAccrualSuccess(); ok {
+		status.LastSuccess = &last
+	}
+
+	return status
+}
+
+// writeHealth отправляет ответ health check, 503 - если статус не ok
+func (h *HealthHandler) writeHealth(w http.ResponseWriter, status string, response any) {
 	w.Header().Set("Content-Type", "application/json")
-	if response.Status != "ok" {
+	if status != "ok" {
 		w.WriteHeader(http.StatusServiceUnavailable)
 	}
 	if err := json.NewEncoder(w).Encode(response); err != nil {
@@ -59,7 +165,7 @@ func (h *HealthHandler) Health(w http.ResponseWriter, r *http.Request) {
 // Ready возвращает готовность приложения принимать трафик
 func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
 	// Проверяем подключение к БД
-	ctx, cancel := context.WithTimeout(r.Context(), 2*time.Second)
+	ctx, cancel := context.WithTimeout(r.Context(), healthCheckTimeout)
 	defer cancel()
 
 	if err := h.db.Ping(ctx); err != nil {
@@ -68,6 +174,11 @@ func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if _, down := h.monitor.UnavailableSince(); down {
+		http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		return
+	}
+
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte("OK"))
 }
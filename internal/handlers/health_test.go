@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type stubPinger struct {
+	err error
+}
+
+func (p stubPinger) Ping(context.Context) error {
+	return p.err
+}
+
+type stubMonitor struct {
+	since time.Time
+	down  bool
+}
+
+func (m stubMonitor) UnavailableSince() (time.Time, bool) {
+	return m.since, m.down
+}
+
+type stubAccrualMonitor struct {
+	last time.Time
+}
+
+func (m stubAccrualMonitor) LastAccrualSuccess() (time.Time, bool) {
+	return m.last, !m.last.IsZero()
+}
+
+func TestHealthHandler_Health(t *testing.T) {
+	since := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		pingErr        error
+		monitor        stubMonitor
+		expectedStatus int
+		expectedBody   HealthResponse
+	}{
+		{
+			name:           "Healthy",
+			expectedStatus: http.StatusOK,
+			expectedBody:   HealthResponse{Status: "ok", Database: "ok"},
+		},
+		{
+			name:           "Ping failed",
+			pingErr:        errors.New("connection refused"),
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   HealthResponse{Status: "degraded", Database: "unavailable"},
+		},
+		{
+			name:           "Degraded mode reported by monitor",
+			monitor:        stubMonitor{since: since, down: true},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   HealthResponse{Status: "degraded", Database: "unavailable", UnavailableSince: &since},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewHealthHandler(stubPinger{err: tt.pingErr}, tt.monitor, stubPinger{}, stubAccrualMonitor{}, zap.NewNop())
+
+			w := httptest.NewRecorder()
+			handler.Health(w, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var body HealthResponse
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+			assert.Equal(t, tt.expectedBody.Status, body.Status)
+			assert.Equal(t, tt.expectedBody.Database, body.Database)
+			if tt.expectedBody.UnavailableSince != nil {
+				require.NotNil(t, body.UnavailableSince)
+				assert.True(t, tt.expectedBody.UnavailableSince.Equal(*body.UnavailableSince))
+			} else {
+				assert.Nil(t, body.UnavailableSince)
+			}
+		})
+	}
+}
+
+func TestHealthHandler_Ready(t *testing.T) {
+	t.Run("Ready", func(t *testing.T) {
+		handler := NewHealthHandler(stubPinger{}, stubMonitor{}, stubPinger{}, stubAccrualMonitor{}, zap.NewNop())
+
+		w := httptest.NewRecorder()
+		handler.Ready(w, httptest.NewRequest(http.MethodGet, "/ready", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Not ready while degraded", func(t *testing.T) {
+		handler := NewHealthHandler(stubPinger{}, stubMonitor{since: time.Now(), down: true}, stubPinger{}, stubAccrualMonitor{}, zap.NewNop())
+
+		w := httptest.NewRecorder()
+		handler.Ready(w, httptest.NewRequest(http.MethodGet, "/ready", nil))
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	})
+}
+
+func TestHealthHandler_Dependencies(t *testing.T) {
+	lastPoll := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name            string
+		dbErr           error
+		accrualErr      error
+		expectedStatus  int
+		expectedDB      string
+		expectedAccrual string
+	}{
+		{
+			name:            "All dependencies available",
+			expectedStatus:  http.StatusOK,
+			expectedDB:      "ok",
+			expectedAccrual: "ok",
+		},
+		{
+			name:            "Accrual system unavailable",
+			accrualErr:      errors.New("connection refused"),
+			expectedStatus:  http.StatusServiceUnavailable,
+			expectedDB:      "ok",
+			expectedAccrual: "unavailable",
+		},
+		{
+			name:            "Database unavailable",
+			dbErr:           errors.New("connection refused"),
+			expectedStatus:  http.StatusServiceUnavailable,
+			expectedDB:      "unavailable",
+			expectedAccrual: "ok",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewHealthHandler(
+				stubPinger{err: tt.dbErr}, stubMonitor{},
+				stubPinger{err: tt.accrualErr}, stubAccrualMonitor{last: lastPoll},
+				zap.NewNop(),
+			)
+
+			w := httptest.NewRecorder()
+			handler.Dependencies(w, httptest.NewRequest(http.MethodGet, "/health/dependencies", nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var body DependenciesResponse
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+			assert.Equal(t, tt.expectedDB, body.Database.Status)
+			assert.Equal(t, tt.expectedAccrual, body.Accrual.Status)
+			require.NotNil(t, body.Accrual.LastSuccess)
+			assert.True(t, lastPoll.Equal(*body.Accrual.LastSuccess))
+		})
+	}
+
+	t.Run("No successful poll yet", func(t *testing.T) {
+		handler := NewHealthHandler(stubPinger{}, stubMonitor{}, stubPinger{}, stubAccrualMonitor{}, zap.NewNop())
+
+		w := httptest.NewRecorder()
+		handler.Dependencies(w, httptest.NewRequest(http.MethodGet, "/health/dependencies", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, w.Body.String(), "last_success")
+	})
+}
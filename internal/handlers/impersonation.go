@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"go.uber.org/zap"
+)
+
+// ImpersonationMiddleware записывает в журнал аудита каждый запрос, выполненный
+// администратором от имени пользователя. При readOnly такие запросы могут только читать:
+// изменяющие методы отклоняются с 403. Запросы по обычным токенам проходят без изменений.
+// Должен подключаться после AuthMiddleware.
+func ImpersonationMiddleware(readOnly bool, audit *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			impersonatorID, ok := GetImpersonatorID(r.Context())
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			defer func() {
+				userID, _ := GetUserID(r.Context())
+				requestID, _ := r.Context().Value(RequestIDKey).(string)
+				status := ww.Status()
+				if status == 0 {
+					status = http.StatusOK
+				}
+				audit.Info("impersonated request",
+					zap.String("request_id", requestID),
+					zap.Int64("impersonator_id", impersonatorID),
+					zap.Int64("user_id", userID),
+					zap.String("method", r.Method),
+					zap.String("route", routePattern(r)),
+					zap.Int("status", status),
+				)
+			}()
+
+			if readOnly && !isSafeMethod(r.Method) {
+				writeJSONError(ww, r, http.StatusForbidden, "write operations are not allowed while impersonating")
+				return
+			}
+			next.ServeHTTP(ww, r)
+		})
+	}
+}
+
+// isSafeMethod сообщает, что метод только читает данные
+func isSafeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// Impersonator выдает администратору токен для работы от имени пользователя.
+type Impersonator interface {
+	Impersonate(ctx context.Context, adminID, userID int64) (*domain.Impersonation, error)
+}
+
+// ImpersonationHandler обрабатывает запросы администраторов на работу от имени пользователя
+type ImpersonationHandler struct {
+	service Impersonator
+	logger  *zap.Logger
+}
+
+// NewImpersonationHandler создает новый ImpersonationHandler
+func NewImpersonationHandler(service Impersonator, logger *zap.Logger) *ImpersonationHandler {
+	return &ImpersonationHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// ImpersonationResponse описывает выданный токен; сам токен передается в заголовке Authorization
+type ImpersonationResponse struct {
+	UserID    int64     `json:"user_id"`
+	Login     string    `json:"login"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Impersonate выдает токен пользователя из пути от имени текущего администратора
+func (h *ImpersonationHandler) Impersonate(w http.ResponseWriter, r *http.Request) {
+	adminID, ok := GetUserID(r.Context())
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	userID, err := strconv.ParseInt(chi.URLParam(r, "userID"), 10, 64)
+	if err != nil || userID <= 0 {
+		writeJSONError(w, r, http.StatusBadRequest, "invalid user id")
+		return
+	}
+
+	impersonation, err := h.service.Impersonate(r.Context(), adminID, userID)
+	switch {
+	case err == nil:
+	case errors.Is(err, domain.ErrInvalidInput):
+		writeJSONError(w, r, http.StatusBadRequest, "cannot impersonate yourself")
+		return
+	case errors.Is(err, domain.ErrUserNotFound):
+		writeJSONError(w, r, http.StatusNotFound, "user not found")
+		return
+	case errors.Is(err, domain.ErrCannotImpersonate):
+		writeJSONError(w, r, http.StatusForbidden, "admins cannot be impersonated")
+		return
+	default:
+		h.logger.Error("failed to impersonate user", zap.Int64("user_id", userID), zap.Error(err))
+		writeInternalError(w, err)
+		return
+	}
+
+	w.Header().Set("Authorization", "Bearer "+impersonation.Token)
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	response := ImpersonationResponse{
+		UserID:    impersonation.UserID,
+		Login:     impersonation.Login,
+		ExpiresAt: impersonation.ExpiresAt,
+	}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error("failed to encode impersonation response", zap.Error(err))
+	}
+}
@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	domainmocks "github.com/avc/loyalty-system-diploma/internal/domain/mocks"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestImpersonationHandler_Impersonate(t *testing.T) {
+	expiresAt := time.Date(2024, 3, 1, 10, 15, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		userID         string
+		setupMock      func(*domainmocks.ImpersonatorMock)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "Issued",
+			userID: "7",
+			setupMock: func(m *domainmocks.ImpersonatorMock) {
+				m.EXPECT().Impersonate(mock.Anything, int64(1), int64(7)).Return(&domain.Impersonation{
+					Token: "token", UserID: 7, Login: "alice", ImpersonatorID: 1, ExpiresAt: expiresAt,
+				}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"user_id":7,"login":"alice","expires_at":"2024-03-01T10:15:00Z"}`,
+		},
+		{
+			name:           "Invalid user id",
+			userID:         "alice",
+			setupMock:      func(m *domainmocks.ImpersonatorMock) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid user id"}`,
+		},
+		{
+			name:   "Self",
+			userID: "1",
+			setupMock: func(m *domainmocks.ImpersonatorMock) {
+				m.EXPECT().Impersonate(mock.Anything, int64(1), int64(1)).Return(nil, domain.ErrInvalidInput).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"cannot impersonate yourself"}`,
+		},
+		{
+			name:   "User not found",
+			userID: "7",
+			setupMock: func(m *domainmocks.ImpersonatorMock) {
+				m.EXPECT().Impersonate(mock.Anything, int64(1), int64(7)).Return(nil, domain.ErrUserNotFound).Once()
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":"user not found"}`,
+		},
+		{
+			name:   "Admin",
+			userID: "2",
+			setupMock: func(m *domainmocks.ImpersonatorMock) {
+				m.EXPECT().Impersonate(mock.Anything, int64(1), int64(2)).Return(nil, domain.ErrCannotImpersonate).Once()
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   `{"error":"admins cannot be impersonated"}`,
+		},
+		{
+			name:   "Internal error",
+			userID: "7",
+			setupMock: func(m *domainmocks.ImpersonatorMock) {
+				m.EXPECT().Impersonate(mock.Anything, int64(1), int64(7)).Return(nil, errors.New("boom")).Once()
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := domainmocks.NewImpersonatorMock(t)
+			tt.setupMock(svc)
+			r := chi.NewRouter()
+			r.Post("/api/admin/impersonate/{userID}", NewImpersonationHandler(svc, zap.NewNop()).Impersonate)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/admin/impersonate/"+tt.userID, nil)
+			req = req.WithContext(context.WithValue(req.Context(), UserIDKey, int64(1)))
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+			if tt.expectedStatus == http.StatusOK {
+				assert.Equal(t, "Bearer token", w.Header().Get("Authorization"))
+			}
+		})
+	}
+}
+
+func TestImpersonationMiddleware(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	})
+	impersonated := func(method string) *http.Request {
+		req := httptest.NewRequest(method, "/api/user/orders", nil)
+		ctx := context.WithValue(req.Context(), UserIDKey, int64(7))
+		ctx = context.WithValue(ctx, ImpersonatorIDKey, int64(1))
+		return req.WithContext(ctx)
+	}
+
+	t.Run("Regular token is not audited", func(t *testing.T) {
+		core, logs := observer.New(zap.InfoLevel)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/user/orders", nil)
+		ImpersonationMiddleware(true, zap.New(core))(ok).ServeHTTP(w, req.WithContext(context.WithValue(req.Context(), UserIDKey, int64(7))))
+
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		assert.Zero(t, logs.Len())
+	})
+
+	t.Run("Read audited", func(t *testing.T) {
+		core, logs := observer.New(zap.InfoLevel)
+		w := httptest.NewRecorder()
+		ImpersonationMiddleware(true, zap.New(core))(ok).ServeHTTP(w, impersonated(http.MethodGet))
+
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		require.Equal(t, 1, logs.Len())
+		fields := logs.All()[0].ContextMap()
+		assert.Equal(t, int64(1), fields["impersonator_id"])
+		assert.Equal(t, int64(7), fields["user_id"])
+		assert.Equal(t, "GET", fields["method"])
+		assert.Equal(t, int64(http.StatusAccepted), fields["status"])
+	})
+
+	t.Run("Write rejected in read-only mode", func(t *testing.T) {
+		core, logs := observer.New(zap.InfoLevel)
+		w := httptest.NewRecorder()
+		ImpersonationMiddleware(true, zap.New(core))(ok).ServeHTTP(w, impersonated(http.MethodPost))
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		require.Equal(t, 1, logs.Len())
+		assert.Equal(t, int64(http.StatusForbidden), logs.All()[0].ContextMap()["status"])
+	})
+
+	t.Run("Write allowed", func(t *testing.T) {
+		core, logs := observer.New(zap.InfoLevel)
+		w := httptest.NewRecorder()
+		ImpersonationMiddleware(false, zap.New(core))(ok).ServeHTTP(w, impersonated(http.MethodPost))
+
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		assert.Equal(t, 1, logs.Len())
+	})
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+// RequestTracker считает выполняемые HTTP запросы, чтобы при остановке сервера
+// было видно, сколько запросов завершилось, а сколько было прервано
+type RequestTracker struct {
+	inFlight atomic.Int64
+}
+
+// NewRequestTracker создает новый RequestTracker
+func NewRequestTracker() *RequestTracker {
+	return &RequestTracker{}
+}
+
+// Middleware учитывает запрос от начала до конца обработки
+func (t *RequestTracker) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.inFlight.Add(1)
+		defer t.inFlight.Add(-1)
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// InFlight возвращает число выполняемых запросов
+func (t *RequestTracker) InFlight() int64 {
+	return t.inFlight.Load()
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"go.uber.org/zap"
+)
+
+// maxOrderStatusBodySize ограничивает тело запроса статусов: 500 номеров по 64 символа с запасом
+const maxOrderStatusBodySize = 64 << 10
+
+// OrderStatusService определяет получение заказов по списку номеров.
+type OrderStatusService interface {
+	GetOrderStatuses(ctx context.Context, numbers []string) ([]*domain.Order, error)
+}
+
+// InternalOrdersHandler обрабатывает запросы внутренних сервисов к заказам
+type InternalOrdersHandler struct {
+	service OrderStatusService
+	logger  *zap.Logger
+}
+
+// NewInternalOrdersHandler создает новый InternalOrdersHandler
+func NewInternalOrdersHandler(service OrderStatusService, logger *zap.Logger) *InternalOrdersHandler {
+	return &InternalOrdersHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// orderStatusRequest - номера заказов, статусы которых нужны сервису
+type orderStatusRequest struct {
+	Orders []string `json:"orders"`
+}
+
+// Statuses возвращает статусы и начисления заказов из тела запроса одним ответом
+func (h *InternalOrdersHandler) Statuses(w http.ResponseWriter, r *http.Request) {
+	var req orderStatusRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxOrderStatusBodySize)).Decode(&req); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeJSONError(w, r, http.StatusRequestEntityTooLarge, "request body is too large")
+			return
+		}
+		writeJSONError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	orders, err := h.service.GetOrderStatuses(r.Context(), req.Orders)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidInput) {
+			writeJSONError(w, r, http.StatusBadRequest,
+				fmt.Sprintf("orders must contain 1 to %d valid order numbers", domain.MaxOrderStatusBatch))
+			return
+		}
+		h.logger.Error("failed to get order statuses", zap.Error(err))
+		writeInternalError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newOrderStatusesResponse(req.Orders, orders)); err != nil {
+		h.logger.Error("failed to encode order statuses response", zap.Error(err))
+	}
+}
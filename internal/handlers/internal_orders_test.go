@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	domainmocks "github.com/avc/loyalty-system-diploma/internal/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestInternalOrdersHandler_Statuses(t *testing.T) {
+	uploadedAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	accrual := 500.0
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(*domainmocks.OrderStatusServiceMock)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "Found and missing orders in request order",
+			body: `{"orders":["79927398713","12345678903","2377225624","12345678903"]}`,
+			setupMock: func(m *domainmocks.OrderStatusServiceMock) {
+				m.EXPECT().GetOrderStatuses(mock.Anything, []string{"79927398713", "12345678903", "2377225624", "12345678903"}).
+					Return([]*domain.Order{
+						{Number: "12345678903", UserID: 7, Status: domain.OrderStatusProcessed, Accrual: &accrual, UploadedAt: uploadedAt},
+						{Number: "79927398713", UserID: 8, Status: domain.OrderStatusProcessing, UploadedAt: uploadedAt},
+					}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"orders":[
+				{"number":"79927398713","status":"PROCESSING","uploaded_at":"2024-03-01T10:00:00Z"},
+				{"number":"12345678903","status":"PROCESSED","accrual":500,"uploaded_at":"2024-03-01T10:00:00Z"}
+			],"not_found":["2377225624"]}`,
+		},
+		{
+			name: "Nothing found",
+			body: `{"orders":["2377225624"]}`,
+			setupMock: func(m *domainmocks.OrderStatusServiceMock) {
+				m.EXPECT().GetOrderStatuses(mock.Anything, []string{"2377225624"}).Return([]*domain.Order{}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"orders":[],"not_found":["2377225624"]}`,
+		},
+		{
+			name:           "Invalid body",
+			body:           `{"orders":"12345678903"}`,
+			setupMock:      func(m *domainmocks.OrderStatusServiceMock) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid request body"}`,
+		},
+		{
+			name: "Invalid batch",
+			body: `{"orders":[]}`,
+			setupMock: func(m *domainmocks.OrderStatusServiceMock) {
+				m.EXPECT().GetOrderStatuses(mock.Anything, []string{}).
+					Return(nil, fmt.Errorf("order service: %w", domain.ErrInvalidInput)).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"orders must contain 1 to 500 valid order numbers"}`,
+		},
+		{
+			name: "Service error",
+			body: `{"orders":["12345678903"]}`,
+			setupMock: func(m *domainmocks.OrderStatusServiceMock) {
+				m.EXPECT().GetOrderStatuses(mock.Anything, []string{"12345678903"}).Return(nil, errors.New("db error")).Once()
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := domainmocks.NewOrderStatusServiceMock(t)
+			tt.setupMock(service)
+			handler := NewInternalOrdersHandler(service, zap.NewNop())
+
+			req := httptest.NewRequest(http.MethodPost, "/api/internal/orders/status", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			handler.Statuses(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestInternalOrdersHandler_Statuses_BodyTooLarge(t *testing.T) {
+	handler := NewInternalOrdersHandler(domainmocks.NewOrderStatusServiceMock(t), zap.NewNop())
+
+	body := `{"orders":["` + strings.Repeat("1", maxOrderStatusBodySize) + `"]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/internal/orders/status", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	handler.Statuses(w, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"net/http"
+	"net/netip"
+
+	"go.uber.org/zap"
+)
+
+// IPFilterConfig содержит правила доступа к группе маршрутов по адресу клиента
+type IPFilterConfig struct {
+	Allowed []netip.Prefix // Разрешенные сети (пусто - разрешены все, кроме запрещенных)
+	Denied  []netip.Prefix // Запрещенные сети, имеют приоритет над разрешенными
+}
+
+// IPFilterMiddleware пропускает запросы только с разрешенных адресов.
+// Адрес берется из RemoteAddr, поэтому за прокси должен подключаться после RealIPMiddleware.
+// Подключается к группам маршрутов, например административной.
+func IPFilterMiddleware(cfg IPFilterConfig, logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if len(cfg.Allowed) == 0 && len(cfg.Denied) == 0 {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			addr, ok := parseRemoteAddr(r.RemoteAddr)
+			if !ok || !ipAllowed(addr, cfg) {
+				logger.Warn("request rejected by IP filter",
+					zap.String("remote_addr", r.RemoteAddr),
+					zap.String("path", r.URL.Path),
+				)
+				writeJSONError(w, r, http.StatusForbidden, "access from this address is not allowed")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ipAllowed проверяет адрес по спискам запрещенных и разрешенных сетей
+func ipAllowed(addr netip.Addr, cfg IPFilterConfig) bool {
+	if containsAddr(cfg.Denied, addr) {
+		return false
+	}
+	return len(cfg.Allowed) == 0 || containsAddr(cfg.Allowed, addr)
+}
+
+// containsAddr проверяет, входит ли адрес в одну из сетей
+func containsAddr(prefixes []netip.Prefix, addr netip.Addr) bool {
+	for _, prefix := range prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func prefixes(values ...string) []netip.Prefix {
+	result := make([]netip.Prefix, len(values))
+	for i, value := range values {
+		result[i] = netip.MustParsePrefix(value)
+	}
+	return result
+}
+
+func TestIPFilterMiddleware(t *testing.T) {
+	cfg := IPFilterConfig{
+		Allowed: prefixes("192.168.0.0/16", "2001:db8::/32"),
+		Denied:  prefixes("192.168.10.0/24"),
+	}
+	handler := IPFilterMiddleware(cfg, zap.NewNop())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		want       int
+	}{
+		{"allowed network", "192.168.1.1:5000", http.StatusOK},
+		{"address set by RealIPMiddleware", "192.168.1.1", http.StatusOK},
+		{"allowed ipv6 network", "[2001:db8::1]:5000", http.StatusOK},
+		{"denied takes precedence", "192.168.10.1:5000", http.StatusForbidden},
+		{"outside allowed networks", "203.0.113.5:5000", http.StatusForbidden},
+		{"unknown client", "pipe", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/admin/config", nil)
+			req.RemoteAddr = tt.remoteAddr
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.want, w.Code)
+		})
+	}
+}
+
+func TestIPFilterMiddleware_Disabled(t *testing.T) {
+	handler := IPFilterMiddleware(IPFilterConfig{}, zap.NewNop())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/config", nil)
+	req.RemoteAddr = "pipe"
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/avc/loyalty-system-diploma/internal/metrics"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// loadShedRetryAfter - через сколько секунд клиенту стоит повторить отклоненный запрос
+const loadShedRetryAfter = "1"
+
+// RouteWeigher возвращает шаблон маршрута запроса и его вес в лимите одновременных запросов
+type RouteWeigher func(r *http.Request) (route string, weight int)
+
+// LoadShedConfig содержит настройки ограничения одновременных запросов
+type LoadShedConfig struct {
+	MaxInFlight int          // Суммарный вес выполняемых запросов (0 - ограничение отключено)
+	Weigher     RouteWeigher // nil - вес каждого запроса 1
+}
+
+// LoadShedMiddleware сразу отвечает 503, если суммарный вес выполняемых запросов превысит лимит,
+// чтобы при всплеске нагрузки запросы не копились в ожидании соединений с БД до таймаутов.
+// Запросы с весом 0 (проверки состояния, long polling) не ограничиваются.
+func LoadShedMiddleware(cfg LoadShedConfig, m *metrics.Metrics, logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if cfg.MaxInFlight <= 0 {
+			return next
+		}
+		weigher := cfg.Weigher
+		if weigher == nil {
+			weigher = func(*http.Request) (string, int) { return "", 1 }
+		}
+		limit := int64(cfg.MaxInFlight)
+		var inFlight atomic.Int64
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route, weight := weigher(r)
+			if weight <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// Запрос тяжелее всего лимита пропускается, только когда других запросов нет
+			total := inFlight.Add(int64(weight))
+			if total > limit && total != int64(weight) {
+				m.ObserveInFlightRequests(inFlight.Add(-int64(weight)))
+				m.ObserveShedRequest(route)
+				logger.Warn("request shed due to overload",
+					zap.String("method", r.Method),
+					zap.String("route", route),
+					zap.Int("weight", weight),
+					zap.Int64("in_flight", total-int64(weight)),
+				)
+				w.Header().Set("Retry-After", loadShedRetryAfter)
+				writeJSONError(w, r, http.StatusServiceUnavailable, "server is overloaded")
+				return
+			}
+			m.ObserveInFlightRequests(total)
+			defer func() {
+				m.ObserveInFlightRequests(inFlight.Add(-int64(weight)))
+			}()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RouteWeights определяет вес запроса по шаблону маршрута роутера.
+// Ключ - метод и шаблон, например "GET /api/user/orders". Остальные маршруты весят 1.
+// HEAD, обслуживаемый GET обработчиком (MethodsMiddleware), весит как GET.
+func RouteWeights(routes chi.Routes, weights map[string]int) RouteWeigher {
+	return func(r *http.Request) (string, int) {
+		method := r.Method
+		if current := chi.RouteContext(r.Context()); current != nil && current.RouteMethod != "" {
+			method = current.RouteMethod
+		}
+		rctx := chi.NewRouteContext()
+		if !routes.Match(rctx, method, r.URL.Path) {
+			return "unknown", 1
+		}
+		route := rctx.RoutePattern()
+		if weight, ok := weights[method+" "+route]; ok {
+			return route, weight
+		}
+		return route, 1
+	}
+}
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// blockingHandler держит запросы, пока не закрыт release, и сообщает о начале каждого в started
+func blockingHandler(started chan<- struct{}, release <-chan struct{}) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-release
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+func TestLoadShedMiddleware(t *testing.T) {
+	weights := func(r *http.Request) (string, int) {
+		switch r.URL.Path {
+		case "/heavy":
+			return "/heavy", 2
+		case "/health":
+			return "/health", 0
+		}
+		return "/light", 1
+	}
+
+	started := make(chan struct{}, 10)
+	release := make(chan struct{})
+	handler := LoadShedMiddleware(LoadShedConfig{MaxInFlight: 2, Weigher: weights}, nil, zap.NewNop())(
+		blockingHandler(started, release))
+
+	serve := func(path string) <-chan int {
+		code := make(chan int, 1)
+		go func() {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+			code <- w.Code
+		}()
+		return code
+	}
+
+	// Тяжелый запрос занимает весь лимит
+	heavy := serve("/heavy")
+	<-started
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/light", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+	assert.JSONEq(t, `{"error":"server is overloaded"}`, w.Body.String())
+
+	// Запросы с весом 0 не ограничиваются
+	health := serve("/health")
+	<-started
+
+	close(release)
+	assert.Equal(t, http.StatusOK, <-heavy)
+	assert.Equal(t, http.StatusOK, <-health)
+
+	// После завершения запросов лимит освобождается
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/light", nil))
+	<-started
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestLoadShedMiddleware_HeavierThanLimit(t *testing.T) {
+	weigher := func(*http.Request) (string, int) { return "/export", 5 }
+	handler := LoadShedMiddleware(LoadShedConfig{MaxInFlight: 2, Weigher: weigher}, nil, zap.NewNop())(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/export", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestLoadShedMiddleware_Disabled(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	handler := LoadShedMiddleware(LoadShedConfig{}, nil, zap.NewNop())(next)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRouteWeights(t *testing.T) {
+	r := chi.NewRouter()
+	r.Get("/api/user/orders", func(w http.ResponseWriter, r *http.Request) {})
+	r.Get("/api/user/orders/{id}", func(w http.ResponseWriter, r *http.Request) {})
+	r.Post("/api/user/orders", func(w http.ResponseWriter, r *http.Request) {})
+
+	weigher := RouteWeights(r, map[string]int{"GET /api/user/orders": 3})
+
+	tests := []struct {
+		method     string
+		path       string
+		wantRoute  string
+		wantWeight int
+	}{
+		{http.MethodGet, "/api/user/orders", "/api/user/orders", 3},
+		{http.MethodPost, "/api/user/orders", "/api/user/orders", 1},
+		{http.MethodGet, "/api/user/orders/01890a5d", "/api/user/orders/{id}", 1},
+		{http.MethodGet, "/unknown", "unknown", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			route, weight := weigher(httptest.NewRequest(tt.method, tt.path, nil))
+			assert.Equal(t, tt.wantRoute, route)
+			assert.Equal(t, tt.wantWeight, weight)
+		})
+	}
+
+	// HEAD, переадресованный на GET обработчик, весит как GET
+	rctx := chi.NewRouteContext()
+	rctx.RouteMethod = http.MethodGet
+	req := httptest.NewRequest(http.MethodHead, "/api/user/orders", nil)
+	route, weight := weigher(req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx)))
+	assert.Equal(t, "/api/user/orders", route)
+	assert.Equal(t, 3, weight)
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/avc/loyalty-system-diploma/internal/locale"
+)
+
+// LocalePreferences возвращает язык, выбранный пользователем
+type LocalePreferences interface {
+	PreferredLocale(ctx context.Context, userID int64) (locale.Locale, bool)
+}
+
+// LocaleMiddleware определяет язык ответа по заголовку Accept-Language.
+// Ответ зависит от заголовка, поэтому он добавляется в Vary для кешей
+func LocaleMiddleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Language")
+			l := locale.FromAcceptLanguage(r.Header.Get("Accept-Language"))
+			next.ServeHTTP(w, r.WithContext(locale.With(r.Context(), l)))
+		})
+	}
+}
+
+// UserLocaleMiddleware заменяет язык из Accept-Language языком, выбранным пользователем.
+// Выбор загружается только при первом обращении к языку ответа.
+// Должен подключаться после AuthMiddleware.
+func UserLocaleMiddleware(prefs LocalePreferences) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := GetUserID(r.Context())
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx := r.Context()
+			lookup := func() (locale.Locale, bool) {
+				return prefs.PreferredLocale(ctx, userID)
+			}
+			next.ServeHTTP(w, r.WithContext(locale.WithLookup(ctx, lookup)))
+		})
+	}
+}
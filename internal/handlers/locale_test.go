@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	domainmocks "github.com/avc/loyalty-system-diploma/internal/domain/mocks"
+	"github.com/avc/loyalty-system-diploma/internal/locale"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLocaleMiddleware(t *testing.T) {
+	var got locale.Locale
+	handler := LocaleMiddleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = locale.From(r.Context())
+		writeJSONError(w, r, http.StatusNotFound, "order not found")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Language", "ru-RU,ru;q=0.9,en;q=0.8")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, locale.Russian, got)
+	assert.Equal(t, "Accept-Language", w.Header().Get("Vary"))
+	assert.JSONEq(t, `{"error":"заказ не найден"}`, w.Body.String())
+}
+
+func TestUserLocaleMiddleware(t *testing.T) {
+	t.Run("User choice overrides Accept-Language", func(t *testing.T) {
+		prefs := domainmocks.NewLocalePreferencesMock(t)
+		prefs.EXPECT().PreferredLocale(mock.Anything, int64(1)).Return(locale.Russian, true).Once()
+
+		var got locale.Locale
+		handler := UserLocaleMiddleware(prefs)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = locale.From(r.Context())
+			// Повторное обращение не загружает выбор снова
+			locale.From(r.Context())
+		}))
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		ctx := context.WithValue(locale.With(req.Context(), locale.English), UserIDKey, int64(1))
+		handler.ServeHTTP(httptest.NewRecorder(), req.WithContext(ctx))
+
+		assert.Equal(t, locale.Russian, got)
+	})
+
+	t.Run("Choice is not loaded until needed", func(t *testing.T) {
+		prefs := domainmocks.NewLocalePreferencesMock(t)
+		handler := UserLocaleMiddleware(prefs)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		handler.ServeHTTP(httptest.NewRecorder(), req.WithContext(context.WithValue(req.Context(), UserIDKey, int64(1))))
+	})
+
+	t.Run("Without choice Accept-Language is kept", func(t *testing.T) {
+		prefs := domainmocks.NewLocalePreferencesMock(t)
+		prefs.EXPECT().PreferredLocale(mock.Anything, int64(1)).Return("", false).Once()
+
+		var got locale.Locale
+		handler := UserLocaleMiddleware(prefs)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = locale.From(r.Context())
+		}))
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		ctx := context.WithValue(locale.With(req.Context(), locale.Russian), UserIDKey, int64(1))
+		handler.ServeHTTP(httptest.NewRecorder(), req.WithContext(ctx))
+
+		assert.Equal(t, locale.Russian, got)
+	})
+}
@@ -2,11 +2,17 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
-	"github.com/avc/loyalty-system-diploma/internal/utils/jwt"
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/avc/loyalty-system-diploma/internal/logctx"
+	"github.com/avc/loyalty-system-diploma/internal/metrics"
+	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
@@ -15,41 +21,110 @@ import (
 type contextKey string
 
 const (
-	UserIDKey    contextKey = "user_id"
-	RequestIDKey contextKey = "request_id"
+	UserIDKey         contextKey = "user_id"
+	RequestIDKey      contextKey = "request_id"
+	TokenExpiresAtKey contextKey = "token_expires_at"
+	ImpersonatorIDKey contextKey = "impersonator_id"
+
+	requestMetaKey contextKey = "request_meta"
 )
 
-// AuthMiddleware проверяет JWT токен и извлекает user ID
-func AuthMiddleware(jwtManager *jwt.Manager) func(http.Handler) http.Handler {
+// requestMeta хранит данные, которые становятся известны во вложенных middleware
+// (например, user ID после аутентификации), но нужны внешним
+type requestMeta struct {
+	userID atomic.Int64
+}
+
+// TokenValidator проверяет токен доступа и возвращает ID пользователя.
+type TokenValidator interface {
+	ValidateToken(ctx context.Context, token string) (*domain.AccessToken, error)
+}
+
+// AuthMiddleware проверяет токен доступа и извлекает user ID.
+// Отклоненные токены учитываются в метриках и логе по причине, содержимое токена не пишется.
+// m может быть nil.
+func AuthMiddleware(validator TokenValidator, m *metrics.Metrics) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			authHeader := r.Header.Get("Authorization")
-			if authHeader == "" {
+			token, ok := bearerToken(r)
+			if !ok {
 				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
 				return
 			}
 
-			// Извлекаем токен из заголовка "Bearer <token>"
-			parts := strings.Split(authHeader, " ")
-			if len(parts) != 2 || parts[0] != "Bearer" {
+			access, err := validator.ValidateToken(r.Context(), token)
+			if errors.Is(err, domain.ErrInvalidToken) {
+				reason := tokenFailureReason(err)
+				m.ObserveTokenFailure(reason)
+				logctx.From(r.Context()).Info("access token rejected",
+					zap.String("reason", reason),
+					zap.String("path", r.URL.Path),
+					zap.String("user_agent", r.UserAgent()),
+				)
 				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
 				return
 			}
-
-			token := parts[1]
-			userID, err := jwtManager.Validate(token)
 			if err != nil {
-				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				// Токен не удалось проверить из-за сбоя, а не потому что он недействителен
+				writeInternalError(w, err)
 				return
 			}
 
-			// Добавляем user ID в контекст
+			// Добавляем user ID в контекст и в контекстный логгер
+			userID := access.UserID
 			ctx := context.WithValue(r.Context(), UserIDKey, userID)
+			ctx = logctx.WithFields(ctx, zap.Int64("user_id", userID))
+			if meta, ok := ctx.Value(requestMetaKey).(*requestMeta); ok {
+				meta.userID.Store(userID)
+			}
+			if access.ImpersonatorID != 0 {
+				ctx = context.WithValue(ctx, ImpersonatorIDKey, access.ImpersonatorID)
+				ctx = logctx.WithFields(ctx, zap.Int64("impersonator_id", access.ImpersonatorID))
+			}
+
+			// Клиент заранее узнает, когда обновить токен
+			if !access.ExpiresAt.IsZero() {
+				ctx = context.WithValue(ctx, TokenExpiresAtKey, access.ExpiresAt)
+				w.Header().Set(tokenExpiresInHeader, strconv.FormatInt(tokenExpiresIn(access.ExpiresAt, time.Now()), 10))
+			}
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
 
+// tokenExpiresInHeader - секунды до истечения токена в ответах на аутентифицированные запросы
+const tokenExpiresInHeader = "X-Token-Expires-In"
+
+// tokenExpiresIn возвращает целые секунды до истечения токена, не меньше нуля
+func tokenExpiresIn(expiresAt, now time.Time) int64 {
+	return max(int64(expiresAt.Sub(now)/time.Second), 0)
+}
+
+// tokenFailureReason возвращает причину отказа в токене для метки метрики:
+// истекший срок чаще всего означает сбитые часы клиента, чужая подпись - другой секрет
+func tokenFailureReason(err error) string {
+	switch {
+	case errors.Is(err, domain.ErrTokenExpired):
+		return "expired"
+	case errors.Is(err, domain.ErrTokenMalformed):
+		return "malformed"
+	case errors.Is(err, domain.ErrTokenSignature):
+		return "signature"
+	case errors.Is(err, domain.ErrTokenRevoked):
+		return "revoked"
+	}
+	return "invalid"
+}
+
+// bearerToken извлекает токен из заголовка "Authorization: Bearer <token>"
+func bearerToken(r *http.Request) (string, bool) {
+	parts := strings.Split(r.Header.Get("Authorization"), " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		return "", false
+	}
+	return parts[1], true
+}
+
 // RequestIDMiddleware генерирует уникальный request ID
 func RequestIDMiddleware() func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -62,8 +137,22 @@ func RequestIDMiddleware() func(http.Handler) http.Handler {
 	}
 }
 
-// LoggingMiddleware логирует HTTP запросы
-func LoggingMiddleware(logger *zap.Logger) func(http.Handler) http.Handler {
+// ContextLoggerMiddleware кладет в контекст запроса логгер с request ID.
+// Сервисы и репозитории получают его через logctx.From.
+// Должен подключаться после RequestIDMiddleware.
+func ContextLoggerMiddleware(logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestID, _ := r.Context().Value(RequestIDKey).(string)
+			ctx := logctx.With(r.Context(), logger.With(zap.String("request_id", requestID)))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// LoggingMiddleware логирует HTTP запросы и учитывает их в метриках.
+// В метки попадает шаблон маршрута chi, сырой путь пишется только в лог.
+func LoggingMiddleware(logger *zap.Logger, m *metrics.Metrics) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			start := time.Now()
@@ -72,13 +161,24 @@ func LoggingMiddleware(logger *zap.Logger) func(http.Handler) http.Handler {
 			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
 
 			defer func() {
+				duration := time.Since(start)
+				route := routePattern(r)
+				status := ww.Status()
+				if status == 0 {
+					// Обработчик ничего не записал, net/http ответит 200
+					status = http.StatusOK
+				}
+				m.ObserveHTTPRequest(r.Method, route, status, duration)
+
 				requestID, _ := r.Context().Value(RequestIDKey).(string)
 				logger.Info("HTTP request",
 					zap.String("request_id", requestID),
 					zap.String("method", r.Method),
 					zap.String("path", r.URL.Path),
-					zap.Int("status", ww.Status()),
-					zap.Duration("duration", time.Since(start)),
+					zap.String("route", route),
+					zap.String("client_ip", clientAddr(r)),
+					zap.Int("status", status),
+					zap.Duration("duration", duration),
 				)
 			}()
 
@@ -87,6 +187,56 @@ func LoggingMiddleware(logger *zap.Logger) func(http.Handler) http.Handler {
 	}
 }
 
+// SlowRequestMiddleware логирует предупреждение и увеличивает счетчик,
+// если обработка запроса заняла больше threshold. При threshold <= 0 отключен.
+func SlowRequestMiddleware(logger *zap.Logger, threshold time.Duration, m *metrics.Metrics) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if threshold <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			meta := &requestMeta{}
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+			next.ServeHTTP(ww, r.WithContext(context.WithValue(r.Context(), requestMetaKey, meta)))
+
+			duration := time.Since(start)
+			if duration < threshold {
+				return
+			}
+
+			route := routePattern(r)
+			m.ObserveSlowRequest(r.Method, route)
+
+			requestID, _ := r.Context().Value(RequestIDKey).(string)
+			fields := []zap.Field{
+				zap.String("request_id", requestID),
+				zap.String("method", r.Method),
+				zap.String("route", route),
+				zap.Int("status", ww.Status()),
+				zap.Duration("duration", duration),
+				zap.Duration("threshold", threshold),
+			}
+			if userID := meta.userID.Load(); userID != 0 {
+				fields = append(fields, zap.Int64("user_id", userID))
+			}
+			logger.Warn("slow HTTP request", fields...)
+		})
+	}
+}
+
+// routePattern возвращает шаблон маршрута chi (например, /api/user/orders).
+// Сырой путь не используется, чтобы не раздувать кардинальность меток.
+func routePattern(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		if pattern := rctx.RoutePattern(); pattern != "" {
+			return pattern
+		}
+	}
+	return "unknown"
+}
+
 // RecoveryMiddleware обрабатывает паники
 func RecoveryMiddleware(logger *zap.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -112,3 +262,15 @@ func GetUserID(ctx context.Context) (int64, bool) {
 	userID, ok := ctx.Value(UserIDKey).(int64)
 	return userID, ok
 }
+
+// GetImpersonatorID возвращает администратора, если запрос выполняется от имени пользователя
+func GetImpersonatorID(ctx context.Context) (int64, bool) {
+	impersonatorID, ok := ctx.Value(ImpersonatorIDKey).(int64)
+	return impersonatorID, ok
+}
+
+// GetTokenExpiresAt возвращает срок действия токена запроса, если он ограничен
+func GetTokenExpiresAt(ctx context.Context) (time.Time, bool) {
+	expiresAt, ok := ctx.Value(TokenExpiresAtKey).(time.Time)
+	return expiresAt, ok
+}
@@ -5,9 +5,18 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	domainmocks "github.com/avc/loyalty-system-diploma/internal/domain/mocks"
+	"github.com/avc/loyalty-system-diploma/internal/logctx"
+	"github.com/avc/loyalty-system-diploma/internal/metrics"
+	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 func TestRequestIDMiddleware(t *testing.T) {
@@ -31,20 +40,117 @@ func TestRequestIDMiddleware(t *testing.T) {
 }
 
 func TestLoggingMiddleware(t *testing.T) {
-	logger, _ := zap.NewDevelopment()
-	middleware := LoggingMiddleware(logger)
+	core, logs := observer.New(zap.DebugLevel)
+	m := metrics.New()
 
-	handler := middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
+	r := chi.NewRouter()
+	r.Use(LoggingMiddleware(zap.New(core), m))
+	r.Get("/api/user/orders/{number}", func(w http.ResponseWriter, r *http.Request) {
 		w.Write([]byte("OK"))
-	}))
+	})
+
+	for _, number := range []string{"12345678903", "79927398713"} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/user/orders/"+number, nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/missing/1", nil))
+
+	entries := logs.All()
+	require.Len(t, entries, 3)
+	fields := entries[0].ContextMap()
+	assert.Equal(t, "/api/user/orders/12345678903", fields["path"])
+	assert.Equal(t, "/api/user/orders/{number}", fields["route"])
+	assert.Equal(t, int64(http.StatusOK), fields["status"])
+	assert.Equal(t, "unknown", entries[2].ContextMap()["route"])
+
+	// Разные номера заказов попадают в один ряд метрики
+	body := scrapeMetrics(t, m)
+	assert.Contains(t, body, `gophermart_http_requests_total{method="GET",route="/api/user/orders/{number}",status="200"} 2`)
+	assert.Contains(t, body, `gophermart_http_requests_total{method="GET",route="unknown",status="404"} 1`)
+	assert.NotContains(t, body, "12345678903")
+}
+
+// scrapeMetrics возвращает метрики в текстовом формате Prometheus
+func scrapeMetrics(t *testing.T, m *metrics.Metrics) string {
+	t.Helper()
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	return w.Body.String()
+}
+
+func TestContextLoggerMiddleware(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	chain := RequestIDMiddleware()(ContextLoggerMiddleware(zap.New(core))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logctx.From(r.Context()).Info("from service")
+		w.WriteHeader(http.StatusOK)
+	})))
 
 	req := httptest.NewRequest(http.MethodGet, "/test", nil)
 	w := httptest.NewRecorder()
 
-	handler.ServeHTTP(w, req)
+	chain.ServeHTTP(w, req)
 
-	assert.Equal(t, http.StatusOK, w.Code)
+	entries := logs.All()
+	assert.Len(t, entries, 1)
+	assert.Equal(t, w.Header().Get("X-Request-ID"), entries[0].ContextMap()["request_id"])
+}
+
+func TestSlowRequestMiddleware(t *testing.T) {
+	token := "valid.token.string"
+	validator := domainmocks.NewTokenValidatorMock(t)
+	validator.EXPECT().ValidateToken(mock.Anything, token).Return(&domain.AccessToken{UserID: 42}, nil).Maybe()
+
+	newRouter := func(logger *zap.Logger, threshold time.Duration, delay time.Duration) *chi.Mux {
+		r := chi.NewRouter()
+		r.Use(SlowRequestMiddleware(logger, threshold, metrics.New()))
+		r.With(AuthMiddleware(validator, nil)).Get("/api/user/orders/{number}", func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(delay)
+			w.WriteHeader(http.StatusOK)
+		})
+		return r
+	}
+
+	t.Run("Slow request is logged with route and user", func(t *testing.T) {
+		core, logs := observer.New(zap.DebugLevel)
+		router := newRouter(zap.New(core), 10*time.Millisecond, 20*time.Millisecond)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/user/orders/12345678903", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		entries := logs.All()
+		require.Len(t, entries, 1)
+		assert.Equal(t, zap.WarnLevel, entries[0].Level)
+		fields := entries[0].ContextMap()
+		assert.Equal(t, "/api/user/orders/{number}", fields["route"])
+		assert.Equal(t, int64(42), fields["user_id"])
+		assert.Equal(t, int64(http.StatusOK), fields["status"])
+	})
+
+	t.Run("Fast request is not logged", func(t *testing.T) {
+		core, logs := observer.New(zap.DebugLevel)
+		router := newRouter(zap.New(core), time.Second, 0)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/user/orders/12345678903", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+
+		assert.Equal(t, 0, logs.Len())
+	})
+
+	t.Run("Disabled threshold", func(t *testing.T) {
+		core, logs := observer.New(zap.DebugLevel)
+		router := newRouter(zap.New(core), 0, 5*time.Millisecond)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/user/orders/12345678903", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+
+		assert.Equal(t, 0, logs.Len())
+	})
 }
 
 func TestRecoveryMiddleware(t *testing.T) {
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// oauthNonceCookie хранит nonce начатого входа: callback принимается только
+// от браузера, который этот вход начал
+const oauthNonceCookie = "oauth_nonce"
+
+// oauthCookiePath ограничивает cookie эндпоинтами входа через провайдеров
+const oauthCookiePath = "/api/user/oauth"
+
+// OAuthService определяет методы входа через внешних провайдеров.
+type OAuthService interface {
+	BeginLogin(ctx context.Context, provider string, linkUserID int64) (*domain.OAuthLogin, error)
+	CompleteLogin(ctx context.Context, provider, state, nonce, code string) (string, error)
+}
+
+// OAuthHandler обрабатывает вход через социальные сети
+type OAuthHandler struct {
+	service OAuthService
+	tokens  TokenValidator
+	logger  *zap.Logger
+}
+
+// NewOAuthHandler создает новый OAuthHandler
+func NewOAuthHandler(service OAuthService, tokens TokenValidator, logger *zap.Logger) *OAuthHandler {
+	return &OAuthHandler{
+		service: service,
+		tokens:  tokens,
+		logger:  logger,
+	}
+}
+
+// Login перенаправляет на страницу входа провайдера. С заголовком Authorization
+// учетная запись провайдера будет привязана к текущему пользователю
+func (h *OAuthHandler) Login(w http.ResponseWriter, r *http.Request) {
+	var linkUserID int64
+	if r.Header.Get("Authorization") != "" {
+		token, ok := bearerToken(r)
+		if !ok {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		access, err := h.tokens.ValidateToken(r.Context(), token)
+		if errors.Is(err, domain.ErrInvalidToken) {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		if err != nil {
+			writeInternalError(w, err)
+			return
+		}
+		linkUserID = access.UserID
+	}
+
+	login, err := h.service.BeginLogin(r.Context(), chi.URLParam(r, "provider"), linkUserID)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     oauthNonceCookie,
+		Value:    login.Nonce,
+		Path:     oauthCookiePath,
+		MaxAge:   int((10 * time.Minute).Seconds()),
+		Secure:   r.TLS != nil,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, login.URL, http.StatusFound)
+}
+
+// Callback завершает вход по коду авторизации и выдает токен доступа, как /api/user/login
+func (h *OAuthHandler) Callback(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("error") != "" {
+		writeJSONError(w, r, http.StatusUnauthorized, "authorization denied by provider")
+		return
+	}
+
+	var nonce string
+	if cookie, err := r.Cookie(oauthNonceCookie); err == nil {
+		nonce = cookie.Value
+	}
+	// Nonce одноразовый
+	http.SetCookie(w, &http.Cookie{Name: oauthNonceCookie, Path: oauthCookiePath, MaxAge: -1})
+
+	token, err := h.service.CompleteLogin(r.Context(), chi.URLParam(r, "provider"), query.Get("state"), nonce, query.Get("code"))
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Authorization", "Bearer "+token)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *OAuthHandler) writeError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, domain.ErrUnknownOAuthProvider):
+		writeJSONError(w, r, http.StatusNotFound, "unknown provider")
+	case errors.Is(err, domain.ErrInvalidOAuthState), errors.Is(err, domain.ErrInvalidInput):
+		writeJSONError(w, r, http.StatusBadRequest, "invalid or expired login attempt")
+	case errors.Is(err, domain.ErrOAuthExchangeFailed):
+		writeJSONError(w, r, http.StatusBadGateway, "provider rejected the login")
+	case errors.Is(err, domain.ErrIdentityAlreadyLinked):
+		writeJSONError(w, r, http.StatusConflict, "account is already linked to another user")
+	case errors.Is(err, domain.ErrUserNotFound):
+		writeJSONError(w, r, http.StatusUnauthorized, "user not found")
+	default:
+		h.logger.Error("failed to process oauth login", zap.Error(err))
+		writeInternalError(w, err)
+	}
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	domainmocks "github.com/avc/loyalty-system-diploma/internal/domain/mocks"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newOAuthRouter(handler *OAuthHandler) http.Handler {
+	r := chi.NewRouter()
+	r.Get("/api/user/oauth/{provider}/login", handler.Login)
+	r.Get("/api/user/oauth/{provider}/callback", handler.Callback)
+	return r
+}
+
+func TestOAuthHandler_Login(t *testing.T) {
+	t.Run("Redirects to provider", func(t *testing.T) {
+		service := domainmocks.NewOAuthServiceMock(t)
+		service.EXPECT().BeginLogin(mock.Anything, "google", int64(0)).
+			Return(&domain.OAuthLogin{URL: "https://accounts.example.com/authorize?state=s", Nonce: "nonce"}, nil).Once()
+		handler := NewOAuthHandler(service, domainmocks.NewTokenValidatorMock(t), zap.NewNop())
+
+		w := httptest.NewRecorder()
+		newOAuthRouter(handler).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/user/oauth/google/login", nil))
+
+		assert.Equal(t, http.StatusFound, w.Code)
+		assert.Equal(t, "https://accounts.example.com/authorize?state=s", w.Header().Get("Location"))
+		cookies := w.Result().Cookies()
+		require.Len(t, cookies, 1)
+		assert.Equal(t, oauthNonceCookie, cookies[0].Name)
+		assert.Equal(t, "nonce", cookies[0].Value)
+		assert.True(t, cookies[0].HttpOnly)
+	})
+
+	t.Run("Authenticated user links account", func(t *testing.T) {
+		service := domainmocks.NewOAuthServiceMock(t)
+		service.EXPECT().BeginLogin(mock.Anything, "vk", int64(7)).
+			Return(&domain.OAuthLogin{URL: "https://oauth.example.com", Nonce: "nonce"}, nil).Once()
+		tokens := domainmocks.NewTokenValidatorMock(t)
+		tokens.EXPECT().ValidateToken(mock.Anything, "token").Return(&domain.AccessToken{UserID: 7}, nil).Once()
+		handler := NewOAuthHandler(service, tokens, zap.NewNop())
+
+		req := httptest.NewRequest(http.MethodGet, "/api/user/oauth/vk/login", nil)
+		req.Header.Set("Authorization", "Bearer token")
+		w := httptest.NewRecorder()
+		newOAuthRouter(handler).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusFound, w.Code)
+	})
+
+	t.Run("Invalid token", func(t *testing.T) {
+		tokens := domainmocks.NewTokenValidatorMock(t)
+		tokens.EXPECT().ValidateToken(mock.Anything, "expired").Return(nil, domain.ErrInvalidToken).Once()
+		handler := NewOAuthHandler(domainmocks.NewOAuthServiceMock(t), tokens, zap.NewNop())
+
+		req := httptest.NewRequest(http.MethodGet, "/api/user/oauth/vk/login", nil)
+		req.Header.Set("Authorization", "Bearer expired")
+		w := httptest.NewRecorder()
+		newOAuthRouter(handler).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("Unknown provider", func(t *testing.T) {
+		service := domainmocks.NewOAuthServiceMock(t)
+		service.EXPECT().BeginLogin(mock.Anything, "facebook", int64(0)).Return(nil, domain.ErrUnknownOAuthProvider).Once()
+		handler := NewOAuthHandler(service, domainmocks.NewTokenValidatorMock(t), zap.NewNop())
+
+		w := httptest.NewRecorder()
+		newOAuthRouter(handler).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/user/oauth/facebook/login", nil))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestOAuthHandler_Callback(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		cookie         string
+		setupMock      func(*domainmocks.OAuthServiceMock)
+		expectedStatus int
+		expectedToken  string
+	}{
+		{
+			name:   "Success",
+			query:  "?code=code&state=state",
+			cookie: "nonce",
+			setupMock: func(m *domainmocks.OAuthServiceMock) {
+				m.EXPECT().CompleteLogin(mock.Anything, "google", "state", "nonce", "code").Return("jwt", nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedToken:  "Bearer jwt",
+		},
+		{
+			name:           "Denied by user",
+			query:          "?error=access_denied&state=state",
+			cookie:         "nonce",
+			setupMock:      func(m *domainmocks.OAuthServiceMock) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:  "Missing cookie",
+			query: "?code=code&state=state",
+			setupMock: func(m *domainmocks.OAuthServiceMock) {
+				m.EXPECT().CompleteLogin(mock.Anything, "google", "state", "", "code").Return("", domain.ErrInvalidOAuthState).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Provider rejected code",
+			query:  "?code=code&state=state",
+			cookie: "nonce",
+			setupMock: func(m *domainmocks.OAuthServiceMock) {
+				m.EXPECT().CompleteLogin(mock.Anything, "google", "state", "nonce", "code").Return("", domain.ErrOAuthExchangeFailed).Once()
+			},
+			expectedStatus: http.StatusBadGateway,
+		},
+		{
+			name:   "Already linked",
+			query:  "?code=code&state=state",
+			cookie: "nonce",
+			setupMock: func(m *domainmocks.OAuthServiceMock) {
+				m.EXPECT().CompleteLogin(mock.Anything, "google", "state", "nonce", "code").Return("", domain.ErrIdentityAlreadyLinked).Once()
+			},
+			expectedStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := domainmocks.NewOAuthServiceMock(t)
+			tt.setupMock(service)
+			handler := NewOAuthHandler(service, domainmocks.NewTokenValidatorMock(t), zap.NewNop())
+
+			req := httptest.NewRequest(http.MethodGet, "/api/user/oauth/google/callback"+tt.query, nil)
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: oauthNonceCookie, Value: tt.cookie})
+			}
+			w := httptest.NewRecorder()
+			newOAuthRouter(handler).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedToken, w.Header().Get("Authorization"))
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// OrderDisputeService определяет споры о принадлежности заказов.
+type OrderDisputeService interface {
+	OpenDispute(ctx context.Context, userID int64, number, comment string) (*domain.OrderDispute, error)
+	ListDisputes(ctx context.Context, status domain.DisputeStatus, limit, offset int) (*domain.OrderDisputePage, error)
+	ResolveDispute(ctx context.Context, id int64, resolution domain.DisputeResolution, comment string, adminID int64) (*domain.OrderDispute, error)
+}
+
+// OrderDisputesHandler обрабатывает споры о заказах: открытие пользователем и решение администратором
+type OrderDisputesHandler struct {
+	service OrderDisputeService
+	logger  *zap.Logger
+}
+
+// NewOrderDisputesHandler создает новый OrderDisputesHandler
+func NewOrderDisputesHandler(service OrderDisputeService, logger *zap.Logger) *OrderDisputesHandler {
+	return &OrderDisputesHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// openDisputeRequest - необязательное пояснение заявителя, например номер чека
+type openDisputeRequest struct {
+	Comment string `json:"comment"`
+}
+
+// resolveDisputeRequest - решение администратора
+type resolveDisputeRequest struct {
+	Resolution domain.DisputeResolution `json:"resolution"`
+	Comment    string                   `json:"comment"`
+}
+
+// Open открывает спор о заказе, загруженном другим пользователем. Тело необязательно
+func (h *OrderDisputesHandler) Open(w http.ResponseWriter, r *http.Request) {
+	userID, ok := GetUserID(r.Context())
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+	number := chi.URLParam(r, "number")
+
+	var req openDisputeRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeJSONError(w, r, http.StatusBadRequest, "invalid request body")
+			return
+		}
+	}
+
+	dispute, err := h.service.OpenDispute(r.Context(), userID, number, req.Comment)
+	var limitErr *domain.SubmissionLimitError
+	switch {
+	case err == nil:
+	case errors.As(err, &limitErr):
+		writeSubmissionLimitError(w, r, limitErr)
+		return
+	case errors.Is(err, domain.ErrInvalidOrderNumber):
+		http.Error(w, http.StatusText(http.StatusUnprocessableEntity), http.StatusUnprocessableEntity)
+		return
+	case errors.Is(err, domain.ErrInvalidInput):
+		writeJSONError(w, r, http.StatusBadRequest, "comment is too long")
+		return
+	case errors.Is(err, domain.ErrOrderNotFound):
+		writeJSONError(w, r, http.StatusNotFound, "order not found")
+		return
+	case errors.Is(err, domain.ErrOrderAlreadyOwned):
+		writeErrorResponse(w, r, http.StatusConflict, ErrorResponse{Error: "order already belongs to this user", Code: "order_owned"})
+		return
+	case errors.Is(err, domain.ErrDisputeExists):
+		writeErrorResponse(w, r, http.StatusConflict, ErrorResponse{Error: "dispute already open", Code: "dispute_exists"})
+		return
+	default:
+		h.logger.Error("failed to open order dispute", zap.String("order", number), zap.Error(err))
+		writeInternalError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(newOrderDisputeResponse(dispute)); err != nil {
+		h.logger.Error("failed to encode order dispute response", zap.Error(err))
+	}
+}
+
+// List возвращает страницу споров, старые первыми
+func (h *OrderDisputesHandler) List(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	limit, ok := intQueryParam(query, "limit")
+	if !ok {
+		writeJSONError(w, r, http.StatusBadRequest, "limit must be a non-negative integer")
+		return
+	}
+	offset, ok := intQueryParam(query, "offset")
+	if !ok {
+		writeJSONError(w, r, http.StatusBadRequest, "offset must be a non-negative integer")
+		return
+	}
+
+	page, err := h.service.ListDisputes(r.Context(), domain.DisputeStatus(query.Get("status")), limit, offset)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidInput) {
+			writeJSONError(w, r, http.StatusBadRequest, "status must be open, transferred or rejected")
+			return
+		}
+		h.logger.Error("failed to list order disputes", zap.Error(err))
+		writeInternalError(w, err)
+		return
+	}
+
+	response := newOrderDisputesResponse(page, offset)
+	w.Header().Add("Vary", "Accept")
+	if prefersEnvelope(r.Header.Get("Accept")) {
+		writeEnvelope(w, r, response.Disputes, offset, response.HasMore, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error("failed to encode order disputes response", zap.Error(err))
+	}
+}
+
+// Resolve передает заказ заявителю или отклоняет спор
+func (h *OrderDisputesHandler) Resolve(w http.ResponseWriter, r *http.Request) {
+	adminID, ok := GetUserID(r.Context())
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil || id <= 0 {
+		writeJSONError(w, r, http.StatusBadRequest, "invalid dispute id")
+		return
+	}
+
+	var req resolveDisputeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	dispute, err := h.service.ResolveDispute(r.Context(), id, req.Resolution, req.Comment, adminID)
+	switch {
+	case err == nil:
+	case errors.Is(err, domain.ErrInvalidInput):
+		writeJSONError(w, r, http.StatusBadRequest, "resolution must be transfer or reject, comment is limited")
+		return
+	case errors.Is(err, domain.ErrDisputeNotFound):
+		writeJSONError(w, r, http.StatusNotFound, "dispute not found")
+		return
+	case errors.Is(err, domain.ErrOrderNotFound):
+		writeJSONError(w, r, http.StatusNotFound, "order not found")
+		return
+	case errors.Is(err, domain.ErrDisputeResolved):
+		writeErrorResponse(w, r, http.StatusConflict, ErrorResponse{Error: "dispute already resolved", Code: "dispute_resolved"})
+		return
+	case errors.Is(err, domain.ErrOrderAlreadyOwned):
+		writeErrorResponse(w, r, http.StatusConflict, ErrorResponse{Error: "order already belongs to the claimant", Code: "order_owned"})
+		return
+	default:
+		h.logger.Error("failed to resolve order dispute", zap.Int64("dispute_id", id), zap.Error(err))
+		writeInternalError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newOrderDisputeResponse(dispute)); err != nil {
+		h.logger.Error("failed to encode order dispute response", zap.Error(err))
+	}
+}
@@ -0,0 +1,256 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	domainmocks "github.com/avc/loyalty-system-diploma/internal/domain/mocks"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newOrderDisputesRouter(handler *OrderDisputesHandler) http.Handler {
+	r := chi.NewRouter()
+	r.Post("/api/user/orders/{number}/dispute", handler.Open)
+	r.Get("/api/admin/disputes", handler.List)
+	r.Post("/api/admin/disputes/{id}/resolve", handler.Resolve)
+	return r
+}
+
+func serveWithUser(router http.Handler, req *http.Request) *httptest.ResponseRecorder {
+	req = req.WithContext(context.WithValue(req.Context(), UserIDKey, int64(1)))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestOrderDisputesHandler_Open(t *testing.T) {
+	number := "12345678903"
+	createdAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(*domainmocks.OrderDisputeServiceMock)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "Opened",
+			body: `{"comment":"receipt 0001"}`,
+			setupMock: func(m *domainmocks.OrderDisputeServiceMock) {
+				m.EXPECT().OpenDispute(mock.Anything, int64(1), number, "receipt 0001").Return(&domain.OrderDispute{
+					ID:          5,
+					OrderNumber: number,
+					ClaimantID:  1,
+					OwnerID:     2,
+					Comment:     "receipt 0001",
+					Status:      domain.DisputeStatusOpen,
+					CreatedAt:   createdAt,
+				}, nil).Once()
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"id":5,"order":"12345678903","status":"open","comment":"receipt 0001","created_at":"2024-03-01T10:00:00Z"}`,
+		},
+		{
+			name: "Empty body",
+			setupMock: func(m *domainmocks.OrderDisputeServiceMock) {
+				m.EXPECT().OpenDispute(mock.Anything, int64(1), number, "").
+					Return(&domain.OrderDispute{ID: 5, OrderNumber: number, Status: domain.DisputeStatusOpen}, nil).Once()
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "Invalid body",
+			body:           `{"comment":`,
+			setupMock:      func(m *domainmocks.OrderDisputeServiceMock) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Order not found",
+			setupMock: func(m *domainmocks.OrderDisputeServiceMock) {
+				m.EXPECT().OpenDispute(mock.Anything, int64(1), number, "").Return(nil, domain.ErrOrderNotFound).Once()
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "Own order",
+			setupMock: func(m *domainmocks.OrderDisputeServiceMock) {
+				m.EXPECT().OpenDispute(mock.Anything, int64(1), number, "").Return(nil, domain.ErrOrderAlreadyOwned).Once()
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":"order already belongs to this user","code":"order_owned"}`,
+		},
+		{
+			name: "Already open",
+			setupMock: func(m *domainmocks.OrderDisputeServiceMock) {
+				m.EXPECT().OpenDispute(mock.Anything, int64(1), number, "").Return(nil, domain.ErrDisputeExists).Once()
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":"dispute already open","code":"dispute_exists"}`,
+		},
+		{
+			name: "Invalid number",
+			setupMock: func(m *domainmocks.OrderDisputeServiceMock) {
+				m.EXPECT().OpenDispute(mock.Anything, int64(1), number, "").Return(nil, domain.ErrInvalidOrderNumber).Once()
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "Submissions banned",
+			setupMock: func(m *domainmocks.OrderDisputeServiceMock) {
+				err := &domain.SubmissionLimitError{Err: domain.ErrSubmissionsBanned, Until: time.Now().Add(time.Hour)}
+				m.EXPECT().OpenDispute(mock.Anything, int64(1), number, "").Return(nil, err).Once()
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name: "Internal error",
+			setupMock: func(m *domainmocks.OrderDisputeServiceMock) {
+				m.EXPECT().OpenDispute(mock.Anything, int64(1), number, "").Return(nil, errors.New("boom")).Once()
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := domainmocks.NewOrderDisputeServiceMock(t)
+			router := newOrderDisputesRouter(NewOrderDisputesHandler(svc, zap.NewNop()))
+			tt.setupMock(svc)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/user/orders/"+number+"/dispute", strings.NewReader(tt.body))
+			w := serveWithUser(router, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestOrderDisputesHandler_List(t *testing.T) {
+	svc := domainmocks.NewOrderDisputeServiceMock(t)
+	router := newOrderDisputesRouter(NewOrderDisputesHandler(svc, zap.NewNop()))
+	createdAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	svc.EXPECT().ListDisputes(mock.Anything, domain.DisputeStatusOpen, 10, 0).Return(&domain.OrderDisputePage{
+		Disputes: []*domain.OrderDispute{{
+			ID:            5,
+			OrderNumber:   "12345678903",
+			ClaimantLogin: "bob",
+			OwnerLogin:    "alice",
+			Status:        domain.DisputeStatusOpen,
+			CreatedAt:     createdAt,
+		}},
+		HasMore: true,
+	}, nil).Once()
+
+	w := serveWithUser(router, httptest.NewRequest(http.MethodGet, "/api/admin/disputes?status=open&limit=10", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"disputes":[{"id":5,"order":"12345678903","status":"open","claimant":"bob","owner":"alice",
+		"created_at":"2024-03-01T10:00:00Z"}],"offset":0,"has_more":true}`, w.Body.String())
+
+	svc.EXPECT().ListDisputes(mock.Anything, domain.DisputeStatus("closed"), 0, 0).Return(nil, domain.ErrInvalidInput).Once()
+	w = serveWithUser(router, httptest.NewRequest(http.MethodGet, "/api/admin/disputes?status=closed", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = serveWithUser(router, httptest.NewRequest(http.MethodGet, "/api/admin/disputes?offset=-1", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestOrderDisputesHandler_Resolve(t *testing.T) {
+	resolvedAt := time.Date(2024, 3, 2, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		path           string
+		body           string
+		setupMock      func(*domainmocks.OrderDisputeServiceMock)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "Transferred",
+			path: "/api/admin/disputes/5/resolve",
+			body: `{"resolution":"transfer","comment":"receipt checked"}`,
+			setupMock: func(m *domainmocks.OrderDisputeServiceMock) {
+				m.EXPECT().ResolveDispute(mock.Anything, int64(5), domain.DisputeResolutionTransfer, "receipt checked", int64(1)).
+					Return(&domain.OrderDispute{
+						ID:                5,
+						OrderNumber:       "12345678903",
+						Status:            domain.DisputeStatusTransferred,
+						ResolutionComment: "receipt checked",
+						CreatedAt:         resolvedAt.Add(-time.Hour),
+						ResolvedAt:        &resolvedAt,
+					}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"id":5,"order":"12345678903","status":"transferred","resolution_comment":"receipt checked",
+				"created_at":"2024-03-02T09:00:00Z","resolved_at":"2024-03-02T10:00:00Z"}`,
+		},
+		{
+			name:           "Invalid id",
+			path:           "/api/admin/disputes/abc/resolve",
+			body:           `{"resolution":"reject"}`,
+			setupMock:      func(m *domainmocks.OrderDisputeServiceMock) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Unknown resolution",
+			path: "/api/admin/disputes/5/resolve",
+			body: `{"resolution":"approve"}`,
+			setupMock: func(m *domainmocks.OrderDisputeServiceMock) {
+				m.EXPECT().ResolveDispute(mock.Anything, int64(5), domain.DisputeResolution("approve"), "", int64(1)).
+					Return(nil, domain.ErrInvalidInput).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Not found",
+			path: "/api/admin/disputes/5/resolve",
+			body: `{"resolution":"reject"}`,
+			setupMock: func(m *domainmocks.OrderDisputeServiceMock) {
+				m.EXPECT().ResolveDispute(mock.Anything, int64(5), domain.DisputeResolutionReject, "", int64(1)).
+					Return(nil, domain.ErrDisputeNotFound).Once()
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "Already resolved",
+			path: "/api/admin/disputes/5/resolve",
+			body: `{"resolution":"reject"}`,
+			setupMock: func(m *domainmocks.OrderDisputeServiceMock) {
+				m.EXPECT().ResolveDispute(mock.Anything, int64(5), domain.DisputeResolutionReject, "", int64(1)).
+					Return(nil, domain.ErrDisputeResolved).Once()
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":"dispute already resolved","code":"dispute_resolved"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := domainmocks.NewOrderDisputeServiceMock(t)
+			router := newOrderDisputesRouter(NewOrderDisputesHandler(svc, zap.NewNop()))
+			tt.setupMock(svc)
+
+			w := serveWithUser(router, httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body)))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
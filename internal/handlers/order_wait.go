@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// OrderWaitService определяет получение заказа пользователя по номеру.
+type OrderWaitService interface {
+	GetOrderByNumber(ctx context.Context, userID int64, number string) (*domain.Order, error)
+}
+
+// OrderWaiter сообщает о событии по заказу, после которого статус стоит перечитать.
+type OrderWaiter interface {
+	Wait(number string) (<-chan struct{}, func())
+}
+
+// Параметры ожидания статуса заказа
+const (
+	defaultOrderWaitTimeout = 30 * time.Second
+	maxOrderWaitTimeout     = 60 * time.Second
+	// Запас к сроку записи ответа сверх времени ожидания
+	orderWaitWriteMargin = 5 * time.Second
+	// Событие может быть доставлено другому экземпляру сервиса,
+	// поэтому статус дополнительно перечитывается с этим интервалом
+	orderWaitRecheckInterval = 5 * time.Second
+)
+
+// OrderWaitHandler обрабатывает long polling статуса заказа
+// для клиентов, которые не могут использовать SSE или WebSocket
+type OrderWaitHandler struct {
+	service OrderWaitService
+	waiter  OrderWaiter
+	recheck time.Duration
+	logger  *zap.Logger
+}
+
+// NewOrderWaitHandler создает новый OrderWaitHandler
+func NewOrderWaitHandler(service OrderWaitService, waiter OrderWaiter, logger *zap.Logger) *OrderWaitHandler {
+	return &OrderWaitHandler{
+		service: service,
+		waiter:  waiter,
+		recheck: orderWaitRecheckInterval,
+		logger:  logger,
+	}
+}
+
+// Wait отвечает, когда заказ получит окончательный статус или истечет timeout.
+// В обоих случаях возвращается заказ с текущим статусом, по которому клиент решает, ждать ли дальше.
+func (h *OrderWaitHandler) Wait(w http.ResponseWriter, r *http.Request) {
+	userID, ok := GetUserID(r.Context())
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+	number := chi.URLParam(r, "number")
+
+	timeout, err := parseOrderWaitTimeout(r.URL.Query().Get("timeout"))
+	if err != nil {
+		writeJSONError(w, r, http.StatusBadRequest, "invalid timeout")
+		return
+	}
+
+	// Ожидание может быть дольше общего срока записи ответа сервера
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Now().Add(timeout + orderWaitWriteMargin)); err != nil &&
+		!errors.Is(err, http.ErrNotSupported) {
+		h.logger.Warn("failed to extend write deadline", zap.Error(err))
+	}
+
+	// Подписываемся до чтения статуса, чтобы не пропустить событие между ними
+	done, cancel := h.waiter.Wait(number)
+	defer cancel()
+
+	order, err := h.service.GetOrderByNumber(r.Context(), userID, number)
+	if err != nil {
+		h.writeError(w, r, number, err)
+		return
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	recheck := time.NewTicker(h.recheck)
+	defer recheck.Stop()
+
+	for !order.Status.IsFinal() {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-timer.C:
+			h.writeOrder(w, order)
+			return
+		case <-done:
+			// Событие уже получено, дальше статус перечитывается только по интервалу
+			done = nil
+		case <-recheck.C:
+		}
+
+		if order, err = h.service.GetOrderByNumber(r.Context(), userID, number); err != nil {
+			h.writeError(w, r, number, err)
+			return
+		}
+	}
+
+	h.writeOrder(w, order)
+}
+
+func (h *OrderWaitHandler) writeOrder(w http.ResponseWriter, order *domain.Order) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(w).Encode(newOrderResponse(order)); err != nil {
+		h.logger.Error("failed to encode order response", zap.Error(err))
+	}
+}
+
+func (h *OrderWaitHandler) writeError(w http.ResponseWriter, r *http.Request, number string, err error) {
+	if errors.Is(err, domain.ErrOrderNotFound) {
+		writeJSONError(w, r, http.StatusNotFound, "order not found")
+		return
+	}
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+	h.logger.Error("failed to get order", zap.String("order", number), zap.Error(err))
+	writeInternalError(w, err)
+}
+
+// parseOrderWaitTimeout разбирает время ожидания в формате Go duration или в секундах.
+// Пустое значение заменяется значением по умолчанию, слишком большое - ограничивается.
+func parseOrderWaitTimeout(value string) (time.Duration, error) {
+	if value == "" {
+		return defaultOrderWaitTimeout, nil
+	}
+
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		seconds, convErr := strconv.Atoi(value)
+		if convErr != nil {
+			return 0, err
+		}
+		timeout = time.Duration(seconds) * time.Second
+	}
+	if timeout <= 0 {
+		return 0, errors.New("timeout must be positive")
+	}
+	return min(timeout, maxOrderWaitTimeout), nil
+}
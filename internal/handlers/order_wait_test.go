@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	domainmocks "github.com/avc/loyalty-system-diploma/internal/domain/mocks"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newOrderWaitRouter(handler *OrderWaitHandler) http.Handler {
+	r := chi.NewRouter()
+	r.Get("/api/user/orders/{number}/wait", handler.Wait)
+	return r
+}
+
+func newOrderWaitRequest(query string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/api/user/orders/12345678903/wait"+query, nil)
+	return req.WithContext(context.WithValue(req.Context(), UserIDKey, int64(1)))
+}
+
+func TestOrderWaitHandler_Wait(t *testing.T) {
+	number := "12345678903"
+	newOrder := &domain.Order{UserID: 1, Number: number, Status: domain.OrderStatusNew}
+	accrual := 500.0
+	processed := &domain.Order{UserID: 1, Number: number, Status: domain.OrderStatusProcessed, Accrual: &accrual}
+
+	t.Run("Already final", func(t *testing.T) {
+		service := domainmocks.NewOrderWaitServiceMock(t)
+		waiter := domainmocks.NewOrderWaiterMock(t)
+		waiter.EXPECT().Wait(number).Return(make(chan struct{}), func() {}).Once()
+		service.EXPECT().GetOrderByNumber(mock.Anything, int64(1), number).Return(processed, nil).Once()
+
+		w := httptest.NewRecorder()
+		newOrderWaitRouter(NewOrderWaitHandler(service, waiter, zap.NewNop())).ServeHTTP(w, newOrderWaitRequest(""))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+		var body OrderResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+		assert.Equal(t, "PROCESSED", body.Status)
+	})
+
+	t.Run("Event wakes waiter", func(t *testing.T) {
+		service := domainmocks.NewOrderWaitServiceMock(t)
+		waiter := domainmocks.NewOrderWaiterMock(t)
+		done := make(chan struct{})
+		close(done)
+		cancelled := false
+		waiter.EXPECT().Wait(number).Return(done, func() { cancelled = true }).Once()
+		service.EXPECT().GetOrderByNumber(mock.Anything, int64(1), number).Return(newOrder, nil).Once()
+		service.EXPECT().GetOrderByNumber(mock.Anything, int64(1), number).Return(processed, nil).Once()
+
+		w := httptest.NewRecorder()
+		newOrderWaitRouter(NewOrderWaitHandler(service, waiter, zap.NewNop())).ServeHTTP(w, newOrderWaitRequest("?timeout=30s"))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var body OrderResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+		assert.Equal(t, "PROCESSED", body.Status)
+		assert.True(t, cancelled)
+	})
+
+	t.Run("Recheck finds final status without event", func(t *testing.T) {
+		service := domainmocks.NewOrderWaitServiceMock(t)
+		waiter := domainmocks.NewOrderWaiterMock(t)
+		waiter.EXPECT().Wait(number).Return(make(chan struct{}), func() {}).Once()
+		service.EXPECT().GetOrderByNumber(mock.Anything, int64(1), number).Return(newOrder, nil).Once()
+		service.EXPECT().GetOrderByNumber(mock.Anything, int64(1), number).Return(processed, nil).Once()
+
+		handler := NewOrderWaitHandler(service, waiter, zap.NewNop())
+		handler.recheck = 10 * time.Millisecond
+		w := httptest.NewRecorder()
+		newOrderWaitRouter(handler).ServeHTTP(w, newOrderWaitRequest("?timeout=30s"))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var body OrderResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+		assert.Equal(t, "PROCESSED", body.Status)
+	})
+
+	t.Run("Timeout returns current status", func(t *testing.T) {
+		service := domainmocks.NewOrderWaitServiceMock(t)
+		waiter := domainmocks.NewOrderWaiterMock(t)
+		waiter.EXPECT().Wait(number).Return(make(chan struct{}), func() {}).Once()
+		service.EXPECT().GetOrderByNumber(mock.Anything, int64(1), number).Return(newOrder, nil).Once()
+
+		w := httptest.NewRecorder()
+		newOrderWaitRouter(NewOrderWaitHandler(service, waiter, zap.NewNop())).ServeHTTP(w, newOrderWaitRequest("?timeout=20ms"))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var body OrderResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+		assert.Equal(t, "NEW", body.Status)
+	})
+
+	t.Run("Client gone", func(t *testing.T) {
+		service := domainmocks.NewOrderWaitServiceMock(t)
+		waiter := domainmocks.NewOrderWaiterMock(t)
+		waiter.EXPECT().Wait(number).Return(make(chan struct{}), func() {}).Once()
+		service.EXPECT().GetOrderByNumber(mock.Anything, int64(1), number).Return(newOrder, nil).Once()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		req := newOrderWaitRequest("")
+		w := httptest.NewRecorder()
+		newOrderWaitRouter(NewOrderWaitHandler(service, waiter, zap.NewNop())).ServeHTTP(w, req.WithContext(
+			context.WithValue(ctx, UserIDKey, int64(1))))
+
+		assert.Empty(t, w.Body.String())
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		service := domainmocks.NewOrderWaitServiceMock(t)
+		waiter := domainmocks.NewOrderWaiterMock(t)
+		waiter.EXPECT().Wait(number).Return(make(chan struct{}), func() {}).Once()
+		service.EXPECT().GetOrderByNumber(mock.Anything, int64(1), number).Return(nil, domain.ErrOrderNotFound).Once()
+
+		w := httptest.NewRecorder()
+		newOrderWaitRouter(NewOrderWaitHandler(service, waiter, zap.NewNop())).ServeHTTP(w, newOrderWaitRequest(""))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Service error", func(t *testing.T) {
+		service := domainmocks.NewOrderWaitServiceMock(t)
+		waiter := domainmocks.NewOrderWaiterMock(t)
+		waiter.EXPECT().Wait(number).Return(make(chan struct{}), func() {}).Once()
+		service.EXPECT().GetOrderByNumber(mock.Anything, int64(1), number).Return(nil, errors.New("db error")).Once()
+
+		w := httptest.NewRecorder()
+		newOrderWaitRouter(NewOrderWaitHandler(service, waiter, zap.NewNop())).ServeHTTP(w, newOrderWaitRequest(""))
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+
+	t.Run("Invalid timeout", func(t *testing.T) {
+		handler := NewOrderWaitHandler(domainmocks.NewOrderWaitServiceMock(t), domainmocks.NewOrderWaiterMock(t), zap.NewNop())
+
+		w := httptest.NewRecorder()
+		newOrderWaitRouter(handler).ServeHTTP(w, newOrderWaitRequest("?timeout=-1s"))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		handler := NewOrderWaitHandler(domainmocks.NewOrderWaitServiceMock(t), domainmocks.NewOrderWaiterMock(t), zap.NewNop())
+
+		w := httptest.NewRecorder()
+		newOrderWaitRouter(handler).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/user/orders/1/wait", nil))
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
+
+func TestParseOrderWaitTimeout(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"", defaultOrderWaitTimeout, false},
+		{"10s", 10 * time.Second, false},
+		{"15", 15 * time.Second, false},
+		{"5m", maxOrderWaitTimeout, false},
+		{"0", 0, true},
+		{"-1s", 0, true},
+		{"soon", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseOrderWaitTimeout(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -5,32 +5,60 @@ import (
 	"encoding/json"
 	"errors"
 	"io"
+	"math"
+	"mime"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/avc/loyalty-system-diploma/internal/domain"
-	"github.com/avc/loyalty-system-diploma/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
 // OrderService определяет методы работы с заказами.
 type OrderService interface {
-	SubmitOrder(ctx context.Context, userID int64, orderNumber string) error
-	GetOrders(ctx context.Context, userID int64) ([]*domain.Order, error)
+	SubmitOrder(ctx context.Context, userID int64, orderNumber string, metadata *domain.OrderMetadata) error
+	SubmitFiscalReceipt(ctx context.Context, userID int64, payload string) (string, error)
+	GetOrders(ctx context.Context, userID int64, filter domain.OrderFilter) (*domain.OrderPage, error)
+	GetOrder(ctx context.Context, userID int64, publicID uuid.UUID) (*domain.Order, error)
 }
 
+// BacklogMonitor сообщает, что обработка заказов отстает от их загрузки,
+// и оценивает ожидание обработки нового заказа.
+type BacklogMonitor interface {
+	Overloaded() bool
+	EstimateProcessing() domain.ProcessingEstimate
+}
+
+// processingDelayedHeader предупреждает клиента, что принятый заказ будет обработан с задержкой
+const processingDelayedHeader = "X-Processing-Delayed"
+
 type OrdersHandler struct {
 	orderService OrderService
+	backlog      BacklogMonitor
 	logger       *zap.Logger
 }
 
-func NewOrdersHandler(orderService OrderService, logger *zap.Logger) *OrdersHandler {
+// NewOrdersHandler создает новый OrdersHandler. backlog может быть nil.
+func NewOrdersHandler(orderService OrderService, backlog BacklogMonitor, logger *zap.Logger) *OrdersHandler {
 	return &OrdersHandler{
 		orderService: orderService,
+		backlog:      backlog,
 		logger:       logger,
 	}
 }
 
+// submitOrderRequest - JSON форма загрузки заказа с необязательными метаданными
+type submitOrderRequest struct {
+	Number   string                `json:"number"`
+	Metadata *domain.OrderMetadata `json:"metadata"`
+}
+
+// SubmitOrder принимает номер заказа текстом или JSON с метаданными
 func (h *OrdersHandler) SubmitOrder(w http.ResponseWriter, r *http.Request) {
 	userID, ok := GetUserID(r.Context())
 	if !ok {
@@ -38,40 +66,153 @@ func (h *OrdersHandler) SubmitOrder(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	body, err := io.ReadAll(r.Body)
-	if err != nil {
-		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
-		return
+	var req submitOrderRequest
+	if isJSONContent(r.Header.Get("Content-Type")) {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+	} else {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+		req.Number = string(body)
 	}
 
-	orderNumber := strings.TrimSpace(string(body))
+	orderNumber := strings.TrimSpace(req.Number)
 	if orderNumber == "" {
 		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
 		return
 	}
 
-	err = h.orderService.SubmitOrder(r.Context(), userID, orderNumber)
+	err := h.orderService.SubmitOrder(r.Context(), userID, orderNumber, req.Metadata)
 	if err != nil {
-		if errors.Is(err, service.ErrInvalidOrderNumber) {
+		var limitErr *domain.SubmissionLimitError
+		if errors.As(err, &limitErr) {
+			writeSubmissionLimitError(w, r, limitErr)
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidOrderNumber) {
 			http.Error(w, http.StatusText(http.StatusUnprocessableEntity), http.StatusUnprocessableEntity)
 			return
 		}
-		if errors.Is(err, service.ErrOrderExists) {
+		if errors.Is(err, domain.ErrInvalidOrderMetadata) {
+			writeJSONError(w, r, http.StatusBadRequest, "invalid order metadata")
+			return
+		}
+		if errors.Is(err, domain.ErrOrderExists) {
 			w.WriteHeader(http.StatusOK)
 			return
 		}
-		if errors.Is(err, service.ErrOrderOwnedByAnother) {
+		if errors.Is(err, domain.ErrOrderOwnedByAnother) {
 			http.Error(w, http.StatusText(http.StatusConflict), http.StatusConflict)
 			return
 		}
 		h.logger.Error("failed to submit order", zap.Error(err))
-		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		writeInternalError(w, err)
+		return
+	}
+
+	if h.backlog != nil && h.backlog.Overloaded() {
+		w.Header().Set(processingDelayedHeader, "true")
+	}
+
+	// Квитанцию получают только клиенты, явно запросившие JSON; остальным - пустой 202, как раньше
+	w.Header().Add("Vary", "Accept")
+	if !acceptsJSON(r.Header.Get("Accept")) {
+		w.WriteHeader(http.StatusAccepted)
 		return
 	}
+	h.writeOrderReceipt(w, orderNumber)
+}
 
-	w.WriteHeader(http.StatusAccepted)
+// fiscalReceiptRequest - JSON форма загрузки заказа по QR коду кассового чека
+type fiscalReceiptRequest struct {
+	QR string `json:"qr"`
 }
 
+// SubmitFiscalReceipt принимает заказ по содержимому QR кода кассового чека текстом
+// или JSON. Номер заказа выводится из чека, поэтому квитанция отдается всегда
+func (h *OrdersHandler) SubmitFiscalReceipt(w http.ResponseWriter, r *http.Request) {
+	userID, ok := GetUserID(r.Context())
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	var req fiscalReceiptRequest
+	if isJSONContent(r.Header.Get("Content-Type")) {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeJSONError(w, r, http.StatusBadRequest, "invalid request body")
+			return
+		}
+	} else {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeJSONError(w, r, http.StatusBadRequest, "invalid request body")
+			return
+		}
+		req.QR = string(body)
+	}
+
+	orderNumber, err := h.orderService.SubmitFiscalReceipt(r.Context(), userID, req.QR)
+	if err != nil {
+		var amountErr *domain.AmountError
+		var limitErr *domain.SubmissionLimitError
+		switch {
+		case errors.As(err, &limitErr):
+			writeSubmissionLimitError(w, r, limitErr)
+		case errors.Is(err, domain.ErrInvalidFiscalReceipt):
+			writeJSONError(w, r, http.StatusBadRequest, "invalid receipt QR code")
+		case errors.As(err, &amountErr):
+			writeErrorResponse(w, r, http.StatusBadRequest, ErrorResponse{Error: amountErrorMessage(amountErr.Reason), Code: amountErr.Reason})
+		case errors.Is(err, domain.ErrInvalidOrderNumber):
+			http.Error(w, http.StatusText(http.StatusUnprocessableEntity), http.StatusUnprocessableEntity)
+		case errors.Is(err, domain.ErrOrderExists):
+			h.writeReceipt(w, http.StatusOK, OrderReceiptResponse{Number: orderNumber})
+		case errors.Is(err, domain.ErrOrderOwnedByAnother):
+			http.Error(w, http.StatusText(http.StatusConflict), http.StatusConflict)
+		default:
+			h.logger.Error("failed to submit fiscal receipt", zap.Error(err))
+			writeInternalError(w, err)
+		}
+		return
+	}
+
+	if h.backlog != nil && h.backlog.Overloaded() {
+		w.Header().Set(processingDelayedHeader, "true")
+	}
+	h.writeOrderReceipt(w, orderNumber)
+}
+
+// writeOrderReceipt отвечает 202 с квитанцией о приеме заказа и подсказкой,
+// когда запрашивать статус (Retry-After)
+func (h *OrdersHandler) writeOrderReceipt(w http.ResponseWriter, orderNumber string) {
+	receipt := OrderReceiptResponse{Number: orderNumber, Status: string(domain.OrderStatusNew)}
+	if h.backlog != nil {
+		estimate := h.backlog.EstimateProcessing()
+		wait := max(int(math.Ceil(estimate.Wait.Seconds())), 1)
+		receipt.QueuePosition = estimate.QueuePosition
+		receipt.EstimatedWaitSeconds = wait
+		w.Header().Set("Retry-After", strconv.Itoa(wait))
+	}
+
+	h.writeReceipt(w, http.StatusAccepted, receipt)
+}
+
+func (h *OrdersHandler) writeReceipt(w http.ResponseWriter, status int, receipt OrderReceiptResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(receipt); err != nil {
+		h.logger.Error("failed to encode order receipt", zap.Error(err))
+	}
+}
+
+// GetOrders возвращает заказы пользователя. Query параметры status, from и to (RFC 3339,
+// from включительно) фильтруют заказы, limit и offset задают страницу; без limit
+// возвращаются все заказы
 func (h *OrdersHandler) GetOrders(w http.ResponseWriter, r *http.Request) {
 	userID, ok := GetUserID(r.Context())
 	if !ok {
@@ -79,20 +220,175 @@ func (h *OrdersHandler) GetOrders(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	orders, err := h.orderService.GetOrders(r.Context(), userID)
+	filter, ok := orderFilterParams(w, r, r.URL.Query())
+	if !ok {
+		return
+	}
+
+	page, err := h.orderService.GetOrders(r.Context(), userID, filter)
 	if err != nil {
+		if errors.Is(err, domain.ErrInvalidInput) {
+			writeJSONError(w, r, http.StatusBadRequest, "invalid order filter")
+			return
+		}
 		h.logger.Error("failed to get orders", zap.Error(err))
-		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		writeInternalError(w, err)
 		return
 	}
+	orders := page.Orders
 
+	w.Header().Add("Vary", "Accept")
+	if prefersEnvelope(r.Header.Get("Accept")) {
+		writeEnvelope(w, r, newOrdersResponse(orders), filter.Offset, page.HasMore, h.logger)
+		return
+	}
 	if len(orders) == 0 {
 		w.WriteHeader(http.StatusNoContent)
 		return
 	}
 
+	if prefersNDJSON(r.Header.Get("Accept")) {
+		h.writeOrdersNDJSON(w, orders)
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(orders); err != nil {
+	if err := json.NewEncoder(w).Encode(newOrdersResponse(orders)); err != nil {
 		h.logger.Error("failed to encode orders response", zap.Error(err))
 	}
 }
+
+// orderFilterParams разбирает фильтр списка заказов из query параметров.
+// При неверном значении отвечает 400 и возвращает false.
+func orderFilterParams(w http.ResponseWriter, r *http.Request, query url.Values) (domain.OrderFilter, bool) {
+	filter := domain.OrderFilter{Status: domain.OrderStatus(query.Get("status"))}
+
+	var ok bool
+	if filter.Limit, ok = intQueryParam(query, "limit"); !ok {
+		writeJSONError(w, r, http.StatusBadRequest, "limit must be a non-negative integer")
+		return filter, false
+	}
+	if filter.Offset, ok = intQueryParam(query, "offset"); !ok {
+		writeJSONError(w, r, http.StatusBadRequest, "offset must be a non-negative integer")
+		return filter, false
+	}
+	if filter.From, ok = timeQueryParam(query, "from"); !ok {
+		writeJSONError(w, r, http.StatusBadRequest, "from must be an RFC 3339 timestamp")
+		return filter, false
+	}
+	if filter.To, ok = timeQueryParam(query, "to"); !ok {
+		writeJSONError(w, r, http.StatusBadRequest, "to must be an RFC 3339 timestamp")
+		return filter, false
+	}
+	return filter, true
+}
+
+// timeQueryParam читает время в формате RFC 3339 из query параметра, нулевое - если параметр не задан
+func timeQueryParam(query url.Values, name string) (time.Time, bool) {
+	value := query.Get(name)
+	if value == "" {
+		return time.Time{}, true
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// GetOrder возвращает заказ пользователя по публичному идентификатору
+func (h *OrdersHandler) GetOrder(w http.ResponseWriter, r *http.Request) {
+	userID, ok := GetUserID(r.Context())
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	publicID, ok := publicIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	order, err := h.orderService.GetOrder(r.Context(), userID, publicID)
+	if err != nil {
+		if errors.Is(err, domain.ErrOrderNotFound) {
+			writeJSONError(w, r, http.StatusNotFound, "order not found")
+			return
+		}
+		h.logger.Error("failed to get order", zap.Error(err))
+		writeInternalError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newOrderResponse(order)); err != nil {
+		h.logger.Error("failed to encode order response", zap.Error(err))
+	}
+}
+
+// publicIDParam разбирает публичный идентификатор из пути запроса.
+// При неверном формате отвечает 400 и возвращает false.
+func publicIDParam(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	publicID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeJSONError(w, r, http.StatusBadRequest, "invalid id")
+		return uuid.UUID{}, false
+	}
+	return publicID, true
+}
+
+// writeOrdersNDJSON пишет заказы по одному на строку, чтобы клиент
+// мог обрабатывать их потоком, не дожидаясь всего массива
+func (h *OrdersHandler) writeOrdersNDJSON(w http.ResponseWriter, orders []*domain.Order) {
+	w.Header().Set("Content-Type", mediaTypeNDJSON)
+
+	enc := json.NewEncoder(w)
+	for _, order := range orders {
+		if err := enc.Encode(newOrderResponse(order)); err != nil {
+			h.logger.Error("failed to encode orders response", zap.Error(err))
+			return
+		}
+	}
+}
+
+// mediaTypeNDJSON - newline delimited JSON, один объект на строку
+const mediaTypeNDJSON = "application/x-ndjson"
+
+// prefersNDJSON сообщает, запросил ли клиент NDJSON явно и не предпочел ли ему JSON.
+// Без явного запроса (в том числе при */*) ответ остается массивом JSON.
+func prefersNDJSON(accept string) bool {
+	ndjsonQ := acceptQuality(accept, mediaTypeNDJSON, "application/ndjson")
+	return ndjsonQ > 0 && ndjsonQ >= acceptQuality(accept, "application/json")
+}
+
+// acceptsJSON сообщает, указал ли клиент application/json в Accept явно; */* не считается
+func acceptsJSON(accept string) bool {
+	return acceptQuality(accept, "application/json") > 0
+}
+
+// acceptQuality возвращает наибольший вес q, с которым в Accept явно указан один из типов, 0 - если не указан
+func acceptQuality(accept string, mediaTypes ...string) float64 {
+	var quality float64
+	for _, part := range strings.Split(accept, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				q = parsed
+			}
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		for _, mediaType := range mediaTypes {
+			if name == mediaType {
+				quality = max(quality, q)
+			}
+		}
+	}
+	return quality
+}
+
+// isJSONContent проверяет, что тело запроса передано как application/json
+func isJSONContent(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == "application/json"
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"go.uber.org/zap"
+)
+
+// ProcessingStatusService определяет получение состояния обработки заказов.
+type ProcessingStatusService interface {
+	GetProcessingStatus(ctx context.Context) (*domain.ProcessingStatus, error)
+}
+
+// ProcessingStatusHandler отдает клиентским приложениям ожидаемое время начисления
+type ProcessingStatusHandler struct {
+	service ProcessingStatusService
+	maxAge  time.Duration
+	logger  *zap.Logger
+}
+
+// NewProcessingStatusHandler создает новый ProcessingStatusHandler.
+// maxAge - сколько клиенты и прокси могут кэшировать ответ.
+func NewProcessingStatusHandler(service ProcessingStatusService, maxAge time.Duration, logger *zap.Logger) *ProcessingStatusHandler {
+	return &ProcessingStatusHandler{
+		service: service,
+		maxAge:  maxAge,
+		logger:  logger,
+	}
+}
+
+// Get возвращает среднее время от загрузки заказа до начисления и число необработанных заказов
+func (h *ProcessingStatusHandler) Get(w http.ResponseWriter, r *http.Request) {
+	status, err := h.service.GetProcessingStatus(r.Context())
+	if err != nil {
+		h.logger.Error("failed to get processing status", zap.Error(err))
+		writeInternalError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(h.maxAge.Seconds())))
+	if err := json.NewEncoder(w).Encode(newProcessingStatusResponse(status)); err != nil {
+		h.logger.Error("failed to encode processing status response", zap.Error(err))
+	}
+}
+
+// ProcessingStatusResponse представляет состояние обработки заказов в ответе API
+type ProcessingStatusResponse struct {
+	AverageLatencySeconds int       `json:"average_latency_seconds"`
+	ProcessedOrders       int       `json:"processed_orders"`
+	Backlog               int       `json:"backlog"`
+	UpdatedAt             time.Time `json:"updated_at"`
+}
+
+// newProcessingStatusResponse преобразует состояние обработки в ответ API
+func newProcessingStatusResponse(status *domain.ProcessingStatus) ProcessingStatusResponse {
+	return ProcessingStatusResponse{
+		AverageLatencySeconds: int(math.Ceil(status.AverageLatency.Seconds())),
+		ProcessedOrders:       status.Processed,
+		Backlog:               status.Backlog,
+		UpdatedAt:             status.UpdatedAt,
+	}
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	domainmocks "github.com/avc/loyalty-system-diploma/internal/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestProcessingStatusHandler_Get(t *testing.T) {
+	updatedAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	t.Run("Success", func(t *testing.T) {
+		svc := domainmocks.NewProcessingStatusServiceMock(t)
+		svc.EXPECT().GetProcessingStatus(mock.Anything).Return(&domain.ProcessingStatus{
+			AverageLatency: 95500 * time.Millisecond,
+			Processed:      120,
+			Backlog:        4,
+			UpdatedAt:      updatedAt,
+		}, nil).Once()
+		handler := NewProcessingStatusHandler(svc, 30*time.Second, zap.NewNop())
+
+		w := httptest.NewRecorder()
+		handler.Get(w, httptest.NewRequest(http.MethodGet, "/api/status/processing", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "public, max-age=30", w.Header().Get("Cache-Control"))
+		assert.JSONEq(t, `{"average_latency_seconds":96,"processed_orders":120,"backlog":4,"updated_at":"2024-03-01T10:00:00Z"}`, w.Body.String())
+	})
+
+	t.Run("Service error", func(t *testing.T) {
+		svc := domainmocks.NewProcessingStatusServiceMock(t)
+		svc.EXPECT().GetProcessingStatus(mock.Anything).Return(nil, errors.New("db error")).Once()
+		handler := NewProcessingStatusHandler(svc, 30*time.Second, zap.NewNop())
+
+		w := httptest.NewRecorder()
+		handler.Get(w, httptest.NewRequest(http.MethodGet, "/api/status/processing", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Empty(t, w.Header().Get("Cache-Control"))
+	})
+}
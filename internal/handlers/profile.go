@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/avc/loyalty-system-diploma/internal/locale"
+	"go.uber.org/zap"
+)
+
+// ProfileService определяет управление настройками пользователей
+type ProfileService interface {
+	GetProfile(ctx context.Context, userID int64) (*domain.UserProfile, error)
+	UpdateProfile(ctx context.Context, userID int64, profile domain.UserProfile) (*domain.UserProfile, error)
+}
+
+// ProfileHandler обрабатывает запросы к настройкам пользователя
+type ProfileHandler struct {
+	service ProfileService
+	logger  *zap.Logger
+}
+
+// NewProfileHandler создает новый ProfileHandler
+func NewProfileHandler(service ProfileService, logger *zap.Logger) *ProfileHandler {
+	return &ProfileHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// profileRequest - новые настройки; пустой или пропущенный язык сбрасывает выбор
+type profileRequest struct {
+	Locale string `json:"locale"`
+}
+
+// profileResponse - настройки пользователя. EffectiveLocale - язык, на котором
+// формируются ответы: выбранный пользователем или определенный по Accept-Language
+type profileResponse struct {
+	Locale          string `json:"locale,omitempty"`
+	EffectiveLocale string `json:"effective_locale"`
+}
+
+// Get возвращает настройки текущего пользователя
+func (h *ProfileHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID, ok := GetUserID(r.Context())
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	profile, err := h.service.GetProfile(r.Context(), userID)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	h.writeProfile(w, r, profile)
+}
+
+// Update заменяет настройки текущего пользователя
+func (h *ProfileHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID, ok := GetUserID(r.Context())
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	var req profileRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	profile, err := h.service.UpdateProfile(r.Context(), userID, domain.UserProfile{Locale: req.Locale})
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	h.writeProfile(w, r, profile)
+}
+
+func (h *ProfileHandler) writeProfile(w http.ResponseWriter, r *http.Request, profile *domain.UserProfile) {
+	// Язык из контекста определен до изменения настроек, поэтому новый выбор применяется сразу
+	effective := locale.From(r.Context())
+	if l, err := locale.Parse(profile.Locale); err == nil {
+		effective = l
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	resp := profileResponse{Locale: profile.Locale, EffectiveLocale: string(effective)}
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.logger.Error("failed to encode profile response", zap.Error(err))
+	}
+}
+
+func (h *ProfileHandler) writeError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, domain.ErrUnsupportedLocale):
+		writeErrorResponse(w, r, http.StatusBadRequest, ErrorResponse{Error: "unsupported locale", Code: "unsupported_locale"})
+	case errors.Is(err, domain.ErrUserNotFound):
+		writeJSONError(w, r, http.StatusNotFound, "user not found")
+	default:
+		h.logger.Error("failed to manage profile", zap.Error(err))
+		writeInternalError(w, err)
+	}
+}
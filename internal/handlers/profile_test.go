@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	domainmocks "github.com/avc/loyalty-system-diploma/internal/domain/mocks"
+	"github.com/avc/loyalty-system-diploma/internal/locale"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestProfileHandler_Get(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         *int64
+		requestLocale  locale.Locale
+		setupMock      func(*domainmocks.ProfileServiceMock)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:          "Chosen locale",
+			userID:        ptrInt64(1),
+			requestLocale: locale.English,
+			setupMock: func(m *domainmocks.ProfileServiceMock) {
+				m.EXPECT().GetProfile(mock.Anything, int64(1)).Return(&domain.UserProfile{Locale: "ru"}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"locale":"ru","effective_locale":"ru"}`,
+		},
+		{
+			name:          "Locale from Accept-Language",
+			userID:        ptrInt64(1),
+			requestLocale: locale.Russian,
+			setupMock: func(m *domainmocks.ProfileServiceMock) {
+				m.EXPECT().GetProfile(mock.Anything, int64(1)).Return(&domain.UserProfile{}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"effective_locale":"ru"}`,
+		},
+		{
+			name:          "User not found",
+			userID:        ptrInt64(1),
+			requestLocale: locale.Russian,
+			setupMock: func(m *domainmocks.ProfileServiceMock) {
+				m.EXPECT().GetProfile(mock.Anything, int64(1)).Return(nil, domain.ErrUserNotFound).Once()
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":"пользователь не найден"}`,
+		},
+		{
+			name:           "Unauthorized",
+			setupMock:      func(m *domainmocks.ProfileServiceMock) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := domainmocks.NewProfileServiceMock(t)
+			tt.setupMock(svc)
+			handler := NewProfileHandler(svc, zap.NewNop())
+
+			req := httptest.NewRequest(http.MethodGet, "/api/user/profile", nil)
+			ctx := locale.With(req.Context(), tt.requestLocale)
+			if tt.userID != nil {
+				ctx = context.WithValue(ctx, UserIDKey, *tt.userID)
+			}
+			w := httptest.NewRecorder()
+			handler.Get(w, req.WithContext(ctx))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestProfileHandler_Update(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(*domainmocks.ProfileServiceMock)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "Success",
+			body: `{"locale":"ru-RU"}`,
+			setupMock: func(m *domainmocks.ProfileServiceMock) {
+				m.EXPECT().UpdateProfile(mock.Anything, int64(1), domain.UserProfile{Locale: "ru-RU"}).
+					Return(&domain.UserProfile{Locale: "ru"}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"locale":"ru","effective_locale":"ru"}`,
+		},
+		{
+			name: "Unsupported locale",
+			body: `{"locale":"de"}`,
+			setupMock: func(m *domainmocks.ProfileServiceMock) {
+				m.EXPECT().UpdateProfile(mock.Anything, int64(1), domain.UserProfile{Locale: "de"}).
+					Return(nil, domain.ErrUnsupportedLocale).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"unsupported locale","code":"unsupported_locale"}`,
+		},
+		{
+			name:           "Invalid body",
+			body:           `{`,
+			setupMock:      func(m *domainmocks.ProfileServiceMock) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid request body"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := domainmocks.NewProfileServiceMock(t)
+			tt.setupMock(svc)
+			handler := NewProfileHandler(svc, zap.NewNop())
+
+			req := httptest.NewRequest(http.MethodPut, "/api/user/profile", strings.NewReader(tt.body))
+			req = req.WithContext(context.WithValue(req.Context(), UserIDKey, int64(1)))
+			w := httptest.NewRecorder()
+			handler.Update(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
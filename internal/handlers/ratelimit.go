@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/metrics"
+)
+
+// RateLimitConfig содержит настройки ограничения частоты запросов
+type RateLimitConfig struct {
+	Name   string        // Имя лимита в метрике отклоненных запросов
+	Limit  int           // Количество запросов с одного адреса за окно (0 - ограничение отключено)
+	Window time.Duration // Длительность окна
+}
+
+// rateWindow - счетчик запросов клиента в текущем окне
+type rateWindow struct {
+	count   int
+	resetAt time.Time
+}
+
+// rateLimiter считает запросы по клиентам в фиксированных окнах
+type rateLimiter struct {
+	mu        sync.Mutex
+	limit     int
+	window    time.Duration
+	clients   map[string]*rateWindow
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func newRateLimiter(cfg RateLimitConfig) *rateLimiter {
+	return &rateLimiter{
+		limit:   cfg.Limit,
+		window:  cfg.Window,
+		clients: make(map[string]*rateWindow),
+		now:     time.Now,
+	}
+}
+
+// allow учитывает запрос клиента и возвращает остаток запросов и время сброса окна
+func (l *rateLimiter) allow(key string) (remaining int, resetAt time.Time, ok bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	w, exists := l.clients[key]
+	if !exists || !now.Before(w.resetAt) {
+		w = &rateWindow{resetAt: now.Add(l.window)}
+		l.clients[key] = w
+	}
+
+	if w.count >= l.limit {
+		return 0, w.resetAt, false
+	}
+	w.count++
+	return l.limit - w.count, w.resetAt, true
+}
+
+// sweep раз в окно удаляет клиентов с истекшими окнами. Вызывается под mu.
+func (l *rateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.window {
+		return
+	}
+	l.lastSweep = now
+	for key, w := range l.clients {
+		if !now.Before(w.resetAt) {
+			delete(l.clients, key)
+		}
+	}
+}
+
+// RateLimitMiddleware ограничивает частоту запросов с одного адреса.
+// Каждый ответ содержит X-RateLimit-Limit, X-RateLimit-Remaining и X-RateLimit-Reset
+// (Unix-время сброса окна), чтобы клиент мог снизить темп до получения 429.
+func RateLimitMiddleware(cfg RateLimitConfig, m *metrics.Metrics) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if cfg.Limit <= 0 || cfg.Window <= 0 {
+			return next
+		}
+
+		limiter := newRateLimiter(cfg)
+		limit := strconv.Itoa(cfg.Limit)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			remaining, resetAt, ok := limiter.allow(clientAddr(r))
+
+			h := w.Header()
+			h.Set("X-RateLimit-Limit", limit)
+			h.Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+			h.Set("X-RateLimit-Reset", strconv.FormatInt(resetAt.Unix(), 10))
+
+			if !ok {
+				retryAfter := int(math.Ceil(resetAt.Sub(limiter.now()).Seconds()))
+				h.Set("Retry-After", strconv.Itoa(max(retryAfter, 1)))
+				m.ObserveRateLimited(cfg.Name)
+				writeJSONError(w, r, http.StatusTooManyRequests, "rate limit exceeded")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// clientAddr возвращает IP клиента без порта
+func clientAddr(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
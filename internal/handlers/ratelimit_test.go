@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter_Allow(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	limiter := newRateLimiter(RateLimitConfig{Limit: 2, Window: time.Minute})
+	limiter.now = func() time.Time { return now }
+
+	remaining, resetAt, ok := limiter.allow("10.0.0.1")
+	assert.True(t, ok)
+	assert.Equal(t, 1, remaining)
+	assert.Equal(t, now.Add(time.Minute), resetAt)
+
+	remaining, _, ok = limiter.allow("10.0.0.1")
+	assert.True(t, ok)
+	assert.Equal(t, 0, remaining)
+
+	_, _, ok = limiter.allow("10.0.0.1")
+	assert.False(t, ok)
+
+	// Другие клиенты считаются отдельно
+	_, _, ok = limiter.allow("10.0.0.2")
+	assert.True(t, ok)
+
+	// После окончания окна счетчик сбрасывается, устаревшие окна удаляются
+	now = now.Add(time.Minute)
+	remaining, _, ok = limiter.allow("10.0.0.1")
+	assert.True(t, ok)
+	assert.Equal(t, 1, remaining)
+	assert.Len(t, limiter.clients, 1)
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	m := metrics.New()
+	handler := RateLimitMiddleware(RateLimitConfig{Name: "requests", Limit: 2, Window: time.Minute}, m)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	request := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/user/orders", nil)
+		req.RemoteAddr = "10.0.0.1:51234"
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	w := request()
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2", w.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "1", w.Header().Get("X-RateLimit-Remaining"))
+	reset, err := strconv.ParseInt(w.Header().Get("X-RateLimit-Reset"), 10, 64)
+	assert.NoError(t, err)
+	assert.InDelta(t, time.Now().Add(time.Minute).Unix(), reset, 1)
+
+	w = request()
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+
+	w = request()
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+	assert.Equal(t, "60", w.Header().Get("Retry-After"))
+	assert.JSONEq(t, `{"error":"rate limit exceeded"}`, w.Body.String())
+
+	scrape := httptest.NewRecorder()
+	m.Handler().ServeHTTP(scrape, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Contains(t, scrape.Body.String(), `gophermart_http_rate_limited_requests_total{limit="requests"} 1`)
+}
+
+func TestRateLimitMiddleware_Disabled(t *testing.T) {
+	handler := RateLimitMiddleware(RateLimitConfig{}, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("X-RateLimit-Limit"))
+}
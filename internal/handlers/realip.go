@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// RealIPMiddleware заменяет RemoteAddr запросов от доверенных прокси адресом клиента
+// из X-Forwarded-For, чтобы ограничение частоты, фильтр адресов и логи видели клиента,
+// а не прокси. Запросы от остальных адресов и с некорректным заголовком не меняются.
+// Должен подключаться первым. Без доверенных прокси отключен.
+func RealIPMiddleware(trustedProxies []netip.Prefix) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if len(trustedProxies) == 0 {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			peer, ok := parseRemoteAddr(r.RemoteAddr)
+			if !ok || !containsAddr(trustedProxies, peer) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if addr, ok := clientIP(r, trustedProxies); ok {
+				r.RemoteAddr = addr.String()
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// clientIP возвращает адрес клиента. Если запрос пришел от доверенного прокси,
+// X-Forwarded-For просматривается справа налево до первого адреса не из доверенных
+// сетей: левые значения заголовка клиент может подставить сам.
+func clientIP(r *http.Request, trustedProxies []netip.Prefix) (netip.Addr, bool) {
+	addr, ok := parseRemoteAddr(r.RemoteAddr)
+	if !ok {
+		return netip.Addr{}, false
+	}
+
+	var forwarded []string
+	for _, header := range r.Header.Values("X-Forwarded-For") {
+		forwarded = append(forwarded, strings.Split(header, ",")...)
+	}
+
+	for i := len(forwarded) - 1; i >= 0 && containsAddr(trustedProxies, addr); i-- {
+		hop, err := netip.ParseAddr(strings.TrimSpace(forwarded[i]))
+		if err != nil {
+			// Доверенный прокси передал некорректный адрес - клиент неизвестен
+			return netip.Addr{}, false
+		}
+		addr = hop.Unmap()
+	}
+	return addr, true
+}
+
+// parseRemoteAddr разбирает адрес соединения с портом или без него
+func parseRemoteAddr(remoteAddr string) (netip.Addr, bool) {
+	if addrPort, err := netip.ParseAddrPort(remoteAddr); err == nil {
+		return addrPort.Addr().Unmap(), true
+	}
+	if addr, err := netip.ParseAddr(remoteAddr); err == nil {
+		return addr.Unmap(), true
+	}
+	return netip.Addr{}, false
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientIP(t *testing.T) {
+	trusted := prefixes("10.0.0.0/8")
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  []string
+		want       string
+		ok         bool
+	}{
+		{"direct connection", "203.0.113.5:5000", nil, "203.0.113.5", true},
+		{"untrusted peer header ignored", "203.0.113.5:5000", []string{"198.51.100.1"}, "203.0.113.5", true},
+		{"trusted proxy", "10.0.0.2:5000", []string{"198.51.100.1"}, "198.51.100.1", true},
+		{"spoofed left values ignored", "10.0.0.2:5000", []string{"1.1.1.1, 198.51.100.1, 10.0.0.3"}, "198.51.100.1", true},
+		{"multiple headers", "10.0.0.2:5000", []string{"1.1.1.1", "198.51.100.1"}, "198.51.100.1", true},
+		{"only proxies", "10.0.0.2:5000", []string{"10.0.0.3"}, "10.0.0.3", true},
+		{"trusted proxy without header", "10.0.0.2:5000", nil, "10.0.0.2", true},
+		{"ipv4 mapped ipv6", "[::ffff:10.0.0.2]:5000", []string{"198.51.100.1"}, "198.51.100.1", true},
+		{"invalid forwarded address", "10.0.0.2:5000", []string{"unknown"}, "", false},
+		{"invalid remote address", "pipe", nil, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for _, value := range tt.forwarded {
+				req.Header.Add("X-Forwarded-For", value)
+			}
+
+			addr, ok := clientIP(req, trusted)
+			assert.Equal(t, tt.ok, ok)
+			if tt.ok {
+				assert.Equal(t, tt.want, addr.String())
+			}
+		})
+	}
+}
+
+func TestRealIPMiddleware(t *testing.T) {
+	var remoteAddr string
+	handler := RealIPMiddleware(prefixes("10.0.0.0/8"))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		remoteAddr = r.RemoteAddr
+	}))
+
+	request := func(addr, forwarded string) string {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = addr
+		req.Header.Set("X-Forwarded-For", forwarded)
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		return remoteAddr
+	}
+
+	assert.Equal(t, "198.51.100.1", request("10.0.0.2:5000", "198.51.100.1"))
+	assert.Equal(t, "2001:db8::1", request("10.0.0.2:5000", "2001:db8::1"))
+	// Заголовок от недоверенного адреса и некорректный заголовок не меняют адрес
+	assert.Equal(t, "203.0.113.5:5000", request("203.0.113.5:5000", "198.51.100.1"))
+	assert.Equal(t, "10.0.0.2:5000", request("10.0.0.2:5000", "garbage"))
+
+	// Адрес без порта разбирается ограничением частоты и фильтром адресов
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = request("10.0.0.2:5000", "2001:db8::1")
+	assert.Equal(t, "2001:db8::1", clientAddr(req))
+}
+
+func TestRealIPMiddleware_NoTrustedProxies(t *testing.T) {
+	var remoteAddr string
+	handler := RealIPMiddleware(nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		remoteAddr = r.RemoteAddr
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "10.0.0.2:5000"
+	req.Header.Set("X-Forwarded-For", "198.51.100.1")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, "10.0.0.2:5000", remoteAddr)
+}
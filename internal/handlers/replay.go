@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/logctx"
+	"go.uber.org/zap"
+)
+
+// Заголовки защиты от повтора запросов
+const (
+	requestTimestampHeader = "X-Request-Timestamp"
+	requestNonceHeader     = "X-Request-Nonce"
+)
+
+// maxNonceLength ограничивает длину nonce: он хранится весь срок окна
+const maxNonceLength = 128
+
+// NonceStore запоминает nonce запросов на короткий срок (internal/noncestore)
+type NonceStore interface {
+	// Remember запоминает nonce на ttl; false - nonce уже предъявлялся
+	Remember(ctx context.Context, nonce string, ttl time.Duration) (bool, error)
+}
+
+// ReplayProtectionConfig содержит настройки защиты от повтора запросов
+type ReplayProtectionConfig struct {
+	Window time.Duration // Допустимое расхождение времени запроса с часами сервера (0 - защита выключена)
+}
+
+// ReplayProtectionMiddleware отклоняет устаревшие и повторные запросы. Запрос несет время
+// отправки в X-Request-Timestamp (Unix-время в секундах) и случайный nonce в X-Request-Nonce.
+// Запрос старше или новее Window отклоняется, а nonce запоминается на 2*Window - весь срок,
+// пока запрос с ним проходит проверку времени, - поэтому перехваченный запрос нельзя повторить.
+// Подключается к группам маршрутов после проверки подлинности, чтобы чужие запросы не занимали nonce.
+func ReplayProtectionMiddleware(cfg ReplayProtectionConfig, nonces NonceStore, logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if cfg.Window <= 0 {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			sent, err := strconv.ParseInt(r.Header.Get(requestTimestampHeader), 10, 64)
+			nonce := r.Header.Get(requestNonceHeader)
+			if err != nil || nonce == "" || len(nonce) > maxNonceLength {
+				writeJSONError(w, r, http.StatusBadRequest, "X-Request-Timestamp and X-Request-Nonce headers are required")
+				return
+			}
+
+			skew := time.Since(time.Unix(sent, 0))
+			if skew > cfg.Window || skew < -cfg.Window {
+				logger.Warn("request rejected: stale timestamp",
+					zap.String("path", r.URL.Path),
+					zap.Duration("skew", skew),
+				)
+				writeJSONError(w, r, http.StatusUnauthorized, "request timestamp is outside the allowed window")
+				return
+			}
+
+			fresh, err := nonces.Remember(r.Context(), nonce, 2*cfg.Window)
+			if err != nil {
+				logctx.From(r.Context()).Error("failed to store request nonce", zap.Error(err))
+				writeInternalError(w, err)
+				return
+			}
+			if !fresh {
+				logger.Warn("request rejected: replayed nonce", zap.String("path", r.URL.Path))
+				writeJSONError(w, r, http.StatusUnauthorized, "request has already been processed")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
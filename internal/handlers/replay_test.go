@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	domainmocks "github.com/avc/loyalty-system-diploma/internal/domain/mocks"
+	"github.com/avc/loyalty-system-diploma/internal/noncestore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestReplayProtectionMiddleware(t *testing.T) {
+	handler := ReplayProtectionMiddleware(ReplayProtectionConfig{Window: time.Minute}, noncestore.NewMemoryStore(), zap.NewNop())(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+
+	now := time.Now().Unix()
+	tests := []struct {
+		name      string
+		timestamp string
+		nonce     string
+		want      int
+	}{
+		{"fresh request", strconv.FormatInt(now, 10), "n-1", http.StatusOK},
+		{"replayed nonce", strconv.FormatInt(now, 10), "n-1", http.StatusUnauthorized},
+		{"clock slightly ahead", strconv.FormatInt(now+30, 10), "n-2", http.StatusOK},
+		{"stale request", strconv.FormatInt(now-120, 10), "n-3", http.StatusUnauthorized},
+		{"request from the future", strconv.FormatInt(now+120, 10), "n-4", http.StatusUnauthorized},
+		{"missing timestamp", "", "n-5", http.StatusBadRequest},
+		{"invalid timestamp", "yesterday", "n-6", http.StatusBadRequest},
+		{"missing nonce", strconv.FormatInt(now, 10), "", http.StatusBadRequest},
+		{"nonce too long", strconv.FormatInt(now, 10), string(make([]byte, 129)), http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/internal/orders/status", nil)
+			if tt.timestamp != "" {
+				req.Header.Set("X-Request-Timestamp", tt.timestamp)
+			}
+			if tt.nonce != "" {
+				req.Header.Set("X-Request-Nonce", tt.nonce)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.want, w.Code)
+		})
+	}
+}
+
+func TestReplayProtectionMiddleware_StaleRequestKeepsNonce(t *testing.T) {
+	handler := ReplayProtectionMiddleware(ReplayProtectionConfig{Window: time.Minute}, noncestore.NewMemoryStore(), zap.NewNop())(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+
+	send := func(timestamp int64) int {
+		req := httptest.NewRequest(http.MethodPost, "/api/internal/orders/status", nil)
+		req.Header.Set("X-Request-Timestamp", strconv.FormatInt(timestamp, 10))
+		req.Header.Set("X-Request-Nonce", "n-1")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// Отклоненный по времени запрос не занимает nonce
+	assert.Equal(t, http.StatusUnauthorized, send(time.Now().Add(-time.Hour).Unix()))
+	assert.Equal(t, http.StatusOK, send(time.Now().Unix()))
+}
+
+func TestReplayProtectionMiddleware_StoreError(t *testing.T) {
+	store := domainmocks.NewNonceStoreMock(t)
+	store.EXPECT().Remember(mock.Anything, "n-1", 2*time.Minute).Return(false, errors.New("redis down")).Once()
+	handler := ReplayProtectionMiddleware(ReplayProtectionConfig{Window: time.Minute}, store, zap.NewNop())(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/internal/orders/status", nil)
+	req.Header.Set("X-Request-Timestamp", strconv.FormatInt(time.Now().Unix(), 10))
+	req.Header.Set("X-Request-Nonce", "n-1")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestReplayProtectionMiddleware_Disabled(t *testing.T) {
+	handler := ReplayProtectionMiddleware(ReplayProtectionConfig{}, nil, zap.NewNop())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/internal/orders/status", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"go.uber.org/zap"
+)
+
+// RewardRuleService определяет чтение локальной копии правил вознаграждения.
+type RewardRuleService interface {
+	ListRewardRules(ctx context.Context) (*domain.RewardRuleSet, error)
+}
+
+// RewardRulesHandler обрабатывает административные запросы к копии правил вознаграждения
+type RewardRulesHandler struct {
+	service RewardRuleService
+	logger  *zap.Logger
+}
+
+// NewRewardRulesHandler создает новый RewardRulesHandler
+func NewRewardRulesHandler(service RewardRuleService, logger *zap.Logger) *RewardRulesHandler {
+	return &RewardRulesHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// List возвращает локальную копию правил вознаграждения системы начислений
+func (h *RewardRulesHandler) List(w http.ResponseWriter, r *http.Request) {
+	set, err := h.service.ListRewardRules(r.Context())
+	if err != nil {
+		h.logger.Error("failed to list reward rules", zap.Error(err))
+		writeInternalError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newRewardRulesResponse(set)); err != nil {
+		h.logger.Error("failed to encode reward rules response", zap.Error(err))
+	}
+}
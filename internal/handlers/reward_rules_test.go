@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	domainmocks "github.com/avc/loyalty-system-diploma/internal/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestRewardRulesHandler_List(t *testing.T) {
+	svc := domainmocks.NewRewardRuleServiceMock(t)
+	handler := NewRewardRulesHandler(svc, zap.NewNop())
+	checkedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	svc.EXPECT().ListRewardRules(mock.Anything).Return(&domain.RewardRuleSet{
+		Rules: []*domain.RewardRule{
+			{Match: "Bork", Reward: 10, RewardType: domain.RewardTypePercent, UpdatedAt: checkedAt.Add(-time.Hour)},
+		},
+		CheckedAt: checkedAt,
+		ChangedAt: checkedAt.Add(-time.Hour),
+	}, nil).Once()
+
+	w := httptest.NewRecorder()
+	handler.List(w, httptest.NewRequest(http.MethodGet, "/api/admin/reward-rules", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{
+		"rules": [{"match":"Bork","reward":10,"reward_type":"%","updated_at":"2024-01-02T02:04:05Z"}],
+		"checked_at": "2024-01-02T03:04:05Z",
+		"changed_at": "2024-01-02T02:04:05Z"
+	}`, w.Body.String())
+
+	// До первой синхронизации - пустой список без времени синхронизации
+	svc.EXPECT().ListRewardRules(mock.Anything).Return(&domain.RewardRuleSet{}, nil).Once()
+	w = httptest.NewRecorder()
+	handler.List(w, httptest.NewRequest(http.MethodGet, "/api/admin/reward-rules", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"rules":[]}`, w.Body.String())
+
+	svc.EXPECT().ListRewardRules(mock.Anything).Return(nil, errors.New("db error")).Once()
+	w = httptest.NewRecorder()
+	handler.List(w, httptest.NewRequest(http.MethodGet, "/api/admin/reward-rules", nil))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// routeMethods - методы, которые проверяются при формировании заголовка Allow
+var routeMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+	http.MethodOptions,
+}
+
+// NotFoundHandler отвечает 404 в JSON формате вместо текстового ответа chi
+func NotFoundHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeJSONError(w, r, http.StatusNotFound, "route not found")
+	}
+}
+
+// MethodNotAllowedHandler отвечает 405 в JSON формате с заголовком Allow.
+// Собственный обработчик chi не получает список методов маршрута,
+// поэтому они определяются через routes.Match.
+func MethodNotAllowedHandler(routes chi.Routes) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		for _, method := range allowedMethods(routes, r.URL.Path) {
+			w.Header().Add("Allow", method)
+		}
+		writeJSONError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// MethodsMiddleware обслуживает HEAD и OPTIONS для маршрутов без собственных обработчиков
+// этих методов. HEAD выполняет GET обработчик и отдает только заголовки с длиной тела,
+// которое вернул бы GET. OPTIONS отвечает 204 со списком методов пути в Allow,
+// по нему шлюзы проверяют маршрут до того, как пускать на него трафик
+func MethodsMiddleware(routes chi.Routes) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodHead:
+				rctx := chi.RouteContext(r.Context())
+				if rctx == nil || routes.Match(chi.NewRouteContext(), http.MethodHead, r.URL.Path) ||
+					!routes.Match(chi.NewRouteContext(), http.MethodGet, r.URL.Path) {
+					break
+				}
+				rctx.RouteMethod = http.MethodGet
+				hw := &headResponseWriter{ResponseWriter: w}
+				next.ServeHTTP(hw, r)
+				hw.finish()
+				return
+			case http.MethodOptions:
+				if routes.Match(chi.NewRouteContext(), http.MethodOptions, r.URL.Path) {
+					break
+				}
+				allowed := allowedMethods(routes, r.URL.Path)
+				if len(allowed) == 0 {
+					break
+				}
+				for _, method := range allowed {
+					w.Header().Add("Allow", method)
+				}
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// allowedMethods возвращает методы пути в порядке routeMethods. HEAD доступен
+// для каждого GET маршрута, OPTIONS - для каждого существующего пути
+func allowedMethods(routes chi.Routes, path string) []string {
+	var matched []string
+	for _, method := range routeMethods {
+		if routes.Match(chi.NewRouteContext(), method, path) {
+			matched = append(matched, method)
+		}
+	}
+	if len(matched) == 0 {
+		return nil
+	}
+
+	var allowed []string
+	for _, method := range routeMethods {
+		switch {
+		case slices.Contains(matched, method),
+			method == http.MethodHead && slices.Contains(matched, http.MethodGet),
+			method == http.MethodOptions:
+			allowed = append(allowed, method)
+		}
+	}
+	return allowed
+}
+
+// headResponseWriter отбрасывает тело ответа на HEAD и считает его длину. Заголовки
+// отправляются после обработчика, чтобы Content-Length совпал с длиной тела GET
+type headResponseWriter struct {
+	http.ResponseWriter
+	status int
+	length int
+}
+
+func (w *headResponseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *headResponseWriter) Write(p []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	w.length += len(p)
+	return len(p), nil
+}
+
+// finish отправляет заголовки ответа с длиной отброшенного тела
+func (w *headResponseWriter) finish() {
+	w.WriteHeader(http.StatusOK)
+	if bodyAllowedForStatus(w.status) {
+		w.Header().Set("Content-Length", strconv.Itoa(w.length))
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+}
+
+// bodyAllowedForStatus сообщает, может ли ответ с этим статусом иметь тело
+func bodyAllowedForStatus(status int) bool {
+	return status >= http.StatusOK && status != http.StatusNoContent && status != http.StatusNotModified
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRoutingTestRouter() *chi.Mux {
+	r := chi.NewRouter()
+	noop := func(http.ResponseWriter, *http.Request) {}
+	r.Get("/items", noop)
+	r.Post("/items", noop)
+	r.NotFound(NotFoundHandler())
+	r.MethodNotAllowed(MethodNotAllowedHandler(r))
+	return r
+}
+
+func TestNotFoundHandler(t *testing.T) {
+	w := httptest.NewRecorder()
+	newRoutingTestRouter().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/unknown", nil))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	var body ErrorResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+	assert.Equal(t, "route not found", body.Error)
+}
+
+func TestMethodNotAllowedHandler(t *testing.T) {
+	w := httptest.NewRecorder()
+	newRoutingTestRouter().ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/items", nil))
+
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Equal(t, []string{http.MethodGet, http.MethodHead, http.MethodPost, http.MethodOptions}, w.Header().Values("Allow"))
+
+	var body ErrorResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+	assert.Equal(t, "method not allowed", body.Error)
+}
+
+func newMethodsTestRouter() *chi.Mux {
+	r := chi.NewRouter()
+	r.Use(MethodsMiddleware(r))
+	r.Get("/items", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Method", r.Method)
+		_, _ = w.Write([]byte(`[{"id":1},`))
+		_, _ = w.Write([]byte(`{"id":2}]`))
+	})
+	r.Post("/items", func(w http.ResponseWriter, r *http.Request) {})
+	r.Get("/empty", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	r.Head("/custom", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Custom", "head")
+	})
+	r.Get("/custom", func(w http.ResponseWriter, r *http.Request) {})
+	r.Options("/custom", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	r.NotFound(NotFoundHandler())
+	r.MethodNotAllowed(MethodNotAllowedHandler(r))
+	return r
+}
+
+func TestMethodsMiddleware_Head(t *testing.T) {
+	router := newMethodsTestRouter()
+
+	get := httptest.NewRecorder()
+	router.ServeHTTP(get, httptest.NewRequest(http.MethodGet, "/items", nil))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodHead, "/items", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Body.String())
+	assert.Equal(t, strconv.Itoa(get.Body.Len()), w.Header().Get("Content-Length"))
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Equal(t, http.MethodHead, w.Header().Get("X-Method"))
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodHead, "/empty", nil))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Empty(t, w.Header().Get("Content-Length"))
+
+	// Собственный обработчик HEAD не подменяется
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodHead, "/custom", nil))
+	assert.Equal(t, "head", w.Header().Get("X-Custom"))
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodHead, "/unknown", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestMethodsMiddleware_Options(t *testing.T) {
+	router := newMethodsTestRouter()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodOptions, "/items", nil))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, []string{http.MethodGet, http.MethodHead, http.MethodPost, http.MethodOptions}, w.Header().Values("Allow"))
+	assert.Empty(t, w.Body.String())
+
+	// Собственный обработчик OPTIONS не подменяется
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodOptions, "/custom", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodOptions, "/unknown", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/avc/loyalty-system-diploma/internal/logctx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestServiceTokenMiddleware(t *testing.T) {
+	tokens := map[string]string{"storefront": "token-1", "billing": "token-2"}
+
+	tests := []struct {
+		name           string
+		tokens         map[string]string
+		authorization  string
+		expectedStatus int
+		expectedName   string
+	}{
+		{"Known token", tokens, "Bearer token-2", http.StatusOK, "billing"},
+		{"Unknown token", tokens, "Bearer token-3", http.StatusUnauthorized, ""},
+		{"Token prefix", tokens, "Bearer token", http.StatusUnauthorized, ""},
+		{"No header", tokens, "", http.StatusUnauthorized, ""},
+		{"Not bearer", tokens, "Basic token-1", http.StatusUnauthorized, ""},
+		{"No tokens configured", nil, "Bearer token-1", http.StatusUnauthorized, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zap.InfoLevel)
+			handler := ContextLoggerMiddleware(zap.New(core))(
+				ServiceTokenMiddleware(tt.tokens, zap.NewNop())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					logctx.From(r.Context()).Info("handled")
+				})))
+
+			req := httptest.NewRequest(http.MethodPost, "/api/internal/orders/status", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedName == "" {
+				assert.Zero(t, logs.Len())
+				return
+			}
+			require.Equal(t, 1, logs.Len())
+			assert.Equal(t, tt.expectedName, logs.All()[0].ContextMap()["service"])
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/avc/loyalty-system-diploma/internal/locale"
+)
+
+// writeStatement пишет выписку по счету для выгрузки данных пользователя:
+// итоги и операции с суммами и датами в формате языка l. Суммы списаний в журнале
+// отрицательные, отрицательная корректировка уменьшает начисленное
+func writeStatement(w io.Writer, export *domain.UserDataExport, l locale.Locale) error {
+	var accrued, withdrawn float64
+	for _, tx := range export.Transactions {
+		if tx.Type == domain.TransactionTypeWithdrawal {
+			withdrawn -= tx.Amount
+		} else {
+			accrued += tx.Amount
+		}
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "%s\n\n", l.Translate("Account statement"))
+	fmt.Fprintf(tw, "%s:\t%s\n", l.Translate("Login"), export.User.Login)
+	fmt.Fprintf(tw, "%s:\t%s\n", l.Translate("Generated"), l.FormatDate(export.GeneratedAt))
+	fmt.Fprintf(tw, "%s:\t%s\n", l.Translate("Accrued"), l.FormatAmount(accrued))
+	fmt.Fprintf(tw, "%s:\t%s\n", l.Translate("Withdrawn"), l.FormatAmount(withdrawn))
+	fmt.Fprintf(tw, "%s:\t%s\n\n", l.Translate("Balance"), l.FormatAmount(accrued-withdrawn))
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if len(export.Transactions) == 0 {
+		_, err := fmt.Fprintln(w, l.Translate("No transactions"))
+		return err
+	}
+
+	// Суммы выравниваются по правому краю
+	tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t\n",
+		l.Translate("Date"), l.Translate("Order"), l.Translate("Operation"), l.Translate("Amount, points"))
+	for _, tx := range export.Transactions {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t\n",
+			l.FormatDate(tx.ProcessedAt), tx.OrderNumber, l.Translate(string(tx.Type)), l.FormatAmount(tx.Amount))
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/avc/loyalty-system-diploma/internal/locale"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteStatement(t *testing.T) {
+	at := time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC)
+	export := &domain.UserDataExport{
+		User: &domain.User{Login: "alice"},
+		Transactions: []*domain.Transaction{
+			{OrderNumber: "12345678903", Amount: 1500, Type: domain.TransactionTypeAccrual, ProcessedAt: at},
+			{OrderNumber: "2377225624", Amount: -250.5, Type: domain.TransactionTypeWithdrawal, ProcessedAt: at},
+		},
+		GeneratedAt: at,
+	}
+
+	t.Run("English", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, writeStatement(&buf, export, locale.English))
+
+		statement := buf.String()
+		assert.Contains(t, statement, "Account statement")
+		assert.Contains(t, statement, "Generated:  2024-03-05")
+		assert.Contains(t, statement, "Accrued:    1,500.00")
+		assert.Contains(t, statement, "Withdrawn:  250.50")
+		assert.Contains(t, statement, "Balance:    1,249.50")
+		assert.Contains(t, statement, "withdrawal")
+		assert.Contains(t, statement, "-250.50")
+	})
+
+	t.Run("Russian", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, writeStatement(&buf, export, locale.Russian))
+
+		statement := buf.String()
+		assert.Contains(t, statement, "Выписка по счету")
+		assert.Contains(t, statement, "05.03.2024")
+		assert.Contains(t, statement, "1\u00a0500,00")
+		assert.Contains(t, statement, "1\u00a0249,50")
+		assert.Contains(t, statement, "списание")
+		assert.Contains(t, statement, "-250,50")
+	})
+
+	t.Run("No transactions", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, writeStatement(&buf, &domain.UserDataExport{User: &domain.User{Login: "bob"}}, locale.Russian))
+
+		assert.Contains(t, buf.String(), "Операций нет")
+		assert.Contains(t, buf.String(), "Баланс:")
+	})
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// SubmissionBanService определяет просмотр и снятие запретов загрузки заказов.
+type SubmissionBanService interface {
+	ListSubmissionBans(ctx context.Context) ([]*domain.SubmissionBan, error)
+	LiftSubmissionBan(ctx context.Context, login string) error
+}
+
+// SubmissionBansHandler обрабатывает административные запросы к запретам загрузки заказов
+type SubmissionBansHandler struct {
+	service SubmissionBanService
+	logger  *zap.Logger
+}
+
+// NewSubmissionBansHandler создает новый SubmissionBansHandler
+func NewSubmissionBansHandler(service SubmissionBanService, logger *zap.Logger) *SubmissionBansHandler {
+	return &SubmissionBansHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// List возвращает действующие запреты загрузки заказов
+func (h *SubmissionBansHandler) List(w http.ResponseWriter, r *http.Request) {
+	bans, err := h.service.ListSubmissionBans(r.Context())
+	if err != nil {
+		h.logger.Error("failed to list submission bans", zap.Error(err))
+		writeInternalError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newSubmissionBansResponse(bans)); err != nil {
+		h.logger.Error("failed to encode submission bans response", zap.Error(err))
+	}
+}
+
+// Lift досрочно снимает запрет загрузки заказов с пользователя
+func (h *SubmissionBansHandler) Lift(w http.ResponseWriter, r *http.Request) {
+	err := h.service.LiftSubmissionBan(r.Context(), chi.URLParam(r, "login"))
+	switch {
+	case err == nil:
+		w.WriteHeader(http.StatusNoContent)
+	case errors.Is(err, domain.ErrUserNotFound):
+		writeJSONError(w, r, http.StatusNotFound, "user not found")
+	case errors.Is(err, domain.ErrSubmissionBanNotFound):
+		writeJSONError(w, r, http.StatusNotFound, "submission ban not found")
+	default:
+		h.logger.Error("failed to lift submission ban", zap.Error(err))
+		writeInternalError(w, err)
+	}
+}
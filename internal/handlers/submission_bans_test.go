@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	domainmocks "github.com/avc/loyalty-system-diploma/internal/domain/mocks"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newSubmissionBansRouter(handler *SubmissionBansHandler) http.Handler {
+	r := chi.NewRouter()
+	r.Get("/api/admin/submission-bans", handler.List)
+	r.Delete("/api/admin/users/{login}/submission-ban", handler.Lift)
+	return r
+}
+
+func TestSubmissionBansHandler_List(t *testing.T) {
+	svc := domainmocks.NewSubmissionBanServiceMock(t)
+	router := newSubmissionBansRouter(NewSubmissionBansHandler(svc, zap.NewNop()))
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	svc.EXPECT().ListSubmissionBans(mock.Anything).Return([]*domain.SubmissionBan{{
+		UserID:      1,
+		Login:       "alice",
+		Reason:      domain.SubmissionBanReasonConflicts,
+		CreatedAt:   createdAt,
+		BannedUntil: createdAt.Add(24 * time.Hour),
+	}}, nil).Once()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/admin/submission-bans", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[{"login":"alice","reason":"conflicts","created_at":"2024-01-02T03:04:05Z","banned_until":"2024-01-03T03:04:05Z"}]`, w.Body.String())
+
+	// Без запретов - пустой список, а не null
+	svc.EXPECT().ListSubmissionBans(mock.Anything).Return(nil, nil).Once()
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/admin/submission-bans", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[]`, w.Body.String())
+
+	svc.EXPECT().ListSubmissionBans(mock.Anything).Return(nil, errors.New("db error")).Once()
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/admin/submission-bans", nil))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestSubmissionBansHandler_Lift(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{name: "Lifted", expectedStatus: http.StatusNoContent},
+		{name: "Unknown user", err: domain.ErrUserNotFound, expectedStatus: http.StatusNotFound},
+		{name: "No active ban", err: domain.ErrSubmissionBanNotFound, expectedStatus: http.StatusNotFound},
+		{name: "Database error", err: errors.New("db error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := domainmocks.NewSubmissionBanServiceMock(t)
+			router := newSubmissionBansRouter(NewSubmissionBansHandler(svc, zap.NewNop()))
+			svc.EXPECT().LiftSubmissionBan(mock.Anything, "alice").Return(tt.err).Once()
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/admin/users/alice/submission-ban", nil))
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/avc/loyalty-system-diploma/internal/jobs"
+	"github.com/avc/loyalty-system-diploma/internal/locale"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// UserExportService определяет сбор данных пользователя для выгрузки.
+type UserExportService interface {
+	ExportUserData(ctx context.Context, userID int64, progress jobs.Progress) (*domain.UserDataExport, error)
+}
+
+// UserJobRunner определяет запуск фоновых задач от имени пользователя и получение их состояния.
+type UserJobRunner interface {
+	StartFor(jobType string, owner int64, fn jobs.Func) jobs.Snapshot
+	Latest(jobType string, owner int64) (jobs.Snapshot, error)
+	Result(id string) (jobs.Snapshot, *jobs.Result, error)
+}
+
+const (
+	jobTypeUserExport = "user_export"
+
+	mediaTypeZIP = "application/zip"
+)
+
+// UserExportHandler обрабатывает запросы на выгрузку данных пользователя
+type UserExportHandler struct {
+	service UserExportService
+	jobs    UserJobRunner
+	logger  *zap.Logger
+}
+
+// NewUserExportHandler создает новый UserExportHandler
+func NewUserExportHandler(service UserExportService, jobs UserJobRunner, logger *zap.Logger) *UserExportHandler {
+	return &UserExportHandler{
+		service: service,
+		jobs:    jobs,
+		logger:  logger,
+	}
+}
+
+// Export запускает выгрузку данных текущего пользователя. Пока предыдущая выгрузка
+// выполняется или ее архив не удален, возвращается она, а не запускается новая
+func (h *UserExportHandler) Export(w http.ResponseWriter, r *http.Request) {
+	userID, ok := GetUserID(r.Context())
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	if snapshot, err := h.jobs.Latest(jobTypeUserExport, userID); err == nil && snapshot.Status != jobs.StatusFailed {
+		h.writeJob(w, http.StatusOK, snapshot)
+		return
+	}
+
+	// Задача выполняется после ответа, поэтому язык выписки определяется сейчас
+	loc := locale.From(r.Context())
+	snapshot := h.jobs.StartFor(jobTypeUserExport, userID, func(ctx context.Context, progress jobs.Progress) (*jobs.Result, error) {
+		ctx = locale.With(ctx, loc)
+		export, err := h.service.ExportUserData(ctx, userID, progress)
+		if err != nil {
+			return nil, err
+		}
+		return encodeUserExportResult(export, locale.From(ctx))
+	})
+
+	w.Header().Set("Location", userExportPath(snapshot.ID))
+	h.writeJob(w, http.StatusAccepted, snapshot)
+}
+
+// Get возвращает состояние выгрузки текущего пользователя
+func (h *UserExportHandler) Get(w http.ResponseWriter, r *http.Request) {
+	snapshot, _, ok := h.find(w, r)
+	if !ok {
+		return
+	}
+
+	h.writeJob(w, http.StatusOK, snapshot)
+}
+
+// Download отдает архив завершенной выгрузки текущего пользователя
+func (h *UserExportHandler) Download(w http.ResponseWriter, r *http.Request) {
+	snapshot, result, ok := h.find(w, r)
+	if !ok {
+		return
+	}
+
+	switch {
+	case snapshot.Status == jobs.StatusRunning:
+		writeJSONError(w, r, http.StatusConflict, "export is still running")
+		return
+	case snapshot.Status == jobs.StatusFailed:
+		writeJSONError(w, r, http.StatusConflict, "export failed")
+		return
+	case result == nil:
+		writeJSONError(w, r, http.StatusConflict, "export has no result")
+		return
+	}
+
+	w.Header().Set("Content-Type", result.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="user-export-%s.zip"`, snapshot.ID))
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(result.Data); err != nil {
+		h.logger.Error("failed to write user export", zap.Error(err), zap.String("job_id", snapshot.ID))
+	}
+}
+
+// find возвращает выгрузку из пути запроса. Чужая выгрузка не отличается от несуществующей
+func (h *UserExportHandler) find(w http.ResponseWriter, r *http.Request) (jobs.Snapshot, *jobs.Result, bool) {
+	userID, ok := GetUserID(r.Context())
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return jobs.Snapshot{}, nil, false
+	}
+
+	snapshot, result, err := h.jobs.Result(chi.URLParam(r, "id"))
+	if err == nil && (snapshot.Type != jobTypeUserExport || snapshot.Owner != userID) {
+		err = jobs.ErrJobNotFound
+	}
+	if err != nil {
+		writeJSONError(w, r, http.StatusNotFound, "export not found")
+		return jobs.Snapshot{}, nil, false
+	}
+	return snapshot, result, true
+}
+
+// writeJob отвечает состоянием выгрузки; ссылка на результат ведет на скачивание архива.
+// Текст ошибки выгрузки не раскрывается, он есть в журнале
+func (h *UserExportHandler) writeJob(w http.ResponseWriter, status int, snapshot jobs.Snapshot) {
+	response := newJobResponse(snapshot)
+	response.Error = ""
+	if response.ResultURL != "" {
+		response.ResultURL = userExportPath(snapshot.ID) + "/download"
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error("failed to encode user export response", zap.Error(err))
+	}
+}
+
+// userExportPath возвращает путь эндпоинта состояния выгрузки
+func userExportPath(id string) string {
+	return "/api/user/export/" + id
+}
+
+// encodeUserExportResult упаковывает данные пользователя в ZIP архив:
+// profile.json, orders.json, transactions.json и выписку statement.txt на языке l
+func encodeUserExportResult(export *domain.UserDataExport, l locale.Locale) (*jobs.Result, error) {
+	if export == nil || export.User == nil {
+		return nil, errors.New("user export is empty")
+	}
+
+	files := []struct {
+		name  string
+		write func(io.Writer) error
+	}{
+		{"profile.json", jsonFile(newUserExportProfileResponse(export.User, export.Contacts))},
+		{"orders.json", jsonFile(newOrdersResponse(export.Orders))},
+		{"transactions.json", jsonFile(newUserExportTransactionsResponse(export.Transactions))},
+		{"statement.txt", func(w io.Writer) error { return writeStatement(w, export, l) }},
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, file := range files {
+		fw, err := archive.CreateHeader(&zip.FileHeader{
+			Name:     file.name,
+			Method:   zip.Deflate,
+			Modified: export.GeneratedAt,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to add %s to archive: %w", file.name, err)
+		}
+		if err := file.write(fw); err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %w", file.name, err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close archive: %w", err)
+	}
+	return &jobs.Result{ContentType: mediaTypeZIP, Data: buf.Bytes()}, nil
+}
+
+// jsonFile возвращает запись data в файл архива в виде JSON с отступами
+func jsonFile(data any) func(io.Writer) error {
+	return func(w io.Writer) error {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(data)
+	}
+}
@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	domainmocks "github.com/avc/loyalty-system-diploma/internal/domain/mocks"
+	"github.com/avc/loyalty-system-diploma/internal/jobs"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// newUserExportRouter собирает маршруты выгрузки так же, как приложение, от имени пользователя userID
+func newUserExportRouter(handler *UserExportHandler, userID int64) http.Handler {
+	r := chi.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), UserIDKey, userID)))
+		})
+	})
+	r.Get("/api/user/export", handler.Export)
+	r.Get("/api/user/export/{id}", handler.Get)
+	r.Get("/api/user/export/{id}/download", handler.Download)
+	return r
+}
+
+// startUserExport запрашивает выгрузку и ожидает ее завершения
+func startUserExport(t *testing.T, router http.Handler, manager *jobs.Manager) JobResponse {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/user/export", nil))
+	require.Equal(t, http.StatusAccepted, w.Code)
+	var started JobResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &started))
+	assert.Equal(t, userExportPath(started.ID), w.Header().Get("Location"))
+
+	require.Eventually(t, func() bool {
+		snapshot, err := manager.Get(started.ID)
+		require.NoError(t, err)
+		return snapshot.Status != jobs.StatusRunning
+	}, time.Second, 5*time.Millisecond)
+	return started
+}
+
+func TestUserExportHandler_Export(t *testing.T) {
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	txID := uuid.MustParse("6f1c0d5e-8a4b-4c1e-9f3a-2b7d5e8c9a10")
+	service := domainmocks.NewUserExportServiceMock(t)
+	service.EXPECT().ExportUserData(mock.Anything, int64(1), mock.Anything).
+		Return(&domain.UserDataExport{
+			User:     &domain.User{ID: 1, Login: "alice", CreatedAt: createdAt},
+			Contacts: &domain.UserContacts{Email: "alice@example.com"},
+			Orders:   []*domain.Order{{Number: "12345678903", Status: domain.OrderStatusProcessed, UploadedAt: createdAt}},
+			Transactions: []*domain.Transaction{
+				{PublicID: txID, OrderNumber: "2377225624", Amount: -100, Type: domain.TransactionTypeWithdrawal, ProcessedAt: createdAt},
+			},
+			GeneratedAt: createdAt,
+		}, nil).Once()
+
+	manager := jobs.NewManager(time.Hour, zap.NewNop())
+	defer manager.Shutdown()
+	router := newUserExportRouter(NewUserExportHandler(service, manager, zap.NewNop()), 1)
+
+	started := startUserExport(t, router, manager)
+
+	// Повторный запрос возвращает готовую выгрузку, а не запускает новую
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/user/export", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var job JobResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &job))
+	assert.Equal(t, started.ID, job.ID)
+	assert.Equal(t, string(jobs.StatusCompleted), job.Status)
+	assert.Equal(t, userExportPath(job.ID)+"/download", job.ResultURL)
+	require.NotNil(t, job.ExpiresAt)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), *job.ExpiresAt, time.Minute)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, job.ResultURL, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, mediaTypeZIP, w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "user-export-"+job.ID+".zip")
+
+	archive, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+	require.NoError(t, err)
+	files := make(map[string]string)
+	for _, f := range archive.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		require.NoError(t, err)
+		files[f.Name] = string(data)
+	}
+
+	require.Len(t, files, 4)
+	assert.JSONEq(t, `{"id":1,"login":"alice","created_at":"2024-01-02T03:04:05Z","contacts":{"email":"alice@example.com","phone":""}}`, files["profile.json"])
+	assert.JSONEq(t, `[{"id":"00000000-0000-0000-0000-000000000000","number":"12345678903","status":"PROCESSED","uploaded_at":"2024-01-02T03:04:05Z"}]`, files["orders.json"])
+	assert.JSONEq(t, `[{"id":"6f1c0d5e-8a4b-4c1e-9f3a-2b7d5e8c9a10","order":"2377225624","type":"withdrawal","amount":-100,"processed_at":"2024-01-02T03:04:05Z"}]`, files["transactions.json"])
+	assert.Contains(t, files["statement.txt"], "Account statement")
+}
+
+func TestUserExportHandler_Export_Locale(t *testing.T) {
+	service := domainmocks.NewUserExportServiceMock(t)
+	service.EXPECT().ExportUserData(mock.Anything, int64(1), mock.Anything).
+		Return(&domain.UserDataExport{User: &domain.User{ID: 1, Login: "alice"}}, nil).Once()
+
+	manager := jobs.NewManager(time.Hour, zap.NewNop())
+	defer manager.Shutdown()
+	r := chi.NewRouter()
+	r.Use(LocaleMiddleware())
+	r.Mount("/", newUserExportRouter(NewUserExportHandler(service, manager, zap.NewNop()), 1))
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/user/export", nil)
+	req.Header.Set("Accept-Language", "ru-RU,ru;q=0.9")
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusAccepted, w.Code)
+	var started JobResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &started))
+
+	require.Eventually(t, func() bool {
+		snapshot, err := manager.Get(started.ID)
+		require.NoError(t, err)
+		return snapshot.Status != jobs.StatusRunning
+	}, time.Second, 5*time.Millisecond)
+	_, result, err := manager.Result(started.ID)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+
+	archive, err := zip.NewReader(bytes.NewReader(result.Data), int64(len(result.Data)))
+	require.NoError(t, err)
+	rc, err := archive.Open("statement.txt")
+	require.NoError(t, err)
+	defer rc.Close()
+	statement, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	assert.Contains(t, string(statement), "Выписка по счету")
+	assert.Contains(t, string(statement), "Операций нет")
+}
+
+func TestUserExportHandler_FailedExport(t *testing.T) {
+	service := domainmocks.NewUserExportServiceMock(t)
+	service.EXPECT().ExportUserData(mock.Anything, int64(1), mock.Anything).Return(nil, errors.New("db is down")).Twice()
+
+	manager := jobs.NewManager(time.Hour, zap.NewNop())
+	defer manager.Shutdown()
+	router := newUserExportRouter(NewUserExportHandler(service, manager, zap.NewNop()), 1)
+
+	started := startUserExport(t, router, manager)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, userExportPath(started.ID), nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var job JobResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &job))
+	assert.Equal(t, string(jobs.StatusFailed), job.Status)
+	assert.Empty(t, job.Error)
+	assert.Empty(t, job.ResultURL)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, userExportPath(started.ID)+"/download", nil))
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	// После неудачи запрос запускает новую выгрузку
+	retried := startUserExport(t, router, manager)
+	assert.NotEqual(t, started.ID, retried.ID)
+}
+
+func TestUserExportHandler_ForeignExport(t *testing.T) {
+	service := domainmocks.NewUserExportServiceMock(t)
+	service.EXPECT().ExportUserData(mock.Anything, int64(1), mock.Anything).
+		Return(&domain.UserDataExport{User: &domain.User{ID: 1, Login: "alice"}}, nil).Once()
+
+	manager := jobs.NewManager(time.Hour, zap.NewNop())
+	defer manager.Shutdown()
+	handler := NewUserExportHandler(service, manager, zap.NewNop())
+	started := startUserExport(t, newUserExportRouter(handler, 1), manager)
+
+	// Чужая выгрузка и административная задача недоступны
+	other := newUserExportRouter(handler, 2)
+	adminJob := manager.StartFor(jobTypeUsersExport, 2, func(context.Context, jobs.Progress) (*jobs.Result, error) {
+		return nil, nil
+	})
+	for _, path := range []string{
+		userExportPath(started.ID),
+		userExportPath(started.ID) + "/download",
+		userExportPath(adminJob.ID),
+		userExportPath("unknown"),
+	} {
+		w := httptest.NewRecorder()
+		other.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusNotFound, w.Code, path)
+	}
+}
+
+func TestUserExportHandler_Unauthorized(t *testing.T) {
+	handler := NewUserExportHandler(domainmocks.NewUserExportServiceMock(t), jobs.NewManager(time.Hour, zap.NewNop()), zap.NewNop())
+
+	for _, h := range []http.HandlerFunc{handler.Export, handler.Get, handler.Download} {
+		w := httptest.NewRecorder()
+		h(w, httptest.NewRequest(http.MethodGet, "/api/user/export", nil))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	}
+}
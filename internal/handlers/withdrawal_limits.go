@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// WithdrawalLimitService определяет методы управления ограничениями списаний.
+type WithdrawalLimitService interface {
+	GetWithdrawalLimits(ctx context.Context, login string) (*domain.UserWithdrawalLimits, error)
+	SetWithdrawalLimits(ctx context.Context, login string, limits domain.WithdrawalLimits) (*domain.UserWithdrawalLimits, error)
+	ResetWithdrawalLimits(ctx context.Context, login string) error
+}
+
+// WithdrawalLimitsHandler обрабатывает административные запросы к ограничениям списаний
+type WithdrawalLimitsHandler struct {
+	service WithdrawalLimitService
+	logger  *zap.Logger
+}
+
+// NewWithdrawalLimitsHandler создает новый WithdrawalLimitsHandler
+func NewWithdrawalLimitsHandler(service WithdrawalLimitService, logger *zap.Logger) *WithdrawalLimitsHandler {
+	return &WithdrawalLimitsHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// withdrawalLimitsRequest - новые ограничения пользователя. Все поля обязательны,
+// чтобы пропущенное поле не снимало ограничение незаметно; 0 - без ограничения.
+type withdrawalLimitsRequest struct {
+	PerWithdrawal *float64 `json:"per_withdrawal"`
+	Daily         *float64 `json:"daily"`
+	Monthly       *float64 `json:"monthly"`
+}
+
+// Get возвращает действующие ограничения пользователя
+func (h *WithdrawalLimitsHandler) Get(w http.ResponseWriter, r *http.Request) {
+	limits, err := h.service.GetWithdrawalLimits(r.Context(), chi.URLParam(r, "login"))
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	h.writeLimits(w, limits)
+}
+
+// Set задает пользователю собственные ограничения вместо значений по умолчанию
+func (h *WithdrawalLimitsHandler) Set(w http.ResponseWriter, r *http.Request) {
+	var req withdrawalLimitsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.PerWithdrawal == nil || req.Daily == nil || req.Monthly == nil {
+		writeJSONError(w, r, http.StatusBadRequest, "per_withdrawal, daily and monthly are required")
+		return
+	}
+
+	limits, err := h.service.SetWithdrawalLimits(r.Context(), chi.URLParam(r, "login"), domain.WithdrawalLimits{
+		PerWithdrawal: *req.PerWithdrawal,
+		Daily:         *req.Daily,
+		Monthly:       *req.Monthly,
+	})
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	h.writeLimits(w, limits)
+}
+
+// Reset возвращает пользователю ограничения по умолчанию
+func (h *WithdrawalLimitsHandler) Reset(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.ResetWithdrawalLimits(r.Context(), chi.URLParam(r, "login")); err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *WithdrawalLimitsHandler) writeLimits(w http.ResponseWriter, limits *domain.UserWithdrawalLimits) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newWithdrawalLimitsResponse(limits)); err != nil {
+		h.logger.Error("failed to encode withdrawal limits response", zap.Error(err))
+	}
+}
+
+func (h *WithdrawalLimitsHandler) writeError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, domain.ErrUserNotFound):
+		writeJSONError(w, r, http.StatusNotFound, "user not found")
+	case errors.Is(err, domain.ErrInvalidInput):
+		writeJSONError(w, r, http.StatusBadRequest, "limits must be between 0 and 99999999.99")
+	default:
+		h.logger.Error("failed to manage withdrawal limits", zap.Error(err))
+		writeInternalError(w, err)
+	}
+}
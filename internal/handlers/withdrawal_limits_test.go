@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	domainmocks "github.com/avc/loyalty-system-diploma/internal/domain/mocks"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const withdrawalLimitsPath = "/api/admin/users/{login}/withdrawal-limits"
+
+func newWithdrawalLimitsRouter(handler *WithdrawalLimitsHandler) http.Handler {
+	r := chi.NewRouter()
+	r.Get(withdrawalLimitsPath, handler.Get)
+	r.Put(withdrawalLimitsPath, handler.Set)
+	r.Delete(withdrawalLimitsPath, handler.Reset)
+	return r
+}
+
+func TestWithdrawalLimitsHandler_Get(t *testing.T) {
+	svc := domainmocks.NewWithdrawalLimitServiceMock(t)
+	router := newWithdrawalLimitsRouter(NewWithdrawalLimitsHandler(svc, zap.NewNop()))
+
+	svc.EXPECT().GetWithdrawalLimits(mock.Anything, "alice").Return(&domain.UserWithdrawalLimits{
+		Login:  "alice",
+		Limits: domain.WithdrawalLimits{Daily: 1000},
+	}, nil).Once()
+	svc.EXPECT().GetWithdrawalLimits(mock.Anything, "nobody").Return(nil, domain.ErrUserNotFound).Once()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/admin/users/alice/withdrawal-limits", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"login":"alice","per_withdrawal":0,"daily":1000,"monthly":0,"override":false}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/admin/users/nobody/withdrawal-limits", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestWithdrawalLimitsHandler_Set(t *testing.T) {
+	limits := domain.WithdrawalLimits{PerWithdrawal: 500, Daily: 2000, Monthly: 0}
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(*domainmocks.WithdrawalLimitServiceMock)
+		expectedStatus int
+	}{
+		{
+			name: "Success",
+			body: `{"per_withdrawal":500,"daily":2000,"monthly":0}`,
+			setupMock: func(m *domainmocks.WithdrawalLimitServiceMock) {
+				m.EXPECT().SetWithdrawalLimits(mock.Anything, "alice", limits).
+					Return(&domain.UserWithdrawalLimits{Login: "alice", Limits: limits, Override: true}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Missing field",
+			body:           `{"per_withdrawal":500,"daily":2000}`,
+			setupMock:      func(m *domainmocks.WithdrawalLimitServiceMock) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid JSON",
+			body:           `{"daily":`,
+			setupMock:      func(m *domainmocks.WithdrawalLimitServiceMock) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Invalid values",
+			body: `{"per_withdrawal":-1,"daily":0,"monthly":0}`,
+			setupMock: func(m *domainmocks.WithdrawalLimitServiceMock) {
+				m.EXPECT().SetWithdrawalLimits(mock.Anything, "alice", domain.WithdrawalLimits{PerWithdrawal: -1}).
+					Return(nil, domain.ErrInvalidInput).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Service error",
+			body: `{"per_withdrawal":500,"daily":2000,"monthly":0}`,
+			setupMock: func(m *domainmocks.WithdrawalLimitServiceMock) {
+				m.EXPECT().SetWithdrawalLimits(mock.Anything, "alice", limits).Return(nil, errors.New("db error")).Once()
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := domainmocks.NewWithdrawalLimitServiceMock(t)
+			router := newWithdrawalLimitsRouter(NewWithdrawalLimitsHandler(svc, zap.NewNop()))
+			tt.setupMock(svc)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPut, "/api/admin/users/alice/withdrawal-limits", strings.NewReader(tt.body))
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response WithdrawalLimitsResponse
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+				assert.True(t, response.Override)
+				assert.Equal(t, Amount(2000), response.Daily)
+			}
+		})
+	}
+}
+
+func TestWithdrawalLimitsHandler_Reset(t *testing.T) {
+	svc := domainmocks.NewWithdrawalLimitServiceMock(t)
+	router := newWithdrawalLimitsRouter(NewWithdrawalLimitsHandler(svc, zap.NewNop()))
+
+	svc.EXPECT().ResetWithdrawalLimits(mock.Anything, "alice").Return(nil).Once()
+	svc.EXPECT().ResetWithdrawalLimits(mock.Anything, "nobody").Return(domain.ErrUserNotFound).Once()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/admin/users/alice/withdrawal-limits", nil))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/admin/users/nobody/withdrawal-limits", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
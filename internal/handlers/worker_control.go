@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// WorkerController приостанавливает и возобновляет обработку заказов.
+type WorkerController interface {
+	Pause() bool
+	Resume() bool
+	PausedSince() (time.Time, bool)
+}
+
+// WorkerControlHandler обрабатывает административные запросы управления обработкой заказов
+type WorkerControlHandler struct {
+	worker WorkerController
+	logger *zap.Logger
+}
+
+// NewWorkerControlHandler создает новый WorkerControlHandler
+func NewWorkerControlHandler(worker WorkerController, logger *zap.Logger) *WorkerControlHandler {
+	return &WorkerControlHandler{
+		worker: worker,
+		logger: logger,
+	}
+}
+
+// Status возвращает, приостановлена ли обработка заказов
+func (h *WorkerControlHandler) Status(w http.ResponseWriter, r *http.Request) {
+	h.writeStatus(w)
+}
+
+// Pause приостанавливает обработку заказов на этой реплике. Повторный запрос ничего не меняет
+func (h *WorkerControlHandler) Pause(w http.ResponseWriter, r *http.Request) {
+	if h.worker.Pause() {
+		adminID, _ := GetUserID(r.Context())
+		h.logger.Warn("order processing paused by admin", zap.Int64("admin_id", adminID))
+	}
+	h.writeStatus(w)
+}
+
+// Resume возобновляет обработку заказов на этой реплике. Повторный запрос ничего не меняет
+func (h *WorkerControlHandler) Resume(w http.ResponseWriter, r *http.Request) {
+	if h.worker.Resume() {
+		adminID, _ := GetUserID(r.Context())
+		h.logger.Info("order processing resumed by admin", zap.Int64("admin_id", adminID))
+	}
+	h.writeStatus(w)
+}
+
+func (h *WorkerControlHandler) writeStatus(w http.ResponseWriter) {
+	since, paused := h.worker.PausedSince()
+	response := WorkerStatusResponse{Paused: paused}
+	if paused {
+		response.PausedSince = &since
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error("failed to encode worker status response", zap.Error(err))
+	}
+}
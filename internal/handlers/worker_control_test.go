@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	domainmocks "github.com/avc/loyalty-system-diploma/internal/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestWorkerControlHandler(t *testing.T) {
+	pausedSince := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	adminRequest := func(method, path string) *http.Request {
+		req := httptest.NewRequest(method, path, nil)
+		return req.WithContext(context.WithValue(req.Context(), UserIDKey, int64(1)))
+	}
+
+	t.Run("Status running", func(t *testing.T) {
+		worker := domainmocks.NewWorkerControllerMock(t)
+		worker.EXPECT().PausedSince().Return(time.Time{}, false).Once()
+
+		w := httptest.NewRecorder()
+		NewWorkerControlHandler(worker, zap.NewNop()).Status(w, adminRequest(http.MethodGet, "/api/admin/worker"))
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"paused":false}`, w.Body.String())
+	})
+
+	t.Run("Pause", func(t *testing.T) {
+		worker := domainmocks.NewWorkerControllerMock(t)
+		worker.EXPECT().Pause().Return(true).Once()
+		worker.EXPECT().PausedSince().Return(pausedSince, true).Once()
+
+		w := httptest.NewRecorder()
+		NewWorkerControlHandler(worker, zap.NewNop()).Pause(w, adminRequest(http.MethodPost, "/api/admin/worker/pause"))
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"paused":true,"paused_since":"2024-01-02T03:04:05Z"}`, w.Body.String())
+	})
+
+	t.Run("Repeated pause", func(t *testing.T) {
+		worker := domainmocks.NewWorkerControllerMock(t)
+		worker.EXPECT().Pause().Return(false).Once()
+		worker.EXPECT().PausedSince().Return(pausedSince, true).Once()
+
+		w := httptest.NewRecorder()
+		NewWorkerControlHandler(worker, zap.NewNop()).Pause(w, adminRequest(http.MethodPost, "/api/admin/worker/pause"))
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"paused":true,"paused_since":"2024-01-02T03:04:05Z"}`, w.Body.String())
+	})
+
+	t.Run("Resume", func(t *testing.T) {
+		worker := domainmocks.NewWorkerControllerMock(t)
+		worker.EXPECT().Resume().Return(true).Once()
+		worker.EXPECT().PausedSince().Return(time.Time{}, false).Once()
+
+		w := httptest.NewRecorder()
+		NewWorkerControlHandler(worker, zap.NewNop()).Resume(w, adminRequest(http.MethodPost, "/api/admin/worker/resume"))
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"paused":false}`, w.Body.String())
+	})
+}
@@ -0,0 +1,395 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"sort"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/avc/loyalty-system-diploma/internal/utils/luhn"
+	"go.uber.org/zap"
+)
+
+// LedgerStore определяет методы хранилища, нужные для переноса данных.
+type LedgerStore interface {
+	GetUserIDsByLogins(ctx context.Context, logins []string) (map[string]int64, error)
+	ImportOrders(ctx context.Context, orders []*domain.Order) (*domain.BatchImport, error)
+	ImportTransactions(ctx context.Context, transactions []*domain.Transaction) (*domain.BatchImport, error)
+	GetBalance(ctx context.Context, userID int64) (*domain.Balance, error)
+}
+
+// DefaultBatchSize - количество записей в одном запросе на вставку
+const DefaultBatchSize = 500
+
+// Config содержит настройки импорта
+type Config struct {
+	BatchSize int
+	// DryRun только проверяет данные, ничего не записывая
+	DryRun bool
+}
+
+// Ledger - прочитанные из файлов данные прежней системы
+type Ledger struct {
+	Orders       []OrderRecord
+	Transactions []TransactionRecord
+	// Rejected - записи, отклоненные еще при чтении файлов
+	Rejected []Rejection
+}
+
+// Summary - счетчики по одному виду записей
+type Summary struct {
+	Read int `json:"read"`
+	// Rejected - записи, отклоненные при чтении, проверке или самой БД
+	Rejected int `json:"rejected"`
+	Imported int `json:"imported"`
+	// Skipped - корректные записи, которые уже были в БД
+	Skipped int `json:"skipped"`
+}
+
+// BalanceCheck сравнивает баланс по данным прежней системы с балансом в БД после импорта.
+// В режиме DryRun заполняется только Expected.
+type BalanceCheck struct {
+	Login    string          `json:"login"`
+	UserID   int64           `json:"user_id"`
+	Expected domain.Balance  `json:"expected"`
+	Actual   *domain.Balance `json:"actual,omitempty"`
+	Match    bool            `json:"match"`
+}
+
+// Report - отчет сверки по итогам импорта
+type Report struct {
+	DryRun       bool           `json:"dry_run"`
+	Orders       Summary        `json:"orders"`
+	Transactions Summary        `json:"transactions"`
+	Rejected     []Rejection    `json:"rejected"`
+	Balances     []BalanceCheck `json:"balances"`
+	Mismatches   int            `json:"mismatches"`
+}
+
+// Reconciled сообщает, что все записи приняты и балансы совпали
+func (r *Report) Reconciled() bool {
+	return len(r.Rejected) == 0 && r.Mismatches == 0
+}
+
+// Importer переносит заказы и операции из прежней системы лояльности.
+// Пользователи должны быть созданы заранее, например через POST /api/admin/users/import.
+type Importer struct {
+	store  LedgerStore
+	config Config
+	logger *zap.Logger
+}
+
+// New создает новый Importer
+func New(store LedgerStore, config Config, logger *zap.Logger) *Importer {
+	if config.BatchSize <= 0 {
+		config.BatchSize = DefaultBatchSize
+	}
+	return &Importer{
+		store:  store,
+		config: config,
+		logger: logger,
+	}
+}
+
+// Run проверяет данные, записывает корректные записи пачками и сверяет балансы.
+// Ошибка возвращается только при сбое хранилища; проблемы с данными попадают в отчет.
+func (im *Importer) Run(ctx context.Context, ledger Ledger) (*Report, error) {
+	report := &Report{
+		DryRun:       im.config.DryRun,
+		Orders:       Summary{Read: len(ledger.Orders)},
+		Transactions: Summary{Read: len(ledger.Transactions)},
+		Rejected:     slices.Clone(ledger.Rejected),
+	}
+	for _, r := range ledger.Rejected {
+		if r.Source == SourceOrders {
+			report.Orders.Read++
+		} else {
+			report.Transactions.Read++
+		}
+	}
+
+	userIDs, err := im.store.GetUserIDsByLogins(ctx, collectLogins(ledger))
+	if err != nil {
+		return nil, fmt.Errorf("importer: failed to resolve users: %w", err)
+	}
+
+	orders, orderLines, ordersByNumber := im.validateOrders(ledger.Orders, userIDs, report)
+	transactions, transactionLines, expected := im.validateTransactions(ledger.Transactions, userIDs, ordersByNumber, report)
+
+	report.Orders.Rejected = countRejected(report.Rejected, SourceOrders)
+	report.Transactions.Rejected = countRejected(report.Rejected, SourceTransactions)
+
+	if im.config.DryRun {
+		report.Balances = expectedBalances(expected, userIDs)
+		return report, nil
+	}
+
+	// Заказы пишутся первыми, чтобы начисления ссылались на уже импортированные заказы
+	if err := importBatches(ctx, im, SourceOrders, orders, orderLines, &report.Orders, report, im.store.ImportOrders); err != nil {
+		return nil, err
+	}
+	if err := importBatches(ctx, im, SourceTransactions, transactions, transactionLines, &report.Transactions, report, im.store.ImportTransactions); err != nil {
+		return nil, err
+	}
+
+	if err := im.reconcile(ctx, expected, userIDs, report); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// importBatches записывает проверенные записи пачками. Записи, отклоненные БД, попадают
+// в отчет с номером строки из lines, остальные записи пачки при этом сохраняются
+func importBatches[T any](
+	ctx context.Context,
+	im *Importer,
+	source string,
+	records []T,
+	lines []int,
+	summary *Summary,
+	report *Report,
+	store func(ctx context.Context, batch []T) (*domain.BatchImport, error),
+) error {
+	var rejected int
+	for start := 0; start < len(records); start += im.config.BatchSize {
+		batch := records[start:min(start+im.config.BatchSize, len(records))]
+		result, err := store(ctx, batch)
+		if err != nil {
+			return fmt.Errorf("importer: failed to import %s: %w", source, err)
+		}
+
+		for _, i := range slices.Sorted(maps.Keys(result.Rejected)) {
+			report.Rejected = append(report.Rejected, Rejection{
+				Source: source,
+				Line:   lines[start+i],
+				Reason: fmt.Sprintf("rejected by database: %v", result.Rejected[i]),
+			})
+		}
+		summary.Imported += result.Imported
+		rejected += len(result.Rejected)
+		im.logger.Info(source+" batch imported",
+			zap.Int("batch", len(batch)),
+			zap.Int("imported", result.Imported),
+			zap.Int("rejected", len(result.Rejected)),
+		)
+	}
+
+	summary.Rejected += rejected
+	summary.Skipped = len(records) - summary.Imported - rejected
+	return nil
+}
+
+// validateOrders отбирает корректные заказы с номерами их строк и индексирует заказы по номеру
+func (im *Importer) validateOrders(records []OrderRecord, userIDs map[string]int64, report *Report) ([]*domain.Order, []int, map[string]*domain.Order) {
+	orders := make([]*domain.Order, 0, len(records))
+	lines := make([]int, 0, len(records))
+	byNumber := make(map[string]*domain.Order, len(records))
+
+	for _, rec := range records {
+		reject := func(reason string) {
+			report.Rejected = append(report.Rejected, Rejection{Source: SourceOrders, Line: rec.Line, Reason: reason})
+		}
+
+		userID, ok := userIDs[rec.Login]
+		status := domain.OrderStatus(rec.Status)
+		switch {
+		case !ok:
+			reject(fmt.Sprintf("user %q not found", rec.Login))
+		case len(rec.Number) > domain.MaxOrderNumberLength:
+			reject(fmt.Sprintf("order number is longer than %d characters", domain.MaxOrderNumberLength))
+		case !luhn.Validate(rec.Number):
+			reject(fmt.Sprintf("order number %q fails Luhn check", rec.Number))
+		case byNumber[rec.Number] != nil:
+			reject(fmt.Sprintf("duplicate order number %q", rec.Number))
+		case !isOrderStatus(status):
+			reject(fmt.Sprintf("unknown order status %q", rec.Status))
+		case rec.Accrual != nil && (status != domain.OrderStatusProcessed || *rec.Accrual < 0):
+			reject("accrual is allowed only for PROCESSED orders and must not be negative")
+		case rec.UploadedAt.IsZero():
+			reject("uploaded_at is required")
+		default:
+			order := &domain.Order{
+				UserID:     userID,
+				Number:     rec.Number,
+				Status:     status,
+				Accrual:    rec.Accrual,
+				UploadedAt: rec.UploadedAt,
+			}
+			orders = append(orders, order)
+			lines = append(lines, rec.Line)
+			byNumber[order.Number] = order
+		}
+	}
+
+	return orders, lines, byNumber
+}
+
+// validateTransactions отбирает корректные операции и считает ожидаемые балансы.
+// Операции пользователя проверяются в хронологическом порядке:
+// списание, превышающее накопленный к этому моменту баланс, отклоняется.
+// Вместе с операциями возвращаются номера их строк.
+func (im *Importer) validateTransactions(records []TransactionRecord, userIDs map[string]int64, orders map[string]*domain.Order, report *Report) ([]*domain.Transaction, []int, map[string]*domain.Balance) {
+	sorted := slices.Clone(records)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].ProcessedAt.Before(sorted[j].ProcessedAt)
+	})
+
+	transactions := make([]*domain.Transaction, 0, len(sorted))
+	lines := make([]int, 0, len(sorted))
+	balances := make(map[string]*domain.Balance)
+	accrued := make(map[string]bool)
+
+	for _, rec := range sorted {
+		reject := func(reason string) {
+			report.Rejected = append(report.Rejected, Rejection{Source: SourceTransactions, Line: rec.Line, Reason: reason})
+		}
+
+		userID, ok := userIDs[rec.Login]
+		txType := domain.TransactionType(rec.Type)
+		order := orders[rec.Order]
+		switch {
+		case !ok:
+			reject(fmt.Sprintf("user %q not found", rec.Login))
+			continue
+		case len(rec.Order) > domain.MaxOrderNumberLength:
+			reject(fmt.Sprintf("order number is longer than %d characters", domain.MaxOrderNumberLength))
+			continue
+		case !luhn.Validate(rec.Order):
+			reject(fmt.Sprintf("order number %q fails Luhn check", rec.Order))
+			continue
+		case txType != domain.TransactionTypeAccrual && txType != domain.TransactionTypeWithdrawal:
+			reject(fmt.Sprintf("unknown transaction type %q", rec.Type))
+			continue
+		case rec.Sum <= 0:
+			reject("sum must be positive")
+			continue
+		case rec.ProcessedAt.IsZero():
+			reject("processed_at is required")
+			continue
+		}
+
+		balance := balances[rec.Login]
+		if balance == nil {
+			balance = &domain.Balance{}
+		}
+
+		amount := rec.Sum
+		if txType == domain.TransactionTypeAccrual {
+			switch {
+			case accrued[rec.Order]:
+				reject(fmt.Sprintf("duplicate accrual for order %q", rec.Order))
+				continue
+			case order != nil && order.UserID != userID:
+				reject(fmt.Sprintf("order %q belongs to another user", rec.Order))
+				continue
+			case order != nil && (order.Accrual == nil || !equalAmounts(*order.Accrual, rec.Sum)):
+				reject(fmt.Sprintf("accrual does not match order %q", rec.Order))
+				continue
+			}
+			accrued[rec.Order] = true
+			balance.Current += amount
+		} else {
+			if rec.Sum > balance.Current && !equalAmounts(rec.Sum, balance.Current) {
+				reject(fmt.Sprintf("withdrawal of %.2f exceeds balance %.2f", rec.Sum, balance.Current))
+				continue
+			}
+			amount = -rec.Sum
+			balance.Current -= rec.Sum
+			balance.Withdrawn += rec.Sum
+		}
+		balances[rec.Login] = balance
+
+		transactions = append(transactions, &domain.Transaction{
+			UserID:      userID,
+			OrderNumber: rec.Order,
+			Amount:      amount,
+			Type:        txType,
+			ProcessedAt: rec.ProcessedAt,
+		})
+		lines = append(lines, rec.Line)
+	}
+
+	return transactions, lines, balances
+}
+
+// reconcile сравнивает ожидаемые балансы с балансами в БД
+func (im *Importer) reconcile(ctx context.Context, expected map[string]*domain.Balance, userIDs map[string]int64, report *Report) error {
+	report.Balances = expectedBalances(expected, userIDs)
+
+	for i := range report.Balances {
+		check := &report.Balances[i]
+		actual, err := im.store.GetBalance(ctx, check.UserID)
+		if err != nil {
+			return fmt.Errorf("importer: failed to get balance of %q: %w", check.Login, err)
+		}
+
+		check.Actual = actual
+		check.Match = equalAmounts(actual.Current, check.Expected.Current) &&
+			equalAmounts(actual.Withdrawn, check.Expected.Withdrawn)
+		if !check.Match {
+			report.Mismatches++
+			im.logger.Warn("balance mismatch",
+				zap.String("login", check.Login),
+				zap.Float64("expected_current", check.Expected.Current),
+				zap.Float64("actual_current", actual.Current),
+			)
+		}
+	}
+
+	return nil
+}
+
+// expectedBalances возвращает проверки балансов, упорядоченные по логину
+func expectedBalances(expected map[string]*domain.Balance, userIDs map[string]int64) []BalanceCheck {
+	checks := make([]BalanceCheck, 0, len(expected))
+	for login, balance := range expected {
+		checks = append(checks, BalanceCheck{Login: login, UserID: userIDs[login], Expected: *balance})
+	}
+	sort.Slice(checks, func(i, j int) bool { return checks[i].Login < checks[j].Login })
+	return checks
+}
+
+func collectLogins(ledger Ledger) []string {
+	seen := make(map[string]bool)
+	var logins []string
+	add := func(login string) {
+		if !seen[login] {
+			seen[login] = true
+			logins = append(logins, login)
+		}
+	}
+	for _, rec := range ledger.Orders {
+		add(rec.Login)
+	}
+	for _, rec := range ledger.Transactions {
+		add(rec.Login)
+	}
+	return logins
+}
+
+func countRejected(rejected []Rejection, source string) int {
+	count := 0
+	for _, r := range rejected {
+		if r.Source == source {
+			count++
+		}
+	}
+	return count
+}
+
+func isOrderStatus(status domain.OrderStatus) bool {
+	switch status {
+	case domain.OrderStatusNew, domain.OrderStatusProcessing, domain.OrderStatusInvalid, domain.OrderStatusProcessed:
+		return true
+	}
+	return false
+}
+
+// equalAmounts сравнивает суммы с точностью до копейки, как они хранятся в БД
+func equalAmounts(a, b float64) bool {
+	return math.Round(a*100) == math.Round(b*100)
+}
@@ -0,0 +1,187 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	domainmocks "github.com/avc/loyalty-system-diploma/internal/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+var legacyTime = time.Date(2020, 12, 10, 15, 15, 45, 0, time.UTC)
+
+func ptrFloat(f float64) *float64 {
+	return &f
+}
+
+// testLedger - заказы и операции двух пользователей с несколькими ошибками в данных
+func testLedger() Ledger {
+	return Ledger{
+		Orders: []OrderRecord{
+			{Line: 2, Login: "alice", Number: "9278923470", Status: "PROCESSED", Accrual: ptrFloat(500), UploadedAt: legacyTime},
+			{Line: 3, Login: "bob", Number: "346436439", Status: "NEW", UploadedAt: legacyTime},
+			{Line: 4, Login: "bob", Number: "12345678900", Status: "NEW", UploadedAt: legacyTime},
+			{Line: 5, Login: "carol", Number: "12345678903", Status: "NEW", UploadedAt: legacyTime},
+			{Line: 6, Login: "bob", Number: "346436439", Status: "NEW", UploadedAt: legacyTime},
+			{Line: 7, Login: "bob", Number: strings.Repeat("0", 65), Status: "NEW", UploadedAt: legacyTime},
+		},
+		Transactions: []TransactionRecord{
+			// Списание идет в файле раньше начисления, но позже по времени
+			{Line: 2, Login: "alice", Order: "2377225624", Type: "withdrawal", Sum: 100, ProcessedAt: legacyTime.Add(time.Hour)},
+			{Line: 3, Login: "alice", Order: "9278923470", Type: "accrual", Sum: 500, ProcessedAt: legacyTime},
+			{Line: 4, Login: "alice", Order: "2377225624", Type: "withdrawal", Sum: 1000, ProcessedAt: legacyTime.Add(2 * time.Hour)},
+			{Line: 5, Login: "bob", Order: "9278923470", Type: "accrual", Sum: 500, ProcessedAt: legacyTime},
+		},
+		Rejected: []Rejection{{Source: SourceTransactions, Line: 6, Reason: `invalid sum "many"`}},
+	}
+}
+
+func TestImporter_Run(t *testing.T) {
+	store := domainmocks.NewLedgerStoreMock(t)
+	store.EXPECT().GetUserIDsByLogins(mock.Anything, []string{"alice", "bob", "carol"}).
+		Return(map[string]int64{"alice": 1, "bob": 2}, nil)
+
+	// Пачки по два заказа; второй заказ bob уже был в БД
+	store.EXPECT().ImportOrders(mock.Anything, mock.MatchedBy(func(orders []*domain.Order) bool {
+		return len(orders) == 2 && orders[0].Number == "9278923470" && orders[1].Number == "346436439"
+	})).Return(&domain.BatchImport{Imported: 1}, nil).Once()
+
+	store.EXPECT().ImportTransactions(mock.Anything, mock.MatchedBy(func(txs []*domain.Transaction) bool {
+		return len(txs) == 2 &&
+			txs[0].Type == domain.TransactionTypeAccrual && txs[0].Amount == 500 &&
+			txs[1].Type == domain.TransactionTypeWithdrawal && txs[1].Amount == -100 &&
+			txs[1].ProcessedAt.Equal(legacyTime.Add(time.Hour))
+	})).Return(&domain.BatchImport{Imported: 2}, nil).Once()
+
+	store.EXPECT().GetBalance(mock.Anything, int64(1)).Return(&domain.Balance{Current: 400, Withdrawn: 100}, nil)
+
+	im := New(store, Config{BatchSize: 2}, zap.NewNop())
+	report, err := im.Run(context.Background(), testLedger())
+	require.NoError(t, err)
+
+	assert.Equal(t, Summary{Read: 6, Rejected: 4, Imported: 1, Skipped: 1}, report.Orders)
+	assert.Equal(t, Summary{Read: 5, Rejected: 3, Imported: 2}, report.Transactions)
+
+	reasons := make(map[Rejection]bool)
+	for _, r := range report.Rejected {
+		reasons[r] = true
+	}
+	assert.True(t, reasons[Rejection{Source: SourceOrders, Line: 4, Reason: `order number "12345678900" fails Luhn check`}])
+	assert.True(t, reasons[Rejection{Source: SourceOrders, Line: 5, Reason: `user "carol" not found`}])
+	assert.True(t, reasons[Rejection{Source: SourceOrders, Line: 6, Reason: `duplicate order number "346436439"`}])
+	assert.True(t, reasons[Rejection{Source: SourceOrders, Line: 7, Reason: "order number is longer than 64 characters"}])
+	assert.True(t, reasons[Rejection{Source: SourceTransactions, Line: 4, Reason: "withdrawal of 1000.00 exceeds balance 400.00"}])
+	assert.True(t, reasons[Rejection{Source: SourceTransactions, Line: 5, Reason: `duplicate accrual for order "9278923470"`}])
+	assert.True(t, reasons[Rejection{Source: SourceTransactions, Line: 6, Reason: `invalid sum "many"`}])
+
+	require.Len(t, report.Balances, 1)
+	assert.Equal(t, "alice", report.Balances[0].Login)
+	assert.Equal(t, domain.Balance{Current: 400, Withdrawn: 100}, report.Balances[0].Expected)
+	assert.True(t, report.Balances[0].Match)
+	assert.Zero(t, report.Mismatches)
+	assert.False(t, report.Reconciled())
+}
+
+func TestImporter_Run_BalanceMismatch(t *testing.T) {
+	store := domainmocks.NewLedgerStoreMock(t)
+	store.EXPECT().GetUserIDsByLogins(mock.Anything, []string{"alice"}).Return(map[string]int64{"alice": 1}, nil)
+	store.EXPECT().ImportOrders(mock.Anything, mock.Anything).Return(&domain.BatchImport{Imported: 1}, nil)
+	store.EXPECT().ImportTransactions(mock.Anything, mock.Anything).Return(&domain.BatchImport{Imported: 1}, nil)
+	// У пользователя в БД уже были другие операции
+	store.EXPECT().GetBalance(mock.Anything, int64(1)).Return(&domain.Balance{Current: 750}, nil)
+
+	ledger := Ledger{
+		Orders:       []OrderRecord{{Login: "alice", Number: "9278923470", Status: "PROCESSED", Accrual: ptrFloat(500), UploadedAt: legacyTime}},
+		Transactions: []TransactionRecord{{Login: "alice", Order: "9278923470", Type: "accrual", Sum: 500, ProcessedAt: legacyTime}},
+	}
+
+	report, err := New(store, Config{}, zap.NewNop()).Run(context.Background(), ledger)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, report.Mismatches)
+	assert.False(t, report.Balances[0].Match)
+	assert.Equal(t, 750.0, report.Balances[0].Actual.Current)
+	assert.False(t, report.Reconciled())
+}
+
+func TestImporter_Run_RejectedByDatabase(t *testing.T) {
+	store := domainmocks.NewLedgerStoreMock(t)
+	store.EXPECT().GetUserIDsByLogins(mock.Anything, []string{"alice"}).Return(map[string]int64{"alice": 1}, nil)
+	// БД отклонила второй заказ первой пачки; первый и третий сохранены
+	store.EXPECT().ImportOrders(mock.Anything, mock.MatchedBy(func(orders []*domain.Order) bool { return len(orders) == 2 })).
+		Return(&domain.BatchImport{Imported: 1, Rejected: map[int]error{1: errors.New("value too long")}}, nil).Once()
+	store.EXPECT().ImportOrders(mock.Anything, mock.MatchedBy(func(orders []*domain.Order) bool { return len(orders) == 1 })).
+		Return(&domain.BatchImport{Imported: 1}, nil).Once()
+
+	ledger := Ledger{
+		Orders: []OrderRecord{
+			{Line: 2, Login: "alice", Number: "9278923470", Status: "NEW", UploadedAt: legacyTime},
+			{Line: 3, Login: "alice", Number: "346436439", Status: "NEW", UploadedAt: legacyTime},
+			{Line: 4, Login: "alice", Number: "2377225624", Status: "NEW", UploadedAt: legacyTime},
+		},
+	}
+
+	report, err := New(store, Config{BatchSize: 2}, zap.NewNop()).Run(context.Background(), ledger)
+	require.NoError(t, err)
+
+	assert.Equal(t, Summary{Read: 3, Rejected: 1, Imported: 2}, report.Orders)
+	assert.Equal(t, []Rejection{{Source: SourceOrders, Line: 3, Reason: "rejected by database: value too long"}}, report.Rejected)
+	assert.False(t, report.Reconciled())
+}
+
+func TestImporter_Run_DryRun(t *testing.T) {
+	// В режиме проверки в хранилище ничего не пишется
+	store := domainmocks.NewLedgerStoreMock(t)
+	store.EXPECT().GetUserIDsByLogins(mock.Anything, []string{"alice"}).Return(map[string]int64{"alice": 1}, nil)
+
+	ledger := Ledger{
+		Orders:       []OrderRecord{{Login: "alice", Number: "9278923470", Status: "PROCESSED", Accrual: ptrFloat(500), UploadedAt: legacyTime}},
+		Transactions: []TransactionRecord{{Login: "alice", Order: "9278923470", Type: "accrual", Sum: 400, ProcessedAt: legacyTime}},
+	}
+
+	report, err := New(store, Config{DryRun: true}, zap.NewNop()).Run(context.Background(), ledger)
+	require.NoError(t, err)
+
+	assert.True(t, report.DryRun)
+	require.Len(t, report.Rejected, 1)
+	assert.Equal(t, `accrual does not match order "9278923470"`, report.Rejected[0].Reason)
+	assert.Empty(t, report.Balances)
+}
+
+func TestImporter_Run_StoreErrors(t *testing.T) {
+	ledger := Ledger{
+		Orders: []OrderRecord{{Login: "alice", Number: "9278923470", Status: "NEW", UploadedAt: legacyTime}},
+	}
+
+	t.Run("resolve users", func(t *testing.T) {
+		store := domainmocks.NewLedgerStoreMock(t)
+		store.EXPECT().GetUserIDsByLogins(mock.Anything, mock.Anything).Return(nil, errors.New("database error"))
+
+		report, err := New(store, Config{}, zap.NewNop()).Run(context.Background(), ledger)
+		assert.Error(t, err)
+		assert.Nil(t, report)
+	})
+
+	t.Run("import orders", func(t *testing.T) {
+		store := domainmocks.NewLedgerStoreMock(t)
+		store.EXPECT().GetUserIDsByLogins(mock.Anything, mock.Anything).Return(map[string]int64{"alice": 1}, nil)
+		store.EXPECT().ImportOrders(mock.Anything, mock.Anything).Return(nil, errors.New("database error"))
+
+		report, err := New(store, Config{}, zap.NewNop()).Run(context.Background(), ledger)
+		assert.Error(t, err)
+		assert.Nil(t, report)
+	})
+}
+
+func TestEqualAmounts(t *testing.T) {
+	assert.True(t, equalAmounts(0.1+0.2, 0.3))
+	assert.True(t, equalAmounts(100, 100.001))
+	assert.False(t, equalAmounts(100, 100.01))
+}
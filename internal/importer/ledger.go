@@ -0,0 +1,205 @@
+package importer
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Format представляет формат файла выгрузки из прежней системы
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatJSON Format = "json"
+)
+
+// Источники записей в отчете
+const (
+	SourceOrders       = "orders"
+	SourceTransactions = "transactions"
+)
+
+// DetectFormat определяет формат файла по расширению
+func DetectFormat(path string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return FormatCSV, nil
+	case ".json":
+		return FormatJSON, nil
+	}
+	return "", fmt.Errorf("importer: unsupported file extension %q, expected .csv or .json", filepath.Ext(path))
+}
+
+// OrderRecord - заказ из выгрузки прежней системы
+type OrderRecord struct {
+	Line       int       `json:"-"`
+	Login      string    `json:"login"`
+	Number     string    `json:"number"`
+	Status     string    `json:"status"`
+	Accrual    *float64  `json:"accrual,omitempty"`
+	UploadedAt time.Time `json:"uploaded_at"`
+}
+
+// TransactionRecord - операция по счету из выгрузки прежней системы.
+// Sum всегда положительна, направление определяется типом.
+type TransactionRecord struct {
+	Line        int       `json:"-"`
+	Login       string    `json:"login"`
+	Order       string    `json:"order"`
+	Type        string    `json:"type"`
+	Sum         float64   `json:"sum"`
+	ProcessedAt time.Time `json:"processed_at"`
+}
+
+// Rejection описывает запись, не прошедшую разбор или проверку.
+// Line - номер строки CSV файла или номер элемента JSON массива.
+type Rejection struct {
+	Source string `json:"source"`
+	Line   int    `json:"line"`
+	Reason string `json:"reason"`
+}
+
+// ReadOrders читает заказы. Записи, которые не удалось разобрать, возвращаются как отклоненные,
+// ошибка возвращается только если файл нельзя прочитать целиком.
+func ReadOrders(r io.Reader, format Format) ([]OrderRecord, []Rejection, error) {
+	if format == FormatJSON {
+		return readJSON[OrderRecord](r, SourceOrders, func(rec *OrderRecord, line int) { rec.Line = line })
+	}
+
+	return readCSV(r, SourceOrders, []string{"login", "number", "status", "uploaded_at"}, func(get func(string) string, line int) (OrderRecord, error) {
+		rec := OrderRecord{Line: line, Login: get("login"), Number: get("number"), Status: get("status")}
+
+		if value := get("accrual"); value != "" {
+			accrual, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return rec, fmt.Errorf("invalid accrual %q", value)
+			}
+			rec.Accrual = &accrual
+		}
+
+		uploadedAt, err := time.Parse(time.RFC3339, get("uploaded_at"))
+		if err != nil {
+			return rec, fmt.Errorf("invalid uploaded_at %q", get("uploaded_at"))
+		}
+		rec.UploadedAt = uploadedAt
+
+		return rec, nil
+	})
+}
+
+// ReadTransactions читает операции по счетам аналогично ReadOrders
+func ReadTransactions(r io.Reader, format Format) ([]TransactionRecord, []Rejection, error) {
+	if format == FormatJSON {
+		return readJSON[TransactionRecord](r, SourceTransactions, func(rec *TransactionRecord, line int) { rec.Line = line })
+	}
+
+	return readCSV(r, SourceTransactions, []string{"login", "order", "type", "sum", "processed_at"}, func(get func(string) string, line int) (TransactionRecord, error) {
+		rec := TransactionRecord{Line: line, Login: get("login"), Order: get("order"), Type: get("type")}
+
+		sum, err := strconv.ParseFloat(get("sum"), 64)
+		if err != nil {
+			return rec, fmt.Errorf("invalid sum %q", get("sum"))
+		}
+		rec.Sum = sum
+
+		processedAt, err := time.Parse(time.RFC3339, get("processed_at"))
+		if err != nil {
+			return rec, fmt.Errorf("invalid processed_at %q", get("processed_at"))
+		}
+		rec.ProcessedAt = processedAt
+
+		return rec, nil
+	})
+}
+
+// readCSV читает CSV файл с заголовком. Колонки ищутся по имени, порядок не важен.
+func readCSV[T any](r io.Reader, source string, required []string, parse func(get func(string) string, line int) (T, error)) ([]T, []Rejection, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil, nil
+		}
+		return nil, nil, fmt.Errorf("importer: failed to read %s header: %w", source, err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range required {
+		if _, ok := columns[name]; !ok {
+			return nil, nil, fmt.Errorf("importer: %s header must contain %q column", source, name)
+		}
+	}
+
+	var (
+		records  []T
+		rejected []Rejection
+	)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("importer: failed to read %s: %w", source, err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		get := func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		rec, err := parse(get, line)
+		if err != nil {
+			rejected = append(rejected, Rejection{Source: source, Line: line, Reason: err.Error()})
+			continue
+		}
+		records = append(records, rec)
+	}
+
+	return records, rejected, nil
+}
+
+// readJSON читает JSON массив. Элементы разбираются по одному,
+// чтобы ошибка в одной записи не отменяла остальные.
+func readJSON[T any](r io.Reader, source string, setLine func(rec *T, line int)) ([]T, []Rejection, error) {
+	var items []json.RawMessage
+	if err := json.NewDecoder(r).Decode(&items); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil, nil
+		}
+		return nil, nil, fmt.Errorf("importer: failed to decode %s: %w", source, err)
+	}
+
+	var (
+		records  []T
+		rejected []Rejection
+	)
+	for i, item := range items {
+		var rec T
+		if err := json.Unmarshal(item, &rec); err != nil {
+			rejected = append(rejected, Rejection{Source: source, Line: i + 1, Reason: err.Error()})
+			continue
+		}
+		setLine(&rec, i+1)
+		records = append(records, rec)
+	}
+
+	return records, rejected, nil
+}
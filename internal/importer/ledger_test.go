@@ -0,0 +1,90 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectFormat(t *testing.T) {
+	format, err := DetectFormat("legacy/orders.CSV")
+	require.NoError(t, err)
+	assert.Equal(t, FormatCSV, format)
+
+	format, err = DetectFormat("transactions.json")
+	require.NoError(t, err)
+	assert.Equal(t, FormatJSON, format)
+
+	_, err = DetectFormat("orders.xml")
+	assert.Error(t, err)
+}
+
+func TestReadOrders_CSV(t *testing.T) {
+	input := "number,login,status,accrual,uploaded_at\n" +
+		"9278923470,alice,PROCESSED,500,2020-12-10T15:15:45+03:00\n" +
+		"346436439,bob,NEW,,2020-12-10T15:12:01+03:00\n" +
+		"12345678903,bob,PROCESSED,abc,2020-12-10T15:12:01+03:00\n" +
+		"2377225624,bob,NEW,,yesterday\n"
+
+	orders, rejected, err := ReadOrders(strings.NewReader(input), FormatCSV)
+	require.NoError(t, err)
+
+	require.Len(t, orders, 2)
+	assert.Equal(t, 2, orders[0].Line)
+	assert.Equal(t, "alice", orders[0].Login)
+	require.NotNil(t, orders[0].Accrual)
+	assert.Equal(t, 500.0, *orders[0].Accrual)
+	assert.True(t, orders[0].UploadedAt.Equal(time.Date(2020, 12, 10, 12, 15, 45, 0, time.UTC)))
+	assert.Nil(t, orders[1].Accrual)
+
+	assert.Equal(t, []Rejection{
+		{Source: SourceOrders, Line: 4, Reason: `invalid accrual "abc"`},
+		{Source: SourceOrders, Line: 5, Reason: `invalid uploaded_at "yesterday"`},
+	}, rejected)
+}
+
+func TestReadOrders_CSVMissingColumn(t *testing.T) {
+	_, _, err := ReadOrders(strings.NewReader("login,number\nalice,9278923470\n"), FormatCSV)
+	assert.ErrorContains(t, err, "status")
+}
+
+func TestReadOrders_JSON(t *testing.T) {
+	input := `[
+		{"login":"alice","number":"9278923470","status":"PROCESSED","accrual":500,"uploaded_at":"2020-12-10T15:15:45+03:00"},
+		{"login":"bob","number":346436439,"status":"NEW","uploaded_at":"2020-12-10T15:12:01+03:00"}
+	]`
+
+	orders, rejected, err := ReadOrders(strings.NewReader(input), FormatJSON)
+	require.NoError(t, err)
+
+	require.Len(t, orders, 1)
+	assert.Equal(t, 1, orders[0].Line)
+	require.Len(t, rejected, 1)
+	assert.Equal(t, 2, rejected[0].Line)
+}
+
+func TestReadTransactions(t *testing.T) {
+	csvInput := "login,order,type,sum,processed_at\n" +
+		"alice,9278923470,accrual,500,2020-12-10T15:15:45+03:00\n" +
+		"alice,2377225624,withdrawal,many,2020-12-11T15:15:45+03:00\n"
+
+	transactions, rejected, err := ReadTransactions(strings.NewReader(csvInput), FormatCSV)
+	require.NoError(t, err)
+	require.Len(t, transactions, 1)
+	assert.Equal(t, "accrual", transactions[0].Type)
+	assert.Equal(t, 500.0, transactions[0].Sum)
+	assert.Equal(t, []Rejection{{Source: SourceTransactions, Line: 3, Reason: `invalid sum "many"`}}, rejected)
+
+	jsonInput := `[{"login":"alice","order":"2377225624","type":"withdrawal","sum":100,"processed_at":"2020-12-11T15:15:45+03:00"}]`
+	transactions, rejected, err = ReadTransactions(strings.NewReader(jsonInput), FormatJSON)
+	require.NoError(t, err)
+	require.Len(t, transactions, 1)
+	assert.Equal(t, "withdrawal", transactions[0].Type)
+	assert.Empty(t, rejected)
+
+	_, _, err = ReadTransactions(strings.NewReader(`{"login":"alice"}`), FormatJSON)
+	assert.Error(t, err)
+}
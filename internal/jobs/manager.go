@@ -0,0 +1,264 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/logctx"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ErrJobNotFound возвращается для неизвестной или уже удаленной задачи
+var ErrJobNotFound = errors.New("job not found")
+
+// Status представляет состояние фоновой задачи
+type Status string
+
+const (
+	StatusRunning   Status = "running"
+	StatusCompleted Status = "completed"
+	StatusFailed    Status = "failed"
+)
+
+// Progress принимает от задачи сведения о ходе выполнения
+type Progress interface {
+	SetTotal(total int)
+	Advance(ok bool)
+}
+
+// Result содержит результат задачи, который отдается клиенту как есть
+type Result struct {
+	ContentType string
+	Data        []byte
+}
+
+// Func выполняет задачу, сообщая прогресс через progress
+type Func func(ctx context.Context, progress Progress) (*Result, error)
+
+// Snapshot - состояние задачи на момент запроса
+type Snapshot struct {
+	ID         string
+	Type       string
+	Owner      int64 // Пользователь, запустивший задачу для себя (0 - административная задача)
+	Status     Status
+	Total      int
+	Processed  int
+	Failed     int
+	Error      string
+	CreatedAt  time.Time
+	FinishedAt time.Time
+	ExpiresAt  time.Time // Когда завершенная задача и ее результат будут удалены
+}
+
+// job хранит состояние задачи; поля защищены mu
+type job struct {
+	mu       sync.Mutex
+	snapshot Snapshot
+	result   *Result
+}
+
+// String описывает задачу без обращения к изменяемому состоянию: fmt и моки в тестах
+// иначе читали бы поля через reflect без блокировки, параллельно с выполнением задачи.
+// ID задается при создании и больше не меняется
+func (j *job) String() string {
+	return "job " + j.snapshot.ID
+}
+
+func (j *job) SetTotal(total int) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.snapshot.Total = total
+}
+
+func (j *job) Advance(ok bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.snapshot.Processed++
+	if !ok {
+		j.snapshot.Failed++
+	}
+}
+
+func (j *job) finish(result *Result, err error, ttl time.Duration) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.snapshot.FinishedAt = time.Now()
+	j.snapshot.ExpiresAt = j.snapshot.FinishedAt.Add(ttl)
+	if err != nil {
+		j.snapshot.Status = StatusFailed
+		j.snapshot.Error = err.Error()
+		return
+	}
+	j.snapshot.Status = StatusCompleted
+	j.result = result
+}
+
+func (j *job) state() (Snapshot, *Result) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.snapshot, j.result
+}
+
+// Manager запускает фоновые задачи и хранит их состояние в памяти.
+// Завершенные задачи удаляются через resultTTL, при перезапуске сервиса задачи теряются.
+type Manager struct {
+	mu        sync.Mutex
+	jobs      map[string]*job
+	resultTTL time.Duration
+	ctx       context.Context
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+	logger    *zap.Logger
+}
+
+// NewManager создает менеджер фоновых задач
+func NewManager(resultTTL time.Duration, logger *zap.Logger) *Manager {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Manager{
+		jobs:      make(map[string]*job),
+		resultTTL: resultTTL,
+		ctx:       ctx,
+		cancel:    cancel,
+		logger:    logger,
+	}
+}
+
+// Start запускает задачу в отдельной горутине и возвращает ее начальное состояние
+func (m *Manager) Start(jobType string, fn Func) Snapshot {
+	return m.StartFor(jobType, 0, fn)
+}
+
+// StartFor запускает задачу пользователя owner. Владелец сохраняется в состоянии задачи,
+// чтобы пользователь видел только свои задачи
+func (m *Manager) StartFor(jobType string, owner int64, fn Func) Snapshot {
+	j := &job{snapshot: Snapshot{
+		ID:        uuid.New().String(),
+		Type:      jobType,
+		Owner:     owner,
+		Status:    StatusRunning,
+		CreatedAt: time.Now(),
+	}}
+
+	m.mu.Lock()
+	m.removeExpired()
+	m.jobs[j.snapshot.ID] = j
+	m.mu.Unlock()
+
+	logger := m.logger.With(zap.String("job_id", j.snapshot.ID), zap.String("job_type", jobType))
+	logger.Info("job started")
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+
+		result, err := m.run(logctx.With(m.ctx, logger), j, fn)
+		j.finish(result, err, m.resultTTL)
+
+		snapshot, _ := j.state()
+		if err != nil {
+			logger.Error("job failed", zap.Int("processed", snapshot.Processed), zap.Error(err))
+			return
+		}
+		logger.Info("job completed",
+			zap.Int("processed", snapshot.Processed),
+			zap.Int("failed", snapshot.Failed),
+		)
+	}()
+
+	snapshot, _ := j.state()
+	return snapshot
+}
+
+// run выполняет задачу, превращая панику в ошибку задачи
+func (m *Manager) run(ctx context.Context, j *job, fn Func) (result *Result, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			m.logger.Error("job panicked",
+				zap.String("job_id", j.snapshot.ID),
+				zap.String("panic", fmt.Sprint(r)),
+				zap.ByteString("stack", debug.Stack()),
+			)
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+
+	return fn(ctx, j)
+}
+
+// Get возвращает состояние задачи
+func (m *Manager) Get(id string) (Snapshot, error) {
+	j, err := m.find(id)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	snapshot, _ := j.state()
+	return snapshot, nil
+}
+
+// Result возвращает состояние задачи и ее результат, если она завершилась успешно
+func (m *Manager) Result(id string) (Snapshot, *Result, error) {
+	j, err := m.find(id)
+	if err != nil {
+		return Snapshot{}, nil, err
+	}
+
+	snapshot, result := j.state()
+	return snapshot, result, nil
+}
+
+// Latest возвращает последнюю задачу типа jobType, запущенную пользователем owner
+func (m *Manager) Latest(jobType string, owner int64) (Snapshot, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.removeExpired()
+	var latest Snapshot
+	found := false
+	for _, j := range m.jobs {
+		snapshot, _ := j.state()
+		if snapshot.Type != jobType || snapshot.Owner != owner {
+			continue
+		}
+		if !found || snapshot.CreatedAt.After(latest.CreatedAt) {
+			latest, found = snapshot, true
+		}
+	}
+	if !found {
+		return Snapshot{}, ErrJobNotFound
+	}
+	return latest, nil
+}
+
+// Shutdown отменяет выполняющиеся задачи и ожидает их завершения
+func (m *Manager) Shutdown() {
+	m.cancel()
+	m.wg.Wait()
+}
+
+func (m *Manager) find(id string) (*job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.removeExpired()
+	j, ok := m.jobs[id]
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	return j, nil
+}
+
+// removeExpired удаляет задачи, завершившиеся раньше resultTTL. Вызывается под mu.
+func (m *Manager) removeExpired() {
+	for id, j := range m.jobs {
+		snapshot, _ := j.state()
+		if !snapshot.ExpiresAt.IsZero() && time.Now().After(snapshot.ExpiresAt) {
+			delete(m.jobs, id)
+		}
+	}
+}
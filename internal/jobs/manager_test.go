@@ -0,0 +1,169 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// waitFinished ожидает завершения задачи
+func waitFinished(t *testing.T, m *Manager, id string) Snapshot {
+	t.Helper()
+
+	var snapshot Snapshot
+	require.Eventually(t, func() bool {
+		var err error
+		snapshot, err = m.Get(id)
+		require.NoError(t, err)
+		return snapshot.Status != StatusRunning
+	}, time.Second, 5*time.Millisecond)
+	return snapshot
+}
+
+func TestManager_Completed(t *testing.T) {
+	m := NewManager(time.Hour, zap.NewNop())
+	defer m.Shutdown()
+
+	release := make(chan struct{})
+	started := m.Start("test", func(ctx context.Context, progress Progress) (*Result, error) {
+		progress.SetTotal(3)
+		progress.Advance(true)
+		progress.Advance(false)
+		<-release
+		progress.Advance(true)
+		return &Result{ContentType: "text/plain", Data: []byte("done")}, nil
+	})
+
+	assert.Equal(t, StatusRunning, started.Status)
+	assert.Equal(t, "test", started.Type)
+
+	// Результат недоступен, пока задача выполняется
+	require.Eventually(t, func() bool {
+		snapshot, _ := m.Get(started.ID)
+		return snapshot.Processed == 2
+	}, time.Second, 5*time.Millisecond)
+	_, result, err := m.Result(started.ID)
+	require.NoError(t, err)
+	assert.Nil(t, result)
+
+	close(release)
+	snapshot := waitFinished(t, m, started.ID)
+
+	assert.Equal(t, StatusCompleted, snapshot.Status)
+	assert.Equal(t, 3, snapshot.Total)
+	assert.Equal(t, 3, snapshot.Processed)
+	assert.Equal(t, 1, snapshot.Failed)
+	assert.False(t, snapshot.FinishedAt.IsZero())
+	assert.Equal(t, snapshot.FinishedAt.Add(time.Hour), snapshot.ExpiresAt)
+
+	_, result, err = m.Result(started.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("done"), result.Data)
+}
+
+func TestManager_Failed(t *testing.T) {
+	m := NewManager(time.Hour, zap.NewNop())
+	defer m.Shutdown()
+
+	failing := m.Start("test", func(context.Context, Progress) (*Result, error) {
+		return nil, errors.New("boom")
+	})
+	panicking := m.Start("test", func(context.Context, Progress) (*Result, error) {
+		panic("unexpected")
+	})
+
+	snapshot := waitFinished(t, m, failing.ID)
+	assert.Equal(t, StatusFailed, snapshot.Status)
+	assert.Equal(t, "boom", snapshot.Error)
+
+	snapshot = waitFinished(t, m, panicking.ID)
+	assert.Equal(t, StatusFailed, snapshot.Status)
+	assert.Contains(t, snapshot.Error, "unexpected")
+}
+
+func TestManager_NotFound(t *testing.T) {
+	m := NewManager(time.Hour, zap.NewNop())
+
+	_, err := m.Get("unknown")
+	assert.ErrorIs(t, err, ErrJobNotFound)
+
+	_, _, err = m.Result("unknown")
+	assert.ErrorIs(t, err, ErrJobNotFound)
+}
+
+func TestManager_Latest(t *testing.T) {
+	m := NewManager(time.Hour, zap.NewNop())
+	defer m.Shutdown()
+
+	done := func(context.Context, Progress) (*Result, error) { return &Result{}, nil }
+	_, err := m.Latest("export", 7)
+	assert.ErrorIs(t, err, ErrJobNotFound)
+
+	first := m.StartFor("export", 7, done)
+	waitFinished(t, m, first.ID)
+	time.Sleep(time.Millisecond)
+	second := m.StartFor("export", 7, done)
+	m.StartFor("export", 8, done)
+	m.Start("export", done)
+
+	latest, err := m.Latest("export", 7)
+	require.NoError(t, err)
+	assert.Equal(t, second.ID, latest.ID)
+	assert.Equal(t, int64(7), latest.Owner)
+
+	_, err = m.Latest("import", 7)
+	assert.ErrorIs(t, err, ErrJobNotFound)
+}
+
+func TestManager_ExpiredJobsRemoved(t *testing.T) {
+	m := NewManager(10*time.Millisecond, zap.NewNop())
+	defer m.Shutdown()
+
+	started := m.Start("test", func(context.Context, Progress) (*Result, error) {
+		return &Result{}, nil
+	})
+	waitFinished(t, m, started.ID)
+
+	time.Sleep(20 * time.Millisecond)
+	_, err := m.Get(started.ID)
+	assert.ErrorIs(t, err, ErrJobNotFound)
+}
+
+func TestManager_ShutdownCancelsJobs(t *testing.T) {
+	m := NewManager(time.Hour, zap.NewNop())
+
+	started := m.Start("test", func(ctx context.Context, _ Progress) (*Result, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	m.Shutdown()
+
+	snapshot, err := m.Get(started.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusFailed, snapshot.Status)
+}
+
+func TestManager_ProgressFormatting(t *testing.T) {
+	m := NewManager(time.Hour, zap.NewNop())
+	defer m.Shutdown()
+
+	// Форматирование progress (например, моками при сравнении аргументов) не должно
+	// читать состояние задачи, пока его меняют другие горутины
+	formatted := make(chan string, 1)
+	started := m.Start("test", func(ctx context.Context, progress Progress) (*Result, error) {
+		progress.SetTotal(1)
+		formatted <- fmt.Sprintf("%v", progress)
+		return nil, nil
+	})
+	_, _ = m.Get(started.ID)
+
+	assert.Equal(t, "job "+started.ID, <-formatted)
+	waitFinished(t, m, started.ID)
+}
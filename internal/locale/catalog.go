@@ -0,0 +1,92 @@
+package locale
+
+import (
+	"fmt"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+)
+
+// Translate переводит текст на язык l. Ключ каталога - исходный английский текст,
+// поэтому текст без перевода возвращается как есть
+func (l Locale) Translate(text string) string {
+	if translated, ok := catalog[l][text]; ok {
+		return translated
+	}
+	return text
+}
+
+// catalog - переводы текстов пользовательских эндпоинтов и выписки.
+// Ответы административных эндпоинтов не переводятся
+var catalog = map[Locale]map[string]string{
+	Russian: {
+		// Общие ошибки запросов
+		"invalid request body":                    "некорректное тело запроса",
+		"request body is too large":               "тело запроса слишком большое",
+		"route not found":                         "маршрут не найден",
+		"method not allowed":                      "метод не поддерживается",
+		"rate limit exceeded":                     "превышен лимит запросов",
+		"server is overloaded":                    "сервер перегружен",
+		"access from this address is not allowed": "доступ с этого адреса запрещен",
+		"limit must be a non-negative integer":    "limit должен быть неотрицательным целым числом",
+		"offset must be a non-negative integer":   "offset должен быть неотрицательным целым числом",
+		"invalid cursor":                          "некорректный курсор",
+		"invalid id":                              "некорректный идентификатор",
+		"unsupported locale":                      "язык не поддерживается",
+
+		// Пользователи и вход. Длина логина берется из domain, как в тексте ошибки
+		fmt.Sprintf("login must be from 1 to %d bytes", domain.MaxLoginLength): fmt.Sprintf("логин должен быть длиной от 1 до %d байт", domain.MaxLoginLength),
+
+		"user not found":                                       "пользователь не найден",
+		"login is required":                                    "логин обязателен",
+		"unknown provider":                                     "неизвестный провайдер",
+		"provider rejected the login":                          "провайдер отклонил вход",
+		"authorization denied by provider":                     "провайдер отказал в авторизации",
+		"invalid or expired login attempt":                     "попытка входа недействительна или истекла",
+		"account is already linked to another user":            "учетная запись уже привязана к другому пользователю",
+		"write operations are not allowed while impersonating": "изменения недоступны при входе от имени пользователя",
+		"email must be a plain address and phone must be in international format": "email должен быть простым адресом, а телефон - в международном формате",
+		"personal data encryption is not configured":                              "шифрование персональных данных не настроено",
+
+		// Заказы
+		"order not found":                          "заказ не найден",
+		"invalid order filter":                     "некорректный фильтр заказов",
+		"invalid order metadata":                   "некорректные метаданные заказа",
+		"invalid receipt QR code":                  "некорректный QR код чека",
+		"from must be an RFC 3339 timestamp":       "from должен быть временем в формате RFC 3339",
+		"to must be an RFC 3339 timestamp":         "to должен быть временем в формате RFC 3339",
+		"invalid timeout":                          "некорректный timeout",
+		"too many order submissions":               "слишком много загрузок заказов",
+		"order submissions are temporarily banned": "загрузка заказов временно запрещена",
+		"order is not processed":                   "заказ еще не обработан",
+		"dispute already open":                     "спор по заказу уже открыт",
+		"comment is too long":                      "комментарий слишком длинный",
+
+		// Баланс и списания
+		"withdrawal not found":      "списание не найдено",
+		"withdrawal limit exceeded": "превышен лимит списаний",
+		"partner is too long":       "идентификатор партнера слишком длинный",
+		"invalid withdrawals page":  "некорректная страница списаний",
+
+		// Выгрузка данных
+		"export not found":        "выгрузка не найдена",
+		"export is still running": "выгрузка еще выполняется",
+		"export failed":           "выгрузка завершилась ошибкой",
+		"export has no result":    "у выгрузки нет результата",
+
+		// Выписка
+		"Account statement": "Выписка по счету",
+		"Login":             "Логин",
+		"Generated":         "Сформирована",
+		"Accrued":           "Начислено",
+		"Withdrawn":         "Списано",
+		"Balance":           "Баланс",
+		"Date":              "Дата",
+		"Order":             "Заказ",
+		"Operation":         "Операция",
+		"Amount, points":    "Сумма, баллы",
+		"accrual":           "начисление",
+		"withdrawal":        "списание",
+		"adjustment":        "корректировка",
+		"No transactions":   "Операций нет",
+	},
+}
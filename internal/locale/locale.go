@@ -0,0 +1,135 @@
+// Package locale хранит язык ответа в контексте запроса и переводит тексты,
+// которые формирует сервис: сообщения об ошибках API и выписки.
+package locale
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+// Locale - язык ответа в виде базового кода BCP 47 ("en", "ru")
+type Locale string
+
+// Поддерживаемые языки
+const (
+	English Locale = "en"
+	Russian Locale = "ru"
+)
+
+// Default - язык без выбора пользователя и без подходящего Accept-Language.
+// Исходные тексты сервиса написаны на нем, поэтому перевод для него не нужен
+const Default = English
+
+// ErrUnsupported - язык не поддерживается
+var ErrUnsupported = errors.New("unsupported locale")
+
+// supported - поддерживаемые языки в порядке предпочтения при равном качестве
+var supported = []Locale{English, Russian}
+
+var matcher = language.NewMatcher([]language.Tag{language.English, language.Russian})
+
+// Supported возвращает поддерживаемые языки
+func Supported() []Locale {
+	return append([]Locale(nil), supported...)
+}
+
+// Parse приводит тег BCP 47 к поддерживаемому языку: "ru-RU" и "RU" дают Russian
+func Parse(s string) (Locale, error) {
+	tag, err := language.Parse(strings.TrimSpace(s))
+	if err != nil {
+		return "", ErrUnsupported
+	}
+	base, _ := tag.Base()
+	for _, l := range supported {
+		if string(l) == base.String() {
+			return l, nil
+		}
+	}
+	return "", ErrUnsupported
+}
+
+// FromAcceptLanguage выбирает поддерживаемый язык по заголовку Accept-Language.
+// Пустой, некорректный заголовок или только неподдерживаемые языки дают Default
+func FromAcceptLanguage(header string) Locale {
+	tags, _, err := language.ParseAcceptLanguage(header)
+	if err != nil || len(tags) == 0 {
+		return Default
+	}
+	_, index, confidence := matcher.Match(tags...)
+	if confidence == language.No {
+		return Default
+	}
+	return supported[index]
+}
+
+// tag возвращает тег языка для форматирования; неизвестный язык форматируется как Default
+func (l Locale) tag() language.Tag {
+	if l == Russian {
+		return language.Russian
+	}
+	return language.English
+}
+
+// FormatAmount форматирует сумму с двумя знаками и разделителями разрядов языка:
+// 1,234.50 для английского и 1 234,50 для русского
+func (l Locale) FormatAmount(amount float64) string {
+	return message.NewPrinter(l.tag()).Sprint(number.Decimal(amount, number.Scale(2)))
+}
+
+// FormatDate форматирует дату в принятом для языка виде
+func (l Locale) FormatDate(t time.Time) string {
+	if l == Russian {
+		return t.Format("02.01.2006")
+	}
+	return t.Format("2006-01-02")
+}
+
+type contextKey struct{}
+
+// preference - язык контекста. Выбор пользователя загружается при первом обращении:
+// большинству запросов язык не нужен, и они не обращаются за ним к БД
+type preference struct {
+	once     sync.Once
+	fallback Locale
+	lookup   func() (Locale, bool)
+	value    Locale
+}
+
+func (p *preference) locale() Locale {
+	p.once.Do(func() {
+		p.value = p.fallback
+		if p.lookup == nil {
+			return
+		}
+		if l, ok := p.lookup(); ok {
+			p.value = l
+		}
+	})
+	return p.value
+}
+
+// With возвращает контекст с языком l
+func With(ctx context.Context, l Locale) context.Context {
+	return context.WithValue(ctx, contextKey{}, &preference{fallback: l})
+}
+
+// WithLookup возвращает контекст, язык которого определяется при первом обращении:
+// lookup возвращает сохраненный выбор пользователя, без него действует язык из ctx
+func WithLookup(ctx context.Context, lookup func() (Locale, bool)) context.Context {
+	return context.WithValue(ctx, contextKey{}, &preference{fallback: From(ctx), lookup: lookup})
+}
+
+// From возвращает язык из контекста или Default
+func From(ctx context.Context) Locale {
+	if p, ok := ctx.Value(contextKey{}).(*preference); ok {
+		return p.locale()
+	}
+	return Default
+}
@@ -0,0 +1,99 @@
+package locale
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Locale
+		wantErr bool
+	}{
+		{input: "ru", want: Russian},
+		{input: "ru-RU", want: Russian},
+		{input: " EN-us ", want: English},
+		{input: "de", wantErr: true},
+		{input: "not a tag", wantErr: true},
+		{input: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := Parse(tt.input)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrUnsupported)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFromAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		header string
+		want   Locale
+	}{
+		{header: "ru-RU,ru;q=0.9,en;q=0.8", want: Russian},
+		{header: "de-DE,ru;q=0.5", want: Russian},
+		{header: "en-GB", want: English},
+		{header: "de", want: Default},
+		{header: "", want: Default},
+		{header: ";;;", want: Default},
+	}
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			assert.Equal(t, tt.want, FromAcceptLanguage(tt.header))
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	at := time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, "1,234,567.50", English.FormatAmount(1234567.5))
+	assert.Equal(t, "1\u00a0234\u00a0567,50", Russian.FormatAmount(1234567.5))
+	assert.Equal(t, "-0.01", English.FormatAmount(-0.01))
+	assert.Equal(t, "2024-03-05", English.FormatDate(at))
+	assert.Equal(t, "05.03.2024", Russian.FormatDate(at))
+}
+
+func TestTranslate(t *testing.T) {
+	assert.Equal(t, "заказ не найден", Russian.Translate("order not found"))
+	assert.Equal(t, "order not found", English.Translate("order not found"))
+	assert.Equal(t, "логин должен быть длиной от 1 до 255 байт", Russian.Translate("login must be from 1 to 255 bytes"))
+	// Текст без перевода возвращается как есть
+	assert.Equal(t, "something new", Russian.Translate("something new"))
+}
+
+func TestContext(t *testing.T) {
+	t.Run("Default without locale", func(t *testing.T) {
+		assert.Equal(t, Default, From(context.Background()))
+	})
+
+	t.Run("Lookup overrides fallback once", func(t *testing.T) {
+		calls := 0
+		ctx := WithLookup(With(context.Background(), English), func() (Locale, bool) {
+			calls++
+			return Russian, true
+		})
+
+		assert.Equal(t, Russian, From(ctx))
+		assert.Equal(t, Russian, From(ctx))
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("Fallback without user choice", func(t *testing.T) {
+		ctx := WithLookup(With(context.Background(), Russian), func() (Locale, bool) {
+			return "", false
+		})
+
+		assert.Equal(t, Russian, From(ctx))
+	})
+}
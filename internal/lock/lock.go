@@ -0,0 +1,114 @@
+package lock
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// SettlementKey - ключ блокировки выгрузки списаний для взаиморасчетов
+const SettlementKey = "settlement"
+
+// ReconciliationKey - ключ блокировки сверки заказов с журналом транзакций: отчета
+// о расхождениях, корректировок и исправлений начислений
+const ReconciliationKey = "reconciliation"
+
+// UserKey возвращает ключ блокировки баланса пользователя
+func UserKey(userID int64) string {
+	return "user:" + strconv.FormatInt(userID, 10)
+}
+
+// Backend хранит блокировки с ограниченным сроком (lease). Блокировка с истекшим
+// сроком считается снятой, поэтому упавшая реплика не держит ключ вечно.
+type Backend interface {
+	// TryAcquire занимает свободный ключ под token на ttl; false - ключ занят
+	TryAcquire(ctx context.Context, key, token string, ttl time.Duration) (bool, error)
+	// Release освобождает ключ, только если он занят под token
+	Release(ctx context.Context, key, token string) error
+}
+
+// Config содержит настройки блокировок
+type Config struct {
+	TTL           time.Duration // Срок блокировки, больше самой долгой операции под ней
+	RetryInterval time.Duration // Пауза между попытками занять ключ
+}
+
+// DefaultConfig возвращает конфигурацию по умолчанию
+func DefaultConfig() Config {
+	return Config{
+		TTL:           30 * time.Second,
+		RetryInterval: 50 * time.Millisecond,
+	}
+}
+
+// Locker выдает блокировки по ключу, общие для всех реплик
+type Locker struct {
+	backend Backend
+	config  Config
+	logger  *zap.Logger
+}
+
+// New создает новый Locker
+func New(backend Backend, config Config, logger *zap.Logger) *Locker {
+	defaults := DefaultConfig()
+	if config.TTL <= 0 {
+		config.TTL = defaults.TTL
+	}
+	if config.RetryInterval <= 0 {
+		config.RetryInterval = defaults.RetryInterval
+	}
+	return &Locker{
+		backend: backend,
+		config:  config,
+		logger:  logger,
+	}
+}
+
+// Lock ждет, пока ключ освободится, и занимает его. Ожидание прерывается отменой контекста.
+// unlock снимает блокировку; ошибка снятия пишется в лог - ключ освободится по истечении срока.
+func (l *Locker) Lock(ctx context.Context, key string) (unlock func(), err error) {
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+
+	ticker := time.NewTicker(l.config.RetryInterval)
+	defer ticker.Stop()
+	for {
+		acquired, err := l.backend.TryAcquire(ctx, key, token, l.config.TTL)
+		if err != nil {
+			return nil, fmt.Errorf("lock: failed to acquire %q: %w", key, err)
+		}
+		if acquired {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("lock: waiting for %q interrupted: %w", key, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+
+	return func() {
+		// Блокировку нужно снять, даже если запрос уже отменен
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), l.config.RetryInterval+time.Second)
+		defer cancel()
+		if err := l.backend.Release(ctx, key, token); err != nil {
+			l.logger.Warn("failed to release lock", zap.String("key", key), zap.Error(err))
+		}
+	}, nil
+}
+
+// newToken возвращает случайный идентификатор владельца блокировки
+func newToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("lock: failed to generate token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,125 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// memoryBackend хранит блокировки в памяти процесса
+type memoryBackend struct {
+	mu      sync.Mutex
+	owners  map[string]string
+	err     error
+	release int
+}
+
+func newMemoryBackend() *memoryBackend {
+	return &memoryBackend{owners: make(map[string]string)}
+}
+
+func (b *memoryBackend) TryAcquire(_ context.Context, key, token string, _ time.Duration) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.err != nil {
+		return false, b.err
+	}
+	if _, ok := b.owners[key]; ok {
+		return false, nil
+	}
+	b.owners[key] = token
+	return true, nil
+}
+
+func (b *memoryBackend) Release(_ context.Context, key, token string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.release++
+	if b.owners[key] == token {
+		delete(b.owners, key)
+	}
+	return nil
+}
+
+func TestNew_Defaults(t *testing.T) {
+	l := New(nil, Config{}, zap.NewNop())
+
+	assert.Equal(t, DefaultConfig(), l.config)
+}
+
+func TestLocker_Lock(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Waits for release", func(t *testing.T) {
+		backend := newMemoryBackend()
+		l := New(backend, Config{RetryInterval: time.Millisecond}, zap.NewNop())
+
+		unlock, err := l.Lock(ctx, UserKey(1))
+		require.NoError(t, err)
+
+		acquired := make(chan struct{})
+		go func() {
+			unlock, err := l.Lock(ctx, UserKey(1))
+			if assert.NoError(t, err) {
+				unlock()
+			}
+			close(acquired)
+		}()
+
+		select {
+		case <-acquired:
+			t.Fatal("lock acquired while held")
+		case <-time.After(20 * time.Millisecond):
+		}
+
+		unlock()
+		select {
+		case <-acquired:
+		case <-time.After(time.Second):
+			t.Fatal("lock not acquired after release")
+		}
+		assert.Empty(t, backend.owners)
+	})
+
+	t.Run("Different keys do not block", func(t *testing.T) {
+		l := New(newMemoryBackend(), Config{}, zap.NewNop())
+
+		unlock1, err := l.Lock(ctx, UserKey(1))
+		require.NoError(t, err)
+		defer unlock1()
+		unlock2, err := l.Lock(ctx, UserKey(2))
+		require.NoError(t, err)
+		defer unlock2()
+	})
+
+	t.Run("Context canceled while waiting", func(t *testing.T) {
+		l := New(newMemoryBackend(), Config{RetryInterval: time.Millisecond}, zap.NewNop())
+		unlock, err := l.Lock(ctx, SettlementKey)
+		require.NoError(t, err)
+		defer unlock()
+
+		waitCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		_, err = l.Lock(waitCtx, SettlementKey)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("Backend error", func(t *testing.T) {
+		backend := newMemoryBackend()
+		backend.err = errors.New("connection refused")
+		l := New(backend, Config{}, zap.NewNop())
+
+		_, err := l.Lock(ctx, UserKey(1))
+		assert.ErrorIs(t, err, backend.err)
+	})
+}
+
+func TestUserKey(t *testing.T) {
+	assert.Equal(t, "user:42", UserKey(42))
+}
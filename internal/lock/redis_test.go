@@ -0,0 +1,72 @@
+package lock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRedisBackend(t *testing.T) (*RedisBackend, *miniredis.Miniredis) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	backend, err := NewRedisBackend("redis://" + server.Addr())
+	require.NoError(t, err)
+	t.Cleanup(func() { backend.Close() })
+	return backend, server
+}
+
+func TestRedisBackend(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Acquire and release", func(t *testing.T) {
+		backend, server := newTestRedisBackend(t)
+
+		acquired, err := backend.TryAcquire(ctx, "user:1", "a", time.Minute)
+		require.NoError(t, err)
+		assert.True(t, acquired)
+		assert.Equal(t, time.Minute, server.TTL(redisKeyPrefix+"user:1"))
+
+		acquired, err = backend.TryAcquire(ctx, "user:1", "b", time.Minute)
+		require.NoError(t, err)
+		assert.False(t, acquired)
+
+		// Чужой владелец не снимает блокировку
+		require.NoError(t, backend.Release(ctx, "user:1", "b"))
+		assert.True(t, server.Exists(redisKeyPrefix+"user:1"))
+
+		require.NoError(t, backend.Release(ctx, "user:1", "a"))
+		assert.False(t, server.Exists(redisKeyPrefix+"user:1"))
+	})
+
+	t.Run("Expired lock is free", func(t *testing.T) {
+		backend, server := newTestRedisBackend(t)
+
+		acquired, err := backend.TryAcquire(ctx, "settlement", "a", time.Second)
+		require.NoError(t, err)
+		require.True(t, acquired)
+
+		server.FastForward(2 * time.Second)
+		acquired, err = backend.TryAcquire(ctx, "settlement", "b", time.Second)
+		require.NoError(t, err)
+		assert.True(t, acquired)
+	})
+
+	t.Run("Unavailable", func(t *testing.T) {
+		backend, server := newTestRedisBackend(t)
+		server.Close()
+
+		_, err := backend.TryAcquire(ctx, "user:1", "a", time.Second)
+		assert.Error(t, err)
+		assert.Error(t, backend.Ping(ctx))
+	})
+
+	t.Run("Invalid URL", func(t *testing.T) {
+		_, err := NewRedisBackend("http://localhost")
+		assert.Error(t, err)
+	})
+}
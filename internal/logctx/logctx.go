@@ -0,0 +1,45 @@
+package logctx
+
+import (
+	"context"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+)
+
+type ctxKey struct{}
+
+var defaultLogger atomic.Pointer[zap.Logger]
+
+func init() {
+	defaultLogger.Store(zap.NewNop())
+}
+
+// SetDefault задает логгер, который возвращается для контекстов без логгера
+func SetDefault(logger *zap.Logger) {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	defaultLogger.Store(logger)
+}
+
+// With возвращает копию контекста с переданным логгером
+func With(ctx context.Context, logger *zap.Logger) context.Context {
+	return context.WithValue(ctx, ctxKey{}, logger)
+}
+
+// WithFields дополняет логгер из контекста полями и возвращает новый контекст
+func WithFields(ctx context.Context, fields ...zap.Field) context.Context {
+	return With(ctx, From(ctx).With(fields...))
+}
+
+// From извлекает логгер из контекста.
+// Если логгер не задан, возвращается логгер по умолчанию.
+func From(ctx context.Context) *zap.Logger {
+	if ctx != nil {
+		if logger, ok := ctx.Value(ctxKey{}).(*zap.Logger); ok && logger != nil {
+			return logger
+		}
+	}
+	return defaultLogger.Load()
+}
@@ -0,0 +1,44 @@
+package logctx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestFrom(t *testing.T) {
+	t.Run("Default logger when context is empty", func(t *testing.T) {
+		core, logs := observer.New(zap.DebugLevel)
+		SetDefault(zap.New(core))
+		defer SetDefault(nil)
+
+		From(context.Background()).Info("message")
+		assert.Equal(t, 1, logs.Len())
+	})
+
+	t.Run("Logger from context", func(t *testing.T) {
+		core, logs := observer.New(zap.DebugLevel)
+		ctx := With(context.Background(), zap.New(core))
+
+		From(ctx).Info("message")
+		assert.Equal(t, 1, logs.Len())
+	})
+}
+
+func TestWithFields(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	ctx := With(context.Background(), zap.New(core))
+	ctx = WithFields(ctx, zap.String("request_id", "req-1"))
+	ctx = WithFields(ctx, zap.Int64("user_id", 7))
+
+	From(ctx).Info("message")
+
+	entries := logs.All()
+	assert.Len(t, entries, 1)
+	fields := entries[0].ContextMap()
+	assert.Equal(t, "req-1", fields["request_id"])
+	assert.Equal(t, int64(7), fields["user_id"])
+}
@@ -0,0 +1,382 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "gophermart"
+
+// Metrics содержит метрики приложения.
+// Все методы безопасны для вызова на nil, поэтому компоненты
+// могут работать без метрик (например, в тестах).
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests  *prometheus.CounterVec
+	httpDuration  *prometheus.HistogramVec
+	slowRequests  *prometheus.CounterVec
+	shedRequests  *prometheus.CounterVec
+	rateLimited   *prometheus.CounterVec
+	inFlight      prometheus.Gauge
+	slowQueries   prometheus.Counter
+	dbDuration    *prometheus.HistogramVec
+	workerPanics  *prometheus.CounterVec
+	workerQueue   prometheus.Gauge
+	workerBacklog prometheus.Gauge
+	workerPaused  prometheus.Gauge
+	workerOrders  *prometheus.HistogramVec
+
+	duplicateOrders prometheus.Counter
+	submissionAbuse *prometheus.CounterVec
+
+	tokenFailures *prometheus.CounterVec
+
+	accrualResponses  *prometheus.CounterVec
+	accrualDuration   *prometheus.HistogramVec
+	accrualRetryAfter prometheus.Histogram
+
+	shutdownRequests *prometheus.GaugeVec
+	shutdownOrders   *prometheus.GaugeVec
+}
+
+// New создает метрики и регистрирует их в собственном реестре
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "Number of HTTP requests by method, chi route pattern and status code.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "Duration of HTTP requests by method and chi route pattern.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		slowRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "slow_requests_total",
+			Help:      "Number of HTTP requests exceeding the slow request threshold.",
+		}, []string{"method", "route"}),
+		shedRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "shed_requests_total",
+			Help:      "Number of HTTP requests rejected with 503 because the in-flight limit was reached.",
+		}, []string{"route"}),
+		rateLimited: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "rate_limited_requests_total",
+			Help:      "Number of HTTP requests rejected with 429 by limit (requests or availability).",
+		}, []string{"limit"}),
+		inFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "in_flight_weight",
+			Help:      "Total weight of HTTP requests currently counted against the in-flight limit.",
+		}),
+		slowQueries: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "db",
+			Name:      "slow_queries_total",
+			Help:      "Number of SQL queries exceeding the slow query threshold.",
+		}),
+		dbDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "db",
+			Name:      "query_duration_seconds",
+			Help:      "Duration of SQL queries by operation (select, insert, update, delete or other).",
+			Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"operation"}),
+		workerPanics: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "worker",
+			Name:      "panics_total",
+			Help:      "Number of recovered panics in background worker goroutines.",
+		}, []string{"component"}),
+		workerQueue: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "worker",
+			Name:      "queue_length",
+			Help:      "Number of orders waiting in the worker queue.",
+		}),
+		workerBacklog: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "worker",
+			Name:      "pending_orders",
+			Help:      "Number of orders without a final status found by the last scan plus orders submitted since.",
+		}),
+		workerPaused: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "worker",
+			Name:      "paused",
+			Help:      "Whether accrual processing is paused by an administrator (1) or running (0).",
+		}),
+		workerOrders: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "worker",
+			Name:      "order_duration_seconds",
+			Help:      "Duration of processing one order by result: final, pending, rate_limited, invalid_response, accrual_error, apply_error, already_final or panic.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"result"}),
+		duplicateOrders: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "orders",
+			Name:      "duplicate_submissions_total",
+			Help:      "Number of submissions of orders already uploaded by the same user.",
+		}),
+		submissionAbuse: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "orders",
+			Name:      "submission_abuse_total",
+			Help:      "Number of suspicious order submissions by event: conflict, rate_limited, banned.",
+		}, []string{"event"}),
+		tokenFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "auth",
+			Name:      "token_failures_total",
+			Help:      "Number of rejected access tokens by reason (expired, malformed, signature or invalid).",
+		}, []string{"reason"}),
+		accrualResponses: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "accrual",
+			Name:      "responses_total",
+			Help:      "Number of accrual system responses by status (200, 204, 429, 4xx, 5xx or error).",
+		}, []string{"status"}),
+		accrualDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "accrual",
+			Name:      "request_duration_seconds",
+			Help:      "Duration of a single HTTP request to the accrual system, retries are observed separately.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"status"}),
+		accrualRetryAfter: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "accrual",
+			Name:      "retry_after_seconds",
+			Help:      "Retry-After values returned by the accrual system with 429 responses.",
+			Buckets:   []float64{1, 5, 10, 30, 60, 120, 300, 600},
+		}),
+		shutdownRequests: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "shutdown",
+			Name:      "requests",
+			Help:      "HTTP requests at graceful shutdown: in_flight when it started, then drained or aborted by the timeout.",
+		}, []string{"state"}),
+		shutdownOrders: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "shutdown",
+			Name:      "abandoned_orders",
+			Help:      "Orders left in the worker queues when the worker pool stopped, by queue (main or retry).",
+		}, []string{"queue"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpDuration,
+		m.slowRequests,
+		m.shedRequests,
+		m.rateLimited,
+		m.inFlight,
+		m.slowQueries,
+		m.dbDuration,
+		m.workerPanics,
+		m.workerQueue,
+		m.workerBacklog,
+		m.workerPaused,
+		m.workerOrders,
+		m.duplicateOrders,
+		m.submissionAbuse,
+		m.tokenFailures,
+		m.accrualResponses,
+		m.accrualDuration,
+		m.accrualRetryAfter,
+		m.shutdownRequests,
+		m.shutdownOrders,
+	)
+
+	return m
+}
+
+// Handler возвращает HTTP обработчик для экспорта метрик
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// ObserveHTTPRequest учитывает обработанный HTTP запрос.
+// route должен быть шаблоном маршрута, а не сырым путем, иначе число рядов метрики не ограничено.
+func (m *Metrics) ObserveHTTPRequest(method, route string, status int, duration time.Duration) {
+	if m == nil {
+		return
+	}
+	m.httpRequests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	m.httpDuration.WithLabelValues(method, route).Observe(duration.Seconds())
+}
+
+// ObserveSlowRequest учитывает медленный HTTP запрос
+func (m *Metrics) ObserveSlowRequest(method, route string) {
+	if m == nil {
+		return
+	}
+	m.slowRequests.WithLabelValues(method, route).Inc()
+}
+
+// ObserveShedRequest учитывает запрос, отклоненный из-за перегрузки
+func (m *Metrics) ObserveShedRequest(route string) {
+	if m == nil {
+		return
+	}
+	m.shedRequests.WithLabelValues(route).Inc()
+}
+
+// ObserveRateLimited учитывает запрос, отклоненный лимитом запросов с одного адреса
+func (m *Metrics) ObserveRateLimited(limit string) {
+	if m == nil {
+		return
+	}
+	m.rateLimited.WithLabelValues(limit).Inc()
+}
+
+// ObserveInFlightRequests обновляет суммарный вес выполняемых запросов
+func (m *Metrics) ObserveInFlightRequests(weight int64) {
+	if m == nil {
+		return
+	}
+	m.inFlight.Set(float64(weight))
+}
+
+// ObserveSlowQuery учитывает медленный SQL запрос
+func (m *Metrics) ObserveSlowQuery() {
+	if m == nil {
+		return
+	}
+	m.slowQueries.Inc()
+}
+
+// ObserveQuery учитывает длительность SQL запроса. operation - первое ключевое слово
+// запроса в нижнем регистре, чтобы число рядов метрики не зависело от текста запросов
+func (m *Metrics) ObserveQuery(operation string, duration time.Duration) {
+	if m == nil {
+		return
+	}
+	m.dbDuration.WithLabelValues(operation).Observe(duration.Seconds())
+}
+
+// ObserveWorkerPanic учитывает перехваченную панику в фоновой горутине
+func (m *Metrics) ObserveWorkerPanic(component string) {
+	if m == nil {
+		return
+	}
+	m.workerPanics.WithLabelValues(component).Inc()
+}
+
+// ObserveWorkerBacklog обновляет длину очереди воркеров и число необработанных заказов
+func (m *Metrics) ObserveWorkerBacklog(queued, pending int) {
+	if m == nil {
+		return
+	}
+	m.workerQueue.Set(float64(queued))
+	m.workerBacklog.Set(float64(pending))
+}
+
+// ObserveWorkerPaused учитывает приостановку и возобновление обработки заказов
+func (m *Metrics) ObserveWorkerPaused(paused bool) {
+	if m == nil {
+		return
+	}
+	if paused {
+		m.workerPaused.Set(1)
+	} else {
+		m.workerPaused.Set(0)
+	}
+}
+
+// ObserveOrderProcessed учитывает обработку заказа воркером: число обработанных заказов
+// по результату - это счетчик гистограммы
+func (m *Metrics) ObserveOrderProcessed(result string, duration time.Duration) {
+	if m == nil {
+		return
+	}
+	m.workerOrders.WithLabelValues(result).Observe(duration.Seconds())
+}
+
+// ObserveDuplicateOrder учитывает повторную загрузку заказа, уже загруженного тем же пользователем
+func (m *Metrics) ObserveDuplicateOrder() {
+	if m == nil {
+		return
+	}
+	m.duplicateOrders.Inc()
+}
+
+// ObserveSubmissionAbuse учитывает подозрительную загрузку заказа: номер чужого заказа (conflict),
+// превышение частоты загрузок (rate_limited) или выданный запрет (banned)
+func (m *Metrics) ObserveSubmissionAbuse(event string) {
+	if m == nil {
+		return
+	}
+	m.submissionAbuse.WithLabelValues(event).Inc()
+}
+
+// ObserveTokenFailure учитывает отклоненный токен доступа с причиной отказа
+func (m *Metrics) ObserveTokenFailure(reason string) {
+	if m == nil {
+		return
+	}
+	m.tokenFailures.WithLabelValues(reason).Inc()
+}
+
+// ObserveAccrualRequest учитывает запрос к системе начислений и его длительность
+func (m *Metrics) ObserveAccrualRequest(status string, duration time.Duration) {
+	if m == nil {
+		return
+	}
+	m.accrualResponses.WithLabelValues(status).Inc()
+	m.accrualDuration.WithLabelValues(status).Observe(duration.Seconds())
+}
+
+// ObserveAccrualRetryAfter учитывает значение Retry-After из ответа 429
+func (m *Metrics) ObserveAccrualRetryAfter(retryAfter time.Duration) {
+	if m == nil {
+		return
+	}
+	m.accrualRetryAfter.Observe(retryAfter.Seconds())
+}
+
+// ObserveShutdownStarted учитывает запросы, выполнявшиеся в момент начала остановки сервера
+func (m *Metrics) ObserveShutdownStarted(inFlight int64) {
+	if m == nil {
+		return
+	}
+	m.shutdownRequests.WithLabelValues("in_flight").Set(float64(inFlight))
+}
+
+// ObserveShutdownRequests учитывает, сколько запросов завершилось и сколько прервано при остановке
+func (m *Metrics) ObserveShutdownRequests(drained, aborted int64) {
+	if m == nil {
+		return
+	}
+	m.shutdownRequests.WithLabelValues("drained").Set(float64(drained))
+	m.shutdownRequests.WithLabelValues("aborted").Set(float64(aborted))
+}
+
+// ObserveAbandonedOrders учитывает заказы, оставшиеся в очередях воркеров при остановке пула
+func (m *Metrics) ObserveAbandonedOrders(queued, retries int) {
+	if m == nil {
+		return
+	}
+	m.shutdownOrders.WithLabelValues("main").Set(float64(queued))
+	m.shutdownOrders.WithLabelValues("retry").Set(float64(retries))
+}
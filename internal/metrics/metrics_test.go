@@ -0,0 +1,209 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics_SlowRequests(t *testing.T) {
+	m := New()
+
+	m.ObserveSlowRequest(http.MethodGet, "/api/user/orders")
+	m.ObserveSlowRequest(http.MethodGet, "/api/user/orders")
+	m.ObserveSlowRequest(http.MethodPost, "/api/user/orders")
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.slowRequests.WithLabelValues(http.MethodGet, "/api/user/orders")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.slowRequests.WithLabelValues(http.MethodPost, "/api/user/orders")))
+}
+
+func TestMetrics_HTTPRequests(t *testing.T) {
+	m := New()
+
+	m.ObserveHTTPRequest(http.MethodGet, "/api/user/orders/{number}", http.StatusOK, 10*time.Millisecond)
+	m.ObserveHTTPRequest(http.MethodGet, "/api/user/orders/{number}", http.StatusNotFound, 20*time.Millisecond)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.httpRequests.WithLabelValues(http.MethodGet, "/api/user/orders/{number}", "200")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.httpRequests.WithLabelValues(http.MethodGet, "/api/user/orders/{number}", "404")))
+	assert.Equal(t, 1, testutil.CollectAndCount(m.httpDuration))
+}
+
+func TestMetrics_LoadShedding(t *testing.T) {
+	m := New()
+
+	m.ObserveShedRequest("/api/user/orders")
+	m.ObserveInFlightRequests(7)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.shedRequests.WithLabelValues("/api/user/orders")))
+	assert.Equal(t, 7.0, testutil.ToFloat64(m.inFlight))
+}
+
+func TestMetrics_Shutdown(t *testing.T) {
+	m := New()
+
+	m.ObserveShutdownStarted(5)
+	m.ObserveShutdownRequests(3, 2)
+	m.ObserveAbandonedOrders(4, 1)
+
+	assert.Equal(t, 5.0, testutil.ToFloat64(m.shutdownRequests.WithLabelValues("in_flight")))
+	assert.Equal(t, 3.0, testutil.ToFloat64(m.shutdownRequests.WithLabelValues("drained")))
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.shutdownRequests.WithLabelValues("aborted")))
+	assert.Equal(t, 4.0, testutil.ToFloat64(m.shutdownOrders.WithLabelValues("main")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.shutdownOrders.WithLabelValues("retry")))
+}
+
+func TestMetrics_SlowQueries(t *testing.T) {
+	m := New()
+
+	m.ObserveSlowQuery()
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.slowQueries))
+}
+
+func TestMetrics_WorkerPanics(t *testing.T) {
+	m := New()
+
+	m.ObserveWorkerPanic("worker")
+	m.ObserveWorkerPanic("scanner")
+	m.ObserveWorkerPanic("worker")
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.workerPanics.WithLabelValues("worker")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.workerPanics.WithLabelValues("scanner")))
+}
+
+func TestMetrics_WorkerBacklog(t *testing.T) {
+	m := New()
+
+	m.ObserveWorkerBacklog(5, 120)
+
+	assert.Equal(t, 5.0, testutil.ToFloat64(m.workerQueue))
+	assert.Equal(t, 120.0, testutil.ToFloat64(m.workerBacklog))
+}
+
+func TestMetrics_WorkerPaused(t *testing.T) {
+	m := New()
+
+	m.ObserveWorkerPaused(true)
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.workerPaused))
+
+	m.ObserveWorkerPaused(false)
+	assert.Equal(t, 0.0, testutil.ToFloat64(m.workerPaused))
+}
+
+func TestMetrics_DuplicateOrders(t *testing.T) {
+	m := New()
+
+	m.ObserveDuplicateOrder()
+	m.ObserveDuplicateOrder()
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.duplicateOrders))
+}
+
+func TestMetrics_SubmissionAbuse(t *testing.T) {
+	m := New()
+
+	m.ObserveSubmissionAbuse("conflict")
+	m.ObserveSubmissionAbuse("conflict")
+	m.ObserveSubmissionAbuse("banned")
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.submissionAbuse.WithLabelValues("conflict")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.submissionAbuse.WithLabelValues("banned")))
+}
+
+func TestMetrics_TokenFailures(t *testing.T) {
+	m := New()
+
+	m.ObserveTokenFailure("expired")
+	m.ObserveTokenFailure("expired")
+	m.ObserveTokenFailure("signature")
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.tokenFailures.WithLabelValues("expired")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.tokenFailures.WithLabelValues("signature")))
+}
+
+func TestMetrics_Accrual(t *testing.T) {
+	m := New()
+
+	m.ObserveAccrualRequest("200", 50*time.Millisecond)
+	m.ObserveAccrualRequest("429", 10*time.Millisecond)
+	m.ObserveAccrualRequest("200", 20*time.Millisecond)
+	m.ObserveAccrualRetryAfter(60 * time.Second)
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.accrualResponses.WithLabelValues("200")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.accrualResponses.WithLabelValues("429")))
+	assert.Equal(t, 2, testutil.CollectAndCount(m.accrualDuration))
+
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Contains(t, w.Body.String(), `gophermart_accrual_request_duration_seconds_count{status="200"} 2`)
+	assert.Contains(t, w.Body.String(), "gophermart_accrual_retry_after_seconds_sum 60")
+}
+
+func TestMetrics_NilSafe(t *testing.T) {
+	var m *Metrics
+
+	assert.NotPanics(t, func() {
+		m.ObserveHTTPRequest(http.MethodGet, "/", http.StatusOK, time.Second)
+		m.ObserveSlowRequest(http.MethodGet, "/")
+		m.ObserveSlowQuery()
+		m.ObserveQuery("select", time.Second)
+		m.ObserveRateLimited("requests")
+		m.ObserveOrderProcessed("final", time.Second)
+		m.ObserveWorkerPanic("worker")
+		m.ObserveWorkerPaused(true)
+		m.ObserveDuplicateOrder()
+		m.ObserveSubmissionAbuse("conflict")
+		m.ObserveTokenFailure("expired")
+		m.ObserveAccrualRequest("200", time.Second)
+		m.ObserveAccrualRetryAfter(time.Second)
+	})
+}
+
+func TestMetrics_Handler(t *testing.T) {
+	m := New()
+	m.ObserveSlowQuery()
+
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "gophermart_db_slow_queries_total 1")
+}
+
+func TestMetrics_RateLimited(t *testing.T) {
+	m := New()
+
+	m.ObserveRateLimited("requests")
+	m.ObserveRateLimited("availability")
+	m.ObserveRateLimited("requests")
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.rateLimited.WithLabelValues("requests")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.rateLimited.WithLabelValues("availability")))
+}
+
+func TestMetrics_Queries(t *testing.T) {
+	m := New()
+
+	m.ObserveQuery("select", 3*time.Millisecond)
+	m.ObserveQuery("insert", 5*time.Millisecond)
+
+	assert.Equal(t, 2, testutil.CollectAndCount(m.dbDuration))
+}
+
+func TestMetrics_OrderProcessed(t *testing.T) {
+	m := New()
+
+	m.ObserveOrderProcessed("final", 100*time.Millisecond)
+	m.ObserveOrderProcessed("final", 50*time.Millisecond)
+	m.ObserveOrderProcessed("rate_limited", time.Millisecond)
+
+	assert.Equal(t, 2, testutil.CollectAndCount(m.workerOrders))
+
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Contains(t, w.Body.String(), `gophermart_worker_order_duration_seconds_count{result="final"} 2`)
+}
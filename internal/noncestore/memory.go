@@ -0,0 +1,51 @@
+// Package noncestore запоминает одноразовые значения (nonce) запросов на короткий срок,
+// чтобы повтор перехваченного запроса можно было отклонить: в памяти процесса для одного
+// экземпляра и в Redis для нескольких реплик.
+package noncestore
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore хранит nonce в памяти процесса. Другие экземпляры их не видят,
+// поэтому хранилище подходит только для одного экземпляра
+type MemoryStore struct {
+	mu        sync.Mutex
+	nonces    map[string]time.Time // nonce -> момент, когда его можно забыть
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewMemoryStore создает пустое хранилище в памяти
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		nonces: make(map[string]time.Time),
+		now:    time.Now,
+	}
+}
+
+// Remember запоминает nonce на ttl. false - nonce уже предъявлялся и еще не забыт.
+// Истекшие nonce удаляются не чаще раза за ttl, поэтому память ограничена числом
+// запросов за два срока
+func (s *MemoryStore) Remember(_ context.Context, nonce string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if now.Sub(s.lastSweep) >= ttl {
+		for n, expiresAt := range s.nonces {
+			if !expiresAt.After(now) {
+				delete(s.nonces, n)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	if expiresAt, ok := s.nonces[nonce]; ok && expiresAt.After(now) {
+		return false, nil
+	}
+	s.nonces[nonce] = now.Add(ttl)
+	return true, nil
+}
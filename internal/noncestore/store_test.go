@@ -0,0 +1,94 @@
+package noncestore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// nonceStore - общий метод хранилищ, проверяемый одними тестами
+type nonceStore interface {
+	Remember(ctx context.Context, nonce string, ttl time.Duration) (bool, error)
+}
+
+// testStore - хранилище и сдвиг его часов вперед
+type testStore struct {
+	store   nonceStore
+	advance func(d time.Duration)
+}
+
+func newTestStores(t *testing.T) map[string]func() testStore {
+	return map[string]func() testStore{
+		"Memory": func() testStore {
+			store := NewMemoryStore()
+			now := time.Now()
+			store.now = func() time.Time { return now }
+			return testStore{store: store, advance: func(d time.Duration) { now = now.Add(d) }}
+		},
+		"Redis": func() testStore {
+			server := miniredis.RunT(t)
+			store, err := NewRedisStore("redis://" + server.Addr())
+			require.NoError(t, err)
+			t.Cleanup(func() { store.Close() })
+			return testStore{store: store, advance: server.FastForward}
+		},
+	}
+}
+
+func TestNonceStores(t *testing.T) {
+	ctx := context.Background()
+
+	for name, newStore := range newTestStores(t) {
+		t.Run(name, func(t *testing.T) {
+			t.Run("Repeated nonce is rejected", func(t *testing.T) {
+				s := newStore()
+
+				stored, err := s.store.Remember(ctx, "a", time.Minute)
+				require.NoError(t, err)
+				assert.True(t, stored)
+
+				stored, err = s.store.Remember(ctx, "a", time.Minute)
+				require.NoError(t, err)
+				assert.False(t, stored)
+
+				stored, err = s.store.Remember(ctx, "b", time.Minute)
+				require.NoError(t, err)
+				assert.True(t, stored)
+			})
+
+			t.Run("Nonce is forgotten after ttl", func(t *testing.T) {
+				s := newStore()
+
+				stored, err := s.store.Remember(ctx, "a", time.Minute)
+				require.NoError(t, err)
+				require.True(t, stored)
+
+				s.advance(2 * time.Minute)
+				stored, err = s.store.Remember(ctx, "a", time.Minute)
+				require.NoError(t, err)
+				assert.True(t, stored)
+			})
+		})
+	}
+}
+
+func TestMemoryStore_ForgetsExpiredNonces(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	for _, nonce := range []string{"a", "b", "c"} {
+		_, err := store.Remember(ctx, nonce, time.Minute)
+		require.NoError(t, err)
+	}
+	now = now.Add(2 * time.Minute)
+
+	_, err := store.Remember(ctx, "d", time.Minute)
+	require.NoError(t, err)
+	assert.Len(t, store.nonces, 1)
+}
@@ -0,0 +1,148 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+// FindHalfAppliedAccruals находит заказы, начисление которых применено наполовину:
+// обработанный заказ с начислением без записи в журнале или запись в журнале
+// у заказа без финального статуса. Amount - начисление, которое восстановит исправление
+func (r *OrderRepository) FindHalfAppliedAccruals(ctx context.Context, limit int) ([]*domain.AccrualRepair, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT o.number, o.user_id, o.status, o.accrual, t.amount 
+		 FROM orders o 
+		 LEFT JOIN ( 
+			SELECT order_number, SUM(amount) AS amount 
+			FROM transactions 
+			WHERE type IN ($1, $2) 
+			GROUP BY order_number 
+		 ) t ON t.order_number = o.number 
+		 WHERE (o.status = $3 AND o.accrual > 0 AND t.order_number IS NULL) 
+		    OR (o.status IN ($4, $5) AND t.order_number IS NOT NULL) 
+		 ORDER BY o.id 
+		 LIMIT $6`,
+		domain.TransactionTypeAccrual, domain.TransactionTypeAdjustment,
+		domain.OrderStatusProcessed, domain.OrderStatusNew, domain.OrderStatusProcessing, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to find half-applied accruals: %w", err)
+	}
+	defer rows.Close()
+
+	var repairs []*domain.AccrualRepair
+	for rows.Next() {
+		var (
+			repair        domain.AccrualRepair
+			status        domain.OrderStatus
+			orderAccrual  *float64
+			ledgerAccrual *float64
+		)
+		if err := rows.Scan(&repair.OrderNumber, &repair.UserID, &status, &orderAccrual, &ledgerAccrual); err != nil {
+			return nil, fmt.Errorf("repository: failed to scan half-applied accrual: %w", err)
+		}
+		if ledgerAccrual == nil {
+			repair.Kind, repair.Amount = domain.AccrualRepairMissingTransaction, *orderAccrual
+		} else {
+			repair.Kind, repair.Amount = domain.AccrualRepairMissingStatus, *ledgerAccrual
+		}
+		repairs = append(repairs, &repair)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repository: error iterating half-applied accruals: %w", err)
+	}
+
+	return repairs, nil
+}
+
+// RepairAccrual доводит наполовину примененное начисление заказа до конца. Заказ
+// и строка баланса пользователя блокируются, как при обработке и корректировке, а состояние проверяется
+// заново, поэтому исправление не расходится с параллельно работающими воркерами.
+// Недостающая транзакция начисления создается по начислению заказа; заказ без
+// финального статуса переводится в PROCESSED с начислением из журнала и событием
+// order.processed. Если расхождения уже нет, возвращает ErrAccrualConsistent
+func (r *OrderRepository) RepairAccrual(ctx context.Context, number string) (*domain.AccrualRepair, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to begin transaction for order %q: %w", number, err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // Rollback после Commit безопасен
+
+	repair := &domain.AccrualRepair{OrderNumber: number}
+	var status domain.OrderStatus
+	var accrual *float64
+	var metadata *domain.OrderMetadata
+	err = tx.QueryRow(ctx,
+		`SELECT user_id, status, accrual, metadata FROM orders WHERE number = $1 FOR UPDATE`,
+		number,
+	).Scan(&repair.UserID, &status, &accrual, &metadata)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrOrderNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to lock order %q: %w", number, err)
+	}
+
+	if err := lockBalancesTx(ctx, tx, repair.UserID); err != nil {
+		return nil, err
+	}
+
+	var entries int
+	var recorded float64
+	err = tx.QueryRow(ctx,
+		`SELECT COUNT(*), COALESCE(SUM(amount), 0) 
+		 FROM transactions 
+		 WHERE order_number = $1 AND type IN ($2, $3)`,
+		number, domain.TransactionTypeAccrual, domain.TransactionTypeAdjustment,
+	).Scan(&entries, &recorded)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to get recorded accrual for order %q: %w", number, err)
+	}
+
+	switch {
+	case status == domain.OrderStatusProcessed && accrual != nil && *accrual > 0 && entries == 0:
+		repair.Kind, repair.Amount = domain.AccrualRepairMissingTransaction, *accrual
+		// Политика округления исходного начисления неизвестна, сумма уже округлена
+		_, err = tx.Exec(ctx,
+			`INSERT INTO transactions (user_id, order_number, amount, type, public_id, accrual_raw) 
+			 VALUES ($1, $2, $3, $4, $5, $3) 
+			 ON CONFLICT (order_number) WHERE type = 'accrual' DO NOTHING`,
+			repair.UserID, number, repair.Amount, domain.TransactionTypeAccrual, newPublicID(),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("repository: failed to create accrual for order %q: %w", number, err)
+		}
+
+	case !status.IsFinal() && entries > 0:
+		repair.Kind, repair.Amount = domain.AccrualRepairMissingStatus, recorded
+		_, err = tx.Exec(ctx,
+			`UPDATE orders SET status = $1, accrual = $2 WHERE number = $3`,
+			domain.OrderStatusProcessed, recorded, number,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("repository: failed to update order %q status: %w", number, err)
+		}
+		_, err = tx.Exec(ctx,
+			`INSERT INTO order_events (type, order_number, user_id, status, accrual, metadata) 
+			 VALUES ($1, $2, $3, $4, $5, $6)`,
+			domain.OrderEventProcessed, number, repair.UserID, domain.OrderStatusProcessed, recorded, metadata,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("repository: failed to create event for order %q: %w", number, err)
+		}
+
+	default:
+		return nil, domain.ErrAccrualConsistent
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("repository: failed to commit repair of order %q: %w", number, err)
+	}
+
+	return repair, nil
+}
@@ -0,0 +1,116 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderRepository_FindHalfAppliedAccruals(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewOrderRepository(mock)
+	ctx := context.Background()
+	accrual, recorded := 100.0, 50.0
+
+	mock.ExpectQuery(`SELECT o.number, o.user_id, o.status, o.accrual, t.amount FROM orders o LEFT JOIN .* WHERE \(o.status = \$3 AND o.accrual > 0 AND t.order_number IS NULL\) OR \(o.status IN \(\$4, \$5\) AND t.order_number IS NOT NULL\) ORDER BY o.id LIMIT \$6`).
+		WithArgs(domain.TransactionTypeAccrual, domain.TransactionTypeAdjustment,
+			domain.OrderStatusProcessed, domain.OrderStatusNew, domain.OrderStatusProcessing, 100).
+		WillReturnRows(pgxmock.NewRows([]string{"number", "user_id", "status", "accrual", "amount"}).
+			AddRow("111", int64(1), domain.OrderStatusProcessed, &accrual, nil).
+			AddRow("222", int64(2), domain.OrderStatusProcessing, nil, &recorded))
+
+	repairs, err := repo.FindHalfAppliedAccruals(ctx, 100)
+	require.NoError(t, err)
+	assert.Equal(t, []*domain.AccrualRepair{
+		{OrderNumber: "111", UserID: 1, Kind: domain.AccrualRepairMissingTransaction, Amount: 100},
+		{OrderNumber: "222", UserID: 2, Kind: domain.AccrualRepairMissingStatus, Amount: 50},
+	}, repairs)
+
+	mock.ExpectQuery(`FROM orders o`).
+		WithArgs(domain.TransactionTypeAccrual, domain.TransactionTypeAdjustment,
+			domain.OrderStatusProcessed, domain.OrderStatusNew, domain.OrderStatusProcessing, 100).
+		WillReturnError(errors.New("connection refused"))
+	_, err = repo.FindHalfAppliedAccruals(ctx, 100)
+	assert.Error(t, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOrderRepository_RepairAccrual(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewOrderRepository(mock)
+	ctx := context.Background()
+	accrual := 100.0
+
+	expectLocked := func(number string, status domain.OrderStatus, accrual *float64, entries int, recorded float64) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT user_id, status, accrual, metadata FROM orders WHERE number = \$1 FOR UPDATE`).
+			WithArgs(number).
+			WillReturnRows(pgxmock.NewRows([]string{"user_id", "status", "accrual", "metadata"}).
+				AddRow(int64(1), status, accrual, nil))
+		mock.ExpectExec(`INSERT INTO user_balances \(user_id\) VALUES \(\$1\) ON CONFLICT \(user_id\) DO UPDATE SET updated_at = user_balances.updated_at`).WithArgs(int64(1)).WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectQuery(`SELECT COUNT\(\*\), COALESCE\(SUM\(amount\), 0\) FROM transactions WHERE order_number = \$1 AND type IN \(\$2, \$3\)`).
+			WithArgs(number, domain.TransactionTypeAccrual, domain.TransactionTypeAdjustment).
+			WillReturnRows(pgxmock.NewRows([]string{"count", "sum"}).AddRow(entries, recorded))
+	}
+
+	t.Run("Missing ledger transaction", func(t *testing.T) {
+		expectLocked("111", domain.OrderStatusProcessed, &accrual, 0, 0)
+		mock.ExpectExec(`INSERT INTO transactions \(user_id, order_number, amount, type, public_id, accrual_raw\) VALUES \(\$1, \$2, \$3, \$4, \$5, \$3\) ON CONFLICT \(order_number\) WHERE type = 'accrual' DO NOTHING`).
+			WithArgs(int64(1), "111", 100.0, domain.TransactionTypeAccrual, pgxmock.AnyArg()).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectCommit()
+
+		repair, err := repo.RepairAccrual(ctx, "111")
+		require.NoError(t, err)
+		assert.Equal(t, &domain.AccrualRepair{OrderNumber: "111", UserID: 1, Kind: domain.AccrualRepairMissingTransaction, Amount: 100}, repair)
+	})
+
+	t.Run("Missing order status", func(t *testing.T) {
+		expectLocked("222", domain.OrderStatusProcessing, nil, 1, 50)
+		mock.ExpectExec(`UPDATE orders SET status = \$1, accrual = \$2 WHERE number = \$3`).
+			WithArgs(domain.OrderStatusProcessed, 50.0, "222").
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectExec(`INSERT INTO order_events`).
+			WithArgs(domain.OrderEventProcessed, "222", int64(1), domain.OrderStatusProcessed, 50.0, (*domain.OrderMetadata)(nil)).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectCommit()
+
+		repair, err := repo.RepairAccrual(ctx, "222")
+		require.NoError(t, err)
+		assert.Equal(t, &domain.AccrualRepair{OrderNumber: "222", UserID: 1, Kind: domain.AccrualRepairMissingStatus, Amount: 50}, repair)
+	})
+
+	t.Run("Already consistent", func(t *testing.T) {
+		expectLocked("333", domain.OrderStatusProcessed, &accrual, 1, 100)
+		mock.ExpectRollback()
+
+		_, err := repo.RepairAccrual(ctx, "333")
+		assert.ErrorIs(t, err, domain.ErrAccrualConsistent)
+	})
+
+	t.Run("Order not found", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`FROM orders WHERE number = \$1 FOR UPDATE`).
+			WithArgs("444").
+			WillReturnError(pgx.ErrNoRows)
+		mock.ExpectRollback()
+
+		_, err := repo.RepairAccrual(ctx, "444")
+		assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
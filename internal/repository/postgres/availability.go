@@ -0,0 +1,120 @@
+package postgres
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Pinger проверяет соединение с БД
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// Availability отслеживает доступность БД по результатам запросов.
+// Пока БД недоступна, фоновые задачи ждут восстановления через WaitAvailable,
+// а health check сообщает о деградации.
+type Availability struct {
+	mu        sync.Mutex
+	available bool
+	since     time.Time
+	recovered chan struct{} // закрывается при восстановлении
+	logger    *zap.Logger
+}
+
+// NewAvailability создает трекер, считающий БД доступной
+func NewAvailability(logger *zap.Logger) *Availability {
+	return &Availability{
+		available: true,
+		recovered: make(chan struct{}),
+		logger:    logger,
+	}
+}
+
+// Available сообщает, доступна ли БД
+func (a *Availability) Available() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.available
+}
+
+// UnavailableSince возвращает момент потери соединения, если БД недоступна
+func (a *Availability) UnavailableSince() (time.Time, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.since, !a.available
+}
+
+// MarkUnavailable переводит БД в недоступное состояние
+func (a *Availability) MarkUnavailable(err error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if !a.available {
+		return
+	}
+	a.available = false
+	a.since = time.Now()
+	a.recovered = make(chan struct{})
+	a.logger.Error("database unavailable, entering degraded mode", zap.Error(err))
+}
+
+// MarkAvailable отмечает восстановление соединения и будит ожидающих
+func (a *Availability) MarkAvailable() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.available {
+		return
+	}
+	a.logger.Info("database connection restored", zap.Duration("downtime", time.Since(a.since)))
+	a.available = true
+	a.since = time.Time{}
+	close(a.recovered)
+}
+
+// WaitAvailable блокируется, пока БД недоступна.
+// Возвращает false, если контекст отменен раньше.
+func (a *Availability) WaitAvailable(ctx context.Context) bool {
+	a.mu.Lock()
+	available, recovered := a.available, a.recovered
+	a.mu.Unlock()
+
+	if available {
+		return true
+	}
+
+	select {
+	case <-recovered:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// Watch периодически пингует БД, пока она недоступна, и отмечает восстановление.
+// Блокируется до отмены контекста.
+func (a *Availability) Watch(ctx context.Context, pinger Pinger, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if a.Available() {
+				continue
+			}
+
+			pingCtx, cancel := context.WithTimeout(ctx, interval)
+			err := pinger.Ping(pingCtx)
+			cancel()
+			if err == nil {
+				a.MarkAvailable()
+			}
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type flakyPinger struct {
+	healthy atomic.Bool
+}
+
+func (p *flakyPinger) Ping(context.Context) error {
+	if p.healthy.Load() {
+		return nil
+	}
+	return errors.New("connection refused")
+}
+
+func TestAvailability(t *testing.T) {
+	state := NewAvailability(zap.NewNop())
+
+	_, down := state.UnavailableSince()
+	assert.False(t, down)
+	assert.True(t, state.WaitAvailable(context.Background()))
+
+	state.MarkUnavailable(errors.New("connection refused"))
+	since, down := state.UnavailableSince()
+	assert.True(t, down)
+	assert.False(t, since.IsZero())
+
+	// Повторная отметка не сдвигает момент начала простоя
+	state.MarkUnavailable(errors.New("connection refused"))
+	again, _ := state.UnavailableSince()
+	assert.Equal(t, since, again)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.False(t, state.WaitAvailable(ctx))
+
+	done := make(chan bool)
+	go func() {
+		done <- state.WaitAvailable(context.Background())
+	}()
+	state.MarkAvailable()
+	assert.True(t, <-done)
+	assert.True(t, state.Available())
+}
+
+func TestAvailability_Watch(t *testing.T) {
+	state := NewAvailability(zap.NewNop())
+	pinger := &flakyPinger{}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go state.Watch(ctx, pinger, 5*time.Millisecond)
+
+	state.MarkUnavailable(errors.New("connection refused"))
+	time.Sleep(20 * time.Millisecond)
+	assert.False(t, state.Available())
+
+	pinger.healthy.Store(true)
+	assert.Eventually(t, state.Available, time.Second, 5*time.Millisecond)
+}
@@ -0,0 +1,117 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// CredentialsSource возвращает актуальную строку подключения к БД,
+// например из файла, который обновляет агент менеджера секретов
+type CredentialsSource func() (string, error)
+
+// Resetter закрывает соединения пула. Занятые соединения закрываются
+// после возврата в пул, поэтому выполняемые запросы не прерываются
+type Resetter interface {
+	Reset()
+}
+
+// Credentials хранит логин и пароль, с которыми пул открывает новые соединения,
+// и обновляет их из источника без перезапуска сервиса
+type Credentials struct {
+	source CredentialsSource
+	logger *zap.Logger
+
+	mu       sync.RWMutex
+	uri      string
+	target   string // Хост, порт и база: их смена требует перезапуска
+	user     string
+	password string
+}
+
+// NewCredentials создает Credentials и сразу читает строку подключения из источника
+func NewCredentials(source CredentialsSource, logger *zap.Logger) (*Credentials, error) {
+	c := &Credentials{source: source, logger: logger}
+	if _, err := c.Reload(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// BeforeConnect подставляет текущие логин и пароль в настройки нового соединения.
+// Предназначен для pgxpool.Config.BeforeConnect
+func (c *Credentials) BeforeConnect(_ context.Context, connConfig *pgx.ConnConfig) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	connConfig.User = c.user
+	connConfig.Password = c.password
+	return nil
+}
+
+// Reload перечитывает строку подключения и сообщает, сменились ли логин или пароль.
+// При ошибке остаются прежние значения
+func (c *Credentials) Reload() (bool, error) {
+	uri, err := c.source()
+	if err != nil {
+		return false, fmt.Errorf("postgres: failed to load credentials: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if uri == c.uri {
+		return false, nil
+	}
+
+	connConfig, err := pgx.ParseConfig(uri)
+	if err != nil {
+		return false, fmt.Errorf("postgres: invalid database URI: %w", err)
+	}
+
+	target := fmt.Sprintf("%s:%d/%s", connConfig.Host, connConfig.Port, connConfig.Database)
+	if c.target != "" && target != c.target {
+		c.logger.Warn("database address changed, restart is required to apply it",
+			zap.String("current", c.target),
+			zap.String("new", target),
+		)
+	}
+	if c.target == "" {
+		c.target = target
+	}
+
+	changed := connConfig.User != c.user || connConfig.Password != c.password
+	c.uri = uri
+	c.user = connConfig.User
+	c.password = connConfig.Password
+	return changed, nil
+}
+
+// Watch периодически перечитывает строку подключения. После смены логина или пароля
+// свободные соединения пула закрываются сразу, занятые - после завершения запроса,
+// а новые открываются уже с новыми учетными данными. Блокируется до отмены контекста.
+func (c *Credentials) Watch(ctx context.Context, pool Resetter, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			changed, err := c.Reload()
+			if err != nil {
+				c.logger.Warn("failed to reload database credentials, keeping current ones", zap.Error(err))
+				continue
+			}
+			if changed {
+				c.logger.Info("database credentials changed, reconnecting pool")
+				pool.Reset()
+			}
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// rotatingSource - источник строки подключения, которую тест меняет на ходу
+type rotatingSource struct {
+	mu  sync.Mutex
+	uri string
+	err error
+}
+
+func (s *rotatingSource) set(uri string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.uri, s.err = uri, err
+}
+
+func (s *rotatingSource) load() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.uri, s.err
+}
+
+type countingResetter struct {
+	resets atomic.Int32
+}
+
+func (r *countingResetter) Reset() { r.resets.Add(1) }
+
+func connCredentials(t *testing.T, c *Credentials) (string, string) {
+	t.Helper()
+	connConfig := &pgx.ConnConfig{}
+	require.NoError(t, c.BeforeConnect(context.Background(), connConfig))
+	return connConfig.User, connConfig.Password
+}
+
+func TestCredentials_Reload(t *testing.T) {
+	source := &rotatingSource{uri: "postgres://app:v1@db:5432/gophermart"}
+	c, err := NewCredentials(source.load, zap.NewNop())
+	require.NoError(t, err)
+
+	user, password := connCredentials(t, c)
+	assert.Equal(t, "app", user)
+	assert.Equal(t, "v1", password)
+
+	// Та же строка - изменений нет
+	changed, err := c.Reload()
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	// Другие параметры без смены учетных данных не требуют переподключения
+	source.set("postgres://app:v1@db:5432/gophermart?sslmode=disable", nil)
+	changed, err = c.Reload()
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	source.set("postgres://app-rotated:v2@db:5432/gophermart", nil)
+	changed, err = c.Reload()
+	require.NoError(t, err)
+	assert.True(t, changed)
+	user, password = connCredentials(t, c)
+	assert.Equal(t, "app-rotated", user)
+	assert.Equal(t, "v2", password)
+
+	// При ошибке остаются прежние учетные данные
+	source.set("", errors.New("secret is not mounted"))
+	_, err = c.Reload()
+	require.Error(t, err)
+	source.set("postgres://app:v3@db:notaport/gophermart", nil)
+	_, err = c.Reload()
+	require.Error(t, err)
+	user, password = connCredentials(t, c)
+	assert.Equal(t, "app-rotated", user)
+	assert.Equal(t, "v2", password)
+}
+
+func TestNewCredentials_InvalidSource(t *testing.T) {
+	_, err := NewCredentials(func() (string, error) { return "", errors.New("no such file") }, zap.NewNop())
+	assert.Error(t, err)
+}
+
+func TestCredentials_Watch(t *testing.T) {
+	source := &rotatingSource{uri: "postgres://app:v1@db/gophermart"}
+	c, err := NewCredentials(source.load, zap.NewNop())
+	require.NoError(t, err)
+	pool := &countingResetter{}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go c.Watch(ctx, pool, 5*time.Millisecond)
+
+	time.Sleep(20 * time.Millisecond)
+	assert.Zero(t, pool.resets.Load())
+
+	source.set("postgres://app:v2@db/gophermart", nil)
+	assert.Eventually(t, func() bool { return pool.resets.Load() == 1 }, time.Second, 5*time.Millisecond)
+
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, int32(1), pool.resets.Load())
+}
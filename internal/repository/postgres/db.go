@@ -2,16 +2,46 @@ package postgres
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 )
 
-// DBTX определяет интерфейс для работы с базой данных.
-// Позволяет использовать как реальный пул соединений, так и моки в тестах.
-type DBTX interface {
+// Querier определяет выполнение запросов - общее у пула соединений и транзакции.
+type Querier interface {
 	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
 	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
 	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+}
+
+// DBTX определяет интерфейс для работы с базой данных.
+// Позволяет использовать как реальный пул соединений, так и моки в тестах.
+// pgx.Tx тоже реализует DBTX: Begin внутри транзакции создает точку сохранения,
+// поэтому методы репозиториев со своей транзакцией работают и внутри внешней
+type DBTX interface {
+	Querier
 	Begin(ctx context.Context) (pgx.Tx, error)
 }
+
+// RunInTx выполняет fn в транзакции: фиксирует ее, если fn завершилась без ошибки,
+// и откатывает иначе. Репозитории, привязанные к tx через WithTx, видят изменения
+// друг друга, и все изменения фиксируются вместе. Вызванная с pgx.Tx, RunInTx работает
+// в точке сохранения: ошибка fn откатывает только изменения fn, и внешняя транзакция
+// может продолжаться
+func RunInTx(ctx context.Context, db DBTX, fn func(tx pgx.Tx) error) error {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("repository: failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // Rollback после Commit безопасен
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("repository: failed to commit transaction: %w", err)
+	}
+	return nil
+}
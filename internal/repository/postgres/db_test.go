@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunInTx(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	ctx := context.Background()
+	users := NewUserRepository(mock, nil)
+	transactions := NewTransactionRepository(mock)
+
+	t.Run("Repositories share the transaction", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO users`).
+			WithArgs("alice", "hash").
+			WillReturnRows(pgxmock.NewRows([]string{"id", "login", "password_hash", "created_at"}).
+				AddRow(int64(7), "alice", "hash", time.Now()))
+		// Своя транзакция метода внутри внешней - точка сохранения
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE user_balances`).
+			WithArgs(int64(7), 10.0).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectExec(`INSERT INTO transactions`).
+			WithArgs(int64(7), "12345678903", -10.0, domain.TransactionTypeWithdrawal, pgxmock.AnyArg(), "").
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectCommit()
+		mock.ExpectCommit()
+
+		err := RunInTx(ctx, mock, func(tx pgx.Tx) error {
+			user, err := users.WithTx(tx).CreateUser(ctx, "alice", "hash")
+			if err != nil {
+				return err
+			}
+			return transactions.WithTx(tx).WithdrawWithLock(ctx, user.ID, "12345678903", 10, "", domain.WithdrawalLimits{})
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("Error rolls back everything", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO users`).
+			WithArgs("bob", "hash").
+			WillReturnRows(pgxmock.NewRows([]string{"id", "login", "password_hash", "created_at"}).
+				AddRow(int64(8), "bob", "hash", time.Now()))
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE user_balances`).
+			WithArgs(int64(8), 10.0).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+		mock.ExpectRollback()
+		mock.ExpectRollback()
+
+		err := RunInTx(ctx, mock, func(tx pgx.Tx) error {
+			user, err := users.WithTx(tx).CreateUser(ctx, "bob", "hash")
+			if err != nil {
+				return err
+			}
+			return transactions.WithTx(tx).WithdrawWithLock(ctx, user.ID, "12345678903", 10, "", domain.WithdrawalLimits{})
+		})
+		assert.ErrorIs(t, err, domain.ErrInsufficientFunds)
+	})
+
+	t.Run("Begin error", func(t *testing.T) {
+		mock.ExpectBegin().WillReturnError(errors.New("connection refused"))
+
+		err := RunInTx(ctx, mock, func(pgx.Tx) error {
+			t.Fatal("fn must not be called")
+			return nil
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("Commit error", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectCommit().WillReturnError(errors.New("serialization failure"))
+		mock.ExpectRollback()
+
+		err := RunInTx(ctx, mock, func(pgx.Tx) error { return nil })
+		assert.Error(t, err)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
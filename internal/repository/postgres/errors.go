@@ -1,22 +1,119 @@
 package postgres
 
-import "errors"
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
 
-// Ошибки пользователей
-var (
-	ErrUserExists   = errors.New("user already exists")
-	ErrUserNotFound = errors.New("user not found")
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
-// Ошибки заказов
-var (
-	ErrOrderExists         = errors.New("order already exists")
-	ErrOrderOwnedByAnother = errors.New("order owned by another user")
-	ErrOrderNotFound       = errors.New("order not found")
+// Коды ошибок PostgreSQL, которые репозитории переводят в доменные ошибки.
+// Полный список: https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	pgCodeUniqueViolation     = "23505"
+	pgCodeForeignKeyViolation = "23503"
+	pgCodeCheckViolation      = "23514"
 )
 
-// Ошибки транзакций и баланса
-var (
-	ErrInsufficientFunds = errors.New("insufficient funds")
-	ErrDuplicateAccrual  = errors.New("accrual already exists for this order")
+// Коды временных ошибок, после которых запрос можно повторить
+const (
+	pgCodeSerializationFailure = "40001"
+	pgCodeDeadlockDetected     = "40P01"
+	pgCodeTooManyConnections   = "53300"
+	pgCodeAdminShutdown        = "57P01"
+	pgCodeCrashShutdown        = "57P02"
+	pgCodeCannotConnectNow     = "57P03"
+
+	// Класс 08 - ошибки соединения
+	pgClassConnectionException = "08"
+)
+
+// Классы ошибок, вызванных данными запроса: неверное значение и нарушение ограничения
+const (
+	pgClassDataException                = "22"
+	pgClassIntegrityConstraintViolation = "23"
 )
+
+// pgErrorCode возвращает SQLSTATE код ошибки PostgreSQL или пустую строку,
+// если ошибка не пришла от сервера БД
+func pgErrorCode(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code
+	}
+	return ""
+}
+
+// isUniqueViolation проверяет нарушение уникального ограничения
+func isUniqueViolation(err error) bool {
+	return pgErrorCode(err) == pgCodeUniqueViolation
+}
+
+// isForeignKeyViolation проверяет нарушение внешнего ключа
+func isForeignKeyViolation(err error) bool {
+	return pgErrorCode(err) == pgCodeForeignKeyViolation
+}
+
+// isCheckViolation проверяет нарушение CHECK ограничения
+func isCheckViolation(err error) bool {
+	return pgErrorCode(err) == pgCodeCheckViolation
+}
+
+// isDataError проверяет, что БД отклонила запрос из-за переданных данных, а не из-за сбоя:
+// такой запрос бесполезно повторять, но можно выполнить без отклоненной записи
+func isDataError(err error) bool {
+	code := pgErrorCode(err)
+	return strings.HasPrefix(code, pgClassDataException) || strings.HasPrefix(code, pgClassIntegrityConstraintViolation)
+}
+
+// isRetryable проверяет, что запрос не дошел до БД или был ею откачен
+// и его безопасно выполнить повторно
+func isRetryable(err error) bool {
+	if err == nil || isContextError(err) {
+		return false
+	}
+	if pgconn.SafeToRetry(err) || isDialError(err) {
+		return true
+	}
+
+	switch pgErrorCode(err) {
+	case pgCodeSerializationFailure, pgCodeDeadlockDetected, pgCodeTooManyConnections, pgCodeCannotConnectNow:
+		return true
+	}
+	return false
+}
+
+// isConnectionError проверяет, что ошибка вызвана потерей соединения с БД,
+// а не самим запросом
+func isConnectionError(err error) bool {
+	if err == nil || isContextError(err) {
+		return false
+	}
+	if isDialError(err) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	code := pgErrorCode(err)
+	switch {
+	case code == pgCodeAdminShutdown, code == pgCodeCrashShutdown, code == pgCodeCannotConnectNow:
+		return true
+	case strings.HasPrefix(code, pgClassConnectionException):
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// isDialError проверяет ошибку установки соединения (например, connection refused)
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
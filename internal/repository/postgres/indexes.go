@@ -0,0 +1,49 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ExpectedIndexes - индексы, на которые рассчитаны частые запросы. Миграции создают их
+// всегда, но индекс могут удалить вручную или оставить невалидным после прерванной сборки
+var ExpectedIndexes = []string{
+	"idx_orders_status_uploaded_at",           // Опрос необработанных и истечение старых заказов
+	"idx_orders_user_id_uploaded_at",          // Заказы пользователя постранично
+	"idx_transactions_user_id_amount",         // Баланс пользователя
+	"idx_transactions_user_type_processed_at", // Списания пользователя за окно
+	"idx_order_events_pending",                // Неотправленные события заказов
+}
+
+// MissingIndexes возвращает ожидаемые индексы, которых нет в текущей схеме или которые невалидны
+func MissingIndexes(ctx context.Context, db Querier, expected []string) ([]string, error) {
+	rows, err := db.Query(ctx,
+		`SELECT i.relname 
+		 FROM pg_index x 
+		 JOIN pg_class i ON i.oid = x.indexrelid 
+		 JOIN pg_namespace n ON n.oid = i.relnamespace 
+		 WHERE n.nspname = current_schema() AND i.relname = ANY($1) AND x.indisvalid`,
+		expected,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to list indexes: %w", err)
+	}
+	names, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to scan indexes: %w", err)
+	}
+
+	present := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		present[name] = struct{}{}
+	}
+	var missing []string
+	for _, name := range expected {
+		if _, ok := present[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	return missing, nil
+}
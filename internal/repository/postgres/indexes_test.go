@@ -0,0 +1,50 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMissingIndexes(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	expected := []string{"idx_a", "idx_b", "idx_c"}
+
+	t.Run("Some indexes are missing", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT i.relname FROM pg_index`).
+			WithArgs(expected).
+			WillReturnRows(pgxmock.NewRows([]string{"relname"}).AddRow("idx_b"))
+
+		missing, err := MissingIndexes(context.Background(), mock, expected)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"idx_a", "idx_c"}, missing)
+	})
+
+	t.Run("All indexes are present", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT i.relname FROM pg_index`).
+			WithArgs(expected).
+			WillReturnRows(pgxmock.NewRows([]string{"relname"}).AddRow("idx_c").AddRow("idx_a").AddRow("idx_b"))
+
+		missing, err := MissingIndexes(context.Background(), mock, expected)
+		require.NoError(t, err)
+		assert.Empty(t, missing)
+	})
+
+	t.Run("Query error", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT i.relname FROM pg_index`).
+			WithArgs(expected).
+			WillReturnError(errors.New("permission denied"))
+
+		_, err := MissingIndexes(context.Background(), mock, expected)
+		assert.Error(t, err)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,155 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Тестам этого файла нужна настоящая БД: функции хеша и триггеры журнала живут в ней.
+// Тесты работают в транзакции и откатывают ее, но проверки и отчеты охватывают весь
+// журнал, поэтому цепочки в базе из TEST_DATABASE_URI должны быть целыми.
+//
+//	TEST_DATABASE_URI=postgres://... go test ./internal/repository/postgres -run 'LedgerChain|MergeUsers_DB'
+
+// chainUsers создает в tx пользователей с одной транзакцией у каждого
+func chainUsers(t *testing.T, tx pgx.Tx, n int) []int64 {
+	t.Helper()
+	ctx := context.Background()
+	prefix := fmt.Sprintf("chain-%d", time.Now().UnixNano())
+
+	ids := make([]int64, n)
+	for i := range ids {
+		login := fmt.Sprintf("%s-%d", prefix, i)
+		require.NoError(t, tx.QueryRow(ctx,
+			`INSERT INTO users (login, password_hash) VALUES ($1, '') RETURNING id`, login,
+		).Scan(&ids[i]))
+		_, err := tx.Exec(ctx,
+			`INSERT INTO transactions (user_id, order_number, amount, type, public_id) VALUES ($1, $2, 100, $3, $4)`,
+			ids[i], login, domain.TransactionTypeAccrual, newPublicID(),
+		)
+		require.NoError(t, err)
+	}
+	return ids
+}
+
+func TestLedgerChain_OwnerRewriteBreaksVerification(t *testing.T) {
+	db := openDB(t, "TEST_DATABASE_URI")
+	ctx := context.Background()
+	tx, err := db.Begin(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback(ctx) //nolint:errcheck // Тест не фиксирует изменения
+
+	repo := NewTransactionRepository(tx)
+	ids := chainUsers(t, tx, 3)
+	result, err := repo.VerifyLedgerChain(ctx)
+	require.NoError(t, err)
+	require.Nil(t, result.Break)
+
+	// Первые записи двух цепочек меняются владельцами через пользователя без транзакций:
+	// номера в цепочках остаются без пропусков, расходятся только хеши
+	_, err = tx.Exec(ctx, `DELETE FROM transactions WHERE user_id = $1`, ids[2])
+	require.NoError(t, err)
+	for _, move := range [][2]int64{{ids[0], ids[2]}, {ids[1], ids[0]}, {ids[2], ids[1]}} {
+		_, err = tx.Exec(ctx, `UPDATE transactions SET user_id = $1 WHERE user_id = $2`, move[1], move[0])
+		require.NoError(t, err)
+	}
+
+	result, err = repo.VerifyLedgerChain(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, result.Break)
+	assert.Equal(t, ids[0], result.Break.UserID)
+	assert.Equal(t, int64(1), result.Break.Seq)
+	assert.Equal(t, domain.LedgerBreakHashMismatch, result.Break.Reason)
+}
+
+func TestLedgerChain_MergeKeepsChainsIntact(t *testing.T) {
+	db := openDB(t, "TEST_DATABASE_URI")
+	ctx := context.Background()
+	tx, err := db.Begin(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback(ctx) //nolint:errcheck // Тест не фиксирует изменения
+
+	ids := chainUsers(t, tx, 2)
+	var source, target string
+	require.NoError(t, tx.QueryRow(ctx, `SELECT login FROM users WHERE id = $1`, ids[0]).Scan(&source))
+	require.NoError(t, tx.QueryRow(ctx, `SELECT login FROM users WHERE id = $1`, ids[1]).Scan(&target))
+
+	_, err = NewUserRepository(tx, nil).MergeUsers(ctx, source, target, ids[1], false)
+	require.NoError(t, err)
+
+	result, err := NewTransactionRepository(tx).VerifyLedgerChain(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, result.Break)
+
+	var sourceBalance, targetBalance float64
+	require.NoError(t, tx.QueryRow(ctx, `SELECT SUM(amount) FROM transactions WHERE user_id = $1`, ids[0]).Scan(&sourceBalance))
+	require.NoError(t, tx.QueryRow(ctx, `SELECT SUM(amount) FROM transactions WHERE user_id = $1`, ids[1]).Scan(&targetBalance))
+	assert.Zero(t, sourceBalance)
+	assert.Equal(t, 200.0, targetBalance)
+}
+
+func TestMergeUsers_DBMovesWithdrawals(t *testing.T) {
+	db := openDB(t, "TEST_DATABASE_URI")
+	ctx := context.Background()
+	tx, err := db.Begin(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback(ctx) //nolint:errcheck // Тест не фиксирует изменения
+
+	ids := chainUsers(t, tx, 2)
+	var source, target string
+	require.NoError(t, tx.QueryRow(ctx, `SELECT login FROM users WHERE id = $1`, ids[0]).Scan(&source))
+	require.NoError(t, tx.QueryRow(ctx, `SELECT login FROM users WHERE id = $1`, ids[1]).Scan(&target))
+
+	transactions := NewTransactionRepository(tx)
+	number := source + "-w"
+	require.NoError(t, transactions.WithdrawWithLock(ctx, ids[0], number, 30, "shop", domain.WithdrawalLimits{}))
+	// Загруженный заказ с номером списания: начислений по нему в журнале быть не должно
+	_, err = tx.Exec(ctx, `INSERT INTO orders (user_id, number, status) VALUES ($1, $2, $3)`, ids[1], number, domain.OrderStatusNew)
+	require.NoError(t, err)
+
+	_, err = NewUserRepository(tx, nil).MergeUsers(ctx, source, target, ids[1], false)
+	require.NoError(t, err)
+
+	balance, err := transactions.GetBalance(ctx, ids[1])
+	require.NoError(t, err)
+	assert.Equal(t, &domain.Balance{Current: 170, Withdrawn: 30}, balance)
+	balance, err = transactions.GetBalance(ctx, ids[0])
+	require.NoError(t, err)
+	assert.Equal(t, &domain.Balance{}, balance)
+
+	withdrawals, err := transactions.GetWithdrawals(ctx, ids[1], nil, 0)
+	require.NoError(t, err)
+	require.Len(t, withdrawals, 1)
+	assert.Equal(t, number, withdrawals[0].OrderNumber)
+	assert.Equal(t, 30.0, withdrawals[0].Amount)
+	summary, err := transactions.GetWithdrawalSummary(ctx, ids[1])
+	require.NoError(t, err)
+	assert.Equal(t, 1, summary.Count)
+	assert.Equal(t, 30.0, summary.Total)
+
+	orders := NewOrderRepository(tx)
+	repairs, err := orders.FindHalfAppliedAccruals(ctx, 1000)
+	require.NoError(t, err)
+	for _, repair := range repairs {
+		assert.NotEqual(t, number, repair.OrderNumber)
+	}
+	mismatches, err := orders.FindAccrualMismatches(ctx, 1000, 0)
+	require.NoError(t, err)
+	for _, mismatch := range mismatches {
+		assert.NotEqual(t, number, mismatch.OrderNumber)
+	}
+
+	var settleable int
+	require.NoError(t, tx.QueryRow(ctx,
+		`SELECT COUNT(*) FROM transactions WHERE order_number = $1 AND type = $2 AND merged_from IS NULL`,
+		number, domain.TransactionTypeWithdrawal,
+	).Scan(&settleable))
+	assert.Equal(t, 1, settleable)
+}
@@ -0,0 +1,152 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// LockConn - выделенное соединение, в сессии которого удерживается advisory lock
+type LockConn interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Ping(ctx context.Context) error
+	Close(ctx context.Context) error
+}
+
+// Connector открывает выделенное соединение для LeaderElector
+type Connector func(ctx context.Context) (LockConn, error)
+
+// PoolConnector забирает соединение из пула насовсем (Hijack), чтобы сессия
+// с блокировкой не вернулась в пул и не досталась обычным запросам
+func PoolConnector(pool *pgxpool.Pool) Connector {
+	return func(ctx context.Context) (LockConn, error) {
+		conn, err := pool.Acquire(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return conn.Hijack(), nil
+	}
+}
+
+// LeaderConfig содержит настройки выбора лидера
+type LeaderConfig struct {
+	Key           int64         // Ключ advisory lock, общий для всех реплик
+	RenewInterval time.Duration // Интервал проверки сессии лидером и попыток захвата остальными
+	OnElected     func()        // Вызывается при получении лидерства
+}
+
+// LeaderElector выбирает среди реплик одного лидера через session-level advisory lock.
+// Блокировка живет, пока жива сессия: при падении лидера PostgreSQL снимает ее сам,
+// и следующая реплика захватывает ее в течение RenewInterval.
+type LeaderElector struct {
+	connect Connector
+	config  LeaderConfig
+	logger  *zap.Logger
+	leader  atomic.Bool
+	conn    LockConn // используется только горутиной Run
+}
+
+// NewLeaderElector создает LeaderElector
+func NewLeaderElector(connect Connector, config LeaderConfig, logger *zap.Logger) *LeaderElector {
+	return &LeaderElector{
+		connect: connect,
+		config:  config,
+		logger:  logger.With(zap.Int64("lock_key", config.Key)),
+	}
+}
+
+// IsLeader сообщает, удерживает ли реплика лидерство
+func (e *LeaderElector) IsLeader() bool {
+	return e.leader.Load()
+}
+
+// Run пытается получить лидерство и удерживает его до отмены контекста.
+// При остановке закрывает соединение, освобождая блокировку для других реплик.
+func (e *LeaderElector) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.config.RenewInterval)
+	defer ticker.Stop()
+	defer e.resign()
+
+	for {
+		e.campaign(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// campaign проверяет сессию лидера или пытается захватить блокировку
+func (e *LeaderElector) campaign(ctx context.Context) {
+	checkCtx, cancel := context.WithTimeout(ctx, e.config.RenewInterval)
+	defer cancel()
+
+	if e.IsLeader() {
+		if err := e.conn.Ping(checkCtx); err != nil {
+			e.logger.Warn("leadership lost", zap.Error(err))
+			e.resign()
+		}
+		return
+	}
+
+	acquired, err := e.tryLock(checkCtx)
+	if err != nil {
+		if ctx.Err() == nil {
+			e.logger.Warn("leader election attempt failed", zap.Error(err))
+		}
+		e.resign()
+		return
+	}
+	if !acquired {
+		return
+	}
+
+	e.leader.Store(true)
+	e.logger.Info("elected as leader")
+	if e.config.OnElected != nil {
+		e.config.OnElected()
+	}
+}
+
+// tryLock захватывает блокировку без ожидания. Соединение переиспользуется
+// между попытками, чтобы не открывать новое каждые RenewInterval.
+func (e *LeaderElector) tryLock(ctx context.Context) (bool, error) {
+	if e.conn == nil {
+		conn, err := e.connect(ctx)
+		if err != nil {
+			return false, fmt.Errorf("leader: failed to connect: %w", err)
+		}
+		e.conn = conn
+	}
+
+	var acquired bool
+	if err := e.conn.QueryRow(ctx, `SELECT pg_try_advisory_lock($1)`, e.config.Key).Scan(&acquired); err != nil {
+		return false, fmt.Errorf("leader: failed to acquire advisory lock: %w", err)
+	}
+
+	return acquired, nil
+}
+
+// resign снимает лидерство и закрывает соединение вместе с блокировкой
+func (e *LeaderElector) resign() {
+	if e.leader.Swap(false) {
+		e.logger.Info("leadership released")
+	}
+	if e.conn == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := e.conn.Close(ctx); err != nil {
+		e.logger.Debug("failed to close leader election connection", zap.Error(err))
+	}
+	e.conn = nil
+}
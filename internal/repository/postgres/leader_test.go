@@ -0,0 +1,134 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const testLockKey int64 = 42
+
+func newTestElector(t *testing.T, onElected func()) (*LeaderElector, pgxmock.PgxConnIface) {
+	mock, err := pgxmock.NewConn()
+	require.NoError(t, err)
+
+	connect := func(context.Context) (LockConn, error) { return mock, nil }
+	config := LeaderConfig{Key: testLockKey, RenewInterval: time.Second, OnElected: onElected}
+
+	return NewLeaderElector(connect, config, zap.NewNop()), mock
+}
+
+func expectTryLock(mock pgxmock.PgxConnIface, acquired bool) {
+	mock.ExpectQuery(`SELECT pg_try_advisory_lock\(\$1\)`).
+		WithArgs(testLockKey).
+		WillReturnRows(pgxmock.NewRows([]string{"pg_try_advisory_lock"}).AddRow(acquired))
+}
+
+func TestLeaderElector_Campaign(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Acquires lock", func(t *testing.T) {
+		var elected int
+		elector, mock := newTestElector(t, func() { elected++ })
+
+		expectTryLock(mock, true)
+		elector.campaign(ctx)
+
+		assert.True(t, elector.IsLeader())
+		assert.Equal(t, 1, elected)
+
+		// Лидер только проверяет сессию, не захватывая блокировку повторно
+		mock.ExpectPing()
+		elector.campaign(ctx)
+
+		assert.True(t, elector.IsLeader())
+		assert.Equal(t, 1, elected)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Lock held by another replica", func(t *testing.T) {
+		elector, mock := newTestElector(t, nil)
+
+		// Соединение переиспользуется между попытками
+		expectTryLock(mock, false)
+		expectTryLock(mock, false)
+		elector.campaign(ctx)
+		elector.campaign(ctx)
+
+		assert.False(t, elector.IsLeader())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Session lost", func(t *testing.T) {
+		elector, mock := newTestElector(t, nil)
+
+		expectTryLock(mock, true)
+		elector.campaign(ctx)
+		require.True(t, elector.IsLeader())
+
+		mock.ExpectPing().WillReturnError(errors.New("connection reset"))
+		mock.ExpectClose()
+		elector.campaign(ctx)
+
+		assert.False(t, elector.IsLeader())
+		assert.Nil(t, elector.conn)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Query error closes connection", func(t *testing.T) {
+		elector, mock := newTestElector(t, nil)
+
+		mock.ExpectQuery(`SELECT pg_try_advisory_lock`).
+			WithArgs(testLockKey).
+			WillReturnError(errors.New("database error"))
+		mock.ExpectClose()
+		elector.campaign(ctx)
+
+		assert.False(t, elector.IsLeader())
+		assert.Nil(t, elector.conn)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Connect error", func(t *testing.T) {
+		connect := func(context.Context) (LockConn, error) { return nil, errors.New("connection refused") }
+		elector := NewLeaderElector(connect, LeaderConfig{Key: testLockKey, RenewInterval: time.Second}, zap.NewNop())
+
+		elector.campaign(ctx)
+
+		assert.False(t, elector.IsLeader())
+	})
+}
+
+func TestLeaderElector_RunReleasesOnStop(t *testing.T) {
+	elected := make(chan struct{})
+	elector, mock := newTestElector(t, func() { close(elected) })
+
+	expectTryLock(mock, true)
+	mock.ExpectPing().Maybe()
+	mock.ExpectClose()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		elector.Run(ctx)
+	}()
+
+	select {
+	case <-elected:
+	case <-time.After(time.Second):
+		t.Fatal("leader was not elected")
+	}
+
+	cancel()
+	<-done
+
+	assert.False(t, elector.IsLeader())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,163 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+// LegacyImportRepository записывает заказы и транзакции, перенесенные из другой системы лояльности.
+// Записи сохраняются с исходными временными метками, повторный импорт тех же данных ничего не меняет.
+type LegacyImportRepository struct {
+	db DBTX
+}
+
+// NewLegacyImportRepository создает новый LegacyImportRepository
+func NewLegacyImportRepository(db DBTX) *LegacyImportRepository {
+	return &LegacyImportRepository{db: db}
+}
+
+// GetUserIDsByLogins возвращает ID найденных пользователей по логину
+func (r *LegacyImportRepository) GetUserIDsByLogins(ctx context.Context, logins []string) (map[string]int64, error) {
+	rows, err := r.db.Query(ctx, `SELECT id, login FROM users WHERE login = ANY($1)`, logins)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to get users by logins: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make(map[string]int64, len(logins))
+	for rows.Next() {
+		var (
+			id    int64
+			login string
+		)
+		if err := rows.Scan(&id, &login); err != nil {
+			return nil, fmt.Errorf("repository: failed to scan user: %w", err)
+		}
+		ids[login] = id
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repository: error iterating users: %w", err)
+	}
+
+	return ids, nil
+}
+
+// ImportOrders вставляет пачку заказов, см. importBatch. Заказы с уже существующим номером пропускаются.
+func (r *LegacyImportRepository) ImportOrders(ctx context.Context, orders []*domain.Order) (*domain.BatchImport, error) {
+	return importBatch(ctx, r.db, orders, insertOrders)
+}
+
+// ImportTransactions вставляет пачку транзакций, см. importBatch. Транзакция пропускается,
+// если для ее заказа уже есть транзакция того же типа. Списания передаются с отрицательной суммой.
+func (r *LegacyImportRepository) ImportTransactions(ctx context.Context, transactions []*domain.Transaction) (*domain.BatchImport, error) {
+	return importBatch(ctx, r.db, transactions, insertTransactions)
+}
+
+// importBatch вставляет пачку записей в одной транзакции. Сначала пачка пишется одним
+// запросом; если БД отклонила его из-за данных, записи вставляются по одной, каждая
+// в своей точке сохранения: отклоненная запись откатывается, не прерывая остальные.
+// Сбой БД прерывает всю пачку
+func importBatch[T any](ctx context.Context, db DBTX, records []T, insert func(ctx context.Context, q Querier, records []T) (int64, error)) (*domain.BatchImport, error) {
+	result := &domain.BatchImport{}
+	err := RunInTx(ctx, db, func(tx pgx.Tx) error {
+		err := RunInTx(ctx, tx, func(sp pgx.Tx) error {
+			imported, err := insert(ctx, sp, records)
+			result.Imported = int(imported)
+			return err
+		})
+		if err == nil || !isDataError(err) {
+			return err
+		}
+
+		result.Imported = 0
+		result.Rejected = make(map[int]error)
+		for i := range records {
+			err := RunInTx(ctx, tx, func(sp pgx.Tx) error {
+				imported, err := insert(ctx, sp, records[i:i+1])
+				if err == nil {
+					result.Imported += int(imported)
+				}
+				return err
+			})
+			if isDataError(err) {
+				result.Rejected[i] = err
+				continue
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// insertOrders вставляет заказы одним запросом и возвращает количество вставленных
+func insertOrders(ctx context.Context, q Querier, orders []*domain.Order) (int64, error) {
+	userIDs := make([]int64, len(orders))
+	numbers := make([]string, len(orders))
+	statuses := make([]string, len(orders))
+	accruals := make([]*float64, len(orders))
+	uploadedAt := make([]time.Time, len(orders))
+	for i, o := range orders {
+		userIDs[i] = o.UserID
+		numbers[i] = o.Number
+		statuses[i] = string(o.Status)
+		accruals[i] = o.Accrual
+		uploadedAt[i] = o.UploadedAt.UTC()
+	}
+
+	tag, err := q.Exec(ctx,
+		`INSERT INTO orders (user_id, number, status, accrual, uploaded_at)
+		 SELECT * FROM unnest($1::integer[], $2::varchar[], $3::varchar[], $4::numeric[], $5::timestamp[])
+		 ON CONFLICT (number) DO NOTHING`,
+		userIDs, numbers, statuses, accruals, uploadedAt,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("repository: failed to import %d orders: %w", len(orders), err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// insertTransactions вставляет транзакции одним запросом и возвращает количество вставленных
+func insertTransactions(ctx context.Context, q Querier, transactions []*domain.Transaction) (int64, error) {
+	userIDs := make([]int64, len(transactions))
+	orderNumbers := make([]string, len(transactions))
+	amounts := make([]float64, len(transactions))
+	types := make([]string, len(transactions))
+	processedAt := make([]time.Time, len(transactions))
+	for i, tx := range transactions {
+		userIDs[i] = tx.UserID
+		orderNumbers[i] = tx.OrderNumber
+		amounts[i] = tx.Amount
+		types[i] = string(tx.Type)
+		processedAt[i] = tx.ProcessedAt.UTC()
+	}
+
+	tag, err := q.Exec(ctx,
+		`INSERT INTO transactions (user_id, order_number, amount, type, processed_at)
+		 SELECT t.user_id, t.order_number, t.amount, t.type, t.processed_at
+		 FROM unnest($1::integer[], $2::varchar[], $3::numeric[], $4::varchar[], $5::timestamp[])
+		 	AS t(user_id, order_number, amount, type, processed_at)
+		 WHERE NOT EXISTS (
+		 	SELECT 1 FROM transactions x WHERE x.order_number = t.order_number AND x.type = t.type
+		 )
+		 ON CONFLICT DO NOTHING`,
+		userIDs, orderNumbers, amounts, types, processedAt,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("repository: failed to import %d transactions: %w", len(transactions), err)
+	}
+
+	return tag.RowsAffected(), nil
+}
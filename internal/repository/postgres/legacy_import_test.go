@@ -0,0 +1,183 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLegacyImportRepository_GetUserIDsByLogins(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewLegacyImportRepository(mock)
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT id, login FROM users WHERE login = ANY\(\$1\)`).
+			WithArgs([]string{"alice", "bob"}).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "login"}).AddRow(int64(1), "alice"))
+
+		ids, err := repo.GetUserIDsByLogins(ctx, []string{"alice", "bob"})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int64{"alice": 1}, ids)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Database error", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT id, login FROM users`).
+			WithArgs([]string{"alice"}).
+			WillReturnError(errors.New("database error"))
+
+		ids, err := repo.GetUserIDsByLogins(ctx, []string{"alice"})
+		assert.Error(t, err)
+		assert.Nil(t, ids)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestLegacyImportRepository_ImportOrders(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewLegacyImportRepository(mock)
+	ctx := context.Background()
+
+	accrual := 500.0
+	uploadedAt := time.Date(2020, 12, 10, 15, 15, 45, 0, time.FixedZone("MSK", 3*60*60))
+	orders := []*domain.Order{
+		{UserID: 1, Number: "9278923470", Status: domain.OrderStatusProcessed, Accrual: &accrual, UploadedAt: uploadedAt},
+		{UserID: 2, Number: "346436439", Status: domain.OrderStatusNew, UploadedAt: uploadedAt},
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectBegin()
+		// Исходное время сохраняется в UTC
+		mock.ExpectExec(`INSERT INTO orders .* FROM unnest\(.*\) ON CONFLICT \(number\) DO NOTHING`).
+			WithArgs(
+				[]int64{1, 2},
+				[]string{"9278923470", "346436439"},
+				[]string{"PROCESSED", "NEW"},
+				[]*float64{&accrual, nil},
+				[]time.Time{uploadedAt.UTC(), uploadedAt.UTC()},
+			).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectCommit()
+		mock.ExpectCommit()
+
+		result, err := repo.ImportOrders(ctx, orders)
+		require.NoError(t, err)
+		assert.Equal(t, &domain.BatchImport{Imported: 1}, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Rejected order is rolled back to savepoint", func(t *testing.T) {
+		tooLong := &pgconn.PgError{Code: "22001", Message: "value too long for type character varying(64)"}
+		mock.ExpectBegin()
+		mock.ExpectBegin()
+		mock.ExpectExec(`INSERT INTO orders`).
+			WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
+			WillReturnError(tooLong)
+		mock.ExpectRollback()
+		// Заказы по одному, каждый в своей точке сохранения
+		mock.ExpectBegin()
+		mock.ExpectExec(`INSERT INTO orders`).
+			WithArgs([]int64{1}, []string{"9278923470"}, pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectCommit()
+		mock.ExpectBegin()
+		mock.ExpectExec(`INSERT INTO orders`).
+			WithArgs([]int64{2}, []string{"346436439"}, pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
+			WillReturnError(tooLong)
+		mock.ExpectRollback()
+		mock.ExpectCommit()
+
+		result, err := repo.ImportOrders(ctx, orders)
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Imported)
+		require.Len(t, result.Rejected, 1)
+		assert.ErrorIs(t, result.Rejected[1], tooLong)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Database error", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectBegin()
+		mock.ExpectExec(`INSERT INTO orders`).
+			WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
+			WillReturnError(errors.New("database error"))
+		mock.ExpectRollback()
+		mock.ExpectRollback()
+
+		result, err := repo.ImportOrders(ctx, orders)
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestLegacyImportRepository_ImportTransactions(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewLegacyImportRepository(mock)
+	ctx := context.Background()
+
+	processedAt := time.Date(2020, 12, 10, 15, 15, 45, 0, time.UTC)
+	transactions := []*domain.Transaction{
+		{UserID: 1, OrderNumber: "9278923470", Amount: 500, Type: domain.TransactionTypeAccrual, ProcessedAt: processedAt},
+		{UserID: 1, OrderNumber: "2377225624", Amount: -100, Type: domain.TransactionTypeWithdrawal, ProcessedAt: processedAt},
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectBegin()
+		mock.ExpectExec(`INSERT INTO transactions .* FROM unnest\(.*\) .* WHERE NOT EXISTS`).
+			WithArgs(
+				[]int64{1, 1},
+				[]string{"9278923470", "2377225624"},
+				[]float64{500, -100},
+				[]string{"accrual", "withdrawal"},
+				[]time.Time{processedAt, processedAt},
+			).
+			WillReturnResult(pgxmock.NewResult("INSERT", 2))
+		mock.ExpectCommit()
+		mock.ExpectCommit()
+
+		result, err := repo.ImportTransactions(ctx, transactions)
+		require.NoError(t, err)
+		assert.Equal(t, &domain.BatchImport{Imported: 2}, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Failure while importing one by one aborts batch", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectBegin()
+		mock.ExpectExec(`INSERT INTO transactions`).
+			WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
+			WillReturnError(&pgconn.PgError{Code: "23514"})
+		mock.ExpectRollback()
+		mock.ExpectBegin()
+		mock.ExpectExec(`INSERT INTO transactions`).
+			WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
+			WillReturnError(errors.New("connection reset"))
+		mock.ExpectRollback()
+		mock.ExpectRollback()
+
+		result, err := repo.ImportTransactions(ctx, transactions)
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
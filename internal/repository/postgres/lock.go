@@ -0,0 +1,46 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// LockRepository хранит блокировки internal/lock в таблице distributed_locks.
+// В отличие от pg_advisory_lock, блокировка не занимает соединение из пула на время удержания.
+type LockRepository struct {
+	db DBTX
+}
+
+// NewLockRepository создает новый LockRepository
+func NewLockRepository(db DBTX) *LockRepository {
+	return &LockRepository{db: db}
+}
+
+// TryAcquire занимает свободный или просроченный ключ. Повтор с тем же token
+// продлевает блокировку, поэтому повтор запроса после обрыва соединения безопасен.
+func (r *LockRepository) TryAcquire(ctx context.Context, key, token string, ttl time.Duration) (bool, error) {
+	tag, err := r.db.Exec(ctx,
+		`INSERT INTO distributed_locks (key, token, expires_at) 
+		 VALUES ($1, $2, NOW() + make_interval(secs => $3)) 
+		 ON CONFLICT (key) DO UPDATE SET token = EXCLUDED.token, expires_at = EXCLUDED.expires_at 
+		 WHERE distributed_locks.expires_at <= NOW() OR distributed_locks.token = EXCLUDED.token`,
+		key, token, ttl.Seconds(),
+	)
+	if err != nil {
+		return false, fmt.Errorf("repository: failed to acquire lock %q: %w", key, err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// Release освобождает ключ, если он занят под token
+func (r *LockRepository) Release(ctx context.Context, key, token string) error {
+	_, err := r.db.Exec(ctx,
+		`DELETE FROM distributed_locks WHERE key = $1 AND token = $2`,
+		key, token,
+	)
+	if err != nil {
+		return fmt.Errorf("repository: failed to release lock %q: %w", key, err)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLockRepository_TryAcquire(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewLockRepository(mock)
+	ctx := context.Background()
+
+	t.Run("Acquired", func(t *testing.T) {
+		mock.ExpectExec(`INSERT INTO distributed_locks .* ON CONFLICT \(key\) DO UPDATE`).
+			WithArgs("user:1", "token", 30.0).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+		acquired, err := repo.TryAcquire(ctx, "user:1", "token", 30*time.Second)
+		require.NoError(t, err)
+		assert.True(t, acquired)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Held by another owner", func(t *testing.T) {
+		mock.ExpectExec(`INSERT INTO distributed_locks`).
+			WithArgs("user:1", "token", 30.0).
+			WillReturnResult(pgxmock.NewResult("INSERT", 0))
+
+		acquired, err := repo.TryAcquire(ctx, "user:1", "token", 30*time.Second)
+		require.NoError(t, err)
+		assert.False(t, acquired)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Database error", func(t *testing.T) {
+		mock.ExpectExec(`INSERT INTO distributed_locks`).
+			WithArgs("user:1", "token", 30.0).
+			WillReturnError(errors.New("database error"))
+
+		_, err := repo.TryAcquire(ctx, "user:1", "token", 30*time.Second)
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestLockRepository_Release(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewLockRepository(mock)
+
+	mock.ExpectExec(`DELETE FROM distributed_locks WHERE key = \$1 AND token = \$2`).
+		WithArgs("user:1", "token").
+		WillReturnResult(pgxmock.NewResult("DELETE", 1))
+
+	require.NoError(t, repo.Release(context.Background(), "user:1", "token"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
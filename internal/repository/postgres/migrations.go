@@ -4,9 +4,11 @@ import (
 	"context"
 	"embed"
 	"fmt"
-	"path/filepath"
+	"io/fs"
+	"slices"
 	"sort"
 	"strings"
+	"sync"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/zap"
@@ -15,38 +17,114 @@ import (
 //go:embed migrations/*.sql
 var migrationsFS embed.FS
 
-// RunMigrations выполняет миграции базы данных
-// Автоматически находит все *.up.sql файлы и выполняет их в алфавитном порядке
-func RunMigrations(ctx context.Context, pool *pgxpool.Pool, logger *zap.Logger) error {
-	entries, err := migrationsFS.ReadDir("migrations")
+// coreMigrations - имя источника миграций основной схемы
+const coreMigrations = "core"
+
+// MigrationSource - миграции подсистемы: файлы *.up.sql в корне FS
+type MigrationSource struct {
+	Name string // Уникальное имя подсистемы, выводится в лог
+	FS   fs.FS
+}
+
+// migration - файл миграции с источником
+type migration struct {
+	source string
+	name   string
+	fsys   fs.FS
+}
+
+var (
+	sourcesMu sync.Mutex
+	sources   []MigrationSource
+)
+
+// RegisterMigrations добавляет миграции подсистемы. Вызывается из init пакета подсистемы,
+// поэтому повторное или пустое имя - ошибка программы
+func RegisterMigrations(name string, fsys fs.FS) {
+	sourcesMu.Lock()
+	defer sourcesMu.Unlock()
+
+	if name == "" || name == coreMigrations || fsys == nil {
+		panic(fmt.Sprintf("postgres: invalid migration source %q", name))
+	}
+	for _, source := range sources {
+		if source.Name == name {
+			panic(fmt.Sprintf("postgres: migration source %q registered twice", name))
+		}
+	}
+	sources = append(sources, MigrationSource{Name: name, FS: fsys})
+}
+
+// registeredSources возвращает основную схему и зарегистрированные подсистемы
+func registeredSources() []MigrationSource {
+	core, err := fs.Sub(migrationsFS, "migrations")
 	if err != nil {
-		return fmt.Errorf("failed to read migrations directory: %w", err)
+		panic(err) // Каталог встроен в бинарник
 	}
 
-	// Собираем только up миграции и сортируем
-	var upMigrations []string
-	for _, entry := range entries {
-		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".up.sql") {
-			upMigrations = append(upMigrations, entry.Name())
-		}
+	sourcesMu.Lock()
+	defer sourcesMu.Unlock()
+	return append([]MigrationSource{{Name: coreMigrations, FS: core}}, sources...)
+}
+
+// RunMigrations выполняет миграции базы данных: сначала основной схемы, затем подсистем
+// в порядке их имен, внутри источника - *.up.sql файлы в алфавитном порядке.
+// Порядок не зависит от порядка регистрации, а таблицы основной схемы создаются
+// раньше ссылающихся на них таблиц подсистем
+func RunMigrations(ctx context.Context, pool *pgxpool.Pool, logger *zap.Logger) error {
+	migrations, err := collectMigrations(registeredSources())
+	if err != nil {
+		return err
 	}
-	sort.Strings(upMigrations)
 
 	// Выполняем миграции по порядку
-	for _, name := range upMigrations {
-		migrationPath := filepath.Join("migrations", name)
-		content, err := migrationsFS.ReadFile(migrationPath)
+	for _, m := range migrations {
+		content, err := fs.ReadFile(m.fsys, m.name)
 		if err != nil {
-			return fmt.Errorf("failed to read migration %s: %w", name, err)
+			return fmt.Errorf("failed to read migration %s/%s: %w", m.source, m.name, err)
 		}
 
-		logger.Info("running migration", zap.String("name", name))
+		logger.Info("running migration", zap.String("source", m.source), zap.String("name", m.name))
 		_, err = pool.Exec(ctx, string(content))
 		if err != nil {
-			return fmt.Errorf("failed to run migration %s: %w", name, err)
+			return fmt.Errorf("failed to run migration %s/%s: %w", m.source, m.name, err)
 		}
-		logger.Info("migration completed", zap.String("name", name))
+		logger.Info("migration completed", zap.String("source", m.source), zap.String("name", m.name))
 	}
 
 	return nil
 }
+
+// collectMigrations собирает up миграции источников в порядке выполнения.
+// Первый источник - основная схема, остальные сортируются по имени
+func collectMigrations(all []MigrationSource) ([]migration, error) {
+	if len(all) == 0 {
+		return nil, nil
+	}
+	ordered := slices.Clone(all)
+	slices.SortStableFunc(ordered[1:], func(a, b MigrationSource) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	var migrations []migration
+	for _, source := range ordered {
+		entries, err := fs.ReadDir(source.FS, ".")
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migrations of %s: %w", source.Name, err)
+		}
+
+		// Собираем только up миграции и сортируем
+		var names []string
+		for _, entry := range entries {
+			if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".up.sql") {
+				names = append(names, entry.Name())
+			}
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			migrations = append(migrations, migration{source: source.Name, name: name, fsys: source.FS})
+		}
+	}
+	return migrations, nil
+}
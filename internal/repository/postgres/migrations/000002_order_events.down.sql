@@ -0,0 +1,3 @@
+-- Откат таблицы событий заказов
+DROP INDEX IF EXISTS idx_order_events_pending;
+DROP TABLE IF EXISTS order_events;
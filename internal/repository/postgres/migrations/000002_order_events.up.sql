@@ -0,0 +1,16 @@
+-- Таблица событий заказов (outbox): пишется в одной транзакции с начислением
+-- и читается диспетчером, который рассылает события подписчикам
+CREATE TABLE IF NOT EXISTS order_events (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    type VARCHAR(50) NOT NULL,
+    order_number VARCHAR(255) NOT NULL,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL,
+    accrual DECIMAL(10,2),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    dispatched_at TIMESTAMP
+);
+
+-- Индекс для выборки неотправленных событий
+CREATE INDEX IF NOT EXISTS idx_order_events_pending
+    ON order_events(id) WHERE dispatched_at IS NULL;
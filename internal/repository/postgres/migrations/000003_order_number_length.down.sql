@@ -0,0 +1,2 @@
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_order_number_length;
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_number_length;
@@ -0,0 +1,14 @@
+-- Ограничение длины номеров заказов (см. domain.MaxOrderNumberLength).
+-- NOT VALID: уже сохраненные строки не проверяются, ограничение действует для новых записей.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'orders_number_length') THEN
+        ALTER TABLE orders
+            ADD CONSTRAINT orders_number_length CHECK (char_length(number) <= 64) NOT VALID;
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'transactions_order_number_length') THEN
+        ALTER TABLE transactions
+            ADD CONSTRAINT transactions_order_number_length CHECK (char_length(order_number) <= 64) NOT VALID;
+    END IF;
+END $$;
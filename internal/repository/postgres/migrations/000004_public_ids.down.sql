@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_transactions_public_id;
+ALTER TABLE transactions DROP COLUMN IF EXISTS public_id;
+
+DROP INDEX IF EXISTS idx_orders_public_id;
+ALTER TABLE orders DROP COLUMN IF EXISTS public_id;
@@ -0,0 +1,8 @@
+-- Публичные идентификаторы заказов и транзакций для ссылок в API и поддержке.
+-- Существующие и импортированные строки получают случайный UUID по умолчанию,
+-- записи, создаваемые приложением, получают UUIDv7.
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS public_id UUID NOT NULL DEFAULT gen_random_uuid();
+CREATE UNIQUE INDEX IF NOT EXISTS idx_orders_public_id ON orders(public_id);
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS public_id UUID NOT NULL DEFAULT gen_random_uuid();
+CREATE UNIQUE INDEX IF NOT EXISTS idx_transactions_public_id ON transactions(public_id);
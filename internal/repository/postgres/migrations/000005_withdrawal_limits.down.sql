@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_transactions_user_type_processed_at;
+DROP TABLE IF EXISTS withdrawal_limits;
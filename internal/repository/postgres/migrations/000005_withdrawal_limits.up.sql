@@ -0,0 +1,13 @@
+-- Ограничения списаний, заданные администратором для отдельных пользователей.
+-- Пользователи без записи получают ограничения по умолчанию из конфигурации, 0 - без ограничения.
+CREATE TABLE IF NOT EXISTS withdrawal_limits (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    per_withdrawal DECIMAL(10,2) NOT NULL DEFAULT 0 CHECK (per_withdrawal >= 0),
+    daily DECIMAL(10,2) NOT NULL DEFAULT 0 CHECK (daily >= 0),
+    monthly DECIMAL(10,2) NOT NULL DEFAULT 0 CHECK (monthly >= 0),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Суммы списаний за окно выбираются по пользователю и времени операции
+CREATE INDEX IF NOT EXISTS idx_transactions_user_type_processed_at
+    ON transactions(user_id, type, processed_at);
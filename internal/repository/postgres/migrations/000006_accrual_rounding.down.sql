@@ -0,0 +1,2 @@
+ALTER TABLE transactions DROP COLUMN IF EXISTS rounding_policy;
+ALTER TABLE transactions DROP COLUMN IF EXISTS accrual_raw;
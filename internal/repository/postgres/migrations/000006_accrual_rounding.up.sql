@@ -0,0 +1,5 @@
+-- Аудит округления начислений: исходная сумма из системы начислений
+-- и политика, по которой она была округлена до записи в amount.
+-- У списаний и начислений, записанных до миграции, колонки пустые.
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS accrual_raw NUMERIC;
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS rounding_policy VARCHAR(32);
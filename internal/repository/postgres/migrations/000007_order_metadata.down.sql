@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_orders_metadata;
+ALTER TABLE order_events DROP COLUMN IF EXISTS metadata;
+ALTER TABLE orders DROP COLUMN IF EXISTS metadata;
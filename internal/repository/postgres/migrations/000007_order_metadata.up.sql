@@ -0,0 +1,7 @@
+-- Необязательные метаданные заказа (канал, магазин, чек), переданные клиентом.
+-- Копируются в событие заказа, чтобы подписчики получали их без чтения orders.
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS metadata JSONB;
+ALTER TABLE order_events ADD COLUMN IF NOT EXISTS metadata JSONB;
+
+-- Индекс для фильтрации заказов по метаданным (metadata @> '{"store_id": "42"}')
+CREATE INDEX IF NOT EXISTS idx_orders_metadata ON orders USING GIN (metadata);
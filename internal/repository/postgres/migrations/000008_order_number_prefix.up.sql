@@ -0,0 +1,3 @@
+-- Поиск заказов по началу номера (number LIKE '1234%') в административном API.
+-- text_pattern_ops позволяет использовать индекс для LIKE при любой локали БД.
+CREATE INDEX IF NOT EXISTS idx_orders_number_prefix ON orders (number text_pattern_ops);
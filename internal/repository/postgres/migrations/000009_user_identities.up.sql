@@ -0,0 +1,11 @@
+-- Учетные записи внешнего провайдера удостоверений (SSO/OIDC), привязанные к локальным пользователям.
+-- Локальный пользователь создается при первом запросе с токеном провайдера.
+CREATE TABLE IF NOT EXISTS user_identities (
+    issuer VARCHAR(255) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (issuer, subject)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_identities_user_id ON user_identities(user_id);
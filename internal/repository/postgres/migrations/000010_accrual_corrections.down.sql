@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS accrual_corrections;
+DELETE FROM transactions WHERE type = 'adjustment';
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_type_check;
+ALTER TABLE transactions
+    ADD CONSTRAINT transactions_type_check CHECK (type IN ('accrual', 'withdrawal'));
@@ -0,0 +1,31 @@
+-- Корректировки начислений: если система начислений пересчитала уже зачисленный заказ,
+-- разница записывается отдельной транзакцией типа adjustment, исходное начисление не меняется.
+-- Ограничение типа заменяется один раз: замена блокирует таблицу и проверяет все строки.
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint
+        WHERE conname = 'transactions_type_check' AND pg_get_constraintdef(oid) LIKE '%adjustment%'
+    ) THEN
+        ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_type_check;
+        ALTER TABLE transactions
+            ADD CONSTRAINT transactions_type_check CHECK (type IN ('accrual', 'withdrawal', 'adjustment'));
+    END IF;
+END $$;
+
+-- Журнал корректировок: прежняя и новая сумма, исходное значение из системы начислений
+-- и администратор, запустивший проверку (NULL - корректировка при обработке заказа)
+CREATE TABLE IF NOT EXISTS accrual_corrections (
+    id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    order_number VARCHAR(255) NOT NULL,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    transaction_id INTEGER NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    previous_amount DECIMAL(10,2) NOT NULL,
+    new_amount DECIMAL(10,2) NOT NULL,
+    accrual_raw NUMERIC,
+    rounding_policy VARCHAR(32),
+    corrected_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_accrual_corrections_order_number ON accrual_corrections(order_number);
@@ -0,0 +1,15 @@
+-- Журнал переноса заказов между пользователями. Начисление по заказу переносится
+-- парой транзакций корректировки: списание у прежнего владельца и начисление новому.
+CREATE TABLE IF NOT EXISTS order_transfers (
+    id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    order_number VARCHAR(255) NOT NULL,
+    from_user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    to_user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    amount DECIMAL(10,2) NOT NULL,
+    from_transaction_id INTEGER REFERENCES transactions(id) ON DELETE SET NULL,
+    to_transaction_id INTEGER REFERENCES transactions(id) ON DELETE SET NULL,
+    transferred_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_transfers_order_number ON order_transfers(order_number);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS user_merges;
+ALTER TABLE users DROP COLUMN IF EXISTS merged_into;
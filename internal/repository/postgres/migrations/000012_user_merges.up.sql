@@ -0,0 +1,17 @@
+-- Объединение учетных записей: заказы, транзакции и привязки внешних провайдеров
+-- переносятся на основную запись, объединенная запись отключается и ссылается на нее.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS merged_into INTEGER REFERENCES users(id) ON DELETE SET NULL;
+
+-- Журнал объединений; логины сохраняются, чтобы запись осталась понятной после удаления пользователей
+CREATE TABLE IF NOT EXISTS user_merges (
+    id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    source_user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    source_login VARCHAR(255) NOT NULL,
+    target_user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    target_login VARCHAR(255) NOT NULL,
+    orders INTEGER NOT NULL,
+    transactions INTEGER NOT NULL,
+    balance DECIMAL(10,2) NOT NULL,
+    merged_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,6 @@
+DROP TRIGGER IF EXISTS transactions_chain ON transactions;
+DROP FUNCTION IF EXISTS transactions_chain_append();
+DROP FUNCTION IF EXISTS transactions_chain_hash(BYTEA, UUID, VARCHAR, DECIMAL, VARCHAR, TIMESTAMP);
+DROP INDEX IF EXISTS idx_transactions_chain_seq;
+ALTER TABLE transactions DROP COLUMN IF EXISTS chain_hash;
+ALTER TABLE transactions DROP COLUMN IF EXISTS chain_seq;
@@ -0,0 +1,60 @@
+-- Цепочка хешей журнала транзакций для обнаружения подделки: каждая запись хранит
+-- порядковый номер в цепочке и SHA-256 от хеша предыдущей записи и своих данных.
+-- Цепочку ведет триггер, поэтому в нее попадают записи из любого места вставки.
+-- Миграция 000031 заменяет общую цепочку цепочками пользователей.
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS chain_seq BIGINT;
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS chain_hash BYTEA;
+
+-- Хеш записи; формат полей не зависит от настроек сессии (DateStyle, extra_float_digits)
+CREATE OR REPLACE FUNCTION transactions_chain_hash(
+    p_prev_hash BYTEA, p_public_id UUID, p_order_number VARCHAR, p_amount DECIMAL, p_type VARCHAR, p_processed_at TIMESTAMP
+) RETURNS BYTEA AS $$
+    SELECT sha256(COALESCE(p_prev_hash, ''::BYTEA) || convert_to(concat_ws('|',
+        p_public_id::TEXT,
+        p_order_number,
+        p_amount::TEXT,
+        p_type,
+        to_char(p_processed_at, 'YYYY-MM-DD"T"HH24:MI:SS.US')
+    ), 'UTF8'))
+$$ LANGUAGE SQL STABLE;
+
+-- Вставки сериализуются advisory lock до конца транзакции, чтобы у цепочки был один хвост
+CREATE OR REPLACE FUNCTION transactions_chain_append() RETURNS TRIGGER AS $$
+DECLARE
+    last_seq BIGINT;
+    last_hash BYTEA;
+BEGIN
+    PERFORM pg_advisory_xact_lock(7810759523990397800); -- "ledgerch"
+    SELECT chain_seq, chain_hash INTO last_seq, last_hash
+    FROM transactions WHERE chain_seq IS NOT NULL
+    ORDER BY chain_seq DESC LIMIT 1;
+
+    NEW.chain_seq := COALESCE(last_seq, 0) + 1;
+    NEW.chain_hash := transactions_chain_hash(last_hash, NEW.public_id, NEW.order_number, NEW.amount, NEW.type, NEW.processed_at);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Записи, созданные до появления цепочки, включаются в нее один раз при установке триггера.
+-- Повторно миграция их не трогает: запись без номера в цепочке после этого - признак обхода триггера.
+DO $$
+DECLARE
+    rec RECORD;
+    seq BIGINT := 0;
+    prev BYTEA;
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_trigger WHERE tgname = 'transactions_chain') THEN
+        LOCK TABLE transactions IN EXCLUSIVE MODE;
+        FOR rec IN SELECT id, public_id, order_number, amount, type, processed_at FROM transactions ORDER BY id LOOP
+            seq := seq + 1;
+            prev := transactions_chain_hash(prev, rec.public_id, rec.order_number, rec.amount, rec.type, rec.processed_at);
+            UPDATE transactions SET chain_seq = seq, chain_hash = prev WHERE id = rec.id;
+        END LOOP;
+        -- Индекс создается вместе с триггером: миграции выполняются при каждом запуске, а 000031 его удаляет
+        CREATE UNIQUE INDEX IF NOT EXISTS idx_transactions_chain_seq ON transactions(chain_seq);
+
+        CREATE TRIGGER transactions_chain
+            BEFORE INSERT ON transactions
+            FOR EACH ROW EXECUTE FUNCTION transactions_chain_append();
+    END IF;
+END $$;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_users_pii_key_id;
+ALTER TABLE users DROP COLUMN IF EXISTS pii_key_id;
+ALTER TABLE users DROP COLUMN IF EXISTS phone_encrypted;
+ALTER TABLE users DROP COLUMN IF EXISTS email_encrypted;
@@ -0,0 +1,8 @@
+-- Контактные данные пользователя хранятся только в зашифрованном виде (см. internal/utils/envelope).
+-- pii_key_id - мастер-ключ, которым зашифрованы ключи данных записи; по нему находятся
+-- записи для перешифрования после смены ключа. NULL - контактов нет.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_encrypted TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS phone_encrypted TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS pii_key_id VARCHAR(32);
+
+CREATE INDEX IF NOT EXISTS idx_users_pii_key_id ON users(pii_key_id) WHERE pii_key_id IS NOT NULL;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_transactions_settlement_id;
+DROP INDEX IF EXISTS idx_transactions_unsettled;
+ALTER TABLE transactions DROP COLUMN IF EXISTS settlement_id;
+ALTER TABLE transactions DROP COLUMN IF EXISTS partner;
+DROP TABLE IF EXISTS settlements;
@@ -0,0 +1,22 @@
+-- Взаиморасчеты с партнерами по списаниям баллов. Партнер (магазин, где потрачены баллы)
+-- указывается клиентом при списании. Списание, попавшее в выгрузку, помечается ее
+-- идентификатором и в следующие выгрузки не входит. Колонки не участвуют в цепочке хешей журнала.
+CREATE TABLE IF NOT EXISTS settlements (
+    id UUID PRIMARY KEY,
+    cutoff TIMESTAMP NOT NULL,
+    withdrawals INTEGER NOT NULL DEFAULT 0,
+    total DECIMAL(12,2) NOT NULL DEFAULT 0,
+    created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_settlements_created_at ON settlements(created_at DESC);
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS partner VARCHAR(128);
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS settlement_id UUID REFERENCES settlements(id);
+
+-- Выгрузка выбирает еще не выгруженные списания
+CREATE INDEX IF NOT EXISTS idx_transactions_unsettled
+    ON transactions(processed_at) WHERE type = 'withdrawal' AND settlement_id IS NULL;
+CREATE INDEX IF NOT EXISTS idx_transactions_settlement_id
+    ON transactions(settlement_id) WHERE settlement_id IS NOT NULL;
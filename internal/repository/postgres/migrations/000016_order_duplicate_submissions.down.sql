@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_order_duplicate_submissions_user_id;
+DROP TABLE IF EXISTS order_duplicate_submissions;
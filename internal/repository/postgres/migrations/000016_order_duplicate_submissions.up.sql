@@ -0,0 +1,13 @@
+-- Повторные загрузки заказов, уже загруженных тем же пользователем, по суткам,
+-- пользователям и каналу продажи из метаданных повторной загрузки ('' - канал не указан).
+-- Нужны, чтобы отличить ошибочные повторы клиента от разовых повторов пользователей.
+CREATE TABLE IF NOT EXISTS order_duplicate_submissions (
+    day DATE NOT NULL,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    channel VARCHAR(128) NOT NULL DEFAULT '',
+    submissions INTEGER NOT NULL,
+    last_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (day, user_id, channel)
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_duplicate_submissions_user_id ON order_duplicate_submissions(user_id);
@@ -0,0 +1,2 @@
+-- Индекс для подсчета времени обработки заказов за последний период
+CREATE INDEX IF NOT EXISTS idx_order_events_created_at ON order_events(created_at);
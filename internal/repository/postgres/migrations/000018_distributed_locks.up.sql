@@ -0,0 +1,7 @@
+-- Блокировки по ключу, общие для всех реплик (internal/lock), когда Redis не используется.
+-- Запись с истекшим сроком считается свободной и занимается заново
+CREATE TABLE IF NOT EXISTS distributed_locks (
+    key VARCHAR(255) PRIMARY KEY,
+    token VARCHAR(64) NOT NULL,
+    expires_at TIMESTAMP NOT NULL
+);
@@ -0,0 +1,4 @@
+CREATE INDEX IF NOT EXISTS idx_transactions_user_id ON transactions(user_id);
+CREATE INDEX IF NOT EXISTS idx_orders_status ON orders(status);
+DROP INDEX IF EXISTS idx_transactions_user_id_amount;
+DROP INDEX IF EXISTS idx_orders_status_uploaded_at;
@@ -0,0 +1,9 @@
+-- Составные индексы для частых запросов.
+-- Опрос необработанных заказов и истечение старых выбирают заказы по статусу в порядке загрузки
+CREATE INDEX IF NOT EXISTS idx_orders_status_uploaded_at ON orders(status, uploaded_at);
+-- Баланс суммирует операции пользователя только по индексу, не читая таблицу
+CREATE INDEX IF NOT EXISTS idx_transactions_user_id_amount ON transactions(user_id) INCLUDE (type, amount);
+
+-- Покрываются новыми индексами по первому столбцу
+DROP INDEX IF EXISTS idx_orders_status;
+DROP INDEX IF EXISTS idx_transactions_user_id;
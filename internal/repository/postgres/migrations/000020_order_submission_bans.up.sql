@@ -0,0 +1,10 @@
+-- Временные запреты загрузки заказов пользователям, перебирающим номера чужих заказов.
+-- Запись с истекшим сроком не действует и перезаписывается следующим запретом
+CREATE TABLE IF NOT EXISTS order_submission_bans (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    reason VARCHAR(32) NOT NULL,
+    banned_until TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_submission_bans_banned_until ON order_submission_bans(banned_until);
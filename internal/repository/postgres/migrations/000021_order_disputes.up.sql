@@ -0,0 +1,19 @@
+-- Споры о принадлежности заказа: пользователь, получивший 409 при загрузке, просит
+-- передать ему заказ. Администратор переносит заказ (запись в order_transfers) или отклоняет спор
+CREATE TABLE IF NOT EXISTS order_disputes (
+    id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    order_number VARCHAR(255) NOT NULL,
+    claimant_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    owner_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    comment TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'open',
+    resolution_comment TEXT NOT NULL DEFAULT '',
+    resolved_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    resolved_at TIMESTAMP
+);
+
+-- Один открытый спор пользователя по заказу
+CREATE UNIQUE INDEX IF NOT EXISTS idx_order_disputes_open
+    ON order_disputes(order_number, claimant_id) WHERE status = 'open';
+CREATE INDEX IF NOT EXISTS idx_order_disputes_status_created_at ON order_disputes(status, created_at);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS accrual_reward_rule_sync;
+DROP TABLE IF EXISTS accrual_reward_rules;
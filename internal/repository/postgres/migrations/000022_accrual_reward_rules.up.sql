@@ -0,0 +1,17 @@
+-- Локальная копия правил вознаграждения системы начислений (/api/goods).
+-- Заполняется периодической синхронизацией, updated_at меняется только при изменении правила
+CREATE TABLE IF NOT EXISTS accrual_reward_rules (
+    match VARCHAR(255) PRIMARY KEY,
+    reward DECIMAL(10, 2) NOT NULL,
+    reward_type VARCHAR(8) NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Состояние синхронизации: отпечаток последнего набора правил позволяет
+-- не переписывать таблицу, если правила не менялись
+CREATE TABLE IF NOT EXISTS accrual_reward_rule_sync (
+    id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+    fingerprint VARCHAR(64) NOT NULL,
+    checked_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    changed_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
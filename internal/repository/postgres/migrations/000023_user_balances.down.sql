@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS user_balances_apply ON transactions;
+DROP FUNCTION IF EXISTS user_balances_apply();
+DROP TABLE IF EXISTS user_balances;
@@ -0,0 +1,39 @@
+-- Текущий баланс пользователя. Его ведет триггер журнала транзакций, поэтому в баланс
+-- попадают записи из любого места вставки, а также перенос транзакций при объединении
+-- учетных записей. Списание проверяет и блокирует строку баланса одним UPDATE
+-- вместо блокировки пользователя и суммы по всей истории.
+CREATE TABLE IF NOT EXISTS user_balances (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    balance DECIMAL(12,2) NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE OR REPLACE FUNCTION user_balances_apply() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        UPDATE user_balances SET balance = balance - OLD.amount, updated_at = NOW()
+        WHERE user_id = OLD.user_id;
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        INSERT INTO user_balances (user_id, balance) VALUES (NEW.user_id, NEW.amount)
+        ON CONFLICT (user_id) DO UPDATE
+        SET balance = user_balances.balance + EXCLUDED.balance, updated_at = NOW();
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Балансы существующих пользователей считаются один раз при установке триггера
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_trigger WHERE tgname = 'user_balances_apply') THEN
+        LOCK TABLE transactions IN EXCLUSIVE MODE;
+        INSERT INTO user_balances (user_id, balance)
+        SELECT user_id, SUM(amount) FROM transactions GROUP BY user_id
+        ON CONFLICT (user_id) DO UPDATE SET balance = EXCLUDED.balance, updated_at = NOW();
+
+        CREATE TRIGGER user_balances_apply
+            AFTER INSERT OR DELETE OR UPDATE OF user_id, amount ON transactions
+            FOR EACH ROW EXECUTE FUNCTION user_balances_apply();
+    END IF;
+END $$;
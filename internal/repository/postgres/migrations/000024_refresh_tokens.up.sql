@@ -0,0 +1,12 @@
+-- Выданные refresh-токены. Подпись токена проверяется по JWT_SECRET, а таблица хранит
+-- только его идентификатор (jti), чтобы токен можно было отозвать. При обновлении токен
+-- отзывается; повторно предъявленный отозванный токен отзывает все токены пользователя
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id VARCHAR(64) PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    revoked_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user_id ON refresh_tokens(user_id) WHERE revoked_at IS NULL;
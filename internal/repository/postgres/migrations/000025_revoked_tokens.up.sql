@@ -0,0 +1,11 @@
+-- Черный список отозванных при выходе токенов доступа. Токен хранится как SHA-256, поэтому
+-- так же отзываются и токены внешнего провайдера. Запись не нужна после истечения токена:
+-- expires_at NULL - у токена нет срока действия
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+    token_hash VARCHAR(64) PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMP,
+    revoked_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_revoked_tokens_expires_at ON revoked_tokens(expires_at);
@@ -0,0 +1,2 @@
+CREATE INDEX IF NOT EXISTS idx_orders_user_id ON orders(user_id);
+DROP INDEX IF EXISTS idx_orders_user_id_uploaded_at;
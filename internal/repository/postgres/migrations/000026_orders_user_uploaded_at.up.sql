@@ -0,0 +1,6 @@
+-- Список заказов пользователя постранично, новые первыми: страница читается по индексу
+-- без сортировки всех заказов пользователя
+CREATE INDEX IF NOT EXISTS idx_orders_user_id_uploaded_at ON orders(user_id, uploaded_at DESC, id DESC);
+
+-- Покрывается новым индексом по первому столбцу
+DROP INDEX IF EXISTS idx_orders_user_id;
@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS user_balances_apply ON transactions;
+CREATE TRIGGER user_balances_apply
+    AFTER INSERT OR DELETE OR UPDATE OF user_id, amount ON transactions
+    FOR EACH ROW EXECUTE FUNCTION user_balances_apply();
+DROP FUNCTION IF EXISTS user_balances_apply_withdrawn();
+
+ALTER TABLE user_balances DROP COLUMN IF EXISTS withdrawn;
@@ -0,0 +1,53 @@
+-- Сумма списаний пользователя рядом с балансом: запрос баланса читает одну строку
+-- вместо суммы по всей истории транзакций. Отрицательная корректировка начисления
+-- уменьшает баланс, но списанием не считается.
+-- Функция триггера называется иначе, чем в 000023: миграции выполняются при каждом
+-- запуске, и повторная 000023 не подменяет функцию, которой пользуется триггер.
+CREATE OR REPLACE FUNCTION user_balances_apply_withdrawn() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        UPDATE user_balances
+        SET balance = balance - OLD.amount,
+            withdrawn = withdrawn + CASE WHEN OLD.type = 'withdrawal' THEN OLD.amount ELSE 0 END,
+            updated_at = NOW()
+        WHERE user_id = OLD.user_id;
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        INSERT INTO user_balances (user_id, balance, withdrawn)
+        VALUES (NEW.user_id, NEW.amount, CASE WHEN NEW.type = 'withdrawal' THEN -NEW.amount ELSE 0 END)
+        ON CONFLICT (user_id) DO UPDATE
+        SET balance = user_balances.balance + EXCLUDED.balance,
+            withdrawn = user_balances.withdrawn + EXCLUDED.withdrawn,
+            updated_at = NOW();
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Колонка заполняется и триггер переключается один раз. Журнал блокируется до переключения,
+-- чтобы вставки не прошли мимо пересчета
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_trigger t JOIN pg_proc p ON p.oid = t.tgfoid
+        WHERE t.tgname = 'user_balances_apply' AND p.proname = 'user_balances_apply_withdrawn'
+    ) THEN
+        LOCK TABLE transactions IN EXCLUSIVE MODE;
+        ALTER TABLE user_balances ADD COLUMN IF NOT EXISTS withdrawn DECIMAL(12,2) NOT NULL DEFAULT 0;
+
+        UPDATE user_balances b
+        SET withdrawn = COALESCE(w.total, 0)
+        FROM (
+            SELECT ub.user_id, SUM(-t.amount) AS total
+            FROM user_balances ub
+            LEFT JOIN transactions t ON t.user_id = ub.user_id AND t.type = 'withdrawal'
+            GROUP BY ub.user_id
+        ) w
+        WHERE b.user_id = w.user_id AND b.withdrawn IS DISTINCT FROM COALESCE(w.total, 0);
+
+        DROP TRIGGER IF EXISTS user_balances_apply ON transactions;
+        CREATE TRIGGER user_balances_apply
+            AFTER INSERT OR DELETE OR UPDATE OF user_id, amount ON transactions
+            FOR EACH ROW EXECUTE FUNCTION user_balances_apply_withdrawn();
+    END IF;
+END $$;
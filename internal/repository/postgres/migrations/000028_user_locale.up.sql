@@ -0,0 +1,3 @@
+-- Язык, выбранный пользователем для ошибок API и выписок. NULL - язык не выбран,
+-- тогда действует заголовок Accept-Language запроса
+ALTER TABLE users ADD COLUMN IF NOT EXISTS locale VARCHAR(16);
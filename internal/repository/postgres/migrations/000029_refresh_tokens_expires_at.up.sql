@@ -0,0 +1,2 @@
+-- Истекшие refresh-токены удаляются периодической задачей, отбор идет по сроку действия
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_expires_at ON refresh_tokens(expires_at);
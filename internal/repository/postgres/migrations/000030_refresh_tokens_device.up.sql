@@ -0,0 +1,4 @@
+-- Refresh-токен привязан к устройству, которому выдан: предъявленный с другого устройства
+-- токен считается украденным. Токены, выданные до привязки, принадлежат устройству без
+-- идентификатора
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS device VARCHAR(128) NOT NULL DEFAULT '';
@@ -0,0 +1,28 @@
+LOCK TABLE transactions IN EXCLUSIVE MODE;
+
+-- Записи цепочек пользователей снова собираются в общую цепочку в порядке вставки
+DROP INDEX IF EXISTS idx_transactions_user_chain_seq;
+
+DO $$
+DECLARE
+    rec RECORD;
+    seq BIGINT := 0;
+    prev BYTEA;
+BEGIN
+    FOR rec IN SELECT id, public_id, order_number, amount, type, processed_at
+               FROM transactions WHERE chain_seq IS NOT NULL ORDER BY id LOOP
+        seq := seq + 1;
+        prev := transactions_chain_hash(prev, rec.public_id, rec.order_number, rec.amount, rec.type, rec.processed_at);
+        UPDATE transactions SET chain_seq = seq, chain_hash = prev WHERE id = rec.id;
+    END LOOP;
+END $$;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_transactions_chain_seq ON transactions(chain_seq);
+
+DROP TRIGGER IF EXISTS transactions_chain ON transactions;
+CREATE TRIGGER transactions_chain
+    BEFORE INSERT ON transactions
+    FOR EACH ROW EXECUTE FUNCTION transactions_chain_append();
+
+DROP FUNCTION IF EXISTS transactions_user_chain_append();
+DROP FUNCTION IF EXISTS transactions_chain_hash(BYTEA, INTEGER, UUID, VARCHAR, DECIMAL, VARCHAR, TIMESTAMP);
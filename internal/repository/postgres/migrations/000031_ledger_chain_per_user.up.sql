@@ -0,0 +1,86 @@
+-- Цепочка хешей ведется отдельно для каждого пользователя, и user_id входит в хеш записи:
+-- вставки сериализуются только в пределах пользователя, а перенос записи другому
+-- пользователю (UPDATE transactions SET user_id) обнаруживается проверкой цепочки.
+-- Баланс между пользователями переносится новыми транзакциями (см. MergeUsers).
+
+-- Хеш записи вместе с владельцем; формат полей не зависит от настроек сессии
+CREATE OR REPLACE FUNCTION transactions_chain_hash(
+    p_prev_hash BYTEA, p_user_id INTEGER, p_public_id UUID, p_order_number VARCHAR, p_amount DECIMAL, p_type VARCHAR, p_processed_at TIMESTAMP
+) RETURNS BYTEA AS $$
+    SELECT sha256(COALESCE(p_prev_hash, ''::BYTEA) || convert_to(concat_ws('|',
+        p_user_id::TEXT,
+        p_public_id::TEXT,
+        p_order_number,
+        p_amount::TEXT,
+        p_type,
+        to_char(p_processed_at, 'YYYY-MM-DD"T"HH24:MI:SS.US')
+    ), 'UTF8'))
+$$ LANGUAGE SQL STABLE;
+
+-- Хвост цепочки пользователя защищает блокировка строки его баланса, которую и так
+-- берут списание (UPDATE user_balances) и триггер баланса после вставки. Списание,
+-- уже удерживающее строку, не ждет повторно, порядок блокировок у всех вставок один,
+-- а вставки разных пользователей друг друга не ждут
+CREATE OR REPLACE FUNCTION transactions_user_chain_append() RETURNS TRIGGER AS $$
+DECLARE
+    last_seq BIGINT;
+    last_hash BYTEA;
+BEGIN
+    INSERT INTO user_balances (user_id) VALUES (NEW.user_id)
+    ON CONFLICT (user_id) DO UPDATE SET updated_at = user_balances.updated_at;
+    SELECT chain_seq, chain_hash INTO last_seq, last_hash
+    FROM transactions WHERE user_id = NEW.user_id AND chain_seq IS NOT NULL
+    ORDER BY chain_seq DESC LIMIT 1;
+
+    NEW.chain_seq := COALESCE(last_seq, 0) + 1;
+    NEW.chain_hash := transactions_chain_hash(last_hash, NEW.user_id, NEW.public_id, NEW.order_number, NEW.amount, NEW.type, NEW.processed_at);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Общая цепочка переводится один раз, пока есть ее индекс; журнал блокируется только
+-- на время перевода. Перед пересчетом цепочка проверяется старым хешем, иначе пересчет
+-- узаконил бы измененные записи.
+-- Записи вне цепочки (вставленные в обход триггера) в нее по-прежнему не входят.
+DO $$
+DECLARE
+    rec RECORD;
+    chain_user INTEGER;
+    seq BIGINT := 0;
+    prev BYTEA;
+BEGIN
+    IF EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_transactions_chain_seq') THEN
+        LOCK TABLE transactions IN EXCLUSIVE MODE;
+        -- Другая реплика могла перевести цепочку, пока эта ждала блокировку
+        IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_transactions_chain_seq') THEN
+            RETURN;
+        END IF;
+        FOR rec IN SELECT public_id, order_number, amount, type, processed_at, chain_seq, chain_hash
+                   FROM transactions WHERE chain_seq IS NOT NULL ORDER BY chain_seq LOOP
+            seq := seq + 1;
+            prev := transactions_chain_hash(prev, rec.public_id, rec.order_number, rec.amount, rec.type, rec.processed_at);
+            IF rec.chain_seq <> seq OR rec.chain_hash <> prev THEN
+                RAISE EXCEPTION 'ledger chain is broken at chain_seq %, transaction %', rec.chain_seq, rec.public_id;
+            END IF;
+        END LOOP;
+
+        DROP INDEX idx_transactions_chain_seq;
+        FOR rec IN SELECT id, user_id, public_id, order_number, amount, type, processed_at
+                   FROM transactions WHERE chain_seq IS NOT NULL ORDER BY user_id, chain_seq LOOP
+            IF chain_user IS DISTINCT FROM rec.user_id THEN
+                chain_user := rec.user_id;
+                seq := 0;
+                prev := NULL;
+            END IF;
+            seq := seq + 1;
+            prev := transactions_chain_hash(prev, rec.user_id, rec.public_id, rec.order_number, rec.amount, rec.type, rec.processed_at);
+            UPDATE transactions SET chain_seq = seq, chain_hash = prev WHERE id = rec.id;
+        END LOOP;
+        CREATE UNIQUE INDEX idx_transactions_user_chain_seq ON transactions(user_id, chain_seq);
+
+        DROP TRIGGER transactions_chain ON transactions;
+        CREATE TRIGGER transactions_chain
+            BEFORE INSERT ON transactions
+            FOR EACH ROW EXECUTE FUNCTION transactions_user_chain_append();
+    END IF;
+END $$;
@@ -0,0 +1,4 @@
+-- Списания, перенесенные при объединении учетных записей: копия у основной записи и отмена
+-- у дубликата ссылаются на public_id исходного списания. В выгрузки для взаиморасчетов
+-- они не входят: партнеру выгружается только исходное списание. Колонка не участвует в цепочке хешей.
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS merged_from UUID;
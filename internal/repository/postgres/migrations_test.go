@@ -0,0 +1,66 @@
+package postgres
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectMigrations(t *testing.T) {
+	file := &fstest.MapFile{Data: []byte("SELECT 1;")}
+	core := fstest.MapFS{
+		"000002_orders.up.sql":  file,
+		"000001_init.up.sql":    file,
+		"000001_init.down.sql":  file,
+		"README.md":             file,
+		"nested/000003.up.sql":  file,
+		"000003_indexes.up.sql": file,
+	}
+	webhooks := fstest.MapFS{"000001_webhooks.up.sql": file}
+	audit := fstest.MapFS{"000001_audit.up.sql": file, "000002_audit_index.up.sql": file}
+
+	migrations, err := collectMigrations([]MigrationSource{
+		{Name: "core", FS: core},
+		{Name: "webhooks", FS: webhooks},
+		{Name: "audit", FS: audit},
+	})
+	require.NoError(t, err)
+
+	var got []string
+	for _, m := range migrations {
+		got = append(got, m.source+"/"+m.name)
+	}
+	assert.Equal(t, []string{
+		"core/000001_init.up.sql",
+		"core/000002_orders.up.sql",
+		"core/000003_indexes.up.sql",
+		"audit/000001_audit.up.sql",
+		"audit/000002_audit_index.up.sql",
+		"webhooks/000001_webhooks.up.sql",
+	}, got)
+}
+
+func TestCollectMigrations_Embedded(t *testing.T) {
+	migrations, err := collectMigrations(registeredSources())
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+	assert.Equal(t, "core", migrations[0].source)
+	assert.Equal(t, "000001_init_schema.up.sql", migrations[0].name)
+}
+
+func TestRegisterMigrations(t *testing.T) {
+	defer func() { sources = nil }()
+
+	RegisterMigrations("promo", fstest.MapFS{})
+	assert.Panics(t, func() { RegisterMigrations("promo", fstest.MapFS{}) })
+	assert.Panics(t, func() { RegisterMigrations("core", fstest.MapFS{}) })
+	assert.Panics(t, func() { RegisterMigrations("", fstest.MapFS{}) })
+	assert.Panics(t, func() { RegisterMigrations("audit", nil) })
+
+	registered := registeredSources()
+	require.Len(t, registered, 2)
+	assert.Equal(t, "core", registered[0].Name)
+	assert.Equal(t, "promo", registered[1].Name)
+}
@@ -4,10 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
+	"strings"
+	"time"
 
 	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgconn"
 )
 
 // OrderRepository реализует репозиторий заказов.
@@ -20,38 +23,60 @@ func NewOrderRepository(db DBTX) *OrderRepository {
 	return &OrderRepository{db: db}
 }
 
-// CreateOrder создает новый заказ
-func (r *OrderRepository) CreateOrder(ctx context.Context, userID int64, number string) (*domain.Order, error) {
-	order := &domain.Order{
-		UserID: userID,
-		Number: number,
-		Status: domain.OrderStatusNew,
-	}
+// WithTx возвращает репозиторий заказов, работающий в транзакции tx
+func (r *OrderRepository) WithTx(tx pgx.Tx) *OrderRepository {
+	return &OrderRepository{db: tx}
+}
 
-	err := r.db.QueryRow(ctx,
-		`INSERT INTO orders (user_id, number, status) 
-		 VALUES ($1, $2, $3) 
-		 RETURNING id, uploaded_at`,
-		userID, number, order.Status,
-	).Scan(&order.ID, &order.UploadedAt)
-
-	if err != nil {
-		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
-			// Проверяем, кому принадлежит заказ
-			existingOrder, getErr := r.GetOrderByNumber(ctx, number)
-			if getErr != nil {
-				return nil, fmt.Errorf("repository: failed to check existing order: %w", getErr)
-			}
-			if existingOrder.UserID != userID {
-				return nil, ErrOrderOwnedByAnother
+// createOrderQuery вставляет заказ или возвращает уже существующий за один запрос.
+// Поле created отличает новую запись от найденной.
+const createOrderQuery = `
+	WITH inserted AS (
+		INSERT INTO orders (user_id, number, status, public_id, metadata)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (number) DO NOTHING
+		RETURNING id, public_id, user_id, number, status, accrual, uploaded_at, metadata
+	)
+	SELECT id, public_id, user_id, number, status, accrual, uploaded_at, metadata, TRUE AS created FROM inserted
+	UNION ALL
+	SELECT id, public_id, user_id, number, status, accrual, uploaded_at, metadata, FALSE AS created FROM orders WHERE number = $2
+	LIMIT 1`
+
+// createOrderMaxAttempts ограничивает повторы, когда конкурентная вставка
+// зафиксирована после снимка запроса и не видна ни в одной из веток
+const createOrderMaxAttempts = 3
+
+// CreateOrder создает новый заказ, metadata может быть nil.
+// Если номер уже загружен, возвращает существующий заказ с ErrOrderExists
+// или ErrOrderOwnedByAnother, если он принадлежит другому пользователю.
+func (r *OrderRepository) CreateOrder(ctx context.Context, userID int64, number string, metadata *domain.OrderMetadata) (*domain.Order, error) {
+	for attempt := 0; attempt < createOrderMaxAttempts; attempt++ {
+		order := &domain.Order{}
+		var created bool
+
+		err := r.db.QueryRow(ctx, createOrderQuery, userID, number, domain.OrderStatusNew, newPublicID(), metadata).
+			Scan(&order.ID, &order.PublicID, &order.UserID, &order.Number, &order.Status, &order.Accrual, &order.UploadedAt, &order.Metadata, &created)
+		if errors.Is(err, pgx.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			if isForeignKeyViolation(err) {
+				return nil, domain.ErrUserNotFound
 			}
-			return existingOrder, ErrOrderExists
+			return nil, fmt.Errorf("repository: failed to create order %q: %w", number, err)
+		}
+
+		switch {
+		case created:
+			return order, nil
+		case order.UserID != userID:
+			return nil, domain.ErrOrderOwnedByAnother
+		default:
+			return order, domain.ErrOrderExists
 		}
-		return nil, fmt.Errorf("repository: failed to create order %q: %w", number, err)
 	}
 
-	return order, nil
+	return nil, fmt.Errorf("repository: failed to create order %q: conflicting row not visible after %d attempts", number, createOrderMaxAttempts)
 }
 
 // GetOrderByNumber получает заказ по номеру
@@ -59,15 +84,15 @@ func (r *OrderRepository) GetOrderByNumber(ctx context.Context, number string) (
 	order := &domain.Order{}
 
 	err := r.db.QueryRow(ctx,
-		`SELECT id, user_id, number, status, accrual, uploaded_at 
+		`SELECT id, public_id, user_id, number, status, accrual, uploaded_at, metadata 
 		 FROM orders 
 		 WHERE number = $1`,
 		number,
-	).Scan(&order.ID, &order.UserID, &order.Number, &order.Status, &order.Accrual, &order.UploadedAt)
+	).Scan(&order.ID, &order.PublicID, &order.UserID, &order.Number, &order.Status, &order.Accrual, &order.UploadedAt, &order.Metadata)
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, ErrOrderNotFound
+			return nil, domain.ErrOrderNotFound
 		}
 		return nil, fmt.Errorf("repository: failed to get order by number %q: %w", number, err)
 	}
@@ -75,16 +100,61 @@ func (r *OrderRepository) GetOrderByNumber(ctx context.Context, number string) (
 	return order, nil
 }
 
-// GetOrdersByUserID получает все заказы пользователя
-func (r *OrderRepository) GetOrdersByUserID(ctx context.Context, userID int64) ([]*domain.Order, error) {
-	rows, err := r.db.Query(ctx,
-		`SELECT id, user_id, number, status, accrual, uploaded_at 
+// GetOrderByPublicID получает заказ пользователя по публичному идентификатору.
+// Чужой заказ не отличается от несуществующего, чтобы не раскрывать чужие идентификаторы.
+func (r *OrderRepository) GetOrderByPublicID(ctx context.Context, userID int64, publicID uuid.UUID) (*domain.Order, error) {
+	order := &domain.Order{}
+
+	err := r.db.QueryRow(ctx,
+		`SELECT id, public_id, user_id, number, status, accrual, uploaded_at, metadata 
 		 FROM orders 
-		 WHERE user_id = $1 
-		 ORDER BY uploaded_at DESC`,
-		userID,
-	)
+		 WHERE public_id = $1 AND user_id = $2`,
+		publicID, userID,
+	).Scan(&order.ID, &order.PublicID, &order.UserID, &order.Number, &order.Status, &order.Accrual, &order.UploadedAt, &order.Metadata)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrOrderNotFound
+		}
+		return nil, fmt.Errorf("repository: failed to get order %s: %w", publicID, err)
+	}
+
+	return order, nil
+}
+
+// GetOrdersByUserID получает заказы пользователя, подходящие под фильтр, новые первыми
+func (r *OrderRepository) GetOrdersByUserID(ctx context.Context, userID int64, filter domain.OrderFilter) ([]*domain.Order, error) {
+	conditions := []string{"user_id = $1"}
+	args := []any{userID}
+	addCondition := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.Status != "" {
+		addCondition("status = $%d", filter.Status)
+	}
+	if !filter.From.IsZero() {
+		addCondition("uploaded_at >= $%d", filter.From.UTC())
+	}
+	if !filter.To.IsZero() {
+		addCondition("uploaded_at < $%d", filter.To.UTC())
+	}
 
+	query := `SELECT id, public_id, user_id, number, status, accrual, uploaded_at, metadata 
+		 FROM orders 
+		 WHERE ` + strings.Join(conditions, " AND ") + ` 
+		 ORDER BY uploaded_at DESC, id DESC`
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+	if filter.Offset > 0 {
+		args = append(args, filter.Offset)
+		query += fmt.Sprintf(" OFFSET $%d", len(args))
+	}
+
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("repository: failed to get orders for user %d: %w", userID, err)
 	}
@@ -93,7 +163,38 @@ func (r *OrderRepository) GetOrdersByUserID(ctx context.Context, userID int64) (
 	var orders []*domain.Order
 	for rows.Next() {
 		order := &domain.Order{}
-		err := rows.Scan(&order.ID, &order.UserID, &order.Number, &order.Status, &order.Accrual, &order.UploadedAt)
+		err := rows.Scan(&order.ID, &order.PublicID, &order.UserID, &order.Number, &order.Status, &order.Accrual, &order.UploadedAt, &order.Metadata)
+		if err != nil {
+			return nil, fmt.Errorf("repository: failed to scan order: %w", err)
+		}
+		orders = append(orders, order)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repository: error iterating orders: %w", err)
+	}
+
+	return orders, nil
+}
+
+// GetOrdersByNumbers получает заказы по списку номеров одним запросом.
+// Ненайденных номеров в результате нет, порядок заказов не определен
+func (r *OrderRepository) GetOrdersByNumbers(ctx context.Context, numbers []string) ([]*domain.Order, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT id, public_id, user_id, number, status, accrual, uploaded_at, metadata
+		 FROM orders
+		 WHERE number = ANY($1)`,
+		numbers,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to get %d orders by number: %w", len(numbers), err)
+	}
+	defer rows.Close()
+
+	orders := make([]*domain.Order, 0, len(numbers))
+	for rows.Next() {
+		order := &domain.Order{}
+		err := rows.Scan(&order.ID, &order.PublicID, &order.UserID, &order.Number, &order.Status, &order.Accrual, &order.UploadedAt, &order.Metadata)
 		if err != nil {
 			return nil, fmt.Errorf("repository: failed to scan order: %w", err)
 		}
@@ -107,34 +208,439 @@ func (r *OrderRepository) GetOrdersByUserID(ctx context.Context, userID int64) (
 	return orders, nil
 }
 
-// UpdateOrderStatus обновляет статус заказа и начисление
-func (r *OrderRepository) UpdateOrderStatus(ctx context.Context, number string, status domain.OrderStatus, accrual *float64) error {
-	result, err := r.db.Exec(ctx,
+// SearchOrders ищет заказы всех пользователей по фильтру, новые первыми.
+// Поиск по началу номера использует индекс idx_orders_number_prefix.
+func (r *OrderRepository) SearchOrders(ctx context.Context, filter domain.OrderSearchFilter) ([]*domain.AdminOrder, error) {
+	var conditions []string
+	var args []any
+	addCondition := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.NumberPrefix != "" {
+		// Префикс состоит только из цифр, экранировать шаблон LIKE не нужно
+		addCondition("o.number LIKE $%d", filter.NumberPrefix+"%")
+	}
+	if filter.Login != "" {
+		addCondition("u.login = $%d", filter.Login)
+	}
+	if filter.Status != "" {
+		addCondition("o.status = $%d", filter.Status)
+	}
+	if !filter.Metadata.IsEmpty() {
+		addCondition("o.metadata @> $%d", filter.Metadata)
+	}
+
+	query := `SELECT o.id, o.public_id, o.user_id, o.number, o.status, o.accrual, o.uploaded_at, o.metadata, u.login 
+		 FROM orders o 
+		 JOIN users u ON u.id = o.user_id`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	args = append(args, filter.Limit, filter.Offset)
+	query += fmt.Sprintf(" ORDER BY o.uploaded_at DESC, o.id DESC LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to search orders: %w", err)
+	}
+	defer rows.Close()
+
+	var orders []*domain.AdminOrder
+	for rows.Next() {
+		order := &domain.AdminOrder{}
+		err := rows.Scan(&order.ID, &order.PublicID, &order.UserID, &order.Number, &order.Status, &order.Accrual, &order.UploadedAt, &order.Metadata, &order.Login)
+		if err != nil {
+			return nil, fmt.Errorf("repository: failed to scan order: %w", err)
+		}
+		orders = append(orders, order)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repository: error iterating found orders: %w", err)
+	}
+
+	return orders, nil
+}
+
+// FindAccrualMismatches сверяет начисления заказов с журналом транзакций и возвращает
+// расхождения, новые заказы первыми. Начисление в журнале - сумма начисления и корректировок
+func (r *OrderRepository) FindAccrualMismatches(ctx context.Context, limit, offset int) ([]*domain.AccrualMismatch, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT o.number, o.user_id, u.login, o.status, o.accrual, t.amount 
+		 FROM orders o 
+		 JOIN users u ON u.id = o.user_id 
+		 LEFT JOIN (
+			SELECT order_number, SUM(amount) AS amount 
+			FROM transactions 
+			WHERE type IN ($1, $2) 
+			GROUP BY order_number
+		 ) t ON t.order_number = o.number 
+		 WHERE (t.order_number IS NULL AND o.status = $3 AND o.accrual > 0) 
+		    OR (t.order_number IS NOT NULL AND o.accrual IS DISTINCT FROM t.amount) 
+		 ORDER BY o.uploaded_at DESC, o.id DESC 
+		 LIMIT $4 OFFSET $5`,
+		domain.TransactionTypeAccrual, domain.TransactionTypeAdjustment, domain.OrderStatusProcessed, limit, offset,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to find accrual mismatches: %w", err)
+	}
+	defer rows.Close()
+
+	var mismatches []*domain.AccrualMismatch
+	for rows.Next() {
+		m := &domain.AccrualMismatch{}
+		if err := rows.Scan(&m.OrderNumber, &m.UserID, &m.Login, &m.Status, &m.OrderAccrual, &m.LedgerAccrual); err != nil {
+			return nil, fmt.Errorf("repository: failed to scan accrual mismatch: %w", err)
+		}
+		mismatches = append(mismatches, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repository: error iterating accrual mismatches: %w", err)
+	}
+
+	return mismatches, nil
+}
+
+// ApplyAccrual применяет ответ системы начислений к заказу. Строка заказа блокируется
+// (FOR UPDATE), поэтому параллельные вызовы для одного заказа с разных воркеров и
+// экземпляров выполняются по очереди и видят результат друг друга.
+//
+// Промежуточный статус просто сохраняется. Финальный статус, начисление баллов и событие
+// заказа фиксируются одной транзакцией, поэтому событие появляется только вместе с начислением.
+// Исходная сумма и политика округления сохраняются в транзакции начисления для аудита.
+// Для уже обработанного заказа возвращает ErrDuplicateAccrual, для заказа в другом
+// финальном статусе - ErrOrderAlreadyFinal; в обоих случаях ничего не меняет.
+func (r *OrderRepository) ApplyAccrual(ctx context.Context, number string, status domain.OrderStatus, accrual *domain.Accrual) error {
+	if !status.IsValid() {
+		return fmt.Errorf("repository: invalid status %q for order %q: %w", status, number, domain.ErrInvalidInput)
+	}
+
+	var amount *float64
+	if accrual != nil {
+		amount = &accrual.Amount
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("repository: failed to begin transaction for order %q: %w", number, err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // Rollback после Commit безопасен
+
+	var userID int64
+	var current domain.OrderStatus
+	var metadata *domain.OrderMetadata
+	err = tx.QueryRow(ctx,
+		`SELECT user_id, status, metadata FROM orders WHERE number = $1 FOR UPDATE`,
+		number,
+	).Scan(&userID, &current, &metadata)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ErrOrderNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("repository: failed to lock order %q: %w", number, err)
+	}
+
+	if current.IsFinal() {
+		if current == domain.OrderStatusProcessed && status == domain.OrderStatusProcessed {
+			return domain.ErrDuplicateAccrual
+		}
+		return fmt.Errorf("repository: order %q is already %s: %w", number, current, domain.ErrOrderAlreadyFinal)
+	}
+
+	if !status.IsFinal() {
+		if status == current {
+			return nil
+		}
+		if _, err := tx.Exec(ctx, `UPDATE orders SET status = $1 WHERE number = $2`, status, number); err != nil {
+			return fmt.Errorf("repository: failed to update order %q status: %w", number, err)
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return fmt.Errorf("repository: failed to commit order %q status: %w", number, err)
+		}
+		return nil
+	}
+
+	eventType := domain.OrderEventInvalid
+	if status == domain.OrderStatusProcessed {
+		eventType = domain.OrderEventProcessed
+	}
+
+	if _, err := tx.Exec(ctx,
 		`UPDATE orders 
 		 SET status = $1, accrual = $2 
 		 WHERE number = $3`,
-		status, accrual, number,
+		status, amount, number,
+	); err != nil {
+		return fmt.Errorf("repository: failed to update order %q status: %w", number, err)
+	}
+
+	if status == domain.OrderStatusProcessed && accrual != nil && accrual.Amount > 0 {
+		// Уникальный индекс остается последней защитой от двойного начисления
+		result, err := tx.Exec(ctx,
+			`INSERT INTO transactions (user_id, order_number, amount, type, public_id, accrual_raw, rounding_policy) 
+			 VALUES ($1, $2, $3, $4, $5, $6, $7) 
+			 ON CONFLICT (order_number) WHERE type = 'accrual' DO NOTHING`,
+			userID, number, accrual.Amount, domain.TransactionTypeAccrual, newPublicID(),
+			accrual.Raw, accrual.Policy.String(),
+		)
+		if err != nil {
+			return fmt.Errorf("repository: failed to create accrual for order %q: %w", number, err)
+		}
+		if result.RowsAffected() == 0 {
+			return domain.ErrDuplicateAccrual
+		}
+	}
+
+	_, err = tx.Exec(ctx,
+		`INSERT INTO order_events (type, order_number, user_id, status, accrual, metadata) 
+		 VALUES ($1, $2, $3, $4, $5, $6)`,
+		eventType, number, userID, status, amount, metadata,
 	)
+	if err != nil {
+		return fmt.Errorf("repository: failed to create event for order %q: %w", number, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("repository: failed to commit order %q completion: %w", number, err)
+	}
+
+	return nil
+}
 
+// CorrectAccrual приводит начисление по обработанному заказу к новой сумме из системы начислений.
+// Разница записывается транзакцией корректировки владельцу заказа, исходное начисление не меняется.
+// Корректировка и запись журнала accrual_corrections выполняются под блокировкой строки баланса
+// пользователя, как списания, поэтому уменьшение начисления не расходится с параллельным списанием.
+// Если сумма не изменилась, возвращает ErrAccrualUnchanged.
+func (r *OrderRepository) CorrectAccrual(ctx context.Context, number string, accrual *domain.Accrual, correctedBy int64) (*domain.AccrualCorrection, error) {
+	tx, err := r.db.Begin(ctx)
 	if err != nil {
-		return fmt.Errorf("repository: failed to update order %q status: %w", number, err)
+		return nil, fmt.Errorf("repository: failed to begin transaction for order %q: %w", number, err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // Rollback после Commit безопасен
+
+	var userID int64
+	var status domain.OrderStatus
+	err = tx.QueryRow(ctx,
+		`SELECT user_id, status FROM orders WHERE number = $1 FOR UPDATE`,
+		number,
+	).Scan(&userID, &status)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrOrderNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to get order %q: %w", number, err)
+	}
+	if status != domain.OrderStatusProcessed {
+		return nil, domain.ErrOrderNotProcessed
 	}
 
-	if result.RowsAffected() == 0 {
-		return ErrOrderNotFound
+	if err := lockBalancesTx(ctx, tx, userID); err != nil {
+		return nil, err
+	}
+
+	var recorded float64
+	err = tx.QueryRow(ctx,
+		`SELECT COALESCE(SUM(amount), 0) 
+		 FROM transactions 
+		 WHERE order_number = $1 AND type IN ($2, $3)`,
+		number, domain.TransactionTypeAccrual, domain.TransactionTypeAdjustment,
+	).Scan(&recorded)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to get recorded accrual for order %q: %w", number, err)
+	}
+
+	// Суммы хранятся с точностью до копейки, сравниваем в копейках
+	recordedCents, newCents := math.Round(recorded*100), math.Round(accrual.Amount*100)
+	if recordedCents == newCents {
+		return nil, domain.ErrAccrualUnchanged
+	}
+	correction := &domain.AccrualCorrection{
+		OrderNumber:    number,
+		UserID:         userID,
+		PreviousAmount: recorded,
+		NewAmount:      accrual.Amount,
+		Delta:          (newCents - recordedCents) / 100,
+		CorrectedBy:    correctedBy,
+	}
+
+	var transactionID int64
+	err = tx.QueryRow(ctx,
+		`INSERT INTO transactions (user_id, order_number, amount, type, public_id, accrual_raw, rounding_policy) 
+		 VALUES ($1, $2, $3, $4, $5, $6, $7) 
+		 RETURNING id`,
+		userID, number, correction.Delta, domain.TransactionTypeAdjustment, newPublicID(),
+		accrual.Raw, accrual.Policy.String(),
+	).Scan(&transactionID)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to create adjustment for order %q: %w", number, err)
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE orders SET accrual = $1 WHERE number = $2`, accrual.Amount, number); err != nil {
+		return nil, fmt.Errorf("repository: failed to update accrual of order %q: %w", number, err)
+	}
+
+	var correctedByID *int64
+	if correctedBy != 0 {
+		correctedByID = &correctedBy
+	}
+	err = tx.QueryRow(ctx,
+		`INSERT INTO accrual_corrections 
+			(order_number, user_id, transaction_id, previous_amount, new_amount, accrual_raw, rounding_policy, corrected_by) 
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8) 
+		 RETURNING created_at`,
+		number, userID, transactionID, recorded, accrual.Amount, accrual.Raw, accrual.Policy.String(), correctedByID,
+	).Scan(&correction.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to record correction of order %q: %w", number, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("repository: failed to commit correction of order %q: %w", number, err)
+	}
+
+	return correction, nil
+}
+
+// TransferOrder передает заказ пользователю с логином toLogin вместе с начислением по нему.
+// Начисление переносится парой транзакций корректировки: списанием у прежнего владельца
+// и начислением новому, исходные транзакции не меняются. Перенос записывается в журнал
+// order_transfers. Строки баланса обоих пользователей блокируются в порядке возрастания ID,
+// как при списании, поэтому перенос не расходится с параллельными списаниями.
+// Если заказ уже принадлежит этому пользователю, возвращает ErrOrderAlreadyOwned.
+func (r *OrderRepository) TransferOrder(ctx context.Context, number, toLogin string, transferredBy int64) (*domain.OrderTransfer, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to begin transaction for order %q: %w", number, err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // Rollback после Commit безопасен
+
+	transfer := &domain.OrderTransfer{OrderNumber: number, ToLogin: toLogin, TransferredBy: transferredBy}
+
+	err = tx.QueryRow(ctx, `SELECT id FROM users WHERE login = $1`, toLogin).Scan(&transfer.ToUserID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to get user %q: %w", toLogin, err)
+	}
+
+	if err := transferOrderTx(ctx, tx, transfer); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("repository: failed to commit transfer of order %q: %w", number, err)
+	}
+
+	return transfer, nil
+}
+
+// transferOrderTx переносит заказ пользователю transfer.ToUserID в транзакции tx
+// и заполняет прежнего владельца, сумму и время переноса
+func transferOrderTx(ctx context.Context, tx pgx.Tx, transfer *domain.OrderTransfer) error {
+	number := transfer.OrderNumber
+
+	err := tx.QueryRow(ctx,
+		`SELECT o.user_id, u.login 
+		 FROM orders o 
+		 JOIN users u ON u.id = o.user_id 
+		 WHERE o.number = $1 
+		 FOR UPDATE OF o`,
+		number,
+	).Scan(&transfer.FromUserID, &transfer.FromLogin)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ErrOrderNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("repository: failed to get order %q: %w", number, err)
+	}
+	if transfer.FromUserID == transfer.ToUserID {
+		return domain.ErrOrderAlreadyOwned
+	}
+
+	if err := lockBalancesTx(ctx, tx, transfer.FromUserID, transfer.ToUserID); err != nil {
+		return err
+	}
+
+	err = tx.QueryRow(ctx,
+		`SELECT COALESCE(SUM(amount), 0) 
+		 FROM transactions 
+		 WHERE order_number = $1 AND user_id = $2 AND type IN ($3, $4)`,
+		number, transfer.FromUserID, domain.TransactionTypeAccrual, domain.TransactionTypeAdjustment,
+	).Scan(&transfer.Amount)
+	if err != nil {
+		return fmt.Errorf("repository: failed to get recorded accrual for order %q: %w", number, err)
+	}
+
+	// Заказ без начисления переносится без транзакций
+	var fromTransactionID, toTransactionID *int64
+	if math.Round(transfer.Amount*100) != 0 {
+		if fromTransactionID, err = insertAdjustment(ctx, tx, transfer.FromUserID, number, -transfer.Amount); err != nil {
+			return err
+		}
+		if toTransactionID, err = insertAdjustment(ctx, tx, transfer.ToUserID, number, transfer.Amount); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE orders SET user_id = $1 WHERE number = $2`, transfer.ToUserID, number); err != nil {
+		return fmt.Errorf("repository: failed to update owner of order %q: %w", number, err)
+	}
+
+	err = tx.QueryRow(ctx,
+		`INSERT INTO order_transfers 
+			(order_number, from_user_id, to_user_id, amount, from_transaction_id, to_transaction_id, transferred_by) 
+		 VALUES ($1, $2, $3, $4, $5, $6, $7) 
+		 RETURNING created_at`,
+		number, transfer.FromUserID, transfer.ToUserID, transfer.Amount, fromTransactionID, toTransactionID, transfer.TransferredBy,
+	).Scan(&transfer.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("repository: failed to record transfer of order %q: %w", number, err)
 	}
 
 	return nil
 }
 
-// GetPendingOrders получает все заказы со статусом NEW или PROCESSING
-func (r *OrderRepository) GetPendingOrders(ctx context.Context) ([]*domain.Order, error) {
+// insertAdjustment записывает транзакцию корректировки по заказу и возвращает ее ID
+func insertAdjustment(ctx context.Context, tx pgx.Tx, userID int64, number string, amount float64) (*int64, error) {
+	var id int64
+	err := tx.QueryRow(ctx,
+		`INSERT INTO transactions (user_id, order_number, amount, type, public_id) 
+		 VALUES ($1, $2, $3, $4, $5) 
+		 RETURNING id`,
+		userID, number, amount, domain.TransactionTypeAdjustment, newPublicID(),
+	).Scan(&id)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to create adjustment for order %q: %w", number, err)
+	}
+	return &id, nil
+}
+
+// GetPendingOrders получает заказы в статусах политик, загруженные не раньше MaxAge политики
+// своего статуса. Возраст считается по часам БД, как и время загрузки
+func (r *OrderRepository) GetPendingOrders(ctx context.Context, policies []domain.OrderScanPolicy) ([]*domain.Order, error) {
+	if len(policies) == 0 {
+		return nil, nil
+	}
+
+	statuses := make([]string, len(policies))
+	maxAges := make([]int64, len(policies))
+	for i, policy := range policies {
+		statuses[i] = string(policy.Status)
+		maxAges[i] = int64(policy.MaxAge / time.Second)
+	}
+
 	rows, err := r.db.Query(ctx,
-		`SELECT id, user_id, number, status, accrual, uploaded_at 
-		 FROM orders 
-		 WHERE status IN ($1, $2) 
-		 ORDER BY uploaded_at ASC`,
-		domain.OrderStatusNew, domain.OrderStatusProcessing,
+		`SELECT o.id, o.public_id, o.user_id, o.number, o.status, o.accrual, o.uploaded_at, o.metadata 
+		 FROM orders o 
+		 JOIN unnest($1::varchar[], $2::bigint[]) AS p(status, max_age) ON o.status = p.status 
+		 WHERE p.max_age = 0 OR o.uploaded_at >= NOW() - make_interval(secs => p.max_age) 
+		 ORDER BY o.uploaded_at ASC`,
+		statuses, maxAges,
 	)
 
 	if err != nil {
@@ -145,7 +651,7 @@ func (r *OrderRepository) GetPendingOrders(ctx context.Context) ([]*domain.Order
 	var orders []*domain.Order
 	for rows.Next() {
 		order := &domain.Order{}
-		err := rows.Scan(&order.ID, &order.UserID, &order.Number, &order.Status, &order.Accrual, &order.UploadedAt)
+		err := rows.Scan(&order.ID, &order.PublicID, &order.UserID, &order.Number, &order.Status, &order.Accrual, &order.UploadedAt, &order.Metadata)
 		if err != nil {
 			return nil, fmt.Errorf("repository: failed to scan order: %w", err)
 		}
@@ -158,3 +664,20 @@ func (r *OrderRepository) GetPendingOrders(ctx context.Context) ([]*domain.Order
 
 	return orders, nil
 }
+
+// GetPendingAccrual считает необработанные заказы пользователя и уже известную сумму начислений по ним
+func (r *OrderRepository) GetPendingAccrual(ctx context.Context, userID int64) (*domain.PendingAccrual, error) {
+	pending := &domain.PendingAccrual{}
+
+	err := r.db.QueryRow(ctx,
+		`SELECT COUNT(*), COALESCE(SUM(accrual), 0) 
+		 FROM orders 
+		 WHERE user_id = $1 AND status IN ($2, $3)`,
+		userID, domain.OrderStatusNew, domain.OrderStatusProcessing,
+	).Scan(&pending.Orders, &pending.Accrual)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to get pending accrual for user %d: %w", userID, err)
+	}
+
+	return pending, nil
+}
@@ -0,0 +1,153 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+// orderDisputeQuery выбирает споры вместе с логинами заявителя и владельца
+const orderDisputeQuery = `SELECT d.id, d.order_number, d.claimant_id, c.login, d.owner_id, w.login, 
+		d.comment, d.status, d.resolution_comment, d.resolved_by, d.created_at, d.resolved_at 
+	 FROM order_disputes d 
+	 JOIN users c ON c.id = d.claimant_id 
+	 JOIN users w ON w.id = d.owner_id `
+
+func scanOrderDispute(row pgx.Row) (*domain.OrderDispute, error) {
+	dispute := &domain.OrderDispute{}
+	err := row.Scan(&dispute.ID, &dispute.OrderNumber, &dispute.ClaimantID, &dispute.ClaimantLogin,
+		&dispute.OwnerID, &dispute.OwnerLogin, &dispute.Comment, &dispute.Status, &dispute.ResolutionComment,
+		&dispute.ResolvedBy, &dispute.CreatedAt, &dispute.ResolvedAt)
+	return dispute, err
+}
+
+// CreateOrderDispute открывает спор пользователя о заказе, загруженном другим пользователем.
+// Возвращает ErrOrderNotFound, ErrOrderAlreadyOwned, если заказ уже принадлежит заявителю,
+// и ErrDisputeExists, если у заявителя уже есть открытый спор по этому заказу
+func (r *OrderRepository) CreateOrderDispute(ctx context.Context, claimantID int64, number, comment string) (*domain.OrderDispute, error) {
+	dispute := &domain.OrderDispute{OrderNumber: number, ClaimantID: claimantID, Comment: comment}
+
+	err := r.db.QueryRow(ctx, `SELECT user_id FROM orders WHERE number = $1`, number).Scan(&dispute.OwnerID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrOrderNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to get order %q: %w", number, err)
+	}
+	if dispute.OwnerID == claimantID {
+		return nil, domain.ErrOrderAlreadyOwned
+	}
+
+	err = r.db.QueryRow(ctx,
+		`INSERT INTO order_disputes (order_number, claimant_id, owner_id, comment) 
+		 VALUES ($1, $2, $3, $4) 
+		 ON CONFLICT (order_number, claimant_id) WHERE status = 'open' DO NOTHING 
+		 RETURNING id, status, created_at`,
+		number, claimantID, dispute.OwnerID, comment,
+	).Scan(&dispute.ID, &dispute.Status, &dispute.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrDisputeExists
+	}
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to create dispute for order %q: %w", number, err)
+	}
+
+	return dispute, nil
+}
+
+// ListOrderDisputes возвращает споры в статусе status (пустой - все), старые первыми
+func (r *OrderRepository) ListOrderDisputes(ctx context.Context, status domain.DisputeStatus, limit, offset int) ([]*domain.OrderDispute, error) {
+	rows, err := r.db.Query(ctx,
+		orderDisputeQuery+
+			`WHERE ($1 = '' OR d.status = $1) 
+		 ORDER BY d.created_at, d.id 
+		 LIMIT $2 OFFSET $3`,
+		status, limit, offset,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to list order disputes: %w", err)
+	}
+	defer rows.Close()
+
+	var disputes []*domain.OrderDispute
+	for rows.Next() {
+		dispute, err := scanOrderDispute(rows)
+		if err != nil {
+			return nil, fmt.Errorf("repository: failed to scan order dispute: %w", err)
+		}
+		disputes = append(disputes, dispute)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repository: error iterating order disputes: %w", err)
+	}
+
+	return disputes, nil
+}
+
+// ResolveOrderDispute решает открытый спор: передает заказ заявителю (как TransferOrder)
+// или отклоняет спор. В той же транзакции записывается событие для заявителя, поэтому
+// уведомление появляется только вместе с решением. Возвращает ErrDisputeNotFound,
+// ErrDisputeResolved для решенного спора и ошибки переноса заказа
+func (r *OrderRepository) ResolveOrderDispute(ctx context.Context, id int64, resolution domain.DisputeResolution, comment string, resolvedBy int64) (*domain.OrderDispute, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to begin transaction for dispute %d: %w", id, err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // Rollback после Commit безопасен
+
+	dispute, err := scanOrderDispute(tx.QueryRow(ctx, orderDisputeQuery+`WHERE d.id = $1 FOR UPDATE OF d`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrDisputeNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to get dispute %d: %w", id, err)
+	}
+	if dispute.Status != domain.DisputeStatusOpen {
+		return nil, domain.ErrDisputeResolved
+	}
+
+	dispute.Status, dispute.ResolutionComment, dispute.ResolvedBy = domain.DisputeStatusRejected, comment, &resolvedBy
+	eventType := domain.OrderEventDisputeRejected
+	if resolution == domain.DisputeResolutionTransfer {
+		err := transferOrderTx(ctx, tx, &domain.OrderTransfer{
+			OrderNumber:   dispute.OrderNumber,
+			ToUserID:      dispute.ClaimantID,
+			ToLogin:       dispute.ClaimantLogin,
+			TransferredBy: resolvedBy,
+		})
+		if err != nil {
+			return nil, err
+		}
+		dispute.Status, eventType = domain.DisputeStatusTransferred, domain.OrderEventDisputeTransferred
+	}
+
+	err = tx.QueryRow(ctx,
+		`UPDATE order_disputes 
+		 SET status = $2, resolution_comment = $3, resolved_by = $4, resolved_at = NOW() 
+		 WHERE id = $1 
+		 RETURNING resolved_at`,
+		id, dispute.Status, comment, resolvedBy,
+	).Scan(&dispute.ResolvedAt)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to resolve dispute %d: %w", id, err)
+	}
+
+	_, err = tx.Exec(ctx,
+		`INSERT INTO order_events (type, order_number, user_id, status, metadata) 
+		 SELECT $1, number, $2, status, metadata FROM orders WHERE number = $3`,
+		eventType, dispute.ClaimantID, dispute.OrderNumber,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to create event for dispute %d: %w", id, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("repository: failed to commit resolution of dispute %d: %w", id, err)
+	}
+
+	return dispute, nil
+}
@@ -0,0 +1,186 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var orderDisputeColumns = []string{
+	"id", "order_number", "claimant_id", "claimant_login", "owner_id", "owner_login",
+	"comment", "status", "resolution_comment", "resolved_by", "created_at", "resolved_at",
+}
+
+func TestOrderRepository_CreateOrderDispute(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewOrderRepository(mock)
+	ctx := context.Background()
+	number := "12345678903"
+
+	t.Run("Created", func(t *testing.T) {
+		createdAt := time.Now()
+		mock.ExpectQuery(`SELECT user_id FROM orders WHERE number = \$1`).
+			WithArgs(number).
+			WillReturnRows(pgxmock.NewRows([]string{"user_id"}).AddRow(int64(9)))
+		mock.ExpectQuery(`INSERT INTO order_disputes .* ON CONFLICT \(order_number, claimant_id\) WHERE status = 'open' DO NOTHING`).
+			WithArgs(number, int64(8), int64(9), "my receipt").
+			WillReturnRows(pgxmock.NewRows([]string{"id", "status", "created_at"}).AddRow(int64(1), domain.DisputeStatusOpen, createdAt))
+
+		dispute, err := repo.CreateOrderDispute(ctx, 8, number, "my receipt")
+		require.NoError(t, err)
+		assert.Equal(t, &domain.OrderDispute{
+			ID:          1,
+			OrderNumber: number,
+			ClaimantID:  8,
+			OwnerID:     9,
+			Comment:     "my receipt",
+			Status:      domain.DisputeStatusOpen,
+			CreatedAt:   createdAt,
+		}, dispute)
+	})
+
+	t.Run("Order not found", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT user_id FROM orders`).WithArgs(number).WillReturnError(pgx.ErrNoRows)
+
+		_, err := repo.CreateOrderDispute(ctx, 8, number, "")
+		assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+	})
+
+	t.Run("Own order", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT user_id FROM orders`).
+			WithArgs(number).
+			WillReturnRows(pgxmock.NewRows([]string{"user_id"}).AddRow(int64(8)))
+
+		_, err := repo.CreateOrderDispute(ctx, 8, number, "")
+		assert.ErrorIs(t, err, domain.ErrOrderAlreadyOwned)
+	})
+
+	t.Run("Dispute already open", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT user_id FROM orders`).
+			WithArgs(number).
+			WillReturnRows(pgxmock.NewRows([]string{"user_id"}).AddRow(int64(9)))
+		mock.ExpectQuery(`INSERT INTO order_disputes`).
+			WithArgs(number, int64(8), int64(9), "").
+			WillReturnError(pgx.ErrNoRows)
+
+		_, err := repo.CreateOrderDispute(ctx, 8, number, "")
+		assert.ErrorIs(t, err, domain.ErrDisputeExists)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOrderRepository_ListOrderDisputes(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewOrderRepository(mock)
+	createdAt := time.Now()
+
+	mock.ExpectQuery(`FROM order_disputes d JOIN users c ON c.id = d.claimant_id JOIN users w ON w.id = d.owner_id WHERE \(\$1 = '' OR d.status = \$1\) ORDER BY d.created_at, d.id LIMIT \$2 OFFSET \$3`).
+		WithArgs(domain.DisputeStatusOpen, 51, 0).
+		WillReturnRows(pgxmock.NewRows(orderDisputeColumns).
+			AddRow(int64(1), "12345678903", int64(8), "bob", int64(9), "alice", "my receipt", domain.DisputeStatusOpen, "", (*int64)(nil), createdAt, (*time.Time)(nil)))
+
+	disputes, err := repo.ListOrderDisputes(context.Background(), domain.DisputeStatusOpen, 51, 0)
+	require.NoError(t, err)
+	require.Len(t, disputes, 1)
+	assert.Equal(t, "bob", disputes[0].ClaimantLogin)
+	assert.Equal(t, "alice", disputes[0].OwnerLogin)
+	assert.Nil(t, disputes[0].ResolvedAt)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOrderRepository_ResolveOrderDispute(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewOrderRepository(mock)
+	ctx := context.Background()
+	number := "12345678903"
+	createdAt := time.Now()
+
+	expectDispute := func(status domain.DisputeStatus) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`FROM order_disputes d .* WHERE d.id = \$1 FOR UPDATE OF d`).
+			WithArgs(int64(1)).
+			WillReturnRows(pgxmock.NewRows(orderDisputeColumns).
+				AddRow(int64(1), number, int64(8), "bob", int64(9), "alice", "my receipt", status, "", (*int64)(nil), createdAt, (*time.Time)(nil)))
+	}
+	expectResolved := func(status domain.DisputeStatus, event domain.OrderEventType) {
+		resolvedAt := createdAt.Add(time.Hour)
+		mock.ExpectQuery(`UPDATE order_disputes SET status = \$2, resolution_comment = \$3, resolved_by = \$4, resolved_at = NOW\(\) WHERE id = \$1 RETURNING resolved_at`).
+			WithArgs(int64(1), status, "checked", int64(2)).
+			WillReturnRows(pgxmock.NewRows([]string{"resolved_at"}).AddRow(&resolvedAt))
+		mock.ExpectExec(`INSERT INTO order_events \(type, order_number, user_id, status, metadata\) SELECT \$1, number, \$2, status, metadata FROM orders WHERE number = \$3`).
+			WithArgs(event, int64(8), number).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+	}
+
+	t.Run("Transfer", func(t *testing.T) {
+		expectDispute(domain.DisputeStatusOpen)
+		mock.ExpectQuery(`SELECT o.user_id, u.login FROM orders o JOIN users u ON u.id = o.user_id WHERE o.number = \$1 FOR UPDATE OF o`).
+			WithArgs(number).
+			WillReturnRows(pgxmock.NewRows([]string{"user_id", "login"}).AddRow(int64(9), "alice"))
+		mock.ExpectExec(`INSERT INTO user_balances \(user_id\) VALUES \(\$1\) ON CONFLICT \(user_id\) DO UPDATE SET updated_at = user_balances.updated_at`).WithArgs(int64(8)).WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectExec(`INSERT INTO user_balances \(user_id\) VALUES \(\$1\) ON CONFLICT \(user_id\) DO UPDATE SET updated_at = user_balances.updated_at`).WithArgs(int64(9)).WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectQuery(`SELECT COALESCE\(SUM\(amount\), 0\) FROM transactions`).
+			WithArgs(number, int64(9), domain.TransactionTypeAccrual, domain.TransactionTypeAdjustment).
+			WillReturnRows(pgxmock.NewRows([]string{"sum"}).AddRow(0.0))
+		mock.ExpectExec(`UPDATE orders SET user_id = \$1 WHERE number = \$2`).
+			WithArgs(int64(8), number).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectQuery(`INSERT INTO order_transfers`).
+			WithArgs(number, int64(9), int64(8), 0.0, (*int64)(nil), (*int64)(nil), int64(2)).
+			WillReturnRows(pgxmock.NewRows([]string{"created_at"}).AddRow(createdAt))
+		expectResolved(domain.DisputeStatusTransferred, domain.OrderEventDisputeTransferred)
+
+		dispute, err := repo.ResolveOrderDispute(ctx, 1, domain.DisputeResolutionTransfer, "checked", 2)
+		require.NoError(t, err)
+		assert.Equal(t, domain.DisputeStatusTransferred, dispute.Status)
+		assert.Equal(t, "checked", dispute.ResolutionComment)
+		require.NotNil(t, dispute.ResolvedAt)
+	})
+
+	t.Run("Reject", func(t *testing.T) {
+		expectDispute(domain.DisputeStatusOpen)
+		expectResolved(domain.DisputeStatusRejected, domain.OrderEventDisputeRejected)
+
+		dispute, err := repo.ResolveOrderDispute(ctx, 1, domain.DisputeResolutionReject, "checked", 2)
+		require.NoError(t, err)
+		assert.Equal(t, domain.DisputeStatusRejected, dispute.Status)
+	})
+
+	t.Run("Already resolved", func(t *testing.T) {
+		expectDispute(domain.DisputeStatusRejected)
+		mock.ExpectRollback()
+
+		_, err := repo.ResolveOrderDispute(ctx, 1, domain.DisputeResolutionTransfer, "", 2)
+		assert.ErrorIs(t, err, domain.ErrDisputeResolved)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`FROM order_disputes`).WithArgs(int64(5)).WillReturnError(pgx.ErrNoRows)
+		mock.ExpectRollback()
+
+		_, err := repo.ResolveOrderDispute(ctx, 5, domain.DisputeResolutionReject, "", 2)
+		assert.ErrorIs(t, err, domain.ErrDisputeNotFound)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,94 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+)
+
+// RecordDuplicateSubmission учитывает повторную загрузку заказа пользователем
+// в счетчике текущих суток по каналу продажи
+func (r *OrderRepository) RecordDuplicateSubmission(ctx context.Context, userID int64, channel string) error {
+	_, err := r.db.Exec(ctx,
+		`INSERT INTO order_duplicate_submissions (day, user_id, channel, submissions, last_at) 
+		 VALUES (CURRENT_DATE, $1, $2, 1, NOW()) 
+		 ON CONFLICT (day, user_id, channel) DO UPDATE 
+		 SET submissions = order_duplicate_submissions.submissions + 1, last_at = EXCLUDED.last_at`,
+		userID, channel,
+	)
+	if err != nil {
+		return fmt.Errorf("repository: failed to record duplicate submission for user %d: %w", userID, err)
+	}
+	return nil
+}
+
+// GetDuplicateSubmissionReport возвращает повторные загрузки заказов за последние days суток,
+// включая текущие. Submitters содержит до limit пользователей начиная с offset
+func (r *OrderRepository) GetDuplicateSubmissionReport(ctx context.Context, days, limit, offset int) (*domain.DuplicateSubmissionReport, error) {
+	report := &domain.DuplicateSubmissionReport{}
+	err := r.db.QueryRow(ctx,
+		`WITH period AS (SELECT CURRENT_DATE - ($1::int - 1) AS since) 
+		 SELECT p.since::timestamp, COALESCE(SUM(d.submissions), 0), COUNT(DISTINCT d.user_id), 
+		        (SELECT COUNT(*) FROM orders WHERE uploaded_at >= p.since) 
+		 FROM period p 
+		 LEFT JOIN order_duplicate_submissions d ON d.day >= p.since 
+		 GROUP BY p.since`,
+		days,
+	).Scan(&report.Since, &report.Submissions, &report.Users, &report.Orders)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to count duplicate submissions: %w", err)
+	}
+
+	channels, err := r.db.Query(ctx,
+		`SELECT channel, SUM(submissions), COUNT(DISTINCT user_id) 
+		 FROM order_duplicate_submissions 
+		 WHERE day >= $1 
+		 GROUP BY channel 
+		 ORDER BY 2 DESC, channel`,
+		report.Since,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to get duplicate submissions by channel: %w", err)
+	}
+	defer channels.Close()
+
+	for channels.Next() {
+		c := &domain.DuplicateSubmissionChannel{}
+		if err := channels.Scan(&c.Channel, &c.Submissions, &c.Users); err != nil {
+			return nil, fmt.Errorf("repository: failed to scan duplicate submission channel: %w", err)
+		}
+		report.Channels = append(report.Channels, c)
+	}
+	if err := channels.Err(); err != nil {
+		return nil, fmt.Errorf("repository: error iterating duplicate submission channels: %w", err)
+	}
+
+	submitters, err := r.db.Query(ctx,
+		`SELECT d.user_id, u.login, SUM(d.submissions), MAX(d.last_at) 
+		 FROM order_duplicate_submissions d 
+		 JOIN users u ON u.id = d.user_id 
+		 WHERE d.day >= $1 
+		 GROUP BY d.user_id, u.login 
+		 ORDER BY 3 DESC, d.user_id 
+		 LIMIT $2 OFFSET $3`,
+		report.Since, limit, offset,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to get duplicate submitters: %w", err)
+	}
+	defer submitters.Close()
+
+	for submitters.Next() {
+		s := &domain.DuplicateSubmitter{}
+		if err := submitters.Scan(&s.UserID, &s.Login, &s.Submissions, &s.LastAt); err != nil {
+			return nil, fmt.Errorf("repository: failed to scan duplicate submitter: %w", err)
+		}
+		report.Submitters = append(report.Submitters, s)
+	}
+	if err := submitters.Err(); err != nil {
+		return nil, fmt.Errorf("repository: error iterating duplicate submitters: %w", err)
+	}
+
+	return report, nil
+}
@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderRepository_RecordDuplicateSubmission(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Upsert", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+		repo := NewOrderRepository(mock)
+
+		mock.ExpectExec(`INSERT INTO order_duplicate_submissions .* ON CONFLICT \(day, user_id, channel\) DO UPDATE`).
+			WithArgs(int64(1), "mobile").
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+		require.NoError(t, repo.RecordDuplicateSubmission(ctx, 1, "mobile"))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+		repo := NewOrderRepository(mock)
+
+		mock.ExpectExec(`INSERT INTO order_duplicate_submissions`).
+			WithArgs(int64(1), "").
+			WillReturnError(errors.New("boom"))
+
+		assert.Error(t, repo.RecordDuplicateSubmission(ctx, 1, ""))
+	})
+}
+
+func TestOrderRepository_GetDuplicateSubmissionReport(t *testing.T) {
+	ctx := context.Background()
+	since := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	lastAt := time.Date(2024, 3, 7, 12, 0, 0, 0, time.UTC)
+
+	t.Run("Report", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+		repo := NewOrderRepository(mock)
+
+		mock.ExpectQuery(`WITH period AS`).
+			WithArgs(7).
+			WillReturnRows(pgxmock.NewRows([]string{"since", "submissions", "users", "orders"}).
+				AddRow(since, int64(12), 2, int64(40)))
+		mock.ExpectQuery(`SELECT channel, SUM\(submissions\)`).
+			WithArgs(since).
+			WillReturnRows(pgxmock.NewRows([]string{"channel", "submissions", "users"}).
+				AddRow("mobile", int64(10), 1).
+				AddRow("", int64(2), 1))
+		mock.ExpectQuery(`SELECT d.user_id, u.login`).
+			WithArgs(since, 11, 0).
+			WillReturnRows(pgxmock.NewRows([]string{"user_id", "login", "submissions", "last_at"}).
+				AddRow(int64(1), "alice", int64(10), lastAt).
+				AddRow(int64(2), "bob", int64(2), lastAt))
+
+		report, err := repo.GetDuplicateSubmissionReport(ctx, 7, 11, 0)
+		require.NoError(t, err)
+		assert.Equal(t, since, report.Since)
+		assert.Equal(t, int64(12), report.Submissions)
+		assert.Equal(t, 2, report.Users)
+		assert.Equal(t, int64(40), report.Orders)
+		require.Len(t, report.Channels, 2)
+		assert.Equal(t, "mobile", report.Channels[0].Channel)
+		require.Len(t, report.Submitters, 2)
+		assert.Equal(t, "alice", report.Submitters[0].Login)
+		assert.Equal(t, lastAt, report.Submitters[0].LastAt)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+		repo := NewOrderRepository(mock)
+
+		mock.ExpectQuery(`WITH period AS`).WithArgs(7).WillReturnError(errors.New("boom"))
+
+		_, err = repo.GetDuplicateSubmissionReport(ctx, 7, 11, 0)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,66 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+)
+
+// OrderEventRepository реализует чтение outbox таблицы событий заказов.
+// События записываются OrderRepository.ApplyAccrual.
+type OrderEventRepository struct {
+	db DBTX
+}
+
+// NewOrderEventRepository создает новый OrderEventRepository
+func NewOrderEventRepository(db DBTX) *OrderEventRepository {
+	return &OrderEventRepository{db: db}
+}
+
+// GetPendingEvents возвращает неотправленные события в порядке их создания
+func (r *OrderEventRepository) GetPendingEvents(ctx context.Context, limit int) ([]*domain.OrderEvent, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT id, type, order_number, user_id, status, accrual, metadata, created_at
+		 FROM order_events
+		 WHERE dispatched_at IS NULL
+		 ORDER BY id
+		 LIMIT $1`,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to get pending order events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*domain.OrderEvent
+	for rows.Next() {
+		event := &domain.OrderEvent{}
+		err := rows.Scan(&event.ID, &event.Type, &event.OrderNumber, &event.UserID, &event.Status, &event.Accrual, &event.Metadata, &event.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("repository: failed to scan order event: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repository: error iterating order events: %w", err)
+	}
+
+	return events, nil
+}
+
+// MarkEventsDispatched отмечает события как отправленные подписчикам
+func (r *OrderEventRepository) MarkEventsDispatched(ctx context.Context, ids []int64) error {
+	_, err := r.db.Exec(ctx,
+		`UPDATE order_events
+		 SET dispatched_at = NOW()
+		 WHERE id = ANY($1)`,
+		ids,
+	)
+	if err != nil {
+		return fmt.Errorf("repository: failed to mark %d order events dispatched: %w", len(ids), err)
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderEventRepository_GetPendingEvents(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewOrderEventRepository(mock)
+	ctx := context.Background()
+	columns := []string{"id", "type", "order_number", "user_id", "status", "accrual", "metadata", "created_at"}
+
+	t.Run("Success", func(t *testing.T) {
+		accrual := 100.0
+		metadata := &domain.OrderMetadata{ReceiptID: "R-1"}
+		rows := pgxmock.NewRows(columns).
+			AddRow(int64(1), domain.OrderEventProcessed, "111", int64(1), domain.OrderStatusProcessed, &accrual, metadata, time.Now()).
+			AddRow(int64(2), domain.OrderEventInvalid, "222", int64(2), domain.OrderStatusInvalid, (*float64)(nil), nil, time.Now())
+
+		mock.ExpectQuery(`SELECT .* FROM order_events WHERE dispatched_at IS NULL ORDER BY id LIMIT \$1`).
+			WithArgs(100).
+			WillReturnRows(rows)
+
+		events, err := repo.GetPendingEvents(ctx, 100)
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+		assert.Equal(t, domain.OrderEventProcessed, events[0].Type)
+		assert.Equal(t, accrual, *events[0].Accrual)
+		assert.Nil(t, events[1].Accrual)
+		assert.Equal(t, metadata, events[0].Metadata)
+		assert.Nil(t, events[1].Metadata)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Database error", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT .* FROM order_events`).
+			WithArgs(100).
+			WillReturnError(errors.New("database error"))
+
+		events, err := repo.GetPendingEvents(ctx, 100)
+		assert.Error(t, err)
+		assert.Nil(t, events)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestOrderEventRepository_MarkEventsDispatched(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewOrderEventRepository(mock)
+	ids := []int64{1, 2, 3}
+
+	mock.ExpectExec(`UPDATE order_events SET dispatched_at = NOW\(\) WHERE id = ANY\(\$1\)`).
+		WithArgs(ids).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 3))
+
+	err = repo.MarkEventsDispatched(context.Background(), ids)
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
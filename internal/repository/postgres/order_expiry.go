@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+)
+
+// FindStuckOrders возвращает до limit заказов в статусе PROCESSING, загруженных
+// больше days суток назад, старые первыми
+func (r *OrderRepository) FindStuckOrders(ctx context.Context, days, limit int) ([]*domain.Order, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT id, public_id, user_id, number, status, accrual, uploaded_at, metadata 
+		 FROM orders 
+		 WHERE status = $1 AND uploaded_at < NOW() - make_interval(days => $2) 
+		 ORDER BY uploaded_at, id 
+		 LIMIT $3`,
+		domain.OrderStatusProcessing, days, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to find stuck orders: %w", err)
+	}
+	defer rows.Close()
+
+	var orders []*domain.Order
+	for rows.Next() {
+		order := &domain.Order{}
+		if err := rows.Scan(&order.ID, &order.PublicID, &order.UserID, &order.Number, &order.Status, &order.Accrual, &order.UploadedAt, &order.Metadata); err != nil {
+			return nil, fmt.Errorf("repository: failed to scan stuck order: %w", err)
+		}
+		orders = append(orders, order)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repository: error iterating stuck orders: %w", err)
+	}
+
+	return orders, nil
+}
+
+// expireStuckOrdersQuery переводит зависшие заказы в INVALID и записывает события
+// order.expired одним запросом, поэтому уведомление появляется только вместе со сменой статуса.
+// Заказы, заблокированные обработкой, пропускаются до следующего запуска
+const expireStuckOrdersQuery = `
+	WITH stuck AS (
+		SELECT id FROM orders 
+		WHERE status = $1 AND uploaded_at < NOW() - make_interval(days => $2) 
+		ORDER BY uploaded_at, id 
+		LIMIT $3 
+		FOR UPDATE SKIP LOCKED
+	), expired AS (
+		UPDATE orders o SET status = $4 
+		FROM stuck 
+		WHERE o.id = stuck.id 
+		RETURNING o.number, o.user_id, o.metadata
+	), events AS (
+		INSERT INTO order_events (type, order_number, user_id, status, metadata) 
+		SELECT $5, number, user_id, $4, metadata FROM expired
+	)
+	SELECT number FROM expired`
+
+// ExpireStuckOrders переводит до limit зависших заказов (см. FindStuckOrders) в INVALID
+// с событием order.expired и возвращает их номера
+func (r *OrderRepository) ExpireStuckOrders(ctx context.Context, days, limit int) ([]string, error) {
+	rows, err := r.db.Query(ctx, expireStuckOrdersQuery,
+		domain.OrderStatusProcessing, days, limit, domain.OrderStatusInvalid, domain.OrderEventExpired,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to expire stuck orders: %w", err)
+	}
+	defer rows.Close()
+
+	var numbers []string
+	for rows.Next() {
+		var number string
+		if err := rows.Scan(&number); err != nil {
+			return nil, fmt.Errorf("repository: failed to scan expired order: %w", err)
+		}
+		numbers = append(numbers, number)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repository: error iterating expired orders: %w", err)
+	}
+
+	return numbers, nil
+}
@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/google/uuid"
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderRepository_FindStuckOrders(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Found", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+		repo := NewOrderRepository(mock)
+
+		uploadedAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+		mock.ExpectQuery(`SELECT .* FROM orders WHERE status = \$1 AND uploaded_at < NOW\(\) - make_interval`).
+			WithArgs(domain.OrderStatusProcessing, 14, 100).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "public_id", "user_id", "number", "status", "accrual", "uploaded_at", "metadata"}).
+				AddRow(int64(1), uuid.New(), int64(7), "12345678903", domain.OrderStatusProcessing, nil, uploadedAt, nil))
+
+		orders, err := repo.FindStuckOrders(ctx, 14, 100)
+		require.NoError(t, err)
+		require.Len(t, orders, 1)
+		assert.Equal(t, "12345678903", orders[0].Number)
+		assert.Equal(t, int64(7), orders[0].UserID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+		repo := NewOrderRepository(mock)
+
+		mock.ExpectQuery(`SELECT .* FROM orders`).WillReturnError(errors.New("boom"))
+
+		_, err = repo.FindStuckOrders(ctx, 14, 100)
+		assert.Error(t, err)
+	})
+}
+
+func TestOrderRepository_ExpireStuckOrders(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Expired", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+		repo := NewOrderRepository(mock)
+
+		mock.ExpectQuery(`FOR UPDATE SKIP LOCKED.*UPDATE orders o SET status = \$4.*INSERT INTO order_events`).
+			WithArgs(domain.OrderStatusProcessing, 14, 100, domain.OrderStatusInvalid, domain.OrderEventExpired).
+			WillReturnRows(pgxmock.NewRows([]string{"number"}).AddRow("12345678903").AddRow("2377225624"))
+
+		numbers, err := repo.ExpireStuckOrders(ctx, 14, 100)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"12345678903", "2377225624"}, numbers)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+		repo := NewOrderRepository(mock)
+
+		mock.ExpectQuery(`UPDATE orders o SET status`).WillReturnError(errors.New("boom"))
+
+		_, err = repo.ExpireStuckOrders(ctx, 14, 100)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,35 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+)
+
+// GetProcessingStatus считает необработанные заказы и среднее время от загрузки
+// до финального статуса по заказам, обработанным после since. Заказы, завершенные
+// по истечении срока, в среднее не входят: они не отражают работу системы начислений.
+func (r *OrderRepository) GetProcessingStatus(ctx context.Context, since time.Time) (*domain.ProcessingStatus, error) {
+	status := &domain.ProcessingStatus{}
+	var latencySeconds float64
+
+	err := r.db.QueryRow(ctx,
+		`SELECT 
+			(SELECT COUNT(*) FROM orders WHERE status IN ($1, $2)),
+			COUNT(*),
+			COALESCE(AVG(EXTRACT(EPOCH FROM e.created_at - o.uploaded_at)), 0)
+		 FROM order_events e 
+		 JOIN orders o ON o.number = e.order_number 
+		 WHERE e.type IN ($3, $4) AND e.created_at > $5`,
+		domain.OrderStatusNew, domain.OrderStatusProcessing,
+		domain.OrderEventProcessed, domain.OrderEventInvalid, since,
+	).Scan(&status.Backlog, &status.Processed, &latencySeconds)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to get processing status: %w", err)
+	}
+
+	status.AverageLatency = time.Duration(latencySeconds * float64(time.Second))
+	return status, nil
+}
@@ -0,0 +1,49 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderRepository_GetProcessingStatus(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewOrderRepository(mock)
+	ctx := context.Background()
+	since := time.Now().Add(-time.Hour)
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectQuery(`FROM order_events e JOIN orders o`).
+			WithArgs(domain.OrderStatusNew, domain.OrderStatusProcessing, domain.OrderEventProcessed, domain.OrderEventInvalid, since).
+			WillReturnRows(pgxmock.NewRows([]string{"backlog", "processed", "latency"}).AddRow(4, 120, 95.5))
+
+		status, err := repo.GetProcessingStatus(ctx, since)
+		require.NoError(t, err)
+		assert.Equal(t, 4, status.Backlog)
+		assert.Equal(t, 120, status.Processed)
+		assert.Equal(t, 95500*time.Millisecond, status.AverageLatency)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Database error", func(t *testing.T) {
+		mock.ExpectQuery(`FROM order_events`).
+			WithArgs(domain.OrderStatusNew, domain.OrderStatusProcessing, domain.OrderEventProcessed, domain.OrderEventInvalid, since).
+			WillReturnError(errors.New("database error"))
+
+		status, err := repo.GetProcessingStatus(ctx, since)
+		assert.Error(t, err)
+		assert.Nil(t, status)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
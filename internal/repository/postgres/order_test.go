@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/pashagolub/pgxmock/v3"
@@ -21,25 +22,27 @@ func TestOrderRepository_CreateOrder(t *testing.T) {
 
 	repo := NewOrderRepository(mock)
 	ctx := context.Background()
+	columns := []string{"id", "public_id", "user_id", "number", "status", "accrual", "uploaded_at", "metadata", "created"}
 
 	t.Run("Success", func(t *testing.T) {
 		userID := int64(1)
 		number := "12345678903"
 		now := time.Now()
 
-		rows := pgxmock.NewRows([]string{"id", "uploaded_at"}).
-			AddRow(int64(1), now)
+		rows := pgxmock.NewRows(columns).
+			AddRow(int64(1), uuid.New(), userID, number, domain.OrderStatusNew, (*float64)(nil), now, (*domain.OrderMetadata)(nil), true)
 
-		mock.ExpectQuery(`INSERT INTO orders`).
-			WithArgs(userID, number, domain.OrderStatusNew).
+		mock.ExpectQuery(`WITH inserted AS \( INSERT INTO orders .* ON CONFLICT \(number\) DO NOTHING`).
+			WithArgs(userID, number, domain.OrderStatusNew, pgxmock.AnyArg(), (*domain.OrderMetadata)(nil)).
 			WillReturnRows(rows)
 
-		order, err := repo.CreateOrder(ctx, userID, number)
+		order, err := repo.CreateOrder(ctx, userID, number, nil)
 		require.NoError(t, err)
 		assert.Equal(t, int64(1), order.ID)
 		assert.Equal(t, userID, order.UserID)
 		assert.Equal(t, number, order.Number)
 		assert.Equal(t, domain.OrderStatusNew, order.Status)
+		assert.Equal(t, now, order.UploadedAt)
 
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
@@ -48,29 +51,18 @@ func TestOrderRepository_CreateOrder(t *testing.T) {
 		userID := int64(1)
 		number := "12345678903"
 
-		mock.ExpectQuery(`INSERT INTO orders`).
-			WithArgs(userID, number, domain.OrderStatusNew).
-			WillReturnError(&pgconn.PgError{Code: "23505"})
+		rows := pgxmock.NewRows(columns).
+			AddRow(int64(1), uuid.New(), userID, number, domain.OrderStatusProcessing, (*float64)(nil), time.Now(), (*domain.OrderMetadata)(nil), false)
 
-		// Мокируем GetOrderByNumber
-		existingOrder := &domain.Order{
-			ID:         1,
-			UserID:     userID,
-			Number:     number,
-			Status:     domain.OrderStatusProcessing,
-			UploadedAt: time.Now(),
-		}
-
-		rows := pgxmock.NewRows([]string{"id", "user_id", "number", "status", "accrual", "uploaded_at"}).
-			AddRow(existingOrder.ID, existingOrder.UserID, existingOrder.Number, existingOrder.Status, existingOrder.Accrual, existingOrder.UploadedAt)
-
-		mock.ExpectQuery(`SELECT id, user_id, number, status, accrual, uploaded_at FROM orders WHERE number`).
-			WithArgs(number).
+		mock.ExpectQuery(`WITH inserted AS`).
+			WithArgs(userID, number, domain.OrderStatusNew, pgxmock.AnyArg(), (*domain.OrderMetadata)(nil)).
 			WillReturnRows(rows)
 
-		order, err := repo.CreateOrder(ctx, userID, number)
-		assert.ErrorIs(t, err, ErrOrderExists)
-		assert.Equal(t, existingOrder.ID, order.ID)
+		order, err := repo.CreateOrder(ctx, userID, number, nil)
+		assert.ErrorIs(t, err, domain.ErrOrderExists)
+		require.NotNil(t, order)
+		assert.Equal(t, int64(1), order.ID)
+		assert.Equal(t, domain.OrderStatusProcessing, order.Status)
 
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
@@ -80,28 +72,83 @@ func TestOrderRepository_CreateOrder(t *testing.T) {
 		otherUserID := int64(2)
 		number := "12345678903"
 
-		mock.ExpectQuery(`INSERT INTO orders`).
-			WithArgs(userID, number, domain.OrderStatusNew).
-			WillReturnError(&pgconn.PgError{Code: "23505"})
+		rows := pgxmock.NewRows(columns).
+			AddRow(int64(1), uuid.New(), otherUserID, number, domain.OrderStatusProcessing, (*float64)(nil), time.Now(), (*domain.OrderMetadata)(nil), false)
 
-		// Мокируем GetOrderByNumber - заказ принадлежит другому пользователю
-		existingOrder := &domain.Order{
-			ID:         1,
-			UserID:     otherUserID,
-			Number:     number,
-			Status:     domain.OrderStatusProcessing,
-			UploadedAt: time.Now(),
+		mock.ExpectQuery(`WITH inserted AS`).
+			WithArgs(userID, number, domain.OrderStatusNew, pgxmock.AnyArg(), (*domain.OrderMetadata)(nil)).
+			WillReturnRows(rows)
+
+		order, err := repo.CreateOrder(ctx, userID, number, nil)
+		assert.ErrorIs(t, err, domain.ErrOrderOwnedByAnother)
+		assert.Nil(t, order)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Concurrent insert not yet visible - retried", func(t *testing.T) {
+		userID := int64(1)
+		number := "12345678903"
+
+		// Первый запрос не видит ни вставки, ни конкурентной строки
+		mock.ExpectQuery(`WITH inserted AS`).
+			WithArgs(userID, number, domain.OrderStatusNew, pgxmock.AnyArg(), (*domain.OrderMetadata)(nil)).
+			WillReturnRows(pgxmock.NewRows(columns))
+		mock.ExpectQuery(`WITH inserted AS`).
+			WithArgs(userID, number, domain.OrderStatusNew, pgxmock.AnyArg(), (*domain.OrderMetadata)(nil)).
+			WillReturnRows(pgxmock.NewRows(columns).
+				AddRow(int64(5), uuid.New(), userID, number, domain.OrderStatusNew, (*float64)(nil), time.Now(), (*domain.OrderMetadata)(nil), false))
+
+		order, err := repo.CreateOrder(ctx, userID, number, nil)
+		assert.ErrorIs(t, err, domain.ErrOrderExists)
+		require.NotNil(t, order)
+		assert.Equal(t, int64(5), order.ID)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Conflicting row never visible", func(t *testing.T) {
+		userID := int64(1)
+		number := "12345678903"
+
+		for i := 0; i < createOrderMaxAttempts; i++ {
+			mock.ExpectQuery(`WITH inserted AS`).
+				WithArgs(userID, number, domain.OrderStatusNew, pgxmock.AnyArg(), (*domain.OrderMetadata)(nil)).
+				WillReturnRows(pgxmock.NewRows(columns))
 		}
 
-		rows := pgxmock.NewRows([]string{"id", "user_id", "number", "status", "accrual", "uploaded_at"}).
-			AddRow(existingOrder.ID, existingOrder.UserID, existingOrder.Number, existingOrder.Status, existingOrder.Accrual, existingOrder.UploadedAt)
+		order, err := repo.CreateOrder(ctx, userID, number, nil)
+		assert.Error(t, err)
+		assert.Nil(t, order)
 
-		mock.ExpectQuery(`SELECT id, user_id, number, status, accrual, uploaded_at FROM orders WHERE number`).
-			WithArgs(number).
-			WillReturnRows(rows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
 
-		order, err := repo.CreateOrder(ctx, userID, number)
-		assert.ErrorIs(t, err, ErrOrderOwnedByAnother)
+	t.Run("Unknown user", func(t *testing.T) {
+		userID := int64(42)
+		number := "12345678903"
+
+		mock.ExpectQuery(`WITH inserted AS`).
+			WithArgs(userID, number, domain.OrderStatusNew, pgxmock.AnyArg(), (*domain.OrderMetadata)(nil)).
+			WillReturnError(&pgconn.PgError{Code: "23503"})
+
+		order, err := repo.CreateOrder(ctx, userID, number, nil)
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+		assert.Nil(t, order)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Database error", func(t *testing.T) {
+		userID := int64(1)
+		number := "12345678903"
+
+		mock.ExpectQuery(`WITH inserted AS`).
+			WithArgs(userID, number, domain.OrderStatusNew, pgxmock.AnyArg(), (*domain.OrderMetadata)(nil)).
+			WillReturnError(errors.New("connection reset"))
+
+		order, err := repo.CreateOrder(ctx, userID, number, nil)
+		assert.Error(t, err)
 		assert.Nil(t, order)
 
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -128,10 +175,10 @@ func TestOrderRepository_GetOrderByNumber(t *testing.T) {
 			UploadedAt: time.Now(),
 		}
 
-		rows := pgxmock.NewRows([]string{"id", "user_id", "number", "status", "accrual", "uploaded_at"}).
-			AddRow(expectedOrder.ID, expectedOrder.UserID, expectedOrder.Number, expectedOrder.Status, expectedOrder.Accrual, expectedOrder.UploadedAt)
+		rows := pgxmock.NewRows([]string{"id", "public_id", "user_id", "number", "status", "accrual", "uploaded_at", "metadata"}).
+			AddRow(expectedOrder.ID, uuid.New(), expectedOrder.UserID, expectedOrder.Number, expectedOrder.Status, expectedOrder.Accrual, expectedOrder.UploadedAt, nil)
 
-		mock.ExpectQuery(`SELECT id, user_id, number, status, accrual, uploaded_at FROM orders WHERE number`).
+		mock.ExpectQuery(`SELECT id, public_id, user_id, number, status, accrual, uploaded_at, metadata FROM orders WHERE number`).
 			WithArgs(number).
 			WillReturnRows(rows)
 
@@ -147,12 +194,67 @@ func TestOrderRepository_GetOrderByNumber(t *testing.T) {
 	t.Run("Order not found", func(t *testing.T) {
 		number := "99999999999"
 
-		mock.ExpectQuery(`SELECT id, user_id, number, status, accrual, uploaded_at FROM orders WHERE number`).
+		mock.ExpectQuery(`SELECT id, public_id, user_id, number, status, accrual, uploaded_at, metadata FROM orders WHERE number`).
 			WithArgs(number).
 			WillReturnError(pgx.ErrNoRows)
 
 		order, err := repo.GetOrderByNumber(ctx, number)
-		assert.ErrorIs(t, err, ErrOrderNotFound)
+		assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+		assert.Nil(t, order)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestOrderRepository_GetOrderByPublicID(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewOrderRepository(mock)
+	ctx := context.Background()
+	columns := []string{"id", "public_id", "user_id", "number", "status", "accrual", "uploaded_at", "metadata"}
+
+	t.Run("Success", func(t *testing.T) {
+		publicID := uuid.New()
+
+		mock.ExpectQuery(`SELECT id, public_id, user_id, number, status, accrual, uploaded_at, metadata FROM orders WHERE public_id = \$1 AND user_id = \$2`).
+			WithArgs(publicID, int64(1)).
+			WillReturnRows(pgxmock.NewRows(columns).
+				AddRow(int64(1), publicID, int64(1), "12345678903", domain.OrderStatusNew, nil, time.Now(), nil))
+
+		order, err := repo.GetOrderByPublicID(ctx, 1, publicID)
+		require.NoError(t, err)
+		assert.Equal(t, publicID, order.PublicID)
+		assert.Equal(t, "12345678903", order.Number)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Not found or owned by another user", func(t *testing.T) {
+		publicID := uuid.New()
+
+		mock.ExpectQuery(`FROM orders WHERE public_id`).
+			WithArgs(publicID, int64(2)).
+			WillReturnError(pgx.ErrNoRows)
+
+		order, err := repo.GetOrderByPublicID(ctx, 2, publicID)
+		assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+		assert.Nil(t, order)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Database error", func(t *testing.T) {
+		publicID := uuid.New()
+
+		mock.ExpectQuery(`FROM orders WHERE public_id`).
+			WithArgs(publicID, int64(1)).
+			WillReturnError(errors.New("connection reset"))
+
+		order, err := repo.GetOrderByPublicID(ctx, 1, publicID)
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, domain.ErrOrderNotFound)
 		assert.Nil(t, order)
 
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -171,32 +273,50 @@ func TestOrderRepository_GetOrdersByUserID(t *testing.T) {
 		userID := int64(1)
 		accrual := 100.0
 
-		rows := pgxmock.NewRows([]string{"id", "user_id", "number", "status", "accrual", "uploaded_at"}).
-			AddRow(int64(1), userID, "111", domain.OrderStatusProcessed, &accrual, time.Now()).
-			AddRow(int64(2), userID, "222", domain.OrderStatusProcessing, nil, time.Now()).
-			AddRow(int64(3), userID, "333", domain.OrderStatusNew, nil, time.Now())
+		rows := pgxmock.NewRows([]string{"id", "public_id", "user_id", "number", "status", "accrual", "uploaded_at", "metadata"}).
+			AddRow(int64(1), uuid.New(), userID, "111", domain.OrderStatusProcessed, &accrual, time.Now(), nil).
+			AddRow(int64(2), uuid.New(), userID, "222", domain.OrderStatusProcessing, nil, time.Now(), nil).
+			AddRow(int64(3), uuid.New(), userID, "333", domain.OrderStatusNew, nil, time.Now(), nil)
 
-		mock.ExpectQuery(`SELECT id, user_id, number, status, accrual, uploaded_at FROM orders WHERE user_id`).
+		mock.ExpectQuery(`SELECT id, public_id, user_id, number, status, accrual, uploaded_at, metadata FROM orders WHERE user_id`).
 			WithArgs(userID).
 			WillReturnRows(rows)
 
-		orders, err := repo.GetOrdersByUserID(ctx, userID)
+		orders, err := repo.GetOrdersByUserID(ctx, userID, domain.OrderFilter{})
 		require.NoError(t, err)
 		assert.Len(t, orders, 3)
 
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
+	t.Run("Filter and page", func(t *testing.T) {
+		from := time.Date(2024, 1, 1, 3, 0, 0, 0, time.FixedZone("MSK", 3*60*60))
+		to := from.AddDate(0, 1, 0)
+
+		// Время сравнивается в UTC, как оно хранится
+		mock.ExpectQuery(`FROM orders WHERE user_id = \$1 AND status = \$2 AND uploaded_at >= \$3 AND uploaded_at < \$4 ORDER BY uploaded_at DESC, id DESC LIMIT \$5 OFFSET \$6`).
+			WithArgs(int64(1), domain.OrderStatusProcessed, from.UTC(), to.UTC(), 11, 20).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "public_id", "user_id", "number", "status", "accrual", "uploaded_at", "metadata"}))
+
+		orders, err := repo.GetOrdersByUserID(ctx, 1, domain.OrderFilter{
+			Status: domain.OrderStatusProcessed, From: from, To: to, Limit: 11, Offset: 20,
+		})
+		require.NoError(t, err)
+		assert.Empty(t, orders)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
 	t.Run("Success - no orders", func(t *testing.T) {
 		userID := int64(999)
 
-		rows := pgxmock.NewRows([]string{"id", "user_id", "number", "status", "accrual", "uploaded_at"})
+		rows := pgxmock.NewRows([]string{"id", "public_id", "user_id", "number", "status", "accrual", "uploaded_at", "metadata"})
 
-		mock.ExpectQuery(`SELECT id, user_id, number, status, accrual, uploaded_at FROM orders WHERE user_id`).
+		mock.ExpectQuery(`SELECT id, public_id, user_id, number, status, accrual, uploaded_at, metadata FROM orders WHERE user_id`).
 			WithArgs(userID).
 			WillReturnRows(rows)
 
-		orders, err := repo.GetOrdersByUserID(ctx, userID)
+		orders, err := repo.GetOrdersByUserID(ctx, userID, domain.OrderFilter{})
 		require.NoError(t, err)
 		assert.Empty(t, orders)
 
@@ -206,11 +326,11 @@ func TestOrderRepository_GetOrdersByUserID(t *testing.T) {
 	t.Run("Database error", func(t *testing.T) {
 		userID := int64(1)
 
-		mock.ExpectQuery(`SELECT id, user_id, number, status, accrual, uploaded_at FROM orders WHERE user_id`).
+		mock.ExpectQuery(`SELECT id, public_id, user_id, number, status, accrual, uploaded_at, metadata FROM orders WHERE user_id`).
 			WithArgs(userID).
 			WillReturnError(errors.New("database error"))
 
-		orders, err := repo.GetOrdersByUserID(ctx, userID)
+		orders, err := repo.GetOrdersByUserID(ctx, userID, domain.OrderFilter{})
 		assert.Error(t, err)
 		assert.Nil(t, orders)
 
@@ -218,41 +338,383 @@ func TestOrderRepository_GetOrdersByUserID(t *testing.T) {
 	})
 }
 
-func TestOrderRepository_UpdateOrderStatus(t *testing.T) {
+func TestOrderRepository_GetOrdersByNumbers(t *testing.T) {
 	mock, err := pgxmock.NewPool()
 	require.NoError(t, err)
 	defer mock.Close()
 
 	repo := NewOrderRepository(mock)
 	ctx := context.Background()
+	numbers := []string{"111", "222", "999"}
 
 	t.Run("Success", func(t *testing.T) {
-		number := "12345678903"
-		status := domain.OrderStatusProcessed
 		accrual := 100.0
+		rows := pgxmock.NewRows([]string{"id", "public_id", "user_id", "number", "status", "accrual", "uploaded_at", "metadata"}).
+			AddRow(int64(1), uuid.New(), int64(1), "111", domain.OrderStatusProcessed, &accrual, time.Now(), nil).
+			AddRow(int64(2), uuid.New(), int64(2), "222", domain.OrderStatusProcessing, nil, time.Now(), nil)
+
+		mock.ExpectQuery(`SELECT .+ FROM orders WHERE number = ANY\(\$1\)`).
+			WithArgs(numbers).
+			WillReturnRows(rows)
+
+		orders, err := repo.GetOrdersByNumbers(ctx, numbers)
+		require.NoError(t, err)
+		require.Len(t, orders, 2)
+		assert.Equal(t, "111", orders[0].Number)
+		assert.Equal(t, 100.0, *orders[0].Accrual)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Database error", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT .+ FROM orders WHERE number = ANY`).
+			WithArgs(numbers).
+			WillReturnError(errors.New("database error"))
+
+		orders, err := repo.GetOrdersByNumbers(ctx, numbers)
+		assert.Error(t, err)
+		assert.Nil(t, orders)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestOrderRepository_SearchOrders(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewOrderRepository(mock)
+	ctx := context.Background()
+	columns := []string{"id", "public_id", "user_id", "number", "status", "accrual", "uploaded_at", "metadata", "login"}
+
+	t.Run("All filters", func(t *testing.T) {
+		metadata := domain.OrderMetadata{StoreID: "42"}
+		mock.ExpectQuery(`SELECT .* FROM orders o JOIN users u ON u.id = o.user_id `+
+			`WHERE o.number LIKE \$1 AND u.login = \$2 AND o.status = \$3 AND o.metadata @> \$4 `+
+			`ORDER BY o.uploaded_at DESC, o.id DESC LIMIT \$5 OFFSET \$6`).
+			WithArgs("9278%", "alice", domain.OrderStatusProcessed, metadata, 11, 20).
+			WillReturnRows(pgxmock.NewRows(columns).
+				AddRow(int64(1), uuid.New(), int64(7), "9278923470", domain.OrderStatusProcessed, nil, time.Now(), &metadata, "alice"))
+
+		orders, err := repo.SearchOrders(ctx, domain.OrderSearchFilter{
+			NumberPrefix: "9278",
+			Login:        "alice",
+			Status:       domain.OrderStatusProcessed,
+			Metadata:     metadata,
+			Limit:        11,
+			Offset:       20,
+		})
+		require.NoError(t, err)
+		require.Len(t, orders, 1)
+		assert.Equal(t, "alice", orders[0].Login)
+		assert.Equal(t, "9278923470", orders[0].Number)
+		assert.Equal(t, &metadata, orders[0].Metadata)
 
-		mock.ExpectExec(`UPDATE orders SET status`).
-			WithArgs(status, &accrual, number).
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("No filters", func(t *testing.T) {
+		mock.ExpectQuery(`FROM orders o JOIN users u ON u.id = o.user_id ORDER BY o.uploaded_at DESC, o.id DESC LIMIT \$1 OFFSET \$2`).
+			WithArgs(51, 0).
+			WillReturnRows(pgxmock.NewRows(columns))
+
+		orders, err := repo.SearchOrders(ctx, domain.OrderSearchFilter{Limit: 51})
+		require.NoError(t, err)
+		assert.Empty(t, orders)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Database error", func(t *testing.T) {
+		mock.ExpectQuery(`FROM orders o`).
+			WithArgs("alice", 51, 0).
+			WillReturnError(errors.New("connection reset"))
+
+		orders, err := repo.SearchOrders(ctx, domain.OrderSearchFilter{Login: "alice", Limit: 51})
+		assert.Error(t, err)
+		assert.Nil(t, orders)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestOrderRepository_FindAccrualMismatches(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewOrderRepository(mock)
+	ctx := context.Background()
+	columns := []string{"number", "user_id", "login", "status", "accrual", "amount"}
+
+	t.Run("Success", func(t *testing.T) {
+		orderAccrual, ledgerAccrual := 150.0, 100.0
+		mock.ExpectQuery(`FROM orders o JOIN users u ON u.id = o.user_id LEFT JOIN \( SELECT order_number, SUM\(amount\) AS amount FROM transactions WHERE type IN \(\$1, \$2\)`).
+			WithArgs(domain.TransactionTypeAccrual, domain.TransactionTypeAdjustment, domain.OrderStatusProcessed, 51, 0).
+			WillReturnRows(pgxmock.NewRows(columns).
+				AddRow("12345678903", int64(7), "alice", domain.OrderStatusProcessed, &orderAccrual, &ledgerAccrual).
+				AddRow("9278923470", int64(8), "bob", domain.OrderStatusProcessed, &orderAccrual, (*float64)(nil)))
+
+		mismatches, err := repo.FindAccrualMismatches(ctx, 51, 0)
+		require.NoError(t, err)
+		require.Len(t, mismatches, 2)
+		assert.Equal(t, &domain.AccrualMismatch{
+			OrderNumber:   "12345678903",
+			UserID:        7,
+			Login:         "alice",
+			Status:        domain.OrderStatusProcessed,
+			OrderAccrual:  &orderAccrual,
+			LedgerAccrual: &ledgerAccrual,
+		}, mismatches[0])
+		assert.Nil(t, mismatches[1].LedgerAccrual)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Database error", func(t *testing.T) {
+		mock.ExpectQuery(`FROM orders o`).
+			WithArgs(domain.TransactionTypeAccrual, domain.TransactionTypeAdjustment, domain.OrderStatusProcessed, 51, 0).
+			WillReturnError(errors.New("connection reset"))
+
+		mismatches, err := repo.FindAccrualMismatches(ctx, 51, 0)
+		assert.Error(t, err)
+		assert.Nil(t, mismatches)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestOrderRepository_CorrectAccrual(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewOrderRepository(mock)
+	ctx := context.Background()
+	number := "12345678903"
+
+	expectOrder := func(status domain.OrderStatus) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT user_id, status FROM orders WHERE number = \$1 FOR UPDATE`).
+			WithArgs(number).
+			WillReturnRows(pgxmock.NewRows([]string{"user_id", "status"}).AddRow(int64(7), status))
+	}
+	expectRecorded := func(amount float64) {
+		mock.ExpectExec(`INSERT INTO user_balances \(user_id\) VALUES \(\$1\) ON CONFLICT \(user_id\) DO UPDATE SET updated_at = user_balances.updated_at`).
+			WithArgs(int64(7)).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectQuery(`SELECT COALESCE\(SUM\(amount\), 0\) FROM transactions WHERE order_number = \$1 AND type IN \(\$2, \$3\)`).
+			WithArgs(number, domain.TransactionTypeAccrual, domain.TransactionTypeAdjustment).
+			WillReturnRows(pgxmock.NewRows([]string{"sum"}).AddRow(amount))
+	}
+
+	t.Run("Lower accrual creates negative adjustment", func(t *testing.T) {
+		accrual := domain.DefaultRoundingPolicy().RoundAccrual(70.254)
+		createdAt := time.Now()
+		adminID := int64(1)
+
+		expectOrder(domain.OrderStatusProcessed)
+		expectRecorded(100.1)
+		mock.ExpectQuery(`INSERT INTO transactions .* RETURNING id`).
+			WithArgs(int64(7), number, -29.85, domain.TransactionTypeAdjustment, pgxmock.AnyArg(), 70.254, "round:2").
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(int64(42)))
+		mock.ExpectExec(`UPDATE orders SET accrual = \$1 WHERE number = \$2`).
+			WithArgs(70.25, number).
 			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectQuery(`INSERT INTO accrual_corrections`).
+			WithArgs(number, int64(7), int64(42), 100.1, 70.25, 70.254, "round:2", &adminID).
+			WillReturnRows(pgxmock.NewRows([]string{"created_at"}).AddRow(createdAt))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
 
-		err := repo.UpdateOrderStatus(ctx, number, status, &accrual)
-		assert.NoError(t, err)
+		correction, err := repo.CorrectAccrual(ctx, number, accrual, adminID)
+		require.NoError(t, err)
+		assert.Equal(t, &domain.AccrualCorrection{
+			OrderNumber:    number,
+			UserID:         7,
+			PreviousAmount: 100.1,
+			NewAmount:      70.25,
+			Delta:          -29.85,
+			CorrectedBy:    adminID,
+			CreatedAt:      createdAt,
+		}, correction)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Correction by worker has no initiator", func(t *testing.T) {
+		accrual := domain.DefaultRoundingPolicy().RoundAccrual(150)
+
+		expectOrder(domain.OrderStatusProcessed)
+		expectRecorded(100)
+		mock.ExpectQuery(`INSERT INTO transactions`).
+			WithArgs(int64(7), number, 50.0, domain.TransactionTypeAdjustment, pgxmock.AnyArg(), 150.0, "round:2").
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(int64(43)))
+		mock.ExpectExec(`UPDATE orders`).
+			WithArgs(150.0, number).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectQuery(`INSERT INTO accrual_corrections`).
+			WithArgs(number, int64(7), int64(43), 100.0, 150.0, 150.0, "round:2", (*int64)(nil)).
+			WillReturnRows(pgxmock.NewRows([]string{"created_at"}).AddRow(time.Now()))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
 
+		correction, err := repo.CorrectAccrual(ctx, number, accrual, 0)
+		require.NoError(t, err)
+		assert.Equal(t, 50.0, correction.Delta)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Unchanged accrual", func(t *testing.T) {
+		expectOrder(domain.OrderStatusProcessed)
+		expectRecorded(100.1)
+		mock.ExpectRollback()
+
+		_, err := repo.CorrectAccrual(ctx, number, domain.DefaultRoundingPolicy().RoundAccrual(100.1), 0)
+		assert.ErrorIs(t, err, domain.ErrAccrualUnchanged)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Order not processed", func(t *testing.T) {
+		expectOrder(domain.OrderStatusProcessing)
+		mock.ExpectRollback()
+
+		_, err := repo.CorrectAccrual(ctx, number, domain.DefaultRoundingPolicy().RoundAccrual(100), 0)
+		assert.ErrorIs(t, err, domain.ErrOrderNotProcessed)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("Order not found", func(t *testing.T) {
-		number := "99999999999"
-		status := domain.OrderStatusProcessed
-		accrual := 100.0
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT user_id, status FROM orders`).
+			WithArgs(number).
+			WillReturnError(pgx.ErrNoRows)
+		mock.ExpectRollback()
 
-		mock.ExpectExec(`UPDATE orders SET status`).
-			WithArgs(status, &accrual, number).
-			WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+		_, err := repo.CorrectAccrual(ctx, number, domain.DefaultRoundingPolicy().RoundAccrual(100), 0)
+		assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
 
-		err := repo.UpdateOrderStatus(ctx, number, status, &accrual)
-		assert.ErrorIs(t, err, ErrOrderNotFound)
+func TestOrderRepository_TransferOrder(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
 
+	repo := NewOrderRepository(mock)
+	ctx := context.Background()
+	number := "12345678903"
+
+	expectOwners := func(fromUserID int64) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT id FROM users WHERE login = \$1`).
+			WithArgs("bob").
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(int64(8)))
+		mock.ExpectQuery(`SELECT o.user_id, u.login FROM orders o JOIN users u ON u.id = o.user_id WHERE o.number = \$1 FOR UPDATE OF o`).
+			WithArgs(number).
+			WillReturnRows(pgxmock.NewRows([]string{"user_id", "login"}).AddRow(fromUserID, "alice"))
+	}
+	expectRecorded := func(amount float64) {
+		// Пользователи блокируются в порядке возрастания ID
+		mock.ExpectExec(`INSERT INTO user_balances \(user_id\) VALUES \(\$1\) ON CONFLICT \(user_id\) DO UPDATE SET updated_at = user_balances.updated_at`).
+			WithArgs(int64(8)).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectExec(`INSERT INTO user_balances \(user_id\) VALUES \(\$1\) ON CONFLICT \(user_id\) DO UPDATE SET updated_at = user_balances.updated_at`).
+			WithArgs(int64(9)).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectQuery(`SELECT COALESCE\(SUM\(amount\), 0\) FROM transactions WHERE order_number = \$1 AND user_id = \$2 AND type IN \(\$3, \$4\)`).
+			WithArgs(number, int64(9), domain.TransactionTypeAccrual, domain.TransactionTypeAdjustment).
+			WillReturnRows(pgxmock.NewRows([]string{"sum"}).AddRow(amount))
+	}
+
+	t.Run("Accrual is moved by adjustments", func(t *testing.T) {
+		createdAt := time.Now()
+		fromTransactionID, toTransactionID := int64(42), int64(43)
+
+		expectOwners(9)
+		expectRecorded(500)
+		mock.ExpectQuery(`INSERT INTO transactions .* RETURNING id`).
+			WithArgs(int64(9), number, -500.0, domain.TransactionTypeAdjustment, pgxmock.AnyArg()).
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(fromTransactionID))
+		mock.ExpectQuery(`INSERT INTO transactions .* RETURNING id`).
+			WithArgs(int64(8), number, 500.0, domain.TransactionTypeAdjustment, pgxmock.AnyArg()).
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(toTransactionID))
+		mock.ExpectExec(`UPDATE orders SET user_id = \$1 WHERE number = \$2`).
+			WithArgs(int64(8), number).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectQuery(`INSERT INTO order_transfers`).
+			WithArgs(number, int64(9), int64(8), 500.0, &fromTransactionID, &toTransactionID, int64(1)).
+			WillReturnRows(pgxmock.NewRows([]string{"created_at"}).AddRow(createdAt))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		transfer, err := repo.TransferOrder(ctx, number, "bob", 1)
+		require.NoError(t, err)
+		assert.Equal(t, &domain.OrderTransfer{
+			OrderNumber:   number,
+			FromUserID:    9,
+			FromLogin:     "alice",
+			ToUserID:      8,
+			ToLogin:       "bob",
+			Amount:        500,
+			TransferredBy: 1,
+			CreatedAt:     createdAt,
+		}, transfer)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Order without accrual has no transactions", func(t *testing.T) {
+		expectOwners(9)
+		expectRecorded(0)
+		mock.ExpectExec(`UPDATE orders SET user_id`).
+			WithArgs(int64(8), number).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectQuery(`INSERT INTO order_transfers`).
+			WithArgs(number, int64(9), int64(8), 0.0, (*int64)(nil), (*int64)(nil), int64(1)).
+			WillReturnRows(pgxmock.NewRows([]string{"created_at"}).AddRow(time.Now()))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		transfer, err := repo.TransferOrder(ctx, number, "bob", 1)
+		require.NoError(t, err)
+		assert.Zero(t, transfer.Amount)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Order already owned", func(t *testing.T) {
+		expectOwners(8)
+		mock.ExpectRollback()
+
+		_, err := repo.TransferOrder(ctx, number, "bob", 1)
+		assert.ErrorIs(t, err, domain.ErrOrderAlreadyOwned)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("User not found", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT id FROM users`).
+			WithArgs("bob").
+			WillReturnError(pgx.ErrNoRows)
+		mock.ExpectRollback()
+
+		_, err := repo.TransferOrder(ctx, number, "bob", 1)
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Order not found", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT id FROM users`).
+			WithArgs("bob").
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(int64(8)))
+		mock.ExpectQuery(`SELECT o.user_id, u.login FROM orders`).
+			WithArgs(number).
+			WillReturnError(pgx.ErrNoRows)
+		mock.ExpectRollback()
+
+		_, err := repo.TransferOrder(ctx, number, "bob", 1)
+		assert.ErrorIs(t, err, domain.ErrOrderNotFound)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 }
@@ -266,18 +728,210 @@ func TestOrderRepository_GetPendingOrders(t *testing.T) {
 	ctx := context.Background()
 
 	t.Run("Success", func(t *testing.T) {
-		rows := pgxmock.NewRows([]string{"id", "user_id", "number", "status", "accrual", "uploaded_at"}).
-			AddRow(int64(1), int64(1), "111", domain.OrderStatusNew, nil, time.Now()).
-			AddRow(int64(2), int64(2), "222", domain.OrderStatusProcessing, nil, time.Now())
+		rows := pgxmock.NewRows([]string{"id", "public_id", "user_id", "number", "status", "accrual", "uploaded_at", "metadata"}).
+			AddRow(int64(1), uuid.New(), int64(1), "111", domain.OrderStatusNew, nil, time.Now(), nil).
+			AddRow(int64(2), uuid.New(), int64(2), "222", domain.OrderStatusProcessing, nil, time.Now(), nil)
 
-		mock.ExpectQuery(`SELECT id, user_id, number, status, accrual, uploaded_at FROM orders WHERE status IN`).
-			WithArgs(domain.OrderStatusNew, domain.OrderStatusProcessing).
+		mock.ExpectQuery(`SELECT o.id, .* FROM orders o JOIN unnest\(\$1::varchar\[\], \$2::bigint\[\]\) AS p\(status, max_age\) ON o.status = p.status WHERE p.max_age = 0 OR o.uploaded_at >= NOW\(\) - make_interval`).
+			WithArgs([]string{"NEW", "PROCESSING"}, []int64{0, 259200}).
 			WillReturnRows(rows)
 
-		orders, err := repo.GetPendingOrders(ctx)
+		orders, err := repo.GetPendingOrders(ctx, []domain.OrderScanPolicy{
+			{Status: domain.OrderStatusNew},
+			{Status: domain.OrderStatusProcessing, Interval: time.Minute, MaxAge: 72 * time.Hour},
+		})
 		require.NoError(t, err)
 		assert.Len(t, orders, 2)
 
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
+
+	t.Run("No policies", func(t *testing.T) {
+		orders, err := repo.GetPendingOrders(ctx, nil)
+		require.NoError(t, err)
+		assert.Empty(t, orders)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestOrderRepository_GetPendingAccrual(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewOrderRepository(mock)
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT COUNT\(\*\), COALESCE\(SUM\(accrual\), 0\) FROM orders WHERE user_id = \$1 AND status IN`).
+			WithArgs(int64(1), domain.OrderStatusNew, domain.OrderStatusProcessing).
+			WillReturnRows(pgxmock.NewRows([]string{"count", "sum"}).AddRow(3, 150.5))
+
+		pending, err := repo.GetPendingAccrual(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, &domain.PendingAccrual{Orders: 3, Accrual: 150.5}, pending)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Database error", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT COUNT`).
+			WithArgs(int64(1), domain.OrderStatusNew, domain.OrderStatusProcessing).
+			WillReturnError(errors.New("db error"))
+
+		pending, err := repo.GetPendingAccrual(ctx, 1)
+		assert.Error(t, err)
+		assert.Nil(t, pending)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestOrderRepository_ApplyAccrual(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewOrderRepository(mock)
+	ctx := context.Background()
+	number := "12345678903"
+
+	lockRows := func(status domain.OrderStatus, metadata *domain.OrderMetadata) *pgxmock.Rows {
+		return pgxmock.NewRows([]string{"user_id", "status", "metadata"}).AddRow(int64(7), status, metadata)
+	}
+
+	t.Run("Processed with accrual", func(t *testing.T) {
+		accrual := domain.RoundingPolicy{Mode: domain.RoundingFloor, Precision: 2}.RoundAccrual(100.129)
+		amount := 100.12
+		metadata := &domain.OrderMetadata{Channel: "pos", StoreID: "42"}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT user_id, status, metadata FROM orders WHERE number = \$1 FOR UPDATE`).
+			WithArgs(number).
+			WillReturnRows(lockRows(domain.OrderStatusProcessing, metadata))
+		mock.ExpectExec(`UPDATE orders SET status = \$1, accrual = \$2 WHERE number = \$3`).
+			WithArgs(domain.OrderStatusProcessed, &amount, number).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectExec(`INSERT INTO transactions .* ON CONFLICT \(order_number\) WHERE type = 'accrual' DO NOTHING`).
+			WithArgs(int64(7), number, amount, domain.TransactionTypeAccrual, pgxmock.AnyArg(), 100.129, "floor:2").
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectExec(`INSERT INTO order_events`).
+			WithArgs(domain.OrderEventProcessed, number, int64(7), domain.OrderStatusProcessed, &amount, metadata).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		err := repo.ApplyAccrual(ctx, number, domain.OrderStatusProcessed, accrual)
+		require.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Invalid without accrual", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`FOR UPDATE`).
+			WithArgs(number).
+			WillReturnRows(lockRows(domain.OrderStatusNew, nil))
+		mock.ExpectExec(`UPDATE orders`).
+			WithArgs(domain.OrderStatusInvalid, (*float64)(nil), number).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectExec(`INSERT INTO order_events`).
+			WithArgs(domain.OrderEventInvalid, number, int64(7), domain.OrderStatusInvalid, (*float64)(nil), (*domain.OrderMetadata)(nil)).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		err := repo.ApplyAccrual(ctx, number, domain.OrderStatusInvalid, nil)
+		require.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Intermediate status - no event", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`FOR UPDATE`).
+			WithArgs(number).
+			WillReturnRows(lockRows(domain.OrderStatusNew, nil))
+		mock.ExpectExec(`UPDATE orders SET status = \$1 WHERE number = \$2`).
+			WithArgs(domain.OrderStatusProcessing, number).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		err := repo.ApplyAccrual(ctx, number, domain.OrderStatusProcessing, nil)
+		require.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Unchanged intermediate status - no write", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`FOR UPDATE`).
+			WithArgs(number).
+			WillReturnRows(lockRows(domain.OrderStatusProcessing, nil))
+		mock.ExpectRollback()
+
+		err := repo.ApplyAccrual(ctx, number, domain.OrderStatusProcessing, nil)
+		require.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Already processed - duplicate accrual", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`FOR UPDATE`).
+			WithArgs(number).
+			WillReturnRows(lockRows(domain.OrderStatusProcessed, nil))
+		mock.ExpectRollback()
+
+		err := repo.ApplyAccrual(ctx, number, domain.OrderStatusProcessed, domain.DefaultRoundingPolicy().RoundAccrual(100))
+		assert.ErrorIs(t, err, domain.ErrDuplicateAccrual)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Final status is not overwritten", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`FOR UPDATE`).
+			WithArgs(number).
+			WillReturnRows(lockRows(domain.OrderStatusProcessed, nil))
+		mock.ExpectRollback()
+
+		err := repo.ApplyAccrual(ctx, number, domain.OrderStatusProcessing, nil)
+		assert.ErrorIs(t, err, domain.ErrOrderAlreadyFinal)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Accrual inserted concurrently - no event", func(t *testing.T) {
+		accrual := domain.DefaultRoundingPolicy().RoundAccrual(100)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`FOR UPDATE`).
+			WithArgs(number).
+			WillReturnRows(lockRows(domain.OrderStatusProcessing, nil))
+		mock.ExpectExec(`UPDATE orders`).
+			WithArgs(domain.OrderStatusProcessed, &accrual.Amount, number).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectExec(`INSERT INTO transactions`).
+			WithArgs(int64(7), number, 100.0, domain.TransactionTypeAccrual, pgxmock.AnyArg(), 100.0, "round:2").
+			WillReturnResult(pgxmock.NewResult("INSERT", 0))
+		mock.ExpectRollback()
+
+		err := repo.ApplyAccrual(ctx, number, domain.OrderStatusProcessed, accrual)
+		assert.ErrorIs(t, err, domain.ErrDuplicateAccrual)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Order not found", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`FOR UPDATE`).
+			WithArgs(number).
+			WillReturnError(pgx.ErrNoRows)
+		mock.ExpectRollback()
+
+		err := repo.ApplyAccrual(ctx, number, domain.OrderStatusInvalid, nil)
+		assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Unknown status rejected", func(t *testing.T) {
+		err := repo.ApplyAccrual(ctx, number, domain.OrderStatus("DONE"), nil)
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
 }
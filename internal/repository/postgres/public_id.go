@@ -0,0 +1,27 @@
+package postgres
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// newPublicID создает UUIDv7 (RFC 9562): старшие 48 бит - Unix-время в миллисекундах,
+// поэтому новые идентификаторы монотонно растут и не фрагментируют индекс.
+// Остальные биты берутся из случайного UUIDv4.
+func newPublicID() uuid.UUID {
+	return newPublicIDAt(time.Now())
+}
+
+func newPublicIDAt(t time.Time) uuid.UUID {
+	id := uuid.New()
+
+	var ts [8]byte
+	binary.BigEndian.PutUint64(ts[:], uint64(t.UnixMilli()))
+	copy(id[:6], ts[2:])
+
+	id[6] = id[6]&0x0f | 0x70 // версия 7
+	id[8] = id[8]&0x3f | 0x80 // вариант RFC 9562
+	return id
+}
@@ -0,0 +1,29 @@
+package postgres
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPublicID(t *testing.T) {
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	id := newPublicIDAt(at)
+	assert.Equal(t, uuid.Version(7), id.Version())
+	assert.Equal(t, uuid.RFC4122, id.Variant())
+
+	var ms [8]byte
+	copy(ms[2:], id[:6])
+	assert.Equal(t, uint64(at.UnixMilli()), binary.BigEndian.Uint64(ms[:]))
+
+	// Идентификаторы, созданные позже, сортируются после ранних
+	later := newPublicIDAt(at.Add(time.Millisecond))
+	assert.Negative(t, bytes.Compare(id[:], later[:]))
+
+	assert.NotEqual(t, newPublicID(), newPublicID())
+}
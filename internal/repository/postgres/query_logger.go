@@ -0,0 +1,268 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/logctx"
+	"github.com/avc/loyalty-system-diploma/internal/metrics"
+	"github.com/avc/loyalty-system-diploma/internal/tracing"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// QueryLoggerConfig содержит настройки логирования SQL запросов
+type QueryLoggerConfig struct {
+	LogQueries    bool             // Логировать все запросы на уровне debug
+	SlowThreshold time.Duration    // Порог медленного запроса (0 - отключено)
+	Metrics       *metrics.Metrics // Длительность запросов и счетчик медленных
+
+	// ExplainThreshold - порог, начиная с которого к медленному запросу прикладывается
+	// план EXPLAIN (0 - отключено). План строится повторным запросом к БД, поэтому
+	// включать стоит только при разработке
+	ExplainThreshold time.Duration
+}
+
+// explainTimeout ограничивает построение плана медленного запроса
+const explainTimeout = 5 * time.Second
+
+// queryLogger логирует запросы и отслеживает медленные
+type queryLogger struct {
+	config QueryLoggerConfig
+	db     DBTX // Соединения без логирования для EXPLAIN
+}
+
+// loggedDB оборачивает DBTX и логирует каждый запрос с временем выполнения.
+// Логгер берется из контекста, поэтому записи содержат request_id и user_id.
+type loggedDB struct {
+	db     DBTX
+	logger *queryLogger
+}
+
+// WithQueryLogging возвращает DBTX, логирующий SQL запросы согласно конфигурации
+func WithQueryLogging(db DBTX, config QueryLoggerConfig) DBTX {
+	return &loggedDB{db: db, logger: &queryLogger{config: config, db: db}}
+}
+
+func (d *loggedDB) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	start := time.Now()
+	return &loggedRow{row: d.db.QueryRow(ctx, sql, args...), logger: d.logger, ctx: ctx, sql: sql, args: args, start: start}
+}
+
+func (d *loggedDB) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	start := time.Now()
+	rows, err := d.db.Query(ctx, sql, args...)
+	d.logger.log(ctx, sql, args, start, err)
+	return rows, err
+}
+
+func (d *loggedDB) Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error) {
+	start := time.Now()
+	tag, err := d.db.Exec(ctx, sql, arguments...)
+	d.logger.log(ctx, sql, arguments, start, err)
+	return tag, err
+}
+
+func (d *loggedDB) Begin(ctx context.Context) (pgx.Tx, error) {
+	tx, err := d.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &loggedTx{Tx: tx, logger: d.logger}, nil
+}
+
+// loggedTx логирует запросы, выполняемые внутри транзакции
+type loggedTx struct {
+	pgx.Tx
+	logger *queryLogger
+}
+
+func (t *loggedTx) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	start := time.Now()
+	return &loggedRow{row: t.Tx.QueryRow(ctx, sql, args...), logger: t.logger, ctx: ctx, sql: sql, args: args, start: start}
+}
+
+func (t *loggedTx) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	start := time.Now()
+	rows, err := t.Tx.Query(ctx, sql, args...)
+	t.logger.log(ctx, sql, args, start, err)
+	return rows, err
+}
+
+func (t *loggedTx) Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error) {
+	start := time.Now()
+	tag, err := t.Tx.Exec(ctx, sql, arguments...)
+	t.logger.log(ctx, sql, arguments, start, err)
+	return tag, err
+}
+
+// loggedRow откладывает логирование до Scan, так как ошибка QueryRow видна только в Scan.
+// Время отсчитывается до вызова QueryRow: pgx выполняет запрос сразу
+type loggedRow struct {
+	row    pgx.Row
+	logger *queryLogger
+	ctx    context.Context
+	sql    string
+	args   []any
+	start  time.Time
+}
+
+func (r *loggedRow) Scan(dest ...any) error {
+	err := r.row.Scan(dest...)
+	r.logger.log(r.ctx, r.sql, r.args, r.start, err)
+	return err
+}
+
+// queryOperation возвращает первое ключевое слово запроса в нижнем регистре для метрики
+// длительности запросов или "other" для остальных команд
+func queryOperation(sql string) string {
+	fields := strings.Fields(sql)
+	if len(fields) == 0 {
+		return "other"
+	}
+	switch keyword := strings.ToLower(fields[0]); keyword {
+	case "select", "with", "insert", "update", "delete":
+		return keyword
+	default:
+		return "other"
+	}
+}
+
+// log пишет в лог текст запроса, аргументы без значений строк и время выполнения.
+// Запросы дольше порога логируются с уровнем warn независимо от LogQueries.
+func (l *queryLogger) log(ctx context.Context, sql string, args []any, start time.Time, err error) {
+	end := time.Now()
+	duration := end.Sub(start)
+	operation := queryOperation(sql)
+	l.config.Metrics.ObserveQuery(operation, duration)
+	traceQuery(ctx, operation, sql, start, end, err)
+	slow := l.config.SlowThreshold > 0 && duration >= l.config.SlowThreshold
+
+	var ce *zapcore.CheckedEntry
+	logger := logctx.From(ctx)
+	switch {
+	case slow:
+		l.config.Metrics.ObserveSlowQuery()
+		ce = logger.Check(zap.WarnLevel, "slow sql query")
+	case l.config.LogQueries:
+		ce = logger.Check(zap.DebugLevel, "sql query")
+	}
+	if ce == nil {
+		return
+	}
+
+	fields := []zap.Field{
+		zap.String("sql", compactSQL(sql)),
+		zap.Duration("duration", duration),
+	}
+	if len(args) > 0 {
+		fields = append(fields, zap.Strings("args", redactArgs(args)))
+	}
+	if slow {
+		fields = append(fields, zap.Duration("threshold", l.config.SlowThreshold))
+	}
+	failed := err != nil && err != pgx.ErrNoRows
+	if failed {
+		fields = append(fields, zap.Error(err))
+	}
+	if slow && !failed && l.config.ExplainThreshold > 0 && duration >= l.config.ExplainThreshold && explainable(sql) {
+		if plan, err := l.explain(ctx, sql, args); err != nil {
+			fields = append(fields, zap.NamedError("explain_error", err))
+		} else {
+			fields = append(fields, zap.String("plan", plan))
+		}
+	}
+	ce.Write(fields...)
+}
+
+// traceQuery записывает клиентский спан запроса задним числом: ошибка QueryRow
+// становится известна только в Scan, поэтому спан строится по сохраненному времени начала
+func traceQuery(ctx context.Context, operation, sql string, start, end time.Time, err error) {
+	_, span := tracing.Tracer().Start(ctx, "db."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithTimestamp(start),
+	)
+	if !span.IsRecording() {
+		return
+	}
+	span.SetAttributes(
+		semconv.DBSystemNamePostgreSQL,
+		semconv.DBOperationName(operation),
+		semconv.DBQueryText(compactSQL(sql)),
+	)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) && !errors.Is(err, context.Canceled) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End(trace.WithTimestamp(end))
+}
+
+// explain возвращает план запроса. EXPLAIN без ANALYZE не выполняет запрос, поэтому
+// безопасен и для изменяющих данные запросов. План строится в отдельном соединении:
+// транзакция запроса к этому моменту может быть уже прервана
+func (l *queryLogger) explain(ctx context.Context, sql string, args []any) (string, error) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), explainTimeout)
+	defer cancel()
+
+	rows, err := l.db.Query(ctx, "EXPLAIN "+sql, args...)
+	if err != nil {
+		return "", err
+	}
+	lines, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// explainable сообщает, можно ли построить план запроса
+func explainable(sql string) bool {
+	fields := strings.Fields(sql)
+	if len(fields) == 0 {
+		return false
+	}
+	switch strings.ToUpper(fields[0]) {
+	case "SELECT", "WITH", "INSERT", "UPDATE", "DELETE":
+		return true
+	default:
+		return false
+	}
+}
+
+// redactArgs описывает аргументы запроса для лога. Числа, флаги, время и идентификаторы
+// выводятся как есть, строки и прочие значения - только типом и длиной: в них бывают
+// логины, хеши паролей и персональные данные
+func redactArgs(args []any) []string {
+	redacted := make([]string, len(args))
+	for i, arg := range args {
+		switch v := arg.(type) {
+		case nil:
+			redacted[i] = "NULL"
+		case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, time.Duration, uuid.UUID:
+			redacted[i] = fmt.Sprint(v)
+		case time.Time:
+			redacted[i] = v.Format(time.RFC3339Nano)
+		case string:
+			redacted[i] = fmt.Sprintf("<string len=%d>", len(v))
+		case []byte:
+			redacted[i] = fmt.Sprintf("<bytes len=%d>", len(v))
+		default:
+			redacted[i] = fmt.Sprintf("<%T>", v)
+		}
+	}
+	return redacted
+}
+
+// compactSQL схлопывает переводы строк и отступы в запросе
+func compactSQL(sql string) string {
+	return strings.Join(strings.Fields(sql), " ")
+}
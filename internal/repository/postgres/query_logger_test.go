@@ -0,0 +1,265 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/logctx"
+	"github.com/avc/loyalty-system-diploma/internal/metrics"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestWithQueryLogging(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	core, logs := observer.New(zap.DebugLevel)
+	ctx := logctx.With(context.Background(), zap.New(core).With(zap.String("request_id", "req-1")))
+	db := WithQueryLogging(mock, QueryLoggerConfig{LogQueries: true})
+
+	t.Run("Exec", func(t *testing.T) {
+		mock.ExpectExec(`UPDATE orders`).
+			WithArgs("1").
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+		_, err := db.Exec(ctx, "UPDATE orders\n\t\t SET status = 'NEW'\n\t\t WHERE number = $1", "1")
+		require.NoError(t, err)
+
+		entries := logs.TakeAll()
+		require.Len(t, entries, 1)
+		assert.Equal(t, "sql query", entries[0].Message)
+		fields := entries[0].ContextMap()
+		assert.Equal(t, "UPDATE orders SET status = 'NEW' WHERE number = $1", fields["sql"])
+		assert.Equal(t, []any{"<string len=1>"}, fields["args"])
+		assert.Equal(t, "req-1", fields["request_id"])
+		assert.Contains(t, fields, "duration")
+	})
+
+	t.Run("QueryRow logs on Scan", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT 1`).
+			WillReturnRows(pgxmock.NewRows([]string{"n"}).AddRow(1))
+
+		row := db.QueryRow(ctx, "SELECT 1")
+		assert.Equal(t, 0, logs.Len())
+
+		var n int
+		require.NoError(t, row.Scan(&n))
+		assert.Equal(t, 1, logs.Len())
+		logs.TakeAll()
+	})
+
+	t.Run("Queries inside transaction", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`SELECT pg_advisory_xact_lock`).
+			WithArgs(int64(1)).
+			WillReturnResult(pgxmock.NewResult("SELECT", 1))
+		mock.ExpectRollback()
+
+		tx, err := db.Begin(ctx)
+		require.NoError(t, err)
+		_, err = tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, int64(1))
+		require.NoError(t, err)
+		require.NoError(t, tx.Rollback(ctx))
+
+		assert.Equal(t, 1, logs.Len())
+		logs.TakeAll()
+	})
+
+	t.Run("Disabled debug level", func(t *testing.T) {
+		infoCore, infoLogs := observer.New(zap.InfoLevel)
+		infoCtx := logctx.With(context.Background(), zap.New(infoCore))
+
+		mock.ExpectExec(`DELETE FROM orders`).
+			WillReturnResult(pgxmock.NewResult("DELETE", 0))
+
+		_, err := db.Exec(infoCtx, "DELETE FROM orders")
+		require.NoError(t, err)
+		assert.Equal(t, 0, infoLogs.Len())
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWithQueryLogging_SlowQuery(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	core, logs := observer.New(zap.InfoLevel)
+	ctx := logctx.With(context.Background(), zap.New(core))
+	m := metrics.New()
+	db := WithQueryLogging(mock, QueryLoggerConfig{SlowThreshold: 10 * time.Millisecond, Metrics: m})
+
+	t.Run("Fast query is not logged", func(t *testing.T) {
+		mock.ExpectExec(`SELECT 1`).
+			WillReturnResult(pgxmock.NewResult("SELECT", 1))
+
+		_, err := db.Exec(ctx, "SELECT 1")
+		require.NoError(t, err)
+		assert.Equal(t, 0, logs.Len())
+		assert.Contains(t, testMetricsOutput(t, m), `gophermart_db_query_duration_seconds_count{operation="select"} 1`)
+	})
+
+	t.Run("Slow query is logged as warning", func(t *testing.T) {
+		mock.ExpectExec(`SELECT pg_sleep`).
+			WillReturnResult(pgxmock.NewResult("SELECT", 1)).
+			WillDelayFor(20 * time.Millisecond)
+
+		_, err := db.Exec(ctx, "SELECT pg_sleep(1)")
+		require.NoError(t, err)
+
+		entries := logs.TakeAll()
+		require.Len(t, entries, 1)
+		assert.Equal(t, zap.WarnLevel, entries[0].Level)
+		assert.Equal(t, "slow sql query", entries[0].Message)
+		assert.Equal(t, "SELECT pg_sleep(1)", entries[0].ContextMap()["sql"])
+		assert.Contains(t, testMetricsOutput(t, m), "gophermart_db_slow_queries_total 1")
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWithQueryLogging_Explain(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	core, logs := observer.New(zap.InfoLevel)
+	ctx := logctx.With(context.Background(), zap.New(core))
+	db := WithQueryLogging(mock, QueryLoggerConfig{
+		SlowThreshold:    5 * time.Millisecond,
+		ExplainThreshold: 10 * time.Millisecond,
+		Metrics:          metrics.New(),
+	})
+
+	t.Run("Plan is attached to slow query", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT COALESCE\(SUM\(amount\)`).
+			WithArgs(int64(1)).
+			WillReturnRows(pgxmock.NewRows([]string{"sum"}).AddRow(100.0)).
+			WillDelayFor(20 * time.Millisecond)
+		mock.ExpectQuery(`EXPLAIN SELECT COALESCE\(SUM\(amount\)`).
+			WithArgs(int64(1)).
+			WillReturnRows(pgxmock.NewRows([]string{"QUERY PLAN"}).
+				AddRow("Aggregate  (cost=10.00..10.01 rows=1 width=32)").
+				AddRow("  ->  Seq Scan on transactions"))
+
+		var sum float64
+		require.NoError(t, db.QueryRow(ctx, "SELECT COALESCE(SUM(amount), 0) FROM transactions WHERE user_id = $1", int64(1)).Scan(&sum))
+
+		entries := logs.TakeAll()
+		require.Len(t, entries, 1)
+		fields := entries[0].ContextMap()
+		assert.Equal(t, "Aggregate  (cost=10.00..10.01 rows=1 width=32)\n  ->  Seq Scan on transactions", fields["plan"])
+		assert.Equal(t, []any{"1"}, fields["args"])
+	})
+
+	t.Run("Plan failure is logged with query", func(t *testing.T) {
+		mock.ExpectExec(`UPDATE orders`).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1)).
+			WillDelayFor(20 * time.Millisecond)
+		mock.ExpectQuery(`EXPLAIN UPDATE orders`).
+			WillReturnError(errors.New("permission denied"))
+
+		_, err := db.Exec(ctx, "UPDATE orders SET status = 'NEW'")
+		require.NoError(t, err)
+
+		entries := logs.TakeAll()
+		require.Len(t, entries, 1)
+		assert.Equal(t, "permission denied", entries[0].ContextMap()["explain_error"])
+	})
+
+	t.Run("Query below explain threshold", func(t *testing.T) {
+		mock.ExpectExec(`SELECT pg_sleep`).
+			WillReturnResult(pgxmock.NewResult("SELECT", 1)).
+			WillDelayFor(6 * time.Millisecond)
+
+		_, err := db.Exec(ctx, "SELECT pg_sleep(1)")
+		require.NoError(t, err)
+
+		entries := logs.TakeAll()
+		require.Len(t, entries, 1)
+		assert.NotContains(t, entries[0].ContextMap(), "plan")
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWithQueryLogging_Tracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	db := WithQueryLogging(mock, QueryLoggerConfig{})
+	mock.ExpectExec(`UPDATE orders`).WillReturnError(errors.New("connection reset"))
+	mock.ExpectQuery(`SELECT id`).WillReturnError(pgx.ErrNoRows)
+
+	_, err = db.Exec(context.Background(), "UPDATE orders\n\t\t SET status = 'NEW'")
+	require.Error(t, err)
+	var id int64
+	err = db.QueryRow(context.Background(), "SELECT id FROM users").Scan(&id)
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "db.update", spans[0].Name())
+	assert.Contains(t, spans[0].Attributes(), attribute.String("db.query.text", "UPDATE orders SET status = 'NEW'"))
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	// Отсутствие строки - ожидаемый исход, а не ошибка запроса
+	assert.Equal(t, "db.select", spans[1].Name())
+	assert.Equal(t, codes.Unset, spans[1].Status().Code)
+}
+
+func TestRedactArgs(t *testing.T) {
+	id := uuid.MustParse("6f1c0d5e-8a4b-4c1e-9f3a-2b7d5e8c9a10")
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	assert.Equal(t, []string{
+		"NULL", "42", "true", "1.5", "6f1c0d5e-8a4b-4c1e-9f3a-2b7d5e8c9a10", "2024-01-02T03:04:05Z",
+		"<string len=6>", "<bytes len=2>", "<[]string>",
+	}, redactArgs([]any{nil, int64(42), true, 1.5, id, at, "secret", []byte{1, 2}, []string{"NEW"}}))
+}
+
+func TestExplainable(t *testing.T) {
+	assert.True(t, explainable("\n\t\tselect 1"))
+	assert.True(t, explainable("WITH x AS (SELECT 1) SELECT * FROM x"))
+	assert.True(t, explainable("UPDATE orders SET status = 'NEW'"))
+	assert.False(t, explainable(""))
+	assert.False(t, explainable("SET LOCAL lock_timeout = '1s'"))
+	assert.False(t, explainable("EXPLAIN SELECT 1"))
+}
+
+func TestQueryOperation(t *testing.T) {
+	assert.Equal(t, "select", queryOperation("\n\t\tselect 1"))
+	assert.Equal(t, "with", queryOperation("WITH x AS (SELECT 1) SELECT * FROM x"))
+	assert.Equal(t, "insert", queryOperation("INSERT INTO orders (number) VALUES ($1)"))
+	assert.Equal(t, "other", queryOperation("SET LOCAL lock_timeout = '1s'"))
+	assert.Equal(t, "other", queryOperation(""))
+}
+
+func testMetricsOutput(t *testing.T, m *metrics.Metrics) string {
+	t.Helper()
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	return w.Body.String()
+}
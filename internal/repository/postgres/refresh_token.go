@@ -0,0 +1,126 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+// RefreshTokenRepository хранит выданные refresh-токены для их отзыва.
+type RefreshTokenRepository struct {
+	db DBTX
+}
+
+// NewRefreshTokenRepository создает новый RefreshTokenRepository
+func NewRefreshTokenRepository(db DBTX) *RefreshTokenRepository {
+	return &RefreshTokenRepository{db: db}
+}
+
+// CreateRefreshToken сохраняет refresh-токен id пользователя userID, выданный устройству
+// device, со временем жизни ttl
+func (r *RefreshTokenRepository) CreateRefreshToken(ctx context.Context, userID int64, id, device string, ttl time.Duration) error {
+	_, err := r.db.Exec(ctx,
+		`INSERT INTO refresh_tokens (id, user_id, device, expires_at) 
+		 VALUES ($1, $2, $3, NOW() + make_interval(secs => $4))`,
+		id, userID, device, ttl.Seconds(),
+	)
+	if err != nil {
+		return fmt.Errorf("repository: failed to create refresh token for user %d: %w", userID, err)
+	}
+	return nil
+}
+
+// RotateRefreshToken отзывает refresh-токен id, предъявленный устройством device, и сохраняет
+// выданный взамен токен nextID. Неизвестный, истекший или чужой токен - ErrInvalidToken.
+// Уже отозванный токен означает, что его предъявили повторно (скорее всего, он украден):
+// тогда отзываются все токены пользователя и возвращается ErrRefreshTokenReused. Так же
+// отзываются все токены, если токен предъявлен не тем устройством, которому выдан, -
+// с ошибкой ErrRefreshTokenDevice
+func (r *RefreshTokenRepository) RotateRefreshToken(ctx context.Context, userID int64, id, nextID, device string, ttl time.Duration) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("repository: failed to begin transaction for user %d: %w", userID, err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // Rollback после Commit безопасен
+
+	var owner int64
+	var owned string
+	var revoked bool
+	err = tx.QueryRow(ctx,
+		`SELECT user_id, device, revoked_at IS NOT NULL 
+		 FROM refresh_tokens 
+		 WHERE id = $1 AND expires_at > NOW() 
+		 FOR UPDATE`,
+		id,
+	).Scan(&owner, &owned, &revoked)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ErrInvalidToken
+	}
+	if err != nil {
+		return fmt.Errorf("repository: failed to get refresh token of user %d: %w", userID, err)
+	}
+	if owner != userID {
+		return domain.ErrInvalidToken
+	}
+
+	if revoked || owned != device {
+		_, err = tx.Exec(ctx,
+			`UPDATE refresh_tokens SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL`,
+			userID,
+		)
+		if err != nil {
+			return fmt.Errorf("repository: failed to revoke refresh tokens of user %d: %w", userID, err)
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return fmt.Errorf("repository: failed to commit refresh token revocation: %w", err)
+		}
+		if revoked {
+			return domain.ErrRefreshTokenReused
+		}
+		return domain.ErrRefreshTokenDevice
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE refresh_tokens SET revoked_at = NOW() WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("repository: failed to revoke refresh token of user %d: %w", userID, err)
+	}
+	_, err = tx.Exec(ctx,
+		`INSERT INTO refresh_tokens (id, user_id, device, expires_at) 
+		 VALUES ($1, $2, $3, NOW() + make_interval(secs => $4))`,
+		nextID, userID, device, ttl.Seconds(),
+	)
+	if err != nil {
+		return fmt.Errorf("repository: failed to create refresh token for user %d: %w", userID, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("repository: failed to commit refresh token rotation: %w", err)
+	}
+	return nil
+}
+
+// RevokeRefreshTokens отзывает все refresh-токены пользователя userID. Токены удаляются:
+// предъявленный после этого токен неизвестен и отклоняется как ErrInvalidToken
+func (r *RefreshTokenRepository) RevokeRefreshTokens(ctx context.Context, userID int64) error {
+	if _, err := r.db.Exec(ctx, `DELETE FROM refresh_tokens WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("repository: failed to delete refresh tokens of user %d: %w", userID, err)
+	}
+	return nil
+}
+
+// DeleteExpiredRefreshTokens удаляет до limit истекших refresh-токенов и возвращает их число.
+// Отозванные, но не истекшие токены остаются: по ним распознается повторное предъявление
+func (r *RefreshTokenRepository) DeleteExpiredRefreshTokens(ctx context.Context, limit int) (int, error) {
+	tag, err := r.db.Exec(ctx,
+		`DELETE FROM refresh_tokens 
+		 WHERE id IN (SELECT id FROM refresh_tokens WHERE expires_at < NOW() LIMIT $1)`,
+		limit,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("repository: failed to delete expired refresh tokens: %w", err)
+	}
+	return int(tag.RowsAffected()), nil
+}
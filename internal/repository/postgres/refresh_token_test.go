@@ -0,0 +1,175 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefreshTokenRepository_CreateRefreshToken(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewRefreshTokenRepository(mock)
+	ctx := context.Background()
+
+	mock.ExpectExec(`INSERT INTO refresh_tokens \(id, user_id, device, expires_at\) VALUES \(\$1, \$2, \$3, NOW\(\) \+ make_interval\(secs => \$4\)\)`).
+		WithArgs("jti-1", int64(7), "phone", float64(3600)).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	assert.NoError(t, repo.CreateRefreshToken(ctx, 7, "jti-1", "phone", time.Hour))
+
+	mock.ExpectExec(`INSERT INTO refresh_tokens`).
+		WithArgs("jti-2", int64(7), "phone", float64(3600)).
+		WillReturnError(errors.New("connection refused"))
+	assert.Error(t, repo.CreateRefreshToken(ctx, 7, "jti-2", "phone", time.Hour))
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRefreshTokenRepository_RotateRefreshToken(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewRefreshTokenRepository(mock)
+	ctx := context.Background()
+
+	expectToken := func(owner int64, device string, revoked bool) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT user_id, device, revoked_at IS NOT NULL FROM refresh_tokens WHERE id = \$1 AND expires_at > NOW\(\) FOR UPDATE`).
+			WithArgs("old").
+			WillReturnRows(pgxmock.NewRows([]string{"user_id", "device", "revoked"}).AddRow(owner, device, revoked))
+	}
+
+	t.Run("Rotated", func(t *testing.T) {
+		expectToken(7, "phone", false)
+		mock.ExpectExec(`UPDATE refresh_tokens SET revoked_at = NOW\(\) WHERE id = \$1`).
+			WithArgs("old").
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectExec(`INSERT INTO refresh_tokens`).
+			WithArgs("new", int64(7), "phone", float64(3600)).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectCommit()
+
+		assert.NoError(t, repo.RotateRefreshToken(ctx, 7, "old", "new", "phone", time.Hour))
+	})
+
+	t.Run("Reused token revokes all tokens of the user", func(t *testing.T) {
+		expectToken(7, "phone", true)
+		mock.ExpectExec(`UPDATE refresh_tokens SET revoked_at = NOW\(\) WHERE user_id = \$1 AND revoked_at IS NULL`).
+			WithArgs(int64(7)).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 2))
+		mock.ExpectCommit()
+
+		err := repo.RotateRefreshToken(ctx, 7, "old", "new", "phone", time.Hour)
+		assert.ErrorIs(t, err, domain.ErrRefreshTokenReused)
+	})
+
+	t.Run("Token from another device revokes all tokens of the user", func(t *testing.T) {
+		expectToken(7, "phone", false)
+		mock.ExpectExec(`UPDATE refresh_tokens SET revoked_at = NOW\(\) WHERE user_id = \$1 AND revoked_at IS NULL`).
+			WithArgs(int64(7)).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 2))
+		mock.ExpectCommit()
+
+		err := repo.RotateRefreshToken(ctx, 7, "old", "new", "laptop", time.Hour)
+		assert.ErrorIs(t, err, domain.ErrRefreshTokenDevice)
+	})
+
+	t.Run("Token bound to device without device ID revokes all tokens of the user", func(t *testing.T) {
+		expectToken(7, "phone", false)
+		mock.ExpectExec(`UPDATE refresh_tokens SET revoked_at = NOW\(\) WHERE user_id = \$1 AND revoked_at IS NULL`).
+			WithArgs(int64(7)).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 2))
+		mock.ExpectCommit()
+
+		err := repo.RotateRefreshToken(ctx, 7, "old", "new", "", time.Hour)
+		assert.ErrorIs(t, err, domain.ErrRefreshTokenDevice)
+	})
+
+	t.Run("Token of another user", func(t *testing.T) {
+		expectToken(8, "phone", false)
+		mock.ExpectRollback()
+
+		err := repo.RotateRefreshToken(ctx, 7, "old", "new", "phone", time.Hour)
+		assert.ErrorIs(t, err, domain.ErrInvalidToken)
+	})
+
+	t.Run("Unknown or expired token", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`FROM refresh_tokens`).
+			WithArgs("old").
+			WillReturnError(pgx.ErrNoRows)
+		mock.ExpectRollback()
+
+		err := repo.RotateRefreshToken(ctx, 7, "old", "new", "phone", time.Hour)
+		assert.ErrorIs(t, err, domain.ErrInvalidToken)
+	})
+
+	t.Run("Database error", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`FROM refresh_tokens`).
+			WithArgs("old").
+			WillReturnError(errors.New("connection refused"))
+		mock.ExpectRollback()
+
+		err := repo.RotateRefreshToken(ctx, 7, "old", "new", "phone", time.Hour)
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, domain.ErrInvalidToken)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRefreshTokenRepository_RevokeRefreshTokens(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewRefreshTokenRepository(mock)
+	ctx := context.Background()
+
+	mock.ExpectExec(`DELETE FROM refresh_tokens WHERE user_id = \$1`).
+		WithArgs(int64(7)).
+		WillReturnResult(pgxmock.NewResult("DELETE", 3))
+	assert.NoError(t, repo.RevokeRefreshTokens(ctx, 7))
+
+	mock.ExpectExec(`DELETE FROM refresh_tokens`).
+		WithArgs(int64(7)).
+		WillReturnError(errors.New("connection refused"))
+	assert.Error(t, repo.RevokeRefreshTokens(ctx, 7))
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRefreshTokenRepository_DeleteExpiredRefreshTokens(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewRefreshTokenRepository(mock)
+	ctx := context.Background()
+
+	mock.ExpectExec(`DELETE FROM refresh_tokens WHERE id IN \(SELECT id FROM refresh_tokens WHERE expires_at < NOW\(\) LIMIT \$1\)`).
+		WithArgs(100).
+		WillReturnResult(pgxmock.NewResult("DELETE", 42))
+	deleted, err := repo.DeleteExpiredRefreshTokens(ctx, 100)
+	require.NoError(t, err)
+	assert.Equal(t, 42, deleted)
+
+	mock.ExpectExec(`DELETE FROM refresh_tokens`).
+		WithArgs(100).
+		WillReturnError(errors.New("connection refused"))
+	_, err = repo.DeleteExpiredRefreshTokens(ctx, 100)
+	assert.Error(t, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
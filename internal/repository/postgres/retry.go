@@ -0,0 +1,188 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/avc/loyalty-system-diploma/internal/logctx"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.uber.org/zap"
+)
+
+// RetryConfig содержит настройки повтора запросов при временных ошибках БД
+type RetryConfig struct {
+	MaxAttempts    int           // Максимальное число попыток (включая первую)
+	InitialBackoff time.Duration // Пауза перед первым повтором
+	MaxBackoff     time.Duration // Верхняя граница паузы
+	Availability   *Availability // Трекер доступности БД (может быть nil)
+}
+
+// retryingDB повторяет запросы при временных ошибках (обрыв соединения,
+// serialization failure, deadlock) с экспоненциальной паузой.
+// Ошибки соединения после исчерпания попыток оборачиваются в domain.ErrStorageUnavailable.
+type retryingDB struct {
+	db     DBTX
+	config RetryConfig
+}
+
+// WithRetry возвращает DBTX, повторяющий запросы согласно конфигурации.
+// Запросы внутри транзакции не повторяются, так как транзакция уже прервана.
+func WithRetry(db DBTX, config RetryConfig) DBTX {
+	if config.MaxAttempts < 1 {
+		config.MaxAttempts = 1
+	}
+	return &retryingDB{db: db, config: config}
+}
+
+func (d *retryingDB) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return &retryingRow{db: d, ctx: ctx, sql: sql, args: args}
+}
+
+func (d *retryingDB) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	var rows pgx.Rows
+	err := d.do(ctx, func() error {
+		var err error
+		rows, err = d.db.Query(ctx, sql, args...)
+		return err
+	})
+	return rows, err
+}
+
+func (d *retryingDB) Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error) {
+	var tag pgconn.CommandTag
+	err := d.do(ctx, func() error {
+		var err error
+		tag, err = d.db.Exec(ctx, sql, arguments...)
+		return err
+	})
+	return tag, err
+}
+
+func (d *retryingDB) Begin(ctx context.Context) (pgx.Tx, error) {
+	var tx pgx.Tx
+	err := d.do(ctx, func() error {
+		var err error
+		tx, err = d.db.Begin(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &retryingTx{Tx: tx, db: d}, nil
+}
+
+// do выполняет fn, повторяя ее при временных ошибках
+func (d *retryingDB) do(ctx context.Context, fn func() error) error {
+	backoff := d.config.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil {
+			d.markAvailable()
+			return nil
+		}
+		if !isRetryable(err) || attempt >= d.config.MaxAttempts {
+			return d.classify(err)
+		}
+
+		logctx.From(ctx).Warn("transient database error, retrying",
+			zap.Int("attempt", attempt),
+			zap.Duration("backoff", backoff),
+			zap.Error(err),
+		)
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return d.classify(err)
+		case <-timer.C:
+		}
+		backoff = min(backoff*2, d.config.MaxBackoff)
+	}
+}
+
+// classify отмечает недоступность БД и оборачивает ошибки соединения
+// в доменную ошибку, чтобы хендлеры могли ответить 503 вместо 500
+func (d *retryingDB) classify(err error) error {
+	if isConnectionError(err) {
+		if d.config.Availability != nil {
+			d.config.Availability.MarkUnavailable(err)
+		}
+		return fmt.Errorf("%w: %w", domain.ErrStorageUnavailable, err)
+	}
+	if isRetryable(err) {
+		return fmt.Errorf("%w: %w", domain.ErrStorageUnavailable, err)
+	}
+	return err
+}
+
+func (d *retryingDB) markAvailable() {
+	if d.config.Availability != nil {
+		d.config.Availability.MarkAvailable()
+	}
+}
+
+// retryingRow откладывает выполнение до Scan, чтобы повторять запрос целиком
+type retryingRow struct {
+	db   *retryingDB
+	ctx  context.Context
+	sql  string
+	args []any
+}
+
+func (r *retryingRow) Scan(dest ...any) error {
+	return r.db.do(r.ctx, func() error {
+		return r.db.db.QueryRow(r.ctx, r.sql, r.args...).Scan(dest...)
+	})
+}
+
+// retryingTx не повторяет запросы, но классифицирует их ошибки
+type retryingTx struct {
+	pgx.Tx
+	db *retryingDB
+}
+
+func (t *retryingTx) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return &classifiedRow{row: t.Tx.QueryRow(ctx, sql, args...), db: t.db}
+}
+
+func (t *retryingTx) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	rows, err := t.Tx.Query(ctx, sql, args...)
+	if err != nil {
+		return rows, t.db.classify(err)
+	}
+	return rows, nil
+}
+
+func (t *retryingTx) Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error) {
+	tag, err := t.Tx.Exec(ctx, sql, arguments...)
+	if err != nil {
+		return tag, t.db.classify(err)
+	}
+	return tag, nil
+}
+
+func (t *retryingTx) Commit(ctx context.Context) error {
+	if err := t.Tx.Commit(ctx); err != nil {
+		return t.db.classify(err)
+	}
+	return nil
+}
+
+type classifiedRow struct {
+	row pgx.Row
+	db  *retryingDB
+}
+
+func (r *classifiedRow) Scan(dest ...any) error {
+	if err := r.row.Scan(dest...); err != nil {
+		if err == pgx.ErrNoRows {
+			return err
+		}
+		return r.db.classify(err)
+	}
+	return nil
+}
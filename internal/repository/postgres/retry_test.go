@@ -0,0 +1,158 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func connRefused() error {
+	return &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+}
+
+func TestWithRetry(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	ctx := context.Background()
+	state := NewAvailability(zap.NewNop())
+	db := WithRetry(mock, RetryConfig{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     2 * time.Millisecond,
+		Availability:   state,
+	})
+
+	t.Run("Exec retried after serialization failure", func(t *testing.T) {
+		mock.ExpectExec(`UPDATE orders`).
+			WillReturnError(&pgconn.PgError{Code: pgCodeSerializationFailure})
+		mock.ExpectExec(`UPDATE orders`).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+		tag, err := db.Exec(ctx, `UPDATE orders SET status = 'NEW'`)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), tag.RowsAffected())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("QueryRow retried on Scan", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT 1`).WillReturnError(connRefused())
+		mock.ExpectQuery(`SELECT 1`).WillReturnRows(pgxmock.NewRows([]string{"n"}).AddRow(1))
+
+		var n int
+		require.NoError(t, db.QueryRow(ctx, `SELECT 1`).Scan(&n))
+		assert.Equal(t, 1, n)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Connection lost - storage unavailable", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			mock.ExpectQuery(`SELECT id FROM users`).WillReturnError(connRefused())
+		}
+
+		_, err := db.Query(ctx, `SELECT id FROM users`)
+		assert.ErrorIs(t, err, domain.ErrStorageUnavailable)
+		assert.ErrorIs(t, err, syscall.ECONNREFUSED)
+		assert.False(t, state.Available())
+		assert.NoError(t, mock.ExpectationsWereMet())
+
+		// Первый успешный запрос выводит из режима деградации
+		mock.ExpectExec(`SELECT 1`).WillReturnResult(pgxmock.NewResult("SELECT", 1))
+		_, err = db.Exec(ctx, `SELECT 1`)
+		require.NoError(t, err)
+		assert.True(t, state.Available())
+	})
+
+	t.Run("Constraint violation not retried", func(t *testing.T) {
+		mock.ExpectExec(`INSERT INTO users`).
+			WillReturnError(&pgconn.PgError{Code: pgCodeUniqueViolation})
+
+		_, err := db.Exec(ctx, `INSERT INTO users (login) VALUES ('a')`)
+		assert.True(t, isUniqueViolation(err))
+		assert.NotErrorIs(t, err, domain.ErrStorageUnavailable)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("No rows passed through", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT id`).WillReturnError(pgx.ErrNoRows)
+
+		var id int64
+		err := db.QueryRow(ctx, `SELECT id FROM orders`).Scan(&id)
+		assert.ErrorIs(t, err, pgx.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Queries inside transaction are not retried", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`SELECT pg_advisory_xact_lock`).
+			WithArgs(int64(1)).
+			WillReturnError(&pgconn.PgError{Code: pgCodeDeadlockDetected})
+		mock.ExpectRollback()
+
+		tx, err := db.Begin(ctx)
+		require.NoError(t, err)
+		_, err = tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, int64(1))
+		assert.ErrorIs(t, err, domain.ErrStorageUnavailable)
+		require.NoError(t, tx.Rollback(ctx))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestWithRetry_ContextCanceled(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	db := WithRetry(mock, RetryConfig{MaxAttempts: 5, InitialBackoff: time.Hour, MaxBackoff: time.Hour})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	mock.ExpectExec(`SELECT 1`).WillReturnError(connRefused())
+
+	_, err = db.Exec(ctx, `SELECT 1`)
+	assert.ErrorIs(t, err, domain.ErrStorageUnavailable)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestErrorClassification(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		retryable  bool
+		connection bool
+	}{
+		{"Connection refused", fmt.Errorf("connect: %w", connRefused()), true, true},
+		{"Serialization failure", &pgconn.PgError{Code: pgCodeSerializationFailure}, true, false},
+		{"Deadlock", &pgconn.PgError{Code: pgCodeDeadlockDetected}, true, false},
+		{"Server starting up", &pgconn.PgError{Code: pgCodeCannotConnectNow}, true, true},
+		{"Admin shutdown", &pgconn.PgError{Code: pgCodeAdminShutdown}, false, true},
+		{"Connection failure", &pgconn.PgError{Code: "08006"}, false, true},
+		{"Unexpected EOF", fmt.Errorf("read: %w", io.ErrUnexpectedEOF), false, true},
+		{"Unique violation", &pgconn.PgError{Code: pgCodeUniqueViolation}, false, false},
+		{"Context canceled", context.Canceled, false, false},
+		{"No rows", pgx.ErrNoRows, false, false},
+		{"Other", errors.New("boom"), false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.retryable, isRetryable(tt.err))
+			assert.Equal(t, tt.connection, isConnectionError(tt.err))
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// RevokedTokenRepository хранит черный список токенов доступа, отозванных при выходе.
+type RevokedTokenRepository struct {
+	db DBTX
+}
+
+// NewRevokedTokenRepository создает новый RevokedTokenRepository
+func NewRevokedTokenRepository(db DBTX) *RevokedTokenRepository {
+	return &RevokedTokenRepository{db: db}
+}
+
+// RevokeToken добавляет токен пользователя userID в черный список на оставшееся время
+// жизни ttl (0 - токен без срока действия). Заодно удаляются записи об уже истекших
+// токенах: выход редок, и отдельная очистка по расписанию не нужна
+func (r *RevokedTokenRepository) RevokeToken(ctx context.Context, userID int64, tokenHash string, ttl time.Duration) error {
+	if _, err := r.db.Exec(ctx, `DELETE FROM revoked_tokens WHERE expires_at < NOW()`); err != nil {
+		return fmt.Errorf("repository: failed to delete expired revoked tokens: %w", err)
+	}
+
+	var secs *float64
+	if ttl > 0 {
+		s := ttl.Seconds()
+		secs = &s
+	}
+	_, err := r.db.Exec(ctx,
+		`INSERT INTO revoked_tokens (token_hash, user_id, expires_at) 
+		 VALUES ($1, $2, NOW() + make_interval(secs => $3)) 
+		 ON CONFLICT (token_hash) DO NOTHING`,
+		tokenHash, userID, secs,
+	)
+	if err != nil {
+		return fmt.Errorf("repository: failed to revoke token of user %d: %w", userID, err)
+	}
+	return nil
+}
+
+// IsTokenRevoked проверяет, есть ли токен в черном списке
+func (r *RevokedTokenRepository) IsTokenRevoked(ctx context.Context, tokenHash string) (bool, error) {
+	var revoked bool
+	err := r.db.QueryRow(ctx,
+		`SELECT EXISTS(SELECT 1 FROM revoked_tokens WHERE token_hash = $1)`,
+		tokenHash,
+	).Scan(&revoked)
+	if err != nil {
+		return false, fmt.Errorf("repository: failed to check revoked token: %w", err)
+	}
+	return revoked, nil
+}
@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRevokedTokenRepository_RevokeToken(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewRevokedTokenRepository(mock)
+	ctx := context.Background()
+	ttl := float64(3600)
+
+	t.Run("Revoked", func(t *testing.T) {
+		mock.ExpectExec(`DELETE FROM revoked_tokens WHERE expires_at < NOW\(\)`).
+			WillReturnResult(pgxmock.NewResult("DELETE", 2))
+		mock.ExpectExec(`INSERT INTO revoked_tokens \(token_hash, user_id, expires_at\) VALUES \(\$1, \$2, NOW\(\) \+ make_interval\(secs => \$3\)\) ON CONFLICT \(token_hash\) DO NOTHING`).
+			WithArgs("hash-1", int64(7), &ttl).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+		assert.NoError(t, repo.RevokeToken(ctx, 7, "hash-1", time.Hour))
+	})
+
+	t.Run("Without expiry", func(t *testing.T) {
+		mock.ExpectExec(`DELETE FROM revoked_tokens`).
+			WillReturnResult(pgxmock.NewResult("DELETE", 0))
+		mock.ExpectExec(`INSERT INTO revoked_tokens`).
+			WithArgs("hash-2", int64(7), (*float64)(nil)).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+		assert.NoError(t, repo.RevokeToken(ctx, 7, "hash-2", 0))
+	})
+
+	t.Run("Cleanup error", func(t *testing.T) {
+		mock.ExpectExec(`DELETE FROM revoked_tokens`).
+			WillReturnError(errors.New("connection refused"))
+
+		assert.Error(t, repo.RevokeToken(ctx, 7, "hash-3", time.Hour))
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRevokedTokenRepository_IsTokenRevoked(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewRevokedTokenRepository(mock)
+	ctx := context.Background()
+
+	mock.ExpectQuery(`SELECT EXISTS\(SELECT 1 FROM revoked_tokens WHERE token_hash = \$1\)`).
+		WithArgs("hash-1").
+		WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(true))
+	revoked, err := repo.IsTokenRevoked(ctx, "hash-1")
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	mock.ExpectQuery(`FROM revoked_tokens`).
+		WithArgs("hash-2").
+		WillReturnError(errors.New("connection refused"))
+	_, err = repo.IsTokenRevoked(ctx, "hash-2")
+	assert.Error(t, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
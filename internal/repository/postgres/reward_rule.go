@@ -0,0 +1,147 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+// RewardRuleRepository хранит локальную копию правил вознаграждения системы начислений
+type RewardRuleRepository struct {
+	db DBTX
+}
+
+// NewRewardRuleRepository создает новый RewardRuleRepository
+func NewRewardRuleRepository(db DBTX) *RewardRuleRepository {
+	return &RewardRuleRepository{db: db}
+}
+
+// SyncRewardRules заменяет локальную копию правил набором rules. Если fingerprint
+// совпадает с отпечатком прошлой синхронизации, правила не переписываются,
+// обновляется только время проверки. Правила в rules не должны повторяться по Match
+func (r *RewardRuleRepository) SyncRewardRules(ctx context.Context, rules []domain.RewardRule, fingerprint string) (*domain.RewardRuleSync, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // Rollback после Commit безопасен
+
+	// Блокировка строки состояния не дает двум синхронизациям пересечься
+	var previous string
+	err = tx.QueryRow(ctx,
+		`SELECT fingerprint FROM accrual_reward_rule_sync WHERE id FOR UPDATE`,
+	).Scan(&previous)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("repository: failed to get reward rule fingerprint: %w", err)
+	}
+
+	sync := &domain.RewardRuleSync{}
+	if previous == fingerprint {
+		err = tx.QueryRow(ctx,
+			`UPDATE accrual_reward_rule_sync SET checked_at = NOW() WHERE id RETURNING checked_at`,
+		).Scan(&sync.CheckedAt)
+		if err != nil {
+			return nil, fmt.Errorf("repository: failed to update reward rule sync time: %w", err)
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return nil, fmt.Errorf("repository: failed to commit transaction: %w", err)
+		}
+		return sync, nil
+	}
+
+	matches := make([]string, len(rules))
+	rewards := make([]float64, len(rules))
+	types := make([]string, len(rules))
+	for i, rule := range rules {
+		matches[i], rewards[i], types[i] = rule.Match, rule.Reward, string(rule.RewardType)
+	}
+
+	// Неизменившиеся правила не обновляются, чтобы updated_at отражал реальные изменения
+	err = tx.QueryRow(ctx,
+		`WITH upserted AS ( 
+		   INSERT INTO accrual_reward_rules (match, reward, reward_type) 
+		   SELECT * FROM unnest($1::varchar[], $2::numeric[], $3::varchar[]) 
+		   ON CONFLICT (match) DO UPDATE SET 
+		     reward = EXCLUDED.reward, 
+		     reward_type = EXCLUDED.reward_type, 
+		     updated_at = NOW() 
+		   WHERE (accrual_reward_rules.reward, accrual_reward_rules.reward_type) 
+		     IS DISTINCT FROM (EXCLUDED.reward, EXCLUDED.reward_type) 
+		   RETURNING (xmax = 0) AS inserted 
+		 ) 
+		 SELECT COUNT(*) FILTER (WHERE inserted), COUNT(*) FILTER (WHERE NOT inserted) 
+		 FROM upserted`,
+		matches, rewards, types,
+	).Scan(&sync.Added, &sync.Updated)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to upsert reward rules: %w", err)
+	}
+
+	tag, err := tx.Exec(ctx,
+		`DELETE FROM accrual_reward_rules WHERE match <> ALL($1::varchar[])`,
+		matches,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to delete stale reward rules: %w", err)
+	}
+	sync.Removed = int(tag.RowsAffected())
+
+	err = tx.QueryRow(ctx,
+		`INSERT INTO accrual_reward_rule_sync (id, fingerprint) 
+		 VALUES (TRUE, $1) 
+		 ON CONFLICT (id) DO UPDATE SET 
+		   fingerprint = EXCLUDED.fingerprint, 
+		   checked_at = NOW(), 
+		   changed_at = NOW() 
+		 RETURNING checked_at`,
+		fingerprint,
+	).Scan(&sync.CheckedAt)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to save reward rule fingerprint: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("repository: failed to commit transaction: %w", err)
+	}
+
+	sync.Changed = true
+	return sync, nil
+}
+
+// ListRewardRules возвращает локальную копию правил, упорядоченную по Match
+func (r *RewardRuleRepository) ListRewardRules(ctx context.Context) (*domain.RewardRuleSet, error) {
+	set := &domain.RewardRuleSet{}
+
+	err := r.db.QueryRow(ctx,
+		`SELECT checked_at, changed_at FROM accrual_reward_rule_sync WHERE id`,
+	).Scan(&set.CheckedAt, &set.ChangedAt)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("repository: failed to get reward rule sync state: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx,
+		`SELECT match, reward, reward_type, updated_at 
+		 FROM accrual_reward_rules 
+		 ORDER BY match`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to list reward rules: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		rule := &domain.RewardRule{}
+		if err := rows.Scan(&rule.Match, &rule.Reward, &rule.RewardType, &rule.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("repository: failed to scan reward rule: %w", err)
+		}
+		set.Rules = append(set.Rules, rule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repository: failed to iterate reward rules: %w", err)
+	}
+
+	return set, nil
+}
@@ -0,0 +1,154 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRewardRuleRepository_SyncRewardRules(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewRewardRuleRepository(mock)
+	ctx := context.Background()
+	checkedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	rules := []domain.RewardRule{
+		{Match: "Bork", Reward: 10, RewardType: domain.RewardTypePercent},
+		{Match: "Acer", Reward: 50, RewardType: domain.RewardTypePoints},
+	}
+
+	t.Run("Rules changed", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT fingerprint FROM accrual_reward_rule_sync WHERE id FOR UPDATE`).
+			WillReturnRows(pgxmock.NewRows([]string{"fingerprint"}).AddRow("old"))
+		mock.ExpectQuery(`INSERT INTO accrual_reward_rules \(match, reward, reward_type\) SELECT \* FROM unnest\(.*\) ON CONFLICT \(match\) DO UPDATE .* IS DISTINCT FROM`).
+			WithArgs([]string{"Bork", "Acer"}, []float64{10, 50}, []string{"%", "pt"}).
+			WillReturnRows(pgxmock.NewRows([]string{"added", "updated"}).AddRow(1, 1))
+		mock.ExpectExec(`DELETE FROM accrual_reward_rules WHERE match <> ALL`).
+			WithArgs([]string{"Bork", "Acer"}).
+			WillReturnResult(pgxmock.NewResult("DELETE", 3))
+		mock.ExpectQuery(`INSERT INTO accrual_reward_rule_sync \(id, fingerprint\) VALUES \(TRUE, \$1\) ON CONFLICT \(id\) DO UPDATE`).
+			WithArgs("new").
+			WillReturnRows(pgxmock.NewRows([]string{"checked_at"}).AddRow(checkedAt))
+		mock.ExpectCommit()
+
+		sync, err := repo.SyncRewardRules(ctx, rules, "new")
+		require.NoError(t, err)
+		assert.Equal(t, &domain.RewardRuleSync{
+			Changed:   true,
+			Added:     1,
+			Updated:   1,
+			Removed:   3,
+			CheckedAt: checkedAt,
+		}, sync)
+	})
+
+	t.Run("First sync", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT fingerprint FROM accrual_reward_rule_sync`).
+			WillReturnError(pgx.ErrNoRows)
+		mock.ExpectQuery(`INSERT INTO accrual_reward_rules`).
+			WithArgs([]string{}, []float64{}, []string{}).
+			WillReturnRows(pgxmock.NewRows([]string{"added", "updated"}).AddRow(0, 0))
+		mock.ExpectExec(`DELETE FROM accrual_reward_rules`).
+			WithArgs([]string{}).
+			WillReturnResult(pgxmock.NewResult("DELETE", 0))
+		mock.ExpectQuery(`INSERT INTO accrual_reward_rule_sync`).
+			WithArgs("empty").
+			WillReturnRows(pgxmock.NewRows([]string{"checked_at"}).AddRow(checkedAt))
+		mock.ExpectCommit()
+
+		sync, err := repo.SyncRewardRules(ctx, nil, "empty")
+		require.NoError(t, err)
+		assert.True(t, sync.Changed)
+	})
+
+	t.Run("Fingerprint unchanged", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT fingerprint FROM accrual_reward_rule_sync`).
+			WillReturnRows(pgxmock.NewRows([]string{"fingerprint"}).AddRow("same"))
+		mock.ExpectQuery(`UPDATE accrual_reward_rule_sync SET checked_at = NOW\(\) WHERE id RETURNING checked_at`).
+			WillReturnRows(pgxmock.NewRows([]string{"checked_at"}).AddRow(checkedAt))
+		mock.ExpectCommit()
+
+		sync, err := repo.SyncRewardRules(ctx, rules, "same")
+		require.NoError(t, err)
+		assert.Equal(t, &domain.RewardRuleSync{CheckedAt: checkedAt}, sync)
+	})
+
+	t.Run("Database error", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT fingerprint FROM accrual_reward_rule_sync`).
+			WillReturnRows(pgxmock.NewRows([]string{"fingerprint"}).AddRow("old"))
+		mock.ExpectQuery(`INSERT INTO accrual_reward_rules`).
+			WithArgs([]string{"Bork", "Acer"}, []float64{10, 50}, []string{"%", "pt"}).
+			WillReturnError(errors.New("connection refused"))
+		mock.ExpectRollback()
+
+		_, err := repo.SyncRewardRules(ctx, rules, "new")
+		assert.Error(t, err)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRewardRuleRepository_ListRewardRules(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewRewardRuleRepository(mock)
+	ctx := context.Background()
+	checkedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	changedAt := checkedAt.Add(-time.Hour)
+
+	t.Run("Synced", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT checked_at, changed_at FROM accrual_reward_rule_sync WHERE id`).
+			WillReturnRows(pgxmock.NewRows([]string{"checked_at", "changed_at"}).AddRow(checkedAt, changedAt))
+		mock.ExpectQuery(`SELECT match, reward, reward_type, updated_at FROM accrual_reward_rules ORDER BY match`).
+			WillReturnRows(pgxmock.NewRows([]string{"match", "reward", "reward_type", "updated_at"}).
+				AddRow("Acer", 50.0, domain.RewardTypePoints, changedAt).
+				AddRow("Bork", 10.0, domain.RewardTypePercent, changedAt))
+
+		set, err := repo.ListRewardRules(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, &domain.RewardRuleSet{
+			Rules: []*domain.RewardRule{
+				{Match: "Acer", Reward: 50, RewardType: domain.RewardTypePoints, UpdatedAt: changedAt},
+				{Match: "Bork", Reward: 10, RewardType: domain.RewardTypePercent, UpdatedAt: changedAt},
+			},
+			CheckedAt: checkedAt,
+			ChangedAt: changedAt,
+		}, set)
+	})
+
+	t.Run("Never synced", func(t *testing.T) {
+		mock.ExpectQuery(`FROM accrual_reward_rule_sync`).
+			WillReturnError(pgx.ErrNoRows)
+		mock.ExpectQuery(`FROM accrual_reward_rules`).
+			WillReturnRows(pgxmock.NewRows([]string{"match", "reward", "reward_type", "updated_at"}))
+
+		set, err := repo.ListRewardRules(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, &domain.RewardRuleSet{}, set)
+	})
+
+	t.Run("Database error", func(t *testing.T) {
+		mock.ExpectQuery(`FROM accrual_reward_rule_sync`).
+			WillReturnError(errors.New("connection refused"))
+
+		_, err := repo.ListRewardRules(ctx)
+		assert.Error(t, err)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,163 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// SettlementRepository реализует хранение выгрузок списаний для взаиморасчетов с партнерами.
+type SettlementRepository struct {
+	db DBTX
+}
+
+// NewSettlementRepository создает новый SettlementRepository
+func NewSettlementRepository(db DBTX) *SettlementRepository {
+	return &SettlementRepository{db: db}
+}
+
+// settleWithdrawalsQuery помечает выгрузкой $1 еще не выгруженные списания до ее границы.
+// Списания, перенесенные при объединении учетных записей, выгружаются по исходной записи.
+// Параллельная выгрузка ждет блокировки строк и после нее уже не видит их невыгруженными,
+// поэтому одно списание не попадает в две выгрузки
+const settleWithdrawalsQuery = `
+	WITH settled AS (
+		UPDATE transactions SET settlement_id = $1
+		WHERE type = $2 AND settlement_id IS NULL AND merged_from IS NULL
+			AND processed_at < (SELECT cutoff FROM settlements WHERE id = $1)
+		RETURNING amount
+	)
+	SELECT COUNT(*), COALESCE(SUM(-amount), 0) FROM settled`
+
+// CreateSettlement создает выгрузку завершенных списаний до начала текущих суток.
+// Граница берется по часам БД, как и время списаний. createdBy - администратор, nil - расписание.
+// Если выгружать нечего, возвращает ErrNothingToSettle, и выгрузка не сохраняется.
+func (r *SettlementRepository) CreateSettlement(ctx context.Context, createdBy *int64) (*domain.Settlement, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to begin settlement transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // Rollback после Commit безопасен
+
+	settlement := &domain.Settlement{ID: newPublicID(), CreatedBy: createdBy}
+
+	err = tx.QueryRow(ctx,
+		`INSERT INTO settlements (id, cutoff, created_by) 
+		 VALUES ($1, date_trunc('day', LOCALTIMESTAMP), $2) 
+		 RETURNING cutoff, created_at`,
+		settlement.ID, createdBy,
+	).Scan(&settlement.Cutoff, &settlement.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to create settlement: %w", err)
+	}
+
+	err = tx.QueryRow(ctx, settleWithdrawalsQuery, settlement.ID, domain.TransactionTypeWithdrawal).
+		Scan(&settlement.Withdrawals, &settlement.Total)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to settle withdrawals: %w", err)
+	}
+	if settlement.Withdrawals == 0 {
+		return nil, domain.ErrNothingToSettle
+	}
+
+	_, err = tx.Exec(ctx,
+		`UPDATE settlements SET withdrawals = $2, total = $3 WHERE id = $1`,
+		settlement.ID, settlement.Withdrawals, settlement.Total,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to update settlement %s totals: %w", settlement.ID, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("repository: failed to commit settlement %s: %w", settlement.ID, err)
+	}
+
+	return settlement, nil
+}
+
+// GetSettlement получает выгрузку по идентификатору
+func (r *SettlementRepository) GetSettlement(ctx context.Context, id uuid.UUID) (*domain.Settlement, error) {
+	settlement := &domain.Settlement{}
+
+	err := r.db.QueryRow(ctx,
+		`SELECT id, cutoff, withdrawals, total, created_by, created_at 
+		 FROM settlements 
+		 WHERE id = $1`,
+		id,
+	).Scan(&settlement.ID, &settlement.Cutoff, &settlement.Withdrawals, &settlement.Total, &settlement.CreatedBy, &settlement.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrSettlementNotFound
+		}
+		return nil, fmt.Errorf("repository: failed to get settlement %s: %w", id, err)
+	}
+
+	return settlement, nil
+}
+
+// ListSettlements получает страницу выгрузок, новые первыми
+func (r *SettlementRepository) ListSettlements(ctx context.Context, limit, offset int) ([]*domain.Settlement, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT id, cutoff, withdrawals, total, created_by, created_at 
+		 FROM settlements 
+		 ORDER BY created_at DESC, id DESC 
+		 LIMIT $1 OFFSET $2`,
+		limit, offset,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to list settlements: %w", err)
+	}
+	defer rows.Close()
+
+	var settlements []*domain.Settlement
+	for rows.Next() {
+		settlement := &domain.Settlement{}
+		err := rows.Scan(&settlement.ID, &settlement.Cutoff, &settlement.Withdrawals, &settlement.Total, &settlement.CreatedBy, &settlement.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("repository: failed to scan settlement: %w", err)
+		}
+		settlements = append(settlements, settlement)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repository: error iterating settlements: %w", err)
+	}
+
+	return settlements, nil
+}
+
+// GetSettlementLines считает итоги выгрузки по партнерам и суткам. Итоги строятся по
+// помеченным списаниям, поэтому повторное скачивание дает тот же файл
+func (r *SettlementRepository) GetSettlementLines(ctx context.Context, id uuid.UUID) ([]*domain.SettlementLine, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT date_trunc('day', processed_at) AS day, COALESCE(partner, '') AS partner, COUNT(*), SUM(-amount) 
+		 FROM transactions 
+		 WHERE settlement_id = $1 
+		 GROUP BY 1, 2 
+		 ORDER BY 1, 2`,
+		id,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to get settlement %s lines: %w", id, err)
+	}
+	defer rows.Close()
+
+	var lines []*domain.SettlementLine
+	for rows.Next() {
+		line := &domain.SettlementLine{}
+		if err := rows.Scan(&line.Day, &line.Partner, &line.Withdrawals, &line.Total); err != nil {
+			return nil, fmt.Errorf("repository: failed to scan settlement line: %w", err)
+		}
+		lines = append(lines, line)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repository: error iterating settlement lines: %w", err)
+	}
+
+	return lines, nil
+}
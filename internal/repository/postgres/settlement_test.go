@@ -0,0 +1,163 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSettlementRepository_CreateSettlement(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewSettlementRepository(mock)
+	ctx := context.Background()
+	adminID := int64(1)
+	cutoff := time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)
+	createdAt := time.Date(2024, 3, 2, 1, 0, 0, 0, time.UTC)
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO settlements`).
+			WithArgs(pgxmock.AnyArg(), &adminID).
+			WillReturnRows(pgxmock.NewRows([]string{"cutoff", "created_at"}).AddRow(cutoff, createdAt))
+		mock.ExpectQuery(`UPDATE transactions SET settlement_id`).
+			WithArgs(pgxmock.AnyArg(), domain.TransactionTypeWithdrawal).
+			WillReturnRows(pgxmock.NewRows([]string{"count", "sum"}).AddRow(3, 1250.5))
+		mock.ExpectExec(`UPDATE settlements SET withdrawals`).
+			WithArgs(pgxmock.AnyArg(), 3, 1250.5).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectCommit()
+
+		settlement, err := repo.CreateSettlement(ctx, &adminID)
+		require.NoError(t, err)
+		assert.NotEqual(t, uuid.Nil, settlement.ID)
+		assert.Equal(t, cutoff, settlement.Cutoff)
+		assert.Equal(t, 3, settlement.Withdrawals)
+		assert.Equal(t, 1250.5, settlement.Total)
+		assert.Equal(t, &adminID, settlement.CreatedBy)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Nothing to settle", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO settlements`).
+			WithArgs(pgxmock.AnyArg(), (*int64)(nil)).
+			WillReturnRows(pgxmock.NewRows([]string{"cutoff", "created_at"}).AddRow(cutoff, createdAt))
+		mock.ExpectQuery(`UPDATE transactions SET settlement_id`).
+			WithArgs(pgxmock.AnyArg(), domain.TransactionTypeWithdrawal).
+			WillReturnRows(pgxmock.NewRows([]string{"count", "sum"}).AddRow(0, 0.0))
+		mock.ExpectRollback()
+
+		_, err := repo.CreateSettlement(ctx, nil)
+		assert.ErrorIs(t, err, domain.ErrNothingToSettle)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Database error", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO settlements`).
+			WithArgs(pgxmock.AnyArg(), (*int64)(nil)).
+			WillReturnError(errors.New("database error"))
+		mock.ExpectRollback()
+
+		_, err := repo.CreateSettlement(ctx, nil)
+		assert.Error(t, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestSettlementRepository_GetSettlement(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewSettlementRepository(mock)
+	ctx := context.Background()
+	id := uuid.New()
+	columns := []string{"id", "cutoff", "withdrawals", "total", "created_by", "created_at"}
+
+	t.Run("Found", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT .+ FROM settlements WHERE id`).
+			WithArgs(id).
+			WillReturnRows(pgxmock.NewRows(columns).AddRow(id, time.Now(), 2, 300.0, nil, time.Now()))
+
+		settlement, err := repo.GetSettlement(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, id, settlement.ID)
+		assert.Nil(t, settlement.CreatedBy)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT .+ FROM settlements WHERE id`).
+			WithArgs(id).
+			WillReturnError(pgx.ErrNoRows)
+
+		_, err := repo.GetSettlement(ctx, id)
+		assert.ErrorIs(t, err, domain.ErrSettlementNotFound)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestSettlementRepository_ListSettlements(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewSettlementRepository(mock)
+	columns := []string{"id", "cutoff", "withdrawals", "total", "created_by", "created_at"}
+	adminID := int64(1)
+
+	mock.ExpectQuery(`SELECT .+ FROM settlements ORDER BY created_at DESC`).
+		WithArgs(11, 20).
+		WillReturnRows(pgxmock.NewRows(columns).
+			AddRow(uuid.New(), time.Now(), 2, 300.0, &adminID, time.Now()).
+			AddRow(uuid.New(), time.Now(), 1, 50.0, nil, time.Now()))
+
+	settlements, err := repo.ListSettlements(context.Background(), 11, 20)
+	require.NoError(t, err)
+	require.Len(t, settlements, 2)
+	assert.Equal(t, &adminID, settlements[0].CreatedBy)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSettlementRepository_GetSettlementLines(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewSettlementRepository(mock)
+	id := uuid.New()
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`SELECT date_trunc\('day', processed_at\) .+ FROM transactions WHERE settlement_id = \$1 GROUP BY 1, 2`).
+		WithArgs(id).
+		WillReturnRows(pgxmock.NewRows([]string{"day", "partner", "count", "sum"}).
+			AddRow(day, "", 1, 100.0).
+			AddRow(day, "store-42", 2, 250.5))
+
+	lines, err := repo.GetSettlementLines(context.Background(), id)
+	require.NoError(t, err)
+	assert.Equal(t, []*domain.SettlementLine{
+		{Day: day, Partner: "", Withdrawals: 1, Total: 100},
+		{Day: day, Partner: "store-42", Withdrawals: 2, Total: 250.5},
+	}, lines)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,128 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+// SubmissionBanRepository хранит временные запреты загрузки заказов.
+// Сроки отсчитываются по часам БД, общим для всех реплик
+type SubmissionBanRepository struct {
+	db DBTX
+}
+
+// NewSubmissionBanRepository создает новый SubmissionBanRepository
+func NewSubmissionBanRepository(db DBTX) *SubmissionBanRepository {
+	return &SubmissionBanRepository{db: db}
+}
+
+// GetSubmissionBan получает действующий запрет пользователя.
+// Если запрета нет или он истек, возвращает ErrSubmissionBanNotFound
+func (r *SubmissionBanRepository) GetSubmissionBan(ctx context.Context, userID int64) (*domain.SubmissionBan, error) {
+	ban := &domain.SubmissionBan{UserID: userID}
+
+	err := r.db.QueryRow(ctx,
+		`SELECT reason, created_at, banned_until 
+		 FROM order_submission_bans 
+		 WHERE user_id = $1 AND banned_until > NOW()`,
+		userID,
+	).Scan(&ban.Reason, &ban.CreatedAt, &ban.BannedUntil)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrSubmissionBanNotFound
+		}
+		return nil, fmt.Errorf("repository: failed to get submission ban for user %d: %w", userID, err)
+	}
+
+	return ban, nil
+}
+
+// BanSubmissions запрещает пользователю загружать заказы на duration. Действующий
+// запрет продлевается, если новый заканчивается позже
+func (r *SubmissionBanRepository) BanSubmissions(ctx context.Context, userID int64, reason string, duration time.Duration) (*domain.SubmissionBan, error) {
+	ban := &domain.SubmissionBan{UserID: userID}
+
+	err := r.db.QueryRow(ctx,
+		`INSERT INTO order_submission_bans (user_id, reason, banned_until) 
+		 VALUES ($1, $2, NOW() + make_interval(secs => $3)) 
+		 ON CONFLICT (user_id) DO UPDATE SET 
+		   reason = EXCLUDED.reason, 
+		   created_at = CASE WHEN order_submission_bans.banned_until > NOW() 
+		     THEN order_submission_bans.created_at ELSE EXCLUDED.created_at END, 
+		   banned_until = GREATEST(order_submission_bans.banned_until, EXCLUDED.banned_until) 
+		 RETURNING reason, created_at, banned_until`,
+		userID, reason, duration.Seconds(),
+	).Scan(&ban.Reason, &ban.CreatedAt, &ban.BannedUntil)
+
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to ban submissions for user %d: %w", userID, err)
+	}
+
+	return ban, nil
+}
+
+// ListSubmissionBans возвращает действующие запреты, ближайшие к окончанию первыми
+func (r *SubmissionBanRepository) ListSubmissionBans(ctx context.Context) ([]*domain.SubmissionBan, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT b.user_id, u.login, b.reason, b.created_at, b.banned_until 
+		 FROM order_submission_bans b 
+		 JOIN users u ON u.id = b.user_id 
+		 WHERE b.banned_until > NOW() 
+		 ORDER BY b.banned_until, b.user_id`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to list submission bans: %w", err)
+	}
+	defer rows.Close()
+
+	var bans []*domain.SubmissionBan
+	for rows.Next() {
+		ban := &domain.SubmissionBan{}
+		if err := rows.Scan(&ban.UserID, &ban.Login, &ban.Reason, &ban.CreatedAt, &ban.BannedUntil); err != nil {
+			return nil, fmt.Errorf("repository: failed to scan submission ban: %w", err)
+		}
+		bans = append(bans, ban)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repository: failed to iterate submission bans: %w", err)
+	}
+
+	return bans, nil
+}
+
+// LiftSubmissionBan снимает запрет пользователя досрочно. Возвращает ErrUserNotFound
+// для неизвестного логина и ErrSubmissionBanNotFound, если действующего запрета нет
+func (r *SubmissionBanRepository) LiftSubmissionBan(ctx context.Context, login string) error {
+	var lifted bool
+
+	err := r.db.QueryRow(ctx,
+		`WITH lifted AS ( 
+		   DELETE FROM order_submission_bans b 
+		   USING users u 
+		   WHERE u.id = b.user_id AND u.login = $1 AND b.banned_until > NOW() 
+		   RETURNING b.user_id 
+		 ) 
+		 SELECT EXISTS (SELECT 1 FROM lifted) 
+		 FROM users 
+		 WHERE login = $1`,
+		login,
+	).Scan(&lifted)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrUserNotFound
+		}
+		return fmt.Errorf("repository: failed to lift submission ban for %q: %w", login, err)
+	}
+	if !lifted {
+		return domain.ErrSubmissionBanNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubmissionBanRepository_GetSubmissionBan(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewSubmissionBanRepository(mock)
+	ctx := context.Background()
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	t.Run("Active ban", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT reason, created_at, banned_until FROM order_submission_bans WHERE user_id = \$1 AND banned_until > NOW\(\)`).
+			WithArgs(int64(1)).
+			WillReturnRows(pgxmock.NewRows([]string{"reason", "created_at", "banned_until"}).
+				AddRow(domain.SubmissionBanReasonConflicts, createdAt, createdAt.Add(time.Hour)))
+
+		ban, err := repo.GetSubmissionBan(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, &domain.SubmissionBan{
+			UserID:      1,
+			Reason:      domain.SubmissionBanReasonConflicts,
+			CreatedAt:   createdAt,
+			BannedUntil: createdAt.Add(time.Hour),
+		}, ban)
+	})
+
+	t.Run("No ban", func(t *testing.T) {
+		mock.ExpectQuery(`FROM order_submission_bans`).
+			WithArgs(int64(2)).
+			WillReturnError(pgx.ErrNoRows)
+
+		_, err := repo.GetSubmissionBan(ctx, 2)
+		assert.ErrorIs(t, err, domain.ErrSubmissionBanNotFound)
+	})
+
+	t.Run("Database error", func(t *testing.T) {
+		mock.ExpectQuery(`FROM order_submission_bans`).
+			WithArgs(int64(3)).
+			WillReturnError(errors.New("connection refused"))
+
+		_, err := repo.GetSubmissionBan(ctx, 3)
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, domain.ErrSubmissionBanNotFound)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSubmissionBanRepository_BanSubmissions(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewSubmissionBanRepository(mock)
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	mock.ExpectQuery(`INSERT INTO order_submission_bans \(user_id, reason, banned_until\) VALUES \(\$1, \$2, NOW\(\) \+ make_interval\(secs => \$3\)\) ON CONFLICT \(user_id\) DO UPDATE`).
+		WithArgs(int64(1), domain.SubmissionBanReasonConflicts, float64(3600)).
+		WillReturnRows(pgxmock.NewRows([]string{"reason", "created_at", "banned_until"}).
+			AddRow(domain.SubmissionBanReasonConflicts, createdAt, createdAt.Add(time.Hour)))
+
+	ban, err := repo.BanSubmissions(context.Background(), 1, domain.SubmissionBanReasonConflicts, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, createdAt.Add(time.Hour), ban.BannedUntil)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSubmissionBanRepository_ListSubmissionBans(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewSubmissionBanRepository(mock)
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	mock.ExpectQuery(`SELECT b.user_id, u.login, b.reason, b.created_at, b.banned_until FROM order_submission_bans b JOIN users u`).
+		WillReturnRows(pgxmock.NewRows([]string{"user_id", "login", "reason", "created_at", "banned_until"}).
+			AddRow(int64(1), "alice", domain.SubmissionBanReasonConflicts, createdAt, createdAt.Add(time.Hour)).
+			AddRow(int64(2), "bob", domain.SubmissionBanReasonConflicts, createdAt, createdAt.Add(2*time.Hour)))
+
+	bans, err := repo.ListSubmissionBans(context.Background())
+	require.NoError(t, err)
+	require.Len(t, bans, 2)
+	assert.Equal(t, "alice", bans[0].Login)
+	assert.Equal(t, int64(2), bans[1].UserID)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSubmissionBanRepository_LiftSubmissionBan(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewSubmissionBanRepository(mock)
+	ctx := context.Background()
+
+	t.Run("Lifted", func(t *testing.T) {
+		mock.ExpectQuery(`WITH lifted AS \( DELETE FROM order_submission_bans b USING users u`).
+			WithArgs("alice").
+			WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(true))
+
+		assert.NoError(t, repo.LiftSubmissionBan(ctx, "alice"))
+	})
+
+	t.Run("No active ban", func(t *testing.T) {
+		mock.ExpectQuery(`WITH lifted AS`).
+			WithArgs("bob").
+			WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(false))
+
+		assert.ErrorIs(t, repo.LiftSubmissionBan(ctx, "bob"), domain.ErrSubmissionBanNotFound)
+	})
+
+	t.Run("Unknown user", func(t *testing.T) {
+		mock.ExpectQuery(`WITH lifted AS`).
+			WithArgs("nobody").
+			WillReturnError(pgx.ErrNoRows)
+
+		assert.ErrorIs(t, repo.LiftSubmissionBan(ctx, "nobody"), domain.ErrUserNotFound)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/avc/loyalty-system-diploma/internal/domain"
-	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 )
 
 // TransactionRepository реализует репозиторий транзакций.
@@ -19,19 +21,26 @@ func NewTransactionRepository(db DBTX) *TransactionRepository {
 	return &TransactionRepository{db: db}
 }
 
+// WithTx возвращает репозиторий транзакций, работающий в транзакции tx
+func (r *TransactionRepository) WithTx(tx pgx.Tx) *TransactionRepository {
+	return &TransactionRepository{db: tx}
+}
+
 // CreateTransaction создает новую транзакцию (начисление или списание)
 func (r *TransactionRepository) CreateTransaction(ctx context.Context, userID int64, orderNumber string, amount float64, txType domain.TransactionType) error {
 	_, err := r.db.Exec(ctx,
-		`INSERT INTO transactions (user_id, order_number, amount, type) 
-		 VALUES ($1, $2, $3, $4)`,
-		userID, orderNumber, amount, txType,
+		`INSERT INTO transactions (user_id, order_number, amount, type, public_id) 
+		 VALUES ($1, $2, $3, $4, $5)`,
+		userID, orderNumber, amount, txType, newPublicID(),
 	)
 
 	if err != nil {
 		// Проверяем на дублирование начисления (unique constraint violation)
-		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) && pgErr.Code == "23505" && txType == domain.TransactionTypeAccrual {
-			return ErrDuplicateAccrual
+		if isUniqueViolation(err) && txType == domain.TransactionTypeAccrual {
+			return domain.ErrDuplicateAccrual
+		}
+		if isForeignKeyViolation(err) {
+			return domain.ErrUserNotFound
 		}
 		return fmt.Errorf("repository: failed to create transaction for user %d: %w", userID, err)
 	}
@@ -39,38 +48,49 @@ func (r *TransactionRepository) CreateTransaction(ctx context.Context, userID in
 	return nil
 }
 
-// GetBalance получает баланс пользователя через группировку транзакций
+// GetBalance получает баланс пользователя из user_balances, который ведет триггер журнала.
+// Отрицательная корректировка начисления уменьшает баланс, а не увеличивает списанное.
+// Нет строки баланса - у пользователя еще не было транзакций
 func (r *TransactionRepository) GetBalance(ctx context.Context, userID int64) (*domain.Balance, error) {
 	balance := &domain.Balance{}
 
 	err := r.db.QueryRow(ctx,
-		`SELECT 
-			COALESCE(SUM(CASE WHEN amount > 0 THEN amount ELSE 0 END), 0) as total_accrued,
-			COALESCE(SUM(CASE WHEN amount < 0 THEN ABS(amount) ELSE 0 END), 0) as total_withdrawn
-		 FROM transactions 
+		`SELECT balance, withdrawn 
+		 FROM user_balances 
 		 WHERE user_id = $1`,
 		userID,
 	).Scan(&balance.Current, &balance.Withdrawn)
 
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return balance, nil
+		}
 		return nil, fmt.Errorf("repository: failed to get balance for user %d: %w", userID, err)
 	}
 
-	// Current = accrued - withdrawn
-	balance.Current = balance.Current - balance.Withdrawn
-
 	return balance, nil
 }
 
-// GetWithdrawals получает историю списаний пользователя
-func (r *TransactionRepository) GetWithdrawals(ctx context.Context, userID int64) ([]*domain.Transaction, error) {
-	rows, err := r.db.Query(ctx,
-		`SELECT id, user_id, order_number, ABS(amount) as amount, type, processed_at 
+// GetWithdrawals получает историю списаний пользователя от новых к старым, начиная после
+// позиции after (nil - с начала). Нулевой limit не ограничивает выборку
+func (r *TransactionRepository) GetWithdrawals(ctx context.Context, userID int64, after *domain.WithdrawalCursor, limit int) ([]*domain.Transaction, error) {
+	query := `SELECT id, public_id, user_id, order_number, ABS(amount) as amount, type, processed_at 
 		 FROM transactions 
-		 WHERE user_id = $1 AND type = $2 
-		 ORDER BY processed_at DESC`,
-		userID, domain.TransactionTypeWithdrawal,
-	)
+		 WHERE user_id = $1 AND type = $2 AND amount < 0`
+	args := []any{userID, domain.TransactionTypeWithdrawal}
+
+	// Позиция по паре (processed_at, id): списания с одинаковым временем не теряются на границе страниц
+	if after != nil {
+		args = append(args, after.ProcessedAt.UTC(), after.ID)
+		query += fmt.Sprintf(` AND (processed_at, id) < ($%d, $%d)`, len(args)-1, len(args))
+	}
+	query += ` ORDER BY processed_at DESC, id DESC`
+	if limit > 0 {
+		args = append(args, limit)
+		query += fmt.Sprintf(` LIMIT $%d`, len(args))
+	}
+
+	rows, err := r.db.Query(ctx, query, args...)
 
 	if err != nil {
 		return nil, fmt.Errorf("repository: failed to get withdrawals for user %d: %w", userID, err)
@@ -80,7 +100,7 @@ func (r *TransactionRepository) GetWithdrawals(ctx context.Context, userID int64
 	var transactions []*domain.Transaction
 	for rows.Next() {
 		tx := &domain.Transaction{}
-		err := rows.Scan(&tx.ID, &tx.UserID, &tx.OrderNumber, &tx.Amount, &tx.Type, &tx.ProcessedAt)
+		err := rows.Scan(&tx.ID, &tx.PublicID, &tx.UserID, &tx.OrderNumber, &tx.Amount, &tx.Type, &tx.ProcessedAt)
 		if err != nil {
 			return nil, fmt.Errorf("repository: failed to scan transaction: %w", err)
 		}
@@ -94,8 +114,111 @@ func (r *TransactionRepository) GetWithdrawals(ctx context.Context, userID int64
 	return transactions, nil
 }
 
-// WithdrawWithLock списывает средства с блокировкой для обеспечения атомарности
-func (r *TransactionRepository) WithdrawWithLock(ctx context.Context, userID int64, orderNumber string, amount float64) error {
+// GetTransactions возвращает все транзакции пользователя со знаком суммы, от старых к новым
+func (r *TransactionRepository) GetTransactions(ctx context.Context, userID int64) ([]*domain.Transaction, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT id, public_id, user_id, order_number, amount, type, processed_at
+		 FROM transactions
+		 WHERE user_id = $1
+		 ORDER BY processed_at, id`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to get transactions for user %d: %w", userID, err)
+	}
+	defer rows.Close()
+
+	var transactions []*domain.Transaction
+	for rows.Next() {
+		tx := &domain.Transaction{}
+		if err := rows.Scan(&tx.ID, &tx.PublicID, &tx.UserID, &tx.OrderNumber, &tx.Amount, &tx.Type, &tx.ProcessedAt); err != nil {
+			return nil, fmt.Errorf("repository: failed to scan transaction: %w", err)
+		}
+		transactions = append(transactions, tx)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repository: error iterating transactions: %w", err)
+	}
+
+	return transactions, nil
+}
+
+// GetWithdrawalSummary считает сводку по списаниям пользователя одним агрегирующим запросом
+func (r *TransactionRepository) GetWithdrawalSummary(ctx context.Context, userID int64) (*domain.WithdrawalSummary, error) {
+	summary := &domain.WithdrawalSummary{}
+
+	err := r.db.QueryRow(ctx,
+		`SELECT COUNT(*), COALESCE(SUM(ABS(amount)), 0), COALESCE(MAX(ABS(amount)), 0), MAX(processed_at)
+		 FROM transactions 
+		 WHERE user_id = $1 AND type = $2 AND amount < 0`,
+		userID, domain.TransactionTypeWithdrawal,
+	).Scan(&summary.Count, &summary.Total, &summary.Largest, &summary.LastAt)
+
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to get withdrawal summary for user %d: %w", userID, err)
+	}
+
+	return summary, nil
+}
+
+// GetWithdrawalByPublicID получает списание пользователя по публичному идентификатору
+func (r *TransactionRepository) GetWithdrawalByPublicID(ctx context.Context, userID int64, publicID uuid.UUID) (*domain.Transaction, error) {
+	tx := &domain.Transaction{}
+
+	err := r.db.QueryRow(ctx,
+		`SELECT id, public_id, user_id, order_number, ABS(amount) as amount, type, processed_at 
+		 FROM transactions 
+		 WHERE public_id = $1 AND user_id = $2 AND type = $3 AND amount < 0`,
+		publicID, userID, domain.TransactionTypeWithdrawal,
+	).Scan(&tx.ID, &tx.PublicID, &tx.UserID, &tx.OrderNumber, &tx.Amount, &tx.Type, &tx.ProcessedAt)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrTransactionNotFound
+		}
+		return nil, fmt.Errorf("repository: failed to get withdrawal %s: %w", publicID, err)
+	}
+
+	return tx, nil
+}
+
+// withdrawalTotalsQuery считает списания пользователя за последние сутки и 30 дней
+const withdrawalTotalsQuery = `
+	SELECT 
+		COALESCE(SUM(-amount) FILTER (WHERE processed_at > NOW() - INTERVAL '1 day'), 0) AS daily,
+		COALESCE(SUM(-amount), 0) AS monthly
+	FROM transactions 
+	WHERE user_id = $1 AND type = $2 AND processed_at > NOW() - INTERVAL '30 days'`
+
+// lockBalancesTx блокирует до конца транзакции tx строки баланса пользователей в порядке
+// возрастания ID. Ту же строку блокируют WithdrawWithLock и триггер цепочки хешей, поэтому
+// операция под ней выполняется последовательно со списаниями. Нет строки - она создается
+// с нулевым балансом, как это сделал бы триггер баланса при первой транзакции
+func lockBalancesTx(ctx context.Context, tx pgx.Tx, userIDs ...int64) error {
+	ids := slices.Clone(userIDs)
+	slices.Sort(ids)
+	for _, id := range slices.Compact(ids) {
+		_, err := tx.Exec(ctx,
+			`INSERT INTO user_balances (user_id) VALUES ($1) 
+			 ON CONFLICT (user_id) DO UPDATE SET updated_at = user_balances.updated_at`,
+			id,
+		)
+		if err != nil {
+			return fmt.Errorf("repository: failed to lock balance of user %d: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// WithdrawWithLock списывает средства с блокировкой для обеспечения атомарности.
+// Блокируется строка баланса пользователя: параллельные списания разных пользователей
+// друг друга не ждут. Триггер цепочки хешей при вставке берет ту же блокировку
+// (см. миграцию 000031), поэтому отдельной очереди вставок в журнал нет.
+// Ограничения limits проверяются под той же блокировкой, поэтому
+// параллельные списания не могут вместе превысить суточный или месячный лимит.
+// Пустой partner - списание без партнера.
+func (r *TransactionRepository) WithdrawWithLock(ctx context.Context, userID int64, orderNumber string, amount float64, partner string, limits domain.WithdrawalLimits) error {
 	// Начинаем транзакцию
 	tx, err := r.db.Begin(ctx)
 	if err != nil {
@@ -103,34 +226,37 @@ func (r *TransactionRepository) WithdrawWithLock(ctx context.Context, userID int
 	}
 	defer tx.Rollback(ctx) //nolint:errcheck // Rollback после Commit безопасен
 
-	// Используем advisory lock для блокировки по user_id
-	// Это предотвращает race condition при параллельных списаниях
-	_, err = tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, userID)
+	// Проверяем достаточность средств и блокируем баланс одним запросом. Сам баланс
+	// уменьшит триггер при вставке списания; нет строки - у пользователя не было транзакций
+	result, err := tx.Exec(ctx,
+		`UPDATE user_balances SET updated_at = NOW() 
+		 WHERE user_id = $1 AND balance >= $2`,
+		userID, amount,
+	)
 	if err != nil {
-		return fmt.Errorf("repository: failed to acquire lock for user %d: %w", userID, err)
+		return fmt.Errorf("repository: failed to lock balance for user %d: %w", userID, err)
 	}
-
-	// Получаем баланс
-	var balance float64
-	err = tx.QueryRow(ctx, `
-		SELECT COALESCE(SUM(amount), 0) 
-		FROM transactions 
-		WHERE user_id = $1`, userID).Scan(&balance)
-
-	if err != nil {
-		return fmt.Errorf("repository: failed to get balance for user %d: %w", userID, err)
+	if result.RowsAffected() == 0 {
+		return domain.ErrInsufficientFunds
 	}
 
-	// Проверяем достаточность средств
-	if balance < amount {
-		return ErrInsufficientFunds
+	// Проверяем ограничения; суммы за окна нужны, только если такие ограничения заданы
+	var daily, monthly float64
+	if limits.HasWindows() {
+		err = tx.QueryRow(ctx, withdrawalTotalsQuery, userID, domain.TransactionTypeWithdrawal).Scan(&daily, &monthly)
+		if err != nil {
+			return fmt.Errorf("repository: failed to get withdrawal totals for user %d: %w", userID, err)
+		}
+	}
+	if err := limits.Check(amount, daily, monthly); err != nil {
+		return err
 	}
 
 	// Создаем транзакцию списания (отрицательная сумма)
 	_, err = tx.Exec(ctx,
-		`INSERT INTO transactions (user_id, order_number, amount, type) 
-		 VALUES ($1, $2, $3, $4)`,
-		userID, orderNumber, -amount, domain.TransactionTypeWithdrawal,
+		`INSERT INTO transactions (user_id, order_number, amount, type, public_id, partner) 
+		 VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''))`,
+		userID, orderNumber, -amount, domain.TransactionTypeWithdrawal, newPublicID(), partner,
 	)
 
 	if err != nil {
@@ -144,3 +270,71 @@ func (r *TransactionRepository) WithdrawWithLock(ctx context.Context, userID int
 
 	return nil
 }
+
+// verifyLedgerChainQuery пересчитывает хеш каждой записи цепочки пользователя от хеша
+// предыдущей той же функцией, что и триггер вставки, и находит первый разрыв.
+// Общий хеш - SHA-256 от хешей последних записей всех цепочек по возрастанию user_id
+const verifyLedgerChainQuery = `
+	WITH chain AS (
+		SELECT user_id, chain_seq, public_id, chain_hash,
+			LAG(chain_seq) OVER w AS prev_seq,
+			transactions_chain_hash(LAG(chain_hash) OVER w, user_id, public_id, order_number, amount, type, processed_at) AS expected
+		FROM transactions
+		WHERE chain_seq IS NOT NULL
+		WINDOW w AS (PARTITION BY user_id ORDER BY chain_seq)
+	), heads AS (
+		SELECT DISTINCT ON (user_id) user_id, chain_hash FROM chain ORDER BY user_id, chain_seq DESC
+	), broken AS (
+		SELECT user_id, chain_seq, public_id,
+			CASE WHEN chain_seq <> COALESCE(prev_seq, 0) + 1 THEN 'gap' ELSE 'hash_mismatch' END AS reason
+		FROM chain
+		WHERE chain_seq <> COALESCE(prev_seq, 0) + 1 OR chain_hash IS DISTINCT FROM expected
+		ORDER BY user_id, chain_seq
+		LIMIT 1
+	)
+	SELECT
+		(SELECT COUNT(*) FROM chain),
+		(SELECT COUNT(*) FROM transactions WHERE chain_seq IS NULL),
+		(SELECT COUNT(*) FROM heads),
+		(SELECT sha256(convert_to(string_agg(user_id::TEXT || ':' || encode(chain_hash, 'hex'), ',' ORDER BY user_id), 'UTF8')) FROM heads),
+		(SELECT user_id FROM broken),
+		(SELECT u.login FROM broken b JOIN users u ON u.id = b.user_id),
+		(SELECT chain_seq FROM broken),
+		(SELECT public_id FROM broken),
+		(SELECT reason FROM broken)`
+
+// VerifyLedgerChain проверяет цепочки хешей журнала транзакций всех пользователей
+func (r *TransactionRepository) VerifyLedgerChain(ctx context.Context) (*domain.LedgerVerification, error) {
+	result := &domain.LedgerVerification{}
+	var (
+		brokenUserID *int64
+		brokenLogin  *string
+		brokenSeq    *int64
+		brokenID     *uuid.UUID
+		brokenReason *string
+	)
+
+	err := r.db.QueryRow(ctx, verifyLedgerChainQuery).Scan(
+		&result.Checked, &result.Unchained, &result.Chains, &result.HeadHash,
+		&brokenUserID, &brokenLogin, &brokenSeq, &brokenID, &brokenReason,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to verify ledger chain: %w", err)
+	}
+
+	if brokenSeq != nil {
+		result.Break = &domain.LedgerBreak{
+			UserID: *brokenUserID,
+			Seq:    *brokenSeq,
+			Reason: domain.LedgerBreakReason(*brokenReason),
+		}
+		if brokenLogin != nil {
+			result.Break.Login = *brokenLogin
+		}
+		if brokenID != nil {
+			result.Break.PublicID = *brokenID
+		}
+	}
+
+	return result, nil
+}
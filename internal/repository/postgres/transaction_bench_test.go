@@ -0,0 +1,216 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// Бенчмаркам списаний нужна настоящая БД: отдельная база, в которую бенчмарки
+// пишут пользователей и транзакции, задается BENCH_DATABASE_URI. Миграции ставят
+// триггеры баланса и цепочки хешей, поэтому списания измеряются вместе с ними.
+//
+//	BENCH_DATABASE_URI=postgres://... go test ./internal/repository/postgres -run '^$' -bench WithdrawWithLock -cpu 1,8,32
+
+// benchHistory - число начислений в истории каждого пользователя бенчмарка
+const benchHistory = 5000
+
+var benchOrderSeq atomic.Int64
+
+func openBenchDB(b *testing.B) *pgxpool.Pool {
+	return openDB(b, "BENCH_DATABASE_URI")
+}
+
+// openDB подключается к БД из переменной окружения env и выполняет миграции.
+// Без переменной или с -short тест пропускается
+func openDB(tb testing.TB, env string) *pgxpool.Pool {
+	tb.Helper()
+	uri := os.Getenv(env)
+	if uri == "" || testing.Short() {
+		tb.Skip(env + " is not set")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, uri)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(pool.Close)
+	if err := RunMigrations(ctx, pool, zap.NewNop()); err != nil {
+		tb.Fatal(err)
+	}
+	return pool
+}
+
+// createBenchUsers создает пользователей с историей начислений, баланса которых хватит на все списания
+func createBenchUsers(b *testing.B, db *pgxpool.Pool, n int) []int64 {
+	b.Helper()
+	ctx := context.Background()
+	prefix := fmt.Sprintf("bench-%d", time.Now().UnixNano())
+
+	ids := make([]int64, n)
+	for i := range ids {
+		err := db.QueryRow(ctx,
+			`INSERT INTO users (login, password_hash) VALUES ($1, '') RETURNING id`,
+			fmt.Sprintf("%s-%d", prefix, i),
+		).Scan(&ids[i])
+		if err != nil {
+			b.Fatal(err)
+		}
+		_, err = db.Exec(ctx,
+			`INSERT INTO transactions (user_id, order_number, amount, type, public_id) 
+			 SELECT $1, $2 || '-' || s, 1000, $3, gen_random_uuid() 
+			 FROM generate_series(1, $4) s`,
+			ids[i], fmt.Sprintf("%s-%d", prefix, i), domain.TransactionTypeAccrual, benchHistory,
+		)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+	return ids
+}
+
+// withdrawWithAdvisoryLock - прежняя реализация списания для сравнения:
+// блокировка пользователя и сумма по всей истории под ней
+func withdrawWithAdvisoryLock(ctx context.Context, db *pgxpool.Pool, userID int64, orderNumber string, amount float64) error {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // Rollback после Commit безопасен
+
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, userID); err != nil {
+		return err
+	}
+	var balance float64
+	if err := tx.QueryRow(ctx, `SELECT COALESCE(SUM(amount), 0) FROM transactions WHERE user_id = $1`, userID).Scan(&balance); err != nil {
+		return err
+	}
+	if balance < amount {
+		return domain.ErrInsufficientFunds
+	}
+	_, err = tx.Exec(ctx,
+		`INSERT INTO transactions (user_id, order_number, amount, type, public_id) VALUES ($1, $2, $3, $4, $5)`,
+		userID, orderNumber, -amount, domain.TransactionTypeWithdrawal, newPublicID(),
+	)
+	if err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func BenchmarkWithdrawWithLock(b *testing.B) {
+	db := openBenchDB(b)
+	repo := NewTransactionRepository(db)
+
+	var chained bool
+	err := db.QueryRow(context.Background(),
+		`SELECT EXISTS (SELECT 1 FROM pg_trigger WHERE tgname = 'transactions_chain')`,
+	).Scan(&chained)
+	if err != nil {
+		b.Fatal(err)
+	}
+	if !chained {
+		b.Fatal("ledger chain trigger is not installed")
+	}
+
+	implementations := []struct {
+		name     string
+		withdraw func(ctx context.Context, userID int64, orderNumber string) error
+	}{
+		{name: "AdvisoryLockSum", withdraw: func(ctx context.Context, userID int64, orderNumber string) error {
+			return withdrawWithAdvisoryLock(ctx, db, userID, orderNumber, 0.01)
+		}},
+		{name: "BalanceRow", withdraw: func(ctx context.Context, userID int64, orderNumber string) error {
+			return repo.WithdrawWithLock(ctx, userID, orderNumber, 0.01, "", domain.WithdrawalLimits{})
+		}},
+	}
+
+	// Один пользователь - списания конкурируют за его блокировку,
+	// много пользователей - каждая горутина списывает у своего
+	for _, users := range []int{1, 32} {
+		for _, impl := range implementations {
+			b.Run(fmt.Sprintf("%s/users=%d", impl.name, users), func(b *testing.B) {
+				ids := createBenchUsers(b, db, users)
+				var next atomic.Int64
+
+				b.ResetTimer()
+				b.RunParallel(func(pb *testing.PB) {
+					ctx := context.Background()
+					userID := ids[int(next.Add(1)-1)%len(ids)]
+					for pb.Next() {
+						number := fmt.Sprintf("bench-w-%d", benchOrderSeq.Add(1))
+						if err := impl.withdraw(ctx, userID, number); err != nil {
+							b.Error(err)
+							return
+						}
+					}
+				})
+			})
+		}
+	}
+}
+
+// getBalanceBySum - прежняя реализация запроса баланса для сравнения: сумма по всей истории
+func getBalanceBySum(ctx context.Context, db *pgxpool.Pool, userID int64) (*domain.Balance, error) {
+	balance := &domain.Balance{}
+	err := db.QueryRow(ctx,
+		`SELECT 
+			COALESCE(SUM(amount), 0),
+			COALESCE(SUM(-amount) FILTER (WHERE type = $2), 0)
+		 FROM transactions 
+		 WHERE user_id = $1`,
+		userID, domain.TransactionTypeWithdrawal,
+	).Scan(&balance.Current, &balance.Withdrawn)
+	return balance, err
+}
+
+func BenchmarkGetBalance(b *testing.B) {
+	db := openBenchDB(b)
+	repo := NewTransactionRepository(db)
+	ctx := context.Background()
+
+	ids := createBenchUsers(b, db, 1)
+	if err := repo.WithdrawWithLock(ctx, ids[0], fmt.Sprintf("bench-w-%d", benchOrderSeq.Add(1)), 10, "", domain.WithdrawalLimits{}); err != nil {
+		b.Fatal(err)
+	}
+
+	// Баланс из user_balances должен совпадать с суммой по журналу
+	want, err := getBalanceBySum(ctx, db, ids[0])
+	if err != nil {
+		b.Fatal(err)
+	}
+	got, err := repo.GetBalance(ctx, ids[0])
+	if err != nil {
+		b.Fatal(err)
+	}
+	if *got != *want {
+		b.Fatalf("balance row %+v differs from ledger sum %+v", *got, *want)
+	}
+
+	implementations := []struct {
+		name string
+		get  func(ctx context.Context, userID int64) (*domain.Balance, error)
+	}{
+		{name: "LedgerSum", get: func(ctx context.Context, userID int64) (*domain.Balance, error) {
+			return getBalanceBySum(ctx, db, userID)
+		}},
+		{name: "BalanceRow", get: repo.GetBalance},
+	}
+	for _, impl := range implementations {
+		b.Run(impl.name, func(b *testing.B) {
+			for b.Loop() {
+				if _, err := impl.get(ctx, ids[0]); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/pashagolub/pgxmock/v3"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -26,7 +28,7 @@ func TestTransactionRepository_CreateTransaction(t *testing.T) {
 		amount := 100.0
 
 		mock.ExpectExec(`INSERT INTO transactions`).
-			WithArgs(userID, orderNumber, amount, domain.TransactionTypeAccrual).
+			WithArgs(userID, orderNumber, amount, domain.TransactionTypeAccrual, pgxmock.AnyArg()).
 			WillReturnResult(pgxmock.NewResult("INSERT", 1))
 
 		err := repo.CreateTransaction(ctx, userID, orderNumber, amount, domain.TransactionTypeAccrual)
@@ -41,7 +43,7 @@ func TestTransactionRepository_CreateTransaction(t *testing.T) {
 		amount := -50.0
 
 		mock.ExpectExec(`INSERT INTO transactions`).
-			WithArgs(userID, orderNumber, amount, domain.TransactionTypeWithdrawal).
+			WithArgs(userID, orderNumber, amount, domain.TransactionTypeWithdrawal, pgxmock.AnyArg()).
 			WillReturnResult(pgxmock.NewResult("INSERT", 1))
 
 		err := repo.CreateTransaction(ctx, userID, orderNumber, amount, domain.TransactionTypeWithdrawal)
@@ -56,7 +58,7 @@ func TestTransactionRepository_CreateTransaction(t *testing.T) {
 		amount := 100.0
 
 		mock.ExpectExec(`INSERT INTO transactions`).
-			WithArgs(userID, orderNumber, amount, domain.TransactionTypeAccrual).
+			WithArgs(userID, orderNumber, amount, domain.TransactionTypeAccrual, pgxmock.AnyArg()).
 			WillReturnError(errors.New("database error"))
 
 		err := repo.CreateTransaction(ctx, userID, orderNumber, amount, domain.TransactionTypeAccrual)
@@ -76,19 +78,17 @@ func TestTransactionRepository_GetBalance(t *testing.T) {
 
 	t.Run("Success - with balance", func(t *testing.T) {
 		userID := int64(1)
-		totalAccrued := 500.0
-		totalWithdrawn := 200.0
 
-		rows := pgxmock.NewRows([]string{"total_accrued", "total_withdrawn"}).
-			AddRow(totalAccrued, totalWithdrawn)
+		rows := pgxmock.NewRows([]string{"balance", "withdrawn"}).
+			AddRow(300.0, 200.0)
 
-		mock.ExpectQuery(`SELECT`).
+		mock.ExpectQuery(`SELECT balance, withdrawn FROM user_balances WHERE user_id = \$1`).
 			WithArgs(userID).
 			WillReturnRows(rows)
 
 		balance, err := repo.GetBalance(ctx, userID)
 		require.NoError(t, err)
-		assert.Equal(t, 300.0, balance.Current) // 500 - 200
+		assert.Equal(t, 300.0, balance.Current)
 		assert.Equal(t, 200.0, balance.Withdrawn)
 
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -97,12 +97,10 @@ func TestTransactionRepository_GetBalance(t *testing.T) {
 	t.Run("Success - no transactions", func(t *testing.T) {
 		userID := int64(999)
 
-		rows := pgxmock.NewRows([]string{"total_accrued", "total_withdrawn"}).
-			AddRow(0.0, 0.0)
-
-		mock.ExpectQuery(`SELECT`).
+		// Строка баланса появляется с первой транзакцией пользователя
+		mock.ExpectQuery(`SELECT balance, withdrawn FROM user_balances`).
 			WithArgs(userID).
-			WillReturnRows(rows)
+			WillReturnError(pgx.ErrNoRows)
 
 		balance, err := repo.GetBalance(ctx, userID)
 		require.NoError(t, err)
@@ -115,7 +113,7 @@ func TestTransactionRepository_GetBalance(t *testing.T) {
 	t.Run("Database error", func(t *testing.T) {
 		userID := int64(1)
 
-		mock.ExpectQuery(`SELECT`).
+		mock.ExpectQuery(`SELECT balance, withdrawn FROM user_balances`).
 			WithArgs(userID).
 			WillReturnError(errors.New("database error"))
 
@@ -138,15 +136,15 @@ func TestTransactionRepository_GetWithdrawals(t *testing.T) {
 	t.Run("Success - with withdrawals", func(t *testing.T) {
 		userID := int64(1)
 
-		rows := pgxmock.NewRows([]string{"id", "user_id", "order_number", "amount", "type", "processed_at"}).
-			AddRow(int64(1), userID, "111", 100.0, domain.TransactionTypeWithdrawal, time.Now()).
-			AddRow(int64(2), userID, "222", 50.0, domain.TransactionTypeWithdrawal, time.Now())
+		rows := pgxmock.NewRows([]string{"id", "public_id", "user_id", "order_number", "amount", "type", "processed_at"}).
+			AddRow(int64(1), uuid.New(), userID, "111", 100.0, domain.TransactionTypeWithdrawal, time.Now()).
+			AddRow(int64(2), uuid.New(), userID, "222", 50.0, domain.TransactionTypeWithdrawal, time.Now())
 
-		mock.ExpectQuery(`SELECT id, user_id, order_number, ABS\(amount\) as amount, type, processed_at FROM transactions WHERE user_id`).
+		mock.ExpectQuery(`SELECT id, public_id, user_id, order_number, ABS\(amount\) as amount, type, processed_at FROM transactions WHERE user_id = \$1 AND type = \$2 AND amount < 0 ORDER BY processed_at DESC, id DESC$`).
 			WithArgs(userID, domain.TransactionTypeWithdrawal).
 			WillReturnRows(rows)
 
-		transactions, err := repo.GetWithdrawals(ctx, userID)
+		transactions, err := repo.GetWithdrawals(ctx, userID, nil, 0)
 		require.NoError(t, err)
 		assert.Len(t, transactions, 2)
 
@@ -156,18 +154,168 @@ func TestTransactionRepository_GetWithdrawals(t *testing.T) {
 	t.Run("Success - no withdrawals", func(t *testing.T) {
 		userID := int64(999)
 
-		rows := pgxmock.NewRows([]string{"id", "user_id", "order_number", "amount", "type", "processed_at"})
+		rows := pgxmock.NewRows([]string{"id", "public_id", "user_id", "order_number", "amount", "type", "processed_at"})
 
-		mock.ExpectQuery(`SELECT id, user_id, order_number, ABS\(amount\) as amount, type, processed_at FROM transactions WHERE user_id`).
+		mock.ExpectQuery(`SELECT id, public_id, user_id, order_number, ABS\(amount\) as amount, type, processed_at FROM transactions WHERE user_id`).
 			WithArgs(userID, domain.TransactionTypeWithdrawal).
 			WillReturnRows(rows)
 
-		transactions, err := repo.GetWithdrawals(ctx, userID)
+		transactions, err := repo.GetWithdrawals(ctx, userID, nil, 0)
 		require.NoError(t, err)
 		assert.Empty(t, transactions)
 
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
+
+	t.Run("Success - after cursor", func(t *testing.T) {
+		userID := int64(1)
+		processedAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.FixedZone("MSK", 3*60*60))
+		after := &domain.WithdrawalCursor{ProcessedAt: processedAt, ID: 10}
+
+		rows := pgxmock.NewRows([]string{"id", "public_id", "user_id", "order_number", "amount", "type", "processed_at"}).
+			AddRow(int64(9), uuid.New(), userID, "111", 100.0, domain.TransactionTypeWithdrawal, processedAt)
+
+		mock.ExpectQuery(`WHERE user_id = \$1 AND type = \$2 AND amount < 0 AND \(processed_at, id\) < \(\$3, \$4\) ORDER BY processed_at DESC, id DESC LIMIT \$5`).
+			WithArgs(userID, domain.TransactionTypeWithdrawal, processedAt.UTC(), int64(10), 3).
+			WillReturnRows(rows)
+
+		transactions, err := repo.GetWithdrawals(ctx, userID, after, 3)
+		require.NoError(t, err)
+		assert.Len(t, transactions, 1)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestTransactionRepository_GetTransactions(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewTransactionRepository(mock)
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		rows := pgxmock.NewRows([]string{"id", "public_id", "user_id", "order_number", "amount", "type", "processed_at"}).
+			AddRow(int64(1), uuid.New(), int64(1), "111", 500.0, domain.TransactionTypeAccrual, time.Now()).
+			AddRow(int64(2), uuid.New(), int64(1), "222", -100.0, domain.TransactionTypeWithdrawal, time.Now())
+
+		mock.ExpectQuery(`SELECT id, public_id, user_id, order_number, amount, type, processed_at FROM transactions WHERE user_id = \$1 ORDER BY processed_at, id`).
+			WithArgs(int64(1)).
+			WillReturnRows(rows)
+
+		transactions, err := repo.GetTransactions(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, transactions, 2)
+		assert.Equal(t, -100.0, transactions[1].Amount)
+		assert.Equal(t, domain.TransactionTypeWithdrawal, transactions[1].Type)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Database error", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT id, public_id, user_id, order_number, amount, type, processed_at FROM transactions`).
+			WithArgs(int64(1)).
+			WillReturnError(errors.New("connection lost"))
+
+		_, err := repo.GetTransactions(ctx, 1)
+		assert.Error(t, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestTransactionRepository_GetWithdrawalSummary(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewTransactionRepository(mock)
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		lastAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+		rows := pgxmock.NewRows([]string{"count", "total", "largest", "last_at"}).
+			AddRow(3, 1500.0, 800.0, &lastAt)
+
+		mock.ExpectQuery(`SELECT COUNT\(\*\)`).
+			WithArgs(int64(1), domain.TransactionTypeWithdrawal).
+			WillReturnRows(rows)
+
+		summary, err := repo.GetWithdrawalSummary(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, &domain.WithdrawalSummary{Count: 3, Total: 1500, Largest: 800, LastAt: &lastAt}, summary)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("No withdrawals", func(t *testing.T) {
+		rows := pgxmock.NewRows([]string{"count", "total", "largest", "last_at"}).
+			AddRow(0, 0.0, 0.0, nil)
+
+		mock.ExpectQuery(`SELECT COUNT\(\*\)`).
+			WithArgs(int64(999), domain.TransactionTypeWithdrawal).
+			WillReturnRows(rows)
+
+		summary, err := repo.GetWithdrawalSummary(ctx, 999)
+		require.NoError(t, err)
+		assert.Equal(t, &domain.WithdrawalSummary{}, summary)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Database error", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT COUNT\(\*\)`).
+			WithArgs(int64(1), domain.TransactionTypeWithdrawal).
+			WillReturnError(errors.New("database error"))
+
+		summary, err := repo.GetWithdrawalSummary(ctx, 1)
+		assert.Error(t, err)
+		assert.Nil(t, summary)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestTransactionRepository_GetWithdrawalByPublicID(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewTransactionRepository(mock)
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		publicID := uuid.New()
+
+		rows := pgxmock.NewRows([]string{"id", "public_id", "user_id", "order_number", "amount", "type", "processed_at"}).
+			AddRow(int64(1), publicID, int64(1), "2377225624", 100.0, domain.TransactionTypeWithdrawal, time.Now())
+
+		mock.ExpectQuery(`FROM transactions WHERE public_id = \$1 AND user_id = \$2 AND type = \$3`).
+			WithArgs(publicID, int64(1), domain.TransactionTypeWithdrawal).
+			WillReturnRows(rows)
+
+		tx, err := repo.GetWithdrawalByPublicID(ctx, 1, publicID)
+		require.NoError(t, err)
+		assert.Equal(t, publicID, tx.PublicID)
+		assert.Equal(t, 100.0, tx.Amount)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		publicID := uuid.New()
+
+		mock.ExpectQuery(`FROM transactions WHERE public_id`).
+			WithArgs(publicID, int64(1), domain.TransactionTypeWithdrawal).
+			WillReturnError(pgx.ErrNoRows)
+
+		tx, err := repo.GetWithdrawalByPublicID(ctx, 1, publicID)
+		assert.ErrorIs(t, err, domain.ErrTransactionNotFound)
+		assert.Nil(t, tx)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
 }
 
 func TestTransactionRepository_WithdrawWithLock(t *testing.T) {
@@ -178,30 +326,28 @@ func TestTransactionRepository_WithdrawWithLock(t *testing.T) {
 	repo := NewTransactionRepository(mock)
 	ctx := context.Background()
 
+	expectBalanceLock := func(userID int64, amount float64, locked int64) {
+		mock.ExpectExec(`UPDATE user_balances SET updated_at = NOW\(\) WHERE user_id = \$1 AND balance >= \$2`).
+			WithArgs(userID, amount).
+			WillReturnResult(pgxmock.NewResult("UPDATE", locked))
+	}
+
 	t.Run("Success", func(t *testing.T) {
 		userID := int64(1)
 		orderNumber := "12345678903"
 		amount := 100.0
-		currentBalance := 500.0
 
 		mock.ExpectBegin()
 
-		mock.ExpectExec(`SELECT pg_advisory_xact_lock`).
-			WithArgs(userID).
-			WillReturnResult(pgxmock.NewResult("SELECT", 1))
-
-		balanceRows := pgxmock.NewRows([]string{"balance"}).AddRow(currentBalance)
-		mock.ExpectQuery(`SELECT COALESCE\(SUM\(amount\), 0\) FROM transactions WHERE user_id`).
-			WithArgs(userID).
-			WillReturnRows(balanceRows)
+		expectBalanceLock(userID, amount, 1)
 
 		mock.ExpectExec(`INSERT INTO transactions`).
-			WithArgs(userID, orderNumber, -amount, domain.TransactionTypeWithdrawal).
+			WithArgs(userID, orderNumber, -amount, domain.TransactionTypeWithdrawal, pgxmock.AnyArg(), "store-42").
 			WillReturnResult(pgxmock.NewResult("INSERT", 1))
 
 		mock.ExpectCommit()
 
-		err := repo.WithdrawWithLock(ctx, userID, orderNumber, amount)
+		err := repo.WithdrawWithLock(ctx, userID, orderNumber, amount, "store-42", domain.WithdrawalLimits{})
 		assert.NoError(t, err)
 
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -211,23 +357,16 @@ func TestTransactionRepository_WithdrawWithLock(t *testing.T) {
 		userID := int64(1)
 		orderNumber := "12345678903"
 		amount := 500.0
-		currentBalance := 100.0
 
 		mock.ExpectBegin()
 
-		mock.ExpectExec(`SELECT pg_advisory_xact_lock`).
-			WithArgs(userID).
-			WillReturnResult(pgxmock.NewResult("SELECT", 1))
-
-		balanceRows := pgxmock.NewRows([]string{"balance"}).AddRow(currentBalance)
-		mock.ExpectQuery(`SELECT COALESCE\(SUM\(amount\), 0\) FROM transactions WHERE user_id`).
-			WithArgs(userID).
-			WillReturnRows(balanceRows)
+		// Баланс меньше суммы или у пользователя еще нет транзакций
+		expectBalanceLock(userID, amount, 0)
 
 		mock.ExpectRollback()
 
-		err := repo.WithdrawWithLock(ctx, userID, orderNumber, amount)
-		assert.ErrorIs(t, err, ErrInsufficientFunds)
+		err := repo.WithdrawWithLock(ctx, userID, orderNumber, amount, "", domain.WithdrawalLimits{})
+		assert.ErrorIs(t, err, domain.ErrInsufficientFunds)
 
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
@@ -239,31 +378,28 @@ func TestTransactionRepository_WithdrawWithLock(t *testing.T) {
 
 		mock.ExpectBegin().WillReturnError(errors.New("begin error"))
 
-		err := repo.WithdrawWithLock(ctx, userID, orderNumber, amount)
+		err := repo.WithdrawWithLock(ctx, userID, orderNumber, amount, "", domain.WithdrawalLimits{})
 		assert.Error(t, err)
 
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
-	t.Run("Get balance error", func(t *testing.T) {
+	t.Run("Lock balance error", func(t *testing.T) {
 		userID := int64(1)
 		orderNumber := "12345678903"
 		amount := 100.0
 
 		mock.ExpectBegin()
 
-		mock.ExpectExec(`SELECT pg_advisory_xact_lock`).
-			WithArgs(userID).
-			WillReturnResult(pgxmock.NewResult("SELECT", 1))
-
-		mock.ExpectQuery(`SELECT COALESCE\(SUM\(amount\), 0\) FROM transactions WHERE user_id`).
-			WithArgs(userID).
+		mock.ExpectExec(`UPDATE user_balances`).
+			WithArgs(userID, amount).
 			WillReturnError(errors.New("query error"))
 
 		mock.ExpectRollback()
 
-		err := repo.WithdrawWithLock(ctx, userID, orderNumber, amount)
+		err := repo.WithdrawWithLock(ctx, userID, orderNumber, amount, "", domain.WithdrawalLimits{})
 		assert.Error(t, err)
+		assert.NotErrorIs(t, err, domain.ErrInsufficientFunds)
 
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
@@ -272,26 +408,119 @@ func TestTransactionRepository_WithdrawWithLock(t *testing.T) {
 		userID := int64(1)
 		orderNumber := "12345678903"
 		amount := 100.0
-		currentBalance := 500.0
 
 		mock.ExpectBegin()
 
-		mock.ExpectExec(`SELECT pg_advisory_xact_lock`).
-			WithArgs(userID).
-			WillReturnResult(pgxmock.NewResult("SELECT", 1))
-
-		balanceRows := pgxmock.NewRows([]string{"balance"}).AddRow(currentBalance)
-		mock.ExpectQuery(`SELECT COALESCE\(SUM\(amount\), 0\) FROM transactions WHERE user_id`).
-			WithArgs(userID).
-			WillReturnRows(balanceRows)
+		expectBalanceLock(userID, amount, 1)
 
 		mock.ExpectExec(`INSERT INTO transactions`).
-			WithArgs(userID, orderNumber, -amount, domain.TransactionTypeWithdrawal).
+			WithArgs(userID, orderNumber, -amount, domain.TransactionTypeWithdrawal, pgxmock.AnyArg(), "").
 			WillReturnError(errors.New("insert error"))
 
 		mock.ExpectRollback()
 
-		err := repo.WithdrawWithLock(ctx, userID, orderNumber, amount)
+		err := repo.WithdrawWithLock(ctx, userID, orderNumber, amount, "", domain.WithdrawalLimits{})
+		assert.Error(t, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Within limits", func(t *testing.T) {
+		userID := int64(1)
+		orderNumber := "12345678903"
+		limits := domain.WithdrawalLimits{Daily: 1000, Monthly: 3000}
+
+		mock.ExpectBegin()
+		expectBalanceLock(userID, 100, 1)
+		mock.ExpectQuery(`FILTER \(WHERE processed_at > NOW\(\) - INTERVAL '1 day'\)`).
+			WithArgs(userID, domain.TransactionTypeWithdrawal).
+			WillReturnRows(pgxmock.NewRows([]string{"daily", "monthly"}).AddRow(900.0, 2900.0))
+		mock.ExpectExec(`INSERT INTO transactions`).
+			WithArgs(userID, orderNumber, -100.0, domain.TransactionTypeWithdrawal, pgxmock.AnyArg(), "").
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectCommit()
+
+		err := repo.WithdrawWithLock(ctx, userID, orderNumber, 100, "", limits)
+		assert.NoError(t, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Limits exceeded", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			daily   float64
+			monthly float64
+			wantErr error
+		}{
+			{name: "Daily", daily: 950, monthly: 950, wantErr: domain.ErrDailyWithdrawalLimit},
+			{name: "Monthly", daily: 0, monthly: 2950, wantErr: domain.ErrMonthlyWithdrawalLimit},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				userID := int64(1)
+
+				mock.ExpectBegin()
+				expectBalanceLock(userID, 100, 1)
+				mock.ExpectQuery(`INTERVAL '30 days'`).
+					WithArgs(userID, domain.TransactionTypeWithdrawal).
+					WillReturnRows(pgxmock.NewRows([]string{"daily", "monthly"}).AddRow(tt.daily, tt.monthly))
+				mock.ExpectRollback()
+
+				err := repo.WithdrawWithLock(ctx, userID, "12345678903", 100, "", domain.WithdrawalLimits{Daily: 1000, Monthly: 3000})
+				assert.ErrorIs(t, err, tt.wantErr)
+
+				assert.NoError(t, mock.ExpectationsWereMet())
+			})
+		}
+	})
+}
+
+func TestTransactionRepository_VerifyLedgerChain(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewTransactionRepository(mock)
+	ctx := context.Background()
+	columns := []string{"checked", "unchained", "chains", "head_hash", "broken_user_id", "broken_login", "broken_seq", "broken_id", "broken_reason"}
+
+	t.Run("Intact chain", func(t *testing.T) {
+		head := []byte{0xab, 0xcd}
+		mock.ExpectQuery(`transactions_chain_hash`).
+			WillReturnRows(pgxmock.NewRows(columns).AddRow(int64(3), int64(0), int64(2), head, nil, nil, nil, nil, nil))
+
+		result, err := repo.VerifyLedgerChain(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, &domain.LedgerVerification{Checked: 3, Chains: 2, HeadHash: head}, result)
+		assert.True(t, result.IsIntact())
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Broken chain", func(t *testing.T) {
+		publicID := uuid.New()
+		userID := int64(7)
+		login := "alice"
+		seq := int64(2)
+		reason := "hash_mismatch"
+		mock.ExpectQuery(`transactions_chain_hash`).
+			WillReturnRows(pgxmock.NewRows(columns).AddRow(int64(3), int64(1), int64(2), []byte{0x01}, &userID, &login, &seq, &publicID, &reason))
+
+		result, err := repo.VerifyLedgerChain(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, &domain.LedgerBreak{UserID: 7, Login: "alice", Seq: 2, PublicID: publicID, Reason: domain.LedgerBreakHashMismatch}, result.Break)
+		assert.Equal(t, int64(1), result.Unchained)
+		assert.False(t, result.IsIntact())
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Database error", func(t *testing.T) {
+		mock.ExpectQuery(`transactions_chain_hash`).WillReturnError(errors.New("db error"))
+
+		_, err := repo.VerifyLedgerChain(ctx)
 		assert.Error(t, err)
 
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -4,20 +4,28 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgconn"
 )
 
 // UserRepository реализует репозиторий пользователей.
 type UserRepository struct {
-	db DBTX
+	db     DBTX
+	cipher FieldCipher
 }
 
-// NewUserRepository создает новый UserRepository
-func NewUserRepository(db DBTX) *UserRepository {
-	return &UserRepository{db: db}
+// NewUserRepository создает новый UserRepository.
+// cipher шифрует контактные данные; при nil они не сохраняются и не читаются.
+func NewUserRepository(db DBTX, cipher FieldCipher) *UserRepository {
+	return &UserRepository{db: db, cipher: cipher}
+}
+
+// WithTx возвращает репозиторий пользователей с тем же шифрованием, работающий в транзакции tx
+func (r *UserRepository) WithTx(tx pgx.Tx) *UserRepository {
+	return &UserRepository{db: tx, cipher: r.cipher}
 }
 
 // CreateUser создает нового пользователя
@@ -32,10 +40,9 @@ func (r *UserRepository) CreateUser(ctx context.Context, login, passwordHash str
 	).Scan(&user.ID, &user.Login, &user.PasswordHash, &user.CreatedAt)
 
 	if err != nil {
-		// Проверка на уникальность логина (код ошибки PostgreSQL)
-		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
-			return nil, ErrUserExists
+		// Проверка на уникальность логина
+		if isUniqueViolation(err) {
+			return nil, domain.ErrUserExists
 		}
 		return nil, fmt.Errorf("repository: failed to create user %q: %w", login, err)
 	}
@@ -56,7 +63,7 @@ func (r *UserRepository) GetUserByLogin(ctx context.Context, login string) (*dom
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, ErrUserNotFound
+			return nil, domain.ErrUserNotFound
 		}
 		return nil, fmt.Errorf("repository: failed to get user by login %q: %w", login, err)
 	}
@@ -64,6 +71,152 @@ func (r *UserRepository) GetUserByLogin(ctx context.Context, login string) (*dom
 	return user, nil
 }
 
+// GetOrCreateExternalUser возвращает локального пользователя, привязанного к учетной записи
+// внешнего провайдера, и создает его при первом обращении. Если желаемый логин занят,
+// используется резервный; пароль не задается, поэтому вход по паролю для такого пользователя невозможен.
+func (r *UserRepository) GetOrCreateExternalUser(ctx context.Context, identity domain.ExternalIdentity) (*domain.User, error) {
+	user, err := r.getExternalUser(ctx, identity)
+	if err == nil || !errors.Is(err, domain.ErrUserNotFound) {
+		return user, err
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to begin transaction for identity %q: %w", identity.Subject, err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // Rollback после Commit безопасен
+
+	user = &domain.User{}
+	for _, login := range []string{identity.Login, identity.FallbackLogin()} {
+		err = tx.QueryRow(ctx,
+			`INSERT INTO users (login, password_hash) 
+			 VALUES ($1, '') 
+			 ON CONFLICT (login) DO NOTHING 
+			 RETURNING id, login, password_hash, created_at`,
+			login,
+		).Scan(&user.ID, &user.Login, &user.PasswordHash, &user.CreatedAt)
+		if !errors.Is(err, pgx.ErrNoRows) {
+			break
+		}
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("repository: no free login for identity %q: %w", identity.Subject, domain.ErrUserExists)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to create user for identity %q: %w", identity.Subject, err)
+	}
+
+	result, err := tx.Exec(ctx,
+		`INSERT INTO user_identities (issuer, subject, user_id) 
+		 VALUES ($1, $2, $3) 
+		 ON CONFLICT (issuer, subject) DO NOTHING`,
+		identity.Issuer, identity.Subject, user.ID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to link identity %q: %w", identity.Subject, err)
+	}
+	if result.RowsAffected() == 0 {
+		// Параллельный запрос успел создать пользователя: наш откатываем
+		return r.getExternalUser(ctx, identity)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("repository: failed to commit identity %q: %w", identity.Subject, err)
+	}
+
+	return user, nil
+}
+
+// LinkExternalIdentity привязывает учетную запись внешнего провайдера к пользователю.
+// Повторная привязка к тому же пользователю не считается ошибкой
+func (r *UserRepository) LinkExternalIdentity(ctx context.Context, userID int64, identity domain.ExternalIdentity) error {
+	var linkedUserID int64
+	err := r.db.QueryRow(ctx,
+		`INSERT INTO user_identities (issuer, subject, user_id) 
+		 VALUES ($1, $2, $3) 
+		 ON CONFLICT (issuer, subject) DO UPDATE SET user_id = user_identities.user_id 
+		 RETURNING user_id`,
+		identity.Issuer, identity.Subject, userID,
+	).Scan(&linkedUserID)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return domain.ErrUserNotFound
+		}
+		return fmt.Errorf("repository: failed to link identity %q to user %d: %w", identity.Subject, userID, err)
+	}
+
+	if linkedUserID != userID {
+		return domain.ErrIdentityAlreadyLinked
+	}
+	return nil
+}
+
+// getExternalUser получает пользователя, привязанного к учетной записи внешнего провайдера
+func (r *UserRepository) getExternalUser(ctx context.Context, identity domain.ExternalIdentity) (*domain.User, error) {
+	user := &domain.User{}
+
+	err := r.db.QueryRow(ctx,
+		`SELECT u.id, u.login, u.password_hash, u.created_at 
+		 FROM user_identities i 
+		 JOIN users u ON u.id = i.user_id 
+		 WHERE i.issuer = $1 AND i.subject = $2`,
+		identity.Issuer, identity.Subject,
+	).Scan(&user.ID, &user.Login, &user.PasswordHash, &user.CreatedAt)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("repository: failed to get user for identity %q: %w", identity.Subject, err)
+	}
+
+	return user, nil
+}
+
+// CountUsers возвращает количество пользователей
+func (r *UserRepository) CountUsers(ctx context.Context) (int, error) {
+	var count int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM users`).Scan(&count); err != nil {
+		return 0, fmt.Errorf("repository: failed to count users: %w", err)
+	}
+	return count, nil
+}
+
+// ListUserBalances возвращает пользователей с балансами, начиная после afterID, по возрастанию ID.
+// Балансы читаются из user_balances; пользователи без транзакций получают нулевой баланс.
+func (r *UserRepository) ListUserBalances(ctx context.Context, afterID int64, limit int) ([]*domain.UserBalance, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT u.id, u.login, u.created_at,
+			COALESCE(b.balance, 0) AS current,
+			COALESCE(b.withdrawn, 0) AS withdrawn
+		 FROM users u
+		 LEFT JOIN user_balances b ON b.user_id = u.id
+		 WHERE u.id > $1
+		 ORDER BY u.id
+		 LIMIT $2`,
+		afterID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to list user balances after id %d: %w", afterID, err)
+	}
+	defer rows.Close()
+
+	var balances []*domain.UserBalance
+	for rows.Next() {
+		b := &domain.UserBalance{}
+		if err := rows.Scan(&b.UserID, &b.Login, &b.CreatedAt, &b.Current, &b.Withdrawn); err != nil {
+			return nil, fmt.Errorf("repository: failed to scan user balance: %w", err)
+		}
+		balances = append(balances, b)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repository: error iterating user balances: %w", err)
+	}
+
+	return balances, nil
+}
+
 // GetUserByID получает пользователя по ID
 func (r *UserRepository) GetUserByID(ctx context.Context, id int64) (*domain.User, error) {
 	user := &domain.User{}
@@ -77,10 +230,219 @@ func (r *UserRepository) GetUserByID(ctx context.Context, id int64) (*domain.Use
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, ErrUserNotFound
+			return nil, domain.ErrUserNotFound
 		}
 		return nil, fmt.Errorf("repository: failed to get user by id %d: %w", id, err)
 	}
 
 	return user, nil
 }
+
+// MergeUsers объединяет учетную запись-дубликат sourceLogin с основной targetLogin в одной транзакции.
+// Заказы и привязки внешних провайдеров переносятся на основную запись, а баланс дубликата -
+// корректировками по каждому заказу (см. moveBalanceTx). Собственные ограничения списаний дубликата удаляются.
+// Дубликат отключается: пароль сбрасывается, а merged_into указывает на основную запись.
+// Объединение записывается в журнал user_merges. При dryRun возвращает то же описание без изменений.
+// Если одна из записей уже объединена, возвращает ErrUserMerged.
+func (r *UserRepository) MergeUsers(ctx context.Context, sourceLogin, targetLogin string, mergedBy int64, dryRun bool) (*domain.UserMerge, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to begin transaction for merge of %q: %w", sourceLogin, err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // Rollback после Commit безопасен
+
+	merge := &domain.UserMerge{SourceLogin: sourceLogin, TargetLogin: targetLogin, DryRun: dryRun, MergedBy: mergedBy}
+
+	// Записи блокируются в порядке возрастания ID, чтобы встречные объединения не взаимоблокировались
+	rows, err := tx.Query(ctx,
+		`SELECT id, login, merged_into IS NOT NULL 
+		 FROM users 
+		 WHERE login IN ($1, $2) 
+		 ORDER BY id 
+		 FOR UPDATE`,
+		sourceLogin, targetLogin,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to lock users %q and %q: %w", sourceLogin, targetLogin, err)
+	}
+	var ids []int64
+	merged := false
+	for rows.Next() {
+		var id int64
+		var login string
+		var isMerged bool
+		if err := rows.Scan(&id, &login, &isMerged); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("repository: failed to scan user: %w", err)
+		}
+		if login == sourceLogin {
+			merge.SourceUserID = id
+		} else {
+			merge.TargetUserID = id
+		}
+		ids = append(ids, id)
+		merged = merged || isMerged
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repository: error iterating users: %w", err)
+	}
+	if merge.SourceUserID == 0 || merge.TargetUserID == 0 {
+		return nil, domain.ErrUserNotFound
+	}
+	if merged {
+		return nil, domain.ErrUserMerged
+	}
+
+	// Строки баланса блокируются, как при списании: баланс дубликата не меняется до конца объединения
+	if err := lockBalancesTx(ctx, tx, ids...); err != nil {
+		return nil, err
+	}
+
+	err = tx.QueryRow(ctx,
+		`SELECT (SELECT COUNT(*) FROM orders WHERE user_id = $1), COUNT(*), COALESCE(SUM(amount), 0) 
+		 FROM transactions 
+		 WHERE user_id = $1`,
+		merge.SourceUserID,
+	).Scan(&merge.Orders, &merge.Transactions, &merge.Balance)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to count data of user %q: %w", sourceLogin, err)
+	}
+	if dryRun {
+		return merge, nil
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE orders SET user_id = $1 WHERE user_id = $2`, merge.TargetUserID, merge.SourceUserID); err != nil {
+		return nil, fmt.Errorf("repository: failed to move orders of user %q: %w", sourceLogin, err)
+	}
+	if err := moveBalanceTx(ctx, tx, merge.SourceUserID, merge.TargetUserID); err != nil {
+		return nil, fmt.Errorf("repository: failed to move balance of user %q: %w", sourceLogin, err)
+	}
+	if _, err := tx.Exec(ctx, `UPDATE user_identities SET user_id = $1 WHERE user_id = $2`, merge.TargetUserID, merge.SourceUserID); err != nil {
+		return nil, fmt.Errorf("repository: failed to move identities of user %q: %w", sourceLogin, err)
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM withdrawal_limits WHERE user_id = $1`, merge.SourceUserID); err != nil {
+		return nil, fmt.Errorf("repository: failed to delete withdrawal limits of user %q: %w", sourceLogin, err)
+	}
+	if _, err := tx.Exec(ctx,
+		`UPDATE users SET password_hash = '', merged_into = $1 WHERE id = $2`,
+		merge.TargetUserID, merge.SourceUserID,
+	); err != nil {
+		return nil, fmt.Errorf("repository: failed to disable user %q: %w", sourceLogin, err)
+	}
+
+	err = tx.QueryRow(ctx,
+		`INSERT INTO user_merges 
+			(source_user_id, source_login, target_user_id, target_login, orders, transactions, balance, merged_by) 
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8) 
+		 RETURNING created_at`,
+		merge.SourceUserID, sourceLogin, merge.TargetUserID, targetLogin,
+		merge.Orders, merge.Transactions, merge.Balance, mergedBy,
+	).Scan(&merge.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to record merge of user %q: %w", sourceLogin, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("repository: failed to commit merge of user %q: %w", sourceLogin, err)
+	}
+
+	return merge, nil
+}
+
+// moveBalanceTx переносит баланс пользователя fromUserID на toUserID новыми транзакциями:
+// журнал только дополняется, поэтому цепочки хешей обоих пользователей остаются целыми.
+// Начисления и корректировки переносятся парами корректировок по каждому заказу, как при
+// передаче заказа: сумма по заказу не меняется. Каждое списание переносится списанием
+// с тем же временем и партнером, а у fromUserID отменяется списанием с обратным знаком,
+// поэтому сумма списаний и лимиты переходят к toUserID. Перенесенные списания ссылаются
+// на исходное через merged_from и не попадают в выгрузки для взаиморасчетов
+func moveBalanceTx(ctx context.Context, tx pgx.Tx, fromUserID, toUserID int64) error {
+	rows, err := tx.Query(ctx,
+		`SELECT order_number, SUM(amount) 
+		 FROM transactions 
+		 WHERE user_id = $1 AND type IN ($2, $3) 
+		 GROUP BY order_number 
+		 HAVING SUM(amount) <> 0 
+		 ORDER BY MIN(id)`,
+		fromUserID, domain.TransactionTypeAccrual, domain.TransactionTypeAdjustment,
+	)
+	if err != nil {
+		return fmt.Errorf("repository: failed to get accruals by order of user %d: %w", fromUserID, err)
+	}
+	type orderAmount struct {
+		number string
+		amount float64
+	}
+	var amounts []orderAmount
+	for rows.Next() {
+		var a orderAmount
+		if err := rows.Scan(&a.number, &a.amount); err != nil {
+			rows.Close()
+			return fmt.Errorf("repository: failed to scan accruals by order: %w", err)
+		}
+		amounts = append(amounts, a)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("repository: error iterating accruals by order: %w", err)
+	}
+
+	for _, a := range amounts {
+		if _, err := insertAdjustment(ctx, tx, fromUserID, a.number, -a.amount); err != nil {
+			return err
+		}
+		if _, err := insertAdjustment(ctx, tx, toUserID, a.number, a.amount); err != nil {
+			return err
+		}
+	}
+
+	rows, err = tx.Query(ctx,
+		`SELECT public_id, order_number, amount, COALESCE(partner, ''), processed_at 
+		 FROM transactions 
+		 WHERE user_id = $1 AND type = $2 AND amount < 0 
+		 ORDER BY id`,
+		fromUserID, domain.TransactionTypeWithdrawal,
+	)
+	if err != nil {
+		return fmt.Errorf("repository: failed to get withdrawals of user %d: %w", fromUserID, err)
+	}
+	type withdrawal struct {
+		publicID    uuid.UUID
+		number      string
+		amount      float64
+		partner     string
+		processedAt time.Time
+	}
+	var withdrawals []withdrawal
+	for rows.Next() {
+		var w withdrawal
+		if err := rows.Scan(&w.publicID, &w.number, &w.amount, &w.partner, &w.processedAt); err != nil {
+			rows.Close()
+			return fmt.Errorf("repository: failed to scan withdrawal: %w", err)
+		}
+		withdrawals = append(withdrawals, w)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("repository: error iterating withdrawals: %w", err)
+	}
+
+	for _, w := range withdrawals {
+		if _, err := tx.Exec(ctx,
+			`INSERT INTO transactions (user_id, order_number, amount, type, public_id, partner, merged_from) 
+			 VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7)`,
+			fromUserID, w.number, -w.amount, domain.TransactionTypeWithdrawal, newPublicID(), w.partner, w.publicID,
+		); err != nil {
+			return fmt.Errorf("repository: failed to cancel withdrawal %s: %w", w.publicID, err)
+		}
+		if _, err := tx.Exec(ctx,
+			`INSERT INTO transactions (user_id, order_number, amount, type, public_id, partner, processed_at, merged_from) 
+			 VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7, $8)`,
+			toUserID, w.number, w.amount, domain.TransactionTypeWithdrawal, newPublicID(), w.partner, w.processedAt, w.publicID,
+		); err != nil {
+			return fmt.Errorf("repository: failed to move withdrawal %s: %w", w.publicID, err)
+		}
+	}
+	return nil
+}
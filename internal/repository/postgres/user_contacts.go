@@ -0,0 +1,203 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+// FieldCipher шифрует значения колонок с персональными данными (см. internal/utils/envelope).
+type FieldCipher interface {
+	Encrypt(plaintext string, aad []byte) (string, error)
+	Decrypt(ciphertext string, aad []byte) (string, error)
+	Rewrap(ciphertext string) (string, error)
+	CurrentKeyID() string
+}
+
+// contactAAD привязывает шифротекст к колонке и пользователю, чтобы его нельзя было
+// скопировать в чужую запись или поменять местами email и телефон
+func contactAAD(column string, userID int64) []byte {
+	return []byte("users." + column + ":" + strconv.FormatInt(userID, 10))
+}
+
+// GetUserContacts возвращает расшифрованные контактные данные пользователя
+func (r *UserRepository) GetUserContacts(ctx context.Context, userID int64) (*domain.UserContacts, error) {
+	var email, phone *string
+	err := r.db.QueryRow(ctx,
+		`SELECT email_encrypted, phone_encrypted FROM users WHERE id = $1`,
+		userID,
+	).Scan(&email, &phone)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("repository: failed to get contacts of user %d: %w", userID, err)
+	}
+
+	contacts := &domain.UserContacts{}
+	if contacts.Email, err = r.decryptContact(email, "email", userID); err != nil {
+		return nil, err
+	}
+	if contacts.Phone, err = r.decryptContact(phone, "phone", userID); err != nil {
+		return nil, err
+	}
+	return contacts, nil
+}
+
+// SetUserContacts шифрует и сохраняет контактные данные пользователя; пустое поле удаляет контакт
+func (r *UserRepository) SetUserContacts(ctx context.Context, userID int64, contacts domain.UserContacts) error {
+	if r.cipher == nil {
+		return domain.ErrPIIEncryptionDisabled
+	}
+
+	email, err := r.encryptContact(contacts.Email, "email", userID)
+	if err != nil {
+		return err
+	}
+	phone, err := r.encryptContact(contacts.Phone, "phone", userID)
+	if err != nil {
+		return err
+	}
+	var keyID *string
+	if email != nil || phone != nil {
+		current := r.cipher.CurrentKeyID()
+		keyID = &current
+	}
+
+	tag, err := r.db.Exec(ctx,
+		`UPDATE users SET email_encrypted = $1, phone_encrypted = $2, pii_key_id = $3 WHERE id = $4`,
+		email, phone, keyID, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("repository: failed to set contacts of user %d: %w", userID, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+// CountStaleUserContacts считает пользователей, контакты которых зашифрованы не текущим мастер-ключом
+func (r *UserRepository) CountStaleUserContacts(ctx context.Context) (int, error) {
+	if r.cipher == nil {
+		return 0, domain.ErrPIIEncryptionDisabled
+	}
+
+	var count int
+	err := r.db.QueryRow(ctx,
+		`SELECT COUNT(*) FROM users WHERE pii_key_id IS NOT NULL AND pii_key_id <> $1`,
+		r.cipher.CurrentKeyID(),
+	).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("repository: failed to count stale contacts: %w", err)
+	}
+	return count, nil
+}
+
+// RewrapUserContacts перешифровывает текущим мастер-ключом контакты не более limit пользователей
+// и возвращает их число. Значения не расшифровываются, меняются только ключи данных.
+func (r *UserRepository) RewrapUserContacts(ctx context.Context, limit int) (int, error) {
+	if r.cipher == nil {
+		return 0, domain.ErrPIIEncryptionDisabled
+	}
+	current := r.cipher.CurrentKeyID()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("repository: failed to begin contacts rewrap: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // Rollback после Commit безопасен
+
+	// SKIP LOCKED: записи, которые сейчас меняет пользователь, попадут в следующую пачку
+	rows, err := tx.Query(ctx,
+		`SELECT id, email_encrypted, phone_encrypted 
+		 FROM users 
+		 WHERE pii_key_id IS NOT NULL AND pii_key_id <> $1 
+		 ORDER BY id 
+		 LIMIT $2 
+		 FOR UPDATE SKIP LOCKED`,
+		current, limit,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("repository: failed to select stale contacts: %w", err)
+	}
+
+	type staleContacts struct {
+		userID       int64
+		email, phone *string
+	}
+	var batch []staleContacts
+	for rows.Next() {
+		var c staleContacts
+		if err := rows.Scan(&c.userID, &c.email, &c.phone); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("repository: failed to scan stale contacts: %w", err)
+		}
+		batch = append(batch, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("repository: error iterating stale contacts: %w", err)
+	}
+
+	for _, c := range batch {
+		if c.email, err = r.rewrapContact(c.email, c.userID); err != nil {
+			return 0, err
+		}
+		if c.phone, err = r.rewrapContact(c.phone, c.userID); err != nil {
+			return 0, err
+		}
+		_, err = tx.Exec(ctx,
+			`UPDATE users SET email_encrypted = $1, phone_encrypted = $2, pii_key_id = $3 WHERE id = $4`,
+			c.email, c.phone, current, c.userID,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("repository: failed to update contacts of user %d: %w", c.userID, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("repository: failed to commit contacts rewrap: %w", err)
+	}
+	return len(batch), nil
+}
+
+func (r *UserRepository) encryptContact(value, column string, userID int64) (*string, error) {
+	if value == "" {
+		return nil, nil
+	}
+	encrypted, err := r.cipher.Encrypt(value, contactAAD(column, userID))
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to encrypt %s of user %d: %w", column, userID, err)
+	}
+	return &encrypted, nil
+}
+
+func (r *UserRepository) decryptContact(value *string, column string, userID int64) (string, error) {
+	if value == nil {
+		return "", nil
+	}
+	if r.cipher == nil {
+		return "", domain.ErrPIIEncryptionDisabled
+	}
+	plaintext, err := r.cipher.Decrypt(*value, contactAAD(column, userID))
+	if err != nil {
+		return "", fmt.Errorf("repository: failed to decrypt %s of user %d: %w", column, userID, err)
+	}
+	return plaintext, nil
+}
+
+func (r *UserRepository) rewrapContact(value *string, userID int64) (*string, error) {
+	if value == nil {
+		return nil, nil
+	}
+	rewrapped, err := r.cipher.Rewrap(*value)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to rewrap contacts of user %d: %w", userID, err)
+	}
+	return &rewrapped, nil
+}
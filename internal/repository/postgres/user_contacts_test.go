@@ -0,0 +1,150 @@
+package postgres
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/avc/loyalty-system-diploma/internal/utils/envelope"
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testKeyring(t *testing.T, ids ...string) *envelope.Keyring {
+	t.Helper()
+	keys := make([]envelope.Key, len(ids))
+	for i, id := range ids {
+		keys[i] = envelope.Key{ID: id, Secret: bytes.Repeat([]byte(id[len(id)-1:]), envelope.KeySize)}
+	}
+	keyring, err := envelope.NewKeyring(keys)
+	require.NoError(t, err)
+	return keyring
+}
+
+// capturedArg запоминает строковый аргумент запроса, чтобы вернуть его из следующего запроса
+type capturedArg struct {
+	value *string
+}
+
+func (a *capturedArg) Match(v any) bool {
+	value, ok := v.(*string)
+	a.value = value
+	return ok && value != nil
+}
+
+func TestUserRepository_UserContacts(t *testing.T) {
+	ctx := context.Background()
+	keyring := testKeyring(t, "k1")
+
+	t.Run("Set encrypts and get decrypts", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+		repo := NewUserRepository(mock, keyring)
+
+		email := &capturedArg{}
+		mock.ExpectExec(`UPDATE users SET email_encrypted`).
+			WithArgs(email, (*string)(nil), pgxmock.AnyArg(), int64(1)).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+		require.NoError(t, repo.SetUserContacts(ctx, 1, domain.UserContacts{Email: "alice@example.com"}))
+		require.NoError(t, mock.ExpectationsWereMet())
+		require.NotNil(t, email.value)
+		assert.NotContains(t, *email.value, "alice")
+
+		mock.ExpectQuery(`SELECT email_encrypted, phone_encrypted FROM users`).
+			WithArgs(int64(1)).
+			WillReturnRows(pgxmock.NewRows([]string{"email_encrypted", "phone_encrypted"}).AddRow(email.value, nil))
+
+		contacts, err := repo.GetUserContacts(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, &domain.UserContacts{Email: "alice@example.com"}, contacts)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Value copied from another user is rejected", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+		repo := NewUserRepository(mock, keyring)
+
+		encrypted, err := keyring.Encrypt("alice@example.com", contactAAD("email", 1))
+		require.NoError(t, err)
+		mock.ExpectQuery(`SELECT email_encrypted, phone_encrypted FROM users`).
+			WithArgs(int64(2)).
+			WillReturnRows(pgxmock.NewRows([]string{"email_encrypted", "phone_encrypted"}).AddRow(&encrypted, nil))
+
+		_, err = repo.GetUserContacts(ctx, 2)
+		assert.ErrorIs(t, err, envelope.ErrDecrypt)
+	})
+
+	t.Run("User not found", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+		repo := NewUserRepository(mock, keyring)
+
+		mock.ExpectExec(`UPDATE users SET email_encrypted`).
+			WithArgs((*string)(nil), pgxmock.AnyArg(), pgxmock.AnyArg(), int64(1)).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+
+		err = repo.SetUserContacts(ctx, 1, domain.UserContacts{Phone: "+79990000000"})
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	})
+
+	t.Run("Encryption disabled", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+		repo := NewUserRepository(mock, nil)
+
+		err = repo.SetUserContacts(ctx, 1, domain.UserContacts{Email: "alice@example.com"})
+		assert.ErrorIs(t, err, domain.ErrPIIEncryptionDisabled)
+
+		// Пользователь без контактов читается и без ключа
+		mock.ExpectQuery(`SELECT email_encrypted, phone_encrypted FROM users`).
+			WithArgs(int64(1)).
+			WillReturnRows(pgxmock.NewRows([]string{"email_encrypted", "phone_encrypted"}).AddRow(nil, nil))
+		contacts, err := repo.GetUserContacts(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, &domain.UserContacts{}, contacts)
+	})
+}
+
+func TestUserRepository_RewrapUserContacts(t *testing.T) {
+	ctx := context.Background()
+	old := testKeyring(t, "k1")
+	rotated := testKeyring(t, "k2", "k1")
+
+	email, err := old.Encrypt("alice@example.com", contactAAD("email", 1))
+	require.NoError(t, err)
+
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+	repo := NewUserRepository(mock, rotated)
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM users WHERE pii_key_id`).
+		WithArgs("k2").
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(1))
+
+	count, err := repo.CountStaleUserContacts(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FOR UPDATE SKIP LOCKED`).
+		WithArgs("k2", 100).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "email_encrypted", "phone_encrypted"}).AddRow(int64(1), &email, nil))
+	mock.ExpectExec(`UPDATE users SET email_encrypted`).
+		WithArgs(pgxmock.AnyArg(), (*string)(nil), "k2", int64(1)).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectCommit()
+
+	rewrapped, err := repo.RewrapUserContacts(ctx, 100)
+	require.NoError(t, err)
+	assert.Equal(t, 1, rewrapped)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,38 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+// GetUserLocale возвращает язык, выбранный пользователем, или пустую строку, если язык не выбран
+func (r *UserRepository) GetUserLocale(ctx context.Context, userID int64) (string, error) {
+	var locale *string
+	err := r.db.QueryRow(ctx, `SELECT locale FROM users WHERE id = $1`, userID).Scan(&locale)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", domain.ErrUserNotFound
+		}
+		return "", fmt.Errorf("repository: failed to get locale of user %d: %w", userID, err)
+	}
+	if locale == nil {
+		return "", nil
+	}
+	return *locale, nil
+}
+
+// SetUserLocale сохраняет язык пользователя; пустая строка сбрасывает выбор
+func (r *UserRepository) SetUserLocale(ctx context.Context, userID int64, locale string) error {
+	tag, err := r.db.Exec(ctx, `UPDATE users SET locale = NULLIF($1, '') WHERE id = $2`, locale, userID)
+	if err != nil {
+		return fmt.Errorf("repository: failed to set locale of user %d: %w", userID, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
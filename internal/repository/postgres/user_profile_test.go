@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserRepository_GetUserLocale(t *testing.T) {
+	ctx := context.Background()
+	russian := "ru"
+
+	tests := []struct {
+		name    string
+		locale  *string
+		want    string
+		noRows  bool
+		wantErr error
+	}{
+		{name: "Locale chosen", locale: &russian, want: "ru"},
+		{name: "Locale not chosen", locale: nil, want: ""},
+		{name: "User not found", noRows: true, wantErr: domain.ErrUserNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			require.NoError(t, err)
+			defer mock.Close()
+			repo := NewUserRepository(mock, nil)
+
+			query := mock.ExpectQuery(`SELECT locale FROM users WHERE id = \$1`).WithArgs(int64(1))
+			if tt.noRows {
+				query.WillReturnError(pgx.ErrNoRows)
+			} else {
+				query.WillReturnRows(pgxmock.NewRows([]string{"locale"}).AddRow(tt.locale))
+			}
+
+			got, err := repo.GetUserLocale(ctx, 1)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUserRepository_SetUserLocale(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Saves locale", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+		repo := NewUserRepository(mock, nil)
+
+		mock.ExpectExec(`UPDATE users SET locale = NULLIF\(\$1, ''\) WHERE id = \$2`).
+			WithArgs("ru", int64(1)).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+		require.NoError(t, repo.SetUserLocale(ctx, 1, "ru"))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("User not found", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+		repo := NewUserRepository(mock, nil)
+
+		mock.ExpectExec(`UPDATE users SET locale`).
+			WithArgs("", int64(2)).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+
+		assert.ErrorIs(t, repo.SetUserLocale(ctx, 2, ""), domain.ErrUserNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
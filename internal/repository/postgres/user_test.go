@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/pashagolub/pgxmock/v3"
@@ -19,7 +20,7 @@ func TestUserRepository_CreateUser(t *testing.T) {
 	require.NoError(t, err)
 	defer mock.Close()
 
-	repo := NewUserRepository(mock)
+	repo := NewUserRepository(mock, nil)
 	ctx := context.Background()
 
 	t.Run("Success", func(t *testing.T) {
@@ -57,7 +58,7 @@ func TestUserRepository_CreateUser(t *testing.T) {
 			WillReturnError(&pgconn.PgError{Code: "23505"})
 
 		user, err := repo.CreateUser(ctx, login, passwordHash)
-		assert.ErrorIs(t, err, ErrUserExists)
+		assert.ErrorIs(t, err, domain.ErrUserExists)
 		assert.Nil(t, user)
 
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -84,7 +85,7 @@ func TestUserRepository_GetUserByLogin(t *testing.T) {
 	require.NoError(t, err)
 	defer mock.Close()
 
-	repo := NewUserRepository(mock)
+	repo := NewUserRepository(mock, nil)
 	ctx := context.Background()
 
 	t.Run("Success", func(t *testing.T) {
@@ -119,7 +120,7 @@ func TestUserRepository_GetUserByLogin(t *testing.T) {
 			WillReturnError(pgx.ErrNoRows)
 
 		user, err := repo.GetUserByLogin(ctx, login)
-		assert.ErrorIs(t, err, ErrUserNotFound)
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
 		assert.Nil(t, user)
 
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -145,7 +146,7 @@ func TestUserRepository_GetUserByID(t *testing.T) {
 	require.NoError(t, err)
 	defer mock.Close()
 
-	repo := NewUserRepository(mock)
+	repo := NewUserRepository(mock, nil)
 	ctx := context.Background()
 
 	t.Run("Success", func(t *testing.T) {
@@ -180,7 +181,7 @@ func TestUserRepository_GetUserByID(t *testing.T) {
 			WillReturnError(pgx.ErrNoRows)
 
 		user, err := repo.GetUserByID(ctx, userID)
-		assert.ErrorIs(t, err, ErrUserNotFound)
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
 		assert.Nil(t, user)
 
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -200,3 +201,332 @@ func TestUserRepository_GetUserByID(t *testing.T) {
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 }
+
+func TestUserRepository_CountUsers(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewUserRepository(mock, nil)
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM users`).
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(42))
+
+	count, err := repo.CountUsers(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 42, count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_ListUserBalances(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewUserRepository(mock, nil)
+	ctx := context.Background()
+	columns := []string{"id", "login", "created_at", "current", "withdrawn"}
+
+	t.Run("Success", func(t *testing.T) {
+		rows := pgxmock.NewRows(columns).
+			AddRow(int64(11), "alice", time.Now(), 500.0, 100.0).
+			AddRow(int64(12), "bob", time.Now(), 0.0, 0.0)
+
+		mock.ExpectQuery(`SELECT u.id, u.login, u.created_at, .* FROM users u LEFT JOIN user_balances b ON b.user_id = u.id WHERE u.id > \$1 .* LIMIT \$2`).
+			WithArgs(int64(10), 2).
+			WillReturnRows(rows)
+
+		balances, err := repo.ListUserBalances(ctx, 10, 2)
+		require.NoError(t, err)
+		require.Len(t, balances, 2)
+		assert.Equal(t, "alice", balances[0].Login)
+		assert.Equal(t, 500.0, balances[0].Current)
+		assert.Equal(t, 100.0, balances[0].Withdrawn)
+		assert.Equal(t, int64(12), balances[1].UserID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Database error", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT u.id`).
+			WithArgs(int64(0), 100).
+			WillReturnError(errors.New("database error"))
+
+		balances, err := repo.ListUserBalances(ctx, 0, 100)
+		assert.Error(t, err)
+		assert.Nil(t, balances)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestUserRepository_GetOrCreateExternalUser(t *testing.T) {
+	identity := domain.ExternalIdentity{Issuer: "https://sso.example.com", Subject: "user-1", Login: "alice"}
+	userColumns := []string{"id", "login", "password_hash", "created_at"}
+	now := time.Now()
+
+	newRepo := func(t *testing.T) (*UserRepository, pgxmock.PgxPoolIface) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		t.Cleanup(mock.Close)
+		return NewUserRepository(mock, nil), mock
+	}
+
+	t.Run("Linked user", func(t *testing.T) {
+		repo, mock := newRepo(t)
+		mock.ExpectQuery(`FROM user_identities i JOIN users u`).
+			WithArgs(identity.Issuer, identity.Subject).
+			WillReturnRows(pgxmock.NewRows(userColumns).AddRow(int64(5), "alice", "", now))
+
+		user, err := repo.GetOrCreateExternalUser(context.Background(), identity)
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), user.ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("First request creates user", func(t *testing.T) {
+		repo, mock := newRepo(t)
+		mock.ExpectQuery(`FROM user_identities i JOIN users u`).
+			WithArgs(identity.Issuer, identity.Subject).
+			WillReturnError(pgx.ErrNoRows)
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO users`).
+			WithArgs("alice").
+			WillReturnRows(pgxmock.NewRows(userColumns).AddRow(int64(6), "alice", "", now))
+		mock.ExpectExec(`INSERT INTO user_identities`).
+			WithArgs(identity.Issuer, identity.Subject, int64(6)).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		user, err := repo.GetOrCreateExternalUser(context.Background(), identity)
+		require.NoError(t, err)
+		assert.Equal(t, int64(6), user.ID)
+		assert.Equal(t, "alice", user.Login)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Taken login falls back", func(t *testing.T) {
+		repo, mock := newRepo(t)
+		mock.ExpectQuery(`FROM user_identities i JOIN users u`).
+			WithArgs(identity.Issuer, identity.Subject).
+			WillReturnError(pgx.ErrNoRows)
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO users`).
+			WithArgs("alice").
+			WillReturnError(pgx.ErrNoRows)
+		mock.ExpectQuery(`INSERT INTO users`).
+			WithArgs("oidc:user-1").
+			WillReturnRows(pgxmock.NewRows(userColumns).AddRow(int64(7), "oidc:user-1", "", now))
+		mock.ExpectExec(`INSERT INTO user_identities`).
+			WithArgs(identity.Issuer, identity.Subject, int64(7)).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		user, err := repo.GetOrCreateExternalUser(context.Background(), identity)
+		require.NoError(t, err)
+		assert.Equal(t, "oidc:user-1", user.Login)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Concurrent request linked first", func(t *testing.T) {
+		repo, mock := newRepo(t)
+		mock.ExpectQuery(`FROM user_identities i JOIN users u`).
+			WithArgs(identity.Issuer, identity.Subject).
+			WillReturnError(pgx.ErrNoRows)
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO users`).
+			WithArgs("alice").
+			WillReturnError(pgx.ErrNoRows)
+		mock.ExpectQuery(`INSERT INTO users`).
+			WithArgs("oidc:user-1").
+			WillReturnRows(pgxmock.NewRows(userColumns).AddRow(int64(8), "oidc:user-1", "", now))
+		mock.ExpectExec(`INSERT INTO user_identities`).
+			WithArgs(identity.Issuer, identity.Subject, int64(8)).
+			WillReturnResult(pgxmock.NewResult("INSERT", 0))
+		mock.ExpectQuery(`FROM user_identities i JOIN users u`).
+			WithArgs(identity.Issuer, identity.Subject).
+			WillReturnRows(pgxmock.NewRows(userColumns).AddRow(int64(6), "alice", "", now))
+		mock.ExpectRollback()
+
+		user, err := repo.GetOrCreateExternalUser(context.Background(), identity)
+		require.NoError(t, err)
+		assert.Equal(t, int64(6), user.ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Database error", func(t *testing.T) {
+		repo, mock := newRepo(t)
+		mock.ExpectQuery(`FROM user_identities i JOIN users u`).
+			WithArgs(identity.Issuer, identity.Subject).
+			WillReturnError(errors.New("connection refused"))
+
+		_, err := repo.GetOrCreateExternalUser(context.Background(), identity)
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, domain.ErrUserNotFound)
+	})
+}
+
+func TestUserRepository_LinkExternalIdentity(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewUserRepository(mock, nil)
+	ctx := context.Background()
+	identity := domain.ExternalIdentity{Issuer: "google", Subject: "1001", Login: "alice@example.com"}
+
+	t.Run("Linked", func(t *testing.T) {
+		mock.ExpectQuery(`INSERT INTO user_identities`).
+			WithArgs(identity.Issuer, identity.Subject, int64(5)).
+			WillReturnRows(pgxmock.NewRows([]string{"user_id"}).AddRow(int64(5)))
+
+		assert.NoError(t, repo.LinkExternalIdentity(ctx, 5, identity))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Linked to another user", func(t *testing.T) {
+		mock.ExpectQuery(`INSERT INTO user_identities`).
+			WithArgs(identity.Issuer, identity.Subject, int64(5)).
+			WillReturnRows(pgxmock.NewRows([]string{"user_id"}).AddRow(int64(9)))
+
+		assert.ErrorIs(t, repo.LinkExternalIdentity(ctx, 5, identity), domain.ErrIdentityAlreadyLinked)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("User deleted", func(t *testing.T) {
+		mock.ExpectQuery(`INSERT INTO user_identities`).
+			WithArgs(identity.Issuer, identity.Subject, int64(5)).
+			WillReturnError(&pgconn.PgError{Code: "23503"})
+
+		assert.ErrorIs(t, repo.LinkExternalIdentity(ctx, 5, identity), domain.ErrUserNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestUserRepository_MergeUsers(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewUserRepository(mock, nil)
+	ctx := context.Background()
+
+	expectUsers := func(rows *pgxmock.Rows) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT id, login, merged_into IS NOT NULL FROM users WHERE login IN \(\$1, \$2\) ORDER BY id FOR UPDATE`).
+			WithArgs("alice2", "alice").
+			WillReturnRows(rows)
+	}
+	expectCounts := func() {
+		mock.ExpectExec(`INSERT INTO user_balances \(user_id\) VALUES \(\$1\) ON CONFLICT \(user_id\) DO UPDATE SET updated_at = user_balances.updated_at`).
+			WithArgs(int64(3)).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectExec(`INSERT INTO user_balances \(user_id\) VALUES \(\$1\) ON CONFLICT \(user_id\) DO UPDATE SET updated_at = user_balances.updated_at`).
+			WithArgs(int64(8)).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectQuery(`SELECT \(SELECT COUNT\(\*\) FROM orders WHERE user_id = \$1\), COUNT\(\*\), COALESCE\(SUM\(amount\), 0\) FROM transactions WHERE user_id = \$1`).
+			WithArgs(int64(8)).
+			WillReturnRows(pgxmock.NewRows([]string{"orders", "transactions", "balance"}).AddRow(2, 3, 250.5))
+	}
+	users := func() *pgxmock.Rows {
+		return pgxmock.NewRows([]string{"id", "login", "merged"}).
+			AddRow(int64(3), "alice", false).
+			AddRow(int64(8), "alice2", false)
+	}
+
+	t.Run("Merged", func(t *testing.T) {
+		createdAt := time.Now()
+
+		expectUsers(users())
+		expectCounts()
+		mock.ExpectExec(`UPDATE orders SET user_id = \$1 WHERE user_id = \$2`).
+			WithArgs(int64(3), int64(8)).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 2))
+		mock.ExpectQuery(`SELECT order_number, SUM\(amount\) FROM transactions WHERE user_id = \$1 AND type IN \(\$2, \$3\) GROUP BY order_number HAVING SUM\(amount\) <> 0 ORDER BY MIN\(id\)`).
+			WithArgs(int64(8), domain.TransactionTypeAccrual, domain.TransactionTypeAdjustment).
+			WillReturnRows(pgxmock.NewRows([]string{"order_number", "sum"}).
+				AddRow("12345678903", 300.5))
+		for _, adj := range []struct {
+			userID int64
+			amount float64
+		}{{8, -300.5}, {3, 300.5}} {
+			mock.ExpectQuery(`INSERT INTO transactions \(user_id, order_number, amount, type, public_id\)`).
+				WithArgs(adj.userID, "12345678903", adj.amount, domain.TransactionTypeAdjustment, pgxmock.AnyArg()).
+				WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(int64(100)))
+		}
+		withdrawalID := uuid.New()
+		withdrawnAt := time.Date(2024, 2, 10, 12, 0, 0, 0, time.UTC)
+		mock.ExpectQuery(`SELECT public_id, order_number, amount, COALESCE\(partner, ''\), processed_at FROM transactions WHERE user_id = \$1 AND type = \$2 AND amount < 0 ORDER BY id`).
+			WithArgs(int64(8), domain.TransactionTypeWithdrawal).
+			WillReturnRows(pgxmock.NewRows([]string{"public_id", "order_number", "amount", "partner", "processed_at"}).
+				AddRow(withdrawalID, "2377225624", -50.0, "shop", withdrawnAt))
+		mock.ExpectExec(`INSERT INTO transactions \(user_id, order_number, amount, type, public_id, partner, merged_from\)`).
+			WithArgs(int64(8), "2377225624", 50.0, domain.TransactionTypeWithdrawal, pgxmock.AnyArg(), "shop", withdrawalID).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectExec(`INSERT INTO transactions \(user_id, order_number, amount, type, public_id, partner, processed_at, merged_from\)`).
+			WithArgs(int64(3), "2377225624", -50.0, domain.TransactionTypeWithdrawal, pgxmock.AnyArg(), "shop", withdrawnAt, withdrawalID).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectExec(`UPDATE user_identities SET user_id = \$1 WHERE user_id = \$2`).
+			WithArgs(int64(3), int64(8)).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+		mock.ExpectExec(`DELETE FROM withdrawal_limits WHERE user_id = \$1`).
+			WithArgs(int64(8)).
+			WillReturnResult(pgxmock.NewResult("DELETE", 0))
+		mock.ExpectExec(`UPDATE users SET password_hash = '', merged_into = \$1 WHERE id = \$2`).
+			WithArgs(int64(3), int64(8)).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectQuery(`INSERT INTO user_merges`).
+			WithArgs(int64(8), "alice2", int64(3), "alice", 2, 3, 250.5, int64(1)).
+			WillReturnRows(pgxmock.NewRows([]string{"created_at"}).AddRow(createdAt))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		merge, err := repo.MergeUsers(ctx, "alice2", "alice", 1, false)
+		require.NoError(t, err)
+		assert.Equal(t, &domain.UserMerge{
+			SourceUserID: 8,
+			SourceLogin:  "alice2",
+			TargetUserID: 3,
+			TargetLogin:  "alice",
+			Orders:       2,
+			Transactions: 3,
+			Balance:      250.5,
+			MergedBy:     1,
+			CreatedAt:    createdAt,
+		}, merge)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Dry run changes nothing", func(t *testing.T) {
+		expectUsers(users())
+		expectCounts()
+		mock.ExpectRollback()
+
+		merge, err := repo.MergeUsers(ctx, "alice2", "alice", 1, true)
+		require.NoError(t, err)
+		assert.True(t, merge.DryRun)
+		assert.Equal(t, 2, merge.Orders)
+		assert.Equal(t, 250.5, merge.Balance)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("User not found", func(t *testing.T) {
+		expectUsers(pgxmock.NewRows([]string{"id", "login", "merged"}).AddRow(int64(3), "alice", false))
+		mock.ExpectRollback()
+
+		_, err := repo.MergeUsers(ctx, "alice2", "alice", 1, false)
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Already merged", func(t *testing.T) {
+		expectUsers(pgxmock.NewRows([]string{"id", "login", "merged"}).
+			AddRow(int64(3), "alice", false).
+			AddRow(int64(8), "alice2", true))
+		mock.ExpectRollback()
+
+		_, err := repo.MergeUsers(ctx, "alice2", "alice", 1, false)
+		assert.ErrorIs(t, err, domain.ErrUserMerged)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,119 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+// WithdrawalLimitRepository хранит ограничения списаний, заданные администратором.
+type WithdrawalLimitRepository struct {
+	db DBTX
+}
+
+// NewWithdrawalLimitRepository создает новый WithdrawalLimitRepository
+func NewWithdrawalLimitRepository(db DBTX) *WithdrawalLimitRepository {
+	return &WithdrawalLimitRepository{db: db}
+}
+
+// GetWithdrawalLimits получает ограничения пользователя.
+// Если администратор их не задавал, возвращает ErrWithdrawalLimitsNotSet.
+func (r *WithdrawalLimitRepository) GetWithdrawalLimits(ctx context.Context, userID int64) (*domain.WithdrawalLimits, error) {
+	limits := &domain.WithdrawalLimits{}
+
+	err := r.db.QueryRow(ctx,
+		`SELECT per_withdrawal, daily, monthly 
+		 FROM withdrawal_limits 
+		 WHERE user_id = $1`,
+		userID,
+	).Scan(&limits.PerWithdrawal, &limits.Daily, &limits.Monthly)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrWithdrawalLimitsNotSet
+		}
+		return nil, fmt.Errorf("repository: failed to get withdrawal limits for user %d: %w", userID, err)
+	}
+
+	return limits, nil
+}
+
+// GetWithdrawalLimitsByLogin получает ограничения пользователя по логину.
+// Возвращает ErrUserNotFound для неизвестного логина и ErrWithdrawalLimitsNotSet,
+// если ограничения не заданы.
+func (r *WithdrawalLimitRepository) GetWithdrawalLimitsByLogin(ctx context.Context, login string) (*domain.WithdrawalLimits, error) {
+	var perWithdrawal, daily, monthly *float64
+
+	err := r.db.QueryRow(ctx,
+		`SELECT l.per_withdrawal, l.daily, l.monthly 
+		 FROM users u 
+		 LEFT JOIN withdrawal_limits l ON l.user_id = u.id 
+		 WHERE u.login = $1`,
+		login,
+	).Scan(&perWithdrawal, &daily, &monthly)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("repository: failed to get withdrawal limits for %q: %w", login, err)
+	}
+
+	if perWithdrawal == nil || daily == nil || monthly == nil {
+		return nil, domain.ErrWithdrawalLimitsNotSet
+	}
+
+	return &domain.WithdrawalLimits{PerWithdrawal: *perWithdrawal, Daily: *daily, Monthly: *monthly}, nil
+}
+
+// SetWithdrawalLimits задает ограничения пользователя, заменяя прежние
+func (r *WithdrawalLimitRepository) SetWithdrawalLimits(ctx context.Context, login string, limits domain.WithdrawalLimits) error {
+	result, err := r.db.Exec(ctx,
+		`INSERT INTO withdrawal_limits (user_id, per_withdrawal, daily, monthly) 
+		 SELECT id, $2, $3, $4 FROM users WHERE login = $1 
+		 ON CONFLICT (user_id) DO UPDATE 
+		 SET per_withdrawal = EXCLUDED.per_withdrawal, 
+		     daily = EXCLUDED.daily, 
+		     monthly = EXCLUDED.monthly, 
+		     updated_at = NOW()`,
+		login, limits.PerWithdrawal, limits.Daily, limits.Monthly,
+	)
+
+	if err != nil {
+		if isCheckViolation(err) {
+			return fmt.Errorf("repository: negative withdrawal limit for %q: %w", login, domain.ErrInvalidInput)
+		}
+		return fmt.Errorf("repository: failed to set withdrawal limits for %q: %w", login, err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// DeleteWithdrawalLimits удаляет ограничения пользователя, возвращая значения по умолчанию.
+// Удаление отсутствующих ограничений не считается ошибкой.
+func (r *WithdrawalLimitRepository) DeleteWithdrawalLimits(ctx context.Context, login string) error {
+	var userID int64
+
+	err := r.db.QueryRow(ctx,
+		`WITH u AS (SELECT id FROM users WHERE login = $1), 
+		 deleted AS (DELETE FROM withdrawal_limits l USING u WHERE l.user_id = u.id) 
+		 SELECT id FROM u`,
+		login,
+	).Scan(&userID)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrUserNotFound
+		}
+		return fmt.Errorf("repository: failed to delete withdrawal limits for %q: %w", login, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,170 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithdrawalLimitRepository_GetWithdrawalLimits(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewWithdrawalLimitRepository(mock)
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT per_withdrawal, daily, monthly FROM withdrawal_limits WHERE user_id`).
+			WithArgs(int64(1)).
+			WillReturnRows(pgxmock.NewRows([]string{"per_withdrawal", "daily", "monthly"}).AddRow(500.0, 1000.0, 0.0))
+
+		limits, err := repo.GetWithdrawalLimits(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, domain.WithdrawalLimits{PerWithdrawal: 500, Daily: 1000}, *limits)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Not set", func(t *testing.T) {
+		mock.ExpectQuery(`FROM withdrawal_limits`).
+			WithArgs(int64(2)).
+			WillReturnError(pgx.ErrNoRows)
+
+		limits, err := repo.GetWithdrawalLimits(ctx, 2)
+		assert.ErrorIs(t, err, domain.ErrWithdrawalLimitsNotSet)
+		assert.Nil(t, limits)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestWithdrawalLimitRepository_GetWithdrawalLimitsByLogin(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewWithdrawalLimitRepository(mock)
+	ctx := context.Background()
+	columns := []string{"per_withdrawal", "daily", "monthly"}
+
+	t.Run("Success", func(t *testing.T) {
+		perWithdrawal, daily, monthly := 500.0, 1000.0, 3000.0
+		mock.ExpectQuery(`FROM users u LEFT JOIN withdrawal_limits l ON l.user_id = u.id WHERE u.login`).
+			WithArgs("alice").
+			WillReturnRows(pgxmock.NewRows(columns).AddRow(&perWithdrawal, &daily, &monthly))
+
+		limits, err := repo.GetWithdrawalLimitsByLogin(ctx, "alice")
+		require.NoError(t, err)
+		assert.Equal(t, domain.WithdrawalLimits{PerWithdrawal: 500, Daily: 1000, Monthly: 3000}, *limits)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Not set", func(t *testing.T) {
+		mock.ExpectQuery(`LEFT JOIN withdrawal_limits`).
+			WithArgs("bob").
+			WillReturnRows(pgxmock.NewRows(columns).AddRow(nil, nil, nil))
+
+		limits, err := repo.GetWithdrawalLimitsByLogin(ctx, "bob")
+		assert.ErrorIs(t, err, domain.ErrWithdrawalLimitsNotSet)
+		assert.Nil(t, limits)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Unknown user", func(t *testing.T) {
+		mock.ExpectQuery(`LEFT JOIN withdrawal_limits`).
+			WithArgs("nobody").
+			WillReturnError(pgx.ErrNoRows)
+
+		limits, err := repo.GetWithdrawalLimitsByLogin(ctx, "nobody")
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+		assert.Nil(t, limits)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestWithdrawalLimitRepository_SetWithdrawalLimits(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewWithdrawalLimitRepository(mock)
+	ctx := context.Background()
+	limits := domain.WithdrawalLimits{PerWithdrawal: 500, Daily: 1000, Monthly: 3000}
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectExec(`INSERT INTO withdrawal_limits .* SELECT id, \$2, \$3, \$4 FROM users WHERE login = \$1 ON CONFLICT \(user_id\) DO UPDATE`).
+			WithArgs("alice", 500.0, 1000.0, 3000.0).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+		assert.NoError(t, repo.SetWithdrawalLimits(ctx, "alice", limits))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Unknown user", func(t *testing.T) {
+		mock.ExpectExec(`INSERT INTO withdrawal_limits`).
+			WithArgs("nobody", 500.0, 1000.0, 3000.0).
+			WillReturnResult(pgxmock.NewResult("INSERT", 0))
+
+		assert.ErrorIs(t, repo.SetWithdrawalLimits(ctx, "nobody", limits), domain.ErrUserNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Negative limit", func(t *testing.T) {
+		mock.ExpectExec(`INSERT INTO withdrawal_limits`).
+			WithArgs("alice", -1.0, 0.0, 0.0).
+			WillReturnError(&pgconn.PgError{Code: pgCodeCheckViolation})
+
+		err := repo.SetWithdrawalLimits(ctx, "alice", domain.WithdrawalLimits{PerWithdrawal: -1})
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestWithdrawalLimitRepository_DeleteWithdrawalLimits(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewWithdrawalLimitRepository(mock)
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectQuery(`DELETE FROM withdrawal_limits l USING u`).
+			WithArgs("alice").
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(int64(1)))
+
+		assert.NoError(t, repo.DeleteWithdrawalLimits(ctx, "alice"))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Unknown user", func(t *testing.T) {
+		mock.ExpectQuery(`DELETE FROM withdrawal_limits`).
+			WithArgs("nobody").
+			WillReturnError(pgx.ErrNoRows)
+
+		assert.ErrorIs(t, repo.DeleteWithdrawalLimits(ctx, "nobody"), domain.ErrUserNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Database error", func(t *testing.T) {
+		mock.ExpectQuery(`DELETE FROM withdrawal_limits`).
+			WithArgs("alice").
+			WillReturnError(errors.New("connection reset"))
+
+		err := repo.DeleteWithdrawalLimits(ctx, "alice")
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, domain.ErrUserNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
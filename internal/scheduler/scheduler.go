@@ -0,0 +1,90 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Leadership сообщает, удерживает ли реплика лидерство
+type Leadership interface {
+	IsLeader() bool
+}
+
+// Task - периодическая задача. Ошибка пишется в лог, следующий запуск - по расписанию
+type Task func(ctx context.Context) error
+
+type task struct {
+	name     string
+	interval time.Duration
+	run      Task
+}
+
+// Scheduler запускает периодические задачи только на лидере, чтобы реплики
+// не выполняли их одновременно. leadership может быть nil - задачи выполняются всегда.
+type Scheduler struct {
+	leadership Leadership
+	logger     *zap.Logger
+	tasks      []task
+	wg         sync.WaitGroup
+}
+
+// New создает новый Scheduler
+func New(leadership Leadership, logger *zap.Logger) *Scheduler {
+	return &Scheduler{
+		leadership: leadership,
+		logger:     logger,
+	}
+}
+
+// Every регистрирует задачу с интервалом interval. Задача с неположительным интервалом отключена.
+// Задачи регистрируются до Start
+func (s *Scheduler) Every(name string, interval time.Duration, run Task) {
+	if interval <= 0 {
+		s.logger.Info("scheduled task disabled", zap.String("task", name))
+		return
+	}
+	s.tasks = append(s.tasks, task{name: name, interval: interval, run: run})
+}
+
+// Start запускает задачи до отмены контекста. Первый запуск - через интервал после старта
+func (s *Scheduler) Start(ctx context.Context) {
+	for _, t := range s.tasks {
+		s.wg.Add(1)
+		go s.loop(ctx, t)
+	}
+}
+
+// Stop ожидает завершения выполняемых задач после отмены контекста
+func (s *Scheduler) Stop() {
+	s.wg.Wait()
+}
+
+func (s *Scheduler) loop(ctx context.Context, t task) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if s.leadership != nil && !s.leadership.IsLeader() {
+			s.logger.Debug("not a leader, scheduled task skipped", zap.String("task", t.name))
+			continue
+		}
+
+		start := time.Now()
+		if err := t.run(ctx); err != nil {
+			s.logger.Error("scheduled task failed", zap.String("task", t.name), zap.Error(err))
+			continue
+		}
+		s.logger.Debug("scheduled task completed", zap.String("task", t.name), zap.Duration("duration", time.Since(start)))
+	}
+}
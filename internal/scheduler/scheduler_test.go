@@ -0,0 +1,69 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type fakeLeadership struct {
+	leader atomic.Bool
+}
+
+func (l *fakeLeadership) IsLeader() bool {
+	return l.leader.Load()
+}
+
+func TestScheduler_RunsOnLeaderOnly(t *testing.T) {
+	leadership := &fakeLeadership{}
+	s := New(leadership, zap.NewNop())
+
+	var runs atomic.Int32
+	s.Every("count", 5*time.Millisecond, func(ctx context.Context) error {
+		runs.Add(1)
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.Start(ctx)
+
+	time.Sleep(30 * time.Millisecond)
+	assert.Zero(t, runs.Load())
+
+	leadership.leader.Store(true)
+	assert.Eventually(t, func() bool { return runs.Load() >= 2 }, time.Second, 5*time.Millisecond)
+
+	cancel()
+	s.Stop()
+}
+
+func TestScheduler_ErrorDoesNotStopTask(t *testing.T) {
+	s := New(nil, zap.NewNop())
+
+	var runs atomic.Int32
+	s.Every("failing", 5*time.Millisecond, func(ctx context.Context) error {
+		runs.Add(1)
+		return errors.New("boom")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.Start(ctx)
+	assert.Eventually(t, func() bool { return runs.Load() >= 2 }, time.Second, 5*time.Millisecond)
+
+	cancel()
+	s.Stop()
+}
+
+func TestScheduler_DisabledTask(t *testing.T) {
+	s := New(nil, zap.NewNop())
+	s.Every("disabled", 0, func(ctx context.Context) error {
+		t.Error("disabled task must not run")
+		return nil
+	})
+	assert.Empty(t, s.tasks)
+}
@@ -4,14 +4,25 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"net/http"
 	"strconv"
 	"time"
+	"unicode/utf8"
 
+	"github.com/hashicorp/go-cleanhttp"
 	"github.com/hashicorp/go-retryablehttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
 
 	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/avc/loyalty-system-diploma/internal/metrics"
+	"github.com/avc/loyalty-system-diploma/internal/tracing"
 )
 
 // AccrualClient определяет методы взаимодействия с системой начислений.
@@ -19,10 +30,25 @@ type AccrualClient interface {
 	GetOrderAccrual(ctx context.Context, orderNumber string) (*domain.AccrualResponse, error)
 }
 
+// maxAccrual соответствует максимуму колонки DECIMAL(10,2)
+const maxAccrual = 99_999_999.99
+
+// maxRewardRuleMatchLength соответствует колонке match локальной копии правил
+const maxRewardRuleMatchLength = 255
+
+// accrualPingTimeout ограничивает проверку доступности системы начислений
+const accrualPingTimeout = 2 * time.Second
+
+// accrualRequestTimeout ограничивает запрос к системе начислений
+const accrualRequestTimeout = 10 * time.Second
+
 // HTTPAccrualClient реализует AccrualClient.
 type HTTPAccrualClient struct {
-	baseURL    string
-	httpClient *http.Client
+	baseURL     string
+	httpClient  *http.Client
+	probeClient *http.Client // без повторов, для проверки доступности
+	logger      *zap.Logger
+	orders      singleflight.Group // Одновременные запросы по номеру заказа
 }
 
 type zapRetryLogger struct {
@@ -36,20 +62,148 @@ func (l *zapRetryLogger) Printf(format string, args ...any) {
 	l.logger.Infof(format, args...)
 }
 
-// NewAccrualClient создает новый AccrualClient
-func NewAccrualClient(baseURL string, logger *zap.Logger) AccrualClient {
+// checkRetry повторяет запросы по стандартной политике retryablehttp,
+// но не трогает 429: лимит обрабатывается воркером через общий cooldown
+func checkRetry(ctx context.Context, resp *http.Response, err error) (bool, error) {
+	if err == nil && resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+		return false, nil
+	}
+	return retryablehttp.DefaultRetryPolicy(ctx, resp, err)
+}
+
+// instrumentedTransport учитывает в метриках каждую попытку запроса, включая повторы,
+// и передает контекст трассы в заголовке traceparent: каждая попытка - отдельный спан
+type instrumentedTransport struct {
+	next    http.RoundTripper
+	metrics *metrics.Metrics
+}
+
+func (t *instrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, span := tracing.Tracer().Start(req.Context(), "accrual "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.HTTPRequestMethodKey.String(req.Method),
+			semconv.URLFull(req.URL.String()),
+		),
+	)
+	defer span.End()
+
+	// RoundTripper не должен изменять исходный запрос
+	req = req.Clone(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		t.metrics.ObserveAccrualRequest("error", time.Since(start))
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	t.metrics.ObserveAccrualRequest(accrualStatusLabel(resp.StatusCode), time.Since(start))
+	span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+	if resp.StatusCode >= http.StatusInternalServerError {
+		span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			t.metrics.ObserveAccrualRetryAfter(time.Duration(seconds) * time.Second)
+		}
+	}
+
+	return resp, nil
+}
+
+// accrualStatusLabel группирует коды ответа, чтобы не раздувать кардинальность метки:
+// коды протокола системы начислений учитываются отдельно, остальные - по классу
+func accrualStatusLabel(code int) string {
+	switch {
+	case code == http.StatusOK, code == http.StatusNoContent, code == http.StatusTooManyRequests:
+		return strconv.Itoa(code)
+	case code >= 500:
+		return "5xx"
+	case code >= 400:
+		return "4xx"
+	default:
+		return "other"
+	}
+}
+
+// coalesceAccrual объединяет одновременные запросы начисления по номеру заказа:
+// воркер и проверка корректировок могут запросить один заказ одновременно
+func coalesceAccrual(
+	ctx context.Context,
+	group *singleflight.Group,
+	orderNumber string,
+	get func(context.Context, string) (*domain.AccrualResponse, error),
+) (*domain.AccrualResponse, error) {
+	resp, err := coalesce(ctx, group, orderNumber, func(ctx context.Context) (*domain.AccrualResponse, error) {
+		return get(ctx, orderNumber)
+	})
+	if resp == nil || err != nil {
+		return nil, err
+	}
+	// Ответ общий для всех ожидавших, каждый получает свою копию
+	result := *resp
+	return &result, nil
+}
+
+// NewAccrualClient создает новый AccrualClient.
+// transport == nil - стандартный транспорт с прокси из переменных окружения
+func NewAccrualClient(baseURL string, transport http.RoundTripper, m *metrics.Metrics, logger *zap.Logger) *HTTPAccrualClient {
+	if transport == nil {
+		transport = cleanhttp.DefaultPooledTransport()
+	}
+
 	retryClient := retryablehttp.NewClient()
-	retryClient.HTTPClient.Timeout = 10 * time.Second
+	retryClient.HTTPClient.Timeout = accrualRequestTimeout
+	retryClient.HTTPClient.Transport = &instrumentedTransport{
+		next:    transport,
+		metrics: m,
+	}
 	retryClient.Logger = &zapRetryLogger{logger: logger.Sugar()}
+	retryClient.CheckRetry = checkRetry
 
 	return &HTTPAccrualClient{
-		baseURL: baseURL,
-		httpClient: retryClient.StandardClient(),
+		baseURL:     baseURL,
+		httpClient:  retryClient.StandardClient(),
+		probeClient: &http.Client{Timeout: accrualPingTimeout, Transport: transport},
+		logger:      logger,
+	}
+}
+
+// Close закрывает простаивающие соединения
+func (c *HTTPAccrualClient) Close() error {
+	c.httpClient.CloseIdleConnections()
+	c.probeClient.CloseIdleConnections()
+	return nil
+}
+
+// Ping проверяет, что система начислений отвечает по HTTP.
+// Любой ответ, включая 404, означает, что сервис доступен.
+func (c *HTTPAccrualClient) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL, nil)
+	if err != nil {
+		return fmt.Errorf("accrual client: failed to create ping request: %w", err)
+	}
+
+	resp, err := c.probeClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("accrual client: ping failed: %w", err)
 	}
+	resp.Body.Close()
+
+	return nil
 }
 
-// GetOrderAccrual получает информацию о начислении для заказа
+// GetOrderAccrual получает информацию о начислении для заказа.
+// Одновременные запросы по одному заказу выполняются одним HTTP запросом
 func (c *HTTPAccrualClient) GetOrderAccrual(ctx context.Context, orderNumber string) (*domain.AccrualResponse, error) {
+	return coalesceAccrual(ctx, &c.orders, orderNumber, c.getOrderAccrual)
+}
+
+func (c *HTTPAccrualClient) getOrderAccrual(ctx context.Context, orderNumber string) (*domain.AccrualResponse, error) {
 	url := fmt.Sprintf("%s/api/orders/%s", c.baseURL, orderNumber)
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
@@ -69,6 +223,9 @@ func (c *HTTPAccrualClient) GetOrderAccrual(ctx context.Context, orderNumber str
 		if err := json.NewDecoder(resp.Body).Decode(&accrualResp); err != nil {
 			return nil, fmt.Errorf("accrual client: failed to decode response: %w", err)
 		}
+		if err := validateAccrualResponse(orderNumber, &accrualResp, c.logger); err != nil {
+			return nil, err
+		}
 		return &accrualResp, nil
 
 	case http.StatusNoContent:
@@ -88,3 +245,108 @@ func (c *HTTPAccrualClient) GetOrderAccrual(ctx context.Context, orderNumber str
 		return nil, fmt.Errorf("accrual client: unexpected status code: %d", resp.StatusCode)
 	}
 }
+
+// GetRewardRules получает правила вознаграждения системы начислений (GET /api/goods).
+// Если система начислений не отдает правила, возвращает ErrRewardRulesUnavailable
+func (c *HTTPAccrualClient) GetRewardRules(ctx context.Context) ([]domain.RewardRule, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/api/goods", nil)
+	if err != nil {
+		return nil, fmt.Errorf("accrual client: failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("accrual client: failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		var rules []domain.RewardRule
+		if err := json.NewDecoder(resp.Body).Decode(&rules); err != nil {
+			return nil, fmt.Errorf("accrual client: failed to decode reward rules: %w", err)
+		}
+		if err := validateRewardRules(rules); err != nil {
+			return nil, err
+		}
+		return rules, nil
+
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		// Чтение правил - необязательная часть API системы начислений
+		return nil, fmt.Errorf("accrual client: %w", domain.ErrRewardRulesUnavailable)
+
+	case http.StatusTooManyRequests:
+		retryAfter := resp.Header.Get("Retry-After")
+		seconds, err := strconv.Atoi(retryAfter)
+		if err != nil {
+			return nil, fmt.Errorf("accrual client: invalid Retry-After header %q: %w", retryAfter, err)
+		}
+		return nil, NewRateLimitError(time.Duration(seconds) * time.Second)
+
+	default:
+		return nil, fmt.Errorf("accrual client: unexpected status code: %d", resp.StatusCode)
+	}
+}
+
+// validateRewardRules проверяет правила вознаграждения. Набор с ошибкой отклоняется
+// целиком, чтобы локальная копия не потеряла правила из-за сбоя системы начислений
+func validateRewardRules(rules []domain.RewardRule) error {
+	for _, rule := range rules {
+		switch {
+		case rule.Match == "" || utf8.RuneCountInString(rule.Match) > maxRewardRuleMatchLength:
+			return fmt.Errorf("accrual client: invalid reward rule match %q: %w", rule.Match, domain.ErrInvalidAccrualResponse)
+		case !rule.RewardType.IsValid():
+			return fmt.Errorf("accrual client: unknown reward type %q for %q: %w",
+				rule.RewardType, rule.Match, domain.ErrInvalidAccrualResponse)
+		case math.IsNaN(rule.Reward) || rule.Reward < 0 || rule.Reward > maxAccrual,
+			rule.RewardType == domain.RewardTypePercent && rule.Reward > 100:
+			return fmt.Errorf("accrual client: invalid reward %f for %q: %w",
+				rule.Reward, rule.Match, domain.ErrInvalidAccrualResponse)
+		}
+	}
+	return nil
+}
+
+// validateAccrualResponse проверяет ответ системы начислений перед записью в БД.
+// Неизвестный статус, чужой номер заказа и слишком большое начисление отклоняются,
+// отрицательное начисление приводится к нулю с предупреждением.
+func validateAccrualResponse(orderNumber string, resp *domain.AccrualResponse, logger *zap.Logger) error {
+	if resp.Order != orderNumber {
+		return fmt.Errorf("accrual client: response for order %q does not match requested %q: %w",
+			resp.Order, orderNumber, domain.ErrInvalidAccrualResponse)
+	}
+
+	if !resp.Status.IsValid() {
+		return fmt.Errorf("accrual client: unknown status %q for order %q: %w",
+			resp.Status, orderNumber, domain.ErrInvalidAccrualResponse)
+	}
+
+	if resp.Accrual == nil {
+		return nil
+	}
+
+	accrual := *resp.Accrual
+	switch {
+	case accrual > maxAccrual:
+		return fmt.Errorf("accrual client: accrual %f for order %q exceeds maximum: %w",
+			accrual, orderNumber, domain.ErrInvalidAccrualResponse)
+	case accrual < 0:
+		logger.Warn("negative accrual clamped to zero",
+			zap.String("order", orderNumber),
+			zap.Float64("accrual", accrual),
+		)
+		zero := 0.0
+		resp.Accrual = &zero
+	}
+
+	if resp.Status != domain.AccrualStatusProcessed {
+		logger.Warn("accrual ignored for non-final status",
+			zap.String("order", orderNumber),
+			zap.String("status", string(resp.Status)),
+			zap.Float64("accrual", *resp.Accrual),
+		)
+		resp.Accrual = nil
+	}
+
+	return nil
+}
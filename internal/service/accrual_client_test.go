@@ -5,11 +5,19 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/avc/loyalty-system-diploma/internal/metrics"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"go.uber.org/zap"
 )
 
@@ -20,7 +28,7 @@ func TestAccrualClient_GetOrderAccrual(t *testing.T) {
 		accrual := 100.0
 		response := domain.AccrualResponse{
 			Order:   "12345678903",
-			Status:  domain.OrderStatusProcessed,
+			Status:  domain.AccrualStatusProcessed,
 			Accrual: &accrual,
 		}
 
@@ -31,7 +39,7 @@ func TestAccrualClient_GetOrderAccrual(t *testing.T) {
 		}))
 		defer server.Close()
 
-		client := NewAccrualClient(server.URL, zap.NewNop())
+		client := NewAccrualClient(server.URL, nil, nil, zap.NewNop())
 		result, err := client.GetOrderAccrual(ctx, "12345678903")
 		require.NoError(t, err)
 		assert.Equal(t, response.Order, result.Order)
@@ -42,7 +50,7 @@ func TestAccrualClient_GetOrderAccrual(t *testing.T) {
 	t.Run("Success - order processing", func(t *testing.T) {
 		response := domain.AccrualResponse{
 			Order:  "12345678903",
-			Status: domain.OrderStatusProcessing,
+			Status: domain.AccrualStatusProcessing,
 		}
 
 		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -51,7 +59,7 @@ func TestAccrualClient_GetOrderAccrual(t *testing.T) {
 		}))
 		defer server.Close()
 
-		client := NewAccrualClient(server.URL, zap.NewNop())
+		client := NewAccrualClient(server.URL, nil, nil, zap.NewNop())
 		result, err := client.GetOrderAccrual(ctx, "12345678903")
 		require.NoError(t, err)
 		assert.Equal(t, response.Status, result.Status)
@@ -64,7 +72,7 @@ func TestAccrualClient_GetOrderAccrual(t *testing.T) {
 		}))
 		defer server.Close()
 
-		client := NewAccrualClient(server.URL, zap.NewNop())
+		client := NewAccrualClient(server.URL, nil, nil, zap.NewNop())
 		result, err := client.GetOrderAccrual(ctx, "99999999999")
 		require.NoError(t, err)
 		assert.Nil(t, result)
@@ -77,7 +85,7 @@ func TestAccrualClient_GetOrderAccrual(t *testing.T) {
 		}))
 		defer server.Close()
 
-		client := NewAccrualClient(server.URL, zap.NewNop())
+		client := NewAccrualClient(server.URL, nil, nil, zap.NewNop())
 		result, err := client.GetOrderAccrual(ctx, "12345678903")
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -92,7 +100,7 @@ func TestAccrualClient_GetOrderAccrual(t *testing.T) {
 		}))
 		defer server.Close()
 
-		client := NewAccrualClient(server.URL, zap.NewNop())
+		client := NewAccrualClient(server.URL, nil, nil, zap.NewNop())
 		result, err := client.GetOrderAccrual(ctx, "12345678903")
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -105,9 +113,269 @@ func TestAccrualClient_GetOrderAccrual(t *testing.T) {
 		}))
 		defer server.Close()
 
-		client := NewAccrualClient(server.URL, zap.NewNop())
+		client := NewAccrualClient(server.URL, nil, nil, zap.NewNop())
 		result, err := client.GetOrderAccrual(ctx, "12345678903")
 		assert.Error(t, err)
 		assert.Nil(t, result)
 	})
+
+	t.Run("Malformed responses", func(t *testing.T) {
+		tests := []struct {
+			name string
+			body string
+		}{
+			{name: "Unknown status", body: `{"order":"12345678903","status":"DONE","accrual":10}`},
+			{name: "Mismatched order number", body: `{"order":"79927398713","status":"PROCESSED","accrual":10}`},
+			{name: "Accrual exceeds column precision", body: `{"order":"12345678903","status":"PROCESSED","accrual":1e12}`},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusOK)
+					w.Write([]byte(tt.body))
+				}))
+				defer server.Close()
+
+				client := NewAccrualClient(server.URL, nil, nil, zap.NewNop())
+				result, err := client.GetOrderAccrual(ctx, "12345678903")
+				assert.ErrorIs(t, err, domain.ErrInvalidAccrualResponse)
+				assert.Nil(t, result)
+			})
+		}
+	})
+
+	t.Run("Negative accrual is clamped", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"order":"12345678903","status":"PROCESSED","accrual":-5}`))
+		}))
+		defer server.Close()
+
+		client := NewAccrualClient(server.URL, nil, nil, zap.NewNop())
+		result, err := client.GetOrderAccrual(ctx, "12345678903")
+		require.NoError(t, err)
+		require.NotNil(t, result.Accrual)
+		assert.Equal(t, 0.0, *result.Accrual)
+	})
+
+	t.Run("Accrual dropped for non-final status", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"order":"12345678903","status":"REGISTERED","accrual":50}`))
+		}))
+		defer server.Close()
+
+		client := NewAccrualClient(server.URL, nil, nil, zap.NewNop())
+		result, err := client.GetOrderAccrual(ctx, "12345678903")
+		require.NoError(t, err)
+		assert.Equal(t, domain.AccrualStatusRegistered, result.Status)
+		assert.Nil(t, result.Accrual)
+	})
+}
+
+func TestAccrualClient_Ping(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Reachable", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		client := NewAccrualClient(server.URL, nil, nil, zap.NewNop())
+		assert.NoError(t, client.Ping(ctx))
+	})
+
+	t.Run("Unreachable", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		server.Close()
+
+		client := NewAccrualClient(server.URL, nil, nil, zap.NewNop())
+		assert.Error(t, client.Ping(ctx))
+	})
+}
+
+func TestAccrualClient_Metrics(t *testing.T) {
+	ctx := context.Background()
+
+	responses := []func(w http.ResponseWriter){
+		func(w http.ResponseWriter) {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"order":"12345678903","status":"PROCESSING"}`))
+		},
+		func(w http.ResponseWriter) { w.WriteHeader(http.StatusNoContent) },
+		func(w http.ResponseWriter) {
+			w.Header().Set("Retry-After", "60")
+			w.WriteHeader(http.StatusTooManyRequests)
+		},
+	}
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		responses[calls](w)
+		calls++
+	}))
+	defer server.Close()
+
+	m := metrics.New()
+	client := NewAccrualClient(server.URL, nil, m, zap.NewNop())
+	for range responses {
+		_, _ = client.GetOrderAccrual(ctx, "12345678903")
+	}
+
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := w.Body.String()
+
+	assert.Contains(t, body, `gophermart_accrual_responses_total{status="200"} 1`)
+	assert.Contains(t, body, `gophermart_accrual_responses_total{status="204"} 1`)
+	assert.Contains(t, body, `gophermart_accrual_responses_total{status="429"} 1`)
+	assert.Contains(t, body, `gophermart_accrual_request_duration_seconds_count{status="200"} 1`)
+	assert.Contains(t, body, "gophermart_accrual_retry_after_seconds_sum 60")
+}
+
+func TestAccrualStatusLabel(t *testing.T) {
+	assert.Equal(t, "200", accrualStatusLabel(http.StatusOK))
+	assert.Equal(t, "204", accrualStatusLabel(http.StatusNoContent))
+	assert.Equal(t, "429", accrualStatusLabel(http.StatusTooManyRequests))
+	assert.Equal(t, "5xx", accrualStatusLabel(http.StatusBadGateway))
+	assert.Equal(t, "4xx", accrualStatusLabel(http.StatusNotFound))
+	assert.Equal(t, "other", accrualStatusLabel(http.StatusMovedPermanently))
+}
+
+func TestAccrualClient_GetRewardRules(t *testing.T) {
+	ctx := context.Background()
+
+	serve := func(status int, body string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodGet, r.Method)
+			assert.Equal(t, "/api/goods", r.URL.Path)
+			w.Header().Set("Retry-After", "30")
+			w.WriteHeader(status)
+			w.Write([]byte(body))
+		}))
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		server := serve(http.StatusOK, `[{"match":"Bork","reward":10,"reward_type":"%"},{"match":"Acer","reward":50,"reward_type":"pt"}]`)
+		defer server.Close()
+
+		client := NewAccrualClient(server.URL, nil, nil, zap.NewNop())
+		rules, err := client.GetRewardRules(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []domain.RewardRule{
+			{Match: "Bork", Reward: 10, RewardType: domain.RewardTypePercent},
+			{Match: "Acer", Reward: 50, RewardType: domain.RewardTypePoints},
+		}, rules)
+	})
+
+	t.Run("Not exposed", func(t *testing.T) {
+		for _, status := range []int{http.StatusNotFound, http.StatusMethodNotAllowed} {
+			server := serve(status, "")
+			client := NewAccrualClient(server.URL, nil, nil, zap.NewNop())
+			_, err := client.GetRewardRules(ctx)
+			server.Close()
+			assert.ErrorIs(t, err, domain.ErrRewardRulesUnavailable)
+		}
+	})
+
+	t.Run("Rate limited", func(t *testing.T) {
+		server := serve(http.StatusTooManyRequests, "")
+		defer server.Close()
+
+		client := NewAccrualClient(server.URL, nil, nil, zap.NewNop())
+		_, err := client.GetRewardRules(ctx)
+		var rateLimitErr *RateLimitError
+		require.ErrorAs(t, err, &rateLimitErr)
+		assert.Equal(t, 30*time.Second, rateLimitErr.RetryAfter)
+	})
+
+	t.Run("Invalid rules", func(t *testing.T) {
+		bodies := map[string]string{
+			"malformed":        `{"match":"Bork"}`,
+			"empty match":      `[{"match":"","reward":10,"reward_type":"%"}]`,
+			"unknown type":     `[{"match":"Bork","reward":10,"reward_type":"rub"}]`,
+			"negative reward":  `[{"match":"Bork","reward":-1,"reward_type":"pt"}]`,
+			"percent over 100": `[{"match":"Bork","reward":101,"reward_type":"%"}]`,
+		}
+		for name, body := range bodies {
+			t.Run(name, func(t *testing.T) {
+				server := serve(http.StatusOK, body)
+				defer server.Close()
+
+				client := NewAccrualClient(server.URL, nil, nil, zap.NewNop())
+				_, err := client.GetRewardRules(ctx)
+				assert.Error(t, err)
+			})
+		}
+	})
+}
+
+func TestAccrualClient_TracePropagation(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	prevProvider, prevPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+	})
+
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "parent")
+	client := NewAccrualClient(server.URL, nil, nil, zap.NewNop())
+	_, err := client.GetOrderAccrual(ctx, "12345678903")
+	require.NoError(t, err)
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	accrual := spans[0]
+	assert.Equal(t, "accrual GET", accrual.Name())
+	assert.Equal(t, parent.SpanContext().TraceID(), accrual.SpanContext().TraceID())
+	assert.Equal(t, parent.SpanContext().SpanID(), accrual.Parent().SpanID())
+	assert.Equal(t, "00-"+accrual.SpanContext().TraceID().String()+"-"+accrual.SpanContext().SpanID().String()+"-01", traceparent)
+}
+
+func TestAccrualClient_GetOrderAccrual_Coalesced(t *testing.T) {
+	var requests atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		<-release
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"order":"12345678903","status":"PROCESSED","accrual":500}`))
+	}))
+	defer server.Close()
+
+	client := NewAccrualClient(server.URL, nil, nil, zap.NewNop())
+
+	const callers = 3
+	var wg sync.WaitGroup
+	results := make([]*domain.AccrualResponse, callers)
+	for i := range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.GetOrderAccrual(context.Background(), "12345678903")
+			assert.NoError(t, err)
+			results[i] = resp
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), requests.Load())
+	for _, resp := range results {
+		require.NotNil(t, resp)
+		assert.Equal(t, domain.AccrualStatusProcessed, resp.Status)
+	}
+	assert.NotSame(t, results[0], results[1])
 }
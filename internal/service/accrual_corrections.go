@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/avc/loyalty-system-diploma/internal/lock"
+	"github.com/avc/loyalty-system-diploma/internal/logctx"
+	"go.uber.org/zap"
+)
+
+// AccrualCorrectionService сверяет начисления по обработанным заказам с системой начислений
+// и корректирует их, если система пересчитала заказ.
+type AccrualCorrectionService struct {
+	orderRepo     OrderRepository
+	accrualClient AccrualClient
+	rounding      domain.RoundingPolicy
+	enabled       bool
+	locker        Locker
+}
+
+// NewAccrualCorrectionService создает новый AccrualCorrectionService.
+// При enabled == false корректировки отключены. locker может быть nil.
+func NewAccrualCorrectionService(orderRepo OrderRepository, accrualClient AccrualClient, rounding domain.RoundingPolicy, enabled bool, locker Locker) *AccrualCorrectionService {
+	if !rounding.IsValid() {
+		rounding = domain.DefaultRoundingPolicy()
+	}
+	return &AccrualCorrectionService{
+		orderRepo:     orderRepo,
+		accrualClient: accrualClient,
+		rounding:      rounding,
+		enabled:       enabled,
+		locker:        locker,
+	}
+}
+
+// RecheckAccrual запрашивает начисление по заказу у системы начислений и, если сумма
+// отличается от зачисленной, записывает корректировку от имени администратора adminID.
+// Если система начислений не считает заказ обработанным, возвращает ErrAccrualUnchanged.
+func (s *AccrualCorrectionService) RecheckAccrual(ctx context.Context, number string, adminID int64) (*domain.AccrualCorrection, error) {
+	if !s.enabled {
+		return nil, domain.ErrAccrualCorrectionsDisabled
+	}
+
+	resp, err := s.accrualClient.GetOrderAccrual(ctx, number)
+	if err != nil {
+		return nil, fmt.Errorf("accrual correction service: failed to get accrual for order %q: %w", number, err)
+	}
+	if resp == nil || resp.Status != domain.AccrualStatusProcessed {
+		return nil, fmt.Errorf("accrual correction service: order %q is not processed by accrual system: %w", number, domain.ErrAccrualUnchanged)
+	}
+
+	raw := 0.0
+	if resp.Accrual != nil {
+		raw = *resp.Accrual
+	}
+
+	// Система начислений опрашивается до блокировки: ее ответ не зависит от сверки
+	unlock, err := lockKeys(ctx, s.locker, lock.ReconciliationKey)
+	if err != nil {
+		return nil, fmt.Errorf("accrual correction service: failed to lock reconciliation for order %q: %w", number, err)
+	}
+	defer unlock()
+
+	correction, err := s.orderRepo.CorrectAccrual(ctx, number, s.rounding.RoundAccrual(raw), adminID)
+	if err != nil {
+		if errors.Is(err, domain.ErrAccrualUnchanged) || errors.Is(err, domain.ErrOrderNotFound) || errors.Is(err, domain.ErrOrderNotProcessed) {
+			return nil, err
+		}
+		logctx.From(ctx).Error("accrual correction service: failed to correct accrual",
+			zap.String("order", number), zap.Error(err))
+		return nil, fmt.Errorf("accrual correction service: failed to correct accrual for order %q: %w", number, err)
+	}
+
+	logctx.From(ctx).Info("accrual corrected",
+		zap.String("order", number),
+		zap.Int64("user_id", correction.UserID),
+		zap.Float64("previous_accrual", correction.PreviousAmount),
+		zap.Float64("accrual", correction.NewAmount),
+		zap.Float64("adjustment", correction.Delta),
+		zap.Int64("corrected_by", adminID),
+	)
+	return correction, nil
+}
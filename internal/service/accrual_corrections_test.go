@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	domainmocks "github.com/avc/loyalty-system-diploma/internal/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccrualCorrectionService_RecheckAccrual(t *testing.T) {
+	ctx := context.Background()
+	number := "12345678903"
+	policy := domain.RoundingPolicy{Mode: domain.RoundingFloor, Precision: 2}
+
+	newService := func(t *testing.T, enabled bool) (*AccrualCorrectionService, *domainmocks.OrderRepositoryMock, *domainmocks.AccrualClientMock) {
+		repo := domainmocks.NewOrderRepositoryMock(t)
+		client := domainmocks.NewAccrualClientMock(t)
+		return NewAccrualCorrectionService(repo, client, policy, enabled, nil), repo, client
+	}
+	processed := func(accrual *float64) *domain.AccrualResponse {
+		return &domain.AccrualResponse{Order: number, Status: domain.AccrualStatusProcessed, Accrual: accrual}
+	}
+
+	t.Run("Changed accrual is corrected", func(t *testing.T) {
+		svc, repo, client := newService(t, true)
+		raw := 70.259
+		correction := &domain.AccrualCorrection{OrderNumber: number, PreviousAmount: 100, NewAmount: 70.25, Delta: -29.75, CorrectedBy: 1}
+
+		client.EXPECT().GetOrderAccrual(mock.Anything, number).Return(processed(&raw), nil).Once()
+		repo.EXPECT().CorrectAccrual(mock.Anything, number, policy.RoundAccrual(raw), int64(1)).Return(correction, nil).Once()
+
+		result, err := svc.RecheckAccrual(ctx, number, 1)
+		require.NoError(t, err)
+		assert.Equal(t, correction, result)
+	})
+
+	t.Run("Under reconciliation lock", func(t *testing.T) {
+		repo := domainmocks.NewOrderRepositoryMock(t)
+		client := domainmocks.NewAccrualClientMock(t)
+		locker := domainmocks.NewLockerMock(t)
+		svc := NewAccrualCorrectionService(repo, client, policy, true, locker)
+		raw := 70.0
+		unlocked := false
+
+		client.EXPECT().GetOrderAccrual(mock.Anything, number).Return(processed(&raw), nil).Once()
+		locker.EXPECT().Lock(mock.Anything, "reconciliation").Return(func() { unlocked = true }, nil).Once()
+		repo.EXPECT().CorrectAccrual(mock.Anything, number, policy.RoundAccrual(raw), int64(1)).Return(nil, domain.ErrAccrualUnchanged).Once()
+
+		_, err := svc.RecheckAccrual(ctx, number, 1)
+		assert.ErrorIs(t, err, domain.ErrAccrualUnchanged)
+		assert.True(t, unlocked)
+	})
+
+	t.Run("Lock error", func(t *testing.T) {
+		client := domainmocks.NewAccrualClientMock(t)
+		locker := domainmocks.NewLockerMock(t)
+		svc := NewAccrualCorrectionService(domainmocks.NewOrderRepositoryMock(t), client, policy, true, locker)
+		raw := 70.0
+
+		client.EXPECT().GetOrderAccrual(mock.Anything, number).Return(processed(&raw), nil).Once()
+		locker.EXPECT().Lock(mock.Anything, "reconciliation").Return(nil, context.Canceled).Once()
+
+		_, err := svc.RecheckAccrual(ctx, number, 1)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("Missing accrual means zero", func(t *testing.T) {
+		svc, repo, client := newService(t, true)
+
+		client.EXPECT().GetOrderAccrual(mock.Anything, number).Return(processed(nil), nil).Once()
+		repo.EXPECT().CorrectAccrual(mock.Anything, number, policy.RoundAccrual(0), int64(1)).
+			Return(&domain.AccrualCorrection{}, nil).Once()
+
+		_, err := svc.RecheckAccrual(ctx, number, 1)
+		require.NoError(t, err)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		svc, _, _ := newService(t, false)
+
+		_, err := svc.RecheckAccrual(ctx, number, 1)
+		assert.ErrorIs(t, err, domain.ErrAccrualCorrectionsDisabled)
+	})
+
+	t.Run("Order not processed by accrual system", func(t *testing.T) {
+		svc, _, client := newService(t, true)
+
+		client.EXPECT().GetOrderAccrual(mock.Anything, number).
+			Return(&domain.AccrualResponse{Order: number, Status: domain.AccrualStatusProcessing}, nil).Once()
+
+		_, err := svc.RecheckAccrual(ctx, number, 1)
+		assert.ErrorIs(t, err, domain.ErrAccrualUnchanged)
+	})
+
+	t.Run("Unchanged accrual", func(t *testing.T) {
+		svc, repo, client := newService(t, true)
+		raw := 100.0
+
+		client.EXPECT().GetOrderAccrual(mock.Anything, number).Return(processed(&raw), nil).Once()
+		repo.EXPECT().CorrectAccrual(mock.Anything, number, mock.Anything, int64(1)).Return(nil, domain.ErrAccrualUnchanged).Once()
+
+		_, err := svc.RecheckAccrual(ctx, number, 1)
+		assert.ErrorIs(t, err, domain.ErrAccrualUnchanged)
+	})
+
+	t.Run("Accrual system error", func(t *testing.T) {
+		svc, _, client := newService(t, true)
+
+		client.EXPECT().GetOrderAccrual(mock.Anything, number).Return(nil, errors.New("connection refused")).Once()
+
+		_, err := svc.RecheckAccrual(ctx, number, 1)
+		assert.Error(t, err)
+	})
+
+	t.Run("Repository error", func(t *testing.T) {
+		svc, repo, client := newService(t, true)
+		raw := 100.0
+
+		client.EXPECT().GetOrderAccrual(mock.Anything, number).Return(processed(&raw), nil).Once()
+		repo.EXPECT().CorrectAccrual(mock.Anything, number, mock.Anything, int64(1)).Return(nil, errors.New("boom")).Once()
+
+		_, err := svc.RecheckAccrual(ctx, number, 1)
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, domain.ErrAccrualUnchanged)
+	})
+}
@@ -0,0 +1,321 @@
+package service
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/avc/loyalty-system-diploma/internal/accrualpb"
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/avc/loyalty-system-diploma/internal/metrics"
+	"github.com/avc/loyalty-system-diploma/internal/tracing"
+)
+
+// defaultGRPCRetryAfter - пауза после RESOURCE_EXHAUSTED без google.rpc.RetryInfo
+const defaultGRPCRetryAfter = time.Minute
+
+// grpcServiceConfig повторяет недоступность сервера так же, как HTTP клиент повторяет 5xx
+const grpcServiceConfig = `{
+	"methodConfig": [{
+		"name": [{"service": "accrual.v2.AccrualService"}],
+		"retryPolicy": {
+			"maxAttempts": 5,
+			"initialBackoff": "1s",
+			"maxBackoff": "30s",
+			"backoffMultiplier": 2,
+			"retryableStatusCodes": ["UNAVAILABLE"]
+		}
+	}]
+}`
+
+// GRPCAccrualConfig содержит настройки клиента протокола v2 системы начислений
+type GRPCAccrualConfig struct {
+	Target string // Адрес host:port
+	TLS    bool   // Подключаться по TLS
+	CAFile string // PEM файл с дополнительными корневыми сертификатами
+}
+
+// GRPCAccrualClient реализует AccrualClient поверх протокола v2 (gRPC)
+type GRPCAccrualClient struct {
+	conn   *grpc.ClientConn
+	client accrualpb.AccrualServiceClient
+	logger *zap.Logger
+	orders singleflight.Group // Одновременные запросы по номеру заказа
+}
+
+// NewGRPCAccrualClient создает клиент протокола v2. Соединение устанавливается при первом запросе
+func NewGRPCAccrualClient(cfg GRPCAccrualConfig, m *metrics.Metrics, logger *zap.Logger) (*GRPCAccrualClient, error) {
+	creds := insecure.NewCredentials()
+	if cfg.TLS {
+		tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+		if cfg.CAFile != "" {
+			roots, err := loadCertPool(cfg.CAFile)
+			if err != nil {
+				return nil, err
+			}
+			tlsConfig.RootCAs = roots
+		}
+		creds = credentials.NewTLS(tlsConfig)
+	}
+
+	conn, err := grpc.NewClient(cfg.Target,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithDefaultServiceConfig(grpcServiceConfig),
+		grpc.WithChainUnaryInterceptor(grpcTracingInterceptor(), grpcMetricsInterceptor(m)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("accrual client: invalid gRPC target %q: %w", cfg.Target, err)
+	}
+
+	return &GRPCAccrualClient{
+		conn:   conn,
+		client: accrualpb.NewAccrualServiceClient(conn),
+		logger: logger,
+	}, nil
+}
+
+// grpcMetricsInterceptor учитывает вызовы в тех же метриках, что и HTTP запросы:
+// коды gRPC переводятся в метки HTTP протокола системы начислений
+func grpcMetricsInterceptor(m *metrics.Metrics) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		m.ObserveAccrualRequest(grpcStatusLabel(status.Code(err)), time.Since(start))
+		if retryAfter, ok := grpcRetryAfter(err); ok {
+			m.ObserveAccrualRetryAfter(retryAfter)
+		}
+		return err
+	}
+}
+
+// grpcTracingInterceptor начинает клиентский спан вызова и передает контекст трассы в метаданных
+func grpcTracingInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx, span := tracing.Tracer().Start(ctx, "accrual "+method,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(semconv.RPCSystemGRPC, semconv.RPCMethod(method)),
+		)
+		defer span.End()
+
+		md, _ := metadata.FromOutgoingContext(ctx)
+		md = md.Copy()
+		otel.GetTextMapPropagator().Inject(ctx, metadataCarrier(md))
+		ctx = metadata.NewOutgoingContext(ctx, md)
+
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		code := status.Code(err)
+		span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(int(code)))
+		if err != nil && code != codes.NotFound && code != codes.ResourceExhausted {
+			span.RecordError(err)
+			span.SetStatus(otelcodes.Error, err.Error())
+		}
+		return err
+	}
+}
+
+// metadataCarrier адаптирует метаданные gRPC к propagation.TextMapCarrier
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	if values := metadata.MD(c).Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// grpcStatusLabel сопоставляет код gRPC с меткой accrualStatusLabel
+func grpcStatusLabel(code codes.Code) string {
+	switch code {
+	case codes.OK:
+		return accrualStatusLabel(200)
+	case codes.NotFound:
+		return accrualStatusLabel(204)
+	case codes.ResourceExhausted:
+		return accrualStatusLabel(429)
+	case codes.InvalidArgument, codes.PermissionDenied, codes.Unauthenticated, codes.FailedPrecondition, codes.Unimplemented:
+		return "4xx"
+	case codes.Canceled, codes.DeadlineExceeded:
+		return "error"
+	default:
+		return "5xx"
+	}
+}
+
+// grpcRetryAfter возвращает паузу из google.rpc.RetryInfo ответа RESOURCE_EXHAUSTED
+func grpcRetryAfter(err error) (time.Duration, bool) {
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != codes.ResourceExhausted {
+		return 0, false
+	}
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok && info.GetRetryDelay() != nil {
+			return info.GetRetryDelay().AsDuration(), true
+		}
+	}
+	return defaultGRPCRetryAfter, true
+}
+
+// Ping проверяет, что соединение с системой начислений устанавливается
+func (c *GRPCAccrualClient) Ping(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, accrualPingTimeout)
+	defer cancel()
+
+	c.conn.Connect()
+	for {
+		state := c.conn.GetState()
+		if state == connectivity.Ready {
+			return nil
+		}
+		if !c.conn.WaitForStateChange(ctx, state) {
+			return fmt.Errorf("accrual client: ping failed: connection is %s: %w", state, ctx.Err())
+		}
+	}
+}
+
+// Close закрывает соединение
+func (c *GRPCAccrualClient) Close() error {
+	return c.conn.Close()
+}
+
+// GetOrderAccrual получает информацию о начислении для заказа.
+// Одновременные запросы по одному заказу выполняются одним вызовом
+func (c *GRPCAccrualClient) GetOrderAccrual(ctx context.Context, orderNumber string) (*domain.AccrualResponse, error) {
+	return coalesceAccrual(ctx, &c.orders, orderNumber, c.getOrderAccrual)
+}
+
+func (c *GRPCAccrualClient) getOrderAccrual(ctx context.Context, orderNumber string) (*domain.AccrualResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, accrualRequestTimeout)
+	defer cancel()
+
+	order, err := c.client.GetOrder(ctx, &accrualpb.GetOrderRequest{Number: orderNumber})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			// Заказ не зарегистрирован в системе расчета
+			return nil, nil
+		}
+		return nil, grpcAccrualError(err)
+	}
+
+	resp := accrualFromProto(order)
+	if err := validateAccrualResponse(orderNumber, resp, c.logger); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetOrderAccruals получает начисления по нескольким заказам одним потоком.
+// Незарегистрированных заказов в результате нет. Если сервер не поддерживает
+// потоковый запрос, заказы запрашиваются по одному.
+func (c *GRPCAccrualClient) GetOrderAccruals(ctx context.Context, orderNumbers []string) (map[string]*domain.AccrualResponse, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := c.client.StreamOrders(ctx, &accrualpb.StreamOrdersRequest{Numbers: orderNumbers})
+	if err != nil {
+		return nil, grpcAccrualError(err)
+	}
+
+	requested := make(map[string]bool, len(orderNumbers))
+	for _, number := range orderNumbers {
+		requested[number] = true
+	}
+
+	result := make(map[string]*domain.AccrualResponse, len(orderNumbers))
+	for {
+		order, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return result, nil
+		}
+		if status.Code(err) == codes.Unimplemented && len(result) == 0 {
+			return c.getOrderAccrualsOneByOne(ctx, orderNumbers)
+		}
+		if err != nil {
+			return nil, grpcAccrualError(err)
+		}
+
+		// Неожиданный или некорректный элемент не мешает остальным заказам
+		if !requested[order.GetNumber()] {
+			c.logger.Warn("accrual stream returned unrequested order", zap.String("order", order.GetNumber()))
+			continue
+		}
+		resp := accrualFromProto(order)
+		if err := validateAccrualResponse(order.GetNumber(), resp, c.logger); err != nil {
+			c.logger.Warn("invalid accrual in stream", zap.String("order", order.GetNumber()), zap.Error(err))
+			continue
+		}
+		result[resp.Order] = resp
+	}
+}
+
+func (c *GRPCAccrualClient) getOrderAccrualsOneByOne(ctx context.Context, orderNumbers []string) (map[string]*domain.AccrualResponse, error) {
+	result := make(map[string]*domain.AccrualResponse, len(orderNumbers))
+	for _, number := range orderNumbers {
+		resp, err := c.GetOrderAccrual(ctx, number)
+		if err != nil {
+			return nil, err
+		}
+		if resp != nil {
+			result[number] = resp
+		}
+	}
+	return result, nil
+}
+
+// grpcAccrualError переводит ошибку вызова в ошибки клиента: лимит запросов - в RateLimitError
+func grpcAccrualError(err error) error {
+	if retryAfter, ok := grpcRetryAfter(err); ok {
+		return NewRateLimitError(retryAfter)
+	}
+	return fmt.Errorf("accrual client: gRPC request failed: %w", err)
+}
+
+// accrualFromProto переводит ответ протокола v2 в ответ системы начислений.
+// Неизвестный статус остается пустым и отклоняется при проверке ответа
+func accrualFromProto(order *accrualpb.Order) *domain.AccrualResponse {
+	resp := &domain.AccrualResponse{Order: order.GetNumber()}
+	switch order.GetStatus() {
+	case accrualpb.OrderStatus_ORDER_STATUS_REGISTERED:
+		resp.Status = domain.AccrualStatusRegistered
+	case accrualpb.OrderStatus_ORDER_STATUS_INVALID:
+		resp.Status = domain.AccrualStatusInvalid
+	case accrualpb.OrderStatus_ORDER_STATUS_PROCESSING:
+		resp.Status = domain.AccrualStatusProcessing
+	case accrualpb.OrderStatus_ORDER_STATUS_PROCESSED:
+		resp.Status = domain.AccrualStatusProcessed
+	}
+	if order.Accrual != nil {
+		accrual := order.GetAccrual()
+		resp.Accrual = &accrual
+	}
+	return resp
+}
@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/avc/loyalty-system-diploma/internal/accrualpb"
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+)
+
+// fakeAccrualServer отвечает по заранее заданным заказам
+type fakeAccrualServer struct {
+	accrualpb.UnimplementedAccrualServiceServer
+	orders map[string]*accrualpb.Order
+	err    error
+}
+
+func (s *fakeAccrualServer) GetOrder(_ context.Context, req *accrualpb.GetOrderRequest) (*accrualpb.Order, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	order, ok := s.orders[req.GetNumber()]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "order is not registered")
+	}
+	return order, nil
+}
+
+// streamingAccrualServer дополнительно поддерживает потоковый запрос
+type streamingAccrualServer struct {
+	*fakeAccrualServer
+	extra []*accrualpb.Order
+}
+
+func (s *streamingAccrualServer) StreamOrders(req *accrualpb.StreamOrdersRequest, stream grpc.ServerStreamingServer[accrualpb.Order]) error {
+	for _, number := range req.GetNumbers() {
+		if order, ok := s.orders[number]; ok {
+			if err := stream.Send(order); err != nil {
+				return err
+			}
+		}
+	}
+	for _, order := range s.extra {
+		if err := stream.Send(order); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newGRPCTestClient(t *testing.T, srv accrualpb.AccrualServiceServer) *GRPCAccrualClient {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	accrualpb.RegisterAccrualServiceServer(server, srv)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	client, err := NewGRPCAccrualClient(GRPCAccrualConfig{Target: lis.Addr().String()}, nil, zap.NewNop())
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func rateLimitStatus(t *testing.T, delay time.Duration) error {
+	t.Helper()
+	st, err := status.New(codes.ResourceExhausted, "too many requests").
+		WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(delay)})
+	require.NoError(t, err)
+	return st.Err()
+}
+
+func TestGRPCAccrualClient_GetOrderAccrual(t *testing.T) {
+	ctx := context.Background()
+	orders := map[string]*accrualpb.Order{
+		"12345678903": {Number: "12345678903", Status: accrualpb.OrderStatus_ORDER_STATUS_PROCESSED, Accrual: proto.Float64(729.98)},
+		"2377225624":  {Number: "2377225624", Status: accrualpb.OrderStatus_ORDER_STATUS_PROCESSING},
+		"79927398713": {Number: "79927398713"},
+	}
+	client := newGRPCTestClient(t, &fakeAccrualServer{orders: orders})
+
+	t.Run("Processed", func(t *testing.T) {
+		resp, err := client.GetOrderAccrual(ctx, "12345678903")
+		require.NoError(t, err)
+		require.NotNil(t, resp.Accrual)
+		assert.Equal(t, domain.AccrualStatusProcessed, resp.Status)
+		assert.Equal(t, 729.98, *resp.Accrual)
+	})
+
+	t.Run("Processing", func(t *testing.T) {
+		resp, err := client.GetOrderAccrual(ctx, "2377225624")
+		require.NoError(t, err)
+		assert.Equal(t, &domain.AccrualResponse{Order: "2377225624", Status: domain.AccrualStatusProcessing}, resp)
+	})
+
+	t.Run("Not registered", func(t *testing.T) {
+		resp, err := client.GetOrderAccrual(ctx, "4561261212345467")
+		require.NoError(t, err)
+		assert.Nil(t, resp)
+	})
+
+	t.Run("Unknown status", func(t *testing.T) {
+		_, err := client.GetOrderAccrual(ctx, "79927398713")
+		assert.ErrorIs(t, err, domain.ErrInvalidAccrualResponse)
+	})
+}
+
+func TestGRPCAccrualClient_Errors(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Rate limit with retry info", func(t *testing.T) {
+		client := newGRPCTestClient(t, &fakeAccrualServer{err: rateLimitStatus(t, 5*time.Second)})
+
+		_, err := client.GetOrderAccrual(ctx, "12345678903")
+		var rateLimitErr *RateLimitError
+		require.ErrorAs(t, err, &rateLimitErr)
+		assert.Equal(t, 5*time.Second, rateLimitErr.RetryAfter)
+	})
+
+	t.Run("Rate limit without retry info", func(t *testing.T) {
+		client := newGRPCTestClient(t, &fakeAccrualServer{err: status.Error(codes.ResourceExhausted, "too many requests")})
+
+		_, err := client.GetOrderAccrual(ctx, "12345678903")
+		var rateLimitErr *RateLimitError
+		require.ErrorAs(t, err, &rateLimitErr)
+		assert.Equal(t, defaultGRPCRetryAfter, rateLimitErr.RetryAfter)
+	})
+
+	t.Run("Server error", func(t *testing.T) {
+		client := newGRPCTestClient(t, &fakeAccrualServer{err: status.Error(codes.Internal, "boom")})
+
+		_, err := client.GetOrderAccrual(ctx, "12345678903")
+		require.Error(t, err)
+		assert.Equal(t, codes.Internal, status.Code(err))
+	})
+}
+
+func TestGRPCAccrualClient_GetOrderAccruals(t *testing.T) {
+	ctx := context.Background()
+	orders := map[string]*accrualpb.Order{
+		"12345678903": {Number: "12345678903", Status: accrualpb.OrderStatus_ORDER_STATUS_PROCESSED, Accrual: proto.Float64(100)},
+		"2377225624":  {Number: "2377225624", Status: accrualpb.OrderStatus_ORDER_STATUS_REGISTERED},
+	}
+	numbers := []string{"12345678903", "2377225624", "4561261212345467"}
+
+	t.Run("Stream", func(t *testing.T) {
+		client := newGRPCTestClient(t, &streamingAccrualServer{
+			fakeAccrualServer: &fakeAccrualServer{orders: orders},
+			// Чужой заказ в потоке пропускается
+			extra: []*accrualpb.Order{{Number: "79927398713", Status: accrualpb.OrderStatus_ORDER_STATUS_PROCESSED}},
+		})
+
+		result, err := client.GetOrderAccruals(ctx, numbers)
+		require.NoError(t, err)
+		assert.Len(t, result, 2)
+		assert.Equal(t, domain.AccrualStatusProcessed, result["12345678903"].Status)
+		assert.Equal(t, domain.AccrualStatusRegistered, result["2377225624"].Status)
+	})
+
+	t.Run("Fallback to unary calls", func(t *testing.T) {
+		client := newGRPCTestClient(t, &fakeAccrualServer{orders: orders})
+
+		result, err := client.GetOrderAccruals(ctx, numbers)
+		require.NoError(t, err)
+		assert.Len(t, result, 2)
+		assert.Equal(t, 100.0, *result["12345678903"].Accrual)
+	})
+}
+
+func TestGRPCAccrualClient_Ping(t *testing.T) {
+	client := newGRPCTestClient(t, &fakeAccrualServer{})
+	assert.NoError(t, client.Ping(context.Background()))
+
+	// Порт, на котором никто не слушает
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := lis.Addr().String()
+	lis.Close()
+
+	unreachable, err := NewGRPCAccrualClient(GRPCAccrualConfig{Target: addr}, nil, zap.NewNop())
+	require.NoError(t, err)
+	defer unreachable.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	assert.Error(t, unreachable.Ping(ctx))
+}
+
+func TestGRPCStatusLabel(t *testing.T) {
+	assert.Equal(t, "200", grpcStatusLabel(codes.OK))
+	assert.Equal(t, "204", grpcStatusLabel(codes.NotFound))
+	assert.Equal(t, "429", grpcStatusLabel(codes.ResourceExhausted))
+	assert.Equal(t, "4xx", grpcStatusLabel(codes.InvalidArgument))
+	assert.Equal(t, "5xx", grpcStatusLabel(codes.Unavailable))
+	assert.Equal(t, "error", grpcStatusLabel(codes.DeadlineExceeded))
+}
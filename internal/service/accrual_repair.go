@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/avc/loyalty-system-diploma/internal/lock"
+	"github.com/avc/loyalty-system-diploma/internal/logctx"
+	"go.uber.org/zap"
+)
+
+// AccrualRepairRepository определяет поиск и исправление начислений,
+// примененных наполовину (заказ и журнал транзакций расходятся).
+type AccrualRepairRepository interface {
+	FindHalfAppliedAccruals(ctx context.Context, limit int) ([]*domain.AccrualRepair, error)
+	RepairAccrual(ctx context.Context, number string) (*domain.AccrualRepair, error)
+}
+
+// accrualRepairBatch ограничивает число заказов, проверяемых за один проход
+const accrualRepairBatch = 500
+
+// AccrualRepairService сводит статусы заказов с журналом транзакций после сбоев:
+// каждое исправление пишется в лог
+type AccrualRepairService struct {
+	repo   AccrualRepairRepository
+	locker Locker
+}
+
+// NewAccrualRepairService создает новый AccrualRepairService.
+// locker не дает двум репликам исправлять начисления пользователя одновременно, может быть nil
+func NewAccrualRepairService(repo AccrualRepairRepository, locker Locker) *AccrualRepairService {
+	return &AccrualRepairService{repo: repo, locker: locker}
+}
+
+// Sweep находит и исправляет все расхождения пачками и возвращает число исправленных заказов.
+// Ошибка исправления одного заказа пишется в лог и не останавливает проверку остальных
+func (s *AccrualRepairService) Sweep(ctx context.Context) (int, error) {
+	logger := logctx.From(ctx)
+
+	var total int
+	for {
+		candidates, err := s.repo.FindHalfAppliedAccruals(ctx, accrualRepairBatch)
+		if err != nil {
+			return total, fmt.Errorf("accrual repair service: failed to find half-applied accruals after %d repaired: %w", total, err)
+		}
+
+		var repaired int
+		for _, candidate := range candidates {
+			repair, err := s.repair(ctx, candidate)
+			if errors.Is(err, domain.ErrAccrualConsistent) || errors.Is(err, domain.ErrOrderNotFound) {
+				// Заказ успел обработать воркер
+				continue
+			}
+			if err != nil {
+				logger.Error("failed to repair half-applied accrual",
+					zap.String("order", candidate.OrderNumber),
+					zap.String("kind", string(candidate.Kind)),
+					zap.Error(err),
+				)
+				continue
+			}
+			logger.Warn("half-applied accrual repaired",
+				zap.String("order", repair.OrderNumber),
+				zap.Int64("user_id", repair.UserID),
+				zap.String("kind", string(repair.Kind)),
+				zap.Float64("amount", repair.Amount),
+			)
+			repaired++
+		}
+		total += repaired
+
+		// Неисправимые заказы остаются в выборке, поэтому следующая пачка
+		// запрашивается, только пока текущая что-то исправила
+		if len(candidates) < accrualRepairBatch || repaired == 0 {
+			break
+		}
+	}
+
+	if total > 0 {
+		logger.Info("half-applied accruals repaired", zap.Int("orders", total))
+	}
+	return total, nil
+}
+
+// repair исправляет один заказ под блокировками сверки и баланса его владельца
+func (s *AccrualRepairService) repair(ctx context.Context, candidate *domain.AccrualRepair) (*domain.AccrualRepair, error) {
+	unlock, err := lockKeys(ctx, s.locker, lock.ReconciliationKey, lock.UserKey(candidate.UserID))
+	if err != nil {
+		return nil, fmt.Errorf("accrual repair service: failed to lock order %q: %w", candidate.OrderNumber, err)
+	}
+	defer unlock()
+
+	return s.repo.RepairAccrual(ctx, candidate.OrderNumber)
+}
+
+// RunScheduled - задача расписания: исправляет расхождения
+func (s *AccrualRepairService) RunScheduled(ctx context.Context) error {
+	_, err := s.Sweep(ctx)
+	return err
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	domainmocks "github.com/avc/loyalty-system-diploma/internal/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccrualRepairService_Sweep(t *testing.T) {
+	ctx := context.Background()
+	missingTransaction := &domain.AccrualRepair{OrderNumber: "111", UserID: 1, Kind: domain.AccrualRepairMissingTransaction, Amount: 100}
+	missingStatus := &domain.AccrualRepair{OrderNumber: "222", UserID: 2, Kind: domain.AccrualRepairMissingStatus, Amount: 50}
+
+	t.Run("Repairs found accruals", func(t *testing.T) {
+		repo := domainmocks.NewAccrualRepairRepositoryMock(t)
+		repo.EXPECT().FindHalfAppliedAccruals(mock.Anything, accrualRepairBatch).
+			Return([]*domain.AccrualRepair{missingTransaction, missingStatus, {OrderNumber: "333"}, {OrderNumber: "444"}}, nil).Once()
+		repo.EXPECT().RepairAccrual(mock.Anything, "111").Return(missingTransaction, nil).Once()
+		repo.EXPECT().RepairAccrual(mock.Anything, "222").Return(missingStatus, nil).Once()
+		// Заказ успел обработать воркер
+		repo.EXPECT().RepairAccrual(mock.Anything, "333").Return(nil, domain.ErrAccrualConsistent).Once()
+		// Ошибка одного заказа не останавливает проверку
+		repo.EXPECT().RepairAccrual(mock.Anything, "444").Return(nil, errors.New("db error")).Once()
+
+		repaired, err := NewAccrualRepairService(repo, nil).Sweep(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 2, repaired)
+	})
+
+	t.Run("Under reconciliation and user locks", func(t *testing.T) {
+		repo := domainmocks.NewAccrualRepairRepositoryMock(t)
+		locker := domainmocks.NewLockerMock(t)
+		var locked []string
+		unlocked := 0
+		locker.EXPECT().Lock(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, key string) (func(), error) {
+			locked = append(locked, key)
+			return func() { unlocked++ }, nil
+		}).Times(2)
+		repo.EXPECT().FindHalfAppliedAccruals(mock.Anything, accrualRepairBatch).Return([]*domain.AccrualRepair{missingTransaction}, nil).Once()
+		repo.EXPECT().RepairAccrual(mock.Anything, "111").Return(missingTransaction, nil).Once()
+
+		repaired, err := NewAccrualRepairService(repo, locker).Sweep(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, repaired)
+		assert.Equal(t, []string{"reconciliation", "user:1"}, locked)
+		assert.Equal(t, 2, unlocked)
+	})
+
+	t.Run("Lock error skips the order", func(t *testing.T) {
+		repo := domainmocks.NewAccrualRepairRepositoryMock(t)
+		locker := domainmocks.NewLockerMock(t)
+		locker.EXPECT().Lock(mock.Anything, "reconciliation").Return(nil, context.DeadlineExceeded).Once()
+		repo.EXPECT().FindHalfAppliedAccruals(mock.Anything, accrualRepairBatch).Return([]*domain.AccrualRepair{missingTransaction}, nil).Once()
+
+		repaired, err := NewAccrualRepairService(repo, locker).Sweep(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, repaired)
+	})
+
+	t.Run("Continues with full batches", func(t *testing.T) {
+		repo := domainmocks.NewAccrualRepairRepositoryMock(t)
+		batch := make([]*domain.AccrualRepair, accrualRepairBatch)
+		for i := range batch {
+			batch[i] = missingTransaction
+		}
+		repo.EXPECT().FindHalfAppliedAccruals(mock.Anything, accrualRepairBatch).Return(batch, nil).Once()
+		repo.EXPECT().FindHalfAppliedAccruals(mock.Anything, accrualRepairBatch).Return(nil, nil).Once()
+		repo.EXPECT().RepairAccrual(mock.Anything, "111").Return(missingTransaction, nil).Times(accrualRepairBatch)
+
+		repaired, err := NewAccrualRepairService(repo, nil).Sweep(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, accrualRepairBatch, repaired)
+	})
+
+	t.Run("Stops when nothing is repaired", func(t *testing.T) {
+		repo := domainmocks.NewAccrualRepairRepositoryMock(t)
+		batch := make([]*domain.AccrualRepair, accrualRepairBatch)
+		for i := range batch {
+			batch[i] = missingTransaction
+		}
+		repo.EXPECT().FindHalfAppliedAccruals(mock.Anything, accrualRepairBatch).Return(batch, nil).Once()
+		repo.EXPECT().RepairAccrual(mock.Anything, "111").Return(nil, errors.New("db error")).Times(accrualRepairBatch)
+
+		repaired, err := NewAccrualRepairService(repo, nil).Sweep(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, repaired)
+	})
+
+	t.Run("Find error", func(t *testing.T) {
+		repo := domainmocks.NewAccrualRepairRepositoryMock(t)
+		repo.EXPECT().FindHalfAppliedAccruals(mock.Anything, accrualRepairBatch).Return(nil, errors.New("db error")).Once()
+
+		assert.Error(t, NewAccrualRepairService(repo, nil).RunScheduled(ctx))
+	})
+}
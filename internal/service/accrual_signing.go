@@ -0,0 +1,109 @@
+package service
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Заголовки подписи запросов к системе начислений
+const (
+	AccrualTimestampHeader = "X-Accrual-Timestamp"
+	AccrualSignatureHeader = "X-Accrual-Signature"
+)
+
+// AccrualSignature вычисляет HMAC-SHA256 подпись запроса в hex.
+// Подписываются метод, путь с параметрами, время в Unix секундах и SHA-256 тела
+func AccrualSignature(key []byte, method, requestURI string, timestamp int64, body []byte) string {
+	bodyHash := sha256.Sum256(body)
+	mac := hmac.New(sha256.New, key)
+	fmt.Fprintf(mac, "%s\n%s\n%d\n%s", method, requestURI, timestamp, hex.EncodeToString(bodyHash[:]))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// signingTransport подписывает каждую попытку запроса, поэтому повторы
+// отправляются со свежим временем
+type signingTransport struct {
+	next      http.RoundTripper
+	key       []byte
+	clockSkew time.Duration
+	now       func() time.Time
+	logger    *zap.Logger
+
+	// Поправка к локальным часам по заголовку Date системы начислений, в наносекундах.
+	// Ненулевая, только пока расхождение больше clockSkew
+	offset atomic.Int64
+}
+
+func newSigningTransport(next http.RoundTripper, key []byte, clockSkew time.Duration, logger *zap.Logger) *signingTransport {
+	return &signingTransport{
+		next:      next,
+		key:       key,
+		clockSkew: clockSkew,
+		now:       time.Now,
+		logger:    logger,
+	}
+}
+
+func (t *signingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("accrual client: failed to read request body for signing: %w", err)
+		}
+	}
+
+	// RoundTripper не должен менять исходный запрос
+	signed := req.Clone(req.Context())
+	if body != nil {
+		signed.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	timestamp := t.now().Add(time.Duration(t.offset.Load())).Unix()
+	signed.Header.Set(AccrualTimestampHeader, strconv.FormatInt(timestamp, 10))
+	signed.Header.Set(AccrualSignatureHeader, AccrualSignature(t.key, signed.Method, signed.URL.RequestURI(), timestamp, body))
+
+	resp, err := t.next.RoundTrip(signed)
+	if err == nil {
+		t.observeServerTime(resp)
+	}
+	return resp, err
+}
+
+// observeServerTime сравнивает локальные часы со временем системы начислений.
+// Если расхождение больше допустимого, время подписи сдвигается на него,
+// иначе запросы отклонялись бы как просроченные
+func (t *signingTransport) observeServerTime(resp *http.Response) {
+	serverTime, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return
+	}
+
+	skew := serverTime.Sub(t.now())
+	if skew.Abs() <= t.clockSkew {
+		if t.offset.Swap(0) != 0 {
+			t.logger.Info("clock skew with accrual system is back within tolerance")
+		}
+		return
+	}
+
+	// Date имеет точность в секунду, поэтому поправка меньше секунды не пишется в лог повторно
+	if previous := time.Duration(t.offset.Swap(int64(skew))); (skew - previous).Abs() >= time.Second {
+		t.logger.Warn("clock skew with accrual system exceeds tolerance, correcting signature timestamps",
+			zap.Duration("skew", skew),
+			zap.Duration("tolerance", t.clockSkew),
+		)
+	}
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestAccrualClient_SignsRequests(t *testing.T) {
+	key := "signing-key"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		timestamp, err := strconv.ParseInt(r.Header.Get(AccrualTimestampHeader), 10, 64)
+		require.NoError(t, err)
+		assert.InDelta(t, time.Now().Unix(), timestamp, 5)
+		assert.Equal(t, AccrualSignature([]byte(key), http.MethodGet, "/api/orders/12345678903", timestamp, nil),
+			r.Header.Get(AccrualSignatureHeader))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	transport, err := NewAccrualTransport(AccrualTransportConfig{SigningKey: key, SigningClockSkew: time.Minute}, zap.NewNop())
+	require.NoError(t, err)
+
+	_, err = NewAccrualClient(server.URL, transport, nil, zap.NewNop()).GetOrderAccrual(context.Background(), "12345678903")
+	require.NoError(t, err)
+}
+
+func TestAccrualSignature(t *testing.T) {
+	key := []byte("key")
+	base := AccrualSignature(key, http.MethodGet, "/api/orders/1", 1700000000, nil)
+
+	assert.Len(t, base, 64)
+	assert.Equal(t, base, AccrualSignature(key, http.MethodGet, "/api/orders/1", 1700000000, []byte{}))
+	assert.NotEqual(t, base, AccrualSignature([]byte("other"), http.MethodGet, "/api/orders/1", 1700000000, nil))
+	assert.NotEqual(t, base, AccrualSignature(key, http.MethodPost, "/api/orders/1", 1700000000, nil))
+	assert.NotEqual(t, base, AccrualSignature(key, http.MethodGet, "/api/orders/2", 1700000000, nil))
+	assert.NotEqual(t, base, AccrualSignature(key, http.MethodGet, "/api/orders/1", 1700000001, nil))
+	assert.NotEqual(t, base, AccrualSignature(key, http.MethodGet, "/api/orders/1", 1700000000, []byte("{}")))
+}
+
+func TestSigningTransport_Body(t *testing.T) {
+	key := []byte("key")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.Equal(t, `{"order":"1"}`, string(body))
+
+		timestamp, err := strconv.ParseInt(r.Header.Get(AccrualTimestampHeader), 10, 64)
+		require.NoError(t, err)
+		assert.Equal(t, AccrualSignature(key, http.MethodPost, "/api/goods?dry_run=1", timestamp, body),
+			r.Header.Get(AccrualSignatureHeader))
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: newSigningTransport(http.DefaultTransport, key, time.Minute, zap.NewNop())}
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/api/goods?dry_run=1", strings.NewReader(`{"order":"1"}`))
+	require.NoError(t, err)
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Empty(t, req.Header.Get(AccrualSignatureHeader))
+}
+
+func TestSigningTransport_ClockSkew(t *testing.T) {
+	local := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	serverTime := local.Add(2 * time.Minute)
+
+	var timestamps []int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		timestamp, _ := strconv.ParseInt(r.Header.Get(AccrualTimestampHeader), 10, 64)
+		timestamps = append(timestamps, timestamp)
+		w.Header().Set("Date", serverTime.Format(http.TimeFormat))
+	}))
+	defer server.Close()
+
+	transport := newSigningTransport(http.DefaultTransport, []byte("key"), 30*time.Second, zap.NewNop())
+	transport.now = func() time.Time { return local }
+	client := &http.Client{Transport: transport}
+
+	send := func() {
+		resp, err := client.Get(server.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	// Первый запрос подписан по локальным часам, следующий - с поправкой на расхождение
+	send()
+	send()
+	// Расхождение в пределах допуска - поправка снимается
+	serverTime = local.Add(10 * time.Second)
+	send()
+	send()
+
+	shifted := local.Add(2 * time.Minute).Unix()
+	assert.Equal(t, []int64{local.Unix(), shifted, shifted, local.Unix()}, timestamps)
+}
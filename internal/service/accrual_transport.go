@@ -0,0 +1,82 @@
+package service
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/hashicorp/go-cleanhttp"
+	"go.uber.org/zap"
+)
+
+// AccrualTransportConfig содержит сетевые настройки клиента системы начислений
+type AccrualTransportConfig struct {
+	// Прокси для всех запросов. Пусто - из HTTP_PROXY, HTTPS_PROXY и NO_PROXY
+	ProxyURL string
+	// PEM файл с дополнительными корневыми сертификатами, например CA прокси,
+	// который перешифровывает TLS. Добавляются к системным
+	CAFile string
+	// Ключ HMAC подписи запросов. Пусто - запросы не подписываются
+	SigningKey string
+	// Допустимое расхождение часов с системой начислений, после которого
+	// время подписи сдвигается по заголовку Date ее ответов
+	SigningClockSkew time.Duration
+}
+
+// NewAccrualTransport создает транспорт клиента системы начислений с пулом соединений
+func NewAccrualTransport(cfg AccrualTransportConfig, logger *zap.Logger) (http.RoundTripper, error) {
+	transport := cleanhttp.DefaultPooledTransport()
+
+	if cfg.ProxyURL != "" {
+		proxyURL, err := url.Parse(cfg.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("accrual client: invalid proxy URL: %w", err)
+		}
+		switch proxyURL.Scheme {
+		case "http", "https", "socks5":
+		default:
+			return nil, fmt.Errorf("accrual client: unsupported proxy scheme %q", proxyURL.Scheme)
+		}
+		if proxyURL.Host == "" {
+			return nil, fmt.Errorf("accrual client: proxy URL %q has no host", proxyURL.Redacted())
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if cfg.CAFile != "" {
+		roots, err := loadCertPool(cfg.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = &tls.Config{
+			RootCAs:    roots,
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+
+	if cfg.SigningKey != "" {
+		return newSigningTransport(transport, []byte(cfg.SigningKey), cfg.SigningClockSkew, logger), nil
+	}
+	return transport, nil
+}
+
+// loadCertPool добавляет сертификаты из PEM файла к системным корневым сертификатам
+func loadCertPool(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("accrual client: failed to read CA bundle: %w", err)
+	}
+
+	roots, err := x509.SystemCertPool()
+	if err != nil {
+		roots = x509.NewCertPool()
+	}
+	if !roots.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("accrual client: no certificates found in CA bundle %s", path)
+	}
+	return roots, nil
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestNewAccrualTransport_Proxy(t *testing.T) {
+	// Прокси получает запрос с абсолютным адресом системы начислений
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer proxy.Close()
+
+	transport, err := NewAccrualTransport(AccrualTransportConfig{ProxyURL: proxy.URL}, zap.NewNop())
+	require.NoError(t, err)
+
+	client := NewAccrualClient("http://accrual.internal:8080", transport, nil, zap.NewNop())
+	result, err := client.GetOrderAccrual(context.Background(), "12345678903")
+	require.NoError(t, err)
+	assert.Nil(t, result)
+	assert.Equal(t, "http://accrual.internal:8080/api/orders/12345678903", proxied)
+}
+
+func TestNewAccrualTransport_CAFile(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	// Без сертификата сервера запрос отклоняется
+	transport, err := NewAccrualTransport(AccrualTransportConfig{}, zap.NewNop())
+	require.NoError(t, err)
+	assert.Error(t, NewAccrualClient(server.URL, transport, nil, zap.NewNop()).Ping(context.Background()))
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.NoError(t, os.WriteFile(caFile, certPEM, 0o600))
+
+	transport, err = NewAccrualTransport(AccrualTransportConfig{CAFile: caFile}, zap.NewNop())
+	require.NoError(t, err)
+	assert.NoError(t, NewAccrualClient(server.URL, transport, nil, zap.NewNop()).Ping(context.Background()))
+}
+
+func TestNewAccrualTransport_Invalid(t *testing.T) {
+	emptyFile := filepath.Join(t.TempDir(), "empty.pem")
+	require.NoError(t, os.WriteFile(emptyFile, []byte("not a certificate"), 0o600))
+
+	tests := []struct {
+		name string
+		cfg  AccrualTransportConfig
+	}{
+		{"unsupported scheme", AccrualTransportConfig{ProxyURL: "ftp://proxy:21"}},
+		{"no host", AccrualTransportConfig{ProxyURL: "http://"}},
+		{"malformed proxy", AccrualTransportConfig{ProxyURL: "http://proxy:port"}},
+		{"missing CA file", AccrualTransportConfig{CAFile: filepath.Join(t.TempDir(), "missing.pem")}},
+		{"CA file without certificates", AccrualTransportConfig{CAFile: emptyFile}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewAccrualTransport(tt.cfg, zap.NewNop())
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/avc/loyalty-system-diploma/internal/jobs"
+	"github.com/avc/loyalty-system-diploma/internal/lock"
+	"github.com/avc/loyalty-system-diploma/internal/logctx"
+	"github.com/avc/loyalty-system-diploma/internal/utils/password"
+	"go.uber.org/zap"
+)
+
+// UserAdminRepository определяет методы для массовых операций с пользователями.
+type UserAdminRepository interface {
+	GetUserByLogin(ctx context.Context, login string) (*domain.User, error)
+	CreateUser(ctx context.Context, login, passwordHash string) (*domain.User, error)
+	CountUsers(ctx context.Context) (int, error)
+	ListUserBalances(ctx context.Context, afterID int64, limit int) ([]*domain.UserBalance, error)
+	MergeUsers(ctx context.Context, sourceLogin, targetLogin string, mergedBy int64, dryRun bool) (*domain.UserMerge, error)
+}
+
+const (
+	// temporaryPasswordLength - длина временного пароля импортированного пользователя
+	temporaryPasswordLength = 16
+	// exportBatchSize - количество пользователей, читаемых за один запрос при выгрузке
+	exportBatchSize = 500
+)
+
+// UserAdminService предоставляет массовый импорт и выгрузку пользователей
+// для переноса данных из других систем лояльности и объединение дубликатов.
+type UserAdminService struct {
+	userRepo       UserAdminRepository
+	passwordHasher password.Hasher
+	locker         Locker
+}
+
+// NewUserAdminService создает новый UserAdminService.
+// locker защищает балансы объединяемых пользователей от параллельных списаний, может быть nil.
+func NewUserAdminService(userRepo UserAdminRepository, passwordHasher password.Hasher, locker Locker) *UserAdminService {
+	return &UserAdminService{
+		userRepo:       userRepo,
+		passwordHasher: passwordHasher,
+		locker:         locker,
+	}
+}
+
+// ImportUsers создает пользователей с временными паролями.
+// Ошибка по одному логину не прерывает импорт, а попадает в его результат;
+// импорт прерывается только отменой контекста.
+func (s *UserAdminService) ImportUsers(ctx context.Context, logins []string, progress jobs.Progress) ([]domain.ImportedUser, error) {
+	progress.SetTotal(len(logins))
+
+	results := make([]domain.ImportedUser, 0, len(logins))
+	for _, login := range logins {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("user admin service: import interrupted after %d users: %w", len(results), err)
+		}
+
+		result := s.importUser(ctx, login)
+		results = append(results, result)
+		progress.Advance(result.Status == domain.ImportStatusCreated || result.Status == domain.ImportStatusExists)
+	}
+
+	return results, nil
+}
+
+func (s *UserAdminService) importUser(ctx context.Context, login string) domain.ImportedUser {
+	result := domain.ImportedUser{Login: login}
+
+	if login == "" || len(login) > domain.MaxLoginLength {
+		result.Status = domain.ImportStatusInvalid
+		return result
+	}
+
+	temporaryPassword, err := password.Generate(temporaryPasswordLength)
+	if err != nil {
+		logctx.From(ctx).Error("user admin service: failed to generate password", zap.String("login", login), zap.Error(err))
+		result.Status = domain.ImportStatusFailed
+		return result
+	}
+
+	hash, err := s.passwordHasher.Hash(temporaryPassword)
+	if err != nil {
+		logctx.From(ctx).Error("user admin service: failed to hash password", zap.String("login", login), zap.Error(err))
+		result.Status = domain.ImportStatusFailed
+		return result
+	}
+
+	if _, err := s.userRepo.CreateUser(ctx, login, hash); err != nil {
+		if errors.Is(err, domain.ErrUserExists) {
+			result.Status = domain.ImportStatusExists
+			return result
+		}
+		logctx.From(ctx).Error("user admin service: failed to create user", zap.String("login", login), zap.Error(err))
+		result.Status = domain.ImportStatusFailed
+		return result
+	}
+
+	result.Status = domain.ImportStatusCreated
+	result.TemporaryPassword = temporaryPassword
+	return result
+}
+
+// ExportUsers выгружает всех пользователей с балансами пачками по exportBatchSize
+func (s *UserAdminService) ExportUsers(ctx context.Context, progress jobs.Progress) ([]*domain.UserBalance, error) {
+	total, err := s.userRepo.CountUsers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("user admin service: failed to count users: %w", err)
+	}
+	progress.SetTotal(total)
+
+	balances := make([]*domain.UserBalance, 0, total)
+	var afterID int64
+	for {
+		batch, err := s.userRepo.ListUserBalances(ctx, afterID, exportBatchSize)
+		if err != nil {
+			return nil, fmt.Errorf("user admin service: failed to export users after id %d: %w", afterID, err)
+		}
+
+		for _, b := range batch {
+			balances = append(balances, b)
+			progress.Advance(true)
+		}
+
+		if len(batch) < exportBatchSize {
+			return balances, nil
+		}
+		afterID = batch[len(batch)-1].UserID
+	}
+}
+
+// MergeUsers объединяет учетную запись-дубликат sourceLogin с основной targetLogin
+// от имени администратора adminID. При dryRun только показывает, что будет перенесено.
+func (s *UserAdminService) MergeUsers(ctx context.Context, sourceLogin, targetLogin string, adminID int64, dryRun bool) (*domain.UserMerge, error) {
+	if sourceLogin == "" || targetLogin == "" {
+		return nil, fmt.Errorf("user admin service: source and target logins are required: %w", domain.ErrInvalidInput)
+	}
+	if sourceLogin == targetLogin {
+		return nil, fmt.Errorf("user admin service: cannot merge user %q into itself: %w", sourceLogin, domain.ErrInvalidInput)
+	}
+
+	if !dryRun {
+		unlock, err := s.lockMergedUsers(ctx, sourceLogin, targetLogin)
+		if err != nil {
+			return nil, err
+		}
+		defer unlock()
+	}
+
+	merge, err := s.userRepo.MergeUsers(ctx, sourceLogin, targetLogin, adminID, dryRun)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) || errors.Is(err, domain.ErrUserMerged) {
+			return nil, fmt.Errorf("user admin service: cannot merge %q into %q: %w", sourceLogin, targetLogin, err)
+		}
+		logctx.From(ctx).Error("user admin service: failed to merge users",
+			zap.String("source", sourceLogin), zap.String("target", targetLogin), zap.Error(err))
+		return nil, fmt.Errorf("user admin service: failed to merge %q into %q: %w", sourceLogin, targetLogin, err)
+	}
+
+	if !dryRun {
+		logctx.From(ctx).Info("users merged",
+			zap.Int64("source_user_id", merge.SourceUserID),
+			zap.Int64("target_user_id", merge.TargetUserID),
+			zap.Int("orders", merge.Orders),
+			zap.Int("transactions", merge.Transactions),
+			zap.Float64("balance", merge.Balance),
+			zap.Int64("merged_by", adminID),
+		)
+	}
+	return merge, nil
+}
+
+// lockMergedUsers блокирует балансы обоих пользователей в порядке возрастания id,
+// чтобы встречные объединения не ждали друг друга бесконечно
+func (s *UserAdminService) lockMergedUsers(ctx context.Context, sourceLogin, targetLogin string) (func(), error) {
+	if s.locker == nil {
+		return func() {}, nil
+	}
+
+	ids := make([]int64, 0, 2)
+	for _, login := range []string{sourceLogin, targetLogin} {
+		user, err := s.userRepo.GetUserByLogin(ctx, login)
+		if err != nil {
+			if errors.Is(err, domain.ErrUserNotFound) {
+				return nil, fmt.Errorf("user admin service: cannot merge %q into %q: %w", sourceLogin, targetLogin, err)
+			}
+			return nil, fmt.Errorf("user admin service: failed to get user %q: %w", login, err)
+		}
+		ids = append(ids, user.ID)
+	}
+	slices.Sort(ids)
+
+	unlock, err := lockKeys(ctx, s.locker, lock.UserKey(ids[0]), lock.UserKey(ids[1]))
+	if err != nil {
+		return nil, fmt.Errorf("user admin service: failed to lock users %q and %q: %w", sourceLogin, targetLogin, err)
+	}
+	return unlock, nil
+}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	domainmocks "github.com/avc/loyalty-system-diploma/internal/domain/mocks"
+	passwordmocks "github.com/avc/loyalty-system-diploma/internal/utils/password/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingProgress запоминает прогресс задачи
+type recordingProgress struct {
+	total, processed, failed int
+}
+
+func (p *recordingProgress) SetTotal(total int) {
+	p.total = total
+}
+
+func (p *recordingProgress) Advance(ok bool) {
+	p.processed++
+	if !ok {
+		p.failed++
+	}
+}
+
+func TestUserAdminService_ImportUsers(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := domainmocks.NewUserAdminRepositoryMock(t)
+	mockHasher := passwordmocks.NewHasherMock(t)
+	svc := NewUserAdminService(mockRepo, mockHasher, nil)
+
+	mockHasher.EXPECT().Hash(mock.Anything).Return("hash", nil).Times(3)
+	mockRepo.EXPECT().CreateUser(mock.Anything, "alice", "hash").Return(&domain.User{ID: 1, Login: "alice"}, nil).Once()
+	mockRepo.EXPECT().CreateUser(mock.Anything, "bob", "hash").Return(nil, domain.ErrUserExists).Once()
+	mockRepo.EXPECT().CreateUser(mock.Anything, "carol", "hash").Return(nil, errors.New("db error")).Once()
+
+	progress := &recordingProgress{}
+	results, err := svc.ImportUsers(ctx, []string{"alice", "bob", "", "carol"}, progress)
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+
+	assert.Equal(t, domain.ImportStatusCreated, results[0].Status)
+	assert.Len(t, results[0].TemporaryPassword, temporaryPasswordLength)
+	assert.Equal(t, domain.ImportStatusExists, results[1].Status)
+	assert.Empty(t, results[1].TemporaryPassword)
+	assert.Equal(t, domain.ImportStatusInvalid, results[2].Status)
+	assert.Equal(t, domain.ImportStatusFailed, results[3].Status)
+
+	assert.Equal(t, 4, progress.total)
+	assert.Equal(t, 4, progress.processed)
+	assert.Equal(t, 2, progress.failed)
+}
+
+func TestUserAdminService_ImportUsers_Canceled(t *testing.T) {
+	svc := NewUserAdminService(domainmocks.NewUserAdminRepositoryMock(t), passwordmocks.NewHasherMock(t), nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := svc.ImportUsers(ctx, []string{"alice"}, &recordingProgress{})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestUserAdminService_ExportUsers(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Multiple batches", func(t *testing.T) {
+		mockRepo := domainmocks.NewUserAdminRepositoryMock(t)
+		svc := NewUserAdminService(mockRepo, nil, nil)
+
+		firstBatch := make([]*domain.UserBalance, exportBatchSize)
+		for i := range firstBatch {
+			firstBatch[i] = &domain.UserBalance{UserID: int64(i + 1)}
+		}
+		secondBatch := []*domain.UserBalance{{UserID: exportBatchSize + 1}}
+
+		mockRepo.EXPECT().CountUsers(mock.Anything).Return(exportBatchSize+1, nil).Once()
+		mockRepo.EXPECT().ListUserBalances(mock.Anything, int64(0), exportBatchSize).Return(firstBatch, nil).Once()
+		mockRepo.EXPECT().ListUserBalances(mock.Anything, int64(exportBatchSize), exportBatchSize).Return(secondBatch, nil).Once()
+
+		progress := &recordingProgress{}
+		balances, err := svc.ExportUsers(ctx, progress)
+		require.NoError(t, err)
+		assert.Len(t, balances, exportBatchSize+1)
+		assert.Equal(t, exportBatchSize+1, progress.total)
+		assert.Equal(t, exportBatchSize+1, progress.processed)
+	})
+
+	t.Run("Database error", func(t *testing.T) {
+		mockRepo := domainmocks.NewUserAdminRepositoryMock(t)
+		svc := NewUserAdminService(mockRepo, nil, nil)
+
+		mockRepo.EXPECT().CountUsers(mock.Anything).Return(10, nil).Once()
+		mockRepo.EXPECT().ListUserBalances(mock.Anything, int64(0), exportBatchSize).Return(nil, errors.New("db error")).Once()
+
+		_, err := svc.ExportUsers(ctx, &recordingProgress{})
+		assert.Error(t, err)
+	})
+}
+
+func TestUserAdminService_MergeUsers(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := domainmocks.NewUserAdminRepositoryMock(t)
+		svc := NewUserAdminService(mockRepo, passwordmocks.NewHasherMock(t), nil)
+
+		merge := &domain.UserMerge{SourceUserID: 8, SourceLogin: "alice2", TargetUserID: 3, TargetLogin: "alice", Orders: 2}
+		mockRepo.EXPECT().MergeUsers(mock.Anything, "alice2", "alice", int64(1), false).Return(merge, nil).Once()
+
+		result, err := svc.MergeUsers(ctx, "alice2", "alice", 1, false)
+		require.NoError(t, err)
+		assert.Equal(t, merge, result)
+	})
+
+	t.Run("Same login", func(t *testing.T) {
+		svc := NewUserAdminService(domainmocks.NewUserAdminRepositoryMock(t), passwordmocks.NewHasherMock(t), nil)
+
+		_, err := svc.MergeUsers(ctx, "alice", "alice", 1, true)
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	})
+
+	t.Run("Empty login", func(t *testing.T) {
+		svc := NewUserAdminService(domainmocks.NewUserAdminRepositoryMock(t), passwordmocks.NewHasherMock(t), nil)
+
+		_, err := svc.MergeUsers(ctx, "", "alice", 1, true)
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	})
+
+	t.Run("Already merged", func(t *testing.T) {
+		mockRepo := domainmocks.NewUserAdminRepositoryMock(t)
+		svc := NewUserAdminService(mockRepo, passwordmocks.NewHasherMock(t), nil)
+		mockRepo.EXPECT().MergeUsers(mock.Anything, "alice2", "alice", int64(1), false).Return(nil, domain.ErrUserMerged).Once()
+
+		_, err := svc.MergeUsers(ctx, "alice2", "alice", 1, false)
+		assert.ErrorIs(t, err, domain.ErrUserMerged)
+	})
+
+	t.Run("Repository error", func(t *testing.T) {
+		mockRepo := domainmocks.NewUserAdminRepositoryMock(t)
+		svc := NewUserAdminService(mockRepo, passwordmocks.NewHasherMock(t), nil)
+		mockRepo.EXPECT().MergeUsers(mock.Anything, "alice2", "alice", int64(1), false).Return(nil, errors.New("boom")).Once()
+
+		_, err := svc.MergeUsers(ctx, "alice2", "alice", 1, false)
+		assert.Error(t, err)
+	})
+}
+
+func TestUserAdminService_MergeUsers_Lock(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Users locked in id order", func(t *testing.T) {
+		mockRepo := domainmocks.NewUserAdminRepositoryMock(t)
+		locker := domainmocks.NewLockerMock(t)
+		svc := NewUserAdminService(mockRepo, passwordmocks.NewHasherMock(t), locker)
+
+		var events []string
+		mockRepo.EXPECT().GetUserByLogin(mock.Anything, "alice2").Return(&domain.User{ID: 8, Login: "alice2"}, nil).Once()
+		mockRepo.EXPECT().GetUserByLogin(mock.Anything, "alice").Return(&domain.User{ID: 3, Login: "alice"}, nil).Once()
+		locker.EXPECT().Lock(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, key string) (func(), error) {
+			events = append(events, "lock "+key)
+			return func() { events = append(events, "unlock "+key) }, nil
+		}).Twice()
+		mockRepo.EXPECT().MergeUsers(mock.Anything, "alice2", "alice", int64(1), false).
+			RunAndReturn(func(context.Context, string, string, int64, bool) (*domain.UserMerge, error) {
+				events = append(events, "merge")
+				return &domain.UserMerge{SourceUserID: 8, TargetUserID: 3}, nil
+			}).Once()
+
+		_, err := svc.MergeUsers(ctx, "alice2", "alice", 1, false)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"lock user:3", "lock user:8", "merge", "unlock user:8", "unlock user:3"}, events)
+	})
+
+	t.Run("Dry run without lock", func(t *testing.T) {
+		mockRepo := domainmocks.NewUserAdminRepositoryMock(t)
+		svc := NewUserAdminService(mockRepo, passwordmocks.NewHasherMock(t), domainmocks.NewLockerMock(t))
+		mockRepo.EXPECT().MergeUsers(mock.Anything, "alice2", "alice", int64(1), true).Return(&domain.UserMerge{}, nil).Once()
+
+		_, err := svc.MergeUsers(ctx, "alice2", "alice", 1, true)
+		require.NoError(t, err)
+	})
+
+	t.Run("Unknown user", func(t *testing.T) {
+		mockRepo := domainmocks.NewUserAdminRepositoryMock(t)
+		svc := NewUserAdminService(mockRepo, passwordmocks.NewHasherMock(t), domainmocks.NewLockerMock(t))
+		mockRepo.EXPECT().GetUserByLogin(mock.Anything, "alice2").Return(nil, domain.ErrUserNotFound).Once()
+
+		_, err := svc.MergeUsers(ctx, "alice2", "alice", 1, false)
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	})
+
+	t.Run("Second lock fails", func(t *testing.T) {
+		mockRepo := domainmocks.NewUserAdminRepositoryMock(t)
+		locker := domainmocks.NewLockerMock(t)
+		svc := NewUserAdminService(mockRepo, passwordmocks.NewHasherMock(t), locker)
+
+		unlocked := false
+		mockRepo.EXPECT().GetUserByLogin(mock.Anything, "alice2").Return(&domain.User{ID: 8}, nil).Once()
+		mockRepo.EXPECT().GetUserByLogin(mock.Anything, "alice").Return(&domain.User{ID: 3}, nil).Once()
+		locker.EXPECT().Lock(mock.Anything, "user:3").Return(func() { unlocked = true }, nil).Once()
+		locker.EXPECT().Lock(mock.Anything, "user:8").Return(nil, errors.New("redis down")).Once()
+
+		_, err := svc.MergeUsers(ctx, "alice2", "alice", 1, false)
+		assert.Error(t, err)
+		assert.True(t, unlocked)
+	})
+}
@@ -2,13 +2,19 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/avc/loyalty-system-diploma/internal/domain"
-	"github.com/avc/loyalty-system-diploma/internal/repository/postgres"
+	"github.com/avc/loyalty-system-diploma/internal/logctx"
+	"github.com/avc/loyalty-system-diploma/internal/tracing"
 	"github.com/avc/loyalty-system-diploma/internal/utils/jwt"
 	"github.com/avc/loyalty-system-diploma/internal/utils/password"
+	"go.uber.org/zap"
 )
 
 // UserRepository определяет методы для работы с пользователями.
@@ -16,13 +22,46 @@ type UserRepository interface {
 	CreateUser(ctx context.Context, login, passwordHash string) (*domain.User, error)
 	GetUserByLogin(ctx context.Context, login string) (*domain.User, error)
 	GetUserByID(ctx context.Context, id int64) (*domain.User, error)
+	GetOrCreateExternalUser(ctx context.Context, identity domain.ExternalIdentity) (*domain.User, error)
+	LinkExternalIdentity(ctx context.Context, userID int64, identity domain.ExternalIdentity) error
+}
+
+// ExternalTokenVerifier проверяет токены внешнего провайдера удостоверений (SSO/OIDC).
+type ExternalTokenVerifier interface {
+	Accepts(token string) bool
+	Verify(ctx context.Context, token string) (*domain.ExternalIdentity, error)
+}
+
+// RefreshTokenRepository определяет хранение выданных refresh-токенов для их отзыва.
+type RefreshTokenRepository interface {
+	CreateRefreshToken(ctx context.Context, userID int64, id, device string, ttl time.Duration) error
+	RotateRefreshToken(ctx context.Context, userID int64, id, nextID, device string, ttl time.Duration) error
+	RevokeRefreshTokens(ctx context.Context, userID int64) error
+	DeleteExpiredRefreshTokens(ctx context.Context, limit int) (int, error)
+}
+
+// RevokedTokenRepository определяет черный список токенов доступа, отозванных при выходе.
+type RevokedTokenRepository interface {
+	RevokeToken(ctx context.Context, userID int64, tokenHash string, ttl time.Duration) error
+	IsTokenRevoked(ctx context.Context, tokenHash string) (bool, error)
 }
 
 // AuthServiceConfig содержит конфигурацию AuthService
 type AuthServiceConfig struct {
 	MinPasswordLength int
+	AdminLogins       []string      // Логины пользователей с доступом к /api/admin
+	RememberTTL       time.Duration // Время жизни токена при входе с remember, не меньше обычного
+	ImpersonationTTL  time.Duration // Время жизни токена администратора от имени пользователя
+	RefreshTTL        time.Duration // Время жизни refresh-токена (0 - refresh-токены не выдаются)
 }
 
+const (
+	// defaultImpersonationTTL - время жизни токена от имени пользователя, если не задано
+	defaultImpersonationTTL = 15 * time.Minute
+	// refreshCleanupBatch - истекшие refresh-токены, удаляемые одним запросом
+	refreshCleanupBatch = 1000
+)
+
 // DefaultAuthServiceConfig возвращает конфигурацию по умолчанию
 func DefaultAuthServiceConfig() AuthServiceConfig {
 	return AuthServiceConfig{
@@ -35,89 +74,404 @@ type AuthService struct {
 	userRepo          UserRepository
 	passwordHasher    password.Hasher
 	jwtManager        *jwt.Manager
+	external          ExternalTokenVerifier
+	refreshTokens     RefreshTokenRepository
+	revokedTokens     RevokedTokenRepository
 	minPasswordLength int
+	adminLogins       map[string]struct{}
+	rememberTTL       time.Duration
+	impersonationTTL  time.Duration
+	refreshTTL        time.Duration
+
+	// Хеш случайного пароля той же стоимости, что и у пользователей: по нему проверяется
+	// пароль неизвестного пользователя
+	dummyHash string
 }
 
-// NewAuthService создает новый AuthService
+// NewAuthService создает новый AuthService.
+// external может быть nil: тогда принимаются только токены, выпущенные сервисом.
+// refreshTokens может быть nil: тогда refresh-токены не выдаются.
+// revokedTokens может быть nil: тогда выход недоступен и черный список не проверяется.
+// Хеш для проверки пароля неизвестного пользователя вычисляется здесь: без него
+// неудачный вход по времени выдавал бы, существует ли логин
 func NewAuthService(
 	userRepo UserRepository,
 	passwordHasher password.Hasher,
 	jwtManager *jwt.Manager,
+	external ExternalTokenVerifier,
+	refreshTokens RefreshTokenRepository,
+	revokedTokens RevokedTokenRepository,
 	config AuthServiceConfig,
-) *AuthService {
+) (*AuthService, error) {
 	if config.MinPasswordLength <= 0 {
 		config.MinPasswordLength = 6
 	}
+	// Вход с remember не может дать токен короче обычного
+	if jwtManager != nil && config.RememberTTL < jwtManager.TokenTTL() {
+		config.RememberTTL = 0
+	}
+	if refreshTokens == nil || config.RefreshTTL < 0 {
+		config.RefreshTTL = 0
+	}
+	if config.ImpersonationTTL <= 0 {
+		config.ImpersonationTTL = defaultImpersonationTTL
+	}
+	adminLogins := make(map[string]struct{}, len(config.AdminLogins))
+	for _, login := range config.AdminLogins {
+		adminLogins[login] = struct{}{}
+	}
+	var dummyHash string
+	if passwordHasher != nil {
+		hash, err := passwordHasher.Hash(rand.Text())
+		if err != nil {
+			return nil, fmt.Errorf("auth service: failed to hash dummy password: %w", err)
+		}
+		dummyHash = hash
+	}
 	return &AuthService{
 		userRepo:          userRepo,
 		passwordHasher:    passwordHasher,
 		jwtManager:        jwtManager,
+		external:          external,
+		refreshTokens:     refreshTokens,
+		revokedTokens:     revokedTokens,
 		minPasswordLength: config.MinPasswordLength,
-	}
+		adminLogins:       adminLogins,
+		rememberTTL:       config.RememberTTL,
+		impersonationTTL:  config.ImpersonationTTL,
+		refreshTTL:        config.RefreshTTL,
+		dummyHash:         dummyHash,
+	}, nil
 }
 
-// Register регистрирует нового пользователя
-func (s *AuthService) Register(ctx context.Context, login, userPassword string) (string, error) {
+// Register регистрирует нового пользователя. Refresh-токен привязывается к устройству device
+func (s *AuthService) Register(ctx context.Context, login, userPassword, device string) (*domain.AuthTokens, error) {
+	ctx, span := tracing.Start(ctx, "AuthService.Register")
+	defer span.End()
+
 	// Валидация входных данных
 	if login == "" || userPassword == "" {
-		return "", fmt.Errorf("%w: empty login or password", ErrInvalidInput)
+		return nil, fmt.Errorf("%w: empty login or password", domain.ErrInvalidInput)
+	}
+
+	if err := validateLogin(login); err != nil {
+		return nil, err
 	}
 
 	if len(userPassword) < s.minPasswordLength {
-		return "", fmt.Errorf("%w: password must be at least %d characters", ErrInvalidInput, s.minPasswordLength)
+		return nil, fmt.Errorf("%w: password must be at least %d characters", domain.ErrInvalidInput, s.minPasswordLength)
 	}
 
 	// Хеширование пароля
 	hash, err := s.passwordHasher.Hash(userPassword)
 	if err != nil {
-		return "", fmt.Errorf("auth service: failed to hash password for user %q: %w", login, err)
+		tracing.Fail(span, err)
+		logctx.From(ctx).Error("auth service: failed to hash password", zap.String("login", login), zap.Error(err))
+		return nil, fmt.Errorf("auth service: failed to hash password for user %q: %w", login, err)
 	}
 
 	// Создание пользователя
 	user, err := s.userRepo.CreateUser(ctx, login, hash)
 	if err != nil {
-		if errors.Is(err, postgres.ErrUserExists) {
-			return "", fmt.Errorf("auth service: user %q already exists: %w", login, ErrUserExists)
+		if errors.Is(err, domain.ErrUserExists) {
+			return nil, fmt.Errorf("auth service: user %q already exists: %w", login, err)
 		}
-		return "", fmt.Errorf("auth service: failed to register user %q: %w", login, err)
+		tracing.Fail(span, err)
+		logctx.From(ctx).Error("auth service: failed to create user", zap.String("login", login), zap.Error(err))
+		return nil, fmt.Errorf("auth service: failed to register user %q: %w", login, err)
 	}
 
-	// Генерация JWT токена
-	token, err := s.jwtManager.Generate(user.ID)
-	if err != nil {
-		return "", fmt.Errorf("auth service: failed to generate token for user %d: %w", user.ID, err)
-	}
+	return s.issueTokens(ctx, user.ID, device, 0)
+}
 
-	return token, nil
+// CheckLogin проверяет, подходит ли логин для регистрации.
+// Занятость логина не проверяется, чтобы проверку нельзя было использовать для перебора
+// зарегистрированных логинов: о занятом логине сообщает только регистрация.
+func (s *AuthService) CheckLogin(_ context.Context, login string) error {
+	return validateLogin(login)
 }
 
-// Login аутентифицирует пользователя
-func (s *AuthService) Login(ctx context.Context, login, userPassword string) (string, error) {
+// validateLogin проверяет формат логина
+func validateLogin(login string) error {
+	if login == "" || len(login) > domain.MaxLoginLength {
+		return fmt.Errorf("%w: login must be from 1 to %d bytes", domain.ErrInvalidInput, domain.MaxLoginLength)
+	}
+	return nil
+}
+
+// Login аутентифицирует пользователя. С remember токен доступа живет RememberTTL вместо обычного срока.
+// Refresh-токен привязывается к устройству device
+func (s *AuthService) Login(ctx context.Context, login, userPassword, device string, remember bool) (*domain.AuthTokens, error) {
+	ctx, span := tracing.Start(ctx, "AuthService.Login")
+	defer span.End()
+
 	// Валидация входных данных
 	if login == "" || userPassword == "" {
-		return "", fmt.Errorf("%w: empty login or password", ErrInvalidInput)
+		return nil, fmt.Errorf("%w: empty login or password", domain.ErrInvalidInput)
 	}
 
 	// Получение пользователя по логину
 	user, err := s.userRepo.GetUserByLogin(ctx, login)
-	if err != nil {
-		if errors.Is(err, postgres.ErrUserNotFound) {
-			return "", ErrInvalidCredentials
-		}
-		return "", fmt.Errorf("auth service: failed to get user %q: %w", login, err)
+	if err != nil && !errors.Is(err, domain.ErrUserNotFound) {
+		tracing.Fail(span, err)
+		logctx.From(ctx).Error("auth service: failed to get user", zap.String("login", login), zap.Error(err))
+		return nil, fmt.Errorf("auth service: failed to get user %q: %w", login, err)
+	}
+
+	// Неизвестный пользователь и пользователь без пароля (вход только через внешнего
+	// провайдера) проверяются по фиктивному хешу: иначе ответ без вычисления bcrypt
+	// по времени выдавал бы, существует ли логин
+	if user == nil || user.PasswordHash == "" {
+		_ = s.passwordHasher.Check(s.dummyHash, userPassword)
+		return nil, domain.ErrInvalidCredentials
 	}
 
 	// Проверка пароля
 	err = s.passwordHasher.Check(user.PasswordHash, userPassword)
 	if err != nil {
-		return "", ErrInvalidCredentials
+		return nil, domain.ErrInvalidCredentials
+	}
+
+	var ttl time.Duration
+	if remember {
+		ttl = s.rememberTTL
+	}
+	return s.issueTokens(ctx, user.ID, device, ttl)
+}
+
+// issueTokens выдает токен доступа со временем жизни ttl (0 - обычный срок)
+// и, если они включены, refresh-токен устройства device
+func (s *AuthService) issueTokens(ctx context.Context, userID int64, device string, ttl time.Duration) (*domain.AuthTokens, error) {
+	token, err := s.jwtManager.Generate(userID, ttl)
+	if err != nil {
+		logctx.From(ctx).Error("auth service: failed to generate token", zap.Int64("user_id", userID), zap.Error(err))
+		return nil, fmt.Errorf("auth service: failed to generate token for user %d: %w", userID, err)
+	}
+	tokens := &domain.AuthTokens{AccessToken: token}
+	if s.refreshTTL == 0 {
+		return tokens, nil
+	}
+
+	refreshToken, claims, err := s.jwtManager.GenerateRefresh(userID, s.refreshTTL)
+	if err != nil {
+		return nil, fmt.Errorf("auth service: failed to generate refresh token for user %d: %w", userID, err)
+	}
+	if err := s.refreshTokens.CreateRefreshToken(ctx, userID, claims.ID, device, s.refreshTTL); err != nil {
+		return nil, fmt.Errorf("auth service: failed to save refresh token for user %d: %w", userID, err)
+	}
+	tokens.RefreshToken = refreshToken
+	return tokens, nil
+}
+
+// Refresh обменивает refresh-токен на новый токен доступа обычного срока и новый
+// refresh-токен; предъявленный токен отзывается. Повторное предъявление отозванного
+// токена или предъявление с устройства device, которому токен не выдавался (в том числе
+// без идентификатора устройства, если токен выдан устройству), отзывает все refresh-токены
+// пользователя
+func (s *AuthService) Refresh(ctx context.Context, refreshToken, device string) (*domain.AuthTokens, error) {
+	if s.refreshTTL == 0 {
+		return nil, fmt.Errorf("%w: refresh tokens are disabled", domain.ErrInvalidToken)
+	}
+
+	claims, err := s.jwtManager.ValidateRefresh(refreshToken)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", domain.ErrInvalidToken, err)
+	}
+
+	token, err := s.jwtManager.Generate(claims.UserID, 0)
+	if err != nil {
+		return nil, fmt.Errorf("auth service: failed to generate token for user %d: %w", claims.UserID, err)
+	}
+	next, nextClaims, err := s.jwtManager.GenerateRefresh(claims.UserID, s.refreshTTL)
+	if err != nil {
+		return nil, fmt.Errorf("auth service: failed to generate refresh token for user %d: %w", claims.UserID, err)
+	}
+
+	err = s.refreshTokens.RotateRefreshToken(ctx, claims.UserID, claims.ID, nextClaims.ID, device, s.refreshTTL)
+	if errors.Is(err, domain.ErrRefreshTokenReused) {
+		logctx.From(ctx).Warn("refresh token reused, all refresh tokens of the user revoked", zap.Int64("user_id", claims.UserID))
+		return nil, fmt.Errorf("%w: %w", domain.ErrInvalidToken, err)
+	}
+	if errors.Is(err, domain.ErrRefreshTokenDevice) {
+		logctx.From(ctx).Warn("refresh token presented from another device, all refresh tokens of the user revoked", zap.Int64("user_id", claims.UserID))
+		return nil, fmt.Errorf("%w: %w", domain.ErrInvalidToken, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("auth service: failed to rotate refresh token of user %d: %w", claims.UserID, err)
+	}
+
+	return &domain.AuthTokens{AccessToken: token, RefreshToken: next}, nil
+}
+
+// PurgeExpiredRefreshTokens удаляет истекшие refresh-токены. Каждый вход и каждое
+// обновление сохраняют новый токен, поэтому без очистки хранилище растет неограниченно
+func (s *AuthService) PurgeExpiredRefreshTokens(ctx context.Context) error {
+	if s.refreshTokens == nil {
+		return nil
+	}
+
+	var total int
+	for {
+		n, err := s.refreshTokens.DeleteExpiredRefreshTokens(ctx, refreshCleanupBatch)
+		if err != nil {
+			return fmt.Errorf("auth service: failed to delete expired refresh tokens after %d: %w", total, err)
+		}
+		total += n
+		if n < refreshCleanupBatch {
+			break
+		}
+	}
+
+	if total > 0 {
+		logctx.From(ctx).Info("expired refresh tokens deleted", zap.Int("tokens", total))
+	}
+	return nil
+}
+
+// ValidateToken проверяет токен и возвращает ID локального пользователя и срок действия токена.
+// Токены внешнего провайдера проверяются по его ключам, а при первом обращении
+// для пользователя провайдера создается локальная учетная запись.
+// Токен, отозванный при выходе, отклоняется с причиной ErrTokenRevoked
+func (s *AuthService) ValidateToken(ctx context.Context, token string) (*domain.AccessToken, error) {
+	access, err := s.verifyToken(ctx, token)
+	if err != nil || s.revokedTokens == nil {
+		return access, err
+	}
+
+	// Черный список проверяется после подписи, чтобы поддельные токены не доходили до БД
+	revoked, err := s.revokedTokens.IsTokenRevoked(ctx, tokenHash(token))
+	if err != nil {
+		return nil, fmt.Errorf("auth service: failed to check revocation of token of user %d: %w", access.UserID, err)
+	}
+	if revoked {
+		return nil, fmt.Errorf("%w: %w", domain.ErrInvalidToken, domain.ErrTokenRevoked)
+	}
+	return access, nil
+}
+
+// Logout отзывает токен доступа до истечения его срока: токен попадает в черный список,
+// который проверяет ValidateToken. Вместе с ним отзываются refresh-токены пользователя,
+// иначе по ним можно было бы сразу получить новый токен доступа. Выход администратора,
+// работающего от имени пользователя, refresh-токены пользователя не трогает.
+// Недействительный токен - ErrInvalidToken
+func (s *AuthService) Logout(ctx context.Context, token string) error {
+	if s.revokedTokens == nil {
+		return errors.New("auth service: token revocation is not configured")
+	}
+
+	access, err := s.verifyToken(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	var ttl time.Duration
+	if !access.ExpiresAt.IsZero() {
+		// Не меньше секунды: ноль означал бы токен без срока действия
+		ttl = max(time.Until(access.ExpiresAt), time.Second)
+	}
+	if err := s.revokedTokens.RevokeToken(ctx, access.UserID, tokenHash(token), ttl); err != nil {
+		return fmt.Errorf("auth service: failed to revoke token of user %d: %w", access.UserID, err)
+	}
+	if s.refreshTokens != nil && access.ImpersonatorID == 0 {
+		if err := s.refreshTokens.RevokeRefreshTokens(ctx, access.UserID); err != nil {
+			return fmt.Errorf("auth service: failed to revoke refresh tokens of user %d: %w", access.UserID, err)
+		}
+	}
+
+	logctx.From(ctx).Info("access token revoked", zap.Int64("user_id", access.UserID))
+	return nil
+}
+
+// tokenHash возвращает SHA-256 токена: в черном списке не хранятся сами токены
+func tokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// verifyToken проверяет подпись и срок действия токена без черного списка
+func (s *AuthService) verifyToken(ctx context.Context, token string) (*domain.AccessToken, error) {
+	if s.external == nil || !s.external.Accepts(token) {
+		claims, err := s.jwtManager.ValidateClaims(token)
+		if err != nil {
+			// Причина (истек, поврежден, чужая подпись) сохраняется для метрик и логов
+			return nil, fmt.Errorf("%w: %w", domain.ErrInvalidToken, err)
+		}
+		access := &domain.AccessToken{UserID: claims.UserID, ImpersonatorID: claims.ImpersonatorID}
+		if claims.ExpiresAt != nil {
+			access.ExpiresAt = claims.ExpiresAt.Time
+		}
+		return access, nil
+	}
+
+	identity, err := s.external.Verify(ctx, token)
+	if err != nil {
+		logctx.From(ctx).Debug("auth service: external token rejected", zap.Error(err))
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidToken, err)
+	}
+
+	user, err := s.userRepo.GetOrCreateExternalUser(ctx, *identity)
+	if err != nil {
+		logctx.From(ctx).Error("auth service: failed to provision external user",
+			zap.String("issuer", identity.Issuer), zap.String("subject", identity.Subject), zap.Error(err))
+		return nil, fmt.Errorf("auth service: failed to provision user for subject %q: %w", identity.Subject, err)
+	}
+
+	return &domain.AccessToken{UserID: user.ID, ExpiresAt: identity.ExpiresAt}, nil
+}
+
+// IsAdmin проверяет, входит ли пользователь в список администраторов
+func (s *AuthService) IsAdmin(ctx context.Context, userID int64) (bool, error) {
+	if len(s.adminLogins) == 0 {
+		return false, nil
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("auth service: failed to get user %d: %w", userID, err)
+	}
+
+	_, ok := s.adminLogins[user.Login]
+	return ok, nil
+}
+
+// Impersonate выдает администратору adminID короткоживущий токен пользователя userID.
+// Токен помечен администратором, поэтому запросы по нему отличимы от запросов самого пользователя.
+// Действовать от имени другого администратора нельзя - ErrCannotImpersonate.
+func (s *AuthService) Impersonate(ctx context.Context, adminID, userID int64) (*domain.Impersonation, error) {
+	if userID == adminID {
+		return nil, fmt.Errorf("auth service: admin %d cannot impersonate themselves: %w", adminID, domain.ErrInvalidInput)
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil, fmt.Errorf("auth service: cannot impersonate user %d: %w", userID, err)
+		}
+		return nil, fmt.Errorf("auth service: failed to get user %d: %w", userID, err)
+	}
+	if _, ok := s.adminLogins[user.Login]; ok {
+		return nil, fmt.Errorf("auth service: user %d is an admin: %w", userID, domain.ErrCannotImpersonate)
 	}
 
-	// Генерация JWT токена
-	token, err := s.jwtManager.Generate(user.ID)
+	expiresAt := time.Now().Add(s.impersonationTTL)
+	token, err := s.jwtManager.GenerateImpersonation(user.ID, adminID, s.impersonationTTL)
 	if err != nil {
-		return "", fmt.Errorf("auth service: failed to generate token for user %d: %w", user.ID, err)
+		return nil, fmt.Errorf("auth service: failed to generate impersonation token: %w", err)
 	}
 
-	return token, nil
+	logctx.From(ctx).Info("impersonation started",
+		zap.Int64("impersonator_id", adminID),
+		zap.Int64("impersonated_user_id", user.ID),
+		zap.Time("expires_at", expiresAt),
+	)
+	return &domain.Impersonation{
+		Token:          token,
+		UserID:         user.ID,
+		Login:          user.Login,
+		ImpersonatorID: adminID,
+		ExpiresAt:      expiresAt,
+	}, nil
 }
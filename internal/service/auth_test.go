@@ -3,12 +3,13 @@ package service
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/avc/loyalty-system-diploma/internal/domain"
 	domainmocks "github.com/avc/loyalty-system-diploma/internal/domain/mocks"
-	"github.com/avc/loyalty-system-diploma/internal/repository/postgres"
+	"github.com/avc/loyalty-system-diploma/internal/tokenstore"
 	"github.com/avc/loyalty-system-diploma/internal/utils/jwt"
 	passwordmocks "github.com/avc/loyalty-system-diploma/internal/utils/password/mocks"
 	"github.com/stretchr/testify/assert"
@@ -19,9 +20,11 @@ import (
 func newTestAuthService(t *testing.T) (*AuthService, *domainmocks.UserRepositoryMock, *passwordmocks.HasherMock) {
 	mockUserRepo := domainmocks.NewUserRepositoryMock(t)
 	mockHasher := passwordmocks.NewHasherMock(t)
+	mockHasher.EXPECT().Hash(mock.Anything).Return("dummy_hash", nil).Once()
 	jwtManager := jwt.NewManager("test-secret", time.Hour)
 	config := AuthServiceConfig{MinPasswordLength: 6}
-	svc := NewAuthService(mockUserRepo, mockHasher, jwtManager, config)
+	svc, err := NewAuthService(mockUserRepo, mockHasher, jwtManager, nil, nil, nil, config)
+	require.NoError(t, err)
 	return svc, mockUserRepo, mockHasher
 }
 
@@ -52,21 +55,28 @@ func TestAuthService_Register(t *testing.T) {
 			login:      "",
 			password:   "password",
 			setupMocks: func(userRepo *domainmocks.UserRepositoryMock, hasher *passwordmocks.HasherMock) {},
-			wantErr:    ErrInvalidInput,
+			wantErr:    domain.ErrInvalidInput,
 		},
 		{
 			name:       "Empty password",
 			login:      "testuser",
 			password:   "",
 			setupMocks: func(userRepo *domainmocks.UserRepositoryMock, hasher *passwordmocks.HasherMock) {},
-			wantErr:    ErrInvalidInput,
+			wantErr:    domain.ErrInvalidInput,
+		},
+		{
+			name:       "Login too long",
+			login:      strings.Repeat("a", 256),
+			password:   "password123",
+			setupMocks: func(userRepo *domainmocks.UserRepositoryMock, hasher *passwordmocks.HasherMock) {},
+			wantErr:    domain.ErrInvalidInput,
 		},
 		{
 			name:       "Password too short",
 			login:      "testuser",
 			password:   "12345", // < 6 characters
 			setupMocks: func(userRepo *domainmocks.UserRepositoryMock, hasher *passwordmocks.HasherMock) {},
-			wantErr:    ErrInvalidInput,
+			wantErr:    domain.ErrInvalidInput,
 		},
 		{
 			name:     "Hash password error",
@@ -84,9 +94,9 @@ func TestAuthService_Register(t *testing.T) {
 			setupMocks: func(userRepo *domainmocks.UserRepositoryMock, hasher *passwordmocks.HasherMock) {
 				hasher.EXPECT().Hash("password123").Return("hashed_password", nil).Once()
 				userRepo.EXPECT().CreateUser(mock.Anything, "existinguser", "hashed_password").
-					Return(nil, postgres.ErrUserExists).Once()
+					Return(nil, domain.ErrUserExists).Once()
 			},
-			wantErr: ErrUserExists,
+			wantErr: domain.ErrUserExists,
 		},
 		{
 			name:     "Database error",
@@ -106,7 +116,7 @@ func TestAuthService_Register(t *testing.T) {
 			svc, userRepo, hasher := newTestAuthService(t)
 			tt.setupMocks(userRepo, hasher)
 
-			token, err := svc.Register(ctx, tt.login, tt.password)
+			token, err := svc.Register(ctx, tt.login, tt.password, "")
 
 			if tt.wantErr != nil {
 				assert.ErrorIs(t, err, tt.wantErr)
@@ -122,6 +132,16 @@ func TestAuthService_Register(t *testing.T) {
 	}
 }
 
+func TestAuthService_CheckLogin(t *testing.T) {
+	svc, _, _ := newTestAuthService(t)
+	ctx := context.Background()
+
+	assert.NoError(t, svc.CheckLogin(ctx, "testuser"))
+	assert.NoError(t, svc.CheckLogin(ctx, strings.Repeat("a", 255)))
+	assert.ErrorIs(t, svc.CheckLogin(ctx, ""), domain.ErrInvalidInput)
+	assert.ErrorIs(t, svc.CheckLogin(ctx, strings.Repeat("a", 256)), domain.ErrInvalidInput)
+}
+
 func TestAuthService_Login(t *testing.T) {
 	ctx := context.Background()
 
@@ -149,23 +169,36 @@ func TestAuthService_Login(t *testing.T) {
 			login:      "",
 			password:   "password",
 			setupMocks: func(userRepo *domainmocks.UserRepositoryMock, hasher *passwordmocks.HasherMock) {},
-			wantErr:    ErrInvalidInput,
+			wantErr:    domain.ErrInvalidInput,
 		},
 		{
 			name:       "Empty password",
 			login:      "testuser",
 			password:   "",
 			setupMocks: func(userRepo *domainmocks.UserRepositoryMock, hasher *passwordmocks.HasherMock) {},
-			wantErr:    ErrInvalidInput,
+			wantErr:    domain.ErrInvalidInput,
 		},
 		{
 			name:     "User not found",
 			login:    "nonexistent",
 			password: "password123",
 			setupMocks: func(userRepo *domainmocks.UserRepositoryMock, hasher *passwordmocks.HasherMock) {
-				userRepo.EXPECT().GetUserByLogin(mock.Anything, "nonexistent").Return(nil, postgres.ErrUserNotFound).Once()
+				userRepo.EXPECT().GetUserByLogin(mock.Anything, "nonexistent").Return(nil, domain.ErrUserNotFound).Once()
+				// Пароль все равно проверяется, чтобы время ответа не выдавало отсутствие логина
+				hasher.EXPECT().Check("dummy_hash", "password123").Return(errors.New("password mismatch")).Once()
+			},
+			wantErr: domain.ErrInvalidCredentials,
+		},
+		{
+			name:     "User without password",
+			login:    "sso-user",
+			password: "password123",
+			setupMocks: func(userRepo *domainmocks.UserRepositoryMock, hasher *passwordmocks.HasherMock) {
+				user := &domain.User{ID: 2, Login: "sso-user"}
+				userRepo.EXPECT().GetUserByLogin(mock.Anything, "sso-user").Return(user, nil).Once()
+				hasher.EXPECT().Check("dummy_hash", "password123").Return(errors.New("password mismatch")).Once()
 			},
-			wantErr: ErrInvalidCredentials,
+			wantErr: domain.ErrInvalidCredentials,
 		},
 		{
 			name:     "Wrong password",
@@ -176,7 +209,7 @@ func TestAuthService_Login(t *testing.T) {
 				userRepo.EXPECT().GetUserByLogin(mock.Anything, "testuser").Return(user, nil).Once()
 				hasher.EXPECT().Check("hashed_password", "wrongpassword").Return(errors.New("password mismatch")).Once()
 			},
-			wantErr: ErrInvalidCredentials,
+			wantErr: domain.ErrInvalidCredentials,
 		},
 		{
 			name:     "Database error",
@@ -194,7 +227,7 @@ func TestAuthService_Login(t *testing.T) {
 			svc, userRepo, hasher := newTestAuthService(t)
 			tt.setupMocks(userRepo, hasher)
 
-			token, err := svc.Login(ctx, tt.login, tt.password)
+			token, err := svc.Login(ctx, tt.login, tt.password, "", false)
 
 			if tt.wantErr != nil {
 				assert.ErrorIs(t, err, tt.wantErr)
@@ -209,3 +242,476 @@ func TestAuthService_Login(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthService_IsAdmin(t *testing.T) {
+	ctx := context.Background()
+	mockUserRepo := domainmocks.NewUserRepositoryMock(t)
+	config := AuthServiceConfig{AdminLogins: []string{"admin"}}
+	svc, err := NewAuthService(mockUserRepo, nil, nil, nil, nil, nil, config)
+	require.NoError(t, err)
+
+	t.Run("Admin", func(t *testing.T) {
+		mockUserRepo.EXPECT().GetUserByID(mock.Anything, int64(1)).Return(&domain.User{ID: 1, Login: "admin"}, nil).Once()
+
+		ok, err := svc.IsAdmin(ctx, 1)
+		require.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("Regular user", func(t *testing.T) {
+		mockUserRepo.EXPECT().GetUserByID(mock.Anything, int64(2)).Return(&domain.User{ID: 2, Login: "user"}, nil).Once()
+
+		ok, err := svc.IsAdmin(ctx, 2)
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("Deleted user", func(t *testing.T) {
+		mockUserRepo.EXPECT().GetUserByID(mock.Anything, int64(3)).Return(nil, domain.ErrUserNotFound).Once()
+
+		ok, err := svc.IsAdmin(ctx, 3)
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("Database error", func(t *testing.T) {
+		mockUserRepo.EXPECT().GetUserByID(mock.Anything, int64(4)).Return(nil, errors.New("db error")).Once()
+
+		_, err := svc.IsAdmin(ctx, 4)
+		assert.Error(t, err)
+	})
+
+	t.Run("No admins configured", func(t *testing.T) {
+		svc, err := NewAuthService(mockUserRepo, nil, nil, nil, nil, nil, AuthServiceConfig{})
+		require.NoError(t, err)
+
+		ok, err := svc.IsAdmin(ctx, 1)
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+}
+
+func TestAuthService_Impersonate(t *testing.T) {
+	ctx := context.Background()
+	mockUserRepo := domainmocks.NewUserRepositoryMock(t)
+	jwtManager := jwt.NewManager("test-secret", time.Hour)
+	config := AuthServiceConfig{AdminLogins: []string{"admin"}, ImpersonationTTL: 10 * time.Minute}
+	svc, err := NewAuthService(mockUserRepo, nil, jwtManager, nil, nil, nil, config)
+	require.NoError(t, err)
+
+	t.Run("Success", func(t *testing.T) {
+		mockUserRepo.EXPECT().GetUserByID(mock.Anything, int64(7)).Return(&domain.User{ID: 7, Login: "alice"}, nil).Once()
+
+		impersonation, err := svc.Impersonate(ctx, 1, 7)
+		require.NoError(t, err)
+		assert.Equal(t, "alice", impersonation.Login)
+		assert.Equal(t, int64(1), impersonation.ImpersonatorID)
+		assert.WithinDuration(t, time.Now().Add(10*time.Minute), impersonation.ExpiresAt, 5*time.Second)
+
+		// Токен проверяется как обычный, но несет администратора
+		access, err := svc.ValidateToken(ctx, impersonation.Token)
+		require.NoError(t, err)
+		assert.Equal(t, int64(7), access.UserID)
+		assert.Equal(t, int64(1), access.ImpersonatorID)
+	})
+
+	t.Run("Self", func(t *testing.T) {
+		_, err := svc.Impersonate(ctx, 1, 1)
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	})
+
+	t.Run("Unknown user", func(t *testing.T) {
+		mockUserRepo.EXPECT().GetUserByID(mock.Anything, int64(8)).Return(nil, domain.ErrUserNotFound).Once()
+
+		_, err := svc.Impersonate(ctx, 1, 8)
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	})
+
+	t.Run("Another admin", func(t *testing.T) {
+		mockUserRepo.EXPECT().GetUserByID(mock.Anything, int64(2)).Return(&domain.User{ID: 2, Login: "admin"}, nil).Once()
+
+		_, err := svc.Impersonate(ctx, 1, 2)
+		assert.ErrorIs(t, err, domain.ErrCannotImpersonate)
+	})
+}
+
+func TestAuthService_ValidateToken(t *testing.T) {
+	ctx := context.Background()
+	jwtManager := jwt.NewManager("test-secret", time.Hour)
+	localToken, err := jwtManager.Generate(7, 0)
+	require.NoError(t, err)
+
+	ssoExpiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	identity := &domain.ExternalIdentity{Issuer: "https://sso.example.com", Subject: "user-1", Login: "alice", ExpiresAt: ssoExpiresAt}
+
+	t.Run("Local token without external provider", func(t *testing.T) {
+		svc, err := NewAuthService(domainmocks.NewUserRepositoryMock(t), nil, jwtManager, nil, nil, nil, AuthServiceConfig{})
+		require.NoError(t, err)
+
+		access, err := svc.ValidateToken(ctx, localToken)
+		require.NoError(t, err)
+		assert.Equal(t, int64(7), access.UserID)
+		assert.WithinDuration(t, time.Now().Add(time.Hour), access.ExpiresAt, 5*time.Second)
+
+		_, err = svc.ValidateToken(ctx, "invalid.token")
+		assert.ErrorIs(t, err, domain.ErrInvalidToken)
+	})
+
+	t.Run("Local token with external provider", func(t *testing.T) {
+		verifier := domainmocks.NewExternalTokenVerifierMock(t)
+		verifier.EXPECT().Accepts(localToken).Return(false).Once()
+		svc, err := NewAuthService(domainmocks.NewUserRepositoryMock(t), nil, jwtManager, verifier, nil, nil, AuthServiceConfig{})
+		require.NoError(t, err)
+
+		access, err := svc.ValidateToken(ctx, localToken)
+		require.NoError(t, err)
+		assert.Equal(t, int64(7), access.UserID)
+	})
+
+	t.Run("External token provisions user", func(t *testing.T) {
+		verifier := domainmocks.NewExternalTokenVerifierMock(t)
+		verifier.EXPECT().Accepts("sso-token").Return(true).Once()
+		verifier.EXPECT().Verify(mock.Anything, "sso-token").Return(identity, nil).Once()
+		userRepo := domainmocks.NewUserRepositoryMock(t)
+		userRepo.EXPECT().GetOrCreateExternalUser(mock.Anything, *identity).Return(&domain.User{ID: 42, Login: "alice"}, nil).Once()
+		svc, err := NewAuthService(userRepo, nil, jwtManager, verifier, nil, nil, AuthServiceConfig{})
+		require.NoError(t, err)
+
+		access, err := svc.ValidateToken(ctx, "sso-token")
+		require.NoError(t, err)
+		assert.Equal(t, &domain.AccessToken{UserID: 42, ExpiresAt: ssoExpiresAt}, access)
+	})
+
+	t.Run("Rejected external token", func(t *testing.T) {
+		verifier := domainmocks.NewExternalTokenVerifierMock(t)
+		verifier.EXPECT().Accepts("sso-token").Return(true).Once()
+		verifier.EXPECT().Verify(mock.Anything, "sso-token").Return(nil, errors.New("token is expired")).Once()
+		svc, err := NewAuthService(domainmocks.NewUserRepositoryMock(t), nil, jwtManager, verifier, nil, nil, AuthServiceConfig{})
+		require.NoError(t, err)
+
+		_, err = svc.ValidateToken(ctx, "sso-token")
+		assert.ErrorIs(t, err, domain.ErrInvalidToken)
+	})
+
+	t.Run("Provisioning failure is not an invalid token", func(t *testing.T) {
+		verifier := domainmocks.NewExternalTokenVerifierMock(t)
+		verifier.EXPECT().Accepts("sso-token").Return(true).Once()
+		verifier.EXPECT().Verify(mock.Anything, "sso-token").Return(identity, nil).Once()
+		userRepo := domainmocks.NewUserRepositoryMock(t)
+		userRepo.EXPECT().GetOrCreateExternalUser(mock.Anything, *identity).Return(nil, domain.ErrStorageUnavailable).Once()
+		svc, err := NewAuthService(userRepo, nil, jwtManager, verifier, nil, nil, AuthServiceConfig{})
+		require.NoError(t, err)
+
+		_, err = svc.ValidateToken(ctx, "sso-token")
+		assert.ErrorIs(t, err, domain.ErrStorageUnavailable)
+		assert.NotErrorIs(t, err, domain.ErrInvalidToken)
+	})
+}
+
+func TestAuthService_Login_DummyHashIsReused(t *testing.T) {
+	// Фиктивный хеш вычисляется при создании сервиса, вход его только проверяет
+	svc, userRepo, hasher := newTestAuthService(t)
+	userRepo.EXPECT().GetUserByLogin(mock.Anything, "nonexistent").Return(nil, domain.ErrUserNotFound).Times(3)
+	hasher.EXPECT().Check("dummy_hash", "password123").Return(errors.New("password mismatch")).Times(3)
+
+	for range 3 {
+		_, err := svc.Login(context.Background(), "nonexistent", "password123", "", false)
+		assert.ErrorIs(t, err, domain.ErrInvalidCredentials)
+	}
+}
+
+func TestNewAuthService_DummyHashError(t *testing.T) {
+	hasher := passwordmocks.NewHasherMock(t)
+	hasher.EXPECT().Hash(mock.Anything).Return("", errors.New("rng failure")).Once()
+
+	_, err := NewAuthService(domainmocks.NewUserRepositoryMock(t), hasher, jwt.NewManager("test-secret", time.Hour), nil, nil, nil, AuthServiceConfig{})
+	assert.Error(t, err)
+}
+
+func TestAuthService_Login_Remember(t *testing.T) {
+	newService := func(t *testing.T, rememberTTL time.Duration) (*AuthService, *jwt.Manager) {
+		userRepo := domainmocks.NewUserRepositoryMock(t)
+		hasher := passwordmocks.NewHasherMock(t)
+		user := &domain.User{ID: 1, Login: "testuser", PasswordHash: "hashed_password"}
+		userRepo.EXPECT().GetUserByLogin(mock.Anything, "testuser").Return(user, nil)
+		hasher.EXPECT().Hash(mock.Anything).Return("dummy_hash", nil).Once()
+		hasher.EXPECT().Check("hashed_password", "password123").Return(nil)
+
+		jwtManager := jwt.NewManager("test-secret", time.Hour)
+		svc, err := NewAuthService(userRepo, hasher, jwtManager, nil, nil, nil, AuthServiceConfig{RememberTTL: rememberTTL})
+		require.NoError(t, err)
+		return svc, jwtManager
+	}
+	expiresIn := func(t *testing.T, m *jwt.Manager, token string) time.Duration {
+		claims, err := m.ValidateClaims(token)
+		require.NoError(t, err)
+		return time.Until(claims.ExpiresAt.Time)
+	}
+
+	t.Run("Longer token", func(t *testing.T) {
+		svc, jwtManager := newService(t, 24*time.Hour)
+
+		token, err := svc.Login(context.Background(), "testuser", "password123", "", false)
+		require.NoError(t, err)
+		assert.InDelta(t, time.Hour.Seconds(), expiresIn(t, jwtManager, token.AccessToken).Seconds(), 5)
+
+		token, err = svc.Login(context.Background(), "testuser", "password123", "", true)
+		require.NoError(t, err)
+		assert.InDelta(t, (24 * time.Hour).Seconds(), expiresIn(t, jwtManager, token.AccessToken).Seconds(), 5)
+	})
+
+	t.Run("Shorter than default", func(t *testing.T) {
+		svc, jwtManager := newService(t, time.Minute)
+
+		token, err := svc.Login(context.Background(), "testuser", "password123", "", true)
+		require.NoError(t, err)
+		assert.InDelta(t, time.Hour.Seconds(), expiresIn(t, jwtManager, token.AccessToken).Seconds(), 5)
+	})
+}
+
+func TestAuthService_Refresh(t *testing.T) {
+	ctx := context.Background()
+	const refreshTTL = 30 * 24 * time.Hour
+
+	newService := func(t *testing.T) (*AuthService, *domainmocks.RefreshTokenRepositoryMock, *jwt.Manager) {
+		refreshTokens := domainmocks.NewRefreshTokenRepositoryMock(t)
+		jwtManager := jwt.NewManager("test-secret", time.Hour)
+		svc, err := NewAuthService(domainmocks.NewUserRepositoryMock(t), nil, jwtManager, nil, refreshTokens, nil, AuthServiceConfig{RefreshTTL: refreshTTL})
+		require.NoError(t, err)
+		return svc, refreshTokens, jwtManager
+	}
+
+	t.Run("Login issues refresh token", func(t *testing.T) {
+		userRepo := domainmocks.NewUserRepositoryMock(t)
+		hasher := passwordmocks.NewHasherMock(t)
+		hasher.EXPECT().Hash(mock.Anything).Return("dummy_hash", nil).Once()
+		refreshTokens := domainmocks.NewRefreshTokenRepositoryMock(t)
+		jwtManager := jwt.NewManager("test-secret", time.Hour)
+		svc, err := NewAuthService(userRepo, hasher, jwtManager, nil, refreshTokens, nil, AuthServiceConfig{RefreshTTL: refreshTTL})
+		require.NoError(t, err)
+
+		userRepo.EXPECT().GetUserByLogin(mock.Anything, "testuser").
+			Return(&domain.User{ID: 1, Login: "testuser", PasswordHash: "hashed_password"}, nil).Once()
+		hasher.EXPECT().Check("hashed_password", "password123").Return(nil).Once()
+		var saved string
+		refreshTokens.EXPECT().CreateRefreshToken(mock.Anything, int64(1), mock.Anything, "phone", refreshTTL).
+			RunAndReturn(func(_ context.Context, _ int64, id, _ string, _ time.Duration) error {
+				saved = id
+				return nil
+			}).Once()
+
+		tokens, err := svc.Login(ctx, "testuser", "password123", "phone", false)
+		require.NoError(t, err)
+		claims, err := jwtManager.ValidateRefresh(tokens.RefreshToken)
+		require.NoError(t, err)
+		assert.Equal(t, saved, claims.ID)
+	})
+
+	t.Run("Rotated", func(t *testing.T) {
+		svc, refreshTokens, jwtManager := newService(t)
+		old, oldClaims, err := jwtManager.GenerateRefresh(1, refreshTTL)
+		require.NoError(t, err)
+		refreshTokens.EXPECT().RotateRefreshToken(mock.Anything, int64(1), oldClaims.ID, mock.Anything, "phone", refreshTTL).Return(nil).Once()
+
+		tokens, err := svc.Refresh(ctx, old, "phone")
+		require.NoError(t, err)
+		userID, err := jwtManager.Validate(tokens.AccessToken)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), userID)
+		assert.NotEqual(t, old, tokens.RefreshToken)
+	})
+
+	t.Run("Reused token", func(t *testing.T) {
+		svc, refreshTokens, jwtManager := newService(t)
+		old, _, err := jwtManager.GenerateRefresh(1, refreshTTL)
+		require.NoError(t, err)
+		refreshTokens.EXPECT().RotateRefreshToken(mock.Anything, int64(1), mock.Anything, mock.Anything, "phone", refreshTTL).
+			Return(domain.ErrRefreshTokenReused).Once()
+
+		_, err = svc.Refresh(ctx, old, "phone")
+		assert.ErrorIs(t, err, domain.ErrInvalidToken)
+	})
+
+	t.Run("Token from another device", func(t *testing.T) {
+		svc, refreshTokens, jwtManager := newService(t)
+		old, _, err := jwtManager.GenerateRefresh(1, refreshTTL)
+		require.NoError(t, err)
+		refreshTokens.EXPECT().RotateRefreshToken(mock.Anything, int64(1), mock.Anything, mock.Anything, "laptop", refreshTTL).
+			Return(domain.ErrRefreshTokenDevice).Once()
+
+		_, err = svc.Refresh(ctx, old, "laptop")
+		assert.ErrorIs(t, err, domain.ErrInvalidToken)
+		assert.ErrorIs(t, err, domain.ErrRefreshTokenDevice)
+	})
+
+	t.Run("Access token is not accepted", func(t *testing.T) {
+		svc, _, jwtManager := newService(t)
+		access, err := jwtManager.Generate(1, 0)
+		require.NoError(t, err)
+
+		_, err = svc.Refresh(ctx, access, "phone")
+		assert.ErrorIs(t, err, domain.ErrInvalidToken)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		jwtManager := jwt.NewManager("test-secret", time.Hour)
+		svc, err := NewAuthService(domainmocks.NewUserRepositoryMock(t), nil, jwtManager, nil, nil, nil, AuthServiceConfig{RefreshTTL: refreshTTL})
+		require.NoError(t, err)
+		old, _, err := jwtManager.GenerateRefresh(1, refreshTTL)
+		require.NoError(t, err)
+
+		_, err = svc.Refresh(ctx, old, "phone")
+		assert.ErrorIs(t, err, domain.ErrInvalidToken)
+	})
+}
+
+func TestAuthService_PurgeExpiredRefreshTokens(t *testing.T) {
+	ctx := context.Background()
+	newService := func(t *testing.T) (*AuthService, *domainmocks.RefreshTokenRepositoryMock) {
+		refreshTokens := domainmocks.NewRefreshTokenRepositoryMock(t)
+		svc, err := NewAuthService(domainmocks.NewUserRepositoryMock(t), nil, jwt.NewManager("test-secret", time.Hour), nil, refreshTokens, nil, AuthServiceConfig{RefreshTTL: time.Hour})
+		require.NoError(t, err)
+		return svc, refreshTokens
+	}
+
+	t.Run("Deletes in batches until the last partial batch", func(t *testing.T) {
+		svc, refreshTokens := newService(t)
+		refreshTokens.EXPECT().DeleteExpiredRefreshTokens(mock.Anything, refreshCleanupBatch).Return(refreshCleanupBatch, nil).Twice()
+		refreshTokens.EXPECT().DeleteExpiredRefreshTokens(mock.Anything, refreshCleanupBatch).Return(5, nil).Once()
+
+		assert.NoError(t, svc.PurgeExpiredRefreshTokens(ctx))
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		svc, refreshTokens := newService(t)
+		refreshTokens.EXPECT().DeleteExpiredRefreshTokens(mock.Anything, refreshCleanupBatch).Return(0, errors.New("db is down")).Once()
+
+		assert.Error(t, svc.PurgeExpiredRefreshTokens(ctx))
+	})
+
+	t.Run("Refresh tokens disabled", func(t *testing.T) {
+		svc, err := NewAuthService(domainmocks.NewUserRepositoryMock(t), nil, nil, nil, nil, nil, AuthServiceConfig{})
+		require.NoError(t, err)
+
+		assert.NoError(t, svc.PurgeExpiredRefreshTokens(ctx))
+	})
+}
+
+func TestAuthService_Logout(t *testing.T) {
+	ctx := context.Background()
+	jwtManager := jwt.NewManager("test-secret", time.Hour)
+	token, err := jwtManager.Generate(7, 0)
+	require.NoError(t, err)
+
+	newService := func(t *testing.T) (*AuthService, *domainmocks.RevokedTokenRepositoryMock) {
+		revokedTokens := domainmocks.NewRevokedTokenRepositoryMock(t)
+		svc, err := NewAuthService(domainmocks.NewUserRepositoryMock(t), nil, jwtManager, nil, nil, revokedTokens, AuthServiceConfig{})
+		require.NoError(t, err)
+		return svc, revokedTokens
+	}
+
+	t.Run("Revoked token is rejected", func(t *testing.T) {
+		svc, revokedTokens := newService(t)
+		var revokedHash string
+		revokedTokens.EXPECT().RevokeToken(mock.Anything, int64(7), mock.Anything, mock.Anything).
+			RunAndReturn(func(_ context.Context, _ int64, hash string, ttl time.Duration) error {
+				revokedHash = hash
+				assert.InDelta(t, time.Hour.Seconds(), ttl.Seconds(), 5)
+				return nil
+			}).Once()
+		require.NoError(t, svc.Logout(ctx, token))
+		assert.NotContains(t, revokedHash, token)
+
+		revokedTokens.EXPECT().IsTokenRevoked(mock.Anything, revokedHash).Return(true, nil).Once()
+		_, err := svc.ValidateToken(ctx, token)
+		assert.ErrorIs(t, err, domain.ErrInvalidToken)
+		assert.ErrorIs(t, err, domain.ErrTokenRevoked)
+	})
+
+	t.Run("Token not revoked", func(t *testing.T) {
+		svc, revokedTokens := newService(t)
+		revokedTokens.EXPECT().IsTokenRevoked(mock.Anything, mock.Anything).Return(false, nil).Once()
+
+		access, err := svc.ValidateToken(ctx, token)
+		require.NoError(t, err)
+		assert.Equal(t, int64(7), access.UserID)
+	})
+
+	t.Run("Blacklist is not checked for forged token", func(t *testing.T) {
+		svc, _ := newService(t)
+
+		_, err := svc.ValidateToken(ctx, "invalid.token")
+		assert.ErrorIs(t, err, domain.ErrInvalidToken)
+		assert.NotErrorIs(t, err, domain.ErrTokenRevoked)
+	})
+
+	t.Run("Blacklist unavailable is not an invalid token", func(t *testing.T) {
+		svc, revokedTokens := newService(t)
+		revokedTokens.EXPECT().IsTokenRevoked(mock.Anything, mock.Anything).Return(false, domain.ErrStorageUnavailable).Once()
+
+		_, err := svc.ValidateToken(ctx, token)
+		assert.ErrorIs(t, err, domain.ErrStorageUnavailable)
+		assert.NotErrorIs(t, err, domain.ErrInvalidToken)
+	})
+
+	t.Run("Refresh fails after logout", func(t *testing.T) {
+		userRepo := domainmocks.NewUserRepositoryMock(t)
+		hasher := passwordmocks.NewHasherMock(t)
+		hasher.EXPECT().Hash(mock.Anything).Return("dummy_hash", nil).Once()
+		revokedTokens := domainmocks.NewRevokedTokenRepositoryMock(t)
+		svc, err := NewAuthService(userRepo, hasher, jwtManager, nil, tokenstore.NewMemoryStore(), revokedTokens, AuthServiceConfig{RefreshTTL: time.Hour})
+		require.NoError(t, err)
+
+		userRepo.EXPECT().GetUserByLogin(mock.Anything, "testuser").
+			Return(&domain.User{ID: 7, Login: "testuser", PasswordHash: "hashed_password"}, nil).Once()
+		hasher.EXPECT().Check("hashed_password", "password123").Return(nil).Once()
+		tokens, err := svc.Login(ctx, "testuser", "password123", "phone", false)
+		require.NoError(t, err)
+		tokens, err = svc.Refresh(ctx, tokens.RefreshToken, "phone")
+		require.NoError(t, err)
+
+		revokedTokens.EXPECT().RevokeToken(mock.Anything, int64(7), mock.Anything, mock.Anything).Return(nil).Once()
+		require.NoError(t, svc.Logout(ctx, tokens.AccessToken))
+
+		_, err = svc.Refresh(ctx, tokens.RefreshToken, "phone")
+		assert.ErrorIs(t, err, domain.ErrInvalidToken)
+	})
+
+	t.Run("Impersonation logout keeps refresh tokens of the user", func(t *testing.T) {
+		revokedTokens := domainmocks.NewRevokedTokenRepositoryMock(t)
+		refreshTokens := domainmocks.NewRefreshTokenRepositoryMock(t)
+		svc, err := NewAuthService(domainmocks.NewUserRepositoryMock(t), nil, jwtManager, nil, refreshTokens, revokedTokens, AuthServiceConfig{RefreshTTL: time.Hour})
+		require.NoError(t, err)
+		impersonation, err := jwtManager.GenerateImpersonation(7, 1, time.Hour)
+		require.NoError(t, err)
+
+		revokedTokens.EXPECT().RevokeToken(mock.Anything, int64(7), mock.Anything, mock.Anything).Return(nil).Once()
+		require.NoError(t, svc.Logout(ctx, impersonation))
+	})
+
+	t.Run("Refresh token revocation error", func(t *testing.T) {
+		revokedTokens := domainmocks.NewRevokedTokenRepositoryMock(t)
+		refreshTokens := domainmocks.NewRefreshTokenRepositoryMock(t)
+		svc, err := NewAuthService(domainmocks.NewUserRepositoryMock(t), nil, jwtManager, nil, refreshTokens, revokedTokens, AuthServiceConfig{RefreshTTL: time.Hour})
+		require.NoError(t, err)
+
+		revokedTokens.EXPECT().RevokeToken(mock.Anything, int64(7), mock.Anything, mock.Anything).Return(nil).Once()
+		refreshTokens.EXPECT().RevokeRefreshTokens(mock.Anything, int64(7)).Return(errors.New("connection refused")).Once()
+		assert.Error(t, svc.Logout(ctx, token))
+	})
+
+	t.Run("Invalid token", func(t *testing.T) {
+		svc, _ := newService(t)
+
+		assert.ErrorIs(t, svc.Logout(ctx, "invalid.token"), domain.ErrInvalidToken)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		svc, err := NewAuthService(domainmocks.NewUserRepositoryMock(t), nil, jwtManager, nil, nil, nil, AuthServiceConfig{})
+		require.NoError(t, err)
+
+		err = svc.Logout(ctx, token)
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, domain.ErrInvalidToken)
+	})
+}
@@ -4,72 +4,330 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/avc/loyalty-system-diploma/internal/domain"
-	"github.com/avc/loyalty-system-diploma/internal/repository/postgres"
-	"github.com/avc/loyalty-system-diploma/internal/utils/luhn"
+	"github.com/avc/loyalty-system-diploma/internal/logctx"
+	"github.com/avc/loyalty-system-diploma/internal/tracing"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
 )
 
 // TransactionRepository определяет методы для работы с транзакциями.
 type TransactionRepository interface {
 	CreateTransaction(ctx context.Context, userID int64, orderNumber string, amount float64, txType domain.TransactionType) error
 	GetBalance(ctx context.Context, userID int64) (*domain.Balance, error)
-	GetWithdrawals(ctx context.Context, userID int64) ([]*domain.Transaction, error)
-	WithdrawWithLock(ctx context.Context, userID int64, orderNumber string, amount float64) error
+	GetWithdrawals(ctx context.Context, userID int64, after *domain.WithdrawalCursor, limit int) ([]*domain.Transaction, error)
+	GetTransactions(ctx context.Context, userID int64) ([]*domain.Transaction, error)
+	GetWithdrawalSummary(ctx context.Context, userID int64) (*domain.WithdrawalSummary, error)
+	GetWithdrawalByPublicID(ctx context.Context, userID int64, publicID uuid.UUID) (*domain.Transaction, error)
+	WithdrawWithLock(ctx context.Context, userID int64, orderNumber string, amount float64, partner string, limits domain.WithdrawalLimits) error
+	VerifyLedgerChain(ctx context.Context) (*domain.LedgerVerification, error)
 }
 
+// WithdrawalLimitRepository определяет методы для работы с ограничениями списаний,
+// заданными администратором.
+type WithdrawalLimitRepository interface {
+	GetWithdrawalLimits(ctx context.Context, userID int64) (*domain.WithdrawalLimits, error)
+	GetWithdrawalLimitsByLogin(ctx context.Context, login string) (*domain.WithdrawalLimits, error)
+	SetWithdrawalLimits(ctx context.Context, login string, limits domain.WithdrawalLimits) error
+	DeleteWithdrawalLimits(ctx context.Context, login string) error
+}
+
+// Размер страницы истории списаний
+const (
+	defaultWithdrawalsLimit = 50
+	maxWithdrawalsLimit     = 100
+)
+
+// maxWithdrawalLimit соответствует максимуму колонок лимитов DECIMAL(10,2)
+const maxWithdrawalLimit = 99_999_999.99
+
 // BalanceService предоставляет операции с балансом.
 type BalanceService struct {
 	transactionRepo TransactionRepository
+	orderRepo       OrderRepository
+	limitRepo       WithdrawalLimitRepository
+	numberLimits    OrderNumberLimits
+	defaultLimits   domain.WithdrawalLimits
+	balances        singleflight.Group // Одновременные запросы баланса по пользователю
 }
 
-// NewBalanceService создает новый BalanceService
-func NewBalanceService(transactionRepo TransactionRepository) *BalanceService {
+// NewBalanceService создает новый BalanceService.
+// defaultLimits действуют для пользователей без ограничений, заданных администратором.
+func NewBalanceService(
+	transactionRepo TransactionRepository,
+	orderRepo OrderRepository,
+	limitRepo WithdrawalLimitRepository,
+	numberLimits OrderNumberLimits,
+	defaultLimits domain.WithdrawalLimits,
+) *BalanceService {
 	return &BalanceService{
 		transactionRepo: transactionRepo,
+		orderRepo:       orderRepo,
+		limitRepo:       limitRepo,
+		numberLimits:    numberLimits.normalize(),
+		defaultLimits:   defaultLimits,
 	}
 }
 
-// GetBalance получает баланс пользователя
+// GetBalance получает баланс пользователя. Одновременные запросы баланса одного
+// пользователя выполняются одним запросом к БД
 func (s *BalanceService) GetBalance(ctx context.Context, userID int64) (*domain.Balance, error) {
-	balance, err := s.transactionRepo.GetBalance(ctx, userID)
+	balance, err := coalesce(ctx, &s.balances, balanceKey(userID), func(ctx context.Context) (*domain.Balance, error) {
+		return s.transactionRepo.GetBalance(ctx, userID)
+	})
 	if err != nil {
+		logctx.From(ctx).Error("balance service: failed to get balance", zap.Error(err))
 		return nil, fmt.Errorf("balance service: failed to get balance for user %d: %w", userID, err)
 	}
 
-	return balance, nil
+	// Баланс общий для всех ожидавших, каждый получает свою копию
+	result := *balance
+	return &result, nil
 }
 
-// Withdraw списывает средства со счета пользователя
-func (s *BalanceService) Withdraw(ctx context.Context, userID int64, orderNumber string, amount float64) error {
-	// Валидация номера заказа по алгоритму Луна
-	if !luhn.Validate(orderNumber) {
-		return ErrInvalidOrderNumber
+// balanceKey - ключ объединения запросов баланса пользователя
+func balanceKey(userID int64) string {
+	return strconv.FormatInt(userID, 10)
+}
+
+// GetBalanceDetails получает баланс пользователя вместе с ожидаемыми начислениями
+func (s *BalanceService) GetBalanceDetails(ctx context.Context, userID int64) (*domain.BalanceDetails, error) {
+	balance, err := s.GetBalance(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	pending, err := s.orderRepo.GetPendingAccrual(ctx, userID)
+	if err != nil {
+		logctx.From(ctx).Error("balance service: failed to get pending accrual", zap.Error(err))
+		return nil, fmt.Errorf("balance service: failed to get pending accrual for user %d: %w", userID, err)
+	}
+
+	return &domain.BalanceDetails{Balance: *balance, Pending: *pending}, nil
+}
+
+// Withdraw списывает средства со счета пользователя. partner - необязательный идентификатор
+// партнера, у которого потрачены баллы; по нему группируются выгрузки для взаиморасчетов
+func (s *BalanceService) Withdraw(ctx context.Context, userID int64, orderNumber string, amount float64, partner string) error {
+	ctx, span := tracing.Start(ctx, "BalanceService.Withdraw", attribute.Int64("user.id", userID))
+	defer span.End()
+
+	// Валидация длины и контрольной цифры номера заказа по правилам партнера
+	if err := s.numberLimits.validate(orderNumber, strings.TrimSpace(partner)); err != nil {
+		return err
 	}
 
 	// Валидация суммы
-	if amount <= 0 {
-		return fmt.Errorf("balance service: invalid withdrawal amount: %f", amount)
+	if err := domain.ValidateAmount(amount); err != nil {
+		return fmt.Errorf("balance service: %w", err)
+	}
+
+	partner = strings.TrimSpace(partner)
+	if len(partner) > domain.MaxPartnerLength {
+		return fmt.Errorf("balance service: partner is longer than %d bytes: %w", domain.MaxPartnerLength, domain.ErrInvalidPartner)
+	}
+
+	limits, err := s.userLimits(ctx, userID)
+	if err != nil {
+		return err
 	}
 
-	// Списание средств с блокировкой
-	err := s.transactionRepo.WithdrawWithLock(ctx, userID, orderNumber, amount)
+	// Сумма больше любого из лимитов отклоняется без обращения к истории списаний,
+	// суточный и месячный лимиты с учетом прежних списаний проверяются под блокировкой
+	if err := limits.Check(amount, 0, 0); err != nil {
+		return fmt.Errorf("balance service: withdrawal of %.2f for user %d rejected: %w", amount, userID, err)
+	}
+
+	// Списание средств с блокировкой строки баланса: условное обновление в транзакции БД
+	// атомарно проверяет остаток, поэтому отдельная блокировка пользователя не нужна
+	err = s.transactionRepo.WithdrawWithLock(ctx, userID, orderNumber, amount, partner, limits)
 	if err != nil {
-		if errors.Is(err, postgres.ErrInsufficientFunds) {
-			return fmt.Errorf("balance service: insufficient funds for user %d: %w", userID, ErrInsufficientFunds)
+		if errors.Is(err, domain.ErrInsufficientFunds) {
+			return fmt.Errorf("balance service: insufficient funds for user %d: %w", userID, err)
+		}
+		if isWithdrawalLimitError(err) {
+			return fmt.Errorf("balance service: withdrawal of %.2f for user %d rejected: %w", amount, userID, err)
 		}
+		tracing.Fail(span, err)
+		logctx.From(ctx).Error("balance service: failed to withdraw",
+			zap.String("order", orderNumber),
+			zap.Float64("sum", amount),
+			zap.Error(err),
+		)
 		return fmt.Errorf("balance service: failed to withdraw %f for user %d: %w", amount, userID, err)
 	}
 
+	// Запрос баланса, начатый до списания, не должен достаться тем, кто спросит после
+	s.balances.Forget(balanceKey(userID))
+
+	logctx.From(ctx).Debug("withdrawal completed", zap.String("order", orderNumber), zap.Float64("sum", amount))
 	return nil
 }
 
-// GetWithdrawals получает историю списаний пользователя
-func (s *BalanceService) GetWithdrawals(ctx context.Context, userID int64) ([]*domain.Transaction, error) {
-	withdrawals, err := s.transactionRepo.GetWithdrawals(ctx, userID)
+// GetWithdrawals получает страницу истории списаний пользователя после позиции after
+// (nil - первая страница). Нулевой limit означает размер страницы по умолчанию
+func (s *BalanceService) GetWithdrawals(ctx context.Context, userID int64, after *domain.WithdrawalCursor, limit int) (*domain.WithdrawalPage, error) {
+	ctx, span := tracing.Start(ctx, "BalanceService.GetWithdrawals", attribute.Int64("user.id", userID))
+	defer span.End()
+
+	if limit < 0 {
+		return nil, fmt.Errorf("balance service: negative withdrawals limit: %w", domain.ErrInvalidInput)
+	}
+	if limit == 0 {
+		limit = defaultWithdrawalsLimit
+	}
+	limit = min(limit, maxWithdrawalsLimit)
+
+	// Лишнее списание показывает, что за страницей есть продолжение
+	withdrawals, err := s.transactionRepo.GetWithdrawals(ctx, userID, after, limit+1)
 	if err != nil {
+		tracing.Fail(span, err)
+		logctx.From(ctx).Error("balance service: failed to get withdrawals", zap.Error(err))
 		return nil, fmt.Errorf("balance service: failed to get withdrawals for user %d: %w", userID, err)
 	}
 
-	return withdrawals, nil
+	page := &domain.WithdrawalPage{Withdrawals: withdrawals}
+	if len(withdrawals) > limit {
+		page.Withdrawals = withdrawals[:limit]
+		last := page.Withdrawals[limit-1]
+		page.Next = &domain.WithdrawalCursor{ProcessedAt: last.ProcessedAt, ID: last.ID}
+	}
+	return page, nil
+}
+
+// GetWithdrawalSummary получает сводку по списаниям пользователя
+func (s *BalanceService) GetWithdrawalSummary(ctx context.Context, userID int64) (*domain.WithdrawalSummary, error) {
+	summary, err := s.transactionRepo.GetWithdrawalSummary(ctx, userID)
+	if err != nil {
+		logctx.From(ctx).Error("balance service: failed to get withdrawal summary", zap.Error(err))
+		return nil, fmt.Errorf("balance service: failed to get withdrawal summary for user %d: %w", userID, err)
+	}
+
+	return summary, nil
+}
+
+// GetWithdrawal получает списание пользователя по публичному идентификатору
+func (s *BalanceService) GetWithdrawal(ctx context.Context, userID int64, publicID uuid.UUID) (*domain.Transaction, error) {
+	withdrawal, err := s.transactionRepo.GetWithdrawalByPublicID(ctx, userID, publicID)
+	if err != nil {
+		if errors.Is(err, domain.ErrTransactionNotFound) {
+			return nil, fmt.Errorf("balance service: withdrawal %s not found: %w", publicID, err)
+		}
+		logctx.From(ctx).Error("balance service: failed to get withdrawal", zap.Error(err))
+		return nil, fmt.Errorf("balance service: failed to get withdrawal %s: %w", publicID, err)
+	}
+
+	return withdrawal, nil
+}
+
+// GetWithdrawalLimits возвращает действующие ограничения списаний пользователя
+func (s *BalanceService) GetWithdrawalLimits(ctx context.Context, login string) (*domain.UserWithdrawalLimits, error) {
+	limits, err := s.limitRepo.GetWithdrawalLimitsByLogin(ctx, login)
+	switch {
+	case errors.Is(err, domain.ErrWithdrawalLimitsNotSet):
+		return &domain.UserWithdrawalLimits{Login: login, Limits: s.defaultLimits}, nil
+	case errors.Is(err, domain.ErrUserNotFound):
+		return nil, fmt.Errorf("balance service: user %q not found: %w", login, err)
+	case err != nil:
+		logctx.From(ctx).Error("balance service: failed to get withdrawal limits", zap.Error(err))
+		return nil, fmt.Errorf("balance service: failed to get withdrawal limits for %q: %w", login, err)
+	}
+
+	return &domain.UserWithdrawalLimits{Login: login, Limits: *limits, Override: true}, nil
+}
+
+// SetWithdrawalLimits задает пользователю собственные ограничения списаний
+// вместо значений по умолчанию
+func (s *BalanceService) SetWithdrawalLimits(ctx context.Context, login string, limits domain.WithdrawalLimits) (*domain.UserWithdrawalLimits, error) {
+	for _, limit := range []float64{limits.PerWithdrawal, limits.Daily, limits.Monthly} {
+		if limit < 0 || limit > maxWithdrawalLimit {
+			return nil, fmt.Errorf("balance service: invalid withdrawal limit %.2f: %w", limit, domain.ErrInvalidInput)
+		}
+	}
+
+	if err := s.limitRepo.SetWithdrawalLimits(ctx, login, limits); err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil, fmt.Errorf("balance service: user %q not found: %w", login, err)
+		}
+		logctx.From(ctx).Error("balance service: failed to set withdrawal limits", zap.Error(err))
+		return nil, fmt.Errorf("balance service: failed to set withdrawal limits for %q: %w", login, err)
+	}
+
+	logctx.From(ctx).Info("withdrawal limits overridden",
+		zap.String("login", login),
+		zap.Float64("per_withdrawal", limits.PerWithdrawal),
+		zap.Float64("daily", limits.Daily),
+		zap.Float64("monthly", limits.Monthly),
+	)
+	return &domain.UserWithdrawalLimits{Login: login, Limits: limits, Override: true}, nil
+}
+
+// ResetWithdrawalLimits возвращает пользователю ограничения по умолчанию
+func (s *BalanceService) ResetWithdrawalLimits(ctx context.Context, login string) error {
+	if err := s.limitRepo.DeleteWithdrawalLimits(ctx, login); err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return fmt.Errorf("balance service: user %q not found: %w", login, err)
+		}
+		logctx.From(ctx).Error("balance service: failed to reset withdrawal limits", zap.Error(err))
+		return fmt.Errorf("balance service: failed to reset withdrawal limits for %q: %w", login, err)
+	}
+
+	logctx.From(ctx).Info("withdrawal limits reset to defaults", zap.String("login", login))
+	return nil
+}
+
+// VerifyLedger проверяет цепочку хешей журнала транзакций.
+// Нарушение цепочки не считается ошибкой проверки: оно возвращается в результате и логируется.
+func (s *BalanceService) VerifyLedger(ctx context.Context) (*domain.LedgerVerification, error) {
+	result, err := s.transactionRepo.VerifyLedgerChain(ctx)
+	if err != nil {
+		logctx.From(ctx).Error("balance service: failed to verify ledger chain", zap.Error(err))
+		return nil, fmt.Errorf("balance service: failed to verify ledger chain: %w", err)
+	}
+
+	fields := []zap.Field{
+		zap.Int64("checked", result.Checked),
+		zap.Int64("unchained", result.Unchained),
+		zap.Int64("chains", result.Chains),
+	}
+	if result.Break != nil {
+		fields = append(fields,
+			zap.Int64("break_user_id", result.Break.UserID),
+			zap.Int64("break_seq", result.Break.Seq),
+			zap.Stringer("break_id", result.Break.PublicID),
+			zap.String("break_reason", string(result.Break.Reason)),
+		)
+	}
+	if !result.IsIntact() {
+		logctx.From(ctx).Error("ledger chain integrity violated", fields...)
+	} else {
+		logctx.From(ctx).Info("ledger chain verified", fields...)
+	}
+
+	return result, nil
+}
+
+// userLimits возвращает ограничения пользователя: заданные администратором или по умолчанию
+func (s *BalanceService) userLimits(ctx context.Context, userID int64) (domain.WithdrawalLimits, error) {
+	limits, err := s.limitRepo.GetWithdrawalLimits(ctx, userID)
+	if errors.Is(err, domain.ErrWithdrawalLimitsNotSet) {
+		return s.defaultLimits, nil
+	}
+	if err != nil {
+		logctx.From(ctx).Error("balance service: failed to get withdrawal limits", zap.Error(err))
+		return domain.WithdrawalLimits{}, fmt.Errorf("balance service: failed to get withdrawal limits for user %d: %w", userID, err)
+	}
+	return *limits, nil
+}
+
+// isWithdrawalLimitError проверяет, что списание отклонено одним из ограничений
+func isWithdrawalLimitError(err error) bool {
+	return errors.Is(err, domain.ErrWithdrawalAmountLimit) ||
+		errors.Is(err, domain.ErrDailyWithdrawalLimit) ||
+		errors.Is(err, domain.ErrMonthlyWithdrawalLimit)
 }
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/avc/loyalty-system-diploma/internal/lock"
+	"go.uber.org/zap"
+)
+
+// benchWithdrawLatency - время транзакции списания в БД, которое имитирует benchRowLockRepo
+const benchWithdrawLatency = 2 * time.Millisecond
+
+// benchRowLockRepo имитирует WithdrawWithLock: строка баланса пользователя блокируется
+// на время транзакции, списания разных пользователей выполняются параллельно
+type benchRowLockRepo struct {
+	TransactionRepository
+	rows sync.Map // userID -> *sync.Mutex
+}
+
+func (r *benchRowLockRepo) WithdrawWithLock(_ context.Context, userID int64, _ string, _ float64, _ string, _ domain.WithdrawalLimits) error {
+	row, _ := r.rows.LoadOrStore(userID, &sync.Mutex{})
+	row.(*sync.Mutex).Lock()
+	defer row.(*sync.Mutex).Unlock()
+	time.Sleep(benchWithdrawLatency)
+	return nil
+}
+
+// benchNoLimits - пользователи без ограничений, заданных администратором
+type benchNoLimits struct {
+	WithdrawalLimitRepository
+}
+
+func (benchNoLimits) GetWithdrawalLimits(context.Context, int64) (*domain.WithdrawalLimits, error) {
+	return nil, domain.ErrWithdrawalLimitsNotSet
+}
+
+// benchLeaseBackend хранит блокировки internal/lock в памяти процесса
+type benchLeaseBackend struct {
+	mu     sync.Mutex
+	owners map[string]string
+}
+
+func (b *benchLeaseBackend) TryAcquire(_ context.Context, key, token string, _ time.Duration) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.owners[key]; ok {
+		return false, nil
+	}
+	b.owners[key] = token
+	return true, nil
+}
+
+func (b *benchLeaseBackend) Release(_ context.Context, key, token string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.owners[key] == token {
+		delete(b.owners, key)
+	}
+	return nil
+}
+
+// Списание через сервис: прежний путь с блокировкой пользователя internal/lock
+// перед транзакцией против одной блокировки строки баланса в транзакции.
+// Блокировка хранится в памяти, поэтому прежний путь измеряется без сетевых задержек Redis или БД.
+//
+//	go test ./internal/service -run '^$' -bench BalanceService_Withdraw -cpu 1,8,32
+func BenchmarkBalanceService_Withdraw(b *testing.B) {
+	implementations := []struct {
+		name     string
+		withdraw func(svc *BalanceService, locker Locker) func(ctx context.Context, userID int64) error
+	}{
+		{name: "UserLease", withdraw: func(svc *BalanceService, locker Locker) func(ctx context.Context, userID int64) error {
+			return func(ctx context.Context, userID int64) error {
+				unlock, err := lockKeys(ctx, locker, lock.UserKey(userID))
+				if err != nil {
+					return err
+				}
+				defer unlock()
+				return svc.Withdraw(ctx, userID, "79927398713", 0.01, "")
+			}
+		}},
+		{name: "BalanceRow", withdraw: func(svc *BalanceService, _ Locker) func(ctx context.Context, userID int64) error {
+			return func(ctx context.Context, userID int64) error {
+				return svc.Withdraw(ctx, userID, "79927398713", 0.01, "")
+			}
+		}},
+	}
+
+	// Один пользователь - списания конкурируют за его блокировку,
+	// много пользователей - каждая горутина списывает у своего
+	for _, users := range []int{1, 32} {
+		for _, impl := range implementations {
+			b.Run(fmt.Sprintf("%s/users=%d", impl.name, users), func(b *testing.B) {
+				svc := NewBalanceService(&benchRowLockRepo{}, nil, benchNoLimits{}, DefaultOrderNumberLimits(), domain.WithdrawalLimits{})
+				locker := lock.New(&benchLeaseBackend{owners: make(map[string]string)}, lock.Config{}, zap.NewNop())
+				withdraw := impl.withdraw(svc, locker)
+				var next atomic.Int64
+
+				b.ResetTimer()
+				b.RunParallel(func(pb *testing.PB) {
+					ctx := context.Background()
+					userID := next.Add(1) % int64(users)
+					for pb.Next() {
+						if err := withdraw(ctx, userID); err != nil {
+							b.Error(err)
+							return
+						}
+					}
+				})
+			})
+		}
+	}
+}
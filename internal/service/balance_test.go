@@ -3,12 +3,14 @@ package service
 import (
 	"context"
 	"errors"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/avc/loyalty-system-diploma/internal/domain"
 	domainmocks "github.com/avc/loyalty-system-diploma/internal/domain/mocks"
-	"github.com/avc/loyalty-system-diploma/internal/repository/postgres"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -18,10 +20,10 @@ func TestBalanceService_GetBalance(t *testing.T) {
 	ctx := context.Background()
 
 	tests := []struct {
-		name         string
-		userID       int64
-		setupMock    func(*domainmocks.TransactionRepositoryMock) *domain.Balance
-		wantErr      bool
+		name      string
+		userID    int64
+		setupMock func(*domainmocks.TransactionRepositoryMock) *domain.Balance
+		wantErr   bool
 	}{
 		{
 			name:   "Success",
@@ -46,7 +48,7 @@ func TestBalanceService_GetBalance(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockTxRepo := domainmocks.NewTransactionRepositoryMock(t)
-			svc := NewBalanceService(mockTxRepo)
+			svc := NewBalanceService(mockTxRepo, nil, nil, DefaultOrderNumberLimits(), domain.WithdrawalLimits{})
 
 			expectedBalance := tt.setupMock(mockTxRepo)
 
@@ -63,6 +65,54 @@ func TestBalanceService_GetBalance(t *testing.T) {
 	}
 }
 
+func TestBalanceService_GetBalanceDetails(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		txRepo := domainmocks.NewTransactionRepositoryMock(t)
+		orderRepo := domainmocks.NewOrderRepositoryMock(t)
+		svc := NewBalanceService(txRepo, orderRepo, nil, DefaultOrderNumberLimits(), domain.WithdrawalLimits{})
+
+		txRepo.EXPECT().GetBalance(mock.Anything, int64(1)).
+			Return(&domain.Balance{Current: 500, Withdrawn: 200}, nil).Once()
+		orderRepo.EXPECT().GetPendingAccrual(mock.Anything, int64(1)).
+			Return(&domain.PendingAccrual{Orders: 2, Accrual: 40}, nil).Once()
+
+		result, err := svc.GetBalanceDetails(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, &domain.BalanceDetails{
+			Balance: domain.Balance{Current: 500, Withdrawn: 200},
+			Pending: domain.PendingAccrual{Orders: 2, Accrual: 40},
+		}, result)
+	})
+
+	t.Run("Balance error", func(t *testing.T) {
+		txRepo := domainmocks.NewTransactionRepositoryMock(t)
+		orderRepo := domainmocks.NewOrderRepositoryMock(t)
+		svc := NewBalanceService(txRepo, orderRepo, nil, DefaultOrderNumberLimits(), domain.WithdrawalLimits{})
+
+		txRepo.EXPECT().GetBalance(mock.Anything, int64(1)).Return(nil, errors.New("db error")).Once()
+
+		result, err := svc.GetBalanceDetails(ctx, 1)
+		assert.Error(t, err)
+		assert.Nil(t, result)
+	})
+
+	t.Run("Pending accrual error", func(t *testing.T) {
+		txRepo := domainmocks.NewTransactionRepositoryMock(t)
+		orderRepo := domainmocks.NewOrderRepositoryMock(t)
+		svc := NewBalanceService(txRepo, orderRepo, nil, DefaultOrderNumberLimits(), domain.WithdrawalLimits{})
+
+		txRepo.EXPECT().GetBalance(mock.Anything, int64(1)).
+			Return(&domain.Balance{Current: 500, Withdrawn: 200}, nil).Once()
+		orderRepo.EXPECT().GetPendingAccrual(mock.Anything, int64(1)).Return(nil, errors.New("db error")).Once()
+
+		result, err := svc.GetBalanceDetails(ctx, 1)
+		assert.Error(t, err)
+		assert.Nil(t, result)
+	})
+}
+
 func TestBalanceService_Withdraw(t *testing.T) {
 	ctx := context.Background()
 
@@ -80,7 +130,7 @@ func TestBalanceService_Withdraw(t *testing.T) {
 			orderNumber: "79927398713", // Valid Luhn
 			amount:      100.0,
 			setupMock: func(m *domainmocks.TransactionRepositoryMock) {
-				m.EXPECT().WithdrawWithLock(mock.Anything, int64(1), "79927398713", 100.0).Return(nil).Once()
+				m.EXPECT().WithdrawWithLock(mock.Anything, int64(1), "79927398713", 100.0, "", domain.WithdrawalLimits{}).Return(nil).Once()
 			},
 		},
 		{
@@ -89,7 +139,7 @@ func TestBalanceService_Withdraw(t *testing.T) {
 			orderNumber: "12345", // Invalid Luhn
 			amount:      100.0,
 			setupMock:   func(m *domainmocks.TransactionRepositoryMock) {},
-			wantErr:     ErrInvalidOrderNumber,
+			wantErr:     domain.ErrInvalidOrderNumber,
 		},
 		{
 			name:        "Invalid amount - zero",
@@ -97,7 +147,7 @@ func TestBalanceService_Withdraw(t *testing.T) {
 			orderNumber: "79927398713",
 			amount:      0.0,
 			setupMock:   func(m *domainmocks.TransactionRepositoryMock) {},
-			wantErr:     nil, // Generic error
+			wantErr:     domain.ErrInvalidAmount,
 		},
 		{
 			name:        "Invalid amount - negative",
@@ -105,7 +155,23 @@ func TestBalanceService_Withdraw(t *testing.T) {
 			orderNumber: "79927398713",
 			amount:      -100.0,
 			setupMock:   func(m *domainmocks.TransactionRepositoryMock) {},
-			wantErr:     nil, // Generic error
+			wantErr:     domain.ErrInvalidAmount,
+		},
+		{
+			name:        "Invalid amount - less than a cent",
+			userID:      1,
+			orderNumber: "79927398713",
+			amount:      0.001,
+			setupMock:   func(m *domainmocks.TransactionRepositoryMock) {},
+			wantErr:     domain.ErrInvalidAmount,
+		},
+		{
+			name:        "Invalid amount - exceeds column precision",
+			userID:      1,
+			orderNumber: "79927398713",
+			amount:      1e10,
+			setupMock:   func(m *domainmocks.TransactionRepositoryMock) {},
+			wantErr:     domain.ErrInvalidAmount,
 		},
 		{
 			name:        "Insufficient funds",
@@ -113,9 +179,9 @@ func TestBalanceService_Withdraw(t *testing.T) {
 			orderNumber: "79927398713",
 			amount:      1000.0,
 			setupMock: func(m *domainmocks.TransactionRepositoryMock) {
-				m.EXPECT().WithdrawWithLock(mock.Anything, int64(1), "79927398713", 1000.0).Return(postgres.ErrInsufficientFunds).Once()
+				m.EXPECT().WithdrawWithLock(mock.Anything, int64(1), "79927398713", 1000.0, "", domain.WithdrawalLimits{}).Return(domain.ErrInsufficientFunds).Once()
 			},
-			wantErr: ErrInsufficientFunds,
+			wantErr: domain.ErrInsufficientFunds,
 		},
 		{
 			name:        "Database error",
@@ -123,7 +189,7 @@ func TestBalanceService_Withdraw(t *testing.T) {
 			orderNumber: "79927398713",
 			amount:      100.0,
 			setupMock: func(m *domainmocks.TransactionRepositoryMock) {
-				m.EXPECT().WithdrawWithLock(mock.Anything, int64(1), "79927398713", 100.0).Return(errors.New("db error")).Once()
+				m.EXPECT().WithdrawWithLock(mock.Anything, int64(1), "79927398713", 100.0, "", domain.WithdrawalLimits{}).Return(errors.New("db error")).Once()
 			},
 			wantErr: nil, // Generic error
 		},
@@ -132,15 +198,18 @@ func TestBalanceService_Withdraw(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockTxRepo := domainmocks.NewTransactionRepositoryMock(t)
-			svc := NewBalanceService(mockTxRepo)
+			mockLimitRepo := domainmocks.NewWithdrawalLimitRepositoryMock(t)
+			svc := NewBalanceService(mockTxRepo, nil, mockLimitRepo, DefaultOrderNumberLimits(), domain.WithdrawalLimits{})
 
+			mockLimitRepo.EXPECT().GetWithdrawalLimits(mock.Anything, tt.userID).
+				Return(nil, domain.ErrWithdrawalLimitsNotSet).Maybe()
 			tt.setupMock(mockTxRepo)
 
-			err := svc.Withdraw(ctx, tt.userID, tt.orderNumber, tt.amount)
+			err := svc.Withdraw(ctx, tt.userID, tt.orderNumber, tt.amount, "")
 
 			if tt.wantErr != nil {
 				assert.ErrorIs(t, err, tt.wantErr)
-			} else if tt.name == "Invalid amount - zero" || tt.name == "Invalid amount - negative" || tt.name == "Database error" {
+			} else if tt.name == "Database error" {
 				assert.Error(t, err)
 			} else {
 				require.NoError(t, err)
@@ -149,6 +218,162 @@ func TestBalanceService_Withdraw(t *testing.T) {
 	}
 }
 
+func TestBalanceService_Withdraw_Limits(t *testing.T) {
+	ctx := context.Background()
+	defaults := domain.WithdrawalLimits{PerWithdrawal: 500, Daily: 1000}
+	override := domain.WithdrawalLimits{PerWithdrawal: 5000}
+
+	t.Run("Defaults passed to repository", func(t *testing.T) {
+		txRepo := domainmocks.NewTransactionRepositoryMock(t)
+		limitRepo := domainmocks.NewWithdrawalLimitRepositoryMock(t)
+		svc := NewBalanceService(txRepo, nil, limitRepo, DefaultOrderNumberLimits(), defaults)
+
+		limitRepo.EXPECT().GetWithdrawalLimits(mock.Anything, int64(1)).Return(nil, domain.ErrWithdrawalLimitsNotSet).Once()
+		txRepo.EXPECT().WithdrawWithLock(mock.Anything, int64(1), "79927398713", 100.0, "", defaults).Return(nil).Once()
+
+		require.NoError(t, svc.Withdraw(ctx, 1, "79927398713", 100, ""))
+	})
+
+	t.Run("Override replaces defaults", func(t *testing.T) {
+		txRepo := domainmocks.NewTransactionRepositoryMock(t)
+		limitRepo := domainmocks.NewWithdrawalLimitRepositoryMock(t)
+		svc := NewBalanceService(txRepo, nil, limitRepo, DefaultOrderNumberLimits(), defaults)
+
+		limitRepo.EXPECT().GetWithdrawalLimits(mock.Anything, int64(1)).Return(&override, nil).Once()
+		txRepo.EXPECT().WithdrawWithLock(mock.Anything, int64(1), "79927398713", 2000.0, "", override).Return(nil).Once()
+
+		require.NoError(t, svc.Withdraw(ctx, 1, "79927398713", 2000, ""))
+	})
+
+	t.Run("Amount above limit rejected without locking", func(t *testing.T) {
+		txRepo := domainmocks.NewTransactionRepositoryMock(t)
+		limitRepo := domainmocks.NewWithdrawalLimitRepositoryMock(t)
+		svc := NewBalanceService(txRepo, nil, limitRepo, DefaultOrderNumberLimits(), defaults)
+
+		limitRepo.EXPECT().GetWithdrawalLimits(mock.Anything, int64(1)).Return(nil, domain.ErrWithdrawalLimitsNotSet).Once()
+
+		err := svc.Withdraw(ctx, 1, "79927398713", 600, "")
+		assert.ErrorIs(t, err, domain.ErrWithdrawalAmountLimit)
+	})
+
+	t.Run("Daily limit reported by repository", func(t *testing.T) {
+		txRepo := domainmocks.NewTransactionRepositoryMock(t)
+		limitRepo := domainmocks.NewWithdrawalLimitRepositoryMock(t)
+		svc := NewBalanceService(txRepo, nil, limitRepo, DefaultOrderNumberLimits(), defaults)
+
+		limitRepo.EXPECT().GetWithdrawalLimits(mock.Anything, int64(1)).Return(nil, domain.ErrWithdrawalLimitsNotSet).Once()
+		txRepo.EXPECT().WithdrawWithLock(mock.Anything, int64(1), "79927398713", 100.0, "", defaults).
+			Return(domain.ErrDailyWithdrawalLimit).Once()
+
+		err := svc.Withdraw(ctx, 1, "79927398713", 100, "")
+		assert.ErrorIs(t, err, domain.ErrDailyWithdrawalLimit)
+	})
+
+	t.Run("Limits lookup error", func(t *testing.T) {
+		txRepo := domainmocks.NewTransactionRepositoryMock(t)
+		limitRepo := domainmocks.NewWithdrawalLimitRepositoryMock(t)
+		svc := NewBalanceService(txRepo, nil, limitRepo, DefaultOrderNumberLimits(), defaults)
+
+		limitRepo.EXPECT().GetWithdrawalLimits(mock.Anything, int64(1)).Return(nil, errors.New("db error")).Once()
+
+		assert.Error(t, svc.Withdraw(ctx, 1, "79927398713", 100, ""))
+	})
+}
+
+func TestBalanceService_Withdraw_Partner(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Partner trimmed", func(t *testing.T) {
+		txRepo := domainmocks.NewTransactionRepositoryMock(t)
+		limitRepo := domainmocks.NewWithdrawalLimitRepositoryMock(t)
+		svc := NewBalanceService(txRepo, nil, limitRepo, DefaultOrderNumberLimits(), domain.WithdrawalLimits{})
+
+		limitRepo.EXPECT().GetWithdrawalLimits(mock.Anything, int64(1)).Return(nil, domain.ErrWithdrawalLimitsNotSet).Once()
+		txRepo.EXPECT().WithdrawWithLock(mock.Anything, int64(1), "79927398713", 100.0, "store-42", domain.WithdrawalLimits{}).Return(nil).Once()
+
+		require.NoError(t, svc.Withdraw(ctx, 1, "79927398713", 100, " store-42 "))
+	})
+
+	t.Run("Partner too long", func(t *testing.T) {
+		svc := NewBalanceService(domainmocks.NewTransactionRepositoryMock(t), nil, domainmocks.NewWithdrawalLimitRepositoryMock(t),
+			DefaultOrderNumberLimits(), domain.WithdrawalLimits{})
+
+		err := svc.Withdraw(ctx, 1, "79927398713", 100, strings.Repeat("p", domain.MaxPartnerLength+1))
+		assert.ErrorIs(t, err, domain.ErrInvalidPartner)
+	})
+}
+
+func TestBalanceService_WithdrawalLimitsAdmin(t *testing.T) {
+	ctx := context.Background()
+	defaults := domain.WithdrawalLimits{Daily: 1000}
+	override := domain.WithdrawalLimits{PerWithdrawal: 500, Daily: 2000, Monthly: 10000}
+
+	t.Run("Get defaults", func(t *testing.T) {
+		limitRepo := domainmocks.NewWithdrawalLimitRepositoryMock(t)
+		svc := NewBalanceService(nil, nil, limitRepo, DefaultOrderNumberLimits(), defaults)
+
+		limitRepo.EXPECT().GetWithdrawalLimitsByLogin(mock.Anything, "alice").Return(nil, domain.ErrWithdrawalLimitsNotSet).Once()
+
+		limits, err := svc.GetWithdrawalLimits(ctx, "alice")
+		require.NoError(t, err)
+		assert.Equal(t, &domain.UserWithdrawalLimits{Login: "alice", Limits: defaults}, limits)
+	})
+
+	t.Run("Get override", func(t *testing.T) {
+		limitRepo := domainmocks.NewWithdrawalLimitRepositoryMock(t)
+		svc := NewBalanceService(nil, nil, limitRepo, DefaultOrderNumberLimits(), defaults)
+
+		limitRepo.EXPECT().GetWithdrawalLimitsByLogin(mock.Anything, "alice").Return(&override, nil).Once()
+
+		limits, err := svc.GetWithdrawalLimits(ctx, "alice")
+		require.NoError(t, err)
+		assert.Equal(t, &domain.UserWithdrawalLimits{Login: "alice", Limits: override, Override: true}, limits)
+	})
+
+	t.Run("Get unknown user", func(t *testing.T) {
+		limitRepo := domainmocks.NewWithdrawalLimitRepositoryMock(t)
+		svc := NewBalanceService(nil, nil, limitRepo, DefaultOrderNumberLimits(), defaults)
+
+		limitRepo.EXPECT().GetWithdrawalLimitsByLogin(mock.Anything, "nobody").Return(nil, domain.ErrUserNotFound).Once()
+
+		_, err := svc.GetWithdrawalLimits(ctx, "nobody")
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	})
+
+	t.Run("Set", func(t *testing.T) {
+		limitRepo := domainmocks.NewWithdrawalLimitRepositoryMock(t)
+		svc := NewBalanceService(nil, nil, limitRepo, DefaultOrderNumberLimits(), defaults)
+
+		limitRepo.EXPECT().SetWithdrawalLimits(mock.Anything, "alice", override).Return(nil).Once()
+
+		limits, err := svc.SetWithdrawalLimits(ctx, "alice", override)
+		require.NoError(t, err)
+		assert.True(t, limits.Override)
+		assert.Equal(t, override, limits.Limits)
+	})
+
+	t.Run("Set invalid values", func(t *testing.T) {
+		svc := NewBalanceService(nil, nil, domainmocks.NewWithdrawalLimitRepositoryMock(t), DefaultOrderNumberLimits(), defaults)
+
+		_, err := svc.SetWithdrawalLimits(ctx, "alice", domain.WithdrawalLimits{Daily: -1})
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+
+		_, err = svc.SetWithdrawalLimits(ctx, "alice", domain.WithdrawalLimits{Monthly: 1e9})
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	})
+
+	t.Run("Reset", func(t *testing.T) {
+		limitRepo := domainmocks.NewWithdrawalLimitRepositoryMock(t)
+		svc := NewBalanceService(nil, nil, limitRepo, DefaultOrderNumberLimits(), defaults)
+
+		limitRepo.EXPECT().DeleteWithdrawalLimits(mock.Anything, "alice").Return(nil).Once()
+		limitRepo.EXPECT().DeleteWithdrawalLimits(mock.Anything, "nobody").Return(domain.ErrUserNotFound).Once()
+
+		assert.NoError(t, svc.ResetWithdrawalLimits(ctx, "alice"))
+		assert.ErrorIs(t, svc.ResetWithdrawalLimits(ctx, "nobody"), domain.ErrUserNotFound)
+	})
+}
+
 func TestBalanceService_GetWithdrawals(t *testing.T) {
 	ctx := context.Background()
 
@@ -167,7 +392,7 @@ func TestBalanceService_GetWithdrawals(t *testing.T) {
 					{ID: 1, UserID: 1, OrderNumber: "111", Amount: 100.0, Type: domain.TransactionTypeWithdrawal, ProcessedAt: time.Now()},
 					{ID: 2, UserID: 1, OrderNumber: "222", Amount: 50.0, Type: domain.TransactionTypeWithdrawal, ProcessedAt: time.Now()},
 				}
-				m.EXPECT().GetWithdrawals(mock.Anything, int64(1)).Return(withdrawals, nil).Once()
+				m.EXPECT().GetWithdrawals(mock.Anything, int64(1), (*domain.WithdrawalCursor)(nil), defaultWithdrawalsLimit+1).Return(withdrawals, nil).Once()
 				return withdrawals
 			},
 			wantWithdrawals: 2,
@@ -176,7 +401,7 @@ func TestBalanceService_GetWithdrawals(t *testing.T) {
 			name:   "No withdrawals",
 			userID: 999,
 			setupMock: func(m *domainmocks.TransactionRepositoryMock) []*domain.Transaction {
-				m.EXPECT().GetWithdrawals(mock.Anything, int64(999)).Return([]*domain.Transaction{}, nil).Once()
+				m.EXPECT().GetWithdrawals(mock.Anything, int64(999), (*domain.WithdrawalCursor)(nil), defaultWithdrawalsLimit+1).Return([]*domain.Transaction{}, nil).Once()
 				return nil
 			},
 			wantWithdrawals: 0,
@@ -185,7 +410,7 @@ func TestBalanceService_GetWithdrawals(t *testing.T) {
 			name:   "Database error",
 			userID: 1,
 			setupMock: func(m *domainmocks.TransactionRepositoryMock) []*domain.Transaction {
-				m.EXPECT().GetWithdrawals(mock.Anything, int64(1)).Return(nil, errors.New("db error")).Once()
+				m.EXPECT().GetWithdrawals(mock.Anything, int64(1), (*domain.WithdrawalCursor)(nil), defaultWithdrawalsLimit+1).Return(nil, errors.New("db error")).Once()
 				return nil
 			},
 			wantErr: true,
@@ -195,22 +420,187 @@ func TestBalanceService_GetWithdrawals(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockTxRepo := domainmocks.NewTransactionRepositoryMock(t)
-			svc := NewBalanceService(mockTxRepo)
+			svc := NewBalanceService(mockTxRepo, nil, nil, DefaultOrderNumberLimits(), domain.WithdrawalLimits{})
 
 			expectedWithdrawals := tt.setupMock(mockTxRepo)
 
-			result, err := svc.GetWithdrawals(ctx, tt.userID)
+			result, err := svc.GetWithdrawals(ctx, tt.userID, nil, 0)
 
 			if tt.wantErr {
 				assert.Error(t, err)
 				assert.Nil(t, result)
 			} else {
 				require.NoError(t, err)
-				assert.Len(t, result, tt.wantWithdrawals)
+				assert.Len(t, result.Withdrawals, tt.wantWithdrawals)
+				assert.Nil(t, result.Next)
 				if expectedWithdrawals != nil {
-					assert.Equal(t, expectedWithdrawals, result)
+					assert.Equal(t, expectedWithdrawals, result.Withdrawals)
 				}
 			}
 		})
 	}
 }
+
+func TestBalanceService_GetWithdrawals_Pages(t *testing.T) {
+	ctx := context.Background()
+	processedAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	withdrawals := []*domain.Transaction{
+		{ID: 3, ProcessedAt: processedAt},
+		{ID: 2, ProcessedAt: processedAt},
+		{ID: 1, ProcessedAt: processedAt.Add(-time.Hour)},
+	}
+
+	t.Run("Page with continuation", func(t *testing.T) {
+		mockTxRepo := domainmocks.NewTransactionRepositoryMock(t)
+		svc := NewBalanceService(mockTxRepo, nil, nil, DefaultOrderNumberLimits(), domain.WithdrawalLimits{})
+		after := &domain.WithdrawalCursor{ProcessedAt: processedAt, ID: 4}
+		mockTxRepo.EXPECT().GetWithdrawals(mock.Anything, int64(1), after, 3).Return(withdrawals, nil).Once()
+
+		page, err := svc.GetWithdrawals(ctx, 1, after, 2)
+
+		require.NoError(t, err)
+		assert.Equal(t, withdrawals[:2], page.Withdrawals)
+		assert.Equal(t, &domain.WithdrawalCursor{ProcessedAt: processedAt, ID: 2}, page.Next)
+	})
+
+	t.Run("Limit is capped", func(t *testing.T) {
+		mockTxRepo := domainmocks.NewTransactionRepositoryMock(t)
+		svc := NewBalanceService(mockTxRepo, nil, nil, DefaultOrderNumberLimits(), domain.WithdrawalLimits{})
+		mockTxRepo.EXPECT().GetWithdrawals(mock.Anything, int64(1), (*domain.WithdrawalCursor)(nil), maxWithdrawalsLimit+1).Return(withdrawals, nil).Once()
+
+		page, err := svc.GetWithdrawals(ctx, 1, nil, 1000)
+
+		require.NoError(t, err)
+		assert.Len(t, page.Withdrawals, 3)
+		assert.Nil(t, page.Next)
+	})
+
+	t.Run("Negative limit", func(t *testing.T) {
+		svc := NewBalanceService(domainmocks.NewTransactionRepositoryMock(t), nil, nil, DefaultOrderNumberLimits(), domain.WithdrawalLimits{})
+
+		_, err := svc.GetWithdrawals(ctx, 1, nil, -1)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	})
+}
+
+func TestBalanceService_GetWithdrawalSummary(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		repo := domainmocks.NewTransactionRepositoryMock(t)
+		svc := NewBalanceService(repo, nil, nil, DefaultOrderNumberLimits(), domain.WithdrawalLimits{})
+
+		summary := &domain.WithdrawalSummary{Count: 2, Total: 150, Largest: 100}
+		repo.EXPECT().GetWithdrawalSummary(mock.Anything, int64(1)).Return(summary, nil).Once()
+
+		result, err := svc.GetWithdrawalSummary(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, summary, result)
+	})
+
+	t.Run("Database error", func(t *testing.T) {
+		repo := domainmocks.NewTransactionRepositoryMock(t)
+		svc := NewBalanceService(repo, nil, nil, DefaultOrderNumberLimits(), domain.WithdrawalLimits{})
+
+		repo.EXPECT().GetWithdrawalSummary(mock.Anything, int64(1)).Return(nil, errors.New("db error")).Once()
+
+		result, err := svc.GetWithdrawalSummary(ctx, 1)
+		assert.Error(t, err)
+		assert.Nil(t, result)
+	})
+}
+
+func TestBalanceService_GetWithdrawal(t *testing.T) {
+	ctx := context.Background()
+	publicID := uuid.New()
+
+	t.Run("Success", func(t *testing.T) {
+		repo := domainmocks.NewTransactionRepositoryMock(t)
+		svc := NewBalanceService(repo, nil, nil, DefaultOrderNumberLimits(), domain.WithdrawalLimits{})
+
+		withdrawal := &domain.Transaction{ID: 1, PublicID: publicID, UserID: 1, OrderNumber: "2377225624", Amount: 100}
+		repo.EXPECT().GetWithdrawalByPublicID(mock.Anything, int64(1), publicID).Return(withdrawal, nil).Once()
+
+		result, err := svc.GetWithdrawal(ctx, 1, publicID)
+		require.NoError(t, err)
+		assert.Equal(t, withdrawal, result)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		repo := domainmocks.NewTransactionRepositoryMock(t)
+		svc := NewBalanceService(repo, nil, nil, DefaultOrderNumberLimits(), domain.WithdrawalLimits{})
+
+		repo.EXPECT().GetWithdrawalByPublicID(mock.Anything, int64(1), publicID).Return(nil, domain.ErrTransactionNotFound).Once()
+
+		result, err := svc.GetWithdrawal(ctx, 1, publicID)
+		assert.ErrorIs(t, err, domain.ErrTransactionNotFound)
+		assert.Nil(t, result)
+	})
+}
+
+func TestBalanceService_VerifyLedger(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Broken chain is returned as result", func(t *testing.T) {
+		txRepo := domainmocks.NewTransactionRepositoryMock(t)
+		svc := NewBalanceService(txRepo, nil, nil, DefaultOrderNumberLimits(), domain.WithdrawalLimits{})
+
+		verification := &domain.LedgerVerification{
+			Checked: 10,
+			Chains:  10,
+			Break:   &domain.LedgerBreak{UserID: 2, Seq: 4, PublicID: uuid.New(), Reason: domain.LedgerBreakGap},
+		}
+		txRepo.EXPECT().VerifyLedgerChain(mock.Anything).Return(verification, nil).Once()
+
+		result, err := svc.VerifyLedger(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, verification, result)
+	})
+
+	t.Run("Repository error", func(t *testing.T) {
+		txRepo := domainmocks.NewTransactionRepositoryMock(t)
+		svc := NewBalanceService(txRepo, nil, nil, DefaultOrderNumberLimits(), domain.WithdrawalLimits{})
+
+		txRepo.EXPECT().VerifyLedgerChain(mock.Anything).Return(nil, errors.New("db error")).Once()
+
+		result, err := svc.VerifyLedger(ctx)
+		assert.Error(t, err)
+		assert.Nil(t, result)
+	})
+}
+
+func TestBalanceService_GetBalance_Coalesced(t *testing.T) {
+	ctx := context.Background()
+	txRepo := domainmocks.NewTransactionRepositoryMock(t)
+	svc := NewBalanceService(txRepo, nil, nil, DefaultOrderNumberLimits(), domain.WithdrawalLimits{})
+
+	release := make(chan struct{})
+	txRepo.EXPECT().GetBalance(mock.Anything, int64(1)).
+		RunAndReturn(func(context.Context, int64) (*domain.Balance, error) {
+			<-release
+			return &domain.Balance{Current: 500, Withdrawn: 200}, nil
+		}).Once()
+
+	const callers = 3
+	var wg sync.WaitGroup
+	results := make([]*domain.Balance, callers)
+	for i := range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			balance, err := svc.GetBalance(ctx, 1)
+			assert.NoError(t, err)
+			results[i] = balance
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	// Один запрос к БД, но у каждого вызывающего своя копия баланса
+	for _, balance := range results {
+		assert.Equal(t, &domain.Balance{Current: 500, Withdrawn: 200}, balance)
+	}
+	assert.NotSame(t, results[0], results[1])
+}
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// coalesce выполняет fn один раз для всех одновременных вызовов с тем же ключом.
+// fn получает контекст первого вызывающего без отмены: отказ одного из ожидающих
+// не должен прерывать работу остальных. Каждый вызывающий перестает ждать при отмене
+// своего контекста. Результат общий для всех вызывающих, изменять его нельзя
+func coalesce[T any](ctx context.Context, group *singleflight.Group, key string, fn func(context.Context) (T, error)) (T, error) {
+	ch := group.DoChan(key, func() (any, error) {
+		return fn(context.WithoutCancel(ctx))
+	})
+
+	select {
+	case res := <-ch:
+		if res.Err != nil {
+			var zero T
+			return zero, res.Err
+		}
+		return res.Val.(T), nil
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/sync/singleflight"
+)
+
+func TestCoalesce(t *testing.T) {
+	t.Run("Concurrent calls share one execution", func(t *testing.T) {
+		var group singleflight.Group
+		var calls atomic.Int32
+		release := make(chan struct{})
+
+		const callers = 5
+		var wg sync.WaitGroup
+		results := make([]int, callers)
+		for i := range callers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				v, err := coalesce(context.Background(), &group, "key", func(context.Context) (int, error) {
+					calls.Add(1)
+					<-release
+					return 42, nil
+				})
+				assert.NoError(t, err)
+				results[i] = v
+			}()
+		}
+		// Выполнение держится, пока остальные вызовы подключаются к нему
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		assert.Equal(t, int32(1), calls.Load())
+		assert.Equal(t, []int{42, 42, 42, 42, 42}, results)
+	})
+
+	t.Run("Error is shared", func(t *testing.T) {
+		var group singleflight.Group
+		errBoom := errors.New("boom")
+
+		_, err := coalesce(context.Background(), &group, "key", func(context.Context) (int, error) {
+			return 0, errBoom
+		})
+		assert.ErrorIs(t, err, errBoom)
+	})
+
+	t.Run("Canceled caller does not cancel the others", func(t *testing.T) {
+		var group singleflight.Group
+		started := make(chan struct{})
+		release := make(chan struct{})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		leaderErr := make(chan error, 1)
+		go func() {
+			_, err := coalesce(ctx, &group, "key", func(ctx context.Context) (int, error) {
+				close(started)
+				<-release
+				return 1, ctx.Err()
+			})
+			leaderErr <- err
+		}()
+		<-started
+
+		followerResult := make(chan int, 1)
+		go func() {
+			v, err := coalesce(context.Background(), &group, "key", func(context.Context) (int, error) {
+				return 2, nil
+			})
+			assert.NoError(t, err)
+			followerResult <- v
+		}()
+		time.Sleep(20 * time.Millisecond)
+
+		cancel()
+		assert.ErrorIs(t, <-leaderErr, context.Canceled)
+		close(release)
+		assert.Equal(t, 1, <-followerResult)
+	})
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"regexp"
+	"strings"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/avc/loyalty-system-diploma/internal/jobs"
+	"github.com/avc/loyalty-system-diploma/internal/logctx"
+	"go.uber.org/zap"
+)
+
+// UserContactsRepository определяет хранение контактных данных пользователей.
+// Репозиторий шифрует значения при записи и расшифровывает при чтении.
+type UserContactsRepository interface {
+	GetUserContacts(ctx context.Context, userID int64) (*domain.UserContacts, error)
+	SetUserContacts(ctx context.Context, userID int64, contacts domain.UserContacts) error
+	CountStaleUserContacts(ctx context.Context) (int, error)
+	RewrapUserContacts(ctx context.Context, limit int) (int, error)
+}
+
+const (
+	// maxEmailLength - наибольшая длина адреса по RFC 5321
+	maxEmailLength = 254
+	// rewrapBatchSize - пользователи, перешифровываемые в одной транзакции БД
+	rewrapBatchSize = 200
+)
+
+// phonePattern - номер в международном формате после удаления пробелов, скобок и дефисов
+var phonePattern = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+
+// phoneSeparators удаляются из номера перед проверкой
+var phoneSeparators = strings.NewReplacer(" ", "", "-", "", "(", "", ")", "")
+
+// UserContactsService управляет контактными данными пользователей
+type UserContactsService struct {
+	repo UserContactsRepository
+}
+
+// NewUserContactsService создает новый UserContactsService
+func NewUserContactsService(repo UserContactsRepository) *UserContactsService {
+	return &UserContactsService{repo: repo}
+}
+
+// GetContacts возвращает контактные данные пользователя
+func (s *UserContactsService) GetContacts(ctx context.Context, userID int64) (*domain.UserContacts, error) {
+	contacts, err := s.repo.GetUserContacts(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) || errors.Is(err, domain.ErrPIIEncryptionDisabled) {
+			return nil, err
+		}
+		logctx.From(ctx).Error("contacts service: failed to get contacts", zap.Error(err))
+		return nil, fmt.Errorf("contacts service: failed to get contacts of user %d: %w", userID, err)
+	}
+	return contacts, nil
+}
+
+// UpdateContacts проверяет, нормализует и сохраняет контактные данные пользователя.
+// Пустое поле удаляет контакт.
+func (s *UserContactsService) UpdateContacts(ctx context.Context, userID int64, contacts domain.UserContacts) (*domain.UserContacts, error) {
+	normalized, err := normalizeContacts(contacts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.SetUserContacts(ctx, userID, normalized); err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) || errors.Is(err, domain.ErrPIIEncryptionDisabled) {
+			return nil, err
+		}
+		logctx.From(ctx).Error("contacts service: failed to set contacts", zap.Error(err))
+		return nil, fmt.Errorf("contacts service: failed to set contacts of user %d: %w", userID, err)
+	}
+	return &normalized, nil
+}
+
+// RewrapContacts перешифровывает контакты всех пользователей текущим мастер-ключом
+// и возвращает число перешифрованных записей. После завершения прежний ключ можно удалить из конфигурации.
+func (s *UserContactsService) RewrapContacts(ctx context.Context, progress jobs.Progress) (int, error) {
+	total, err := s.repo.CountStaleUserContacts(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("contacts service: failed to count stale contacts: %w", err)
+	}
+	progress.SetTotal(total)
+
+	rewrapped := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return rewrapped, fmt.Errorf("contacts service: rewrap interrupted after %d users: %w", rewrapped, err)
+		}
+
+		n, err := s.repo.RewrapUserContacts(ctx, rewrapBatchSize)
+		if err != nil {
+			return rewrapped, fmt.Errorf("contacts service: rewrap failed after %d users: %w", rewrapped, err)
+		}
+		for i := 0; i < n; i++ {
+			progress.Advance(true)
+		}
+		rewrapped += n
+		if n < rewrapBatchSize {
+			break
+		}
+	}
+
+	logctx.From(ctx).Info("user contacts rewrapped", zap.Int("users", rewrapped))
+	return rewrapped, nil
+}
+
+// normalizeContacts убирает пробелы и разделители в номере и проверяет формат
+func normalizeContacts(contacts domain.UserContacts) (domain.UserContacts, error) {
+	email := strings.TrimSpace(contacts.Email)
+	if email != "" {
+		addr, err := mail.ParseAddress(email)
+		if err != nil || addr.Address != email || len(email) > maxEmailLength {
+			return domain.UserContacts{}, fmt.Errorf("contacts service: invalid email: %w", domain.ErrInvalidContacts)
+		}
+	}
+
+	phone := phoneSeparators.Replace(strings.TrimSpace(contacts.Phone))
+	if phone != "" && !phonePattern.MatchString(phone) {
+		return domain.UserContacts{}, fmt.Errorf("contacts service: invalid phone: %w", domain.ErrInvalidContacts)
+	}
+
+	return domain.UserContacts{Email: email, Phone: phone}, nil
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	domainmocks "github.com/avc/loyalty-system-diploma/internal/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserContactsService_UpdateContacts(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Normalizes before saving", func(t *testing.T) {
+		repo := domainmocks.NewUserContactsRepositoryMock(t)
+		svc := NewUserContactsService(repo)
+
+		want := domain.UserContacts{Email: "alice@example.com", Phone: "+79991234567"}
+		repo.EXPECT().SetUserContacts(mock.Anything, int64(1), want).Return(nil).Once()
+
+		contacts, err := svc.UpdateContacts(ctx, 1, domain.UserContacts{Email: " alice@example.com ", Phone: "+7 (999) 123-45-67"})
+		require.NoError(t, err)
+		assert.Equal(t, &want, contacts)
+	})
+
+	invalid := []struct {
+		name     string
+		contacts domain.UserContacts
+	}{
+		{"Email without domain", domain.UserContacts{Email: "alice"}},
+		{"Email with display name", domain.UserContacts{Email: "Alice <alice@example.com>"}},
+		{"Phone without country code", domain.UserContacts{Phone: "89991234567"}},
+		{"Phone with letters", domain.UserContacts{Phone: "+7999CALLME"}},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewUserContactsService(domainmocks.NewUserContactsRepositoryMock(t))
+
+			_, err := svc.UpdateContacts(ctx, 1, tt.contacts)
+			assert.ErrorIs(t, err, domain.ErrInvalidContacts)
+		})
+	}
+
+	t.Run("Encryption disabled", func(t *testing.T) {
+		repo := domainmocks.NewUserContactsRepositoryMock(t)
+		svc := NewUserContactsService(repo)
+
+		repo.EXPECT().SetUserContacts(mock.Anything, int64(1), domain.UserContacts{}).
+			Return(domain.ErrPIIEncryptionDisabled).Once()
+
+		_, err := svc.UpdateContacts(ctx, 1, domain.UserContacts{})
+		assert.ErrorIs(t, err, domain.ErrPIIEncryptionDisabled)
+	})
+}
+
+func TestUserContactsService_RewrapContacts(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Rewraps in batches", func(t *testing.T) {
+		repo := domainmocks.NewUserContactsRepositoryMock(t)
+		svc := NewUserContactsService(repo)
+		progress := &recordingProgress{}
+
+		repo.EXPECT().CountStaleUserContacts(mock.Anything).Return(rewrapBatchSize+3, nil).Once()
+		repo.EXPECT().RewrapUserContacts(mock.Anything, rewrapBatchSize).Return(rewrapBatchSize, nil).Once()
+		repo.EXPECT().RewrapUserContacts(mock.Anything, rewrapBatchSize).Return(3, nil).Once()
+
+		rewrapped, err := svc.RewrapContacts(ctx, progress)
+		require.NoError(t, err)
+		assert.Equal(t, rewrapBatchSize+3, rewrapped)
+		assert.Equal(t, rewrapBatchSize+3, progress.total)
+		assert.Equal(t, rewrapBatchSize+3, progress.processed)
+	})
+
+	t.Run("Unknown key stops the job", func(t *testing.T) {
+		repo := domainmocks.NewUserContactsRepositoryMock(t)
+		svc := NewUserContactsService(repo)
+
+		repo.EXPECT().CountStaleUserContacts(mock.Anything).Return(5, nil).Once()
+		repo.EXPECT().RewrapUserContacts(mock.Anything, rewrapBatchSize).Return(0, errors.New("unknown master key")).Once()
+
+		_, err := svc.RewrapContacts(ctx, &recordingProgress{})
+		assert.Error(t, err)
+	})
+}
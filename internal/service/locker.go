@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+)
+
+// Locker выдает блокировку по ключу, общую для всех реплик (internal/lock).
+// Блокировки транзакций БД защищают только данные в этой БД; Locker
+// распространяется и на данные вне нее.
+type Locker interface {
+	Lock(ctx context.Context, key string) (unlock func(), err error)
+}
+
+// lockKeys занимает ключи по порядку и возвращает функцию, снимающую их в обратном порядке.
+// Без locker блокировки не берутся.
+func lockKeys(ctx context.Context, locker Locker, keys ...string) (func(), error) {
+	if locker == nil {
+		return func() {}, nil
+	}
+
+	unlocks := make([]func(), 0, len(keys))
+	unlockAll := func() {
+		for i := len(unlocks) - 1; i >= 0; i-- {
+			unlocks[i]()
+		}
+	}
+	for _, key := range keys {
+		unlock, err := locker.Lock(ctx, key)
+		if err != nil {
+			unlockAll()
+			return nil, err
+		}
+		unlocks = append(unlocks, unlock)
+	}
+	return unlockAll, nil
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/avc/loyalty-system-diploma/internal/logctx"
+	"github.com/avc/loyalty-system-diploma/internal/utils/jwt"
+	"github.com/avc/loyalty-system-diploma/internal/utils/oauth"
+	"go.uber.org/zap"
+)
+
+// oauthLoginTTL - сколько пользователь может провести на странице входа провайдера
+const oauthLoginTTL = 10 * time.Minute
+
+// OAuthProvider реализует вход через внешний сервис по OAuth2 (authorization code flow).
+type OAuthProvider interface {
+	Name() string
+	AuthCodeURL(state string) string
+	Exchange(ctx context.Context, code string) (*domain.ExternalIdentity, error)
+}
+
+// OAuthService предоставляет вход через социальные сети
+type OAuthService struct {
+	userRepo   UserRepository
+	jwtManager *jwt.Manager
+	states     *oauth.StateSigner
+	providers  map[string]OAuthProvider
+}
+
+// NewOAuthService создает новый OAuthService
+func NewOAuthService(userRepo UserRepository, jwtManager *jwt.Manager, states *oauth.StateSigner, providers ...OAuthProvider) *OAuthService {
+	byName := make(map[string]OAuthProvider, len(providers))
+	for _, provider := range providers {
+		byName[provider.Name()] = provider
+	}
+	return &OAuthService{
+		userRepo:   userRepo,
+		jwtManager: jwtManager,
+		states:     states,
+		providers:  byName,
+	}
+}
+
+// BeginLogin начинает вход через провайдера. Если linkUserID не 0, учетная запись
+// провайдера будет привязана к этому пользователю вместо входа под отдельной
+func (s *OAuthService) BeginLogin(ctx context.Context, providerName string, linkUserID int64) (*domain.OAuthLogin, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return nil, domain.ErrUnknownOAuthProvider
+	}
+
+	nonce, err := newNonce()
+	if err != nil {
+		return nil, fmt.Errorf("oauth service: failed to generate nonce: %w", err)
+	}
+
+	state := s.states.Sign(oauth.State{
+		Provider:   providerName,
+		Nonce:      nonce,
+		LinkUserID: linkUserID,
+		ExpiresAt:  time.Now().Add(oauthLoginTTL).Unix(),
+	})
+
+	return &domain.OAuthLogin{
+		URL:   provider.AuthCodeURL(state),
+		Nonce: nonce,
+	}, nil
+}
+
+// CompleteLogin завершает вход по коду авторизации и возвращает токен доступа сервиса.
+// nonce - значение cookie браузера: без него state, перехваченный у другого пользователя, бесполезен
+func (s *OAuthService) CompleteLogin(ctx context.Context, providerName, state, nonce, code string) (string, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return "", domain.ErrUnknownOAuthProvider
+	}
+
+	login, err := s.states.Verify(state, time.Now())
+	if err != nil || login.Provider != providerName || nonce == "" || login.Nonce != nonce {
+		return "", domain.ErrInvalidOAuthState
+	}
+	if code == "" {
+		return "", fmt.Errorf("%w: empty authorization code", domain.ErrInvalidInput)
+	}
+
+	identity, err := provider.Exchange(ctx, code)
+	if err != nil {
+		logctx.From(ctx).Warn("oauth service: code exchange failed", zap.String("provider", providerName), zap.Error(err))
+		return "", fmt.Errorf("%w: %v", domain.ErrOAuthExchangeFailed, err)
+	}
+
+	userID := login.LinkUserID
+	if userID != 0 {
+		if err := s.userRepo.LinkExternalIdentity(ctx, userID, *identity); err != nil {
+			if errors.Is(err, domain.ErrIdentityAlreadyLinked) || errors.Is(err, domain.ErrUserNotFound) {
+				return "", err
+			}
+			logctx.From(ctx).Error("oauth service: failed to link identity",
+				zap.String("provider", providerName), zap.Int64("user_id", userID), zap.Error(err))
+			return "", fmt.Errorf("oauth service: failed to link %s identity to user %d: %w", providerName, userID, err)
+		}
+	} else {
+		user, err := s.userRepo.GetOrCreateExternalUser(ctx, *identity)
+		if err != nil {
+			logctx.From(ctx).Error("oauth service: failed to provision user",
+				zap.String("provider", providerName), zap.String("subject", identity.Subject), zap.Error(err))
+			return "", fmt.Errorf("oauth service: failed to provision %s user %q: %w", providerName, identity.Subject, err)
+		}
+		userID = user.ID
+	}
+
+	token, err := s.jwtManager.Generate(userID, 0)
+	if err != nil {
+		return "", fmt.Errorf("oauth service: failed to generate token for user %d: %w", userID, err)
+	}
+	return token, nil
+}
+
+// newNonce генерирует случайное значение для привязки входа к браузеру
+func newNonce() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	domainmocks "github.com/avc/loyalty-system-diploma/internal/domain/mocks"
+	"github.com/avc/loyalty-system-diploma/internal/utils/jwt"
+	"github.com/avc/loyalty-system-diploma/internal/utils/oauth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestOAuthService(t *testing.T) (*OAuthService, *domainmocks.UserRepositoryMock, *domainmocks.OAuthProviderMock, *jwt.Manager) {
+	userRepo := domainmocks.NewUserRepositoryMock(t)
+	provider := domainmocks.NewOAuthProviderMock(t)
+	provider.EXPECT().Name().Return("google").Once()
+	provider.EXPECT().AuthCodeURL(mock.Anything).RunAndReturn(func(state string) string {
+		return "https://accounts.example.com/authorize?state=" + url.QueryEscape(state)
+	}).Maybe()
+	jwtManager := jwt.NewManager("test-secret", time.Hour)
+	svc := NewOAuthService(userRepo, jwtManager, oauth.NewStateSigner("test-secret"), provider)
+	return svc, userRepo, provider, jwtManager
+}
+
+// beginLogin начинает вход и возвращает state из адреса провайдера и nonce
+func beginLogin(t *testing.T, svc *OAuthService, linkUserID int64) (string, string) {
+	login, err := svc.BeginLogin(context.Background(), "google", linkUserID)
+	require.NoError(t, err)
+	authURL, err := url.Parse(login.URL)
+	require.NoError(t, err)
+	return authURL.Query().Get("state"), login.Nonce
+}
+
+func TestOAuthService_BeginLogin(t *testing.T) {
+	svc, _, _, _ := newTestOAuthService(t)
+
+	_, err := svc.BeginLogin(context.Background(), "facebook", 0)
+	assert.ErrorIs(t, err, domain.ErrUnknownOAuthProvider)
+
+	first, firstNonce := beginLogin(t, svc, 0)
+	second, secondNonce := beginLogin(t, svc, 0)
+	assert.NotEmpty(t, firstNonce)
+	assert.NotEqual(t, firstNonce, secondNonce)
+	assert.NotEqual(t, first, second)
+}
+
+func TestOAuthService_CompleteLogin(t *testing.T) {
+	ctx := context.Background()
+	identity := &domain.ExternalIdentity{Issuer: "google", Subject: "1001", Login: "alice@example.com"}
+
+	t.Run("New user", func(t *testing.T) {
+		svc, userRepo, provider, jwtManager := newTestOAuthService(t)
+		state, nonce := beginLogin(t, svc, 0)
+		provider.EXPECT().Exchange(mock.Anything, "code").Return(identity, nil).Once()
+		userRepo.EXPECT().GetOrCreateExternalUser(mock.Anything, *identity).Return(&domain.User{ID: 5}, nil).Once()
+
+		token, err := svc.CompleteLogin(ctx, "google", state, nonce, "code")
+		require.NoError(t, err)
+		userID, err := jwtManager.Validate(token)
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), userID)
+	})
+
+	t.Run("Link to current user", func(t *testing.T) {
+		svc, userRepo, provider, jwtManager := newTestOAuthService(t)
+		state, nonce := beginLogin(t, svc, 7)
+		provider.EXPECT().Exchange(mock.Anything, "code").Return(identity, nil).Once()
+		userRepo.EXPECT().LinkExternalIdentity(mock.Anything, int64(7), *identity).Return(nil).Once()
+
+		token, err := svc.CompleteLogin(ctx, "google", state, nonce, "code")
+		require.NoError(t, err)
+		userID, err := jwtManager.Validate(token)
+		require.NoError(t, err)
+		assert.Equal(t, int64(7), userID)
+	})
+
+	t.Run("Identity linked to another user", func(t *testing.T) {
+		svc, userRepo, provider, _ := newTestOAuthService(t)
+		state, nonce := beginLogin(t, svc, 7)
+		provider.EXPECT().Exchange(mock.Anything, "code").Return(identity, nil).Once()
+		userRepo.EXPECT().LinkExternalIdentity(mock.Anything, int64(7), *identity).Return(domain.ErrIdentityAlreadyLinked).Once()
+
+		_, err := svc.CompleteLogin(ctx, "google", state, nonce, "code")
+		assert.ErrorIs(t, err, domain.ErrIdentityAlreadyLinked)
+	})
+
+	t.Run("Nonce from another browser", func(t *testing.T) {
+		svc, _, _, _ := newTestOAuthService(t)
+		state, _ := beginLogin(t, svc, 0)
+
+		_, err := svc.CompleteLogin(ctx, "google", state, "other-nonce", "code")
+		assert.ErrorIs(t, err, domain.ErrInvalidOAuthState)
+
+		_, err = svc.CompleteLogin(ctx, "google", state, "", "code")
+		assert.ErrorIs(t, err, domain.ErrInvalidOAuthState)
+	})
+
+	t.Run("Forged state", func(t *testing.T) {
+		svc, _, _, _ := newTestOAuthService(t)
+		forged := oauth.NewStateSigner("other-secret").Sign(oauth.State{
+			Provider: "google", Nonce: "n", ExpiresAt: time.Now().Add(time.Minute).Unix(),
+		})
+
+		_, err := svc.CompleteLogin(ctx, "google", forged, "n", "code")
+		assert.ErrorIs(t, err, domain.ErrInvalidOAuthState)
+	})
+
+	t.Run("Exchange failed", func(t *testing.T) {
+		svc, _, provider, _ := newTestOAuthService(t)
+		state, nonce := beginLogin(t, svc, 0)
+		provider.EXPECT().Exchange(mock.Anything, "code").Return(nil, errors.New("invalid_grant")).Once()
+
+		_, err := svc.CompleteLogin(ctx, "google", state, nonce, "code")
+		assert.ErrorIs(t, err, domain.ErrOAuthExchangeFailed)
+	})
+
+	t.Run("Unknown provider", func(t *testing.T) {
+		svc, _, _, _ := newTestOAuthService(t)
+
+		_, err := svc.CompleteLogin(ctx, "facebook", "state", "nonce", "code")
+		assert.ErrorIs(t, err, domain.ErrUnknownOAuthProvider)
+	})
+}
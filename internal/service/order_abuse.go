@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/avc/loyalty-system-diploma/internal/logctx"
+	"github.com/avc/loyalty-system-diploma/internal/metrics"
+	"go.uber.org/zap"
+)
+
+// SubmissionBanRepository определяет хранение временных запретов загрузки заказов.
+type SubmissionBanRepository interface {
+	GetSubmissionBan(ctx context.Context, userID int64) (*domain.SubmissionBan, error)
+	BanSubmissions(ctx context.Context, userID int64, reason string, duration time.Duration) (*domain.SubmissionBan, error)
+	ListSubmissionBans(ctx context.Context) ([]*domain.SubmissionBan, error)
+	LiftSubmissionBan(ctx context.Context, login string) error
+}
+
+// События подозрительной загрузки заказов для метрик
+const (
+	submissionEventConflict    = "conflict"
+	submissionEventRateLimited = "rate_limited"
+	submissionEventBanned      = "banned"
+)
+
+// SubmissionGuardConfig содержит настройки защиты от перебора номеров заказов
+type SubmissionGuardConfig struct {
+	RateLimit      int           // Загрузок заказов пользователем за окно (0 - без ограничения)
+	RateWindow     time.Duration // Окно учета загрузок
+	ConflictLimit  int           // Загрузок чужих заказов за окно до запрета (0 - не запрещать)
+	ConflictWindow time.Duration // Окно учета загрузок чужих заказов
+	BanDuration    time.Duration // Срок запрета загрузки
+}
+
+// submissionWindow - счетчик пользователя в текущем окне
+type submissionWindow struct {
+	count   int
+	resetAt time.Time
+}
+
+// submissionCounter считает события по пользователям в фиксированных окнах
+type submissionCounter struct {
+	window    time.Duration
+	users     map[int64]*submissionWindow
+	lastSweep time.Time
+}
+
+func newSubmissionCounter(window time.Duration) *submissionCounter {
+	return &submissionCounter{window: window, users: make(map[int64]*submissionWindow)}
+}
+
+// add учитывает событие и возвращает окно пользователя. Вызывается под mu.
+func (c *submissionCounter) add(userID int64, now time.Time) *submissionWindow {
+	c.sweep(now)
+
+	w, ok := c.users[userID]
+	if !ok || !now.Before(w.resetAt) {
+		w = &submissionWindow{resetAt: now.Add(c.window)}
+		c.users[userID] = w
+	}
+	w.count++
+	return w
+}
+
+// sweep раз в окно удаляет истекшие окна. Вызывается под mu.
+func (c *submissionCounter) sweep(now time.Time) {
+	if now.Sub(c.lastSweep) < c.window {
+		return
+	}
+	c.lastSweep = now
+	for userID, w := range c.users {
+		if !now.Before(w.resetAt) {
+			delete(c.users, userID)
+		}
+	}
+}
+
+// SubmissionGuard защищает от перебора Luhn-валидных номеров: ограничивает частоту
+// загрузок пользователя и запрещает загрузку на время, если пользователь слишком часто
+// получает конфликт с чужим заказом. Счетчики ведутся в памяти реплики, запреты
+// хранятся в БД и действуют на всех репликах. Методы безопасны для вызова на nil.
+type SubmissionGuard struct {
+	repo    SubmissionBanRepository
+	config  SubmissionGuardConfig
+	metrics *metrics.Metrics
+
+	mu          sync.Mutex
+	submissions *submissionCounter
+	conflicts   *submissionCounter
+	now         func() time.Time
+}
+
+// NewSubmissionGuard создает новый SubmissionGuard. m может быть nil.
+func NewSubmissionGuard(repo SubmissionBanRepository, config SubmissionGuardConfig, m *metrics.Metrics) *SubmissionGuard {
+	return &SubmissionGuard{
+		repo:        repo,
+		config:      config,
+		metrics:     m,
+		submissions: newSubmissionCounter(config.RateWindow),
+		conflicts:   newSubmissionCounter(config.ConflictWindow),
+		now:         time.Now,
+	}
+}
+
+// Allow проверяет, может ли пользователь загрузить заказ, и учитывает загрузку.
+// Возвращает *domain.SubmissionLimitError, если пользователю запрещена загрузка
+// или он превысил частоту загрузок. Ошибка чтения запрета не мешает загрузке
+func (g *SubmissionGuard) Allow(ctx context.Context, userID int64) error {
+	if g == nil {
+		return nil
+	}
+
+	ban, err := g.repo.GetSubmissionBan(ctx, userID)
+	switch {
+	case err == nil:
+		return &domain.SubmissionLimitError{Err: domain.ErrSubmissionsBanned, Until: ban.BannedUntil}
+	case !errors.Is(err, domain.ErrSubmissionBanNotFound):
+		logctx.From(ctx).Warn("submission guard: failed to check submission ban",
+			zap.Int64("user_id", userID),
+			zap.Error(err),
+		)
+	}
+
+	if g.config.RateLimit <= 0 || g.config.RateWindow <= 0 {
+		return nil
+	}
+
+	g.mu.Lock()
+	w := g.submissions.add(userID, g.now())
+	exceeded, resetAt := w.count > g.config.RateLimit, w.resetAt
+	g.mu.Unlock()
+
+	if exceeded {
+		g.metrics.ObserveSubmissionAbuse(submissionEventRateLimited)
+		return &domain.SubmissionLimitError{Err: domain.ErrTooManySubmissions, Until: resetAt}
+	}
+	return nil
+}
+
+// RecordConflict учитывает загрузку номера чужого заказа. Достигнув ConflictLimit
+// за окно, пользователь получает запрет загрузки на BanDuration
+func (g *SubmissionGuard) RecordConflict(ctx context.Context, userID int64) {
+	if g == nil {
+		return
+	}
+	g.metrics.ObserveSubmissionAbuse(submissionEventConflict)
+
+	if g.config.ConflictLimit <= 0 || g.config.ConflictWindow <= 0 || g.config.BanDuration <= 0 {
+		return
+	}
+
+	g.mu.Lock()
+	w := g.conflicts.add(userID, g.now())
+	reached := w.count >= g.config.ConflictLimit
+	conflicts := w.count
+	if reached {
+		// Следующий запрет - только после новой серии конфликтов
+		delete(g.conflicts.users, userID)
+	}
+	g.mu.Unlock()
+
+	if !reached {
+		return
+	}
+
+	logger := logctx.From(ctx).With(zap.Int64("user_id", userID), zap.Int("conflicts", conflicts))
+	ban, err := g.repo.BanSubmissions(ctx, userID, domain.SubmissionBanReasonConflicts, g.config.BanDuration)
+	if err != nil {
+		logger.Error("submission guard: failed to ban order submissions", zap.Error(err))
+		return
+	}
+	g.metrics.ObserveSubmissionAbuse(submissionEventBanned)
+	logger.Warn("submission guard: order submissions banned for conflicting numbers",
+		zap.Time("banned_until", ban.BannedUntil),
+	)
+}
+
+// ListSubmissionBans возвращает действующие запреты загрузки заказов
+func (g *SubmissionGuard) ListSubmissionBans(ctx context.Context) ([]*domain.SubmissionBan, error) {
+	bans, err := g.repo.ListSubmissionBans(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("submission guard: failed to list submission bans: %w", err)
+	}
+	return bans, nil
+}
+
+// LiftSubmissionBan досрочно снимает запрет загрузки заказов с пользователя
+func (g *SubmissionGuard) LiftSubmissionBan(ctx context.Context, login string) error {
+	if err := g.repo.LiftSubmissionBan(ctx, login); err != nil {
+		return fmt.Errorf("submission guard: failed to lift submission ban for %q: %w", login, err)
+	}
+	logctx.From(ctx).Info("submission guard: submission ban lifted", zap.String("login", login))
+	return nil
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	domainmocks "github.com/avc/loyalty-system-diploma/internal/domain/mocks"
+	"github.com/avc/loyalty-system-diploma/internal/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSubmissionGuard(repo SubmissionBanRepository, config SubmissionGuardConfig) (*SubmissionGuard, *time.Time) {
+	guard := NewSubmissionGuard(repo, config, metrics.New())
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	guard.now = func() time.Time { return now }
+	return guard, &now
+}
+
+func TestSubmissionGuard_Allow_RateLimit(t *testing.T) {
+	ctx := context.Background()
+	repo := domainmocks.NewSubmissionBanRepositoryMock(t)
+	repo.EXPECT().GetSubmissionBan(mock.Anything, mock.Anything).Return(nil, domain.ErrSubmissionBanNotFound)
+	guard, now := newTestSubmissionGuard(repo, SubmissionGuardConfig{RateLimit: 2, RateWindow: time.Minute})
+
+	require.NoError(t, guard.Allow(ctx, 1))
+	require.NoError(t, guard.Allow(ctx, 1))
+
+	err := guard.Allow(ctx, 1)
+	var limitErr *domain.SubmissionLimitError
+	require.ErrorAs(t, err, &limitErr)
+	assert.ErrorIs(t, err, domain.ErrTooManySubmissions)
+	assert.Equal(t, now.Add(time.Minute), limitErr.Until)
+
+	// Счетчики пользователей независимы
+	require.NoError(t, guard.Allow(ctx, 2))
+
+	// В новом окне загрузки снова разрешены
+	*now = now.Add(time.Minute)
+	require.NoError(t, guard.Allow(ctx, 1))
+}
+
+func TestSubmissionGuard_Allow_Banned(t *testing.T) {
+	ctx := context.Background()
+	until := time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)
+
+	repo := domainmocks.NewSubmissionBanRepositoryMock(t)
+	repo.EXPECT().GetSubmissionBan(mock.Anything, int64(1)).
+		Return(&domain.SubmissionBan{UserID: 1, BannedUntil: until}, nil).Once()
+	guard, _ := newTestSubmissionGuard(repo, SubmissionGuardConfig{})
+
+	err := guard.Allow(ctx, 1)
+	var limitErr *domain.SubmissionLimitError
+	require.ErrorAs(t, err, &limitErr)
+	assert.ErrorIs(t, err, domain.ErrSubmissionsBanned)
+	assert.Equal(t, until, limitErr.Until)
+}
+
+func TestSubmissionGuard_Allow_BanCheckFails(t *testing.T) {
+	repo := domainmocks.NewSubmissionBanRepositoryMock(t)
+	repo.EXPECT().GetSubmissionBan(mock.Anything, int64(1)).Return(nil, errors.New("db error")).Once()
+	guard, _ := newTestSubmissionGuard(repo, SubmissionGuardConfig{})
+
+	// Недоступность таблицы запретов не мешает загрузке
+	assert.NoError(t, guard.Allow(context.Background(), 1))
+}
+
+func TestSubmissionGuard_RecordConflict(t *testing.T) {
+	ctx := context.Background()
+	config := SubmissionGuardConfig{ConflictLimit: 3, ConflictWindow: time.Hour, BanDuration: 24 * time.Hour}
+
+	t.Run("Ban after limit", func(t *testing.T) {
+		repo := domainmocks.NewSubmissionBanRepositoryMock(t)
+		guard, now := newTestSubmissionGuard(repo, config)
+		repo.EXPECT().BanSubmissions(mock.Anything, int64(1), domain.SubmissionBanReasonConflicts, 24*time.Hour).
+			Return(&domain.SubmissionBan{UserID: 1, BannedUntil: now.Add(24 * time.Hour)}, nil).Once()
+
+		guard.RecordConflict(ctx, 1)
+		guard.RecordConflict(ctx, 1)
+		guard.RecordConflict(ctx, 2)
+		guard.RecordConflict(ctx, 1)
+
+		// Серия начинается заново после запрета
+		guard.RecordConflict(ctx, 1)
+	})
+
+	t.Run("Window expires", func(t *testing.T) {
+		repo := domainmocks.NewSubmissionBanRepositoryMock(t)
+		guard, now := newTestSubmissionGuard(repo, config)
+
+		guard.RecordConflict(ctx, 1)
+		guard.RecordConflict(ctx, 1)
+		*now = now.Add(time.Hour)
+		guard.RecordConflict(ctx, 1)
+	})
+
+	t.Run("Bans disabled", func(t *testing.T) {
+		repo := domainmocks.NewSubmissionBanRepositoryMock(t)
+		guard, _ := newTestSubmissionGuard(repo, SubmissionGuardConfig{ConflictLimit: 1, ConflictWindow: time.Hour})
+
+		guard.RecordConflict(ctx, 1)
+		guard.RecordConflict(ctx, 1)
+	})
+}
+
+func TestSubmissionGuard_Nil(t *testing.T) {
+	var guard *SubmissionGuard
+
+	assert.NoError(t, guard.Allow(context.Background(), 1))
+	assert.NotPanics(t, func() { guard.RecordConflict(context.Background(), 1) })
+}
+
+func TestSubmissionGuard_LiftSubmissionBan(t *testing.T) {
+	repo := domainmocks.NewSubmissionBanRepositoryMock(t)
+	guard, _ := newTestSubmissionGuard(repo, SubmissionGuardConfig{})
+
+	repo.EXPECT().LiftSubmissionBan(mock.Anything, "alice").Return(nil).Once()
+	repo.EXPECT().LiftSubmissionBan(mock.Anything, "bob").Return(domain.ErrSubmissionBanNotFound).Once()
+
+	assert.NoError(t, guard.LiftSubmissionBan(context.Background(), "alice"))
+	assert.ErrorIs(t, guard.LiftSubmissionBan(context.Background(), "bob"), domain.ErrSubmissionBanNotFound)
+}
+
+func TestOrderService_SubmitOrder_SubmissionGuard(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Conflicts lead to ban", func(t *testing.T) {
+		orderRepo := domainmocks.NewOrderRepositoryMock(t)
+		banRepo := domainmocks.NewSubmissionBanRepositoryMock(t)
+		guard, now := newTestSubmissionGuard(banRepo, SubmissionGuardConfig{
+			ConflictLimit: 1, ConflictWindow: time.Hour, BanDuration: time.Hour,
+		})
+		svc := NewOrderService(orderRepo, nil, DefaultOrderNumberLimits(), guard, nil, nil)
+
+		banRepo.EXPECT().GetSubmissionBan(mock.Anything, int64(1)).Return(nil, domain.ErrSubmissionBanNotFound).Once()
+		orderRepo.EXPECT().CreateOrder(mock.Anything, int64(1), "79927398713", (*domain.OrderMetadata)(nil)).
+			Return(nil, domain.ErrOrderOwnedByAnother).Once()
+		banRepo.EXPECT().BanSubmissions(mock.Anything, int64(1), domain.SubmissionBanReasonConflicts, time.Hour).
+			Return(&domain.SubmissionBan{UserID: 1, BannedUntil: now.Add(time.Hour)}, nil).Once()
+
+		err := svc.SubmitOrder(ctx, 1, "79927398713", nil)
+		require.ErrorIs(t, err, domain.ErrOrderOwnedByAnother)
+	})
+
+	t.Run("Banned user does not reach repository", func(t *testing.T) {
+		orderRepo := domainmocks.NewOrderRepositoryMock(t)
+		banRepo := domainmocks.NewSubmissionBanRepositoryMock(t)
+		guard, now := newTestSubmissionGuard(banRepo, SubmissionGuardConfig{})
+		svc := NewOrderService(orderRepo, nil, DefaultOrderNumberLimits(), guard, nil, nil)
+
+		banRepo.EXPECT().GetSubmissionBan(mock.Anything, int64(1)).
+			Return(&domain.SubmissionBan{UserID: 1, BannedUntil: now.Add(time.Hour)}, nil).Once()
+
+		err := svc.SubmitOrder(ctx, 1, "79927398713", nil)
+		assert.ErrorIs(t, err, domain.ErrSubmissionsBanned)
+	})
+
+	t.Run("Invalid number is not counted", func(t *testing.T) {
+		guard, _ := newTestSubmissionGuard(domainmocks.NewSubmissionBanRepositoryMock(t), SubmissionGuardConfig{})
+		svc := NewOrderService(domainmocks.NewOrderRepositoryMock(t), nil, DefaultOrderNumberLimits(), guard, nil, nil)
+
+		err := svc.SubmitOrder(ctx, 1, "12345", nil)
+		assert.ErrorIs(t, err, domain.ErrInvalidOrderNumber)
+	})
+}
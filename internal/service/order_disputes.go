@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/avc/loyalty-system-diploma/internal/logctx"
+	"go.uber.org/zap"
+)
+
+// OpenDispute открывает спор о заказе, загруженном другим пользователем. Спор раскрывает
+// то же, что и 409 при загрузке, поэтому проходит те же ограничения частоты и запреты
+func (s *OrderService) OpenDispute(ctx context.Context, userID int64, number, comment string) (*domain.OrderDispute, error) {
+	number, comment = strings.TrimSpace(number), strings.TrimSpace(comment)
+	if len(comment) > domain.MaxDisputeCommentLength {
+		return nil, fmt.Errorf("order service: dispute comment must be at most %d bytes: %w", domain.MaxDisputeCommentLength, domain.ErrInvalidInput)
+	}
+	if err := s.numberLimits.validate(number, ""); err != nil {
+		return nil, err
+	}
+	if err := s.guard.Allow(ctx, userID); err != nil {
+		return nil, fmt.Errorf("order service: dispute for order %q rejected: %w", number, err)
+	}
+
+	dispute, err := s.orderRepo.CreateOrderDispute(ctx, userID, number, comment)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrOrderNotFound):
+			return nil, fmt.Errorf("order service: order %q not found: %w", number, err)
+		case errors.Is(err, domain.ErrOrderAlreadyOwned):
+			return nil, fmt.Errorf("order service: order %q already belongs to user %d: %w", number, userID, err)
+		case errors.Is(err, domain.ErrDisputeExists):
+			return nil, fmt.Errorf("order service: dispute for order %q already open: %w", number, err)
+		}
+		logctx.From(ctx).Error("order service: failed to open dispute", zap.String("order", number), zap.Error(err))
+		return nil, fmt.Errorf("order service: failed to open dispute for order %q: %w", number, err)
+	}
+
+	logctx.From(ctx).Info("order dispute opened",
+		zap.Int64("dispute_id", dispute.ID),
+		zap.String("order", number),
+		zap.Int64("claimant_id", userID),
+		zap.Int64("owner_id", dispute.OwnerID),
+	)
+	return dispute, nil
+}
+
+// ListDisputes возвращает страницу споров в статусе status (пустой - все), старые первыми.
+// Размер страницы - как у поиска заказов.
+func (s *OrderService) ListDisputes(ctx context.Context, status domain.DisputeStatus, limit, offset int) (*domain.OrderDisputePage, error) {
+	switch status {
+	case "", domain.DisputeStatusOpen, domain.DisputeStatusTransferred, domain.DisputeStatusRejected:
+	default:
+		return nil, fmt.Errorf("order service: unknown dispute status %q: %w", status, domain.ErrInvalidInput)
+	}
+	if limit < 0 || offset < 0 {
+		return nil, fmt.Errorf("order service: negative limit or offset: %w", domain.ErrInvalidInput)
+	}
+	if limit == 0 {
+		limit = defaultOrderSearchLimit
+	}
+	limit = min(limit, maxOrderSearchLimit)
+
+	// Лишний спор показывает, что за страницей есть продолжение
+	disputes, err := s.orderRepo.ListOrderDisputes(ctx, status, limit+1, offset)
+	if err != nil {
+		logctx.From(ctx).Error("order service: failed to list disputes", zap.Error(err))
+		return nil, fmt.Errorf("order service: failed to list disputes: %w", err)
+	}
+
+	page := &domain.OrderDisputePage{Disputes: disputes}
+	if len(disputes) > limit {
+		page.Disputes = disputes[:limit]
+		page.HasMore = true
+	}
+	return page, nil
+}
+
+// ResolveDispute решает спор: передает заказ заявителю вместе с начислением или отклоняет спор.
+// Заявитель узнает о решении из события order.dispute_transferred или order.dispute_rejected
+func (s *OrderService) ResolveDispute(ctx context.Context, id int64, resolution domain.DisputeResolution, comment string, adminID int64) (*domain.OrderDispute, error) {
+	comment = strings.TrimSpace(comment)
+	if resolution != domain.DisputeResolutionTransfer && resolution != domain.DisputeResolutionReject {
+		return nil, fmt.Errorf("order service: unknown dispute resolution %q: %w", resolution, domain.ErrInvalidInput)
+	}
+	if len(comment) > domain.MaxDisputeCommentLength {
+		return nil, fmt.Errorf("order service: resolution comment must be at most %d bytes: %w", domain.MaxDisputeCommentLength, domain.ErrInvalidInput)
+	}
+
+	dispute, err := s.orderRepo.ResolveOrderDispute(ctx, id, resolution, comment, adminID)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrDisputeNotFound):
+			return nil, fmt.Errorf("order service: dispute %d not found: %w", id, err)
+		case errors.Is(err, domain.ErrDisputeResolved):
+			return nil, fmt.Errorf("order service: dispute %d already resolved: %w", id, err)
+		case errors.Is(err, domain.ErrOrderNotFound), errors.Is(err, domain.ErrOrderAlreadyOwned):
+			return nil, fmt.Errorf("order service: cannot transfer order of dispute %d: %w", id, err)
+		}
+		logctx.From(ctx).Error("order service: failed to resolve dispute", zap.Int64("dispute_id", id), zap.Error(err))
+		return nil, fmt.Errorf("order service: failed to resolve dispute %d: %w", id, err)
+	}
+
+	logctx.From(ctx).Info("order dispute resolved",
+		zap.Int64("dispute_id", id),
+		zap.String("order", dispute.OrderNumber),
+		zap.String("status", string(dispute.Status)),
+		zap.Int64("resolved_by", adminID),
+	)
+	return dispute, nil
+}
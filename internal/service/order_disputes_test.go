@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	domainmocks "github.com/avc/loyalty-system-diploma/internal/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderService_OpenDispute(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		repo := domainmocks.NewOrderRepositoryMock(t)
+		svc := NewOrderService(repo, nil, DefaultOrderNumberLimits(), nil, nil, nil)
+
+		expected := &domain.OrderDispute{ID: 1, OrderNumber: "79927398713", ClaimantID: 1, OwnerID: 2, Status: domain.DisputeStatusOpen}
+		repo.EXPECT().CreateOrderDispute(mock.Anything, int64(1), "79927398713", "my receipt").Return(expected, nil).Once()
+
+		dispute, err := svc.OpenDispute(ctx, 1, " 79927398713 ", " my receipt ")
+		require.NoError(t, err)
+		assert.Equal(t, expected, dispute)
+	})
+
+	t.Run("Repository errors", func(t *testing.T) {
+		for _, repoErr := range []error{domain.ErrOrderNotFound, domain.ErrOrderAlreadyOwned, domain.ErrDisputeExists, errors.New("db error")} {
+			repo := domainmocks.NewOrderRepositoryMock(t)
+			svc := NewOrderService(repo, nil, DefaultOrderNumberLimits(), nil, nil, nil)
+			repo.EXPECT().CreateOrderDispute(mock.Anything, int64(1), "79927398713", "").Return(nil, repoErr).Once()
+
+			_, err := svc.OpenDispute(ctx, 1, "79927398713", "")
+			assert.ErrorIs(t, err, repoErr)
+		}
+	})
+
+	t.Run("Invalid input", func(t *testing.T) {
+		svc := NewOrderService(domainmocks.NewOrderRepositoryMock(t), nil, DefaultOrderNumberLimits(), nil, nil, nil)
+
+		_, err := svc.OpenDispute(ctx, 1, "12345", "")
+		assert.ErrorIs(t, err, domain.ErrInvalidOrderNumber)
+
+		_, err = svc.OpenDispute(ctx, 1, "79927398713", strings.Repeat("a", domain.MaxDisputeCommentLength+1))
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	})
+
+	t.Run("Banned user", func(t *testing.T) {
+		banRepo := domainmocks.NewSubmissionBanRepositoryMock(t)
+		banRepo.EXPECT().GetSubmissionBan(mock.Anything, int64(1)).
+			Return(&domain.SubmissionBan{UserID: 1, BannedUntil: time.Now().Add(time.Hour)}, nil).Once()
+		guard := NewSubmissionGuard(banRepo, SubmissionGuardConfig{}, nil)
+		svc := NewOrderService(domainmocks.NewOrderRepositoryMock(t), nil, DefaultOrderNumberLimits(), guard, nil, nil)
+
+		_, err := svc.OpenDispute(ctx, 1, "79927398713", "")
+		assert.ErrorIs(t, err, domain.ErrSubmissionsBanned)
+	})
+}
+
+func TestOrderService_ListDisputes(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Page with more", func(t *testing.T) {
+		repo := domainmocks.NewOrderRepositoryMock(t)
+		svc := NewOrderService(repo, nil, DefaultOrderNumberLimits(), nil, nil, nil)
+
+		disputes := []*domain.OrderDispute{{ID: 1}, {ID: 2}, {ID: 3}}
+		repo.EXPECT().ListOrderDisputes(mock.Anything, domain.DisputeStatusOpen, 3, 4).Return(disputes, nil).Once()
+
+		page, err := svc.ListDisputes(ctx, domain.DisputeStatusOpen, 2, 4)
+		require.NoError(t, err)
+		assert.Len(t, page.Disputes, 2)
+		assert.True(t, page.HasMore)
+	})
+
+	t.Run("Default limit", func(t *testing.T) {
+		repo := domainmocks.NewOrderRepositoryMock(t)
+		svc := NewOrderService(repo, nil, DefaultOrderNumberLimits(), nil, nil, nil)
+		repo.EXPECT().ListOrderDisputes(mock.Anything, domain.DisputeStatus(""), defaultOrderSearchLimit+1, 0).Return(nil, nil).Once()
+
+		page, err := svc.ListDisputes(ctx, "", 0, 0)
+		require.NoError(t, err)
+		assert.False(t, page.HasMore)
+	})
+
+	t.Run("Invalid input", func(t *testing.T) {
+		svc := NewOrderService(domainmocks.NewOrderRepositoryMock(t), nil, DefaultOrderNumberLimits(), nil, nil, nil)
+
+		_, err := svc.ListDisputes(ctx, "closed", 0, 0)
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+		_, err = svc.ListDisputes(ctx, "", -1, 0)
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	})
+}
+
+func TestOrderService_ResolveDispute(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Transfer", func(t *testing.T) {
+		repo := domainmocks.NewOrderRepositoryMock(t)
+		svc := NewOrderService(repo, nil, DefaultOrderNumberLimits(), nil, nil, nil)
+
+		expected := &domain.OrderDispute{ID: 1, Status: domain.DisputeStatusTransferred}
+		repo.EXPECT().ResolveOrderDispute(mock.Anything, int64(1), domain.DisputeResolutionTransfer, "receipt checked", int64(9)).
+			Return(expected, nil).Once()
+
+		dispute, err := svc.ResolveDispute(ctx, 1, domain.DisputeResolutionTransfer, " receipt checked ", 9)
+		require.NoError(t, err)
+		assert.Equal(t, expected, dispute)
+	})
+
+	t.Run("Repository errors", func(t *testing.T) {
+		for _, repoErr := range []error{domain.ErrDisputeNotFound, domain.ErrDisputeResolved, domain.ErrOrderAlreadyOwned, errors.New("db error")} {
+			repo := domainmocks.NewOrderRepositoryMock(t)
+			svc := NewOrderService(repo, nil, DefaultOrderNumberLimits(), nil, nil, nil)
+			repo.EXPECT().ResolveOrderDispute(mock.Anything, int64(1), domain.DisputeResolutionReject, "", int64(9)).Return(nil, repoErr).Once()
+
+			_, err := svc.ResolveDispute(ctx, 1, domain.DisputeResolutionReject, "", 9)
+			assert.ErrorIs(t, err, repoErr)
+		}
+	})
+
+	t.Run("Unknown resolution", func(t *testing.T) {
+		svc := NewOrderService(domainmocks.NewOrderRepositoryMock(t), nil, DefaultOrderNumberLimits(), nil, nil, nil)
+
+		_, err := svc.ResolveDispute(ctx, 1, "approve", "", 9)
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	})
+}
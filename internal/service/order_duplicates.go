@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/avc/loyalty-system-diploma/internal/logctx"
+	"go.uber.org/zap"
+)
+
+// defaultDuplicateReportDays - период отчета о повторных загрузках по умолчанию
+const defaultDuplicateReportDays = 7
+
+// recordDuplicate учитывает повторную загрузку заказа тем же пользователем.
+// Ошибка записи счетчика не влияет на ответ клиенту
+func (s *OrderService) recordDuplicate(ctx context.Context, userID int64, metadata *domain.OrderMetadata) {
+	s.metrics.ObserveDuplicateOrder()
+
+	var channel string
+	if metadata != nil {
+		channel = metadata.Channel
+	}
+	if err := s.orderRepo.RecordDuplicateSubmission(ctx, userID, channel); err != nil {
+		logctx.From(ctx).Warn("order service: failed to record duplicate submission", zap.Error(err))
+	}
+}
+
+// DuplicateSubmissions возвращает отчет о повторных загрузках заказов за последние days суток
+// со страницей пользователей. Размер страницы - как у поиска заказов.
+func (s *OrderService) DuplicateSubmissions(ctx context.Context, days, limit, offset int) (*domain.DuplicateSubmissionReport, error) {
+	if days < 0 || days > domain.MaxDuplicateReportDays || limit < 0 || offset < 0 {
+		return nil, fmt.Errorf("order service: days must be within 0..%d, limit and offset must not be negative: %w",
+			domain.MaxDuplicateReportDays, domain.ErrInvalidInput)
+	}
+	if days == 0 {
+		days = defaultDuplicateReportDays
+	}
+	if limit == 0 {
+		limit = defaultOrderSearchLimit
+	}
+	limit = min(limit, maxOrderSearchLimit)
+
+	report, err := s.orderRepo.GetDuplicateSubmissionReport(ctx, days, limit+1, offset)
+	if err != nil {
+		logctx.From(ctx).Error("order service: failed to build duplicate submission report", zap.Error(err))
+		return nil, fmt.Errorf("order service: failed to build duplicate submission report: %w", err)
+	}
+
+	if len(report.Submitters) > limit {
+		report.Submitters = report.Submitters[:limit]
+		report.HasMore = true
+	}
+
+	return report, nil
+}
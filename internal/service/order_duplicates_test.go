@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	domainmocks "github.com/avc/loyalty-system-diploma/internal/domain/mocks"
+	"github.com/avc/loyalty-system-diploma/internal/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderService_SubmitOrder_Duplicate(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Recorded with channel", func(t *testing.T) {
+		repo := domainmocks.NewOrderRepositoryMock(t)
+		svc := NewOrderService(repo, nil, DefaultOrderNumberLimits(), nil, nil, metrics.New())
+
+		metadata := &domain.OrderMetadata{Channel: "mobile"}
+		repo.EXPECT().CreateOrder(mock.Anything, int64(1), "79927398713", metadata).
+			Return(&domain.Order{ID: 1, UserID: 1}, domain.ErrOrderExists).Once()
+		repo.EXPECT().RecordDuplicateSubmission(mock.Anything, int64(1), "mobile").Return(nil).Once()
+
+		err := svc.SubmitOrder(ctx, 1, "79927398713", &domain.OrderMetadata{Channel: " mobile "})
+		assert.ErrorIs(t, err, domain.ErrOrderExists)
+	})
+
+	t.Run("Recording failure does not change the result", func(t *testing.T) {
+		repo := domainmocks.NewOrderRepositoryMock(t)
+		svc := NewOrderService(repo, nil, DefaultOrderNumberLimits(), nil, nil, nil)
+
+		repo.EXPECT().CreateOrder(mock.Anything, int64(1), "79927398713", (*domain.OrderMetadata)(nil)).
+			Return(&domain.Order{ID: 1, UserID: 1}, domain.ErrOrderExists).Once()
+		repo.EXPECT().RecordDuplicateSubmission(mock.Anything, int64(1), "").Return(errors.New("db error")).Once()
+
+		err := svc.SubmitOrder(ctx, 1, "79927398713", nil)
+		assert.ErrorIs(t, err, domain.ErrOrderExists)
+	})
+
+	t.Run("Order of another user is not a duplicate", func(t *testing.T) {
+		repo := domainmocks.NewOrderRepositoryMock(t)
+		svc := NewOrderService(repo, nil, DefaultOrderNumberLimits(), nil, nil, nil)
+
+		repo.EXPECT().CreateOrder(mock.Anything, int64(1), "79927398713", (*domain.OrderMetadata)(nil)).
+			Return(nil, domain.ErrOrderOwnedByAnother).Once()
+
+		err := svc.SubmitOrder(ctx, 1, "79927398713", nil)
+		assert.ErrorIs(t, err, domain.ErrOrderOwnedByAnother)
+	})
+}
+
+func TestOrderService_DuplicateSubmissions(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Defaults and has more", func(t *testing.T) {
+		repo := domainmocks.NewOrderRepositoryMock(t)
+		svc := NewOrderService(repo, nil, DefaultOrderNumberLimits(), nil, nil, nil)
+
+		submitters := make([]*domain.DuplicateSubmitter, defaultOrderSearchLimit+1)
+		repo.EXPECT().GetDuplicateSubmissionReport(mock.Anything, defaultDuplicateReportDays, defaultOrderSearchLimit+1, 0).
+			Return(&domain.DuplicateSubmissionReport{Submitters: submitters}, nil).Once()
+
+		report, err := svc.DuplicateSubmissions(ctx, 0, 0, 0)
+		require.NoError(t, err)
+		assert.Len(t, report.Submitters, defaultOrderSearchLimit)
+		assert.True(t, report.HasMore)
+	})
+
+	t.Run("Limit is capped", func(t *testing.T) {
+		repo := domainmocks.NewOrderRepositoryMock(t)
+		svc := NewOrderService(repo, nil, DefaultOrderNumberLimits(), nil, nil, nil)
+
+		repo.EXPECT().GetDuplicateSubmissionReport(mock.Anything, 30, maxOrderSearchLimit+1, 10).
+			Return(&domain.DuplicateSubmissionReport{}, nil).Once()
+
+		report, err := svc.DuplicateSubmissions(ctx, 30, 1000, 10)
+		require.NoError(t, err)
+		assert.False(t, report.HasMore)
+	})
+
+	t.Run("Invalid parameters", func(t *testing.T) {
+		svc := NewOrderService(domainmocks.NewOrderRepositoryMock(t), nil, DefaultOrderNumberLimits(), nil, nil, nil)
+
+		for _, params := range [][3]int{{-1, 0, 0}, {domain.MaxDuplicateReportDays + 1, 0, 0}, {7, -1, 0}, {7, 0, -1}} {
+			_, err := svc.DuplicateSubmissions(ctx, params[0], params[1], params[2])
+			assert.ErrorIs(t, err, domain.ErrInvalidInput)
+		}
+	})
+
+	t.Run("Repository error", func(t *testing.T) {
+		repo := domainmocks.NewOrderRepositoryMock(t)
+		svc := NewOrderService(repo, nil, DefaultOrderNumberLimits(), nil, nil, nil)
+
+		repo.EXPECT().GetDuplicateSubmissionReport(mock.Anything, 7, 51, 0).Return(nil, errors.New("db error")).Once()
+
+		_, err := svc.DuplicateSubmissions(ctx, 7, 50, 0)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/avc/loyalty-system-diploma/internal/logctx"
+	"go.uber.org/zap"
+)
+
+// OrderExpiryRepository определяет поиск и завершение заказов, зависших в статусе PROCESSING.
+type OrderExpiryRepository interface {
+	FindStuckOrders(ctx context.Context, days, limit int) ([]*domain.Order, error)
+	ExpireStuckOrders(ctx context.Context, days, limit int) ([]string, error)
+}
+
+// orderExpiryBatch ограничивает число заказов, завершаемых одним запросом
+const orderExpiryBatch = 500
+
+// OrderExpiryConfig содержит настройки завершения зависших заказов
+type OrderExpiryConfig struct {
+	Days   int  // Сколько суток заказ может оставаться в PROCESSING (0 - не завершать)
+	DryRun bool // Только писать в лог, какие заказы были бы завершены
+}
+
+// OrderExpiryService переводит в INVALID заказы, которые система начислений
+// не обработала за отведенное время. Владелец узнает об этом из события order.expired.
+type OrderExpiryService struct {
+	repo   OrderExpiryRepository
+	config OrderExpiryConfig
+}
+
+// NewOrderExpiryService создает новый OrderExpiryService
+func NewOrderExpiryService(repo OrderExpiryRepository, config OrderExpiryConfig) *OrderExpiryService {
+	return &OrderExpiryService{
+		repo:   repo,
+		config: config,
+	}
+}
+
+// RunScheduled - задача расписания: завершает все зависшие заказы пачками,
+// в режиме dry-run только перечисляет первую пачку в логе
+func (s *OrderExpiryService) RunScheduled(ctx context.Context) error {
+	if s.config.Days <= 0 {
+		return nil
+	}
+	logger := logctx.From(ctx).With(zap.Int("expiry_days", s.config.Days))
+
+	if s.config.DryRun {
+		orders, err := s.repo.FindStuckOrders(ctx, s.config.Days, orderExpiryBatch)
+		if err != nil {
+			return fmt.Errorf("order expiry service: failed to find stuck orders: %w", err)
+		}
+		for _, order := range orders {
+			logger.Info("dry run: order would expire",
+				zap.String("order", order.Number),
+				zap.Int64("user_id", order.UserID),
+				zap.Time("uploaded_at", order.UploadedAt),
+			)
+		}
+		if len(orders) > 0 {
+			logger.Info("dry run: stuck orders found", zap.Int("orders", len(orders)))
+		}
+		return nil
+	}
+
+	var total int
+	for {
+		numbers, err := s.repo.ExpireStuckOrders(ctx, s.config.Days, orderExpiryBatch)
+		if err != nil {
+			return fmt.Errorf("order expiry service: failed to expire stuck orders after %d expired: %w", total, err)
+		}
+		for _, number := range numbers {
+			logger.Warn("stuck order expired", zap.String("order", number))
+		}
+		total += len(numbers)
+		if len(numbers) < orderExpiryBatch {
+			break
+		}
+	}
+
+	if total > 0 {
+		logger.Info("stuck orders expired", zap.Int("orders", total))
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	domainmocks "github.com/avc/loyalty-system-diploma/internal/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestOrderExpiryService_RunScheduled(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Disabled", func(t *testing.T) {
+		svc := NewOrderExpiryService(domainmocks.NewOrderExpiryRepositoryMock(t), OrderExpiryConfig{})
+		assert.NoError(t, svc.RunScheduled(ctx))
+	})
+
+	t.Run("Expires in batches", func(t *testing.T) {
+		repo := domainmocks.NewOrderExpiryRepositoryMock(t)
+		svc := NewOrderExpiryService(repo, OrderExpiryConfig{Days: 14})
+
+		repo.EXPECT().ExpireStuckOrders(mock.Anything, 14, orderExpiryBatch).
+			Return(make([]string, orderExpiryBatch), nil).Once()
+		repo.EXPECT().ExpireStuckOrders(mock.Anything, 14, orderExpiryBatch).
+			Return([]string{"12345678903"}, nil).Once()
+
+		assert.NoError(t, svc.RunScheduled(ctx))
+	})
+
+	t.Run("Dry run does not change orders", func(t *testing.T) {
+		repo := domainmocks.NewOrderExpiryRepositoryMock(t)
+		svc := NewOrderExpiryService(repo, OrderExpiryConfig{Days: 14, DryRun: true})
+
+		repo.EXPECT().FindStuckOrders(mock.Anything, 14, orderExpiryBatch).
+			Return([]*domain.Order{{Number: "12345678903", UserID: 1}}, nil).Once()
+
+		assert.NoError(t, svc.RunScheduled(ctx))
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		repo := domainmocks.NewOrderExpiryRepositoryMock(t)
+		svc := NewOrderExpiryService(repo, OrderExpiryConfig{Days: 14})
+
+		repo.EXPECT().ExpireStuckOrders(mock.Anything, 14, orderExpiryBatch).Return(nil, errors.New("db error")).Once()
+
+		assert.Error(t, svc.RunScheduled(ctx))
+	})
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/avc/loyalty-system-diploma/internal/utils/ordernum"
+)
+
+// OrderNumberLimits ограничивает длину принимаемых номеров заказов и задает их проверку
+type OrderNumberLimits struct {
+	MinLength int
+	MaxLength int // Не больше domain.MaxOrderNumberLength
+	// Проверка номера по источнику или префиксу, nil - алгоритм Луна для всех номеров
+	Validators *ordernum.Registry
+}
+
+// DefaultOrderNumberLimits возвращает ограничения по умолчанию
+func DefaultOrderNumberLimits() OrderNumberLimits {
+	return OrderNumberLimits{
+		MinLength: 2,
+		MaxLength: 32,
+	}
+}
+
+// normalize подставляет значения по умолчанию и не дает превысить ограничение БД
+func (l OrderNumberLimits) normalize() OrderNumberLimits {
+	defaults := DefaultOrderNumberLimits()
+	if l.MinLength <= 0 {
+		l.MinLength = defaults.MinLength
+	}
+	if l.MaxLength <= 0 {
+		l.MaxLength = defaults.MaxLength
+	}
+	l.MaxLength = min(l.MaxLength, domain.MaxOrderNumberLength)
+	l.MinLength = min(l.MinLength, l.MaxLength)
+	return l
+}
+
+// validate проверяет длину номера и сам номер алгоритмом его источника source:
+// канала продажи при загрузке заказа или партнера при списании.
+// Длина проверяется первой, чтобы не прогонять проверку по заведомо длинным строкам.
+func (l OrderNumberLimits) validate(number, source string) error {
+	if n := len(number); n < l.MinLength || n > l.MaxLength {
+		return fmt.Errorf("order number length %d is outside [%d, %d]: %w",
+			n, l.MinLength, l.MaxLength, domain.ErrInvalidOrderNumber)
+	}
+	if !l.Validators.Validate(number, source) {
+		return fmt.Errorf("order number fails %s check: %w",
+			l.Validators.Algorithm(number, source), domain.ErrInvalidOrderNumber)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/avc/loyalty-system-diploma/internal/utils/ordernum"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderNumberLimits_Normalize(t *testing.T) {
+	assert.Equal(t, DefaultOrderNumberLimits(), OrderNumberLimits{}.normalize())
+
+	// Максимум не превышает ограничение колонки в БД
+	limits := OrderNumberLimits{MinLength: 100, MaxLength: 1000}.normalize()
+	assert.Equal(t, domain.MaxOrderNumberLength, limits.MaxLength)
+	assert.Equal(t, domain.MaxOrderNumberLength, limits.MinLength)
+}
+
+func TestOrderNumberLimits_Validate(t *testing.T) {
+	limits := OrderNumberLimits{MinLength: 4, MaxLength: 12}.normalize()
+
+	tests := []struct {
+		name    string
+		number  string
+		wantErr bool
+	}{
+		{name: "valid", number: "79927398713"},
+		{name: "too short", number: "00", wantErr: true},
+		{name: "too long", number: strings.Repeat("0", 13), wantErr: true},
+		{name: "fails Luhn", number: "79927398710", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := limits.validate(tt.number, "")
+			if tt.wantErr {
+				assert.ErrorIs(t, err, domain.ErrInvalidOrderNumber)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestOrderNumberLimits_Validators(t *testing.T) {
+	validators, err := ordernum.NewRegistry([]ordernum.Rule{
+		{Prefix: "77", Algorithm: ordernum.AlgorithmDigits},
+		{Source: "cinema", Algorithm: ordernum.AlgorithmAlnum},
+	})
+	require.NoError(t, err)
+	limits := OrderNumberLimits{Validators: validators}.normalize()
+
+	assert.NoError(t, limits.validate("7700", ""))
+	assert.NoError(t, limits.validate("TICKET42", "cinema"))
+	assert.ErrorIs(t, limits.validate("TICKET42", ""), domain.ErrInvalidOrderNumber)
+	// Длина проверяется для всех алгоритмов
+	assert.ErrorIs(t, limits.validate(strings.Repeat("7", 33), ""), domain.ErrInvalidOrderNumber)
+}
@@ -4,61 +4,378 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/avc/loyalty-system-diploma/internal/domain"
-	"github.com/avc/loyalty-system-diploma/internal/repository/postgres"
-	"github.com/avc/loyalty-system-diploma/internal/utils/luhn"
+	"github.com/avc/loyalty-system-diploma/internal/lock"
+	"github.com/avc/loyalty-system-diploma/internal/logctx"
+	"github.com/avc/loyalty-system-diploma/internal/metrics"
+	"github.com/avc/loyalty-system-diploma/internal/tracing"
+	"github.com/avc/loyalty-system-diploma/internal/utils/fiscalqr"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
 )
 
 // OrderRepository определяет методы для работы с заказами.
 type OrderRepository interface {
-	CreateOrder(ctx context.Context, userID int64, number string) (*domain.Order, error)
+	CreateOrder(ctx context.Context, userID int64, number string, metadata *domain.OrderMetadata) (*domain.Order, error)
 	GetOrderByNumber(ctx context.Context, number string) (*domain.Order, error)
-	GetOrdersByUserID(ctx context.Context, userID int64) ([]*domain.Order, error)
-	UpdateOrderStatus(ctx context.Context, number string, status domain.OrderStatus, accrual *float64) error
-	GetPendingOrders(ctx context.Context) ([]*domain.Order, error)
+	GetOrderByPublicID(ctx context.Context, userID int64, publicID uuid.UUID) (*domain.Order, error)
+	GetOrdersByUserID(ctx context.Context, userID int64, filter domain.OrderFilter) ([]*domain.Order, error)
+	GetOrdersByNumbers(ctx context.Context, numbers []string) ([]*domain.Order, error)
+	SearchOrders(ctx context.Context, filter domain.OrderSearchFilter) ([]*domain.AdminOrder, error)
+	FindAccrualMismatches(ctx context.Context, limit, offset int) ([]*domain.AccrualMismatch, error)
+	ApplyAccrual(ctx context.Context, number string, status domain.OrderStatus, accrual *domain.Accrual) error
+	CorrectAccrual(ctx context.Context, number string, accrual *domain.Accrual, correctedBy int64) (*domain.AccrualCorrection, error)
+	TransferOrder(ctx context.Context, number, toLogin string, transferredBy int64) (*domain.OrderTransfer, error)
+	GetPendingOrders(ctx context.Context, policies []domain.OrderScanPolicy) ([]*domain.Order, error)
+	GetPendingAccrual(ctx context.Context, userID int64) (*domain.PendingAccrual, error)
+	RecordDuplicateSubmission(ctx context.Context, userID int64, channel string) error
+	GetDuplicateSubmissionReport(ctx context.Context, days, limit, offset int) (*domain.DuplicateSubmissionReport, error)
+	CreateOrderDispute(ctx context.Context, claimantID int64, number, comment string) (*domain.OrderDispute, error)
+	ListOrderDisputes(ctx context.Context, status domain.DisputeStatus, limit, offset int) ([]*domain.OrderDispute, error)
+	ResolveOrderDispute(ctx context.Context, id int64, resolution domain.DisputeResolution, comment string, resolvedBy int64) (*domain.OrderDispute, error)
+}
+
+// Размер страницы поиска заказов администратором
+const (
+	defaultOrderSearchLimit = 50
+	maxOrderSearchLimit     = 100
+)
+
+// OrderNotifier получает уведомление о новом заказе, чтобы начать его обработку без ожидания.
+type OrderNotifier interface {
+	NotifyNewOrder()
 }
 
 // OrderService предоставляет операции с заказами.
 type OrderService struct {
-	orderRepo OrderRepository
+	orderRepo    OrderRepository
+	notifier     OrderNotifier
+	numberLimits OrderNumberLimits
+	guard        *SubmissionGuard
+	locker       Locker
+	metrics      *metrics.Metrics
 }
 
-// NewOrderService создает новый OrderService
-func NewOrderService(orderRepo OrderRepository) *OrderService {
+// NewOrderService создает новый OrderService. notifier, guard, locker и m могут быть nil.
+// locker не дает отчету о расхождениях застать сверку на другой реплике.
+func NewOrderService(orderRepo OrderRepository, notifier OrderNotifier, numberLimits OrderNumberLimits, guard *SubmissionGuard, locker Locker, m *metrics.Metrics) *OrderService {
 	return &OrderService{
-		orderRepo: orderRepo,
+		orderRepo:    orderRepo,
+		notifier:     notifier,
+		numberLimits: numberLimits.normalize(),
+		guard:        guard,
+		locker:       locker,
+		metrics:      m,
 	}
 }
 
-// SubmitOrder принимает номер заказа для обработки
-func (s *OrderService) SubmitOrder(ctx context.Context, userID int64, orderNumber string) error {
-	// Валидация номера заказа по алгоритму Луна
-	if !luhn.Validate(orderNumber) {
-		return ErrInvalidOrderNumber
+// SubmitOrder принимает номер заказа для обработки, metadata может быть nil
+func (s *OrderService) SubmitOrder(ctx context.Context, userID int64, orderNumber string, metadata *domain.OrderMetadata) error {
+	ctx, span := tracing.Start(ctx, "OrderService.SubmitOrder", attribute.Int64("user.id", userID))
+	defer span.End()
+
+	metadata, err := normalizeOrderMetadata(metadata)
+	if err != nil {
+		return err
+	}
+
+	// Валидация длины и контрольной цифры номера заказа по правилам канала продажи
+	var channel string
+	if metadata != nil {
+		channel = metadata.Channel
+	}
+	if err := s.numberLimits.validate(orderNumber, channel); err != nil {
+		return err
+	}
+
+	// Ограничение частоты загрузок и запрет после перебора чужих номеров
+	if err := s.guard.Allow(ctx, userID); err != nil {
+		return fmt.Errorf("order service: submission of order %q rejected: %w", orderNumber, err)
 	}
 
 	// Создание заказа
-	_, err := s.orderRepo.CreateOrder(ctx, userID, orderNumber)
+	_, err = s.orderRepo.CreateOrder(ctx, userID, orderNumber, metadata)
 	if err != nil {
-		if errors.Is(err, postgres.ErrOrderExists) {
-			return fmt.Errorf("order service: order %q already exists: %w", orderNumber, ErrOrderExists)
+		if errors.Is(err, domain.ErrOrderExists) {
+			s.recordDuplicate(ctx, userID, metadata)
+			return fmt.Errorf("order service: order %q already exists: %w", orderNumber, err)
 		}
-		if errors.Is(err, postgres.ErrOrderOwnedByAnother) {
-			return fmt.Errorf("order service: order %q belongs to another user: %w", orderNumber, ErrOrderOwnedByAnother)
+		if errors.Is(err, domain.ErrOrderOwnedByAnother) {
+			s.guard.RecordConflict(ctx, userID)
+			return fmt.Errorf("order service: order %q belongs to another user: %w", orderNumber, err)
 		}
+		tracing.Fail(span, err)
+		logctx.From(ctx).Error("order service: failed to create order",
+			zap.String("order", orderNumber),
+			zap.Error(err),
+		)
 		return fmt.Errorf("order service: failed to submit order %q: %w", orderNumber, err)
 	}
 
+	if s.notifier != nil {
+		s.notifier.NotifyNewOrder()
+	}
+
+	logctx.From(ctx).Debug("order submitted", zap.String("order", orderNumber))
 	return nil
 }
 
-// GetOrders получает все заказы пользователя
-func (s *OrderService) GetOrders(ctx context.Context, userID int64) ([]*domain.Order, error) {
-	orders, err := s.orderRepo.GetOrdersByUserID(ctx, userID)
+// SubmitFiscalReceipt принимает заказ по содержимому QR кода кассового чека и возвращает
+// выведенный из чека номер заказа. Баллы начисляются только за чеки прихода.
+// Номер возвращается и вместе с ошибкой загрузки, если его удалось вывести
+func (s *OrderService) SubmitFiscalReceipt(ctx context.Context, userID int64, payload string) (string, error) {
+	receipt, err := fiscalqr.Parse(payload)
 	if err != nil {
+		return "", fmt.Errorf("order service: %w: %w", domain.ErrInvalidFiscalReceipt, err)
+	}
+	if receipt.Operation != fiscalqr.OperationSale {
+		return "", fmt.Errorf("order service: receipt operation %d is not a sale: %w", receipt.Operation, domain.ErrInvalidFiscalReceipt)
+	}
+	if err := domain.ValidateAmount(receipt.Sum); err != nil {
+		return "", fmt.Errorf("order service: receipt sum: %w", err)
+	}
+
+	number := receipt.OrderNumber()
+	metadata := &domain.OrderMetadata{Channel: domain.FiscalReceiptChannel, ReceiptID: receipt.FP}
+	return number, s.SubmitOrder(ctx, userID, number, metadata)
+}
+
+// GetOrders получает заказы пользователя, подходящие под фильтр, новые первыми.
+// Нулевой Limit - все заказы, слишком большой ограничивается размером страницы поиска заказов
+func (s *OrderService) GetOrders(ctx context.Context, userID int64, filter domain.OrderFilter) (*domain.OrderPage, error) {
+	ctx, span := tracing.Start(ctx, "OrderService.GetOrders", attribute.Int64("user.id", userID))
+	defer span.End()
+
+	if filter.Limit < 0 || filter.Offset < 0 {
+		return nil, fmt.Errorf("order service: negative limit or offset: %w", domain.ErrInvalidInput)
+	}
+	if filter.Status != "" && !filter.Status.IsValid() {
+		return nil, fmt.Errorf("order service: unknown order status %q: %w", filter.Status, domain.ErrInvalidInput)
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return nil, fmt.Errorf("order service: period start must be before its end: %w", domain.ErrInvalidInput)
+	}
+
+	// Лишний заказ показывает, что за страницей есть продолжение
+	limit := min(filter.Limit, maxOrderSearchLimit)
+	if limit > 0 {
+		filter.Limit = limit + 1
+	}
+
+	orders, err := s.orderRepo.GetOrdersByUserID(ctx, userID, filter)
+	if err != nil {
+		tracing.Fail(span, err)
+		logctx.From(ctx).Error("order service: failed to get orders", zap.Error(err))
 		return nil, fmt.Errorf("order service: failed to get orders for user %d: %w", userID, err)
 	}
 
+	page := &domain.OrderPage{Orders: orders}
+	if limit > 0 && len(orders) > limit {
+		page.Orders = orders[:limit]
+		page.HasMore = true
+	}
+	return page, nil
+}
+
+// GetOrder получает заказ пользователя по публичному идентификатору
+func (s *OrderService) GetOrder(ctx context.Context, userID int64, publicID uuid.UUID) (*domain.Order, error) {
+	order, err := s.orderRepo.GetOrderByPublicID(ctx, userID, publicID)
+	if err != nil {
+		if errors.Is(err, domain.ErrOrderNotFound) {
+			return nil, fmt.Errorf("order service: order %s not found: %w", publicID, err)
+		}
+		logctx.From(ctx).Error("order service: failed to get order", zap.Error(err))
+		return nil, fmt.Errorf("order service: failed to get order %s: %w", publicID, err)
+	}
+
+	return order, nil
+}
+
+// GetOrderByNumber получает заказ пользователя по номеру.
+// Чужой заказ считается ненайденным, чтобы не раскрывать номера других пользователей.
+func (s *OrderService) GetOrderByNumber(ctx context.Context, userID int64, number string) (*domain.Order, error) {
+	order, err := s.orderRepo.GetOrderByNumber(ctx, number)
+	if err != nil {
+		if errors.Is(err, domain.ErrOrderNotFound) {
+			return nil, fmt.Errorf("order service: order %q not found: %w", number, err)
+		}
+		logctx.From(ctx).Error("order service: failed to get order", zap.Error(err))
+		return nil, fmt.Errorf("order service: failed to get order %q: %w", number, err)
+	}
+	if order.UserID != userID {
+		return nil, fmt.Errorf("order service: order %q not found: %w", number, domain.ErrOrderNotFound)
+	}
+
+	return order, nil
+}
+
+// GetOrderStatuses получает заказы по номерам для внутренних сервисов, без проверки владельца.
+// Повторяющиеся номера запрашиваются один раз, ненайденных номеров в результате нет.
+func (s *OrderService) GetOrderStatuses(ctx context.Context, numbers []string) ([]*domain.Order, error) {
+	if len(numbers) == 0 || len(numbers) > domain.MaxOrderStatusBatch {
+		return nil, fmt.Errorf("order service: expected 1 to %d order numbers, got %d: %w",
+			domain.MaxOrderStatusBatch, len(numbers), domain.ErrInvalidInput)
+	}
+
+	seen := make(map[string]bool, len(numbers))
+	unique := make([]string, 0, len(numbers))
+	for _, number := range numbers {
+		if number == "" || len(number) > domain.MaxOrderNumberLength {
+			return nil, fmt.Errorf("order service: invalid order number %q: %w", number, domain.ErrInvalidInput)
+		}
+		if !seen[number] {
+			seen[number] = true
+			unique = append(unique, number)
+		}
+	}
+
+	orders, err := s.orderRepo.GetOrdersByNumbers(ctx, unique)
+	if err != nil {
+		logctx.From(ctx).Error("order service: failed to get order statuses", zap.Error(err))
+		return nil, fmt.Errorf("order service: failed to get %d order statuses: %w", len(unique), err)
+	}
+
 	return orders, nil
 }
+
+// SearchOrders ищет заказы всех пользователей для службы поддержки.
+// Нулевой Limit заменяется значением по умолчанию, слишком большой - ограничивается.
+func (s *OrderService) SearchOrders(ctx context.Context, filter domain.OrderSearchFilter) (*domain.OrderSearchResult, error) {
+	if err := validateOrderSearchFilter(&filter); err != nil {
+		return nil, err
+	}
+
+	// Лишний заказ показывает, что за страницей есть продолжение
+	limit := filter.Limit
+	filter.Limit++
+
+	orders, err := s.orderRepo.SearchOrders(ctx, filter)
+	if err != nil {
+		logctx.From(ctx).Error("order service: failed to search orders", zap.Error(err))
+		return nil, fmt.Errorf("order service: failed to search orders: %w", err)
+	}
+
+	result := &domain.OrderSearchResult{Orders: orders}
+	if len(orders) > limit {
+		result.Orders = orders[:limit]
+		result.HasMore = true
+	}
+
+	return result, nil
+}
+
+// AccrualMismatches возвращает страницу отчета о заказах, начисление которых
+// расходится с журналом транзакций. Размер страницы - как у поиска заказов.
+// Отчет строится под блокировкой сверки, поэтому не показывает заказы, которые
+// в этот момент корректируются или исправляются.
+func (s *OrderService) AccrualMismatches(ctx context.Context, limit, offset int) (*domain.AccrualMismatchReport, error) {
+	if limit < 0 || offset < 0 {
+		return nil, fmt.Errorf("order service: limit and offset must not be negative: %w", domain.ErrInvalidInput)
+	}
+	if limit == 0 {
+		limit = defaultOrderSearchLimit
+	}
+	limit = min(limit, maxOrderSearchLimit)
+
+	unlock, err := lockKeys(ctx, s.locker, lock.ReconciliationKey)
+	if err != nil {
+		return nil, fmt.Errorf("order service: failed to lock reconciliation: %w", err)
+	}
+	defer unlock()
+
+	mismatches, err := s.orderRepo.FindAccrualMismatches(ctx, limit+1, offset)
+	if err != nil {
+		logctx.From(ctx).Error("order service: failed to find accrual mismatches", zap.Error(err))
+		return nil, fmt.Errorf("order service: failed to find accrual mismatches: %w", err)
+	}
+
+	report := &domain.AccrualMismatchReport{Mismatches: mismatches}
+	if len(mismatches) > limit {
+		report.Mismatches = mismatches[:limit]
+		report.HasMore = true
+	}
+
+	return report, nil
+}
+
+// TransferOrder передает заказ пользователю с логином login вместе с начислением по нему.
+// Используется, когда покупатель загрузил заказ не в ту учетную запись.
+func (s *OrderService) TransferOrder(ctx context.Context, number, login string, adminID int64) (*domain.OrderTransfer, error) {
+	login = strings.TrimSpace(login)
+	if number == "" || login == "" {
+		return nil, fmt.Errorf("order service: order number and login are required: %w", domain.ErrInvalidInput)
+	}
+
+	transfer, err := s.orderRepo.TransferOrder(ctx, number, login, adminID)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrOrderNotFound):
+			return nil, fmt.Errorf("order service: order %q not found: %w", number, err)
+		case errors.Is(err, domain.ErrUserNotFound):
+			return nil, fmt.Errorf("order service: user %q not found: %w", login, err)
+		case errors.Is(err, domain.ErrOrderAlreadyOwned):
+			return nil, fmt.Errorf("order service: order %q already belongs to %q: %w", number, login, err)
+		}
+		logctx.From(ctx).Error("order service: failed to transfer order",
+			zap.String("order", number), zap.String("login", login), zap.Error(err))
+		return nil, fmt.Errorf("order service: failed to transfer order %q: %w", number, err)
+	}
+
+	logctx.From(ctx).Info("order transferred",
+		zap.String("order", number),
+		zap.Int64("from_user_id", transfer.FromUserID),
+		zap.Int64("to_user_id", transfer.ToUserID),
+		zap.Float64("amount", transfer.Amount),
+		zap.Int64("transferred_by", adminID),
+	)
+	return transfer, nil
+}
+
+// validateOrderSearchFilter проверяет фильтр поиска и подставляет размер страницы
+func validateOrderSearchFilter(filter *domain.OrderSearchFilter) error {
+	filter.NumberPrefix = strings.TrimSpace(filter.NumberPrefix)
+	if len(filter.NumberPrefix) > domain.MaxOrderNumberLength || strings.Trim(filter.NumberPrefix, "0123456789") != "" {
+		return fmt.Errorf("order service: number prefix must contain up to %d digits: %w", domain.MaxOrderNumberLength, domain.ErrInvalidInput)
+	}
+	if filter.Status != "" && !filter.Status.IsValid() {
+		return fmt.Errorf("order service: unknown order status %q: %w", filter.Status, domain.ErrInvalidInput)
+	}
+	if err := filter.Metadata.Validate(); err != nil {
+		return fmt.Errorf("order service: metadata filter fields must be at most %d bytes: %w", domain.MaxOrderMetadataFieldLength, domain.ErrInvalidInput)
+	}
+	if filter.Limit < 0 || filter.Offset < 0 {
+		return fmt.Errorf("order service: limit and offset must not be negative: %w", domain.ErrInvalidInput)
+	}
+
+	if filter.Limit == 0 {
+		filter.Limit = defaultOrderSearchLimit
+	}
+	filter.Limit = min(filter.Limit, maxOrderSearchLimit)
+
+	return nil
+}
+
+// normalizeOrderMetadata обрезает пробелы в полях и проверяет их длину.
+// Пустые метаданные не сохраняются.
+func normalizeOrderMetadata(metadata *domain.OrderMetadata) (*domain.OrderMetadata, error) {
+	if metadata == nil {
+		return nil, nil
+	}
+
+	normalized := domain.OrderMetadata{
+		Channel:   strings.TrimSpace(metadata.Channel),
+		StoreID:   strings.TrimSpace(metadata.StoreID),
+		ReceiptID: strings.TrimSpace(metadata.ReceiptID),
+	}
+	if normalized.IsEmpty() {
+		return nil, nil
+	}
+	if err := normalized.Validate(); err != nil {
+		return nil, fmt.Errorf("order service: metadata fields must be at most %d bytes: %w", domain.MaxOrderMetadataFieldLength, err)
+	}
+
+	return &normalized, nil
+}
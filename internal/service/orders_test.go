@@ -3,12 +3,14 @@ package service
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/avc/loyalty-system-diploma/internal/domain"
 	domainmocks "github.com/avc/loyalty-system-diploma/internal/domain/mocks"
-	"github.com/avc/loyalty-system-diploma/internal/repository/postgres"
+	"github.com/avc/loyalty-system-diploma/internal/utils/ordernum"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -22,6 +24,7 @@ func TestOrderService_SubmitOrder(t *testing.T) {
 		userID      int64
 		orderNumber string
 		setupMock   func(*domainmocks.OrderRepositoryMock)
+		wantNotify  bool
 		wantErr     error
 	}{
 		{
@@ -30,41 +33,50 @@ func TestOrderService_SubmitOrder(t *testing.T) {
 			orderNumber: "79927398713", // Valid Luhn
 			setupMock: func(m *domainmocks.OrderRepositoryMock) {
 				order := &domain.Order{ID: 1, UserID: 1, Number: "79927398713", Status: domain.OrderStatusNew}
-				m.EXPECT().CreateOrder(mock.Anything, int64(1), "79927398713").Return(order, nil).Once()
+				m.EXPECT().CreateOrder(mock.Anything, int64(1), "79927398713", (*domain.OrderMetadata)(nil)).Return(order, nil).Once()
 			},
-			wantErr: nil,
+			wantNotify: true,
+			wantErr:    nil,
 		},
 		{
 			name:        "Invalid order number - fails Luhn",
 			userID:      1,
 			orderNumber: "12345", // Invalid Luhn
 			setupMock:   func(m *domainmocks.OrderRepositoryMock) {},
-			wantErr:     ErrInvalidOrderNumber,
+			wantErr:     domain.ErrInvalidOrderNumber,
+		},
+		{
+			name:        "Invalid order number - too long",
+			userID:      1,
+			orderNumber: strings.Repeat("0", 1000), // Нули проходят алгоритм Луна
+			setupMock:   func(m *domainmocks.OrderRepositoryMock) {},
+			wantErr:     domain.ErrInvalidOrderNumber,
 		},
 		{
 			name:        "Order already exists - same user",
 			userID:      1,
 			orderNumber: "79927398713",
 			setupMock: func(m *domainmocks.OrderRepositoryMock) {
-				m.EXPECT().CreateOrder(mock.Anything, int64(1), "79927398713").Return(nil, postgres.ErrOrderExists).Once()
+				m.EXPECT().CreateOrder(mock.Anything, int64(1), "79927398713", (*domain.OrderMetadata)(nil)).Return(nil, domain.ErrOrderExists).Once()
+				m.EXPECT().RecordDuplicateSubmission(mock.Anything, int64(1), "").Return(nil).Once()
 			},
-			wantErr: ErrOrderExists,
+			wantErr: domain.ErrOrderExists,
 		},
 		{
 			name:        "Order owned by another user",
 			userID:      1,
 			orderNumber: "79927398713",
 			setupMock: func(m *domainmocks.OrderRepositoryMock) {
-				m.EXPECT().CreateOrder(mock.Anything, int64(1), "79927398713").Return(nil, postgres.ErrOrderOwnedByAnother).Once()
+				m.EXPECT().CreateOrder(mock.Anything, int64(1), "79927398713", (*domain.OrderMetadata)(nil)).Return(nil, domain.ErrOrderOwnedByAnother).Once()
 			},
-			wantErr: ErrOrderOwnedByAnother,
+			wantErr: domain.ErrOrderOwnedByAnother,
 		},
 		{
 			name:        "Database error",
 			userID:      1,
 			orderNumber: "79927398713",
 			setupMock: func(m *domainmocks.OrderRepositoryMock) {
-				m.EXPECT().CreateOrder(mock.Anything, int64(1), "79927398713").Return(nil, errors.New("db error")).Once()
+				m.EXPECT().CreateOrder(mock.Anything, int64(1), "79927398713", (*domain.OrderMetadata)(nil)).Return(nil, errors.New("db error")).Once()
 			},
 			wantErr: nil, // Generic error, just check error exists
 		},
@@ -73,11 +85,15 @@ func TestOrderService_SubmitOrder(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockOrderRepo := domainmocks.NewOrderRepositoryMock(t)
-			svc := NewOrderService(mockOrderRepo)
+			mockNotifier := domainmocks.NewOrderNotifierMock(t)
+			svc := NewOrderService(mockOrderRepo, mockNotifier, DefaultOrderNumberLimits(), nil, nil, nil)
 
 			tt.setupMock(mockOrderRepo)
+			if tt.wantNotify {
+				mockNotifier.EXPECT().NotifyNewOrder().Once()
+			}
 
-			err := svc.SubmitOrder(ctx, tt.userID, tt.orderNumber)
+			err := svc.SubmitOrder(ctx, tt.userID, tt.orderNumber, nil)
 
 			if tt.wantErr != nil {
 				assert.ErrorIs(t, err, tt.wantErr)
@@ -90,6 +106,111 @@ func TestOrderService_SubmitOrder(t *testing.T) {
 	}
 }
 
+func TestOrderService_SubmitOrder_Metadata(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Fields are trimmed", func(t *testing.T) {
+		repo := domainmocks.NewOrderRepositoryMock(t)
+		svc := NewOrderService(repo, nil, DefaultOrderNumberLimits(), nil, nil, nil)
+
+		expected := &domain.OrderMetadata{Channel: "pos", StoreID: "42"}
+		repo.EXPECT().CreateOrder(mock.Anything, int64(1), "79927398713", expected).
+			Return(&domain.Order{ID: 1}, nil).Once()
+
+		err := svc.SubmitOrder(ctx, 1, "79927398713", &domain.OrderMetadata{Channel: " pos ", StoreID: "42"})
+		require.NoError(t, err)
+	})
+
+	t.Run("Empty metadata is not stored", func(t *testing.T) {
+		repo := domainmocks.NewOrderRepositoryMock(t)
+		svc := NewOrderService(repo, nil, DefaultOrderNumberLimits(), nil, nil, nil)
+
+		repo.EXPECT().CreateOrder(mock.Anything, int64(1), "79927398713", (*domain.OrderMetadata)(nil)).
+			Return(&domain.Order{ID: 1}, nil).Once()
+
+		err := svc.SubmitOrder(ctx, 1, "79927398713", &domain.OrderMetadata{Channel: "  "})
+		require.NoError(t, err)
+	})
+
+	t.Run("Channel selects number validator", func(t *testing.T) {
+		validators, err := ordernum.NewRegistry([]ordernum.Rule{{Source: "cinema", Algorithm: ordernum.AlgorithmAlnum}})
+		require.NoError(t, err)
+		repo := domainmocks.NewOrderRepositoryMock(t)
+		svc := NewOrderService(repo, nil, OrderNumberLimits{Validators: validators}, nil, nil, nil)
+
+		expected := &domain.OrderMetadata{Channel: "cinema"}
+		repo.EXPECT().CreateOrder(mock.Anything, int64(1), "TICKET42", expected).
+			Return(&domain.Order{ID: 1}, nil).Once()
+
+		require.NoError(t, svc.SubmitOrder(ctx, 1, "TICKET42", &domain.OrderMetadata{Channel: "cinema"}))
+		assert.ErrorIs(t, svc.SubmitOrder(ctx, 1, "TICKET42", nil), domain.ErrInvalidOrderNumber)
+	})
+
+	t.Run("Too long field", func(t *testing.T) {
+		repo := domainmocks.NewOrderRepositoryMock(t)
+		svc := NewOrderService(repo, nil, DefaultOrderNumberLimits(), nil, nil, nil)
+
+		metadata := &domain.OrderMetadata{ReceiptID: strings.Repeat("r", domain.MaxOrderMetadataFieldLength+1)}
+		err := svc.SubmitOrder(ctx, 1, "79927398713", metadata)
+		assert.ErrorIs(t, err, domain.ErrInvalidOrderMetadata)
+	})
+}
+
+func TestOrderService_SubmitFiscalReceipt(t *testing.T) {
+	ctx := context.Background()
+	const payload = "t=20200924T1837&s=349.93&fn=9282440300682838&i=46534&fp=1273019065&n=1"
+
+	t.Run("Success", func(t *testing.T) {
+		repo := domainmocks.NewOrderRepositoryMock(t)
+		svc := NewOrderService(repo, nil, DefaultOrderNumberLimits(), nil, nil, nil)
+
+		expected := &domain.OrderMetadata{Channel: domain.FiscalReceiptChannel, ReceiptID: "1273019065"}
+		repo.EXPECT().CreateOrder(mock.Anything, int64(1), "9282440300682838465342", expected).
+			Return(&domain.Order{ID: 1}, nil).Once()
+
+		number, err := svc.SubmitFiscalReceipt(ctx, 1, payload)
+		require.NoError(t, err)
+		assert.Equal(t, "9282440300682838465342", number)
+	})
+
+	t.Run("Already submitted", func(t *testing.T) {
+		repo := domainmocks.NewOrderRepositoryMock(t)
+		svc := NewOrderService(repo, nil, DefaultOrderNumberLimits(), nil, nil, nil)
+
+		repo.EXPECT().CreateOrder(mock.Anything, int64(1), "9282440300682838465342", mock.Anything).
+			Return(nil, domain.ErrOrderExists).Once()
+		repo.EXPECT().RecordDuplicateSubmission(mock.Anything, int64(1), domain.FiscalReceiptChannel).Return(nil).Once()
+
+		number, err := svc.SubmitFiscalReceipt(ctx, 1, payload)
+		assert.ErrorIs(t, err, domain.ErrOrderExists)
+		assert.Equal(t, "9282440300682838465342", number)
+	})
+
+	t.Run("Invalid payload", func(t *testing.T) {
+		svc := NewOrderService(domainmocks.NewOrderRepositoryMock(t), nil, DefaultOrderNumberLimits(), nil, nil, nil)
+
+		_, err := svc.SubmitFiscalReceipt(ctx, 1, "12345678903")
+		assert.ErrorIs(t, err, domain.ErrInvalidFiscalReceipt)
+	})
+
+	t.Run("Refund receipt", func(t *testing.T) {
+		svc := NewOrderService(domainmocks.NewOrderRepositoryMock(t), nil, DefaultOrderNumberLimits(), nil, nil, nil)
+
+		_, err := svc.SubmitFiscalReceipt(ctx, 1, strings.Replace(payload, "n=1", "n=2", 1))
+		assert.ErrorIs(t, err, domain.ErrInvalidFiscalReceipt)
+	})
+
+	t.Run("Invalid sum", func(t *testing.T) {
+		svc := NewOrderService(domainmocks.NewOrderRepositoryMock(t), nil, DefaultOrderNumberLimits(), nil, nil, nil)
+
+		for _, sum := range []string{"0", "-5", "1.005", "100000000"} {
+			_, err := svc.SubmitFiscalReceipt(ctx, 1, strings.Replace(payload, "s=349.93", "s="+sum, 1))
+			var amountErr *domain.AmountError
+			assert.ErrorAs(t, err, &amountErr, sum)
+		}
+	})
+}
+
 func TestOrderService_GetOrders(t *testing.T) {
 	ctx := context.Background()
 
@@ -108,7 +229,7 @@ func TestOrderService_GetOrders(t *testing.T) {
 					{ID: 1, UserID: 1, Number: "111", Status: domain.OrderStatusProcessed, UploadedAt: time.Now()},
 					{ID: 2, UserID: 1, Number: "222", Status: domain.OrderStatusNew, UploadedAt: time.Now()},
 				}
-				m.EXPECT().GetOrdersByUserID(mock.Anything, int64(1)).Return(orders, nil).Once()
+				m.EXPECT().GetOrdersByUserID(mock.Anything, int64(1), domain.OrderFilter{}).Return(orders, nil).Once()
 				return orders
 			},
 			wantOrders: 2,
@@ -117,7 +238,7 @@ func TestOrderService_GetOrders(t *testing.T) {
 			name:   "No orders",
 			userID: 999,
 			setupMock: func(m *domainmocks.OrderRepositoryMock) []*domain.Order {
-				m.EXPECT().GetOrdersByUserID(mock.Anything, int64(999)).Return([]*domain.Order{}, nil).Once()
+				m.EXPECT().GetOrdersByUserID(mock.Anything, int64(999), domain.OrderFilter{}).Return([]*domain.Order{}, nil).Once()
 				return nil
 			},
 			wantOrders: 0,
@@ -126,7 +247,7 @@ func TestOrderService_GetOrders(t *testing.T) {
 			name:   "Database error",
 			userID: 1,
 			setupMock: func(m *domainmocks.OrderRepositoryMock) []*domain.Order {
-				m.EXPECT().GetOrdersByUserID(mock.Anything, int64(1)).Return(nil, errors.New("db error")).Once()
+				m.EXPECT().GetOrdersByUserID(mock.Anything, int64(1), domain.OrderFilter{}).Return(nil, errors.New("db error")).Once()
 				return nil
 			},
 			wantErr: true,
@@ -136,22 +257,400 @@ func TestOrderService_GetOrders(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockOrderRepo := domainmocks.NewOrderRepositoryMock(t)
-			svc := NewOrderService(mockOrderRepo)
+			svc := NewOrderService(mockOrderRepo, nil, DefaultOrderNumberLimits(), nil, nil, nil)
 
 			expectedOrders := tt.setupMock(mockOrderRepo)
 
-			result, err := svc.GetOrders(ctx, tt.userID)
+			result, err := svc.GetOrders(ctx, tt.userID, domain.OrderFilter{})
 
 			if tt.wantErr {
 				assert.Error(t, err)
 				assert.Nil(t, result)
 			} else {
 				require.NoError(t, err)
-				assert.Len(t, result, tt.wantOrders)
+				assert.Len(t, result.Orders, tt.wantOrders)
+				assert.False(t, result.HasMore)
 				if expectedOrders != nil {
-					assert.Equal(t, expectedOrders, result)
+					assert.Equal(t, expectedOrders, result.Orders)
 				}
 			}
 		})
 	}
 }
+
+func TestOrderService_GetOrders_Filter(t *testing.T) {
+	ctx := context.Background()
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+
+	t.Run("Page", func(t *testing.T) {
+		repo := domainmocks.NewOrderRepositoryMock(t)
+		orders := []*domain.Order{{Number: "111"}, {Number: "222"}, {Number: "333"}}
+		// Лишний заказ показывает, что за страницей есть продолжение
+		repo.EXPECT().GetOrdersByUserID(mock.Anything, int64(1), domain.OrderFilter{
+			Status: domain.OrderStatusProcessed, From: from, To: to, Limit: 3, Offset: 4,
+		}).Return(orders, nil).Once()
+
+		page, err := NewOrderService(repo, nil, DefaultOrderNumberLimits(), nil, nil, nil).GetOrders(ctx, 1, domain.OrderFilter{
+			Status: domain.OrderStatusProcessed, From: from, To: to, Limit: 2, Offset: 4,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, orders[:2], page.Orders)
+		assert.True(t, page.HasMore)
+	})
+
+	t.Run("Limit is capped", func(t *testing.T) {
+		repo := domainmocks.NewOrderRepositoryMock(t)
+		repo.EXPECT().GetOrdersByUserID(mock.Anything, int64(1), domain.OrderFilter{Limit: maxOrderSearchLimit + 1}).Return(nil, nil).Once()
+
+		page, err := NewOrderService(repo, nil, DefaultOrderNumberLimits(), nil, nil, nil).GetOrders(ctx, 1, domain.OrderFilter{Limit: 10000})
+		require.NoError(t, err)
+		assert.False(t, page.HasMore)
+	})
+
+	invalid := map[string]domain.OrderFilter{
+		"Negative offset": {Offset: -1},
+		"Unknown status":  {Status: "LOST"},
+		"Empty period":    {From: to, To: from},
+	}
+	for name, filter := range invalid {
+		t.Run(name, func(t *testing.T) {
+			svc := NewOrderService(domainmocks.NewOrderRepositoryMock(t), nil, DefaultOrderNumberLimits(), nil, nil, nil)
+
+			_, err := svc.GetOrders(ctx, 1, filter)
+			assert.ErrorIs(t, err, domain.ErrInvalidInput)
+		})
+	}
+}
+
+func TestOrderService_GetOrder(t *testing.T) {
+	ctx := context.Background()
+	publicID := uuid.New()
+
+	t.Run("Success", func(t *testing.T) {
+		repo := domainmocks.NewOrderRepositoryMock(t)
+		svc := NewOrderService(repo, nil, DefaultOrderNumberLimits(), nil, nil, nil)
+
+		order := &domain.Order{ID: 1, PublicID: publicID, UserID: 1, Number: "12345678903"}
+		repo.EXPECT().GetOrderByPublicID(mock.Anything, int64(1), publicID).Return(order, nil).Once()
+
+		result, err := svc.GetOrder(ctx, 1, publicID)
+		require.NoError(t, err)
+		assert.Equal(t, order, result)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		repo := domainmocks.NewOrderRepositoryMock(t)
+		svc := NewOrderService(repo, nil, DefaultOrderNumberLimits(), nil, nil, nil)
+
+		repo.EXPECT().GetOrderByPublicID(mock.Anything, int64(1), publicID).Return(nil, domain.ErrOrderNotFound).Once()
+
+		result, err := svc.GetOrder(ctx, 1, publicID)
+		assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+		assert.Nil(t, result)
+	})
+
+	t.Run("Database error", func(t *testing.T) {
+		repo := domainmocks.NewOrderRepositoryMock(t)
+		svc := NewOrderService(repo, nil, DefaultOrderNumberLimits(), nil, nil, nil)
+
+		repo.EXPECT().GetOrderByPublicID(mock.Anything, int64(1), publicID).Return(nil, errors.New("db error")).Once()
+
+		result, err := svc.GetOrder(ctx, 1, publicID)
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, domain.ErrOrderNotFound)
+		assert.Nil(t, result)
+	})
+}
+
+func TestOrderService_GetOrderByNumber(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		repo := domainmocks.NewOrderRepositoryMock(t)
+		svc := NewOrderService(repo, nil, DefaultOrderNumberLimits(), nil, nil, nil)
+
+		order := &domain.Order{ID: 1, UserID: 1, Number: "12345678903"}
+		repo.EXPECT().GetOrderByNumber(mock.Anything, "12345678903").Return(order, nil).Once()
+
+		result, err := svc.GetOrderByNumber(ctx, 1, "12345678903")
+		require.NoError(t, err)
+		assert.Equal(t, order, result)
+	})
+
+	t.Run("Order of another user", func(t *testing.T) {
+		repo := domainmocks.NewOrderRepositoryMock(t)
+		svc := NewOrderService(repo, nil, DefaultOrderNumberLimits(), nil, nil, nil)
+
+		order := &domain.Order{ID: 1, UserID: 2, Number: "12345678903"}
+		repo.EXPECT().GetOrderByNumber(mock.Anything, "12345678903").Return(order, nil).Once()
+
+		result, err := svc.GetOrderByNumber(ctx, 1, "12345678903")
+		assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+		assert.Nil(t, result)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		repo := domainmocks.NewOrderRepositoryMock(t)
+		svc := NewOrderService(repo, nil, DefaultOrderNumberLimits(), nil, nil, nil)
+
+		repo.EXPECT().GetOrderByNumber(mock.Anything, "12345678903").Return(nil, domain.ErrOrderNotFound).Once()
+
+		result, err := svc.GetOrderByNumber(ctx, 1, "12345678903")
+		assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+		assert.Nil(t, result)
+	})
+
+	t.Run("Database error", func(t *testing.T) {
+		repo := domainmocks.NewOrderRepositoryMock(t)
+		svc := NewOrderService(repo, nil, DefaultOrderNumberLimits(), nil, nil, nil)
+
+		repo.EXPECT().GetOrderByNumber(mock.Anything, "12345678903").Return(nil, errors.New("db error")).Once()
+
+		result, err := svc.GetOrderByNumber(ctx, 1, "12345678903")
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, domain.ErrOrderNotFound)
+		assert.Nil(t, result)
+	})
+}
+
+func TestOrderService_GetOrderStatuses(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Duplicates requested once", func(t *testing.T) {
+		mockOrderRepo := domainmocks.NewOrderRepositoryMock(t)
+		svc := NewOrderService(mockOrderRepo, nil, DefaultOrderNumberLimits(), nil, nil, nil)
+
+		orders := []*domain.Order{{ID: 1, UserID: 7, Number: "111", Status: domain.OrderStatusProcessed}}
+		mockOrderRepo.EXPECT().GetOrdersByNumbers(mock.Anything, []string{"111", "222"}).Return(orders, nil).Once()
+
+		result, err := svc.GetOrderStatuses(ctx, []string{"111", "222", "111"})
+		require.NoError(t, err)
+		assert.Equal(t, orders, result)
+	})
+
+	t.Run("Invalid batch", func(t *testing.T) {
+		svc := NewOrderService(domainmocks.NewOrderRepositoryMock(t), nil, DefaultOrderNumberLimits(), nil, nil, nil)
+
+		tooMany := make([]string, domain.MaxOrderStatusBatch+1)
+		for i := range tooMany {
+			tooMany[i] = "111"
+		}
+		for _, numbers := range [][]string{nil, tooMany, {"111", ""}, {strings.Repeat("1", domain.MaxOrderNumberLength+1)}} {
+			_, err := svc.GetOrderStatuses(ctx, numbers)
+			assert.ErrorIs(t, err, domain.ErrInvalidInput)
+		}
+	})
+
+	t.Run("Database error", func(t *testing.T) {
+		mockOrderRepo := domainmocks.NewOrderRepositoryMock(t)
+		svc := NewOrderService(mockOrderRepo, nil, DefaultOrderNumberLimits(), nil, nil, nil)
+		mockOrderRepo.EXPECT().GetOrdersByNumbers(mock.Anything, []string{"111"}).Return(nil, errors.New("db error")).Once()
+
+		_, err := svc.GetOrderStatuses(ctx, []string{"111"})
+		assert.Error(t, err)
+	})
+}
+
+func TestOrderService_SearchOrders(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Default page size", func(t *testing.T) {
+		repo := domainmocks.NewOrderRepositoryMock(t)
+		svc := NewOrderService(repo, nil, DefaultOrderNumberLimits(), nil, nil, nil)
+
+		orders := []*domain.AdminOrder{{Login: "alice"}}
+		repo.EXPECT().SearchOrders(mock.Anything, domain.OrderSearchFilter{
+			NumberPrefix: "9278",
+			Limit:        defaultOrderSearchLimit + 1,
+		}).Return(orders, nil).Once()
+
+		result, err := svc.SearchOrders(ctx, domain.OrderSearchFilter{NumberPrefix: " 9278 "})
+		require.NoError(t, err)
+		assert.Equal(t, orders, result.Orders)
+		assert.False(t, result.HasMore)
+	})
+
+	t.Run("Extra order means more pages", func(t *testing.T) {
+		repo := domainmocks.NewOrderRepositoryMock(t)
+		svc := NewOrderService(repo, nil, DefaultOrderNumberLimits(), nil, nil, nil)
+
+		orders := []*domain.AdminOrder{{Login: "a"}, {Login: "b"}, {Login: "c"}}
+		repo.EXPECT().SearchOrders(mock.Anything, domain.OrderSearchFilter{Login: "a", Limit: 3, Offset: 4}).
+			Return(orders, nil).Once()
+
+		result, err := svc.SearchOrders(ctx, domain.OrderSearchFilter{Login: "a", Limit: 2, Offset: 4})
+		require.NoError(t, err)
+		assert.Equal(t, orders[:2], result.Orders)
+		assert.True(t, result.HasMore)
+	})
+
+	t.Run("Page size is capped", func(t *testing.T) {
+		repo := domainmocks.NewOrderRepositoryMock(t)
+		svc := NewOrderService(repo, nil, DefaultOrderNumberLimits(), nil, nil, nil)
+
+		repo.EXPECT().SearchOrders(mock.Anything, domain.OrderSearchFilter{Limit: maxOrderSearchLimit + 1}).
+			Return(nil, nil).Once()
+
+		result, err := svc.SearchOrders(ctx, domain.OrderSearchFilter{Limit: 1000})
+		require.NoError(t, err)
+		assert.Empty(t, result.Orders)
+	})
+
+	invalid := []struct {
+		name   string
+		filter domain.OrderSearchFilter
+	}{
+		{"Non-digit prefix", domain.OrderSearchFilter{NumberPrefix: "12%"}},
+		{"Too long prefix", domain.OrderSearchFilter{NumberPrefix: strings.Repeat("1", domain.MaxOrderNumberLength+1)}},
+		{"Unknown status", domain.OrderSearchFilter{Status: "DONE"}},
+		{"Too long metadata", domain.OrderSearchFilter{Metadata: domain.OrderMetadata{StoreID: strings.Repeat("s", domain.MaxOrderMetadataFieldLength+1)}}},
+		{"Negative offset", domain.OrderSearchFilter{Offset: -1}},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewOrderService(domainmocks.NewOrderRepositoryMock(t), nil, DefaultOrderNumberLimits(), nil, nil, nil)
+
+			result, err := svc.SearchOrders(ctx, tt.filter)
+			assert.ErrorIs(t, err, domain.ErrInvalidInput)
+			assert.Nil(t, result)
+		})
+	}
+
+	t.Run("Database error", func(t *testing.T) {
+		repo := domainmocks.NewOrderRepositoryMock(t)
+		svc := NewOrderService(repo, nil, DefaultOrderNumberLimits(), nil, nil, nil)
+
+		repo.EXPECT().SearchOrders(mock.Anything, mock.Anything).Return(nil, errors.New("db error")).Once()
+
+		result, err := svc.SearchOrders(ctx, domain.OrderSearchFilter{})
+		assert.Error(t, err)
+		assert.Nil(t, result)
+	})
+}
+
+func TestOrderService_AccrualMismatches(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Default page size", func(t *testing.T) {
+		repo := domainmocks.NewOrderRepositoryMock(t)
+		svc := NewOrderService(repo, nil, DefaultOrderNumberLimits(), nil, nil, nil)
+
+		mismatches := []*domain.AccrualMismatch{{OrderNumber: "12345678903"}}
+		repo.EXPECT().FindAccrualMismatches(mock.Anything, defaultOrderSearchLimit+1, 0).Return(mismatches, nil).Once()
+
+		report, err := svc.AccrualMismatches(ctx, 0, 0)
+		require.NoError(t, err)
+		assert.Equal(t, mismatches, report.Mismatches)
+		assert.False(t, report.HasMore)
+	})
+
+	t.Run("Extra mismatch means more pages", func(t *testing.T) {
+		repo := domainmocks.NewOrderRepositoryMock(t)
+		svc := NewOrderService(repo, nil, DefaultOrderNumberLimits(), nil, nil, nil)
+
+		mismatches := []*domain.AccrualMismatch{{OrderNumber: "1"}, {OrderNumber: "2"}}
+		repo.EXPECT().FindAccrualMismatches(mock.Anything, 2, 10).Return(mismatches, nil).Once()
+
+		report, err := svc.AccrualMismatches(ctx, 1, 10)
+		require.NoError(t, err)
+		assert.Equal(t, mismatches[:1], report.Mismatches)
+		assert.True(t, report.HasMore)
+	})
+
+	t.Run("Under reconciliation lock", func(t *testing.T) {
+		repo := domainmocks.NewOrderRepositoryMock(t)
+		locker := domainmocks.NewLockerMock(t)
+		svc := NewOrderService(repo, nil, DefaultOrderNumberLimits(), nil, locker, nil)
+		unlocked := false
+
+		locker.EXPECT().Lock(mock.Anything, "reconciliation").Return(func() { unlocked = true }, nil).Once()
+		repo.EXPECT().FindAccrualMismatches(mock.Anything, defaultOrderSearchLimit+1, 0).Return(nil, nil).Once()
+
+		_, err := svc.AccrualMismatches(ctx, 0, 0)
+		require.NoError(t, err)
+		assert.True(t, unlocked)
+	})
+
+	t.Run("Lock error", func(t *testing.T) {
+		locker := domainmocks.NewLockerMock(t)
+		svc := NewOrderService(domainmocks.NewOrderRepositoryMock(t), nil, DefaultOrderNumberLimits(), nil, locker, nil)
+
+		locker.EXPECT().Lock(mock.Anything, "reconciliation").Return(nil, context.Canceled).Once()
+
+		_, err := svc.AccrualMismatches(ctx, 0, 0)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("Page size is capped", func(t *testing.T) {
+		repo := domainmocks.NewOrderRepositoryMock(t)
+		svc := NewOrderService(repo, nil, DefaultOrderNumberLimits(), nil, nil, nil)
+
+		repo.EXPECT().FindAccrualMismatches(mock.Anything, maxOrderSearchLimit+1, 0).Return(nil, nil).Once()
+
+		report, err := svc.AccrualMismatches(ctx, 1000, 0)
+		require.NoError(t, err)
+		assert.Empty(t, report.Mismatches)
+	})
+
+	t.Run("Negative offset", func(t *testing.T) {
+		svc := NewOrderService(domainmocks.NewOrderRepositoryMock(t), nil, DefaultOrderNumberLimits(), nil, nil, nil)
+
+		_, err := svc.AccrualMismatches(ctx, 0, -1)
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	})
+
+	t.Run("Repository error", func(t *testing.T) {
+		repo := domainmocks.NewOrderRepositoryMock(t)
+		svc := NewOrderService(repo, nil, DefaultOrderNumberLimits(), nil, nil, nil)
+
+		repo.EXPECT().FindAccrualMismatches(mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("boom")).Once()
+
+		_, err := svc.AccrualMismatches(ctx, 0, 0)
+		assert.Error(t, err)
+	})
+}
+
+func TestOrderService_TransferOrder(t *testing.T) {
+	ctx := context.Background()
+	number := "12345678903"
+
+	t.Run("Success", func(t *testing.T) {
+		repo := domainmocks.NewOrderRepositoryMock(t)
+		svc := NewOrderService(repo, nil, DefaultOrderNumberLimits(), nil, nil, nil)
+
+		transfer := &domain.OrderTransfer{OrderNumber: number, FromUserID: 7, ToUserID: 8, ToLogin: "bob", Amount: 500, TransferredBy: 1}
+		repo.EXPECT().TransferOrder(mock.Anything, number, "bob", int64(1)).Return(transfer, nil).Once()
+
+		result, err := svc.TransferOrder(ctx, number, " bob ", 1)
+		require.NoError(t, err)
+		assert.Equal(t, transfer, result)
+	})
+
+	t.Run("Login required", func(t *testing.T) {
+		svc := NewOrderService(domainmocks.NewOrderRepositoryMock(t), nil, DefaultOrderNumberLimits(), nil, nil, nil)
+
+		_, err := svc.TransferOrder(ctx, number, "  ", 1)
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	})
+
+	t.Run("Known errors are passed through", func(t *testing.T) {
+		for _, want := range []error{domain.ErrOrderNotFound, domain.ErrUserNotFound, domain.ErrOrderAlreadyOwned} {
+			repo := domainmocks.NewOrderRepositoryMock(t)
+			svc := NewOrderService(repo, nil, DefaultOrderNumberLimits(), nil, nil, nil)
+			repo.EXPECT().TransferOrder(mock.Anything, number, "bob", int64(1)).Return(nil, want).Once()
+
+			_, err := svc.TransferOrder(ctx, number, "bob", 1)
+			assert.ErrorIs(t, err, want)
+		}
+	})
+
+	t.Run("Repository error", func(t *testing.T) {
+		repo := domainmocks.NewOrderRepositoryMock(t)
+		svc := NewOrderService(repo, nil, DefaultOrderNumberLimits(), nil, nil, nil)
+		repo.EXPECT().TransferOrder(mock.Anything, number, "bob", int64(1)).Return(nil, errors.New("boom")).Once()
+
+		_, err := svc.TransferOrder(ctx, number, "bob", 1)
+		assert.Error(t, err)
+	})
+}
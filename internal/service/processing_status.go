@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/avc/loyalty-system-diploma/internal/logctx"
+	"go.uber.org/zap"
+)
+
+// ProcessingStatusRepository определяет подсчет состояния обработки заказов.
+type ProcessingStatusRepository interface {
+	GetProcessingStatus(ctx context.Context, since time.Time) (*domain.ProcessingStatus, error)
+}
+
+// ProcessingStatusConfig содержит настройки подсчета состояния обработки
+type ProcessingStatusConfig struct {
+	Window   time.Duration // За какой период усредняется время обработки
+	CacheTTL time.Duration // Сколько переиспользуется последний подсчет
+}
+
+// DefaultProcessingStatusConfig возвращает конфигурацию по умолчанию
+func DefaultProcessingStatusConfig() ProcessingStatusConfig {
+	return ProcessingStatusConfig{
+		Window:   time.Hour,
+		CacheTTL: 30 * time.Second,
+	}
+}
+
+// ProcessingStatusService сообщает, сколько обычно ждать начисления по новому заказу.
+// Запрос публичный, поэтому подсчет кэшируется и БД опрашивается не чаще раза за CacheTTL.
+type ProcessingStatusService struct {
+	repo   ProcessingStatusRepository
+	config ProcessingStatusConfig
+	now    func() time.Time
+
+	mu     sync.Mutex
+	cached *domain.ProcessingStatus
+}
+
+// NewProcessingStatusService создает новый ProcessingStatusService
+func NewProcessingStatusService(repo ProcessingStatusRepository, config ProcessingStatusConfig) *ProcessingStatusService {
+	defaults := DefaultProcessingStatusConfig()
+	if config.Window <= 0 {
+		config.Window = defaults.Window
+	}
+	if config.CacheTTL <= 0 {
+		config.CacheTTL = defaults.CacheTTL
+	}
+	return &ProcessingStatusService{
+		repo:   repo,
+		config: config,
+		now:    time.Now,
+	}
+}
+
+// GetProcessingStatus возвращает состояние обработки заказов, не старше CacheTTL.
+// Одновременные запросы с устаревшим кэшем ждут один подсчет.
+func (s *ProcessingStatusService) GetProcessingStatus(ctx context.Context) (*domain.ProcessingStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if s.cached != nil && now.Sub(s.cached.UpdatedAt) < s.config.CacheTTL {
+		return s.cached, nil
+	}
+
+	status, err := s.repo.GetProcessingStatus(ctx, now.Add(-s.config.Window))
+	if err != nil {
+		logctx.From(ctx).Error("processing status service: failed to get processing status", zap.Error(err))
+		return nil, fmt.Errorf("processing status service: failed to get processing status: %w", err)
+	}
+	status.UpdatedAt = now
+	s.cached = status
+
+	return status, nil
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	domainmocks "github.com/avc/loyalty-system-diploma/internal/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewProcessingStatusService_Defaults(t *testing.T) {
+	svc := NewProcessingStatusService(nil, ProcessingStatusConfig{})
+
+	assert.Equal(t, DefaultProcessingStatusConfig(), svc.config)
+}
+
+func TestProcessingStatusService_GetProcessingStatus(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	t.Run("Cached within TTL", func(t *testing.T) {
+		repo := domainmocks.NewProcessingStatusRepositoryMock(t)
+		svc := NewProcessingStatusService(repo, ProcessingStatusConfig{Window: time.Hour, CacheTTL: 30 * time.Second})
+		svc.now = func() time.Time { return now }
+
+		repo.EXPECT().GetProcessingStatus(mock.Anything, now.Add(-time.Hour)).
+			Return(&domain.ProcessingStatus{AverageLatency: 2 * time.Minute, Processed: 10, Backlog: 3}, nil).Once()
+
+		status, err := svc.GetProcessingStatus(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, &domain.ProcessingStatus{AverageLatency: 2 * time.Minute, Processed: 10, Backlog: 3, UpdatedAt: now}, status)
+
+		// Повторный запрос в пределах CacheTTL не обращается к БД
+		svc.now = func() time.Time { return now.Add(29 * time.Second) }
+		cached, err := svc.GetProcessingStatus(ctx)
+		require.NoError(t, err)
+		assert.Same(t, status, cached)
+	})
+
+	t.Run("Refreshed after TTL", func(t *testing.T) {
+		repo := domainmocks.NewProcessingStatusRepositoryMock(t)
+		svc := NewProcessingStatusService(repo, ProcessingStatusConfig{Window: time.Hour, CacheTTL: 30 * time.Second})
+		svc.now = func() time.Time { return now }
+
+		repo.EXPECT().GetProcessingStatus(mock.Anything, mock.Anything).Return(&domain.ProcessingStatus{Backlog: 1}, nil).Once()
+		_, err := svc.GetProcessingStatus(ctx)
+		require.NoError(t, err)
+
+		later := now.Add(30 * time.Second)
+		svc.now = func() time.Time { return later }
+		repo.EXPECT().GetProcessingStatus(mock.Anything, later.Add(-time.Hour)).Return(&domain.ProcessingStatus{Backlog: 5}, nil).Once()
+
+		status, err := svc.GetProcessingStatus(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 5, status.Backlog)
+		assert.Equal(t, later, status.UpdatedAt)
+	})
+
+	t.Run("Error is not cached", func(t *testing.T) {
+		repo := domainmocks.NewProcessingStatusRepositoryMock(t)
+		svc := NewProcessingStatusService(repo, ProcessingStatusConfig{})
+		svc.now = func() time.Time { return now }
+
+		repo.EXPECT().GetProcessingStatus(mock.Anything, mock.Anything).Return(nil, errors.New("db error")).Once()
+		status, err := svc.GetProcessingStatus(ctx)
+		assert.Error(t, err)
+		assert.Nil(t, status)
+
+		repo.EXPECT().GetProcessingStatus(mock.Anything, mock.Anything).Return(&domain.ProcessingStatus{}, nil).Once()
+		_, err = svc.GetProcessingStatus(ctx)
+		assert.NoError(t, err)
+	})
+}
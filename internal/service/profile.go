@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/avc/loyalty-system-diploma/internal/locale"
+	"github.com/avc/loyalty-system-diploma/internal/logctx"
+	"go.uber.org/zap"
+)
+
+// UserProfileRepository определяет хранение настроек пользователей
+type UserProfileRepository interface {
+	GetUserLocale(ctx context.Context, userID int64) (string, error)
+	SetUserLocale(ctx context.Context, userID int64, locale string) error
+}
+
+// ProfileService управляет настройками пользователей
+type ProfileService struct {
+	repo UserProfileRepository
+}
+
+// NewProfileService создает новый ProfileService
+func NewProfileService(repo UserProfileRepository) *ProfileService {
+	return &ProfileService{repo: repo}
+}
+
+// GetProfile возвращает настройки пользователя
+func (s *ProfileService) GetProfile(ctx context.Context, userID int64) (*domain.UserProfile, error) {
+	stored, err := s.repo.GetUserLocale(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil, err
+		}
+		logctx.From(ctx).Error("profile service: failed to get profile", zap.Error(err))
+		return nil, fmt.Errorf("profile service: failed to get profile of user %d: %w", userID, err)
+	}
+	return &domain.UserProfile{Locale: stored}, nil
+}
+
+// UpdateProfile проверяет и сохраняет настройки пользователя.
+// Язык приводится к базовому коду ("ru-RU" сохраняется как "ru"), пустой язык сбрасывает выбор
+func (s *ProfileService) UpdateProfile(ctx context.Context, userID int64, profile domain.UserProfile) (*domain.UserProfile, error) {
+	var normalized domain.UserProfile
+	if profile.Locale != "" {
+		l, err := locale.Parse(profile.Locale)
+		if err != nil {
+			return nil, fmt.Errorf("profile service: locale %q: %w", profile.Locale, domain.ErrUnsupportedLocale)
+		}
+		normalized.Locale = string(l)
+	}
+
+	if err := s.repo.SetUserLocale(ctx, userID, normalized.Locale); err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil, err
+		}
+		logctx.From(ctx).Error("profile service: failed to set profile", zap.Error(err))
+		return nil, fmt.Errorf("profile service: failed to set profile of user %d: %w", userID, err)
+	}
+	return &normalized, nil
+}
+
+// PreferredLocale возвращает язык, выбранный пользователем. false - язык не выбран
+// или не загрузился: ответ тогда формируется на языке из Accept-Language
+func (s *ProfileService) PreferredLocale(ctx context.Context, userID int64) (locale.Locale, bool) {
+	stored, err := s.repo.GetUserLocale(ctx, userID)
+	if err != nil {
+		logctx.From(ctx).Warn("profile service: failed to get user locale", zap.Int64("user_id", userID), zap.Error(err))
+		return "", false
+	}
+	if stored == "" {
+		return "", false
+	}
+	l, err := locale.Parse(stored)
+	if err != nil {
+		return "", false
+	}
+	return l, true
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	domainmocks "github.com/avc/loyalty-system-diploma/internal/domain/mocks"
+	"github.com/avc/loyalty-system-diploma/internal/locale"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfileService_UpdateProfile(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Normalizes locale before saving", func(t *testing.T) {
+		repo := domainmocks.NewUserProfileRepositoryMock(t)
+		svc := NewProfileService(repo)
+
+		repo.EXPECT().SetUserLocale(mock.Anything, int64(1), "ru").Return(nil).Once()
+
+		profile, err := svc.UpdateProfile(ctx, 1, domain.UserProfile{Locale: "ru-RU"})
+		require.NoError(t, err)
+		assert.Equal(t, &domain.UserProfile{Locale: "ru"}, profile)
+	})
+
+	t.Run("Empty locale resets choice", func(t *testing.T) {
+		repo := domainmocks.NewUserProfileRepositoryMock(t)
+		svc := NewProfileService(repo)
+
+		repo.EXPECT().SetUserLocale(mock.Anything, int64(1), "").Return(nil).Once()
+
+		profile, err := svc.UpdateProfile(ctx, 1, domain.UserProfile{})
+		require.NoError(t, err)
+		assert.Equal(t, &domain.UserProfile{}, profile)
+	})
+
+	t.Run("Unsupported locale", func(t *testing.T) {
+		svc := NewProfileService(domainmocks.NewUserProfileRepositoryMock(t))
+
+		_, err := svc.UpdateProfile(ctx, 1, domain.UserProfile{Locale: "de"})
+		assert.ErrorIs(t, err, domain.ErrUnsupportedLocale)
+	})
+
+	t.Run("User not found", func(t *testing.T) {
+		repo := domainmocks.NewUserProfileRepositoryMock(t)
+		svc := NewProfileService(repo)
+
+		repo.EXPECT().SetUserLocale(mock.Anything, int64(1), "en").Return(domain.ErrUserNotFound).Once()
+
+		_, err := svc.UpdateProfile(ctx, 1, domain.UserProfile{Locale: "en"})
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	})
+}
+
+func TestProfileService_PreferredLocale(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		stored   string
+		err      error
+		want     locale.Locale
+		wantBool bool
+	}{
+		{name: "Chosen", stored: "ru", want: locale.Russian, wantBool: true},
+		{name: "Not chosen", stored: ""},
+		{name: "No longer supported", stored: "de"},
+		{name: "Storage error", err: errors.New("db is down")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := domainmocks.NewUserProfileRepositoryMock(t)
+			svc := NewProfileService(repo)
+
+			repo.EXPECT().GetUserLocale(mock.Anything, int64(1)).Return(tt.stored, tt.err).Once()
+
+			got, ok := svc.PreferredLocale(ctx, 1)
+			assert.Equal(t, tt.wantBool, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/avc/loyalty-system-diploma/internal/logctx"
+	"go.uber.org/zap"
+)
+
+// RewardRuleSource определяет получение правил вознаграждения из системы начислений.
+type RewardRuleSource interface {
+	GetRewardRules(ctx context.Context) ([]domain.RewardRule, error)
+}
+
+// RewardRuleRepository определяет хранение локальной копии правил вознаграждения.
+type RewardRuleRepository interface {
+	SyncRewardRules(ctx context.Context, rules []domain.RewardRule, fingerprint string) (*domain.RewardRuleSync, error)
+	ListRewardRules(ctx context.Context) (*domain.RewardRuleSet, error)
+}
+
+// RewardRuleService поддерживает локальную копию правил вознаграждения системы начислений,
+// чтобы отчеты и предварительный расчет начислений не обращались к ней
+type RewardRuleService struct {
+	source RewardRuleSource
+	repo   RewardRuleRepository
+}
+
+// NewRewardRuleService создает новый RewardRuleService.
+// source == nil - система начислений не отдает правила, синхронизация недоступна
+func NewRewardRuleService(source RewardRuleSource, repo RewardRuleRepository) *RewardRuleService {
+	return &RewardRuleService{
+		source: source,
+		repo:   repo,
+	}
+}
+
+// SyncRewardRules загружает правила из системы начислений и обновляет локальную копию.
+// Если правила не изменились с прошлой синхронизации, копия не переписывается
+func (s *RewardRuleService) SyncRewardRules(ctx context.Context) (*domain.RewardRuleSync, error) {
+	if s.source == nil {
+		return nil, fmt.Errorf("reward rule service: %w", domain.ErrRewardRulesUnavailable)
+	}
+
+	rules, err := s.source.GetRewardRules(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reward rule service: failed to get reward rules: %w", err)
+	}
+	rules = normalizeRewardRules(rules)
+
+	sync, err := s.repo.SyncRewardRules(ctx, rules, rewardRulesFingerprint(rules))
+	if err != nil {
+		return nil, fmt.Errorf("reward rule service: failed to sync reward rules: %w", err)
+	}
+
+	if sync.Changed {
+		logctx.From(ctx).Info("reward rules synced",
+			zap.Int("rules", len(rules)),
+			zap.Int("added", sync.Added),
+			zap.Int("updated", sync.Updated),
+			zap.Int("removed", sync.Removed),
+		)
+	}
+	return sync, nil
+}
+
+// RunScheduled - задача расписания: синхронизирует правила. Если система начислений
+// не отдает правила, это пишется в лог, а не считается ошибкой
+func (s *RewardRuleService) RunScheduled(ctx context.Context) error {
+	_, err := s.SyncRewardRules(ctx)
+	if errors.Is(err, domain.ErrRewardRulesUnavailable) {
+		logctx.From(ctx).Warn("accrual system does not expose reward rules, sync skipped")
+		return nil
+	}
+	return err
+}
+
+// ListRewardRules возвращает локальную копию правил
+func (s *RewardRuleService) ListRewardRules(ctx context.Context) (*domain.RewardRuleSet, error) {
+	set, err := s.repo.ListRewardRules(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reward rule service: failed to list reward rules: %w", err)
+	}
+	return set, nil
+}
+
+// normalizeRewardRules упорядочивает правила по Match и убирает повторы:
+// из правил с одинаковым Match остается последнее
+func normalizeRewardRules(rules []domain.RewardRule) []domain.RewardRule {
+	normalized := slices.Clone(rules)
+	slices.Reverse(normalized)
+	slices.SortStableFunc(normalized, func(a, b domain.RewardRule) int {
+		return cmp.Compare(a.Match, b.Match)
+	})
+	return slices.CompactFunc(normalized, func(a, b domain.RewardRule) bool {
+		return a.Match == b.Match
+	})
+}
+
+// rewardRulesFingerprint вычисляет отпечаток упорядоченного набора правил.
+// Вознаграждение учитывается с точностью колонки reward
+func rewardRulesFingerprint(rules []domain.RewardRule) string {
+	h := sha256.New()
+	for _, rule := range rules {
+		h.Write([]byte(rule.Match))
+		h.Write([]byte{0})
+		h.Write(strconv.AppendFloat(nil, rule.Reward, 'f', 2, 64))
+		h.Write([]byte{0})
+		h.Write([]byte(rule.RewardType))
+		h.Write([]byte{'\n'})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	domainmocks "github.com/avc/loyalty-system-diploma/internal/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRewardRuleService_SyncRewardRules(t *testing.T) {
+	ctx := context.Background()
+	bork := domain.RewardRule{Match: "Bork", Reward: 10, RewardType: domain.RewardTypePercent}
+	acer := domain.RewardRule{Match: "Acer", Reward: 50, RewardType: domain.RewardTypePoints}
+
+	t.Run("Synced", func(t *testing.T) {
+		source := domainmocks.NewRewardRuleSourceMock(t)
+		repo := domainmocks.NewRewardRuleRepositoryMock(t)
+		updated := bork
+		updated.Reward = 15
+
+		// Правила упорядочиваются, из повторов остается последнее
+		source.EXPECT().GetRewardRules(mock.Anything).Return([]domain.RewardRule{bork, acer, updated}, nil).Once()
+		sync := &domain.RewardRuleSync{Changed: true, Added: 1}
+		repo.EXPECT().SyncRewardRules(mock.Anything, []domain.RewardRule{acer, updated}, mock.Anything).Return(sync, nil).Once()
+
+		result, err := NewRewardRuleService(source, repo).SyncRewardRules(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, sync, result)
+	})
+
+	t.Run("Fingerprint does not depend on order", func(t *testing.T) {
+		source := domainmocks.NewRewardRuleSourceMock(t)
+		repo := domainmocks.NewRewardRuleRepositoryMock(t)
+		source.EXPECT().GetRewardRules(mock.Anything).Return([]domain.RewardRule{bork, acer}, nil).Once()
+		source.EXPECT().GetRewardRules(mock.Anything).Return([]domain.RewardRule{acer, bork}, nil).Once()
+
+		var fingerprints []string
+		repo.EXPECT().SyncRewardRules(mock.Anything, mock.Anything, mock.Anything).
+			RunAndReturn(func(_ context.Context, _ []domain.RewardRule, fingerprint string) (*domain.RewardRuleSync, error) {
+				fingerprints = append(fingerprints, fingerprint)
+				return &domain.RewardRuleSync{}, nil
+			}).Twice()
+
+		svc := NewRewardRuleService(source, repo)
+		_, err := svc.SyncRewardRules(ctx)
+		require.NoError(t, err)
+		_, err = svc.SyncRewardRules(ctx)
+		require.NoError(t, err)
+		require.Len(t, fingerprints, 2)
+		assert.Equal(t, fingerprints[0], fingerprints[1])
+		assert.NotEqual(t, rewardRulesFingerprint(nil), fingerprints[0])
+	})
+
+	t.Run("Source error", func(t *testing.T) {
+		source := domainmocks.NewRewardRuleSourceMock(t)
+		repo := domainmocks.NewRewardRuleRepositoryMock(t)
+		source.EXPECT().GetRewardRules(mock.Anything).Return(nil, domain.ErrInvalidAccrualResponse).Once()
+
+		_, err := NewRewardRuleService(source, repo).SyncRewardRules(ctx)
+		assert.ErrorIs(t, err, domain.ErrInvalidAccrualResponse)
+	})
+
+	t.Run("No source", func(t *testing.T) {
+		repo := domainmocks.NewRewardRuleRepositoryMock(t)
+
+		_, err := NewRewardRuleService(nil, repo).SyncRewardRules(ctx)
+		assert.ErrorIs(t, err, domain.ErrRewardRulesUnavailable)
+	})
+}
+
+func TestRewardRuleService_RunScheduled(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Rules not exposed", func(t *testing.T) {
+		source := domainmocks.NewRewardRuleSourceMock(t)
+		repo := domainmocks.NewRewardRuleRepositoryMock(t)
+		source.EXPECT().GetRewardRules(mock.Anything).Return(nil, domain.ErrRewardRulesUnavailable).Once()
+
+		assert.NoError(t, NewRewardRuleService(source, repo).RunScheduled(ctx))
+	})
+
+	t.Run("Repository error", func(t *testing.T) {
+		source := domainmocks.NewRewardRuleSourceMock(t)
+		repo := domainmocks.NewRewardRuleRepositoryMock(t)
+		source.EXPECT().GetRewardRules(mock.Anything).Return(nil, nil).Once()
+		repo.EXPECT().SyncRewardRules(mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("db error")).Once()
+
+		assert.Error(t, NewRewardRuleService(source, repo).RunScheduled(ctx))
+	})
+}
+
+func TestRewardRuleService_ListRewardRules(t *testing.T) {
+	repo := domainmocks.NewRewardRuleRepositoryMock(t)
+	set := &domain.RewardRuleSet{Rules: []*domain.RewardRule{{Match: "Bork", Reward: 10, RewardType: domain.RewardTypePercent}}}
+	repo.EXPECT().ListRewardRules(mock.Anything).Return(set, nil).Once()
+
+	result, err := NewRewardRuleService(nil, repo).ListRewardRules(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, set, result)
+}
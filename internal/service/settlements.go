@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/avc/loyalty-system-diploma/internal/lock"
+	"github.com/avc/loyalty-system-diploma/internal/logctx"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// SettlementRepository определяет методы хранения выгрузок для взаиморасчетов.
+type SettlementRepository interface {
+	CreateSettlement(ctx context.Context, createdBy *int64) (*domain.Settlement, error)
+	GetSettlement(ctx context.Context, id uuid.UUID) (*domain.Settlement, error)
+	ListSettlements(ctx context.Context, limit, offset int) ([]*domain.Settlement, error)
+	GetSettlementLines(ctx context.Context, id uuid.UUID) ([]*domain.SettlementLine, error)
+}
+
+// Размер страницы списка выгрузок
+const (
+	defaultSettlementPageLimit = 50
+	maxSettlementPageLimit     = 100
+)
+
+// SettlementService формирует выгрузки завершенных списаний для финансового отдела.
+type SettlementService struct {
+	repo   SettlementRepository
+	locker Locker
+}
+
+// NewSettlementService создает новый SettlementService.
+// locker не дает репликам формировать выгрузки одновременно, может быть nil.
+func NewSettlementService(repo SettlementRepository, locker Locker) *SettlementService {
+	return &SettlementService{repo: repo, locker: locker}
+}
+
+// CreateSettlement выгружает списания, еще не вошедшие ни в одну выгрузку, до начала текущих суток.
+// createdBy - администратор, nil - выгрузка по расписанию. Если выгружать нечего - ErrNothingToSettle.
+func (s *SettlementService) CreateSettlement(ctx context.Context, createdBy *int64) (*domain.Settlement, error) {
+	unlock, err := lockKeys(ctx, s.locker, lock.SettlementKey)
+	if err != nil {
+		return nil, fmt.Errorf("settlement service: failed to lock settlements: %w", err)
+	}
+	defer unlock()
+
+	settlement, err := s.repo.CreateSettlement(ctx, createdBy)
+	if err != nil {
+		if errors.Is(err, domain.ErrNothingToSettle) {
+			return nil, fmt.Errorf("settlement service: %w", err)
+		}
+		logctx.From(ctx).Error("settlement service: failed to create settlement", zap.Error(err))
+		return nil, fmt.Errorf("settlement service: failed to create settlement: %w", err)
+	}
+
+	logctx.From(ctx).Info("withdrawals settled",
+		zap.String("settlement_id", settlement.ID.String()),
+		zap.Int("withdrawals", settlement.Withdrawals),
+		zap.Float64("total", settlement.Total),
+	)
+	return settlement, nil
+}
+
+// RunScheduled - задача расписания: создает выгрузку, отсутствие новых списаний не считается ошибкой
+func (s *SettlementService) RunScheduled(ctx context.Context) error {
+	_, err := s.CreateSettlement(ctx, nil)
+	if errors.Is(err, domain.ErrNothingToSettle) {
+		return nil
+	}
+	return err
+}
+
+// ListSettlements возвращает страницу выгрузок, новые первыми.
+// Нулевой limit заменяется значением по умолчанию, слишком большой - ограничивается.
+func (s *SettlementService) ListSettlements(ctx context.Context, limit, offset int) (*domain.SettlementPage, error) {
+	if limit < 0 || offset < 0 {
+		return nil, fmt.Errorf("settlement service: negative limit or offset: %w", domain.ErrInvalidInput)
+	}
+	if limit == 0 {
+		limit = defaultSettlementPageLimit
+	}
+	limit = min(limit, maxSettlementPageLimit)
+
+	// Лишняя выгрузка показывает, что за страницей есть продолжение
+	settlements, err := s.repo.ListSettlements(ctx, limit+1, offset)
+	if err != nil {
+		logctx.From(ctx).Error("settlement service: failed to list settlements", zap.Error(err))
+		return nil, fmt.Errorf("settlement service: failed to list settlements: %w", err)
+	}
+
+	page := &domain.SettlementPage{Settlements: settlements}
+	if len(settlements) > limit {
+		page.Settlements = settlements[:limit]
+		page.HasMore = true
+	}
+	return page, nil
+}
+
+// GetSettlementFile возвращает выгрузку и ее итоги по партнерам и суткам
+func (s *SettlementService) GetSettlementFile(ctx context.Context, id uuid.UUID) (*domain.Settlement, []*domain.SettlementLine, error) {
+	settlement, err := s.repo.GetSettlement(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrSettlementNotFound) {
+			return nil, nil, fmt.Errorf("settlement service: settlement %s not found: %w", id, err)
+		}
+		logctx.From(ctx).Error("settlement service: failed to get settlement", zap.Error(err))
+		return nil, nil, fmt.Errorf("settlement service: failed to get settlement %s: %w", id, err)
+	}
+
+	lines, err := s.repo.GetSettlementLines(ctx, id)
+	if err != nil {
+		logctx.From(ctx).Error("settlement service: failed to get settlement lines", zap.Error(err))
+		return nil, nil, fmt.Errorf("settlement service: failed to get settlement %s lines: %w", id, err)
+	}
+
+	return settlement, lines, nil
+}
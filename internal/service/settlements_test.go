@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	domainmocks "github.com/avc/loyalty-system-diploma/internal/domain/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSettlementService_CreateSettlement(t *testing.T) {
+	ctx := context.Background()
+	adminID := int64(1)
+
+	t.Run("Created", func(t *testing.T) {
+		repo := domainmocks.NewSettlementRepositoryMock(t)
+		settlement := &domain.Settlement{ID: uuid.New(), Withdrawals: 2, Total: 300, CreatedBy: &adminID}
+		repo.EXPECT().CreateSettlement(mock.Anything, &adminID).Return(settlement, nil).Once()
+
+		result, err := NewSettlementService(repo, nil).CreateSettlement(ctx, &adminID)
+		require.NoError(t, err)
+		assert.Equal(t, settlement, result)
+	})
+
+	t.Run("Nothing to settle", func(t *testing.T) {
+		repo := domainmocks.NewSettlementRepositoryMock(t)
+		repo.EXPECT().CreateSettlement(mock.Anything, &adminID).Return(nil, domain.ErrNothingToSettle).Once()
+
+		_, err := NewSettlementService(repo, nil).CreateSettlement(ctx, &adminID)
+		assert.ErrorIs(t, err, domain.ErrNothingToSettle)
+	})
+
+	t.Run("Under settlement lock", func(t *testing.T) {
+		repo := domainmocks.NewSettlementRepositoryMock(t)
+		locker := domainmocks.NewLockerMock(t)
+		unlocked := false
+		locker.EXPECT().Lock(mock.Anything, "settlement").Return(func() { unlocked = true }, nil).Once()
+		repo.EXPECT().CreateSettlement(mock.Anything, &adminID).Return(nil, domain.ErrNothingToSettle).Once()
+
+		_, err := NewSettlementService(repo, locker).CreateSettlement(ctx, &adminID)
+		assert.ErrorIs(t, err, domain.ErrNothingToSettle)
+		assert.True(t, unlocked)
+	})
+
+	t.Run("Lock error", func(t *testing.T) {
+		locker := domainmocks.NewLockerMock(t)
+		locker.EXPECT().Lock(mock.Anything, "settlement").Return(nil, context.Canceled).Once()
+
+		_, err := NewSettlementService(domainmocks.NewSettlementRepositoryMock(t), locker).CreateSettlement(ctx, &adminID)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestSettlementService_RunScheduled(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Nothing to settle is not an error", func(t *testing.T) {
+		repo := domainmocks.NewSettlementRepositoryMock(t)
+		repo.EXPECT().CreateSettlement(mock.Anything, (*int64)(nil)).Return(nil, domain.ErrNothingToSettle).Once()
+
+		assert.NoError(t, NewSettlementService(repo, nil).RunScheduled(ctx))
+	})
+
+	t.Run("Database error", func(t *testing.T) {
+		repo := domainmocks.NewSettlementRepositoryMock(t)
+		repo.EXPECT().CreateSettlement(mock.Anything, (*int64)(nil)).Return(nil, errors.New("db error")).Once()
+
+		assert.Error(t, NewSettlementService(repo, nil).RunScheduled(ctx))
+	})
+}
+
+func TestSettlementService_ListSettlements(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Default limit and has more", func(t *testing.T) {
+		repo := domainmocks.NewSettlementRepositoryMock(t)
+		settlements := make([]*domain.Settlement, defaultSettlementPageLimit+1)
+		repo.EXPECT().ListSettlements(mock.Anything, defaultSettlementPageLimit+1, 0).Return(settlements, nil).Once()
+
+		page, err := NewSettlementService(repo, nil).ListSettlements(ctx, 0, 0)
+		require.NoError(t, err)
+		assert.Len(t, page.Settlements, defaultSettlementPageLimit)
+		assert.True(t, page.HasMore)
+	})
+
+	t.Run("Limit capped", func(t *testing.T) {
+		repo := domainmocks.NewSettlementRepositoryMock(t)
+		repo.EXPECT().ListSettlements(mock.Anything, maxSettlementPageLimit+1, 10).Return(nil, nil).Once()
+
+		page, err := NewSettlementService(repo, nil).ListSettlements(ctx, 1000, 10)
+		require.NoError(t, err)
+		assert.False(t, page.HasMore)
+	})
+
+	t.Run("Negative offset", func(t *testing.T) {
+		_, err := NewSettlementService(domainmocks.NewSettlementRepositoryMock(t), nil).ListSettlements(ctx, 10, -1)
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	})
+}
+
+func TestSettlementService_GetSettlementFile(t *testing.T) {
+	ctx := context.Background()
+	id := uuid.New()
+
+	t.Run("Found", func(t *testing.T) {
+		repo := domainmocks.NewSettlementRepositoryMock(t)
+		settlement := &domain.Settlement{ID: id, Withdrawals: 1, Total: 100}
+		lines := []*domain.SettlementLine{{Day: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), Partner: "store-42", Withdrawals: 1, Total: 100}}
+		repo.EXPECT().GetSettlement(mock.Anything, id).Return(settlement, nil).Once()
+		repo.EXPECT().GetSettlementLines(mock.Anything, id).Return(lines, nil).Once()
+
+		gotSettlement, gotLines, err := NewSettlementService(repo, nil).GetSettlementFile(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, settlement, gotSettlement)
+		assert.Equal(t, lines, gotLines)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		repo := domainmocks.NewSettlementRepositoryMock(t)
+		repo.EXPECT().GetSettlement(mock.Anything, id).Return(nil, domain.ErrSettlementNotFound).Once()
+
+		_, _, err := NewSettlementService(repo, nil).GetSettlementFile(ctx, id)
+		assert.ErrorIs(t, err, domain.ErrSettlementNotFound)
+	})
+}
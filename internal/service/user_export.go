@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/avc/loyalty-system-diploma/internal/jobs"
+)
+
+// UserDataRepository определяет чтение профиля пользователя для выгрузки его данных.
+type UserDataRepository interface {
+	GetUserByID(ctx context.Context, id int64) (*domain.User, error)
+	GetUserContacts(ctx context.Context, userID int64) (*domain.UserContacts, error)
+}
+
+// userExportSteps - число этапов выгрузки для прогресса задачи: профиль, заказы, транзакции
+const userExportSteps = 3
+
+// UserExportService собирает все данные пользователя для выгрузки по его запросу.
+type UserExportService struct {
+	userRepo        UserDataRepository
+	orderRepo       OrderRepository
+	transactionRepo TransactionRepository
+}
+
+// NewUserExportService создает новый UserExportService
+func NewUserExportService(userRepo UserDataRepository, orderRepo OrderRepository, transactionRepo TransactionRepository) *UserExportService {
+	return &UserExportService{
+		userRepo:        userRepo,
+		orderRepo:       orderRepo,
+		transactionRepo: transactionRepo,
+	}
+}
+
+// ExportUserData возвращает профиль, контакты, заказы и транзакции пользователя.
+// Если шифрование контактов не настроено, контакты в выгрузку не попадают.
+func (s *UserExportService) ExportUserData(ctx context.Context, userID int64, progress jobs.Progress) (*domain.UserDataExport, error) {
+	progress.SetTotal(userExportSteps)
+	export := &domain.UserDataExport{GeneratedAt: time.Now().UTC()}
+
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("user export service: failed to get user %d: %w", userID, err)
+	}
+	export.User = user
+
+	contacts, err := s.userRepo.GetUserContacts(ctx, userID)
+	if err != nil && !errors.Is(err, domain.ErrPIIEncryptionDisabled) {
+		return nil, fmt.Errorf("user export service: failed to get contacts of user %d: %w", userID, err)
+	}
+	export.Contacts = contacts
+	progress.Advance(true)
+
+	orders, err := s.orderRepo.GetOrdersByUserID(ctx, userID, domain.OrderFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("user export service: failed to get orders of user %d: %w", userID, err)
+	}
+	export.Orders = orders
+	progress.Advance(true)
+
+	transactions, err := s.transactionRepo.GetTransactions(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("user export service: failed to get transactions of user %d: %w", userID, err)
+	}
+	export.Transactions = transactions
+	progress.Advance(true)
+
+	return export, nil
+}
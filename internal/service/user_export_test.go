@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	domainmocks "github.com/avc/loyalty-system-diploma/internal/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserExportService_ExportUserData(t *testing.T) {
+	ctx := context.Background()
+	user := &domain.User{ID: 7, Login: "alice"}
+	orders := []*domain.Order{{Number: "12345678903", Status: domain.OrderStatusProcessed}}
+	transactions := []*domain.Transaction{{OrderNumber: "12345678903", Amount: 500, Type: domain.TransactionTypeAccrual}}
+
+	t.Run("Success", func(t *testing.T) {
+		userRepo := domainmocks.NewUserDataRepositoryMock(t)
+		orderRepo := domainmocks.NewOrderRepositoryMock(t)
+		txRepo := domainmocks.NewTransactionRepositoryMock(t)
+		contacts := &domain.UserContacts{Email: "alice@example.com"}
+
+		userRepo.EXPECT().GetUserByID(mock.Anything, int64(7)).Return(user, nil).Once()
+		userRepo.EXPECT().GetUserContacts(mock.Anything, int64(7)).Return(contacts, nil).Once()
+		orderRepo.EXPECT().GetOrdersByUserID(mock.Anything, int64(7), domain.OrderFilter{}).Return(orders, nil).Once()
+		txRepo.EXPECT().GetTransactions(mock.Anything, int64(7)).Return(transactions, nil).Once()
+
+		progress := &recordingProgress{}
+		export, err := NewUserExportService(userRepo, orderRepo, txRepo).ExportUserData(ctx, 7, progress)
+		require.NoError(t, err)
+		assert.Equal(t, user, export.User)
+		assert.Equal(t, contacts, export.Contacts)
+		assert.Equal(t, orders, export.Orders)
+		assert.Equal(t, transactions, export.Transactions)
+		assert.False(t, export.GeneratedAt.IsZero())
+		assert.Equal(t, 3, progress.total)
+		assert.Equal(t, 3, progress.processed)
+	})
+
+	t.Run("Contacts encryption disabled", func(t *testing.T) {
+		userRepo := domainmocks.NewUserDataRepositoryMock(t)
+		orderRepo := domainmocks.NewOrderRepositoryMock(t)
+		txRepo := domainmocks.NewTransactionRepositoryMock(t)
+
+		userRepo.EXPECT().GetUserByID(mock.Anything, int64(7)).Return(user, nil).Once()
+		userRepo.EXPECT().GetUserContacts(mock.Anything, int64(7)).Return(nil, domain.ErrPIIEncryptionDisabled).Once()
+		orderRepo.EXPECT().GetOrdersByUserID(mock.Anything, int64(7), domain.OrderFilter{}).Return(nil, nil).Once()
+		txRepo.EXPECT().GetTransactions(mock.Anything, int64(7)).Return(nil, nil).Once()
+
+		export, err := NewUserExportService(userRepo, orderRepo, txRepo).ExportUserData(ctx, 7, &recordingProgress{})
+		require.NoError(t, err)
+		assert.Nil(t, export.Contacts)
+	})
+
+	t.Run("Repository error", func(t *testing.T) {
+		userRepo := domainmocks.NewUserDataRepositoryMock(t)
+		orderRepo := domainmocks.NewOrderRepositoryMock(t)
+
+		userRepo.EXPECT().GetUserByID(mock.Anything, int64(7)).Return(user, nil).Once()
+		userRepo.EXPECT().GetUserContacts(mock.Anything, int64(7)).Return(nil, nil).Once()
+		orderRepo.EXPECT().GetOrdersByUserID(mock.Anything, int64(7), domain.OrderFilter{}).Return(nil, errors.New("db error")).Once()
+
+		_, err := NewUserExportService(userRepo, orderRepo, domainmocks.NewTransactionRepositoryMock(t)).
+			ExportUserData(ctx, 7, &recordingProgress{})
+		assert.Error(t, err)
+	})
+}
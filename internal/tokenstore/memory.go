@@ -0,0 +1,105 @@
+// Package tokenstore хранит refresh-токены вне PostgreSQL: в памяти процесса
+// для разработки на одном экземпляре и в Redis.
+package tokenstore
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+)
+
+type memoryToken struct {
+	userID    int64
+	device    string
+	expiresAt time.Time
+	revoked   bool
+}
+
+// MemoryStore хранит refresh-токены в памяти процесса. Токены теряются при перезапуске
+// и не видны другим экземплярам, поэтому хранилище подходит только для разработки
+// на одном экземпляре
+type MemoryStore struct {
+	mu     sync.Mutex
+	tokens map[string]*memoryToken
+	now    func() time.Time
+}
+
+// NewMemoryStore создает пустое хранилище в памяти
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		tokens: make(map[string]*memoryToken),
+		now:    time.Now,
+	}
+}
+
+// CreateRefreshToken сохраняет refresh-токен id пользователя userID, выданный устройству
+// device, со временем жизни ttl
+func (s *MemoryStore) CreateRefreshToken(_ context.Context, userID int64, id, device string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens[id] = &memoryToken{userID: userID, device: device, expiresAt: s.now().Add(ttl)}
+	return nil
+}
+
+// RotateRefreshToken отзывает refresh-токен id, предъявленный устройством device,
+// и сохраняет выданный взамен токен nextID. Ошибки - как у хранилища в PostgreSQL
+func (s *MemoryStore) RotateRefreshToken(_ context.Context, userID int64, id, nextID, device string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	token, ok := s.tokens[id]
+	if !ok || !token.expiresAt.After(now) || token.userID != userID {
+		return domain.ErrInvalidToken
+	}
+	if token.revoked || token.device != device {
+		reused := token.revoked
+		for _, t := range s.tokens {
+			if t.userID == userID {
+				t.revoked = true
+			}
+		}
+		if reused {
+			return domain.ErrRefreshTokenReused
+		}
+		return domain.ErrRefreshTokenDevice
+	}
+
+	token.revoked = true
+	s.tokens[nextID] = &memoryToken{userID: userID, device: device, expiresAt: now.Add(ttl)}
+	return nil
+}
+
+// RevokeRefreshTokens удаляет все refresh-токены пользователя userID
+func (s *MemoryStore) RevokeRefreshTokens(_ context.Context, userID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, token := range s.tokens {
+		if token.userID == userID {
+			delete(s.tokens, id)
+		}
+	}
+	return nil
+}
+
+// DeleteExpiredRefreshTokens удаляет до limit истекших refresh-токенов и возвращает их число
+func (s *MemoryStore) DeleteExpiredRefreshTokens(_ context.Context, limit int) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	deleted := 0
+	for id, token := range s.tokens {
+		if deleted == limit {
+			break
+		}
+		if !token.expiresAt.After(now) {
+			delete(s.tokens, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,206 @@
+package tokenstore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// refreshTokenStore - общие методы хранилищ, проверяемые одними тестами
+type refreshTokenStore interface {
+	CreateRefreshToken(ctx context.Context, userID int64, id, device string, ttl time.Duration) error
+	RotateRefreshToken(ctx context.Context, userID int64, id, nextID, device string, ttl time.Duration) error
+	RevokeRefreshTokens(ctx context.Context, userID int64) error
+}
+
+// testStore - хранилище и сдвиг его часов вперед
+type testStore struct {
+	store   refreshTokenStore
+	advance func(d time.Duration)
+}
+
+func newTestStores(t *testing.T) map[string]func() testStore {
+	return map[string]func() testStore{
+		"Memory": func() testStore {
+			store := NewMemoryStore()
+			now := time.Now()
+			store.now = func() time.Time { return now }
+			return testStore{store: store, advance: func(d time.Duration) { now = now.Add(d) }}
+		},
+		"Redis": func() testStore {
+			server := miniredis.RunT(t)
+			store, err := NewRedisStore("redis://" + server.Addr())
+			require.NoError(t, err)
+			t.Cleanup(func() { store.Close() })
+			return testStore{store: store, advance: server.FastForward}
+		},
+	}
+}
+
+func TestRefreshTokenStores(t *testing.T) {
+	ctx := context.Background()
+
+	for name, newStore := range newTestStores(t) {
+		t.Run(name, func(t *testing.T) {
+			t.Run("Rotated", func(t *testing.T) {
+				s := newStore()
+				require.NoError(t, s.store.CreateRefreshToken(ctx, 7, "a", "phone", time.Hour))
+
+				require.NoError(t, s.store.RotateRefreshToken(ctx, 7, "a", "b", "phone", time.Hour))
+				require.NoError(t, s.store.RotateRefreshToken(ctx, 7, "b", "c", "phone", time.Hour))
+			})
+
+			t.Run("Reused token revokes all tokens of the user", func(t *testing.T) {
+				s := newStore()
+				require.NoError(t, s.store.CreateRefreshToken(ctx, 7, "a", "phone", time.Hour))
+				require.NoError(t, s.store.CreateRefreshToken(ctx, 8, "other", "phone", time.Hour))
+				require.NoError(t, s.store.RotateRefreshToken(ctx, 7, "a", "b", "phone", time.Hour))
+
+				assert.ErrorIs(t, s.store.RotateRefreshToken(ctx, 7, "a", "x", "phone", time.Hour), domain.ErrRefreshTokenReused)
+				assert.ErrorIs(t, s.store.RotateRefreshToken(ctx, 7, "b", "y", "phone", time.Hour), domain.ErrRefreshTokenReused)
+				// Токены других пользователей не затрагиваются
+				assert.NoError(t, s.store.RotateRefreshToken(ctx, 8, "other", "z", "phone", time.Hour))
+			})
+
+			t.Run("Token from another device revokes all tokens of the user", func(t *testing.T) {
+				s := newStore()
+				require.NoError(t, s.store.CreateRefreshToken(ctx, 7, "a", "phone", time.Hour))
+				require.NoError(t, s.store.CreateRefreshToken(ctx, 7, "b", "laptop", time.Hour))
+
+				assert.ErrorIs(t, s.store.RotateRefreshToken(ctx, 7, "a", "x", "laptop", time.Hour), domain.ErrRefreshTokenDevice)
+				assert.ErrorIs(t, s.store.RotateRefreshToken(ctx, 7, "b", "y", "laptop", time.Hour), domain.ErrRefreshTokenReused)
+			})
+
+			t.Run("Token bound to device without device ID revokes all tokens of the user", func(t *testing.T) {
+				s := newStore()
+				require.NoError(t, s.store.CreateRefreshToken(ctx, 7, "a", "phone", time.Hour))
+				require.NoError(t, s.store.CreateRefreshToken(ctx, 7, "b", "", time.Hour))
+
+				assert.ErrorIs(t, s.store.RotateRefreshToken(ctx, 7, "a", "x", "", time.Hour), domain.ErrRefreshTokenDevice)
+				assert.ErrorIs(t, s.store.RotateRefreshToken(ctx, 7, "b", "y", "", time.Hour), domain.ErrRefreshTokenReused)
+			})
+
+			t.Run("Revoked tokens of the user", func(t *testing.T) {
+				s := newStore()
+				require.NoError(t, s.store.CreateRefreshToken(ctx, 7, "a", "phone", time.Hour))
+				require.NoError(t, s.store.CreateRefreshToken(ctx, 7, "b", "laptop", time.Hour))
+				require.NoError(t, s.store.CreateRefreshToken(ctx, 8, "other", "phone", time.Hour))
+
+				require.NoError(t, s.store.RevokeRefreshTokens(ctx, 7))
+				assert.ErrorIs(t, s.store.RotateRefreshToken(ctx, 7, "a", "x", "phone", time.Hour), domain.ErrInvalidToken)
+				assert.ErrorIs(t, s.store.RotateRefreshToken(ctx, 7, "b", "y", "laptop", time.Hour), domain.ErrInvalidToken)
+				assert.NoError(t, s.store.RotateRefreshToken(ctx, 8, "other", "z", "phone", time.Hour))
+			})
+
+			t.Run("Token of another user", func(t *testing.T) {
+				s := newStore()
+				require.NoError(t, s.store.CreateRefreshToken(ctx, 7, "a", "phone", time.Hour))
+
+				assert.ErrorIs(t, s.store.RotateRefreshToken(ctx, 8, "a", "b", "phone", time.Hour), domain.ErrInvalidToken)
+			})
+
+			t.Run("Unknown or expired token", func(t *testing.T) {
+				s := newStore()
+				require.NoError(t, s.store.CreateRefreshToken(ctx, 7, "a", "phone", time.Hour))
+
+				assert.ErrorIs(t, s.store.RotateRefreshToken(ctx, 7, "unknown", "b", "phone", time.Hour), domain.ErrInvalidToken)
+				s.advance(2 * time.Hour)
+				assert.ErrorIs(t, s.store.RotateRefreshToken(ctx, 7, "a", "b", "phone", time.Hour), domain.ErrInvalidToken)
+			})
+		})
+	}
+}
+
+func TestMemoryStore_DeleteExpiredRefreshTokens(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	require.NoError(t, store.CreateRefreshToken(ctx, 7, "a", "phone", time.Minute))
+	require.NoError(t, store.CreateRefreshToken(ctx, 7, "b", "phone", time.Minute))
+	require.NoError(t, store.CreateRefreshToken(ctx, 7, "c", "phone", time.Hour))
+	now = now.Add(2 * time.Minute)
+
+	deleted, err := store.DeleteExpiredRefreshTokens(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+	deleted, err = store.DeleteExpiredRefreshTokens(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+	assert.Len(t, store.tokens, 1)
+}
+
+func TestRedisStore(t *testing.T) {
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+	store, err := NewRedisStore("redis://" + server.Addr())
+	require.NoError(t, err)
+	defer store.Close()
+
+	t.Run("Keys expire with tokens", func(t *testing.T) {
+		require.NoError(t, store.CreateRefreshToken(ctx, 7, "a", "phone", time.Minute))
+		assert.Equal(t, time.Minute, server.TTL(tokenKey(7, "a")))
+		assert.Equal(t, time.Minute, server.TTL(userTokensKey(7)))
+
+		// Множество токенов пользователя живет не меньше самого долгого токена
+		require.NoError(t, store.CreateRefreshToken(ctx, 7, "b", "phone", time.Hour))
+		require.NoError(t, store.CreateRefreshToken(ctx, 7, "c", "phone", time.Minute))
+		assert.Equal(t, time.Hour, server.TTL(userTokensKey(7)))
+
+		deleted, err := store.DeleteExpiredRefreshTokens(ctx, 10)
+		require.NoError(t, err)
+		assert.Zero(t, deleted)
+	})
+
+	t.Run("Revocation does not recreate expired tokens", func(t *testing.T) {
+		require.NoError(t, store.CreateRefreshToken(ctx, 9, "short", "phone", time.Minute))
+		require.NoError(t, store.CreateRefreshToken(ctx, 9, "long", "phone", time.Hour))
+		require.NoError(t, store.RotateRefreshToken(ctx, 9, "long", "next", "phone", time.Hour))
+		server.FastForward(2 * time.Minute)
+
+		assert.ErrorIs(t, store.RotateRefreshToken(ctx, 9, "long", "x", "phone", time.Hour), domain.ErrRefreshTokenReused)
+		assert.False(t, server.Exists(tokenKey(9, "short")))
+		members, err := server.Members(userTokensKey(9))
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"long", "next"}, members)
+	})
+
+	t.Run("Keys of a user share a cluster slot", func(t *testing.T) {
+		require.NoError(t, store.CreateRefreshToken(ctx, 11, "a", "phone", time.Minute))
+		require.NoError(t, store.RotateRefreshToken(ctx, 11, "a", "b", "phone", time.Minute))
+
+		keys := server.Keys()
+		assert.Contains(t, keys, "gophermart:refresh:{11}:a")
+		assert.Contains(t, keys, "gophermart:refresh:{11}:b")
+		assert.Contains(t, keys, "gophermart:refresh:{11}:tokens")
+	})
+
+	t.Run("Script sees tokens created after reading the user tokens", func(t *testing.T) {
+		require.NoError(t, store.CreateRefreshToken(ctx, 12, "a", "phone", time.Minute))
+		require.NoError(t, store.CreateRefreshToken(ctx, 12, "b", "laptop", time.Minute))
+
+		// Множество прочитано без токена "b": скрипт должен попросить повтор
+		result, err := rotateScript.Run(ctx, store.client,
+			[]string{tokenKey(12, "a"), tokenKey(12, "x"), userTokensKey(12), tokenKey(12, "a")},
+			int64(12), time.Minute.Milliseconds(), "x", "laptop", "a",
+		).Int()
+		require.NoError(t, err)
+		assert.Equal(t, membersChangedResult, result)
+
+		// С повтором токен "b" тоже отзывается
+		assert.ErrorIs(t, store.RotateRefreshToken(ctx, 12, "a", "x", "laptop", time.Minute), domain.ErrRefreshTokenDevice)
+		assert.ErrorIs(t, store.RotateRefreshToken(ctx, 12, "b", "y", "laptop", time.Minute), domain.ErrRefreshTokenReused)
+	})
+
+	t.Run("Unavailable", func(t *testing.T) {
+		server.Close()
+		assert.Error(t, store.Ping(ctx))
+		assert.Error(t, store.CreateRefreshToken(ctx, 7, "d", "phone", time.Minute))
+	})
+}
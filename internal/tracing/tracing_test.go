@@ -0,0 +1,69 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestSetup_WithoutEndpoint(t *testing.T) {
+	flush, err := Setup(context.Background(), Config{ServiceName: "gophermart", SampleRatio: 1})
+	require.NoError(t, err)
+	assert.NoError(t, flush(context.Background()))
+
+	// Контекст входящего запроса передается дальше и без экспорта спанов
+	header := http.Header{}
+	header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx := otel.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier(header))
+
+	out := http.Header{}
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(out))
+	assert.Equal(t, header.Get("traceparent"), out.Get("traceparent"))
+}
+
+func TestSetup_WithEndpoint(t *testing.T) {
+	prev := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	flush, err := Setup(context.Background(), Config{Endpoint: "http://127.0.0.1:4318", ServiceName: "gophermart", SampleRatio: 1})
+	require.NoError(t, err)
+	assert.IsType(t, &sdktrace.TracerProvider{}, otel.GetTracerProvider())
+
+	_, span := Start(context.Background(), "test")
+	assert.True(t, span.SpanContext().IsSampled())
+	// Спан не завершен, поэтому остановка не обращается к коллектору
+	assert.NoError(t, flush(context.Background()))
+}
+
+func TestFail(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+
+	for _, tt := range []struct {
+		name   string
+		err    error
+		status codes.Code
+	}{
+		{name: "error", err: errors.New("boom"), status: codes.Error},
+		{name: "nil", err: nil, status: codes.Unset},
+		{name: "canceled", err: context.Canceled, status: codes.Unset},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, span := tracer.Start(context.Background(), tt.name)
+			Fail(span, tt.err)
+			span.End()
+
+			spans := recorder.Ended()
+			assert.Equal(t, tt.status, spans[len(spans)-1].Status().Code)
+		})
+	}
+}
@@ -0,0 +1,223 @@
+// Package envelope шифрует значения полей конвертным шифрованием: каждое значение
+// шифруется собственным ключом данных (AES-256-GCM), а ключ данных - мастер-ключом из конфигурации.
+// Для смены мастер-ключа достаточно перешифровать ключи данных, сами значения не меняются.
+package envelope
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// KeySize - размер мастер-ключа и ключа данных в байтах (AES-256)
+const KeySize = 32
+
+// version - префикс формата шифротекста
+const version = "v1"
+
+var (
+	// ErrMalformed - значение не является шифротекстом этого формата
+	ErrMalformed = errors.New("envelope: malformed ciphertext")
+	// ErrUnknownKey - значение зашифровано мастер-ключом, которого нет в конфигурации
+	ErrUnknownKey = errors.New("envelope: unknown master key")
+	// ErrDecrypt - значение повреждено, подменено или относится к другой записи
+	ErrDecrypt = errors.New("envelope: decryption failed")
+)
+
+// keyIDPattern ограничивает идентификатор ключа символами, не встречающимися в разделителях формата
+var keyIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,32}$`)
+
+// Key - мастер-ключ с идентификатором, который записывается в шифротекст
+type Key struct {
+	ID     string
+	Secret []byte
+}
+
+// ParseKeys разбирает список мастер-ключей вида "id:base64,id:base64".
+// Первый ключ - текущий, им шифруются новые значения; остальные нужны для чтения старых.
+func ParseKeys(spec string) ([]Key, error) {
+	var keys []Key
+	seen := make(map[string]bool)
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		id, encoded, ok := strings.Cut(item, ":")
+		if !ok || !keyIDPattern.MatchString(id) {
+			return nil, fmt.Errorf("envelope: invalid key %q: expected id:base64 with id of letters, digits, _ or -", id)
+		}
+		if seen[id] {
+			return nil, fmt.Errorf("envelope: duplicate key id %q", id)
+		}
+		secret, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("envelope: key %q is not valid base64: %w", id, err)
+		}
+		if len(secret) != KeySize {
+			return nil, fmt.Errorf("envelope: key %q must be %d bytes, got %d", id, KeySize, len(secret))
+		}
+		seen[id] = true
+		keys = append(keys, Key{ID: id, Secret: secret})
+	}
+	return keys, nil
+}
+
+// Keyring шифрует значения текущим мастер-ключом и расшифровывает любым из известных
+type Keyring struct {
+	current string
+	keys    map[string]cipher.AEAD
+}
+
+// NewKeyring создает Keyring; первый ключ в списке - текущий
+func NewKeyring(keys []Key) (*Keyring, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("envelope: at least one master key is required")
+	}
+	k := &Keyring{current: keys[0].ID, keys: make(map[string]cipher.AEAD, len(keys))}
+	for _, key := range keys {
+		aead, err := newAEAD(key.Secret)
+		if err != nil {
+			return nil, fmt.Errorf("envelope: key %q: %w", key.ID, err)
+		}
+		k.keys[key.ID] = aead
+	}
+	return k, nil
+}
+
+// CurrentKeyID возвращает идентификатор текущего мастер-ключа
+func (k *Keyring) CurrentKeyID() string {
+	return k.current
+}
+
+// Encrypt шифрует plaintext новым ключом данных. aad привязывает шифротекст к записи
+// (например, к колонке и пользователю): перенесенный в другую запись, он не расшифруется.
+func (k *Keyring) Encrypt(plaintext string, aad []byte) (string, error) {
+	dataKey := make([]byte, KeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", fmt.Errorf("envelope: failed to generate data key: %w", err)
+	}
+	data, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	wrapped, err := seal(k.keys[k.current], dataKey, []byte(k.current))
+	if err != nil {
+		return "", err
+	}
+	sealed, err := seal(data, []byte(plaintext), aad)
+	if err != nil {
+		return "", err
+	}
+	return format(k.current, wrapped, sealed), nil
+}
+
+// Decrypt расшифровывает значение, зашифрованное Encrypt с тем же aad
+func (k *Keyring) Decrypt(ciphertext string, aad []byte) (string, error) {
+	keyID, wrapped, sealed, err := parse(ciphertext)
+	if err != nil {
+		return "", err
+	}
+	dataKey, err := k.unwrap(keyID, wrapped)
+	if err != nil {
+		return "", err
+	}
+	data, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := open(data, sealed, aad)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// Rewrap перешифровывает ключ данных текущим мастер-ключом, не расшифровывая само значение.
+// Значение, уже зашифрованное текущим ключом, возвращается без изменений.
+func (k *Keyring) Rewrap(ciphertext string) (string, error) {
+	keyID, wrapped, sealed, err := parse(ciphertext)
+	if err != nil {
+		return "", err
+	}
+	if keyID == k.current {
+		return ciphertext, nil
+	}
+	dataKey, err := k.unwrap(keyID, wrapped)
+	if err != nil {
+		return "", err
+	}
+	rewrapped, err := seal(k.keys[k.current], dataKey, []byte(k.current))
+	if err != nil {
+		return "", err
+	}
+	return format(k.current, rewrapped, sealed), nil
+}
+
+// unwrap расшифровывает ключ данных мастер-ключом keyID
+func (k *Keyring) unwrap(keyID string, wrapped []byte) ([]byte, error) {
+	master, ok := k.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKey, keyID)
+	}
+	return open(master, wrapped, []byte(keyID))
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("envelope: invalid key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal шифрует plaintext и возвращает nonce вместе с шифротекстом
+func seal(aead cipher.AEAD, plaintext, aad []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("envelope: failed to generate nonce: %w", err)
+	}
+	return aead.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+func open(aead cipher.AEAD, sealed, aad []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, ErrMalformed
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, aad)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return plaintext, nil
+}
+
+// format собирает шифротекст "v1.<id>.<ключ данных>.<значение>"
+func format(keyID string, wrapped, sealed []byte) string {
+	return strings.Join([]string{
+		version,
+		keyID,
+		base64.RawURLEncoding.EncodeToString(wrapped),
+		base64.RawURLEncoding.EncodeToString(sealed),
+	}, ".")
+}
+
+func parse(ciphertext string) (keyID string, wrapped, sealed []byte, err error) {
+	parts := strings.Split(ciphertext, ".")
+	if len(parts) != 4 || parts[0] != version {
+		return "", nil, nil, ErrMalformed
+	}
+	if wrapped, err = base64.RawURLEncoding.DecodeString(parts[2]); err != nil {
+		return "", nil, nil, ErrMalformed
+	}
+	if sealed, err = base64.RawURLEncoding.DecodeString(parts[3]); err != nil {
+		return "", nil, nil, ErrMalformed
+	}
+	return parts[1], wrapped, sealed, nil
+}
@@ -0,0 +1,125 @@
+package envelope
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testKey(id string, fill byte) Key {
+	return Key{ID: id, Secret: bytes.Repeat([]byte{fill}, KeySize)}
+}
+
+func TestParseKeys(t *testing.T) {
+	secret := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, KeySize))
+
+	t.Run("First key is current", func(t *testing.T) {
+		keys, err := ParseKeys("k2:" + secret + ", k1:" + secret)
+		require.NoError(t, err)
+		require.Len(t, keys, 2)
+		assert.Equal(t, "k2", keys[0].ID)
+		assert.Equal(t, "k1", keys[1].ID)
+	})
+
+	tests := []struct {
+		name string
+		spec string
+	}{
+		{"Missing id", secret},
+		{"Invalid id", "k.1:" + secret},
+		{"Invalid base64", "k1:not-base64!"},
+		{"Short key", "k1:" + base64.StdEncoding.EncodeToString([]byte("short"))},
+		{"Duplicate id", "k1:" + secret + ",k1:" + secret},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseKeys(tt.spec)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestKeyring_EncryptDecrypt(t *testing.T) {
+	keyring, err := NewKeyring([]Key{testKey("k1", 1)})
+	require.NoError(t, err)
+	aad := []byte("users.email:1")
+
+	ciphertext, err := keyring.Encrypt("alice@example.com", aad)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(ciphertext, "v1.k1."))
+	assert.NotContains(t, ciphertext, "alice")
+
+	plaintext, err := keyring.Decrypt(ciphertext, aad)
+	require.NoError(t, err)
+	assert.Equal(t, "alice@example.com", plaintext)
+
+	// Каждое значение шифруется своим ключом данных
+	other, err := keyring.Encrypt("alice@example.com", aad)
+	require.NoError(t, err)
+	assert.NotEqual(t, ciphertext, other)
+
+	t.Run("Other record", func(t *testing.T) {
+		_, err := keyring.Decrypt(ciphertext, []byte("users.email:2"))
+		assert.ErrorIs(t, err, ErrDecrypt)
+	})
+
+	t.Run("Tampered", func(t *testing.T) {
+		parts := strings.Split(ciphertext, ".")
+		sealed, _ := base64.RawURLEncoding.DecodeString(parts[3])
+		sealed[len(sealed)-1] ^= 0xff
+		parts[3] = base64.RawURLEncoding.EncodeToString(sealed)
+
+		_, err := keyring.Decrypt(strings.Join(parts, "."), aad)
+		assert.ErrorIs(t, err, ErrDecrypt)
+	})
+
+	t.Run("Malformed", func(t *testing.T) {
+		_, err := keyring.Decrypt("alice@example.com", aad)
+		assert.ErrorIs(t, err, ErrMalformed)
+	})
+}
+
+func TestKeyring_Rotation(t *testing.T) {
+	aad := []byte("users.phone:1")
+	old, err := NewKeyring([]Key{testKey("k1", 1)})
+	require.NoError(t, err)
+	ciphertext, err := old.Encrypt("+79990000000", aad)
+	require.NoError(t, err)
+
+	rotated, err := NewKeyring([]Key{testKey("k2", 2), testKey("k1", 1)})
+	require.NoError(t, err)
+	assert.Equal(t, "k2", rotated.CurrentKeyID())
+
+	// Старые значения читаются, пока прежний ключ остается в списке
+	plaintext, err := rotated.Decrypt(ciphertext, aad)
+	require.NoError(t, err)
+	assert.Equal(t, "+79990000000", plaintext)
+
+	rewrapped, err := rotated.Rewrap(ciphertext)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(rewrapped, "v1.k2."))
+	assert.Equal(t, strings.Split(ciphertext, ".")[3], strings.Split(rewrapped, ".")[3])
+
+	same, err := rotated.Rewrap(rewrapped)
+	require.NoError(t, err)
+	assert.Equal(t, rewrapped, same)
+
+	// После удаления прежнего ключа перешифрованное значение по-прежнему читается
+	current, err := NewKeyring([]Key{testKey("k2", 2)})
+	require.NoError(t, err)
+	plaintext, err = current.Decrypt(rewrapped, aad)
+	require.NoError(t, err)
+	assert.Equal(t, "+79990000000", plaintext)
+
+	_, err = current.Decrypt(ciphertext, aad)
+	assert.ErrorIs(t, err, ErrUnknownKey)
+}
+
+func TestNewKeyring_NoKeys(t *testing.T) {
+	_, err := NewKeyring(nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,146 @@
+// Package fiscalqr разбирает QR код кассового чека вида
+// t=20200924T1837&s=349.93&fn=9282440300682838&i=46534&fp=1273019065&n=1
+// и выводит из реквизитов чека номер заказа.
+package fiscalqr
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/utils/luhn"
+)
+
+// Operation - признак расчета чека
+type Operation int
+
+const (
+	OperationSale          Operation = 1 // Приход
+	OperationSaleRefund    Operation = 2 // Возврат прихода
+	OperationExpense       Operation = 3 // Расход
+	OperationExpenseRefund Operation = 4 // Возврат расхода
+)
+
+// fnLength - длина номера фискального накопителя
+const fnLength = 16
+
+// maxDocumentDigits ограничивает номер документа и фискальный признак: оба не больше uint32
+const maxDocumentDigits = 10
+
+// timeLayouts - форматы времени расчета: секунды в QR коде необязательны
+var timeLayouts = []string{"20060102T150405", "20060102T1504"}
+
+// Receipt - реквизиты чека из QR кода
+type Receipt struct {
+	Time      time.Time // Время расчета без часового пояса
+	Sum       float64   // Итог чека в рублях
+	FN        string    // Номер фискального накопителя
+	FD        string    // Номер фискального документа без ведущих нулей
+	FP        string    // Фискальный признак документа без ведущих нулей
+	Operation Operation
+}
+
+// Parse разбирает содержимое QR кода чека. Все реквизиты обязательны, лишние игнорируются
+func Parse(payload string) (Receipt, error) {
+	values, err := url.ParseQuery(strings.TrimSpace(payload))
+	if err != nil {
+		return Receipt{}, fmt.Errorf("fiscalqr: malformed payload: %w", err)
+	}
+	get := func(key string) (string, error) {
+		value := strings.TrimSpace(values.Get(key))
+		if value == "" {
+			return "", fmt.Errorf("fiscalqr: missing %q", key)
+		}
+		return value, nil
+	}
+
+	var receipt Receipt
+	t, err := get("t")
+	if err != nil {
+		return Receipt{}, err
+	}
+	if receipt.Time, err = parseTime(t); err != nil {
+		return Receipt{}, err
+	}
+
+	s, err := get("s")
+	if err != nil {
+		return Receipt{}, err
+	}
+	receipt.Sum, err = strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(receipt.Sum) || math.IsInf(receipt.Sum, 0) {
+		return Receipt{}, fmt.Errorf("fiscalqr: invalid sum %q", s)
+	}
+
+	if receipt.FN, err = get("fn"); err != nil {
+		return Receipt{}, err
+	}
+	if len(receipt.FN) != fnLength || !isDigits(receipt.FN) {
+		return Receipt{}, fmt.Errorf("fiscalqr: fn must be %d digits", fnLength)
+	}
+
+	if receipt.FD, err = documentNumber(get, "i"); err != nil {
+		return Receipt{}, err
+	}
+	if receipt.FP, err = documentNumber(get, "fp"); err != nil {
+		return Receipt{}, err
+	}
+
+	n, err := get("n")
+	if err != nil {
+		return Receipt{}, err
+	}
+	operation, err := strconv.Atoi(n)
+	if err != nil || operation < int(OperationSale) || operation > int(OperationExpenseRefund) {
+		return Receipt{}, fmt.Errorf("fiscalqr: invalid operation %q", n)
+	}
+	receipt.Operation = Operation(operation)
+
+	return receipt, nil
+}
+
+// OrderNumber выводит номер заказа из реквизитов: номер накопителя, номер документа
+// и контрольная цифра по алгоритму Луна. Номер накопителя фиксированной длины, поэтому
+// разные чеки не дают одинаковых номеров, а повторное сканирование чека - дает тот же номер
+func (r Receipt) OrderNumber() string {
+	number := r.FN + r.FD
+	check, _ := luhn.CheckDigit(number)
+	return number + string(check)
+}
+
+func parseTime(value string) (time.Time, error) {
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("fiscalqr: invalid time %q", value)
+}
+
+// documentNumber возвращает номер из цифр без ведущих нулей: сканеры и кассы
+// по-разному дополняют номера нулями
+func documentNumber(get func(string) (string, error), key string) (string, error) {
+	value, err := get(key)
+	if err != nil {
+		return "", err
+	}
+	if len(value) > maxDocumentDigits || !isDigits(value) {
+		return "", fmt.Errorf("fiscalqr: %s must be at most %d digits", key, maxDocumentDigits)
+	}
+	if trimmed := strings.TrimLeft(value, "0"); trimmed != "" {
+		return trimmed, nil
+	}
+	return "", fmt.Errorf("fiscalqr: %s must not be zero", key)
+}
+
+func isDigits(value string) bool {
+	for _, ch := range value {
+		if ch < '0' || ch > '9' {
+			return false
+		}
+	}
+	return value != ""
+}
@@ -0,0 +1,71 @@
+package fiscalqr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/utils/luhn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	receipt, err := Parse(" t=20200924T1837&s=349.93&fn=9282440300682838&i=046534&fp=1273019065&n=1&extra=1 \n")
+	require.NoError(t, err)
+	assert.Equal(t, Receipt{
+		Time:      time.Date(2020, 9, 24, 18, 37, 0, 0, time.UTC),
+		Sum:       349.93,
+		FN:        "9282440300682838",
+		FD:        "46534",
+		FP:        "1273019065",
+		Operation: OperationSale,
+	}, receipt)
+
+	receipt, err = Parse("t=20200924T183705&s=10&fn=9282440300682838&i=1&fp=2&n=2")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2020, 9, 24, 18, 37, 5, 0, time.UTC), receipt.Time)
+	assert.Equal(t, OperationSaleRefund, receipt.Operation)
+}
+
+func TestParse_Invalid(t *testing.T) {
+	const valid = "t=20200924T1837&s=349.93&fn=9282440300682838&i=46534&fp=1273019065&n=1"
+	tests := map[string]string{
+		"empty":              "",
+		"not a query":        "%zz",
+		"plain order number": "12345678903",
+		"missing time":       "s=349.93&fn=9282440300682838&i=46534&fp=1273019065&n=1",
+		"invalid time":       "t=2020-09-24&s=349.93&fn=9282440300682838&i=46534&fp=1273019065&n=1",
+		"invalid sum":        "t=20200924T1837&s=abc&fn=9282440300682838&i=46534&fp=1273019065&n=1",
+		"infinite sum":       "t=20200924T1837&s=Inf&fn=9282440300682838&i=46534&fp=1273019065&n=1",
+		"short fn":           "t=20200924T1837&s=349.93&fn=928244030068283&i=46534&fp=1273019065&n=1",
+		"letters in fd":      "t=20200924T1837&s=349.93&fn=9282440300682838&i=4653a&fp=1273019065&n=1",
+		"zero fd":            "t=20200924T1837&s=349.93&fn=9282440300682838&i=000&fp=1273019065&n=1",
+		"long fp":            "t=20200924T1837&s=349.93&fn=9282440300682838&i=46534&fp=12730190651&n=1",
+		"unknown operation":  "t=20200924T1837&s=349.93&fn=9282440300682838&i=46534&fp=1273019065&n=5",
+	}
+
+	_, err := Parse(valid)
+	require.NoError(t, err)
+	for name, payload := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse(payload)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestReceipt_OrderNumber(t *testing.T) {
+	receipt := Receipt{FN: "9282440300682838", FD: "46534"}
+	number := receipt.OrderNumber()
+
+	assert.Len(t, number, 22)
+	assert.Equal(t, "928244030068283846534", number[:21])
+	assert.True(t, luhn.Validate(number))
+
+	// Номер зависит только от накопителя и документа
+	other := receipt
+	other.FP, other.Sum = "1", 1
+	assert.Equal(t, number, other.OrderNumber())
+	other.FD = "46535"
+	assert.NotEqual(t, number, other.OrderNumber())
+}
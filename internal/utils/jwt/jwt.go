@@ -1,18 +1,30 @@
 package jwt
 
 import (
+	"crypto/rand"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
 )
 
 // Claims представляет JWT claims с ID пользователя
 type Claims struct {
 	UserID int64 `json:"user_id"`
+	// Администратор, действующий от имени пользователя (0 - обычный токен)
+	ImpersonatorID int64 `json:"impersonator_id,omitempty"`
+	// Назначение токена: пусто - токен доступа, TokenTypeRefresh - обновление токена доступа
+	Type string `json:"typ,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// TokenTypeRefresh - тип refresh-токена. Refresh-токен нельзя использовать для доступа,
+// а токен доступа - для обновления
+const TokenTypeRefresh = "refresh"
+
 // Manager управляет генерацией и валидацией JWT токенов
 type Manager struct {
 	secretKey string
@@ -27,15 +39,48 @@ func NewManager(secretKey string, tokenTTL time.Duration) *Manager {
 	}
 }
 
-// Generate генерирует новый JWT токен для пользователя
-func (m *Manager) Generate(userID int64) (string, error) {
-	claims := Claims{
-		UserID: userID,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(m.tokenTTL)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-		},
+// TokenTTL возвращает время жизни токена по умолчанию
+func (m *Manager) TokenTTL() time.Duration {
+	return m.tokenTTL
+}
+
+// Generate генерирует новый JWT токен для пользователя со временем жизни ttl.
+// ttl <= 0 - время жизни по умолчанию
+func (m *Manager) Generate(userID int64, ttl time.Duration) (string, error) {
+	return m.sign(&Claims{UserID: userID}, ttl)
+}
+
+// GenerateImpersonation генерирует токен пользователя userID, выданный администратору
+// impersonatorID. ttl <= 0 - время жизни по умолчанию
+func (m *Manager) GenerateImpersonation(userID, impersonatorID int64, ttl time.Duration) (string, error) {
+	return m.sign(&Claims{UserID: userID, ImpersonatorID: impersonatorID}, ttl)
+}
+
+// GenerateRefresh генерирует refresh-токен пользователя со временем жизни ttl.
+// Возвращает и его claims: по идентификатору (ID) токен отзывается
+func (m *Manager) GenerateRefresh(userID int64, ttl time.Duration) (string, *Claims, error) {
+	claims := Claims{UserID: userID, Type: TokenTypeRefresh}
+	claims.ID = rand.Text()
+	token, err := m.sign(&claims, ttl)
+	if err != nil {
+		return "", nil, err
+	}
+	return token, &claims, nil
+}
+
+// sign проставляет срок действия и подписывает claims. Токену без идентификатора
+// назначается случайный: иначе токены, выданные пользователю в одну секунду, совпадали бы
+// и выход по одному из них отзывал бы и остальные
+func (m *Manager) sign(claims *Claims, ttl time.Duration) (string, error) {
+	if ttl <= 0 {
+		ttl = m.tokenTTL
 	}
+	if claims.ID == "" {
+		claims.ID = rand.Text()
+	}
+	now := time.Now()
+	claims.ExpiresAt = jwt.NewNumericDate(now.Add(ttl))
+	claims.IssuedAt = jwt.NewNumericDate(now)
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	signedToken, err := token.SignedString([]byte(m.secretKey))
@@ -46,8 +91,55 @@ func (m *Manager) Generate(userID int64) (string, error) {
 	return signedToken, nil
 }
 
+// classifyError дополняет ошибку библиотеки причиной из domain: истекший срок,
+// неверный формат или подпись. Остальные ошибки возвращаются как есть
+func classifyError(err error) error {
+	switch {
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return fmt.Errorf("%w: %w", domain.ErrTokenExpired, err)
+	case errors.Is(err, jwt.ErrTokenMalformed):
+		return fmt.Errorf("%w: %w", domain.ErrTokenMalformed, err)
+	case errors.Is(err, jwt.ErrTokenSignatureInvalid), errors.Is(err, jwt.ErrTokenUnverifiable):
+		return fmt.Errorf("%w: %w", domain.ErrTokenSignature, err)
+	}
+	return err
+}
+
 // Validate валидирует JWT токен и возвращает user ID
 func (m *Manager) Validate(tokenString string) (int64, error) {
+	claims, err := m.ValidateClaims(tokenString)
+	if err != nil {
+		return 0, err
+	}
+	return claims.UserID, nil
+}
+
+// ValidateClaims валидирует JWT токен доступа и возвращает его claims, включая срок действия
+func (m *Manager) ValidateClaims(tokenString string) (*Claims, error) {
+	claims, err := m.parse(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Type != "" {
+		return nil, fmt.Errorf("%w: %s token cannot be used for access", domain.ErrTokenMalformed, claims.Type)
+	}
+	return claims, nil
+}
+
+// ValidateRefresh валидирует refresh-токен и возвращает его claims
+func (m *Manager) ValidateRefresh(tokenString string) (*Claims, error) {
+	claims, err := m.parse(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Type != TokenTypeRefresh || claims.ID == "" {
+		return nil, fmt.Errorf("%w: not a refresh token", domain.ErrTokenMalformed)
+	}
+	return claims, nil
+}
+
+// parse проверяет подпись и срок действия токена любого типа
+func (m *Manager) parse(tokenString string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
 		// Проверяем метод подписи
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
@@ -57,17 +149,17 @@ func (m *Manager) Validate(tokenString string) (int64, error) {
 	})
 
 	if err != nil {
-		return 0, fmt.Errorf("failed to parse token: %w", err)
+		return nil, fmt.Errorf("failed to parse token: %w", classifyError(err))
 	}
 
 	if !token.Valid {
-		return 0, fmt.Errorf("invalid token")
+		return nil, fmt.Errorf("invalid token")
 	}
 
 	claims, ok := token.Claims.(*Claims)
 	if !ok {
-		return 0, fmt.Errorf("invalid token claims")
+		return nil, fmt.Errorf("invalid token claims")
 	}
 
-	return claims.UserID, nil
+	return claims, nil
 }
@@ -6,6 +6,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
 )
 
 func TestManager_Generate(t *testing.T) {
@@ -42,7 +44,7 @@ func TestManager_Generate(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			m := NewManager(tt.secretKey, tt.tokenTTL)
-			token, err := m.Generate(tt.userID)
+			token, err := m.Generate(tt.userID, 0)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -62,7 +64,7 @@ func TestManager_Validate(t *testing.T) {
 
 	t.Run("Valid token", func(t *testing.T) {
 		m := NewManager(secretKey, tokenTTL)
-		token, err := m.Generate(userID)
+		token, err := m.Generate(userID, 0)
 		require.NoError(t, err)
 
 		parsedUserID, err := m.Validate(token)
@@ -72,36 +74,36 @@ func TestManager_Validate(t *testing.T) {
 
 	t.Run("Invalid token - wrong secret", func(t *testing.T) {
 		m1 := NewManager(secretKey, tokenTTL)
-		token, err := m1.Generate(userID)
+		token, err := m1.Generate(userID, 0)
 		require.NoError(t, err)
 
 		m2 := NewManager("wrong-secret", tokenTTL)
 		_, err = m2.Validate(token)
-		assert.Error(t, err)
+		assert.ErrorIs(t, err, domain.ErrTokenSignature)
 	})
 
 	t.Run("Invalid token - malformed", func(t *testing.T) {
 		m := NewManager(secretKey, tokenTTL)
 		_, err := m.Validate("invalid.token.string")
-		assert.Error(t, err)
+		assert.ErrorIs(t, err, domain.ErrTokenMalformed)
 	})
 
 	t.Run("Invalid token - empty", func(t *testing.T) {
 		m := NewManager(secretKey, tokenTTL)
 		_, err := m.Validate("")
-		assert.Error(t, err)
+		assert.ErrorIs(t, err, domain.ErrTokenMalformed)
 	})
 
 	t.Run("Expired token", func(t *testing.T) {
 		m := NewManager(secretKey, time.Nanosecond)
-		token, err := m.Generate(userID)
+		token, err := m.Generate(userID, 0)
 		require.NoError(t, err)
 
 		// Ждем, чтобы токен истек
 		time.Sleep(time.Millisecond * 10)
 
 		_, err = m.Validate(token)
-		assert.Error(t, err)
+		assert.ErrorIs(t, err, domain.ErrTokenExpired)
 	})
 
 	t.Run("Multiple users", func(t *testing.T) {
@@ -110,10 +112,10 @@ func TestManager_Validate(t *testing.T) {
 		userID1 := int64(100)
 		userID2 := int64(200)
 
-		token1, err := m.Generate(userID1)
+		token1, err := m.Generate(userID1, 0)
 		require.NoError(t, err)
 
-		token2, err := m.Generate(userID2)
+		token2, err := m.Generate(userID2, 0)
 		require.NoError(t, err)
 
 		parsedID1, err := m.Validate(token1)
@@ -126,13 +128,80 @@ func TestManager_Validate(t *testing.T) {
 	})
 }
 
+func TestManager_GenerateWithTTL(t *testing.T) {
+	m := NewManager("secret", time.Hour)
+	assert.Equal(t, time.Hour, m.TokenTTL())
+
+	token, err := m.Generate(1, 24*time.Hour)
+	require.NoError(t, err)
+	claims, err := m.ValidateClaims(token)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(24*time.Hour), claims.ExpiresAt.Time, 5*time.Second)
+
+	// Неположительный срок означает срок по умолчанию
+	token, err = m.Generate(1, 0)
+	require.NoError(t, err)
+	claims, err = m.ValidateClaims(token)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), claims.ExpiresAt.Time, 5*time.Second)
+	assert.Zero(t, claims.ImpersonatorID)
+
+	// Токены, выданные в одну секунду, различаются идентификатором
+	other, err := m.Generate(1, 0)
+	require.NoError(t, err)
+	assert.NotEqual(t, token, other)
+	assert.NotEmpty(t, claims.ID)
+}
+
+func TestManager_GenerateImpersonation(t *testing.T) {
+	m := NewManager("secret", time.Hour)
+
+	token, err := m.GenerateImpersonation(7, 1, 15*time.Minute)
+	require.NoError(t, err)
+	claims, err := m.ValidateClaims(token)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), claims.UserID)
+	assert.Equal(t, int64(1), claims.ImpersonatorID)
+	assert.WithinDuration(t, time.Now().Add(15*time.Minute), claims.ExpiresAt.Time, 5*time.Second)
+}
+
+func TestManager_GenerateRefresh(t *testing.T) {
+	m := NewManager("secret", time.Hour)
+
+	token, issued, err := m.GenerateRefresh(7, 30*24*time.Hour)
+	require.NoError(t, err)
+	assert.NotEmpty(t, issued.ID)
+
+	claims, err := m.ValidateRefresh(token)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), claims.UserID)
+	assert.Equal(t, issued.ID, claims.ID)
+	assert.WithinDuration(t, time.Now().Add(30*24*time.Hour), claims.ExpiresAt.Time, 5*time.Second)
+
+	// Каждый refresh-токен получает свой идентификатор
+	_, other, err := m.GenerateRefresh(7, time.Hour)
+	require.NoError(t, err)
+	assert.NotEqual(t, issued.ID, other.ID)
+
+	// Refresh-токен не дает доступа, а токен доступа не обновляется
+	_, err = m.ValidateClaims(token)
+	assert.ErrorIs(t, err, domain.ErrTokenMalformed)
+	access, err := m.Generate(7, 0)
+	require.NoError(t, err)
+	_, err = m.ValidateRefresh(access)
+	assert.ErrorIs(t, err, domain.ErrTokenMalformed)
+
+	_, err = NewManager("other", time.Hour).ValidateRefresh(token)
+	assert.ErrorIs(t, err, domain.ErrTokenSignature)
+}
+
 func TestManager_ValidateWithInvalidSigningMethod(t *testing.T) {
 	// Создаем токен с неправильным методом подписи
 	m := NewManager("secret", time.Hour)
 
 	// Попытка валидации токена с неправильной структурой
 	_, err := m.Validate("eyJhbGciOiJub25lIiwidHlwIjoiSldUIn0.eyJ1c2VyX2lkIjoxMjM0NX0.")
-	assert.Error(t, err)
+	assert.ErrorIs(t, err, domain.ErrTokenSignature)
 }
 
 func BenchmarkManager_Generate(b *testing.B) {
@@ -141,14 +210,14 @@ func BenchmarkManager_Generate(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, _ = m.Generate(userID)
+		_, _ = m.Generate(userID, 0)
 	}
 }
 
 func BenchmarkManager_Validate(b *testing.B) {
 	m := NewManager("test-secret-key", time.Hour)
 	userID := int64(12345)
-	token, _ := m.Generate(userID)
+	token, _ := m.Generate(userID, 0)
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
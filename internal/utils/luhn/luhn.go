@@ -1,7 +1,6 @@
 package luhn
 
 import (
-	"strconv"
 	"strings"
 )
 
@@ -10,7 +9,8 @@ func Validate(number string) bool {
 	// Удаляем пробелы
 	number = strings.ReplaceAll(number, " ", "")
 
-	// Проверяем, что строка содержит только цифры
+	// Проверяем, что строка содержит только цифры ASCII: цифры других
+	// алфавитов (арабские, полноширинные) номером заказа не считаются
 	if len(number) == 0 {
 		return false
 	}
@@ -27,10 +27,7 @@ func Validate(number string) bool {
 
 	// Проходим с конца строки
 	for i := len(number) - 1; i >= 0; i-- {
-		digit, err := strconv.Atoi(string(number[i]))
-		if err != nil {
-			return false
-		}
+		digit := int(number[i] - '0')
 
 		if isSecond {
 			digit *= 2
@@ -45,3 +42,33 @@ func Validate(number string) bool {
 
 	return sum%10 == 0
 }
+
+// CheckDigit возвращает контрольную цифру, которую нужно дописать к номеру из цифр ASCII,
+// чтобы он прошел Validate. Для пустой строки или строки не из цифр возвращает false
+func CheckDigit(number string) (byte, bool) {
+	if len(number) == 0 {
+		return 0, false
+	}
+
+	// Контрольная цифра встанет в конец, поэтому удваивается последняя цифра номера
+	sum := 0
+	isSecond := true
+	for i := len(number) - 1; i >= 0; i-- {
+		if number[i] < '0' || number[i] > '9' {
+			return 0, false
+		}
+		digit := int(number[i] - '0')
+
+		if isSecond {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+
+		sum += digit
+		isSecond = !isSecond
+	}
+
+	return byte('0' + (10-sum%10)%10), true
+}
@@ -1,6 +1,8 @@
 package luhn
 
 import (
+	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -80,6 +82,26 @@ func TestValidate(t *testing.T) {
 			number: "1234-5678-9012",
 			want:   false,
 		},
+		{
+			name:   "Arabic-Indic digits",
+			number: "٧٩٩٢٧٣٩٨٧١٣",
+			want:   false,
+		},
+		{
+			name:   "Fullwidth digits",
+			number: "７９９２７３９８７１３",
+			want:   false,
+		},
+		{
+			name:   "Very long number",
+			number: strings.Repeat("79927398713", 1000),
+			want:   true,
+		},
+		{
+			name:   "Scientific notation",
+			number: "1e10",
+			want:   false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -91,6 +113,65 @@ func TestValidate(t *testing.T) {
 	}
 }
 
+func TestCheckDigit(t *testing.T) {
+	tests := []struct {
+		number string
+		want   byte
+		ok     bool
+	}{
+		{"7992739871", '3', true},
+		{"1234567890", '3', true},
+		{"456126121234546", '7', true},
+		{"0", '0', true},
+		{"", 0, false},
+		{"12a4", 0, false},
+		{"1234 567890", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.number, func(t *testing.T) {
+			got, ok := CheckDigit(tt.number)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("CheckDigit(%q) = %q, %v, want %q, %v", tt.number, got, ok, tt.want, tt.ok)
+			}
+			if ok && !Validate(tt.number+string(got)) {
+				t.Errorf("Validate(%q) = false", tt.number+string(got))
+			}
+		})
+	}
+}
+
+func FuzzValidate(f *testing.F) {
+	for _, seed := range []string{"79927398713", "4561 2612 1234 5467", "٧٩٩٢٧٣٩٨٧١٣", "1e10", "", " "} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, number string) {
+		if Validate(number) {
+			for _, ch := range strings.ReplaceAll(number, " ", "") {
+				if ch < '0' || ch > '9' {
+					t.Fatalf("Validate(%q) accepted non-digit %q", number, ch)
+				}
+			}
+		}
+
+		// Для строки из цифр подходит ровно одна контрольная цифра
+		digits := strings.ReplaceAll(number, " ", "")
+		if digits == "" || strings.Trim(digits, "0123456789") != "" {
+			return
+		}
+		valid := 0
+		for check := 0; check <= 9; check++ {
+			if Validate(digits + strconv.Itoa(check)) {
+				valid++
+			}
+		}
+		if valid != 1 {
+			t.Fatalf("%q has %d valid check digits", digits, valid)
+		}
+	})
+}
+
 func BenchmarkValidate(b *testing.B) {
 	validNumbers := []string{
 		"79927398713",
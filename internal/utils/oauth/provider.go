@@ -0,0 +1,247 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+)
+
+// Адреса провайдеров по умолчанию
+const (
+	googleAuthURL     = "https://accounts.google.com/o/oauth2/v2/auth"
+	googleTokenURL    = "https://oauth2.googleapis.com/token"
+	googleUserInfoURL = "https://openidconnect.googleapis.com/v1/userinfo"
+
+	vkAuthURL     = "https://oauth.vk.com/authorize"
+	vkTokenURL    = "https://oauth.vk.com/access_token"
+	vkUserInfoURL = "https://api.vk.com/method/users.get"
+	vkAPIVersion  = "5.199"
+)
+
+// Config содержит параметры приложения у провайдера OAuth2
+type Config struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string // Адрес callback, зарегистрированный у провайдера
+
+	// Адреса провайдера, пусто - адреса по умолчанию
+	AuthURL     string
+	TokenURL    string
+	UserInfoURL string
+}
+
+// tokenResponse - ответ token endpoint. VK кроме токена возвращает ID и email пользователя
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	UserID           int64  `json:"user_id"`
+	Email            string `json:"email"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// Provider реализует authorization code flow OAuth2 для одного провайдера
+type Provider struct {
+	name      string
+	config    Config
+	scopes    []string
+	client    *http.Client
+	fetchUser func(ctx context.Context, p *Provider, token *tokenResponse) (*domain.ExternalIdentity, error)
+}
+
+// NewGoogle создает провайдер Google
+func NewGoogle(config Config, client *http.Client) *Provider {
+	config.AuthURL = defaultString(config.AuthURL, googleAuthURL)
+	config.TokenURL = defaultString(config.TokenURL, googleTokenURL)
+	config.UserInfoURL = defaultString(config.UserInfoURL, googleUserInfoURL)
+	return newProvider("google", config, []string{"openid", "email"}, client, fetchGoogleUser)
+}
+
+// NewVK создает провайдер VK
+func NewVK(config Config, client *http.Client) *Provider {
+	config.AuthURL = defaultString(config.AuthURL, vkAuthURL)
+	config.TokenURL = defaultString(config.TokenURL, vkTokenURL)
+	config.UserInfoURL = defaultString(config.UserInfoURL, vkUserInfoURL)
+	return newProvider("vk", config, []string{"email"}, client, fetchVKUser)
+}
+
+func newProvider(
+	name string,
+	config Config,
+	scopes []string,
+	client *http.Client,
+	fetchUser func(ctx context.Context, p *Provider, token *tokenResponse) (*domain.ExternalIdentity, error),
+) *Provider {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &Provider{
+		name:      name,
+		config:    config,
+		scopes:    scopes,
+		client:    client,
+		fetchUser: fetchUser,
+	}
+}
+
+// Name возвращает имя провайдера, под которым хранятся привязанные учетные записи
+func (p *Provider) Name() string {
+	return p.name
+}
+
+// AuthCodeURL возвращает адрес страницы входа провайдера
+func (p *Provider) AuthCodeURL(state string) string {
+	params := url.Values{
+		"response_type": {"code"},
+		"client_id":     {p.config.ClientID},
+		"redirect_uri":  {p.config.RedirectURL},
+		"scope":         {strings.Join(p.scopes, " ")},
+		"state":         {state},
+	}
+	separator := "?"
+	if strings.Contains(p.config.AuthURL, "?") {
+		separator = "&"
+	}
+	return p.config.AuthURL + separator + params.Encode()
+}
+
+// Exchange обменивает код авторизации на токен провайдера и возвращает учетную запись пользователя
+func (p *Provider) Exchange(ctx context.Context, code string) (*domain.ExternalIdentity, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.config.RedirectURL},
+		"client_id":     {p.config.ClientID},
+		"client_secret": {p.config.ClientSecret},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.config.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("oauth %s: failed to create token request: %w", p.name, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	var token tokenResponse
+	if err := p.do(req, &token); err != nil {
+		return nil, fmt.Errorf("oauth %s: failed to exchange code: %w", p.name, err)
+	}
+	if token.Error != "" {
+		return nil, fmt.Errorf("oauth %s: token endpoint error %q: %s", p.name, token.Error, token.ErrorDescription)
+	}
+	if token.AccessToken == "" {
+		return nil, fmt.Errorf("oauth %s: token endpoint returned no access token", p.name)
+	}
+
+	identity, err := p.fetchUser(ctx, p, &token)
+	if err != nil {
+		return nil, fmt.Errorf("oauth %s: failed to get user: %w", p.name, err)
+	}
+	identity.Issuer = p.name
+	return identity, nil
+}
+
+// do выполняет запрос и декодирует JSON ответ
+func (p *Provider) do(req *http.Request, dst any) error {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	// Ошибки token endpoint приходят с кодом 4xx и JSON телом, их разбирает вызывающий
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	if err := json.Unmarshal(body, dst); err != nil {
+		return fmt.Errorf("failed to decode response with status %d: %w", resp.StatusCode, err)
+	}
+	return nil
+}
+
+// fetchGoogleUser получает пользователя из userinfo endpoint OpenID Connect
+func fetchGoogleUser(ctx context.Context, p *Provider, token *tokenResponse) (*domain.ExternalIdentity, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.config.UserInfoURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token.AccessToken)
+
+	var info struct {
+		Subject       string `json:"sub"`
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+	}
+	if err := p.do(req, &info); err != nil {
+		return nil, err
+	}
+	if info.Subject == "" {
+		return nil, fmt.Errorf("userinfo has no subject")
+	}
+
+	login := info.Subject
+	if info.Email != "" && info.EmailVerified {
+		login = info.Email
+	}
+	return &domain.ExternalIdentity{Subject: info.Subject, Login: login}, nil
+}
+
+// fetchVKUser получает пользователя через метод users.get API VK
+func fetchVKUser(ctx context.Context, p *Provider, token *tokenResponse) (*domain.ExternalIdentity, error) {
+	params := url.Values{
+		"access_token": {token.AccessToken},
+		"fields":       {"screen_name"},
+		"v":            {vkAPIVersion},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.config.UserInfoURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var info struct {
+		Response []struct {
+			ID         int64  `json:"id"`
+			ScreenName string `json:"screen_name"`
+		} `json:"response"`
+		Error *struct {
+			Message string `json:"error_msg"`
+		} `json:"error"`
+	}
+	if err := p.do(req, &info); err != nil {
+		return nil, err
+	}
+	if info.Error != nil {
+		return nil, fmt.Errorf("api error: %s", info.Error.Message)
+	}
+	if len(info.Response) == 0 || info.Response[0].ID == 0 {
+		return nil, fmt.Errorf("users.get returned no user")
+	}
+
+	user := info.Response[0]
+	subject := strconv.FormatInt(user.ID, 10)
+	login := token.Email
+	if login == "" {
+		login = user.ScreenName
+	}
+	if login == "" {
+		login = "id" + subject
+	}
+	return &domain.ExternalIdentity{Subject: subject, Login: login}, nil
+}
+
+func defaultString(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
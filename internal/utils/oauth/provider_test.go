@@ -0,0 +1,135 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestServer эмулирует token и userinfo endpoints провайдера
+func newTestServer(t *testing.T, token map[string]any, userInfo map[string]any) (*httptest.Server, *url.Values) {
+	var form url.Values
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		form = r.PostForm
+		if token == nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"}) //nolint:errcheck
+			return
+		}
+		json.NewEncoder(w).Encode(token) //nolint:errcheck
+	})
+	mux.HandleFunc("/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer access" && r.URL.Query().Get("access_token") != "access" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(userInfo) //nolint:errcheck
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, &form
+}
+
+func testConfig(server *httptest.Server) Config {
+	return Config{
+		ClientID:     "client",
+		ClientSecret: "secret",
+		RedirectURL:  "https://mart.example.com/api/user/oauth/test/callback",
+		AuthURL:      server.URL + "/authorize",
+		TokenURL:     server.URL + "/token",
+		UserInfoURL:  server.URL + "/userinfo",
+	}
+}
+
+func TestProvider_AuthCodeURL(t *testing.T) {
+	provider := NewGoogle(Config{ClientID: "client", RedirectURL: "https://mart.example.com/cb"}, nil)
+
+	authURL, err := url.Parse(provider.AuthCodeURL("state-value"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "accounts.google.com", authURL.Host)
+	query := authURL.Query()
+	assert.Equal(t, "code", query.Get("response_type"))
+	assert.Equal(t, "client", query.Get("client_id"))
+	assert.Equal(t, "https://mart.example.com/cb", query.Get("redirect_uri"))
+	assert.Equal(t, "openid email", query.Get("scope"))
+	assert.Equal(t, "state-value", query.Get("state"))
+}
+
+func TestProvider_ExchangeGoogle(t *testing.T) {
+	t.Run("Verified email becomes login", func(t *testing.T) {
+		server, form := newTestServer(t,
+			map[string]any{"access_token": "access"},
+			map[string]any{"sub": "1001", "email": "alice@example.com", "email_verified": true})
+		provider := NewGoogle(testConfig(server), server.Client())
+
+		identity, err := provider.Exchange(context.Background(), "code")
+		require.NoError(t, err)
+		assert.Equal(t, &domain.ExternalIdentity{Issuer: "google", Subject: "1001", Login: "alice@example.com"}, identity)
+		assert.Equal(t, "code", form.Get("code"))
+		assert.Equal(t, "authorization_code", form.Get("grant_type"))
+		assert.Equal(t, "secret", form.Get("client_secret"))
+	})
+
+	t.Run("Unverified email is ignored", func(t *testing.T) {
+		server, _ := newTestServer(t,
+			map[string]any{"access_token": "access"},
+			map[string]any{"sub": "1001", "email": "alice@example.com", "email_verified": false})
+		provider := NewGoogle(testConfig(server), server.Client())
+
+		identity, err := provider.Exchange(context.Background(), "code")
+		require.NoError(t, err)
+		assert.Equal(t, "1001", identity.Login)
+	})
+
+	t.Run("Rejected code", func(t *testing.T) {
+		server, _ := newTestServer(t, nil, nil)
+		provider := NewGoogle(testConfig(server), server.Client())
+
+		_, err := provider.Exchange(context.Background(), "code")
+		assert.ErrorContains(t, err, "invalid_grant")
+	})
+}
+
+func TestProvider_ExchangeVK(t *testing.T) {
+	t.Run("Email from token response", func(t *testing.T) {
+		server, _ := newTestServer(t,
+			map[string]any{"access_token": "access", "user_id": 42, "email": "bob@example.com"},
+			map[string]any{"response": []map[string]any{{"id": 42, "screen_name": "bob"}}})
+		provider := NewVK(testConfig(server), server.Client())
+
+		identity, err := provider.Exchange(context.Background(), "code")
+		require.NoError(t, err)
+		assert.Equal(t, &domain.ExternalIdentity{Issuer: "vk", Subject: "42", Login: "bob@example.com"}, identity)
+	})
+
+	t.Run("Screen name without email", func(t *testing.T) {
+		server, _ := newTestServer(t,
+			map[string]any{"access_token": "access", "user_id": 42},
+			map[string]any{"response": []map[string]any{{"id": 42, "screen_name": "bob"}}})
+		provider := NewVK(testConfig(server), server.Client())
+
+		identity, err := provider.Exchange(context.Background(), "code")
+		require.NoError(t, err)
+		assert.Equal(t, "bob", identity.Login)
+	})
+
+	t.Run("API error", func(t *testing.T) {
+		server, _ := newTestServer(t,
+			map[string]any{"access_token": "access"},
+			map[string]any{"error": map[string]any{"error_msg": "User authorization failed"}})
+		provider := NewVK(testConfig(server), server.Client())
+
+		_, err := provider.Exchange(context.Background(), "code")
+		assert.ErrorContains(t, err, "User authorization failed")
+	})
+}
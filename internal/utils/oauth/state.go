@@ -0,0 +1,73 @@
+package oauth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+// ErrInvalidState - параметр state подделан, поврежден или просрочен
+var ErrInvalidState = errors.New("invalid oauth state")
+
+// State - данные входа, которые провайдер возвращает в callback без изменений
+type State struct {
+	Provider   string `json:"p"`
+	Nonce      string `json:"n"`           // Совпадает со значением cookie браузера, начавшего вход
+	LinkUserID int64  `json:"u,omitempty"` // Пользователь, к которому привязывается учетная запись
+	ExpiresAt  int64  `json:"e"`
+}
+
+// StateSigner подписывает параметр state, чтобы не хранить начатые входы на сервере
+type StateSigner struct {
+	key []byte
+}
+
+// NewStateSigner создает StateSigner. Ключ выводится из секрета, чтобы подпись state
+// нельзя было использовать как подпись токена доступа и наоборот
+func NewStateSigner(secret string) *StateSigner {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("oauth-state"))
+	return &StateSigner{key: mac.Sum(nil)}
+}
+
+// Sign возвращает подписанный state
+func (s *StateSigner) Sign(state State) string {
+	payload, _ := json.Marshal(state) //nolint:errcheck // структура из простых полей всегда сериализуется
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(s.mac(encoded))
+}
+
+// Verify проверяет подпись и срок действия state
+func (s *StateSigner) Verify(value string, now time.Time) (*State, error) {
+	encoded, signature, ok := strings.Cut(value, ".")
+	if !ok {
+		return nil, ErrInvalidState
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, s.mac(encoded)) {
+		return nil, ErrInvalidState
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidState
+	}
+	var state State
+	if err := json.Unmarshal(payload, &state); err != nil {
+		return nil, ErrInvalidState
+	}
+	if now.Unix() > state.ExpiresAt {
+		return nil, ErrInvalidState
+	}
+	return &state, nil
+}
+
+func (s *StateSigner) mac(data string) []byte {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
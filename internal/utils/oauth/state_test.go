@@ -0,0 +1,49 @@
+package oauth
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateSigner(t *testing.T) {
+	signer := NewStateSigner("secret")
+	now := time.Now()
+	state := State{Provider: "google", Nonce: "nonce", LinkUserID: 7, ExpiresAt: now.Add(time.Minute).Unix()}
+
+	t.Run("Round trip", func(t *testing.T) {
+		got, err := signer.Verify(signer.Sign(state), now)
+		require.NoError(t, err)
+		assert.Equal(t, &state, got)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		_, err := signer.Verify(signer.Sign(state), now.Add(2*time.Minute))
+		assert.ErrorIs(t, err, ErrInvalidState)
+	})
+
+	t.Run("Signed with another secret", func(t *testing.T) {
+		_, err := signer.Verify(NewStateSigner("other").Sign(state), now)
+		assert.ErrorIs(t, err, ErrInvalidState)
+	})
+
+	t.Run("Tampered payload", func(t *testing.T) {
+		forged := state
+		forged.LinkUserID = 1
+		payload, _, _ := strings.Cut(signer.Sign(forged), ".")
+		_, signature, _ := strings.Cut(signer.Sign(state), ".")
+
+		_, err := signer.Verify(payload+"."+signature, now)
+		assert.ErrorIs(t, err, ErrInvalidState)
+	})
+
+	t.Run("Malformed", func(t *testing.T) {
+		for _, value := range []string{"", "no-dot", "!!!.!!!"} {
+			_, err := signer.Verify(value, now)
+			assert.ErrorIs(t, err, ErrInvalidState, value)
+		}
+	})
+}
@@ -0,0 +1,284 @@
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// DefaultMinRefreshInterval - минимальный интервал между загрузками ключей провайдера.
+// Токен с неизвестным kid не должен приводить к запросу ключей на каждый HTTP запрос.
+const DefaultMinRefreshInterval = time.Minute
+
+// signingMethods - допустимые алгоритмы подписи. HMAC исключен: иначе токен,
+// подписанный публичным ключом как секретом, прошел бы проверку.
+var signingMethods = []string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}
+
+// Config содержит параметры внешнего провайдера удостоверений
+type Config struct {
+	Issuer             string        // Ожидаемое значение iss
+	Audience           string        // Ожидаемое значение aud (пусто - не проверяется)
+	JWKSURL            string        // Адрес ключей; пусто - из /.well-known/openid-configuration
+	MinRefreshInterval time.Duration // Минимальный интервал между загрузками ключей
+}
+
+// Verifier проверяет токены, выпущенные провайдером OIDC, по его публичным ключам (JWKS)
+type Verifier struct {
+	config Config
+	client *http.Client
+
+	mu        sync.Mutex
+	jwksURL   string
+	keys      map[string]any
+	fetchedAt time.Time
+}
+
+// NewVerifier создает новый Verifier. Ключи загружаются при первой проверке токена
+func NewVerifier(config Config, client *http.Client) *Verifier {
+	if config.MinRefreshInterval <= 0 {
+		config.MinRefreshInterval = DefaultMinRefreshInterval
+	}
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &Verifier{
+		config:  config,
+		client:  client,
+		jwksURL: config.JWKSURL,
+	}
+}
+
+// idTokenClaims - claims токена провайдера, из которых берется логин пользователя
+type idTokenClaims struct {
+	PreferredUsername string `json:"preferred_username"`
+	Email             string `json:"email"`
+	jwt.RegisteredClaims
+}
+
+// Accepts сообщает, выпущен ли токен этим провайдером. Подпись не проверяется:
+// метод только выбирает, кто будет проверять токен
+func (v *Verifier) Accepts(token string) bool {
+	var claims jwt.RegisteredClaims
+	if _, _, err := jwt.NewParser().ParseUnverified(token, &claims); err != nil {
+		return false
+	}
+	return claims.Issuer == v.config.Issuer
+}
+
+// Verify проверяет подпись, издателя, аудиторию и срок действия токена
+// и возвращает учетную запись пользователя у провайдера
+func (v *Verifier) Verify(ctx context.Context, token string) (*domain.ExternalIdentity, error) {
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(signingMethods),
+		jwt.WithIssuer(v.config.Issuer),
+		jwt.WithExpirationRequired(),
+	}
+	if v.config.Audience != "" {
+		options = append(options, jwt.WithAudience(v.config.Audience))
+	}
+
+	var claims idTokenClaims
+	_, err := jwt.ParseWithClaims(token, &claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return v.key(ctx, kid)
+	}, options...)
+	if err != nil {
+		return nil, fmt.Errorf("oidc: failed to verify token: %w", err)
+	}
+
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("oidc: token has no subject")
+	}
+
+	login := claims.PreferredUsername
+	if login == "" {
+		login = claims.Email
+	}
+	if login == "" {
+		login = claims.Subject
+	}
+
+	return &domain.ExternalIdentity{
+		Issuer:    claims.Issuer,
+		Subject:   claims.Subject,
+		Login:     login,
+		ExpiresAt: claims.ExpiresAt.Time,
+	}, nil
+}
+
+// key возвращает ключ подписи по kid. Неизвестный kid означает ротацию ключей
+// у провайдера, поэтому ключи перезагружаются, но не чаще MinRefreshInterval
+func (v *Verifier) key(ctx context.Context, kid string) (any, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if key, ok := v.lookup(kid); ok {
+		return key, nil
+	}
+
+	if v.keys != nil && time.Since(v.fetchedAt) < v.config.MinRefreshInterval {
+		return nil, fmt.Errorf("oidc: unknown key %q", kid)
+	}
+
+	keys, err := v.fetchKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	v.keys = keys
+	v.fetchedAt = time.Now()
+
+	if key, ok := v.lookup(kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("oidc: unknown key %q", kid)
+}
+
+// lookup ищет ключ в кеше. Токен без kid допустим, только если ключ у провайдера один
+func (v *Verifier) lookup(kid string) (any, bool) {
+	if kid == "" && len(v.keys) == 1 {
+		for _, key := range v.keys {
+			return key, true
+		}
+	}
+	key, ok := v.keys[kid]
+	return key, ok
+}
+
+// jsonWebKey - открытый ключ в формате JWK (RFC 7517)
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// fetchKeys загружает ключи подписи провайдера
+func (v *Verifier) fetchKeys(ctx context.Context) (map[string]any, error) {
+	if v.jwksURL == "" {
+		var discovery struct {
+			JWKSURI string `json:"jwks_uri"`
+		}
+		discoveryURL := strings.TrimSuffix(v.config.Issuer, "/") + "/.well-known/openid-configuration"
+		if err := v.getJSON(ctx, discoveryURL, &discovery); err != nil {
+			return nil, err
+		}
+		if discovery.JWKSURI == "" {
+			return nil, fmt.Errorf("oidc: discovery document has no jwks_uri")
+		}
+		v.jwksURL = discovery.JWKSURI
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := v.getJSON(ctx, v.jwksURL, &set); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]any, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			// Неподдерживаемый ключ не мешает использовать остальные
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("oidc: no usable signing keys at %s", v.jwksURL)
+	}
+
+	return keys, nil
+}
+
+// getJSON выполняет GET запрос и декодирует JSON ответ
+func (v *Verifier) getJSON(ctx context.Context, url string, dst any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("oidc: failed to create request to %s: %w", url, err)
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("oidc: failed to fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("oidc: unexpected status %d from %s", resp.StatusCode, url)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(dst); err != nil {
+		return fmt.Errorf("oidc: failed to decode response from %s: %w", url, err)
+	}
+	return nil
+}
+
+// publicKey преобразует JWK в открытый ключ RSA или ECDSA
+func (k jsonWebKey) publicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() {
+			return nil, fmt.Errorf("oidc: rsa exponent is too large")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("oidc: unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("oidc: unsupported key type %q", k.Kty)
+	}
+}
+
+// decodeBigInt декодирует число из base64url без выравнивания
+func decodeBigInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || len(data) == 0 {
+		return nil, fmt.Errorf("oidc: invalid key parameter")
+	}
+	return new(big.Int).SetBytes(data), nil
+}
@@ -0,0 +1,220 @@
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/avc/loyalty-system-diploma/internal/domain"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testProvider - провайдер удостоверений с discovery документом и JWKS
+type testProvider struct {
+	server     *httptest.Server
+	keys       atomic.Value // []map[string]string
+	jwksHits   atomic.Int32
+	rsaKey     *rsa.PrivateKey
+	ecKey      *ecdsa.PrivateKey
+	issuer     string
+	keysStatus int
+}
+
+func newTestProvider(t *testing.T) *testProvider {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	p := &testProvider{rsaKey: rsaKey, ecKey: ecKey, keysStatus: http.StatusOK}
+	p.keys.Store([]map[string]string{rsaJWK("rsa-1", &rsaKey.PublicKey), ecJWK("ec-1", &ecKey.PublicKey)})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{"jwks_uri": p.server.URL + "/keys"}) //nolint:errcheck
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		p.jwksHits.Add(1)
+		if p.keysStatus != http.StatusOK {
+			w.WriteHeader(p.keysStatus)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"keys": p.keys.Load()}) //nolint:errcheck
+	})
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+	p.issuer = p.server.URL
+
+	return p
+}
+
+func (p *testProvider) verifier() *Verifier {
+	return NewVerifier(Config{Issuer: p.issuer, Audience: "gophermart"}, p.server.Client())
+}
+
+func (p *testProvider) claims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":                p.issuer,
+		"sub":                "user-1",
+		"aud":                "gophermart",
+		"exp":                time.Now().Add(time.Hour).Unix(),
+		"preferred_username": "alice",
+	}
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, kid string, key any, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+func rsaJWK(kid string, key *rsa.PublicKey) map[string]string {
+	return map[string]string{
+		"kty": "RSA",
+		"kid": kid,
+		"use": "sig",
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+func ecJWK(kid string, key *ecdsa.PublicKey) map[string]string {
+	return map[string]string{
+		"kty": "EC",
+		"kid": kid,
+		"crv": "P-256",
+		"x":   base64.RawURLEncoding.EncodeToString(key.X.Bytes()), //nolint:staticcheck // координаты нужны для JWK
+		"y":   base64.RawURLEncoding.EncodeToString(key.Y.Bytes()), //nolint:staticcheck // координаты нужны для JWK
+	}
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	p := newTestProvider(t)
+	ctx := context.Background()
+
+	t.Run("RSA token", func(t *testing.T) {
+		claims := p.claims()
+		token := sign(t, jwt.SigningMethodRS256, "rsa-1", p.rsaKey, claims)
+
+		identity, err := p.verifier().Verify(ctx, token)
+		require.NoError(t, err)
+		expiresAt := time.Unix(claims["exp"].(int64), 0)
+		assert.Equal(t, &domain.ExternalIdentity{Issuer: p.issuer, Subject: "user-1", Login: "alice", ExpiresAt: expiresAt}, identity)
+	})
+
+	t.Run("EC token", func(t *testing.T) {
+		token := sign(t, jwt.SigningMethodES256, "ec-1", p.ecKey, p.claims())
+
+		identity, err := p.verifier().Verify(ctx, token)
+		require.NoError(t, err)
+		assert.Equal(t, "user-1", identity.Subject)
+	})
+
+	t.Run("Login falls back to email and subject", func(t *testing.T) {
+		claims := p.claims()
+		delete(claims, "preferred_username")
+		claims["email"] = "alice@example.com"
+		identity, err := p.verifier().Verify(ctx, sign(t, jwt.SigningMethodRS256, "rsa-1", p.rsaKey, claims))
+		require.NoError(t, err)
+		assert.Equal(t, "alice@example.com", identity.Login)
+
+		delete(claims, "email")
+		identity, err = p.verifier().Verify(ctx, sign(t, jwt.SigningMethodRS256, "rsa-1", p.rsaKey, claims))
+		require.NoError(t, err)
+		assert.Equal(t, "user-1", identity.Login)
+	})
+
+	rejected := map[string]func(jwt.MapClaims){
+		"wrong issuer":   func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" },
+		"wrong audience": func(c jwt.MapClaims) { c["aud"] = "other-service" },
+		"expired":        func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Minute).Unix() },
+		"no expiration":  func(c jwt.MapClaims) { delete(c, "exp") },
+		"no subject":     func(c jwt.MapClaims) { delete(c, "sub") },
+	}
+	for name, mutate := range rejected {
+		t.Run("Rejects "+name, func(t *testing.T) {
+			claims := p.claims()
+			mutate(claims)
+			_, err := p.verifier().Verify(ctx, sign(t, jwt.SigningMethodRS256, "rsa-1", p.rsaKey, claims))
+			assert.Error(t, err)
+		})
+	}
+
+	t.Run("Rejects token signed by another key", func(t *testing.T) {
+		otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+
+		_, err = p.verifier().Verify(ctx, sign(t, jwt.SigningMethodRS256, "rsa-1", otherKey, p.claims()))
+		assert.Error(t, err)
+	})
+
+	t.Run("Rejects HMAC token", func(t *testing.T) {
+		_, err := p.verifier().Verify(ctx, sign(t, jwt.SigningMethodHS256, "rsa-1", []byte("secret"), p.claims()))
+		assert.Error(t, err)
+	})
+}
+
+func TestVerifier_KeyRotation(t *testing.T) {
+	p := newTestProvider(t)
+	ctx := context.Background()
+	verifier := NewVerifier(Config{Issuer: p.issuer, MinRefreshInterval: time.Hour}, p.server.Client())
+
+	_, err := verifier.Verify(ctx, sign(t, jwt.SigningMethodRS256, "rsa-1", p.rsaKey, p.claims()))
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), p.jwksHits.Load())
+
+	// Ключи кешируются
+	_, err = verifier.Verify(ctx, sign(t, jwt.SigningMethodRS256, "rsa-1", p.rsaKey, p.claims()))
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), p.jwksHits.Load())
+
+	// Новый ключ провайдера не загружается раньше MinRefreshInterval
+	rotated, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	p.keys.Store([]map[string]string{rsaJWK("rsa-2", &rotated.PublicKey)})
+
+	_, err = verifier.Verify(ctx, sign(t, jwt.SigningMethodRS256, "rsa-2", rotated, p.claims()))
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), p.jwksHits.Load())
+
+	// После интервала неизвестный kid приводит к перезагрузке ключей
+	verifier.fetchedAt = time.Now().Add(-2 * time.Hour)
+	_, err = verifier.Verify(ctx, sign(t, jwt.SigningMethodRS256, "rsa-2", rotated, p.claims()))
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), p.jwksHits.Load())
+}
+
+func TestVerifier_KeysUnavailable(t *testing.T) {
+	p := newTestProvider(t)
+	p.keysStatus = http.StatusInternalServerError
+
+	_, err := p.verifier().Verify(context.Background(), sign(t, jwt.SigningMethodRS256, "rsa-1", p.rsaKey, p.claims()))
+	assert.Error(t, err)
+}
+
+func TestVerifier_Accepts(t *testing.T) {
+	p := newTestProvider(t)
+	verifier := p.verifier()
+
+	assert.True(t, verifier.Accepts(sign(t, jwt.SigningMethodRS256, "rsa-1", p.rsaKey, p.claims())))
+
+	local := sign(t, jwt.SigningMethodHS256, "", []byte("secret"), jwt.MapClaims{"user_id": 1})
+	assert.False(t, verifier.Accepts(local))
+	assert.False(t, verifier.Accepts("not-a-token"))
+}
@@ -0,0 +1,148 @@
+// Package ordernum выбирает способ проверки номера заказа по источнику номера
+// или его префиксу: номера некоторых партнеров не используют алгоритм Луна.
+package ordernum
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/avc/loyalty-system-diploma/internal/utils/luhn"
+)
+
+// Algorithm - способ проверки номера заказа
+type Algorithm string
+
+const (
+	AlgorithmLuhn   Algorithm = "luhn"   // Цифры с контрольной цифрой по алгоритму Луна
+	AlgorithmDigits Algorithm = "digits" // Только цифры
+	AlgorithmAlnum  Algorithm = "alnum"  // Латинские буквы и цифры
+)
+
+// validators - проверки номера по алгоритму
+var validators = map[Algorithm]func(string) bool{
+	AlgorithmLuhn:   luhn.Validate,
+	AlgorithmDigits: onlyOf(isDigit),
+	AlgorithmAlnum:  onlyOf(func(ch rune) bool { return isDigit(ch) || ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' }),
+}
+
+func isDigit(ch rune) bool {
+	return ch >= '0' && ch <= '9'
+}
+
+// onlyOf проверяет, что непустой номер состоит только из допустимых символов
+func onlyOf(allowed func(rune) bool) func(string) bool {
+	return func(number string) bool {
+		if number == "" {
+			return false
+		}
+		for _, ch := range number {
+			if !allowed(ch) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Rule назначает алгоритм номерам с префиксом Prefix или номерам из источника Source.
+// Задано ровно одно из полей
+type Rule struct {
+	Prefix    string
+	Source    string
+	Algorithm Algorithm
+}
+
+// String возвращает правило в формате конфигурации: prefix:<префикс>=<алгоритм> или source:<источник>=<алгоритм>
+func (r Rule) String() string {
+	if r.Source != "" {
+		return "source:" + r.Source + "=" + string(r.Algorithm)
+	}
+	return "prefix:" + r.Prefix + "=" + string(r.Algorithm)
+}
+
+// ParseRule разбирает правило в формате Rule.String
+func ParseRule(value string) (Rule, error) {
+	key, algorithm, ok := strings.Cut(value, "=")
+	kind, match, kindOK := strings.Cut(strings.TrimSpace(key), ":")
+	match = strings.TrimSpace(match)
+	if !ok || !kindOK || match == "" {
+		return Rule{}, fmt.Errorf("rule %q must be in prefix:<prefix>=<algorithm> or source:<source>=<algorithm> format", value)
+	}
+
+	rule := Rule{Algorithm: Algorithm(strings.TrimSpace(algorithm))}
+	if _, known := validators[rule.Algorithm]; !known {
+		return Rule{}, fmt.Errorf("unknown algorithm %q, expected luhn, digits or alnum", rule.Algorithm)
+	}
+	switch kind {
+	case "prefix":
+		rule.Prefix = match
+	case "source":
+		rule.Source = match
+	default:
+		return Rule{}, fmt.Errorf("unknown rule kind %q, expected prefix or source", kind)
+	}
+	return rule, nil
+}
+
+// Registry выбирает проверку номера по правилам. Правило источника важнее правила
+// префикса, из префиксов выбирается самый длинный. Номера без правила проверяются
+// алгоритмом Луна. nil Registry проверяет все номера алгоритмом Луна.
+type Registry struct {
+	sources  map[string]Algorithm
+	prefixes []Rule // По убыванию длины префикса
+}
+
+// NewRegistry создает реестр проверок. Повторное правило для того же префикса или источника - ошибка
+func NewRegistry(rules []Rule) (*Registry, error) {
+	r := &Registry{sources: make(map[string]Algorithm)}
+	seen := make(map[string]bool)
+	for _, rule := range rules {
+		if _, known := validators[rule.Algorithm]; !known {
+			return nil, fmt.Errorf("ordernum: unknown algorithm %q", rule.Algorithm)
+		}
+		if (rule.Prefix == "") == (rule.Source == "") {
+			return nil, fmt.Errorf("ordernum: rule must have either prefix or source")
+		}
+		key := "prefix:" + rule.Prefix
+		if rule.Source != "" {
+			key = "source:" + rule.Source
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("ordernum: duplicate rule for %s", key)
+		}
+		seen[key] = true
+
+		if rule.Source != "" {
+			r.sources[rule.Source] = rule.Algorithm
+			continue
+		}
+		r.prefixes = append(r.prefixes, rule)
+	}
+
+	sort.SliceStable(r.prefixes, func(i, j int) bool {
+		return len(r.prefixes[i].Prefix) > len(r.prefixes[j].Prefix)
+	})
+	return r, nil
+}
+
+// Algorithm возвращает алгоритм проверки номера из источника source (пусто - источник не указан)
+func (r *Registry) Algorithm(number, source string) Algorithm {
+	if r == nil {
+		return AlgorithmLuhn
+	}
+	if algorithm, ok := r.sources[source]; ok && source != "" {
+		return algorithm
+	}
+	for _, rule := range r.prefixes {
+		if strings.HasPrefix(number, rule.Prefix) {
+			return rule.Algorithm
+		}
+	}
+	return AlgorithmLuhn
+}
+
+// Validate проверяет номер заказа из источника source
+func (r *Registry) Validate(number, source string) bool {
+	return validators[r.Algorithm(number, source)](number)
+}
@@ -0,0 +1,75 @@
+package ordernum
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRule(t *testing.T) {
+	rule, err := ParseRule(" prefix:99 = digits ")
+	require.NoError(t, err)
+	assert.Equal(t, Rule{Prefix: "99", Algorithm: AlgorithmDigits}, rule)
+	assert.Equal(t, "prefix:99=digits", rule.String())
+
+	rule, err = ParseRule("source:cinema=alnum")
+	require.NoError(t, err)
+	assert.Equal(t, Rule{Source: "cinema", Algorithm: AlgorithmAlnum}, rule)
+	assert.Equal(t, "source:cinema=alnum", rule.String())
+
+	for _, value := range []string{"99=digits", "prefix:=digits", "prefix:99", "prefix:99=mod11", "store:1=luhn"} {
+		_, err := ParseRule(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestRegistry_Validate(t *testing.T) {
+	registry, err := NewRegistry([]Rule{
+		{Prefix: "99", Algorithm: AlgorithmDigits},
+		{Prefix: "991", Algorithm: AlgorithmLuhn},
+		{Prefix: "AB", Algorithm: AlgorithmAlnum},
+		{Source: "cinema", Algorithm: AlgorithmAlnum},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		number string
+		source string
+		want   bool
+	}{
+		{name: "default Luhn", number: "79927398713", want: true},
+		{name: "default Luhn fails", number: "79927398710", want: false},
+		{name: "prefix digits", number: "9912", want: false}, // Самый длинный префикс 991 - алгоритм Луна
+		{name: "prefix digits without checksum", number: "9901", want: true},
+		{name: "digits reject letters", number: "99A1", want: false},
+		{name: "alnum prefix", number: "AB12cd", want: true},
+		{name: "alnum rejects symbols", number: "AB-12", want: false},
+		{name: "source overrides prefix", number: "TICKET42", source: "cinema", want: true},
+		{name: "unknown source uses prefix", number: "9901", source: "shop", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, registry.Validate(tt.number, tt.source))
+		})
+	}
+}
+
+func TestRegistry_Nil(t *testing.T) {
+	var registry *Registry
+	assert.True(t, registry.Validate("79927398713", "cinema"))
+	assert.False(t, registry.Validate("ABC", ""))
+}
+
+func TestNewRegistry_Invalid(t *testing.T) {
+	_, err := NewRegistry([]Rule{{Prefix: "99", Algorithm: AlgorithmDigits}, {Prefix: "99", Algorithm: AlgorithmAlnum}})
+	assert.Error(t, err)
+
+	_, err = NewRegistry([]Rule{{Algorithm: AlgorithmDigits}})
+	assert.Error(t, err)
+
+	_, err = NewRegistry([]Rule{{Source: "cinema", Algorithm: "mod11"}})
+	assert.Error(t, err)
+}
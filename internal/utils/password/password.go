@@ -1,8 +1,10 @@
 package password
 
 import (
+	"crypto/rand"
 	"errors"
 	"fmt"
+	"math/big"
 
 	"golang.org/x/crypto/bcrypt"
 )
@@ -75,3 +77,25 @@ func CheckPassword(hash, password string) error {
 	hasher := NewBCryptHasher(DefaultCost)
 	return hasher.Check(hash, password)
 }
+
+// generateAlphabet не содержит похожих символов (0/O, 1/l/I), чтобы пароль легко переписать
+const generateAlphabet = "abcdefghijkmnpqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// Generate создает случайный пароль заданной длины, например временный пароль при импорте
+func Generate(length int) (string, error) {
+	if length <= 0 {
+		return "", fmt.Errorf("password length must be positive")
+	}
+
+	alphabetSize := big.NewInt(int64(len(generateAlphabet)))
+	buf := make([]byte, length)
+	for i := range buf {
+		n, err := rand.Int(rand.Reader, alphabetSize)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate password: %w", err)
+		}
+		buf[i] = generateAlphabet[n.Int64()]
+	}
+
+	return string(buf), nil
+}
@@ -207,6 +207,22 @@ func TestCheckPassword(t *testing.T) {
 	})
 }
 
+func TestGenerate(t *testing.T) {
+	first, err := Generate(16)
+	require.NoError(t, err)
+	assert.Len(t, first, 16)
+	for _, c := range first {
+		assert.Contains(t, generateAlphabet, string(c))
+	}
+
+	second, err := Generate(16)
+	require.NoError(t, err)
+	assert.NotEqual(t, first, second)
+
+	_, err = Generate(0)
+	assert.Error(t, err)
+}
+
 func BenchmarkBCryptHasher_Hash(b *testing.B) {
 	hasher := NewBCryptHasher(testCost)
 	password := "testpassword"
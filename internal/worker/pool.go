@@ -3,43 +3,114 @@ package worker
 import (
 	"context"
 	"errors"
+	"fmt"
+	"maps"
+	"runtime/debug"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/avc/loyalty-system-diploma/internal/domain"
-	"github.com/avc/loyalty-system-diploma/internal/repository/postgres"
+	"github.com/avc/loyalty-system-diploma/internal/logctx"
+	"github.com/avc/loyalty-system-diploma/internal/metrics"
 	"github.com/avc/loyalty-system-diploma/internal/service"
+	"github.com/avc/loyalty-system-diploma/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	"go.uber.org/zap"
 )
 
 // PoolConfig содержит конфигурацию worker pool
 type PoolConfig struct {
-	Workers      int           // Количество воркеров
-	QueueSize    int           // Размер очереди заказов
-	ScanInterval time.Duration // Интервал сканирования pending заказов
+	Workers         int                   // Количество воркеров
+	QueueSize       int                   // Размер очереди заказов
+	ScanInterval    time.Duration         // Интервал сканирования, пока есть pending заказы
+	MaxScanInterval time.Duration         // Предел, до которого интервал растет, пока pending заказов нет
+	RestartDelay    time.Duration         // Пауза перед перезапуском горутины после паники
+	Rounding        domain.RoundingPolicy // Округление начислений перед записью
+	// Корректировать начисление, если система начислений вернула другую сумму по уже зачисленному заказу
+	AccrualCorrections bool
+	// Число необработанных заказов, начиная с которого пул считается перегруженным (0 - не проверять)
+	BacklogThreshold int
+	// Интервал и предельный возраст опроса по статусам. Статус без политики опрашивается
+	// при каждом сканировании, политики финальных статусов игнорируются
+	ScanPolicies []domain.OrderScanPolicy
 }
 
+// defaultRestartDelay используется, если RestartDelay не задан
+const defaultRestartDelay = time.Second
+
+// defaultOrderProcessingTime используется в оценке ожидания, пока ни один заказ не обработан
+const defaultOrderProcessingTime = time.Second
+
 // DefaultPoolConfig возвращает конфигурацию по умолчанию
 func DefaultPoolConfig() PoolConfig {
 	return PoolConfig{
-		Workers:      3,
-		QueueSize:    100,
-		ScanInterval: 10 * time.Second,
+		Workers:         3,
+		QueueSize:       100,
+		ScanInterval:    10 * time.Second,
+		MaxScanInterval: time.Minute,
+		RestartDelay:    defaultRestartDelay,
+		Rounding:        domain.DefaultRoundingPolicy(),
+		ScanPolicies:    domain.DefaultOrderScanPolicies(),
 	}
 }
 
+// DBAvailability сообщает о доступности БД, чтобы пул мог встать на паузу
+// на время ее недоступности вместо бесконечных ошибок в логе
+type DBAvailability interface {
+	Available() bool
+	WaitAvailable(ctx context.Context) bool
+}
+
+// Leadership сообщает, является ли реплика лидером. Сканер работает только на лидере,
+// чтобы несколько реплик не ставили в очередь одни и те же заказы.
+type Leadership interface {
+	IsLeader() bool
+}
+
 // Pool представляет пул воркеров для обработки заказов
 type Pool struct {
-	config          PoolConfig
-	queue           chan string
-	retryQueue      chan retryItem
-	orderRepo       service.OrderRepository
-	transactionRepo service.TransactionRepository
-	accrualClient   service.AccrualClient
-	logger          *zap.Logger
-	wg              sync.WaitGroup
-	cooldownUntil   int64
+	config        PoolConfig
+	queue         chan string
+	retryQueue    chan retryItem
+	scanNow       chan struct{}
+	orderRepo     service.OrderRepository
+	accrualClient service.AccrualClient
+	db            DBAvailability
+	leadership    Leadership
+	metrics       *metrics.Metrics
+	logger        *zap.Logger
+	wg            sync.WaitGroup
+	cooldownUntil int64
+
+	// Время последнего успешного ответа системы начислений (UnixNano)
+	lastAccrualSuccess atomic.Int64
+
+	// Заказы без финального статуса по последнему сканированию и загруженные после него
+	pendingOrders atomic.Int64
+
+	// Следующее внеочередное сканирование охватывает все статусы, а не только NEW
+	fullScan atomic.Bool
+
+	// Состояние сканирования по статусам, используется только сканером
+	scans map[domain.OrderStatus]*statusScan
+
+	// Скользящее среднее времени обработки одного заказа (наносекунды)
+	avgProcessingTime atomic.Int64
+
+	// Приостановка обработки администратором: resumed закрывается при возобновлении,
+	// nil - обработка идет
+	pauseMu  sync.Mutex
+	resumed  chan struct{}
+	pausedAt time.Time
+}
+
+// statusScan - время последнего сканирования статуса и число найденных заказов
+type statusScan struct {
+	last    time.Time
+	pending int
 }
 
 // retryItem представляет заказ для повторной обработки
@@ -52,53 +123,259 @@ type retryItem struct {
 func NewPool(
 	config PoolConfig,
 	orderRepo service.OrderRepository,
-	transactionRepo service.TransactionRepository,
 	accrualClient service.AccrualClient,
+	db DBAvailability,
+	leadership Leadership,
+	m *metrics.Metrics,
 	logger *zap.Logger,
 ) *Pool {
+	if config.RestartDelay <= 0 {
+		config.RestartDelay = defaultRestartDelay
+	}
+	// Без максимума интервал сканирования остается фиксированным
+	if config.MaxScanInterval < config.ScanInterval {
+		config.MaxScanInterval = config.ScanInterval
+	}
+	if !config.Rounding.IsValid() {
+		config.Rounding = domain.DefaultRoundingPolicy()
+	}
+	config.ScanPolicies = normalizeScanPolicies(config.ScanPolicies)
+	scans := make(map[domain.OrderStatus]*statusScan, len(config.ScanPolicies))
+	for _, policy := range config.ScanPolicies {
+		scans[policy.Status] = &statusScan{}
+	}
+
 	return &Pool{
-		config:          config,
-		queue:           make(chan string, config.QueueSize),
-		retryQueue:      make(chan retryItem, config.QueueSize),
-		orderRepo:       orderRepo,
-		transactionRepo: transactionRepo,
-		accrualClient:   accrualClient,
-		logger:          logger,
+		config:        config,
+		queue:         make(chan string, config.QueueSize),
+		retryQueue:    make(chan retryItem, config.QueueSize),
+		scanNow:       make(chan struct{}, 1),
+		orderRepo:     orderRepo,
+		accrualClient: accrualClient,
+		db:            db,
+		leadership:    leadership,
+		metrics:       m,
+		logger:        logger,
+		scans:         scans,
+	}
+}
+
+// normalizeScanPolicies дополняет политики по умолчанию заданными: политика статуса
+// заменяет политику по умолчанию, политики финальных статусов отбрасываются
+func normalizeScanPolicies(policies []domain.OrderScanPolicy) []domain.OrderScanPolicy {
+	normalized := domain.DefaultOrderScanPolicies()
+	for _, policy := range policies {
+		if policy.Status.IsFinal() {
+			continue
+		}
+		policy.Interval = max(policy.Interval, 0)
+		policy.MaxAge = max(policy.MaxAge, 0)
+		i := slices.IndexFunc(normalized, func(p domain.OrderScanPolicy) bool { return p.Status == policy.Status })
+		if i < 0 {
+			normalized = append(normalized, policy)
+			continue
+		}
+		normalized[i] = policy
 	}
+	return normalized
 }
 
 // Start запускает worker pool
 func (p *Pool) Start(ctx context.Context) {
 	// Запускаем воркеры
 	for i := 0; i < p.config.Workers; i++ {
+		id := i
 		p.wg.Add(1)
-		go p.worker(ctx, i)
+		go p.supervise(ctx, "worker", func(ctx context.Context) { p.worker(ctx, id) })
 	}
 
 	// Запускаем сканер pending заказов
 	p.wg.Add(1)
-	go p.scanner(ctx)
+	go p.supervise(ctx, "scanner", p.scanner)
 
 	// Запускаем обработчик retry очереди
 	p.wg.Add(1)
-	go p.retryProcessor(ctx)
+	go p.supervise(ctx, "retry_processor", p.retryProcessor)
 }
 
-// Stop останавливает worker pool
-func (p *Pool) Stop() {
+// Stop останавливает worker pool и возвращает число заказов, оставшихся в очередях.
+// Такие заказы остаются в БД без финального статуса, их найдет сканер после перезапуска.
+func (p *Pool) Stop() int {
 	close(p.queue)
 	close(p.retryQueue)
 	p.wg.Wait()
+
+	queued, retries := drain(p.queue), drain(p.retryQueue)
+	p.metrics.ObserveAbandonedOrders(queued, retries)
+	if queued+retries > 0 {
+		p.logger.Warn("orders abandoned in worker queues",
+			zap.Int("queued", queued),
+			zap.Int("retries", retries),
+		)
+	}
+	return queued + retries
+}
+
+// drain вычитывает закрытый канал и возвращает число оставшихся в нем элементов
+func drain[T any](ch <-chan T) int {
+	n := 0
+	for range ch {
+		n++
+	}
+	return n
+}
+
+// NotifyNewOrder сообщает о новом заказе, запуская внеочередное сканирование заказов NEW.
+// Остальные статусы сканируются по своим интервалам
+func (p *Pool) NotifyNewOrder() {
+	pending := p.pendingOrders.Add(1)
+	p.metrics.ObserveWorkerBacklog(len(p.queue), int(pending))
+	p.wake()
+}
+
+// Backlog возвращает число заказов, ожидающих обработки.
+// Сканер работает только на лидере, поэтому на остальных репликах
+// учитываются лишь заказы, загруженные через эту реплику.
+func (p *Pool) Backlog() int {
+	return int(p.pendingOrders.Load())
+}
+
+// Overloaded сообщает, что обработка заказов отстает: необработанных заказов
+// не меньше BacklogThreshold или очередь воркеров заполнена.
+func (p *Pool) Overloaded() bool {
+	if p.config.BacklogThreshold <= 0 {
+		return false
+	}
+	return p.Backlog() >= p.config.BacklogThreshold || len(p.queue) == cap(p.queue)
+}
+
+// EstimateProcessing оценивает, через сколько будет обработан только что загруженный заказ:
+// необработанные заказы делятся между воркерами, каждый занимает в среднем столько,
+// сколько занимали последние обработанные, плюс оставшаяся пауза после 429 системы начислений.
+func (p *Pool) EstimateProcessing() domain.ProcessingEstimate {
+	position := max(p.Backlog(), 1)
+
+	perOrder := time.Duration(p.avgProcessingTime.Load())
+	if perOrder <= 0 {
+		perOrder = defaultOrderProcessingTime
+	}
+	workers := max(p.config.Workers, 1)
+	rounds := (position + workers - 1) / workers
+
+	wait := time.Duration(rounds) * perOrder
+	if until := atomic.LoadInt64(&p.cooldownUntil); until != 0 {
+		wait += max(time.Until(time.Unix(0, until)), 0)
+	}
+
+	return domain.ProcessingEstimate{QueuePosition: position, Wait: wait}
+}
+
+// observeProcessingTime учитывает время обработки заказа в скользящем среднем
+func (p *Pool) observeProcessingTime(d time.Duration) {
+	for {
+		current := p.avgProcessingTime.Load()
+		next := int64(d)
+		if current != 0 {
+			// Вес нового значения 1/5: оценка следит за изменениями, но не скачет от одного медленного заказа
+			next = current + (int64(d)-current)/5
+		}
+		if p.avgProcessingTime.CompareAndSwap(current, next) {
+			return
+		}
+	}
+}
+
+// ScanNow запускает внеочередное сканирование всех статусов без учета их интервалов
+// и сбрасывает интервал до минимального.
+// Не блокируется: несколько вызовов подряд схлопываются в одно сканирование.
+func (p *Pool) ScanNow() {
+	p.fullScan.Store(true)
+	p.wake()
+}
+
+func (p *Pool) wake() {
+	select {
+	case p.scanNow <- struct{}{}:
+	default:
+	}
+}
+
+// Pause приостанавливает обработку заказов, например на время обслуживания системы начислений:
+// воркеры доделывают уже взятые заказы и ждут Resume, сканер не ставит заказы в очередь.
+// Действует только на эту реплику. Возвращает false, если обработка уже приостановлена
+func (p *Pool) Pause() bool {
+	p.pauseMu.Lock()
+	defer p.pauseMu.Unlock()
+
+	if p.resumed != nil {
+		return false
+	}
+	p.resumed = make(chan struct{})
+	p.pausedAt = time.Now()
+	p.metrics.ObserveWorkerPaused(true)
+	p.logger.Warn("order processing paused")
+	return true
+}
+
+// Resume возобновляет обработку заказов и запускает сканирование всех статусов.
+// Возвращает false, если обработка не была приостановлена
+func (p *Pool) Resume() bool {
+	p.pauseMu.Lock()
+	defer p.pauseMu.Unlock()
+
+	if p.resumed == nil {
+		return false
+	}
+	close(p.resumed)
+	p.logger.Info("order processing resumed", zap.Duration("paused_for", time.Since(p.pausedAt)))
+	p.resumed, p.pausedAt = nil, time.Time{}
+	p.metrics.ObserveWorkerPaused(false)
+	p.ScanNow()
+	return true
+}
+
+// PausedSince возвращает время приостановки обработки; false, если обработка идет
+func (p *Pool) PausedSince() (time.Time, bool) {
+	p.pauseMu.Lock()
+	defer p.pauseMu.Unlock()
+	return p.pausedAt, p.resumed != nil
+}
+
+// waitForResume ждет возобновления обработки, если она приостановлена.
+// Возвращает false, если контекст отменен
+func (p *Pool) waitForResume(ctx context.Context, id int) bool {
+	p.pauseMu.Lock()
+	resumed := p.resumed
+	p.pauseMu.Unlock()
+	if resumed == nil {
+		return true
+	}
+
+	p.logger.Debug("order processing paused, worker waiting", zap.Int("worker_id", id))
+	select {
+	case <-ctx.Done():
+		return false
+	case <-resumed:
+		return true
+	}
+}
+
+// LastAccrualSuccess возвращает время последнего успешного запроса к системе начислений.
+// false, если успешных запросов еще не было.
+func (p *Pool) LastAccrualSuccess() (time.Time, bool) {
+	last := p.lastAccrualSuccess.Load()
+	if last == 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(0, last), true
 }
 
 // worker обрабатывает заказы из очереди
 func (p *Pool) worker(ctx context.Context, id int) {
-	defer p.wg.Done()
-
 	p.logger.Info("worker started", zap.Int("worker_id", id))
 
 	for {
-		if !p.waitForCooldown(ctx) {
+		if !p.waitForCooldown(ctx) || !p.waitForDB(ctx, id) || !p.waitForResume(ctx, id) {
 			p.logger.Info("worker stopping", zap.Int("worker_id", id))
 			return
 		}
@@ -110,36 +387,130 @@ func (p *Pool) worker(ctx context.Context, id int) {
 			if !ok {
 				return
 			}
-			p.processOrder(ctx, orderNumber)
+			// Заказ, взятый из очереди уже после приостановки, ждет возобновления.
+			// При остановке сервиса он остается в БД без финального статуса
+			if !p.waitForResume(ctx, id) {
+				return
+			}
+			p.processOrderSafe(ctx, id, orderNumber)
 		}
 	}
 }
 
-// scanner периодически сканирует pending заказы
-func (p *Pool) scanner(ctx context.Context) {
+// supervise выполняет run и перезапускает ее после паники с паузой RestartDelay.
+// Нормальный выход из run (остановка пула) завершает горутину.
+func (p *Pool) supervise(ctx context.Context, component string, run func(ctx context.Context)) {
 	defer p.wg.Done()
 
-	ticker := time.NewTicker(p.config.ScanInterval)
-	defer ticker.Stop()
+	for {
+		if !p.runRecovered(component, func() { run(ctx) }) {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(p.config.RestartDelay):
+			p.logger.Info("restarting after panic", zap.String("component", component))
+		}
+	}
+}
+
+// processOrderSafe изолирует панику при обработке одного заказа, чтобы воркер
+// продолжил брать заказы из очереди. Заказ останется pending и будет найден сканером.
+func (p *Pool) processOrderSafe(ctx context.Context, id int, orderNumber string) {
+	p.runRecovered("worker", func() { p.processOrder(ctx, orderNumber) },
+		zap.Int("worker_id", id),
+		zap.String("order", orderNumber),
+	)
+}
+
+// runRecovered выполняет fn, перехватывая панику. Возвращает true, если она была.
+func (p *Pool) runRecovered(component string, fn func(), fields ...zap.Field) (panicked bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			panicked = true
+			p.metrics.ObserveWorkerPanic(component)
+			p.logger.Error("recovered from panic",
+				append(fields,
+					zap.String("component", component),
+					zap.String("panic", fmt.Sprint(r)),
+					zap.ByteString("stack", debug.Stack()),
+				)...,
+			)
+		}
+	}()
+
+	fn()
+	return false
+}
+
+// scanner сканирует pending заказы с адаптивным интервалом: пока заказы есть,
+// раз в ScanInterval, в простое интервал удваивается до MaxScanInterval.
+// Статус с интервалом в политике сканируется не чаще этого интервала.
+// Новый заказ (NotifyNewOrder) запускает сканирование заказов NEW сразу.
+func (p *Pool) scanner(ctx context.Context) {
+	interval := p.config.ScanInterval
 
 	// Сканируем сразу при старте
-	p.scanPendingOrders(ctx)
+	timer := time.NewTimer(0)
+	defer timer.Stop()
 
 	for {
+		var force []domain.OrderStatus
 		select {
 		case <-ctx.Done():
 			p.logger.Info("scanner stopping")
 			return
-		case <-ticker.C:
-			p.scanPendingOrders(ctx)
+		case <-p.scanNow:
+			interval = p.config.ScanInterval
+			force = []domain.OrderStatus{domain.OrderStatusNew}
+			if p.fullScan.Swap(false) {
+				force = slices.Collect(maps.Keys(p.scans))
+			}
+		case <-timer.C:
+		}
+
+		if p.db != nil && !p.db.Available() {
+			p.logger.Debug("database unavailable, scan skipped")
+			timer.Reset(interval)
+			continue
+		}
+
+		if p.leadership != nil && !p.leadership.IsLeader() {
+			p.logger.Debug("not a leader, scan skipped")
+			timer.Reset(interval)
+			continue
+		}
+
+		// После возобновления Resume запускает сканирование сразу
+		if _, paused := p.PausedSince(); paused {
+			p.logger.Debug("order processing paused, scan skipped")
+			timer.Reset(interval)
+			continue
 		}
+
+		pending := p.scanPendingOrders(ctx, force...)
+		interval = p.nextScanInterval(interval, pending)
+		timer.Reset(interval)
 	}
 }
 
+// nextScanInterval возвращает интервал до следующего сканирования
+func (p *Pool) nextScanInterval(current time.Duration, pending int) time.Duration {
+	if pending > 0 {
+		return p.config.ScanInterval
+	}
+
+	next := min(current*2, p.config.MaxScanInterval)
+	if next != current {
+		p.logger.Debug("no pending orders, scan interval increased", zap.Duration("interval", next))
+	}
+	return next
+}
+
 // retryProcessor обрабатывает заказы для повторной попытки
 func (p *Pool) retryProcessor(ctx context.Context) {
-	defer p.wg.Done()
-
 	for {
 		select {
 		case <-ctx.Done():
@@ -176,25 +547,98 @@ func (p *Pool) retryProcessor(ctx context.Context) {
 	}
 }
 
-// scanPendingOrders сканирует и отправляет pending заказы в очередь
-func (p *Pool) scanPendingOrders(ctx context.Context) {
-	orders, err := p.orderRepo.GetPendingOrders(ctx)
+// scanPendingOrders сканирует статусы, интервал которых истек, и статусы force,
+// и отправляет найденные заказы в очередь по очереди от каждого пользователя.
+// Возвращает количество pending заказов по всем статусам, для несканированных статусов -
+// по их последнему сканированию; при ошибке считает, что заказы есть,
+// чтобы не увеличивать интервал сканирования.
+func (p *Pool) scanPendingOrders(ctx context.Context, force ...domain.OrderStatus) int {
+	now := time.Now()
+	var due []domain.OrderScanPolicy
+	for _, policy := range p.config.ScanPolicies {
+		scan := p.scans[policy.Status]
+		if scan.last.IsZero() || now.Sub(scan.last) >= policy.Interval || slices.Contains(force, policy.Status) {
+			due = append(due, policy)
+		}
+	}
+	if len(due) == 0 {
+		return p.scannedPending()
+	}
+
+	orders, err := p.orderRepo.GetPendingOrders(ctx, due)
 	if err != nil {
 		p.logger.Error("failed to get pending orders", zap.Error(err))
-		return
+		return 1
 	}
 
+	for _, policy := range due {
+		p.scans[policy.Status].last, p.scans[policy.Status].pending = now, 0
+	}
 	for _, order := range orders {
+		if scan, ok := p.scans[order.Status]; ok {
+			scan.pending++
+		}
+	}
+	pending := p.scannedPending()
+	p.pendingOrders.Store(int64(pending))
+	defer func() { p.metrics.ObserveWorkerBacklog(len(p.queue), pending) }()
+
+	// Пользователь с тысячами заказов не должен занимать всю очередь:
+	// остальные заказы, не поместившиеся в нее, ждут следующего сканирования
+	for _, order := range interleaveByUser(orders) {
 		select {
 		case p.queue <- order.Number:
 			// Успешно добавлено в очередь
 		case <-ctx.Done():
-			return
+			return pending
 		default:
 			// Очередь заполнена, пропускаем
 			p.logger.Warn("queue is full, skipping order", zap.String("order", order.Number))
 		}
 	}
+
+	return pending
+}
+
+// interleaveByUser переставляет заказы по кругу между пользователями: сначала первый заказ
+// каждого пользователя, затем второй и так далее. Пользователи идут в порядке их самого
+// раннего заказа, порядок заказов одного пользователя сохраняется
+func interleaveByUser(orders []*domain.Order) []*domain.Order {
+	var users []int64
+	byUser := make(map[int64][]*domain.Order)
+	for _, order := range orders {
+		if _, ok := byUser[order.UserID]; !ok {
+			users = append(users, order.UserID)
+		}
+		byUser[order.UserID] = append(byUser[order.UserID], order)
+	}
+	if len(users) <= 1 {
+		return orders
+	}
+
+	interleaved := make([]*domain.Order, 0, len(orders))
+	for round := 0; len(users) > 0; round++ {
+		// Пользователи, у которых заказы закончились, выбывают из следующих кругов
+		active := users[:0]
+		for _, userID := range users {
+			userOrders := byUser[userID]
+			interleaved = append(interleaved, userOrders[round])
+			if round+1 < len(userOrders) {
+				active = append(active, userID)
+			}
+		}
+		users = active
+	}
+	return interleaved
+}
+
+// scannedPending возвращает число pending заказов по последним сканированиям статусов
+func (p *Pool) scannedPending() int {
+	pending := 0
+	for _, scan := range p.scans {
+		pending += scan.pending
+	}
+	return pending
 }
 
 func (p *Pool) setCooldown(until time.Time) {
@@ -241,13 +685,46 @@ func (p *Pool) waitForCooldown(ctx context.Context) bool {
 	}
 }
 
+// waitForDB приостанавливает воркер, пока БД недоступна.
+// Возвращает false, если контекст отменен во время ожидания.
+func (p *Pool) waitForDB(ctx context.Context, id int) bool {
+	if p.db == nil || p.db.Available() {
+		return true
+	}
+
+	p.logger.Warn("database unavailable, worker paused", zap.Int("worker_id", id))
+	if !p.db.WaitAvailable(ctx) {
+		return false
+	}
+	p.logger.Info("database available, worker resumed", zap.Int("worker_id", id))
+	return true
+}
+
 // processOrder обрабатывает один заказ
 func (p *Pool) processOrder(ctx context.Context, orderNumber string) {
 	p.logger.Debug("processing order", zap.String("order", orderNumber))
 
+	// Репозитории логируют через контекстный логгер с номером заказа
+	ctx = logctx.With(ctx, p.logger.With(zap.String("order", orderNumber)))
+
 	if !p.waitForCooldown(ctx) {
 		return
 	}
+	start := time.Now()
+	// Каждая обработка - корневой спан, запросы к accrual и БД становятся его потомками
+	ctx, span := tracing.Start(ctx, "worker.process_order", attribute.String("order.number", orderNumber))
+	// Результат для метрики и спана; panic остается, если обработка прервана паникой
+	result := "panic"
+	defer func() {
+		p.observeProcessingTime(time.Since(start))
+		p.metrics.ObserveOrderProcessed(result, time.Since(start))
+		span.SetAttributes(attribute.String("worker.result", result))
+		switch result {
+		case "panic", "invalid_response", "accrual_error", "apply_error":
+			span.SetStatus(codes.Error, result)
+		}
+		span.End()
+	}()
 
 	// Получаем информацию от accrual системы
 	accrualResp, err := p.accrualClient.GetOrderAccrual(ctx, orderNumber)
@@ -255,6 +732,7 @@ func (p *Pool) processOrder(ctx context.Context, orderNumber string) {
 		// Обработка rate limiting - неблокирующий retry
 		var rateLimitErr *service.RateLimitError
 		if errors.As(err, &rateLimitErr) {
+			result = "rate_limited"
 			retryAt := time.Now().Add(rateLimitErr.RetryAfter)
 			p.setCooldown(retryAt)
 			p.logger.Warn("rate limit exceeded, scheduling retry",
@@ -275,64 +753,107 @@ func (p *Pool) processOrder(ctx context.Context, orderNumber string) {
 			return
 		}
 
+		if errors.Is(err, domain.ErrInvalidAccrualResponse) {
+			result = "invalid_response"
+			p.logger.Error("rejected malformed accrual response",
+				zap.String("order", orderNumber),
+				zap.Error(err),
+			)
+			return
+		}
+
+		result = "accrual_error"
 		p.logger.Error("failed to get accrual",
 			zap.String("order", orderNumber),
 			zap.Error(err),
 		)
 		return
 	}
-
-	// Если заказ не найден в системе начислений, обновляем статус на PROCESSING
-	if accrualResp == nil {
-		if err := p.orderRepo.UpdateOrderStatus(ctx, orderNumber, domain.OrderStatusProcessing, nil); err != nil {
-			p.logger.Error("failed to update order status to PROCESSING",
-				zap.String("order", orderNumber),
-				zap.Error(err),
-			)
+	p.lastAccrualSuccess.Store(time.Now().UnixNano())
+
+	// Заказ, не найденный в системе начислений, переходит в PROCESSING
+	status := domain.OrderStatusProcessing
+	var accrual *domain.Accrual
+	if accrualResp != nil {
+		status = accrualResp.Status.OrderStatus()
+		// Система начислений может вернуть сумму точнее копейки, округляем до записи
+		if status.IsFinal() && accrualResp.Accrual != nil {
+			accrual = p.config.Rounding.RoundAccrual(*accrualResp.Accrual)
 		}
-		return
 	}
 
-	// Обновляем статус заказа
-	if err := p.orderRepo.UpdateOrderStatus(ctx, orderNumber, accrualResp.Status, accrualResp.Accrual); err != nil {
-		p.logger.Error("failed to update order status",
+	// Статус, начисление и событие заказа применяются под блокировкой строки заказа,
+	// поэтому параллельная обработка того же заказа не начислит баллы дважды
+	if err := p.orderRepo.ApplyAccrual(ctx, orderNumber, status, accrual); err != nil {
+		result = "apply_error"
+		// Заказ уже был обработан; сумма могла измениться, если система начислений его пересчитала
+		if errors.Is(err, domain.ErrDuplicateAccrual) {
+			result = "already_final"
+			p.correctAccrual(ctx, orderNumber, accrual)
+			return
+		}
+		// Другой воркер уже завершил обработку заказа
+		if errors.Is(err, domain.ErrOrderAlreadyFinal) {
+			result = "already_final"
+			p.logger.Debug("order already finalized", zap.String("order", orderNumber), zap.Error(err))
+			return
+		}
+		p.logger.Error("failed to apply accrual",
 			zap.String("order", orderNumber),
+			zap.String("status", string(status)),
 			zap.Error(err),
 		)
 		return
 	}
 
-	// Если есть начисление и статус PROCESSED, создаем транзакцию
-	if accrualResp.Status == domain.OrderStatusProcessed && accrualResp.Accrual != nil && *accrualResp.Accrual > 0 {
-		// Получаем информацию о заказе для user_id
-		order, err := p.orderRepo.GetOrderByNumber(ctx, orderNumber)
-		if err != nil {
-			p.logger.Error("failed to get order info",
-				zap.String("order", orderNumber),
-				zap.Error(err),
-			)
-			return
-		}
+	if !status.IsFinal() {
+		result = "pending"
+		return
+	}
+	result = "final"
 
-		// Создаем транзакцию начисления (с защитой от дублирования через БД constraint)
-		if err := p.transactionRepo.CreateTransaction(ctx, order.UserID, orderNumber, *accrualResp.Accrual, domain.TransactionTypeAccrual); err != nil {
-			// Игнорируем ошибку дубликата - заказ уже был обработан
-			if errors.Is(err, postgres.ErrDuplicateAccrual) {
-				p.logger.Debug("accrual already exists for order",
-					zap.String("order", orderNumber))
-				return
-			}
-			p.logger.Error("failed to create accrual transaction",
-				zap.String("order", orderNumber),
-				zap.Float64("accrual", *accrualResp.Accrual),
-				zap.Error(err),
-			)
-			return
-		}
+	fields := []zap.Field{
+		zap.String("order", orderNumber),
+		zap.String("status", string(status)),
+	}
+	if accrual != nil {
+		fields = append(fields,
+			zap.Float64("accrual", accrual.Amount),
+			zap.Float64("accrual_raw", accrual.Raw),
+			zap.Stringer("rounding_policy", accrual.Policy),
+		)
+	}
+	p.logger.Info("order processed successfully", fields...)
+}
+
+// correctAccrual записывает корректировку, если сумма начисления по уже зачисленному
+// заказу отличается от зачисленной. При отключенных корректировках ничего не делает.
+func (p *Pool) correctAccrual(ctx context.Context, orderNumber string, accrual *domain.Accrual) {
+	if !p.config.AccrualCorrections {
+		p.logger.Debug("accrual already exists for order",
+			zap.String("order", orderNumber))
+		return
+	}
 
-		p.logger.Info("order processed successfully",
+	correction, err := p.orderRepo.CorrectAccrual(ctx, orderNumber, accrual, 0)
+	if errors.Is(err, domain.ErrAccrualUnchanged) {
+		p.logger.Debug("accrual already exists for order",
+			zap.String("order", orderNumber))
+		return
+	}
+	if err != nil {
+		p.logger.Error("failed to correct accrual",
 			zap.String("order", orderNumber),
-			zap.Float64("accrual", *accrualResp.Accrual),
+			zap.Error(err),
 		)
+		return
 	}
+
+	p.logger.Warn("accrual corrected after recalculation by accrual system",
+		zap.String("order", orderNumber),
+		zap.Int64("user_id", correction.UserID),
+		zap.Float64("previous_accrual", correction.PreviousAmount),
+		zap.Float64("accrual", correction.NewAmount),
+		zap.Float64("adjustment", correction.Delta),
+	)
 }
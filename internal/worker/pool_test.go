@@ -2,21 +2,24 @@ package worker
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/avc/loyalty-system-diploma/internal/domain"
 	domainmocks "github.com/avc/loyalty-system-diploma/internal/domain/mocks"
-	"github.com/avc/loyalty-system-diploma/internal/repository/postgres"
+	"github.com/avc/loyalty-system-diploma/internal/metrics"
 	"github.com/avc/loyalty-system-diploma/internal/service"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"go.uber.org/zap"
 )
 
-func newTestPool(t *testing.T) (*Pool, *domainmocks.OrderRepositoryMock, *domainmocks.TransactionRepositoryMock, *domainmocks.AccrualClientMock) {
+func newTestPool(t *testing.T) (*Pool, *domainmocks.OrderRepositoryMock, *domainmocks.AccrualClientMock) {
 	mockOrderRepo := domainmocks.NewOrderRepositoryMock(t)
-	mockTxRepo := domainmocks.NewTransactionRepositoryMock(t)
 	mockAccrualClient := domainmocks.NewAccrualClientMock(t)
 	logger, _ := zap.NewDevelopment()
 
@@ -25,79 +28,124 @@ func newTestPool(t *testing.T) (*Pool, *domainmocks.OrderRepositoryMock, *domain
 		QueueSize:    10,
 		ScanInterval: time.Second,
 	}
-	pool := NewPool(config, mockOrderRepo, mockTxRepo, mockAccrualClient, logger)
+	pool := NewPool(config, mockOrderRepo, mockAccrualClient, nil, nil, nil, logger)
 
-	return pool, mockOrderRepo, mockTxRepo, mockAccrualClient
+	return pool, mockOrderRepo, mockAccrualClient
 }
 
 func TestPool_ProcessOrder(t *testing.T) {
 	tests := []struct {
 		name        string
 		orderNumber string
-		setupMocks  func(*domainmocks.OrderRepositoryMock, *domainmocks.TransactionRepositoryMock, *domainmocks.AccrualClientMock)
+		setupMocks  func(*domainmocks.OrderRepositoryMock, *domainmocks.AccrualClientMock)
 	}{
 		{
 			name:        "Success with accrual",
 			orderNumber: "12345678903",
-			setupMocks: func(orderRepo *domainmocks.OrderRepositoryMock, txRepo *domainmocks.TransactionRepositoryMock, accrualClient *domainmocks.AccrualClientMock) {
+			setupMocks: func(orderRepo *domainmocks.OrderRepositoryMock, accrualClient *domainmocks.AccrualClientMock) {
 				accrual := 100.0
 				accrualResp := &domain.AccrualResponse{
 					Order:   "12345678903",
-					Status:  domain.OrderStatusProcessed,
+					Status:  domain.AccrualStatusProcessed,
 					Accrual: &accrual,
 				}
-				order := &domain.Order{ID: 1, UserID: 1, Number: "12345678903", Status: domain.OrderStatusNew}
 
 				accrualClient.EXPECT().GetOrderAccrual(mock.Anything, "12345678903").Return(accrualResp, nil).Once()
-				orderRepo.EXPECT().UpdateOrderStatus(mock.Anything, "12345678903", domain.OrderStatusProcessed, &accrual).Return(nil).Once()
-				orderRepo.EXPECT().GetOrderByNumber(mock.Anything, "12345678903").Return(order, nil).Once()
-				txRepo.EXPECT().CreateTransaction(mock.Anything, int64(1), "12345678903", accrual, domain.TransactionTypeAccrual).Return(nil).Once()
+				expected := &domain.Accrual{Amount: 100, Raw: 100, Policy: domain.DefaultRoundingPolicy()}
+				orderRepo.EXPECT().ApplyAccrual(mock.Anything, "12345678903", domain.OrderStatusProcessed, expected).Return(nil).Once()
+			},
+		},
+		{
+			name:        "Sub-kopeck accrual is rounded before storing",
+			orderNumber: "12345678903",
+			setupMocks: func(orderRepo *domainmocks.OrderRepositoryMock, accrualClient *domainmocks.AccrualClientMock) {
+				accrual := 729.985
+				accrualResp := &domain.AccrualResponse{
+					Order:   "12345678903",
+					Status:  domain.AccrualStatusProcessed,
+					Accrual: &accrual,
+				}
+
+				accrualClient.EXPECT().GetOrderAccrual(mock.Anything, "12345678903").Return(accrualResp, nil).Once()
+				expected := &domain.Accrual{Amount: 729.99, Raw: 729.985, Policy: domain.DefaultRoundingPolicy()}
+				orderRepo.EXPECT().ApplyAccrual(mock.Anything, "12345678903", domain.OrderStatusProcessed, expected).Return(nil).Once()
 			},
 		},
 		{
 			name:        "Order not registered in accrual system",
 			orderNumber: "12345678903",
-			setupMocks: func(orderRepo *domainmocks.OrderRepositoryMock, txRepo *domainmocks.TransactionRepositoryMock, accrualClient *domainmocks.AccrualClientMock) {
+			setupMocks: func(orderRepo *domainmocks.OrderRepositoryMock, accrualClient *domainmocks.AccrualClientMock) {
 				accrualClient.EXPECT().GetOrderAccrual(mock.Anything, "12345678903").Return(nil, nil).Once()
-				orderRepo.EXPECT().UpdateOrderStatus(mock.Anything, "12345678903", domain.OrderStatusProcessing, (*float64)(nil)).Return(nil).Once()
+				orderRepo.EXPECT().ApplyAccrual(mock.Anything, "12345678903", domain.OrderStatusProcessing, (*domain.Accrual)(nil)).Return(nil).Once()
 			},
 		},
 		{
 			name:        "Order rejected by accrual system",
 			orderNumber: "12345678903",
-			setupMocks: func(orderRepo *domainmocks.OrderRepositoryMock, txRepo *domainmocks.TransactionRepositoryMock, accrualClient *domainmocks.AccrualClientMock) {
+			setupMocks: func(orderRepo *domainmocks.OrderRepositoryMock, accrualClient *domainmocks.AccrualClientMock) {
+				accrualResp := &domain.AccrualResponse{
+					Order:  "12345678903",
+					Status: domain.AccrualStatusInvalid,
+				}
+				accrualClient.EXPECT().GetOrderAccrual(mock.Anything, "12345678903").Return(accrualResp, nil).Once()
+				orderRepo.EXPECT().ApplyAccrual(mock.Anything, "12345678903", domain.OrderStatusInvalid, (*domain.Accrual)(nil)).Return(nil).Once()
+			},
+		},
+		{
+			name:        "Order registered in accrual system",
+			orderNumber: "12345678903",
+			setupMocks: func(orderRepo *domainmocks.OrderRepositoryMock, accrualClient *domainmocks.AccrualClientMock) {
 				accrualResp := &domain.AccrualResponse{
 					Order:  "12345678903",
-					Status: domain.OrderStatusInvalid,
+					Status: domain.AccrualStatusRegistered,
 				}
 				accrualClient.EXPECT().GetOrderAccrual(mock.Anything, "12345678903").Return(accrualResp, nil).Once()
-				orderRepo.EXPECT().UpdateOrderStatus(mock.Anything, "12345678903", domain.OrderStatusInvalid, (*float64)(nil)).Return(nil).Once()
+				orderRepo.EXPECT().ApplyAccrual(mock.Anything, "12345678903", domain.OrderStatusProcessing, (*domain.Accrual)(nil)).Return(nil).Once()
+			},
+		},
+		{
+			name:        "Malformed accrual response is not stored",
+			orderNumber: "12345678903",
+			setupMocks: func(orderRepo *domainmocks.OrderRepositoryMock, accrualClient *domainmocks.AccrualClientMock) {
+				accrualClient.EXPECT().GetOrderAccrual(mock.Anything, "12345678903").
+					Return(nil, fmt.Errorf("accrual client: unknown status: %w", domain.ErrInvalidAccrualResponse)).Once()
 			},
 		},
 		{
 			name:        "Duplicate accrual - already processed",
 			orderNumber: "12345678903",
-			setupMocks: func(orderRepo *domainmocks.OrderRepositoryMock, txRepo *domainmocks.TransactionRepositoryMock, accrualClient *domainmocks.AccrualClientMock) {
+			setupMocks: func(orderRepo *domainmocks.OrderRepositoryMock, accrualClient *domainmocks.AccrualClientMock) {
 				accrual := 100.0
 				accrualResp := &domain.AccrualResponse{
 					Order:   "12345678903",
-					Status:  domain.OrderStatusProcessed,
+					Status:  domain.AccrualStatusProcessed,
 					Accrual: &accrual,
 				}
-				order := &domain.Order{ID: 1, UserID: 1, Number: "12345678903", Status: domain.OrderStatusNew}
 
 				accrualClient.EXPECT().GetOrderAccrual(mock.Anything, "12345678903").Return(accrualResp, nil).Once()
-				orderRepo.EXPECT().UpdateOrderStatus(mock.Anything, "12345678903", domain.OrderStatusProcessed, &accrual).Return(nil).Once()
-				orderRepo.EXPECT().GetOrderByNumber(mock.Anything, "12345678903").Return(order, nil).Once()
-				txRepo.EXPECT().CreateTransaction(mock.Anything, int64(1), "12345678903", accrual, domain.TransactionTypeAccrual).Return(postgres.ErrDuplicateAccrual).Once()
+				expected := &domain.Accrual{Amount: 100, Raw: 100, Policy: domain.DefaultRoundingPolicy()}
+				orderRepo.EXPECT().ApplyAccrual(mock.Anything, "12345678903", domain.OrderStatusProcessed, expected).Return(domain.ErrDuplicateAccrual).Once()
+			},
+		},
+		{
+			name:        "Order finalized by another worker",
+			orderNumber: "12345678903",
+			setupMocks: func(orderRepo *domainmocks.OrderRepositoryMock, accrualClient *domainmocks.AccrualClientMock) {
+				accrualResp := &domain.AccrualResponse{
+					Order:  "12345678903",
+					Status: domain.AccrualStatusProcessing,
+				}
+				accrualClient.EXPECT().GetOrderAccrual(mock.Anything, "12345678903").Return(accrualResp, nil).Once()
+				orderRepo.EXPECT().ApplyAccrual(mock.Anything, "12345678903", domain.OrderStatusProcessing, (*domain.Accrual)(nil)).
+					Return(fmt.Errorf("repository: %w", domain.ErrOrderAlreadyFinal)).Once()
 			},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			pool, orderRepo, txRepo, accrualClient := newTestPool(t)
-			tt.setupMocks(orderRepo, txRepo, accrualClient)
+			pool, orderRepo, accrualClient := newTestPool(t)
+			tt.setupMocks(orderRepo, accrualClient)
 
 			ctx := context.Background()
 			pool.processOrder(ctx, tt.orderNumber)
@@ -105,8 +153,51 @@ func TestPool_ProcessOrder(t *testing.T) {
 	}
 }
 
+func TestPool_ProcessOrder_AccrualCorrection(t *testing.T) {
+	ctx := context.Background()
+	number := "12345678903"
+	raw := 150.0
+	accrualResp := &domain.AccrualResponse{Order: number, Status: domain.AccrualStatusProcessed, Accrual: &raw}
+	expected := &domain.Accrual{Amount: 150, Raw: 150, Policy: domain.DefaultRoundingPolicy()}
+
+	t.Run("Changed accrual is corrected", func(t *testing.T) {
+		pool, orderRepo, accrualClient := newTestPool(t)
+		pool.config.AccrualCorrections = true
+
+		accrualClient.EXPECT().GetOrderAccrual(mock.Anything, number).Return(accrualResp, nil).Once()
+		orderRepo.EXPECT().ApplyAccrual(mock.Anything, number, domain.OrderStatusProcessed, expected).Return(domain.ErrDuplicateAccrual).Once()
+		orderRepo.EXPECT().CorrectAccrual(mock.Anything, number, expected, int64(0)).
+			Return(&domain.AccrualCorrection{OrderNumber: number, PreviousAmount: 100, NewAmount: 150, Delta: 50}, nil).Once()
+
+		pool.processOrder(ctx, number)
+	})
+
+	t.Run("Unchanged accrual", func(t *testing.T) {
+		pool, orderRepo, accrualClient := newTestPool(t)
+		pool.config.AccrualCorrections = true
+
+		accrualClient.EXPECT().GetOrderAccrual(mock.Anything, number).Return(accrualResp, nil).Once()
+		orderRepo.EXPECT().ApplyAccrual(mock.Anything, number, domain.OrderStatusProcessed, expected).Return(domain.ErrDuplicateAccrual).Once()
+		orderRepo.EXPECT().CorrectAccrual(mock.Anything, number, expected, int64(0)).Return(nil, domain.ErrAccrualUnchanged).Once()
+
+		pool.processOrder(ctx, number)
+	})
+
+	t.Run("Corrections disabled", func(t *testing.T) {
+		pool, orderRepo, accrualClient := newTestPool(t)
+
+		accrualClient.EXPECT().GetOrderAccrual(mock.Anything, number).Return(accrualResp, nil).Once()
+		orderRepo.EXPECT().ApplyAccrual(mock.Anything, number, domain.OrderStatusProcessed, expected).Return(domain.ErrDuplicateAccrual).Once()
+
+		// Мок падает при неожиданном вызове CorrectAccrual
+		pool.processOrder(ctx, number)
+	})
+}
+
 func TestPool_ProcessOrder_RateLimit(t *testing.T) {
-	pool, _, _, accrualClient := newTestPool(t)
+	pool, _, accrualClient := newTestPool(t)
+	m := metrics.New()
+	pool.metrics = m
 	ctx := context.Background()
 	orderNumber := "12345678903"
 
@@ -124,10 +215,38 @@ func TestPool_ProcessOrder_RateLimit(t *testing.T) {
 	case <-time.After(100 * time.Millisecond):
 		t.Error("expected order in retry queue, got timeout")
 	}
+
+	// Rate limit не считается успешным опросом
+	_, ok := pool.LastAccrualSuccess()
+	assert.False(t, ok)
+
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Contains(t, w.Body.String(), `gophermart_worker_order_duration_seconds_count{result="rate_limited"} 1`)
+}
+
+func TestPool_LastAccrualSuccess(t *testing.T) {
+	pool, orderRepo, accrualClient := newTestPool(t)
+	ctx := context.Background()
+	orderNumber := "12345678903"
+
+	_, ok := pool.LastAccrualSuccess()
+	assert.False(t, ok)
+
+	accrualClient.EXPECT().GetOrderAccrual(mock.Anything, orderNumber).Return(nil, nil).Once()
+	orderRepo.EXPECT().ApplyAccrual(mock.Anything, orderNumber, domain.OrderStatusProcessing, (*domain.Accrual)(nil)).
+		Return(nil).Once()
+
+	before := time.Now()
+	pool.processOrder(ctx, orderNumber)
+
+	last, ok := pool.LastAccrualSuccess()
+	assert.True(t, ok)
+	assert.False(t, last.Before(before))
 }
 
 func TestPool_ScanPendingOrders(t *testing.T) {
-	pool, orderRepo, _, _ := newTestPool(t)
+	pool, orderRepo, _ := newTestPool(t)
 	ctx := context.Background()
 
 	pendingOrders := []*domain.Order{
@@ -135,9 +254,9 @@ func TestPool_ScanPendingOrders(t *testing.T) {
 		{ID: 2, Number: "222", Status: domain.OrderStatusProcessing},
 	}
 
-	orderRepo.EXPECT().GetPendingOrders(mock.Anything).Return(pendingOrders, nil).Once()
+	orderRepo.EXPECT().GetPendingOrders(mock.Anything, mock.Anything).Return(pendingOrders, nil).Once()
 
-	pool.scanPendingOrders(ctx)
+	assert.Equal(t, 2, pool.scanPendingOrders(ctx))
 
 	// Проверяем, что заказы добавлены в очередь
 	assert.Equal(t, 2, len(pool.queue), "expected 2 orders in queue")
@@ -155,3 +274,461 @@ func TestPool_ScanPendingOrders(t *testing.T) {
 	assert.Contains(t, received, "111")
 	assert.Contains(t, received, "222")
 }
+
+func TestPool_ScanPolicies(t *testing.T) {
+	orderRepo := domainmocks.NewOrderRepositoryMock(t)
+	processing := domain.OrderScanPolicy{Status: domain.OrderStatusProcessing, Interval: time.Hour, MaxAge: 72 * time.Hour}
+	config := PoolConfig{QueueSize: 10, ScanPolicies: []domain.OrderScanPolicy{
+		processing,
+		{Status: domain.OrderStatusInvalid, Interval: time.Minute},
+	}}
+	pool := NewPool(config, orderRepo, nil, nil, nil, nil, zap.NewNop())
+	ctx := context.Background()
+	newOrders := domain.OrderScanPolicy{Status: domain.OrderStatusNew}
+
+	// Первое сканирование охватывает все статусы, INVALID не опрашивается никогда
+	orderRepo.EXPECT().GetPendingOrders(mock.Anything, []domain.OrderScanPolicy{newOrders, processing}).
+		Return([]*domain.Order{
+			{Number: "111", Status: domain.OrderStatusNew},
+			{Number: "222", Status: domain.OrderStatusProcessing},
+			{Number: "333", Status: domain.OrderStatusProcessing},
+		}, nil).Once()
+	assert.Equal(t, 3, pool.scanPendingOrders(ctx))
+
+	// До истечения интервала PROCESSING сканируются только новые заказы,
+	// заказы PROCESSING учитываются по прошлому сканированию
+	orderRepo.EXPECT().GetPendingOrders(mock.Anything, []domain.OrderScanPolicy{newOrders}).Return(nil, nil).Once()
+	assert.Equal(t, 2, pool.scanPendingOrders(ctx))
+	assert.Equal(t, 2, pool.Backlog())
+
+	// Внеочередное сканирование статуса не ждет его интервала
+	orderRepo.EXPECT().GetPendingOrders(mock.Anything, []domain.OrderScanPolicy{newOrders, processing}).Return(nil, nil).Once()
+	assert.Equal(t, 0, pool.scanPendingOrders(ctx, domain.OrderStatusProcessing))
+}
+
+func TestNormalizeScanPolicies(t *testing.T) {
+	assert.Equal(t, domain.DefaultOrderScanPolicies(), normalizeScanPolicies(nil))
+
+	policies := normalizeScanPolicies([]domain.OrderScanPolicy{
+		{Status: domain.OrderStatusProcessed},
+		{Status: domain.OrderStatusNew, Interval: -time.Second, MaxAge: 24 * time.Hour},
+	})
+	assert.Equal(t, []domain.OrderScanPolicy{
+		{Status: domain.OrderStatusNew, MaxAge: 24 * time.Hour},
+		{Status: domain.OrderStatusProcessing},
+	}, policies)
+}
+
+func TestPool_NextScanInterval(t *testing.T) {
+	pool := NewPool(PoolConfig{ScanInterval: time.Second, MaxScanInterval: 5 * time.Second}, nil, nil, nil, nil, nil, zap.NewNop())
+
+	assert.Equal(t, 2*time.Second, pool.nextScanInterval(time.Second, 0))
+	assert.Equal(t, 4*time.Second, pool.nextScanInterval(2*time.Second, 0))
+	assert.Equal(t, 5*time.Second, pool.nextScanInterval(4*time.Second, 0))
+	assert.Equal(t, 5*time.Second, pool.nextScanInterval(5*time.Second, 0))
+	assert.Equal(t, time.Second, pool.nextScanInterval(5*time.Second, 3))
+
+	// Без MaxScanInterval интервал фиксированный
+	fixed := NewPool(PoolConfig{ScanInterval: time.Second}, nil, nil, nil, nil, nil, zap.NewNop())
+	assert.Equal(t, time.Second, fixed.nextScanInterval(time.Second, 0))
+}
+
+func TestPool_ScannerWakesOnNewOrder(t *testing.T) {
+	orderRepo := domainmocks.NewOrderRepositoryMock(t)
+	config := PoolConfig{QueueSize: 10, ScanInterval: time.Hour, MaxScanInterval: time.Hour}
+	pool := NewPool(config, orderRepo, nil, nil, nil, nil, zap.NewNop())
+
+	scanned := make(chan struct{}, 2)
+	orderRepo.EXPECT().GetPendingOrders(mock.Anything, mock.Anything).
+		Run(func(context.Context, []domain.OrderScanPolicy) { scanned <- struct{}{} }).
+		Return(nil, nil).Times(2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		pool.scanner(ctx)
+	}()
+
+	waitScan := func() {
+		t.Helper()
+		select {
+		case <-scanned:
+		case <-time.After(time.Second):
+			t.Fatal("scan did not happen")
+		}
+	}
+
+	// Первое сканирование сразу при старте, следующее - только по уведомлению,
+	// хотя до планового сканирования еще час
+	waitScan()
+	pool.NotifyNewOrder()
+	waitScan()
+
+	cancel()
+	<-done
+}
+
+type stubLeadership struct {
+	leader atomic.Bool
+}
+
+func (s *stubLeadership) IsLeader() bool {
+	return s.leader.Load()
+}
+
+func TestPool_ScannerRunsOnlyOnLeader(t *testing.T) {
+	orderRepo := domainmocks.NewOrderRepositoryMock(t)
+	leadership := &stubLeadership{}
+	config := PoolConfig{QueueSize: 10, ScanInterval: time.Hour, MaxScanInterval: time.Hour}
+	pool := NewPool(config, orderRepo, nil, nil, leadership, nil, zap.NewNop())
+
+	scanned := make(chan struct{}, 1)
+	orderRepo.EXPECT().GetPendingOrders(mock.Anything, mock.Anything).
+		Run(func(context.Context, []domain.OrderScanPolicy) { scanned <- struct{}{} }).
+		Return(nil, nil).Once()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		pool.scanner(ctx)
+	}()
+
+	// Стартовое сканирование пропущено: реплика не лидер
+	select {
+	case <-scanned:
+		t.Fatal("scan on follower")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// После избрания сканирование запускается сразу
+	leadership.leader.Store(true)
+	pool.ScanNow()
+	select {
+	case <-scanned:
+	case <-time.After(time.Second):
+		t.Fatal("scan did not happen after election")
+	}
+
+	cancel()
+	<-done
+}
+
+func TestPool_NotifyNewOrderCoalesces(t *testing.T) {
+	pool, _, _ := newTestPool(t)
+
+	pool.NotifyNewOrder()
+	pool.NotifyNewOrder()
+
+	assert.Len(t, pool.scanNow, 1)
+}
+
+func TestPool_StopReportsAbandonedOrders(t *testing.T) {
+	pool, _, _ := newTestPool(t)
+
+	pool.queue <- "111"
+	pool.queue <- "222"
+	pool.retryQueue <- retryItem{orderNumber: "333"}
+
+	assert.Equal(t, 3, pool.Stop())
+}
+
+func TestPool_Overloaded(t *testing.T) {
+	orderRepo := domainmocks.NewOrderRepositoryMock(t)
+	config := PoolConfig{Workers: 1, QueueSize: 3, ScanInterval: time.Second, BacklogThreshold: 3}
+	pool := NewPool(config, orderRepo, domainmocks.NewAccrualClientMock(t), nil, nil, nil, zap.NewNop())
+	ctx := context.Background()
+
+	pendingOrders := []*domain.Order{{Number: "111", Status: domain.OrderStatusNew}, {Number: "222", Status: domain.OrderStatusProcessing}}
+	orderRepo.EXPECT().GetPendingOrders(mock.Anything, mock.Anything).Return(pendingOrders, nil).Once()
+
+	pool.scanPendingOrders(ctx)
+	assert.Equal(t, 2, pool.Backlog())
+	assert.False(t, pool.Overloaded())
+
+	// Заказ, загруженный после сканирования, учитывается до следующего сканирования
+	pool.NotifyNewOrder()
+	assert.Equal(t, 3, pool.Backlog())
+	assert.True(t, pool.Overloaded())
+
+	// Сканирование заменяет оценку фактическим числом заказов
+	orderRepo.EXPECT().GetPendingOrders(mock.Anything, mock.Anything).Return(nil, nil).Once()
+	pool.scanPendingOrders(ctx)
+	assert.Equal(t, 0, pool.Backlog())
+
+	// Заполненная очередь тоже означает перегрузку
+	for len(pool.queue) < cap(pool.queue) {
+		pool.queue <- "333"
+	}
+	assert.True(t, pool.Overloaded())
+}
+
+func TestPool_Overloaded_Disabled(t *testing.T) {
+	pool, _, _ := newTestPool(t)
+
+	for i := 0; i < 100; i++ {
+		pool.NotifyNewOrder()
+	}
+
+	assert.False(t, pool.Overloaded())
+}
+
+func TestPool_EstimateProcessing(t *testing.T) {
+	config := PoolConfig{Workers: 2, QueueSize: 10, ScanInterval: time.Second}
+	pool := NewPool(config, domainmocks.NewOrderRepositoryMock(t), domainmocks.NewAccrualClientMock(t), nil, nil, nil, zap.NewNop())
+
+	// Без обработанных заказов время обработки берется по умолчанию
+	assert.Equal(t, domain.ProcessingEstimate{QueuePosition: 1, Wait: defaultOrderProcessingTime}, pool.EstimateProcessing())
+
+	for i := 0; i < 5; i++ {
+		pool.NotifyNewOrder()
+	}
+	pool.observeProcessingTime(2 * time.Second)
+	// 5 заказов на 2 воркера - 3 раунда по 2 секунды
+	assert.Equal(t, domain.ProcessingEstimate{QueuePosition: 5, Wait: 6 * time.Second}, pool.EstimateProcessing())
+
+	// Среднее сглаживается, а не заменяется последним значением
+	pool.observeProcessingTime(7 * time.Second)
+	assert.Equal(t, 9*time.Second, pool.EstimateProcessing().Wait)
+
+	// Пауза после 429 добавляется к ожиданию
+	pool.setCooldown(time.Now().Add(time.Minute))
+	wait := pool.EstimateProcessing().Wait
+	assert.Greater(t, wait, 68*time.Second)
+	assert.LessOrEqual(t, wait, 69*time.Second)
+}
+
+// stubAvailability имитирует недоступную БД, которая восстанавливается по сигналу
+type stubAvailability struct {
+	available atomic.Bool
+	recovered chan struct{}
+}
+
+func (s *stubAvailability) Available() bool {
+	return s.available.Load()
+}
+
+func (s *stubAvailability) WaitAvailable(ctx context.Context) bool {
+	select {
+	case <-s.recovered:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func TestPool_WaitForDB(t *testing.T) {
+	pool, _, _ := newTestPool(t)
+	db := &stubAvailability{recovered: make(chan struct{})}
+	pool.db = db
+
+	t.Run("Paused until database recovers", func(t *testing.T) {
+		done := make(chan bool)
+		go func() {
+			done <- pool.waitForDB(context.Background(), 0)
+		}()
+
+		select {
+		case <-done:
+			t.Fatal("worker must wait while database is unavailable")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		db.available.Store(true)
+		close(db.recovered)
+		assert.True(t, <-done)
+	})
+
+	t.Run("Context canceled while paused", func(t *testing.T) {
+		db.available.Store(false)
+		db.recovered = make(chan struct{})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		assert.False(t, pool.waitForDB(ctx, 0))
+	})
+}
+
+func TestPool_PauseResume(t *testing.T) {
+	pool, _, _ := newTestPool(t)
+
+	_, paused := pool.PausedSince()
+	assert.False(t, paused)
+	assert.False(t, pool.Resume(), "resume without pause")
+
+	assert.True(t, pool.Pause())
+	assert.False(t, pool.Pause(), "repeated pause")
+	since, paused := pool.PausedSince()
+	assert.True(t, paused)
+	assert.WithinDuration(t, time.Now(), since, time.Second)
+
+	t.Run("Worker waits until resumed", func(t *testing.T) {
+		done := make(chan bool)
+		go func() {
+			done <- pool.waitForResume(context.Background(), 0)
+		}()
+
+		select {
+		case <-done:
+			t.Fatal("worker must wait while processing is paused")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		// Возобновление запускает полное сканирование
+		assert.True(t, pool.Resume())
+		assert.True(t, <-done)
+		assert.Len(t, pool.scanNow, 1)
+		assert.True(t, pool.fullScan.Load())
+	})
+
+	t.Run("Context canceled while paused", func(t *testing.T) {
+		pool.Pause()
+		defer pool.Resume()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		assert.False(t, pool.waitForResume(ctx, 0))
+	})
+
+	_, paused = pool.PausedSince()
+	assert.False(t, paused)
+	assert.True(t, pool.waitForResume(context.Background(), 0))
+}
+
+func TestPool_ScannerSkipsWhilePaused(t *testing.T) {
+	orderRepo := domainmocks.NewOrderRepositoryMock(t)
+	config := PoolConfig{QueueSize: 10, ScanInterval: time.Hour, MaxScanInterval: time.Hour}
+	pool := NewPool(config, orderRepo, nil, nil, nil, nil, zap.NewNop())
+	pool.Pause()
+
+	scanned := make(chan struct{}, 1)
+	orderRepo.EXPECT().GetPendingOrders(mock.Anything, mock.Anything).
+		Run(func(context.Context, []domain.OrderScanPolicy) { scanned <- struct{}{} }).
+		Return(nil, nil).Once()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		pool.scanner(ctx)
+	}()
+
+	// Стартовое сканирование и уведомления о новых заказах пропускаются
+	pool.NotifyNewOrder()
+	select {
+	case <-scanned:
+		t.Fatal("scan while paused")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	pool.Resume()
+	select {
+	case <-scanned:
+	case <-time.After(time.Second):
+		t.Fatal("scan did not happen after resume")
+	}
+
+	cancel()
+	<-done
+}
+
+func TestPool_ProcessOrderSafe(t *testing.T) {
+	pool, _, accrualClient := newTestPool(t)
+	m := metrics.New()
+	pool.metrics = m
+
+	accrualClient.EXPECT().GetOrderAccrual(mock.Anything, "12345678903").
+		Run(func(context.Context, string) { panic("nil map write") }).Once()
+
+	assert.NotPanics(t, func() {
+		pool.processOrderSafe(context.Background(), 0, "12345678903")
+	})
+
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Contains(t, w.Body.String(), `gophermart_worker_panics_total{component="worker"} 1`)
+	assert.Contains(t, w.Body.String(), `gophermart_worker_order_duration_seconds_count{result="panic"} 1`)
+}
+
+func TestPool_SuperviseRestartsAfterPanic(t *testing.T) {
+	pool, _, _ := newTestPool(t)
+	pool.config.RestartDelay = time.Millisecond
+
+	runs := 0
+	pool.wg.Add(1)
+	pool.supervise(context.Background(), "scanner", func(context.Context) {
+		runs++
+		if runs < 3 {
+			panic("boom")
+		}
+	})
+
+	// После двух паник третий запуск завершился штатно
+	assert.Equal(t, 3, runs)
+	pool.wg.Wait()
+}
+
+func TestPool_SuperviseStopsOnCancel(t *testing.T) {
+	pool, _, _ := newTestPool(t)
+	pool.config.RestartDelay = time.Hour
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	pool.wg.Add(1)
+	go func() {
+		pool.supervise(ctx, "scanner", func(context.Context) { panic("boom") })
+		close(done)
+	}()
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("supervisor must stop when context is canceled")
+	}
+}
+
+func TestInterleaveByUser(t *testing.T) {
+	order := func(number string, userID int64) *domain.Order {
+		return &domain.Order{Number: number, UserID: userID}
+	}
+	numbers := func(orders []*domain.Order) []string {
+		result := make([]string, 0, len(orders))
+		for _, o := range orders {
+			result = append(result, o.Number)
+		}
+		return result
+	}
+
+	// Пользователи идут в порядке самого раннего заказа
+	orders := []*domain.Order{
+		order("a1", 1), order("a2", 1), order("a3", 1), order("a4", 1),
+		order("b1", 2), order("c1", 3), order("a5", 1), order("b2", 2),
+	}
+	assert.Equal(t, []string{"a1", "b1", "c1", "a2", "b2", "a3", "a4", "a5"}, numbers(interleaveByUser(orders)))
+
+	single := []*domain.Order{order("a1", 1), order("a2", 1)}
+	assert.Equal(t, []string{"a1", "a2"}, numbers(interleaveByUser(single)))
+	assert.Empty(t, interleaveByUser(nil))
+}
+
+func TestPool_ScanPendingOrders_FairAcrossUsers(t *testing.T) {
+	orderRepo := domainmocks.NewOrderRepositoryMock(t)
+	pool := NewPool(PoolConfig{QueueSize: 3}, orderRepo, nil, nil, nil, nil, zap.NewNop())
+
+	// Очередь вмещает три заказа: заказ второго пользователя не ждет,
+	// пока обработаются все заказы первого
+	orderRepo.EXPECT().GetPendingOrders(mock.Anything, mock.Anything).Return([]*domain.Order{
+		{Number: "111", UserID: 1, Status: domain.OrderStatusNew},
+		{Number: "222", UserID: 1, Status: domain.OrderStatusNew},
+		{Number: "333", UserID: 1, Status: domain.OrderStatusNew},
+		{Number: "444", UserID: 2, Status: domain.OrderStatusNew},
+	}, nil).Once()
+	assert.Equal(t, 4, pool.scanPendingOrders(context.Background()))
+
+	assert.Len(t, pool.queue, 3)
+	assert.Equal(t, "111", <-pool.queue)
+	assert.Equal(t, "444", <-pool.queue)
+	assert.Equal(t, "222", <-pool.queue)
+}
@@ -0,0 +1,408 @@
+// Package client - Go клиент HTTP API накопительной системы лояльности.
+// Хранит токен и при необходимости входит заново, повторяет запросы после 429 и 503
+// с учетом Retry-After и возвращает ошибки, которые можно различать через errors.Is.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Параметры клиента по умолчанию
+const (
+	defaultTimeout      = 10 * time.Second
+	defaultMaxRetries   = 3
+	defaultMaxRetryWait = 30 * time.Second
+	// Токен обновляется заранее, чтобы не получить 401 на истекающем токене
+	tokenRefreshMargin = 30 * time.Second
+)
+
+// Config содержит настройки клиента
+type Config struct {
+	BaseURL      string        // Адрес сервиса, например http://localhost:8080
+	HTTPClient   *http.Client  // nil - клиент с таймаутом 10 секунд
+	MaxRetries   int           // Повторы после 429 и 503, 0 - по умолчанию (3), отрицательное - без повторов
+	MaxRetryWait time.Duration // Наибольший Retry-After, который клиент ждет, 0 - по умолчанию (30 секунд)
+}
+
+// Client - клиент API. Безопасен для использования из нескольких горутин.
+type Client struct {
+	baseURL      string
+	httpClient   *http.Client
+	maxRetries   int
+	maxRetryWait time.Duration
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+	login     string
+	password  string
+}
+
+// New создает новый Client
+func New(cfg Config) *Client {
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = &http.Client{Timeout: defaultTimeout}
+	}
+	if cfg.MaxRetries == 0 {
+		cfg.MaxRetries = defaultMaxRetries
+	}
+	if cfg.MaxRetryWait <= 0 {
+		cfg.MaxRetryWait = defaultMaxRetryWait
+	}
+
+	return &Client{
+		baseURL:      strings.TrimRight(cfg.BaseURL, "/"),
+		httpClient:   cfg.HTTPClient,
+		maxRetries:   max(cfg.MaxRetries, 0),
+		maxRetryWait: cfg.MaxRetryWait,
+	}
+}
+
+// OrderReceipt - результат загрузки заказа
+type OrderReceipt struct {
+	Number        string
+	Status        string
+	QueuePosition int           // Число необработанных заказов вместе с загруженным
+	EstimatedWait time.Duration // Оценка ожидания обработки
+	Duplicate     bool          // Заказ уже был загружен этим пользователем, остальные поля пусты
+}
+
+// Balance - баланс пользователя
+type Balance struct {
+	Current   float64 `json:"current"`
+	Withdrawn float64 `json:"withdrawn"`
+}
+
+// credentials - тело запросов регистрации и входа
+type credentials struct {
+	Login    string `json:"login"`
+	Password string `json:"password"`
+}
+
+// Register регистрирует пользователя и сохраняет выданный токен
+func (c *Client) Register(ctx context.Context, login, password string) error {
+	return c.authenticate(ctx, "/api/user/register", login, password, map[int]error{
+		http.StatusConflict: ErrLoginTaken,
+	})
+}
+
+// Login входит под пользователем и сохраняет выданный токен.
+// Логин и пароль запоминаются, чтобы войти заново, когда токен истечет.
+func (c *Client) Login(ctx context.Context, login, password string) error {
+	return c.authenticate(ctx, "/api/user/login", login, password, nil)
+}
+
+// SetToken задает токен, полученный вне клиента. Такой токен не обновляется автоматически.
+func (c *Client) SetToken(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.token = token
+	c.expiresAt = tokenExpiry(token)
+	c.login, c.password = "", ""
+}
+
+// Token возвращает текущий токен доступа
+func (c *Client) Token() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.token
+}
+
+// SubmitOrder загружает номер заказа на обработку
+func (c *Client) SubmitOrder(ctx context.Context, number string) (*OrderReceipt, error) {
+	resp, err := c.doAuthorized(ctx, request{
+		method:      http.MethodPost,
+		path:        "/api/user/orders",
+		body:        []byte(number),
+		contentType: "text/plain",
+		accept:      "application/json",
+		errors: map[int]error{
+			http.StatusConflict:            ErrOrderOwnedByAnother,
+			http.StatusUnprocessableEntity: ErrInvalidOrderNumber,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return &OrderReceipt{Number: number, Duplicate: true}, nil
+	}
+
+	receipt := &OrderReceipt{Number: number}
+	if !isJSON(resp.Header.Get("Content-Type")) {
+		return receipt, nil
+	}
+	var body struct {
+		Number               string `json:"number"`
+		Status               string `json:"status"`
+		QueuePosition        int    `json:"queue_position"`
+		EstimatedWaitSeconds int    `json:"estimated_wait_seconds"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("client: failed to decode order receipt: %w", err)
+	}
+	receipt.Status = body.Status
+	receipt.QueuePosition = body.QueuePosition
+	receipt.EstimatedWait = time.Duration(body.EstimatedWaitSeconds) * time.Second
+	return receipt, nil
+}
+
+// Balance возвращает текущий баланс пользователя
+func (c *Client) Balance(ctx context.Context) (*Balance, error) {
+	resp, err := c.doAuthorized(ctx, request{method: http.MethodGet, path: "/api/user/balance"})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var balance Balance
+	if err := json.NewDecoder(resp.Body).Decode(&balance); err != nil {
+		return nil, fmt.Errorf("client: failed to decode balance: %w", err)
+	}
+	return &balance, nil
+}
+
+// Withdraw списывает баллы в счет заказа
+func (c *Client) Withdraw(ctx context.Context, order string, sum float64) error {
+	body, err := json.Marshal(struct {
+		Order string  `json:"order"`
+		Sum   float64 `json:"sum"`
+	}{Order: order, Sum: sum})
+	if err != nil {
+		return fmt.Errorf("client: failed to encode withdrawal: %w", err)
+	}
+
+	resp, err := c.doAuthorized(ctx, request{
+		method:      http.MethodPost,
+		path:        "/api/user/balance/withdraw",
+		body:        body,
+		contentType: "application/json",
+		errors: map[int]error{
+			http.StatusPaymentRequired:     ErrInsufficientFunds,
+			http.StatusForbidden:           ErrWithdrawalLimit,
+			http.StatusUnprocessableEntity: ErrInvalidOrderNumber,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// request описывает запрос к API. Тело хранится целиком, чтобы запрос можно было повторить.
+type request struct {
+	method      string
+	path        string
+	body        []byte
+	contentType string
+	accept      string
+	errors      map[int]error // Ошибки пакета для кодов ответа этого эндпоинта
+}
+
+// authenticate выполняет регистрацию или вход и сохраняет токен и учетные данные
+func (c *Client) authenticate(ctx context.Context, path, login, password string, errs map[int]error) error {
+	body, err := json.Marshal(credentials{Login: login, Password: password})
+	if err != nil {
+		return fmt.Errorf("client: failed to encode credentials: %w", err)
+	}
+
+	resp, err := c.do(ctx, request{
+		method:      http.MethodPost,
+		path:        path,
+		body:        body,
+		contentType: "application/json",
+		errors:      errs,
+	}, "")
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	token, ok := strings.CutPrefix(resp.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return errors.New("client: response has no access token")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.token = token
+	c.expiresAt = tokenExpiry(token)
+	c.login, c.password = login, password
+	return nil
+}
+
+// doAuthorized выполняет запрос с токеном. Истекающий токен обновляется заранее,
+// а после 401 клиент один раз входит заново и повторяет запрос.
+func (c *Client) doAuthorized(ctx context.Context, req request) (*http.Response, error) {
+	token, err := c.validToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.do(ctx, req, token)
+	if err == nil || !errors.Is(err, ErrUnauthorized) || !c.canRelogin() {
+		return resp, err
+	}
+
+	if err := c.relogin(ctx, token); err != nil {
+		return nil, err
+	}
+	return c.do(ctx, req, c.Token())
+}
+
+// validToken возвращает токен, при необходимости обновив его входом
+func (c *Client) validToken(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	token, expiresAt := c.token, c.expiresAt
+	c.mu.Unlock()
+
+	if token == "" && !c.canRelogin() {
+		return "", fmt.Errorf("client: not logged in: %w", ErrUnauthorized)
+	}
+	if token != "" && (expiresAt.IsZero() || time.Until(expiresAt) > tokenRefreshMargin || !c.canRelogin()) {
+		return token, nil
+	}
+
+	if err := c.relogin(ctx, token); err != nil {
+		return "", err
+	}
+	return c.Token(), nil
+}
+
+func (c *Client) canRelogin() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.login != ""
+}
+
+// relogin входит заново, если токен не обновила другая горутина
+func (c *Client) relogin(ctx context.Context, staleToken string) error {
+	c.mu.Lock()
+	login, password, token := c.login, c.password, c.token
+	c.mu.Unlock()
+
+	if token != staleToken {
+		return nil
+	}
+	if err := c.Login(ctx, login, password); err != nil {
+		return fmt.Errorf("client: failed to refresh token: %w", err)
+	}
+	return nil
+}
+
+// do выполняет запрос, повторяя его после 429 и 503 через Retry-After.
+// Ответ с кодом ошибки закрывается и возвращается как *APIError.
+func (c *Client) do(ctx context.Context, req request, token string) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		httpReq, err := http.NewRequestWithContext(ctx, req.method, c.baseURL+req.path, bytes.NewReader(req.body))
+		if err != nil {
+			return nil, fmt.Errorf("client: failed to create request: %w", err)
+		}
+		if req.contentType != "" {
+			httpReq.Header.Set("Content-Type", req.contentType)
+		}
+		if req.accept != "" {
+			httpReq.Header.Set("Accept", req.accept)
+		}
+		if token != "" {
+			httpReq.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		resp, err := c.httpClient.Do(httpReq)
+		if err != nil {
+			return nil, fmt.Errorf("client: %s %s: %w", req.method, req.path, err)
+		}
+		if resp.StatusCode < http.StatusBadRequest {
+			return resp, nil
+		}
+
+		apiErr := newAPIError(resp, req.errors)
+		if attempt >= c.maxRetries || !c.retryable(apiErr) {
+			return nil, apiErr
+		}
+
+		timer := time.NewTimer(apiErr.RetryAfter)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("client: %s %s: %w", req.method, req.path, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// retryable сообщает, что сервис просит повторить запрос не позже допустимой паузы
+func (c *Client) retryable(err *APIError) bool {
+	switch err.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return err.RetryAfter > 0 && err.RetryAfter <= c.maxRetryWait
+	}
+	return false
+}
+
+// newAPIError читает ответ с кодом ошибки и закрывает его
+func newAPIError(resp *http.Response, errs map[int]error) *APIError {
+	defer resp.Body.Close()
+
+	apiErr := &APIError{StatusCode: resp.StatusCode, err: errs[resp.StatusCode]}
+	if apiErr.err == nil {
+		apiErr.err = statusError(resp.StatusCode)
+	}
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		apiErr.RetryAfter = time.Duration(seconds) * time.Second
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<16)) //nolint:errcheck // тело ошибки необязательно
+	if isJSON(resp.Header.Get("Content-Type")) {
+		var errResp struct {
+			Error string `json:"error"`
+			Code  string `json:"code"`
+		}
+		if json.Unmarshal(body, &errResp) == nil {
+			apiErr.Message, apiErr.Code = errResp.Error, errResp.Code
+			return apiErr
+		}
+	}
+	apiErr.Message = strings.TrimSpace(string(body))
+	return apiErr
+}
+
+func isJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == "application/json"
+}
+
+// tokenExpiry читает срок действия из JWT без проверки подписи: она нужна серверу, а клиенту
+// срок нужен только чтобы обновить токен заранее. Нулевое время - срок неизвестен.
+func tokenExpiry(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}
+	}
+	var claims struct {
+		ExpiresAt int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.ExpiresAt == 0 {
+		return time.Time{}
+	}
+	return time.Unix(claims.ExpiresAt, 0)
+}
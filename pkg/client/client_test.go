@@ -0,0 +1,302 @@
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testToken собирает JWT с заданным сроком действия, подпись клиентом не проверяется
+func testToken(expiresAt time.Time) string {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+	payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"user_id":1,"exp":%d}`, expiresAt.Unix())))
+	return header + "." + payload + ".signature"
+}
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return New(Config{BaseURL: server.URL + "/", MaxRetryWait: 2 * time.Second})
+}
+
+func TestClient_Login(t *testing.T) {
+	token := testToken(time.Now().Add(time.Hour))
+
+	t.Run("Stores token", func(t *testing.T) {
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/user/login", r.URL.Path)
+			var body credentials
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, credentials{Login: "alice", Password: "secret"}, body)
+			w.Header().Set("Authorization", "Bearer "+token)
+		})
+
+		require.NoError(t, c.Login(context.Background(), "alice", "secret"))
+		assert.Equal(t, token, c.Token())
+	})
+
+	t.Run("Invalid credentials", func(t *testing.T) {
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		})
+
+		err := c.Login(context.Background(), "alice", "wrong")
+		assert.ErrorIs(t, err, ErrUnauthorized)
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, "Unauthorized", apiErr.Message)
+		assert.Empty(t, c.Token())
+	})
+
+	t.Run("Login taken on register", func(t *testing.T) {
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/user/register", r.URL.Path)
+			http.Error(w, http.StatusText(http.StatusConflict), http.StatusConflict)
+		})
+
+		assert.ErrorIs(t, c.Register(context.Background(), "alice", "secret"), ErrLoginTaken)
+	})
+}
+
+func TestClient_SubmitOrder(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Accepted with receipt", func(t *testing.T) {
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+			assert.Equal(t, "application/json", r.Header.Get("Accept"))
+			body, _ := io.ReadAll(r.Body)
+			assert.Equal(t, "79927398713", string(body))
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusAccepted)
+			_, _ = w.Write([]byte(`{"number":"79927398713","status":"NEW","queue_position":3,"estimated_wait_seconds":8}`))
+		})
+		c.SetToken("token")
+
+		receipt, err := c.SubmitOrder(ctx, "79927398713")
+		require.NoError(t, err)
+		assert.Equal(t, &OrderReceipt{
+			Number:        "79927398713",
+			Status:        "NEW",
+			QueuePosition: 3,
+			EstimatedWait: 8 * time.Second,
+		}, receipt)
+	})
+
+	t.Run("Already uploaded", func(t *testing.T) {
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})
+		c.SetToken("token")
+
+		receipt, err := c.SubmitOrder(ctx, "79927398713")
+		require.NoError(t, err)
+		assert.True(t, receipt.Duplicate)
+	})
+
+	t.Run("Typed errors", func(t *testing.T) {
+		tests := []struct {
+			status int
+			want   error
+		}{
+			{http.StatusConflict, ErrOrderOwnedByAnother},
+			{http.StatusUnprocessableEntity, ErrInvalidOrderNumber},
+			{http.StatusBadRequest, ErrBadRequest},
+		}
+		for _, tt := range tests {
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			})
+			c.SetToken("token")
+
+			_, err := c.SubmitOrder(ctx, "79927398713")
+			assert.ErrorIs(t, err, tt.want)
+		}
+	})
+
+	t.Run("Not logged in", func(t *testing.T) {
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			t.Error("unexpected request")
+		})
+
+		_, err := c.SubmitOrder(ctx, "79927398713")
+		assert.ErrorIs(t, err, ErrUnauthorized)
+	})
+}
+
+func TestClient_RateLimit(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Retries after Retry-After", func(t *testing.T) {
+		var calls atomic.Int32
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) == 1 {
+				w.Header().Set("Retry-After", "1")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"current":500.5,"withdrawn":42}`))
+		})
+		c.SetToken("token")
+
+		balance, err := c.Balance(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, &Balance{Current: 500.5, Withdrawn: 42}, balance)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("Retry-After above limit", func(t *testing.T) {
+		var calls atomic.Int32
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.Header().Set("Retry-After", "60")
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"error":"rate limit exceeded"}`))
+		})
+		c.SetToken("token")
+
+		_, err := c.Balance(ctx)
+		assert.ErrorIs(t, err, ErrRateLimited)
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, time.Minute, apiErr.RetryAfter)
+		assert.Equal(t, "rate limit exceeded", apiErr.Message)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("Context cancelled while waiting", func(t *testing.T) {
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusServiceUnavailable)
+		})
+		c.SetToken("token")
+
+		ctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+		_, err := c.Balance(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestClient_TokenRefresh(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Relogin after 401", func(t *testing.T) {
+		fresh := testToken(time.Now().Add(time.Hour))
+		var logins atomic.Int32
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.URL.Path == "/api/user/login":
+				if logins.Add(1) == 1 {
+					w.Header().Set("Authorization", "Bearer "+testToken(time.Now().Add(time.Hour)))
+					return
+				}
+				w.Header().Set("Authorization", "Bearer "+fresh)
+			case r.Header.Get("Authorization") == "Bearer "+fresh:
+				_, _ = w.Write([]byte(`{"current":1,"withdrawn":0}`))
+			default:
+				w.WriteHeader(http.StatusUnauthorized)
+			}
+		})
+		require.NoError(t, c.Login(ctx, "alice", "secret"))
+
+		balance, err := c.Balance(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1.0, balance.Current)
+		assert.Equal(t, fresh, c.Token())
+	})
+
+	t.Run("Expiring token is refreshed before request", func(t *testing.T) {
+		fresh := testToken(time.Now().Add(time.Hour))
+		var logins atomic.Int32
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/api/user/login" {
+				if logins.Add(1) == 1 {
+					w.Header().Set("Authorization", "Bearer "+testToken(time.Now().Add(time.Second)))
+					return
+				}
+				w.Header().Set("Authorization", "Bearer "+fresh)
+				return
+			}
+			assert.Equal(t, "Bearer "+fresh, r.Header.Get("Authorization"))
+		})
+		require.NoError(t, c.Login(ctx, "alice", "secret"))
+
+		require.NoError(t, c.Withdraw(ctx, "2377225624", 10))
+		assert.Equal(t, int32(2), logins.Load())
+	})
+
+	t.Run("External token is not refreshed", func(t *testing.T) {
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.NotEqual(t, "/api/user/login", r.URL.Path)
+			w.WriteHeader(http.StatusUnauthorized)
+		})
+		c.SetToken("token")
+
+		_, err := c.Balance(ctx)
+		assert.ErrorIs(t, err, ErrUnauthorized)
+	})
+}
+
+func TestClient_Withdraw(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   error
+		code   string
+	}{
+		{"Insufficient funds", http.StatusPaymentRequired, "", ErrInsufficientFunds, ""},
+		{"Limit exceeded", http.StatusForbidden, `{"error":"withdrawal limit exceeded","code":"daily_limit"}`, ErrWithdrawalLimit, "daily_limit"},
+		{"Invalid order", http.StatusUnprocessableEntity, "", ErrInvalidOrderNumber, ""},
+		{"Server error", http.StatusInternalServerError, "", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				var body map[string]any
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+				assert.Equal(t, map[string]any{"order": "2377225624", "sum": 751.0}, body)
+				if tt.body != "" {
+					w.Header().Set("Content-Type", "application/json")
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			})
+			c.SetToken("token")
+
+			err := c.Withdraw(context.Background(), "2377225624", 751)
+			var apiErr *APIError
+			require.ErrorAs(t, err, &apiErr)
+			assert.Equal(t, tt.status, apiErr.StatusCode)
+			assert.Equal(t, tt.code, apiErr.Code)
+			if tt.want != nil {
+				assert.ErrorIs(t, err, tt.want)
+			} else {
+				assert.Nil(t, errors.Unwrap(err))
+			}
+		})
+	}
+}
+
+func TestTokenExpiry(t *testing.T) {
+	expiresAt := time.Unix(1700000000, 0)
+
+	assert.Equal(t, expiresAt, tokenExpiry(testToken(expiresAt)))
+	assert.True(t, tokenExpiry("token").IsZero())
+	assert.True(t, tokenExpiry("a.!!!.c").IsZero())
+}
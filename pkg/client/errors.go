@@ -0,0 +1,58 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Ошибки API, которые клиент различает. Проверяются через errors.Is.
+var (
+	ErrBadRequest          = errors.New("bad request")
+	ErrUnauthorized        = errors.New("unauthorized")
+	ErrLoginTaken          = errors.New("login already taken")
+	ErrOrderOwnedByAnother = errors.New("order uploaded by another user")
+	ErrInvalidOrderNumber  = errors.New("invalid order number")
+	ErrInsufficientFunds   = errors.New("insufficient funds")
+	ErrWithdrawalLimit     = errors.New("withdrawal limit exceeded")
+	ErrRateLimited         = errors.New("rate limit exceeded")
+	ErrUnavailable         = errors.New("service unavailable")
+)
+
+// APIError - ответ сервиса с кодом ошибки
+type APIError struct {
+	StatusCode int
+	Message    string        // Текст ошибки из ответа
+	Code       string        // Машиночитаемый код, например daily_limit для лимита списаний
+	RetryAfter time.Duration // Значение Retry-After, если сервис его передал
+	err        error
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("client: unexpected status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("client: status %d: %s", e.StatusCode, e.Message)
+}
+
+// Unwrap возвращает одну из ошибок пакета, соответствующую ответу, или nil
+func (e *APIError) Unwrap() error {
+	return e.err
+}
+
+// statusError сопоставляет коды ответа, общие для всех эндпоинтов, с ошибками пакета.
+// Коды, смысл которых зависит от эндпоинта (402, 403, 409, 422), передают сами методы.
+func statusError(code int) error {
+	switch code {
+	case http.StatusBadRequest:
+		return ErrBadRequest
+	case http.StatusUnauthorized:
+		return ErrUnauthorized
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	case http.StatusServiceUnavailable:
+		return ErrUnavailable
+	}
+	return nil
+}